@@ -22,10 +22,12 @@ import (
 
 	"k8s.io/klog/v2"
 	"k8s.io/kops/channels/pkg/cmd"
+	"k8s.io/kops/pkg/logformat"
 )
 
 func main() {
 	klog.InitFlags(nil)
+	logformat.InitFlags(nil)
 
 	f := &cmd.DefaultFactory{}
 	if err := cmd.Execute(f, os.Stdout); err != nil {
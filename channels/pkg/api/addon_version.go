@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// AddonVersionResource is the resource of the AddonVersion custom resources,
+// whose definition is installed with kops-controller
+var AddonVersionResource = schema.GroupVersionResource{Group: "addons.kops.k8s.io", Version: "v1alpha1", Resource: "addonversions"}
+
+const (
+	// AddonApplySucceeded is the outcome of an addon applied successfully
+	AddonApplySucceeded = "Succeeded"
+	// AddonApplyFailed is the outcome of an addon that failed to apply
+	AddonApplyFailed = "Failed"
+)
+
+// AddonVersion records the version of an addon that channels installed, and the history of its applies.
+// It is named after the addon, in the namespace of the addon.
+// The addons.k8s.io annotations of the namespace remain the record channels compares versions against.
+type AddonVersion struct {
+	metav1.TypeMeta `json:",inline"`
+	ObjectMeta      metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status AddonVersionStatus `json:"status,omitempty"`
+}
+
+// AddonVersionStatus is the installed version of the addon, and its most recent applies
+type AddonVersionStatus struct {
+	// Version is the version of the addon last applied successfully
+	Version string `json:"version,omitempty"`
+	// ManifestHash is the hash of the manifest last applied successfully
+	ManifestHash string `json:"manifestHash,omitempty"`
+	// Channel is the channel the addon was last applied from successfully
+	Channel string `json:"channel,omitempty"`
+	// Id is the id of the addon last applied successfully
+	Id string `json:"id,omitempty"`
+	// History are the most recent applies of the addon, most recent first
+	History []AddonApply `json:"history,omitempty"`
+}
+
+// AddonApply is an attempt to apply an addon
+type AddonApply struct {
+	// Version is the version of the addon applied
+	Version string `json:"version,omitempty"`
+	// ManifestHash is the hash of the manifest applied
+	ManifestHash string `json:"manifestHash,omitempty"`
+	// Channel is the channel the addon was applied from
+	Channel string `json:"channel,omitempty"`
+	// Id is the id of the addon applied
+	Id string `json:"id,omitempty"`
+	// Time is when the apply completed
+	Time metav1.Time `json:"time"`
+	// Duration is how long the apply took
+	Duration metav1.Duration `json:"duration,omitempty"`
+	// Outcome is Succeeded or Failed
+	Outcome string `json:"outcome"`
+	// Message is the error of a failed apply
+	Message string `json:"message,omitempty"`
+}
@@ -18,9 +18,12 @@ package api
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/blang/semver/v4"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/kops/channels/pkg/condition"
 )
 
 type Addons struct {
@@ -70,21 +73,128 @@ type AddonSpec struct {
 
 	// NeedsPKI determines if channels should provision a CA and a cert-manager issuer for the addon.
 	NeedsPKI bool `json:"needsPKI,omitempty"`
+
+	// NeedsCRDs lists the custom resource definitions, by name, that must exist before the addon is applied.
+	// The addon is skipped until they are all installed, for example by an operator the addon integrates with.
+	NeedsCRDs []string `json:"needsCRDs,omitempty"`
+
+	// ServiceAccount is the ServiceAccount, as name or namespace/name, that channels impersonates when applying the manifest,
+	// so that the manifest can only change what the ServiceAccount's RBAC allows.
+	// Without a namespace, the ServiceAccount is in the addon's namespace.
+	// When empty, the manifest is applied with the credentials of channels itself.
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+
+	// DependsOn lists the addons, by name, that must be applied before this addon when they are updated together.
+	// Addons that don't depend on each other are applied concurrently.
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// Condition is an expression evaluated against the ClusterFacts; the addon is only applied if it is true.
+	// For example: cloudProvider == "aws" && nodeCount >= 10
+	Condition string `json:"condition,omitempty"`
+}
+
+// ClusterFacts are the facts about a cluster that addon conditions are evaluated against.
+type ClusterFacts struct {
+	// ClusterName is the name of the cluster
+	ClusterName string `json:"clusterName,omitempty"`
+	// CloudProvider is the cloud provider of the cluster, as in the kOps cluster spec: aws, gce, azure, ...
+	CloudProvider string `json:"cloudProvider,omitempty"`
+	// Networking is the networking provider of the cluster, as in the kOps cluster spec: cilium, calico, kubenet, ...
+	Networking string `json:"networking,omitempty"`
+	// KubernetesVersion is the version of kubernetes, without pre-release
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+	// NodeCount is the number of nodes in the cluster, including control-plane nodes
+	NodeCount int `json:"nodeCount,omitempty"`
+	// ControlPlaneCount is the number of control-plane nodes in the cluster
+	ControlPlaneCount int `json:"controlPlaneCount,omitempty"`
+	// FeatureGates are the kubernetes feature gates set on the control plane
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+}
+
+// ConditionVariables are the variables available to addon conditions
+var ConditionVariables = []string{"clusterName", "cloudProvider", "networking", "kubernetesVersion", "nodeCount", "controlPlaneCount", "featureGates"}
+
+// Variables returns the facts as the variables of addon conditions
+func (f *ClusterFacts) Variables() map[string]interface{} {
+	featureGates := f.FeatureGates
+	if featureGates == nil {
+		featureGates = map[string]bool{}
+	}
+	return map[string]interface{}{
+		"clusterName":       f.ClusterName,
+		"cloudProvider":     f.CloudProvider,
+		"networking":        f.Networking,
+		"kubernetesVersion": f.KubernetesVersion,
+		"nodeCount":         f.NodeCount,
+		"controlPlaneCount": f.ControlPlaneCount,
+		"featureGates":      featureGates,
+	}
+}
+
+// AddonOverride disables an addon, or pins it to a version, whatever channel ships it.
+type AddonOverride struct {
+	// Name is the name of the addon
+	Name string `json:"name"`
+	// Disabled stops channels from installing or updating the addon
+	Disabled bool `json:"disabled,omitempty"`
+	// Version restricts the addon to this version
+	Version string `json:"version,omitempty"`
+	// ManifestHash restricts the addon to the manifest with this hash
+	ManifestHash string `json:"manifestHash,omitempty"`
+}
+
+// ServiceAccountRef returns the namespace and name of the ServiceAccount applying the addon, or empty strings if there is none.
+func (a *AddonSpec) ServiceAccountRef() (string, string, error) {
+	if a.ServiceAccount == "" {
+		return "", "", nil
+	}
+
+	namespace := "kube-system"
+	if a.Namespace != nil && *a.Namespace != "" {
+		namespace = *a.Namespace
+	}
+	name := a.ServiceAccount
+	if tokens := strings.Split(a.ServiceAccount, "/"); len(tokens) == 2 {
+		namespace, name = tokens[0], tokens[1]
+	} else if len(tokens) > 2 {
+		return "", "", fmt.Errorf("serviceAccount %q is not of the form name or namespace/name", a.ServiceAccount)
+	}
+
+	if errs := validation.IsDNS1123Label(namespace); len(errs) != 0 {
+		return "", "", fmt.Errorf("serviceAccount %q has invalid namespace %q: %s", a.ServiceAccount, namespace, strings.Join(errs, "; "))
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) != 0 {
+		return "", "", fmt.Errorf("serviceAccount %q has invalid name %q: %s", a.ServiceAccount, name, strings.Join(errs, "; "))
+	}
+	return namespace, name, nil
 }
 
 func (a *Addons) Verify() error {
 	for _, addon := range a.Spec.Addons {
-		if addon != nil && addon.Version != nil && *addon.Version != "" {
-			name := a.ObjectMeta.Name
-			if addon.Name != nil {
-				name = *addon.Name
-			}
+		if addon == nil {
+			continue
+		}
+		name := a.ObjectMeta.Name
+		if addon.Name != nil {
+			name = *addon.Name
+		}
 
+		if addon.Version != nil && *addon.Version != "" {
 			_, err := semver.ParseTolerant(*addon.Version)
 			if err != nil {
 				return fmt.Errorf("addon %q has unparseable version %q: %v", name, *addon.Version, err)
 			}
 		}
+
+		if _, _, err := addon.ServiceAccountRef(); err != nil {
+			return fmt.Errorf("addon %q: %v", name, err)
+		}
+
+		if addon.Condition != "" {
+			if _, err := condition.Compile(addon.Condition); err != nil {
+				return fmt.Errorf("addon %q has invalid condition: %v", name, err)
+			}
+		}
 	}
 
 	return nil
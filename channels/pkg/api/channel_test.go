@@ -45,6 +45,74 @@ func Test_UnparseableVersion(t *testing.T) {
 	assert.EqualError(t, err, "addon \"testaddon\" has unparseable version \"1.0-kops\": Short version cannot contain PreRelease/Build meta data", "detected invalid version")
 }
 
+func Test_InvalidCondition(t *testing.T) {
+	addons := Addons{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "test",
+		},
+		Spec: AddonsSpec{
+			Addons: []*AddonSpec{
+				{
+					Name:      s("testaddon"),
+					Version:   s("1.0.0"),
+					Condition: "nodeCount >",
+				},
+			},
+		},
+	}
+
+	err := addons.Verify()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "addon \"testaddon\" has invalid condition")
+	}
+}
+
 func s(v string) *string {
 	return &v
 }
+
+func Test_ServiceAccountRef(t *testing.T) {
+	grid := []struct {
+		Spec              AddonSpec
+		ExpectedNamespace string
+		ExpectedName      string
+		ExpectedError     string
+	}{
+		{
+			Spec: AddonSpec{},
+		},
+		{
+			Spec:              AddonSpec{ServiceAccount: "dashboard-applier"},
+			ExpectedNamespace: "kube-system",
+			ExpectedName:      "dashboard-applier",
+		},
+		{
+			Spec:              AddonSpec{Namespace: s("dashboard"), ServiceAccount: "applier"},
+			ExpectedNamespace: "dashboard",
+			ExpectedName:      "applier",
+		},
+		{
+			Spec:              AddonSpec{Namespace: s("dashboard"), ServiceAccount: "addons/applier"},
+			ExpectedNamespace: "addons",
+			ExpectedName:      "applier",
+		},
+		{
+			Spec:          AddonSpec{ServiceAccount: "a/b/c"},
+			ExpectedError: `serviceAccount "a/b/c" is not of the form name or namespace/name`,
+		},
+		{
+			Spec:          AddonSpec{ServiceAccount: "Addons/applier"},
+			ExpectedError: `serviceAccount "Addons/applier" has invalid namespace "Addons": a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')`,
+		},
+	}
+	for _, g := range grid {
+		namespace, name, err := g.Spec.ServiceAccountRef()
+		if g.ExpectedError != "" {
+			assert.EqualError(t, err, g.ExpectedError, "serviceAccount %q", g.Spec.ServiceAccount)
+			continue
+		}
+		assert.NoError(t, err, "serviceAccount %q", g.Spec.ServiceAccount)
+		assert.Equal(t, g.ExpectedNamespace, namespace, "namespace of serviceAccount %q", g.Spec.ServiceAccount)
+		assert.Equal(t, g.ExpectedName, name, "name of serviceAccount %q", g.Spec.ServiceAccount)
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// NeedsUpdateAnnotation marks the nodes that must be replaced by a rolling update
+const NeedsUpdateAnnotation = "kops.k8s.io/needs-update"
+
+// NeedsUpdate is the value channels sets on the NeedsUpdateAnnotation, recording why the node needs update,
+// so that kops-controller can clear the annotation once the reasons no longer hold.
+// Nodes annotated by hand, or by older versions of channels, have an empty value.
+type NeedsUpdate struct {
+	// BootID is the boot ID of the node when the update was first requested.
+	// Nodes apply their configuration again when they boot, so a different boot ID means the node has been updated.
+	BootID string `json:"bootID,omitempty"`
+	// Addons are the addons that requested the update
+	Addons []NeedsUpdateAddon `json:"addons"`
+}
+
+// NeedsUpdateAddon is an addon requesting a rolling update of the node
+type NeedsUpdateAddon struct {
+	// Name is the name of the addon
+	Name string `json:"name"`
+	// Namespace is the namespace holding the record of the installed version of the addon
+	Namespace string `json:"namespace"`
+	// Version is the version of the addon requesting the update
+	Version string `json:"version,omitempty"`
+}
+
+// ParseNeedsUpdate parses the value of the NeedsUpdateAnnotation, returning nil for an empty value
+func ParseNeedsUpdate(value string) (*NeedsUpdate, error) {
+	if value == "" {
+		return nil, nil
+	}
+	n := &NeedsUpdate{}
+	if err := json.Unmarshal([]byte(value), n); err != nil {
+		return nil, fmt.Errorf("error parsing %s annotation %q: %v", NeedsUpdateAnnotation, value, err)
+	}
+	return n, nil
+}
+
+// Encode returns the value of the NeedsUpdateAnnotation
+func (n *NeedsUpdate) Encode() (string, error) {
+	b, err := json.Marshal(n)
+	if err != nil {
+		return "", fmt.Errorf("error encoding %s annotation: %v", NeedsUpdateAnnotation, err)
+	}
+	return string(b), nil
+}
+
+// SetAddon records that the addon requests the update, replacing any earlier request of the same addon
+func (n *NeedsUpdate) SetAddon(addon NeedsUpdateAddon) {
+	for i := range n.Addons {
+		if n.Addons[i].Name == addon.Name {
+			n.Addons[i] = addon
+			return
+		}
+	}
+	n.Addons = append(n.Addons, addon)
+}
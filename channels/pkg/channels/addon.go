@@ -19,18 +19,18 @@ package channels
 import (
 	"context"
 	"crypto/x509/pkix"
-	"encoding/json"
 	"fmt"
 	"net/url"
+	"strings"
 
 	"k8s.io/kops/pkg/pki"
 
 	certmanager "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
 	"k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/types"
 
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/channels/pkg/api"
 
@@ -45,6 +45,7 @@ type Addon struct {
 	ChannelName     string
 	ChannelLocation url.URL
 	Spec            *api.AddonSpec
+	Cache           *Cache
 }
 
 // AddonUpdate holds data about a proposed update to an addon
@@ -79,6 +80,48 @@ func (m *AddonMenu) MergeAddons(o *AddonMenu) {
 	}
 }
 
+// AddonConflict is an addon shipped by more than one channel
+type AddonConflict struct {
+	Name string
+	// Addon is the addon from the channel taking precedence, which is applied
+	Addon *Addon
+	// Overridden is the addon from the channel with lower precedence, which is ignored
+	Overridden *Addon
+}
+
+func (c *AddonConflict) String() string {
+	return fmt.Sprintf("addon %q from channel %q (%v) overrides channel %q (%v)", c.Name, c.Addon.ChannelName, c.Addon.ChannelVersion(), c.Overridden.ChannelName, c.Overridden.ChannelVersion())
+}
+
+// OverrideAddons merges the addons of o, which takes precedence over the addons already in the menu:
+// an addon of o replaces the addon of the same name regardless of their versions.
+// It returns the replaced addons, unless they had the same version, id and manifest.
+func (m *AddonMenu) OverrideAddons(o *AddonMenu) []*AddonConflict {
+	var conflicts []*AddonConflict
+	for _, k := range sortedAddonNames(o.Addons) {
+		v := o.Addons[k]
+		existing := m.Addons[k]
+		m.Addons[k] = v
+		if existing == nil || existing.ChannelName == v.ChannelName {
+			continue
+		}
+		if sameAddonVersion(existing.ChannelVersion(), v.ChannelVersion()) {
+			continue
+		}
+		conflicts = append(conflicts, &AddonConflict{
+			Name:       k,
+			Addon:      v,
+			Overridden: existing,
+		})
+	}
+	return conflicts
+}
+
+// sameAddonVersion returns true if the two versions install the same manifest, regardless of their channel
+func sameAddonVersion(a, b *ChannelVersion) bool {
+	return stringValue(a.Version) == stringValue(b.Version) && a.Id == b.Id && a.ManifestHash == b.ManifestHash
+}
+
 func (a *Addon) ChannelVersion() *ChannelVersion {
 	return &ChannelVersion{
 		Channel:      &a.ChannelName,
@@ -102,6 +145,17 @@ func (a *Addon) buildChannel() *Channel {
 }
 
 func (a *Addon) GetRequiredUpdates(ctx context.Context, k8sClient kubernetes.Interface, cmClient certmanager.Interface) (*AddonUpdate, error) {
+	if len(a.Spec.NeedsCRDs) != 0 {
+		installed, err := crdsInstalled(k8sClient, a.Spec.NeedsCRDs)
+		if err != nil {
+			return nil, err
+		}
+		if !installed {
+			klog.V(2).Infof("skipping addon %q until the custom resource definitions %v are installed", a.Name, a.Spec.NeedsCRDs)
+			return nil, nil
+		}
+	}
+
 	newVersion := a.ChannelVersion()
 
 	channel := a.buildChannel()
@@ -136,6 +190,44 @@ func (a *Addon) GetRequiredUpdates(ctx context.Context, k8sClient kubernetes.Int
 	}, nil
 }
 
+// crdsInstalled returns whether the API server serves the resources of all the named custom resource definitions.
+// The names are those of the definitions, the plural resource name followed by the API group.
+func crdsInstalled(k8sClient kubernetes.Interface, names []string) (bool, error) {
+	groups, err := k8sClient.Discovery().ServerGroups()
+	if err != nil {
+		return false, fmt.Errorf("error listing API groups: %v", err)
+	}
+
+	for _, name := range names {
+		tokens := strings.SplitN(name, ".", 2)
+		if len(tokens) != 2 {
+			return false, fmt.Errorf("unexpected custom resource definition name %q", name)
+		}
+		resource, group := tokens[0], tokens[1]
+
+		found := false
+		for _, g := range groups.Groups {
+			if g.Name != group {
+				continue
+			}
+			resources, err := k8sClient.Discovery().ServerResourcesForGroupVersion(g.PreferredVersion.GroupVersion)
+			if err != nil {
+				return false, fmt.Errorf("error listing resources of %q: %v", g.PreferredVersion.GroupVersion, err)
+			}
+			for _, r := range resources.APIResources {
+				if r.Name == resource {
+					found = true
+				}
+			}
+		}
+		if !found {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
 func (a *Addon) GetManifestFullUrl() (*url.URL, error) {
 	if a.Spec.Manifest == nil || *a.Spec.Manifest == "" {
 		return nil, field.Required(field.NewPath("spec", "manifest"), "")
@@ -168,7 +260,17 @@ func (a *Addon) EnsureUpdated(ctx context.Context, k8sClient kubernetes.Interfac
 		}
 		klog.Infof("Applying update from %q", manifestURL)
 
-		err = Apply(manifestURL.String())
+		data, err := a.Cache.ReadFile(manifestURL.String(), a.Spec.ManifestHash)
+		if err != nil {
+			return nil, fmt.Errorf("error reading manifest from %q: %v", manifestURL, err)
+		}
+
+		user, err := a.applyAs(ctx, k8sClient)
+		if err != nil {
+			return nil, err
+		}
+
+		err = Apply(data, user)
 		if err != nil {
 			return nil, fmt.Errorf("error applying update from %q: %v", manifestURL, err)
 		}
@@ -192,6 +294,27 @@ func (a *Addon) EnsureUpdated(ctx context.Context, k8sClient kubernetes.Interfac
 	return required, nil
 }
 
+// applyAs returns the user impersonated to apply the addon, or an empty string to use the credentials of channels itself.
+func (a *Addon) applyAs(ctx context.Context, k8sClient kubernetes.Interface) (string, error) {
+	namespace, name, err := a.Spec.ServiceAccountRef()
+	if err != nil {
+		return "", fmt.Errorf("addon %q: %v", a.Name, err)
+	}
+	if name == "" {
+		return "", nil
+	}
+
+	// Fail clearly rather than with the authorization errors of an unknown user
+	if _, err := k8sClient.CoreV1().ServiceAccounts(namespace).Get(ctx, name, metav1.GetOptions{}); err != nil {
+		if errors.IsNotFound(err) {
+			return "", fmt.Errorf("addon %q is applied as ServiceAccount %s/%s, which does not exist", a.Name, namespace, name)
+		}
+		return "", fmt.Errorf("error getting ServiceAccount %s/%s: %v", namespace, name, err)
+	}
+	klog.Infof("Applying addon %q as ServiceAccount %s/%s", a.Name, namespace, name)
+	return "system:serviceaccount:" + namespace + ":" + name, nil
+}
+
 func (a *Addon) AddNeedsUpdateLabel(ctx context.Context, k8sClient kubernetes.Interface, required *AddonUpdate) error {
 	if required.ExistingVersion != nil {
 		if a.Spec.NeedsRollingUpdate != "" {
@@ -214,12 +337,10 @@ func (a *Addon) patchNeedsUpdateLabel(ctx context.Context, k8sClient kubernetes.
 		selector = "node-role.kubernetes.io/node="
 	}
 
-	annotationPatch := &annotationPatch{Metadata: annotationPatchMetadata{Annotations: map[string]string{
-		"kops.k8s.io/needs-update": "",
-	}}}
-	annotationPatchJSON, err := json.Marshal(annotationPatch)
-	if err != nil {
-		return err
+	request := api.NeedsUpdateAddon{
+		Name:      a.Name,
+		Namespace: a.buildChannel().Namespace,
+		Version:   stringValue(a.Spec.Version),
 	}
 
 	nodeInterface := k8sClient.CoreV1().Nodes()
@@ -227,9 +348,22 @@ func (a *Addon) patchNeedsUpdateLabel(ctx context.Context, k8sClient kubernetes.
 	if err != nil {
 		return err
 	}
-	for _, node := range nodes.Items {
-		_, err = nodeInterface.Patch(ctx, node.Name, types.StrategicMergePatchType, annotationPatchJSON, metav1.PatchOptions{})
-
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		// Addons are applied concurrently, so merge our request with those of other addons
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			changed, err := addNeedsUpdateRequest(node, request)
+			if err != nil || !changed {
+				return err
+			}
+			_, err = nodeInterface.Update(ctx, node, metav1.UpdateOptions{})
+			if errors.IsConflict(err) {
+				if latest, getErr := nodeInterface.Get(ctx, node.Name, metav1.GetOptions{}); getErr == nil {
+					node = latest
+				}
+			}
+			return err
+		})
 		if err != nil {
 			return err
 		}
@@ -237,6 +371,40 @@ func (a *Addon) patchNeedsUpdateLabel(ctx context.Context, k8sClient kubernetes.
 	return nil
 }
 
+// addNeedsUpdateRequest records the request of the addon in the needs-update annotation of the node,
+// returning false if the node doesn't need to change
+func addNeedsUpdateRequest(node *corev1.Node, request api.NeedsUpdateAddon) (bool, error) {
+	value, found := node.Annotations[api.NeedsUpdateAnnotation]
+	if found && value == "" {
+		// Annotated by hand or by an older version of channels; it is never cleared automatically
+		return false, nil
+	}
+
+	bootID := node.Status.NodeInfo.BootID
+	needsUpdate, err := api.ParseNeedsUpdate(value)
+	if err != nil {
+		klog.Warningf("replacing invalid annotation on node %q: %v", node.Name, err)
+	}
+	if needsUpdate == nil || needsUpdate.BootID != bootID {
+		// The node has been updated since the earlier requests
+		needsUpdate = &api.NeedsUpdate{BootID: bootID}
+	}
+	needsUpdate.SetAddon(request)
+	encoded, err := needsUpdate.Encode()
+	if err != nil {
+		return false, err
+	}
+	if found && encoded == value {
+		return false, nil
+	}
+
+	if node.Annotations == nil {
+		node.Annotations = make(map[string]string)
+	}
+	node.Annotations[api.NeedsUpdateAnnotation] = encoded
+	return true, nil
+}
+
 func (a *Addon) installPKI(ctx context.Context, k8sClient kubernetes.Interface, cmClient certmanager.Interface) error {
 	klog.Infof("installing PKI for %q", a.Name)
 	req := &pki.IssueCertRequest{
@@ -0,0 +1,152 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/channels/pkg/api"
+)
+
+// maxAddonApplyHistory is the number of applies recorded for each addon
+const maxAddonApplyHistory = 10
+
+// AddonVersionRecorder records the applies of addons in AddonVersion resources
+type AddonVersionRecorder struct {
+	client dynamic.Interface
+	now    func() time.Time
+
+	// mutex guards missing
+	mutex sync.Mutex
+	// missing is set once we know the AddonVersion resource isn't served, such as before kops-controller is installed
+	missing bool
+}
+
+// NewAddonVersionRecorder builds an AddonVersionRecorder writing with client
+func NewAddonVersionRecorder(client dynamic.Interface) *AddonVersionRecorder {
+	return &AddonVersionRecorder{
+		client: client,
+		now:    time.Now,
+	}
+}
+
+// Record records the apply of the addon, which failed if applyErr is not nil.
+// Nothing is recorded while the AddonVersion resource isn't served.
+func (r *AddonVersionRecorder) Record(ctx context.Context, addon *Addon, duration time.Duration, applyErr error) error {
+	if r == nil || r.isMissing() {
+		return nil
+	}
+
+	version := addon.ChannelVersion()
+	apply := api.AddonApply{
+		Version:      stringValue(version.Version),
+		ManifestHash: version.ManifestHash,
+		Channel:      stringValue(version.Channel),
+		Id:           version.Id,
+		Time:         metav1.NewTime(r.now()),
+		Duration:     metav1.Duration{Duration: duration},
+		Outcome:      api.AddonApplySucceeded,
+	}
+	if applyErr != nil {
+		apply.Outcome = api.AddonApplyFailed
+		apply.Message = applyErr.Error()
+	}
+
+	namespace := addon.buildChannel().Namespace
+	resource := r.client.Resource(api.AddonVersionResource).Namespace(namespace)
+
+	// Retry when another writer changed or created the record since we read it
+	return retry.OnError(retry.DefaultRetry, isWriteConflict, func() error {
+		addonVersion := &api.AddonVersion{}
+		existing, err := resource.Get(ctx, addon.Name, metav1.GetOptions{})
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				return fmt.Errorf("error getting AddonVersion %s/%s: %v", namespace, addon.Name, err)
+			}
+			existing = nil
+		} else if err := runtime.DefaultUnstructuredConverter.FromUnstructured(existing.Object, addonVersion); err != nil {
+			return fmt.Errorf("error parsing AddonVersion %s/%s: %v", namespace, addon.Name, err)
+		}
+
+		addApply(&addonVersion.Status, apply)
+
+		addonVersion.APIVersion = api.AddonVersionResource.GroupVersion().String()
+		addonVersion.Kind = "AddonVersion"
+		addonVersion.ObjectMeta.Name = addon.Name
+		addonVersion.ObjectMeta.Namespace = namespace
+		object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(addonVersion)
+		if err != nil {
+			return fmt.Errorf("error building AddonVersion %s/%s: %v", namespace, addon.Name, err)
+		}
+
+		if existing == nil {
+			_, err = resource.Create(ctx, &unstructured.Unstructured{Object: object}, metav1.CreateOptions{})
+			if errors.IsNotFound(err) {
+				// The namespace exists, as we just applied the addon, so the resource isn't served
+				r.setMissing()
+				klog.Warningf("not recording addon applies: %s are not served; is kops-controller installed?", api.AddonVersionResource.GroupResource())
+				return nil
+			}
+		} else {
+			_, err = resource.Update(ctx, &unstructured.Unstructured{Object: object}, metav1.UpdateOptions{})
+		}
+		if err != nil && !isWriteConflict(err) {
+			return fmt.Errorf("error writing AddonVersion %s/%s: %v", namespace, addon.Name, err)
+		}
+		return err
+	})
+}
+
+func isWriteConflict(err error) bool {
+	return errors.IsConflict(err) || errors.IsAlreadyExists(err)
+}
+
+func (r *AddonVersionRecorder) isMissing() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.missing
+}
+
+func (r *AddonVersionRecorder) setMissing() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.missing = true
+}
+
+// addApply adds the apply to the history, and records the version it installed if it succeeded
+func addApply(status *api.AddonVersionStatus, apply api.AddonApply) {
+	if apply.Outcome == api.AddonApplySucceeded {
+		status.Version = apply.Version
+		status.ManifestHash = apply.ManifestHash
+		status.Channel = apply.Channel
+		status.Id = apply.Id
+	}
+	status.History = append([]api.AddonApply{apply}, status.History...)
+	if len(status.History) > maxAddonApplyHistory {
+		status.History = status.History[:maxAddonApplyHistory]
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/kops/channels/pkg/api"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// fakeAddonVersionServer serves AddonVersion resources from memory
+type fakeAddonVersionServer struct {
+	mutex    sync.Mutex
+	objects  map[string][]byte
+	requests int
+}
+
+func (s *fakeAddonVersionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.requests++
+
+	w.Header().Set("Content-Type", "application/json")
+	prefix := "/apis/addons.kops.k8s.io/v1alpha1/namespaces/kube-system/addonversions"
+	if s.objects == nil || !strings.HasPrefix(r.URL.Path, prefix) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"apiVersion":"v1","kind":"Status","status":"Failure","reason":"NotFound","code":404}`)
+		return
+	}
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	switch r.Method {
+	case http.MethodGet:
+		if data, found := s.objects[name]; found {
+			w.Write(data)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"apiVersion":"v1","kind":"Status","status":"Failure","reason":"NotFound","code":404}`)
+	case http.MethodPost, http.MethodPut:
+		data, _ := ioutil.ReadAll(r.Body)
+		object := &api.AddonVersion{}
+		if err := json.Unmarshal(data, object); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.objects[object.ObjectMeta.Name] = data
+		w.Write(data)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *fakeAddonVersionServer) get(t *testing.T, name string) *api.AddonVersion {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	object := &api.AddonVersion{}
+	require.NoError(t, json.Unmarshal(s.objects[name], object))
+	return object
+}
+
+func newTestAddonVersionRecorder(t *testing.T, server *fakeAddonVersionServer) *AddonVersionRecorder {
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	client, err := dynamic.NewForConfig(&rest.Config{Host: httpServer.URL, QPS: 1000, Burst: 1000})
+	require.NoError(t, err)
+	recorder := NewAddonVersionRecorder(client)
+	recorder.now = func() time.Time { return time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC) }
+	return recorder
+}
+
+func Test_AddonVersionRecorder(t *testing.T) {
+	ctx := context.Background()
+	server := &fakeAddonVersionServer{objects: make(map[string][]byte)}
+	recorder := newTestAddonVersionRecorder(t, server)
+
+	addon := func(version string, hash string) *Addon {
+		return &Addon{
+			Name:        "test",
+			ChannelName: "bootstrap",
+			Spec: &api.AddonSpec{
+				Name:         fi.String("test"),
+				Version:      fi.String(version),
+				ManifestHash: hash,
+			},
+		}
+	}
+
+	require.NoError(t, recorder.Record(ctx, addon("1.0.0", "aaa"), time.Second, nil))
+	record := server.get(t, "test")
+	assert.Equal(t, "AddonVersion", record.Kind)
+	assert.Equal(t, "kube-system", record.ObjectMeta.Namespace)
+	assert.Equal(t, "1.0.0", record.Status.Version)
+	assert.Equal(t, "aaa", record.Status.ManifestHash)
+	assert.Equal(t, "bootstrap", record.Status.Channel)
+	require.Len(t, record.Status.History, 1)
+	assert.Equal(t, api.AddonApplySucceeded, record.Status.History[0].Outcome)
+	assert.Equal(t, time.Second, record.Status.History[0].Duration.Duration)
+
+	// A failed apply is recorded, but leaves the installed version
+	require.NoError(t, recorder.Record(ctx, addon("1.1.0", "bbb"), time.Second, fmt.Errorf("error applying update")))
+	record = server.get(t, "test")
+	assert.Equal(t, "1.0.0", record.Status.Version)
+	assert.Equal(t, "aaa", record.Status.ManifestHash)
+	require.Len(t, record.Status.History, 2)
+	assert.Equal(t, api.AddonApplyFailed, record.Status.History[0].Outcome)
+	assert.Equal(t, "1.1.0", record.Status.History[0].Version)
+	assert.Equal(t, "error applying update", record.Status.History[0].Message)
+
+	// The history is bounded
+	for i := 0; i < 2*maxAddonApplyHistory; i++ {
+		require.NoError(t, recorder.Record(ctx, addon(fmt.Sprintf("1.2.%d", i), "ccc"), time.Second, nil))
+	}
+	record = server.get(t, "test")
+	assert.Equal(t, fmt.Sprintf("1.2.%d", 2*maxAddonApplyHistory-1), record.Status.Version)
+	assert.Len(t, record.Status.History, maxAddonApplyHistory)
+}
+
+func Test_AddonVersionRecorderNotServed(t *testing.T) {
+	ctx := context.Background()
+	server := &fakeAddonVersionServer{}
+	recorder := newTestAddonVersionRecorder(t, server)
+
+	addon := &Addon{
+		Name: "test",
+		Spec: &api.AddonSpec{Name: fi.String("test"), Version: fi.String("1.0.0")},
+	}
+	require.NoError(t, recorder.Record(ctx, addon, time.Second, nil))
+	requests := server.requests
+	assert.True(t, requests > 0)
+
+	// Once the resource is known not to be served, nothing is requested
+	require.NoError(t, recorder.Record(ctx, addon, time.Second, nil))
+	assert.Equal(t, requests, server.requests)
+
+	var nilRecorder *AddonVersionRecorder
+	assert.NoError(t, nilRecorder.Record(ctx, addon, time.Second, nil))
+}
@@ -24,24 +24,31 @@ import (
 	"github.com/blang/semver/v4"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/channels/pkg/api"
+	"k8s.io/kops/channels/pkg/condition"
 	"k8s.io/kops/upup/pkg/fi/utils"
-	"k8s.io/kops/util/pkg/vfs"
 )
 
 type Addons struct {
 	ChannelName     string
 	ChannelLocation url.URL
 	APIObject       *api.Addons
+	Cache           *Cache
 }
 
-func LoadAddons(name string, location *url.URL) (*Addons, error) {
+// LoadAddons reads the addons channel at location, through the cache if it is not nil.
+func LoadAddons(name string, location *url.URL, cache *Cache) (*Addons, error) {
 	klog.V(2).Infof("Loading addons channel from %q", location)
-	data, err := vfs.Context.ReadFile(location.String())
+	data, err := cache.ReadFile(location.String(), "")
 	if err != nil {
 		return nil, fmt.Errorf("error reading addons from %q: %v", location, err)
 	}
 
-	return ParseAddons(name, location, data)
+	addons, err := ParseAddons(name, location, data)
+	if err != nil {
+		return nil, err
+	}
+	addons.Cache = cache
+	return addons, nil
 }
 
 func ParseAddons(name string, location *url.URL, data []byte) (*Addons, error) {
@@ -74,7 +81,10 @@ func ParseAddons(name string, location *url.URL, data []byte) (*Addons, error) {
 	return &Addons{ChannelName: name, ChannelLocation: *location, APIObject: apiObject}, nil
 }
 
-func (a *Addons) GetCurrent(kubernetesVersion semver.Version) (*AddonMenu, error) {
+// GetCurrent returns the latest version of each addon applying to the cluster, leaving out disabled addons
+// and the versions of pinned addons other than the pinned one.
+// facts may be nil if no addon has a condition.
+func (a *Addons) GetCurrent(kubernetesVersion semver.Version, facts *api.ClusterFacts, overrides map[string]*api.AddonOverride) (*AddonMenu, error) {
 	all, err := a.wrapInAddons()
 	if err != nil {
 		return nil, err
@@ -82,7 +92,14 @@ func (a *Addons) GetCurrent(kubernetesVersion semver.Version) (*AddonMenu, error
 
 	menu := NewAddonMenu()
 	for _, addon := range all {
-		if !addon.matches(kubernetesVersion) {
+		if !addon.matches(kubernetesVersion) || !addon.matchesOverride(overrides) {
+			continue
+		}
+		matches, err := addon.matchesCondition(facts)
+		if err != nil {
+			return nil, err
+		}
+		if !matches {
 			continue
 		}
 		name := addon.Name
@@ -109,6 +126,7 @@ func (a *Addons) wrapInAddons() ([]*Addon, error) {
 			ChannelLocation: a.ChannelLocation,
 			Spec:            s,
 			Name:            name,
+			Cache:           a.Cache,
 		}
 
 		addons = append(addons, addon)
@@ -116,6 +134,40 @@ func (a *Addons) wrapInAddons() ([]*Addon, error) {
 	return addons, nil
 }
 
+// HasConditions returns true if any addon of the channel has a condition
+func (a *Addons) HasConditions() bool {
+	for _, s := range a.APIObject.Spec.Addons {
+		if s != nil && s.Condition != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesCondition evaluates the condition of the addon, if any, against the cluster facts.
+// An invalid condition is an error rather than a reason to skip the addon, so that it doesn't go unnoticed.
+func (s *Addon) matchesCondition(facts *api.ClusterFacts) (bool, error) {
+	if s.Spec.Condition == "" {
+		return true, nil
+	}
+	program, err := condition.Compile(s.Spec.Condition)
+	if err != nil {
+		return false, fmt.Errorf("invalid condition of addon %q: %v", s.Name, err)
+	}
+	if facts == nil {
+		klog.Warningf("no cluster facts to evaluate condition %q of addon %q; skipping", s.Spec.Condition, s.Name)
+		return false, nil
+	}
+	matches, err := program.EvalBool(facts.Variables())
+	if err != nil {
+		return false, fmt.Errorf("unable to evaluate condition of addon %q: %v", s.Name, err)
+	}
+	if !matches {
+		klog.V(4).Infof("Skipping addon %q whose condition %q is false", s.Name, s.Spec.Condition)
+	}
+	return matches, nil
+}
+
 func (s *Addon) matches(kubernetesVersion semver.Version) bool {
 	if s.Spec.KubernetesVersion != "" {
 		versionRange, err := semver.ParseRange(s.Spec.KubernetesVersion)
@@ -30,6 +30,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
 	fakekubernetes "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/kops/channels/pkg/api"
 
@@ -232,6 +233,45 @@ func Test_MergeAddons(t *testing.T) {
 	}
 }
 
+func Test_OverrideAddons(t *testing.T) {
+	inChannel := func(addon *Addon, channel string) *Addon {
+		addon.ChannelName = channel
+		return addon
+	}
+
+	menu := addonMenu(
+		inChannel(addon(t, "a", "1.0.1", ">=1.18.0", "k8s-1.18"), "stable"),
+		inChannel(addon(t, "b", "1.0.0", ">=1.18.0", "k8s-1.18"), "stable"),
+		inChannel(addon(t, "c", "1.0.0", ">=1.18.0", "k8s-1.18"), "stable"),
+	)
+	conflicts := menu.OverrideAddons(addonMenu(
+		inChannel(addon(t, "a", "1.0.0", ">=1.18.0", "k8s-1.18"), "company"),
+		inChannel(addon(t, "b", "1.0.0", ">=1.18.0", "k8s-1.18"), "company"),
+		inChannel(addon(t, "d", "1.0.0", ">=1.18.0", "k8s-1.18"), "company"),
+	))
+
+	expected := map[string]string{
+		"a": "company",
+		"b": "company",
+		"c": "stable",
+		"d": "company",
+	}
+	for name, channel := range expected {
+		if menu.Addons[name] == nil || menu.Addons[name].ChannelName != channel {
+			t.Errorf("expected addon %q from channel %q, got %v", name, channel, menu.Addons[name])
+		}
+	}
+
+	// b is the same version in both channels, so only a conflicts
+	var actual []string
+	for _, conflict := range conflicts {
+		actual = append(actual, conflict.String())
+	}
+	assert.Equal(t, []string{
+		`addon "a" from channel "company" (Version=1.0.0 Channel=company Id=k8s-1.18) overrides channel "stable" (Version=1.0.1 Channel=stable Id=k8s-1.18)`,
+	}, actual)
+}
+
 func Test_GetRequiredUpdates(t *testing.T) {
 	ctx := context.Background()
 	kubeSystem := &corev1.Namespace{
@@ -260,6 +300,100 @@ func Test_GetRequiredUpdates(t *testing.T) {
 	}
 }
 
+func Test_ApplyAs(t *testing.T) {
+	ctx := context.Background()
+	applier := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "applier",
+			Namespace: "addons",
+		},
+	}
+	fakek8s := fakekubernetes.NewSimpleClientset(applier)
+
+	grid := []struct {
+		ServiceAccount string
+		ExpectedUser   string
+		ExpectedError  string
+	}{
+		{
+			ServiceAccount: "",
+			ExpectedUser:   "",
+		},
+		{
+			ServiceAccount: "addons/applier",
+			ExpectedUser:   "system:serviceaccount:addons:applier",
+		},
+		{
+			ServiceAccount: "applier",
+			ExpectedError:  `addon "test" is applied as ServiceAccount kube-system/applier, which does not exist`,
+		},
+		{
+			ServiceAccount: "a/b/c",
+			ExpectedError:  `addon "test": serviceAccount "a/b/c" is not of the form name or namespace/name`,
+		},
+	}
+	for _, g := range grid {
+		addon := &Addon{
+			Name: "test",
+			Spec: &api.AddonSpec{
+				Name:           fi.String("test"),
+				ServiceAccount: g.ServiceAccount,
+			},
+		}
+		user, err := addon.applyAs(ctx, fakek8s)
+		if g.ExpectedError != "" {
+			assert.EqualError(t, err, g.ExpectedError, "serviceAccount %q", g.ServiceAccount)
+			continue
+		}
+		require.NoError(t, err, "serviceAccount %q", g.ServiceAccount)
+		assert.Equal(t, g.ExpectedUser, user, "serviceAccount %q", g.ServiceAccount)
+	}
+}
+
+func Test_GetRequiredUpdatesNeedsCRDs(t *testing.T) {
+	ctx := context.Background()
+	kubeSystem := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "kube-system",
+		},
+	}
+	fakek8s := fakekubernetes.NewSimpleClientset(kubeSystem)
+	fakecm := fakecertmanager.NewSimpleClientset()
+	addon := &Addon{
+		Name: "test",
+		Spec: &api.AddonSpec{
+			Name:      fi.String("test"),
+			Version:   fi.String("1"),
+			NeedsCRDs: []string{"servicemonitors.monitoring.coreos.com"},
+		},
+	}
+
+	addonUpdate, err := addon.GetRequiredUpdates(ctx, fakek8s, fakecm)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if addonUpdate != nil {
+		t.Errorf("expected no update before the custom resource definitions are installed, got %v", addonUpdate)
+	}
+
+	fakek8s.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "monitoring.coreos.com/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "podmonitors"},
+				{Name: "servicemonitors"},
+			},
+		},
+	}
+	addonUpdate, err = addon.GetRequiredUpdates(ctx, fakek8s, fakecm)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if addonUpdate == nil || addonUpdate.NewVersion == nil {
+		t.Errorf("expected update once the custom resource definitions are installed, got %v", addonUpdate)
+	}
+}
+
 func Test_NeedsRollingUpdate(t *testing.T) {
 	grid := []struct {
 		newAddon            *Addon
@@ -439,6 +573,83 @@ func Test_NeedsRollingUpdate(t *testing.T) {
 
 }
 
+func Test_AddNeedsUpdateRequest(t *testing.T) {
+	cilium := api.NeedsUpdateAddon{Name: "networking.cilium.io", Namespace: "kube-system", Version: "1.10.0"}
+	custom := api.NeedsUpdateAddon{Name: "custom.example.com", Namespace: "custom", Version: "1.0.0"}
+
+	grid := []struct {
+		annotations map[string]string
+		request     api.NeedsUpdateAddon
+		changed     bool
+		expected    string
+	}{
+		{
+			request:  cilium,
+			changed:  true,
+			expected: `{"bootID":"boot-1","addons":[{"name":"networking.cilium.io","namespace":"kube-system","version":"1.10.0"}]}`,
+		},
+		{
+			annotations: map[string]string{
+				"kops.k8s.io/needs-update": `{"bootID":"boot-1","addons":[{"name":"networking.cilium.io","namespace":"kube-system","version":"1.9.0"}]}`,
+			},
+			request:  custom,
+			changed:  true,
+			expected: `{"bootID":"boot-1","addons":[{"name":"networking.cilium.io","namespace":"kube-system","version":"1.9.0"},{"name":"custom.example.com","namespace":"custom","version":"1.0.0"}]}`,
+		},
+		{
+			annotations: map[string]string{
+				"kops.k8s.io/needs-update": `{"bootID":"boot-1","addons":[{"name":"networking.cilium.io","namespace":"kube-system","version":"1.9.0"}]}`,
+			},
+			request:  cilium,
+			changed:  true,
+			expected: `{"bootID":"boot-1","addons":[{"name":"networking.cilium.io","namespace":"kube-system","version":"1.10.0"}]}`,
+		},
+		{
+			annotations: map[string]string{
+				"kops.k8s.io/needs-update": `{"bootID":"boot-1","addons":[{"name":"networking.cilium.io","namespace":"kube-system","version":"1.10.0"}]}`,
+			},
+			request:  cilium,
+			expected: `{"bootID":"boot-1","addons":[{"name":"networking.cilium.io","namespace":"kube-system","version":"1.10.0"}]}`,
+		},
+		{
+			// The node has rebooted since the earlier request
+			annotations: map[string]string{
+				"kops.k8s.io/needs-update": `{"bootID":"boot-0","addons":[{"name":"custom.example.com","namespace":"custom","version":"0.9.0"}]}`,
+			},
+			request:  cilium,
+			changed:  true,
+			expected: `{"bootID":"boot-1","addons":[{"name":"networking.cilium.io","namespace":"kube-system","version":"1.10.0"}]}`,
+		},
+		{
+			// Annotated by hand
+			annotations: map[string]string{
+				"kops.k8s.io/needs-update": "",
+			},
+			request:  cilium,
+			expected: "",
+		},
+		{
+			annotations: map[string]string{
+				"kops.k8s.io/needs-update": "true",
+			},
+			request:  cilium,
+			changed:  true,
+			expected: `{"bootID":"boot-1","addons":[{"name":"networking.cilium.io","namespace":"kube-system","version":"1.10.0"}]}`,
+		},
+	}
+
+	for _, g := range grid {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node", Annotations: g.annotations},
+			Status:     corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{BootID: "boot-1"}},
+		}
+		changed, err := addNeedsUpdateRequest(node, g.request)
+		require.NoError(t, err)
+		assert.Equal(t, g.changed, changed, "annotations %v", g.annotations)
+		assert.Equal(t, g.expected, node.Annotations[api.NeedsUpdateAnnotation], "annotations %v", g.annotations)
+	}
+}
+
 func Test_InstallPKI(t *testing.T) {
 	ctx := context.Background()
 	kubeSystem := &corev1.Namespace{
@@ -25,18 +25,12 @@ import (
 	"strings"
 
 	"k8s.io/klog/v2"
-	"k8s.io/kops/util/pkg/vfs"
 )
 
-// Apply calls kubectl apply to apply the manifest.
+// Apply calls kubectl apply to apply the manifest, impersonating user if it is not empty.
 // We will likely in future change this to create things directly (or more likely embed this logic into kubectl itself)
-func Apply(manifest string) error {
-	// We copy the manifest to a temp file because it is likely e.g. an s3 URL, which kubectl can't read
-	data, err := vfs.Context.ReadFile(manifest)
-	if err != nil {
-		return fmt.Errorf("error reading manifest: %v", err)
-	}
-
+func Apply(data []byte, user string) error {
+	// We copy the manifest to a temp file because it is likely read from e.g. an s3 URL, which kubectl can't read
 	tmpDir, err := ioutil.TempDir("", "channel")
 	if err != nil {
 		return fmt.Errorf("error creating temp dir: %v", err)
@@ -53,7 +47,11 @@ func Apply(manifest string) error {
 		return fmt.Errorf("error writing temp file: %v", err)
 	}
 
-	_, err = execKubectl("apply", "-f", localManifestFile)
+	args := []string{"apply", "-f", localManifestFile}
+	if user != "" {
+		args = append(args, "--as="+user)
+	}
+	_, err = execKubectl(args...)
 	return err
 }
 
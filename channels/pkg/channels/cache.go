@@ -0,0 +1,262 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi/utils"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// Cache keeps a local copy of the last successfully fetched channels and addon manifests,
+// so that a transient outage of the channel location does not cause apply failures.
+// http and https locations are fetched with conditional requests against the cached copy.
+// A nil Cache reads directly from the source.
+type Cache struct {
+	// Dir is the directory holding the cached copies; empty keeps nothing between runs.
+	Dir string
+	// TTL is how long after it was fetched a cached copy may still be used; zero means no limit.
+	TTL time.Duration
+
+	// Client is the client used for http and https locations.
+	Client *http.Client
+	// Backoff is the retry policy for http and https requests failing with a timeout or a 5xx response.
+	Backoff wait.Backoff
+	// Stats counts the downloads done through the cache.
+	Stats DownloadStats
+	// statsMutex guards Stats while addons are applied concurrently.
+	statsMutex sync.Mutex
+
+	now func() time.Time
+}
+
+// NewCache returns a Cache storing its copies in dir.
+func NewCache(dir string, ttl time.Duration) *Cache {
+	return &Cache{
+		Dir: dir,
+		TTL: ttl,
+		Client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		// Exponential backoff, starting with 500 milliseconds, doubling each time, 5 steps.
+		// The jitter spreads the retries of the many nodes polling the same channel.
+		Backoff: wait.Backoff{
+			Duration: 500 * time.Millisecond,
+			Factor:   2,
+			Jitter:   1,
+			Steps:    5,
+		},
+		now: time.Now,
+	}
+}
+
+// cacheEntry is a cached copy of a file.
+type cacheEntry struct {
+	data    []byte
+	fetched time.Time
+	httpMetadata
+}
+
+// httpMetadata records how an http location was fetched, to revalidate the cached copy.
+type httpMetadata struct {
+	ETag         string     `json:"etag,omitempty"`
+	LastModified string     `json:"lastModified,omitempty"`
+	Expires      *time.Time `json:"expires,omitempty"`
+}
+
+// ReadFile reads the file at location, falling back to the cached copy if the read fails.
+// If manifestHash is not empty, only content matching it is cached or served from the cache.
+func (c *Cache) ReadFile(location string, manifestHash string) ([]byte, error) {
+	if c == nil {
+		return vfs.Context.ReadFile(location)
+	}
+
+	var cached *cacheEntry
+	if c.Dir != "" {
+		entry, err := c.read(location, manifestHash)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				klog.V(2).Infof("ignoring cached copy of %q: %v", location, err)
+			}
+		} else {
+			cached = entry
+		}
+	}
+
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		return c.readHTTP(location, manifestHash, cached)
+	}
+	data, err := vfs.Context.ReadFile(location)
+	return c.result(location, manifestHash, data, nil, err, cached)
+}
+
+// result caches data read successfully from location, or falls back to the cached copy if the read failed.
+// A nil meta means the data must not be revalidated over http.
+func (c *Cache) result(location string, manifestHash string, data []byte, meta *httpMetadata, err error, cached *cacheEntry) ([]byte, error) {
+	if err == nil {
+		if manifestHash != "" {
+			if actual := hashManifest(data); actual != manifestHash {
+				klog.Warningf("manifest %q has hash %q, expected %q; not caching it", location, actual, manifestHash)
+				return data, nil
+			}
+		}
+		if err := c.write(location, data, meta); err != nil {
+			klog.Warningf("error caching %q: %v", location, err)
+		}
+		return data, nil
+	}
+
+	if cached == nil {
+		c.record(func(s *DownloadStats) { s.Failures++ })
+		return nil, err
+	}
+	if c.TTL != 0 {
+		if age := c.now().Sub(cached.fetched); age > c.TTL {
+			klog.V(2).Infof("cached copy of %q expired %v ago", location, (age - c.TTL).Round(time.Second))
+			c.record(func(s *DownloadStats) { s.Failures++ })
+			return nil, err
+		}
+	}
+	klog.Warningf("error reading %q, using cached copy: %v", location, err)
+	c.record(func(s *DownloadStats) { s.Fallbacks++ })
+	return cached.data, nil
+}
+
+// record updates the stats.
+func (c *Cache) record(update func(stats *DownloadStats)) {
+	c.statsMutex.Lock()
+	defer c.statsMutex.Unlock()
+	update(&c.Stats)
+}
+
+// path returns the location of the cached copy of location.
+func (c *Cache) path(location string) string {
+	h := sha256.Sum256([]byte(location))
+	return filepath.Join(c.Dir, hex.EncodeToString(h[:]))
+}
+
+// read returns the cached copy of location, removing it if it doesn't match manifestHash.
+func (c *Cache) read(location string, manifestHash string) (*cacheEntry, error) {
+	p := c.path(location)
+	st, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	if manifestHash != "" {
+		if actual := hashManifest(data); actual != manifestHash {
+			c.remove(location)
+			return nil, fmt.Errorf("cached copy has hash %q, expected %q", actual, manifestHash)
+		}
+	}
+
+	entry := &cacheEntry{
+		data:    data,
+		fetched: st.ModTime(),
+	}
+	meta, err := ioutil.ReadFile(p + ".json")
+	if err == nil {
+		if err := json.Unmarshal(meta, &entry.httpMetadata); err != nil {
+			klog.Warningf("ignoring invalid cache metadata %q: %v", p+".json", err)
+			entry.httpMetadata = httpMetadata{}
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// write stores data and meta as the cached copy of location.
+func (c *Cache) write(location string, data []byte, meta *httpMetadata) error {
+	if c.Dir == "" {
+		return nil
+	}
+	p := c.path(location)
+	if meta == nil {
+		if err := os.Remove(p + ".json"); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	} else {
+		b, err := json.Marshal(meta)
+		if err != nil {
+			return err
+		}
+		if err := c.writeFile(p+".json", b); err != nil {
+			return err
+		}
+	}
+	if err := c.writeFile(p, data); err != nil {
+		return err
+	}
+	now := c.now()
+	return os.Chtimes(p, now, now)
+}
+
+// writeFile stores data at p, replacing it atomically so readers never see a partial file.
+func (c *Cache) writeFile(p string, data []byte) error {
+	if err := os.MkdirAll(c.Dir, 0700); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(c.Dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), p)
+}
+
+// remove deletes the cached copy of location.
+func (c *Cache) remove(location string) {
+	p := c.path(location)
+	for _, f := range []string{p, p + ".json"} {
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			klog.Warningf("error removing cached copy %q: %v", f, err)
+		}
+	}
+}
+
+// hashManifest computes the hash of a manifest the same way kops computes the addon's manifestHash.
+func hashManifest(data []byte) string {
+	hash, err := utils.HashString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return ""
+	}
+	return hash
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/kops/upup/pkg/fi/utils"
+)
+
+func TestCacheReadFile(t *testing.T) {
+	srcDir, err := ioutil.TempDir("", "channel-src")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(srcDir)
+	cacheDir, err := ioutil.TempDir("", "channel-cache")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(cacheDir)
+
+	manifest := "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: test\n"
+	manifestHash, err := utils.HashString("apiVersion: v1\nkind: Namespace\nmetadata:\n  name: test")
+	if err != nil {
+		t.Fatalf("error hashing manifest: %v", err)
+	}
+
+	location := filepath.Join(srcDir, "manifest.yaml")
+	now := time.Now()
+	cache := NewCache(cacheDir, time.Hour)
+	cache.now = func() time.Time { return now }
+
+	if _, err := cache.ReadFile(location, manifestHash); err == nil {
+		t.Fatalf("expected error reading missing manifest with empty cache")
+	}
+
+	if err := ioutil.WriteFile(location, []byte(manifest), 0644); err != nil {
+		t.Fatalf("error writing manifest: %v", err)
+	}
+	data, err := cache.ReadFile(location, manifestHash)
+	if err != nil {
+		t.Fatalf("unexpected error reading manifest: %v", err)
+	}
+	if string(data) != manifest {
+		t.Errorf("unexpected manifest %q", data)
+	}
+
+	// The source becomes unavailable; the cached copy is used
+	if err := os.Remove(location); err != nil {
+		t.Fatalf("error removing manifest: %v", err)
+	}
+	data, err = cache.ReadFile(location, manifestHash)
+	if err != nil {
+		t.Fatalf("expected cached copy, got error: %v", err)
+	}
+	if string(data) != manifest {
+		t.Errorf("unexpected cached manifest %q", data)
+	}
+
+	// A cached copy not matching the expected hash is never used
+	if _, err := cache.ReadFile(location, "deadbeef"); err == nil {
+		t.Errorf("expected error using cached copy with a different hash")
+	}
+	if _, err := os.Stat(cache.path(location)); !os.IsNotExist(err) {
+		t.Errorf("expected cached copy with a different hash to be removed, got %v", err)
+	}
+}
+
+func TestCacheExpiry(t *testing.T) {
+	srcDir, err := ioutil.TempDir("", "channel-src")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(srcDir)
+	cacheDir, err := ioutil.TempDir("", "channel-cache")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(cacheDir)
+
+	location := filepath.Join(srcDir, "addons.yaml")
+	if err := ioutil.WriteFile(location, []byte("kind: Addons\n"), 0644); err != nil {
+		t.Fatalf("error writing channel: %v", err)
+	}
+
+	now := time.Now()
+	cache := NewCache(cacheDir, time.Hour)
+	cache.now = func() time.Time { return now }
+	if _, err := cache.ReadFile(location, ""); err != nil {
+		t.Fatalf("unexpected error reading channel: %v", err)
+	}
+	if err := os.Remove(location); err != nil {
+		t.Fatalf("error removing channel: %v", err)
+	}
+
+	now = now.Add(30 * time.Minute)
+	if _, err := cache.ReadFile(location, ""); err != nil {
+		t.Errorf("expected cached copy within TTL, got error: %v", err)
+	}
+
+	now = now.Add(time.Hour)
+	if _, err := cache.ReadFile(location, ""); err == nil {
+		t.Errorf("expected error using cached copy after TTL")
+	}
+
+	cache.TTL = 0
+	if _, err := cache.ReadFile(location, ""); err != nil {
+		t.Errorf("expected cached copy without TTL, got error: %v", err)
+	}
+}
+
+func TestCacheSkipsMismatchedManifest(t *testing.T) {
+	srcDir, err := ioutil.TempDir("", "channel-src")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(srcDir)
+	cacheDir, err := ioutil.TempDir("", "channel-cache")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(cacheDir)
+
+	location := filepath.Join(srcDir, "manifest.yaml")
+	if err := ioutil.WriteFile(location, []byte("kind: ConfigMap\n"), 0644); err != nil {
+		t.Fatalf("error writing manifest: %v", err)
+	}
+
+	cache := NewCache(cacheDir, 0)
+	if _, err := cache.ReadFile(location, "deadbeef"); err != nil {
+		t.Fatalf("unexpected error reading manifest: %v", err)
+	}
+	if _, err := os.Stat(cache.path(location)); !os.IsNotExist(err) {
+		t.Errorf("expected manifest with a different hash not to be cached, got %v", err)
+	}
+}
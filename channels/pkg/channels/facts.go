@@ -0,0 +1,73 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+
+	"github.com/blang/semver/v4"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kops/channels/pkg/api"
+	"k8s.io/kops/upup/pkg/fi/utils"
+)
+
+// controlPlaneRoleLabels are the labels identifying control-plane nodes
+var controlPlaneRoleLabels = []string{"node-role.kubernetes.io/master", "node-role.kubernetes.io/control-plane"}
+
+// LoadClusterFacts returns the facts that addon conditions are evaluated against:
+// those in factsFile, written by nodeup on control-plane nodes, completed from the state of the cluster.
+func LoadClusterFacts(ctx context.Context, k8sClient kubernetes.Interface, factsFile string, kubernetesVersion semver.Version) (*api.ClusterFacts, error) {
+	facts := &api.ClusterFacts{}
+	if factsFile != "" {
+		data, err := ioutil.ReadFile(factsFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading cluster facts: %v", err)
+		}
+		if err := utils.YamlUnmarshal(data, facts); err != nil {
+			return nil, fmt.Errorf("error parsing cluster facts %q: %v", factsFile, err)
+		}
+	}
+	facts.KubernetesVersion = kubernetesVersion.String()
+
+	// Serve the list from the apiserver cache, as large clusters poll their channels frequently
+	nodes, err := k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{ResourceVersion: "0"})
+	if err != nil {
+		return nil, fmt.Errorf("error listing nodes: %v", err)
+	}
+	facts.NodeCount = len(nodes.Items)
+	facts.ControlPlaneCount = 0
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		for _, label := range controlPlaneRoleLabels {
+			if _, found := node.Labels[label]; found {
+				facts.ControlPlaneCount++
+				break
+			}
+		}
+		if facts.CloudProvider == "" && node.Spec.ProviderID != "" {
+			if u, err := url.Parse(node.Spec.ProviderID); err == nil {
+				facts.CloudProvider = u.Scheme
+			}
+		}
+	}
+
+	return facts, nil
+}
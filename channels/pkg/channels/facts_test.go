@@ -0,0 +1,148 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakekubernetes "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kops/channels/pkg/api"
+)
+
+func Test_LoadClusterFacts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "facts")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	factsFile := filepath.Join(dir, "cluster-facts.yaml")
+	require.NoError(t, ioutil.WriteFile(factsFile, []byte("clusterName: test.example.com\nnetworking: cilium\nfeatureGates:\n  EphemeralContainers: true\n"), 0644))
+
+	node := func(name string, labels map[string]string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Spec:       corev1.NodeSpec{ProviderID: "aws:///us-test-1a/i-" + name},
+		}
+	}
+	fakek8s := fakekubernetes.NewSimpleClientset(
+		node("a", map[string]string{"node-role.kubernetes.io/master": ""}),
+		node("b", map[string]string{"node-role.kubernetes.io/control-plane": ""}),
+		node("c", nil),
+	)
+
+	facts, err := LoadClusterFacts(context.Background(), fakek8s, factsFile, semver.MustParse("1.21.2"))
+	require.NoError(t, err)
+	assert.Equal(t, &api.ClusterFacts{
+		ClusterName:       "test.example.com",
+		CloudProvider:     "aws",
+		Networking:        "cilium",
+		KubernetesVersion: "1.21.2",
+		NodeCount:         3,
+		ControlPlaneCount: 2,
+		FeatureGates:      map[string]bool{"EphemeralContainers": true},
+	}, facts)
+}
+
+func Test_GetCurrentConditions(t *testing.T) {
+	channel := `
+kind: Addons
+metadata:
+  name: test
+spec:
+  addons:
+  - name: ebs-csi
+    version: 1.0.0
+    condition: cloudProvider == "aws"
+  - name: pd-csi
+    version: 1.0.0
+    condition: cloudProvider == "gce"
+  - name: hubble
+    version: 1.0.0
+    condition: networking == "cilium" && nodeCount >= 3
+  - name: always
+    version: 1.0.0
+`
+	location, err := url.Parse("file://x/y/z")
+	require.NoError(t, err)
+	addons, err := ParseAddons("test", location, []byte(channel))
+	require.NoError(t, err)
+	assert.True(t, addons.HasConditions())
+
+	grid := []struct {
+		Facts    *api.ClusterFacts
+		Expected []string
+	}{
+		{
+			Facts:    nil,
+			Expected: []string{"always"},
+		},
+		{
+			Facts:    &api.ClusterFacts{CloudProvider: "aws", Networking: "cilium", NodeCount: 5},
+			Expected: []string{"always", "ebs-csi", "hubble"},
+		},
+		{
+			Facts:    &api.ClusterFacts{CloudProvider: "gce", Networking: "cilium", NodeCount: 1},
+			Expected: []string{"always", "pd-csi"},
+		},
+	}
+	for _, g := range grid {
+		menu, err := addons.GetCurrent(semver.MustParse("1.21.0"), g.Facts, nil)
+		require.NoError(t, err)
+		assert.Equal(t, g.Expected, sortedAddonNames(menu.Addons), "facts %+v", g.Facts)
+	}
+}
+
+func Test_GetCurrentInvalidCondition(t *testing.T) {
+	channel := `
+kind: Addons
+metadata:
+  name: test
+spec:
+  addons:
+  - name: invalid
+    version: 1.0.0
+    condition: nodeCount >
+  - name: unevaluable
+    version: 1.0.0
+    condition: nodeCount > "3"
+`
+	location, err := url.Parse("file://x/y/z")
+	require.NoError(t, err)
+	addons, err := ParseAddons("test", location, []byte(channel))
+	require.NoError(t, err)
+
+	for _, facts := range []*api.ClusterFacts{nil, {CloudProvider: "aws", NodeCount: 5}} {
+		_, err = addons.GetCurrent(semver.MustParse("1.21.0"), facts, nil)
+		if assert.Error(t, err, "facts %+v", facts) {
+			assert.Contains(t, err.Error(), "condition of addon", "facts %+v", facts)
+		}
+	}
+
+	addons.APIObject.Spec.Addons = addons.APIObject.Spec.Addons[1:]
+	_, err = addons.GetCurrent(semver.MustParse("1.21.0"), &api.ClusterFacts{NodeCount: 5}, nil)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "unable to evaluate condition of addon \"unevaluable\"")
+	}
+}
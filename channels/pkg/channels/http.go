@@ -0,0 +1,210 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// DownloadStats counts the work done fetching channels and manifests.
+type DownloadStats struct {
+	// Requests is the number of http requests sent, including retries.
+	Requests int
+	// Retries is the number of http requests retried after a timeout or a 5xx response.
+	Retries int
+	// Downloads is the number of files downloaded in full.
+	Downloads int
+	// DownloadedBytes is the size of the files downloaded in full.
+	DownloadedBytes int64
+	// NotModified is the number of cached copies revalidated with a 304 response.
+	NotModified int
+	// Fresh is the number of cached copies used without a request, as allowed by Cache-Control.
+	Fresh int
+	// Fallbacks is the number of cached copies used because their location could not be read.
+	Fallbacks int
+	// Failures is the number of files that could be neither read nor served from the cache.
+	Failures int
+}
+
+// String returns a human readable summary of the stats.
+func (s *DownloadStats) String() string {
+	return fmt.Sprintf("%d requests (%d retries), %d downloads (%d bytes), %d not modified, %d fresh in cache, %d cache fallbacks, %d failures",
+		s.Requests, s.Retries, s.Downloads, s.DownloadedBytes, s.NotModified, s.Fresh, s.Fallbacks, s.Failures)
+}
+
+// WriteMetrics writes the stats in the Prometheus text format, for the node-exporter textfile collector.
+func (s *DownloadStats) WriteMetrics(w io.Writer) error {
+	metrics := []struct {
+		name  string
+		help  string
+		value int64
+	}{
+		{"channels_http_requests_total", "HTTP requests sent fetching channels and manifests, including retries.", int64(s.Requests)},
+		{"channels_http_retries_total", "HTTP requests retried after a timeout or a 5xx response.", int64(s.Retries)},
+		{"channels_downloads_total", "Channels and manifests downloaded in full.", int64(s.Downloads)},
+		{"channels_downloaded_bytes_total", "Size of the channels and manifests downloaded in full.", s.DownloadedBytes},
+		{"channels_not_modified_total", "Cached channels and manifests revalidated with a 304 response.", int64(s.NotModified)},
+		{"channels_cache_fresh_total", "Cached channels and manifests used without a request, as allowed by Cache-Control.", int64(s.Fresh)},
+		{"channels_cache_fallbacks_total", "Cached channels and manifests used because their location could not be read.", int64(s.Fallbacks)},
+		{"channels_fetch_failures_total", "Channels and manifests that could be neither read nor served from the cache.", int64(s.Failures)},
+	}
+	for _, m := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", m.name, m.help, m.name, m.name, m.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readHTTP fetches an http or https location, revalidating the cached copy if there is one.
+func (c *Cache) readHTTP(location string, manifestHash string, cached *cacheEntry) ([]byte, error) {
+	if cached != nil && cached.Expires != nil && c.now().Before(*cached.Expires) {
+		klog.V(4).Infof("using cached copy of %q, fresh until %v", location, cached.Expires)
+		c.record(func(s *DownloadStats) { s.Fresh++ })
+		return cached.data, nil
+	}
+
+	var response *http.Response
+	var body []byte
+	attempt := 0
+	done, err := vfs.RetryWithBackoff(c.Backoff, func() (bool, error) {
+		if attempt != 0 {
+			c.record(func(s *DownloadStats) { s.Retries++ })
+		}
+		attempt++
+
+		req, err := http.NewRequest("GET", location, nil)
+		if err != nil {
+			return true, err
+		}
+		if cached != nil {
+			if cached.ETag != "" {
+				req.Header.Set("If-None-Match", cached.ETag)
+			}
+			if cached.LastModified != "" {
+				req.Header.Set("If-Modified-Since", cached.LastModified)
+			}
+		}
+
+		klog.V(4).Infof("Performing HTTP request: GET %s", location)
+		c.record(func(s *DownloadStats) { s.Requests++ })
+		response, err = c.Client.Do(req)
+		if err != nil {
+			// Retry on timeouts and connection errors
+			return false, fmt.Errorf("error fetching %q: %v", location, err)
+		}
+		defer response.Body.Close()
+		body, err = ioutil.ReadAll(response.Body)
+		if err != nil {
+			return false, fmt.Errorf("error reading response for %q: %v", location, err)
+		}
+
+		switch {
+		case response.StatusCode == http.StatusOK:
+			return true, nil
+		case response.StatusCode == http.StatusNotModified && cached != nil:
+			return true, nil
+		case response.StatusCode == http.StatusNotFound:
+			return true, os.ErrNotExist
+		case response.StatusCode == http.StatusTooManyRequests, response.StatusCode >= 500 && response.StatusCode <= 599:
+			return false, fmt.Errorf("unexpected response code %q for %q: %v", response.Status, location, string(body))
+		default:
+			return true, fmt.Errorf("unexpected response code %q for %q: %v", response.Status, location, string(body))
+		}
+	})
+	if err == nil && !done {
+		// Shouldn't happen - we always return a non-nil error with false
+		err = wait.ErrWaitTimeout
+	}
+	if err != nil {
+		return c.result(location, manifestHash, nil, nil, err, cached)
+	}
+
+	meta, store := c.parseCacheHeaders(response.Header)
+	if response.StatusCode == http.StatusNotModified {
+		klog.V(4).Infof("cached copy of %q is not modified", location)
+		c.record(func(s *DownloadStats) { s.NotModified++ })
+		if meta.ETag == "" {
+			meta.ETag = cached.ETag
+		}
+		if meta.LastModified == "" {
+			meta.LastModified = cached.LastModified
+		}
+		body = cached.data
+	} else {
+		c.record(func(s *DownloadStats) {
+			s.Downloads++
+			s.DownloadedBytes += int64(len(body))
+		})
+	}
+
+	if !store {
+		c.remove(location)
+		return body, nil
+	}
+	return c.result(location, manifestHash, body, meta, nil, cached)
+}
+
+// parseCacheHeaders returns the metadata to revalidate a response, and whether Cache-Control allows storing it.
+func (c *Cache) parseCacheHeaders(header http.Header) (*httpMetadata, bool) {
+	meta := &httpMetadata{
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+	}
+
+	noStore, noCache := false, false
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		switch {
+		case directive == "no-store":
+			noStore = true
+		case directive == "no-cache":
+			noCache = true
+		case strings.HasPrefix(directive, "max-age="):
+			maxAge, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+			if err != nil {
+				continue
+			}
+			if age, err := strconv.Atoi(header.Get("Age")); err == nil && age > 0 {
+				maxAge -= age
+			}
+			if maxAge > 0 {
+				expires := c.now().Add(time.Duration(maxAge) * time.Second)
+				meta.Expires = &expires
+			}
+		}
+	}
+	if noStore {
+		return meta, false
+	}
+	if noCache {
+		// The cached copy must be revalidated before each use
+		meta.Expires = nil
+	}
+	return meta, true
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// testChannelServer serves a channel, counting requests and failing the first ones as configured.
+type testChannelServer struct {
+	body         string
+	etag         string
+	cacheControl string
+	failures     int
+	requests     int
+}
+
+func (s *testChannelServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.requests++
+	if s.failures > 0 {
+		s.failures--
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	if s.cacheControl != "" {
+		w.Header().Set("Cache-Control", s.cacheControl)
+	}
+	w.Header().Set("ETag", s.etag)
+	if r.Header.Get("If-None-Match") == s.etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	fmt.Fprint(w, s.body)
+}
+
+func newTestCache(dir string) *Cache {
+	cache := NewCache(dir, time.Hour)
+	cache.Backoff = wait.Backoff{
+		Duration: time.Millisecond,
+		Factor:   1,
+		Steps:    3,
+	}
+	return cache
+}
+
+func TestCacheConditionalRequests(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "channel-cache")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(cacheDir)
+
+	s := &testChannelServer{body: "kind: Addons\n", etag: `"v1"`}
+	server := httptest.NewServer(s)
+	defer server.Close()
+	location := server.URL + "/addons.yaml"
+
+	cache := newTestCache(cacheDir)
+	for i := 0; i < 2; i++ {
+		data, err := cache.ReadFile(location, "")
+		if err != nil {
+			t.Fatalf("unexpected error reading channel: %v", err)
+		}
+		if string(data) != s.body {
+			t.Errorf("unexpected channel %q", data)
+		}
+	}
+	if cache.Stats.Downloads != 1 || cache.Stats.NotModified != 1 || s.requests != 2 {
+		t.Errorf("expected one download and one revalidation, got %d requests and stats %v", s.requests, &cache.Stats)
+	}
+
+	// A new etag is downloaded again
+	s.body = "kind: Addons\nmetadata:\n  name: updated\n"
+	s.etag = `"v2"`
+	data, err := cache.ReadFile(location, "")
+	if err != nil {
+		t.Fatalf("unexpected error reading channel: %v", err)
+	}
+	if string(data) != s.body {
+		t.Errorf("unexpected channel %q", data)
+	}
+	if cache.Stats.Downloads != 2 {
+		t.Errorf("expected a second download, got stats %v", &cache.Stats)
+	}
+}
+
+func TestCacheControl(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "channel-cache")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(cacheDir)
+
+	s := &testChannelServer{body: "kind: Addons\n", etag: `"v1"`, cacheControl: "public, max-age=300"}
+	server := httptest.NewServer(s)
+	defer server.Close()
+	location := server.URL + "/addons.yaml"
+
+	now := time.Now()
+	cache := newTestCache(cacheDir)
+	cache.now = func() time.Time { return now }
+	for i := 0; i < 2; i++ {
+		if _, err := cache.ReadFile(location, ""); err != nil {
+			t.Fatalf("unexpected error reading channel: %v", err)
+		}
+	}
+	if s.requests != 1 || cache.Stats.Fresh != 1 {
+		t.Errorf("expected the fresh cached copy to be used without a request, got %d requests and stats %v", s.requests, &cache.Stats)
+	}
+
+	now = now.Add(10 * time.Minute)
+	if _, err := cache.ReadFile(location, ""); err != nil {
+		t.Fatalf("unexpected error reading channel: %v", err)
+	}
+	if s.requests != 2 || cache.Stats.NotModified != 1 {
+		t.Errorf("expected the stale cached copy to be revalidated, got %d requests and stats %v", s.requests, &cache.Stats)
+	}
+
+	// no-store responses are never cached
+	now = now.Add(10 * time.Minute)
+	s.cacheControl = "no-store"
+	s.etag = `"v2"`
+	if _, err := cache.ReadFile(location, ""); err != nil {
+		t.Fatalf("unexpected error reading channel: %v", err)
+	}
+	if _, err := os.Stat(cache.path(location)); !os.IsNotExist(err) {
+		t.Errorf("expected no-store response not to be cached, got %v", err)
+	}
+}
+
+func TestCacheRetries(t *testing.T) {
+	s := &testChannelServer{body: "kind: Addons\n", etag: `"v1"`, failures: 2}
+	server := httptest.NewServer(s)
+	defer server.Close()
+	location := server.URL + "/addons.yaml"
+
+	cache := newTestCache("")
+	data, err := cache.ReadFile(location, "")
+	if err != nil {
+		t.Fatalf("unexpected error reading channel: %v", err)
+	}
+	if string(data) != s.body {
+		t.Errorf("unexpected channel %q", data)
+	}
+	if s.requests != 3 || cache.Stats.Retries != 2 {
+		t.Errorf("expected two retries, got %d requests and stats %v", s.requests, &cache.Stats)
+	}
+
+	s.failures = 3
+	if _, err := cache.ReadFile(location, ""); err == nil {
+		t.Errorf("expected error once retries are exhausted")
+	}
+	if cache.Stats.Failures != 1 {
+		t.Errorf("expected a failure, got stats %v", &cache.Stats)
+	}
+}
+
+func TestDownloadStatsWriteMetrics(t *testing.T) {
+	stats := &DownloadStats{Requests: 3, Retries: 1, Downloads: 2, DownloadedBytes: 42}
+	var buf bytes.Buffer
+	if err := stats.WriteMetrics(&buf); err != nil {
+		t.Fatalf("unexpected error writing metrics: %v", err)
+	}
+	for _, expected := range []string{
+		"# TYPE channels_http_requests_total counter\nchannels_http_requests_total 3\n",
+		"channels_http_retries_total 1\n",
+		"channels_downloaded_bytes_total 42\n",
+		"channels_fetch_failures_total 0\n",
+	} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("expected metrics to contain %q, got:\n%s", expected, buf.String())
+		}
+	}
+}
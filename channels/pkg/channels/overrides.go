@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	"github.com/blang/semver/v4"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/channels/pkg/api"
+	"k8s.io/kops/upup/pkg/fi/utils"
+)
+
+// LoadAddonOverrides reads the addon overrides in p, written by nodeup on control-plane nodes, keyed by addon name.
+func LoadAddonOverrides(p string) (map[string]*api.AddonOverride, error) {
+	data, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("error reading addon overrides: %v", err)
+	}
+	var overrides []*api.AddonOverride
+	if err := utils.YamlUnmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("error parsing addon overrides %q: %v", p, err)
+	}
+
+	byName := make(map[string]*api.AddonOverride)
+	for _, o := range overrides {
+		if o == nil {
+			continue
+		}
+		if o.Name == "" {
+			return nil, fmt.Errorf("addon override in %q has no name", p)
+		}
+		if o.Version != "" {
+			if _, err := semver.ParseTolerant(o.Version); err != nil {
+				return nil, fmt.Errorf("addon override %q has unparseable version %q: %v", o.Name, o.Version, err)
+			}
+		}
+		byName[o.Name] = o
+	}
+	return byName, nil
+}
+
+// matchesOverride returns false if the addon is disabled, or is not the version it is pinned to
+func (s *Addon) matchesOverride(overrides map[string]*api.AddonOverride) bool {
+	o := overrides[s.Name]
+	if o == nil {
+		return true
+	}
+	if o.Disabled {
+		klog.V(4).Infof("Skipping disabled addon %q", s.Name)
+		return false
+	}
+	if o.Version != "" {
+		if s.Spec.Version == nil {
+			klog.V(4).Infof("Skipping addon %q without version, as it is pinned to %s", s.Name, o.Version)
+			return false
+		}
+		pinned, err := semver.ParseTolerant(o.Version)
+		if err != nil {
+			klog.Warningf("addon %q is pinned to unparseable version %q; skipping", s.Name, o.Version)
+			return false
+		}
+		version, err := semver.ParseTolerant(*s.Spec.Version)
+		if err != nil || !version.EQ(pinned) {
+			klog.V(4).Infof("Skipping version %q of addon %q, as it is pinned to %s", *s.Spec.Version, s.Name, o.Version)
+			return false
+		}
+	}
+	if o.ManifestHash != "" && s.Spec.ManifestHash != o.ManifestHash {
+		klog.V(4).Infof("Skipping manifest %q of addon %q, as it is pinned to manifest hash %s", s.Spec.ManifestHash, s.Name, o.ManifestHash)
+		return false
+	}
+	return true
+}
+
+// UnavailablePins describes the pinned addons that no channel in the menu provides at their pinned version.
+// They are left as installed.
+func (m *AddonMenu) UnavailablePins(overrides map[string]*api.AddonOverride) []string {
+	var messages []string
+	for _, name := range sortedOverrideNames(overrides) {
+		o := overrides[name]
+		if o.Disabled || (o.Version == "" && o.ManifestHash == "") {
+			continue
+		}
+		if m.Addons[name] == nil {
+			messages = append(messages, fmt.Sprintf("addon %q is pinned to %s, which no channel provides; leaving it as installed", name, describePin(o)))
+		}
+	}
+	return messages
+}
+
+// describePin returns what the override pins the addon to
+func describePin(o *api.AddonOverride) string {
+	if o.Version != "" && o.ManifestHash != "" {
+		return fmt.Sprintf("version %s with manifest hash %s", o.Version, o.ManifestHash)
+	}
+	if o.Version != "" {
+		return "version " + o.Version
+	}
+	return "manifest hash " + o.ManifestHash
+}
+
+func sortedOverrideNames(overrides map[string]*api.AddonOverride) []string {
+	var names []string
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
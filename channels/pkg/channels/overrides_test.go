@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/kops/channels/pkg/api"
+)
+
+func Test_LoadAddonOverrides(t *testing.T) {
+	dir := t.TempDir()
+
+	p := filepath.Join(dir, "addon-overrides.yaml")
+	require.NoError(t, ioutil.WriteFile(p, []byte(`
+- name: kube-dns.addons.k8s.io
+  disabled: true
+- name: coredns.addons.k8s.io
+  version: 1.8.3
+`), 0644))
+	overrides, err := LoadAddonOverrides(p)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*api.AddonOverride{
+		"kube-dns.addons.k8s.io": {Name: "kube-dns.addons.k8s.io", Disabled: true},
+		"coredns.addons.k8s.io":  {Name: "coredns.addons.k8s.io", Version: "1.8.3"},
+	}, overrides)
+
+	empty := filepath.Join(dir, "empty.yaml")
+	require.NoError(t, ioutil.WriteFile(empty, []byte("[]\n"), 0644))
+	overrides, err = LoadAddonOverrides(empty)
+	require.NoError(t, err)
+	assert.Empty(t, overrides)
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, ioutil.WriteFile(invalid, []byte("- name: coredns.addons.k8s.io\n  version: latest\n"), 0644))
+	_, err = LoadAddonOverrides(invalid)
+	assert.Error(t, err)
+
+	_, err = LoadAddonOverrides(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func Test_GetCurrentOverrides(t *testing.T) {
+	channel := `
+kind: Addons
+metadata:
+  name: test
+spec:
+  addons:
+  - name: kube-dns
+    version: 1.0.0
+  - name: coredns
+    version: 1.8.0
+    manifestHash: aaa
+  - name: coredns
+    version: 1.8.3
+    manifestHash: bbb
+  - name: coredns
+    version: 1.8.4
+    manifestHash: ccc
+  - name: metrics-server
+    version: 0.5.0
+    manifestHash: ddd
+`
+	location, err := url.Parse("file://x/y/z")
+	require.NoError(t, err)
+	addons, err := ParseAddons("test", location, []byte(channel))
+	require.NoError(t, err)
+
+	grid := []struct {
+		Overrides []*api.AddonOverride
+		Expected  map[string]string
+		Pins      []string
+	}{
+		{
+			Expected: map[string]string{"kube-dns": "1.0.0", "coredns": "1.8.4", "metrics-server": "0.5.0"},
+		},
+		{
+			Overrides: []*api.AddonOverride{{Name: "kube-dns", Disabled: true}},
+			Expected:  map[string]string{"coredns": "1.8.4", "metrics-server": "0.5.0"},
+		},
+		{
+			Overrides: []*api.AddonOverride{{Name: "coredns", Version: "v1.8.3"}},
+			Expected:  map[string]string{"kube-dns": "1.0.0", "coredns": "1.8.3", "metrics-server": "0.5.0"},
+		},
+		{
+			Overrides: []*api.AddonOverride{{Name: "coredns", ManifestHash: "aaa"}},
+			Expected:  map[string]string{"kube-dns": "1.0.0", "coredns": "1.8.0", "metrics-server": "0.5.0"},
+		},
+		{
+			Overrides: []*api.AddonOverride{{Name: "coredns", Version: "1.8.3", ManifestHash: "ccc"}},
+			Expected:  map[string]string{"kube-dns": "1.0.0", "metrics-server": "0.5.0"},
+			Pins:      []string{`addon "coredns" is pinned to version 1.8.3 with manifest hash ccc, which no channel provides; leaving it as installed`},
+		},
+		{
+			Overrides: []*api.AddonOverride{{Name: "metrics-server", ManifestHash: "eee"}, {Name: "other", Disabled: true}},
+			Expected:  map[string]string{"kube-dns": "1.0.0", "coredns": "1.8.4"},
+			Pins:      []string{`addon "metrics-server" is pinned to manifest hash eee, which no channel provides; leaving it as installed`},
+		},
+	}
+	for _, g := range grid {
+		overrides := make(map[string]*api.AddonOverride)
+		for _, o := range g.Overrides {
+			overrides[o.Name] = o
+		}
+		menu, err := addons.GetCurrent(semver.MustParse("1.21.0"), nil, overrides)
+		require.NoError(t, err)
+
+		actual := make(map[string]string)
+		for name, addon := range menu.Addons {
+			actual[name] = *addon.Spec.Version
+		}
+		assert.Equal(t, g.Expected, actual, "overrides %v", g.Overrides)
+		assert.Equal(t, g.Pins, menu.UnavailablePins(overrides), "overrides %v", g.Overrides)
+	}
+}
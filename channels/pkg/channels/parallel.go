@@ -0,0 +1,160 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	certmanager "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// AddonResult is the outcome of updating an addon
+type AddonResult struct {
+	Addon *Addon
+	// Update is the update that was applied, or nil if none was needed
+	Update *AddonUpdate
+	Err    error
+	// Duration is how long the update took
+	Duration time.Duration
+}
+
+// EnsureUpdatedAll updates the addons, applying up to parallelism of them concurrently.
+// An addon is only applied once the addons it depends on that are also being updated have been updated successfully;
+// addons whose dependencies failed are not applied.
+// onResult is called with the result of each addon as it completes, never concurrently.
+func EnsureUpdatedAll(ctx context.Context, k8sClient kubernetes.Interface, cmClient certmanager.Interface, addons []*Addon, parallelism int, onResult func(*AddonResult)) []*AddonResult {
+	return runParallel(addons, parallelism, func(addon *Addon) (*AddonUpdate, error) {
+		return addon.EnsureUpdated(ctx, k8sClient, cmClient)
+	}, onResult)
+}
+
+// runParallel runs update for the addons, following the scheduling of EnsureUpdatedAll
+func runParallel(addons []*Addon, parallelism int, update func(*Addon) (*AddonUpdate, error), onResult func(*AddonResult)) []*AddonResult {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	pending := make(map[string]*Addon)
+	for _, addon := range addons {
+		pending[addon.Name] = addon
+	}
+	// failed records whether each completed addon failed
+	failed := make(map[string]bool)
+
+	var results []*AddonResult
+	complete := func(result *AddonResult) {
+		failed[result.Addon.Name] = result.Err != nil
+		results = append(results, result)
+		if onResult != nil {
+			onResult(result)
+		}
+	}
+
+	resultCh := make(chan *AddonResult)
+	running := 0
+	for len(pending) != 0 || running != 0 {
+		for started := true; started; {
+			started = false
+			for _, name := range sortedAddonNames(pending) {
+				addon := pending[name]
+				ready := true
+				var failedDependencies []string
+				for _, dependency := range addon.Spec.DependsOn {
+					if _, found := pending[dependency]; found {
+						ready = false
+					} else if isFailed, completed := failed[dependency]; completed && isFailed {
+						failedDependencies = append(failedDependencies, dependency)
+					} else if !completed && isUpdating(addons, dependency) {
+						// Still running
+						ready = false
+					}
+				}
+				if len(failedDependencies) != 0 {
+					delete(pending, name)
+					complete(&AddonResult{
+						Addon: addon,
+						Err:   fmt.Errorf("not applied because dependencies %s failed", strings.Join(failedDependencies, ", ")),
+					})
+					started = true
+					continue
+				}
+				if !ready || running >= parallelism {
+					continue
+				}
+
+				delete(pending, name)
+				running++
+				started = true
+				go func(addon *Addon) {
+					klog.V(2).Infof("updating addon %q", addon.Name)
+					start := time.Now()
+					u, err := update(addon)
+					resultCh <- &AddonResult{
+						Addon:    addon,
+						Update:   u,
+						Err:      err,
+						Duration: time.Since(start),
+					}
+				}(addon)
+			}
+		}
+
+		if running == 0 {
+			// Nothing can start, so the remaining addons depend on each other
+			for _, name := range sortedAddonNames(pending) {
+				addon := pending[name]
+				delete(pending, name)
+				complete(&AddonResult{
+					Addon: addon,
+					Err:   fmt.Errorf("not applied because of a dependency cycle involving its dependencies %s", strings.Join(addon.Spec.DependsOn, ", ")),
+				})
+			}
+			break
+		}
+
+		result := <-resultCh
+		running--
+		complete(result)
+	}
+
+	return results
+}
+
+// isUpdating returns true if the named addon is one of the addons being updated
+func isUpdating(addons []*Addon, name string) bool {
+	for _, addon := range addons {
+		if addon.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedAddonNames(addons map[string]*Addon) []string {
+	var names []string
+	for name := range addons {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/kops/channels/pkg/api"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func parallelTestAddon(name string, dependsOn ...string) *Addon {
+	return &Addon{
+		Name: name,
+		Spec: &api.AddonSpec{
+			Name:      fi.String(name),
+			DependsOn: dependsOn,
+		},
+	}
+}
+
+func Test_RunParallel(t *testing.T) {
+	addons := []*Addon{
+		parallelTestAddon("a"),
+		parallelTestAddon("b"),
+		parallelTestAddon("c"),
+		parallelTestAddon("d"),
+		parallelTestAddon("e", "a", "b"),
+		parallelTestAddon("f", "e", "not-updated"),
+	}
+
+	var mutex sync.Mutex
+	running, maxRunning := 0, 0
+	finished := make(map[string]bool)
+	var errors []string
+	update := func(addon *Addon) (*AddonUpdate, error) {
+		mutex.Lock()
+		for _, dependency := range addon.Spec.DependsOn {
+			if dependency != "not-updated" && !finished[dependency] {
+				errors = append(errors, fmt.Sprintf("%s started before its dependency %s finished", addon.Name, dependency))
+			}
+		}
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mutex.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mutex.Lock()
+		running--
+		finished[addon.Name] = true
+		mutex.Unlock()
+		return &AddonUpdate{Name: addon.Name}, nil
+	}
+
+	var order []string
+	results := runParallel(addons, 2, update, func(result *AddonResult) {
+		order = append(order, result.Addon.Name)
+	})
+
+	for _, err := range errors {
+		t.Error(err)
+	}
+	if maxRunning != 2 {
+		t.Errorf("expected 2 addons to be applied concurrently, got %d", maxRunning)
+	}
+	if len(results) != len(addons) {
+		t.Fatalf("expected %d results, got %d", len(addons), len(results))
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			t.Errorf("unexpected error for %q: %v", result.Addon.Name, result.Err)
+		}
+		if result.Duration < 10*time.Millisecond {
+			t.Errorf("expected duration of %q to be measured, got %v", result.Addon.Name, result.Duration)
+		}
+	}
+	if order[len(order)-1] != "f" {
+		t.Errorf("expected f to be applied last, got order %v", order)
+	}
+}
+
+func Test_RunParallelFailures(t *testing.T) {
+	addons := []*Addon{
+		parallelTestAddon("a"),
+		parallelTestAddon("b", "a"),
+		parallelTestAddon("c", "b"),
+		parallelTestAddon("d"),
+		parallelTestAddon("x", "y"),
+		parallelTestAddon("y", "x"),
+	}
+
+	var mutex sync.Mutex
+	var applied []string
+	update := func(addon *Addon) (*AddonUpdate, error) {
+		mutex.Lock()
+		applied = append(applied, addon.Name)
+		mutex.Unlock()
+		if addon.Name == "a" {
+			return nil, fmt.Errorf("apply failed")
+		}
+		return &AddonUpdate{Name: addon.Name}, nil
+	}
+
+	actual := make(map[string]string)
+	runParallel(addons, 4, update, func(result *AddonResult) {
+		actual[result.Addon.Name] = "ok"
+		if result.Err != nil {
+			actual[result.Addon.Name] = result.Err.Error()
+		}
+	})
+
+	expected := map[string]string{
+		"a": "apply failed",
+		"b": "not applied because dependencies a failed",
+		"c": "not applied because dependencies b failed",
+		"d": "ok",
+		"x": "not applied because of a dependency cycle involving its dependencies y",
+		"y": "not applied because of a dependency cycle involving its dependencies x",
+	}
+	for name, expectedResult := range expected {
+		if actual[name] != expectedResult {
+			t.Errorf("expected result %q for %q, got %q", expectedResult, name, actual[name])
+		}
+	}
+	if len(applied) != 2 {
+		t.Errorf("expected only a and d to be applied, got %s", strings.Join(applied, ", "))
+	}
+}
@@ -20,12 +20,18 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/url"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/blang/semver/v4"
 	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/channels/pkg/api"
 	"k8s.io/kops/channels/pkg/channels"
 	"k8s.io/kops/util/pkg/tables"
 )
@@ -33,14 +39,31 @@ import (
 type ApplyChannelOptions struct {
 	Yes   bool
 	Files []string
+
+	// CacheDir is where the last successfully fetched channels and manifests are kept; empty disables the cache.
+	CacheDir string
+	// CacheTTL is how long a cached copy may be used when its source can't be read.
+	CacheTTL time.Duration
+	// FactsFile is the file holding the cluster facts that addon conditions are evaluated against.
+	FactsFile string
+	// OverridesFile is the file holding the addons that are disabled or pinned to a version.
+	OverridesFile string
+	// Parallelism is the maximum number of addons applied concurrently.
+	Parallelism int
+	// MetricsFile is where download metrics are written, in the Prometheus text format.
+	MetricsFile string
 }
 
 func NewCmdApplyChannel(f Factory, out io.Writer) *cobra.Command {
-	var options ApplyChannelOptions
+	options := ApplyChannelOptions{
+		CacheTTL:    24 * time.Hour,
+		Parallelism: 4,
+	}
 
 	cmd := &cobra.Command{
 		Use:   "channel",
 		Short: "Apply channel",
+		Long:  "Apply the addons of one or more channels. When several channels ship an addon of the same name, the addon from the channel listed last is applied, and files take precedence over channels.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.TODO()
 			return RunApplyChannel(ctx, f, out, &options, args)
@@ -49,6 +72,12 @@ func NewCmdApplyChannel(f Factory, out io.Writer) *cobra.Command {
 
 	cmd.Flags().BoolVar(&options.Yes, "yes", false, "Apply update")
 	cmd.Flags().StringSliceVarP(&options.Files, "filename", "f", []string{}, "Apply from a local file")
+	cmd.Flags().StringVar(&options.CacheDir, "cache-dir", options.CacheDir, "Directory caching the last fetched channels and manifests, used when their location can't be read")
+	cmd.Flags().DurationVar(&options.CacheTTL, "cache-ttl", options.CacheTTL, "How long a cached channel or manifest may be used after it was fetched (0 for no limit)")
+	cmd.Flags().StringVar(&options.FactsFile, "facts-file", options.FactsFile, "File with the cluster facts that addon conditions are evaluated against; the node count and kubernetes version are read from the cluster")
+	cmd.Flags().StringVar(&options.OverridesFile, "overrides-file", options.OverridesFile, "File with the addons that are disabled, or pinned to a version or manifest hash, whatever channel ships them")
+	cmd.Flags().IntVar(&options.Parallelism, "parallelism", options.Parallelism, "Maximum number of addons applied concurrently; addons wait for the addons listed in their dependsOn")
+	cmd.Flags().StringVar(&options.MetricsFile, "metrics-file", options.MetricsFile, "File to write download metrics to, in the Prometheus text format used by the node-exporter textfile collector")
 
 	return cmd
 }
@@ -77,7 +106,18 @@ func RunApplyChannel(ctx context.Context, f Factory, out io.Writer, options *App
 	// Remove Pre and Patch, as they make semver comparisons impractical
 	kubernetesVersion.Pre = nil
 
-	menu := channels.NewAddonMenu()
+	cache := channels.NewCache(options.CacheDir, options.CacheTTL)
+	defer func() {
+		klog.V(2).Infof("Fetched channels and manifests: %v", &cache.Stats)
+		if options.MetricsFile != "" {
+			if err := writeMetricsFile(options.MetricsFile, &cache.Stats); err != nil {
+				klog.Warningf("error writing metrics file %q: %v", options.MetricsFile, err)
+			}
+		}
+	}()
+
+	// Channels listed later take precedence over earlier ones, and files over channels
+	var loaded []*channels.Addons
 
 	for _, name := range args {
 		location, err := url.Parse(name)
@@ -96,16 +136,11 @@ func RunApplyChannel(ctx context.Context, f Factory, out io.Writer, options *App
 				return fmt.Errorf("unable to parse expanded argument %q as url", expanded)
 			}
 		}
-		o, err := channels.LoadAddons(name, location)
+		o, err := channels.LoadAddons(name, location, cache)
 		if err != nil {
 			return fmt.Errorf("error loading channel %q: %v", location, err)
 		}
-
-		current, err := o.GetCurrent(kubernetesVersion)
-		if err != nil {
-			return fmt.Errorf("error processing latest versions in %q: %v", location, err)
-		}
-		menu.MergeAddons(current)
+		loaded = append(loaded, o)
 	}
 
 	for _, f := range options.Files {
@@ -124,16 +159,48 @@ func RunApplyChannel(ctx context.Context, f Factory, out io.Writer, options *App
 			}
 			location = baseURL.ResolveReference(location)
 		}
-		o, err := channels.LoadAddons(f, location)
+		o, err := channels.LoadAddons(f, location, cache)
 		if err != nil {
 			return fmt.Errorf("error loading file %q: %v", f, err)
 		}
+		loaded = append(loaded, o)
+	}
+
+	// Only gather the cluster facts if some addon has a condition
+	var facts *api.ClusterFacts
+	for _, o := range loaded {
+		if o.HasConditions() {
+			facts, err = channels.LoadClusterFacts(ctx, k8sClient, options.FactsFile, kubernetesVersion)
+			if err != nil {
+				return err
+			}
+			break
+		}
+	}
 
-		current, err := o.GetCurrent(kubernetesVersion)
+	var overrides map[string]*api.AddonOverride
+	if options.OverridesFile != "" {
+		overrides, err = channels.LoadAddonOverrides(options.OverridesFile)
 		if err != nil {
-			return fmt.Errorf("error processing latest versions in %q: %v", f, err)
+			return err
 		}
-		menu.MergeAddons(current)
+	}
+
+	menu := channels.NewAddonMenu()
+	var conflicts []*channels.AddonConflict
+	for _, o := range loaded {
+		current, err := o.GetCurrent(kubernetesVersion, facts, overrides)
+		if err != nil {
+			return fmt.Errorf("error processing latest versions in %q: %v", o.ChannelName, err)
+		}
+		conflicts = append(conflicts, menu.OverrideAddons(current)...)
+	}
+
+	for _, conflict := range conflicts {
+		fmt.Printf("Warning: %v\n", conflict)
+	}
+	for _, message := range menu.UnavailablePins(overrides) {
+		fmt.Printf("Warning: %s\n", message)
 	}
 
 	var updates []*channels.AddonUpdate
@@ -197,22 +264,62 @@ func RunApplyChannel(ctx context.Context, f Factory, out io.Writer, options *App
 		return nil
 	}
 
-	for _, needUpdate := range needUpdates {
-		update, err := needUpdate.EnsureUpdated(ctx, k8sClient, cmClient)
-		if err != nil {
-			return fmt.Errorf("error updating %q: %v", needUpdate.Name, err)
+	dynamicClient, err := f.DynamicClient()
+	if err != nil {
+		return err
+	}
+	recorder := channels.NewAddonVersionRecorder(dynamicClient)
+
+	var failed []string
+	channels.EnsureUpdatedAll(ctx, k8sClient, cmClient, needUpdates, options.Parallelism, func(result *channels.AddonResult) {
+		duration := result.Duration.Round(time.Millisecond)
+		if result.Err != nil || result.Update != nil {
+			if err := recorder.Record(ctx, result.Addon, result.Duration, result.Err); err != nil {
+				klog.Warningf("error recording the apply of %q: %v", result.Addon.Name, err)
+			}
+		}
+		if result.Err != nil {
+			failed = append(failed, result.Addon.Name)
+			fmt.Printf("Error updating %q after %v: %v\n", result.Addon.Name, duration, result.Err)
+			return
 		}
+		update := result.Update
 		// Could have been a concurrent request
 		if update != nil {
 			if update.NewVersion != nil && update.NewVersion.Version != nil {
-				fmt.Printf("Updated %q to %s\n", update.Name, *update.NewVersion.Version)
+				fmt.Printf("Updated %q to %s in %v\n", update.Name, *update.NewVersion.Version, duration)
 			} else {
-				fmt.Printf("Updated %q\n", update.Name)
+				fmt.Printf("Updated %q in %v\n", update.Name, duration)
 			}
 		}
+	})
+
+	if len(failed) != 0 {
+		sort.Strings(failed)
+		return fmt.Errorf("error updating %s", strings.Join(failed, ", "))
 	}
 
 	fmt.Printf("\n")
 
 	return nil
 }
+
+// writeMetricsFile writes the download metrics to p, replacing it atomically so collectors never see a partial file.
+func writeMetricsFile(p string, stats *channels.DownloadStats) error {
+	f, err := ioutil.TempFile(filepath.Dir(p), ".channels-metrics-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := stats.WriteMetrics(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), p)
+}
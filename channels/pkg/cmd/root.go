@@ -24,6 +24,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"k8s.io/kops/pkg/logformat"
 )
 
 type CmdRootOptions struct {
@@ -48,6 +49,9 @@ func NewCmdRoot(f Factory, out io.Writer) *cobra.Command {
 		Short:         "channels applies software from a channel",
 		SilenceErrors: true,
 		SilenceUsage:  true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return logformat.Apply()
+		},
 	}
 
 	cmd.PersistentFlags().AddGoFlagSet(goflag.CommandLine)
@@ -0,0 +1,630 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package condition implements the expression language of addon conditions. Its syntax borrows from CEL,
+// but it is a small, separate language rather than an implementation of the CEL specification: literals,
+// lists and maps, field selection and indexing, the arithmetic, comparison, membership, logical and
+// conditional operators, the has() macro and the size, startsWith, endsWith, contains and matches functions.
+package condition
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Program is a parsed expression
+type Program struct {
+	expr      string
+	root      node
+	variables map[string]bool
+}
+
+// Compile parses expr
+func Compile(expr string) (*Program, error) {
+	root, variables, err := parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing expression %q: %v", expr, err)
+	}
+	return &Program{expr: expr, root: root, variables: variables}, nil
+}
+
+// Variables returns the variables referenced by the expression, sorted
+func (p *Program) Variables() []string {
+	var names []string
+	for name := range p.variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Eval evaluates the expression with the given variables.
+// Variables may be nil, bools, integers, floats, strings, and slices and string-keyed maps of those.
+func (p *Program) Eval(vars map[string]interface{}) (interface{}, error) {
+	activation := make(map[string]interface{}, len(vars))
+	for k, v := range vars {
+		activation[k] = normalize(reflect.ValueOf(v))
+	}
+	v, err := p.root.eval(activation)
+	if err != nil {
+		return nil, fmt.Errorf("error evaluating expression %q: %v", p.expr, err)
+	}
+	return v, nil
+}
+
+// EvalBool evaluates the expression, which must return a bool
+func (p *Program) EvalBool(vars map[string]interface{}) (bool, error) {
+	v, err := p.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression %q returned %s, expected bool", p.expr, typeName(v))
+	}
+	return b, nil
+}
+
+// normalize converts a go value to the types used during evaluation:
+// nil, bool, int64, float64, string, []interface{} and map[string]interface{}
+func normalize(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return normalize(v.Elem())
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.String:
+		return v.String()
+	case reflect.Slice, reflect.Array:
+		l := make([]interface{}, v.Len())
+		for i := range l {
+			l[i] = normalize(v.Index(i))
+		}
+		return l
+	case reflect.Map:
+		m := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m[fmt.Sprint(iter.Key().Interface())] = normalize(iter.Value())
+		}
+		return m
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case int64:
+		return "int"
+	case float64:
+		return "double"
+	case string:
+		return "string"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "map"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+type node interface {
+	eval(vars map[string]interface{}) (interface{}, error)
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n *literalNode) eval(vars map[string]interface{}) (interface{}, error) {
+	return n.value, nil
+}
+
+type identNode struct {
+	name string
+}
+
+func (n *identNode) eval(vars map[string]interface{}) (interface{}, error) {
+	v, found := vars[n.name]
+	if !found {
+		return nil, fmt.Errorf("undeclared reference to '%s'", n.name)
+	}
+	return v, nil
+}
+
+type selectNode struct {
+	operand node
+	field   string
+}
+
+func (n *selectNode) eval(vars map[string]interface{}) (interface{}, error) {
+	operand, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := operand.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("can't select field '%s' of %s", n.field, typeName(operand))
+	}
+	v, found := m[n.field]
+	if !found {
+		return nil, fmt.Errorf("no such key: %s", n.field)
+	}
+	return v, nil
+}
+
+type hasNode struct {
+	selection *selectNode
+}
+
+func (n *hasNode) eval(vars map[string]interface{}) (interface{}, error) {
+	operand, err := n.selection.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := operand.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("has() can't test field '%s' of %s", n.selection.field, typeName(operand))
+	}
+	_, found := m[n.selection.field]
+	return found, nil
+}
+
+type indexNode struct {
+	operand node
+	index   node
+}
+
+func (n *indexNode) eval(vars map[string]interface{}) (interface{}, error) {
+	operand, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	index, err := n.index.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch operand := operand.(type) {
+	case []interface{}:
+		i, ok := index.(int64)
+		if !ok {
+			return nil, fmt.Errorf("list index must be int, got %s", typeName(index))
+		}
+		if i < 0 || i >= int64(len(operand)) {
+			return nil, fmt.Errorf("index %d out of range for list of size %d", i, len(operand))
+		}
+		return operand[i], nil
+	case map[string]interface{}:
+		key, ok := index.(string)
+		if !ok {
+			return nil, fmt.Errorf("map key must be string, got %s", typeName(index))
+		}
+		v, found := operand[key]
+		if !found {
+			return nil, fmt.Errorf("no such key: %s", key)
+		}
+		return v, nil
+	}
+	return nil, fmt.Errorf("can't index %s", typeName(operand))
+}
+
+type listNode struct {
+	elements []node
+}
+
+func (n *listNode) eval(vars map[string]interface{}) (interface{}, error) {
+	l := make([]interface{}, 0, len(n.elements))
+	for _, element := range n.elements {
+		v, err := element.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, v)
+	}
+	return l, nil
+}
+
+type mapNode struct {
+	keys   []node
+	values []node
+}
+
+func (n *mapNode) eval(vars map[string]interface{}) (interface{}, error) {
+	m := make(map[string]interface{}, len(n.keys))
+	for i := range n.keys {
+		k, err := n.keys[i].eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			return nil, fmt.Errorf("map key must be string, got %s", typeName(k))
+		}
+		v, err := n.values[i].eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		m[key] = v
+	}
+	return m, nil
+}
+
+type conditionalNode struct {
+	condition node
+	then      node
+	otherwise node
+}
+
+func (n *conditionalNode) eval(vars map[string]interface{}) (interface{}, error) {
+	c, err := n.condition.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := c.(bool)
+	if !ok {
+		return nil, fmt.Errorf("condition of ?: must be bool, got %s", typeName(c))
+	}
+	if b {
+		return n.then.eval(vars)
+	}
+	return n.otherwise.eval(vars)
+}
+
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+func (n *unaryNode) eval(vars map[string]interface{}) (interface{}, error) {
+	v, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "!":
+		if b, ok := v.(bool); ok {
+			return !b, nil
+		}
+	case "-":
+		switch v := v.(type) {
+		case int64:
+			return -v, nil
+		case float64:
+			return -v, nil
+		}
+	}
+	return nil, fmt.Errorf("no such overload: %s%s", n.op, typeName(v))
+}
+
+type binaryNode struct {
+	op    string
+	left  node
+	right node
+}
+
+func (n *binaryNode) eval(vars map[string]interface{}) (interface{}, error) {
+	if n.op == "&&" || n.op == "||" {
+		return n.evalLogical(vars)
+	}
+
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	case "in":
+		switch container := right.(type) {
+		case []interface{}:
+			for _, element := range container {
+				if equal(left, element) {
+					return true, nil
+				}
+			}
+			return false, nil
+		case map[string]interface{}:
+			key, ok := left.(string)
+			if !ok {
+				return false, nil
+			}
+			_, found := container[key]
+			return found, nil
+		}
+	case "<", "<=", ">", ">=":
+		if c, ok := compare(left, right); ok {
+			switch n.op {
+			case "<":
+				return c < 0, nil
+			case "<=":
+				return c <= 0, nil
+			case ">":
+				return c > 0, nil
+			default:
+				return c >= 0, nil
+			}
+		}
+	default:
+		if v, ok, err := arithmetic(n.op, left, right); ok {
+			return v, err
+		}
+	}
+	return nil, fmt.Errorf("no such overload: %s %s %s", typeName(left), n.op, typeName(right))
+}
+
+// evalLogical evaluates && and || such that errors are absorbed
+// if the other operand determines the result
+func (n *binaryNode) evalLogical(vars map[string]interface{}) (interface{}, error) {
+	// The value that determines the result regardless of the other operand
+	decisive := n.op == "||"
+
+	left, leftErr := n.left.eval(vars)
+	if b, ok := left.(bool); ok && leftErr == nil && b == decisive {
+		return decisive, nil
+	}
+	right, rightErr := n.right.eval(vars)
+	if b, ok := right.(bool); ok && rightErr == nil && b == decisive {
+		return decisive, nil
+	}
+	if leftErr != nil {
+		return nil, leftErr
+	}
+	if rightErr != nil {
+		return nil, rightErr
+	}
+	if _, ok := left.(bool); !ok {
+		return nil, fmt.Errorf("no such overload: %s %s %s", typeName(left), n.op, typeName(right))
+	}
+	if _, ok := right.(bool); !ok {
+		return nil, fmt.Errorf("no such overload: %s %s %s", typeName(left), n.op, typeName(right))
+	}
+	return !decisive, nil
+}
+
+// equal compares values, comparing numbers by value regardless of their type
+func equal(a, b interface{}) bool {
+	if c, ok := compareNumbers(a, b); ok {
+		return c == 0
+	}
+	switch a := a.(type) {
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, v := range a {
+			w, found := b[k]
+			if !found || !equal(v, w) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
+
+func compareNumbers(a, b interface{}) (int, bool) {
+	toFloat := func(v interface{}) (float64, bool) {
+		switch v := v.(type) {
+		case int64:
+			return float64(v), true
+		case float64:
+			return v, true
+		}
+		return 0, false
+	}
+	if a, ok := a.(int64); ok {
+		if b, ok := b.(int64); ok {
+			switch {
+			case a < b:
+				return -1, true
+			case a > b:
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+	x, ok := toFloat(a)
+	if !ok {
+		return 0, false
+	}
+	y, ok := toFloat(b)
+	if !ok || math.IsNaN(x) || math.IsNaN(y) {
+		return 0, false
+	}
+	switch {
+	case x < y:
+		return -1, true
+	case x > y:
+		return 1, true
+	}
+	return 0, true
+}
+
+// compare orders numbers and strings
+func compare(a, b interface{}) (int, bool) {
+	if c, ok := compareNumbers(a, b); ok {
+		return c, true
+	}
+	if a, ok := a.(string); ok {
+		if b, ok := b.(string); ok {
+			return strings.Compare(a, b), true
+		}
+	}
+	return 0, false
+}
+
+// arithmetic applies an arithmetic operator; ok is false if there is no overload for the operand types
+func arithmetic(op string, a, b interface{}) (interface{}, bool, error) {
+	switch a := a.(type) {
+	case int64:
+		b, ok := b.(int64)
+		if !ok {
+			return nil, false, nil
+		}
+		switch op {
+		case "+":
+			return a + b, true, nil
+		case "-":
+			return a - b, true, nil
+		case "*":
+			return a * b, true, nil
+		case "/", "%":
+			if b == 0 {
+				return nil, true, fmt.Errorf("division by zero")
+			}
+			if op == "/" {
+				return a / b, true, nil
+			}
+			return a % b, true, nil
+		}
+	case float64:
+		b, ok := b.(float64)
+		if !ok {
+			return nil, false, nil
+		}
+		switch op {
+		case "+":
+			return a + b, true, nil
+		case "-":
+			return a - b, true, nil
+		case "*":
+			return a * b, true, nil
+		case "/":
+			return a / b, true, nil
+		}
+	case string:
+		if b, ok := b.(string); ok && op == "+" {
+			return a + b, true, nil
+		}
+	case []interface{}:
+		if b, ok := b.([]interface{}); ok && op == "+" {
+			l := make([]interface{}, 0, len(a)+len(b))
+			return append(append(l, a...), b...), true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+type callNode struct {
+	// target is the receiver of a method-style call, or nil
+	target   node
+	function string
+	args     []node
+}
+
+func (n *callNode) eval(vars map[string]interface{}) (interface{}, error) {
+	var args []interface{}
+	if n.target != nil {
+		v, err := n.target.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+	}
+	for _, arg := range n.args {
+		v, err := arg.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+	}
+
+	switch n.function {
+	case "size":
+		if len(args) == 1 {
+			switch v := args[0].(type) {
+			case string:
+				return int64(utf8.RuneCountInString(v)), nil
+			case []interface{}:
+				return int64(len(v)), nil
+			case map[string]interface{}:
+				return int64(len(v)), nil
+			}
+		}
+	case "startsWith", "endsWith", "contains", "matches":
+		if n.target == nil || len(args) != 2 {
+			break
+		}
+		s, ok := args[0].(string)
+		if !ok {
+			break
+		}
+		arg, ok := args[1].(string)
+		if !ok {
+			break
+		}
+		switch n.function {
+		case "startsWith":
+			return strings.HasPrefix(s, arg), nil
+		case "endsWith":
+			return strings.HasSuffix(s, arg), nil
+		case "contains":
+			return strings.Contains(s, arg), nil
+		default:
+			re, err := regexp.Compile(arg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regular expression %q: %v", arg, err)
+			}
+			return re.MatchString(s), nil
+		}
+	}
+
+	var types []string
+	for _, arg := range args {
+		types = append(types, typeName(arg))
+	}
+	return nil, fmt.Errorf("no such overload: %s(%s)", n.function, strings.Join(types, ", "))
+}
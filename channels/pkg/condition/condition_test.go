@@ -0,0 +1,114 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package condition
+
+import (
+	"testing"
+)
+
+func TestEval(t *testing.T) {
+	vars := map[string]interface{}{
+		"cloudProvider": "aws",
+		"networking":    "cilium",
+		"nodeCount":     12,
+		"featureGates": map[string]bool{
+			"EphemeralContainers": true,
+		},
+		"zones": []string{"us-east-1a", "us-east-1b"},
+	}
+
+	grid := []struct {
+		Expr     string
+		Expected interface{}
+		Error    string
+	}{
+		{Expr: `cloudProvider == "aws"`, Expected: true},
+		{Expr: `cloudProvider == 'gce' || networking in ["cilium", "calico"]`, Expected: true},
+		{Expr: `nodeCount > 10 && nodeCount <= 100`, Expected: true},
+		{Expr: `nodeCount >= 12.5`, Expected: false},
+		{Expr: `nodeCount == 12.0`, Expected: true},
+		{Expr: `!(nodeCount < 3)`, Expected: true},
+		{Expr: `nodeCount / 5 * 2 + nodeCount % 5 - -1`, Expected: int64(7)},
+		{Expr: `"EphemeralContainers" in featureGates && featureGates.EphemeralContainers`, Expected: true},
+		{Expr: `featureGates["EphemeralContainers"]`, Expected: true},
+		{Expr: `has(featureGates.CSIMigration)`, Expected: false},
+		{Expr: `has(featureGates.CSIMigration) && featureGates.CSIMigration`, Expected: false},
+		{Expr: `featureGates.CSIMigration || cloudProvider == "aws"`, Expected: true},
+		{Expr: `featureGates.CSIMigration && cloudProvider == "aws"`, Error: `error evaluating expression "featureGates.CSIMigration && cloudProvider == \"aws\"": no such key: CSIMigration`},
+		{Expr: `size(zones) == 2 && zones[0].startsWith("us-east-1") && zones.size() > 1`, Expected: true},
+		{Expr: `networking.matches("^cil") && networking.endsWith("um") && networking.contains("li")`, Expected: true},
+		{Expr: `nodeCount > 50 ? "large" : nodeCount > 10 ? "medium" : "small"`, Expected: "medium"},
+		{Expr: `{"a": [1, 2]}.a == [1, 2.0]`, Expected: true},
+		{Expr: `'it\'s' + " ok"`, Expected: "it's ok"},
+		{Expr: `null == null && true != false`, Expected: true},
+		{Expr: `region == "us-east-1"`, Error: `error evaluating expression "region == \"us-east-1\"": undeclared reference to 'region'`},
+		{Expr: `nodeCount + "1"`, Error: `error evaluating expression "nodeCount + \"1\"": no such overload: int + string`},
+		{Expr: `nodeCount / 0`, Error: `error evaluating expression "nodeCount / 0": division by zero`},
+		{Expr: `zones[2]`, Error: `error evaluating expression "zones[2]": index 2 out of range for list of size 2`},
+		{Expr: `nodeCount >`, Error: `error parsing expression "nodeCount >": unexpected end of expression`},
+		{Expr: `nodeCount > 1 < 2`, Error: `error parsing expression "nodeCount > 1 < 2": unexpected "<" at position 14`},
+		{Expr: `exists(zones)`, Error: `error parsing expression "exists(zones)": undeclared reference to function 'exists' at position 0`},
+		{Expr: `"unterminated`, Error: `error parsing expression "\"unterminated": unterminated string at position 0`},
+		{Expr: `nodeCount = 1`, Error: `error parsing expression "nodeCount = 1": unexpected character '=' at position 10`},
+	}
+
+	for _, g := range grid {
+		p, err := Compile(g.Expr)
+		if err == nil {
+			var actual interface{}
+			actual, err = p.Eval(vars)
+			if err == nil {
+				if g.Error != "" {
+					t.Errorf("%s: expected error %q, got %v", g.Expr, g.Error, actual)
+				} else if !equal(actual, g.Expected) || typeName(actual) != typeName(g.Expected) {
+					t.Errorf("%s: expected %v (%s), got %v (%s)", g.Expr, g.Expected, typeName(g.Expected), actual, typeName(actual))
+				}
+				continue
+			}
+		}
+		if err.Error() != g.Error {
+			t.Errorf("%s: expected error %q, got %q", g.Expr, g.Error, err)
+		}
+	}
+}
+
+func TestEvalBool(t *testing.T) {
+	p, err := Compile(`nodeCount * 2`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := p.EvalBool(map[string]interface{}{"nodeCount": 3}); err == nil || err.Error() != `expression "nodeCount * 2" returned int, expected bool` {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestVariables(t *testing.T) {
+	p, err := Compile(`cloudProvider == "aws" && has(featureGates.Foo) && size(zones) > nodeCount`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	actual := p.Variables()
+	expected := []string{"cloudProvider", "featureGates", "nodeCount", "zones"}
+	if len(actual) != len(expected) {
+		t.Fatalf("expected variables %v, got %v", expected, actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Errorf("expected variables %v, got %v", expected, actual)
+		}
+	}
+}
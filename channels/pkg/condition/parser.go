@@ -0,0 +1,321 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package condition
+
+import (
+	"fmt"
+)
+
+// functions are the supported functions, besides the has() macro
+var functions = map[string]bool{
+	"size":       true,
+	"startsWith": true,
+	"endsWith":   true,
+	"contains":   true,
+	"matches":    true,
+}
+
+// parser is a recursive descent parser for condition expressions
+type parser struct {
+	tokens []token
+	pos    int
+	// variables collects the top-level identifiers referenced by the expression
+	variables map[string]bool
+}
+
+func parse(expr string) (node, map[string]bool, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, nil, err
+	}
+	p := &parser{tokens: tokens, variables: make(map[string]bool)}
+	n, err := p.parseExpr()
+	if err != nil {
+		return nil, nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos)
+	}
+	return n, p.variables, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is the operator op
+func (p *parser) accept(op string) bool {
+	if t := p.peek(); t.kind == tokenOperator && t.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(op string) error {
+	if !p.accept(op) {
+		t := p.peek()
+		if t.kind == tokenEOF {
+			return fmt.Errorf("expected %q at end of expression", op)
+		}
+		return fmt.Errorf("expected %q at position %d, found %q", op, t.pos, t.text)
+	}
+	return nil
+}
+
+// Expr = ConditionalOr ["?" ConditionalOr ":" Expr]
+func (p *parser) parseExpr() (node, error) {
+	n, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if !p.accept("?") {
+		return n, nil
+	}
+	then, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	otherwise, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	return &conditionalNode{condition: n, then: then, otherwise: otherwise}, nil
+}
+
+// precedence lists the binary operators from the lowest to the highest precedence
+var precedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"<", "<=", ">", ">=", "==", "!=", "in"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *parser) parseBinary(level int) (node, error) {
+	if level == len(precedence) {
+		return p.parseUnary()
+	}
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t.kind != tokenOperator && !(t.kind == tokenIdent && t.text == "in") {
+			return left, nil
+		}
+		matched := false
+		for _, op := range precedence[level] {
+			if t.text == op {
+				matched = true
+			}
+		}
+		if !matched {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: t.text, left: left, right: right}
+		// Relations don't chain
+		if level == 2 {
+			return left, nil
+		}
+	}
+}
+
+// Unary = Member | "!" Unary | "-" Unary
+func (p *parser) parseUnary() (node, error) {
+	if p.accept("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: "!", operand: operand}, nil
+	}
+	if p.accept("-") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: "-", operand: operand}, nil
+	}
+	return p.parseMember()
+}
+
+// Member = Primary { "." IDENT ["(" Args ")"] | "[" Expr "]" }
+func (p *parser) parseMember() (node, error) {
+	n, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.accept("."):
+			t := p.next()
+			if t.kind != tokenIdent {
+				return nil, fmt.Errorf("expected field name at position %d", t.pos)
+			}
+			if p.accept("(") {
+				args, err := p.parseList(")")
+				if err != nil {
+					return nil, err
+				}
+				if !functions[t.text] {
+					return nil, fmt.Errorf("undeclared reference to function '%s' at position %d", t.text, t.pos)
+				}
+				n = &callNode{target: n, function: t.text, args: args}
+			} else {
+				n = &selectNode{operand: n, field: t.text}
+			}
+		case p.accept("["):
+			index, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			n = &indexNode{operand: n, index: index}
+		default:
+			return n, nil
+		}
+	}
+}
+
+// parseList parses comma separated expressions up to the closing operator
+func (p *parser) parseList(closing string) ([]node, error) {
+	var nodes []node
+	if p.accept(closing) {
+		return nodes, nil
+	}
+	for {
+		n, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+		if p.accept(closing) {
+			return nodes, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenInt, tokenDouble, tokenString:
+		return &literalNode{value: t.value}, nil
+
+	case tokenIdent:
+		switch t.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		case "null":
+			return &literalNode{value: nil}, nil
+		}
+		if p.accept("(") {
+			args, err := p.parseList(")")
+			if err != nil {
+				return nil, err
+			}
+			if t.text == "has" {
+				if len(args) != 1 {
+					return nil, fmt.Errorf("has() takes a single field selection, at position %d", t.pos)
+				}
+				sel, ok := args[0].(*selectNode)
+				if !ok {
+					return nil, fmt.Errorf("has() argument must be a field selection, at position %d", t.pos)
+				}
+				return &hasNode{selection: sel}, nil
+			}
+			if !functions[t.text] {
+				return nil, fmt.Errorf("undeclared reference to function '%s' at position %d", t.text, t.pos)
+			}
+			return &callNode{function: t.text, args: args}, nil
+		}
+		p.variables[t.text] = true
+		return &identNode{name: t.text}, nil
+
+	case tokenOperator:
+		switch t.text {
+		case "(":
+			n, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return n, nil
+		case "[":
+			elements, err := p.parseList("]")
+			if err != nil {
+				return nil, err
+			}
+			return &listNode{elements: elements}, nil
+		case "{":
+			m := &mapNode{}
+			if p.accept("}") {
+				return m, nil
+			}
+			for {
+				key, err := p.parseExpr()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expect(":"); err != nil {
+					return nil, err
+				}
+				value, err := p.parseExpr()
+				if err != nil {
+					return nil, err
+				}
+				m.keys = append(m.keys, key)
+				m.values = append(m.values, value)
+				if p.accept("}") {
+					return m, nil
+				}
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+		}
+	case tokenEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos)
+}
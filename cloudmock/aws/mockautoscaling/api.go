@@ -32,6 +32,7 @@ type MockAutoscaling struct {
 	WarmPoolInstances    map[string][]*autoscaling.Instance
 	LaunchConfigurations map[string]*autoscaling.LaunchConfiguration
 	LifecycleHooks       map[string]*autoscaling.LifecycleHook
+	ScheduledActions     map[string]map[string]*autoscaling.ScheduledUpdateGroupAction
 }
 
 var _ autoscalingiface.AutoScalingAPI = &MockAutoscaling{}
@@ -324,6 +324,7 @@ func (m *MockAutoscaling) DeleteAutoScalingGroup(request *autoscaling.DeleteAuto
 		return nil, fmt.Errorf("AutoScalingGroup %q not found", id)
 	}
 	delete(m.Groups, id)
+	delete(m.ScheduledActions, id)
 
 	return &autoscaling.DeleteAutoScalingGroupOutput{}, nil
 }
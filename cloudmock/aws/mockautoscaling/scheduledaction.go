@@ -0,0 +1,103 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockautoscaling
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"k8s.io/klog/v2"
+)
+
+func (m *MockAutoscaling) PutScheduledUpdateGroupAction(input *autoscaling.PutScheduledUpdateGroupActionInput) (*autoscaling.PutScheduledUpdateGroupActionOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("Mock PutScheduledUpdateGroupAction: %v", input)
+
+	name := aws.StringValue(input.AutoScalingGroupName)
+	if m.Groups[name] == nil {
+		return nil, fmt.Errorf("AutoScalingGroup %q not found", name)
+	}
+
+	action := &autoscaling.ScheduledUpdateGroupAction{
+		AutoScalingGroupName: input.AutoScalingGroupName,
+		DesiredCapacity:      input.DesiredCapacity,
+		EndTime:              input.EndTime,
+		MaxSize:              input.MaxSize,
+		MinSize:              input.MinSize,
+		Recurrence:           input.Recurrence,
+		ScheduledActionName:  input.ScheduledActionName,
+		StartTime:            input.StartTime,
+		TimeZone:             input.TimeZone,
+	}
+
+	if m.ScheduledActions == nil {
+		m.ScheduledActions = make(map[string]map[string]*autoscaling.ScheduledUpdateGroupAction)
+	}
+	if m.ScheduledActions[name] == nil {
+		m.ScheduledActions[name] = make(map[string]*autoscaling.ScheduledUpdateGroupAction)
+	}
+	m.ScheduledActions[name][aws.StringValue(action.ScheduledActionName)] = action
+
+	return &autoscaling.PutScheduledUpdateGroupActionOutput{}, nil
+}
+
+func (m *MockAutoscaling) DeleteScheduledAction(input *autoscaling.DeleteScheduledActionInput) (*autoscaling.DeleteScheduledActionOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("Mock DeleteScheduledAction: %v", input)
+
+	name := aws.StringValue(input.AutoScalingGroupName)
+	actionName := aws.StringValue(input.ScheduledActionName)
+	if m.ScheduledActions[name][actionName] == nil {
+		return nil, fmt.Errorf("scheduled action %q of AutoScalingGroup %q not found", actionName, name)
+	}
+	delete(m.ScheduledActions[name], actionName)
+
+	return &autoscaling.DeleteScheduledActionOutput{}, nil
+}
+
+func (m *MockAutoscaling) DescribeScheduledActions(input *autoscaling.DescribeScheduledActionsInput) (*autoscaling.DescribeScheduledActionsOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	response := &autoscaling.DescribeScheduledActionsOutput{}
+	for _, action := range m.ScheduledActions[aws.StringValue(input.AutoScalingGroupName)] {
+		response.ScheduledUpdateGroupActions = append(response.ScheduledUpdateGroupActions, action)
+	}
+	sort.Slice(response.ScheduledUpdateGroupActions, func(i, j int) bool {
+		return aws.StringValue(response.ScheduledUpdateGroupActions[i].ScheduledActionName) < aws.StringValue(response.ScheduledUpdateGroupActions[j].ScheduledActionName)
+	})
+
+	return response, nil
+}
+
+func (m *MockAutoscaling) DescribeScheduledActionsPages(input *autoscaling.DescribeScheduledActionsInput, callback func(*autoscaling.DescribeScheduledActionsOutput, bool) bool) error {
+	// For the mock, we just send everything in one page
+	page, err := m.DescribeScheduledActions(input)
+	if err != nil {
+		return err
+	}
+
+	callback(page, false)
+
+	return nil
+}
@@ -60,6 +60,11 @@ type MockEC2 struct {
 
 	NatGateways map[string]*ec2.NatGateway
 
+	NetworkInterfaces map[string]*ec2.NetworkInterface
+
+	VpcEndpointServices map[string]*vpcEndpointServiceInfo
+	VpcEndpoints        map[string]*ec2.VpcEndpoint
+
 	idsMutex sync.Mutex
 	ids      map[string]*idAllocator
 }
@@ -105,6 +110,15 @@ func (m *MockEC2) All() map[string]interface{} {
 	for id, o := range m.NatGateways {
 		all[id] = o
 	}
+	for id, o := range m.NetworkInterfaces {
+		all[id] = o
+	}
+	for id, o := range m.VpcEndpointServices {
+		all[id] = &o.main
+	}
+	for id, o := range m.VpcEndpoints {
+		all[id] = o
+	}
 
 	return all
 }
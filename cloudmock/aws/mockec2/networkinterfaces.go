@@ -0,0 +1,165 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockec2
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/klog/v2"
+)
+
+func (m *MockEC2) CreateNetworkInterface(request *ec2.CreateNetworkInterfaceInput) (*ec2.CreateNetworkInterfaceOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("CreateNetworkInterface: %v", request)
+
+	if request.DryRun != nil {
+		klog.Fatalf("DryRun")
+	}
+
+	id := m.allocateId("eni")
+
+	eni := &ec2.NetworkInterface{
+		NetworkInterfaceId: s(id),
+		Description:        request.Description,
+		SubnetId:           request.SubnetId,
+		Status:             s(ec2.NetworkInterfaceStatusAvailable),
+	}
+	if subnet := m.subnets[aws.StringValue(request.SubnetId)]; subnet != nil {
+		eni.VpcId = subnet.main.VpcId
+	}
+	for _, groupID := range request.Groups {
+		eni.Groups = append(eni.Groups, &ec2.GroupIdentifier{GroupId: groupID})
+	}
+
+	if m.NetworkInterfaces == nil {
+		m.NetworkInterfaces = make(map[string]*ec2.NetworkInterface)
+	}
+	m.NetworkInterfaces[id] = eni
+
+	m.addTags(id, tagSpecificationsToTags(request.TagSpecifications, ec2.ResourceTypeNetworkInterface)...)
+
+	copy := *eni
+	copy.TagSet = m.getTags(ec2.ResourceTypeNetworkInterface, id)
+	return &ec2.CreateNetworkInterfaceOutput{NetworkInterface: &copy}, nil
+}
+
+func (m *MockEC2) CreateNetworkInterfaceWithContext(aws.Context, *ec2.CreateNetworkInterfaceInput, ...request.Option) (*ec2.CreateNetworkInterfaceOutput, error) {
+	panic("Not implemented")
+}
+
+func (m *MockEC2) CreateNetworkInterfaceRequest(*ec2.CreateNetworkInterfaceInput) (*request.Request, *ec2.CreateNetworkInterfaceOutput) {
+	panic("Not implemented")
+}
+
+func (m *MockEC2) DescribeNetworkInterfaces(request *ec2.DescribeNetworkInterfacesInput) (*ec2.DescribeNetworkInterfacesOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("DescribeNetworkInterfaces: %v", request)
+
+	if request.NetworkInterfaceIds != nil {
+		klog.Fatalf("NetworkInterfaceIds")
+	}
+
+	var enis []*ec2.NetworkInterface
+
+	for id, eni := range m.NetworkInterfaces {
+		allFiltersMatch := true
+		for _, filter := range request.Filters {
+			match := false
+			switch *filter.Name {
+
+			default:
+				if strings.HasPrefix(*filter.Name, "tag:") {
+					match = m.hasTag(ec2.ResourceTypeNetworkInterface, id, filter)
+				} else {
+					return nil, fmt.Errorf("unknown filter name: %q", *filter.Name)
+				}
+			}
+
+			if !match {
+				allFiltersMatch = false
+				break
+			}
+		}
+
+		if !allFiltersMatch {
+			continue
+		}
+
+		copy := *eni
+		copy.TagSet = m.getTags(ec2.ResourceTypeNetworkInterface, id)
+		enis = append(enis, &copy)
+	}
+
+	return &ec2.DescribeNetworkInterfacesOutput{
+		NetworkInterfaces: enis,
+	}, nil
+}
+
+func (m *MockEC2) DescribeNetworkInterfacesPages(request *ec2.DescribeNetworkInterfacesInput, callback func(*ec2.DescribeNetworkInterfacesOutput, bool) bool) error {
+	// For the mock, we just send everything in one page
+	page, err := m.DescribeNetworkInterfaces(request)
+	if err != nil {
+		return err
+	}
+
+	callback(page, false)
+
+	return nil
+}
+
+func (m *MockEC2) DescribeNetworkInterfacesWithContext(aws.Context, *ec2.DescribeNetworkInterfacesInput, ...request.Option) (*ec2.DescribeNetworkInterfacesOutput, error) {
+	panic("Not implemented")
+}
+
+func (m *MockEC2) DescribeNetworkInterfacesRequest(*ec2.DescribeNetworkInterfacesInput) (*request.Request, *ec2.DescribeNetworkInterfacesOutput) {
+	panic("Not implemented")
+}
+
+func (m *MockEC2) DescribeNetworkInterfacesPagesWithContext(aws.Context, *ec2.DescribeNetworkInterfacesInput, func(*ec2.DescribeNetworkInterfacesOutput, bool) bool, ...request.Option) error {
+	panic("Not implemented")
+}
+
+func (m *MockEC2) DeleteNetworkInterface(request *ec2.DeleteNetworkInterfaceInput) (*ec2.DeleteNetworkInterfaceOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("DeleteNetworkInterface: %v", request)
+
+	id := aws.StringValue(request.NetworkInterfaceId)
+	if m.NetworkInterfaces[id] == nil {
+		return nil, fmt.Errorf("NetworkInterface %q not found", id)
+	}
+	delete(m.NetworkInterfaces, id)
+
+	return &ec2.DeleteNetworkInterfaceOutput{}, nil
+}
+
+func (m *MockEC2) DeleteNetworkInterfaceWithContext(aws.Context, *ec2.DeleteNetworkInterfaceInput, ...request.Option) (*ec2.DeleteNetworkInterfaceOutput, error) {
+	panic("Not implemented")
+}
+
+func (m *MockEC2) DeleteNetworkInterfaceRequest(*ec2.DeleteNetworkInterfaceInput) (*request.Request, *ec2.DeleteNetworkInterfaceOutput) {
+	panic("Not implemented")
+}
@@ -73,6 +73,12 @@ func (m *MockEC2) addTags(resourceId string, tags ...*ec2.Tag) {
 		resourceType = ec2.ResourceTypeLaunchTemplate
 	} else if strings.HasPrefix(resourceId, "key-") {
 		resourceType = ec2.ResourceTypeKeyPair
+	} else if strings.HasPrefix(resourceId, "eni-") {
+		resourceType = ec2.ResourceTypeNetworkInterface
+	} else if strings.HasPrefix(resourceId, "vpce-svc-") {
+		resourceType = resourceTypeVpcEndpointService
+	} else if strings.HasPrefix(resourceId, "vpce-") {
+		resourceType = resourceTypeVpcEndpoint
 	} else {
 		klog.Fatalf("Unknown resource-type in create tags: %v", resourceId)
 	}
@@ -0,0 +1,447 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockec2
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/klog/v2"
+)
+
+// The version of the SDK we vendor does not define these resource types yet
+const (
+	resourceTypeVpcEndpoint        = "vpc-endpoint"
+	resourceTypeVpcEndpointService = "vpc-endpoint-service"
+)
+
+type vpcEndpointServiceInfo struct {
+	main              ec2.ServiceConfiguration
+	allowedPrincipals []string
+}
+
+func (m *MockEC2) CreateVpcEndpointServiceConfiguration(request *ec2.CreateVpcEndpointServiceConfigurationInput) (*ec2.CreateVpcEndpointServiceConfigurationOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("CreateVpcEndpointServiceConfiguration: %v", request)
+
+	id := m.allocateId("vpce-svc")
+	tags := tagSpecificationsToTags(request.TagSpecifications, resourceTypeVpcEndpointService)
+
+	service := &vpcEndpointServiceInfo{
+		main: ec2.ServiceConfiguration{
+			AcceptanceRequired:      request.AcceptanceRequired,
+			NetworkLoadBalancerArns: request.NetworkLoadBalancerArns,
+			ServiceId:               s(id),
+			ServiceName:             s("com.amazonaws.vpce.us-test-1." + id),
+			ServiceState:            s(ec2.ServiceStateAvailable),
+		},
+	}
+
+	if m.VpcEndpointServices == nil {
+		m.VpcEndpointServices = make(map[string]*vpcEndpointServiceInfo)
+	}
+	m.VpcEndpointServices[id] = service
+
+	m.addTags(id, tags...)
+
+	copy := service.main
+	copy.Tags = tags
+	return &ec2.CreateVpcEndpointServiceConfigurationOutput{
+		ServiceConfiguration: &copy,
+		ClientToken:          request.ClientToken,
+	}, nil
+}
+
+func (m *MockEC2) CreateVpcEndpointServiceConfigurationWithContext(aws.Context, *ec2.CreateVpcEndpointServiceConfigurationInput, ...request.Option) (*ec2.CreateVpcEndpointServiceConfigurationOutput, error) {
+	panic("Not implemented")
+}
+func (m *MockEC2) CreateVpcEndpointServiceConfigurationRequest(*ec2.CreateVpcEndpointServiceConfigurationInput) (*request.Request, *ec2.CreateVpcEndpointServiceConfigurationOutput) {
+	panic("Not implemented")
+}
+
+func (m *MockEC2) DescribeVpcEndpointServiceConfigurations(request *ec2.DescribeVpcEndpointServiceConfigurationsInput) (*ec2.DescribeVpcEndpointServiceConfigurationsOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("DescribeVpcEndpointServiceConfigurations: %v", request)
+
+	if len(request.ServiceIds) != 0 {
+		request.Filters = append(request.Filters, &ec2.Filter{Name: s("service-id"), Values: request.ServiceIds})
+	}
+
+	var services []*ec2.ServiceConfiguration
+	for id, service := range m.VpcEndpointServices {
+		allFiltersMatch := true
+		for _, filter := range request.Filters {
+			match := false
+			switch *filter.Name {
+			case "service-id":
+				for _, v := range filter.Values {
+					if id == aws.StringValue(v) {
+						match = true
+					}
+				}
+			default:
+				if strings.HasPrefix(*filter.Name, "tag:") {
+					match = m.hasTag(resourceTypeVpcEndpointService, id, filter)
+				} else {
+					return nil, fmt.Errorf("unknown filter name: %q", *filter.Name)
+				}
+			}
+
+			if !match {
+				allFiltersMatch = false
+				break
+			}
+		}
+
+		if !allFiltersMatch {
+			continue
+		}
+
+		copy := service.main
+		copy.Tags = m.getTags(resourceTypeVpcEndpointService, id)
+		services = append(services, &copy)
+	}
+
+	return &ec2.DescribeVpcEndpointServiceConfigurationsOutput{
+		ServiceConfigurations: services,
+	}, nil
+}
+
+func (m *MockEC2) DescribeVpcEndpointServiceConfigurationsWithContext(aws.Context, *ec2.DescribeVpcEndpointServiceConfigurationsInput, ...request.Option) (*ec2.DescribeVpcEndpointServiceConfigurationsOutput, error) {
+	panic("Not implemented")
+}
+func (m *MockEC2) DescribeVpcEndpointServiceConfigurationsRequest(*ec2.DescribeVpcEndpointServiceConfigurationsInput) (*request.Request, *ec2.DescribeVpcEndpointServiceConfigurationsOutput) {
+	panic("Not implemented")
+}
+func (m *MockEC2) DescribeVpcEndpointServiceConfigurationsPages(*ec2.DescribeVpcEndpointServiceConfigurationsInput, func(*ec2.DescribeVpcEndpointServiceConfigurationsOutput, bool) bool) error {
+	panic("Not implemented")
+}
+func (m *MockEC2) DescribeVpcEndpointServiceConfigurationsPagesWithContext(aws.Context, *ec2.DescribeVpcEndpointServiceConfigurationsInput, func(*ec2.DescribeVpcEndpointServiceConfigurationsOutput, bool) bool, ...request.Option) error {
+	panic("Not implemented")
+}
+
+func (m *MockEC2) ModifyVpcEndpointServiceConfiguration(request *ec2.ModifyVpcEndpointServiceConfigurationInput) (*ec2.ModifyVpcEndpointServiceConfigurationOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("ModifyVpcEndpointServiceConfiguration: %v", request)
+
+	id := aws.StringValue(request.ServiceId)
+	service := m.VpcEndpointServices[id]
+	if service == nil {
+		return nil, fmt.Errorf("VpcEndpointService %q not found", id)
+	}
+	if request.AcceptanceRequired != nil {
+		service.main.AcceptanceRequired = request.AcceptanceRequired
+	}
+
+	return &ec2.ModifyVpcEndpointServiceConfigurationOutput{Return: aws.Bool(true)}, nil
+}
+
+func (m *MockEC2) ModifyVpcEndpointServiceConfigurationWithContext(aws.Context, *ec2.ModifyVpcEndpointServiceConfigurationInput, ...request.Option) (*ec2.ModifyVpcEndpointServiceConfigurationOutput, error) {
+	panic("Not implemented")
+}
+func (m *MockEC2) ModifyVpcEndpointServiceConfigurationRequest(*ec2.ModifyVpcEndpointServiceConfigurationInput) (*request.Request, *ec2.ModifyVpcEndpointServiceConfigurationOutput) {
+	panic("Not implemented")
+}
+
+func (m *MockEC2) DescribeVpcEndpointServicePermissions(request *ec2.DescribeVpcEndpointServicePermissionsInput) (*ec2.DescribeVpcEndpointServicePermissionsOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("DescribeVpcEndpointServicePermissions: %v", request)
+
+	id := aws.StringValue(request.ServiceId)
+	service := m.VpcEndpointServices[id]
+	if service == nil {
+		return nil, fmt.Errorf("VpcEndpointService %q not found", id)
+	}
+
+	response := &ec2.DescribeVpcEndpointServicePermissionsOutput{}
+	for _, principal := range service.allowedPrincipals {
+		response.AllowedPrincipals = append(response.AllowedPrincipals, &ec2.AllowedPrincipal{
+			Principal: s(principal),
+		})
+	}
+	return response, nil
+}
+
+func (m *MockEC2) DescribeVpcEndpointServicePermissionsWithContext(aws.Context, *ec2.DescribeVpcEndpointServicePermissionsInput, ...request.Option) (*ec2.DescribeVpcEndpointServicePermissionsOutput, error) {
+	panic("Not implemented")
+}
+func (m *MockEC2) DescribeVpcEndpointServicePermissionsRequest(*ec2.DescribeVpcEndpointServicePermissionsInput) (*request.Request, *ec2.DescribeVpcEndpointServicePermissionsOutput) {
+	panic("Not implemented")
+}
+
+func (m *MockEC2) ModifyVpcEndpointServicePermissions(request *ec2.ModifyVpcEndpointServicePermissionsInput) (*ec2.ModifyVpcEndpointServicePermissionsOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("ModifyVpcEndpointServicePermissions: %v", request)
+
+	id := aws.StringValue(request.ServiceId)
+	service := m.VpcEndpointServices[id]
+	if service == nil {
+		return nil, fmt.Errorf("VpcEndpointService %q not found", id)
+	}
+
+	remove := make(map[string]bool)
+	for _, principal := range request.RemoveAllowedPrincipals {
+		remove[aws.StringValue(principal)] = true
+	}
+	var principals []string
+	for _, principal := range service.allowedPrincipals {
+		if !remove[principal] {
+			principals = append(principals, principal)
+		}
+	}
+	for _, principal := range request.AddAllowedPrincipals {
+		principals = append(principals, aws.StringValue(principal))
+	}
+	service.allowedPrincipals = principals
+
+	return &ec2.ModifyVpcEndpointServicePermissionsOutput{ReturnValue: aws.Bool(true)}, nil
+}
+
+func (m *MockEC2) ModifyVpcEndpointServicePermissionsWithContext(aws.Context, *ec2.ModifyVpcEndpointServicePermissionsInput, ...request.Option) (*ec2.ModifyVpcEndpointServicePermissionsOutput, error) {
+	panic("Not implemented")
+}
+func (m *MockEC2) ModifyVpcEndpointServicePermissionsRequest(*ec2.ModifyVpcEndpointServicePermissionsInput) (*request.Request, *ec2.ModifyVpcEndpointServicePermissionsOutput) {
+	panic("Not implemented")
+}
+
+func (m *MockEC2) DeleteVpcEndpointServiceConfigurations(request *ec2.DeleteVpcEndpointServiceConfigurationsInput) (*ec2.DeleteVpcEndpointServiceConfigurationsOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("DeleteVpcEndpointServiceConfigurations: %v", request)
+
+	for _, serviceID := range request.ServiceIds {
+		id := aws.StringValue(serviceID)
+		if m.VpcEndpointServices[id] == nil {
+			return nil, fmt.Errorf("VpcEndpointService %q not found", id)
+		}
+		delete(m.VpcEndpointServices, id)
+	}
+
+	return &ec2.DeleteVpcEndpointServiceConfigurationsOutput{}, nil
+}
+
+func (m *MockEC2) DeleteVpcEndpointServiceConfigurationsWithContext(aws.Context, *ec2.DeleteVpcEndpointServiceConfigurationsInput, ...request.Option) (*ec2.DeleteVpcEndpointServiceConfigurationsOutput, error) {
+	panic("Not implemented")
+}
+func (m *MockEC2) DeleteVpcEndpointServiceConfigurationsRequest(*ec2.DeleteVpcEndpointServiceConfigurationsInput) (*request.Request, *ec2.DeleteVpcEndpointServiceConfigurationsOutput) {
+	panic("Not implemented")
+}
+
+func (m *MockEC2) CreateVpcEndpoint(request *ec2.CreateVpcEndpointInput) (*ec2.CreateVpcEndpointOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("CreateVpcEndpoint: %v", request)
+
+	id := m.allocateId("vpce")
+	tags := tagSpecificationsToTags(request.TagSpecifications, resourceTypeVpcEndpoint)
+
+	endpoint := &ec2.VpcEndpoint{
+		ServiceName:     request.ServiceName,
+		State:           s(ec2.StateAvailable),
+		SubnetIds:       request.SubnetIds,
+		VpcEndpointId:   s(id),
+		VpcEndpointType: request.VpcEndpointType,
+		VpcId:           request.VpcId,
+	}
+	for _, sg := range request.SecurityGroupIds {
+		endpoint.Groups = append(endpoint.Groups, &ec2.SecurityGroupIdentifier{GroupId: sg})
+	}
+
+	if m.VpcEndpoints == nil {
+		m.VpcEndpoints = make(map[string]*ec2.VpcEndpoint)
+	}
+	m.VpcEndpoints[id] = endpoint
+
+	m.addTags(id, tags...)
+
+	copy := *endpoint
+	copy.Tags = tags
+	return &ec2.CreateVpcEndpointOutput{
+		VpcEndpoint: &copy,
+		ClientToken: request.ClientToken,
+	}, nil
+}
+
+func (m *MockEC2) CreateVpcEndpointWithContext(aws.Context, *ec2.CreateVpcEndpointInput, ...request.Option) (*ec2.CreateVpcEndpointOutput, error) {
+	panic("Not implemented")
+}
+func (m *MockEC2) CreateVpcEndpointRequest(*ec2.CreateVpcEndpointInput) (*request.Request, *ec2.CreateVpcEndpointOutput) {
+	panic("Not implemented")
+}
+
+func (m *MockEC2) DescribeVpcEndpoints(request *ec2.DescribeVpcEndpointsInput) (*ec2.DescribeVpcEndpointsOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("DescribeVpcEndpoints: %v", request)
+
+	if len(request.VpcEndpointIds) != 0 {
+		request.Filters = append(request.Filters, &ec2.Filter{Name: s("vpc-endpoint-id"), Values: request.VpcEndpointIds})
+	}
+
+	var endpoints []*ec2.VpcEndpoint
+	for id, endpoint := range m.VpcEndpoints {
+		allFiltersMatch := true
+		for _, filter := range request.Filters {
+			match := false
+			switch *filter.Name {
+			case "vpc-endpoint-id":
+				for _, v := range filter.Values {
+					if id == aws.StringValue(v) {
+						match = true
+					}
+				}
+			case "vpc-id":
+				for _, v := range filter.Values {
+					if aws.StringValue(endpoint.VpcId) == aws.StringValue(v) {
+						match = true
+					}
+				}
+			case "service-name":
+				for _, v := range filter.Values {
+					if aws.StringValue(endpoint.ServiceName) == aws.StringValue(v) {
+						match = true
+					}
+				}
+			default:
+				if strings.HasPrefix(*filter.Name, "tag:") {
+					match = m.hasTag(resourceTypeVpcEndpoint, id, filter)
+				} else {
+					return nil, fmt.Errorf("unknown filter name: %q", *filter.Name)
+				}
+			}
+
+			if !match {
+				allFiltersMatch = false
+				break
+			}
+		}
+
+		if !allFiltersMatch {
+			continue
+		}
+
+		copy := *endpoint
+		copy.Tags = m.getTags(resourceTypeVpcEndpoint, id)
+		endpoints = append(endpoints, &copy)
+	}
+
+	return &ec2.DescribeVpcEndpointsOutput{
+		VpcEndpoints: endpoints,
+	}, nil
+}
+
+func (m *MockEC2) DescribeVpcEndpointsWithContext(aws.Context, *ec2.DescribeVpcEndpointsInput, ...request.Option) (*ec2.DescribeVpcEndpointsOutput, error) {
+	panic("Not implemented")
+}
+func (m *MockEC2) DescribeVpcEndpointsRequest(*ec2.DescribeVpcEndpointsInput) (*request.Request, *ec2.DescribeVpcEndpointsOutput) {
+	panic("Not implemented")
+}
+func (m *MockEC2) DescribeVpcEndpointsPages(*ec2.DescribeVpcEndpointsInput, func(*ec2.DescribeVpcEndpointsOutput, bool) bool) error {
+	panic("Not implemented")
+}
+func (m *MockEC2) DescribeVpcEndpointsPagesWithContext(aws.Context, *ec2.DescribeVpcEndpointsInput, func(*ec2.DescribeVpcEndpointsOutput, bool) bool, ...request.Option) error {
+	panic("Not implemented")
+}
+
+func (m *MockEC2) ModifyVpcEndpoint(request *ec2.ModifyVpcEndpointInput) (*ec2.ModifyVpcEndpointOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("ModifyVpcEndpoint: %v", request)
+
+	id := aws.StringValue(request.VpcEndpointId)
+	endpoint := m.VpcEndpoints[id]
+	if endpoint == nil {
+		return nil, fmt.Errorf("VpcEndpoint %q not found", id)
+	}
+
+	remove := make(map[string]bool)
+	for _, subnet := range request.RemoveSubnetIds {
+		remove[aws.StringValue(subnet)] = true
+	}
+	var subnets []*string
+	for _, subnet := range endpoint.SubnetIds {
+		if !remove[aws.StringValue(subnet)] {
+			subnets = append(subnets, subnet)
+		}
+	}
+	endpoint.SubnetIds = append(subnets, request.AddSubnetIds...)
+
+	remove = make(map[string]bool)
+	for _, sg := range request.RemoveSecurityGroupIds {
+		remove[aws.StringValue(sg)] = true
+	}
+	var groups []*ec2.SecurityGroupIdentifier
+	for _, group := range endpoint.Groups {
+		if !remove[aws.StringValue(group.GroupId)] {
+			groups = append(groups, group)
+		}
+	}
+	for _, sg := range request.AddSecurityGroupIds {
+		groups = append(groups, &ec2.SecurityGroupIdentifier{GroupId: sg})
+	}
+	endpoint.Groups = groups
+
+	return &ec2.ModifyVpcEndpointOutput{Return: aws.Bool(true)}, nil
+}
+
+func (m *MockEC2) ModifyVpcEndpointWithContext(aws.Context, *ec2.ModifyVpcEndpointInput, ...request.Option) (*ec2.ModifyVpcEndpointOutput, error) {
+	panic("Not implemented")
+}
+func (m *MockEC2) ModifyVpcEndpointRequest(*ec2.ModifyVpcEndpointInput) (*request.Request, *ec2.ModifyVpcEndpointOutput) {
+	panic("Not implemented")
+}
+
+func (m *MockEC2) DeleteVpcEndpoints(request *ec2.DeleteVpcEndpointsInput) (*ec2.DeleteVpcEndpointsOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("DeleteVpcEndpoints: %v", request)
+
+	for _, endpointID := range request.VpcEndpointIds {
+		id := aws.StringValue(endpointID)
+		if m.VpcEndpoints[id] == nil {
+			return nil, fmt.Errorf("VpcEndpoint %q not found", id)
+		}
+		delete(m.VpcEndpoints, id)
+	}
+
+	return &ec2.DeleteVpcEndpointsOutput{}, nil
+}
+
+func (m *MockEC2) DeleteVpcEndpointsWithContext(aws.Context, *ec2.DeleteVpcEndpointsInput, ...request.Option) (*ec2.DeleteVpcEndpointsOutput, error) {
+	panic("Not implemented")
+}
+func (m *MockEC2) DeleteVpcEndpointsRequest(*ec2.DeleteVpcEndpointsInput) (*request.Request, *ec2.DeleteVpcEndpointsOutput) {
+	panic("Not implemented")
+}
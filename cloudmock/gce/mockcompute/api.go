@@ -31,12 +31,15 @@ type MockClient struct {
 	subnetworkClient     *subnetworkClient
 	routeClient          *routeClient
 	forwardingRuleClient *forwardingRuleClient
+	backendServiceClient *regionBackendServiceClient
+	healthCheckClient    *healthCheckClient
 	addressClient        *addressClient
 	firewallClient       *firewallClient
 	routerClient         *routerClient
 
 	instanceTemplateClient     *instanceTemplateClient
 	instanceGroupManagerClient *instanceGroupManagerClient
+	autoscalerClient           *autoscalerClient
 	targetPoolClient           *targetPoolClient
 
 	diskClient *diskClient
@@ -54,12 +57,15 @@ func NewMockClient(project string) *MockClient {
 		subnetworkClient:     newSubnetworkClient(),
 		routeClient:          newRouteClient(),
 		forwardingRuleClient: newForwardingRuleClient(),
+		backendServiceClient: newRegionBackendServiceClient(),
+		healthCheckClient:    newHealthCheckClient(),
 		addressClient:        newAddressClient(),
 		firewallClient:       newFirewallClient(),
 		routerClient:         newRouterClient(),
 
 		instanceTemplateClient:     newInstanceTemplateClient(),
 		instanceGroupManagerClient: newInstanceGroupManagerClient(),
+		autoscalerClient:           newAutoscalerClient(),
 		targetPoolClient:           newTargetPoolClient(),
 
 		diskClient: newDiskClient(),
@@ -77,11 +83,14 @@ func (c *MockClient) AllResources() map[string]interface{} {
 		c.subnetworkClient.All,
 		c.routeClient.All,
 		c.forwardingRuleClient.All,
+		c.backendServiceClient.All,
+		c.healthCheckClient.All,
 		c.addressClient.All,
 		c.firewallClient.All,
 		c.routerClient.All,
 		c.instanceTemplateClient.All,
 		c.instanceGroupManagerClient.All,
+		c.autoscalerClient.All,
 		c.targetPoolClient.All,
 		c.diskClient.All,
 	}
@@ -124,6 +133,14 @@ func (c *MockClient) ForwardingRules() gce.ForwardingRuleClient {
 	return c.forwardingRuleClient
 }
 
+func (c *MockClient) RegionBackendServices() gce.RegionBackendServiceClient {
+	return c.backendServiceClient
+}
+
+func (c *MockClient) HealthChecks() gce.HealthCheckClient {
+	return c.healthCheckClient
+}
+
 func (c *MockClient) Addresses() gce.AddressClient {
 	return c.addressClient
 }
@@ -149,6 +166,10 @@ func (c *MockClient) InstanceGroupManagers() gce.InstanceGroupManagerClient {
 	return c.instanceGroupManagerClient
 }
 
+func (c *MockClient) Autoscalers() gce.AutoscalerClient {
+	return c.autoscalerClient
+}
+
 func (c *MockClient) TargetPools() gce.TargetPoolClient {
 	return c.targetPoolClient
 }
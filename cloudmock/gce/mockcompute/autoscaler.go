@@ -0,0 +1,148 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockcompute
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	compute "google.golang.org/api/compute/v1"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+)
+
+type autoscalerClient struct {
+	// autoscalers are autoscalers keyed by project, zone, and name.
+	autoscalers map[string]map[string]map[string]*compute.Autoscaler
+	sync.Mutex
+}
+
+var _ gce.AutoscalerClient = &autoscalerClient{}
+
+func newAutoscalerClient() *autoscalerClient {
+	return &autoscalerClient{
+		autoscalers: map[string]map[string]map[string]*compute.Autoscaler{},
+	}
+}
+
+func (c *autoscalerClient) All() map[string]interface{} {
+	c.Lock()
+	defer c.Unlock()
+	m := map[string]interface{}{}
+	for _, zones := range c.autoscalers {
+		for _, autoscalers := range zones {
+			for n, a := range autoscalers {
+				m[n] = a
+			}
+		}
+	}
+	return m
+}
+
+func (c *autoscalerClient) Insert(project, zone string, a *compute.Autoscaler) (*compute.Operation, error) {
+	c.Lock()
+	defer c.Unlock()
+	zones, ok := c.autoscalers[project]
+	if !ok {
+		zones = map[string]map[string]*compute.Autoscaler{}
+		c.autoscalers[project] = zones
+	}
+	autoscalers, ok := zones[zone]
+	if !ok {
+		autoscalers = map[string]*compute.Autoscaler{}
+		zones[zone] = autoscalers
+	}
+	a.SelfLink = fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/%s/zones/%s/autoscalers/%s", project, zone, a.Name)
+	a.Zone = fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/%s/zones/%s", project, zone)
+	autoscalers[a.Name] = a
+	return doneOperation(), nil
+}
+
+func (c *autoscalerClient) Update(project, zone string, a *compute.Autoscaler) (*compute.Operation, error) {
+	c.Lock()
+	defer c.Unlock()
+	zones, ok := c.autoscalers[project]
+	if !ok {
+		return nil, notFoundError()
+	}
+	autoscalers, ok := zones[zone]
+	if !ok {
+		return nil, notFoundError()
+	}
+	existing, ok := autoscalers[a.Name]
+	if !ok {
+		return nil, notFoundError()
+	}
+	a.SelfLink = existing.SelfLink
+	a.Zone = existing.Zone
+	autoscalers[a.Name] = a
+	return doneOperation(), nil
+}
+
+func (c *autoscalerClient) Delete(project, zone, name string) (*compute.Operation, error) {
+	c.Lock()
+	defer c.Unlock()
+	zones, ok := c.autoscalers[project]
+	if !ok {
+		return nil, notFoundError()
+	}
+	autoscalers, ok := zones[zone]
+	if !ok {
+		return nil, notFoundError()
+	}
+	if _, ok := autoscalers[name]; !ok {
+		return nil, notFoundError()
+	}
+	delete(autoscalers, name)
+	return doneOperation(), nil
+}
+
+func (c *autoscalerClient) Get(project, zone, name string) (*compute.Autoscaler, error) {
+	c.Lock()
+	defer c.Unlock()
+	zones, ok := c.autoscalers[project]
+	if !ok {
+		return nil, notFoundError()
+	}
+	autoscalers, ok := zones[zone]
+	if !ok {
+		return nil, notFoundError()
+	}
+	a, ok := autoscalers[name]
+	if !ok {
+		return nil, notFoundError()
+	}
+	return a, nil
+}
+
+func (c *autoscalerClient) List(ctx context.Context, project, zone string) ([]*compute.Autoscaler, error) {
+	c.Lock()
+	defer c.Unlock()
+	zones, ok := c.autoscalers[project]
+	if !ok {
+		return nil, nil
+	}
+	autoscalers, ok := zones[zone]
+	if !ok {
+		return nil, nil
+	}
+	var l []*compute.Autoscaler
+	for _, a := range autoscalers {
+		l = append(l, a)
+	}
+	return l, nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockcompute
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	compute "google.golang.org/api/compute/v1"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+)
+
+type regionBackendServiceClient struct {
+	// backendServices are regional backendServices keyed by project, region, and backendService name.
+	backendServices map[string]map[string]map[string]*compute.BackendService
+	sync.Mutex
+}
+
+var _ gce.RegionBackendServiceClient = &regionBackendServiceClient{}
+
+func newRegionBackendServiceClient() *regionBackendServiceClient {
+	return &regionBackendServiceClient{
+		backendServices: map[string]map[string]map[string]*compute.BackendService{},
+	}
+}
+
+func (c *regionBackendServiceClient) All() map[string]interface{} {
+	c.Lock()
+	defer c.Unlock()
+	m := map[string]interface{}{}
+	for _, regions := range c.backendServices {
+		for _, bss := range regions {
+			for n, bs := range bss {
+				m[n] = bs
+			}
+		}
+	}
+	return m
+}
+
+func (c *regionBackendServiceClient) Insert(project, region string, bs *compute.BackendService) (*compute.Operation, error) {
+	c.Lock()
+	defer c.Unlock()
+	regions, ok := c.backendServices[project]
+	if !ok {
+		regions = map[string]map[string]*compute.BackendService{}
+		c.backendServices[project] = regions
+	}
+	bss, ok := regions[region]
+	if !ok {
+		bss = map[string]*compute.BackendService{}
+		regions[region] = bss
+	}
+	bs.SelfLink = fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/%s/regions/%s/backendServices/%s", project, region, bs.Name)
+	bss[bs.Name] = bs
+	return doneOperation(), nil
+}
+
+func (c *regionBackendServiceClient) Update(project, region, name string, bs *compute.BackendService) (*compute.Operation, error) {
+	c.Lock()
+	defer c.Unlock()
+	regions, ok := c.backendServices[project]
+	if !ok {
+		return nil, notFoundError()
+	}
+	bss, ok := regions[region]
+	if !ok {
+		return nil, notFoundError()
+	}
+	existing, ok := bss[name]
+	if !ok {
+		return nil, notFoundError()
+	}
+	bs.Name = existing.Name
+	bs.SelfLink = existing.SelfLink
+	bss[name] = bs
+	return doneOperation(), nil
+}
+
+func (c *regionBackendServiceClient) Delete(project, region, name string) (*compute.Operation, error) {
+	c.Lock()
+	defer c.Unlock()
+	regions, ok := c.backendServices[project]
+	if !ok {
+		return nil, notFoundError()
+	}
+	bss, ok := regions[region]
+	if !ok {
+		return nil, notFoundError()
+	}
+	if _, ok := bss[name]; !ok {
+		return nil, notFoundError()
+	}
+	delete(bss, name)
+	return doneOperation(), nil
+}
+
+func (c *regionBackendServiceClient) Get(project, region, name string) (*compute.BackendService, error) {
+	c.Lock()
+	defer c.Unlock()
+	regions, ok := c.backendServices[project]
+	if !ok {
+		return nil, notFoundError()
+	}
+	bss, ok := regions[region]
+	if !ok {
+		return nil, notFoundError()
+	}
+	bs, ok := bss[name]
+	if !ok {
+		return nil, notFoundError()
+	}
+	return bs, nil
+}
+
+func (c *regionBackendServiceClient) List(ctx context.Context, project, region string) ([]*compute.BackendService, error) {
+	c.Lock()
+	defer c.Unlock()
+	regions, ok := c.backendServices[project]
+	if !ok {
+		return nil, nil
+	}
+	bss, ok := regions[region]
+	if !ok {
+		return nil, nil
+	}
+	var l []*compute.BackendService
+	for _, bs := range bss {
+		l = append(l, bs)
+	}
+	return l, nil
+}
@@ -23,6 +23,7 @@ import (
 	"net/url"
 	"regexp"
 
+	"github.com/google/uuid"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
 )
 
@@ -30,6 +31,14 @@ type floatingIPListResponse struct {
 	FloatingIPs []floatingips.FloatingIP `json:"floatingips"`
 }
 
+type floatingIPGetResponse struct {
+	FloatingIP floatingips.FloatingIP `json:"floatingip"`
+}
+
+type floatingIPCreateRequest struct {
+	FloatingIP floatingips.CreateOpts `json:"floatingip"`
+}
+
 func (m *MockClient) mockFloatingIPs() {
 	re := regexp.MustCompile(`/floatingips/?`)
 
@@ -46,6 +55,8 @@ func (m *MockClient) mockFloatingIPs() {
 				r.ParseForm()
 				m.listFloatingIPs(w, r.Form)
 			}
+		case http.MethodPost:
+			m.createFloatingIP(w, r)
 		default:
 			w.WriteHeader(http.StatusBadRequest)
 		}
@@ -60,6 +71,12 @@ func (m *MockClient) listFloatingIPs(w http.ResponseWriter, vals url.Values) {
 
 	floatingips := make([]floatingips.FloatingIP, 0)
 	for _, p := range m.floatingips {
+		if description := vals.Get("description"); description != "" && p.Description != description {
+			continue
+		}
+		if routerID := vals.Get("router_id"); routerID != "" && p.RouterID != routerID {
+			continue
+		}
 		floatingips = append(floatingips, p)
 	}
 	resp := floatingIPListResponse{
@@ -74,3 +91,44 @@ func (m *MockClient) listFloatingIPs(w http.ResponseWriter, vals url.Values) {
 		panic("failed to write body")
 	}
 }
+
+func (m *MockClient) createFloatingIP(w http.ResponseWriter, r *http.Request) {
+	var create floatingIPCreateRequest
+	err := json.NewDecoder(r.Body).Decode(&create)
+	if err != nil {
+		panic("error decoding create floating ip request")
+	}
+
+	w.WriteHeader(http.StatusCreated)
+
+	opts := create.FloatingIP
+	p := floatingips.FloatingIP{
+		ID:                uuid.New().String(),
+		Description:       opts.Description,
+		FloatingNetworkID: opts.FloatingNetworkID,
+		FloatingIP:        opts.FloatingIP,
+		PortID:            opts.PortID,
+		FixedIP:           opts.FixedIP,
+	}
+	if p.PortID != "" {
+		// The floating IP is routed by the router with a gateway on its network
+		for _, router := range m.routers {
+			if router.GatewayInfo.NetworkID == p.FloatingNetworkID {
+				p.RouterID = router.ID
+			}
+		}
+	}
+	m.floatingips[p.ID] = p
+
+	resp := floatingIPGetResponse{
+		FloatingIP: p,
+	}
+	respB, err := json.Marshal(resp)
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal %+v", resp))
+	}
+	_, err = w.Write(respB)
+	if err != nil {
+		panic("failed to write body")
+	}
+}
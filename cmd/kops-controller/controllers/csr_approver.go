@@ -0,0 +1,224 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/go-logr/logr"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	certificatesv1client "k8s.io/client-go/kubernetes/typed/certificates/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/rbac"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// nodeUserPrefix is the prefix of the username of a node authenticated with its client certificate
+const nodeUserPrefix = "system:node:"
+
+// NewCSRApproverReconciler is the constructor for a CSRApproverReconciler
+func NewCSRApproverReconciler(mgr manager.Manager) (*CSRApproverReconciler, error) {
+	r := &CSRApproverReconciler{
+		client:   mgr.GetClient(),
+		log:      ctrl.Log.WithName("controllers").WithName("CSRApprover"),
+		recorder: mgr.GetEventRecorderFor(eventSource),
+	}
+
+	certificatesClient, err := certificatesv1client.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, fmt.Errorf("error building certificatesv1 client: %v", err)
+	}
+	r.certificatesV1Client = certificatesClient
+
+	return r, nil
+}
+
+// CSRApproverReconciler observes CertificateSigningRequest objects, and approves the requests kubelets make
+// to renew their client certificate or to obtain a serving certificate, once it has checked that the
+// requested certificate matches the identity of the node making the request.
+type CSRApproverReconciler struct {
+	// client is the controller-runtime client
+	client client.Client
+
+	// log is a logr
+	log logr.Logger
+
+	// recorder records the events of our decisions on certificate signing requests
+	recorder record.EventRecorder
+
+	// certificatesV1Client is a client-go client for approving certificate signing requests
+	certificatesV1Client *certificatesv1client.CertificatesV1Client
+}
+
+// +kubebuilder:rbac:groups=certificates.k8s.io,resources=certificatesigningrequests,verbs=get;list;watch
+// +kubebuilder:rbac:groups=certificates.k8s.io,resources=certificatesigningrequests/approval,verbs=update
+// +kubebuilder:rbac:groups=certificates.k8s.io,resources=signers,verbs=approve
+// Reconcile is the main reconciler function that observes certificate signing request changes.
+func (r *CSRApproverReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	_ = r.log.WithValues("csrapprover", req.NamespacedName)
+
+	csr := &certificatesv1.CertificateSigningRequest{}
+	if err := r.client.Get(ctx, req.NamespacedName, csr); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if csr.Spec.SignerName != certificatesv1.KubeAPIServerClientKubeletSignerName && csr.Spec.SignerName != certificatesv1.KubeletServingSignerName {
+		return ctrl.Result{}, nil
+	}
+	if len(csr.Status.Conditions) != 0 || len(csr.Status.Certificate) != 0 {
+		// Already approved, denied or issued
+		return ctrl.Result{}, nil
+	}
+
+	var node *corev1.Node
+	if csr.Spec.SignerName == certificatesv1.KubeletServingSignerName && strings.HasPrefix(csr.Spec.Username, nodeUserPrefix) {
+		node = &corev1.Node{}
+		nodeName := strings.TrimPrefix(csr.Spec.Username, nodeUserPrefix)
+		if err := r.client.Get(ctx, types.NamespacedName{Name: nodeName}, node); err != nil {
+			if apierrors.IsNotFound(err) {
+				klog.Infof("not approving certificate signing request %s: node %q not found", csr.Name, nodeName)
+				return ctrl.Result{}, nil
+			}
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := validateKubeletCSR(csr, node); err != nil {
+		klog.Infof("not approving certificate signing request %s: %v", csr.Name, err)
+		csrDecisions.WithLabelValues(csr.Spec.SignerName, "rejected").Inc()
+		r.recorder.Eventf(csr, corev1.EventTypeWarning, "NotApproved", "Not approving the request of %s: %v", csr.Spec.Username, err)
+		return ctrl.Result{}, nil
+	}
+
+	csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+		Type:           certificatesv1.CertificateApproved,
+		Status:         corev1.ConditionTrue,
+		Reason:         "KopsControllerApprove",
+		Message:        "Auto approving kubelet certificate after verifying the node identity",
+		LastUpdateTime: metav1.Now(),
+	})
+	if _, err := r.certificatesV1Client.CertificateSigningRequests().UpdateApproval(ctx, csr.Name, csr, metav1.UpdateOptions{}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("error approving certificate signing request %s: %v", csr.Name, err)
+	}
+	klog.Infof("approved certificate signing request %s from %s for %s", csr.Name, csr.Spec.Username, csr.Spec.SignerName)
+	csrDecisions.WithLabelValues(csr.Spec.SignerName, "approved").Inc()
+	r.recorder.Eventf(csr, corev1.EventTypeNormal, "Approved", "Approved the request of %s after verifying the node identity", csr.Spec.Username)
+
+	return ctrl.Result{}, nil
+}
+
+func (r *CSRApproverReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&certificatesv1.CertificateSigningRequest{}).
+		Complete(r)
+}
+
+// validateKubeletCSR checks that the certificate signing request was made by a node, for its own identity.
+// Client certificates may only be requested by a node renewing its existing certificate.
+// Serving certificates may only be requested for the node's own names and addresses.
+func validateKubeletCSR(csr *certificatesv1.CertificateSigningRequest, node *corev1.Node) error {
+	if !strings.HasPrefix(csr.Spec.Username, nodeUserPrefix) {
+		return fmt.Errorf("requested by %q, which is not a node", csr.Spec.Username)
+	}
+
+	block, _ := pem.Decode(csr.Spec.Request)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return fmt.Errorf("request is not a PEM encoded certificate request")
+	}
+	x509cr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("parsing certificate request: %v", err)
+	}
+
+	if x509cr.Subject.CommonName != csr.Spec.Username {
+		return fmt.Errorf("requested by %q for %q", csr.Spec.Username, x509cr.Subject.CommonName)
+	}
+	if len(x509cr.Subject.Organization) != 1 || x509cr.Subject.Organization[0] != rbac.NodesGroup {
+		return fmt.Errorf("unexpected organization %v", x509cr.Subject.Organization)
+	}
+	if len(x509cr.EmailAddresses) != 0 || len(x509cr.URIs) != 0 {
+		return fmt.Errorf("unexpected email or URI subject alternative names")
+	}
+
+	allowedUsages := sets.NewString(string(certificatesv1.UsageDigitalSignature), string(certificatesv1.UsageKeyEncipherment))
+	switch csr.Spec.SignerName {
+	case certificatesv1.KubeAPIServerClientKubeletSignerName:
+		if len(x509cr.DNSNames) != 0 || len(x509cr.IPAddresses) != 0 {
+			return fmt.Errorf("unexpected subject alternative names in client certificate request")
+		}
+		allowedUsages.Insert(string(certificatesv1.UsageClientAuth))
+
+	case certificatesv1.KubeletServingSignerName:
+		if node == nil || nodeUserPrefix+node.Name != csr.Spec.Username {
+			return fmt.Errorf("node not found for %q", csr.Spec.Username)
+		}
+		if len(x509cr.DNSNames) == 0 && len(x509cr.IPAddresses) == 0 {
+			return fmt.Errorf("no subject alternative names in serving certificate request")
+		}
+
+		hostnames := sets.NewString()
+		ips := sets.NewString()
+		for _, address := range node.Status.Addresses {
+			switch address.Type {
+			case corev1.NodeHostName, corev1.NodeInternalDNS, corev1.NodeExternalDNS:
+				hostnames.Insert(address.Address)
+			case corev1.NodeInternalIP, corev1.NodeExternalIP:
+				if ip := net.ParseIP(address.Address); ip != nil {
+					ips.Insert(ip.String())
+				}
+			}
+		}
+		for _, dnsName := range x509cr.DNSNames {
+			if !hostnames.Has(dnsName) {
+				return fmt.Errorf("DNS name %q is not an address of node %q", dnsName, node.Name)
+			}
+		}
+		for _, ip := range x509cr.IPAddresses {
+			if !ips.Has(ip.String()) {
+				return fmt.Errorf("IP address %q is not an address of node %q", ip, node.Name)
+			}
+		}
+		allowedUsages.Insert(string(certificatesv1.UsageServerAuth))
+
+	default:
+		return fmt.Errorf("unexpected signer %q", csr.Spec.SignerName)
+	}
+
+	for _, usage := range csr.Spec.Usages {
+		if !allowedUsages.Has(string(usage)) {
+			return fmt.Errorf("unexpected usage %q", usage)
+		}
+	}
+
+	return nil
+}
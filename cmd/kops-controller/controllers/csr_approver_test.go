@@ -0,0 +1,141 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"net"
+	"testing"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func buildCSR(t *testing.T, username string, signerName string, template *x509.CertificateRequest, usages ...certificatesv1.KeyUsage) *certificatesv1.CertificateSigningRequest {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		t.Fatalf("error creating certificate request: %v", err)
+	}
+
+	return &certificatesv1.CertificateSigningRequest{
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Request:    pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}),
+			SignerName: signerName,
+			Username:   username,
+			Usages:     usages,
+		},
+	}
+}
+
+func TestValidateKubeletCSR(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "ip-10-0-0-1.ec2.internal"},
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{
+				{Type: corev1.NodeInternalIP, Address: "10.0.0.1"},
+				{Type: corev1.NodeInternalDNS, Address: "ip-10-0-0-1.ec2.internal"},
+				{Type: corev1.NodeHostName, Address: "ip-10-0-0-1.ec2.internal"},
+			},
+		},
+	}
+	nodeUser := "system:node:ip-10-0-0-1.ec2.internal"
+	nodeSubject := pkix.Name{CommonName: nodeUser, Organization: []string{"system:nodes"}}
+	clientUsages := []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageKeyEncipherment, certificatesv1.UsageClientAuth}
+	servingUsages := []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageKeyEncipherment, certificatesv1.UsageServerAuth}
+
+	grid := []struct {
+		Name          string
+		CSR           *certificatesv1.CertificateSigningRequest
+		ExpectApprove bool
+	}{
+		{
+			Name:          "client renewal",
+			CSR:           buildCSR(t, nodeUser, certificatesv1.KubeAPIServerClientKubeletSignerName, &x509.CertificateRequest{Subject: nodeSubject}, clientUsages...),
+			ExpectApprove: true,
+		},
+		{
+			Name: "client certificate for another node",
+			CSR:  buildCSR(t, "system:node:ip-10-0-0-2.ec2.internal", certificatesv1.KubeAPIServerClientKubeletSignerName, &x509.CertificateRequest{Subject: nodeSubject}, clientUsages...),
+		},
+		{
+			Name: "client certificate requested with a bootstrap token",
+			CSR:  buildCSR(t, "system:bootstrap:abcdef", certificatesv1.KubeAPIServerClientKubeletSignerName, &x509.CertificateRequest{Subject: nodeSubject}, clientUsages...),
+		},
+		{
+			Name: "client certificate with extra groups",
+			CSR:  buildCSR(t, nodeUser, certificatesv1.KubeAPIServerClientKubeletSignerName, &x509.CertificateRequest{Subject: pkix.Name{CommonName: nodeUser, Organization: []string{"system:nodes", "system:masters"}}}, clientUsages...),
+		},
+		{
+			Name: "client certificate with server usage",
+			CSR:  buildCSR(t, nodeUser, certificatesv1.KubeAPIServerClientKubeletSignerName, &x509.CertificateRequest{Subject: nodeSubject}, servingUsages...),
+		},
+		{
+			Name: "serving certificate",
+			CSR: buildCSR(t, nodeUser, certificatesv1.KubeletServingSignerName, &x509.CertificateRequest{
+				Subject:     nodeSubject,
+				DNSNames:    []string{"ip-10-0-0-1.ec2.internal"},
+				IPAddresses: []net.IP{net.ParseIP("10.0.0.1")},
+			}, servingUsages...),
+			ExpectApprove: true,
+		},
+		{
+			Name: "serving certificate for a foreign address",
+			CSR: buildCSR(t, nodeUser, certificatesv1.KubeletServingSignerName, &x509.CertificateRequest{
+				Subject:     nodeSubject,
+				DNSNames:    []string{"ip-10-0-0-1.ec2.internal"},
+				IPAddresses: []net.IP{net.ParseIP("10.0.0.2")},
+			}, servingUsages...),
+		},
+		{
+			Name: "serving certificate for a foreign name",
+			CSR: buildCSR(t, nodeUser, certificatesv1.KubeletServingSignerName, &x509.CertificateRequest{
+				Subject:  nodeSubject,
+				DNSNames: []string{"kubernetes.default"},
+			}, servingUsages...),
+		},
+		{
+			Name: "serving certificate without names",
+			CSR:  buildCSR(t, nodeUser, certificatesv1.KubeletServingSignerName, &x509.CertificateRequest{Subject: nodeSubject}, servingUsages...),
+		},
+		{
+			Name: "other signer",
+			CSR:  buildCSR(t, nodeUser, certificatesv1.KubeAPIServerClientSignerName, &x509.CertificateRequest{Subject: nodeSubject}, clientUsages...),
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			err := validateKubeletCSR(g.CSR, node)
+			if g.ExpectApprove && err != nil {
+				t.Errorf("expected approval, got %v", err)
+			}
+			if !g.ExpectApprove && err == nil {
+				t.Errorf("expected request to be rejected")
+			}
+		})
+	}
+}
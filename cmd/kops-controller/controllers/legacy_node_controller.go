@@ -25,6 +25,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/registry"
@@ -42,6 +43,7 @@ func NewLegacyNodeReconciler(mgr manager.Manager, configPath string, identifier
 	r := &LegacyNodeReconciler{
 		client:     mgr.GetClient(),
 		log:        ctrl.Log.WithName("controllers").WithName("Node"),
+		recorder:   mgr.GetEventRecorderFor(eventSource),
 		identifier: identifier,
 		cache:      vfs.NewCache(),
 	}
@@ -70,6 +72,9 @@ type LegacyNodeReconciler struct {
 	// log is a logr
 	log logr.Logger
 
+	// recorder records the events of the changes we make to nodes
+	recorder record.EventRecorder
+
 	// coreV1Client is a client-go client for patching nodes
 	coreV1Client *corev1client.CoreV1Client
 
@@ -107,6 +112,8 @@ func (r *LegacyNodeReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	ig, err := r.getInstanceGroupForNode(ctx, node)
 	if err != nil {
+		nodeIdentifyErrors.Inc()
+		r.recorder.Eventf(node, corev1.EventTypeWarning, "IdentifyFailed", "Failed to identify the instance group of the node: %v", err)
 		return ctrl.Result{}, fmt.Errorf("unable to load instance group object for node %s: %v", node.Name, err)
 	}
 
@@ -121,20 +128,15 @@ func (r *LegacyNodeReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		labels[fmt.Sprintf("node-role.kubernetes.io/%s-worker", lifecycle)] = "true"
 	}
 
-	updateLabels := make(map[string]string)
-	for k, v := range labels {
-		actual, found := node.Labels[k]
-		if !found || actual != v {
-			updateLabels[k] = v
-		}
-	}
+	updateLabels := changedValues(node.Labels, labels)
+	updateAnnotations := changedValues(node.Annotations, nodelabels.BuildNodeAnnotations(ig))
 
-	if len(updateLabels) == 0 {
+	if len(updateLabels) == 0 && len(updateAnnotations) == 0 {
 		klog.V(4).Infof("no label changes needed for %s", node.Name)
 		return ctrl.Result{}, nil
 	}
 
-	if err := patchNodeLabels(r.coreV1Client, ctx, node, updateLabels); err != nil {
+	if err := updateNode(ctx, r.coreV1Client, r.recorder, node, updateLabels, updateAnnotations); err != nil {
 		klog.Warningf("failed to patch node labels on %s: %v", node.Name, err)
 		return ctrl.Result{}, err
 	}
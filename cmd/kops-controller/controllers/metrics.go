@@ -0,0 +1,58 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	resultSuccess = "success"
+	resultError   = "error"
+)
+
+var (
+	// nodePatches counts the patches of node labels and annotations, by result.
+	nodePatches = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kops_controller_node_patches_total",
+		Help: "Number of patches of the labels and annotations of nodes, by result.",
+	}, []string{"result"})
+
+	// nodeIdentifyErrors counts the nodes that could not be mapped to their instance group.
+	nodeIdentifyErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "kops_controller_node_identify_errors_total",
+		Help: "Number of failures to identify the instance group of a node.",
+	})
+
+	// csrDecisions counts the kubelet certificate signing requests, by signer and decision.
+	csrDecisions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kops_controller_csr_decisions_total",
+		Help: "Number of kubelet certificate signing requests approved or rejected, by signer and decision.",
+	}, []string{"signer", "decision"})
+
+	// needsUpdateCleared counts the stale requests for rolling updates cleared from nodes, by reason.
+	needsUpdateCleared = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kops_controller_needs_update_cleared_total",
+		Help: "Number of stale requests cleared from the kops.k8s.io/needs-update annotation of nodes, by reason.",
+	}, []string{"reason"})
+)
+
+func init() {
+	// The manager serves the metrics of the controller-runtime registry on its metrics endpoint
+	metrics.Registry.MustRegister(nodePatches, nodeIdentifyErrors, csrDecisions, needsUpdateCleared)
+}
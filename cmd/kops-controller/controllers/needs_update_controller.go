@@ -0,0 +1,240 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/channels/pkg/api"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// addonVersionAnnotationPrefix is the prefix of the annotations channels sets on the namespace of each installed addon
+const addonVersionAnnotationPrefix = "addons.k8s.io/"
+
+// needsUpdateRecheckInterval is how often the requests of addons are checked again, as removing an addon doesn't change the node
+const needsUpdateRecheckInterval = 10 * time.Minute
+
+const (
+	needsUpdateReasonRebooted     = "rebooted"
+	needsUpdateReasonAddonRemoved = "addon-removed"
+)
+
+// NewNeedsUpdateReconciler is the constructor for a NeedsUpdateReconciler
+func NewNeedsUpdateReconciler(mgr manager.Manager) (*NeedsUpdateReconciler, error) {
+	r := &NeedsUpdateReconciler{
+		client:   mgr.GetClient(),
+		log:      ctrl.Log.WithName("controllers").WithName("NeedsUpdate"),
+		recorder: mgr.GetEventRecorderFor(eventSource),
+	}
+
+	coreClient, err := corev1client.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, fmt.Errorf("error building corev1 client: %v", err)
+	}
+	r.coreV1Client = coreClient
+
+	return r, nil
+}
+
+// NeedsUpdateReconciler observes the nodes that channels annotated with kops.k8s.io/needs-update, and clears the
+// requests of the addons that no longer hold: all of them once the node has rebooted, which applies its configuration
+// again, and those of addons that have since been removed.
+// Annotations without the record channels keeps of the requests, such as those set by hand, are left alone.
+type NeedsUpdateReconciler struct {
+	// client is the controller-runtime client
+	client client.Client
+
+	// log is a logr
+	log logr.Logger
+
+	// recorder records the events of the requests we clear
+	recorder record.EventRecorder
+
+	// coreV1Client is a client-go client for patching nodes and reading the namespaces of addons
+	coreV1Client *corev1client.CoreV1Client
+}
+
+// +kubebuilder:rbac:groups=,resources=nodes,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=,resources=namespaces,verbs=get
+// Reconcile is the main reconciler function that observes the nodes needing update.
+func (r *NeedsUpdateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	_ = r.log.WithValues("needsupdate", req.NamespacedName)
+
+	node := &corev1.Node{}
+	if err := r.client.Get(ctx, req.NamespacedName, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	value, found := node.Annotations[api.NeedsUpdateAnnotation]
+	if !found {
+		return ctrl.Result{}, nil
+	}
+	needsUpdate, err := api.ParseNeedsUpdate(value)
+	if err != nil {
+		klog.Warningf("not checking node %s: %v", node.Name, err)
+		return ctrl.Result{}, nil
+	}
+	if needsUpdate == nil {
+		return ctrl.Result{}, nil
+	}
+
+	remaining, cleared, err := staleNeedsUpdateRequests(node, needsUpdate, func(addon api.NeedsUpdateAddon) (bool, error) {
+		return r.addonInstalled(ctx, addon)
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(cleared) == 0 {
+		return ctrl.Result{RequeueAfter: needsUpdateRecheckInterval}, nil
+	}
+
+	var newValue *string
+	if len(remaining.Addons) != 0 {
+		encoded, err := remaining.Encode()
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		newValue = &encoded
+	}
+	if err := r.patchNeedsUpdate(ctx, node, newValue); err != nil {
+		r.recorder.Eventf(node, corev1.EventTypeWarning, "NeedsUpdateClearFailed", "Failed to clear stale requests from the %s annotation: %v", api.NeedsUpdateAnnotation, err)
+		return ctrl.Result{}, err
+	}
+
+	var messages []string
+	for _, c := range cleared {
+		needsUpdateCleared.WithLabelValues(c.reason).Inc()
+		messages = append(messages, c.message)
+	}
+	if newValue == nil {
+		klog.Infof("cleared the %s annotation of node %s: %s", api.NeedsUpdateAnnotation, node.Name, strings.Join(messages, "; "))
+		r.recorder.Eventf(node, corev1.EventTypeNormal, "NeedsUpdateCleared", "Cleared the %s annotation: %s", api.NeedsUpdateAnnotation, strings.Join(messages, "; "))
+		return ctrl.Result{}, nil
+	}
+	klog.Infof("cleared stale requests from the %s annotation of node %s: %s", api.NeedsUpdateAnnotation, node.Name, strings.Join(messages, "; "))
+	r.recorder.Eventf(node, corev1.EventTypeNormal, "NeedsUpdateCleared", "Cleared stale requests from the %s annotation: %s", api.NeedsUpdateAnnotation, strings.Join(messages, "; "))
+	return ctrl.Result{RequeueAfter: needsUpdateRecheckInterval}, nil
+}
+
+func (r *NeedsUpdateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("needsupdate").
+		For(&corev1.Node{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+			_, found := object.GetAnnotations()[api.NeedsUpdateAnnotation]
+			return found
+		}))).
+		Complete(r)
+}
+
+// addonInstalled returns true if channels still has a record of the installed version of the addon
+func (r *NeedsUpdateReconciler) addonInstalled(ctx context.Context, addon api.NeedsUpdateAddon) (bool, error) {
+	namespace, err := r.coreV1Client.Namespaces().Get(ctx, addon.Namespace, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error getting namespace %q of addon %q: %v", addon.Namespace, addon.Name, err)
+	}
+	_, found := namespace.Annotations[addonVersionAnnotationPrefix+addon.Name]
+	return found, nil
+}
+
+// patchNeedsUpdate sets the needs-update annotation of the node to value, or removes it if value is nil.
+// The patch fails if the node has changed since it was read, so that we never drop a request we haven't checked.
+func (r *NeedsUpdateReconciler) patchNeedsUpdate(ctx context.Context, node *corev1.Node, value *string) error {
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"resourceVersion": node.ResourceVersion,
+			"annotations": map[string]interface{}{
+				api.NeedsUpdateAnnotation: value,
+			},
+		},
+	}
+	patchJSON, err := json.Marshal(patch)
+	if err != nil {
+		return fmt.Errorf("error building node patch: %v", err)
+	}
+
+	klog.V(2).Infof("sending patch for node %q: %q", node.Name, string(patchJSON))
+
+	if _, err := r.coreV1Client.Nodes().Patch(ctx, node.Name, types.MergePatchType, patchJSON, metav1.PatchOptions{}); err != nil {
+		nodePatches.WithLabelValues(resultError).Inc()
+		return fmt.Errorf("error applying patch to node: %v", err)
+	}
+	nodePatches.WithLabelValues(resultSuccess).Inc()
+	return nil
+}
+
+// clearedRequest is a request that no longer holds, with the reason why
+type clearedRequest struct {
+	reason  string
+	message string
+}
+
+// staleNeedsUpdateRequests checks the requests of the needs-update annotation of the node,
+// returning those that still hold and the reasons the others were cleared.
+func staleNeedsUpdateRequests(node *corev1.Node, needsUpdate *api.NeedsUpdate, installed func(addon api.NeedsUpdateAddon) (bool, error)) (*api.NeedsUpdate, []clearedRequest, error) {
+	bootID := node.Status.NodeInfo.BootID
+	if needsUpdate.BootID != "" && bootID != "" && needsUpdate.BootID != bootID {
+		var names []string
+		for _, addon := range needsUpdate.Addons {
+			names = append(names, addon.Name)
+		}
+		return &api.NeedsUpdate{}, []clearedRequest{{
+			reason:  needsUpdateReasonRebooted,
+			message: fmt.Sprintf("the node has rebooted, applying its configuration again, since addons [%s] requested the update", strings.Join(names, ", ")),
+		}}, nil
+	}
+
+	remaining := &api.NeedsUpdate{BootID: needsUpdate.BootID}
+	var cleared []clearedRequest
+	for _, addon := range needsUpdate.Addons {
+		ok, err := installed(addon)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			cleared = append(cleared, clearedRequest{
+				reason:  needsUpdateReasonAddonRemoved,
+				message: fmt.Sprintf("addon %q, which requested the update, has been removed from namespace %q", addon.Name, addon.Namespace),
+			})
+			continue
+		}
+		remaining.Addons = append(remaining.Addons, addon)
+	}
+	return remaining, cleared, nil
+}
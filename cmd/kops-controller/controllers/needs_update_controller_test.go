@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/kops/channels/pkg/api"
+)
+
+func TestStaleNeedsUpdateRequests(t *testing.T) {
+	cilium := api.NeedsUpdateAddon{Name: "networking.cilium.io", Namespace: "kube-system", Version: "1.10.0"}
+	custom := api.NeedsUpdateAddon{Name: "custom.example.com", Namespace: "custom", Version: "1.0.0"}
+
+	grid := []struct {
+		name            string
+		bootID          string
+		needsUpdate     *api.NeedsUpdate
+		removed         []string
+		expectRemaining []api.NeedsUpdateAddon
+		expectReasons   []string
+	}{
+		{
+			name:            "requests hold",
+			bootID:          "boot-1",
+			needsUpdate:     &api.NeedsUpdate{BootID: "boot-1", Addons: []api.NeedsUpdateAddon{cilium, custom}},
+			expectRemaining: []api.NeedsUpdateAddon{cilium, custom},
+		},
+		{
+			name:          "node rebooted",
+			bootID:        "boot-2",
+			needsUpdate:   &api.NeedsUpdate{BootID: "boot-1", Addons: []api.NeedsUpdateAddon{cilium, custom}},
+			expectReasons: []string{needsUpdateReasonRebooted},
+		},
+		{
+			name:            "boot id not reported",
+			needsUpdate:     &api.NeedsUpdate{BootID: "boot-1", Addons: []api.NeedsUpdateAddon{cilium}},
+			expectRemaining: []api.NeedsUpdateAddon{cilium},
+		},
+		{
+			name:            "boot id not recorded",
+			bootID:          "boot-2",
+			needsUpdate:     &api.NeedsUpdate{Addons: []api.NeedsUpdateAddon{cilium}},
+			expectRemaining: []api.NeedsUpdateAddon{cilium},
+		},
+		{
+			name:            "one addon removed",
+			bootID:          "boot-1",
+			needsUpdate:     &api.NeedsUpdate{BootID: "boot-1", Addons: []api.NeedsUpdateAddon{cilium, custom}},
+			removed:         []string{custom.Name},
+			expectRemaining: []api.NeedsUpdateAddon{cilium},
+			expectReasons:   []string{needsUpdateReasonAddonRemoved},
+		},
+		{
+			name:          "all addons removed",
+			bootID:        "boot-1",
+			needsUpdate:   &api.NeedsUpdate{BootID: "boot-1", Addons: []api.NeedsUpdateAddon{cilium, custom}},
+			removed:       []string{cilium.Name, custom.Name},
+			expectReasons: []string{needsUpdateReasonAddonRemoved, needsUpdateReasonAddonRemoved},
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			node := &corev1.Node{}
+			node.Status.NodeInfo.BootID = g.bootID
+
+			remaining, cleared, err := staleNeedsUpdateRequests(node, g.needsUpdate, func(addon api.NeedsUpdateAddon) (bool, error) {
+				for _, name := range g.removed {
+					if addon.Name == name {
+						return false, nil
+					}
+				}
+				return true, nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(remaining.Addons, g.expectRemaining) {
+				t.Errorf("expected remaining requests %v, got %v", g.expectRemaining, remaining.Addons)
+			}
+			var reasons []string
+			for _, c := range cleared {
+				reasons = append(reasons, c.reason)
+			}
+			if !reflect.DeepEqual(reasons, g.expectReasons) {
+				t.Errorf("expected reasons %v, got %v", g.expectReasons, reasons)
+			}
+		})
+	}
+
+	node := &corev1.Node{}
+	_, _, err := staleNeedsUpdateRequests(node, &api.NeedsUpdate{Addons: []api.NeedsUpdateAddon{cilium}}, func(addon api.NeedsUpdateAddon) (bool, error) {
+		return false, fmt.Errorf("apiserver unavailable")
+	})
+	if err == nil {
+		t.Errorf("expected error when the addons can't be checked")
+	}
+}
@@ -20,13 +20,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/nodeidentity"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -39,6 +42,7 @@ func NewNodeReconciler(mgr manager.Manager, identifier nodeidentity.Identifier)
 	r := &NodeReconciler{
 		client:     mgr.GetClient(),
 		log:        ctrl.Log.WithName("controllers").WithName("Node"),
+		recorder:   mgr.GetEventRecorderFor(eventSource),
 		identifier: identifier,
 	}
 
@@ -60,6 +64,9 @@ type NodeReconciler struct {
 	// log is a logr
 	log logr.Logger
 
+	// recorder records the events of the changes we make to nodes
+	recorder record.EventRecorder
+
 	// coreV1Client is a client-go client for patching nodes
 	coreV1Client *corev1client.CoreV1Client
 
@@ -86,25 +93,20 @@ func (r *NodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 
 	info, err := r.identifier.IdentifyNode(ctx, node)
 	if err != nil {
+		nodeIdentifyErrors.Inc()
+		r.recorder.Eventf(node, corev1.EventTypeWarning, "IdentifyFailed", "Failed to identify the instance group of the node: %v", err)
 		return ctrl.Result{}, fmt.Errorf("error identifying node %q: %v", node.Name, err)
 	}
 
-	labels := info.Labels
-
-	updateLabels := make(map[string]string)
-	for k, v := range labels {
-		actual, found := node.Labels[k]
-		if !found || actual != v {
-			updateLabels[k] = v
-		}
-	}
+	updateLabels := changedValues(node.Labels, info.Labels)
+	updateAnnotations := changedValues(node.Annotations, info.Annotations)
 
-	if len(updateLabels) == 0 {
+	if len(updateLabels) == 0 && len(updateAnnotations) == 0 {
 		klog.V(4).Infof("no label changes needed for %s", node.Name)
 		return ctrl.Result{}, nil
 	}
 
-	if err := patchNodeLabels(r.coreV1Client, ctx, node, updateLabels); err != nil {
+	if err := updateNode(ctx, r.coreV1Client, r.recorder, node, updateLabels, updateAnnotations); err != nil {
 		klog.Warningf("failed to patch node labels on %s: %v", node.Name, err)
 		return ctrl.Result{}, err
 	}
@@ -118,18 +120,48 @@ func (r *NodeReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Complete(r)
 }
 
+// eventSource is the component of the events kops-controller records
+const eventSource = "kops-controller"
+
 type nodePatch struct {
 	Metadata *nodePatchMetadata `json:"metadata,omitempty"`
 }
 
 type nodePatchMetadata struct {
-	Labels map[string]string `json:"labels,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// changedValues returns the entries of desired that are missing from or different in actual
+func changedValues(actual map[string]string, desired map[string]string) map[string]string {
+	changed := make(map[string]string)
+	for k, v := range desired {
+		if value, found := actual[k]; !found || value != v {
+			changed[k] = v
+		}
+	}
+	return changed
+}
+
+// updateNode patches the node to set the specified labels and annotations,
+// and records the outcome as an event on the node
+func updateNode(ctx context.Context, client *corev1client.CoreV1Client, recorder record.EventRecorder, node *corev1.Node, setLabels map[string]string, setAnnotations map[string]string) error {
+	if err := patchNode(client, ctx, node, setLabels, setAnnotations); err != nil {
+		nodePatches.WithLabelValues(resultError).Inc()
+		recorder.Eventf(node, corev1.EventTypeWarning, "UpdateFailed", "Failed to update the labels and annotations of the node: %v", err)
+		return err
+	}
+	nodePatches.WithLabelValues(resultSuccess).Inc()
+	recorder.Eventf(node, corev1.EventTypeNormal, "Updated", "Updated labels [%s] and annotations [%s] from the instance group",
+		strings.Join(sets.StringKeySet(setLabels).List(), ", "), strings.Join(sets.StringKeySet(setAnnotations).List(), ", "))
+	return nil
 }
 
-// patchNodeLabels patches the node labels to set the specified labels
-func patchNodeLabels(client *corev1client.CoreV1Client, ctx context.Context, node *corev1.Node, setLabels map[string]string) error {
+// patchNode patches the node to set the specified labels and annotations
+func patchNode(client *corev1client.CoreV1Client, ctx context.Context, node *corev1.Node, setLabels map[string]string, setAnnotations map[string]string) error {
 	nodePatchMetadata := &nodePatchMetadata{
-		Labels: setLabels,
+		Labels:      setLabels,
+		Annotations: setAnnotations,
 	}
 	nodePatch := &nodePatch{
 		Metadata: nodePatchMetadata,
@@ -22,14 +22,16 @@ import (
 	"io/ioutil"
 	"os"
 
+	certificatesv1 "k8s.io/api/certificates/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	"k8s.io/klog/v2"
-	"k8s.io/klog/v2/klogr"
 	"k8s.io/kops/cmd/kops-controller/controllers"
 	"k8s.io/kops/cmd/kops-controller/pkg/config"
 	"k8s.io/kops/cmd/kops-controller/pkg/server"
+	"k8s.io/kops/pkg/jointoken"
+	"k8s.io/kops/pkg/logformat"
 	"k8s.io/kops/pkg/nodeidentity"
 	nodeidentityaws "k8s.io/kops/pkg/nodeidentity/aws"
 	nodeidentityazure "k8s.io/kops/pkg/nodeidentity/azure"
@@ -38,6 +40,7 @@ import (
 	nodeidentityos "k8s.io/kops/pkg/nodeidentity/openstack"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/yaml"
@@ -56,16 +59,17 @@ func init() {
 
 func main() {
 	klog.InitFlags(nil)
-
-	// Disable metrics by default (avoid port conflicts, also risky because we are host network)
-	metricsAddress := ":0"
-	//flag.StringVar(&metricsAddr, "metrics-addr", metricsAddress, "The address the metric endpoint binds to.")
+	logformat.InitFlags(nil)
 
 	configPath := "/etc/kubernetes/kops-controller/config.yaml"
 	flag.StringVar(&configPath, "conf", configPath, "Location of yaml configuration file")
 
 	flag.Parse()
 
+	if err := logformat.Apply(); err != nil {
+		klog.Fatalf("%v", err)
+	}
+
 	if configPath == "" {
 		klog.Fatalf("must specify --conf")
 	}
@@ -84,18 +88,18 @@ func main() {
 		}
 	}
 
-	ctrl.SetLogger(klogr.New())
+	// Disable metrics unless configured (avoid port conflicts, also risky because we are host network)
+	metricsAddress := "0"
+	if opt.MetricsAddress != "" {
+		metricsAddress = opt.MetricsAddress
+	}
+
+	ctrl.SetLogger(logformat.Logger())
 	if opt.Server != nil {
-		var verifier fi.Verifier
-		var err error
-		if opt.Server.Provider.AWS != nil {
-			verifier, err = awsup.NewAWSVerifier(opt.Server.Provider.AWS)
-			if err != nil {
-				setupLog.Error(err, "unable to create verifier")
-				os.Exit(1)
-			}
-		} else {
-			klog.Fatalf("server cloud provider config not provided")
+		verifier, err := buildVerifier(opt.Server)
+		if err != nil {
+			setupLog.Error(err, "unable to create verifier")
+			os.Exit(1)
 		}
 
 		srv, err := server.NewServer(&opt, verifier)
@@ -130,6 +134,18 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "NodeController")
 		os.Exit(1)
 	}
+
+	if err := addNeedsUpdateController(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NeedsUpdateController")
+		os.Exit(1)
+	}
+
+	if opt.ApproveKubeletCertificates {
+		if err := addCSRApproverController(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "CSRApproverController")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	setupLog.Info("starting manager")
@@ -143,9 +159,48 @@ func buildScheme() error {
 	if err := corev1.AddToScheme(scheme); err != nil {
 		return fmt.Errorf("error registering corev1: %v", err)
 	}
+	if err := certificatesv1.AddToScheme(scheme); err != nil {
+		return fmt.Errorf("error registering certificatesv1: %v", err)
+	}
 	return nil
 }
 
+// buildVerifier returns the verifier for the ways nodes may authenticate to the server.
+func buildVerifier(opt *config.ServerOptions) (fi.Verifier, error) {
+	verifier := fi.MultiVerifier{}
+
+	switch {
+	case opt.Provider.AWS != nil:
+		awsVerifier, err := awsup.NewAWSVerifier(opt.Provider.AWS)
+		if err != nil {
+			return nil, err
+		}
+		verifier[awsup.AWSAuthenticationTokenPrefix] = awsVerifier
+	case opt.Provider.GCE != nil:
+		gceVerifier, err := gce.NewGCEVerifier(opt.Provider.GCE)
+		if err != nil {
+			return nil, err
+		}
+		verifier[gce.GCEAuthenticationTokenPrefix] = gceVerifier
+	default:
+		return nil, fmt.Errorf("server cloud provider config not provided")
+	}
+
+	if opt.JoinTokenKeyPath != "" {
+		key, err := jointoken.ReadKey(opt.JoinTokenKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		nodes, err := server.NewNodeLookup()
+		if err != nil {
+			return nil, err
+		}
+		verifier[jointoken.JoinTokenAuthenticationTokenPrefix] = jointoken.NewVerifier(key, nodes)
+	}
+
+	return verifier, nil
+}
+
 func addNodeController(mgr manager.Manager, opt *config.Options) error {
 	var legacyIdentifier nodeidentity.LegacyIdentifier
 	var identifier nodeidentity.Identifier
@@ -212,3 +267,19 @@ func addNodeController(mgr manager.Manager, opt *config.Options) error {
 
 	return nil
 }
+
+func addCSRApproverController(mgr manager.Manager) error {
+	csrApprover, err := controllers.NewCSRApproverReconciler(mgr)
+	if err != nil {
+		return err
+	}
+	return csrApprover.SetupWithManager(mgr)
+}
+
+func addNeedsUpdateController(mgr manager.Manager) error {
+	needsUpdate, err := controllers.NewNeedsUpdateReconciler(mgr)
+	if err != nil {
+		return err
+	}
+	return needsUpdate.SetupWithManager(mgr)
+}
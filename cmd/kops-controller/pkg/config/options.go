@@ -16,13 +16,25 @@ limitations under the License.
 
 package config
 
-import "k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+)
 
 type Options struct {
 	Cloud                 string         `json:"cloud,omitempty"`
 	ConfigBase            string         `json:"configBase,omitempty"`
 	Server                *ServerOptions `json:"server,omitempty"`
 	CacheNodeidentityInfo bool           `json:"cacheNodeidentityInfo,omitempty"`
+
+	// ApproveKubeletCertificates enables approval of the certificate signing requests
+	// kubelets make to renew their client and serving certificates.
+	ApproveKubeletCertificates bool `json:"approveKubeletCertificates,omitempty"`
+
+	// MetricsAddress is the network endpoint (ip and port) where we serve Prometheus metrics.
+	// If empty, metrics are not served.
+	MetricsAddress string `json:"metricsAddress,omitempty"`
 }
 
 func (o *Options) PopulateDefaults() {
@@ -46,8 +58,35 @@ type ServerOptions struct {
 	SigningCAs []string `json:"signingCAs"`
 	// CertNames is the list of active certificate names.
 	CertNames []string `json:"certNames"`
+	// KubeletCertificateValidity is how long the kubelet certificates we issue are valid for.
+	// If unset, they are valid for about 15 months.
+	KubeletCertificateValidity *metav1.Duration `json:"kubeletCertificateValidity,omitempty"`
+	// JoinTokenKeyPath is the path to the key that join tokens are issued with.
+	// If set, nodes may also authenticate with a join token for their instance group.
+	JoinTokenKeyPath string `json:"joinTokenKeyPath,omitempty"`
+	// InstanceGroupScaling configures the API for resizing instance groups.
+	// If nil, the API is not served.
+	InstanceGroupScaling *InstanceGroupScalingOptions `json:"instanceGroupScaling,omitempty"`
+	// Discovery configures the endpoint that serves the discovery records of gossip clusters.
+	// If nil, the endpoint is not served.
+	Discovery *DiscoveryOptions `json:"discovery,omitempty"`
+}
+
+// InstanceGroupScalingOptions configures the API that authorized clients in the cluster use to resize instance groups.
+type InstanceGroupScalingOptions struct {
+	// ClusterName is the name of the cluster, which the cloud groups of its instance groups are named and tagged with.
+	ClusterName string `json:"clusterName"`
+	// StateStoreLockTable is the DynamoDB table that locks the state store, if it is on S3.
+	StateStoreLockTable string `json:"stateStoreLockTable,omitempty"`
+}
+
+// DiscoveryOptions configures the endpoint that replaces the gossip mesh, serving the records dns-controller keeps in a ConfigMap.
+type DiscoveryOptions struct {
+	// ConfigMapName is the name of the ConfigMap in kube-system that holds the records.
+	ConfigMapName string `json:"configMapName"`
 }
 
 type ServerProviderOptions struct {
 	AWS *awsup.AWSVerifierOptions `json:"aws,omitempty"`
+	GCE *gce.GCEVerifierOptions   `json:"gce,omitempty"`
 }
@@ -0,0 +1,125 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/protokube/pkg/gossip/kopscontroller"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// discoveryCacheTTL is how long the discovery records are served before they are read again.
+// Every node polls for the records, so this bounds the load on the apiserver regardless of the size of the cluster.
+const discoveryCacheTTL = 5 * time.Second
+
+// discoveryCache holds the discovery records last read from the ConfigMap.
+type discoveryCache struct {
+	client        kubernetes.Interface
+	configMapName string
+
+	mutex   sync.Mutex
+	fetched time.Time
+	etag    string
+	body    []byte
+}
+
+// enableDiscovery sets up the client the discovery endpoint needs.
+func (s *Server) enableDiscovery() error {
+	restConfig, err := ctrl.GetConfig()
+	if err != nil {
+		return fmt.Errorf("building kubernetes client config: %v", err)
+	}
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("building kubernetes client: %v", err)
+	}
+	s.discovery = &discoveryCache{
+		client:        client,
+		configMapName: s.opt.Server.Discovery.ConfigMapName,
+	}
+	return nil
+}
+
+// get returns the serialized discovery records and their ETag, reading them again if the cached copy has expired.
+func (c *discoveryCache) get(ctx context.Context) (string, []byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.body != nil && time.Since(c.fetched) < discoveryCacheTTL {
+		return c.etag, c.body, nil
+	}
+
+	configMap, err := c.client.CoreV1().ConfigMaps(kopscontroller.ConfigMapNamespace).Get(ctx, c.configMapName, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return "", nil, fmt.Errorf("reading ConfigMap %q: %v", c.configMapName, err)
+		}
+		// dns-controller has not written any records yet
+		configMap = &v1.ConfigMap{}
+	}
+
+	values, err := kopscontroller.ValuesFromConfigMap(configMap)
+	if err != nil {
+		return "", nil, err
+	}
+	body, err := json.Marshal(&kopscontroller.DiscoveryResponse{Values: values})
+	if err != nil {
+		return "", nil, err
+	}
+
+	c.fetched = time.Now()
+	c.etag = strconv.Quote(configMap.ResourceVersion)
+	c.body = body
+	return c.etag, c.body, nil
+}
+
+// serveDiscovery serves GET requests for the discovery records.
+// The records are not confidential, so clients are not authenticated; they verify the server with the cluster CA.
+func (s *Server) serveDiscovery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	etag, body, err := s.discovery.get(r.Context())
+	if err != nil {
+		klog.Infof("discovery %s: %v", r.RemoteAddr, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kops/protokube/pkg/gossip/kopscontroller"
+)
+
+func TestServeDiscovery(t *testing.T) {
+	client := fake.NewSimpleClientset(&v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       kopscontroller.ConfigMapNamespace,
+			Name:            kopscontroller.ConfigMapName,
+			ResourceVersion: "42",
+		},
+		Data: map[string]string{
+			"values.json": `{"dns/local/A/api.internal.minimal.k8s.local":"10.0.0.1"}`,
+		},
+	})
+	s := &Server{
+		discovery: &discoveryCache{
+			client:        client,
+			configMapName: kopscontroller.ConfigMapName,
+		},
+	}
+
+	rec := httptest.NewRecorder()
+	s.serveDiscovery(rec, httptest.NewRequest(http.MethodGet, kopscontroller.DiscoveryPath, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body.String())
+	}
+	etag := rec.Header().Get("ETag")
+	if etag != `"42"` {
+		t.Errorf("unexpected ETag %q", etag)
+	}
+	response := &kopscontroller.DiscoveryResponse{}
+	if err := json.Unmarshal(rec.Body.Bytes(), response); err != nil {
+		t.Fatalf("error parsing response: %v", err)
+	}
+	expected := map[string]string{
+		"dns/local/A/api.internal.minimal.k8s.local": "10.0.0.1",
+	}
+	if !reflect.DeepEqual(response.Values, expected) {
+		t.Errorf("unexpected values: %v", response.Values)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, kopscontroller.DiscoveryPath, nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	s.serveDiscovery(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("expected status %d for unchanged records, got %d", http.StatusNotModified, rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	s.serveDiscovery(rec, httptest.NewRequest(http.MethodPost, kopscontroller.DiscoveryPath, nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status %d for POST, got %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// bootstrapRequests counts the bootstrap requests of nodes, by response code.
+	bootstrapRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kops_controller_bootstrap_requests_total",
+		Help: "Number of node bootstrap requests, by HTTP response code.",
+	}, []string{"code"})
+
+	// bootstrapDuration observes how long serving the bootstrap requests of nodes takes, by response code.
+	bootstrapDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kops_controller_bootstrap_request_duration_seconds",
+		Help:    "Time taken to serve node bootstrap requests, by HTTP response code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"code"})
+
+	// certificatesIssued counts the certificates issued to bootstrapping nodes, by certificate name.
+	certificatesIssued = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kops_controller_certificates_issued_total",
+		Help: "Number of certificates issued to bootstrapping nodes, by certificate name.",
+	}, []string{"name"})
+)
+
+func init() {
+	// The manager serves the metrics of the controller-runtime registry on its metrics endpoint
+	metrics.Registry.MustRegister(bootstrapRequests, bootstrapDuration, certificatesIssued)
+}
+
+// instrumentBootstrap records the number and the latency of the bootstrap requests served by next.
+func instrumentBootstrap(next http.Handler) http.Handler {
+	return promhttp.InstrumentHandlerCounter(bootstrapRequests, promhttp.InstrumentHandlerDuration(bootstrapDuration, next))
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/jointoken"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// kubernetesNodeLookup finds the instance group of nodes from their label in the cluster.
+type kubernetesNodeLookup struct {
+	client kubernetes.Interface
+}
+
+var _ jointoken.NodeLookup = &kubernetesNodeLookup{}
+
+// NewNodeLookup returns a lookup of the instance group of the nodes of the cluster kops-controller runs in.
+func NewNodeLookup() (jointoken.NodeLookup, error) {
+	restConfig, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("building kubernetes client config: %v", err)
+	}
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("building kubernetes client: %v", err)
+	}
+	return &kubernetesNodeLookup{client: client}, nil
+}
+
+func (l *kubernetesNodeLookup) NodeInstanceGroup(ctx context.Context, nodeName string) (string, bool, error) {
+	node, err := l.client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	return node.Labels[kops.NodeLabelInstanceGroup], true, nil
+}
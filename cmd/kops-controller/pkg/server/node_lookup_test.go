@@ -0,0 +1,42 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNodeLookup(t *testing.T) {
+	lookup := &kubernetesNodeLookup{client: fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"kops.k8s.io/instancegroup": "nodes"}}},
+	)}
+
+	instanceGroup, found, err := lookup.NodeInstanceGroup(context.Background(), "node-1")
+	if err != nil || !found || instanceGroup != "nodes" {
+		t.Errorf("unexpected lookup of node-1: %q %v %v", instanceGroup, found, err)
+	}
+
+	_, found, err = lookup.NodeInstanceGroup(context.Background(), "node-2")
+	if err != nil || found {
+		t.Errorf("unexpected lookup of node-2: %v %v", found, err)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
+	"k8s.io/kops/pkg/apis/kops"
+	nodeidentityaws "k8s.io/kops/pkg/nodeidentity/aws"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// awsGroupScaler resizes the autoscaling groups of node instance groups.
+type awsGroupScaler struct {
+	clusterName string
+	autoscaling autoscalingiface.AutoScalingAPI
+}
+
+var _ groupScaler = &awsGroupScaler{}
+
+func newAWSGroupScaler(region string, clusterName string) (*awsGroupScaler, error) {
+	config := aws.NewConfig().WithCredentialsChainVerboseErrors(true).WithRegion(region)
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &awsGroupScaler{
+		clusterName: clusterName,
+		autoscaling: autoscaling.New(sess, config),
+	}, nil
+}
+
+// findGroup returns the autoscaling group of a node instance group.
+func (a *awsGroupScaler) findGroup(ig *kops.InstanceGroup) (*autoscaling.Group, error) {
+	// This is the name kops gives the autoscaling groups of node instance groups.
+	name := ig.Name + "." + a.clusterName
+
+	response, err := a.autoscaling.DescribeAutoScalingGroups(&autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: []*string{aws.String(name)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error describing autoscaling group %q: %v", name, err)
+	}
+	if len(response.AutoScalingGroups) != 1 {
+		return nil, fmt.Errorf("autoscaling group %q not found", name)
+	}
+	group := response.AutoScalingGroups[0]
+
+	// Refuse to touch a group that happens to have the expected name but was not created for this instance group.
+	tags := map[string]string{}
+	for _, tag := range group.Tags {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	if tags[awsup.TagClusterName] != a.clusterName || tags[nodeidentityaws.CloudTagInstanceGroupName] != ig.Name {
+		return nil, fmt.Errorf("autoscaling group %q is not tagged as instance group %q of cluster %q", name, ig.Name, a.clusterName)
+	}
+
+	return group, nil
+}
+
+func (a *awsGroupScaler) GetScale(ig *kops.InstanceGroup) (*InstanceGroupScale, error) {
+	group, err := a.findGroup(ig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InstanceGroupScale{
+		MinSize:    aws.Int32(int32(aws.Int64Value(group.MinSize))),
+		MaxSize:    aws.Int32(int32(aws.Int64Value(group.MaxSize))),
+		TargetSize: aws.Int32(int32(aws.Int64Value(group.DesiredCapacity))),
+	}, nil
+}
+
+func (a *awsGroupScaler) SetScale(ig *kops.InstanceGroup, scale *InstanceGroupScale) error {
+	group, err := a.findGroup(ig)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.autoscaling.UpdateAutoScalingGroup(&autoscaling.UpdateAutoScalingGroupInput{
+		AutoScalingGroupName: group.AutoScalingGroupName,
+		MinSize:              aws.Int64(int64(*scale.MinSize)),
+		MaxSize:              aws.Int64(int64(*scale.MaxSize)),
+		DesiredCapacity:      aws.Int64(int64(*scale.TargetSize)),
+	})
+	if err != nil {
+		return fmt.Errorf("error updating autoscaling group %q: %v", aws.StringValue(group.AutoScalingGroupName), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,329 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cloudmock/aws/mockautoscaling"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/registry"
+	"k8s.io/kops/pkg/client/simple/vfsclientset"
+	"k8s.io/kops/pkg/kopscodecs"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// fakeScaleAuthorizer allows tokens of the form "<user>:<verb>,<verb>".
+type fakeScaleAuthorizer struct{}
+
+func (fakeScaleAuthorizer) Authorize(ctx context.Context, token string, verb string, instanceGroupName string) (string, bool, error) {
+	parts := strings.SplitN(token, ":", 2)
+	if len(parts) != 2 {
+		return "", false, nil
+	}
+	for _, allowed := range strings.Split(parts[1], ",") {
+		if allowed == verb {
+			return parts[0], true, nil
+		}
+	}
+	return parts[0], false, nil
+}
+
+func newScaleTestServer(t *testing.T) (*Server, *mockautoscaling.MockAutoscaling) {
+	clusterName := "minimal.example.com"
+	configBase := vfs.NewMemFSPath(vfs.NewMemFSContext(), "memfs://tests/"+clusterName)
+
+	cluster := &kops.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: clusterName},
+		Spec: kops.ClusterSpec{
+			CloudProvider: "aws",
+			ConfigBase:    configBase.Path(),
+		},
+	}
+	b, err := kopscodecs.ToVersionedYaml(cluster)
+	if err != nil {
+		t.Fatalf("error serializing cluster: %v", err)
+	}
+	if err := configBase.Join(registry.PathClusterCompleted).WriteFile(bytes.NewReader(b), nil); err != nil {
+		t.Fatalf("error writing cluster: %v", err)
+	}
+
+	mirror := vfsclientset.NewInstanceGroupMirror(cluster, configBase)
+	for name, role := range map[string]kops.InstanceGroupRole{
+		"nodes":             kops.InstanceGroupRoleNode,
+		"master-us-test-1a": kops.InstanceGroupRoleMaster,
+	} {
+		ig := &kops.InstanceGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: kops.InstanceGroupSpec{
+				Role:    role,
+				MinSize: fi.Int32(1),
+				MaxSize: fi.Int32(1),
+				Subnets: []string{"us-test-1a"},
+			},
+		}
+		if err := mirror.WriteMirror(ig); err != nil {
+			t.Fatalf("error writing instance group: %v", err)
+		}
+	}
+
+	cloud := &mockautoscaling.MockAutoscaling{
+		Groups: map[string]*autoscaling.Group{
+			"nodes." + clusterName: {
+				AutoScalingGroupName: aws.String("nodes." + clusterName),
+				MinSize:              aws.Int64(1),
+				MaxSize:              aws.Int64(1),
+				DesiredCapacity:      aws.Int64(1),
+				Tags: []*autoscaling.TagDescription{
+					{Key: aws.String("KubernetesCluster"), Value: aws.String(clusterName)},
+					{Key: aws.String("kops.k8s.io/instancegroup"), Value: aws.String("nodes")},
+				},
+			},
+		},
+	}
+
+	s := &Server{
+		configBase:      configBase,
+		scaleAuthorizer: fakeScaleAuthorizer{},
+		groupScaler: &awsGroupScaler{
+			clusterName: clusterName,
+			autoscaling: cloud,
+		},
+	}
+	return s, cloud
+}
+
+func TestScaleInstanceGroupLocked(t *testing.T) {
+	s, cloud := newScaleTestServer(t)
+
+	info, err := vfs.NewLockInfo("update cluster")
+	if err != nil {
+		t.Fatalf("error building lock info: %v", err)
+	}
+	unlock, err := vfs.Lock(s.configBase.Join(registry.PathLock), info)
+	if err != nil {
+		t.Fatalf("error taking lock: %v", err)
+	}
+
+	scale := func(method string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/instancegroups/nodes/scale", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer autoscaler:get,patch")
+		w := httptest.NewRecorder()
+		s.scaleInstanceGroup(w, req)
+		return w
+	}
+
+	if w := scale(http.MethodPatch, `{"maxSize": 5}`); w.Code != http.StatusConflict {
+		t.Fatalf("unexpected status %d while the cluster is locked, expected %d: %s", w.Code, http.StatusConflict, w.Body.String())
+	}
+	if group := cloud.Groups["nodes.minimal.example.com"]; aws.Int64Value(group.MaxSize) != 1 {
+		t.Errorf("autoscaling group was changed while the cluster is locked")
+	}
+	if w := scale(http.MethodGet, ""); w.Code != http.StatusOK {
+		t.Errorf("unexpected status %d reading the scale while the cluster is locked: %s", w.Code, w.Body.String())
+	}
+
+	if err := unlock(); err != nil {
+		t.Fatalf("error releasing lock: %v", err)
+	}
+	if w := scale(http.MethodPatch, `{"maxSize": 5}`); w.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d once the cluster is unlocked: %s", w.Code, w.Body.String())
+	}
+
+	// The update released the lock
+	unlock, err = vfs.Lock(s.configBase.Join(registry.PathLock), info)
+	if err != nil {
+		t.Fatalf("error taking lock after the update: %v", err)
+	}
+	if err := unlock(); err != nil {
+		t.Errorf("error releasing lock: %v", err)
+	}
+}
+
+func TestScaleInstanceGroupS3WithoutLockTable(t *testing.T) {
+	s, cloud := newScaleTestServer(t)
+
+	// Without a lock table, S3 state stores cannot be locked
+	defer os.Setenv(vfs.S3LockTableEnv, os.Getenv(vfs.S3LockTableEnv))
+	os.Unsetenv(vfs.S3LockTableEnv)
+	configBase, err := vfs.Context.BuildVfsPath("s3://bucket/minimal.example.com")
+	if err != nil {
+		t.Fatalf("error building S3 path: %v", err)
+	}
+	s.configBase = configBase
+
+	req := httptest.NewRequest(http.MethodPatch, "/instancegroups/nodes/scale", strings.NewReader(`{"maxSize": 5}`))
+	req.Header.Set("Authorization", "Bearer autoscaler:get,patch")
+	w := httptest.NewRecorder()
+	s.scaleInstanceGroup(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected status %d, expected %d: %s", w.Code, http.StatusServiceUnavailable, w.Body.String())
+	}
+	if group := cloud.Groups["nodes.minimal.example.com"]; aws.Int64Value(group.MaxSize) != 1 {
+		t.Errorf("autoscaling group was changed without the lock")
+	}
+}
+
+func TestScaleInstanceGroup(t *testing.T) {
+	grid := []struct {
+		Name           string
+		Method         string
+		Path           string
+		Token          string
+		Body           string
+		ExpectedStatus int
+		ExpectedScale  InstanceGroupScale
+	}{
+		{
+			Name:           "get",
+			Method:         http.MethodGet,
+			Path:           "/instancegroups/nodes/scale",
+			Token:          "autoscaler:get",
+			ExpectedStatus: http.StatusOK,
+			ExpectedScale:  InstanceGroupScale{MinSize: fi.Int32(1), MaxSize: fi.Int32(1), TargetSize: fi.Int32(1)},
+		},
+		{
+			Name:           "no token",
+			Method:         http.MethodGet,
+			Path:           "/instancegroups/nodes/scale",
+			ExpectedStatus: http.StatusUnauthorized,
+		},
+		{
+			Name:           "invalid token",
+			Method:         http.MethodGet,
+			Path:           "/instancegroups/nodes/scale",
+			Token:          "nobody",
+			ExpectedStatus: http.StatusUnauthorized,
+		},
+		{
+			Name:           "not allowed",
+			Method:         http.MethodPatch,
+			Path:           "/instancegroups/nodes/scale",
+			Token:          "autoscaler:get",
+			Body:           `{"maxSize": 5}`,
+			ExpectedStatus: http.StatusForbidden,
+		},
+		{
+			Name:           "unknown instance group",
+			Method:         http.MethodGet,
+			Path:           "/instancegroups/other/scale",
+			Token:          "autoscaler:get",
+			ExpectedStatus: http.StatusNotFound,
+		},
+		{
+			Name:           "unknown path",
+			Method:         http.MethodGet,
+			Path:           "/instancegroups/nodes",
+			Token:          "autoscaler:get",
+			ExpectedStatus: http.StatusNotFound,
+		},
+		{
+			Name:           "master",
+			Method:         http.MethodPatch,
+			Path:           "/instancegroups/master-us-test-1a/scale",
+			Token:          "autoscaler:patch",
+			Body:           `{"maxSize": 3}`,
+			ExpectedStatus: http.StatusForbidden,
+		},
+		{
+			Name:           "max below min",
+			Method:         http.MethodPatch,
+			Path:           "/instancegroups/nodes/scale",
+			Token:          "autoscaler:patch",
+			Body:           `{"minSize": 3, "maxSize": 2}`,
+			ExpectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			Name:           "target above max",
+			Method:         http.MethodPatch,
+			Path:           "/instancegroups/nodes/scale",
+			Token:          "autoscaler:patch",
+			Body:           `{"targetSize": 2}`,
+			ExpectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			Name:           "patch",
+			Method:         http.MethodPatch,
+			Path:           "/instancegroups/nodes/scale",
+			Token:          "autoscaler:patch",
+			Body:           `{"maxSize": 5, "targetSize": 3}`,
+			ExpectedStatus: http.StatusOK,
+			ExpectedScale:  InstanceGroupScale{MinSize: fi.Int32(1), MaxSize: fi.Int32(5), TargetSize: fi.Int32(3)},
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			s, cloud := newScaleTestServer(t)
+
+			req := httptest.NewRequest(g.Method, g.Path, strings.NewReader(g.Body))
+			if g.Token != "" {
+				req.Header.Set("Authorization", "Bearer "+g.Token)
+			}
+			w := httptest.NewRecorder()
+			s.scaleInstanceGroup(w, req)
+
+			if w.Code != g.ExpectedStatus {
+				t.Fatalf("unexpected status %d, expected %d: %s", w.Code, g.ExpectedStatus, w.Body.String())
+			}
+			if g.ExpectedStatus != http.StatusOK {
+				group := cloud.Groups["nodes.minimal.example.com"]
+				if aws.Int64Value(group.MaxSize) != 1 || aws.Int64Value(group.DesiredCapacity) != 1 {
+					t.Errorf("autoscaling group was changed by a failed request")
+				}
+				return
+			}
+
+			scale := InstanceGroupScale{}
+			if err := json.Unmarshal(w.Body.Bytes(), &scale); err != nil {
+				t.Fatalf("error decoding response: %v", err)
+			}
+			if fi.Int32Value(scale.MinSize) != *g.ExpectedScale.MinSize || fi.Int32Value(scale.MaxSize) != *g.ExpectedScale.MaxSize || fi.Int32Value(scale.TargetSize) != *g.ExpectedScale.TargetSize {
+				t.Errorf("unexpected scale %s", w.Body.String())
+			}
+
+			group := cloud.Groups["nodes.minimal.example.com"]
+			if aws.Int64Value(group.MinSize) != int64(*g.ExpectedScale.MinSize) || aws.Int64Value(group.MaxSize) != int64(*g.ExpectedScale.MaxSize) || aws.Int64Value(group.DesiredCapacity) != int64(*g.ExpectedScale.TargetSize) {
+				t.Errorf("unexpected autoscaling group size %v/%v/%v", aws.Int64Value(group.MinSize), aws.Int64Value(group.MaxSize), aws.Int64Value(group.DesiredCapacity))
+			}
+
+			instanceGroups, err := s.instanceGroupClient()
+			if err != nil {
+				t.Fatalf("error building instance group client: %v", err)
+			}
+			ig, err := instanceGroups.Get(context.TODO(), "nodes", metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("error reading instance group: %v", err)
+			}
+			if fi.Int32Value(ig.Spec.MinSize) != *g.ExpectedScale.MinSize || fi.Int32Value(ig.Spec.MaxSize) != *g.ExpectedScale.MaxSize {
+				t.Errorf("unexpected instance group size %v/%v in state store", fi.Int32Value(ig.Spec.MinSize), fi.Int32Value(ig.Spec.MaxSize))
+			}
+		})
+	}
+}
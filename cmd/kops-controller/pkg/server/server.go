@@ -27,6 +27,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"runtime/debug"
+	"sync"
 	"time"
 
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -35,6 +36,7 @@ import (
 	"k8s.io/kops/pkg/apis/nodeup"
 	"k8s.io/kops/pkg/pki"
 	"k8s.io/kops/pkg/rbac"
+	"k8s.io/kops/protokube/pkg/gossip/kopscontroller"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/util/pkg/vfs"
 )
@@ -48,6 +50,15 @@ type Server struct {
 
 	// configBase is the base of the configuration storage.
 	configBase vfs.Path
+
+	// scaleAuthorizer and groupScaler serve the instance group scaling API, if it is enabled.
+	scaleAuthorizer scaleAuthorizer
+	groupScaler     groupScaler
+	// scaleMutex serializes changes to the size of instance groups.
+	scaleMutex sync.Mutex
+
+	// discovery serves the discovery records of gossip clusters, if it is enabled.
+	discovery *discoveryCache
 }
 
 func NewServer(opt *config.Options, verifier fi.Verifier) (*Server, error) {
@@ -73,7 +84,19 @@ func NewServer(opt *config.Options, verifier fi.Verifier) (*Server, error) {
 	s.configBase = configBase
 
 	r := http.NewServeMux()
-	r.Handle("/bootstrap", http.HandlerFunc(s.bootstrap))
+	r.Handle("/bootstrap", instrumentBootstrap(http.HandlerFunc(s.bootstrap)))
+	if opt.Server.InstanceGroupScaling != nil {
+		if err := s.enableInstanceGroupScaling(); err != nil {
+			return nil, err
+		}
+		r.Handle("/instancegroups/", http.HandlerFunc(s.scaleInstanceGroup))
+	}
+	if opt.Server.Discovery != nil {
+		if err := s.enableDiscovery(); err != nil {
+			return nil, err
+		}
+		r.Handle(kopscontroller.DiscoveryPath, http.HandlerFunc(s.serveDiscovery))
+	}
 	server.Handler = recovery(r)
 
 	return s, nil
@@ -144,15 +167,8 @@ func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 		resp.NodeConfig = nodeConfig
 	}
 
-	// Skew the certificate lifetime by up to 30 days based on information about the requesting node.
-	// This is so that different nodes created at the same time have the certificates they generated
-	// expire at different times, but all certificates on a given node expire around the same time.
-	hash := fnv.New32()
-	_, _ = hash.Write([]byte(r.RemoteAddr))
-	validHours := (455 * 24) + (hash.Sum32() % (30 * 24))
-
 	for name, pubKey := range req.Certs {
-		cert, err := s.issueCert(name, pubKey, id, validHours)
+		cert, err := s.issueCert(name, pubKey, id, s.certificateValidity(name, r.RemoteAddr))
 		if err != nil {
 			klog.Infof("bootstrap %s cert %q issue err: %v", r.RemoteAddr, name, err)
 			w.WriteHeader(http.StatusBadRequest)
@@ -161,13 +177,38 @@ func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 		}
 		resp.Certs[name] = cert
 	}
+	for name := range resp.Certs {
+		certificatesIssued.WithLabelValues(name).Inc()
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(resp)
 	klog.Infof("bootstrap %s %s success", r.RemoteAddr, id.NodeName)
 }
 
-func (s *Server) issueCert(name string, pubKey string, id *fi.VerifyResult, validHours uint32) (string, error) {
+// certificateValidity returns how long the named certificate issued to the requesting node is valid for.
+// The lifetime is skewed based on information about the requesting node. This is so that different nodes
+// created at the same time have the certificates they generated expire at different times,
+// but all certificates on a given node expire around the same time.
+func (s *Server) certificateValidity(name string, remoteAddr string) time.Duration {
+	hash := fnv.New32()
+	_, _ = hash.Write([]byte(remoteAddr))
+	skew := hash.Sum32()
+
+	if validity := s.opt.Server.KubeletCertificateValidity; validity != nil && (name == "kubelet" || name == "kubelet-server") {
+		// Short-lived certificates are renewed by the kubelet, so skew them by up to a tenth of their lifetime.
+		maxSkewMinutes := uint32(validity.Duration / time.Minute / 10)
+		if maxSkewMinutes == 0 {
+			return validity.Duration
+		}
+		return validity.Duration - time.Minute*time.Duration(skew%maxSkewMinutes)
+	}
+
+	// Skew the certificate lifetime by up to 30 days.
+	return time.Hour * time.Duration((455*24)+(skew%(30*24)))
+}
+
+func (s *Server) issueCert(name string, pubKey string, id *fi.VerifyResult, validity time.Duration) (string, error) {
 	block, _ := pem.Decode([]byte(pubKey))
 	if block.Type != "RSA PUBLIC KEY" {
 		return "", fmt.Errorf("unexpected key type %q", block.Type)
@@ -181,7 +222,7 @@ func (s *Server) issueCert(name string, pubKey string, id *fi.VerifyResult, vali
 		Signer:    fi.CertificateIDCA,
 		Type:      "client",
 		PublicKey: key,
-		Validity:  time.Hour * time.Duration(validHours),
+		Validity:  validity,
 	}
 
 	if !s.certNames.Has(name) {
@@ -212,6 +253,10 @@ func (s *Server) issueCert(name string, pubKey string, id *fi.VerifyResult, vali
 		issueReq.Subject = pkix.Name{
 			CommonName: rbac.KubeRouter,
 		}
+	case "calico-windows":
+		issueReq.Subject = pkix.Name{
+			CommonName: rbac.CalicoWindows,
+		}
 	default:
 		return "", fmt.Errorf("unexpected key name")
 	}
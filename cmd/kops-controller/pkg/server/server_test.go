@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cmd/kops-controller/pkg/config"
+)
+
+func TestCertificateValidity(t *testing.T) {
+	s := &Server{
+		opt: &config.Options{
+			Server: &config.ServerOptions{},
+		},
+	}
+
+	for _, addr := range []string{"10.0.0.1:1234", "10.0.0.2:1234", "10.0.0.3:4321"} {
+		validity := s.certificateValidity("kubelet", addr)
+		if validity < 455*24*time.Hour || validity >= 485*24*time.Hour {
+			t.Errorf("unexpected default validity %v for %s", validity, addr)
+		}
+	}
+
+	s.opt.Server.KubeletCertificateValidity = &metav1.Duration{Duration: 24 * time.Hour}
+	for _, addr := range []string{"10.0.0.1:1234", "10.0.0.2:1234", "10.0.0.3:4321"} {
+		for _, name := range []string{"kubelet", "kubelet-server"} {
+			validity := s.certificateValidity(name, addr)
+			if validity <= 21*time.Hour || validity > 24*time.Hour {
+				t.Errorf("unexpected validity %v for %s %s", validity, name, addr)
+			}
+		}
+
+		if s.certificateValidity("kubelet", addr) != s.certificateValidity("kubelet-server", addr) {
+			t.Errorf("kubelet certificates for %s should expire at the same time", addr)
+		}
+
+		validity := s.certificateValidity("kube-proxy", addr)
+		if validity < 455*24*time.Hour {
+			t.Errorf("unexpected kube-proxy validity %v for %s", validity, addr)
+		}
+	}
+}
+
+func TestInstrumentBootstrap(t *testing.T) {
+	handler := instrumentBootstrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+
+	for i := 0; i < 2; i++ {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/bootstrap", nil))
+	}
+
+	metric := &dto.Metric{}
+	if err := bootstrapRequests.WithLabelValues("403").Write(metric); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if metric.GetCounter().GetValue() != 2 {
+		t.Errorf("expected 2 forbidden bootstrap requests, got %v", metric.GetCounter().GetValue())
+	}
+}
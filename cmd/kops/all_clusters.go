@@ -0,0 +1,174 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/tables"
+)
+
+// clusterResult is the outcome of an operation run against one cluster of the state store
+type clusterResult struct {
+	Cluster string `json:"cluster"`
+	Error   string `json:"error,omitempty"`
+}
+
+// forAllClusters runs fn against every cluster in the state store, running at most concurrency
+// operations at once.  The output of each operation is written to out with each line prefixed by
+// the name of its cluster.  The results are sorted by cluster name.
+func forAllClusters(ctx context.Context, f *util.Factory, out io.Writer, concurrency int, fn func(ctx context.Context, cluster *kopsapi.Cluster, out io.Writer) error) ([]*clusterResult, error) {
+	if concurrency < 1 {
+		return nil, fmt.Errorf("--concurrency must be at least 1")
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := clientset.ListClusters(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if len(list.Items) == 0 {
+		return nil, fmt.Errorf("no clusters found")
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	results := make([]*clusterResult, len(list.Items))
+	for i := range list.Items {
+		cluster := &list.Items[i]
+		results[i] = &clusterResult{Cluster: cluster.ObjectMeta.Name}
+
+		wg.Add(1)
+		go func(result *clusterResult) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			w := &prefixWriter{out: out, mutex: &mutex, prefix: "[" + cluster.ObjectMeta.Name + "] "}
+			if err := fn(ctx, cluster, w); err != nil {
+				result.Error = err.Error()
+			}
+			if err := w.Flush(); err != nil {
+				result.Error = err.Error()
+			}
+		}(results[i])
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Cluster < results[j].Cluster
+	})
+	return results, nil
+}
+
+// clusterResultsOutputTable prints the outcome of each cluster,
+// returning an error if the operation failed for any of them.
+func clusterResultsOutputTable(results []*clusterResult, out io.Writer) error {
+	t := &tables.Table{}
+	t.AddColumn("CLUSTER", func(r *clusterResult) string {
+		return r.Cluster
+	})
+	t.AddColumn("STATUS", func(r *clusterResult) string {
+		if r.Error != "" {
+			return "Failed"
+		}
+		return "Succeeded"
+	})
+	t.AddColumn("ERROR", func(r *clusterResult) string {
+		return r.Error
+	})
+
+	fmt.Fprintln(out)
+	if err := t.Render(results, out, "CLUSTER", "STATUS", "ERROR"); err != nil {
+		return err
+	}
+	return clusterResultsError(results)
+}
+
+// clusterResultsError returns an error if the operation failed for any of the clusters
+func clusterResultsError(results []*clusterResult) error {
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+	if failed != 0 {
+		return fmt.Errorf("%d of %d clusters failed", failed, len(results))
+	}
+	return nil
+}
+
+// prefixWriter prefixes each line written to it, writing only complete lines so that
+// the output of concurrent operations sharing the same mutex is not interleaved.
+type prefixWriter struct {
+	out    io.Writer
+	mutex  *sync.Mutex
+	prefix string
+	buf    bytes.Buffer
+}
+
+var _ io.Writer = &prefixWriter{}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		if err := w.writeLine(w.buf.Next(i + 1)); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// Flush writes any incomplete final line
+func (w *prefixWriter) Flush() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	line := append(w.buf.Next(w.buf.Len()), '\n')
+	return w.writeLine(line)
+}
+
+func (w *prefixWriter) writeLine(line []byte) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if _, err := io.WriteString(w.out, w.prefix); err != nil {
+		return err
+	}
+	_, err := w.out.Write(line)
+	return err
+}
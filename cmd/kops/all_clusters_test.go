@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/kopscodecs"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/util/pkg/text"
+)
+
+func TestForAllClusters(t *testing.T) {
+	ctx := context.Background()
+
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+	h.SetupMockAWS()
+
+	factory := util.NewFactory(&util.FactoryOptions{RegistryPath: "memfs://tests"})
+	clientset, err := factory.Clientset()
+	if err != nil {
+		t.Fatalf("error getting clientset: %v", err)
+	}
+
+	contents, err := ioutil.ReadFile("../../tests/integration/create_cluster/minimal-1.21/expected-v1alpha2.yaml")
+	if err != nil {
+		t.Fatalf("error reading manifests: %v", err)
+	}
+	manifest := string(text.SplitContentToSections(contents)[0])
+
+	for _, name := range []string{"c.example.com", "a.example.com", "b.example.com"} {
+		obj, _, err := kopscodecs.Decode([]byte(strings.ReplaceAll(manifest, "minimal.example.com", name)), nil)
+		if err != nil {
+			t.Fatalf("error parsing cluster: %v", err)
+		}
+		if _, err := clientset.CreateCluster(ctx, obj.(*kopsapi.Cluster)); err != nil {
+			t.Fatalf("error creating cluster %q: %v", name, err)
+		}
+	}
+
+	var out bytes.Buffer
+	results, err := forAllClusters(ctx, factory, &out, 2, func(ctx context.Context, cluster *kopsapi.Cluster, out io.Writer) error {
+		fmt.Fprintf(out, "first line\nsecond ")
+		fmt.Fprintf(out, "line\nunterminated")
+		if cluster.ObjectMeta.Name == "b.example.com" {
+			return fmt.Errorf("cluster not yet healthy")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var summary []string
+	for _, r := range results {
+		summary = append(summary, r.Cluster+"="+r.Error)
+	}
+	if actual, expected := strings.Join(summary, ","), "a.example.com=,b.example.com=cluster not yet healthy,c.example.com="; actual != expected {
+		t.Errorf("unexpected results %q, expected %q", actual, expected)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 9 {
+		t.Fatalf("expected 9 lines of output, got:\n%s", out.String())
+	}
+	counts := make(map[string]int)
+	for _, line := range lines {
+		counts[line]++
+	}
+	for _, name := range []string{"a.example.com", "b.example.com", "c.example.com"} {
+		for _, line := range []string{"first line", "second line", "unterminated"} {
+			if counts["["+name+"] "+line] != 1 {
+				t.Errorf("expected the line %q of %s once in output:\n%s", line, name, out.String())
+			}
+		}
+	}
+
+	out.Reset()
+	if err := clusterResultsOutputTable(results, &out); err == nil || err.Error() != "1 of 3 clusters failed" {
+		t.Errorf("unexpected error %v", err)
+	}
+	expected := strings.Join([]string{
+		"",
+		"CLUSTER\t\tSTATUS\t\tERROR",
+		"a.example.com\tSucceeded\t",
+		"b.example.com\tFailed\t\tcluster not yet healthy",
+		"c.example.com\tSucceeded\t",
+		"",
+	}, "\n")
+	if out.String() != expected {
+		t.Errorf("unexpected table:\n%q\nexpected:\n%q", out.String(), expected)
+	}
+}
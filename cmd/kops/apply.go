@@ -0,0 +1,410 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/instancegroups"
+	"k8s.io/kops/pkg/kopscodecs"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/util/pkg/text"
+	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	applyLong = templates.LongDesc(i18n.T(`
+	Apply the Cluster, InstanceGroup and SSHCredential manifests in files or directories to the state store,
+	then update the cloud resources of the cluster to match.
+
+	The manifests must describe exactly one cluster.  The cluster is created if it does not exist,
+	and the cluster and instance groups in the manifests replace those in the state store.
+	With --prune, instance groups of the cluster which are not in the manifests are deleted,
+	along with their cloud resources.  Control plane instance groups are never pruned.
+
+	Without --yes, kops apply only shows the changes it would make to the state store.`))
+
+	applyExample = templates.Examples(i18n.T(`
+	# Show the changes the manifests in a directory would make.
+	kops apply -f ./cluster/
+
+	# Apply the manifests in a directory, delete the instance groups which are not in it,
+	# and update the cloud resources.
+	kops apply -f ./cluster/ --prune --yes
+
+	# Apply the manifests, and write the cloud resources as terraform instead.
+	kops apply -f ./cluster/ --yes --target=terraform --out=./terraform/
+	`))
+
+	applyShort = i18n.T(`Apply manifests to the state store and update the cluster.`)
+)
+
+// ApplyOptions holds the options for kops apply
+type ApplyOptions struct {
+	// Filenames is a list of files and directories containing manifests
+	Filenames []string
+	// Prune deletes the instance groups of the cluster which are not in the manifests
+	Prune bool
+	// Update holds the options for updating the cluster once the manifests are applied
+	Update UpdateClusterOptions
+}
+
+func (o *ApplyOptions) InitDefaults() {
+	o.Update.InitDefaults()
+}
+
+func NewCmdApply(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ApplyOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:     "apply -f FILENAME",
+		Short:   applyShort,
+		Long:    applyLong,
+		Example: applyExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			err := RunApply(ctx, f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&options.Filenames, "filename", "f", options.Filenames, "Files or directories of manifests to apply, or - for stdin")
+	cmd.MarkFlagRequired("filename")
+	cmd.Flags().BoolVar(&options.Prune, "prune", options.Prune, "Delete the instance groups of the cluster which are not in the manifests")
+	cmd.Flags().BoolVarP(&options.Update.Yes, "yes", "y", options.Update.Yes, "Apply the manifests and update the cloud resources, without --yes apply only shows the changes to the state store")
+	cmd.Flags().StringVar(&options.Update.Target, "target", options.Update.Target, "Target - direct, terraform, cloudformation")
+	cmd.Flags().StringVar(&options.Update.OutDir, "out", options.Update.OutDir, "Path to write any local output")
+	cmd.Flags().BoolVar(&options.Update.CreateKubecfg, "create-kube-config", options.Update.CreateKubecfg, "Will control automatically creating the kube config file on your local filesystem")
+
+	return cmd
+}
+
+// manifests holds the objects read from the manifests passed to kops apply
+type manifests struct {
+	Cluster        *kopsapi.Cluster
+	InstanceGroups []*kopsapi.InstanceGroup
+	SSHCredentials []*kopsapi.SSHCredential
+}
+
+func RunApply(ctx context.Context, f *util.Factory, out io.Writer, options *ApplyOptions) error {
+	m, err := readManifests(options.Filenames)
+	if err != nil {
+		return err
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	if err := applyManifests(ctx, clientset, out, m, options.Prune, !options.Update.Yes); err != nil {
+		return err
+	}
+
+	if !options.Update.Yes {
+		fmt.Fprintf(out, "\nMust specify --yes to apply changes\n")
+		return nil
+	}
+
+	_, err = RunUpdateCluster(ctx, f, m.Cluster.ObjectMeta.Name, out, &options.Update)
+	return err
+}
+
+// readManifests reads the manifests in the files and directories, in the order given.
+// The manifests in a directory are read in the order of their file names.
+func readManifests(filenames []string) (*manifests, error) {
+	var paths []string
+	for _, filename := range filenames {
+		if filename == "-" {
+			paths = append(paths, filename)
+			continue
+		}
+		stat, err := os.Stat(filename)
+		if err != nil || !stat.IsDir() {
+			paths = append(paths, filename)
+			continue
+		}
+		entries, err := os.ReadDir(filename)
+		if err != nil {
+			return nil, fmt.Errorf("error reading directory %q: %v", filename, err)
+		}
+		for _, entry := range entries {
+			switch filepath.Ext(entry.Name()) {
+			case ".yaml", ".yml", ".json":
+				if !entry.IsDir() {
+					paths = append(paths, filepath.Join(filename, entry.Name()))
+				}
+			}
+		}
+	}
+
+	m := &manifests{}
+	for _, p := range paths {
+		var contents []byte
+		var err error
+		if p == "-" {
+			contents, err = ConsumeStdin()
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			contents, err = vfs.Context.ReadFile(p)
+			if err != nil {
+				return nil, fmt.Errorf("error reading file %q: %v", p, err)
+			}
+		}
+
+		for _, section := range text.SplitContentToSections(contents) {
+			o, gvk, err := kopscodecs.Decode(section, nil)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing file %q: %v", p, err)
+			}
+
+			switch v := o.(type) {
+			case *kopsapi.Cluster:
+				if m.Cluster != nil {
+					return nil, fmt.Errorf("found Clusters %q and %q, the manifests must describe exactly one cluster", m.Cluster.ObjectMeta.Name, v.ObjectMeta.Name)
+				}
+				m.Cluster = v
+			case *kopsapi.InstanceGroup:
+				m.InstanceGroups = append(m.InstanceGroups, v)
+			case *kopsapi.SSHCredential:
+				if v.Spec.PublicKey == "" {
+					return nil, fmt.Errorf("spec.PublicKey is required")
+				}
+				m.SSHCredentials = append(m.SSHCredentials, v)
+			default:
+				klog.V(2).Infof("Type of object was %T", v)
+				return nil, fmt.Errorf("unhandled kind %q in %q", gvk, p)
+			}
+		}
+	}
+
+	if m.Cluster == nil {
+		return nil, fmt.Errorf("no Cluster found, the manifests must describe exactly one cluster")
+	}
+	clusterName := m.Cluster.ObjectMeta.Name
+
+	names := sets.NewString()
+	for _, ig := range m.InstanceGroups {
+		if names.Has(ig.ObjectMeta.Name) {
+			return nil, fmt.Errorf("found InstanceGroup %q more than once", ig.ObjectMeta.Name)
+		}
+		names.Insert(ig.ObjectMeta.Name)
+		if err := setManifestClusterLabel(&ig.ObjectMeta, "InstanceGroup", clusterName); err != nil {
+			return nil, err
+		}
+	}
+	for _, sshCredential := range m.SSHCredentials {
+		if err := setManifestClusterLabel(&sshCredential.ObjectMeta, "SSHCredential", clusterName); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// setManifestClusterLabel labels an object with the cluster of the manifests, if it was not labelled with a cluster.
+func setManifestClusterLabel(obj *metav1.ObjectMeta, kind string, clusterName string) error {
+	label := obj.Labels[kopsapi.LabelClusterName]
+	if label == "" {
+		if obj.Labels == nil {
+			obj.Labels = make(map[string]string)
+		}
+		obj.Labels[kopsapi.LabelClusterName] = clusterName
+		return nil
+	}
+	if label != clusterName {
+		return fmt.Errorf("%s %q is labelled with cluster %q, but the manifests describe cluster %q", kind, obj.Name, label, clusterName)
+	}
+	return nil
+}
+
+// applyManifests reconciles the state store with the manifests.  With dryRun, it only reports the changes.
+func applyManifests(ctx context.Context, clientset simple.Clientset, out io.Writer, m *manifests, prune bool, dryRun bool) error {
+	verb := func(action string) string {
+		if dryRun {
+			return "Will " + strings.ToLower(action)
+		}
+		return action + "d"
+	}
+
+	clusterName := m.Cluster.ObjectMeta.Name
+	cluster, err := clientset.GetCluster(ctx, clusterName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("error fetching cluster %q: %v", clusterName, err)
+		}
+		cluster = nil
+	}
+
+	cloud, err := cloudup.BuildCloud(m.Cluster)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]*kopsapi.InstanceGroup)
+	if cluster != nil {
+		list, err := clientset.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("error listing instance groups: %v", err)
+		}
+		for i := range list.Items {
+			ig := &list.Items[i]
+			existing[ig.ObjectMeta.Name] = ig
+		}
+	}
+
+	var toPrune []*kopsapi.InstanceGroup
+	if prune {
+		keep := sets.NewString()
+		for _, ig := range m.InstanceGroups {
+			keep.Insert(ig.ObjectMeta.Name)
+		}
+		var groups []*kopsapi.InstanceGroup
+		for _, ig := range existing {
+			groups = append(groups, ig)
+		}
+		toPrune, err = instanceGroupsToPrune(groups, keep)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cluster == nil {
+		if !dryRun {
+			if err := cloudup.PerformAssignments(m.Cluster, cloud); err != nil {
+				return fmt.Errorf("error populating configuration: %v", err)
+			}
+			if _, err := clientset.CreateCluster(ctx, m.Cluster); err != nil {
+				return fmt.Errorf("error creating cluster: %v", err)
+			}
+		}
+		fmt.Fprintf(out, "%s cluster/%s\n", verb("Create"), clusterName)
+	} else {
+		if !dryRun {
+			// Retrieve the current status of the cluster.  This will eventually be part of the cluster object.
+			status, err := cloud.FindClusterStatus(m.Cluster)
+			if err != nil {
+				return err
+			}
+			if err := cloudup.PerformAssignments(m.Cluster, cloud); err != nil {
+				return fmt.Errorf("error populating configuration: %v", err)
+			}
+			if _, err := clientset.UpdateCluster(ctx, m.Cluster, status); err != nil {
+				return fmt.Errorf("error replacing cluster: %v", err)
+			}
+		}
+		fmt.Fprintf(out, "%s cluster/%s\n", verb("Update"), clusterName)
+	}
+
+	for _, ig := range m.InstanceGroups {
+		name := ig.ObjectMeta.Name
+		if existing[name] == nil {
+			if !dryRun {
+				if _, err := clientset.InstanceGroupsFor(m.Cluster).Create(ctx, ig, metav1.CreateOptions{}); err != nil {
+					return fmt.Errorf("error creating instanceGroup %q: %v", name, err)
+				}
+			}
+			fmt.Fprintf(out, "%s instancegroup/%s\n", verb("Create"), name)
+		} else {
+			if !dryRun {
+				if _, err := clientset.InstanceGroupsFor(m.Cluster).Update(ctx, ig, metav1.UpdateOptions{}); err != nil {
+					return fmt.Errorf("error replacing instanceGroup %q: %v", name, err)
+				}
+			}
+			fmt.Fprintf(out, "%s instancegroup/%s\n", verb("Update"), name)
+		}
+	}
+
+	if len(m.SSHCredentials) != 0 {
+		if !dryRun {
+			sshCredentialStore, err := clientset.SSHCredentialStore(m.Cluster)
+			if err != nil {
+				return err
+			}
+			for _, sshCredential := range m.SSHCredentials {
+				if err := sshCredentialStore.AddSSHPublicKey("admin", []byte(sshCredential.Spec.PublicKey)); err != nil {
+					return fmt.Errorf("error adding SSHCredential: %v", err)
+				}
+			}
+		}
+		fmt.Fprintf(out, "%s ssh credential\n", verb("Update"))
+	}
+
+	return deleteInstanceGroups(out, clientset, m.Cluster, cloud, toPrune, dryRun)
+}
+
+// instanceGroupsToPrune returns the instance groups which are not in keep, sorted by name.
+// It refuses to prune control plane instance groups, which must be deleted with kops delete instancegroup.
+func instanceGroupsToPrune(groups []*kopsapi.InstanceGroup, keep sets.String) ([]*kopsapi.InstanceGroup, error) {
+	var prune []*kopsapi.InstanceGroup
+	for _, ig := range groups {
+		if keep.Has(ig.ObjectMeta.Name) {
+			continue
+		}
+		if ig.IsMaster() || ig.Spec.Role == kopsapi.InstanceGroupRoleAPIServer {
+			return nil, fmt.Errorf("not pruning control plane InstanceGroup %q, delete it with kops delete instancegroup", ig.ObjectMeta.Name)
+		}
+		prune = append(prune, ig)
+	}
+	sort.Slice(prune, func(i, j int) bool {
+		return prune[i].ObjectMeta.Name < prune[j].ObjectMeta.Name
+	})
+	return prune, nil
+}
+
+// deleteInstanceGroups deletes the instance groups along with their cloud resources.  With dryRun, it only reports them.
+func deleteInstanceGroups(out io.Writer, clientset simple.Clientset, cluster *kopsapi.Cluster, cloud fi.Cloud, groups []*kopsapi.InstanceGroup, dryRun bool) error {
+	for _, ig := range groups {
+		if dryRun {
+			fmt.Fprintf(out, "Will delete instancegroup/%s\n", ig.ObjectMeta.Name)
+			continue
+		}
+		d := &instancegroups.DeleteInstanceGroup{
+			Cluster:   cluster,
+			Cloud:     cloud,
+			Clientset: clientset,
+		}
+		if err := d.DeleteInstanceGroup(ig); err != nil {
+			return fmt.Errorf("error deleting instanceGroup %q: %v", ig.ObjectMeta.Name, err)
+		}
+		fmt.Fprintf(out, "Deleted instancegroup/%s\n", ig.ObjectMeta.Name)
+	}
+	return nil
+}
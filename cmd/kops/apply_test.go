@@ -0,0 +1,209 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/util/pkg/text"
+)
+
+func TestApplyManifests(t *testing.T) {
+	ctx := context.Background()
+
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+	h.SetupMockAWS()
+
+	factory := util.NewFactory(&util.FactoryOptions{RegistryPath: "memfs://tests"})
+	clientset, err := factory.Clientset()
+	if err != nil {
+		t.Fatalf("error getting clientset: %v", err)
+	}
+
+	contents, err := ioutil.ReadFile("../../tests/integration/create_cluster/minimal-1.21/expected-v1alpha2.yaml")
+	if err != nil {
+		t.Fatalf("error reading manifests: %v", err)
+	}
+	sections := text.SplitContentToSections(contents)
+	if len(sections) != 3 {
+		t.Fatalf("expected a cluster and two instance groups, found %d manifests", len(sections))
+	}
+	cluster, master, nodes := string(sections[0]), string(sections[1]), string(sections[2])
+	otherNodes := strings.ReplaceAll(nodes, "nodes-us-test-1a", "other-nodes")
+
+	writeManifests := func(files map[string]string) string {
+		dir, err := ioutil.TempDir(h.TempDir, "manifests")
+		if err != nil {
+			t.Fatalf("error creating directory: %v", err)
+		}
+		for name, contents := range files {
+			if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+				t.Fatalf("error writing manifest: %v", err)
+			}
+		}
+		return dir
+	}
+
+	apply := func(dir string, prune bool, dryRun bool, expected string) {
+		t.Helper()
+		m, err := readManifests([]string{dir})
+		if err != nil {
+			t.Fatalf("error reading manifests: %v", err)
+		}
+		var out bytes.Buffer
+		if err := applyManifests(ctx, clientset, &out, m, prune, dryRun); err != nil {
+			t.Fatalf("error applying manifests: %v", err)
+		}
+		if out.String() != expected {
+			t.Errorf("unexpected output:\n%s\nexpected:\n%s", out.String(), expected)
+		}
+	}
+
+	instanceGroupNames := func() []string {
+		t.Helper()
+		c, err := clientset.GetCluster(ctx, "minimal.example.com")
+		if err != nil {
+			t.Fatalf("error getting cluster: %v", err)
+		}
+		list, err := clientset.InstanceGroupsFor(c).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			t.Fatalf("error listing instance groups: %v", err)
+		}
+		var names []string
+		for _, ig := range list.Items {
+			names = append(names, ig.ObjectMeta.Name)
+		}
+		return names
+	}
+
+	initial := writeManifests(map[string]string{
+		"cluster.yaml": cluster,
+		"master.yaml":  master,
+		"nodes.yml":    nodes,
+		"README.md":    "not a manifest",
+	})
+
+	apply(initial, true, true, strings.Join([]string{
+		"Will create cluster/minimal.example.com",
+		"Will create instancegroup/master-us-test-1a",
+		"Will create instancegroup/nodes-us-test-1a",
+		"",
+	}, "\n"))
+	if _, err := clientset.GetCluster(ctx, "minimal.example.com"); err == nil {
+		t.Fatalf("expected the dry run not to create the cluster")
+	}
+
+	apply(initial, true, false, strings.Join([]string{
+		"Created cluster/minimal.example.com",
+		"Created instancegroup/master-us-test-1a",
+		"Created instancegroup/nodes-us-test-1a",
+		"",
+	}, "\n"))
+	if names := strings.Join(instanceGroupNames(), ","); names != "master-us-test-1a,nodes-us-test-1a" {
+		t.Errorf("unexpected instance groups %s", names)
+	}
+
+	replaced := writeManifests(map[string]string{
+		"cluster.yaml": cluster + "\n---\n" + master,
+		"other.yaml":   otherNodes,
+	})
+
+	apply(replaced, false, false, strings.Join([]string{
+		"Updated cluster/minimal.example.com",
+		"Updated instancegroup/master-us-test-1a",
+		"Created instancegroup/other-nodes",
+		"",
+	}, "\n"))
+	if names := strings.Join(instanceGroupNames(), ","); names != "master-us-test-1a,nodes-us-test-1a,other-nodes" {
+		t.Errorf("unexpected instance groups %s", names)
+	}
+
+	apply(replaced, true, false, strings.Join([]string{
+		"Updated cluster/minimal.example.com",
+		"Updated instancegroup/master-us-test-1a",
+		"Updated instancegroup/other-nodes",
+		"Deleted instancegroup/nodes-us-test-1a",
+		"",
+	}, "\n"))
+	if names := strings.Join(instanceGroupNames(), ","); names != "master-us-test-1a,other-nodes" {
+		t.Errorf("unexpected instance groups %s", names)
+	}
+
+	m, err := readManifests([]string{writeManifests(map[string]string{"cluster.yaml": cluster, "other.yaml": otherNodes})})
+	if err != nil {
+		t.Fatalf("error reading manifests: %v", err)
+	}
+	var out bytes.Buffer
+	err = applyManifests(ctx, clientset, &out, m, true, false)
+	if err == nil || !strings.Contains(err.Error(), `not pruning control plane InstanceGroup "master-us-test-1a"`) {
+		t.Errorf("expected the control plane instance group not to be pruned, got %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected nothing to be applied, got:\n%s", out.String())
+	}
+}
+
+func TestReadManifestsClusterLabel(t *testing.T) {
+	dir, err := ioutil.TempDir("", "manifests")
+	if err != nil {
+		t.Fatalf("error creating directory: %v", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	manifests := `apiVersion: kops.k8s.io/v1alpha2
+kind: Cluster
+metadata:
+  name: minimal.example.com
+---
+apiVersion: kops.k8s.io/v1alpha2
+kind: InstanceGroup
+metadata:
+  name: nodes
+spec:
+  role: Node
+`
+	if err := ioutil.WriteFile(filepath.Join(dir, "cluster.yaml"), []byte(manifests), 0644); err != nil {
+		t.Fatalf("error writing manifest: %v", err)
+	}
+	m, err := readManifests([]string{dir})
+	if err != nil {
+		t.Fatalf("error reading manifests: %v", err)
+	}
+	if label := m.InstanceGroups[0].ObjectMeta.Labels["kops.k8s.io/cluster"]; label != "minimal.example.com" {
+		t.Errorf("expected the instance group to be labelled with the cluster, got %q", label)
+	}
+
+	other := strings.Replace(manifests, "  name: nodes\n", "  name: nodes\n  labels:\n    kops.k8s.io/cluster: other.example.com\n", 1)
+	if err := ioutil.WriteFile(filepath.Join(dir, "cluster.yaml"), []byte(other), 0644); err != nil {
+		t.Fatalf("error writing manifest: %v", err)
+	}
+	if _, err := readManifests([]string{dir}); err == nil {
+		t.Errorf("expected an error for an instance group of another cluster")
+	}
+}
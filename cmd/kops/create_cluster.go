@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -35,6 +36,7 @@ import (
 	"k8s.io/kops/pkg/apis/kops/registry"
 	"k8s.io/kops/pkg/apis/kops/validation"
 	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/pkg/clusteraddons"
 	"k8s.io/kops/pkg/commands"
 	"k8s.io/kops/pkg/featureflag"
@@ -45,6 +47,14 @@ import (
 	"k8s.io/kops/upup/pkg/fi/utils"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// OutputTaskGraph prints the task graph that would be applied, as YAML
+	OutputTaskGraph = "taskgraph"
+	// OutputTaskGraphJSON prints the task graph that would be applied, as JSON
+	OutputTaskGraphJSON = "taskgraph-json"
 )
 
 type CreateClusterOptions struct {
@@ -206,12 +216,10 @@ func NewCmdCreateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	}
 
 	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Specify --yes to immediately create the cluster")
-	cmd.Flags().StringVar(&options.Target, "target", options.Target, fmt.Sprintf("Valid targets: %s, %s, %s. Set this flag to %s if you want kOps to generate terraform", cloudup.TargetDirect, cloudup.TargetTerraform, cloudup.TargetCloudformation, cloudup.TargetTerraform))
+	cmd.Flags().StringVar(&options.Target, "target", options.Target, fmt.Sprintf("Valid targets: %s, %s, %s, %s. Set this flag to %s if you want kOps to generate terraform", cloudup.TargetDirect, cloudup.TargetTerraform, cloudup.TargetCloudformation, cloudup.TargetCrossplane, cloudup.TargetTerraform))
 
 	// Configuration / state location
-	if featureflag.EnableSeparateConfigBase.Enabled() {
-		cmd.Flags().StringVar(&options.ConfigBase, "config-base", options.ConfigBase, "A cluster-readable location where we mirror configuration information, separate from the state store.  Allows for a state store that is not accessible from the cluster.")
-	}
+	cmd.Flags().StringVar(&options.ConfigBase, "config-base", options.ConfigBase, "A cluster-readable location where we mirror configuration information, separate from the state store.  Allows for a state store that is not accessible from the cluster, and is required with a kubernetes (k8s://) state store.")
 
 	cmd.Flags().StringVar(&options.CloudProvider, "cloud", options.CloudProvider, "Cloud provider to use - gce, aws, openstack")
 
@@ -250,7 +258,7 @@ func NewCmdCreateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().BoolVar(&encryptEtcdStorage, "encrypt-etcd-storage", false, "Generate key in aws kms and use it for encrypt etcd volumes")
 	cmd.Flags().StringVar(&options.EtcdStorageType, "etcd-storage-type", options.EtcdStorageType, "The default storage type for etc members")
 
-	cmd.Flags().StringVar(&options.Networking, "networking", options.Networking, "Networking mode to use.  kubenet, external, weave, flannel-vxlan (or flannel), flannel-udp, calico, canal, kube-router, amazonvpc, cilium, cilium-etcd, cni, lyftvpc.")
+	cmd.Flags().StringVar(&options.Networking, "networking", options.Networking, "Networking mode to use.  kubenet, external, weave, flannel-vxlan (or flannel), flannel-udp, calico, canal, kube-router, amazonvpc, cilium, cilium-etcd, antrea, cni, lyftvpc.")
 
 	cmd.Flags().StringVar(&options.DNSZone, "dns-zone", options.DNSZone, "DNS hosted zone to use (defaults to longest matching zone)")
 	cmd.Flags().StringVar(&options.OutDir, "out", options.OutDir, "Path to write any local output")
@@ -293,7 +301,7 @@ func NewCmdCreateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 
 	// DryRun mode that will print YAML or JSON
 	cmd.Flags().BoolVar(&options.DryRun, "dry-run", options.DryRun, "If true, only print the object that would be sent, without sending it. This flag can be used to create a cluster YAML or JSON manifest.")
-	cmd.Flags().StringVarP(&options.Output, "output", "o", options.Output, "Output format. One of json|yaml. Used with the --dry-run flag.")
+	cmd.Flags().StringVarP(&options.Output, "output", "o", options.Output, "Output format. One of json|yaml|taskgraph|taskgraph-json. Used with the --dry-run flag; taskgraph prints the tasks kops would run instead of the cluster manifest.")
 
 	if featureflag.SpecOverrideFlag.Enabled() {
 		cmd.Flags().StringSliceVar(&options.Overrides, "override", options.Overrides, "Directly configure values in the spec")
@@ -358,6 +366,8 @@ func RunCreateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Cr
 			c.OutDir = "out/terraform"
 		} else if c.Target == cloudup.TargetCloudformation {
 			c.OutDir = "out/cloudformation"
+		} else if c.Target == cloudup.TargetCrossplane {
+			c.OutDir = "out/crossplane"
 		} else {
 			c.OutDir = "out"
 		}
@@ -550,6 +560,29 @@ func RunCreateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Cr
 		return err
 	}
 
+	if len(c.SSHPublicKeys) == 0 {
+		autoloadSSHPublicKeys := true
+		switch c.CloudProvider {
+		case "gce":
+			// We don't normally use SSH keys on GCE
+			autoloadSSHPublicKeys = false
+		}
+
+		if autoloadSSHPublicKeys {
+			// Load from default location, if found
+			sshPublicKeyPath := "~/.ssh/id_rsa.pub"
+			c.SSHPublicKeys, err = loadSSHPublicKeys(sshPublicKeyPath)
+			if err != nil {
+				// Don't wrap file-not-found
+				if os.IsNotExist(err) {
+					klog.V(2).Infof("ssh key not found at %s", sshPublicKeyPath)
+				} else {
+					return fmt.Errorf("error reading SSH key file %q: %v", sshPublicKeyPath, err)
+				}
+			}
+		}
+	}
+
 	if c.DryRun {
 		var obj []runtime.Object
 		obj = append(obj, cluster)
@@ -571,6 +604,8 @@ func RunCreateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Cr
 				return fmt.Errorf("error writing cluster json to stdout: %v", err)
 			}
 			return nil
+		case OutputTaskGraph, OutputTaskGraphJSON:
+			return outputTaskGraph(ctx, out, c, clientset, cloud, cluster, fullInstanceGroups)
 		default:
 			return fmt.Errorf("unsupported output type %q", c.Output)
 		}
@@ -600,29 +635,6 @@ func RunCreateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Cr
 		return fmt.Errorf("error writing completed cluster spec: %v", err)
 	}
 
-	if len(c.SSHPublicKeys) == 0 {
-		autoloadSSHPublicKeys := true
-		switch c.CloudProvider {
-		case "gce":
-			// We don't normally use SSH keys on GCE
-			autoloadSSHPublicKeys = false
-		}
-
-		if autoloadSSHPublicKeys {
-			// Load from default location, if found
-			sshPublicKeyPath := "~/.ssh/id_rsa.pub"
-			c.SSHPublicKeys, err = loadSSHPublicKeys(sshPublicKeyPath)
-			if err != nil {
-				// Don't wrap file-not-found
-				if os.IsNotExist(err) {
-					klog.V(2).Infof("ssh key not found at %s", sshPublicKeyPath)
-				} else {
-					return fmt.Errorf("error reading SSH key file %q: %v", sshPublicKeyPath, err)
-				}
-			}
-		}
-	}
-
 	if len(c.SSHPublicKeys) != 0 {
 		sshCredentialStore, err := clientset.SSHCredentialStore(cluster)
 		if err != nil {
@@ -689,6 +701,48 @@ func RunCreateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Cr
 	return nil
 }
 
+// outputTaskGraph builds the tasks for a cluster that has not been written to the state store yet,
+// and prints them without looking at or changing any cloud resources.
+func outputTaskGraph(ctx context.Context, out io.Writer, c *CreateClusterOptions, clientset simple.Clientset, cloud fi.Cloud, cluster *api.Cluster, instanceGroups []*api.InstanceGroup) error {
+	// The keys have not been written to the state store yet
+	sshPublicKeys := [][]byte{}
+	for _, data := range c.SSHPublicKeys {
+		sshPublicKeys = append(sshPublicKeys, data)
+	}
+
+	applyCmd := &cloudup.ApplyClusterCmd{
+		Cloud:          cloud,
+		Clientset:      clientset,
+		Cluster:        cluster,
+		InstanceGroups: instanceGroups,
+		TargetName:     cloudup.TargetDryRun,
+		DryRun:         true,
+		BuildTasksOnly: true,
+		SSHPublicKeys:  sshPublicKeys,
+	}
+	if err := applyCmd.Run(ctx); err != nil {
+		return err
+	}
+
+	graph, err := fi.BuildTaskGraph(applyCmd.TaskMap)
+	if err != nil {
+		return err
+	}
+
+	var b []byte
+	if c.Output == OutputTaskGraphJSON {
+		b, err = json.MarshalIndent(graph, "", "  ")
+		b = append(b, '\n')
+	} else {
+		b, err = yaml.Marshal(graph)
+	}
+	if err != nil {
+		return fmt.Errorf("error serializing task graph: %v", err)
+	}
+	_, err = out.Write(b)
+	return err
+}
+
 // parseCloudLabels takes a CSV list of key=value records and parses them into a map. Nested '='s are supported via
 // quoted strings (eg `foo="bar=baz"` parses to map[string]string{"foo":"bar=baz"}. Nested commas are not supported.
 func parseCloudLabels(s string) (map[string]string, error) {
@@ -131,6 +131,14 @@ func RunCreateInstanceGroup(ctx context.Context, f *util.Factory, cmd *cobra.Com
 		return err
 	}
 
+	if !options.DryRun {
+		unlock, err := lockCluster(ctx, clientset, cluster, "create instancegroup")
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
 	channel, err := cloudup.ChannelForCluster(cluster)
 	if err != nil {
 		klog.Warningf("%v", err)
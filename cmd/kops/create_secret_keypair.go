@@ -49,6 +49,7 @@ func NewCmdCreateKeypairSecret(f *util.Factory, out io.Writer) *cobra.Command {
 
 	// create subcommands
 	cmd.AddCommand(NewCmdCreateSecretCaCert(f, out))
+	cmd.AddCommand(NewCmdCreateSecretEtcdExternal(f, out))
 
 	return cmd
 }
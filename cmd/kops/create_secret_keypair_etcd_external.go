@@ -0,0 +1,162 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi/utils"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	createSecretEtcdExternalLong = templates.LongDesc(i18n.T(`
+	Add the certificates the API server uses to connect to an external etcd cluster:
+	the CA certificate of the etcd servers, and a client certificate and private key.
+    `))
+
+	createSecretEtcdExternalExample = templates.Examples(i18n.T(`
+	Add the certificates for an external etcd cluster.
+	kops create secret keypair etcd-external \
+		--ca ~/etcd-ca.pem --cert ~/etcd-client.pem --key ~/etcd-client-key.pem \
+		--name k8s-cluster.example.com --state s3://my-state-store
+	`))
+
+	createSecretEtcdExternalShort = i18n.T(`Add the certificates for an external etcd cluster`)
+)
+
+type CreateSecretEtcdExternalOptions struct {
+	ClusterName          string
+	CaCertPath           string
+	ClientCertPath       string
+	ClientPrivateKeyPath string
+}
+
+// NewCmdCreateSecretEtcdExternal returns the create external etcd certificates command
+func NewCmdCreateSecretEtcdExternal(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &CreateSecretEtcdExternalOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "etcd-external",
+		Short:   createSecretEtcdExternalShort,
+		Long:    createSecretEtcdExternalLong,
+		Example: createSecretEtcdExternalExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			err := rootCommand.ProcessArgs(args)
+			if err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName()
+
+			err = RunCreateSecretEtcdExternal(ctx, f, os.Stdout, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.CaCertPath, "ca", options.CaCertPath, "Path to the CA cert of the etcd servers")
+	cmd.Flags().StringVar(&options.ClientCertPath, "cert", options.ClientCertPath, "Path to the etcd client cert")
+	cmd.Flags().StringVar(&options.ClientPrivateKeyPath, "key", options.ClientPrivateKeyPath, "Path to the etcd client cert private key")
+
+	return cmd
+}
+
+// RunCreateSecretEtcdExternal adds the CA certificate and client keypair of an external etcd cluster
+func RunCreateSecretEtcdExternal(ctx context.Context, f *util.Factory, out io.Writer, options *CreateSecretEtcdExternalOptions) error {
+	if options.CaCertPath == "" {
+		return fmt.Errorf("error no ca cert provided")
+	}
+	if options.ClientCertPath == "" {
+		return fmt.Errorf("error no client cert provided")
+	}
+	if options.ClientPrivateKeyPath == "" {
+		return fmt.Errorf("error no client private key provided")
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return fmt.Errorf("error getting cluster: %q: %v", options.ClusterName, err)
+	}
+
+	clientSet, err := f.Clientset()
+	if err != nil {
+		return fmt.Errorf("error getting clientset: %v", err)
+	}
+
+	keyStore, err := clientSet.KeyStore(cluster)
+	if err != nil {
+		return fmt.Errorf("error getting keystore: %v", err)
+	}
+
+	options.CaCertPath = utils.ExpandPath(options.CaCertPath)
+	options.ClientCertPath = utils.ExpandPath(options.ClientCertPath)
+	options.ClientPrivateKeyPath = utils.ExpandPath(options.ClientPrivateKeyPath)
+
+	caBytes, err := ioutil.ReadFile(options.CaCertPath)
+	if err != nil {
+		return fmt.Errorf("error reading user provided ca cert %q: %v", options.CaCertPath, err)
+	}
+	certBytes, err := ioutil.ReadFile(options.ClientCertPath)
+	if err != nil {
+		return fmt.Errorf("error reading user provided cert %q: %v", options.ClientCertPath, err)
+	}
+	privateKeyBytes, err := ioutil.ReadFile(options.ClientPrivateKeyPath)
+	if err != nil {
+		return fmt.Errorf("error reading user provided private key %q: %v", options.ClientPrivateKeyPath, err)
+	}
+
+	caCert, err := pki.ParsePEMCertificate(caBytes)
+	if err != nil {
+		return fmt.Errorf("error loading ca certificate %q: %v", options.CaCertPath, err)
+	}
+	cert, err := pki.ParsePEMCertificate(certBytes)
+	if err != nil {
+		return fmt.Errorf("error loading certificate %q: %v", options.ClientCertPath, err)
+	}
+	privateKey, err := pki.ParsePEMPrivateKey(privateKeyBytes)
+	if err != nil {
+		return fmt.Errorf("error loading private key %q: %v", options.ClientPrivateKeyPath, err)
+	}
+
+	// We only need the certificate of the CA; its private key stays with whoever runs the etcd cluster
+	if err := keyStore.AddCert("etcd-external-ca", caCert); err != nil {
+		return fmt.Errorf("error storing user provided ca cert %q: %v", options.CaCertPath, err)
+	}
+	if err := keyStore.StoreKeypair("etcd-external-client", cert, privateKey); err != nil {
+		return fmt.Errorf("error storing user provided keys %q %q: %v", options.ClientCertPath, options.ClientPrivateKeyPath, err)
+	}
+
+	klog.Infof("using user provided ca cert: %v\n", options.CaCertPath)
+	klog.Infof("using user provided cert: %v\n", options.ClientCertPath)
+	klog.Infof("using user provided private key: %v\n", options.ClientPrivateKeyPath)
+
+	return nil
+}
@@ -26,6 +26,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/cmd/kops/util"
 	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudapi"
 	"k8s.io/kops/pkg/kubeconfig"
 	"k8s.io/kops/pkg/resources"
 	resourceops "k8s.io/kops/pkg/resources/ops"
@@ -43,12 +44,26 @@ type DeleteClusterOptions struct {
 	External    bool
 	Unregister  bool
 	ClusterName string
+
+	// DeleteExternalResources allows deleting the cloud resources created by controllers running in the cluster,
+	// such as the load balancers of Services and the volumes of PersistentVolumeClaims; without it, the cluster
+	// is not deleted while they exist
+	DeleteExternalResources bool
+
+	// APIRateLimits limits the requests per second to each cloud provider API, keyed by API name
+	APIRateLimits map[string]int
 }
 
 var (
 	deleteClusterLong = templates.LongDesc(i18n.T(`
 	Deletes a Kubernetes cluster and all associated resources.  Resources include instancegroups,
 	secrets and the state store.  There is no "UNDO" for this command.
+
+	On AWS, the resources which controllers running in the cluster created, such as the load balancers
+	of Services, the volumes of PersistentVolumeClaims and the network interfaces of the Amazon VPC CNI,
+	are listed separately along with what they were created for.  Nothing is deleted while they exist,
+	unless --delete-external-resources is specified, in which case they are deleted along with the cluster.
+	Deleting the volume of a PersistentVolumeClaim deletes its data.
 	`))
 
 	deleteClusterExample = templates.Examples(i18n.T(`
@@ -56,6 +71,10 @@ var (
 	# The --yes option runs the command immediately.
 	kops delete cluster --name=k8s.cluster.site --yes
 
+	# Delete a cluster along with the load balancers, volumes and network interfaces
+	# that controllers running in it created.
+	kops delete cluster --name=k8s.cluster.site --yes --delete-external-resources
+
 	`))
 
 	deleteClusterShort = i18n.T("Delete a cluster.")
@@ -90,8 +109,10 @@ func NewCmdDeleteCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Specify --yes to delete the cluster")
 	cmd.Flags().BoolVar(&options.Unregister, "unregister", options.Unregister, "Don't delete cloud resources, just unregister the cluster")
 	cmd.Flags().BoolVar(&options.External, "external", options.External, "Delete an external cluster")
+	cmd.Flags().BoolVar(&options.DeleteExternalResources, "delete-external-resources", options.DeleteExternalResources, "Also delete the cloud resources created by controllers running in the cluster, such as the volumes of PersistentVolumeClaims; without it, the cluster is not deleted while they exist")
 
 	cmd.Flags().StringVar(&options.Region, "region", options.Region, "region")
+	cmd.Flags().StringToIntVar(&options.APIRateLimits, "api-rate-limit", options.APIRateLimits, "Maximum number of requests per second to each cloud provider API, for example ec2=20,iam=5")
 	return cmd
 }
 
@@ -105,6 +126,10 @@ func RunDeleteCluster(ctx context.Context, f *util.Factory, out io.Writer, optio
 	var cluster *kopsapi.Cluster
 	var err error
 
+	if err := cloudapi.SetRateLimits(options.APIRateLimits); err != nil {
+		return err
+	}
+
 	if options.External {
 		region := options.Region
 		if region == "" {
@@ -121,6 +146,18 @@ func RunDeleteCluster(ctx context.Context, f *util.Factory, out io.Writer, optio
 		if err != nil {
 			return err
 		}
+
+		if options.Yes {
+			clientset, err := f.Clientset()
+			if err != nil {
+				return err
+			}
+			unlock, err := lockCluster(ctx, clientset, cluster, "delete cluster")
+			if err != nil {
+				return err
+			}
+			defer unlock()
+		}
 	}
 
 	wouldDeleteCloudResources := false
@@ -162,8 +199,13 @@ func RunDeleteCluster(ctx context.Context, f *util.Factory, out io.Writer, optio
 				return r.Name
 			})
 			var l []*resources.Resource
+			var external []*resources.Resource
 			for _, v := range clusterResources {
-				l = append(l, v)
+				if v.CreatedFor != "" {
+					external = append(external, v)
+				} else {
+					l = append(l, v)
+				}
 			}
 
 			err := t.Render(l, out, "TYPE", "NAME", "ID")
@@ -171,17 +213,35 @@ func RunDeleteCluster(ctx context.Context, f *util.Factory, out io.Writer, optio
 				return err
 			}
 
+			if len(external) != 0 {
+				t.AddColumn("CREATED-FOR", func(r *resources.Resource) string {
+					return r.CreatedFor
+				})
+				fmt.Fprintf(out, "\nResources created by controllers running in the cluster:\n")
+				if err := t.Render(external, out, "TYPE", "NAME", "ID", "CREATED-FOR"); err != nil {
+					return err
+				}
+			}
+
 			if !options.Yes {
 				fmt.Fprintf(out, "\nMust specify --yes to delete cluster\n")
 				return nil
 			}
 
+			if len(external) != 0 && !options.DeleteExternalResources {
+				return fmt.Errorf("not deleting cluster: controllers running in it created the %d cloud resources listed above; delete the Kubernetes objects they were created for, or delete the resources along with the cluster with --delete-external-resources", len(external))
+			}
+
 			fmt.Fprintf(out, "\n")
 
 			err = resourceops.DeleteResources(cloud, clusterResources)
 			if err != nil {
 				return err
 			}
+
+			if err := cloudapi.PrintSummary(out, cloudapi.Stats(), maxReportedAPIOperations); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -158,6 +158,12 @@ func RunDeleteInstanceGroup(ctx context.Context, f *util.Factory, out io.Writer,
 		return nil
 	}
 
+	unlock, err := lockCluster(ctx, clientset, cluster, "delete instancegroup")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	d := &instancegroups.DeleteInstanceGroup{}
 	d.Cluster = cluster
 	d.Cloud = cloud
@@ -0,0 +1,53 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	diffLong = templates.LongDesc(i18n.T(`
+	Show the differences between the cluster desired configuration and the real cloud resources.
+	`))
+
+	diffExample = templates.Examples(i18n.T(`
+		# Show the resources that kops update cluster would change
+		kops diff cluster k8s-cluster.example.com --state=s3://my-state-store
+	`))
+
+	diffShort = i18n.T("Show differences between the desired configuration and the cloud.")
+)
+
+func NewCmdDiff(f *util.Factory, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "diff",
+		Short:   diffShort,
+		Long:    diffLong,
+		Example: diffExample,
+	}
+
+	// subcommands
+	cmd.AddCommand(NewCmdDiffCluster(f, out))
+
+	return cmd
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	diffClusterLong = templates.LongDesc(i18n.T(`
+	Compares the cluster desired configuration in the state store with the real cloud resources,
+	and shows what kops update cluster would create, modify or delete.
+
+	Modified resources were either changed outside of kops, or their configuration
+	was changed in the state store without running kops update cluster.
+
+	The command exits with status 0 if there are no differences, 2 if there are differences,
+	and 1 on error, so it can be used to detect drift in CI.
+	`))
+
+	diffClusterExample = templates.Examples(i18n.T(`
+		# Show the differences for a cluster
+		kops diff cluster k8s-cluster.example.com --state=s3://my-state-store
+
+		# Fail a CI job when the cluster has drifted
+		kops diff cluster k8s-cluster.example.com --color=never || exit 1
+	`))
+
+	diffClusterShort = i18n.T("Show differences between the cluster configuration and the cloud.")
+)
+
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+
+	// diffExitCodeChanges is the exit code used when differences are found
+	diffExitCodeChanges = 2
+)
+
+type DiffClusterOptions struct {
+	// Color is one of auto, always or never
+	Color string
+}
+
+func NewCmdDiffCluster(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &DiffClusterOptions{
+		Color: colorAuto,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "cluster",
+		Short:   diffClusterShort,
+		Long:    diffClusterLong,
+		Example: diffClusterExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			err := rootCommand.ProcessArgs(args)
+			if err != nil {
+				exitWithError(err)
+			}
+
+			hasChanges, err := RunDiffCluster(ctx, f, rootCommand.ClusterName(), out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+			if hasChanges {
+				os.Exit(diffExitCodeChanges)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Color, "color", options.Color, "Colorize the output: auto, always or never")
+
+	return cmd
+}
+
+// RunDiffCluster prints the changes kops update cluster would make, and returns true if there are any
+func RunDiffCluster(ctx context.Context, f *util.Factory, clusterName string, out io.Writer, options *DiffClusterOptions) (bool, error) {
+	var useColor bool
+	switch options.Color {
+	case colorAlways:
+		useColor = true
+	case colorNever:
+		useColor = false
+	case colorAuto:
+		if file, ok := out.(*os.File); ok {
+			useColor = term.IsTerminal(int(file.Fd()))
+		}
+	default:
+		return false, fmt.Errorf("unknown --color value %q, must be one of %s, %s or %s", options.Color, colorAuto, colorAlways, colorNever)
+	}
+
+	cluster, err := GetCluster(ctx, f, clusterName)
+	if err != nil {
+		return false, err
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return false, err
+	}
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return false, err
+	}
+
+	applyCmd := &cloudup.ApplyClusterCmd{
+		Cloud:        cloud,
+		Clientset:    clientset,
+		Cluster:      cluster,
+		DryRun:       true,
+		TargetName:   cloudup.TargetDryRun,
+		DryRunOutput: io.Discard,
+	}
+	if err := applyCmd.Run(ctx); err != nil {
+		return false, err
+	}
+
+	target := applyCmd.Target.(*fi.DryRunTarget)
+	changes, err := target.ResourceChanges(applyCmd.TaskMap)
+	if err != nil {
+		return false, err
+	}
+
+	if err := printResourceChanges(out, changes, useColor); err != nil {
+		return false, err
+	}
+
+	return len(changes) != 0, nil
+}
+
+const (
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiReset  = "\x1b[0m"
+)
+
+func printResourceChanges(out io.Writer, changes []*fi.ResourceChange, useColor bool) error {
+	colorize := func(color string, s string) string {
+		if !useColor {
+			return s
+		}
+		return color + s + ansiReset
+	}
+
+	var b strings.Builder
+	if len(changes) == 0 {
+		b.WriteString("No differences: the cloud resources match the cluster configuration.\n")
+		_, err := io.WriteString(out, b.String())
+		return err
+	}
+
+	counts := make(map[fi.ChangeAction]int)
+	for _, change := range changes {
+		counts[change.Action]++
+
+		switch change.Action {
+		case fi.ChangeActionCreate:
+			fmt.Fprintf(&b, "%s\n", colorize(ansiGreen, fmt.Sprintf("+ %s/%s (missing, will be created)", change.Type, change.Name)))
+		case fi.ChangeActionUpdate:
+			fmt.Fprintf(&b, "%s\n", colorize(ansiYellow, fmt.Sprintf("~ %s/%s (differs from the configuration)", change.Type, change.Name)))
+		case fi.ChangeActionDelete:
+			fmt.Fprintf(&b, "%s\n", colorize(ansiRed, fmt.Sprintf("- %s %s (no longer in the configuration, will be deleted)", change.Type, change.Name)))
+		}
+
+		for _, field := range change.Fields {
+			switch {
+			case field.Diff != "":
+				fmt.Fprintf(&b, "    %s:\n", field.Field)
+				for _, line := range strings.Split(strings.TrimRight(field.Diff, "\n"), "\n") {
+					switch {
+					case strings.HasPrefix(line, "+"):
+						line = colorize(ansiGreen, line)
+					case strings.HasPrefix(line, "-"):
+						line = colorize(ansiRed, line)
+					}
+					fmt.Fprintf(&b, "      %s\n", line)
+				}
+			case change.Action == fi.ChangeActionCreate:
+				fmt.Fprintf(&b, "    %-20s %s\n", field.Field+":", field.Expected)
+			default:
+				fmt.Fprintf(&b, "    %-20s %s -> %s\n", field.Field+":", colorize(ansiRed, field.Actual), colorize(ansiGreen, field.Expected))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "%d to create, %d to modify, %d to delete.\n", counts[fi.ChangeActionCreate], counts[fi.ChangeActionUpdate], counts[fi.ChangeActionDelete])
+
+	_, err := io.WriteString(out, b.String())
+	return err
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestPrintResourceChanges(t *testing.T) {
+	changes := []*fi.ResourceChange{
+		{
+			Action: fi.ChangeActionCreate,
+			Type:   "VPC",
+			Name:   "minimal.example.com",
+			Fields: []fi.FieldChange{{Field: "CIDR", Expected: "172.20.0.0/16"}},
+		},
+		{
+			Action: fi.ChangeActionUpdate,
+			Type:   "SecurityGroup",
+			Name:   "nodes.minimal.example.com",
+			Fields: []fi.FieldChange{
+				{Field: "Description", Actual: "old", Expected: "new"},
+				{Field: "UserData", Diff: "  #!/bin/bash\n- echo old\n+ echo new\n"},
+			},
+		},
+		{
+			Action: fi.ChangeActionDelete,
+			Type:   "SecurityGroupRule",
+			Name:   "sg-12345 ingress 22",
+		},
+	}
+
+	var out bytes.Buffer
+	if err := printResourceChanges(&out, changes, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `+ VPC/minimal.example.com (missing, will be created)
+    CIDR:                172.20.0.0/16
+
+~ SecurityGroup/nodes.minimal.example.com (differs from the configuration)
+    Description:         old -> new
+    UserData:
+        #!/bin/bash
+      - echo old
+      + echo new
+
+- SecurityGroupRule sg-12345 ingress 22 (no longer in the configuration, will be deleted)
+
+1 to create, 1 to modify, 1 to delete.
+`
+	if out.String() != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+
+	out.Reset()
+	if err := printResourceChanges(&out, nil, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "No differences: the cloud resources match the cluster configuration.\n" {
+		t.Errorf("unexpected output for no changes: %q", out.String())
+	}
+}
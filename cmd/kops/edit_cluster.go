@@ -27,6 +27,8 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
 	"k8s.io/kops/cmd/kops/util"
 	api "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/registry"
@@ -53,6 +55,9 @@ var (
     	To set your preferred editor, you can define the EDITOR environment variable.
     	When you have done this, kOps will use the editor that you have set.
 
+	If the edited configuration is not valid, the editor is reopened with each error described in a comment
+	below the offending line.  When the configuration is saved, the changes to the completed cluster spec, including the fields which kOps defaults are shown.
+
 	kops edit does not update the cloud resources, to apply the changes use "kops update cluster".`))
 
 	editClusterExample = templates.Examples(i18n.T(`
@@ -93,12 +98,24 @@ func RunEditCluster(ctx context.Context, f *util.Factory, cmd *cobra.Command, ar
 		return err
 	}
 
-	err = oldCluster.FillDefaults()
+	clientset, err := f.Clientset()
 	if err != nil {
 		return err
 	}
 
-	clientset, err := f.Clientset()
+	unlock, err := lockCluster(ctx, clientset, oldCluster, "edit cluster")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Read the cluster again under the lock, so that the edit doesn't revert a change written in between
+	oldCluster, err = rootCommand.Cluster(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = oldCluster.FillDefaults()
 	if err != nil {
 		return err
 	}
@@ -133,6 +150,8 @@ func RunEditCluster(ctx context.Context, f *util.Factory, cmd *cobra.Command, ar
 
 		if !containsError {
 			buf.Write(raw)
+		} else if results.annotated != nil {
+			buf.Write(results.annotated)
 		} else {
 			buf.Write(stripComments(edited))
 		}
@@ -206,6 +225,12 @@ func RunEditCluster(ctx context.Context, f *util.Factory, cmd *cobra.Command, ar
 			continue
 		}
 
+		if errs := validation.ValidateCluster(newCluster, false); len(errs) != 0 {
+			results = annotatedEditResults(file, edited, errs)
+			containsError = true
+			continue
+		}
+
 		cloud, err := cloudup.BuildCloud(newCluster)
 		if err != nil {
 			return err
@@ -242,6 +267,20 @@ func RunEditCluster(ctx context.Context, f *util.Factory, cmd *cobra.Command, ar
 			return preservedFile(err, file, out)
 		}
 
+		completedBefore := &api.Cluster{}
+		err = registry.ReadConfigDeprecated(configBase.Join(registry.PathClusterCompleted), completedBefore)
+		if err == nil {
+			changes, err := edit.DiffObjects(completedBefore, fullCluster)
+			if err != nil {
+				return preservedFile(err, file, out)
+			}
+			if changes != "" {
+				fmt.Fprintf(out, "The edit changes the completed cluster spec, including the fields which kOps defaults, as follows:\n%s\n", changes)
+			}
+		} else if !os.IsNotExist(err) {
+			klog.Warningf("not previewing the changes to the completed cluster spec: %v", err)
+		}
+
 		// Retrieve the current status of the cluster.  This will eventually be part of the cluster object.
 		status, err := cloud.FindClusterStatus(oldCluster)
 		if err != nil {
@@ -266,6 +305,25 @@ func RunEditCluster(ctx context.Context, f *util.Factory, cmd *cobra.Command, ar
 type editResults struct {
 	header editHeader
 	file   string
+	// annotated is the edited file with the validation errors as comments below the offending lines
+	annotated []byte
+}
+
+// annotatedEditResults reports validation errors inline in the edited file, and in the header
+// those which could not be matched to a line.
+func annotatedEditResults(file string, edited []byte, errs field.ErrorList) editResults {
+	annotated, unmatched := edit.AnnotateErrors(stripComments(edited), errs)
+	results := editResults{
+		file:      file,
+		annotated: annotated,
+	}
+	if len(unmatched) < len(errs) {
+		results.header.addError("validation failed, the errors are described below the offending lines")
+	}
+	for _, err := range unmatched {
+		results.header.addError(err.Error())
+	}
+	return results
 }
 
 type editHeader struct {
@@ -23,6 +23,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -31,6 +32,7 @@ import (
 	api "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/validation"
 	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/pkg/edit"
 	"k8s.io/kops/pkg/kopscodecs"
 	"k8s.io/kops/pkg/try"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
@@ -47,6 +49,9 @@ var (
     	To set your preferred editor, you can define the EDITOR environment variable.
     	When you have done this, kOps will use the editor that you have set.
 
+	If the edited configuration is not valid, the editor is reopened with each error described in a comment
+	below the offending line.  When the configuration is saved, the fields which kOps defaults are shown.
+
 	kops edit does not update the cloud resources, to apply the changes use "kops update cluster".`))
 
 	editInstancegroupExample = templates.Examples(i18n.T(`
@@ -111,6 +116,12 @@ func RunEditInstanceGroup(ctx context.Context, f *util.Factory, cmd *cobra.Comma
 		return fmt.Errorf("name is required")
 	}
 
+	unlock, err := lockCluster(ctx, clientset, cluster, "edit instancegroup")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	oldGroup, err := clientset.InstanceGroupsFor(cluster).Get(ctx, groupName, metav1.GetOptions{})
 	if err != nil {
 		return fmt.Errorf("error reading InstanceGroup %q: %v", groupName, err)
@@ -120,7 +131,7 @@ func RunEditInstanceGroup(ctx context.Context, f *util.Factory, cmd *cobra.Comma
 	}
 
 	var (
-		edit = editor.NewDefaultEditor(editorEnvs)
+		ed = editor.NewDefaultEditor(editorEnvs)
 	)
 
 	ext := "yaml"
@@ -129,40 +140,10 @@ func RunEditInstanceGroup(ctx context.Context, f *util.Factory, cmd *cobra.Comma
 		return err
 	}
 
-	// launch the editor
-	edited, file, err := edit.LaunchTempFile(fmt.Sprintf("%s-edit-", filepath.Base(os.Args[0])), ext, bytes.NewReader(raw))
-	defer func() {
-		if file != "" {
-			try.RemoveFile(file)
-		}
-	}()
-	if err != nil {
-		return fmt.Errorf("error launching editor: %v", err)
-	}
-
-	if bytes.Equal(edited, raw) {
-		fmt.Fprintln(os.Stderr, "Edit cancelled, no changes made.")
-		return nil
-	}
-
-	newObj, _, err := kopscodecs.Decode(edited, nil)
-	if err != nil {
-		return fmt.Errorf("error parsing InstanceGroup: %v", err)
-	}
-
-	newGroup, ok := newObj.(*api.InstanceGroup)
-	if !ok {
-		return fmt.Errorf("object was not of expected type: %T", newObj)
-	}
-
 	cloud, err := cloudup.BuildCloud(cluster)
 	if err != nil {
 		return err
 	}
-	fullGroup, err := cloudup.PopulateInstanceGroupSpec(cluster, newGroup, cloud, channel)
-	if err != nil {
-		return err
-	}
 
 	// We need the full cluster spec to perform deep validation
 	// Note that we don't write it back though
@@ -177,16 +158,117 @@ func RunEditInstanceGroup(ctx context.Context, f *util.Factory, cmd *cobra.Comma
 		return err
 	}
 
-	err = validation.CrossValidateInstanceGroup(fullGroup, fullCluster, cloud).ToAggregate()
-	if err != nil {
-		return err
-	}
+	var (
+		results = editResults{}
+		edited  = []byte{}
+		file    string
+	)
 
-	// Note we perform as much validation as we can, before writing a bad config
-	_, err = clientset.InstanceGroupsFor(cluster).Update(ctx, fullGroup, metav1.UpdateOptions{})
-	if err != nil {
-		return err
-	}
+	containsError := false
+
+	for {
+		buf := &bytes.Buffer{}
+		results.header.writeTo(buf)
+		results.header.flush()
+
+		if !containsError {
+			buf.Write(raw)
+		} else if results.annotated != nil {
+			buf.Write(results.annotated)
+		} else {
+			buf.Write(stripComments(edited))
+		}
+
+		// launch the editor
+		editedDiff := edited
+		edited, file, err = ed.LaunchTempFile(fmt.Sprintf("%s-edit-", filepath.Base(os.Args[0])), ext, buf)
+		if err != nil {
+			return preservedFile(fmt.Errorf("error launching editor: %v", err), results.file, out)
+		}
+
+		if containsError {
+			if bytes.Equal(stripComments(editedDiff), stripComments(edited)) {
+				return preservedFile(fmt.Errorf("%s", "Edit cancelled, no valid changes were saved."), file, out)
+			}
+		}
+
+		if len(results.file) > 0 {
+			try.RemoveFile(results.file)
+		}
+
+		if bytes.Equal(stripComments(raw), stripComments(edited)) {
+			try.RemoveFile(file)
+			fmt.Fprintln(os.Stderr, "Edit cancelled, no changes made.")
+			return nil
+		}
 
-	return nil
+		newObj, _, err := kopscodecs.Decode(edited, nil)
+		if err != nil {
+			return preservedFile(fmt.Errorf("error parsing InstanceGroup: %v", err), file, out)
+		}
+
+		newGroup, ok := newObj.(*api.InstanceGroup)
+		if !ok {
+			results = editResults{
+				file: file,
+			}
+			results.header.addError(fmt.Sprintf("object was not of expected type: %T", newObj))
+			containsError = true
+			continue
+		}
+
+		extraFields, err := edit.HasExtraFields(string(edited), newObj)
+		if err != nil {
+			results = editResults{
+				file: file,
+			}
+			results.header.addError(fmt.Sprintf("error checking for extra fields: %v", err))
+			containsError = true
+			continue
+		}
+		if extraFields != "" {
+			results = editResults{
+				file: file,
+			}
+			for _, line := range strings.Split(extraFields, "\n") {
+				results.header.addExtraFields(line)
+			}
+			containsError = true
+			continue
+		}
+
+		if errs := validation.ValidateInstanceGroup(newGroup, cloud); len(errs) != 0 {
+			results = annotatedEditResults(file, edited, errs)
+			containsError = true
+			continue
+		}
+
+		fullGroup, err := cloudup.PopulateInstanceGroupSpec(cluster, newGroup, cloud, channel)
+		if err != nil {
+			return preservedFile(err, file, out)
+		}
+
+		if errs := validation.CrossValidateInstanceGroup(fullGroup, fullCluster, cloud); len(errs) != 0 {
+			results = annotatedEditResults(file, edited, errs)
+			containsError = true
+			continue
+		}
+
+		defaulted, err := edit.DiffObjects(newGroup, fullGroup)
+		if err != nil {
+			return preservedFile(err, file, out)
+		}
+		if defaulted != "" {
+			fmt.Fprintf(out, "kOps sets the following fields of the InstanceGroup which the edit did not:\n%s\n", defaulted)
+		}
+
+		// Note we perform as much validation as we can, before writing a bad config
+		_, err = clientset.InstanceGroupsFor(cluster).Update(ctx, fullGroup, metav1.UpdateOptions{})
+		if err != nil {
+			return preservedFile(err, file, out)
+		}
+
+		try.RemoveFile(file)
+		return nil
+	}
 }
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -49,6 +50,16 @@ var (
 
 	# export using the internal DNS name, bypassing the cloud load balancer
 	kops export kubecfg k8s-cluster.example.com --internal
+
+	# export a cluster admin user credential which expires after one hour
+	kops export kubecfg k8s-cluster.example.com --admin --ttl 1h
+
+	# export a user which logs in through the cluster's OIDC provider using kubelogin
+	kops export kubecfg k8s-cluster.example.com --oidc --oidc-extra-scope email
+
+	# export a user which gets its credentials from an exec plugin
+	kops export kubecfg k8s-cluster.example.com --exec-command aws-iam-authenticator \
+		--exec-arg token --exec-arg -i --exec-arg k8s-cluster.example.com --exec-env AWS_PROFILE=admin
 		`))
 
 	exportKubecfgShort = i18n.T(`Export kubecfg.`)
@@ -63,6 +74,19 @@ type ExportKubecfgOptions struct {
 
 	// UseKopsAuthenticationPlugin controls whether we should use the kOps auth helper instead of a static credential
 	UseKopsAuthenticationPlugin bool
+
+	// TTL is the lifetime of the admin client certificate, overriding the duration given to --admin
+	TTL time.Duration
+
+	// ExecCommand, ExecArgs and ExecEnv configure an exec credential plugin for the user
+	ExecCommand string
+	ExecArgs    []string
+	ExecEnv     []string
+
+	// OIDC configures kubelogin as the exec credential plugin, using the cluster's OIDC issuer and client
+	OIDC             bool
+	OIDCClientSecret string
+	OIDCExtraScopes  []string
 }
 
 func NewCmdExportKubecfg(f *util.Factory, out io.Writer) *cobra.Command {
@@ -89,6 +113,13 @@ func NewCmdExportKubecfg(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().StringVar(&options.user, "user", options.user, "re-use an existing user in kubeconfig.  Value must specify an existing user block in your kubeconfig file.")
 	cmd.Flags().BoolVar(&options.internal, "internal", options.internal, "use the cluster's internal DNS name")
 	cmd.Flags().BoolVar(&options.UseKopsAuthenticationPlugin, "auth-plugin", options.UseKopsAuthenticationPlugin, "use the kOps authentication plugin")
+	cmd.Flags().DurationVar(&options.TTL, "ttl", options.TTL, "lifetime of the cluster admin user credential exported with --admin")
+	cmd.Flags().StringVar(&options.ExecCommand, "exec-command", options.ExecCommand, "use an exec credential plugin running this command instead of a static credential")
+	cmd.Flags().StringArrayVar(&options.ExecArgs, "exec-arg", options.ExecArgs, "argument to pass to the exec credential plugin (can be specified multiple times)")
+	cmd.Flags().StringArrayVar(&options.ExecEnv, "exec-env", options.ExecEnv, "environment variable, as NAME=VALUE, to set for the exec credential plugin (can be specified multiple times)")
+	cmd.Flags().BoolVar(&options.OIDC, "oidc", options.OIDC, "use kubelogin (kubectl oidc-login) with the cluster's OIDC issuer and client")
+	cmd.Flags().StringVar(&options.OIDCClientSecret, "oidc-client-secret", options.OIDCClientSecret, "client secret to pass to kubelogin")
+	cmd.Flags().StringSliceVar(&options.OIDCExtraScopes, "oidc-extra-scope", options.OIDCExtraScopes, "additional scopes for kubelogin to request")
 
 	return cmd
 }
@@ -106,6 +137,49 @@ func RunExportKubecfg(ctx context.Context, f *util.Factory, out io.Writer, optio
 	if options.admin != 0 && options.user != "" {
 		return fmt.Errorf("cannot use both --admin and --user")
 	}
+	if options.TTL != 0 {
+		if options.admin == 0 {
+			return fmt.Errorf("--ttl can only be used with --admin")
+		}
+		options.admin = options.TTL
+	}
+
+	execEnv := make(map[string]string)
+	for _, env := range options.ExecEnv {
+		tokens := strings.SplitN(env, "=", 2)
+		if len(tokens) != 2 || tokens[0] == "" {
+			return fmt.Errorf("--exec-env must be of the form NAME=VALUE, was %q", env)
+		}
+		execEnv[tokens[0]] = tokens[1]
+	}
+	if options.ExecCommand == "" && (len(options.ExecArgs) != 0 || len(execEnv) != 0) {
+		return fmt.Errorf("--exec-arg and --exec-env require --exec-command")
+	}
+	if !options.OIDC && (options.OIDCClientSecret != "" || len(options.OIDCExtraScopes) != 0) {
+		return fmt.Errorf("--oidc-client-secret and --oidc-extra-scope require --oidc")
+	}
+
+	var execSources []string
+	if options.UseKopsAuthenticationPlugin {
+		execSources = append(execSources, "--auth-plugin")
+	}
+	if options.ExecCommand != "" {
+		execSources = append(execSources, "--exec-command")
+	}
+	if options.OIDC {
+		execSources = append(execSources, "--oidc")
+	}
+	if len(execSources) > 1 {
+		return fmt.Errorf("cannot use both %s", strings.Join(execSources, " and "))
+	}
+	if options.ExecCommand != "" || options.OIDC {
+		if options.admin != 0 {
+			return fmt.Errorf("cannot use both --admin and %s", execSources[0])
+		}
+		if options.user != "" {
+			return fmt.Errorf("cannot use both --user and %s", execSources[0])
+		}
+	}
 
 	var clusterList []*kopsapi.Cluster
 	if options.all {
@@ -157,6 +231,17 @@ func RunExportKubecfg(ctx context.Context, f *util.Factory, out io.Writer, optio
 			return err
 		}
 
+		if options.ExecCommand != "" {
+			conf.AuthenticationExec = append([]string{options.ExecCommand}, options.ExecArgs...)
+			conf.AuthenticationExecEnv = execEnv
+		}
+		if options.OIDC {
+			conf.AuthenticationExec, err = kubeconfig.BuildOIDCAuthenticationExec(cluster, options.OIDCClientSecret, options.OIDCExtraScopes)
+			if err != nil {
+				return err
+			}
+		}
+
 		if err := conf.WriteKubecfg(buildPathOptions(options)); err != nil {
 			return err
 		}
@@ -62,6 +62,11 @@ var (
 type GetOptions struct {
 	output      string
 	clusterName string
+
+	// allClusters gets the resources of every cluster in the state store
+	allClusters bool
+	// concurrency is the number of clusters queried at once when allClusters is set
+	concurrency int
 }
 
 const (
@@ -72,7 +77,8 @@ const (
 
 func NewCmdGet(f *util.Factory, out io.Writer) *cobra.Command {
 	options := &GetOptions{
-		output: OutputTable,
+		output:      OutputTable,
+		concurrency: 4,
 	}
 
 	cmd := &cobra.Command{
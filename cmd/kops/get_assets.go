@@ -21,7 +21,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"path/filepath"
 
+	"k8s.io/kops/pkg/assets"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/assettasks"
 	"k8s.io/kubectl/pkg/util/i18n"
@@ -38,6 +40,12 @@ import (
 type GetAssetsOptions struct {
 	*GetOptions
 	Copy bool
+	// CopyConcurrency is the number of assets copied at the same time
+	CopyConcurrency int
+	// CopyWithDocker copies images with the docker daemon, instead of directly between the registries
+	CopyWithDocker bool
+	// FromBundle is an extracted bundle, from which the assets are copied instead of from their canonical location
+	FromBundle string
 }
 
 type Image struct {
@@ -63,7 +71,8 @@ type copyAssetsTarget struct {
 
 func NewCmdGetAssets(f *util.Factory, out io.Writer, getOptions *GetOptions) *cobra.Command {
 	options := GetAssetsOptions{
-		GetOptions: getOptions,
+		GetOptions:      getOptions,
+		CopyConcurrency: 8,
 	}
 
 	getAssetsShort := i18n.T(`Display assets for cluster.`)
@@ -74,6 +83,13 @@ func NewCmdGetAssets(f *util.Factory, out io.Writer, getOptions *GetOptions) *co
 	getAssetsExample := templates.Examples(i18n.T(`
 	# Display all assets.
 	kops get assets
+
+	# Copy all assets to the file and image repositories in spec.assets.
+	# Assets already present are skipped, so an interrupted copy can be re-run.
+	kops get assets --copy
+
+	# Copy all assets from an extracted bundle, without internet access.
+	kops get assets --copy --from-bundle bundle
 	`))
 
 	cmd := &cobra.Command{
@@ -82,7 +98,7 @@ func NewCmdGetAssets(f *util.Factory, out io.Writer, getOptions *GetOptions) *co
 		Long:    getAssetsLong,
 		Example: getAssetsExample,
 		Run: func(cmd *cobra.Command, args []string) {
-			ctx := context.TODO()
+			ctx := cmd.Context()
 
 			if err := rootCommand.ProcessArgs(args); err != nil {
 				exitWithError(err)
@@ -96,6 +112,9 @@ func NewCmdGetAssets(f *util.Factory, out io.Writer, getOptions *GetOptions) *co
 	}
 
 	cmd.Flags().BoolVar(&options.Copy, "copy", options.Copy, "copy assets to local repository")
+	cmd.Flags().IntVar(&options.CopyConcurrency, "copy-concurrency", options.CopyConcurrency, "number of assets to copy at the same time")
+	cmd.Flags().BoolVar(&options.CopyWithDocker, "copy-with-docker", options.CopyWithDocker, "copy images using the docker daemon, instead of directly between registries")
+	cmd.Flags().StringVar(&options.FromBundle, "from-bundle", options.FromBundle, "extracted bundle from kops toolbox bundle, to copy the assets from")
 
 	return cmd
 }
@@ -108,9 +127,14 @@ func RunGetAssets(ctx context.Context, f *util.Factory, out io.Writer, options *
 		return fmt.Errorf("--name is required")
 	}
 
+	if options.FromBundle != "" && options.CopyWithDocker {
+		return fmt.Errorf("--copy-with-docker cannot be used with --from-bundle")
+	}
+
 	updateClusterResults, err := RunUpdateCluster(ctx, f, clusterName, out, &UpdateClusterOptions{
-		Target:    cloudup.TargetDryRun,
-		GetAssets: true,
+		Target:          cloudup.TargetDryRun,
+		GetAssets:       true,
+		BundleDirectory: options.FromBundle,
 	})
 	if err != nil {
 		return err
@@ -142,8 +166,12 @@ func RunGetAssets(ctx context.Context, f *util.Factory, out io.Writer, options *
 				Name:        fi.String(imageAsset.DownloadLocation),
 				SourceImage: fi.String(imageAsset.CanonicalLocation),
 				TargetImage: fi.String(imageAsset.DownloadLocation),
+				UseDocker:   fi.Bool(options.CopyWithDocker),
 				Lifecycle:   fi.LifecycleSync,
 			}
+			if options.FromBundle != "" {
+				copyImageTask.SourceLayout = fi.String(filepath.Join(options.FromBundle, assets.BundleImagesDirectory))
+			}
 
 			if err := ctx.EnsureTask(copyImageTask); err != nil {
 				return fmt.Errorf("error adding image-copy task: %v", err)
@@ -177,6 +205,9 @@ func RunGetAssets(ctx context.Context, f *util.Factory, out io.Writer, options *
 				SHA:        fi.String(fileAsset.SHAValue),
 				Lifecycle:  fi.LifecycleSync,
 			}
+			if options.FromBundle != "" {
+				copyFileTask.SourceFile = fi.String(filepath.Join(options.FromBundle, assets.BundleFilesDirectory, fileAsset.CanonicalURL.Path))
+			}
 
 			if err := ctx.EnsureTask(copyFileTask); err != nil {
 				return fmt.Errorf("error adding file-copy task: %v", err)
@@ -186,8 +217,10 @@ func RunGetAssets(ctx context.Context, f *util.Factory, out io.Writer, options *
 	}
 
 	if options.Copy {
+		copyConcurrency := options.CopyConcurrency
 		var options fi.RunTasksOptions
 		options.InitDefaults()
+		options.MaxConcurrency = copyConcurrency
 
 		context, err := fi.NewContext(&copyAssetsTarget{}, updateClusterResults.Cluster, nil, nil, nil, nil, true, tasks)
 		if err != nil {
@@ -195,7 +228,7 @@ func RunGetAssets(ctx context.Context, f *util.Factory, out io.Writer, options *
 		}
 		defer context.Close()
 
-		err = context.RunTasks(options)
+		err = context.RunTasks(ctx, options)
 		if err != nil {
 			return fmt.Errorf("error running tasks: %v", err)
 		}
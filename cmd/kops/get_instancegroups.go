@@ -26,6 +26,7 @@ import (
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/kops/cmd/kops/util"
 	api "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/formatter"
@@ -47,6 +48,9 @@ var (
 
 	# Save a cluster's instancegroups desired configuration to YAML file
 	kops get ig --name k8s-cluster.example.com -o yaml > instancegroups-desired-config.yaml
+
+	# Get the nodes instancegroup of every cluster in a state store
+	kops get ig --all-clusters nodes
 	`))
 
 	getInstancegroupsShort = i18n.T(`Get one or many instancegroups`)
@@ -69,13 +73,20 @@ func NewCmdGetInstanceGroups(f *util.Factory, out io.Writer, getOptions *GetOpti
 		Example: getInstancegroupsExample,
 		Run: func(cmd *cobra.Command, args []string) {
 			ctx := context.TODO()
-			err := RunGetInstanceGroups(ctx, &options, args)
+			var err error
+			if options.allClusters {
+				err = RunGetAllClustersInstanceGroups(ctx, &options, args)
+			} else {
+				err = RunGetInstanceGroups(ctx, &options, args)
+			}
 			if err != nil {
 				exitWithError(err)
 			}
 		},
 	}
 
+	cmd.Flags().BoolVar(&options.allClusters, "all-clusters", options.allClusters, "Get the instancegroups of every cluster in the state store")
+
 	return cmd
 }
 
@@ -134,6 +145,67 @@ func RunGetInstanceGroups(ctx context.Context, options *GetInstanceGroupsOptions
 	}
 }
 
+// RunGetAllClustersInstanceGroups lists the instancegroups of every cluster in the state store.
+// Clusters without instancegroups of the requested names are skipped.
+func RunGetAllClustersInstanceGroups(ctx context.Context, options *GetInstanceGroupsOptions, args []string) error {
+	out := os.Stdout
+
+	if rootCommand.clusterName != "" {
+		return fmt.Errorf("cannot specify --name with --all-clusters")
+	}
+
+	clientset, err := rootCommand.Clientset()
+	if err != nil {
+		return err
+	}
+
+	clusters, err := clientset.ListClusters(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	names := sets.NewString(args...)
+	clusterOf := make(map[*api.InstanceGroup]*api.Cluster)
+	var instancegroups []*api.InstanceGroup
+	for i := range clusters.Items {
+		cluster := &clusters.Items[i]
+
+		list, err := clientset.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("error listing instancegroups of cluster %q: %v", cluster.ObjectMeta.Name, err)
+		}
+
+		for j := range list.Items {
+			ig := &list.Items[j]
+			if names.Len() != 0 && !names.Has(ig.ObjectMeta.Name) {
+				continue
+			}
+			clusterOf[ig] = cluster
+			instancegroups = append(instancegroups, ig)
+		}
+	}
+
+	if len(instancegroups) == 0 {
+		return fmt.Errorf("No InstanceGroup objects found")
+	}
+
+	switch options.output {
+	case OutputTable:
+		return allClustersIGOutputTable(clusterOf, instancegroups, out)
+	case OutputYaml, OutputJSON:
+		var obj []runtime.Object
+		for _, ig := range instancegroups {
+			obj = append(obj, ig)
+		}
+		if options.output == OutputYaml {
+			return fullOutputYAML(out, obj...)
+		}
+		return fullOutputJSON(out, obj...)
+	default:
+		return fmt.Errorf("Unknown output format: %q", options.output)
+	}
+}
+
 func filterInstanceGroupsByName(instanceGroupNames []string, list []api.InstanceGroup) ([]*api.InstanceGroup, error) {
 	var instancegroups []*api.InstanceGroup
 	if len(instanceGroupNames) != 0 {
@@ -184,6 +256,32 @@ func igOutputTable(cluster *api.Cluster, instancegroups []*api.InstanceGroup, ou
 	return t.Render(instancegroups, os.Stdout, "NAME", "ROLE", "MACHINETYPE", "MIN", "MAX", "ZONES")
 }
 
+func allClustersIGOutputTable(clusterOf map[*api.InstanceGroup]*api.Cluster, instancegroups []*api.InstanceGroup, out io.Writer) error {
+	t := &tables.Table{}
+	t.AddColumn("CLUSTER", func(c *api.InstanceGroup) string {
+		return clusterOf[c].ObjectMeta.Name
+	})
+	t.AddColumn("NAME", func(c *api.InstanceGroup) string {
+		return c.ObjectMeta.Name
+	})
+	t.AddColumn("ROLE", func(c *api.InstanceGroup) string {
+		return string(c.Spec.Role)
+	})
+	t.AddColumn("MACHINETYPE", func(c *api.InstanceGroup) string {
+		return c.Spec.MachineType
+	})
+	t.AddColumn("ZONES", func(c *api.InstanceGroup) string {
+		return formatter.RenderInstanceGroupZones(clusterOf[c])(c)
+	})
+	t.AddColumn("MIN", func(c *api.InstanceGroup) string {
+		return int32PointerToString(c.Spec.MinSize)
+	})
+	t.AddColumn("MAX", func(c *api.InstanceGroup) string {
+		return int32PointerToString(c.Spec.MaxSize)
+	})
+	return t.Render(instancegroups, out, "CLUSTER", "NAME", "ROLE", "MACHINETYPE", "MIN", "MAX", "ZONES")
+}
+
 func int32PointerToString(v *int32) string {
 	if v == nil {
 		return "-"
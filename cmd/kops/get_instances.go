@@ -18,11 +18,15 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
+	"sync"
 
+	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/pkg/cloudinstances"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
@@ -39,19 +43,50 @@ import (
 	"k8s.io/kops/pkg/apis/kops"
 
 	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/kops/cmd/kops/util"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"sigs.k8s.io/yaml"
 )
 
+// InstanceResult is the machine-readable state of an instance
+type InstanceResult struct {
+	// Cluster is the name of the instance's cluster; it is only set with --all-clusters
+	Cluster        string   `json:"cluster,omitempty"`
+	ID             string   `json:"id"`
+	NodeName       string   `json:"nodeName,omitempty"`
+	Status         string   `json:"status,omitempty"`
+	Roles          []string `json:"roles,omitempty"`
+	State          string   `json:"state,omitempty"`
+	InternalIP     string   `json:"internalIP,omitempty"`
+	InstanceGroup  string   `json:"instanceGroup"`
+	MachineType    string   `json:"machineType,omitempty"`
+	KubeletVersion string   `json:"kubeletVersion,omitempty"`
+	// NeedUpdateReasons are the reasons the instance needs update
+	NeedUpdateReasons []cloudinstances.NeedUpdateReason `json:"needUpdateReasons,omitempty"`
+}
+
 func NewCmdGetInstances(f *util.Factory, out io.Writer, options *GetOptions) *cobra.Command {
 	getInstancesShort := i18n.T(`Display cluster instances.`)
 
 	getInstancesLong := templates.LongDesc(i18n.T(`
-	Display cluster instances.`))
+	Display cluster instances, including the reasons an instance needs update:
+
+	* ConfigurationChanged: the instance does not match the current configuration of its instance group.
+	* Detached: the instance has been detached from its instance group to be replaced.
+	* NodeAnnotated: the node has the kops.k8s.io/needs-update annotation, placed by addons which need a rolling update.
+	* KubeletVersionSkew: the kubelet does not run the cluster's Kubernetes version. This is informational;
+	  the instance is replaced once kops update cluster has updated its configuration.`))
 
 	getInstancesExample := templates.Examples(i18n.T(`
 	# Display all instances.
 	kops get instances
+
+	# List the IDs of the instances whose configuration changed.
+	kops get instances -o json | jq -r '.[] | select(.needUpdateReasons[]?.type == "ConfigurationChanged") | .id'
+
+	# Display the instances of every cluster in the state store, querying eight clusters at a time.
+	kops get instances --all-clusters --concurrency 8
 	`))
 
 	cmd := &cobra.Command{
@@ -62,6 +97,16 @@ func NewCmdGetInstances(f *util.Factory, out io.Writer, options *GetOptions) *co
 		Run: func(cmd *cobra.Command, args []string) {
 			ctx := context.TODO()
 
+			if options.allClusters {
+				if len(args) != 0 || rootCommand.clusterName != "" {
+					exitWithError(fmt.Errorf("cannot specify a cluster name with --all-clusters"))
+				}
+				if err := RunGetAllClustersInstances(ctx, f, out, options); err != nil {
+					exitWithError(err)
+				}
+				return
+			}
+
 			if err := rootCommand.ProcessArgs(args); err != nil {
 				exitWithError(err)
 			}
@@ -73,6 +118,9 @@ func NewCmdGetInstances(f *util.Factory, out io.Writer, options *GetOptions) *co
 		},
 	}
 
+	cmd.Flags().BoolVar(&options.allClusters, "all-clusters", options.allClusters, "Display the instances of every cluster in the state store")
+	cmd.Flags().IntVar(&options.concurrency, "concurrency", options.concurrency, "Number of clusters to query at once with --all-clusters")
+
 	return cmd
 }
 
@@ -98,24 +146,83 @@ func RunGetInstances(ctx context.Context, f *util.Factory, out io.Writer, option
 		return fmt.Errorf("cluster not found %q", options.clusterName)
 	}
 
-	cloud, err := cloudup.BuildCloud(cluster)
+	cloudInstances, err := getCloudInstances(ctx, clientset, cluster)
 	if err != nil {
 		return err
 	}
 
-	k8sClient, err := createK8sClient(cluster)
+	return instancesOutput(cloudInstances, nil, out, options)
+}
+
+// RunGetAllClustersInstances displays the instances of every cluster in the state store.
+// Clusters which cannot be queried are reported after the instances of the others.
+func RunGetAllClustersInstances(ctx context.Context, f *util.Factory, out io.Writer, options *GetOptions) error {
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	var mutex sync.Mutex
+	clusterOf := make(map[*cloudinstances.CloudInstance]string)
+	var cloudInstances []*cloudinstances.CloudInstance
+
+	results, err := forAllClusters(ctx, f, os.Stderr, options.concurrency, func(ctx context.Context, cluster *kops.Cluster, _ io.Writer) error {
+		instances, err := getCloudInstances(ctx, clientset, cluster)
+		if err != nil {
+			return err
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		for _, instance := range instances {
+			clusterOf[instance] = cluster.ObjectMeta.Name
+			cloudInstances = append(cloudInstances, instance)
+		}
+		return nil
+	})
 	if err != nil {
 		return err
 	}
 
+	sort.SliceStable(cloudInstances, func(i, j int) bool {
+		return clusterOf[cloudInstances[i]] < clusterOf[cloudInstances[j]]
+	})
+
+	if err := instancesOutput(cloudInstances, clusterOf, out, options); err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		if r.Error != "" {
+			fmt.Fprintf(os.Stderr, "error getting instances of cluster %q: %s\n", r.Cluster, r.Error)
+		}
+	}
+	return clusterResultsError(results)
+}
+
+// getCloudInstances returns the instances of the cluster, with the reasons they need update
+func getCloudInstances(ctx context.Context, clientset simple.Clientset, cluster *kops.Cluster) ([]*cloudinstances.CloudInstance, error) {
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	k8sClient, err := createK8sClient(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []v1.Node
 	nodeList, err := k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		klog.Warningf("cannot list node names. Kubernetes API unavailable: %v", err)
+	} else {
+		nodes = nodeList.Items
 	}
 
 	igList, err := clientset.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var instanceGroups []*kops.InstanceGroup
@@ -125,10 +232,10 @@ func RunGetInstances(ctx context.Context, f *util.Factory, out io.Writer, option
 
 	var cloudInstances []*cloudinstances.CloudInstance
 
-	cloudGroups, err := cloud.GetCloudGroups(cluster, instanceGroups, false, nodeList.Items)
+	cloudGroups, err := cloud.GetCloudGroups(cluster, instanceGroups, false, nodes)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	for _, cg := range cloudGroups {
@@ -137,17 +244,47 @@ func RunGetInstances(ctx context.Context, f *util.Factory, out io.Writer, option
 		cg.AdjustNeedUpdate()
 	}
 
+	for _, cloudInstance := range cloudInstances {
+		cloudInstance.AddKubeletVersionSkew(cluster.Spec.KubernetesVersion)
+	}
+
+	return cloudInstances, nil
+}
+
+// instancesOutput prints the instances; clusterOf maps each instance to its cluster with --all-clusters
+func instancesOutput(cloudInstances []*cloudinstances.CloudInstance, clusterOf map[*cloudinstances.CloudInstance]string, out io.Writer, options *GetOptions) error {
 	switch options.output {
 	case OutputTable:
-		return instanceOutputTable(cloudInstances, out)
+		return instanceOutputTable(cloudInstances, clusterOf, out)
+	case OutputYaml:
+		y, err := yaml.Marshal(instanceResults(cloudInstances, clusterOf))
+		if err != nil {
+			return fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+		return nil
+	case OutputJSON:
+		j, err := json.Marshal(instanceResults(cloudInstances, clusterOf))
+		if err != nil {
+			return fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(j); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+		return nil
 	default:
 		return fmt.Errorf("unsupported output format: %q", options.output)
 	}
 }
 
-func instanceOutputTable(instances []*cloudinstances.CloudInstance, out io.Writer) error {
+func instanceOutputTable(instances []*cloudinstances.CloudInstance, clusterOf map[*cloudinstances.CloudInstance]string, out io.Writer) error {
 	fmt.Println("")
 	t := &tables.Table{}
+	t.AddColumn("CLUSTER", func(i *cloudinstances.CloudInstance) string {
+		return clusterOf[i]
+	})
 	t.AddColumn("ID", func(i *cloudinstances.CloudInstance) string {
 		return i.ID
 	})
@@ -177,11 +314,45 @@ func instanceOutputTable(instances []*cloudinstances.CloudInstance, out io.Write
 	t.AddColumn("STATE", func(i *cloudinstances.CloudInstance) string {
 		return string(i.State)
 	})
+	t.AddColumn("REASONS", func(i *cloudinstances.CloudInstance) string {
+		var reasons []string
+		for _, reason := range i.NeedUpdateReasons {
+			reasons = append(reasons, reason.Type)
+		}
+		return strings.Join(reasons, ",")
+	})
 
-	columns := []string{"ID", "NODE-NAME", "STATUS", "ROLES", "STATE", "INTERNAL-IP", "INSTANCE-GROUP", "MACHINE-TYPE"}
+	var columns []string
+	if clusterOf != nil {
+		columns = append(columns, "CLUSTER")
+	}
+	columns = append(columns, "ID", "NODE-NAME", "STATUS", "ROLES", "STATE", "INTERNAL-IP", "INSTANCE-GROUP", "MACHINE-TYPE", "REASONS")
 	return t.Render(instances, os.Stdout, columns...)
 }
 
+func instanceResults(instances []*cloudinstances.CloudInstance, clusterOf map[*cloudinstances.CloudInstance]string) []*InstanceResult {
+	results := make([]*InstanceResult, 0, len(instances))
+	for _, i := range instances {
+		result := &InstanceResult{
+			Cluster:           clusterOf[i],
+			ID:                i.ID,
+			Status:            i.Status,
+			Roles:             i.Roles,
+			State:             string(i.State),
+			InternalIP:        i.PrivateIP,
+			InstanceGroup:     i.CloudInstanceGroup.HumanName,
+			MachineType:       i.MachineType,
+			NeedUpdateReasons: i.NeedUpdateReasons,
+		}
+		if i.Node != nil {
+			result.NodeName = i.Node.Name
+			result.KubeletVersion = i.Node.Status.NodeInfo.KubeletVersion
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
 func createK8sClient(cluster *kops.Cluster) (*kubernetes.Clientset, error) {
 	contextName := cluster.ObjectMeta.Name
 	clientGetter := genericclioptions.NewConfigFlags(true)
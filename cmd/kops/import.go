@@ -24,13 +24,24 @@ import (
 
 var (
 	importLong = templates.LongDesc(i18n.T(`
-	Imports a kubernetes cluster created by kube-up.sh into a state store.  This command
-	only support AWS clusters at this time.`))
+	Imports an existing cluster.
+
+	The cloud resources tagged for the cluster by kOps, eksctl or kubeadm are scanned, and
+	an editable spec of the cluster and its instance groups is written, starting with a
+	report of what kOps changes on the first update of the cluster. Review the spec, then
+	create the cluster with kops create -f.
+
+	Clusters created by kube-up.sh are imported directly into the state store with
+	--source kube-up. This command only supports AWS clusters at this time.`))
 
 	importExample = templates.Examples(i18n.T(`
-	# Import a cluster
+	# Import the spec of a cluster created with kubeadm
 	kops import cluster --name k8s-cluster.example.com --region us-east-1 \
-	  --state=s3://k8s-cluster.example.com`))
+	  --source kubeadm > cluster.yaml
+
+	# Recover the spec of a kOps cluster whose state store was lost
+	kops import cluster --name k8s-cluster.example.com --region us-east-1 > cluster.yaml
+	kops create -f cluster.yaml --state=s3://k8s-cluster.example.com`))
 
 	importShort = i18n.T(`Import a cluster.`)
 )
@@ -19,16 +19,24 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/spf13/cobra"
+	"k8s.io/kops/pkg/importer"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/kutil"
 )
 
 type ImportClusterCmd struct {
 	Region string
+
+	// Source is the tool which created the cluster: kops, eksctl, kubeadm or kube-up
+	Source string
 }
 
+const importSourceKubeUp = "kube-up"
+
 var importCluster ImportClusterCmd
 
 func init() {
@@ -39,7 +47,7 @@ func init() {
 		Example: importExample,
 		Run: func(cmd *cobra.Command, args []string) {
 			ctx := context.TODO()
-			err := importCluster.Run(ctx)
+			err := importCluster.Run(ctx, os.Stdout)
 			if err != nil {
 				exitWithError(err)
 			}
@@ -49,9 +57,10 @@ func init() {
 	importCmd.AddCommand(cmd)
 
 	cmd.Flags().StringVar(&importCluster.Region, "region", "", "region")
+	cmd.Flags().StringVar(&importCluster.Source, "source", string(importer.SourceKops), "Tool which created the cluster. One of kops, eksctl, kubeadm or kube-up")
 }
 
-func (c *ImportClusterCmd) Run(ctx context.Context) error {
+func (c *ImportClusterCmd) Run(ctx context.Context, out io.Writer) error {
 	if c.Region == "" {
 		return fmt.Errorf("--region is required")
 	}
@@ -60,6 +69,10 @@ func (c *ImportClusterCmd) Run(ctx context.Context) error {
 		return fmt.Errorf("--name is required")
 	}
 
+	if c.Source != importSourceKubeUp {
+		return c.importSpec(out, clusterName)
+	}
+
 	tags := map[string]string{awsup.TagClusterName: clusterName}
 	cloud, err := awsup.NewAWSCloud(c.Region, tags)
 	if err != nil {
@@ -93,3 +106,30 @@ func (c *ImportClusterCmd) Run(ctx context.Context) error {
 
 	return nil
 }
+
+// importSpec writes the spec of a cluster discovered from its cloud resources
+func (c *ImportClusterCmd) importSpec(out io.Writer, clusterName string) error {
+	source := importer.Source(c.Source)
+
+	cloud, err := awsup.NewAWSCloud(c.Region, nil)
+	if err != nil {
+		return fmt.Errorf("error initializing AWS client: %v", err)
+	}
+
+	inventory, err := importer.Discover(cloud, clusterName, source)
+	if err != nil {
+		return err
+	}
+
+	result, err := importer.Build(clusterName, source, inventory)
+	if err != nil {
+		return err
+	}
+
+	y, err := result.ToYAML()
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(y)
+	return err
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/klog/v2"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// lockCluster takes the state store lock of the cluster, for an operation that writes the state of the cluster.
+// The returned function releases the lock, logging any error, so that it can be deferred.
+func lockCluster(ctx context.Context, clientset simple.Clientset, cluster *kopsapi.Cluster, operation string) (func(), error) {
+	unlock, err := clientset.LockCluster(ctx, cluster, operation)
+	if err != nil {
+		if lockHeld, ok := err.(*vfs.LockHeldError); ok {
+			return nil, fmt.Errorf("another operation is modifying cluster %q: %v\nIf that operation is no longer running, release its lock with \"kops unlock cluster %s --lock-id %s\" and retry", cluster.Name, lockHeld, cluster.Name, lockHeld.Holder.ID)
+		}
+		return nil, fmt.Errorf("error locking cluster %q: %v", cluster.Name, err)
+	}
+
+	return func() {
+		if err := unlock(); err != nil {
+			klog.Warningf("error releasing lock of cluster %q: %v", cluster.Name, err)
+		}
+	}, nil
+}
@@ -27,5 +27,6 @@ func main() {
 // exitWithError will terminate execution with an error result
 // It prints the error to stderr and exits with a non-zero exit code
 func exitWithError(err error) {
+	finishTracing(err)
 	commandutils.ExitWithError(err)
 }
@@ -24,9 +24,13 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/cmd/kops/util"
 	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/pkg/kopscodecs"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
 	"k8s.io/kops/util/pkg/text"
@@ -49,6 +53,9 @@ var (
 
 		# Note, if the resource does not exist the command will error, use --force to provision resource
 		kops replace -f my-cluster.yaml --force
+
+		# Replace the instance groups of a cluster, and delete those which are not in the file
+		kops replace -f my-cluster.yaml --force --prune
 		`))
 
 	replaceShort = i18n.T(`Replace cluster resources.`)
@@ -60,6 +67,8 @@ type replaceOptions struct {
 	Filenames []string
 	// create any resources not found - we limit to instance groups only for now
 	force bool
+	// prune deletes the instance groups of the clusters in the files which are not in the files
+	prune bool
 }
 
 // NewCmdReplace returns a new replace command
@@ -83,6 +92,7 @@ func NewCmdReplace(f *util.Factory, out io.Writer) *cobra.Command {
 	}
 	cmd.Flags().StringSliceVarP(&options.Filenames, "filename", "f", options.Filenames, "A list of one or more files separated by a comma.")
 	cmd.Flags().BoolVarP(&options.force, "force", "", false, "Force any changes, which will also create any non-existing resource")
+	cmd.Flags().BoolVar(&options.prune, "prune", false, "Delete the instance groups of each cluster with instance groups in the files which are not in the files, along with their cloud resources")
 	cmd.MarkFlagRequired("filename")
 
 	return cmd
@@ -95,6 +105,7 @@ func RunReplace(ctx context.Context, f *util.Factory, cmd *cobra.Command, out io
 		return err
 	}
 
+	var objects []replaceObject
 	for _, f := range c.Filenames {
 		var contents []byte
 		if f == "-" {
@@ -115,113 +126,203 @@ func RunReplace(ctx context.Context, f *util.Factory, cmd *cobra.Command, out io
 			if err != nil {
 				return fmt.Errorf("error parsing file %q: %v", f, err)
 			}
+			objects = append(objects, replaceObject{obj: o, gvk: gvk, file: f})
+		}
+	}
 
-			switch v := o.(type) {
-			case *kopsapi.Cluster:
-				{
-					// Retrieve the current status of the cluster.  This will eventually be part of the cluster object.
-					cloud, err := cloudup.BuildCloud(v)
-					if err != nil {
-						return err
-					}
-					status, err := cloud.FindClusterStatus(v)
-					if err != nil {
-						return err
-					}
+	// Determine the instance groups to prune before replacing anything, so that we fail early
+	var prune []*replacePrune
+	if c.prune {
+		prune, err = instanceGroupsToPruneForReplace(ctx, clientset, objects)
+		if err != nil {
+			return err
+		}
+	}
 
-					// Check if the cluster exists already
-					clusterName := v.Name
-					cluster, err := clientset.GetCluster(ctx, clusterName)
-					if err != nil {
-						if errors.IsNotFound(err) {
-							cluster = nil
-						} else {
-							return fmt.Errorf("error fetching cluster %q: %v", clusterName, err)
-						}
-					}
-					if cluster == nil {
-						if !c.force {
-							return fmt.Errorf("cluster %v does not exist (try adding --force flag)", clusterName)
-						}
-						_, err = clientset.CreateCluster(ctx, v)
-						if err != nil {
-							return fmt.Errorf("error creating cluster: %v", err)
-						}
-					} else {
-						_, err = clientset.UpdateCluster(ctx, v, status)
-						if err != nil {
-							return fmt.Errorf("error replacing cluster: %v", err)
-						}
-					}
-				}
+	for _, object := range objects {
+		o, gvk, f := object.obj, object.gvk, object.file
 
-			case *kopsapi.InstanceGroup:
-				clusterName := v.ObjectMeta.Labels[kopsapi.LabelClusterName]
-				if clusterName == "" {
-					return fmt.Errorf("must specify %q label with cluster name to replace instanceGroup", kopsapi.LabelClusterName)
+		switch v := o.(type) {
+		case *kopsapi.Cluster:
+			{
+				// Retrieve the current status of the cluster.  This will eventually be part of the cluster object.
+				cloud, err := cloudup.BuildCloud(v)
+				if err != nil {
+					return err
 				}
-				cluster, err := clientset.GetCluster(ctx, clusterName)
+				status, err := cloud.FindClusterStatus(v)
 				if err != nil {
-					if errors.IsNotFound(err) {
-						return fmt.Errorf("cluster %q not found", clusterName)
-					}
-					return fmt.Errorf("error fetching cluster %q: %v", clusterName, err)
+					return err
 				}
-				// check if the instancegroup exists already
-				igName := v.ObjectMeta.Name
-				ig, err := clientset.InstanceGroupsFor(cluster).Get(ctx, igName, metav1.GetOptions{})
+
+				// Check if the cluster exists already
+				clusterName := v.Name
+				cluster, err := clientset.GetCluster(ctx, clusterName)
 				if err != nil {
 					if errors.IsNotFound(err) {
-						if !c.force {
-							return fmt.Errorf("instanceGroup: %v does not exist (try adding --force flag)", igName)
-						}
+						cluster = nil
 					} else {
-						return fmt.Errorf("unable to check for instanceGroup: %v", err)
+						return fmt.Errorf("error fetching cluster %q: %v", clusterName, err)
 					}
 				}
-				switch ig {
-				case nil:
-					klog.Infof("instanceGroup: %v was not found, creating resource now", igName)
-					_, err = clientset.InstanceGroupsFor(cluster).Create(ctx, v, metav1.CreateOptions{})
+				if cluster == nil {
+					if !c.force {
+						return fmt.Errorf("cluster %v does not exist (try adding --force flag)", clusterName)
+					}
+					_, err = clientset.CreateCluster(ctx, v)
 					if err != nil {
-						return fmt.Errorf("error creating instanceGroup: %v", err)
+						return fmt.Errorf("error creating cluster: %v", err)
 					}
-				default:
-					_, err = clientset.InstanceGroupsFor(cluster).Update(ctx, v, metav1.UpdateOptions{})
+				} else {
+					_, err = clientset.UpdateCluster(ctx, v, status)
 					if err != nil {
-						return fmt.Errorf("error replacing instanceGroup: %v", err)
+						return fmt.Errorf("error replacing cluster: %v", err)
 					}
 				}
-			case *kopsapi.SSHCredential:
-				clusterName := v.ObjectMeta.Labels[kopsapi.LabelClusterName]
-				if clusterName == "" {
-					return fmt.Errorf("must specify %q label with cluster name to replace SSHCredential", kopsapi.LabelClusterName)
+			}
+
+		case *kopsapi.InstanceGroup:
+			clusterName := v.ObjectMeta.Labels[kopsapi.LabelClusterName]
+			if clusterName == "" {
+				return fmt.Errorf("must specify %q label with cluster name to replace instanceGroup", kopsapi.LabelClusterName)
+			}
+			cluster, err := clientset.GetCluster(ctx, clusterName)
+			if err != nil {
+				if errors.IsNotFound(err) {
+					return fmt.Errorf("cluster %q not found", clusterName)
 				}
-				if v.Spec.PublicKey == "" {
-					return fmt.Errorf("spec.PublicKey is required")
+				return fmt.Errorf("error fetching cluster %q: %v", clusterName, err)
+			}
+			// check if the instancegroup exists already
+			igName := v.ObjectMeta.Name
+			ig, err := clientset.InstanceGroupsFor(cluster).Get(ctx, igName, metav1.GetOptions{})
+			if err != nil {
+				if errors.IsNotFound(err) {
+					if !c.force {
+						return fmt.Errorf("instanceGroup: %v does not exist (try adding --force flag)", igName)
+					}
+				} else {
+					return fmt.Errorf("unable to check for instanceGroup: %v", err)
 				}
-
-				cluster, err := clientset.GetCluster(ctx, clusterName)
+			}
+			switch ig {
+			case nil:
+				klog.Infof("instanceGroup: %v was not found, creating resource now", igName)
+				_, err = clientset.InstanceGroupsFor(cluster).Create(ctx, v, metav1.CreateOptions{})
 				if err != nil {
-					return err
+					return fmt.Errorf("error creating instanceGroup: %v", err)
 				}
-
-				sshCredentialStore, err := clientset.SSHCredentialStore(cluster)
+			default:
+				_, err = clientset.InstanceGroupsFor(cluster).Update(ctx, v, metav1.UpdateOptions{})
 				if err != nil {
-					return err
+					return fmt.Errorf("error replacing instanceGroup: %v", err)
 				}
+			}
+		case *kopsapi.SSHCredential:
+			clusterName := v.ObjectMeta.Labels[kopsapi.LabelClusterName]
+			if clusterName == "" {
+				return fmt.Errorf("must specify %q label with cluster name to replace SSHCredential", kopsapi.LabelClusterName)
+			}
+			if v.Spec.PublicKey == "" {
+				return fmt.Errorf("spec.PublicKey is required")
+			}
 
-				sshKeyArr := []byte(v.Spec.PublicKey)
-				err = sshCredentialStore.AddSSHPublicKey("admin", sshKeyArr)
-				if err != nil {
-					return fmt.Errorf("error replacing SSHCredential: %v", err)
-				}
-			default:
-				klog.V(2).Infof("Type of object was %T", v)
-				return fmt.Errorf("unhandled kind %q in %q", gvk, f)
+			cluster, err := clientset.GetCluster(ctx, clusterName)
+			if err != nil {
+				return err
+			}
+
+			sshCredentialStore, err := clientset.SSHCredentialStore(cluster)
+			if err != nil {
+				return err
 			}
+
+			sshKeyArr := []byte(v.Spec.PublicKey)
+			err = sshCredentialStore.AddSSHPublicKey("admin", sshKeyArr)
+			if err != nil {
+				return fmt.Errorf("error replacing SSHCredential: %v", err)
+			}
+		default:
+			klog.V(2).Infof("Type of object was %T", v)
+			return fmt.Errorf("unhandled kind %q in %q", gvk, f)
+		}
+	}
+
+	for _, p := range prune {
+		cloud, err := cloudup.BuildCloud(p.cluster)
+		if err != nil {
+			return err
+		}
+		if err := deleteInstanceGroups(out, clientset, p.cluster, cloud, p.instanceGroups, false); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
+
+// replaceObject is an object decoded from the files passed to kops replace
+type replaceObject struct {
+	obj  runtime.Object
+	gvk  *schema.GroupVersionKind
+	file string
+}
+
+// replacePrune holds the instance groups of a cluster which kops replace --prune deletes
+type replacePrune struct {
+	cluster        *kopsapi.Cluster
+	instanceGroups []*kopsapi.InstanceGroup
+}
+
+// instanceGroupsToPruneForReplace returns, for each cluster with instance groups in the objects,
+// the instance groups in the state store which are not in the objects.
+func instanceGroupsToPruneForReplace(ctx context.Context, clientset simple.Clientset, objects []replaceObject) ([]*replacePrune, error) {
+	keep := make(map[string]sets.String)
+	for _, object := range objects {
+		ig, ok := object.obj.(*kopsapi.InstanceGroup)
+		if !ok {
+			continue
+		}
+		clusterName := ig.ObjectMeta.Labels[kopsapi.LabelClusterName]
+		if clusterName == "" {
+			return nil, fmt.Errorf("must specify %q label with cluster name to replace instanceGroup", kopsapi.LabelClusterName)
+		}
+		if keep[clusterName] == nil {
+			keep[clusterName] = sets.NewString()
+		}
+		keep[clusterName].Insert(ig.ObjectMeta.Name)
+	}
+
+	var prune []*replacePrune
+	for _, clusterName := range sets.StringKeySet(keep).List() {
+		cluster, err := clientset.GetCluster(ctx, clusterName)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				// The cluster is created from the files, so it has no other instance groups
+				continue
+			}
+			return nil, fmt.Errorf("error fetching cluster %q: %v", clusterName, err)
+		}
+		if cluster == nil {
+			continue
+		}
+
+		list, err := clientset.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error listing instance groups of cluster %q: %v", clusterName, err)
+		}
+		var groups []*kopsapi.InstanceGroup
+		for i := range list.Items {
+			groups = append(groups, &list.Items[i])
+		}
+
+		instanceGroups, err := instanceGroupsToPrune(groups, keep[clusterName])
+		if err != nil {
+			return nil, err
+		}
+		if len(instanceGroups) != 0 {
+			prune = append(prune, &replacePrune{cluster: cluster, instanceGroups: instanceGroups})
+		}
+	}
+	return prune, nil
+}
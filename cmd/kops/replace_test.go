@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/util/pkg/text"
+)
+
+func TestReplacePrune(t *testing.T) {
+	ctx := context.Background()
+
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+	h.SetupMockAWS()
+
+	factory := util.NewFactory(&util.FactoryOptions{RegistryPath: "memfs://tests"})
+	clientset, err := factory.Clientset()
+	if err != nil {
+		t.Fatalf("error getting clientset: %v", err)
+	}
+
+	contents, err := ioutil.ReadFile("../../tests/integration/create_cluster/minimal-1.21/expected-v1alpha2.yaml")
+	if err != nil {
+		t.Fatalf("error reading manifests: %v", err)
+	}
+	sections := text.SplitContentToSections(contents)
+	if len(sections) != 3 {
+		t.Fatalf("expected a cluster and two instance groups, found %d manifests", len(sections))
+	}
+	cluster, master, nodes := string(sections[0]), string(sections[1]), string(sections[2])
+	otherNodes := strings.ReplaceAll(nodes, "nodes-us-test-1a", "other-nodes")
+
+	replace := func(prune bool, manifests ...string) (string, error) {
+		p := filepath.Join(h.TempDir, "cluster.yaml")
+		if err := ioutil.WriteFile(p, []byte(strings.Join(manifests, "\n---\n")), 0644); err != nil {
+			t.Fatalf("error writing manifests: %v", err)
+		}
+		var out bytes.Buffer
+		err := RunReplace(ctx, factory, nil, &out, &replaceOptions{
+			Filenames: []string{p},
+			force:     true,
+			prune:     prune,
+		})
+		return out.String(), err
+	}
+
+	instanceGroupNames := func() string {
+		t.Helper()
+		c, err := clientset.GetCluster(ctx, "minimal.example.com")
+		if err != nil {
+			t.Fatalf("error getting cluster: %v", err)
+		}
+		list, err := clientset.InstanceGroupsFor(c).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			t.Fatalf("error listing instance groups: %v", err)
+		}
+		var names []string
+		for _, ig := range list.Items {
+			names = append(names, ig.ObjectMeta.Name)
+		}
+		return strings.Join(names, ",")
+	}
+
+	if _, err := replace(true, cluster, master, nodes); err != nil {
+		t.Fatalf("error replacing: %v", err)
+	}
+	if names := instanceGroupNames(); names != "master-us-test-1a,nodes-us-test-1a" {
+		t.Errorf("unexpected instance groups %s", names)
+	}
+
+	if _, err := replace(true, otherNodes); err == nil || !strings.Contains(err.Error(), `not pruning control plane InstanceGroup "master-us-test-1a"`) {
+		t.Errorf("expected the control plane instance group not to be pruned, got %v", err)
+	}
+	if names := instanceGroupNames(); names != "master-us-test-1a,nodes-us-test-1a" {
+		t.Errorf("expected nothing to be replaced, got instance groups %s", names)
+	}
+
+	if _, err := replace(false, master, otherNodes); err != nil {
+		t.Fatalf("error replacing: %v", err)
+	}
+	if names := instanceGroupNames(); names != "master-us-test-1a,nodes-us-test-1a,other-nodes" {
+		t.Errorf("expected no instance groups to be pruned without --prune, got %s", names)
+	}
+
+	out, err := replace(true, master, otherNodes)
+	if err != nil {
+		t.Fatalf("error replacing: %v", err)
+	}
+	if out != "Deleted instancegroup/nodes-us-test-1a\n" {
+		t.Errorf("unexpected output %q", out)
+	}
+	if names := instanceGroupNames(); names != "master-us-test-1a,other-nodes" {
+		t.Errorf("unexpected instance groups %s", names)
+	}
+}
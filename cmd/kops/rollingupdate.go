@@ -33,6 +33,9 @@ func NewCmdRollingUpdate(f *util.Factory, out io.Writer) *cobra.Command {
 
 	// create subcommands
 	cmd.AddCommand(NewCmdRollingUpdateCluster(f, out))
+	cmd.AddCommand(NewCmdRollingUpdatePause(f, out))
+	cmd.AddCommand(NewCmdRollingUpdateResume(f, out))
+	cmd.AddCommand(NewCmdRollingUpdateStatus(f, out))
 
 	return cmd
 }
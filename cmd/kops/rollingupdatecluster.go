@@ -18,6 +18,8 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -95,6 +97,25 @@ var (
 		  --fail-on-validate-error="false" \
 		  --node-interval 8m \
 		  --instance-group nodes
+
+		# Replace a single node of the k8s-cluster.example.com kOps cluster,
+		# then pause so the result can be checked before continuing
+		# with kops rolling-update resume.
+		kops rolling-update cluster k8s-cluster.example.com --yes \
+		  --canary=1
+
+		# Resize the control plane of the k8s-cluster.example.com kOps cluster
+		# after changing the machineType of its control plane instance groups
+		# or the volumeSize of its etcd members and running kops update cluster.
+		# Control plane instances are replaced one at a time, and every etcd
+		# member must be healthy before the next one is replaced.
+		kops rolling-update cluster k8s-cluster.example.com --yes \
+		  --control-plane-resize
+
+		# Roll every cluster in the state store, two clusters at a time,
+		# then print the outcome of each cluster.
+		kops rolling-update cluster --all-clusters --yes \
+		  --concurrency=2
 		`))
 
 	rollingupdateShort = i18n.T(`Rolling update a cluster.`)
@@ -119,6 +140,9 @@ type RollingUpdateOptions struct {
 	// PostDrainDelay is the duration of a pause after a drain operation
 	PostDrainDelay time.Duration
 
+	// PodEvictionGrace overrides the termination grace period of evicted pods; negative uses each pod's own
+	PodEvictionGrace time.Duration
+
 	// ValidationTimeout is the timeout for validation to succeed after the drain and pause
 	ValidationTimeout time.Duration
 
@@ -134,9 +158,16 @@ type RollingUpdateOptions struct {
 	// BastionInterval is the minimum time to wait after stopping a bastion.  This does not include drain and validate time.
 	BastionInterval time.Duration
 
-	// Interactive rolling-update prompts user to continue after each instances is updated.
+	// Interactive rolling-update prompts user to approve or skip the replacement of each instance.
 	Interactive bool
 
+	// Canary, if nonzero, is the number of instances to replace before pausing the rolling update.
+	Canary int
+
+	// ControlPlaneResize replaces every control plane instance, one at a time,
+	// validating the health of the etcd clusters after each one.
+	ControlPlaneResize bool
+
 	ClusterName string
 
 	// InstanceGroups is the list of instance groups to rolling-update;
@@ -146,6 +177,15 @@ type RollingUpdateOptions struct {
 	// InstanceGroupRoles is the list of roles we should rolling-update
 	// if not specified, all instance groups will be updated
 	InstanceGroupRoles []string
+
+	// resume continues the rolling update recorded in the state store
+	resume bool
+
+	// AllClusters rolling-updates every cluster in the state store
+	AllClusters bool
+
+	// Concurrency is the number of clusters updated at once when AllClusters is set
+	Concurrency int
 }
 
 func (o *RollingUpdateOptions) InitDefaults() {
@@ -161,8 +201,10 @@ func (o *RollingUpdateOptions) InitDefaults() {
 	o.Interactive = false
 
 	o.PostDrainDelay = 5 * time.Second
+	o.PodEvictionGrace = -1 * time.Second
 	o.ValidationTimeout = 15 * time.Minute
 	o.ValidateCount = 2
+	o.Concurrency = 1
 }
 
 func NewCmdRollingUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
@@ -192,15 +234,33 @@ func NewCmdRollingUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().DurationVar(&options.NodeInterval, "node-interval", options.NodeInterval, "Time to wait between restarting nodes")
 	cmd.Flags().DurationVar(&options.BastionInterval, "bastion-interval", options.BastionInterval, "Time to wait between restarting bastions")
 	cmd.Flags().DurationVar(&options.PostDrainDelay, "post-drain-delay", options.PostDrainDelay, "Time to wait after draining each node")
-	cmd.Flags().BoolVarP(&options.Interactive, "interactive", "i", options.Interactive, "Prompt to continue after each instance is updated")
+	cmd.Flags().DurationVar(&options.PodEvictionGrace, "pod-eviction-grace", options.PodEvictionGrace, "Termination grace period for pods evicted while draining nodes; negative uses the period of each pod")
+	cmd.Flags().BoolVarP(&options.Interactive, "interactive", "i", options.Interactive, "Prompt to approve or skip the replacement of each instance")
+	cmd.Flags().IntVar(&options.Canary, "canary", options.Canary, "Number of instances to replace before pausing the rolling update, which can then be continued with kops rolling-update resume")
+	cmd.Flags().BoolVar(&options.ControlPlaneResize, "control-plane-resize", options.ControlPlaneResize, "Replace every control plane instance one at a time, waiting for all etcd members to be healthy after each one")
 	cmd.Flags().StringSliceVar(&options.InstanceGroups, "instance-group", options.InstanceGroups, "List of instance groups to update (defaults to all if not specified)")
 	cmd.Flags().StringSliceVar(&options.InstanceGroupRoles, "instance-group-roles", options.InstanceGroupRoles, "If specified, only instance groups of the specified role will be updated ("+strings.Join(allRoles, ",")+")")
 
 	cmd.Flags().BoolVar(&options.FailOnDrainError, "fail-on-drain-error", true, "The rolling-update will fail if draining a node fails.")
 	cmd.Flags().BoolVar(&options.FailOnValidate, "fail-on-validate-error", true, "The rolling-update will fail if the cluster fails to validate.")
 
+	cmd.Flags().BoolVar(&options.AllClusters, "all-clusters", options.AllClusters, "Rolling update every cluster in the state store")
+	cmd.Flags().IntVar(&options.Concurrency, "concurrency", options.Concurrency, "Number of clusters to update at once with --all-clusters")
+
 	cmd.Run = func(cmd *cobra.Command, args []string) {
-		ctx := context.TODO()
+		ctx := cmd.Context()
+
+		if options.AllClusters {
+			if len(args) != 0 || rootCommand.clusterName != "" {
+				exitWithError(fmt.Errorf("cannot specify a cluster name with --all-clusters"))
+				return
+			}
+
+			if err := RunRollingUpdateAllClusters(ctx, f, os.Stdout, &options); err != nil {
+				exitWithError(err)
+			}
+			return
+		}
 
 		err := rootCommand.ProcessArgs(args)
 		if err != nil {
@@ -227,8 +287,39 @@ func NewCmdRollingUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	return cmd
 }
 
+// RunRollingUpdateAllClusters performs a rolling update of every cluster in the state store,
+// then prints the outcome of each cluster.
+func RunRollingUpdateAllClusters(ctx context.Context, f *util.Factory, out io.Writer, options *RollingUpdateOptions) error {
+	if options.Interactive && options.Concurrency > 1 {
+		return fmt.Errorf("cannot use --interactive when updating more than one cluster at once")
+	}
+
+	results, err := forAllClusters(ctx, f, out, options.Concurrency, func(ctx context.Context, cluster *kopsapi.Cluster, out io.Writer) error {
+		clusterOptions := *options
+		clusterOptions.ClusterName = cluster.ObjectMeta.Name
+		return RunRollingUpdateCluster(ctx, f, out, &clusterOptions)
+	})
+	if err != nil {
+		return err
+	}
+
+	return clusterResultsOutputTable(results, out)
+}
+
 func RunRollingUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, options *RollingUpdateOptions) error {
 
+	if options.Canary < 0 {
+		return fmt.Errorf("--canary must not be negative")
+	}
+	if options.ControlPlaneResize {
+		if options.CloudOnly {
+			return fmt.Errorf("cannot use --cloudonly with --control-plane-resize, as the health of etcd is checked through the kubernetes API")
+		}
+		if !options.FailOnValidate {
+			return fmt.Errorf("cannot use --fail-on-validate-error=false with --control-plane-resize")
+		}
+	}
+
 	clientset, err := f.Clientset()
 	if err != nil {
 		return err
@@ -325,6 +416,25 @@ func RunRollingUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer
 		warnUnmatched = false
 	}
 
+	if options.ControlPlaneResize {
+		var filtered []*kopsapi.InstanceGroup
+		for _, ig := range instanceGroups {
+			if ig.Spec.Role == kopsapi.InstanceGroupRoleMaster {
+				filtered = append(filtered, ig)
+			} else if len(options.InstanceGroups) != 0 {
+				return fmt.Errorf("InstanceGroup %q is not a control plane instance group", ig.ObjectMeta.Name)
+			}
+		}
+		if len(filtered) == 0 {
+			return fmt.Errorf("no control plane instance groups selected")
+		}
+
+		instanceGroups = filtered
+
+		// Don't warn if we find more ASGs than IGs
+		warnUnmatched = false
+	}
+
 	cloud, err := cloudup.BuildCloud(cluster)
 	if err != nil {
 		return err
@@ -336,28 +446,34 @@ func RunRollingUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer
 	}
 
 	d := &instancegroups.RollingUpdateCluster{
-		Clientset:         clientset,
-		Ctx:               ctx,
-		Cluster:           cluster,
-		MasterInterval:    options.MasterInterval,
-		NodeInterval:      options.NodeInterval,
-		BastionInterval:   options.BastionInterval,
-		Interactive:       options.Interactive,
-		Force:             options.Force,
-		Cloud:             cloud,
-		K8sClient:         k8sClient,
-		FailOnDrainError:  options.FailOnDrainError,
-		FailOnValidate:    options.FailOnValidate,
-		CloudOnly:         options.CloudOnly,
-		ClusterName:       options.ClusterName,
-		PostDrainDelay:    options.PostDrainDelay,
-		ValidationTimeout: options.ValidationTimeout,
-		ValidateCount:     int(options.ValidateCount),
+		Clientset:          clientset,
+		Ctx:                ctx,
+		Cluster:            cluster,
+		MasterInterval:     options.MasterInterval,
+		NodeInterval:       options.NodeInterval,
+		BastionInterval:    options.BastionInterval,
+		Interactive:        options.Interactive,
+		Canary:             options.Canary,
+		Force:              options.Force,
+		ControlPlaneResize: options.ControlPlaneResize,
+		Cloud:              cloud,
+		K8sClient:          k8sClient,
+		FailOnDrainError:   options.FailOnDrainError,
+		FailOnValidate:     options.FailOnValidate,
+		CloudOnly:          options.CloudOnly,
+		ClusterName:        options.ClusterName,
+		PostDrainDelay:     options.PostDrainDelay,
+		ValidationTimeout:  options.ValidationTimeout,
+		ValidateCount:      int(options.ValidateCount),
 		// TODO should we expose this to the UI?
 		ValidateTickDuration:    30 * time.Second,
 		ValidateSuccessDuration: 10 * time.Second,
 	}
 
+	if options.PodEvictionGrace >= 0 {
+		d.PodEvictionGracePeriod = &options.PodEvictionGrace
+	}
+
 	err = d.AdjustNeedUpdate(groups)
 	if err != nil {
 		return err
@@ -422,24 +538,61 @@ func RunRollingUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer
 		}
 	}
 
-	if !needUpdate && !options.Force {
-		fmt.Printf("\nNo rolling-update required.\n")
+	if !needUpdate && !options.Force && !options.ControlPlaneResize {
+		fmt.Fprintf(out, "\nNo rolling-update required.\n")
 		return nil
 	}
 
 	if !options.Yes {
-		fmt.Printf("\nMust specify --yes to rolling-update.\n")
+		fmt.Fprintf(out, "\nMust specify --yes to rolling-update.\n")
 		return nil
 	}
 
+	unlock, err := lockCluster(ctx, clientset, cluster, "rolling-update cluster")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	d.ProgressStore, err = instancegroups.NewProgressStore(cluster)
+	if err != nil {
+		return err
+	}
+	d.ProgressOptions, err = json.Marshal(options)
+	if err != nil {
+		return fmt.Errorf("error serializing rolling update options: %v", err)
+	}
+	d.Resume = options.resume
+
 	var clusterValidator validation.ClusterValidator
 	if !options.CloudOnly {
-		clusterValidator, err = validation.NewClusterValidator(cluster, cloud, list, config.Host, k8sClient)
+		var selectors []string
+		if options.ControlPlaneResize {
+			// Only move on to the next control plane instance once every etcd member is back
+			selectors = append(selectors, "etcd")
+		}
+		if validation.IsCiliumKubeProxyReplacementStrict(cluster) {
+			// Without kube-proxy, a replacement node cannot reach services until its Cilium agent is ready
+			selectors = append(selectors, "cilium")
+		}
+		var checks []validation.Check
+		if len(selectors) != 0 {
+			checks, err = validation.SelectChecks(selectors)
+			if err != nil {
+				return err
+			}
+		}
+		clusterValidator, err = validation.NewClusterValidator(cluster, cloud, list, config.Host, k8sClient, checks)
 		if err != nil {
 			return fmt.Errorf("cannot create cluster validator: %v", err)
 		}
 	}
 	d.ClusterValidator = clusterValidator
 
-	return d.RollingUpdate(groups, list)
+	err = d.RollingUpdate(groups, list)
+	if errors.Is(err, instancegroups.ErrRollingUpdatePaused) {
+		fmt.Fprintf(out, "\nRolling update paused. Use \"kops rolling-update resume\" to continue.\n")
+		return nil
+	}
+	return err
 }
@@ -0,0 +1,112 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/instancegroups"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	rollingUpdatePauseLong = templates.LongDesc(i18n.T(`
+	Pause the running rolling update of a cluster.
+
+	The rolling update finishes replacing the instances it is currently draining and then stops,
+	recording its progress in the state store. Use "kops rolling-update resume" to continue it.`))
+
+	rollingUpdatePauseExample = templates.Examples(i18n.T(`
+	# Pause the rolling update of a cluster
+	kops rolling-update pause k8s-cluster.example.com
+	`))
+
+	rollingUpdatePauseShort = i18n.T(`Pause a rolling update.`)
+)
+
+func NewCmdRollingUpdatePause(f *util.Factory, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "pause [CLUSTER]",
+		Short:   rollingUpdatePauseShort,
+		Long:    rollingUpdatePauseLong,
+		Example: rollingUpdatePauseExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			if err := RunRollingUpdatePause(ctx, f, out, rootCommand.ClusterName()); err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	return cmd
+}
+
+func RunRollingUpdatePause(ctx context.Context, f *util.Factory, out io.Writer, clusterName string) error {
+	store, progress, err := loadRollingUpdateProgress(ctx, f, clusterName)
+	if err != nil {
+		return err
+	}
+
+	if progress.Phase != instancegroups.ProgressPhaseRunning {
+		return fmt.Errorf("rolling update of cluster %q is not running (%s)", clusterName, progress.Phase)
+	}
+
+	progress.PauseRequested = true
+	if err := store.Save(progress); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Requested the rolling update of cluster %q to pause before draining the next instance.\n", clusterName)
+	return nil
+}
+
+// loadRollingUpdateProgress returns the checkpoint of the cluster's last rolling update
+func loadRollingUpdateProgress(ctx context.Context, f *util.Factory, clusterName string) (instancegroups.ProgressStore, *instancegroups.Progress, error) {
+	if clusterName == "" {
+		return nil, nil, fmt.Errorf("--name is required")
+	}
+
+	cluster, err := GetCluster(ctx, f, clusterName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	store, err := instancegroups.NewProgressStore(cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	progress, err := store.Load()
+	if err != nil {
+		return nil, nil, err
+	}
+	if progress == nil {
+		return nil, nil, fmt.Errorf("no rolling update has been recorded for cluster %q", clusterName)
+	}
+
+	return store, progress, nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/instancegroups"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	rollingUpdateResumeLong = templates.LongDesc(i18n.T(`
+	Resume a paused or interrupted rolling update of a cluster.
+
+	The rolling update continues with the options it was started with. Instances which were
+	replaced before it stopped are not rolled again, even if it was started with --force.`))
+
+	rollingUpdateResumeExample = templates.Examples(i18n.T(`
+	# Preview the remainder of a paused rolling update
+	kops rolling-update resume k8s-cluster.example.com
+
+	# Resume the rolling update
+	kops rolling-update resume k8s-cluster.example.com --yes
+	`))
+
+	rollingUpdateResumeShort = i18n.T(`Resume a rolling update.`)
+)
+
+func NewCmdRollingUpdateResume(f *util.Factory, out io.Writer) *cobra.Command {
+	yes := false
+
+	cmd := &cobra.Command{
+		Use:     "resume [CLUSTER]",
+		Short:   rollingUpdateResumeShort,
+		Long:    rollingUpdateResumeLong,
+		Example: rollingUpdateResumeExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			if err := RunRollingUpdateResume(ctx, f, out, rootCommand.ClusterName(), yes); err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", yes, "Resume the rolling update, without --yes resume executes a dry-run")
+
+	return cmd
+}
+
+func RunRollingUpdateResume(ctx context.Context, f *util.Factory, out io.Writer, clusterName string, yes bool) error {
+	_, progress, err := loadRollingUpdateProgress(ctx, f, clusterName)
+	if err != nil {
+		return err
+	}
+
+	switch progress.Phase {
+	case instancegroups.ProgressPhaseCompleted:
+		return fmt.Errorf("rolling update of cluster %q has already completed", clusterName)
+	case instancegroups.ProgressPhaseRunning:
+		klog.Warningf("rolling update of cluster %q was last recorded as running; make sure it is no longer in progress", clusterName)
+	}
+
+	var options RollingUpdateOptions
+	options.InitDefaults()
+	if len(progress.Options) != 0 {
+		if err := json.Unmarshal(progress.Options, &options); err != nil {
+			return fmt.Errorf("error parsing recorded rolling update options: %v", err)
+		}
+	}
+	options.ClusterName = clusterName
+	options.Yes = yes
+	options.resume = true
+	// The canary has already been replaced; continue with the remaining instances
+	options.Canary = 0
+
+	return RunRollingUpdateCluster(ctx, f, out, &options)
+}
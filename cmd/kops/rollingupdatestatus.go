@@ -0,0 +1,144 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/instancegroups"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	rollingUpdateStatusLong = templates.LongDesc(i18n.T(`
+	Display the progress of the last rolling update of a cluster, as recorded in the state store.`))
+
+	rollingUpdateStatusExample = templates.Examples(i18n.T(`
+	# Display the progress of the rolling update of a cluster
+	kops rolling-update status k8s-cluster.example.com
+	`))
+
+	rollingUpdateStatusShort = i18n.T(`Display the progress of a rolling update.`)
+)
+
+func NewCmdRollingUpdateStatus(f *util.Factory, out io.Writer) *cobra.Command {
+	output := OutputTable
+
+	cmd := &cobra.Command{
+		Use:     "status [CLUSTER]",
+		Short:   rollingUpdateStatusShort,
+		Long:    rollingUpdateStatusLong,
+		Example: rollingUpdateStatusExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			if err := RunRollingUpdateStatus(ctx, f, out, rootCommand.ClusterName(), output); err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", output, "Output format. One of json|yaml|table.")
+
+	return cmd
+}
+
+func RunRollingUpdateStatus(ctx context.Context, f *util.Factory, out io.Writer, clusterName string, output string) error {
+	_, progress, err := loadRollingUpdateProgress(ctx, f, clusterName)
+	if err != nil {
+		return err
+	}
+
+	switch output {
+	case OutputTable:
+		return rollingUpdateStatusOutputTable(progress, out)
+	case OutputYaml:
+		y, err := yaml.Marshal(progress)
+		if err != nil {
+			return fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	case OutputJSON:
+		j, err := json.MarshalIndent(progress, "", "  ")
+		if err != nil {
+			return fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(j); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	default:
+		return fmt.Errorf("unknown output format: %q", output)
+	}
+
+	return nil
+}
+
+type rollingUpdateStatusRow struct {
+	Name string
+	*instancegroups.InstanceGroupProgress
+}
+
+func rollingUpdateStatusOutputTable(progress *instancegroups.Progress, out io.Writer) error {
+	phase := string(progress.Phase)
+	if progress.PauseRequested {
+		phase += " (pause requested)"
+	}
+	fmt.Fprintf(out, "Phase:\t%s\n", phase)
+	fmt.Fprintf(out, "Started:\t%s\n", progress.StartedAt.Local().Format(time.RFC1123))
+	fmt.Fprintf(out, "Updated:\t%s\n", progress.UpdatedAt.Local().Format(time.RFC1123))
+	if progress.Error != "" {
+		fmt.Fprintf(out, "Error:\t%s\n", progress.Error)
+	}
+	fmt.Fprintf(out, "\n")
+
+	var rows []*rollingUpdateStatusRow
+	for name, ig := range progress.InstanceGroups {
+		rows = append(rows, &rollingUpdateStatusRow{Name: name, InstanceGroupProgress: ig})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].Name < rows[j].Name
+	})
+
+	t := &tables.Table{}
+	t.AddColumn("NAME", func(r *rollingUpdateStatusRow) string {
+		return r.Name
+	})
+	t.AddColumn("REPLACED", func(r *rollingUpdateStatusRow) string {
+		return strconv.Itoa(len(r.Replaced))
+	})
+	t.AddColumn("PENDING", func(r *rollingUpdateStatusRow) string {
+		return strconv.Itoa(len(r.Pending))
+	})
+	return t.Render(rows, out, "NAME", "REPLACED", "PENDING")
+}
@@ -25,6 +25,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -36,6 +37,8 @@ import (
 	kopsapi "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/pkg/commands"
+	"k8s.io/kops/pkg/logformat"
+	"k8s.io/kops/pkg/tracing"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
 )
@@ -91,10 +94,37 @@ var rootCommand = RootCmd{
 	},
 }
 
+// finishTracing ends the root span and exports any buffered spans; it is
+// called before exiting, including when a command exits early with an error.
+var finishTracing = func(err error) {}
+
 func Execute() {
 	goflag.Set("logtostderr", "true")
 	goflag.CommandLine.Parse([]string{})
-	if err := rootCommand.cobraCommand.Execute(); err != nil {
+
+	shutdownTracing, err := tracing.Init("kops")
+	if err != nil {
+		klog.Warningf("tracing disabled: %v", err)
+		shutdownTracing = func() {}
+	}
+
+	spanName := rootCommand.cobraCommand.Name()
+	if cmd, _, err := rootCommand.cobraCommand.Find(os.Args[1:]); err == nil {
+		spanName = cmd.CommandPath()
+	}
+	ctx, span := tracing.StartSpan(context.Background(), spanName)
+
+	var once sync.Once
+	finishTracing = func(err error) {
+		once.Do(func() {
+			tracing.EndSpan(span, err)
+			shutdownTracing()
+		})
+	}
+
+	err = rootCommand.cobraCommand.ExecuteContext(ctx)
+	finishTracing(err)
+	if err != nil {
 		exitWithError(err)
 	}
 }
@@ -103,6 +133,7 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	klog.InitFlags(nil)
+	logformat.InitFlags(nil)
 
 	factory := util.NewFactory(&rootCommand.FactoryOptions)
 	rootCommand.factory = factory
@@ -138,9 +169,11 @@ func NewCmdRoot(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.PersistentFlags().StringVarP(&rootCommand.clusterName, "name", "", defaultClusterName, "Name of cluster. Overrides KOPS_CLUSTER_NAME environment variable")
 
 	// create subcommands
+	cmd.AddCommand(NewCmdApply(f, out))
 	cmd.AddCommand(NewCmdCompletion(f, out))
 	cmd.AddCommand(NewCmdCreate(f, out))
 	cmd.AddCommand(NewCmdDelete(f, out))
+	cmd.AddCommand(NewCmdDiff(f, out))
 	cmd.AddCommand(NewCmdEdit(f, out))
 	cmd.AddCommand(NewCmdExport(f, out))
 	cmd.AddCommand(NewCmdGet(f, out))
@@ -149,7 +182,9 @@ func NewCmdRoot(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.AddCommand(NewCmdReplace(f, out))
 	cmd.AddCommand(NewCmdRollingUpdate(f, out))
 	cmd.AddCommand(NewCmdSet(f, out))
+	cmd.AddCommand(NewCmdSSH(f, out))
 	cmd.AddCommand(NewCmdToolbox(f, out))
+	cmd.AddCommand(NewCmdUnlock(f, out))
 	cmd.AddCommand(NewCmdValidate(f, out))
 	cmd.AddCommand(NewCmdVersion(f, out))
 
@@ -158,6 +193,10 @@ func NewCmdRoot(f *util.Factory, out io.Writer) *cobra.Command {
 
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
+	if err := logformat.Apply(); err != nil {
+		exitWithError(err)
+	}
+
 	// Config file precedence: --config flag, ${HOME}/.kops.yaml ${HOME}/.kops/config
 	configFile := rootCommand.configFile
 	if configFile == "" {
@@ -231,11 +270,13 @@ func (c *RootCmd) ProcessArgs(args []string) error {
 }
 
 func (c *RootCmd) ClusterName() string {
-	if c.clusterName != "" {
-		return c.clusterName
+	if c.clusterName == "" {
+		c.clusterName = ClusterNameFromKubecfg()
 	}
 
-	c.clusterName = ClusterNameFromKubecfg()
+	if c.clusterName != "" {
+		logformat.WithValues("cluster", c.clusterName)
+	}
 
 	return c.clusterName
 }
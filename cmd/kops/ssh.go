@@ -0,0 +1,328 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/util/homedir"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+type SSHOptions struct {
+	ClusterName string
+	// Target is the instance group, node name, instance ID or internal IP of the instance
+	Target string
+	// OSUser is the user to log in as
+	OSUser string
+	// SSHKey is the private key used with SSH instance access; by default it is
+	// the key under ~/.ssh matching the SSH public key of the cluster
+	SSHKey string
+}
+
+var (
+	sshLong = templates.LongDesc(i18n.T(`
+	Open a shell on an instance of the cluster.
+
+	The instance is selected by node name, instance ID or internal IP, or by instance
+	group name, in which case the first instance of the group is used.
+
+	With SSH instance access, ssh connects to the public IP of the instance, or to its
+	internal IP through the bastion if the cluster has one, using the private key under
+	~/.ssh which matches the SSH public key of the cluster.
+
+	With SSM or EC2InstanceConnect instance access, the session is opened by the AWS CLI,
+	which must be installed together with the Session Manager plugin for SSM, or be at
+	least version 2.12 for EC2 Instance Connect.`))
+
+	sshExample = templates.Examples(i18n.T(`
+	# Open a shell on a node.
+	kops ssh ip-172-20-32-10.ec2.internal --name k8s-cluster.example.com
+
+	# Open a shell on the first instance of the nodes instance group.
+	kops ssh nodes-us-test-1a
+
+	# Open a shell on an instance as the ec2-user user, with a specific key.
+	kops ssh i-0a5ed581b862d3425 --os-user ec2-user --ssh-key ~/.ssh/kops
+	`))
+
+	sshShort = i18n.T(`Open a shell on an instance of the cluster.`)
+)
+
+func NewCmdSSH(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &SSHOptions{
+		OSUser: "ubuntu",
+	}
+
+	cmd := &cobra.Command{
+		Use:     "ssh",
+		Short:   sshShort,
+		Long:    sshLong,
+		Example: sshExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			options.ClusterName = rootCommand.ClusterName()
+			if options.ClusterName == "" {
+				exitWithError(fmt.Errorf("--name is required"))
+			}
+			if len(args) != 1 {
+				exitWithError(fmt.Errorf("specify the instance group, node name, instance ID or internal IP of the instance"))
+			}
+			options.Target = args[0]
+
+			if err := RunSSH(ctx, f, out, options); err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.OSUser, "os-user", options.OSUser, "User to log in as")
+	cmd.Flags().StringVar(&options.SSHKey, "ssh-key", options.SSHKey, "Private key to use with SSH instance access (defaults to the key under ~/.ssh matching the cluster's SSH public key)")
+
+	return cmd
+}
+
+func RunSSH(ctx context.Context, f *util.Factory, out io.Writer, options *SSHOptions) error {
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	if kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
+		return fmt.Errorf("kops ssh is only supported on AWS")
+	}
+
+	cloudInstances, err := getCloudInstances(ctx, clientset, cluster)
+	if err != nil {
+		return err
+	}
+
+	instance := findSSHTarget(cloudInstances, options.Target)
+	if instance == nil {
+		return fmt.Errorf("instance %q not found in cluster %q", options.Target, cluster.ObjectMeta.Name)
+	}
+
+	access := cluster.Spec.Topology.GetInstanceAccessType()
+	if access != kops.InstanceAccessTypeSSH {
+		region, err := awsup.FindRegion(cluster)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "Opening a session on %s with %s\n", instance.ID, access)
+		return runInteractive("aws", sshSessionArgs(access, region, instance.ID, options.OSUser))
+	}
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return err
+	}
+	publicIPs, err := findPublicIPs(cloud.(awsup.AWSCloud), cloudInstances)
+	if err != nil {
+		return err
+	}
+
+	host := publicIPs[instance.ID]
+	bastion := ""
+	if cluster.Spec.Topology != nil && cluster.Spec.Topology.Bastion != nil {
+		host = instance.PrivateIP
+		bastion = cluster.Spec.Topology.Bastion.BastionPublicName
+		if bastion == "" {
+			bastion = publicIPs[findBastionID(cloudInstances)]
+		}
+		if bastion == "" {
+			return fmt.Errorf("cannot find a public address of the bastion of cluster %q", cluster.ObjectMeta.Name)
+		}
+	}
+	if host == "" {
+		host = instance.PrivateIP
+	}
+
+	key := options.SSHKey
+	if key == "" {
+		sshCredentialStore, err := clientset.SSHCredentialStore(cluster)
+		if err != nil {
+			return err
+		}
+		credentials, err := sshCredentialStore.FindSSHPublicKeys(fi.SecretNameSSHPrimary)
+		if err != nil {
+			return err
+		}
+		var publicKeys []string
+		for _, credential := range credentials {
+			publicKeys = append(publicKeys, credential.Spec.PublicKey)
+		}
+		key = findSSHPrivateKey(filepath.Join(homedir.HomeDir(), ".ssh"), publicKeys)
+	}
+
+	if bastion != "" {
+		fmt.Fprintf(out, "Opening a session on %s through bastion %s\n", host, bastion)
+	} else {
+		fmt.Fprintf(out, "Opening a session on %s\n", host)
+	}
+	return runInteractive("ssh", sshArgs(options.OSUser, host, key, bastion))
+}
+
+func runInteractive(name string, args []string) error {
+	c := exec.Command(name, args...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("error running %s %v: %v", name, args, err)
+	}
+	return nil
+}
+
+// findSSHTarget returns the instance with the given node name, instance ID or internal IP,
+// or the first instance of the instance group with the given name
+func findSSHTarget(cloudInstances []*cloudinstances.CloudInstance, target string) *cloudinstances.CloudInstance {
+	var groupInstances []*cloudinstances.CloudInstance
+	for _, instance := range cloudInstances {
+		if instance.ID == target || instance.PrivateIP == target {
+			return instance
+		}
+		if instance.Node != nil && instance.Node.Name == target {
+			return instance
+		}
+		if ig := instance.CloudInstanceGroup.InstanceGroup; ig != nil && ig.ObjectMeta.Name == target {
+			groupInstances = append(groupInstances, instance)
+		}
+	}
+	if len(groupInstances) == 0 {
+		return nil
+	}
+	sort.Slice(groupInstances, func(i, j int) bool {
+		return groupInstances[i].ID < groupInstances[j].ID
+	})
+	return groupInstances[0]
+}
+
+// findBastionID returns the ID of an instance of a bastion instance group
+func findBastionID(cloudInstances []*cloudinstances.CloudInstance) string {
+	for _, instance := range cloudInstances {
+		if ig := instance.CloudInstanceGroup.InstanceGroup; ig != nil && ig.Spec.Role == kops.InstanceGroupRoleBastion {
+			return instance.ID
+		}
+	}
+	return ""
+}
+
+// findPublicIPs maps the IDs of the instances to their public IP, for those which have one
+func findPublicIPs(cloud awsup.AWSCloud, cloudInstances []*cloudinstances.CloudInstance) (map[string]string, error) {
+	request := &ec2.DescribeInstancesInput{}
+	for _, instance := range cloudInstances {
+		request.InstanceIds = append(request.InstanceIds, aws.String(instance.ID))
+	}
+
+	publicIPs := make(map[string]string)
+	err := cloud.EC2().DescribeInstancesPages(request, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				if ip := aws.StringValue(instance.PublicIpAddress); ip != "" {
+					publicIPs[aws.StringValue(instance.InstanceId)] = ip
+				}
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error describing instances: %v", err)
+	}
+	return publicIPs, nil
+}
+
+// findSSHPrivateKey returns the private key in dir whose public key is one of publicKeys,
+// or "" to leave the choice of key to ssh
+func findSSHPrivateKey(dir string, publicKeys []string) string {
+	wanted := make(map[string]bool)
+	for _, publicKey := range publicKeys {
+		if fields := strings.Fields(publicKey); len(fields) >= 2 {
+			wanted[fields[1]] = true
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.pub"))
+	if err != nil {
+		return ""
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		fields := strings.Fields(string(data))
+		if len(fields) < 2 || !wanted[fields[1]] {
+			continue
+		}
+		privateKey := strings.TrimSuffix(file, ".pub")
+		if _, err := os.Stat(privateKey); err == nil {
+			return privateKey
+		}
+	}
+	return ""
+}
+
+// sshArgs returns the ssh arguments opening a session on host, through the bastion if it is set
+func sshArgs(user string, host string, key string, bastion string) []string {
+	var args []string
+	if key != "" {
+		args = append(args, "-i", key)
+	}
+	if bastion != "" {
+		// ProxyJump would not pass the key on to the bastion connection
+		proxy := "ssh -W %h:%p"
+		if key != "" {
+			proxy += " -i " + key
+		}
+		args = append(args, "-o", "ProxyCommand="+proxy+" "+user+"@"+bastion)
+	}
+	return append(args, user+"@"+host)
+}
+
+// sshSessionArgs returns the AWS CLI arguments opening an interactive session on the instance
+func sshSessionArgs(access kops.InstanceAccessType, region string, instanceID string, osUser string) []string {
+	if access == kops.InstanceAccessTypeEC2InstanceConnect {
+		return []string{"ec2-instance-connect", "ssh", "--region", region, "--instance-id", instanceID, "--os-user", osUser}
+	}
+	return []string{"ssm", "start-session", "--region", region, "--target", instanceID}
+}
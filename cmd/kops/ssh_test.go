@@ -0,0 +1,105 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+func TestFindSSHTarget(t *testing.T) {
+	nodes := &cloudinstances.CloudInstanceGroup{
+		InstanceGroup: &kops.InstanceGroup{ObjectMeta: metav1.ObjectMeta{Name: "nodes"}},
+	}
+	instances := []*cloudinstances.CloudInstance{
+		{ID: "i-2", PrivateIP: "172.20.32.11", CloudInstanceGroup: nodes},
+		{ID: "i-1", PrivateIP: "172.20.32.10", CloudInstanceGroup: nodes, Node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "ip-172-20-32-10.ec2.internal"}}},
+	}
+
+	grid := map[string]string{
+		"i-2":                          "i-2",
+		"172.20.32.11":                 "i-2",
+		"ip-172-20-32-10.ec2.internal": "i-1",
+		"nodes":                        "i-1",
+		"masters":                      "",
+	}
+	for target, expected := range grid {
+		actual := ""
+		if instance := findSSHTarget(instances, target); instance != nil {
+			actual = instance.ID
+		}
+		if actual != expected {
+			t.Errorf("target %q: expected %q, got %q", target, expected, actual)
+		}
+	}
+}
+
+func TestSSHArgs(t *testing.T) {
+	actual := sshArgs("ubuntu", "172.20.32.10", "/home/user/.ssh/id_rsa", "bastion.example.com")
+	expected := []string{
+		"-i", "/home/user/.ssh/id_rsa",
+		"-o", "ProxyCommand=ssh -W %h:%p -i /home/user/.ssh/id_rsa ubuntu@bastion.example.com",
+		"ubuntu@172.20.32.10",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	actual = sshArgs("ubuntu", "203.0.113.10", "", "")
+	expected = []string{"ubuntu@203.0.113.10"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestFindSSHPrivateKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ssh")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"id_rsa":      "private",
+		"id_rsa.pub":  "ssh-rsa AAAAother user@host\n",
+		"kops":        "private",
+		"kops.pub":    "ssh-rsa AAAAcluster user@host\n",
+		"orphan.pub":  "ssh-rsa AAAAorphan user@host\n",
+		"unrelated":   "private",
+		"known_hosts": "",
+	}
+	for name, contents := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0600); err != nil {
+			t.Fatalf("error writing %s: %v", name, err)
+		}
+	}
+
+	if actual := findSSHPrivateKey(dir, []string{"ssh-rsa AAAAcluster admin"}); actual != filepath.Join(dir, "kops") {
+		t.Errorf("expected the kops key, got %q", actual)
+	}
+	if actual := findSSHPrivateKey(dir, []string{"ssh-rsa AAAAorphan admin"}); actual != "" {
+		t.Errorf("expected no key without a private key, got %q", actual)
+	}
+}
@@ -45,8 +45,20 @@ func NewCmdToolbox(f *util.Factory, out io.Writer) *cobra.Command {
 		Example: toolboxExample,
 	}
 
+	cmd.AddCommand(NewCmdToolboxAddons(f, out))
+	cmd.AddCommand(NewCmdToolboxBundle(f, out))
+	cmd.AddCommand(NewCmdToolboxCISReport(f, out))
 	cmd.AddCommand(NewCmdToolboxConvertImported(f, out))
 	cmd.AddCommand(NewCmdToolboxDump(f, out))
+	cmd.AddCommand(NewCmdToolboxBootstrap(f, out))
+	cmd.AddCommand(NewCmdToolboxEnroll(f, out))
+	cmd.AddCommand(NewCmdToolboxEtcdBackup(f, out))
+	cmd.AddCommand(NewCmdToolboxEtcdRestore(f, out))
+	cmd.AddCommand(NewCmdToolboxExportCAPI(f, out))
+	cmd.AddCommand(NewCmdToolboxJoinToken(f, out))
+	cmd.AddCommand(NewCmdToolboxMigrateState(f, out))
+	cmd.AddCommand(NewCmdToolboxMigrateToEKS(f, out))
+	cmd.AddCommand(NewCmdToolboxReencrypt(f, out))
 	cmd.AddCommand(NewCmdToolboxTemplate(f, out))
 	cmd.AddCommand(NewCmdToolboxInstanceSelector(f, out))
 
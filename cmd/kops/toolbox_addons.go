@@ -0,0 +1,67 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxAddonsLong = templates.LongDesc(i18n.T(`
+	Author addons channels.
+
+	An addons channel is a directory with an addons.yaml file listing the addons, and the
+	manifests of the addons. These commands check a channel, keep the manifestHash of its
+	addons in sync with their manifests, and publish it.`))
+
+	toolboxAddonsExample = templates.Examples(i18n.T(`
+	# Check a channel, update the hashes of its manifests and publish it to S3.
+	kops toolbox addons lint ./channel
+	kops toolbox addons package ./channel
+	kops toolbox addons publish ./channel --destination s3://my-channels/stable
+	`))
+
+	toolboxAddonsShort = i18n.T(`Author addons channels`)
+)
+
+func NewCmdToolboxAddons(f *util.Factory, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "addons",
+		Short:   toolboxAddonsShort,
+		Long:    toolboxAddonsLong,
+		Example: toolboxAddonsExample,
+	}
+
+	cmd.AddCommand(NewCmdToolboxAddonsLint(f, out))
+	cmd.AddCommand(NewCmdToolboxAddonsPackage(f, out))
+	cmd.AddCommand(NewCmdToolboxAddonsPublish(f, out))
+
+	return cmd
+}
+
+// channelPath returns the channel given as argument, defaulting to the current directory
+func channelPath(args []string) string {
+	if len(args) == 0 {
+		return "."
+	}
+	return args[0]
+}
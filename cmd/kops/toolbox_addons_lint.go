@@ -0,0 +1,137 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/addonchannel"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	toolboxAddonsLintLong = templates.LongDesc(i18n.T(`
+	Check an addons channel for problems.
+
+	The versions must be semver versions, the kubernetesVersion ranges must be semver ranges,
+	an addon can't be listed twice with the same id for the same kubernetesVersion, and the
+	manifestHash of each addon must match its manifest. The command fails if any error is found.`))
+
+	toolboxAddonsLintExample = templates.Examples(i18n.T(`
+	# Check the channel in the current directory.
+	kops toolbox addons lint
+
+	# Check a channel, printing the findings as JSON.
+	kops toolbox addons lint ./channel/addons.yaml -o json
+	`))
+
+	toolboxAddonsLintShort = i18n.T(`Check an addons channel for problems`)
+)
+
+type ToolboxAddonsLintOptions struct {
+	// Channel is the channel directory, or its addons.yaml file
+	Channel string
+
+	// Output is the format of the findings: table, yaml or json
+	Output string
+}
+
+func NewCmdToolboxAddonsLint(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxAddonsLintOptions{
+		Output: OutputTable,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "lint [CHANNEL]",
+		Short:   toolboxAddonsLintShort,
+		Long:    toolboxAddonsLintLong,
+		Example: toolboxAddonsLintExample,
+		Args:    cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Channel = channelPath(args)
+
+			err := RunToolboxAddonsLint(f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Output, "output", "o", options.Output, "Output format. One of table, yaml or json")
+
+	return cmd
+}
+
+func RunToolboxAddonsLint(f *util.Factory, out io.Writer, options *ToolboxAddonsLintOptions) error {
+	channel, err := addonchannel.Load(options.Channel)
+	if err != nil {
+		return err
+	}
+
+	findings := channel.Lint()
+
+	switch options.Output {
+	case OutputTable:
+		if len(findings) == 0 {
+			fmt.Fprintf(out, "No problems found\n")
+			return nil
+		}
+		t := &tables.Table{}
+		t.AddColumn("SEVERITY", func(finding *addonchannel.Finding) string {
+			return string(finding.Severity)
+		})
+		t.AddColumn("ADDON", func(finding *addonchannel.Finding) string {
+			return finding.Addon
+		})
+		t.AddColumn("MESSAGE", func(finding *addonchannel.Finding) string {
+			return finding.Message
+		})
+		if err := t.Render(findings, out, "SEVERITY", "ADDON", "MESSAGE"); err != nil {
+			return err
+		}
+	case OutputYaml:
+		y, err := yaml.Marshal(findings)
+		if err != nil {
+			return fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	case OutputJSON:
+		j, err := json.Marshal(findings)
+		if err != nil {
+			return fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(append(j, '\n')); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	default:
+		return fmt.Errorf("unknown output format: %q", options.Output)
+	}
+
+	if addonchannel.HasErrors(findings) {
+		return fmt.Errorf("channel %s has errors", options.Channel)
+	}
+	return nil
+}
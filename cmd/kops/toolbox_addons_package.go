@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/addonchannel"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxAddonsPackageLong = templates.LongDesc(i18n.T(`
+	Update the manifest hashes of an addons channel.
+
+	The manifestHash of each addon whose manifest is part of the channel is set to the hash
+	of the manifest, and addons.yaml is rewritten. With --check, addons.yaml is left alone and
+	the command fails if any hash is out of date, which is useful in CI.`))
+
+	toolboxAddonsPackageExample = templates.Examples(i18n.T(`
+	# Update the manifest hashes of a channel.
+	kops toolbox addons package ./channel
+
+	# Fail if any manifest hash is out of date.
+	kops toolbox addons package ./channel --check
+	`))
+
+	toolboxAddonsPackageShort = i18n.T(`Update the manifest hashes of an addons channel`)
+)
+
+type ToolboxAddonsPackageOptions struct {
+	// Channel is the channel directory, or its addons.yaml file
+	Channel string
+
+	// Check reports out of date hashes instead of updating them
+	Check bool
+}
+
+func NewCmdToolboxAddonsPackage(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxAddonsPackageOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "package [CHANNEL]",
+		Short:   toolboxAddonsPackageShort,
+		Long:    toolboxAddonsPackageLong,
+		Example: toolboxAddonsPackageExample,
+		Args:    cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Channel = channelPath(args)
+
+			err := RunToolboxAddonsPackage(f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&options.Check, "check", options.Check, "Fail if any manifest hash is out of date, without updating them")
+
+	return cmd
+}
+
+func RunToolboxAddonsPackage(f *util.Factory, out io.Writer, options *ToolboxAddonsPackageOptions) error {
+	channel, err := addonchannel.Load(options.Channel)
+	if err != nil {
+		return err
+	}
+
+	changed, err := channel.UpdateHashes()
+	if err != nil {
+		return err
+	}
+	if len(changed) == 0 {
+		fmt.Fprintf(out, "All manifest hashes are up to date\n")
+		return nil
+	}
+
+	if options.Check {
+		for _, name := range changed {
+			fmt.Fprintf(out, "The manifest hash of %s is out of date\n", name)
+		}
+		return fmt.Errorf("%d manifest hashes are out of date; run kops toolbox addons package to update them", len(changed))
+	}
+
+	if err := channel.Write(); err != nil {
+		return err
+	}
+	for _, name := range changed {
+		fmt.Fprintf(out, "Updated the manifest hash of %s\n", name)
+	}
+	return nil
+}
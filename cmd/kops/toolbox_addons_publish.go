@@ -0,0 +1,142 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/addonchannel"
+	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxAddonsPublishLong = templates.LongDesc(i18n.T(`
+	Publish an addons channel.
+
+	The manifest hashes of the channel are updated and the channel is checked before it is
+	published; the command fails if the channel has errors.
+
+	The destination is a VFS location such as s3:// or gs://, or an OCI repository prefixed
+	with oci://. Updates are atomic: on VFS locations the manifests are written under a
+	directory named after the revision of the channel before addons.yaml is replaced, and OCI
+	tags are only moved once all the layers of the channel are pushed. OCI registries are
+	authenticated with the credentials of the docker configuration file.`))
+
+	toolboxAddonsPublishExample = templates.Examples(i18n.T(`
+	# Publish a channel to S3.
+	kops toolbox addons publish ./channel --destination s3://my-channels/stable
+
+	# Publish a channel to an OCI registry.
+	kops toolbox addons publish ./channel --destination oci://registry.example.com/kops/channels:stable
+	`))
+
+	toolboxAddonsPublishShort = i18n.T(`Publish an addons channel`)
+)
+
+type ToolboxAddonsPublishOptions struct {
+	// Channel is the channel directory, or its addons.yaml file
+	Channel string
+
+	// Destination is the VFS location or oci:// reference the channel is published to
+	Destination string
+}
+
+func NewCmdToolboxAddonsPublish(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxAddonsPublishOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "publish [CHANNEL]",
+		Short:   toolboxAddonsPublishShort,
+		Long:    toolboxAddonsPublishLong,
+		Example: toolboxAddonsPublishExample,
+		Args:    cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			options.Channel = channelPath(args)
+
+			err := RunToolboxAddonsPublish(ctx, f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Destination, "destination", options.Destination, "Location to publish the channel to, such as s3://bucket/path or oci://registry/repository:tag")
+
+	return cmd
+}
+
+func RunToolboxAddonsPublish(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxAddonsPublishOptions) error {
+	if options.Destination == "" {
+		return fmt.Errorf("--destination is required")
+	}
+
+	channel, err := addonchannel.Load(options.Channel)
+	if err != nil {
+		return err
+	}
+	if _, err := channel.UpdateHashes(); err != nil {
+		return err
+	}
+	findings := channel.Lint()
+	for _, finding := range findings {
+		fmt.Fprintf(out, "%s %s: %s\n", finding.Severity, finding.Addon, finding.Message)
+	}
+	if addonchannel.HasErrors(findings) {
+		return fmt.Errorf("channel %s has errors", options.Channel)
+	}
+
+	if strings.HasPrefix(options.Destination, "oci://") {
+		ref, err := addonchannel.ParseOCIReference(options.Destination)
+		if err != nil {
+			return err
+		}
+		username, password, err := addonchannel.DockerCredentials(ref.Registry)
+		if err != nil {
+			return err
+		}
+		publisher := &addonchannel.OCIPublisher{
+			Username: username,
+			Password: password,
+		}
+		digest, err := channel.PublishOCI(ctx, publisher, ref)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Published %s@%s\n", ref, digest)
+		return nil
+	}
+
+	dest, err := vfs.Context.BuildVfsPath(options.Destination)
+	if err != nil {
+		return fmt.Errorf("error building destination path: %v", err)
+	}
+	revision, err := channel.Publish(dest)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Published revision %s to %s\n", revision, dest)
+	return nil
+}
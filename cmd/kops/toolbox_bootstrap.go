@@ -0,0 +1,281 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/pkg/sshbootstrap"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/kutil"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxBootstrapLong = templates.LongDesc(i18n.T(`
+	Bootstrap the new instances of an instance group that uses SSH delivery of the bootstrap script.
+
+	Instances of such instance groups are created without user-data. kOps connects to each instance
+	of the instance group over SSH, using its private IP address, and runs the bootstrap script of the
+	instance group, which installs nodeup and joins the instance to the cluster. Instances that have
+	already been bootstrapped are skipped; run this again after the instance group has scaled up or
+	been rolled.
+
+	The host keys of the instances are verified against a known_hosts file, because the bootstrap
+	script carries the credentials to join the cluster. Without --yes, the instances are only listed,
+	without connecting to them.
+
+	Bootstrapping refuses to run while the cluster has pending changes; apply them first with
+	kops update cluster.`))
+
+	toolboxBootstrapExample = templates.Examples(i18n.T(`
+	# Preview bootstrapping the new instances of the nodes instance group.
+	kops toolbox bootstrap --name k8s-cluster.example.com --instance-group nodes
+
+	# Bootstrap them, connecting with a specific SSH key.
+	kops toolbox bootstrap --name k8s-cluster.example.com --instance-group nodes \
+	  --private-key ~/.ssh/kops --yes
+
+	# Bootstrap them, verifying their host keys against a specific known_hosts file.
+	kops toolbox bootstrap --name k8s-cluster.example.com --instance-group nodes \
+	  --known-hosts ~/.ssh/k8s-cluster_known_hosts --yes
+	`))
+
+	toolboxBootstrapShort = i18n.T(`Run the bootstrap script on new instances over SSH`)
+)
+
+type ToolboxBootstrapOptions struct {
+	ClusterName   string
+	InstanceGroup string
+
+	PrivateKey string
+	// KnownHosts is the known_hosts file the host keys of instances are verified against
+	KnownHosts string
+	// SSHUser overrides spec.bootstrap.sshUser of the instance group
+	SSHUser string
+
+	Yes bool
+}
+
+func NewCmdToolboxBootstrap(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxBootstrapOptions{
+		PrivateKey: "~/.ssh/id_rsa",
+		KnownHosts: "~/.ssh/known_hosts",
+	}
+
+	cmd := &cobra.Command{
+		Use:     "bootstrap",
+		Short:   toolboxBootstrapShort,
+		Long:    toolboxBootstrapLong,
+		Example: toolboxBootstrapExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName()
+
+			err := RunToolboxBootstrap(ctx, f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.InstanceGroup, "instance-group", options.InstanceGroup, "Name of the instance group whose instances to bootstrap")
+	cmd.Flags().StringVar(&options.PrivateKey, "private-key", options.PrivateKey, "Private key to use for SSH access to instances")
+	cmd.Flags().StringVar(&options.KnownHosts, "known-hosts", options.KnownHosts, "known_hosts file to verify the SSH host keys of instances against")
+	cmd.Flags().StringVar(&options.SSHUser, "ssh-user", options.SSHUser, "The remote user for SSH access to instances (defaults to spec.bootstrap.sshUser of the instance group, or root)")
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Bootstrap the instances immediately, without --yes bootstrap executes a dry-run")
+
+	return cmd
+}
+
+func RunToolboxBootstrap(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxBootstrapOptions) error {
+	if options.ClusterName == "" {
+		return fmt.Errorf("--name is required")
+	}
+	if options.InstanceGroup == "" {
+		return fmt.Errorf("--instance-group is required")
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	ig, err := clientset.InstanceGroupsFor(cluster).Get(ctx, options.InstanceGroup, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error reading instancegroup %q: %v", options.InstanceGroup, err)
+	}
+	if ig == nil {
+		return fmt.Errorf("instancegroup %q not found", options.InstanceGroup)
+	}
+	if !ig.UsesSSHBootstrap() {
+		return fmt.Errorf("instancegroup %q does not use SSH delivery of the bootstrap script; set spec.bootstrap.delivery to %s", ig.ObjectMeta.Name, kops.BootstrapDeliverySSH)
+	}
+
+	sshUser := options.SSHUser
+	if sshUser == "" {
+		sshUser = ig.Spec.Bootstrap.SSHUser
+	}
+	if sshUser == "" {
+		sshUser = "root"
+	}
+	// A dry run only lists the instances, so the private key is only needed with --yes
+	var sshConfig *ssh.ClientConfig
+	if options.Yes {
+		sshConfig, err = buildBootstrapSSHConfig(options.PrivateKey, options.KnownHosts, sshUser)
+		if err != nil {
+			return err
+		}
+	}
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return err
+	}
+
+	// The bootstrap script refers to the configuration in the state store, so it is
+	// only valid once an update of the cluster would make no further changes
+	applyCmd, err := applyInstanceGroup(ctx, clientset, cloud, options.ClusterName, ig, cloudup.TargetDryRun)
+	if err != nil {
+		return err
+	}
+	changes, err := applyCmd.Target.(*fi.DryRunTarget).ResourceChanges(applyCmd.TaskMap)
+	if err != nil {
+		return err
+	}
+	if len(changes) != 0 {
+		var pending []string
+		for _, change := range changes {
+			pending = append(pending, change.Type+"/"+change.Name)
+		}
+		return fmt.Errorf("cluster %q has pending changes, apply them with kops update cluster before bootstrapping instances: %s", options.ClusterName, strings.Join(pending, ", "))
+	}
+
+	task, ok := applyCmd.TaskMap["BootstrapScript/"+ig.ObjectMeta.Name].(*model.BootstrapScript)
+	if !ok {
+		return fmt.Errorf("bootstrap script for instancegroup %q not found", ig.ObjectMeta.Name)
+	}
+	script, err := fi.ResourceAsString(task.Resource())
+	if err != nil {
+		return fmt.Errorf("error rendering bootstrap script: %v", err)
+	}
+
+	groups, err := cloud.GetCloudGroups(cluster, []*kops.InstanceGroup{ig}, false, nil)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, group := range groups {
+		if group.InstanceGroup == nil || group.InstanceGroup.ObjectMeta.Name != ig.ObjectMeta.Name {
+			continue
+		}
+		for _, instance := range append(group.Ready, group.NeedUpdate...) {
+			if err := bootstrapInstance(out, instance, sshConfig, sshUser, script, options.Yes); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if !options.Yes {
+		fmt.Fprintf(out, "\nMust specify --yes to bootstrap the instances.\n")
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// bootstrapInstance runs the bootstrap script on the instance, unless it has already been bootstrapped.
+// Without yes, it only reports the instance, without connecting to it.
+func bootstrapInstance(out io.Writer, instance *cloudinstances.CloudInstance, sshConfig *ssh.ClientConfig, sshUser string, script string, yes bool) error {
+	if instance.State == cloudinstances.WarmPool {
+		return nil
+	}
+	if instance.PrivateIP == "" {
+		return fmt.Errorf("instance %q has no private IP address", instance.ID)
+	}
+
+	if !yes {
+		fmt.Fprintf(out, "Will bootstrap instance %q at %s, unless it has already been bootstrapped\n", instance.ID, instance.PrivateIP)
+		return nil
+	}
+
+	client, err := sshbootstrap.Dial(instance.ID, instance.PrivateIP, sshConfig)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	hash, err := sshbootstrap.BootstrapHash(client, sshUser)
+	if err != nil {
+		return fmt.Errorf("error reading bootstrap state of instance %q: %v", instance.ID, err)
+	}
+	if hash != "" {
+		fmt.Fprintf(out, "Instance %q at %s has already been bootstrapped\n", instance.ID, instance.PrivateIP)
+		return nil
+	}
+
+	if err := sshbootstrap.RunBootstrapScript(client, sshUser, script); err != nil {
+		return fmt.Errorf("error bootstrapping instance %q: %v", instance.ID, err)
+	}
+	fmt.Fprintf(out, "Bootstrapped instance %q at %s\n", instance.ID, instance.PrivateIP)
+	return nil
+}
+
+// buildBootstrapSSHConfig returns the SSH client configuration for connecting to instances as the user,
+// accepting only the host keys in the known_hosts file
+func buildBootstrapSSHConfig(privateKeyPath string, knownHostsPath string, user string) (*ssh.ClientConfig, error) {
+	sshConfig := &ssh.ClientConfig{
+		User:            user,
+		HostKeyCallback: sshbootstrap.HostKeyCallback("", expandHomePath(knownHostsPath)),
+	}
+	if err := kutil.AddSSHIdentity(sshConfig, expandHomePath(privateKeyPath)); err != nil {
+		return nil, err
+	}
+	return sshConfig, nil
+}
+
+func expandHomePath(p string) string {
+	if strings.HasPrefix(p, "~/") {
+		return filepath.Join(os.Getenv("HOME"), p[2:])
+	}
+	return p
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/kopscodecs"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/util/pkg/text"
+)
+
+func TestToolboxBootstrapRequiresSSHDelivery(t *testing.T) {
+	ctx := context.Background()
+
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+	h.SetupMockAWS()
+
+	factory := util.NewFactory(&util.FactoryOptions{RegistryPath: "memfs://tests"})
+	clientset, err := factory.Clientset()
+	if err != nil {
+		t.Fatalf("error getting clientset: %v", err)
+	}
+
+	contents, err := ioutil.ReadFile("../../tests/integration/create_cluster/minimal-1.21/expected-v1alpha2.yaml")
+	if err != nil {
+		t.Fatalf("error reading manifests: %v", err)
+	}
+	var cluster *kopsapi.Cluster
+	for _, section := range text.SplitContentToSections(contents) {
+		obj, _, err := kopscodecs.Decode(section, nil)
+		if err != nil {
+			t.Fatalf("error parsing manifest: %v", err)
+		}
+		switch v := obj.(type) {
+		case *kopsapi.Cluster:
+			if cluster, err = clientset.CreateCluster(ctx, v); err != nil {
+				t.Fatalf("error creating cluster: %v", err)
+			}
+		case *kopsapi.InstanceGroup:
+			if _, err := clientset.InstanceGroupsFor(cluster).Create(ctx, v, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("error creating instancegroup: %v", err)
+			}
+		}
+	}
+
+	grid := []struct {
+		options  ToolboxBootstrapOptions
+		expected string
+	}{
+		{
+			options:  ToolboxBootstrapOptions{ClusterName: "minimal.example.com"},
+			expected: "--instance-group is required",
+		},
+		{
+			options:  ToolboxBootstrapOptions{ClusterName: "minimal.example.com", InstanceGroup: "nodes-us-test-1a"},
+			expected: `instancegroup "nodes-us-test-1a" does not use SSH delivery of the bootstrap script`,
+		},
+	}
+	for _, g := range grid {
+		var out bytes.Buffer
+		err := RunToolboxBootstrap(ctx, factory, &out, &g.options)
+		if err == nil || !strings.Contains(err.Error(), g.expected) {
+			t.Errorf("expected error %q, got %v", g.expected, err)
+		}
+		if out.Len() != 0 {
+			t.Errorf("unexpected output %q", out.String())
+		}
+	}
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/upup/pkg/fi/assettasks"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	toolboxBundleLong = templates.LongDesc(i18n.T(`
+	Write all the assets of a cluster to a single archive, to install the cluster without internet access.
+
+	The archive holds the files and their hashes in the layout of a file repository, the images as an
+	OCI image layout, and the channel of the cluster. Write it on a machine with internet access, extract it
+	in the air-gapped network, and copy the assets to the mirrors with kops get assets --copy --from-bundle.`))
+
+	toolboxBundleExample = templates.Examples(i18n.T(`
+	# Write the assets of a cluster to an archive
+	kops toolbox bundle --name k8s-cluster.example.com --output bundle.tar.gz
+
+	# Copy the assets of the extracted archive to the mirrors in spec.assets
+	mkdir bundle && tar -xzf bundle.tar.gz -C bundle
+	kops get assets --name k8s-cluster.example.com --copy --from-bundle bundle
+	`))
+
+	toolboxBundleShort = i18n.T(`Bundle the assets of a cluster for air-gapped installation`)
+)
+
+type ToolboxBundleOptions struct {
+	ClusterName string
+
+	// Output is the path of the archive
+	Output string
+}
+
+func (o *ToolboxBundleOptions) InitDefaults() {
+	o.Output = "bundle.tar.gz"
+}
+
+func NewCmdToolboxBundle(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxBundleOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:     "bundle",
+		Short:   toolboxBundleShort,
+		Long:    toolboxBundleLong,
+		Example: toolboxBundleExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName()
+
+			err := RunToolboxBundle(ctx, f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Output, "output", "o", options.Output, "path of the archive")
+
+	return cmd
+}
+
+func RunToolboxBundle(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxBundleOptions) error {
+	if options.ClusterName == "" {
+		return fmt.Errorf("--name is required")
+	}
+
+	updateClusterResults, err := RunUpdateCluster(ctx, f, options.ClusterName, out, &UpdateClusterOptions{
+		Target:    cloudup.TargetDryRun,
+		GetAssets: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	channelLocation := updateClusterResults.Cluster.Spec.Channel
+	if channelLocation == "" {
+		channelLocation = kops.DefaultChannel
+	}
+	channelURL, err := kops.ResolveChannel(channelLocation)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(options.Output)
+	if err != nil {
+		return fmt.Errorf("error creating %q: %v", options.Output, err)
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	bundle := assettasks.NewBundleWriter(gz)
+
+	var manifest AssetResult
+
+	seen := map[string]bool{}
+	for _, fileAsset := range updateClusterResults.FileAssets {
+		canonical := fileAsset.CanonicalURL.String()
+		if seen[canonical] {
+			continue
+		}
+		seen[canonical] = true
+
+		if err := bundle.AddFile(fileAsset.CanonicalURL, fileAsset.SHAValue); err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, &File{
+			Canonical: canonical,
+			Download:  fileAsset.DownloadURL.String(),
+			SHA:       fileAsset.SHAValue,
+		})
+	}
+
+	seen = map[string]bool{}
+	for _, imageAsset := range updateClusterResults.ImageAssets {
+		if seen[imageAsset.CanonicalLocation] {
+			continue
+		}
+		seen[imageAsset.CanonicalLocation] = true
+
+		if err := bundle.AddImage(ctx, imageAsset.CanonicalLocation); err != nil {
+			return err
+		}
+		manifest.Images = append(manifest.Images, &Image{
+			Canonical: imageAsset.CanonicalLocation,
+			Download:  imageAsset.DownloadLocation,
+		})
+	}
+
+	if channelURL != nil {
+		channel, err := vfs.Context.ReadFile(channelURL.String())
+		if err != nil {
+			return fmt.Errorf("error reading channel %q: %v", channelURL, err)
+		}
+		if err := bundle.WriteFile(path.Join(assets.BundleChannelsDirectory, path.Base(channelURL.Path)), channel); err != nil {
+			return err
+		}
+	}
+
+	y, err := yaml.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("unable to marshal YAML: %v", err)
+	}
+	if err := bundle.WriteFile(assets.BundleManifest, y); err != nil {
+		return err
+	}
+
+	if err := bundle.Close(); err != nil {
+		return fmt.Errorf("error writing %q: %v", options.Output, err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("error writing %q: %v", options.Output, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error writing %q: %v", options.Output, err)
+	}
+
+	fmt.Fprintf(out, "Wrote %d files and %d images to %s\n", len(manifest.Files), len(manifest.Images), options.Output)
+	return nil
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/cis"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	toolboxCISReportLong = templates.LongDesc(i18n.T(`
+	Check a running cluster against the CIS Kubernetes Benchmark.
+
+	The flags of the control plane components are read from their pods, and the configuration
+	of the kubelets from the configz endpoint of each node. Clusters with spec.hardening.profile
+	set to cis are expected to pass all the checks; warnings are recommendations that depend on
+	the environment of the cluster and have to be assessed manually. The command fails if any
+	check fails.`))
+
+	toolboxCISReportExample = templates.Examples(i18n.T(`
+	# Check a cluster against the CIS Kubernetes Benchmark.
+	kops toolbox cis-report --name k8s-cluster.example.com
+
+	# Only print the failed checks and the warnings.
+	kops toolbox cis-report --name k8s-cluster.example.com --failures-only
+	`))
+
+	toolboxCISReportShort = i18n.T(`Check a cluster against the CIS Kubernetes Benchmark`)
+)
+
+type ToolboxCISReportOptions struct {
+	ClusterName string
+
+	// Output is the format of the report: table, yaml or json
+	Output string
+	// FailuresOnly omits the checks that pass from the report
+	FailuresOnly bool
+}
+
+func NewCmdToolboxCISReport(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxCISReportOptions{
+		Output: OutputTable,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "cis-report",
+		Short:   toolboxCISReportShort,
+		Long:    toolboxCISReportLong,
+		Example: toolboxCISReportExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName()
+
+			err := RunToolboxCISReport(ctx, f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Output, "output", "o", options.Output, "Output format. One of table, yaml or json")
+	cmd.Flags().BoolVar(&options.FailuresOnly, "failures-only", options.FailuresOnly, "Only report the failed checks and the warnings")
+
+	return cmd
+}
+
+func RunToolboxCISReport(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxCISReportOptions) error {
+	if options.ClusterName == "" {
+		return fmt.Errorf("--name is required")
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	contextName := cluster.ObjectMeta.Name
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{CurrentContext: contextName}).ClientConfig()
+	if err != nil {
+		return fmt.Errorf("cannot load kubecfg settings for %q: %v", contextName, err)
+	}
+	k8sClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("cannot build kubernetes api client for %q: %v", contextName, err)
+	}
+
+	components, err := listCISComponents(ctx, k8sClient)
+	if err != nil {
+		return err
+	}
+	nodes, err := listCISNodes(ctx, k8sClient)
+	if err != nil {
+		return err
+	}
+
+	var findings []*cis.Finding
+	failures := 0
+	for _, finding := range cis.Run(components, nodes) {
+		if finding.Result == cis.ResultFail {
+			failures++
+		}
+		if options.FailuresOnly && finding.Result == cis.ResultPass {
+			continue
+		}
+		findings = append(findings, finding)
+	}
+
+	switch options.Output {
+	case OutputTable:
+		t := &tables.Table{}
+		t.AddColumn("ID", func(finding *cis.Finding) string {
+			return finding.ID
+		})
+		t.AddColumn("RESULT", func(finding *cis.Finding) string {
+			return string(finding.Result)
+		})
+		t.AddColumn("OBJECT", func(finding *cis.Finding) string {
+			return finding.Object
+		})
+		t.AddColumn("DESCRIPTION", func(finding *cis.Finding) string {
+			return finding.Description
+		})
+		t.AddColumn("DETAIL", func(finding *cis.Finding) string {
+			return finding.Detail
+		})
+		if err := t.Render(findings, out, "ID", "RESULT", "OBJECT", "DESCRIPTION", "DETAIL"); err != nil {
+			return err
+		}
+	case OutputYaml:
+		y, err := yaml.Marshal(findings)
+		if err != nil {
+			return fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	case OutputJSON:
+		j, err := json.Marshal(findings)
+		if err != nil {
+			return fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(append(j, '\n')); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	default:
+		return fmt.Errorf("unknown output format: %q", options.Output)
+	}
+
+	if failures != 0 {
+		return fmt.Errorf("%d CIS benchmark checks failed", failures)
+	}
+	return nil
+}
+
+// listCISComponents reads the flags and the environment of the control plane components from their pods
+func listCISComponents(ctx context.Context, k8sClient kubernetes.Interface) ([]*cis.Component, error) {
+	pods, err := k8sClient.CoreV1().Pods(metav1.NamespaceSystem).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing pods: %v", err)
+	}
+
+	var components []*cis.Component
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		for j := range pod.Spec.Containers {
+			container := &pod.Spec.Containers[j]
+			switch container.Name {
+			case "kube-apiserver", "kube-controller-manager", "kube-scheduler", "etcd-manager":
+			default:
+				continue
+			}
+
+			env := make(map[string]string)
+			for _, envVar := range container.Env {
+				env[envVar.Name] = envVar.Value
+			}
+			components = append(components, &cis.Component{
+				Name:   container.Name,
+				Object: pod.Namespace + "/" + pod.Name,
+				Flags:  cis.ParseFlags(append(append([]string{}, container.Command...), container.Args...)),
+				Env:    env,
+			})
+		}
+	}
+	if len(components) == 0 {
+		return nil, fmt.Errorf("no control plane pods found in %s", metav1.NamespaceSystem)
+	}
+	return components, nil
+}
+
+// listCISNodes reads the configuration of the kubelet of each node from its configz endpoint
+func listCISNodes(ctx context.Context, k8sClient kubernetes.Interface) ([]*cis.Node, error) {
+	nodeList, err := k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing nodes: %v", err)
+	}
+
+	var nodes []*cis.Node
+	for i := range nodeList.Items {
+		node := &nodeList.Items[i]
+		kubeletConfig, err := getKubeletConfig(ctx, k8sClient, node)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, &cis.Node{Name: node.Name, KubeletConfig: kubeletConfig})
+	}
+	return nodes, nil
+}
+
+func getKubeletConfig(ctx context.Context, k8sClient kubernetes.Interface, node *corev1.Node) (*cis.KubeletConfig, error) {
+	data, err := k8sClient.CoreV1().RESTClient().Get().Resource("nodes").Name(node.Name).SubResource("proxy").Suffix("configz").DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the kubelet configuration of node %q: %v", node.Name, err)
+	}
+
+	configz := struct {
+		KubeletConfig *cis.KubeletConfig `json:"kubeletconfig"`
+	}{}
+	if err := json.Unmarshal(data, &configz); err != nil {
+		return nil, fmt.Errorf("error parsing the kubelet configuration of node %q: %v", node.Name, err)
+	}
+	if configz.KubeletConfig == nil {
+		return nil, fmt.Errorf("no kubelet configuration returned for node %q", node.Name)
+	}
+	return configz.KubeletConfig, nil
+}
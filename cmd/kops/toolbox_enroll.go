@@ -0,0 +1,275 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/validation"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/metal"
+	"k8s.io/kops/upup/pkg/fi/cloudup/metaltasks"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxEnrollLong = templates.LongDesc(i18n.T(`
+	Enroll an existing machine into an instance group of a cluster using the metal cloud provider.
+
+	The machine is added to the machines of the instance group, then kOps connects to it over SSH
+	and runs the bootstrap script of the instance group, which installs nodeup and joins the machine
+	to the cluster. The SSH private key is read from KOPS_METAL_SSH_PRIVATE_KEY, defaulting to ~/.ssh/id_rsa.
+
+	The host key of the machine must match --ssh-host-key-fingerprint or, if that is not given, be listed
+	in KOPS_METAL_SSH_KNOWN_HOSTS, defaulting to ~/.ssh/known_hosts. Its fingerprint is recorded in the
+	instance group, and later connections to the machine only accept that host key.
+
+	Enrolling refuses to run while the cluster has other pending changes; apply them first with
+	kops update cluster. Control plane machines cannot be enrolled, as they are the gossip seeds of the
+	cluster; add them to their instance group with kops edit instancegroup instead.`))
+
+	toolboxEnrollExample = templates.Examples(i18n.T(`
+	# Preview enrolling the machine at 192.168.1.23 into the nodes instance group.
+	kops toolbox enroll --name onprem.k8s.local --instance-group nodes --host 192.168.1.23
+
+	# Enroll the machine as node-3, connecting as the ubuntu user.
+	kops toolbox enroll --name onprem.k8s.local --instance-group nodes --host 192.168.1.23 \
+	  --hostname node-3 --ssh-user ubuntu --yes
+
+	# Enroll a machine that is not in known_hosts, giving the fingerprint of its host key.
+	kops toolbox enroll --name onprem.k8s.local --instance-group nodes --host 192.168.1.24 \
+	  --ssh-host-key-fingerprint SHA256:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU --yes
+	`))
+
+	toolboxEnrollShort = i18n.T(`Enroll an existing machine into an instance group`)
+)
+
+type ToolboxEnrollOptions struct {
+	ClusterName   string
+	InstanceGroup string
+
+	// Host is the IP address or DNS name used to reach the machine over SSH
+	Host string
+	// Hostname is the name of the machine; defaults to the hostname reported by the machine
+	Hostname string
+	SSHUser  string
+	// SSHHostKeyFingerprint is the fingerprint of the host key of the machine; defaults to the key in known_hosts
+	SSHHostKeyFingerprint string
+
+	Yes bool
+}
+
+func NewCmdToolboxEnroll(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxEnrollOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "enroll",
+		Short:   toolboxEnrollShort,
+		Long:    toolboxEnrollLong,
+		Example: toolboxEnrollExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName()
+
+			err := RunToolboxEnroll(ctx, f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.InstanceGroup, "instance-group", options.InstanceGroup, "Name of the instance group to enroll the machine into")
+	cmd.Flags().StringVar(&options.Host, "host", options.Host, "IP address or DNS name of the machine")
+	cmd.Flags().StringVar(&options.Hostname, "hostname", options.Hostname, "Name of the machine, used as its node name (defaults to the hostname of the machine)")
+	cmd.Flags().StringVar(&options.SSHUser, "ssh-user", options.SSHUser, "The remote user for SSH access to the machine (defaults to "+metal.DefaultSSHUser+")")
+	cmd.Flags().StringVar(&options.SSHHostKeyFingerprint, "ssh-host-key-fingerprint", options.SSHHostKeyFingerprint, "SHA256 fingerprint of the SSH host key of the machine (defaults to verifying the host key with known_hosts)")
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Enroll the machine immediately, without --yes enroll executes a dry-run")
+
+	return cmd
+}
+
+func RunToolboxEnroll(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxEnrollOptions) error {
+	if options.ClusterName == "" {
+		return fmt.Errorf("--name is required")
+	}
+	if options.InstanceGroup == "" {
+		return fmt.Errorf("--instance-group is required")
+	}
+	if options.Host == "" {
+		return fmt.Errorf("--host is required")
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	if kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderMetal {
+		return fmt.Errorf("only clusters using the %s cloud provider can enroll machines", kops.CloudProviderMetal)
+	}
+
+	list, err := clientset.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	var ig *kops.InstanceGroup
+	for i := range list.Items {
+		if list.Items[i].ObjectMeta.Name == options.InstanceGroup {
+			ig = &list.Items[i]
+		}
+	}
+	if ig == nil {
+		return fmt.Errorf("instancegroup %q not found", options.InstanceGroup)
+	}
+	if ig.IsMaster() {
+		return fmt.Errorf("cannot enroll machines into the control plane instancegroup %q; add them with kops edit instancegroup then kops update cluster", ig.ObjectMeta.Name)
+	}
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return err
+	}
+
+	metalCloud := cloud.(metal.MetalCloud)
+
+	hostname, fingerprint, err := metaltasks.InspectMachine(metalCloud, options.Host, options.SSHUser, options.SSHHostKeyFingerprint)
+	if err != nil {
+		return fmt.Errorf("error connecting to machine: %v", err)
+	}
+
+	machine := kops.MetalMachineSpec{
+		Name:                  options.Hostname,
+		Address:               options.Host,
+		SSHUser:               options.SSHUser,
+		SSHHostKeyFingerprint: fingerprint,
+	}
+	if machine.Name == "" {
+		machine.Name = hostname
+	}
+
+	for _, other := range list.Items {
+		for _, m := range other.Spec.Machines {
+			if m.Name == machine.Name || m.Address == machine.Address {
+				return fmt.Errorf("machine %q at %s is already in instancegroup %q", m.Name, m.Address, other.ObjectMeta.Name)
+			}
+		}
+	}
+
+	enrolled := ig.DeepCopy()
+	enrolled.Spec.Machines = append(enrolled.Spec.Machines, machine)
+	if errs := validation.CrossValidateInstanceGroup(enrolled, cluster, cloud); len(errs) != 0 {
+		return errs.ToAggregate()
+	}
+
+	// The machine's bootstrap script is built from the whole cluster, so we only continue
+	// if enrolling it is the only change that an update of the cluster would make.
+	// That needs the bootstrap state of the other machines, so the dry run connects to them.
+	metalCloud.EnableSSHDuringDryRun()
+	applyCmd, err := applyInstanceGroup(ctx, clientset, cloud, options.ClusterName, enrolled, cloudup.TargetDryRun)
+	if err != nil {
+		return err
+	}
+	changes, err := applyCmd.Target.(*fi.DryRunTarget).ResourceChanges(applyCmd.TaskMap)
+	if err != nil {
+		return err
+	}
+	var pending []string
+	for _, change := range changes {
+		if m, ok := change.Expected.(*metaltasks.Machine); ok && fi.StringValue(m.Name) == machine.Name {
+			continue
+		}
+		pending = append(pending, change.Type+"/"+change.Name)
+	}
+	if len(pending) != 0 {
+		return fmt.Errorf("cluster %q has pending changes, apply them with kops update cluster before enrolling machines: %s", options.ClusterName, strings.Join(pending, ", "))
+	}
+
+	if !options.Yes {
+		fmt.Fprintf(out, "Will enroll machine %q at %s, with host key %s, into instancegroup %q\n", machine.Name, machine.Address, machine.SSHHostKeyFingerprint, ig.ObjectMeta.Name)
+		fmt.Fprintf(out, "\nMust specify --yes to enroll the machine.\n")
+		return nil
+	}
+
+	if _, err := clientset.InstanceGroupsFor(cluster).Update(ctx, enrolled, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error updating instancegroup %q: %v", ig.ObjectMeta.Name, err)
+	}
+
+	if _, err := applyInstanceGroup(ctx, clientset, cloud, options.ClusterName, enrolled, cloudup.TargetDirect); err != nil {
+		return fmt.Errorf("error bootstrapping machine %q, which remains in instancegroup %q and is bootstrapped again by kops update cluster: %v", machine.Name, ig.ObjectMeta.Name, err)
+	}
+
+	fmt.Fprintf(out, "Enrolled machine %q into instancegroup %q\n", machine.Name, ig.ObjectMeta.Name)
+	fmt.Fprintf(out, "\nThe machine joins the cluster once nodeup has started the kubelet; check with kops validate cluster.\n")
+	return nil
+}
+
+// applyInstanceGroup applies the cluster to the target, with the given instance group in place of the stored one.
+// The cluster is read again each time, as applying it populates its spec.
+func applyInstanceGroup(ctx context.Context, clientset simple.Clientset, cloud fi.Cloud, clusterName string, replacement *kops.InstanceGroup, targetName string) (*cloudup.ApplyClusterCmd, error) {
+	cluster, err := clientset.GetCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := clientset.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var instanceGroups []*kops.InstanceGroup
+	for i := range list.Items {
+		ig := &list.Items[i]
+		if ig.ObjectMeta.Name == replacement.ObjectMeta.Name {
+			ig = replacement.DeepCopy()
+		}
+		instanceGroups = append(instanceGroups, ig)
+	}
+
+	applyCmd := &cloudup.ApplyClusterCmd{
+		Cloud:          cloud,
+		Clientset:      clientset,
+		Cluster:        cluster,
+		InstanceGroups: instanceGroups,
+		TargetName:     targetName,
+		DryRun:         targetName == cloudup.TargetDryRun,
+		DryRunOutput:   io.Discard,
+	}
+	if err := applyCmd.Run(ctx); err != nil {
+		return nil, err
+	}
+	return applyCmd, nil
+}
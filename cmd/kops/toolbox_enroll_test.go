@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/kopscodecs"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/util/pkg/text"
+)
+
+func TestToolboxEnrollRequiresMetal(t *testing.T) {
+	ctx := context.Background()
+
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+	h.SetupMockAWS()
+
+	factory := util.NewFactory(&util.FactoryOptions{RegistryPath: "memfs://tests"})
+	clientset, err := factory.Clientset()
+	if err != nil {
+		t.Fatalf("error getting clientset: %v", err)
+	}
+
+	contents, err := ioutil.ReadFile("../../tests/integration/create_cluster/minimal-1.21/expected-v1alpha2.yaml")
+	if err != nil {
+		t.Fatalf("error reading manifests: %v", err)
+	}
+	obj, _, err := kopscodecs.Decode(text.SplitContentToSections(contents)[0], nil)
+	if err != nil {
+		t.Fatalf("error parsing cluster: %v", err)
+	}
+	if _, err := clientset.CreateCluster(ctx, obj.(*kopsapi.Cluster)); err != nil {
+		t.Fatalf("error creating cluster: %v", err)
+	}
+
+	grid := []struct {
+		options  ToolboxEnrollOptions
+		expected string
+	}{
+		{
+			options:  ToolboxEnrollOptions{ClusterName: "minimal.example.com", Host: "192.168.1.23"},
+			expected: "--instance-group is required",
+		},
+		{
+			options:  ToolboxEnrollOptions{ClusterName: "minimal.example.com", InstanceGroup: "nodes-us-test-1a"},
+			expected: "--host is required",
+		},
+		{
+			options:  ToolboxEnrollOptions{ClusterName: "minimal.example.com", InstanceGroup: "nodes-us-test-1a", Host: "192.168.1.23"},
+			expected: "only clusters using the metal cloud provider can enroll machines",
+		},
+	}
+	for _, g := range grid {
+		var out bytes.Buffer
+		err := RunToolboxEnroll(ctx, factory, &out, &g.options)
+		if err == nil || !strings.Contains(err.Error(), g.expected) {
+			t.Errorf("expected error %q, got %v", g.expected, err)
+		}
+		if out.Len() != 0 {
+			t.Errorf("unexpected output %q", out.String())
+		}
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/commands"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxEtcdBackupLong = templates.LongDesc(i18n.T(`
+	Lists the backups of the etcd clusters managed by etcd-manager.
+
+	etcd-manager takes backups on a schedule, every 15 minutes, and before it changes an etcd cluster;
+	backups cannot be requested on demand. Backups are read from the backup store of each etcd cluster,
+	so no access to the cluster itself is needed.`))
+
+	toolboxEtcdBackupExample = templates.Examples(i18n.T(`
+	# List the backups of all etcd clusters
+	kops toolbox etcd-backup --name k8s-cluster.example.com
+
+	# List the backups of the main etcd cluster
+	kops toolbox etcd-backup --name k8s-cluster.example.com --etcd-cluster main
+	`))
+
+	toolboxEtcdBackupShort = i18n.T(`List etcd backups`)
+)
+
+func NewCmdToolboxEtcdBackup(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &commands.EtcdBackupOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "etcd-backup",
+		Short:   toolboxEtcdBackupShort,
+		Long:    toolboxEtcdBackupLong,
+		Example: toolboxEtcdBackupExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			err := RunToolboxEtcdBackup(ctx, f, out, rootCommand.ClusterName(), options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&options.EtcdClusters, "etcd-cluster", options.EtcdClusters, "etcd clusters to list the backups of; defaults to all etcd clusters managed by etcd-manager")
+
+	return cmd
+}
+
+func RunToolboxEtcdBackup(ctx context.Context, f *util.Factory, out io.Writer, clusterName string, options *commands.EtcdBackupOptions) error {
+	if clusterName == "" {
+		return fmt.Errorf("ClusterName is required")
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := clientset.GetCluster(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+	if cluster == nil {
+		return fmt.Errorf("cluster not found %q", clusterName)
+	}
+
+	return commands.RunEtcdBackup(ctx, out, cluster, options)
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/commands"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxEtcdRestoreLong = templates.LongDesc(i18n.T(`
+	Restores the etcd clusters managed by etcd-manager from backups in their backup stores.
+
+	A restore command is added to the backup store of each etcd cluster. etcd-manager carries it
+	out when it restarts, creating a new etcd cluster and restoring the backup onto it while the
+	cluster is quarantined, so the API server cannot read or write partially restored data.
+
+	With --wait, the command waits until etcd-manager has restored every backup and the API server
+	reports that etcd is ready again. Restart the etcd-manager pods on the control plane nodes, or
+	roll the control plane, while it waits.
+
+	A restore cannot be undone, and resources created after the backup was taken are lost.`))
+
+	toolboxEtcdRestoreExample = templates.Examples(i18n.T(`
+	# Preview restoring the latest backups of all etcd clusters
+	kops toolbox etcd-restore --name k8s-cluster.example.com --backup latest
+
+	# Restore a specific backup of the main etcd cluster and the latest backup of the events etcd cluster
+	kops toolbox etcd-restore --name k8s-cluster.example.com \
+		--backup main=2021-05-01T10:00:00Z-000001 --backup events=latest --yes
+
+	# Restore the latest backups, restarting etcd-manager by rolling the control plane
+	kops toolbox etcd-restore --name k8s-cluster.example.com --backup latest --yes
+	kops rolling-update cluster --name k8s-cluster.example.com --instance-group-roles=Master --cloudonly --force --yes
+	`))
+
+	toolboxEtcdRestoreShort = i18n.T(`Restore etcd from backups`)
+)
+
+func NewCmdToolboxEtcdRestore(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &commands.EtcdRestoreOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "etcd-restore",
+		Short:   toolboxEtcdRestoreShort,
+		Long:    toolboxEtcdRestoreLong,
+		Example: toolboxEtcdRestoreExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			err := RunToolboxEtcdRestore(ctx, f, out, rootCommand.ClusterName(), options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&options.EtcdClusters, "etcd-cluster", options.EtcdClusters, "etcd clusters to restore; defaults to all etcd clusters managed by etcd-manager")
+	cmd.Flags().StringArrayVar(&options.Backups, "backup", options.Backups, "backup to restore: a backup name, \"latest\", or <etcd cluster>=<backup>")
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "restore the backups without confirmation")
+	cmd.Flags().DurationVar(&options.Wait, "wait", options.Wait, "how long to wait for the restore to complete; the command does not wait if 0")
+
+	return cmd
+}
+
+func RunToolboxEtcdRestore(ctx context.Context, f *util.Factory, out io.Writer, clusterName string, options *commands.EtcdRestoreOptions) error {
+	if clusterName == "" {
+		return fmt.Errorf("ClusterName is required")
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := clientset.GetCluster(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+	if cluster == nil {
+		return fmt.Errorf("cluster not found %q", clusterName)
+	}
+
+	if options.Wait != 0 {
+		options.WaitForAPIServer = func(ctx context.Context) error {
+			return waitForAPIServerEtcd(ctx, cluster.ObjectMeta.Name, options.Wait)
+		}
+	}
+
+	return commands.RunEtcdRestore(ctx, out, cluster, options)
+}
+
+// waitForAPIServerEtcd waits until the API server reports that it can reach etcd
+func waitForAPIServerEtcd(ctx context.Context, contextName string, timeout time.Duration) error {
+	clientGetter := genericclioptions.NewConfigFlags(true)
+	clientGetter.Context = &contextName
+
+	config, err := clientGetter.ToRESTConfig()
+	if err != nil {
+		return fmt.Errorf("cannot load kubecfg settings for %q: %v", contextName, err)
+	}
+
+	k8sClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("cannot build kube client for %q: %v", contextName, err)
+	}
+
+	err = wait.PollImmediate(10*time.Second, timeout, func() (bool, error) {
+		if _, err := k8sClient.Discovery().RESTClient().Get().AbsPath("/readyz/etcd").DoRaw(ctx); err != nil {
+			klog.V(2).Infof("API server is not ready: %v", err)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("timed out waiting for the API server to reach etcd: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/capi"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxExportCAPILong = templates.LongDesc(i18n.T(`
+	Generate Cluster API manifests from a cluster.
+
+	The cluster and its instance groups are converted into a Cluster, an AWSCluster, a
+	KubeadmControlPlane, and an AWSMachineTemplate, a KubeadmConfigTemplate and a
+	MachineDeployment for each node instance group. The manifests are a starting point for
+	teams evaluating Cluster API and the AWS provider; the features of the cluster which
+	have no equivalent in them are listed as comments at the top of the output.
+
+	Only AWS clusters can be exported. The cluster in the state store is not modified.`))
+
+	toolboxExportCAPIExample = templates.Examples(i18n.T(`
+	# Generate the Cluster API manifests of a cluster.
+	kops toolbox export-capi --name k8s-cluster.example.com > capi.yaml
+
+	# Generate them in the clusters namespace of the management cluster.
+	kops toolbox export-capi --name k8s-cluster.example.com --namespace clusters
+	`))
+
+	toolboxExportCAPIShort = i18n.T(`Generate Cluster API manifests from a cluster`)
+)
+
+type ToolboxExportCAPIOptions struct {
+	ClusterName string
+
+	// Namespace is the namespace of the generated resources
+	Namespace string
+}
+
+func NewCmdToolboxExportCAPI(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxExportCAPIOptions{
+		Namespace: "default",
+	}
+
+	cmd := &cobra.Command{
+		Use:     "export-capi",
+		Short:   toolboxExportCAPIShort,
+		Long:    toolboxExportCAPILong,
+		Example: toolboxExportCAPIExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName()
+
+			err := RunToolboxExportCAPI(ctx, f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Namespace, "namespace", options.Namespace, "Namespace of the generated resources")
+
+	return cmd
+}
+
+func RunToolboxExportCAPI(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxExportCAPIOptions) error {
+	if options.ClusterName == "" {
+		return fmt.Errorf("--name is required")
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	igList, err := clientset.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	var instanceGroups []*kops.InstanceGroup
+	for i := range igList.Items {
+		instanceGroups = append(instanceGroups, &igList.Items[i])
+	}
+
+	result, err := capi.Export(cluster, instanceGroups, options.Namespace)
+	if err != nil {
+		return err
+	}
+
+	y, err := result.ToYAML()
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(y)
+	return err
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops/model"
+	"k8s.io/kops/pkg/jointoken"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxJoinTokenLong = templates.LongDesc(i18n.T(`
+	Issue a join token for an instance group of a cluster with spec.nodeBootstrap.joinTokens enabled.
+
+	A machine that has the join token in /var/lib/kops/join-token proves its identity to kops-controller with
+	the token when it bootstraps, instead of with its cloud identity. kops-controller then issues the machine
+	the credentials of a node of the instance group, under the node name the machine reports, until the token
+	expires. Keep the token secret, and issue it with the shortest lifetime that covers bootstrapping.`))
+
+	toolboxJoinTokenExample = templates.Examples(i18n.T(`
+	# Issue a join token for the nodes instance group that is valid for an hour.
+	kops toolbox join-token --name k8s-cluster.example.com --instance-group nodes
+
+	# Copy a join token that is valid for 10 minutes to a machine.
+	kops toolbox join-token --name k8s-cluster.example.com --instance-group nodes --ttl 10m \
+	  | ssh root@192.168.1.23 'mkdir -p /var/lib/kops && cat > /var/lib/kops/join-token'
+	`))
+
+	toolboxJoinTokenShort = i18n.T(`Issue a join token for an instance group`)
+)
+
+type ToolboxJoinTokenOptions struct {
+	ClusterName   string
+	InstanceGroup string
+
+	// TTL is how long the join token is valid for
+	TTL time.Duration
+}
+
+func NewCmdToolboxJoinToken(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxJoinTokenOptions{
+		TTL: time.Hour,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "join-token",
+		Short:   toolboxJoinTokenShort,
+		Long:    toolboxJoinTokenLong,
+		Example: toolboxJoinTokenExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName()
+
+			err := RunToolboxJoinToken(ctx, f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.InstanceGroup, "instance-group", options.InstanceGroup, "Name of the instance group whose nodes may use the join token")
+	cmd.Flags().DurationVar(&options.TTL, "ttl", options.TTL, "How long the join token is valid for")
+
+	return cmd
+}
+
+func RunToolboxJoinToken(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxJoinTokenOptions) error {
+	if options.ClusterName == "" {
+		return fmt.Errorf("--name is required")
+	}
+	if options.InstanceGroup == "" {
+		return fmt.Errorf("--instance-group is required")
+	}
+	if options.TTL <= 0 {
+		return fmt.Errorf("--ttl must be positive")
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+	if !model.UseJoinTokensForNodeBootstrap(cluster) {
+		return fmt.Errorf("cluster %q does not accept join tokens; set spec.nodeBootstrap.joinTokens", options.ClusterName)
+	}
+
+	ig, err := clientset.InstanceGroupsFor(cluster).Get(ctx, options.InstanceGroup, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error reading instancegroup %q: %v", options.InstanceGroup, err)
+	}
+	if ig == nil {
+		return fmt.Errorf("instancegroup %q not found", options.InstanceGroup)
+	}
+	if ig.IsMaster() {
+		return fmt.Errorf("cannot issue join tokens for the control plane instancegroup %q", ig.ObjectMeta.Name)
+	}
+
+	secretStore, err := clientset.SecretStore(cluster)
+	if err != nil {
+		return err
+	}
+	secret, err := secretStore.FindSecret(jointoken.SecretName)
+	if err != nil {
+		return fmt.Errorf("error reading join token key: %v", err)
+	}
+	if secret == nil {
+		return fmt.Errorf("join token key not found; apply the cluster with kops update cluster first")
+	}
+
+	token := jointoken.Issue(secret.Data, ig.ObjectMeta.Name, time.Now().Add(options.TTL))
+	fmt.Fprintln(out, token.String())
+	return nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/commands"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxMigrateStateLong = templates.LongDesc(i18n.T(`
+	Copies clusters from one state store to another.
+
+	The cluster spec, instance groups, addons, keypairs, secrets and SSH public keys are copied,
+	and each copy is verified by reading it back from the new state store. Store locations that
+	were in the old configBase of the cluster are moved to the new configBase.
+
+	Nodes keep reading their configuration from the old configBase until the cluster is updated
+	and rolled, so keep the old state store until then.`))
+
+	toolboxMigrateStateExample = templates.Examples(i18n.T(`
+	# Copy all clusters from an S3 bucket to a GCS bucket
+	kops toolbox migrate-state --from s3://old-bucket --to gs://new-bucket
+
+	# Copy a cluster to a kubernetes state store, keeping its configuration for nodes in S3
+	kops toolbox migrate-state --from s3://old-bucket --to k8s://management --name k8s-cluster.example.com
+
+	# Apply the new configBase and replace the instances that read the old one
+	kops update cluster --state gs://new-bucket --name k8s-cluster.example.com --yes
+	kops rolling-update cluster --state gs://new-bucket --name k8s-cluster.example.com --force --yes
+	`))
+
+	toolboxMigrateStateShort = i18n.T(`Copy clusters between state stores`)
+)
+
+func NewCmdToolboxMigrateState(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &commands.MigrateStateOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "migrate-state",
+		Short:   toolboxMigrateStateShort,
+		Long:    toolboxMigrateStateLong,
+		Example: toolboxMigrateStateExample,
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if options.From == "" {
+				options.From = f.KopsStateStore()
+			}
+			// Only copy one cluster if --name (or KOPS_CLUSTER_NAME) is set
+			options.ClusterName = rootCommand.clusterName
+
+			err := commands.RunMigrateState(ctx, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.From, "from", options.From, "state store to copy the clusters from; defaults to --state")
+	cmd.Flags().StringVar(&options.To, "to", options.To, "state store to copy the clusters to")
+	cmd.Flags().StringVar(&options.ConfigBase, "config-base", options.ConfigBase, "cluster-readable location for the configuration of the copied cluster; defaults to its location in the new state store")
+
+	return cmd
+}
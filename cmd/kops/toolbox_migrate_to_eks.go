@@ -0,0 +1,205 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/eksmigration"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	toolboxMigrateToEKSLong = templates.LongDesc(i18n.T(`
+	Analyze the migration of a cluster to Amazon EKS.
+
+	Each feature of the cluster and its instance groups is mapped to its EKS equivalent:
+	instance groups to managed node groups, service account permissions to IAM roles for
+	service accounts, and networking and addons to EKS addons. Features which have to be
+	migrated by hand, and features which have no EKS equivalent such as gossip DNS or
+	custom admission plugins, are flagged.
+
+	With --emit, an eksctl ClusterConfig or a terraform configuration for the EKS cluster
+	is written instead of the report, starting with the findings which need action as
+	comments.
+
+	Only AWS clusters can be analyzed. The cluster in the state store is not modified.`))
+
+	toolboxMigrateToEKSExample = templates.Examples(i18n.T(`
+	# Analyze the migration of a cluster to EKS.
+	kops toolbox migrate-to-eks --name k8s-cluster.example.com
+
+	# Generate the eksctl configuration of the EKS cluster.
+	kops toolbox migrate-to-eks --name k8s-cluster.example.com --emit eksctl > eksctl.yaml
+
+	# Generate its terraform configuration.
+	kops toolbox migrate-to-eks --name k8s-cluster.example.com --emit terraform > eks.tf
+	`))
+
+	toolboxMigrateToEKSShort = i18n.T(`Analyze the migration of a cluster to Amazon EKS`)
+)
+
+const (
+	emitEksctl    = "eksctl"
+	emitTerraform = "terraform"
+)
+
+type ToolboxMigrateToEKSOptions struct {
+	ClusterName string
+
+	// Output is the format of the report: table, yaml or json
+	Output string
+
+	// Emit is the configuration to generate instead of the report: eksctl or terraform
+	Emit string
+}
+
+func NewCmdToolboxMigrateToEKS(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxMigrateToEKSOptions{
+		Output: OutputTable,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "migrate-to-eks",
+		Short:   toolboxMigrateToEKSShort,
+		Long:    toolboxMigrateToEKSLong,
+		Example: toolboxMigrateToEKSExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName()
+
+			err := RunToolboxMigrateToEKS(ctx, f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Output, "output", "o", options.Output, "Output format of the report. One of table, yaml or json")
+	cmd.Flags().StringVar(&options.Emit, "emit", options.Emit, "Generate a configuration for the EKS cluster instead of the report. One of eksctl or terraform")
+
+	return cmd
+}
+
+func RunToolboxMigrateToEKS(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxMigrateToEKSOptions) error {
+	if options.ClusterName == "" {
+		return fmt.Errorf("--name is required")
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	igList, err := clientset.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	var instanceGroups []*kops.InstanceGroup
+	for i := range igList.Items {
+		instanceGroups = append(instanceGroups, &igList.Items[i])
+	}
+
+	plan, err := eksmigration.Analyze(cluster, instanceGroups)
+	if err != nil {
+		return err
+	}
+
+	if options.Emit != "" {
+		var config []byte
+		switch options.Emit {
+		case emitEksctl:
+			config, err = plan.Eksctl()
+			if err != nil {
+				return err
+			}
+		case emitTerraform:
+			config = plan.Terraform()
+		default:
+			return fmt.Errorf("unknown configuration: %q", options.Emit)
+		}
+
+		var b bytes.Buffer
+		for _, finding := range plan.NeedsAction() {
+			fmt.Fprintf(&b, "# %s %s: %s\n", finding.Status, finding.Area, finding.Detail)
+		}
+		if b.Len() != 0 {
+			b.WriteString("\n")
+		}
+		b.Write(config)
+		if _, err := out.Write(b.Bytes()); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+		return nil
+	}
+
+	switch options.Output {
+	case OutputTable:
+		t := &tables.Table{}
+		t.AddColumn("AREA", func(finding *eksmigration.Finding) string {
+			return finding.Area
+		})
+		t.AddColumn("STATUS", func(finding *eksmigration.Finding) string {
+			return string(finding.Status)
+		})
+		t.AddColumn("DETAIL", func(finding *eksmigration.Finding) string {
+			return finding.Detail
+		})
+		return t.Render(plan.Findings, out, "AREA", "STATUS", "DETAIL")
+	case OutputYaml:
+		y, err := yaml.Marshal(plan.Findings)
+		if err != nil {
+			return fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	case OutputJSON:
+		j, err := json.Marshal(plan.Findings)
+		if err != nil {
+			return fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(append(j, '\n')); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	default:
+		return fmt.Errorf("unknown output format: %q", options.Output)
+	}
+
+	return nil
+}
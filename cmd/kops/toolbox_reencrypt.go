@@ -0,0 +1,217 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/registry"
+	"k8s.io/kops/pkg/commands"
+	"k8s.io/kops/pkg/envelope"
+	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxReencryptLong = templates.LongDesc(i18n.T(`
+	Re-encrypts the secret store and keystore of a cluster with the KMS key in spec.secretStoreEncryption.
+
+	Files that are in plaintext, or that were encrypted with a different KMS key, are re-encrypted.
+	Run this after enabling secret store encryption, before updating the cluster, since files in plaintext
+	are rejected once encryption is enabled. Run it again after changing the KMS key.
+
+	With --resources, the resources stored in etcd are instead re-encrypted with the KMS key in
+	spec.encryptionAtRest.kmsKey, by writing back every object unchanged. Run this after rolling
+	the control plane with a new KMS key, before removing the old key from previousKMSKeys.`))
+
+	toolboxReencryptExample = templates.Examples(i18n.T(`
+	# Encrypt the secrets and keys of a cluster after enabling secret store encryption
+	kops toolbox reencrypt --name k8s-cluster.example.com
+
+	# Re-encrypt all secrets and keys with new data keys
+	kops toolbox reencrypt --name k8s-cluster.example.com --all
+
+	# Re-encrypt the resources stored in etcd after rotating the encryption at rest KMS key
+	kops toolbox reencrypt --name k8s-cluster.example.com --resources
+	`))
+
+	toolboxReencryptShort = i18n.T(`Re-encrypt the secret store and keystore with the configured KMS key`)
+)
+
+type ToolboxReencryptOptions struct {
+	ClusterName string
+
+	// All re-encrypts files that are already encrypted with the configured KMS key
+	All bool
+
+	// Resources re-encrypts the resources stored in etcd instead of the secret store and keystore
+	Resources bool
+}
+
+func NewCmdToolboxReencrypt(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxReencryptOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "reencrypt",
+		Short:   toolboxReencryptShort,
+		Long:    toolboxReencryptLong,
+		Example: toolboxReencryptExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName()
+
+			err := RunToolboxReencrypt(ctx, f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&options.All, "all", options.All, "re-encrypt files that are already encrypted with the configured KMS key")
+	cmd.Flags().BoolVar(&options.Resources, "resources", options.Resources, "re-encrypt the resources stored in etcd with spec.encryptionAtRest.kmsKey instead of the secret store and keystore")
+
+	return cmd
+}
+
+func RunToolboxReencrypt(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxReencryptOptions) error {
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	if options.ClusterName == "" {
+		return fmt.Errorf("ClusterName is required")
+	}
+
+	cluster, err := clientset.GetCluster(ctx, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	if cluster == nil {
+		return fmt.Errorf("cluster not found %q", options.ClusterName)
+	}
+
+	if options.Resources {
+		return reencryptClusterResources(ctx, out, cluster)
+	}
+
+	if cluster.Spec.SecretStoreEncryption == nil {
+		return fmt.Errorf("secret store encryption is not configured for cluster %q; set spec.secretStoreEncryption.kmsKey", cluster.Name)
+	}
+
+	stores, err := encryptedStorePaths(cluster)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := lockCluster(ctx, clientset, cluster, "toolbox reencrypt")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	aclOracle := func(p vfs.Path) (vfs.ACL, error) {
+		return acls.GetACL(p, cluster)
+	}
+
+	for _, store := range stores {
+		reencrypted, err := envelope.Reencrypt(store, options.All, aclOracle)
+		for _, p := range reencrypted {
+			fmt.Fprintf(out, "Re-encrypted %s\n", p)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reencryptClusterResources rewrites the resources configured in spec.encryptionAtRest through the API server
+func reencryptClusterResources(ctx context.Context, out io.Writer, cluster *kops.Cluster) error {
+	if cluster.Spec.EncryptionAtRest == nil {
+		return fmt.Errorf("encryption at rest is not configured for cluster %q; set spec.encryptionAtRest.kmsKey", cluster.Name)
+	}
+
+	resources := cluster.Spec.EncryptionAtRest.Resources
+	if len(resources) == 0 {
+		resources = []string{"secrets"}
+	}
+
+	contextName := cluster.ObjectMeta.Name
+	clientGetter := genericclioptions.NewConfigFlags(true)
+	clientGetter.Context = &contextName
+
+	config, err := clientGetter.ToRESTConfig()
+	if err != nil {
+		return fmt.Errorf("cannot load kubecfg settings for %q: %v", contextName, err)
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("cannot build kube client for %q: %v", contextName, err)
+	}
+
+	mapper, err := clientGetter.ToRESTMapper()
+	if err != nil {
+		return fmt.Errorf("cannot discover resources for %q: %v", contextName, err)
+	}
+
+	return commands.ReencryptResources(ctx, out, dynamicClient, mapper, resources)
+}
+
+// encryptedStorePaths returns the encrypted paths of the secret store and keystore of the cluster
+func encryptedStorePaths(cluster *kops.Cluster) ([]*envelope.Path, error) {
+	configBase, err := registry.ConfigBase(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	var stores []*envelope.Path
+	for _, store := range []struct {
+		location   string
+		defaultDir string
+	}{
+		{cluster.Spec.SecretStore, "secrets"},
+		{cluster.Spec.KeyStore, "pki"},
+	} {
+		p := configBase.Join(store.defaultDir)
+		if store.location != "" {
+			p, err = vfs.Context.BuildVfsPath(store.location)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing store path %q: %v", store.location, err)
+			}
+		}
+		stores = append(stores, envelope.NewPath(p, cluster.Spec.SecretStoreEncryption.KMSKey))
+	}
+	return stores, nil
+}
@@ -27,7 +27,6 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
-	"helm.sh/helm/v3/pkg/strvals"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
 	"sigs.k8s.io/yaml"
@@ -52,6 +51,7 @@ var (
 	kops toolbox template \
 		--values values.yaml --values=another.yaml \
 		--set var=value --set-string othervar=true \
+		--environment production \
 		--snippets file_or_directory --snippets=another.dir \
 		--template file_or_directory --template=directory  \
 		--output cluster.yaml
@@ -65,6 +65,7 @@ type toolboxTemplateOption struct {
 	clusterName   string
 	configPath    []string
 	configValue   string
+	environment   string
 	failOnMissing bool
 	formatYAML    bool
 	outputPath    string
@@ -97,6 +98,7 @@ func NewCmdToolboxTemplate(f *util.Factory, out io.Writer) *cobra.Command {
 	}
 
 	cmd.Flags().StringSliceVar(&options.configPath, "values", options.configPath, "Path to a configuration file containing values to include in template")
+	cmd.Flags().StringVar(&options.environment, "environment", options.environment, "Name of the environment whose overlay values files (name.<environment>.yaml) are merged over each values file")
 	cmd.Flags().StringArrayVar(&options.values, "set", options.values, "Set values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
 	cmd.Flags().StringArrayVar(&options.stringValues, "set-string", options.stringValues, "Set STRING values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
 	cmd.Flags().StringSliceVar(&options.templatePath, "template", options.templatePath, "Path to template file or directory of templates to render")
@@ -120,7 +122,7 @@ func NewCmdToolboxTemplate(f *util.Factory, out io.Writer) *cobra.Command {
 // runToolBoxTemplate is the action for the command
 func runToolBoxTemplate(f *util.Factory, out io.Writer, options *toolboxTemplateOption) error {
 	// @step: read in the configuration if any
-	context, err := newTemplateContext(options.configPath, options.values, options.stringValues)
+	context, err := newTemplateContext(options.configPath, options.environment, options.values, options.stringValues)
 	if err != nil {
 		return err
 	}
@@ -135,7 +137,7 @@ func runToolBoxTemplate(f *util.Factory, out io.Writer, options *toolboxTemplate
 
 	// @check if we are just rendering the config value
 	if options.configValue != "" {
-		v, found := context[options.configValue]
+		v, found := lookupConfigValue(context, options.configValue)
 		switch found {
 		case true:
 			fmt.Fprintf(out, "%v\n", v)
@@ -240,54 +242,89 @@ func runToolBoxTemplate(f *util.Factory, out io.Writer, options *toolboxTemplate
 	return nil
 }
 
-// newTemplateContext is responsible for loading the --values and build a context for the template
-func newTemplateContext(files []string, values []string, stringValues []string) (map[string]interface{}, error) {
-	context := make(map[string]interface{})
-
+// newTemplateContext is responsible for loading the --values and build a context for the template. The values
+// files are deep merged in the order given; when an environment is set, each file is followed by its overlay
+// (e.g. values.production.yaml for values.yaml) if one exists.
+func newTemplateContext(files []string, environment string, values []string, stringValues []string) (map[string]interface{}, error) {
+	var list []string
 	for _, x := range files {
-		list, err := expandFiles(utils.ExpandPath(x))
+		expanded, err := expandFiles(utils.ExpandPath(x))
 		if err != nil {
 			return nil, err
 		}
-		for _, j := range list {
-			content, err := ioutil.ReadFile(j)
-			if err != nil {
-				return nil, fmt.Errorf("unable to configuration file: %s, error: %s", j, err)
-			}
+		list = append(list, expanded...)
+	}
 
-			ctx := make(map[string]interface{})
-			if err := utils.YamlUnmarshal(content, &ctx); err != nil {
-				return nil, fmt.Errorf("unable decode the configuration file: %s, error: %v", j, err)
-			}
+	valueOpts := &helmvalues.Options{
+		ValueFiles:   valuesFileOrder(list, environment),
+		Values:       values,
+		StringValues: stringValues,
+	}
 
-			valueOpts := &helmvalues.Options{
-				Values:       values,
-				ValueFiles:   files,
-				StringValues: stringValues,
-			}
+	return valueOpts.MergeValues(nil)
+}
 
-			context, err = valueOpts.MergeValues(nil)
-			if err != nil {
-				return nil, err
-			}
-		}
+// valuesFileOrder returns the values files in the order they should be merged. Overlay files (name.<env>.ext,
+// where name.ext is also in the list) are dropped, and the overlay for the given environment is placed directly
+// after its base file.
+func valuesFileOrder(list []string, environment string) []string {
+	present := make(map[string]bool)
+	for _, x := range list {
+		present[x] = true
 	}
 
-	// User specified a value via --set
-	for _, value := range values {
-		if err := strvals.ParseInto(value, context); err != nil {
-			return nil, fmt.Errorf("failed parsing --set data: %s", err)
+	var ordered []string
+	for _, x := range list {
+		if _, isOverlay := overlayBase(x, present); isOverlay {
+			continue
 		}
+		ordered = append(ordered, x)
+
+		if environment == "" {
+			continue
+		}
+		ext := filepath.Ext(x)
+		overlay := strings.TrimSuffix(x, ext) + "." + environment + ext
+		if present[overlay] {
+			ordered = append(ordered, overlay)
+		} else if _, err := os.Stat(overlay); err == nil {
+			ordered = append(ordered, overlay)
+		}
+	}
+
+	return ordered
+}
+
+// overlayBase returns the base file of an overlay file name.<env>.ext, if that base file is present
+func overlayBase(path string, present map[string]bool) (string, bool) {
+	ext := filepath.Ext(path)
+	name := strings.TrimSuffix(path, ext)
+	env := filepath.Ext(name)
+	if env == "" || env == "." {
+		return "", false
 	}
+	base := strings.TrimSuffix(name, env) + ext
+	return base, present[base]
+}
 
-	// User specified a value via --set-string
-	for _, value := range stringValues {
-		if err := strvals.ParseIntoString(value, context); err != nil {
-			return nil, fmt.Errorf("failed parsing --set-string data: %s", err)
+// lookupConfigValue returns the value at a dotted path (e.g. instanceGroups.nodes.machineType) in the context
+func lookupConfigValue(context map[string]interface{}, key string) (interface{}, bool) {
+	if v, found := context[key]; found {
+		return v, true
+	}
+
+	var current interface{} = context
+	for _, part := range strings.Split(key, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[part]; !ok {
+			return nil, false
 		}
 	}
 
-	return context, nil
+	return current, true
 }
 
 // expandFiles is responsible for resolving any references to directories
@@ -17,12 +17,62 @@ limitations under the License.
 package main
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 )
 
 func TestNewTemplateContext(t *testing.T) {
-	context, _ := newTemplateContext([]string{"test/values.yaml"}, []string{"Foo=baz"}, []string{})
+	context, _ := newTemplateContext([]string{"test/values.yaml"}, "", []string{"Foo=baz"}, []string{})
 	if context["Foo"] != "baz" {
 		t.Errorf("Got %v, expected baz", context["foo"])
 	}
 }
+
+func TestNewTemplateContextEnvironment(t *testing.T) {
+	dir, err := ioutil.TempDir("", "toolbox-template")
+	if err != nil {
+		t.Fatalf("unexpected error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"a.yaml":            "instanceGroups:\n  nodes:\n    image: image-a\n    machineType: m5.large\nregion: eu-west-1\n",
+		"a.production.yaml": "instanceGroups:\n  nodes:\n    machineType: m5.xlarge\n",
+		"a.staging.yaml":    "region: eu-west-2\n",
+		"b.yaml":            "instanceGroups:\n  nodes:\n    maxSize: 10\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("unexpected error writing %s: %v", name, err)
+		}
+	}
+
+	context, err := newTemplateContext([]string{dir}, "production", []string{"instanceGroups.nodes.minSize=2"}, []string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{
+		"instanceGroups": map[string]interface{}{
+			"nodes": map[string]interface{}{
+				"image":       "image-a",
+				"machineType": "m5.xlarge",
+				"maxSize":     float64(10),
+				"minSize":     int64(2),
+			},
+		},
+		"region": "eu-west-1",
+	}
+	if !reflect.DeepEqual(context, expected) {
+		t.Errorf("unexpected context, got %v, expected %v", context, expected)
+	}
+
+	if v, found := lookupConfigValue(context, "instanceGroups.nodes.machineType"); !found || v != "m5.xlarge" {
+		t.Errorf("unexpected config value, got %v, expected m5.xlarge", v)
+	}
+	if _, found := lookupConfigValue(context, "instanceGroups.masters"); found {
+		t.Errorf("expected instanceGroups.masters not to be found")
+	}
+}
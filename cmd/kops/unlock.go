@@ -0,0 +1,54 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	unlockLong = templates.LongDesc(i18n.T(`
+	Release a lock on the state of a cluster that an interrupted operation left behind.
+	See:
+	kops unlock cluster -h
+	`))
+
+	unlockExample = templates.Examples(i18n.T(`
+	# Release the lock left behind by an interrupted "kops update cluster"
+	kops unlock cluster k8s.cluster.site --lock-id 0123456789abcdef0123456789abcdef`))
+
+	unlockShort = i18n.T(`Release a lock on the state of a cluster.`)
+)
+
+func NewCmdUnlock(f *util.Factory, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "unlock",
+		Short:   unlockShort,
+		Long:    unlockLong,
+		Example: unlockExample,
+	}
+
+	// create subcommands
+	cmd.AddCommand(NewCmdUnlockCluster(f, out))
+
+	return cmd
+}
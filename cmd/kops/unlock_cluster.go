@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	unlockClusterLong = templates.LongDesc(i18n.T(`
+	Release the lock on the state of a cluster that an operation which is no longer running left behind.
+
+	Operations that write the state of a cluster, such as "kops update cluster --yes", lock it so that
+	concurrent operations can't interleave their writes. An operation that is interrupted may not release
+	its lock, and the other operations then fail, reporting the lock id of the lock.
+
+	The lock is only released if it is still held with the lock id, so that a lock taken since is kept.
+	Make sure that the operation holding the lock is no longer running before releasing it.`))
+
+	unlockClusterExample = templates.Examples(i18n.T(`
+	# Release the lock left behind by an interrupted "kops update cluster"
+	kops unlock cluster k8s.cluster.site --lock-id 0123456789abcdef0123456789abcdef`))
+
+	unlockClusterShort = i18n.T(`Release a lock on the state of a cluster.`)
+)
+
+type UnlockClusterOptions struct {
+	ClusterName string
+	// LockID is the id of the lock to release, as reported by the operations that fail to take the lock
+	LockID string
+}
+
+func NewCmdUnlockCluster(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &UnlockClusterOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "cluster [CLUSTER]",
+		Short:   unlockClusterShort,
+		Long:    unlockClusterLong,
+		Example: unlockClusterExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.TODO()
+
+			err := rootCommand.ProcessArgs(args)
+			if err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName()
+			if options.ClusterName == "" {
+				exitWithError(fmt.Errorf("--name is required"))
+			}
+
+			err = RunUnlockCluster(ctx, f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.LockID, "lock-id", options.LockID, "The id of the lock to release")
+
+	return cmd
+}
+
+func RunUnlockCluster(ctx context.Context, f *util.Factory, out io.Writer, options *UnlockClusterOptions) error {
+	if options.LockID == "" {
+		return fmt.Errorf("--lock-id is required")
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	err = clientset.ForceUnlockCluster(ctx, cluster, options.LockID)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("cluster %q is not locked", cluster.Name)
+		}
+		if err == vfs.ErrLockingNotSupported {
+			return fmt.Errorf("the state store of cluster %q does not support locking", cluster.Name)
+		}
+		return fmt.Errorf("error releasing lock of cluster %q: %v", cluster.Name, err)
+	}
+
+	fmt.Fprintf(out, "Released lock %s of cluster %q\n", options.LockID, cluster.Name)
+	return nil
+}
@@ -24,6 +24,7 @@ import (
 	"io/ioutil"
 	"path/filepath"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -33,9 +34,12 @@ import (
 	"k8s.io/kops/cmd/kops/util"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/pkg/cloudapi"
+	"k8s.io/kops/pkg/costestimate"
 	"k8s.io/kops/pkg/kubeconfig"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/utils"
 	"k8s.io/kops/upup/pkg/kutil"
 	"k8s.io/kubectl/pkg/util/i18n"
@@ -54,6 +58,9 @@ var (
 	updateClusterExample = templates.Examples(i18n.T(`
 	# After cluster has been edited or upgraded, configure it with:
 	kops update cluster k8s-cluster.example.com --yes --state=s3://my-state-store --yes --admin
+
+	# Preview the changes, and the estimated change in the monthly cost of the cloud resources.
+	kops update cluster k8s-cluster.example.com --estimate-cost --state=s3://my-state-store
 	`))
 
 	updateClusterShort = i18n.T("Update a cluster.")
@@ -68,6 +75,8 @@ type UpdateClusterOptions struct {
 	AllowKopsDowngrade bool
 	// GetAssets is whether this is invoked from the CmdGetAssets.
 	GetAssets bool
+	// BundleDirectory is an extracted bundle, from which the hashes of the assets are read when GetAssets is set.
+	BundleDirectory string
 
 	CreateKubecfg bool
 	admin         time.Duration
@@ -76,6 +85,18 @@ type UpdateClusterOptions struct {
 
 	Phase string
 
+	// CloudformationChangeSet creates a change set for the cluster stack, instead of only writing the template, when the target is cloudformation
+	CloudformationChangeSet bool
+
+	// EstimateCost prints the estimated change in the monthly cost of the cloud resources, when previewing changes
+	EstimateCost bool
+
+	// Cache skips finding the cloud resources of the tasks that are unchanged since the last update with the cache
+	Cache bool
+
+	// APIRateLimits limits the requests per second to each cloud provider API, keyed by API name
+	APIRateLimits map[string]int
+
 	// LifecycleOverrides is a slice of taskName=lifecycle name values.  This slice is used
 	// to populate the LifecycleOverrides struct member in ApplyClusterCmd struct.
 	LifecycleOverrides []string
@@ -103,7 +124,7 @@ func NewCmdUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 		Long:    updateClusterLong,
 		Example: updateClusterExample,
 		Run: func(cmd *cobra.Command, args []string) {
-			ctx := context.TODO()
+			ctx := cmd.Context()
 
 			err := rootCommand.ProcessArgs(args)
 			if err != nil {
@@ -119,7 +140,7 @@ func NewCmdUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	}
 
 	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Create cloud resources, without --yes update is in dry run mode")
-	cmd.Flags().StringVar(&options.Target, "target", options.Target, "Target - direct, terraform, cloudformation")
+	cmd.Flags().StringVar(&options.Target, "target", options.Target, "Target - direct, terraform, cloudformation, crossplane")
 	cmd.Flags().StringVar(&options.SSHPublicKey, "ssh-public-key", options.SSHPublicKey, "SSH public key to use (deprecated: use kops create secret instead)")
 	cmd.Flags().StringVar(&options.OutDir, "out", options.OutDir, "Path to write any local output")
 	cmd.Flags().BoolVar(&options.CreateKubecfg, "create-kube-config", options.CreateKubecfg, "Will control automatically creating the kube config file on your local filesystem")
@@ -129,6 +150,12 @@ func NewCmdUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().BoolVar(&options.internal, "internal", options.internal, "Use the cluster's internal DNS name. Implies --create-kube-config")
 	cmd.Flags().BoolVar(&options.AllowKopsDowngrade, "allow-kops-downgrade", options.AllowKopsDowngrade, "Allow an older version of kOps to update the cluster than last used")
 	cmd.Flags().StringVar(&options.Phase, "phase", options.Phase, "Subset of tasks to run: "+strings.Join(cloudup.Phases.List(), ", "))
+	cmd.Flags().BoolVar(&options.CloudformationChangeSet, "cloudformation-change-set", options.CloudformationChangeSet, "Create a change set for the cluster stack, after checking the stack for drift; only supported with --target=cloudformation")
+	cmd.Flags().BoolVar(&options.EstimateCost, "estimate-cost", options.EstimateCost, "Print the estimated change in the monthly cost of the cloud resources; only supported on AWS, without --yes")
+	cmd.Flags().BoolVar(&options.Cache, "cache", options.Cache, "Skip reconciling the cloud resources that are unchanged since the last update with --cache, which does not repair changes made outside of kOps; use it for both the preview and --yes")
+	cmd.Flags().IntVar(&options.RunTasksOptions.MaxConcurrency, "max-concurrency", options.RunTasksOptions.MaxConcurrency, "Maximum number of tasks to run at the same time; 0 means no limit")
+	cmd.Flags().StringToIntVar(&options.RunTasksOptions.MaxConcurrencyPerAPI, "api-concurrency", options.RunTasksOptions.MaxConcurrencyPerAPI, "Maximum number of tasks calling each cloud provider API at the same time, for example iam=2,ec2=10. The AWS APIs are autoscaling, ec2, elb, eventbridge, iam, route53 and sqs")
+	cmd.Flags().StringToIntVar(&options.APIRateLimits, "api-rate-limit", options.APIRateLimits, "Maximum number of requests per second to each cloud provider API, for example ec2=20,iam=5")
 	cmd.Flags().StringSliceVar(&options.LifecycleOverrides, "lifecycle-overrides", options.LifecycleOverrides, "comma separated list of phase overrides, example: SecurityGroups=Ignore,InternetGateway=ExistsAndWarnIfChanges")
 	viper.BindPFlag("lifecycle-overrides", cmd.Flags().Lookup("lifecycle-overrides"))
 	viper.BindEnv("lifecycle-overrides", "KOPS_LIFECYCLE_OVERRIDES")
@@ -188,11 +215,17 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, clusterName string,
 		targetName = cloudup.TargetDryRun
 	}
 
+	if c.EstimateCost && !isDryrun {
+		return nil, fmt.Errorf("--estimate-cost is only supported when previewing changes, without --yes")
+	}
+
 	if c.OutDir == "" {
 		if c.Target == cloudup.TargetTerraform {
 			c.OutDir = "out/terraform"
 		} else if c.Target == cloudup.TargetCloudformation {
 			c.OutDir = "out/cloudformation"
+		} else if c.Target == cloudup.TargetCrossplane {
+			c.OutDir = "out/crossplane"
 		} else {
 			c.OutDir = "out"
 		}
@@ -208,6 +241,14 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, clusterName string,
 		return results, err
 	}
 
+	if !isDryrun {
+		unlock, err := lockCluster(ctx, clientset, cluster, "update cluster")
+		if err != nil {
+			return results, err
+		}
+		defer unlock()
+	}
+
 	keyStore, err := clientset.KeyStore(cluster)
 	if err != nil {
 		return results, err
@@ -272,6 +313,10 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, clusterName string,
 		lifecycleOverrideMap[taskName] = lifecycleOverride
 	}
 
+	if err := cloudapi.SetRateLimits(c.APIRateLimits); err != nil {
+		return nil, err
+	}
+
 	cloud, err := cloudup.BuildCloud(cluster)
 	if err != nil {
 		return nil, err
@@ -289,6 +334,10 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, clusterName string,
 		TargetName:         targetName,
 		LifecycleOverrides: lifecycleOverrideMap,
 		GetAssets:          c.GetAssets,
+		BundleDirectory:    c.BundleDirectory,
+
+		CloudformationChangeSet: c.CloudformationChangeSet,
+		TaskCache:               c.Cache,
 	}
 
 	if err := applyCmd.Run(ctx); err != nil {
@@ -303,6 +352,11 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, clusterName string,
 
 	if isDryrun && !c.GetAssets {
 		target := applyCmd.Target.(*fi.DryRunTarget)
+		if c.EstimateCost {
+			if err := printCostEstimate(out, cluster, target, applyCmd.TaskMap); err != nil {
+				return results, err
+			}
+		}
 		if target.HasChanges() {
 			fmt.Fprintf(out, "Must specify --yes to apply changes\n")
 		} else {
@@ -349,9 +403,22 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, clusterName string,
 		}
 	}
 
+	if c.CloudformationChangeSet && c.Target != cloudup.TargetCloudformation {
+		return results, fmt.Errorf("--cloudformation-change-set is only supported with --target=%s", cloudup.TargetCloudformation)
+	}
+
 	if !isDryrun {
 		sb := new(bytes.Buffer)
 
+		if c.Target == cloudup.TargetDirect && applyCmd.TaskReport != nil {
+			if err := printTaskReport(sb, applyCmd.TaskReport); err != nil {
+				return nil, err
+			}
+			if err := cloudapi.PrintSummary(sb, cloudapi.Stats(), maxReportedAPIOperations); err != nil {
+				return nil, err
+			}
+		}
+
 		if c.Target == cloudup.TargetTerraform {
 			fmt.Fprintf(sb, "\n")
 			fmt.Fprintf(sb, "Terraform output has been placed into %s\n", c.OutDir)
@@ -367,12 +434,26 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, clusterName string,
 			fmt.Fprintf(sb, "\n")
 			fmt.Fprintf(sb, "Cloudformation output has been placed into %s\n", c.OutDir)
 
-			if firstRun {
-				cfName := "kubernetes-" + strings.Replace(clusterName, ".", "-", -1)
+			cfName := cloudformation.StackName(clusterName)
+			if cfTarget, ok := applyCmd.Target.(*cloudformation.CloudformationTarget); ok && cfTarget.ChangeSet != nil {
+				printChangeSet(sb, cfTarget.ChangeSet)
+			} else if firstRun {
 				cfPath := filepath.Join(c.OutDir, "kubernetes.json")
 				fmt.Fprintf(sb, "Run this command to apply the configuration:\n")
 				fmt.Fprintf(sb, "   aws cloudformation create-stack --capabilities CAPABILITY_NAMED_IAM --stack-name %s --template-body file://%s\n", cfName, cfPath)
 				fmt.Fprintf(sb, "\n")
+			} else {
+				fmt.Fprintf(sb, "To review the changes before they are applied to stack %s, run again with --cloudformation-change-set\n", cfName)
+				fmt.Fprintf(sb, "\n")
+			}
+		} else if c.Target == cloudup.TargetCrossplane {
+			fmt.Fprintf(sb, "\n")
+			fmt.Fprintf(sb, "Crossplane output has been placed into %s\n", c.OutDir)
+
+			if firstRun {
+				fmt.Fprintf(sb, "Run this command against the cluster running Crossplane to apply the configuration:\n")
+				fmt.Fprintf(sb, "   kubectl apply -f %s\n", filepath.Join(c.OutDir, "kubernetes.yaml"))
+				fmt.Fprintf(sb, "\n")
 			}
 		} else if firstRun {
 			fmt.Fprintf(sb, "\n")
@@ -450,6 +531,28 @@ func findBastionPublicName(c *kops.Cluster) string {
 	return bastion.BastionPublicName
 }
 
+// printChangeSet prints the changes of the change set, and how to execute it
+func printChangeSet(sb *bytes.Buffer, changeSet *cloudformation.ChangeSet) {
+	if changeSet.Drifted {
+		fmt.Fprintf(sb, "WARNING: stack %s has drifted from its template; review the drift before executing the change set:\n", changeSet.StackName)
+		fmt.Fprintf(sb, "   aws cloudformation describe-stack-resource-drifts --stack-name %s --stack-resource-drift-status-filters MODIFIED DELETED\n", changeSet.StackName)
+		fmt.Fprintf(sb, "\n")
+	}
+
+	if changeSet.ID == "" || len(changeSet.Changes) == 0 {
+		fmt.Fprintf(sb, "Stack %s is up to date; no changes need to be applied\n", changeSet.StackName)
+		fmt.Fprintf(sb, "\n")
+		return
+	}
+
+	fmt.Fprintf(sb, "Created change set for stack %s:\n", changeSet.StackName)
+	fmt.Fprintf(sb, "%s\n", changeSet.String())
+	fmt.Fprintf(sb, "Run these commands to review and apply the change set:\n")
+	fmt.Fprintf(sb, "   aws cloudformation describe-change-set --change-set-name %s\n", changeSet.ID)
+	fmt.Fprintf(sb, "   aws cloudformation execute-change-set --change-set-name %s\n", changeSet.ID)
+	fmt.Fprintf(sb, "\n")
+}
+
 func hasKubecfg(contextName string) (bool, error) {
 	kubectl := &kutil.Kubectl{}
 
@@ -465,3 +568,85 @@ func hasKubecfg(contextName string) (bool, error) {
 	}
 	return false, nil
 }
+
+// printCostEstimate prints the estimated change in the monthly cost of the cloud resources that the dry run would change
+func printCostEstimate(out io.Writer, cluster *kops.Cluster, target *fi.DryRunTarget, taskMap map[string]fi.Task) error {
+	if kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
+		fmt.Fprintf(out, "Cost estimation is only supported on AWS\n\n")
+		return nil
+	}
+
+	changes, err := target.ResourceChanges(taskMap)
+	if err != nil {
+		return err
+	}
+	estimate := costestimate.EstimateAWS(taskMap, changes)
+
+	fmt.Fprintf(out, "Estimated monthly cost changes, from on-demand prices in %s (USD):\n", costestimate.PriceRegion)
+	if len(estimate.Items) == 0 {
+		fmt.Fprintf(out, "  No changes to the cost of priced resources\n\n")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "  RESOURCE\tBEFORE\tAFTER\tMONTHLY COST\n")
+	for _, item := range estimate.Items {
+		before := "-"
+		if item.Before != nil {
+			before = item.Before.Description
+		}
+		fmt.Fprintf(w, "  %s/%s\t%s\t%s\t%+.2f\n", item.Type, item.Name, before, item.After.Description, item.Delta())
+	}
+	fmt.Fprintf(w, "  Total\t\t\t%+.2f\n", estimate.Total())
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if unpriced := estimate.Unpriced(); len(unpriced) != 0 {
+		fmt.Fprintf(out, "  The estimate excludes the unknown prices of: %s\n", strings.Join(unpriced, ", "))
+	}
+	fmt.Fprintf(out, "  Usage based charges, such as for data transfer, and deleted resources are not included.\n\n")
+	return nil
+}
+
+// maxReportedTasks is the number of slowest tasks listed after applying changes
+const maxReportedTasks = 10
+
+// maxReportedAPIOperations is the number of most called cloud API operations listed after applying changes
+const maxReportedAPIOperations = 10
+
+// printTaskReport prints how long the tasks took and how often they were retried, to help diagnose slow updates.
+func printTaskReport(out io.Writer, report *fi.RunTasksReport) error {
+	if len(report.Tasks) == 0 {
+		if report.Cached != 0 {
+			fmt.Fprintf(out, "\nSkipped %d tasks that are unchanged since the last update with --cache; run without --cache to reconcile them.\n", report.Cached)
+		}
+		return nil
+	}
+
+	retried := 0
+	throttled := 0
+	for _, task := range report.Tasks {
+		if task.Attempts > 1 {
+			retried++
+		}
+		throttled += task.Throttled
+	}
+
+	fmt.Fprintf(out, "\n")
+	fmt.Fprintf(out, "Ran %d tasks in %v; %d needed retries and %d attempts were throttled.\n", len(report.Tasks), report.Duration.Round(time.Second), retried, throttled)
+	if report.Cached != 0 {
+		fmt.Fprintf(out, "Skipped %d tasks that are unchanged since the last update with --cache; run without --cache to reconcile them.\n", report.Cached)
+	}
+	fmt.Fprintf(out, "Slowest tasks:\n")
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "  TASK\tDURATION\tATTEMPTS\tTHROTTLED\n")
+	for i, task := range report.Tasks {
+		if i == maxReportedTasks {
+			break
+		}
+		fmt.Fprintf(w, "  %s\t%v\t%d\t%d\n", task.Key, task.Duration.Round(100*time.Millisecond), task.Attempts, task.Throttled)
+	}
+	return w.Flush()
+}
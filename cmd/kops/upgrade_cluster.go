@@ -257,6 +257,12 @@ func (c *UpgradeClusterCmd) Run(ctx context.Context, args []string) error {
 		fmt.Printf("\nMust specify --yes to perform upgrade\n")
 		return nil
 	}
+	unlock, err := lockCluster(ctx, clientset, cluster, "upgrade cluster")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	for _, action := range actions {
 		action.apply()
 	}
@@ -22,6 +22,7 @@ import (
 	"strings"
 
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
 	gceacls "k8s.io/kops/pkg/acls/gce"
@@ -64,65 +65,83 @@ Trailing slash will be trimmed.`
 
 func (f *Factory) Clientset() (simple.Clientset, error) {
 	if f.clientset == nil {
-		registryPath := f.options.RegistryPath
-		klog.V(2).Infof("state store %s", registryPath)
-		if registryPath == "" {
-			return nil, field.Required(field.NewPath("State Store"), STATE_ERROR)
+		clientset, err := NewClientset(f.options.RegistryPath)
+		if err != nil {
+			return nil, err
 		}
+		f.clientset = clientset
+	}
 
-		// We recognize a `k8s` scheme; this might change in future so we won't document it yet
-		// In practice nobody is going to hit this accidentally, so I don't think we need a feature flag.
-		if strings.HasPrefix(registryPath, "k8s://") {
-			loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	return f.clientset, nil
+}
 
-			configOverrides := &clientcmd.ConfigOverrides{}
+// NewClientset builds the clientset for the state store at registryPath
+func NewClientset(registryPath string) (simple.Clientset, error) {
+	klog.V(2).Infof("state store %s", registryPath)
+	if registryPath == "" {
+		return nil, field.Required(field.NewPath("State Store"), STATE_ERROR)
+	}
 
-			if registryPath == "k8s://" {
-			} else {
-				u, err := url.Parse(registryPath)
-				if err != nil {
-					return nil, fmt.Errorf("invalid kops server url: %q", registryPath)
-				}
-				configOverrides.CurrentContext = u.Host
-			}
+	var clientset simple.Clientset
 
-			kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
-			config, err := kubeConfig.ClientConfig()
-			if err != nil {
-				return nil, fmt.Errorf("error loading kubeconfig for %q", registryPath)
-			}
+	// The `k8s` scheme stores clusters as kOps CRDs in a management cluster;
+	// k8s://<context> selects the kubeconfig context, and k8s:// uses the current context.
+	if strings.HasPrefix(registryPath, "k8s://") {
+		loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
 
-			kopsClient, err := kopsclient.NewForConfig(config)
-			if err != nil {
-				return nil, fmt.Errorf("error building kops API client: %v", err)
-			}
+		configOverrides := &clientcmd.ConfigOverrides{}
 
-			f.clientset = &api.RESTClientset{
-				BaseURL: &url.URL{
-					Scheme: "k8s",
-				},
-				KopsClient: kopsClient.Kops(),
-			}
-		} else if strings.HasPrefix(registryPath, "vault://") {
-			return nil, field.Invalid(field.NewPath("State Store"), registryPath, "Vault is not supported as registry path")
+		if registryPath == "k8s://" {
 		} else {
-			basePath, err := vfs.Context.BuildVfsPath(registryPath)
+			u, err := url.Parse(registryPath)
 			if err != nil {
-				return nil, fmt.Errorf("error building path for %q: %v", registryPath, err)
+				return nil, fmt.Errorf("invalid kops server url: %q", registryPath)
 			}
+			configOverrides.CurrentContext = u.Host
+		}
 
-			if !vfs.IsClusterReadable(basePath) {
-				return nil, field.Invalid(field.NewPath("State Store"), registryPath, INVALID_STATE_ERROR)
-			}
+		kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
+		config, err := kubeConfig.ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("error loading kubeconfig for %q", registryPath)
+		}
+
+		kopsClient, err := kopsclient.NewForConfig(config)
+		if err != nil {
+			return nil, fmt.Errorf("error building kops API client: %v", err)
+		}
+
+		k8sClient, err := kubernetes.NewForConfig(config)
+		if err != nil {
+			return nil, fmt.Errorf("error building kubernetes client: %v", err)
+		}
 
-			f.clientset = vfsclientset.NewVFSClientset(basePath)
+		clientset = &api.RESTClientset{
+			BaseURL: &url.URL{
+				Scheme: "k8s",
+			},
+			KopsClient:       kopsClient.Kops(),
+			KubernetesClient: k8sClient,
 		}
-		if strings.HasPrefix(registryPath, "file://") {
-			klog.Warning("The local filesystem state store is not functional for running clusters")
+	} else if strings.HasPrefix(registryPath, "vault://") {
+		return nil, field.Invalid(field.NewPath("State Store"), registryPath, "Vault is not supported as registry path")
+	} else {
+		basePath, err := vfs.Context.BuildVfsPath(registryPath)
+		if err != nil {
+			return nil, fmt.Errorf("error building path for %q: %v", registryPath, err)
 		}
+
+		if !vfs.IsClusterReadable(basePath) {
+			return nil, field.Invalid(field.NewPath("State Store"), registryPath, INVALID_STATE_ERROR)
+		}
+
+		clientset = vfsclientset.NewVFSClientset(basePath)
+	}
+	if strings.HasPrefix(registryPath, "file://") {
+		klog.Warning("The local filesystem state store is not functional for running clusters")
 	}
 
-	return f.clientset, nil
+	return clientset, nil
 }
 
 // KopsStateStore returns the configured KOPS_STATE_STORE in use
@@ -23,6 +23,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"k8s.io/kops/upup/pkg/fi/cloudup"
@@ -47,10 +48,24 @@ type ValidateClusterOptions struct {
 	wait       time.Duration
 	count      int
 	kubeconfig string
+	checks     []string
+
+	// allClusters validates every cluster in the state store
+	allClusters bool
+	// concurrency is the number of clusters validated at once when allClusters is set
+	concurrency int
+}
+
+// ClusterValidationResult is the machine-readable result of validating one of many clusters
+type ClusterValidationResult struct {
+	Cluster string                        `json:"cluster"`
+	Result  *validation.ValidationCluster `json:"result,omitempty"`
+	Error   string                        `json:"error,omitempty"`
 }
 
 func (o *ValidateClusterOptions) InitDefaults() {
 	o.output = OutputTable
+	o.concurrency = 4
 }
 
 func NewCmdValidateCluster(f *util.Factory, out io.Writer) *cobra.Command {
@@ -64,16 +79,48 @@ func NewCmdValidateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	2. All worker nodes are running and have "Ready" status.
 	3. All control plane nodes have the expected pods.
 	4. All pods with a critical priority are running and have "Ready" status.
+
+	It also runs the following checks, which can be selected or skipped with --checks:
+
+	* addons: The Deployments and DaemonSets of the addons applied by kOps are available.
+	* antrea: The Antrea controller and the Antrea agent of every node are ready, so that NetworkPolicies
+	  are enforced, when the cluster uses Antrea.
+	* certificates: The certificates served by the API server do not expire within 30 days.
+	* cilium: Every node has a ready Cilium agent, when the cluster uses Cilium.
+	* etcd: Every member of each etcd cluster is ready and the API server can reach etcd.
+	* node-conditions: No node reports memory, disk or PID pressure.
+	`))
+
+	validateClusterExample := templates.Examples(i18n.T(`
+	# Validate the cluster set as the current context of the kube config.
+	# Kops will try for 10 minutes to validate the cluster 3 times.
+	kops validate cluster --wait 10m --count 3
+
+	# Only validate the nodes, pods and addons, and write the result as JSON for CI.
+	kops validate cluster --checks=addons -o json
+
+	# Validate the cluster, skipping the certificate expiry check.
+	kops validate cluster --checks=-certificates
+
+	# Validate every cluster in the state store, eight at a time.
+	kops validate cluster --all-clusters --concurrency 8
 	`))
 
 	cmd := &cobra.Command{
 		Use:     "cluster",
 		Short:   validateShort,
 		Long:    validateClusterLong,
-		Example: validateExample,
+		Example: validateClusterExample,
 		Run: func(cmd *cobra.Command, args []string) {
 			ctx := context.TODO()
 
+			if options.allClusters {
+				if err := RunValidateAllClusters(ctx, f, args, os.Stdout, options); err != nil {
+					exitWithError(fmt.Errorf("Validation failed: %v", err))
+				}
+				return
+			}
+
 			result, err := RunValidateCluster(ctx, f, cmd, args, os.Stdout, options)
 			if err != nil {
 				exitWithError(fmt.Errorf("Validation failed: %v", err))
@@ -90,6 +137,9 @@ func NewCmdValidateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().DurationVar(&options.wait, "wait", options.wait, "If set, will wait for cluster to be ready")
 	cmd.Flags().IntVar(&options.count, "count", options.count, "If set, will validate the cluster consecutive times")
 	cmd.Flags().StringVar(&options.kubeconfig, "kubeconfig", "", "Path to the kubeconfig file")
+	cmd.Flags().StringSliceVar(&options.checks, "checks", options.checks, "Checks to run, of "+strings.Join(validation.CheckNames(), ",")+"; prefix a check with - to skip it (defaults to all)")
+	cmd.Flags().BoolVar(&options.allClusters, "all-clusters", options.allClusters, "Validate every cluster in the state store")
+	cmd.Flags().IntVar(&options.concurrency, "concurrency", options.concurrency, "Number of clusters to validate at once with --all-clusters")
 
 	return cmd
 }
@@ -100,11 +150,90 @@ func RunValidateCluster(ctx context.Context, f *util.Factory, cmd *cobra.Command
 		return nil, err
 	}
 
+	checks, err := validation.SelectChecks(options.checks)
+	if err != nil {
+		return nil, err
+	}
+
 	cluster, err := rootCommand.Cluster(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	return validateCluster(ctx, f, cluster, checks, out, options)
+}
+
+// RunValidateAllClusters validates every cluster in the state store, printing the result of each.
+func RunValidateAllClusters(ctx context.Context, f *util.Factory, args []string, out io.Writer, options *ValidateClusterOptions) error {
+	if len(args) != 0 || rootCommand.clusterName != "" {
+		return fmt.Errorf("cannot specify a cluster name with --all-clusters")
+	}
+	if options.kubeconfig != "" {
+		return fmt.Errorf("cannot specify --kubeconfig with --all-clusters")
+	}
+
+	checks, err := validation.SelectChecks(options.checks)
+	if err != nil {
+		return err
+	}
+
+	var mutex sync.Mutex
+	validations := make(map[string]*validation.ValidationCluster)
+
+	results, err := forAllClusters(ctx, f, out, options.concurrency, func(ctx context.Context, cluster *kopsapi.Cluster, out io.Writer) error {
+		result, err := validateCluster(ctx, f, cluster, checks, out, options)
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		validations[cluster.ObjectMeta.Name] = result
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if options.output == OutputTable {
+		return clusterResultsOutputTable(results, out)
+	}
+
+	var aggregated []*ClusterValidationResult
+	for _, r := range results {
+		aggregated = append(aggregated, &ClusterValidationResult{
+			Cluster: r.Cluster,
+			Result:  validations[r.Cluster],
+			Error:   r.Error,
+		})
+	}
+
+	switch options.output {
+	case OutputYaml:
+		y, err := yaml.Marshal(aggregated)
+		if err != nil {
+			return fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	case OutputJSON:
+		j, err := json.Marshal(aggregated)
+		if err != nil {
+			return fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(append(j, '\n')); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	default:
+		return fmt.Errorf("unknown output format: %q", options.output)
+	}
+
+	return clusterResultsError(results)
+}
+
+// validateCluster validates a single cluster.  With --all-clusters, only table output is written
+// to out; the caller aggregates the machine-readable results.  The last result is returned even
+// if the cluster failed validation.
+func validateCluster(ctx context.Context, f *util.Factory, cluster *kopsapi.Cluster, checks []validation.Check, out io.Writer, options *ValidateClusterOptions) (*validation.ValidationCluster, error) {
 	cloud, err := cloudup.BuildCloud(cluster)
 	if err != nil {
 		return nil, err
@@ -155,7 +284,7 @@ func RunValidateCluster(ctx context.Context, f *util.Factory, cmd *cobra.Command
 	timeout := time.Now().Add(options.wait)
 	pollInterval := 10 * time.Second
 
-	validator, err := validation.NewClusterValidator(cluster, cloud, list, config.Host, k8sClient)
+	validator, err := validation.NewClusterValidator(cluster, cloud, list, config.Host, k8sClient, checks)
 	if err != nil {
 		return nil, fmt.Errorf("unexpected error creating validatior: %v", err)
 	}
@@ -178,12 +307,14 @@ func RunValidateCluster(ctx context.Context, f *util.Factory, cmd *cobra.Command
 			}
 		}
 
-		switch options.output {
-		case OutputTable:
+		switch {
+		case options.allClusters && options.output != OutputTable:
+			// Aggregated by RunValidateAllClusters
+		case options.output == OutputTable:
 			if err := validateClusterOutputTable(result, cluster, instanceGroups, out); err != nil {
 				return nil, err
 			}
-		case OutputYaml:
+		case options.output == OutputYaml:
 			y, err := yaml.Marshal(result)
 			if err != nil {
 				return nil, fmt.Errorf("unable to marshal YAML: %v", err)
@@ -191,12 +322,13 @@ func RunValidateCluster(ctx context.Context, f *util.Factory, cmd *cobra.Command
 			if _, err := out.Write(y); err != nil {
 				return nil, fmt.Errorf("error writing to output: %v", err)
 			}
-		case OutputJSON:
+		case options.output == OutputJSON:
 			j, err := json.Marshal(result)
 			if err != nil {
 				return nil, fmt.Errorf("unable to marshal JSON: %v", err)
 			}
-			if _, err := out.Write(j); err != nil {
+			// One result per line, so that repeated results can be consumed as a stream
+			if _, err := out.Write(append(j, '\n')); err != nil {
 				return nil, fmt.Errorf("error writing to output: %v", err)
 			}
 		default:
@@ -211,7 +343,7 @@ func RunValidateCluster(ctx context.Context, f *util.Factory, cmd *cobra.Command
 					time.Sleep(pollInterval)
 					continue
 				} else {
-					return nil, fmt.Errorf("cluster passed validation %d consecutive times", consecutive)
+					return result, fmt.Errorf("cluster passed validation %d consecutive times", consecutive)
 				}
 			} else {
 				return result, nil
@@ -223,7 +355,7 @@ func RunValidateCluster(ctx context.Context, f *util.Factory, cmd *cobra.Command
 				time.Sleep(pollInterval)
 				continue
 			} else {
-				return nil, fmt.Errorf("cluster not yet healthy")
+				return result, fmt.Errorf("cluster not yet healthy")
 			}
 		}
 	}
@@ -17,6 +17,7 @@ limitations under the License.
 package main // import "k8s.io/kops/cmd/nodeup"
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
@@ -25,6 +26,10 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops"
 	"k8s.io/kops/nodeup/pkg/bootstrap"
+	"k8s.io/kops/nodeup/pkg/cloudevents"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/logformat"
+	"k8s.io/kops/pkg/tracing"
 	"k8s.io/kops/upup/pkg/fi/nodeup"
 )
 
@@ -35,8 +40,9 @@ const (
 
 func main() {
 	klog.InitFlags(nil)
+	logformat.InitFlags(nil)
 
-	var flagConf, flagCacheDir, gitVersion string
+	var flagConf, flagCacheDir, flagWatchCloudEvents, gitVersion string
 	var flagRetries int
 	var dryrun, installSystemdUnit bool
 	target := "direct"
@@ -51,6 +57,7 @@ func main() {
 	flag.BoolVar(&dryrun, "dryrun", false, "Don't create cloud resources; just show what would be done")
 	flag.StringVar(&target, "target", target, "Target - direct, cloudinit")
 	flag.BoolVar(&installSystemdUnit, "install-systemd-unit", installSystemdUnit, "If true, will install a systemd unit instead of running directly")
+	flag.StringVar(&flagWatchCloudEvents, "watch-cloud-events", "", "If set, watch the interruption notices of this cloud provider and shut the instance down when one is announced, instead of configuring the node")
 
 	if dryrun {
 		target = "dryrun"
@@ -59,13 +66,35 @@ func main() {
 	flag.Set("logtostderr", "true")
 	flag.Parse()
 
+	if err := logformat.Apply(); err != nil {
+		klog.Exitf("%v", err)
+	}
+
+	if flagWatchCloudEvents != "" {
+		watcher, err := cloudevents.NewWatcher(kopsapi.CloudProviderID(flagWatchCloudEvents))
+		if err != nil {
+			klog.Exitf("%v", err)
+		}
+		if err := cloudevents.Run(context.Background(), watcher, 5*time.Second, cloudevents.Shutdown); err != nil {
+			klog.Exitf("error watching interruption notices: %v", err)
+		}
+		os.Exit(0)
+	}
+
 	if flagConf == "" {
 		klog.Exitf("--conf is required")
 	}
 
+	shutdownTracing, err := tracing.Init("nodeup")
+	if err != nil {
+		klog.Warningf("tracing disabled: %v", err)
+		shutdownTracing = func() {}
+	}
+
 	retries := flagRetries
 
 	for {
+		ctx, span := tracing.StartSpan(context.Background(), "nodeup")
 		var err error
 		if installSystemdUnit {
 			// create a systemd unit to bootstrap kops
@@ -103,8 +132,10 @@ func main() {
 			}
 			i.RunTasksOptions.InitDefaults()
 			i.RunTasksOptions.MaxTaskDuration = 5 * time.Minute
-			err = i.Run()
+			err = i.Run(ctx)
 			if err == nil {
+				tracing.EndSpan(span, nil)
+				shutdownTracing()
 				fmt.Printf("service installed")
 				os.Exit(0)
 			}
@@ -114,14 +145,19 @@ func main() {
 				Target:         target,
 				CacheDir:       flagCacheDir,
 			}
-			err = cmd.Run(os.Stdout)
+			err = cmd.Run(ctx, os.Stdout)
 			if err == nil {
+				tracing.EndSpan(span, nil)
+				shutdownTracing()
 				fmt.Printf("success")
 				os.Exit(0)
 			}
 		}
 
+		tracing.EndSpan(span, err)
+
 		if retries == 0 {
+			shutdownTracing()
 			klog.Exitf("error running nodeup: %v", err)
 			os.Exit(1)
 		}
@@ -41,6 +41,7 @@ import (
 	"k8s.io/kops/protokube/pkg/gossip"
 	gossipdns "k8s.io/kops/protokube/pkg/gossip/dns"
 	gossipdnsprovider "k8s.io/kops/protokube/pkg/gossip/dns/provider"
+	"k8s.io/kops/protokube/pkg/gossip/kopscontroller"
 	_ "k8s.io/kops/protokube/pkg/gossip/memberlist"
 	_ "k8s.io/kops/protokube/pkg/gossip/mesh"
 )
@@ -53,7 +54,7 @@ var (
 func main() {
 	fmt.Printf("dns-controller version %s\n", BuildVersion)
 	var dnsServer, dnsProviderID, gossipListen, gossipSecret, watchNamespace, metricsListen, gossipProtocol, gossipSecretSecondary, gossipListenSecondary, gossipProtocolSecondary string
-	var gossipSeeds, gossipSeedsSecondary, zones []string
+	var gossipSeeds, gossipSeedsSecondary, zones, internalZones []string
 	var watchIngress bool
 	var updateInterval int
 
@@ -65,8 +66,9 @@ func main() {
 	flags.BoolVar(&watchIngress, "watch-ingress", true, "Configure hostnames found in ingress resources")
 	flags.StringSliceVar(&gossipSeeds, "gossip-seed", gossipSeeds, "If set, will enable gossip zones and seed using the provided addresses")
 	flags.StringSliceVarP(&zones, "zone", "z", []string{}, "Configure permitted zones and their mappings")
+	flags.StringSliceVar(&internalZones, "internal-zone", []string{}, "Configure zones which receive the records of internal addresses, for names which also have a zone for external addresses")
 	flags.StringVar(&dnsProviderID, "dns", "aws-route53", "DNS provider we should use (aws-route53, google-clouddns, digitalocean, gossip)")
-	flag.StringVar(&gossipProtocol, "gossip-protocol", "mesh", "mesh/memberlist")
+	flag.StringVar(&gossipProtocol, "gossip-protocol", "mesh", "mesh/memberlist/kops-controller")
 	flags.StringVar(&gossipListen, "gossip-listen", fmt.Sprintf("0.0.0.0:%d", wellknownports.DNSControllerGossipWeaveMesh), "The address on which to listen if gossip is enabled")
 	flags.StringVar(&gossipSecret, "gossip-secret", gossipSecret, "Secret to use to secure gossip")
 	flag.StringVar(&gossipProtocolSecondary, "gossip-protocol-secondary", "", "mesh/memberlist")
@@ -97,6 +99,10 @@ func main() {
 		klog.Errorf("unexpected zone flags: %q", err)
 		os.Exit(1)
 	}
+	if err := zoneRules.ParseInternalZones(internalZones); err != nil {
+		klog.Errorf("unexpected internal zone flags: %q", err)
+		os.Exit(1)
+	}
 
 	config, err := rest.InClusterConfig()
 	if err != nil {
@@ -125,7 +131,7 @@ func main() {
 		dnsProviders = append(dnsProviders, dnsProvider)
 	}
 
-	if len(gossipSeeds) != 0 {
+	if len(gossipSeeds) != 0 || gossipProtocol == kopscontroller.ProtocolName {
 		gossipSeeds := gossip.NewStaticSeedProvider(gossipSeeds)
 
 		id := os.Getenv("HOSTNAME")
@@ -137,10 +143,15 @@ func main() {
 		channelName := "dns"
 		var gossipState gossip.GossipState
 
-		gossipState, err = gossip.GetGossipState(gossipProtocol, gossipListen, channelName, gossipName, []byte(gossipSecret), gossipSeeds)
-		if err != nil {
-			klog.Errorf("Error initializing gossip: %v", err)
-			os.Exit(1)
+		if gossipProtocol == kopscontroller.ProtocolName {
+			// The records are stored in a ConfigMap, which kops-controller serves to the nodes
+			gossipState = kopscontroller.NewConfigMapState(client)
+		} else {
+			gossipState, err = gossip.GetGossipState(gossipProtocol, gossipListen, channelName, gossipName, []byte(gossipSecret), gossipSeeds)
+			if err != nil {
+				klog.Errorf("Error initializing gossip: %v", err)
+				os.Exit(1)
+			}
 		}
 
 		if gossipProtocolSecondary != "" {
@@ -198,6 +198,8 @@ func (c *DNSController) snapshotIfChangedAndReady() *snapshot {
 type recordKey struct {
 	RecordType RecordType
 	FQDN       string
+	// Internal is set if the record is published in the internal zone for the FQDN
+	Internal bool
 }
 
 func (c *DNSController) runOnce() error {
@@ -209,6 +211,11 @@ func (c *DNSController) runOnce() error {
 		return nil
 	}
 
+	op, err := newDNSOp(c.zoneRules, c.dnsCache)
+	if err != nil {
+		return err
+	}
+
 	newValueMap := make(map[recordKey][]string)
 	{
 		// Resolve and build map
@@ -219,19 +226,13 @@ func (c *DNSController) runOnce() error {
 					klog.Infof("Alias in record specified %q, but no records were found for that name", r.Value)
 				}
 				for _, aliasRecord := range aliasRecords {
-					key := recordKey{
-						RecordType: aliasRecord.RecordType,
-						FQDN:       r.FQDN,
-					}
+					key := op.recordKey(aliasRecord.RecordType, r.FQDN, r.Internal)
 					// TODO: Support chains: alias of alias (etc)
 					newValueMap[key] = append(newValueMap[key], aliasRecord.Value)
 				}
 				continue
 			} else {
-				key := recordKey{
-					RecordType: r.RecordType,
-					FQDN:       r.FQDN,
-				}
+				key := op.recordKey(r.RecordType, r.FQDN, r.Internal)
 				newValueMap[key] = append(newValueMap[key], r.Value)
 				continue
 			}
@@ -250,11 +251,6 @@ func (c *DNSController) runOnce() error {
 		oldValueMap = c.lastSuccessfulSnapshot.recordValues
 	}
 
-	op, err := newDNSOp(c.zoneRules, c.dnsCache)
-	if err != nil {
-		return err
-	}
-
 	// Store a list of all the errors, so that one bad apple doesn't block every other request
 	var errors []error
 
@@ -350,10 +346,7 @@ func (c *DNSController) RemoveRecordsImmediate(records []Record) error {
 	var errors []error
 
 	for _, r := range records {
-		k := recordKey{
-			RecordType: r.RecordType,
-			FQDN:       r.FQDN,
-		}
+		k := op.recordKey(r.RecordType, r.FQDN, r.Internal)
 
 		err := op.deleteRecords(k)
 		if err != nil {
@@ -379,9 +372,11 @@ func (c *DNSController) RemoveRecordsImmediate(records []Record) error {
 
 // dnsOp manages a single dns change; we cache results and state for the duration of the operation
 type dnsOp struct {
-	dnsCache     *dnsCache
-	zones        map[string]dnsprovider.Zone
-	recordsCache map[string][]dnsprovider.ResourceRecordSet
+	dnsCache *dnsCache
+	zones    map[string]dnsprovider.Zone
+	// internalZones are the zones receiving the records of internal addresses, by name
+	internalZones map[string]dnsprovider.Zone
+	recordsCache  map[string][]dnsprovider.ResourceRecordSet
 
 	changesets map[string]dnsprovider.ResourceRecordChangeset
 }
@@ -401,7 +396,25 @@ func newDNSOp(zoneRules *ZoneRules, dnsCache *dnsCache) (*dnsOp, error) {
 	}
 
 	zoneMap := make(map[string]dnsprovider.Zone)
-	for name, zones := range allZoneMap {
+	internalZoneMap := make(map[string]dnsprovider.Zone)
+	for name, allZones := range allZoneMap {
+		// Internal zones are kept apart, so that their names can also have a zone for external addresses
+		var zones []dnsprovider.Zone
+		var internalMatches []dnsprovider.Zone
+		for _, zone := range allZones {
+			if zoneRules.MatchesInternal(zone) {
+				internalMatches = append(internalMatches, zone)
+			} else {
+				zones = append(zones, zone)
+			}
+		}
+
+		if len(internalMatches) == 1 {
+			internalZoneMap[name] = internalMatches[0]
+		} else if len(internalMatches) > 1 {
+			klog.Warningf("Found multiple internal zones for name %q, won't manage internal zone (To fix: provide internal zone flag with ID of zone)", name)
+		}
+
 		var matches []dnsprovider.Zone
 		for _, zone := range zones {
 			if zoneRules.MatchesExplicitly(zone) {
@@ -422,10 +435,11 @@ func newDNSOp(zoneRules *ZoneRules, dnsCache *dnsCache) (*dnsOp, error) {
 	}
 
 	o := &dnsOp{
-		dnsCache:     dnsCache,
-		zones:        zoneMap,
-		changesets:   make(map[string]dnsprovider.ResourceRecordChangeset),
-		recordsCache: make(map[string][]dnsprovider.ResourceRecordSet),
+		dnsCache:      dnsCache,
+		zones:         zoneMap,
+		internalZones: internalZoneMap,
+		changesets:    make(map[string]dnsprovider.ResourceRecordChangeset),
+		recordsCache:  make(map[string][]dnsprovider.ResourceRecordSet),
 	}
 
 	return o, nil
@@ -438,10 +452,27 @@ func EnsureDotSuffix(s string) string {
 	return s
 }
 
-func (o *dnsOp) findZone(fqdn string) dnsprovider.Zone {
+// recordKey builds the key for a record of the given type.
+// Records of internal addresses are only kept apart if there is an internal zone for them,
+// otherwise they are published along with the external addresses as before.
+func (o *dnsOp) recordKey(recordType RecordType, fqdn string, internal bool) recordKey {
+	return recordKey{
+		RecordType: recordType,
+		FQDN:       fqdn,
+		Internal:   internal && o.findZone(fqdn, true) != nil,
+	}
+}
+
+// findZone returns the zone for the fqdn, from the internal zones if internal is set
+func (o *dnsOp) findZone(fqdn string, internal bool) dnsprovider.Zone {
+	zones := o.zones
+	if internal {
+		zones = o.internalZones
+	}
+
 	zoneName := EnsureDotSuffix(fqdn)
 	for {
-		zone := o.zones[zoneName]
+		zone := zones[zoneName]
 		if zone != nil {
 			return zone
 		}
@@ -497,7 +528,7 @@ func (o *dnsOp) deleteRecords(k recordKey) error {
 
 	fqdn := EnsureDotSuffix(k.FQDN)
 
-	zone := o.findZone(fqdn)
+	zone := o.findZone(fqdn, k.Internal)
 	if zone == nil {
 		// TODO: Post event into service / pod
 		return fmt.Errorf("no suitable zone found for %q", fqdn)
@@ -539,7 +570,7 @@ func FixWildcards(s string) string {
 func (o *dnsOp) updateRecords(k recordKey, newRecords []string, ttl int64) error {
 	fqdn := EnsureDotSuffix(k.FQDN)
 
-	zone := o.findZone(fqdn)
+	zone := o.findZone(fqdn, k.Internal)
 	if zone == nil {
 		// TODO: Post event into service / pod
 		return fmt.Errorf("no suitable zone found for %q", fqdn)
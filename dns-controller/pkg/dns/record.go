@@ -38,6 +38,10 @@ type Record struct {
 	// but will be used as an expansion for Records with type=RecordTypeAlias,
 	// where the referring record has Value = our FQDN
 	AliasTarget bool
+
+	// Internal is set for records of internal addresses; they are published in the internal zone
+	// for the name if there is one, so that the name can resolve differently inside the network
+	Internal bool
 }
 
 // AliasForNodesInRole returns the alias for nodes in the given role
@@ -51,6 +55,9 @@ func (r *Record) String() string {
 	if r.AliasTarget {
 		s += ",AliasTarget"
 	}
+	if r.Internal {
+		s += ",Internal"
+	}
 
 	s += "]"
 
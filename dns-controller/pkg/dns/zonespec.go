@@ -51,6 +51,10 @@ type ZoneRules struct {
 	// We don't use a map so we can support e.g. *.example.com later
 	Zones    []*ZoneSpec
 	Wildcard bool
+
+	// InternalZones are the zones which receive the records of internal addresses,
+	// typically private zones shadowing a public zone of the same name
+	InternalZones []*ZoneSpec
 }
 
 func ParseZoneRules(zones []string) (*ZoneRules, error) {
@@ -79,12 +83,37 @@ func ParseZoneRules(zones []string) (*ZoneRules, error) {
 	return r, nil
 }
 
+// ParseInternalZones adds the internal zones to the rules
+func (r *ZoneRules) ParseInternalZones(zones []string) error {
+	for _, s := range zones {
+		s = strings.TrimSpace(s)
+		if s == "*" || s == "*/*" {
+			return fmt.Errorf("internal zone %q must be a zone name or ID", s)
+		}
+		zoneSpec, err := ParseZoneSpec(s)
+		if err != nil {
+			return fmt.Errorf("error parsing %q: %v", s, err)
+		}
+		r.InternalZones = append(r.InternalZones, zoneSpec)
+	}
+	return nil
+}
+
 // MatchesExplicitly returns true if this matches an explicit rule (not a wildcard)
 func (r *ZoneRules) MatchesExplicitly(zone dnsprovider.Zone) bool {
+	return matchesZoneSpecs(r.Zones, zone)
+}
+
+// MatchesInternal returns true if the zone is one of the internal zones
+func (r *ZoneRules) MatchesInternal(zone dnsprovider.Zone) bool {
+	return matchesZoneSpecs(r.InternalZones, zone)
+}
+
+func matchesZoneSpecs(zoneSpecs []*ZoneSpec, zone dnsprovider.Zone) bool {
 	name := EnsureDotSuffix(zone.Name())
 	id := zone.ID()
 
-	for _, zoneSpec := range r.Zones {
+	for _, zoneSpec := range zoneSpecs {
 		if zoneSpec.Name != "" && zoneSpec.Name != name {
 			continue
 		}
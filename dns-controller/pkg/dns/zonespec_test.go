@@ -130,6 +130,49 @@ func TestParseZoneRules(t *testing.T) {
 	}
 }
 
+func TestParseInternalZones(t *testing.T) {
+	cases := []struct {
+		zones       []string
+		expected    []*ZoneSpec
+		expectError bool
+	}{
+		{
+			zones: []string{"*/1234"},
+			expected: []*ZoneSpec{
+				{Name: "", ID: "1234"},
+			},
+		},
+		{
+			zones: []string{"example.com/1234"},
+			expected: []*ZoneSpec{
+				{Name: "example.com.", ID: "1234"},
+			},
+		},
+		{
+			zones:       []string{"*/*"},
+			expectError: true,
+		},
+	}
+
+	for _, c := range cases {
+		r := &ZoneRules{}
+		err := r.ParseInternalZones(c.zones)
+		if c.expectError {
+			if err == nil {
+				t.Errorf("ParseInternalZones(%#v) expected error, but got none", c.zones)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseInternalZones(%#v) unexpected error: %v", c.zones, err)
+			continue
+		}
+		if !reflect.DeepEqual(r.InternalZones, c.expected) {
+			t.Errorf("ParseInternalZones(%#v) expected %#v, but got %#v", c.zones, c.expected, r.InternalZones)
+		}
+	}
+}
+
 // This is not correct
 
 // func TestMatchesExplicitly(t *testing.T) {
@@ -196,6 +196,7 @@ func (c *PodController) updatePodRecords(pod *v1.Pod) string {
 					RecordType: dns.RecordTypeA,
 					FQDN:       fqdn,
 					Value:      ip,
+					Internal:   true,
 				})
 			}
 		}
@@ -85,7 +85,7 @@ func TestPodController(t *testing.T) {
 	want := map[string][]dns.Record{
 		"kube-system/somepod": {
 			{RecordType: "_alias", FQDN: "a.foo.com.", Value: "node/my-node/external"},
-			{RecordType: "A", FQDN: "internal.a.foo.com.", Value: "10.0.0.1"},
+			{RecordType: "A", FQDN: "internal.a.foo.com.", Value: "10.0.0.1", Internal: true},
 		},
 	}
 	if diff := cmp.Diff(scope.records, want); diff != "" {
@@ -0,0 +1,139 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudevents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+const (
+	awsMetadataEndpoint = "http://169.254.169.254"
+
+	// awsScheduledEventWindow is how long before a scheduled event the instance is shut down.
+	// Scheduled events are announced days ahead, so the instance keeps running until the event is near.
+	awsScheduledEventWindow = 10 * time.Minute
+
+	// awsScheduledEventTimeFormat is the format of the times of the scheduled events, e.g. 21 Jan 2019 09:00:43 GMT
+	awsScheduledEventTimeFormat = "2 Jan 2006 15:04:05 MST"
+)
+
+// awsWatcher reads the spot interruption notices and the scheduled events from the EC2 instance metadata service
+type awsWatcher struct {
+	httpClient *http.Client
+	endpoint   string
+	window     time.Duration
+	now        func() time.Time
+}
+
+// awsScheduledEvent is an event of the events/maintenance/scheduled metadata
+type awsScheduledEvent struct {
+	Code      string `json:"Code"`
+	EventID   string `json:"EventId"`
+	NotBefore string `json:"NotBefore"`
+	State     string `json:"State"`
+}
+
+func (w *awsWatcher) Poll(ctx context.Context) (string, error) {
+	token, err := w.token(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// The spot interruption notice is served two minutes before the instance is stopped or terminated
+	action, found, err := w.get(ctx, token, "/latest/meta-data/spot/instance-action")
+	if err != nil {
+		return "", err
+	}
+	if found {
+		return fmt.Sprintf("spot interruption %s", action), nil
+	}
+
+	data, found, err := w.get(ctx, token, "/latest/meta-data/events/maintenance/scheduled")
+	if err != nil || !found {
+		return "", err
+	}
+	var events []awsScheduledEvent
+	if err := json.Unmarshal([]byte(data), &events); err != nil {
+		return "", fmt.Errorf("error parsing scheduled events: %v", err)
+	}
+	for _, event := range events {
+		if event.State != "active" {
+			continue
+		}
+		notBefore, err := time.Parse(awsScheduledEventTimeFormat, event.NotBefore)
+		if err != nil {
+			return "", fmt.Errorf("error parsing the time of scheduled event %s: %v", event.EventID, err)
+		}
+		if notBefore.Sub(w.now()) <= w.window {
+			return fmt.Sprintf("scheduled event %s (%s) at %s", event.EventID, event.Code, event.NotBefore), nil
+		}
+	}
+	return "", nil
+}
+
+// token gets a session token of the instance metadata service, which is required when IMDSv2 is enforced
+func (w *awsWatcher) token(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, w.endpoint+"/latest/api/token", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", "60")
+	response, err := w.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error getting metadata token: %v", err)
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", fmt.Errorf("error reading metadata token: %v", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %q getting metadata token", response.Status)
+	}
+	return string(body), nil
+}
+
+// get reads a metadata path; found is false if the metadata does not exist
+func (w *awsWatcher) get(ctx context.Context, token string, path string) (data string, found bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, w.endpoint+path, nil)
+	if err != nil {
+		return "", false, err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token", token)
+	response, err := w.httpClient.Do(req)
+	if err != nil {
+		return "", false, fmt.Errorf("error reading metadata %s: %v", path, err)
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", false, fmt.Errorf("error reading metadata %s: %v", path, err)
+	}
+	switch response.StatusCode {
+	case http.StatusOK:
+		return string(body), true, nil
+	case http.StatusNotFound:
+		return "", false, nil
+	default:
+		return "", false, fmt.Errorf("unexpected status %q reading metadata %s", response.Status, path)
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudevents
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const gceMetadataEndpoint = "http://metadata.google.internal"
+
+// gceWatcher reads the preemption and host maintenance notices from the GCE metadata server
+type gceWatcher struct {
+	httpClient *http.Client
+	endpoint   string
+}
+
+func (w *gceWatcher) Poll(ctx context.Context) (string, error) {
+	// Preemptible instances are stopped 30 seconds after they are preempted
+	preempted, err := w.get(ctx, "/computeMetadata/v1/instance/preempted")
+	if err != nil {
+		return "", err
+	}
+	if preempted == "TRUE" {
+		return "preemption", nil
+	}
+
+	// Instances that migrate on host maintenance keep running; the others are terminated
+	maintenance, err := w.get(ctx, "/computeMetadata/v1/instance/maintenance-event")
+	if err != nil {
+		return "", err
+	}
+	if maintenance == "TERMINATE_ON_HOST_MAINTENANCE" {
+		return "host maintenance", nil
+	}
+	return "", nil
+}
+
+func (w *gceWatcher) get(ctx context.Context, path string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, w.endpoint+path, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	response, err := w.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error reading metadata %s: %v", path, err)
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", fmt.Errorf("error reading metadata %s: %v", path, err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %q reading metadata %s", response.Status, path)
+	}
+	return strings.TrimSpace(string(body)), nil
+}
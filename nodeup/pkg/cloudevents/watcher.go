@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudevents
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+// Watcher reads the interruption notices of the cloud provider for this instance
+type Watcher interface {
+	// Poll returns a description of the interruption the cloud provider has announced, or "" if there is none
+	Poll(ctx context.Context) (string, error)
+}
+
+// NewWatcher builds the Watcher for the cloud provider
+func NewWatcher(cloud kops.CloudProviderID) (Watcher, error) {
+	httpClient := &http.Client{Timeout: 5 * time.Second}
+	switch cloud {
+	case kops.CloudProviderAWS:
+		return &awsWatcher{
+			httpClient: httpClient,
+			endpoint:   awsMetadataEndpoint,
+			window:     awsScheduledEventWindow,
+			now:        time.Now,
+		}, nil
+	case kops.CloudProviderGCE:
+		return &gceWatcher{
+			httpClient: httpClient,
+			endpoint:   gceMetadataEndpoint,
+		}, nil
+	default:
+		return nil, fmt.Errorf("interruption notices are not supported on cloud provider %q", cloud)
+	}
+}
+
+// Run polls the watcher every interval until the cloud provider announces an interruption, then calls shutdown once.
+// Errors reading the notices are logged and do not stop the polling.
+func Run(ctx context.Context, watcher Watcher, interval time.Duration, shutdown func() error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		event, err := watcher.Poll(ctx)
+		if err != nil {
+			klog.Warningf("error reading interruption notices: %v", err)
+		} else if event != "" {
+			klog.Infof("shutting down the instance for %s", event)
+			return shutdown()
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Shutdown powers the instance off through systemd-logind, which waits for the kubelet's shutdown inhibitor
+// so the pods are terminated within their grace periods.
+func Shutdown() error {
+	output, err := exec.Command("systemctl", "poweroff").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error powering off the instance: %v: %s", err, string(output))
+	}
+	return nil
+}
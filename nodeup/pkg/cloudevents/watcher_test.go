@@ -0,0 +1,210 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudevents
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAWSWatcher(t *testing.T) {
+	now := time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	grid := []struct {
+		Name      string
+		Metadata  map[string]string
+		Expected  string
+		ExpectErr bool
+	}{
+		{
+			Name: "no events",
+			Metadata: map[string]string{
+				"/latest/meta-data/events/maintenance/scheduled": `[]`,
+			},
+		},
+		{
+			Name: "spot interruption",
+			Metadata: map[string]string{
+				"/latest/meta-data/spot/instance-action":         `{"action": "terminate", "time": "2021-06-01T12:02:00Z"}`,
+				"/latest/meta-data/events/maintenance/scheduled": `[]`,
+			},
+			Expected: "spot interruption",
+		},
+		{
+			Name: "scheduled event within the window",
+			Metadata: map[string]string{
+				"/latest/meta-data/events/maintenance/scheduled": `[{"Code": "instance-retirement", "EventId": "instance-event-1", "NotBefore": "1 Jun 2021 12:05:00 GMT", "State": "active"}]`,
+			},
+			Expected: "scheduled event instance-event-1 (instance-retirement)",
+		},
+		{
+			Name: "scheduled event later",
+			Metadata: map[string]string{
+				"/latest/meta-data/events/maintenance/scheduled": `[{"Code": "system-reboot", "EventId": "instance-event-2", "NotBefore": "3 Jun 2021 12:00:00 GMT", "State": "active"}]`,
+			},
+		},
+		{
+			Name: "completed scheduled event",
+			Metadata: map[string]string{
+				"/latest/meta-data/events/maintenance/scheduled": `[{"Code": "system-reboot", "EventId": "instance-event-3", "NotBefore": "1 Jun 2021 11:00:00 GMT", "State": "completed"}]`,
+			},
+		},
+		{
+			Name: "invalid scheduled events",
+			Metadata: map[string]string{
+				"/latest/meta-data/events/maintenance/scheduled": `{`,
+			},
+			ExpectErr: true,
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/latest/api/token" {
+					if r.Method != http.MethodPut {
+						w.WriteHeader(http.StatusMethodNotAllowed)
+						return
+					}
+					_, _ = w.Write([]byte("token"))
+					return
+				}
+				if r.Header.Get("X-aws-ec2-metadata-token") != "token" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				data, found := g.Metadata[r.URL.Path]
+				if !found {
+					http.NotFound(w, r)
+					return
+				}
+				_, _ = w.Write([]byte(data))
+			}))
+			defer server.Close()
+
+			watcher := &awsWatcher{
+				httpClient: server.Client(),
+				endpoint:   server.URL,
+				window:     awsScheduledEventWindow,
+				now:        func() time.Time { return now },
+			}
+			event, err := watcher.Poll(context.TODO())
+			if g.ExpectErr {
+				if err == nil {
+					t.Fatalf("expected error, got event %q", event)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (g.Expected == "") != (event == "") || !strings.HasPrefix(event, g.Expected) {
+				t.Errorf("expected event %q, got %q", g.Expected, event)
+			}
+		})
+	}
+}
+
+func TestGCEWatcher(t *testing.T) {
+	grid := []struct {
+		Preempted   string
+		Maintenance string
+		Expected    string
+	}{
+		{
+			Preempted:   "FALSE",
+			Maintenance: "NONE",
+		},
+		{
+			Preempted:   "FALSE",
+			Maintenance: "MIGRATE_ON_HOST_MAINTENANCE",
+		},
+		{
+			Preempted:   "TRUE",
+			Maintenance: "NONE",
+			Expected:    "preemption",
+		},
+		{
+			Preempted:   "FALSE",
+			Maintenance: "TERMINATE_ON_HOST_MAINTENANCE",
+			Expected:    "host maintenance",
+		},
+	}
+
+	for _, g := range grid {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Metadata-Flavor") != "Google" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			switch r.URL.Path {
+			case "/computeMetadata/v1/instance/preempted":
+				_, _ = w.Write([]byte(g.Preempted))
+			case "/computeMetadata/v1/instance/maintenance-event":
+				_, _ = w.Write([]byte(g.Maintenance))
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+
+		watcher := &gceWatcher{
+			httpClient: server.Client(),
+			endpoint:   server.URL,
+		}
+		event, err := watcher.Poll(context.TODO())
+		server.Close()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if event != g.Expected {
+			t.Errorf("expected event %q for %s/%s, got %q", g.Expected, g.Preempted, g.Maintenance, event)
+		}
+	}
+}
+
+type fakeWatcher struct {
+	events []string
+	polls  int
+}
+
+func (w *fakeWatcher) Poll(ctx context.Context) (string, error) {
+	event := w.events[w.polls]
+	w.polls++
+	return event, nil
+}
+
+func TestRun(t *testing.T) {
+	watcher := &fakeWatcher{events: []string{"", "", "preemption"}}
+	shutdowns := 0
+	err := Run(context.TODO(), watcher, time.Millisecond, func() error {
+		shutdowns++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if watcher.polls != 3 {
+		t.Errorf("expected 3 polls, got %d", watcher.polls)
+	}
+	if shutdowns != 1 {
+		t.Errorf("expected 1 shutdown, got %d", shutdowns)
+	}
+}
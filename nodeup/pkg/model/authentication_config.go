@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"sort"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+// AuthenticationConfiguration is the structured authentication configuration read by the API server
+type AuthenticationConfiguration struct {
+	APIVersion string             `json:"apiVersion"`
+	Kind       string             `json:"kind"`
+	JWT        []JWTAuthenticator `json:"jwt"`
+}
+
+// JWTAuthenticator authenticates users with JWTs issued by a single issuer
+type JWTAuthenticator struct {
+	Issuer               JWTIssuer             `json:"issuer"`
+	ClaimValidationRules []ClaimValidationRule `json:"claimValidationRules,omitempty"`
+	ClaimMappings        ClaimMappings         `json:"claimMappings"`
+}
+
+// JWTIssuer identifies the issuer and audiences of the accepted tokens
+type JWTIssuer struct {
+	URL                  string   `json:"url"`
+	Audiences            []string `json:"audiences"`
+	CertificateAuthority string   `json:"certificateAuthority,omitempty"`
+}
+
+// ClaimValidationRule requires a claim to be present with the given value
+type ClaimValidationRule struct {
+	Claim         string `json:"claim"`
+	RequiredValue string `json:"requiredValue"`
+}
+
+// ClaimMappings maps claims to the attributes of the user
+type ClaimMappings struct {
+	Username PrefixedClaim  `json:"username"`
+	Groups   *PrefixedClaim `json:"groups,omitempty"`
+}
+
+// PrefixedClaim maps a claim to a user attribute; the prefix is required by the API server, even if empty
+type PrefixedClaim struct {
+	Claim  string  `json:"claim"`
+	Prefix *string `json:"prefix"`
+}
+
+// buildAuthenticationConfiguration builds the structured authentication configuration for an OIDC provider.
+// The defaults match the behaviour of the equivalent --oidc flags.
+func buildAuthenticationConfiguration(oidc *kops.OIDCAuthenticationSpec) ([]byte, error) {
+	usernamePrefix := ""
+	if oidc.UsernamePrefix != nil {
+		usernamePrefix = *oidc.UsernamePrefix
+	} else if oidc.UsernameClaim != "email" {
+		usernamePrefix = oidc.IssuerURL + "#"
+	}
+
+	authenticator := JWTAuthenticator{
+		Issuer: JWTIssuer{
+			URL:                  oidc.IssuerURL,
+			Audiences:            []string{oidc.ClientID},
+			CertificateAuthority: oidc.CertificateAuthority,
+		},
+		ClaimMappings: ClaimMappings{
+			Username: PrefixedClaim{
+				Claim:  oidc.UsernameClaim,
+				Prefix: &usernamePrefix,
+			},
+		},
+	}
+	if oidc.GroupsClaim != "" {
+		groupsPrefix := oidc.GroupsPrefix
+		authenticator.ClaimMappings.Groups = &PrefixedClaim{
+			Claim:  oidc.GroupsClaim,
+			Prefix: &groupsPrefix,
+		}
+	}
+	for claim, value := range oidc.RequiredClaims {
+		authenticator.ClaimValidationRules = append(authenticator.ClaimValidationRules, ClaimValidationRule{
+			Claim:         claim,
+			RequiredValue: value,
+		})
+	}
+	sort.Slice(authenticator.ClaimValidationRules, func(i, j int) bool {
+		return authenticator.ClaimValidationRules[i].Claim < authenticator.ClaimValidationRules[j].Claim
+	})
+
+	config := &AuthenticationConfiguration{
+		APIVersion: "apiserver.config.k8s.io/v1beta1",
+		Kind:       "AuthenticationConfiguration",
+		JWT:        []JWTAuthenticator{authenticator},
+	}
+	return kops.ToRawYaml(config)
+}
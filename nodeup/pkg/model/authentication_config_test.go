@@ -0,0 +1,112 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestBuildAuthenticationConfiguration(t *testing.T) {
+	grid := []struct {
+		Input    kops.OIDCAuthenticationSpec
+		Expected string
+	}{
+		{
+			Input: kops.OIDCAuthenticationSpec{
+				IssuerURL:     "https://issuer.example.com",
+				ClientID:      "kubernetes",
+				UsernameClaim: "sub",
+			},
+			Expected: `apiVersion: apiserver.config.k8s.io/v1beta1
+jwt:
+- claimMappings:
+    username:
+      claim: sub
+      prefix: https://issuer.example.com#
+  issuer:
+    audiences:
+    - kubernetes
+    url: https://issuer.example.com
+kind: AuthenticationConfiguration
+`,
+		},
+		{
+			Input: kops.OIDCAuthenticationSpec{
+				IssuerURL:      "https://issuer.example.com",
+				ClientID:       "kubernetes",
+				UsernameClaim:  "email",
+				GroupsClaim:    "groups",
+				GroupsPrefix:   "oidc:",
+				RequiredClaims: map[string]string{"hd": "example.com", "aud": "kubernetes"},
+			},
+			Expected: `apiVersion: apiserver.config.k8s.io/v1beta1
+jwt:
+- claimMappings:
+    groups:
+      claim: groups
+      prefix: 'oidc:'
+    username:
+      claim: email
+      prefix: ""
+  claimValidationRules:
+  - claim: aud
+    requiredValue: kubernetes
+  - claim: hd
+    requiredValue: example.com
+  issuer:
+    audiences:
+    - kubernetes
+    url: https://issuer.example.com
+kind: AuthenticationConfiguration
+`,
+		},
+		{
+			Input: kops.OIDCAuthenticationSpec{
+				IssuerURL:      "https://issuer.example.com",
+				ClientID:       "kubernetes",
+				UsernameClaim:  "sub",
+				UsernamePrefix: fi.String("oidc:"),
+			},
+			Expected: `apiVersion: apiserver.config.k8s.io/v1beta1
+jwt:
+- claimMappings:
+    username:
+      claim: sub
+      prefix: 'oidc:'
+  issuer:
+    audiences:
+    - kubernetes
+    url: https://issuer.example.com
+kind: AuthenticationConfiguration
+`,
+		},
+	}
+
+	for _, g := range grid {
+		actual, err := buildAuthenticationConfiguration(&g.Input)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			continue
+		}
+		if string(actual) != g.Expected {
+			t.Errorf("unexpected configuration; expected:\n%s\ngot:\n%s", g.Expected, actual)
+		}
+	}
+}
@@ -18,14 +18,18 @@ package model
 
 import (
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/url"
+	"os"
 	"strconv"
 
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/jointoken"
 	"k8s.io/kops/pkg/wellknownports"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
 )
 
@@ -39,18 +43,7 @@ func (b BootstrapClientBuilder) Build(c *fi.ModelBuilderContext) error {
 		return nil
 	}
 
-	var authenticator fi.Authenticator
-	var err error
-	switch kops.CloudProviderID(b.Cluster.Spec.CloudProvider) {
-	case kops.CloudProviderAWS:
-		region, regionErr := awsup.FindRegion(b.Cluster)
-		if regionErr != nil {
-			return fmt.Errorf("querying AWS region: %v", regionErr)
-		}
-		authenticator, err = awsup.NewAWSAuthenticator(region)
-	default:
-		return fmt.Errorf("unsupported cloud provider %s", b.Cluster.Spec.CloudProvider)
-	}
+	authenticator, err := b.buildAuthenticator()
 	if err != nil {
 		return err
 	}
@@ -86,4 +79,34 @@ func (b BootstrapClientBuilder) Build(c *fi.ModelBuilderContext) error {
 	return nil
 }
 
+// buildAuthenticator returns the authenticator that proves the identity of the node to kops-controller.
+// Machines that have been given a join token use it in preference to their cloud identity.
+func (b BootstrapClientBuilder) buildAuthenticator() (fi.Authenticator, error) {
+	if b.UseJoinTokensForNodeBootstrap() {
+		token, err := ioutil.ReadFile(jointoken.TokenPath)
+		if err == nil {
+			nodeName, err := b.NodeName()
+			if err != nil {
+				return nil, err
+			}
+			return jointoken.NewAuthenticator(string(token), nodeName)
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("reading join token: %v", err)
+		}
+	}
+
+	switch kops.CloudProviderID(b.Cluster.Spec.CloudProvider) {
+	case kops.CloudProviderAWS:
+		region, err := awsup.FindRegion(b.Cluster)
+		if err != nil {
+			return nil, fmt.Errorf("querying AWS region: %v", err)
+		}
+		return awsup.NewAWSAuthenticator(region)
+	case kops.CloudProviderGCE:
+		return gce.NewGCEAuthenticator(b.Cluster.ObjectMeta.Name)
+	default:
+		return nil, fmt.Errorf("unsupported cloud provider %s", b.Cluster.Spec.CloudProvider)
+	}
+}
+
 var _ fi.ModelBuilder = &BootstrapClientBuilder{}
@@ -0,0 +1,142 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+// BottlerocketBuilder configures the kubelet, containerd and kernel of Bottlerocket through its settings API
+type BottlerocketBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &BottlerocketBuilder{}
+
+// Build is responsible for translating the node configuration into Bottlerocket settings
+func (b *BottlerocketBuilder) Build(c *fi.ModelBuilderContext) error {
+	if !b.Distribution.UsesSettingsAPI() {
+		return nil
+	}
+
+	if kops.CloudProviderID(b.Cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
+		return fmt.Errorf("bottlerocket is only supported on AWS")
+	}
+
+	ca, err := b.GetCert(fi.CertificateIDCA)
+	if err != nil {
+		return err
+	}
+
+	kubeletConfig, err := (&KubeletBuilder{NodeupModelContext: b.NodeupModelContext}).buildKubeletConfigSpec()
+	if err != nil {
+		return err
+	}
+
+	settings, err := b.buildSettings(kubeletConfig, ca)
+	if err != nil {
+		return err
+	}
+
+	c.AddTask(&nodetasks.BottlerocketSettings{
+		Name:     "kops",
+		Settings: settings,
+	})
+
+	return nil
+}
+
+// buildSettings returns the settings tree for the node, rooted below the "settings" key of the API
+func (b *BottlerocketBuilder) buildSettings(kubeletConfig *kops.KubeletConfigSpec, ca []byte) (map[string]interface{}, error) {
+	kubernetes := map[string]interface{}{
+		"cluster-name":        b.Cluster.ObjectMeta.Name,
+		"api-server":          "https://" + b.Cluster.Spec.MasterInternalName,
+		"cluster-certificate": base64.StdEncoding.EncodeToString(ca),
+		// Bottlerocket nodes authenticate to the API server through aws-iam-authenticator
+		"authentication-mode": "aws",
+	}
+	if kubeletConfig.ClusterDNS != "" {
+		kubernetes["cluster-dns-ip"] = kubeletConfig.ClusterDNS
+	}
+	if kubeletConfig.ClusterDomain != "" {
+		kubernetes["cluster-domain"] = kubeletConfig.ClusterDomain
+	}
+	if kubeletConfig.MaxPods != nil {
+		kubernetes["max-pods"] = *kubeletConfig.MaxPods
+	}
+	if kubeletConfig.PodInfraContainerImage != "" {
+		kubernetes["pod-infra-container-image"] = kubeletConfig.PodInfraContainerImage
+	}
+	if len(kubeletConfig.Taints) > 0 {
+		taints := make(map[string]string)
+		for _, taint := range kubeletConfig.Taints {
+			// Taints are in the <Key>=<Value>:<Effect> form, while Bottlerocket maps the key to <Value>:<Effect>
+			i := strings.LastIndex(taint, ":")
+			if i == -1 {
+				return nil, fmt.Errorf("invalid taint %q: expected it to contain ':'", taint)
+			}
+			key, value := taint[:i], ""
+			if j := strings.Index(key, "="); j != -1 {
+				key, value = key[:j], key[j+1:]
+			}
+			taints[key] = value + taint[i:]
+		}
+		kubernetes["node-taints"] = taints
+	}
+
+	settings := map[string]interface{}{
+		"kubernetes": kubernetes,
+	}
+
+	if b.Cluster.Spec.Containerd != nil && len(b.Cluster.Spec.Containerd.RegistryMirrors) > 0 {
+		settings["container-registry"] = map[string]interface{}{
+			"mirrors": b.Cluster.Spec.Containerd.RegistryMirrors,
+		}
+	}
+
+	// Cluster parameters are applied last, so they take precedence like they do in the sysctl.d file
+	var params []string
+	params = append(params, b.NodeupConfig.SysctlParameters...)
+	params = append(params, b.Cluster.Spec.SysctlParameters...)
+	sysctls := make(map[string]string)
+	for _, param := range params {
+		i := strings.Index(param, "=")
+		if i == -1 {
+			return nil, fmt.Errorf("Invalid SysctlParameter: expected %q to contain '='", param)
+		}
+		sysctls[strings.TrimSpace(param[:i])] = strings.TrimSpace(param[i+1:])
+	}
+	// The structured node tuning sysctls are written after the parameter lists, so they take precedence
+	if nodeTuning := b.NodeupConfig.NodeTuning; nodeTuning != nil {
+		for k, v := range nodeTuning.Sysctls {
+			sysctls[k] = v
+		}
+	}
+	if len(sysctls) > 0 {
+		settings["kernel"] = map[string]interface{}{
+			"sysctl": sysctls,
+		}
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestBottlerocketSettings(t *testing.T) {
+	cluster := &kops.Cluster{}
+	cluster.ObjectMeta.Name = "minimal.example.com"
+	cluster.Spec.MasterInternalName = "api.internal.minimal.example.com"
+	cluster.Spec.Containerd = &kops.ContainerdConfig{
+		RegistryMirrors: map[string][]string{
+			"docker.io": {"https://registry-1.docker.io"},
+		},
+	}
+	cluster.Spec.SysctlParameters = []string{"net.ipv4.tcp_keepalive_time = 200"}
+
+	b := &BottlerocketBuilder{
+		NodeupModelContext: &NodeupModelContext{
+			Cluster: cluster,
+			NodeupConfig: &nodeup.Config{
+				SysctlParameters: []string{"net.ipv4.tcp_keepalive_time=100", "vm.swappiness=10"},
+			},
+		},
+	}
+
+	kubeletConfig := &kops.KubeletConfigSpec{
+		ClusterDNS:    "100.64.0.10",
+		ClusterDomain: "cluster.local",
+		MaxPods:       fi.Int32(58),
+		Taints:        []string{"dedicated=gpu:NoSchedule", "spot:PreferNoSchedule"},
+	}
+
+	settings, err := b.buildSettings(kubeletConfig, []byte("ca"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actual, err := json.Marshal(settings)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `{"container-registry":{"mirrors":{"docker.io":["https://registry-1.docker.io"]}},` +
+		`"kernel":{"sysctl":{"net.ipv4.tcp_keepalive_time":"200","vm.swappiness":"10"}},` +
+		`"kubernetes":{"api-server":"https://api.internal.minimal.example.com","authentication-mode":"aws","cluster-certificate":"Y2E=",` +
+		`"cluster-dns-ip":"100.64.0.10","cluster-domain":"cluster.local","cluster-name":"minimal.example.com","max-pods":58,` +
+		`"node-taints":{"dedicated":"gpu:NoSchedule","spot":":PreferNoSchedule"}}}`
+	if string(actual) != expected {
+		t.Errorf("unexpected settings; expected:\n%s\ngot:\n%s", expected, actual)
+	}
+
+	kubeletConfig.Taints = []string{"invalid"}
+	if _, err := b.buildSettings(kubeletConfig, []byte("ca")); err == nil {
+		t.Errorf("expected an error for an invalid taint")
+	}
+}
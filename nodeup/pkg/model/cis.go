@@ -0,0 +1,78 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/kops/pkg/model/components"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+// cisRestrictedPaths are the prefixes and suffixes of the files only root may read under the CIS hardening profile:
+// the static pod manifests, the certificates and keys, the kubeconfigs and the configuration of the kubelet
+var (
+	cisRestrictedPrefixes = []string{"/etc/kubernetes/manifests/", "/etc/kubernetes/pki/", "/srv/kubernetes/"}
+	cisRestrictedSuffixes = []string{"/kubeconfig", "/kubelet-config.yaml", "/etc/sysconfig/kubelet"}
+)
+
+// CISBuilder restricts the permissions of the files laid down by the other builders, as required by the CIS Kubernetes Benchmark.
+// It must run after all the builders writing files.
+type CISBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &CISBuilder{}
+
+// Build removes the group and other permissions of the root owned files holding the configuration and the credentials
+func (b *CISBuilder) Build(c *fi.ModelBuilderContext) error {
+	if !components.IsCISHardened(&b.Cluster.Spec) {
+		return nil
+	}
+
+	for _, task := range c.Tasks {
+		file, ok := task.(*nodetasks.File)
+		if !ok || file.Type != nodetasks.FileType_File || file.Owner != nil || !isCISRestrictedPath(file.Path) {
+			continue
+		}
+
+		mode, err := fi.ParseFileMode(fi.StringValue(file.Mode), 0644)
+		if err != nil {
+			return fmt.Errorf("parsing mode of %q: %v", file.Path, err)
+		}
+		file.Mode = fi.String(strconv.FormatUint(uint64(mode&^0077), 8))
+	}
+
+	return nil
+}
+
+func isCISRestrictedPath(path string) bool {
+	for _, prefix := range cisRestrictedPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	for _, suffix := range cisRestrictedSuffixes {
+		if strings.HasSuffix(path, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+func TestCISBuilder(t *testing.T) {
+	grid := []struct {
+		File     *nodetasks.File
+		Expected string
+	}{
+		{
+			File:     &nodetasks.File{Path: "/etc/kubernetes/manifests/kube-apiserver.manifest", Type: nodetasks.FileType_File},
+			Expected: "600",
+		},
+		{
+			File:     &nodetasks.File{Path: "/srv/kubernetes/kube-apiserver/server.crt", Mode: fi.String("0644"), Type: nodetasks.FileType_File},
+			Expected: "600",
+		},
+		{
+			File:     &nodetasks.File{Path: "/var/lib/kube-proxy/kubeconfig", Mode: fi.String("0400"), Type: nodetasks.FileType_File},
+			Expected: "400",
+		},
+		{
+			File:     &nodetasks.File{Path: "/var/lib/kubelet/kubelet-config.yaml", Mode: fi.String("0755"), Type: nodetasks.FileType_File},
+			Expected: "700",
+		},
+		{
+			File:     &nodetasks.File{Path: "/srv/kubernetes/kops-controller/keypair-ids.yaml", Mode: fi.String("0644"), Owner: fi.String("kops-controller"), Type: nodetasks.FileType_File},
+			Expected: "0644",
+		},
+		{
+			File:     &nodetasks.File{Path: "/etc/kubernetes/manifests", Mode: fi.String("0755"), Type: nodetasks.FileType_Directory},
+			Expected: "0755",
+		},
+		{
+			File:     &nodetasks.File{Path: "/etc/sysctl.d/99-k8s-general.conf", Mode: fi.String("0644"), Type: nodetasks.FileType_File},
+			Expected: "0644",
+		},
+	}
+
+	cluster := &kops.Cluster{}
+	cluster.Spec.Hardening = &kops.HardeningSpec{Profile: kops.HardeningProfileCIS}
+	b := &CISBuilder{
+		NodeupModelContext: &NodeupModelContext{
+			Cluster: cluster,
+		},
+	}
+	c := &fi.ModelBuilderContext{Tasks: make(map[string]fi.Task)}
+	for _, g := range grid {
+		c.AddTask(g.File)
+	}
+	if err := b.Build(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, g := range grid {
+		if mode := fi.StringValue(g.File.Mode); mode != g.Expected {
+			t.Errorf("expected mode %q for %s, got %q", g.Expected, g.File.Path, mode)
+		}
+	}
+}
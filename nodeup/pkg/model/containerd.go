@@ -17,12 +17,14 @@ limitations under the License.
 package model
 
 import (
+	"encoding/base64"
 	"fmt"
 	"path/filepath"
 	"regexp"
 	"strings"
 
 	"github.com/blang/semver/v4"
+	"github.com/pelletier/go-toml"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/nodeup/pkg/model/resources"
 	"k8s.io/kops/pkg/apis/kops"
@@ -34,6 +36,12 @@ import (
 	"k8s.io/kops/util/pkg/distributions"
 )
 
+// nvidiaRuntimeName is the name of the containerd runtime, and of the matching RuntimeClass, that runs containers with access to NVIDIA GPUs
+const nvidiaRuntimeName = "nvidia"
+
+// containerdRegistryConfigPath is the directory containing the hosts.toml file of each registry
+const containerdRegistryConfigPath = "/etc/containerd/certs.d"
+
 // ContainerdBuilder install containerd (just the packages at the moment)
 type ContainerdBuilder struct {
 	*NodeupModelContext
@@ -77,7 +85,9 @@ func (b *ContainerdBuilder) Build(c *fi.ModelBuilderContext) error {
 	}
 
 	// If there are containerd configuration overrides, apply them
-	b.buildOverrideConfigFile(c)
+	if err := b.buildOverrideConfigFile(c); err != nil {
+		return err
+	}
 
 	if installContainerd {
 		if err := b.installContainerd(c); err != nil {
@@ -160,7 +170,7 @@ func (b *ContainerdBuilder) buildSystemdService(sv semver.Version) *nodetasks.Se
 	manifest.Set("Unit", "After", "network.target local-fs.target")
 
 	// Restore the default SELinux security contexts for the containerd and runc binaries
-	if b.Distribution.IsRHELFamily() && b.Cluster.Spec.Docker != nil && fi.BoolValue(b.Cluster.Spec.Docker.SelinuxEnabled) {
+	if b.Distribution.IsRHELFamily() && b.selinuxEnabled() {
 		manifest.Set("Service", "ExecStartPre", "/bin/sh -c 'restorecon -v /usr/bin/runc'")
 		manifest.Set("Service", "ExecStartPre", "/bin/sh -c 'restorecon -v /usr/bin/containerd*'")
 	}
@@ -297,17 +307,190 @@ func (b *ContainerdBuilder) buildSysconfigFile(c *fi.ModelBuilderContext) error
 }
 
 // buildOverrideConfigFile is responsible for creating the containerd configuration file
-func (b *ContainerdBuilder) buildOverrideConfigFile(c *fi.ModelBuilderContext) {
+func (b *ContainerdBuilder) buildOverrideConfigFile(c *fi.ModelBuilderContext) error {
 	containerdConfigOverride := ""
 	if b.Cluster.Spec.Containerd != nil {
 		containerdConfigOverride = fi.StringValue(b.Cluster.Spec.Containerd.ConfigOverride)
 	}
 
+	if b.NodeupConfig.NvidiaGPU != nil && b.Cluster.Spec.ContainerRuntime == "containerd" {
+		config, err := addNvidiaRuntime(containerdConfigOverride)
+		if err != nil {
+			return err
+		}
+		containerdConfigOverride = config
+	}
+
+	if b.NodeupConfig.Containerd != nil && b.Cluster.Spec.ContainerRuntime == "containerd" {
+		config, err := applyContainerdSettings(containerdConfigOverride, b.NodeupConfig.Containerd)
+		if err != nil {
+			return err
+		}
+		containerdConfigOverride = config
+
+		b.buildRegistryHostsFiles(c, b.NodeupConfig.Containerd.Registries)
+	}
+
+	if b.NodeupConfig.SecurityModules != nil && fi.BoolValue(b.NodeupConfig.SecurityModules.SELinux) && b.Cluster.Spec.ContainerRuntime == "containerd" {
+		config, err := enableContainerdSELinux(containerdConfigOverride)
+		if err != nil {
+			return err
+		}
+		containerdConfigOverride = config
+	}
+
 	c.AddTask(&nodetasks.File{
 		Path:     b.containerdConfigFilePath(),
 		Contents: fi.NewStringResource(containerdConfigOverride),
 		Type:     nodetasks.FileType_File,
 	})
+
+	return nil
+}
+
+// enableContainerdSELinux runs the containers with SELinux labels
+func enableContainerdSELinux(containerdConfig string) (string, error) {
+	config, err := toml.Load(containerdConfig)
+	if err != nil {
+		return "", fmt.Errorf("error parsing containerd config: %v", err)
+	}
+	config.SetPath([]string{"plugins", "io.containerd.grpc.v1.cri", "enable_selinux"}, true)
+	return config.String(), nil
+}
+
+// addNvidiaRuntime adds the nvidia runtime of the NVIDIA container toolkit to the containerd config and makes it the default,
+// so that the GPUs assigned by the device plugin are made available to the containers
+func addNvidiaRuntime(containerdConfig string) (string, error) {
+	config, err := toml.Load(containerdConfig)
+	if err != nil {
+		return "", fmt.Errorf("error parsing containerd config: %v", err)
+	}
+
+	runtimes := []string{"plugins", "io.containerd.grpc.v1.cri", "containerd", "runtimes"}
+	if !config.HasPath(append(runtimes, nvidiaRuntimeName)) {
+		config.SetPath(append(runtimes, nvidiaRuntimeName, "runtime_type"), "io.containerd.runc.v2")
+		config.SetPath(append(runtimes, nvidiaRuntimeName, "options", "BinaryName"), "/usr/bin/nvidia-container-runtime")
+		if systemdCgroup, ok := config.GetPath(append(runtimes, "runc", "options", "SystemdCgroup")).(bool); ok {
+			config.SetPath(append(runtimes, nvidiaRuntimeName, "options", "SystemdCgroup"), systemdCgroup)
+		}
+	}
+	config.SetPath([]string{"plugins", "io.containerd.grpc.v1.cri", "containerd", "default_runtime_name"}, nvidiaRuntimeName)
+
+	return config.String(), nil
+}
+
+// applyContainerdSettings merges the structured containerd settings of the cluster and instance group into the containerd config
+func applyContainerdSettings(containerdConfig string, settings *kops.ContainerdConfig) (string, error) {
+	config, err := toml.Load(containerdConfig)
+	if err != nil {
+		return "", fmt.Errorf("error parsing containerd config: %v", err)
+	}
+
+	cri := []string{"plugins", "io.containerd.grpc.v1.cri"}
+	if len(settings.Registries) > 0 {
+		config.SetPath(append(cri, "registry", "config_path"), containerdRegistryConfigPath)
+	}
+	if settings.SandboxImage != nil {
+		config.SetPath(append(cri, "sandbox_image"), *settings.SandboxImage)
+	}
+	if settings.NRI != nil {
+		config.SetPath([]string{"plugins", "io.containerd.nri.v1.nri", "disable"}, !fi.BoolValue(settings.NRI.Enabled))
+	}
+
+	runtimes := append(cri, "containerd", "runtimes")
+	for name, runtime := range settings.Runtimes {
+		config.SetPath(append(runtimes, name, "runtime_type"), runtime.Type)
+		for key, value := range runtime.Options {
+			// Boolean options, such as SystemdCgroup, are not accepted as strings
+			switch value {
+			case "true":
+				config.SetPath(append(runtimes, name, "options", key), true)
+			case "false":
+				config.SetPath(append(runtimes, name, "options", key), false)
+			default:
+				config.SetPath(append(runtimes, name, "options", key), value)
+			}
+		}
+	}
+
+	return config.String(), nil
+}
+
+// buildRegistryHostsFiles creates the hosts.toml file, and the CA certificate, of each registry
+func (b *ContainerdBuilder) buildRegistryHostsFiles(c *fi.ModelBuilderContext, registries map[string]kops.ContainerdRegistryConfig) {
+	for host, registry := range registries {
+		dir := filepath.Join(containerdRegistryConfigPath, host)
+
+		caPath := ""
+		if registry.CA != nil {
+			caPath = filepath.Join(dir, "ca.crt")
+			c.AddTask(&nodetasks.File{
+				Path:     caPath,
+				Contents: fi.NewStringResource(*registry.CA),
+				Type:     nodetasks.FileType_File,
+			})
+		}
+
+		// Only root may read the credentials
+		mode := "0644"
+		if registry.Username != nil {
+			mode = "0600"
+		}
+		c.AddTask(&nodetasks.File{
+			Path:     filepath.Join(dir, "hosts.toml"),
+			Contents: fi.NewStringResource(buildRegistryHostsFile(host, registry, caPath)),
+			Type:     nodetasks.FileType_File,
+			Mode:     s(mode),
+		})
+	}
+}
+
+// buildRegistryHostsFile renders the hosts.toml file of a registry; the mirrors are tried in order before the registry itself
+func buildRegistryHostsFile(host string, registry kops.ContainerdRegistryConfig, caPath string) string {
+	server := "https://" + host
+	if host == "docker.io" {
+		server = "https://registry-1.docker.io"
+	}
+
+	var sb strings.Builder
+	writeHost := func(prefix string) {
+		if caPath != "" {
+			sb.WriteString(fmt.Sprintf("%sca = %q\n", prefix, caPath))
+		}
+		if fi.BoolValue(registry.SkipVerify) {
+			sb.WriteString(fmt.Sprintf("%sskip_verify = true\n", prefix))
+		}
+	}
+	writeHeader := func(table string) {
+		if registry.Username == nil {
+			return
+		}
+		auth := base64.StdEncoding.EncodeToString([]byte(fi.StringValue(registry.Username) + ":" + fi.StringValue(registry.Password)))
+		sb.WriteString(fmt.Sprintf("\n[%s]\n", table))
+		sb.WriteString(fmt.Sprintf("  Authorization = %q\n", "Basic "+auth))
+	}
+
+	sb.WriteString(fmt.Sprintf("server = %q\n", server))
+	writeHost("")
+	writeHeader("header")
+	for _, mirror := range registry.Mirrors {
+		sb.WriteString(fmt.Sprintf("\n[host.%q]\n", mirror))
+		sb.WriteString("  capabilities = [\"pull\", \"resolve\"]\n")
+		writeHost("  ")
+		writeHeader(fmt.Sprintf("host.%q.header", mirror))
+	}
+	return sb.String()
+}
+
+// selinuxEnabled determines if containers are run with SELinux labels
+func (b *ContainerdBuilder) selinuxEnabled() bool {
+	if b.Cluster.Spec.Containerd != nil && fi.BoolValue(b.Cluster.Spec.Containerd.SelinuxEnabled) {
+		return true
+	}
+	if b.NodeupConfig.SecurityModules != nil && fi.BoolValue(b.NodeupConfig.SecurityModules.SELinux) {
+		return true
+	}
+	return b.Cluster.Spec.Docker != nil && fi.BoolValue(b.Cluster.Spec.Docker.SelinuxEnabled)
 }
 
 // skipInstall determines if kops should skip the installation and configuration of containerd
@@ -162,3 +162,156 @@ func runContainerdBuilderTest(t *testing.T, key string, distro distributions.Dis
 
 	testutils.ValidateTasks(t, filepath.Join(basedir, "tasks.yaml"), context)
 }
+
+func TestAddNvidiaRuntime(t *testing.T) {
+	config := `version = 2
+
+[plugins]
+
+  [plugins."io.containerd.grpc.v1.cri"]
+
+    [plugins."io.containerd.grpc.v1.cri".containerd]
+
+      [plugins."io.containerd.grpc.v1.cri".containerd.runtimes]
+
+        [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc]
+          runtime_type = "io.containerd.runc.v2"
+
+          [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc.options]
+            SystemdCgroup = true
+`
+
+	actual, err := addNvidiaRuntime(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `version = 2
+
+[plugins]
+
+  [plugins."io.containerd.grpc.v1.cri"]
+
+    [plugins."io.containerd.grpc.v1.cri".containerd]
+      default_runtime_name = "nvidia"
+
+      [plugins."io.containerd.grpc.v1.cri".containerd.runtimes]
+
+        [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.nvidia]
+          runtime_type = "io.containerd.runc.v2"
+
+          [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.nvidia.options]
+            BinaryName = "/usr/bin/nvidia-container-runtime"
+            SystemdCgroup = true
+
+        [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc]
+          runtime_type = "io.containerd.runc.v2"
+
+          [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc.options]
+            SystemdCgroup = true
+`
+	if actual != expected {
+		t.Errorf("unexpected containerd config; expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+func TestApplyContainerdSettings(t *testing.T) {
+	config := `version = 2
+
+[plugins]
+
+  [plugins."io.containerd.grpc.v1.cri"]
+
+    [plugins."io.containerd.grpc.v1.cri".containerd]
+
+      [plugins."io.containerd.grpc.v1.cri".containerd.runtimes]
+
+        [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc]
+          runtime_type = "io.containerd.runc.v2"
+`
+
+	settings := &kops.ContainerdConfig{
+		NRI: &kops.ContainerdNRIConfig{Enabled: fi.Bool(true)},
+		Registries: map[string]kops.ContainerdRegistryConfig{
+			"docker.io": {Mirrors: []string{"https://mirror.example.com"}},
+		},
+		Runtimes: map[string]kops.ContainerdRuntimeConfig{
+			"kata": {Type: "io.containerd.kata.v2", Options: map[string]string{"ConfigPath": "/etc/kata/configuration.toml", "SystemdCgroup": "true"}},
+		},
+		SandboxImage: fi.String("registry.example.com/pause:3.5"),
+	}
+
+	actual, err := applyContainerdSettings(config, settings)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `version = 2
+
+[plugins]
+
+  [plugins."io.containerd.grpc.v1.cri"]
+    sandbox_image = "registry.example.com/pause:3.5"
+
+    [plugins."io.containerd.grpc.v1.cri".containerd]
+
+      [plugins."io.containerd.grpc.v1.cri".containerd.runtimes]
+
+        [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.kata]
+          runtime_type = "io.containerd.kata.v2"
+
+          [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.kata.options]
+            ConfigPath = "/etc/kata/configuration.toml"
+            SystemdCgroup = true
+
+        [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc]
+          runtime_type = "io.containerd.runc.v2"
+
+    [plugins."io.containerd.grpc.v1.cri".registry]
+      config_path = "/etc/containerd/certs.d"
+
+  [plugins."io.containerd.nri.v1.nri"]
+    disable = false
+`
+	if actual != expected {
+		t.Errorf("unexpected containerd config; expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+func TestBuildRegistryHostsFile(t *testing.T) {
+	registry := kops.ContainerdRegistryConfig{
+		Mirrors:    []string{"https://mirror-a.example.com", "https://mirror-b.example.com"},
+		SkipVerify: fi.Bool(true),
+		Username:   fi.String("user"),
+		Password:   fi.String("pass"),
+	}
+
+	actual := buildRegistryHostsFile("docker.io", registry, "/etc/containerd/certs.d/docker.io/ca.crt")
+
+	expected := `server = "https://registry-1.docker.io"
+ca = "/etc/containerd/certs.d/docker.io/ca.crt"
+skip_verify = true
+
+[header]
+  Authorization = "Basic dXNlcjpwYXNz"
+
+[host."https://mirror-a.example.com"]
+  capabilities = ["pull", "resolve"]
+  ca = "/etc/containerd/certs.d/docker.io/ca.crt"
+  skip_verify = true
+
+[host."https://mirror-a.example.com".header]
+  Authorization = "Basic dXNlcjpwYXNz"
+
+[host."https://mirror-b.example.com"]
+  capabilities = ["pull", "resolve"]
+  ca = "/etc/containerd/certs.d/docker.io/ca.crt"
+  skip_verify = true
+
+[host."https://mirror-b.example.com".header]
+  Authorization = "Basic dXNlcjpwYXNz"
+`
+	if actual != expected {
+		t.Errorf("unexpected hosts.toml; expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
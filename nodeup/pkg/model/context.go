@@ -340,6 +340,17 @@ func (c *NodeupModelContext) IsKubernetesLT(version string) bool {
 	return !c.IsKubernetesGTE(version)
 }
 
+// UseExternalEtcd checks if the etcd clusters are managed outside of kOps
+func (c *NodeupModelContext) UseExternalEtcd() bool {
+	for _, x := range c.Cluster.Spec.EtcdClusters {
+		if x.IsExternal() {
+			return true
+		}
+	}
+
+	return false
+}
+
 // UseEtcdManager checks if the etcd cluster has etcd-manager enabled
 func (c *NodeupModelContext) UseEtcdManager() bool {
 	for _, x := range c.Cluster.Spec.EtcdClusters {
@@ -391,6 +402,11 @@ func (c *NodeupModelContext) UseKopsControllerForNodeBootstrap() bool {
 	return model.UseKopsControllerForNodeBootstrap(c.Cluster)
 }
 
+// UseJoinTokensForNodeBootstrap checks if kops-controller accepts join tokens from bootstrapping nodes.
+func (c *NodeupModelContext) UseJoinTokensForNodeBootstrap() bool {
+	return model.UseJoinTokensForNodeBootstrap(c.Cluster)
+}
+
 // UsesSecondaryIP checks if the CNI in use attaches secondary interfaces to the host.
 func (c *NodeupModelContext) UsesSecondaryIP() bool {
 	return (c.Cluster.Spec.Networking.CNI != nil && c.Cluster.Spec.Networking.CNI.UsesSecondaryIP) || c.Cluster.Spec.Networking.AmazonVPC != nil || c.Cluster.Spec.Networking.LyftVPC != nil ||
@@ -647,6 +663,10 @@ func (c *NodeupModelContext) CNIConfDir() string {
 
 func (c *NodeupModelContext) WarmPullImage(ctx *fi.ModelBuilderContext, imageName string) {
 	if c.ConfigurationMode == "Warming" {
+		// The image may already be preloaded for the instance group
+		if _, found := ctx.Tasks["PullImageTask/"+imageName]; found {
+			return
+		}
 		image := &nodetasks.PullImageTask{
 			Name:    imageName,
 			Runtime: c.Cluster.Spec.ContainerRuntime,
@@ -0,0 +1,292 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/k8scodecs"
+	"k8s.io/kops/pkg/kubemanifest"
+	"k8s.io/kops/pkg/wellknownports"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+	"sigs.k8s.io/yaml"
+)
+
+// encryptionProviderSocketDir is the directory holding the sockets the KMS plugins listen on
+const encryptionProviderSocketDir = "/var/run/kmsplugin"
+
+// EncryptionConfiguration is the configuration the API server reads to encrypt resources in etcd
+type EncryptionConfiguration struct {
+	APIVersion string                     `json:"apiVersion"`
+	Kind       string                     `json:"kind"`
+	Resources  []EncryptionResourceConfig `json:"resources"`
+}
+
+// EncryptionResourceConfig lists the providers that encrypt and decrypt a set of resources
+type EncryptionResourceConfig struct {
+	Resources []string                   `json:"resources"`
+	Providers []EncryptionProviderConfig `json:"providers"`
+}
+
+// EncryptionProviderConfig is a single encryption provider; the first one encrypts, all of them decrypt
+type EncryptionProviderConfig struct {
+	KMS       *KMSProviderConfig  `json:"kms,omitempty"`
+	AESCBC    *KeysProviderConfig `json:"aescbc,omitempty"`
+	AESGCM    *KeysProviderConfig `json:"aesgcm,omitempty"`
+	Secretbox *KeysProviderConfig `json:"secretbox,omitempty"`
+	Identity  *struct{}           `json:"identity,omitempty"`
+}
+
+// KMSProviderConfig configures a KMS plugin reached through a unix socket
+type KMSProviderConfig struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Name       string `json:"name"`
+	Endpoint   string `json:"endpoint"`
+	CacheSize  int32  `json:"cachesize,omitempty"`
+	Timeout    string `json:"timeout,omitempty"`
+}
+
+// KeysProviderConfig configures a provider that encrypts with the first of its keys
+type KeysProviderConfig struct {
+	Keys []Key `json:"keys"`
+}
+
+// Key is a named key of a KeysProviderConfig
+type Key struct {
+	Name   string `json:"name"`
+	Secret string `json:"secret"`
+}
+
+// encryptionProviderName returns the name of the KMS provider for a key.
+// The name is recorded in every value the provider encrypts, so it only depends on the key.
+func encryptionProviderName(kmsKey string) string {
+	hash := sha256.Sum256([]byte(kmsKey))
+	return "aws-kms-" + hex.EncodeToString(hash[:])[:10]
+}
+
+// encryptionProviderKeys returns the KMS keys to run plugins for, the one used for encryption first
+func encryptionProviderKeys(spec *kops.EncryptionAtRestSpec) []string {
+	return append([]string{spec.KMSKey}, spec.PreviousKMSKeys...)
+}
+
+// buildEncryptionAtRestConfig builds the API server encryption configuration that uses the KMS plugins.
+//
+// existing is the encryption configuration from the encryptionconfig secret, if encryptionConfig is also enabled.
+// The resources encrypted with KMS use the KMS plugins first, so that they are written with the KMS key,
+// followed by the providers the existing configuration has for them, so that resources written with those
+// are still readable until they are re-encrypted. Resources without existing providers fall back to the identity
+// provider, which reads the resources written before encryption was enabled.
+// The existing configuration of the other resources is kept.
+func buildEncryptionAtRestConfig(spec *kops.EncryptionAtRestSpec, existing []byte) ([]byte, error) {
+	var kmsProviders []EncryptionProviderConfig
+	for _, kmsKey := range encryptionProviderKeys(spec) {
+		name := encryptionProviderName(kmsKey)
+		kmsProviders = append(kmsProviders, EncryptionProviderConfig{
+			KMS: &KMSProviderConfig{
+				Name:      name,
+				Endpoint:  "unix://" + filepath.Join(encryptionProviderSocketDir, name+".sock"),
+				CacheSize: 1000,
+				Timeout:   "3s",
+			},
+		})
+	}
+
+	var existingConfig EncryptionConfiguration
+	if existing != nil {
+		// Unknown providers must not be silently dropped, as the resources they encrypted would become unreadable
+		if err := yaml.UnmarshalStrict(existing, &existingConfig); err != nil {
+			return nil, fmt.Errorf("error parsing encryptionconfig secret: %v", err)
+		}
+	}
+
+	// Group the resources encrypted with KMS by the existing configuration they fall back to, keeping their order
+	fallbacks := make(map[int]*EncryptionResourceConfig)
+	var resourceConfigs []EncryptionResourceConfig
+	var order []int
+	kmsResources := sets.NewString(spec.Resources...)
+	for _, resource := range spec.Resources {
+		index := -1
+		for i, rc := range existingConfig.Resources {
+			if sets.NewString(rc.Resources...).Has(resource) {
+				index = i
+				break
+			}
+		}
+		rc := fallbacks[index]
+		if rc == nil {
+			rc = &EncryptionResourceConfig{}
+			rc.Providers = append(rc.Providers, kmsProviders...)
+			if index == -1 {
+				rc.Providers = append(rc.Providers, EncryptionProviderConfig{Identity: &struct{}{}})
+			} else {
+				rc.Providers = append(rc.Providers, existingConfig.Resources[index].Providers...)
+			}
+			fallbacks[index] = rc
+			order = append(order, index)
+		}
+		rc.Resources = append(rc.Resources, resource)
+	}
+	for _, index := range order {
+		resourceConfigs = append(resourceConfigs, *fallbacks[index])
+	}
+
+	for _, rc := range existingConfig.Resources {
+		var resources []string
+		for _, resource := range rc.Resources {
+			if !kmsResources.Has(resource) {
+				resources = append(resources, resource)
+			}
+		}
+		if len(resources) != 0 {
+			resourceConfigs = append(resourceConfigs, EncryptionResourceConfig{
+				Resources: resources,
+				Providers: rc.Providers,
+			})
+		}
+	}
+
+	config := &EncryptionConfiguration{
+		APIVersion: "apiserver.config.k8s.io/v1",
+		Kind:       "EncryptionConfiguration",
+		Resources:  resourceConfigs,
+	}
+	return kops.ToRawYaml(config)
+}
+
+// EncryptionProviderBuilder runs the aws-encryption-provider KMS plugins for the API server
+type EncryptionProviderBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &EncryptionProviderBuilder{}
+
+// Build is responsible for building the manifest for the KMS plugins
+func (b *EncryptionProviderBuilder) Build(c *fi.ModelBuilderContext) error {
+	if !b.HasAPIServer || b.Cluster.Spec.EncryptionAtRest == nil {
+		return nil
+	}
+
+	pod, err := b.buildPod()
+	if err != nil {
+		return fmt.Errorf("error building aws-encryption-provider pod: %v", err)
+	}
+
+	manifest, err := k8scodecs.ToVersionedYaml(pod)
+	if err != nil {
+		return fmt.Errorf("error marshaling pod to yaml: %v", err)
+	}
+
+	c.AddTask(&nodetasks.File{
+		Path:     "/etc/kubernetes/manifests/aws-encryption-provider.manifest",
+		Contents: fi.NewBytesResource(manifest),
+		Type:     nodetasks.FileType_File,
+	})
+
+	return nil
+}
+
+// buildPod runs one aws-encryption-provider container per KMS key
+func (b *EncryptionProviderBuilder) buildPod() (*v1.Pod, error) {
+	spec := b.Cluster.Spec.EncryptionAtRest
+
+	pod := &v1.Pod{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Pod",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "aws-encryption-provider",
+			Namespace: "kube-system",
+			Labels: map[string]string{
+				"k8s-app": "aws-encryption-provider",
+			},
+		},
+		Spec: v1.PodSpec{
+			HostNetwork: true,
+		},
+	}
+
+	// The containers share the socket directory with the API server
+	volumeType := v1.HostPathDirectoryOrCreate
+	pod.Spec.Volumes = append(pod.Spec.Volumes, v1.Volume{
+		Name: "kmsplugin",
+		VolumeSource: v1.VolumeSource{
+			HostPath: &v1.HostPathVolumeSource{
+				Path: encryptionProviderSocketDir,
+				Type: &volumeType,
+			},
+		},
+	})
+
+	for i, kmsKey := range encryptionProviderKeys(spec) {
+		keyARN, err := arn.Parse(kmsKey)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing KMS key ARN %q: %v", kmsKey, err)
+		}
+
+		name := encryptionProviderName(kmsKey)
+		healthPort := wellknownports.AWSEncryptionProviderHealthCheck + i
+		container := v1.Container{
+			Name:  name,
+			Image: spec.Image,
+			Args: []string{
+				"--key=" + kmsKey,
+				"--region=" + keyARN.Region,
+				"--listen=" + filepath.Join(encryptionProviderSocketDir, name+".sock"),
+				fmt.Sprintf("--health-port=:%d", healthPort),
+			},
+			LivenessProbe: &v1.Probe{
+				Handler: v1.Handler{
+					HTTPGet: &v1.HTTPGetAction{
+						Host: "127.0.0.1",
+						Path: "/healthz",
+						Port: intstr.FromInt(healthPort),
+					},
+				},
+				InitialDelaySeconds: 15,
+				TimeoutSeconds:      15,
+			},
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse("10m"),
+					v1.ResourceMemory: resource.MustParse("32Mi"),
+				},
+			},
+		}
+		container.VolumeMounts = append(container.VolumeMounts, v1.VolumeMount{
+			Name:      "kmsplugin",
+			MountPath: encryptionProviderSocketDir,
+		})
+		pod.Spec.Containers = append(pod.Spec.Containers, container)
+	}
+
+	kubemanifest.MarkPodAsCritical(pod)
+	kubemanifest.MarkPodAsClusterCritical(pod)
+
+	return pod, nil
+}
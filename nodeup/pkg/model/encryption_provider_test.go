@@ -0,0 +1,137 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+func TestBuildEncryptionAtRestConfig(t *testing.T) {
+	key := "arn:aws:kms:us-east-1:123456789012:key/new"
+	previousKey := "arn:aws:kms:us-east-1:123456789012:key/old"
+
+	spec := &kops.EncryptionAtRestSpec{
+		KMSKey:          key,
+		PreviousKMSKeys: []string{previousKey},
+		Resources:       []string{"secrets"},
+	}
+	config, err := buildEncryptionAtRestConfig(spec, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s := string(config)
+	current := strings.Index(s, "name: "+encryptionProviderName(key))
+	previous := strings.Index(s, "name: "+encryptionProviderName(previousKey))
+	identity := strings.Index(s, "identity: {}")
+	if current == -1 || previous == -1 || identity == -1 {
+		t.Fatalf("missing providers in config:\n%s", s)
+	}
+	if !(current < previous && previous < identity) {
+		t.Errorf("expected providers in order current, previous, identity:\n%s", s)
+	}
+
+	if encryptionProviderName(key) == encryptionProviderName(previousKey) {
+		t.Errorf("expected distinct provider names for distinct keys")
+	}
+
+	// The provider name of a key must not change when the key moves to previousKMSKeys
+	spec.KMSKey = "arn:aws:kms:us-east-1:123456789012:key/newer"
+	spec.PreviousKMSKeys = []string{key, previousKey}
+	rotated, err := buildEncryptionAtRestConfig(spec, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(rotated), "name: "+encryptionProviderName(key)+"\n") {
+		t.Errorf("expected provider for rotated key in config:\n%s", rotated)
+	}
+}
+
+func TestBuildEncryptionAtRestConfigWithEncryptionConfig(t *testing.T) {
+	key := "arn:aws:kms:us-east-1:123456789012:key/new"
+	name := encryptionProviderName(key)
+
+	existing := `apiVersion: apiserver.config.k8s.io/v1
+kind: EncryptionConfiguration
+resources:
+- resources:
+  - secrets
+  - configmaps
+  providers:
+  - aescbc:
+      keys:
+      - name: key1
+        secret: c2VjcmV0IGlzIHNlY3VyZQ==
+  - identity: {}
+`
+
+	spec := &kops.EncryptionAtRestSpec{
+		KMSKey:    key,
+		Resources: []string{"secrets", "leases.coordination.k8s.io"},
+	}
+	config, err := buildEncryptionAtRestConfig(spec, []byte(existing))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `apiVersion: apiserver.config.k8s.io/v1
+kind: EncryptionConfiguration
+resources:
+- providers:
+  - kms:
+      cachesize: 1000
+      endpoint: unix:///var/run/kmsplugin/` + name + `.sock
+      name: ` + name + `
+      timeout: 3s
+  - aescbc:
+      keys:
+      - name: key1
+        secret: c2VjcmV0IGlzIHNlY3VyZQ==
+  - identity: {}
+  resources:
+  - secrets
+- providers:
+  - kms:
+      cachesize: 1000
+      endpoint: unix:///var/run/kmsplugin/` + name + `.sock
+      name: ` + name + `
+      timeout: 3s
+  - identity: {}
+  resources:
+  - leases.coordination.k8s.io
+- providers:
+  - aescbc:
+      keys:
+      - name: key1
+        secret: c2VjcmV0IGlzIHNlY3VyZQ==
+  - identity: {}
+  resources:
+  - configmaps
+`
+	if string(config) != expected {
+		t.Errorf("unexpected config, expected:\n%s\ngot:\n%s", expected, config)
+	}
+
+	// Providers that we don't know must not be dropped
+	unknown := strings.Replace(existing, "aescbc:", "aesccm:", 1)
+	if _, err := buildEncryptionAtRestConfig(spec, []byte(unknown)); err == nil {
+		t.Errorf("expected an error for an unknown provider")
+	}
+}
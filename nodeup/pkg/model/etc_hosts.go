@@ -0,0 +1,46 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+// EtcHostsBuilder pins the internal API server and kops-controller names to the API server endpoint
+// of the instance group, for instances that can't reach the internal endpoint of the cluster
+type EtcHostsBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &EtcHostsBuilder{}
+
+// Build is responsible for adding the /etc/hosts entries of the API server endpoint
+func (b *EtcHostsBuilder) Build(c *fi.ModelBuilderContext) error {
+	endpoint := b.NodeupConfig.APIServerEndpoint
+	if endpoint == nil {
+		return nil
+	}
+
+	c.AddTask(&nodetasks.UpdateEtcHostsTask{
+		Name:      "api-server-endpoint",
+		Hostnames: endpoint.Hostnames,
+		Resolve:   endpoint.Resolve,
+		IPs:       endpoint.IPs,
+	})
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+func TestEtcHostsBuilder(t *testing.T) {
+	grid := []struct {
+		Endpoint *kops.APIServerEndpointSpec
+		Expected *nodetasks.UpdateEtcHostsTask
+	}{
+		{
+			Endpoint: nil,
+		},
+		{
+			Endpoint: &kops.APIServerEndpointSpec{Type: kops.APIServerEndpointTypeInternal},
+		},
+		{
+			Endpoint: &kops.APIServerEndpointSpec{Type: kops.APIServerEndpointTypeExternal},
+			Expected: &nodetasks.UpdateEtcHostsTask{
+				Name:      "api-server-endpoint",
+				Hostnames: []string{"api.internal.minimal.example.com", "kops-controller.internal.minimal.example.com"},
+				Resolve:   "api.minimal.example.com",
+			},
+		},
+		{
+			Endpoint: &kops.APIServerEndpointSpec{Type: kops.APIServerEndpointTypeIPs, IPs: []string{"203.0.113.10", "203.0.113.11"}},
+			Expected: &nodetasks.UpdateEtcHostsTask{
+				Name:      "api-server-endpoint",
+				Hostnames: []string{"api.internal.minimal.example.com", "kops-controller.internal.minimal.example.com"},
+				IPs:       []string{"203.0.113.10", "203.0.113.11"},
+			},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{}
+		cluster.ObjectMeta.Name = "minimal.example.com"
+		cluster.Spec.CloudProvider = string(kops.CloudProviderAWS)
+		cluster.Spec.MasterInternalName = "api.internal.minimal.example.com"
+		cluster.Spec.MasterPublicName = "api.minimal.example.com"
+
+		instanceGroup := &kops.InstanceGroup{}
+		instanceGroup.Spec.Role = kops.InstanceGroupRoleNode
+		instanceGroup.Spec.APIServerEndpoint = g.Endpoint
+
+		b := &EtcHostsBuilder{
+			NodeupModelContext: &NodeupModelContext{
+				Cluster:      cluster,
+				NodeupConfig: nodeup.NewConfig(cluster, instanceGroup),
+			},
+		}
+		c := &fi.ModelBuilderContext{Tasks: make(map[string]fi.Task)}
+		if err := b.Build(c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		task, found := c.Tasks["UpdateEtcHostsTask/api-server-endpoint"].(*nodetasks.UpdateEtcHostsTask)
+		if found != (g.Expected != nil) {
+			t.Fatalf("expected task %v for %+v, got %v", g.Expected != nil, g.Endpoint, found)
+		}
+		if found && !reflect.DeepEqual(task, g.Expected) {
+			t.Errorf("expected task %+v, got %+v", g.Expected, task)
+		}
+	}
+}
@@ -34,7 +34,7 @@ var _ fi.ModelBuilder = &EtcdBuilder{}
 
 // Build is responsible for creating the etcd user
 func (b *EtcdBuilder) Build(c *fi.ModelBuilderContext) error {
-	if !b.IsMaster || b.UseEtcdManager() {
+	if !b.IsMaster || b.UseEtcdManager() || b.UseExternalEtcd() {
 		return nil
 	}
 
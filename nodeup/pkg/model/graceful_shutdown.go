@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/systemd"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+	"k8s.io/kops/util/pkg/distributions"
+)
+
+// cloudEventsService is the unit shutting the instance down when the cloud provider announces its interruption
+const cloudEventsService = "kops-cloud-events.service"
+
+// GracefulShutdownBuilder runs nodeup to watch the interruption notices of the cloud provider.
+// The kubelet settings of the graceful shutdown are set with the rest of the kubelet configuration.
+type GracefulShutdownBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &GracefulShutdownBuilder{}
+
+// Build is responsible for creating the unit watching the interruption notices
+func (b *GracefulShutdownBuilder) Build(c *fi.ModelBuilderContext) error {
+	gracefulShutdown := b.NodeupConfig.GracefulShutdown
+	if gracefulShutdown == nil || (gracefulShutdown.CloudEvents != nil && !*gracefulShutdown.CloudEvents) {
+		return nil
+	}
+
+	c.AddTask(b.buildCloudEventsService())
+	return nil
+}
+
+// nodeupPath returns the path where the bootstrap script installs nodeup
+func (b *GracefulShutdownBuilder) nodeupPath() string {
+	if b.Distribution == distributions.DistributionContainerOS {
+		return "/var/lib/toolbox/kops/bin/nodeup"
+	}
+	return "/opt/kops/bin/nodeup"
+}
+
+func (b *GracefulShutdownBuilder) buildCloudEventsService() *nodetasks.Service {
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Shut the instance down when the cloud provider announces its interruption")
+	manifest.Set("Unit", "Documentation", "https://kops.sigs.k8s.io")
+	manifest.Set("Unit", "Wants", "network-online.target")
+	manifest.Set("Unit", "After", "network-online.target")
+
+	manifest.Set("Service", "ExecStart", b.nodeupPath()+" --watch-cloud-events="+b.Cluster.Spec.CloudProvider)
+	manifest.Set("Service", "Restart", "on-failure")
+	manifest.Set("Service", "RestartSec", "10s")
+
+	manifest.Set("Install", "WantedBy", "multi-user.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", cloudEventsService, manifestString)
+
+	service := &nodetasks.Service{
+		Name:       cloudEventsService,
+		Definition: s(manifestString),
+	}
+	service.InitDefaults()
+
+	return service
+}
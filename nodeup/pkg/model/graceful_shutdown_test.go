@@ -0,0 +1,120 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+func TestGracefulShutdownBuilder(t *testing.T) {
+	grid := []struct {
+		CloudProvider           kops.CloudProviderID
+		GracefulShutdown        kops.GracefulShutdownSpec
+		Service                 bool
+		GracePeriod             time.Duration
+		CriticalPodsGracePeriod time.Duration
+	}{
+		{
+			CloudProvider:           kops.CloudProviderAWS,
+			Service:                 true,
+			GracePeriod:             90 * time.Second,
+			CriticalPodsGracePeriod: 30 * time.Second,
+		},
+		{
+			CloudProvider:           kops.CloudProviderGCE,
+			GracefulShutdown:        kops.GracefulShutdownSpec{CloudEvents: fi.Bool(true)},
+			Service:                 true,
+			GracePeriod:             25 * time.Second,
+			CriticalPodsGracePeriod: 10 * time.Second,
+		},
+		{
+			CloudProvider:           kops.CloudProviderAWS,
+			GracefulShutdown:        kops.GracefulShutdownSpec{CloudEvents: fi.Bool(false)},
+			GracePeriod:             90 * time.Second,
+			CriticalPodsGracePeriod: 30 * time.Second,
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{}
+		cluster.Spec.CloudProvider = string(g.CloudProvider)
+		cluster.Spec.KubernetesVersion = "1.21.0"
+
+		instanceGroup := &kops.InstanceGroup{}
+		instanceGroup.Spec.Role = kops.InstanceGroupRoleNode
+		instanceGroup.Spec.GracefulShutdown = &g.GracefulShutdown
+
+		config := nodeup.NewConfig(cluster, instanceGroup)
+		if config.KubeletConfig.ShutdownGracePeriod == nil || config.KubeletConfig.ShutdownGracePeriod.Duration != g.GracePeriod {
+			t.Errorf("expected shutdownGracePeriod %v, got %v", g.GracePeriod, config.KubeletConfig.ShutdownGracePeriod)
+		}
+		if config.KubeletConfig.ShutdownGracePeriodCriticalPods == nil || config.KubeletConfig.ShutdownGracePeriodCriticalPods.Duration != g.CriticalPodsGracePeriod {
+			t.Errorf("expected shutdownGracePeriodCriticalPods %v, got %v", g.CriticalPodsGracePeriod, config.KubeletConfig.ShutdownGracePeriodCriticalPods)
+		}
+
+		b := &GracefulShutdownBuilder{
+			NodeupModelContext: &NodeupModelContext{
+				Cluster:      cluster,
+				NodeupConfig: config,
+			},
+		}
+		c := &fi.ModelBuilderContext{Tasks: make(map[string]fi.Task)}
+		if err := b.Build(c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		task, found := c.Tasks["Service/"+cloudEventsService].(*nodetasks.Service)
+		if found != g.Service {
+			t.Fatalf("expected service %v for %+v, got %v", g.Service, g.GracefulShutdown, found)
+		}
+		if !found {
+			continue
+		}
+		expected := "ExecStart=/opt/kops/bin/nodeup --watch-cloud-events=" + string(g.CloudProvider)
+		if !strings.Contains(fi.StringValue(task.Definition), expected) {
+			t.Errorf("expected %q in unit:\n%s", expected, fi.StringValue(task.Definition))
+		}
+	}
+}
+
+func TestGracefulShutdownKubeletSettings(t *testing.T) {
+	cluster := &kops.Cluster{}
+	cluster.Spec.CloudProvider = string(kops.CloudProviderAWS)
+	cluster.Spec.KubernetesVersion = "1.23.0"
+	cluster.Spec.Kubelet = &kops.KubeletConfigSpec{
+		ShutdownGracePeriodByPodPriority: []kops.KubeletShutdownGracePeriodByPodPriority{
+			{Priority: 0, ShutdownGracePeriodSeconds: 60},
+		},
+	}
+
+	instanceGroup := &kops.InstanceGroup{}
+	instanceGroup.Spec.Role = kops.InstanceGroupRoleNode
+	instanceGroup.Spec.GracefulShutdown = &kops.GracefulShutdownSpec{}
+
+	// The grace periods by pod priority replace the default grace periods
+	config := nodeup.NewConfig(cluster, instanceGroup)
+	if config.KubeletConfig.ShutdownGracePeriod != nil || config.KubeletConfig.ShutdownGracePeriodCriticalPods != nil {
+		t.Errorf("expected no default grace periods, got %v and %v", config.KubeletConfig.ShutdownGracePeriod, config.KubeletConfig.ShutdownGracePeriodCriticalPods)
+	}
+}
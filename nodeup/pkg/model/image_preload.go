@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"strings"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+// ImagePreloadBuilder pulls the images of the instance group before the kubelet starts
+type ImagePreloadBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &ImagePreloadBuilder{}
+
+// Build is responsible for pulling the images to preload
+func (b *ImagePreloadBuilder) Build(c *fi.ModelBuilderContext) error {
+	preload := b.NodeupConfig.ImagePreload
+	if preload == nil {
+		return nil
+	}
+
+	runtime := b.Cluster.Spec.ContainerRuntime
+
+	// ctr only uses the containerd registry settings when pointed at them
+	hostsDir := ""
+	if runtime == "containerd" && b.NodeupConfig.Containerd != nil && len(b.NodeupConfig.Containerd.Registries) > 0 {
+		hostsDir = containerdRegistryConfigPath
+	}
+
+	pulled := make(map[string]bool)
+	for _, image := range preload.Images {
+		name := normalizeImageName(image)
+		if pulled[name] {
+			continue
+		}
+		pulled[name] = true
+
+		task := &nodetasks.PullImageTask{
+			Name:           name,
+			Runtime:        runtime,
+			HostsDir:       hostsDir,
+			BeforeServices: []string{kubeletService},
+			// A missing image must not keep the instance from joining the cluster
+			BestEffort: true,
+		}
+		if preload.PullThroughCache != nil {
+			task.Source = strings.TrimSuffix(*preload.PullThroughCache, "/") + "/" + name
+		}
+		c.AddTask(task)
+	}
+
+	return nil
+}
+
+// normalizeImageName returns the full name of the image, the way the kubelet asks the container runtime for it,
+// e.g. docker.io/library/nginx:latest for nginx
+func normalizeImageName(image string) string {
+	name := image
+	if i := strings.Index(name, "/"); i == -1 {
+		name = "docker.io/library/" + name
+	} else if domain := name[:i]; !strings.ContainsAny(domain, ".:") && domain != "localhost" {
+		name = "docker.io/" + name
+	}
+	if strings.HasPrefix(name, "docker.io/") && strings.Count(name, "/") == 1 {
+		name = "docker.io/library/" + strings.TrimPrefix(name, "docker.io/")
+	}
+
+	// Images without a tag or digest are pulled with the latest tag
+	if !strings.Contains(name, "@") && !strings.Contains(name[strings.LastIndex(name, "/"):], ":") {
+		name += ":latest"
+	}
+	return name
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+func TestNormalizeImageName(t *testing.T) {
+	grid := map[string]string{
+		"nginx":                                 "docker.io/library/nginx:latest",
+		"nginx:1.21":                            "docker.io/library/nginx:1.21",
+		"docker.io/nginx:1.21":                  "docker.io/library/nginx:1.21",
+		"weaveworks/weave-kube:2.8.1":           "docker.io/weaveworks/weave-kube:2.8.1",
+		"k8s.gcr.io/pause:3.5":                  "k8s.gcr.io/pause:3.5",
+		"localhost/app":                         "localhost/app:latest",
+		"registry.example.com:5000/team/app":    "registry.example.com:5000/team/app:latest",
+		"quay.io/app@sha256:0123456789abcdef":   "quay.io/app@sha256:0123456789abcdef",
+		"registry.example.com:5000/team/app:v1": "registry.example.com:5000/team/app:v1",
+	}
+	for image, expected := range grid {
+		if actual := normalizeImageName(image); actual != expected {
+			t.Errorf("expected %q for %q, got %q", expected, image, actual)
+		}
+	}
+}
+
+func TestImagePreloadBuilder(t *testing.T) {
+	cluster := &kops.Cluster{}
+	cluster.Spec.ContainerRuntime = "containerd"
+
+	b := &ImagePreloadBuilder{
+		NodeupModelContext: &NodeupModelContext{
+			Cluster: cluster,
+			NodeupConfig: &nodeup.Config{
+				Containerd: &kops.ContainerdConfig{
+					Registries: map[string]kops.ContainerdRegistryConfig{
+						"cache.example.com": {CA: fi.String("ca")},
+					},
+				},
+				ImagePreload: &kops.ImagePreloadSpec{
+					Images:           []string{"nginx:1.21", "docker.io/library/nginx:1.21", "k8s.gcr.io/pause:3.5"},
+					PullThroughCache: fi.String("cache.example.com/"),
+				},
+			},
+		},
+	}
+	c := &fi.ModelBuilderContext{Tasks: make(map[string]fi.Task)}
+	if err := b.Build(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(c.Tasks) != 2 {
+		t.Fatalf("expected 2 tasks, got %v", c.Tasks)
+	}
+	task, found := c.Tasks["PullImageTask/docker.io/library/nginx:1.21"].(*nodetasks.PullImageTask)
+	if !found {
+		t.Fatalf("expected a task pulling nginx, got %v", c.Tasks)
+	}
+	if task.Source != "cache.example.com/docker.io/library/nginx:1.21" {
+		t.Errorf("unexpected source %q", task.Source)
+	}
+	if task.HostsDir != containerdRegistryConfigPath {
+		t.Errorf("unexpected hosts dir %q", task.HostsDir)
+	}
+	if len(task.BeforeServices) != 1 || task.BeforeServices[0] != kubeletService {
+		t.Errorf("expected the image to be pulled before the kubelet starts, got %v", task.BeforeServices)
+	}
+
+	kubelet := &nodetasks.Service{Name: kubeletService}
+	if deps := kubelet.GetDependencies(c.Tasks); len(deps) != 2 {
+		t.Errorf("expected the kubelet to depend on the 2 pulls, got %v", deps)
+	}
+}
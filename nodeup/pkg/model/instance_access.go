@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+	"k8s.io/kops/util/pkg/distributions"
+)
+
+// InstanceAccessBuilder makes sure the agents used for SSM Session Manager or
+// EC2 Instance Connect access are present and running
+type InstanceAccessBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &InstanceAccessBuilder{}
+
+// Build is responsible for enabling the instance access agents
+func (b *InstanceAccessBuilder) Build(c *fi.ModelBuilderContext) error {
+	if kops.CloudProviderID(b.Cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
+		return nil
+	}
+
+	switch b.Cluster.Spec.Topology.GetInstanceAccessType() {
+	case kops.InstanceAccessTypeSSM:
+		// The agent is preinstalled on the Ubuntu and Amazon Linux images, but not always started
+		var serviceName string
+		switch {
+		case b.Distribution.IsUbuntu():
+			serviceName = "snap.amazon-ssm-agent.amazon-ssm-agent.service"
+		case b.Distribution == distributions.DistributionAmazonLinux2:
+			serviceName = "amazon-ssm-agent.service"
+		default:
+			klog.Warningf("SSM agent is not preinstalled on %v; the image must provide it", b.Distribution)
+			return nil
+		}
+		c.AddTask((&nodetasks.Service{
+			Name:         serviceName,
+			SmartRestart: fi.Bool(false),
+		}).InitDefaults())
+
+	case kops.InstanceAccessTypeEC2InstanceConnect:
+		if b.Distribution.IsUbuntu() || b.Distribution == distributions.DistributionAmazonLinux2 {
+			c.AddTask(&nodetasks.Package{Name: "ec2-instance-connect"})
+		} else {
+			klog.Warningf("ec2-instance-connect is not packaged for %v; the image must provide it", b.Distribution)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+	"k8s.io/kops/util/pkg/distributions"
+)
+
+func TestInstanceAccessBuilder(t *testing.T) {
+	grid := []struct {
+		Access       kops.InstanceAccessType
+		Distribution distributions.Distribution
+		Expected     string
+	}{
+		{
+			Access:       kops.InstanceAccessTypeSSH,
+			Distribution: distributions.DistributionUbuntu2004,
+		},
+		{
+			Access:       kops.InstanceAccessTypeSSM,
+			Distribution: distributions.DistributionUbuntu2004,
+			Expected:     "Service/snap.amazon-ssm-agent.amazon-ssm-agent.service",
+		},
+		{
+			Access:       kops.InstanceAccessTypeSSM,
+			Distribution: distributions.DistributionAmazonLinux2,
+			Expected:     "Service/amazon-ssm-agent.service",
+		},
+		{
+			Access:       kops.InstanceAccessTypeSSM,
+			Distribution: distributions.DistributionFlatcar,
+		},
+		{
+			Access:       kops.InstanceAccessTypeEC2InstanceConnect,
+			Distribution: distributions.DistributionUbuntu2004,
+			Expected:     "Package/ec2-instance-connect",
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{}
+		cluster.Spec.CloudProvider = string(kops.CloudProviderAWS)
+		cluster.Spec.Topology = &kops.TopologySpec{
+			InstanceAccess: &kops.InstanceAccessSpec{Type: g.Access},
+		}
+
+		b := &InstanceAccessBuilder{
+			NodeupModelContext: &NodeupModelContext{
+				Cluster:      cluster,
+				Distribution: g.Distribution,
+			},
+		}
+		c := &fi.ModelBuilderContext{Tasks: make(map[string]fi.Task)}
+		if err := b.Build(c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if g.Expected == "" {
+			if len(c.Tasks) != 0 {
+				t.Errorf("%s on %v: expected no tasks, got %v", g.Access, g.Distribution, c.Tasks)
+			}
+			continue
+		}
+		if len(c.Tasks) != 1 || c.Tasks[g.Expected] == nil {
+			t.Errorf("%s on %v: expected task %q, got %v", g.Access, g.Distribution, g.Expected, c.Tasks)
+		}
+		if service, ok := c.Tasks[g.Expected].(*nodetasks.Service); ok && !fi.BoolValue(service.Enabled) {
+			t.Errorf("%s on %v: expected service to be enabled", g.Access, g.Distribution)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/systemd"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+	"k8s.io/mount-utils"
+	utilexec "k8s.io/utils/exec"
+)
+
+const (
+	// instanceStoreModel is the model of the NVMe instance store devices of EC2 instances
+	instanceStoreModel = "Amazon EC2 NVMe Instance Storage"
+	// instanceStoreName is the name of the RAID array or LVM volume group assembled from the instance store devices
+	instanceStoreName = "kops-instance-store"
+	// instanceStoreMountPath is where the RAID0 volume is mounted, before its subdirectories are bind mounted at the paths
+	instanceStoreMountPath = "/mnt/kops-instance-store"
+)
+
+// InstanceStorageBuilder assembles, formats and mounts the local instance store devices
+type InstanceStorageBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &InstanceStorageBuilder{}
+
+// Build is responsible for mounting the instance store devices at the paths of the instance group spec
+func (b *InstanceStorageBuilder) Build(c *fi.ModelBuilderContext) error {
+	storage := b.NodeupConfig.InstanceStorage
+	if storage == nil {
+		return nil
+	}
+
+	m := &mount.SafeFormatAndMount{
+		Exec:      utilexec.New(),
+		Interface: mount.New(""),
+	}
+
+	// @check if the paths have already been mounted, e.g. by the mount units at boot
+	mounted := true
+	for _, p := range storage.Paths {
+		if notMnt, err := m.IsLikelyNotMountPoint(p); err != nil || notMnt {
+			mounted = false
+		}
+	}
+	if mounted {
+		klog.V(3).Infof("Skipping instance storage, paths %v are already mounted", storage.Paths)
+		return nil
+	}
+
+	devices, err := findInstanceStoreDevices("/sys/block")
+	if err != nil {
+		return err
+	}
+	if len(devices) == 0 {
+		klog.Warningf("No instance store devices found; paths %v will remain on the root volume", storage.Paths)
+		return nil
+	}
+
+	filesystem := storage.Filesystem
+	if filesystem == "" {
+		filesystem = kops.Ext4Filesystem
+	}
+
+	switch storage.Mode {
+	case "", kops.InstanceStorageModeRAID0:
+		device, err := assembleRAID0(m.Exec, devices)
+		if err != nil {
+			return err
+		}
+		uuid, err := b.formatAndMount(m, device, instanceStoreMountPath, filesystem)
+		if err != nil {
+			return err
+		}
+		c.AddTask(buildMountUnit("/dev/disk/by-uuid/"+uuid, instanceStoreMountPath, filesystem, "defaults,nofail"))
+
+		for _, p := range storage.Paths {
+			source := path.Join(instanceStoreMountPath, strings.ReplaceAll(strings.TrimPrefix(p, "/"), "/", "-"))
+			if err := b.EnsureDirectory(source); err != nil {
+				return fmt.Errorf("failed to ensure the directory: %s, error: %s", source, err)
+			}
+			if err := b.EnsureDirectory(p); err != nil {
+				return fmt.Errorf("failed to ensure the directory: %s, error: %s", p, err)
+			}
+			if notMnt, err := m.IsLikelyNotMountPoint(p); err != nil {
+				return err
+			} else if notMnt {
+				klog.Infof("Bind mounting instance storage %s at %s", source, p)
+				if err := m.Mount(source, p, "", []string{"bind"}); err != nil {
+					return fmt.Errorf("failed to bind mount %s at %s: %v", source, p, err)
+				}
+			}
+			c.AddTask(buildMountUnit(source, p, "none", "bind,nofail"))
+		}
+
+	case kops.InstanceStorageModeLVM:
+		volumes, err := assembleLVM(m.Exec, devices, storage.Paths)
+		if err != nil {
+			return err
+		}
+		for _, p := range storage.Paths {
+			uuid, err := b.formatAndMount(m, volumes[p], p, filesystem)
+			if err != nil {
+				return err
+			}
+			c.AddTask(buildMountUnit("/dev/disk/by-uuid/"+uuid, p, filesystem, "defaults,nofail"))
+		}
+
+	default:
+		return fmt.Errorf("unsupported instance storage mode %q", storage.Mode)
+	}
+
+	return nil
+}
+
+// formatAndMount formats the device if needed, mounts it and returns the UUID of its filesystem
+func (b *InstanceStorageBuilder) formatAndMount(m *mount.SafeFormatAndMount, device, target, filesystem string) (string, error) {
+	if err := b.EnsureDirectory(target); err != nil {
+		return "", fmt.Errorf("failed to ensure the directory: %s, error: %s", target, err)
+	}
+
+	if notMnt, err := m.IsLikelyNotMountPoint(target); err != nil {
+		return "", err
+	} else if notMnt {
+		klog.Infof("Attempting to format and mount device: %s, path: %s", device, target)
+		if err := m.FormatAndMount(device, target, filesystem, nil); err != nil {
+			return "", fmt.Errorf("failed to mount the device: %s on: %s, error: %v", device, target, err)
+		}
+	}
+
+	out, err := m.Exec.Command("blkid", "-s", "UUID", "-o", "value", device).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("error reading the UUID of %s: %v: %s", device, err, out)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// findInstanceStoreDevices returns the instance store NVMe devices listed in the sysfs block directory
+func findInstanceStoreDevices(sysBlockPath string) ([]string, error) {
+	entries, err := ioutil.ReadDir(sysBlockPath)
+	if err != nil {
+		return nil, fmt.Errorf("error listing block devices in %s: %v", sysBlockPath, err)
+	}
+
+	var devices []string
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), "nvme") {
+			continue
+		}
+		model, err := ioutil.ReadFile(filepath.Join(sysBlockPath, entry.Name(), "device", "model"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("error reading the model of %s: %v", entry.Name(), err)
+		}
+		if strings.TrimSpace(string(model)) == instanceStoreModel {
+			devices = append(devices, "/dev/"+entry.Name())
+		}
+	}
+	sort.Strings(devices)
+
+	return devices, nil
+}
+
+// assembleRAID0 stripes the devices into a RAID0 array, reassembling it if it already exists, and returns its device
+func assembleRAID0(exec utilexec.Interface, devices []string) (string, error) {
+	if len(devices) == 1 {
+		return devices[0], nil
+	}
+
+	device := "/dev/md/" + instanceStoreName
+	if _, err := os.Stat(device); err == nil {
+		return device, nil
+	}
+
+	args := append([]string{"--assemble", device}, devices...)
+	out, err := exec.Command("mdadm", args...).CombinedOutput()
+	if err == nil {
+		return device, nil
+	}
+	klog.V(2).Infof("Could not assemble an existing array, will create it: %v: %s", err, out)
+
+	klog.Infof("Creating RAID0 array %s from %v", device, devices)
+	args = append([]string{"--create", device, "--level=0", "--raid-devices=" + strconv.Itoa(len(devices)), "--name=" + instanceStoreName, "--run"}, devices...)
+	if out, err := exec.Command("mdadm", args...).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("error creating RAID0 array %s: %v: %s", device, err, out)
+	}
+
+	return device, nil
+}
+
+// assembleLVM creates a volume group from the devices and a logical volume of an equal share of it for each path,
+// unless they already exist, and returns the device of the logical volume of each path
+func assembleLVM(exec utilexec.Interface, devices []string, paths []string) (map[string]string, error) {
+	if err := exec.Command("vgs", instanceStoreName).Run(); err != nil {
+		klog.Infof("Creating volume group %s from %v", instanceStoreName, devices)
+		if out, err := exec.Command("pvcreate", append([]string{"-y"}, devices...)...).CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("error creating physical volumes: %v: %s", err, out)
+		}
+		if out, err := exec.Command("vgcreate", append([]string{instanceStoreName}, devices...)...).CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("error creating volume group %s: %v: %s", instanceStoreName, err, out)
+		}
+	}
+
+	volumes := make(map[string]string)
+	for i, p := range paths {
+		name := strings.ReplaceAll(strings.TrimPrefix(p, "/"), "/", "-")
+		volumes[p] = "/dev/" + instanceStoreName + "/" + name
+		if _, err := os.Stat(volumes[p]); err == nil {
+			continue
+		}
+
+		// Each volume takes an equal share of the space left for the remaining volumes
+		extents := fmt.Sprintf("%d%%FREE", 100/(len(paths)-i))
+		klog.Infof("Creating logical volume %s with %s of volume group %s", name, extents, instanceStoreName)
+		if out, err := exec.Command("lvcreate", "-y", "-n", name, "-l", extents, instanceStoreName).CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("error creating logical volume %s: %v: %s", name, err, out)
+		}
+	}
+
+	return volumes, nil
+}
+
+// systemdMountUnitName returns the name of the systemd mount unit of a path, which is derived from the path
+func systemdMountUnitName(p string) string {
+	name := strings.ReplaceAll(strings.Trim(p, "/"), "-", `\x2d`)
+	return strings.ReplaceAll(name, "/", "-") + ".mount"
+}
+
+// buildMountUnit builds the systemd mount unit that mounts the instance storage again at boot, before the services start
+func buildMountUnit(what, where, fstype, options string) *nodetasks.Service {
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "kops instance storage mounted at "+where)
+	if fstype == "none" {
+		manifest.Set("Unit", "RequiresMountsFor", what)
+	}
+
+	manifest.Set("Mount", "What", what)
+	manifest.Set("Mount", "Where", where)
+	manifest.Set("Mount", "Type", fstype)
+	manifest.Set("Mount", "Options", options)
+
+	manifest.Set("Install", "WantedBy", "local-fs.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built mount manifest %q\n%s", systemdMountUnitName(where), manifestString)
+
+	service := &nodetasks.Service{
+		Name:       systemdMountUnitName(where),
+		Definition: s(manifestString),
+	}
+	service.InitDefaults()
+
+	return service
+}
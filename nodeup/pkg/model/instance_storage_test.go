@@ -0,0 +1,89 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestFindInstanceStoreDevices(t *testing.T) {
+	sysBlock, err := ioutil.TempDir("", "sys-block")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(sysBlock)
+
+	models := map[string]string{
+		"nvme0n1": "Amazon Elastic Block Store              \n",
+		"nvme1n1": "Amazon EC2 NVMe Instance Storage        \n",
+		"nvme2n1": "Amazon EC2 NVMe Instance Storage        \n",
+		"loop0":   "",
+	}
+	for name, model := range models {
+		dir := filepath.Join(sysBlock, name, "device")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("error creating %s: %v", dir, err)
+		}
+		if model != "" {
+			if err := ioutil.WriteFile(filepath.Join(dir, "model"), []byte(model), 0644); err != nil {
+				t.Fatalf("error writing model of %s: %v", name, err)
+			}
+		}
+	}
+
+	devices, err := findInstanceStoreDevices(sysBlock)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"/dev/nvme1n1", "/dev/nvme2n1"}
+	if !reflect.DeepEqual(devices, expected) {
+		t.Errorf("unexpected devices; expected %v, got %v", expected, devices)
+	}
+}
+
+func TestBuildMountUnit(t *testing.T) {
+	if name := systemdMountUnitName("/mnt/kops-instance-store"); name != `mnt-kops\x2dinstance\x2dstore.mount` {
+		t.Errorf("unexpected unit name %q", name)
+	}
+
+	unit := buildMountUnit("/mnt/kops-instance-store/var-lib-containerd", "/var/lib/containerd", "none", "bind,nofail")
+	if unit.Name != "var-lib-containerd.mount" {
+		t.Errorf("unexpected unit name %q", unit.Name)
+	}
+	expected := `[Unit]
+Description=kops instance storage mounted at /var/lib/containerd
+RequiresMountsFor=/mnt/kops-instance-store/var-lib-containerd
+
+[Mount]
+What=/mnt/kops-instance-store/var-lib-containerd
+Where=/var/lib/containerd
+Type=none
+Options=bind,nofail
+
+[Install]
+WantedBy=local-fs.target
+`
+	if actual := fi.StringValue(unit.Definition); actual != expected {
+		t.Errorf("unexpected unit; expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
@@ -17,9 +17,11 @@ limitations under the License.
 package model
 
 import (
+	"fmt"
 	"path/filepath"
 
 	"k8s.io/kops/pkg/apis/kops/model"
+	"k8s.io/kops/pkg/jointoken"
 	"k8s.io/kops/pkg/wellknownusers"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
@@ -78,6 +80,20 @@ func (b *KopsControllerBuilder) Build(c *fi.ModelBuilderContext) error {
 		Owner:    s(wellknownusers.KopsControllerName),
 	})
 
+	if b.UseJoinTokensForNodeBootstrap() {
+		secret, err := b.SecretStore.Secret(jointoken.SecretName)
+		if err != nil {
+			return fmt.Errorf("joinTokens enabled, but could not load %s secret: %v", jointoken.SecretName, err)
+		}
+		c.AddTask(&nodetasks.File{
+			Path:     filepath.Join(pkiDir, "join-token.key"),
+			Contents: fi.NewBytesResource(secret.Data),
+			Type:     nodetasks.FileType_File,
+			Mode:     s("0600"),
+			Owner:    s(wellknownusers.KopsControllerName),
+		})
+	}
+
 	caList := []string{fi.CertificateIDCA}
 	if model.UseCiliumEtcd(b.Cluster) {
 		caList = append(caList, "etcd-clients-ca-cilium")
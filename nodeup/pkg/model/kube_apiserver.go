@@ -26,6 +26,7 @@ import (
 	"k8s.io/kops/pkg/k8scodecs"
 	"k8s.io/kops/pkg/kubeconfig"
 	"k8s.io/kops/pkg/kubemanifest"
+	"k8s.io/kops/pkg/model/components"
 	"k8s.io/kops/pkg/wellknownports"
 	"k8s.io/kops/pkg/wellknownusers"
 	"k8s.io/kops/upup/pkg/fi"
@@ -59,28 +60,47 @@ func (b *KubeAPIServerBuilder) Build(c *fi.ModelBuilderContext) error {
 		return err
 	}
 
-	if b.Cluster.Spec.EncryptionConfig != nil {
-		if *b.Cluster.Spec.EncryptionConfig {
-			encryptionConfigPath := fi.String(filepath.Join(b.PathSrvKubernetes(), "encryptionconfig.yaml"))
-
-			b.Cluster.Spec.KubeAPIServer.EncryptionProviderConfig = encryptionConfigPath
-
-			key := "encryptionconfig"
-			encryptioncfg, err := b.SecretStore.Secret(key)
-			if err == nil {
-				contents := string(encryptioncfg.Data)
-				t := &nodetasks.File{
-					Path:     *encryptionConfigPath,
-					Contents: fi.NewStringResource(contents),
-					Mode:     fi.String("600"),
-					Type:     nodetasks.FileType_File,
-				}
-				c.AddTask(t)
-			} else {
-				return fmt.Errorf("encryptionConfig enabled, but could not load encryptionconfig secret: %v", err)
-			}
+	if err := b.writeOIDCConfig(c); err != nil {
+		return err
+	}
+
+	if err := b.writeAuditConfig(c); err != nil {
+		return err
+	}
+
+	if err := b.writeExternalEtcdKeypair(c); err != nil {
+		return err
+	}
+
+	if err := b.writeEncryptionConfig(c); err != nil {
+		return err
+	}
+
+	cisHardened := components.IsCISHardened(&b.Cluster.Spec)
+	if b.Cluster.Spec.PodSecurity != nil || cisHardened {
+		admissionConfigPath := filepath.Join(b.PathSrvKubernetes(), "admission-config.yaml")
+
+		b.Cluster.Spec.KubeAPIServer.AdmissionControlConfigFile = admissionConfigPath
+
+		podSecurityAPIVersion := "pod-security.admission.config.k8s.io/v1"
+		if b.IsKubernetesLT("1.23") {
+			podSecurityAPIVersion = "pod-security.admission.config.k8s.io/v1alpha1"
+		} else if b.IsKubernetesLT("1.25") {
+			podSecurityAPIVersion = "pod-security.admission.config.k8s.io/v1beta1"
 		}
+
+		contents, err := buildAdmissionConfiguration(b.Cluster.Spec.PodSecurity, podSecurityAPIVersion, cisHardened)
+		if err != nil {
+			return fmt.Errorf("error building admission configuration: %v", err)
+		}
+		c.AddTask(&nodetasks.File{
+			Path:     admissionConfigPath,
+			Contents: fi.NewBytesResource(contents),
+			Mode:     fi.String("600"),
+			Type:     nodetasks.FileType_File,
+		})
 	}
+
 	{
 		pod, err := b.buildPod()
 		if err != nil {
@@ -129,8 +149,174 @@ func (b *KubeAPIServerBuilder) Build(c *fi.ModelBuilderContext) error {
 	return nil
 }
 
+// writeOIDCConfig writes the structured authentication configuration or the CA file for the OIDC provider
+func (b *KubeAPIServerBuilder) writeOIDCConfig(c *fi.ModelBuilderContext) error {
+	if b.Cluster.Spec.Authentication == nil || b.Cluster.Spec.Authentication.OIDC == nil {
+		return nil
+	}
+	oidc := b.Cluster.Spec.Authentication.OIDC
+	kubeAPIServer := b.Cluster.Spec.KubeAPIServer
+
+	if kubeAPIServer.AuthenticationConfigFile != "" {
+		contents, err := buildAuthenticationConfiguration(oidc)
+		if err != nil {
+			return fmt.Errorf("error building authentication configuration: %v", err)
+		}
+		c.AddTask(&nodetasks.File{
+			Path:     kubeAPIServer.AuthenticationConfigFile,
+			Contents: fi.NewBytesResource(contents),
+			Type:     nodetasks.FileType_File,
+			Mode:     fi.String("600"),
+		})
+	}
+
+	if kubeAPIServer.OIDCCAFile != nil && oidc.CertificateAuthority != "" {
+		c.AddTask(&nodetasks.File{
+			Path:     *kubeAPIServer.OIDCCAFile,
+			Contents: fi.NewStringResource(oidc.CertificateAuthority),
+			Type:     nodetasks.FileType_File,
+			Mode:     fi.String("600"),
+		})
+	}
+
+	return nil
+}
+
+// writeExternalEtcdKeypair writes the CA and client keypair the API server uses for an external etcd cluster
+func (b *KubeAPIServerBuilder) writeExternalEtcdKeypair(c *fi.ModelBuilderContext) error {
+	if !b.UseExternalEtcd() || !b.UseEtcdTLS() {
+		return nil
+	}
+
+	basedir := "/etc/kubernetes/pki/kube-apiserver"
+	if err := b.BuildCertificateTask(c, "etcd-external-ca", filepath.Join(basedir, "etcd-ca.crt"), nil); err != nil {
+		return err
+	}
+	if err := b.BuildCertificateTask(c, "etcd-external-client", filepath.Join(basedir, "etcd-client.crt"), nil); err != nil {
+		return err
+	}
+	if err := b.BuildPrivateKeyTask(c, "etcd-external-client", filepath.Join(basedir, "etcd-client.key"), nil); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// writeEncryptionConfig writes the encryption configuration of the API server, which is the encryptionconfig secret
+// if encryptionConfig is enabled, with the KMS providers of encryptionAtRest in front if that is set too
+func (b *KubeAPIServerBuilder) writeEncryptionConfig(c *fi.ModelBuilderContext) error {
+	encryptionAtRest := b.Cluster.Spec.EncryptionAtRest
+
+	var existing []byte
+	if fi.BoolValue(b.Cluster.Spec.EncryptionConfig) {
+		encryptioncfg, err := b.SecretStore.Secret("encryptionconfig")
+		if err != nil {
+			return fmt.Errorf("encryptionConfig enabled, but could not load encryptionconfig secret: %v", err)
+		}
+		existing = encryptioncfg.Data
+	} else if encryptionAtRest == nil {
+		return nil
+	}
+
+	contents := existing
+	if encryptionAtRest != nil {
+		var err error
+		contents, err = buildEncryptionAtRestConfig(encryptionAtRest, existing)
+		if err != nil {
+			return fmt.Errorf("error building encryption config: %v", err)
+		}
+	}
+
+	encryptionConfigPath := fi.String(filepath.Join(b.PathSrvKubernetes(), "encryptionconfig.yaml"))
+	b.Cluster.Spec.KubeAPIServer.EncryptionProviderConfig = encryptionConfigPath
+
+	c.AddTask(&nodetasks.File{
+		Path:     *encryptionConfigPath,
+		Contents: fi.NewBytesResource(contents),
+		Mode:     fi.String("600"),
+		Type:     nodetasks.FileType_File,
+	})
+
+	return nil
+}
+
+// writeAuditConfig writes the audit policy and audit webhook configuration managed by kops
+func (b *KubeAPIServerBuilder) writeAuditConfig(c *fi.ModelBuilderContext) error {
+	audit := b.Cluster.Spec.Audit
+	if audit == nil {
+		return nil
+	}
+	kubeAPIServer := b.Cluster.Spec.KubeAPIServer
+
+	if audit.Policy != "" {
+		c.AddTask(&nodetasks.File{
+			Path:     kubeAPIServer.AuditPolicyFile,
+			Contents: fi.NewStringResource(audit.Policy),
+			Type:     nodetasks.FileType_File,
+			Mode:     fi.String("600"),
+		})
+	}
+
+	if audit.Webhook != nil {
+		cluster := kubeconfig.KubectlCluster{
+			Server: audit.Webhook.Server,
+		}
+		if audit.Webhook.CertificateAuthority != "" {
+			cluster.CertificateAuthorityData = []byte(audit.Webhook.CertificateAuthority)
+		}
+		context := kubeconfig.KubectlContext{
+			Cluster: "audit-webhook",
+			User:    "kube-apiserver",
+		}
+
+		config := kubeconfig.KubectlConfig{
+			Kind:       "Config",
+			ApiVersion: "v1",
+		}
+		config.Clusters = append(config.Clusters, &kubeconfig.KubectlClusterWithName{
+			Name:    "audit-webhook",
+			Cluster: cluster,
+		})
+		config.Users = append(config.Users, &kubeconfig.KubectlUserWithName{
+			Name: "kube-apiserver",
+		})
+		config.CurrentContext = "audit-webhook"
+		config.Contexts = append(config.Contexts, &kubeconfig.KubectlContextWithName{
+			Name:    "audit-webhook",
+			Context: context,
+		})
+
+		manifest, err := kops.ToRawYaml(config)
+		if err != nil {
+			return fmt.Errorf("error marshaling audit webhook config to yaml: %v", err)
+		}
+
+		c.AddTask(&nodetasks.File{
+			Path:     kubeAPIServer.AuditWebhookConfigFile,
+			Contents: fi.NewBytesResource(manifest),
+			Type:     nodetasks.FileType_File,
+			Mode:     fi.String("600"),
+		})
+	}
+
+	// The log directory is mounted into the apiserver, so it must exist
+	if auditLogPath := kubeAPIServer.AuditLogPath; auditLogPath != nil && *auditLogPath != "-" {
+		if err := c.EnsureTask(&nodetasks.File{
+			Path:        filepath.Dir(*auditLogPath),
+			Type:        nodetasks.FileType_Directory,
+			Mode:        fi.String("0700"),
+			IfNotExists: true,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (b *KubeAPIServerBuilder) writeAuthenticationConfig(c *fi.ModelBuilderContext) error {
-	if b.Cluster.Spec.Authentication == nil || b.Cluster.Spec.Authentication.IsEmpty() {
+	if b.Cluster.Spec.Authentication == nil || (b.Cluster.Spec.Authentication.Kopeio == nil && b.Cluster.Spec.Authentication.Aws == nil) {
+		// OIDC is configured by writeOIDCConfig
 		return nil
 	}
 
@@ -328,7 +514,7 @@ func (b *KubeAPIServerBuilder) buildPod() (*v1.Pod, error) {
 		}
 	}
 
-	if b.UseEtcdManager() && b.UseEtcdTLS() {
+	if (b.UseEtcdManager() || b.UseExternalEtcd()) && b.UseEtcdTLS() {
 		basedir := "/etc/kubernetes/pki/kube-apiserver"
 		kubeAPIServer.EtcdCAFile = filepath.Join(basedir, "etcd-ca.crt")
 		kubeAPIServer.EtcdCertFile = filepath.Join(basedir, "etcd-client.crt")
@@ -513,7 +699,7 @@ func (b *KubeAPIServerBuilder) buildPod() (*v1.Pod, error) {
 		addHostPathMapping(pod, container, name, path)
 	}
 
-	if b.UseEtcdManager() {
+	if b.UseEtcdManager() || b.UseExternalEtcd() {
 		volumeType := v1.HostPathDirectoryOrCreate
 		addHostPathVolume(pod, container,
 			v1.HostPathVolumeSource{
@@ -526,6 +712,19 @@ func (b *KubeAPIServerBuilder) buildPod() (*v1.Pod, error) {
 			})
 	}
 
+	if b.Cluster.Spec.EncryptionAtRest != nil {
+		volumeType := v1.HostPathDirectoryOrCreate
+		addHostPathVolume(pod, container,
+			v1.HostPathVolumeSource{
+				Path: encryptionProviderSocketDir,
+				Type: &volumeType,
+			},
+			v1.VolumeMount{
+				Name:     "kmsplugin",
+				ReadOnly: false,
+			})
+	}
+
 	// Add cloud config file if needed
 	if b.Cluster.Spec.CloudConfig != nil {
 		addHostPathMapping(pod, container, "cloudconfig", CloudConfigFilePath)
@@ -580,6 +779,11 @@ func (b *KubeAPIServerBuilder) buildAnnotations() map[string]string {
 			annotations["dns.alpha.kubernetes.io/internal"] = b.Cluster.Spec.MasterInternalName
 		}
 
+		// With split-horizon DNS, the public name also resolves to the internal addresses in the internal zone
+		if b.Cluster.Spec.API.DNS != nil && b.Cluster.Spec.API.DNS.InternalZoneID != "" {
+			annotations["dns.alpha.kubernetes.io/internal"] = b.Cluster.Spec.MasterInternalName + "," + b.Cluster.Spec.MasterPublicName
+		}
+
 		if b.Cluster.Spec.API.DNS != nil {
 			annotations["dns.alpha.kubernetes.io/external"] = b.Cluster.Spec.MasterPublicName
 		}
@@ -27,6 +27,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/session"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/flagbuilder"
@@ -37,12 +38,16 @@ import (
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
 	"k8s.io/kops/util/pkg/distributions"
+	"sigs.k8s.io/yaml"
 )
 
 const (
 	// containerizedMounterHome is the path where we install the containerized mounter (on ContainerOS)
 	containerizedMounterHome = "/home/kubernetes/containerized_mounter"
 
+	// kubeletConfigFilePath is the path of the kubelet configuration file, for the settings that have no flag
+	kubeletConfigFilePath = "/var/lib/kubelet/kubelet-config.yaml"
+
 	// kubeletService is the name of the kubelet service
 	kubeletService = "kubelet.service"
 )
@@ -75,6 +80,14 @@ func (b *KubeletBuilder) Build(c *fi.ModelBuilderContext) error {
 		c.AddTask(t)
 	}
 
+	if usesKubeletConfigFile(kubeletConfig) {
+		t, err := b.buildKubeletConfigFile(kubeletConfig)
+		if err != nil {
+			return err
+		}
+		c.AddTask(t)
+	}
+
 	{
 		// @TODO Extract to common function?
 		assetName := "kubelet"
@@ -194,6 +207,10 @@ func (b *KubeletBuilder) buildSystemdEnvironmentFile(kubeletConfig *kops.Kubelet
 		return nil, fmt.Errorf("error building kubelet flags: %v", err)
 	}
 
+	if usesKubeletConfigFile(kubeletConfig) {
+		flags += " --config=" + kubeletConfigFilePath
+	}
+
 	// Add cloud config file if needed
 	// We build this flag differently because it depends on CloudConfig, and to expose it directly
 	// would be a degree of freedom we don't have (we'd have to write the config to different files)
@@ -233,8 +250,15 @@ func (b *KubeletBuilder) buildSystemdEnvironmentFile(kubeletConfig *kops.Kubelet
 	}
 
 	if b.UseKopsControllerForNodeBootstrap() {
-		flags += " --tls-cert-file=" + b.PathSrvKubernetes() + "/kubelet-server.crt"
-		flags += " --tls-private-key-file=" + b.PathSrvKubernetes() + "/kubelet-server.key"
+		if !b.HasAPIServer && b.Cluster.Spec.NodeCertificates != nil && b.Cluster.Spec.NodeCertificates.KubeletValidity != nil {
+			// The certificates kops-controller issues are short-lived, so the kubelet renews them
+			// through certificate signing requests that kops-controller approves.
+			flags += " --rotate-certificates=true"
+			flags += " --rotate-server-certificates=true"
+		} else {
+			flags += " --tls-cert-file=" + b.PathSrvKubernetes() + "/kubelet-server.crt"
+			flags += " --tls-private-key-file=" + b.PathSrvKubernetes() + "/kubelet-server.key"
+		}
 	}
 
 	sysconfig := "DAEMON_ARGS=\"" + flags + "\"\n"
@@ -250,6 +274,66 @@ func (b *KubeletBuilder) buildSystemdEnvironmentFile(kubeletConfig *kops.Kubelet
 	return t, nil
 }
 
+// kubeletConfiguration is the subset of the kubelet.config.k8s.io/v1beta1 KubeletConfiguration that kops writes.
+type kubeletConfiguration struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+
+	MemorySwap                       *kubeletMemorySwapConfiguration                `json:"memorySwap,omitempty"`
+	MemoryManagerPolicy              string                                         `json:"memoryManagerPolicy,omitempty"`
+	ReservedMemory                   []kops.KubeletReservedMemory                   `json:"reservedMemory,omitempty"`
+	ShutdownGracePeriod              *metav1.Duration                               `json:"shutdownGracePeriod,omitempty"`
+	ShutdownGracePeriodCriticalPods  *metav1.Duration                               `json:"shutdownGracePeriodCriticalPods,omitempty"`
+	ShutdownGracePeriodByPodPriority []kops.KubeletShutdownGracePeriodByPodPriority `json:"shutdownGracePeriodByPodPriority,omitempty"`
+}
+
+type kubeletMemorySwapConfiguration struct {
+	SwapBehavior string `json:"swapBehavior,omitempty"`
+}
+
+// usesKubeletConfigFile returns true if any of the settings that can only be set through a KubeletConfiguration file is set.
+func usesKubeletConfigFile(kubeletConfig *kops.KubeletConfigSpec) bool {
+	return kubeletConfig.MemorySwapBehavior != "" ||
+		kubeletConfig.MemoryManagerPolicy != "" ||
+		len(kubeletConfig.ReservedMemory) != 0 ||
+		kubeletConfig.ShutdownGracePeriod != nil ||
+		kubeletConfig.ShutdownGracePeriodCriticalPods != nil ||
+		len(kubeletConfig.ShutdownGracePeriodByPodPriority) != 0
+}
+
+// buildKubeletConfigFile writes the kubelet settings that can only be set through a KubeletConfiguration file.
+// The settings are those of the instance group, merged over those of the cluster, so each instance group gets its own file.
+// Flags take precedence over the values in the file.
+func (b *KubeletBuilder) buildKubeletConfigFile(kubeletConfig *kops.KubeletConfigSpec) (*nodetasks.File, error) {
+	config := &kubeletConfiguration{
+		APIVersion:                       "kubelet.config.k8s.io/v1beta1",
+		Kind:                             "KubeletConfiguration",
+		MemoryManagerPolicy:              kubeletConfig.MemoryManagerPolicy,
+		ReservedMemory:                   kubeletConfig.ReservedMemory,
+		ShutdownGracePeriod:              kubeletConfig.ShutdownGracePeriod,
+		ShutdownGracePeriodCriticalPods:  kubeletConfig.ShutdownGracePeriodCriticalPods,
+		ShutdownGracePeriodByPodPriority: kubeletConfig.ShutdownGracePeriodByPodPriority,
+	}
+	if kubeletConfig.MemorySwapBehavior != "" {
+		config.MemorySwap = &kubeletMemorySwapConfiguration{
+			SwapBehavior: kubeletConfig.MemorySwapBehavior,
+		}
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("error building kubelet configuration file: %v", err)
+	}
+
+	return &nodetasks.File{
+		Path:           kubeletConfigFilePath,
+		Contents:       fi.NewBytesResource(data),
+		Type:           nodetasks.FileType_File,
+		Mode:           s("0644"),
+		BeforeServices: []string{kubeletService},
+	}, nil
+}
+
 // buildSystemdService is responsible for generating the kubelet systemd unit
 func (b *KubeletBuilder) buildSystemdService() *nodetasks.Service {
 	kubeletCommand := b.kubeletPath()
@@ -265,8 +349,17 @@ func (b *KubeletBuilder) buildSystemdService() *nodetasks.Service {
 	default:
 		klog.Warningf("unknown container runtime %q", b.Cluster.Spec.ContainerRuntime)
 	}
+	if b.NodeupConfig.SecurityModules != nil && fi.BoolValue(b.NodeupConfig.SecurityModules.SELinux) {
+		// The kubelet must not start the pods before SELinux is enforced and their directories are labelled
+		manifest.Set("Unit", "Requires", selinuxService)
+		manifest.Set("Unit", "After", selinuxService)
+	}
 
 	manifest.Set("Service", "EnvironmentFile", "/etc/sysconfig/kubelet")
+	if b.Cluster.Spec.EgressProxy != nil {
+		// The proxy settings are written to /etc/environment by the bootstrap script
+		manifest.Set("Service", "EnvironmentFile", "/etc/environment")
+	}
 
 	// @check if we are using bootstrap tokens and file checker
 	if !b.IsMaster && b.UseBootstrapTokens() {
@@ -20,7 +20,10 @@ import (
 	"fmt"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/nodeup"
@@ -345,3 +348,62 @@ func RunGoldenTest(t *testing.T, basedir string, key string, builder func(*Nodeu
 
 	testutils.ValidateTasks(t, filepath.Join(basedir, "tasks-"+key+".yaml"), context)
 }
+
+func Test_KubeletConfigFile(t *testing.T) {
+	cluster := &kops.Cluster{}
+	cluster.Spec.KubernetesVersion = "1.22.0"
+	cluster.Spec.Kubelet = &kops.KubeletConfigSpec{
+		ShutdownGracePeriod: &metav1.Duration{Duration: 30 * time.Second},
+	}
+
+	instanceGroup := &kops.InstanceGroup{}
+	instanceGroup.Spec.Role = kops.InstanceGroupRoleNode
+	instanceGroup.Spec.Kubelet = &kops.KubeletConfigSpec{
+		ShutdownGracePeriodCriticalPods: &metav1.Duration{Duration: 10 * time.Second},
+		MemoryManagerPolicy:             "Static",
+		ReservedMemory: []kops.KubeletReservedMemory{
+			{NUMANode: 0, Limits: map[string]resource.Quantity{"memory": resource.MustParse("1Gi")}},
+		},
+	}
+
+	b := &KubeletBuilder{
+		&NodeupModelContext{
+			Cluster:       cluster,
+			InstanceGroup: instanceGroup,
+			NodeupConfig:  nodeup.NewConfig(cluster, instanceGroup),
+		},
+	}
+	if err := b.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	kubeletConfig, err := b.buildKubeletConfigSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !usesKubeletConfigFile(kubeletConfig) {
+		t.Fatalf("expected the kubelet configuration file to be used")
+	}
+
+	task, err := b.buildKubeletConfigFile(kubeletConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := fi.ResourceAsString(task.Contents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `apiVersion: kubelet.config.k8s.io/v1beta1
+kind: KubeletConfiguration
+memoryManagerPolicy: Static
+reservedMemory:
+- limits:
+    memory: 1Gi
+  numaNode: 0
+shutdownGracePeriod: 30s
+shutdownGracePeriodCriticalPods: 10s
+`
+	if actual != expected {
+		t.Errorf("unexpected kubelet configuration file:\n%s", actual)
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/kops/nodeup/pkg/model"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+// AntreaBuilder prepares the nodes for the Open vSwitch datapath of Antrea
+type AntreaBuilder struct {
+	*model.NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &AntreaBuilder{}
+
+// Build is responsible for configuring the kernel modules needed by Antrea
+func (b *AntreaBuilder) Build(c *fi.ModelBuilderContext) error {
+	networking := b.Cluster.Spec.Networking
+
+	if networking.Antrea == nil {
+		return nil
+	}
+
+	release, err := kernelRelease()
+	if err != nil {
+		return err
+	}
+	found, err := kernelModuleAvailable(release, "openvswitch")
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("antrea requires the openvswitch kernel module, which kernel %s does not provide", release)
+	}
+
+	// The agent loads the module when it starts, but loading it at boot keeps the datapath ready across restarts
+	c.AddTask(&nodetasks.File{
+		Path:            "/etc/modules-load.d/antrea.conf",
+		Contents:        fi.NewStringResource("openvswitch\n"),
+		Type:            nodetasks.FileType_File,
+		OnChangeExecute: [][]string{{"systemctl", "restart", "systemd-modules-load.service"}},
+	})
+
+	return nil
+}
+
+// kernelModuleAvailable returns true if the kernel either includes the module or ships it as a loadable module
+func kernelModuleAvailable(release string, name string) (bool, error) {
+	for _, index := range []string{"modules.builtin", "modules.dep"} {
+		p := filepath.Join("/lib/modules", release, index)
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return false, fmt.Errorf("error reading %q: %v", p, err)
+		}
+		for _, line := range strings.Split(string(b), "\n") {
+			// Lines look like "kernel/net/openvswitch/openvswitch.ko: kernel/net/nsh/nsh.ko", optionally compressed
+			module := strings.SplitN(line, ":", 2)[0]
+			base := filepath.Base(module)
+			if base == name+".ko" || strings.HasPrefix(base, name+".ko.") {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
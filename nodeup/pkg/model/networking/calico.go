@@ -17,6 +17,10 @@ limitations under the License.
 package networking
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"k8s.io/kops/nodeup/pkg/model"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
@@ -36,10 +40,65 @@ func (b *CalicoBuilder) Build(c *fi.ModelBuilderContext) error {
 	if networking.Calico == nil {
 		return nil
 	}
+	calico := networking.Calico
 
 	if b.Distribution.IsUbuntu() {
 		c.AddTask(&nodetasks.Package{Name: "wireguard"})
 	}
 
+	if calico.BPFEnabled || calico.WireguardEnabled {
+		release, err := kernelRelease()
+		if err != nil {
+			return err
+		}
+
+		// RHEL 8.2 backports what the eBPF dataplane needs to its 4.18 kernel
+		if calico.BPFEnabled && !b.Distribution.IsRHELFamily() {
+			if err := checkKernelVersion(release, 5, 3, "the Calico eBPF dataplane"); err != nil {
+				return err
+			}
+		}
+
+		if calico.WireguardEnabled {
+			// Older kernels have no wireguard module, unless the distribution packages it
+			if !b.Distribution.IsUbuntu() {
+				if err := checkKernelVersion(release, 5, 6, "Calico WireGuard encryption"); err != nil {
+					return err
+				}
+			}
+
+			// Felix only creates the WireGuard interface if the module can be found, so load it at every boot
+			c.AddTask(&nodetasks.File{
+				Path:            "/etc/modules-load.d/calico-wireguard.conf",
+				Contents:        fi.NewStringResource("wireguard\n"),
+				Type:            nodetasks.FileType_File,
+				OnChangeExecute: [][]string{{"systemctl", "restart", "systemd-modules-load.service"}},
+			})
+		}
+	}
+
+	return nil
+}
+
+// checkKernelVersion returns an error if the kernel release is older than major.minor
+func checkKernelVersion(release string, major, minor int, feature string) error {
+	fields := strings.FieldsFunc(release, func(r rune) bool {
+		return r == '.' || r == '-' || r == '+'
+	})
+	if len(fields) < 2 {
+		return fmt.Errorf("cannot parse kernel release %q", release)
+	}
+	actualMajor, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return fmt.Errorf("cannot parse kernel release %q: %v", release, err)
+	}
+	actualMinor, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return fmt.Errorf("cannot parse kernel release %q: %v", release, err)
+	}
+
+	if actualMajor < major || (actualMajor == major && actualMinor < minor) {
+		return fmt.Errorf("%s requires Linux %d.%d or newer, but the kernel is %s", feature, major, minor, release)
+	}
 	return nil
 }
@@ -17,10 +17,8 @@ limitations under the License.
 package networking
 
 import (
-	"fmt"
 	"path/filepath"
 
-	"golang.org/x/sys/unix"
 	"k8s.io/kops/nodeup/pkg/model"
 	apiModel "k8s.io/kops/pkg/apis/kops/model"
 	"k8s.io/kops/upup/pkg/fi"
@@ -63,19 +61,12 @@ func (b *CiliumBuilder) Build(c *fi.ModelBuilderContext) error {
 
 func (b *CiliumBuilder) buildBPFMount(c *fi.ModelBuilderContext) error {
 
-	var fsdata unix.Statfs_t
-	err := unix.Statfs("/sys/fs/bpf", &fsdata)
-
+	// systemd v238 includes the bpffs mount by default; and gives an error "has a bad unit file setting" if we try to mount it again (see mount_point_is_api)
+	alreadyMounted, err := isBPFFSMounted("/sys/fs/bpf")
 	if err != nil {
-		return fmt.Errorf("error checking for /sys/fs/bpf: %v", err)
+		return err
 	}
 
-	// equivalent to unix.BPF_FS_MAGIC in golang.org/x/sys/unix
-	BPF_FS_MAGIC := uint32(0xcafe4a11)
-
-	// systemd v238 includes the bpffs mount by default; and gives an error "has a bad unit file setting" if we try to mount it again (see mount_point_is_api)
-	alreadyMounted := uint32(fsdata.Type) == BPF_FS_MAGIC
-
 	if !alreadyMounted {
 		unit := `
 [Unit]
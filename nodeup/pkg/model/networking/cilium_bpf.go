@@ -0,0 +1,38 @@
+// +build !windows
+
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// isBPFFSMounted returns true if the BPF filesystem is mounted at path
+func isBPFFSMounted(path string) (bool, error) {
+	var fsdata unix.Statfs_t
+	if err := unix.Statfs(path, &fsdata); err != nil {
+		return false, fmt.Errorf("error checking for %s: %v", path, err)
+	}
+
+	// equivalent to unix.BPF_FS_MAGIC in golang.org/x/sys/unix
+	BPF_FS_MAGIC := uint32(0xcafe4a11)
+
+	return uint32(fsdata.Type) == BPF_FS_MAGIC, nil
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/systemd"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+const (
+	// hugePagesService is the unit reserving the huge pages
+	hugePagesService = "kops-hugepages.service"
+)
+
+// NodeTuningBuilder loads the kernel modules, reserves the huge pages and installs the udev rules of the node tuning spec.
+// The sysctls of the node tuning spec are written by the SysctlBuilder.
+type NodeTuningBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &NodeTuningBuilder{}
+
+// Build is responsible for configuring the kernel of the instance
+func (b *NodeTuningBuilder) Build(c *fi.ModelBuilderContext) error {
+	nodeTuning := b.NodeupConfig.NodeTuning
+	if nodeTuning == nil {
+		return nil
+	}
+
+	if len(nodeTuning.KernelModules) > 0 {
+		// systemd-modules-load loads the modules at every boot, before the sysctls are applied
+		c.AddTask(&nodetasks.File{
+			Path:            "/etc/modules-load.d/kops.conf",
+			Contents:        fi.NewStringResource(strings.Join(nodeTuning.KernelModules, "\n") + "\n"),
+			Type:            nodetasks.FileType_File,
+			OnChangeExecute: [][]string{{"systemctl", "restart", "systemd-modules-load.service"}},
+		})
+	}
+
+	if len(nodeTuning.HugePages) > 0 {
+		service, err := b.buildHugePagesService(nodeTuning.HugePages)
+		if err != nil {
+			return err
+		}
+		c.AddTask(service)
+	}
+
+	var rules []string
+	for name := range nodeTuning.UdevRules {
+		rules = append(rules, name)
+	}
+	sort.Strings(rules)
+	for _, name := range rules {
+		c.AddTask(&nodetasks.File{
+			Path:     path.Join("/etc/udev/rules.d", name),
+			Contents: fi.NewStringResource(nodeTuning.UdevRules[name]),
+			Type:     nodetasks.FileType_File,
+			Mode:     s("0644"),
+			OnChangeExecute: [][]string{
+				{"udevadm", "control", "--reload-rules"},
+				{"udevadm", "trigger"},
+			},
+		})
+	}
+
+	return nil
+}
+
+// buildHugePagesService builds the unit reserving the huge pages of each size before the kubelet starts
+func (b *NodeTuningBuilder) buildHugePagesService(hugePages map[string]int32) (*nodetasks.Service, error) {
+	var sizes []string
+	for size := range hugePages {
+		sizes = append(sizes, size)
+	}
+	sort.Strings(sizes)
+
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Reserve the kops huge pages")
+	manifest.Set("Unit", "Before", kubeletService)
+
+	manifest.Set("Service", "Type", "oneshot")
+	manifest.Set("Service", "RemainAfterExit", "yes")
+	for _, size := range sizes {
+		q, err := resource.ParseQuantity(size)
+		if err != nil {
+			return nil, fmt.Errorf("invalid huge page size %q: %v", size, err)
+		}
+		nrHugePages := fmt.Sprintf("/sys/kernel/mm/hugepages/hugepages-%dkB/nr_hugepages", q.Value()/1024)
+		manifest.Set("Service", "ExecStart", fmt.Sprintf("/bin/bash -c 'echo %d > %s'", hugePages[size], nrHugePages))
+	}
+
+	manifest.Set("Install", "WantedBy", "multi-user.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", hugePagesService, manifestString)
+
+	service := &nodetasks.Service{
+		Name:       hugePagesService,
+		Definition: s(manifestString),
+	}
+	service.InitDefaults()
+
+	return service, nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+func TestNodeTuningBuilder(t *testing.T) {
+	b := &NodeTuningBuilder{
+		NodeupModelContext: &NodeupModelContext{
+			Cluster: &kops.Cluster{},
+			NodeupConfig: &nodeup.Config{
+				NodeTuning: &kops.NodeTuningSpec{
+					KernelModules: []string{"ip_vs", "br_netfilter"},
+					HugePages:     map[string]int32{"2Mi": 512, "1Gi": 2},
+					UdevRules:     map[string]string{"90-nvme.rules": "KERNEL==\"nvme*\"\n"},
+				},
+			},
+		},
+	}
+
+	c := &fi.ModelBuilderContext{Tasks: make(map[string]fi.Task)}
+	if err := b.Build(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	modules, ok := c.Tasks["File//etc/modules-load.d/kops.conf"].(*nodetasks.File)
+	if !ok {
+		t.Fatalf("kernel modules file not found in %v", c.Tasks)
+	}
+	if contents, err := fi.ResourceAsString(modules.Contents); err != nil || contents != "ip_vs\nbr_netfilter\n" {
+		t.Errorf("unexpected kernel modules file %q (%v)", contents, err)
+	}
+
+	if _, ok := c.Tasks["File//etc/udev/rules.d/90-nvme.rules"]; !ok {
+		t.Errorf("udev rules file not found in %v", c.Tasks)
+	}
+
+	hugePages, ok := c.Tasks["Service/"+hugePagesService].(*nodetasks.Service)
+	if !ok {
+		t.Fatalf("huge pages service not found in %v", c.Tasks)
+	}
+	expected := `[Unit]
+Description=Reserve the kops huge pages
+Before=kubelet.service
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=/bin/bash -c 'echo 2 > /sys/kernel/mm/hugepages/hugepages-1048576kB/nr_hugepages'
+ExecStart=/bin/bash -c 'echo 512 > /sys/kernel/mm/hugepages/hugepages-2048kB/nr_hugepages'
+
+[Install]
+WantedBy=multi-user.target
+`
+	if actual := fi.StringValue(hugePages.Definition); actual != expected {
+		t.Errorf("unexpected huge pages service; expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
@@ -18,6 +18,7 @@ package model
 
 import (
 	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
 	"k8s.io/kops/util/pkg/distributions"
@@ -48,15 +49,7 @@ func (b *NTPBuilder) Build(c *fi.ModelBuilderContext) error {
 		return nil
 	}
 
-	var ntpHost string
-	switch b.Cluster.Spec.CloudProvider {
-	case "aws":
-		ntpHost = "169.254.169.123"
-	case "gce":
-		ntpHost = "time.google.com"
-	default:
-		ntpHost = ""
-	}
+	ntpHost := NTPHost(b.Cluster)
 
 	if b.Distribution.IsDebianFamily() {
 		if b.Distribution.IsUbuntu() {
@@ -118,6 +111,23 @@ NTP=` + host + `
 	}
 }
 
+// NTPHost returns the time server that kops configures on the instances of the cluster, or "" when it configures
+// none and the distribution's default servers are kept.
+func NTPHost(cluster *kops.Cluster) string {
+	if n := cluster.Spec.NTP; n != nil && n.Managed != nil && !*n.Managed {
+		return ""
+	}
+
+	switch cluster.Spec.CloudProvider {
+	case "aws":
+		return "169.254.169.123"
+	case "gce":
+		return "time.google.com"
+	default:
+		return ""
+	}
+}
+
 // managed determines if kops should manage the installation and configuration of NTP.
 func (b *NTPBuilder) managed() bool {
 	n := b.Cluster.Spec.NTP
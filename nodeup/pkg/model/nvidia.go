@@ -0,0 +1,59 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+// NvidiaBuilder installs the NVIDIA driver and container toolkit on instances with NVIDIA GPUs
+type NvidiaBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &NvidiaBuilder{}
+
+// Build is responsible for installing the NVIDIA packages
+func (b *NvidiaBuilder) Build(c *fi.ModelBuilderContext) error {
+	nvidiaGPU := b.NodeupConfig.NvidiaGPU
+	if nvidiaGPU == nil {
+		return nil
+	}
+
+	if nvidiaGPU.SkipInstall {
+		klog.Infof("SkipInstall is set to true; won't install the NVIDIA driver and container toolkit")
+		return nil
+	}
+
+	if !b.Distribution.IsUbuntu() {
+		return fmt.Errorf("installing the NVIDIA driver is only supported on Ubuntu; use an image that ships it and set nvidiaGPU.skipInstall")
+	}
+
+	c.AddTask(&nodetasks.AptSource{
+		Name:    "nvidia-container-toolkit",
+		Keyring: "https://nvidia.github.io/libnvidia-container/gpgkey",
+		Sources: []string{"https://nvidia.github.io/libnvidia-container/stable/deb/$(ARCH) /"},
+	})
+	c.AddTask(&nodetasks.Package{Name: fi.StringValue(nvidiaGPU.DriverPackage)})
+	c.AddTask(&nodetasks.Package{Name: "nvidia-container-toolkit"})
+
+	return nil
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/slice"
+)
+
+// AdmissionConfiguration is the configuration of the API server admission plugins
+type AdmissionConfiguration struct {
+	APIVersion string                  `json:"apiVersion"`
+	Kind       string                  `json:"kind"`
+	Plugins    []AdmissionPluginConfig `json:"plugins"`
+}
+
+// AdmissionPluginConfig holds the configuration of a single admission plugin
+type AdmissionPluginConfig struct {
+	Name          string      `json:"name"`
+	Configuration interface{} `json:"configuration"`
+}
+
+// PodSecurityConfiguration is the configuration of the PodSecurity admission plugin
+type PodSecurityConfiguration struct {
+	APIVersion string                `json:"apiVersion"`
+	Kind       string                `json:"kind"`
+	Defaults   PodSecurityDefaults   `json:"defaults"`
+	Exemptions PodSecurityExemptions `json:"exemptions"`
+}
+
+// PodSecurityDefaults are the levels applied to namespaces without pod-security.kubernetes.io labels
+type PodSecurityDefaults struct {
+	Enforce        string `json:"enforce"`
+	EnforceVersion string `json:"enforce-version"`
+	Audit          string `json:"audit"`
+	AuditVersion   string `json:"audit-version"`
+	Warn           string `json:"warn"`
+	WarnVersion    string `json:"warn-version"`
+}
+
+// PodSecurityExemptions are the requests the policies do not apply to
+type PodSecurityExemptions struct {
+	Usernames      []string `json:"usernames"`
+	Namespaces     []string `json:"namespaces"`
+	RuntimeClasses []string `json:"runtimeClasses"`
+}
+
+// EventRateLimitConfiguration is the configuration of the EventRateLimit admission plugin
+type EventRateLimitConfiguration struct {
+	APIVersion string           `json:"apiVersion"`
+	Kind       string           `json:"kind"`
+	Limits     []EventRateLimit `json:"limits"`
+}
+
+// EventRateLimit limits the rate of the events accepted by the API server
+type EventRateLimit struct {
+	Type  string `json:"type"`
+	QPS   int32  `json:"qps"`
+	Burst int32  `json:"burst"`
+}
+
+// buildAdmissionConfiguration builds the admission configuration holding the PodSecurity defaults and exemptions,
+// when podSecurity is set, and the limits of the EventRateLimit plugin, when eventRateLimit is set.
+// podSecurityAPIVersion depends on the Kubernetes version, as the configuration API graduated with the plugin.
+func buildAdmissionConfiguration(podSecurity *kops.PodSecuritySpec, podSecurityAPIVersion string, eventRateLimit bool) ([]byte, error) {
+	config := &AdmissionConfiguration{
+		APIVersion: "apiserver.config.k8s.io/v1",
+		Kind:       "AdmissionConfiguration",
+		Plugins:    []AdmissionPluginConfig{},
+	}
+
+	if podSecurity != nil {
+		namespaces := []string{"kube-system"}
+		for _, namespace := range podSecurity.ExemptNamespaces {
+			if !slice.Contains(namespaces, namespace) {
+				namespaces = append(namespaces, namespace)
+			}
+		}
+
+		config.Plugins = append(config.Plugins, AdmissionPluginConfig{
+			Name: "PodSecurity",
+			Configuration: &PodSecurityConfiguration{
+				APIVersion: podSecurityAPIVersion,
+				Kind:       "PodSecurityConfiguration",
+				Defaults: PodSecurityDefaults{
+					Enforce:        podSecurity.Enforce,
+					EnforceVersion: podSecurity.Version,
+					Audit:          podSecurity.Audit,
+					AuditVersion:   podSecurity.Version,
+					Warn:           podSecurity.Warn,
+					WarnVersion:    podSecurity.Version,
+				},
+				Exemptions: PodSecurityExemptions{
+					Usernames:      append([]string{}, podSecurity.ExemptUsernames...),
+					Namespaces:     namespaces,
+					RuntimeClasses: append([]string{}, podSecurity.ExemptRuntimeClasses...),
+				},
+			},
+		})
+	}
+
+	if eventRateLimit {
+		config.Plugins = append(config.Plugins, AdmissionPluginConfig{
+			Name: "EventRateLimit",
+			Configuration: &EventRateLimitConfiguration{
+				APIVersion: "eventratelimit.admission.k8s.io/v1alpha1",
+				Kind:       "Configuration",
+				Limits: []EventRateLimit{
+					{Type: "Server", QPS: 50, Burst: 100},
+				},
+			},
+		})
+	}
+
+	return kops.ToRawYaml(config)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+func TestBuildAdmissionConfiguration(t *testing.T) {
+	podSecurity := &kops.PodSecuritySpec{
+		Enforce:              "baseline",
+		Audit:                "restricted",
+		Warn:                 "restricted",
+		Version:              "latest",
+		ExemptNamespaces:     []string{"monitoring", "kube-system"},
+		ExemptRuntimeClasses: []string{"gvisor"},
+	}
+
+	actual, err := buildAdmissionConfiguration(podSecurity, "pod-security.admission.config.k8s.io/v1beta1", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `apiVersion: apiserver.config.k8s.io/v1
+kind: AdmissionConfiguration
+plugins:
+- configuration:
+    apiVersion: pod-security.admission.config.k8s.io/v1beta1
+    defaults:
+      audit: restricted
+      audit-version: latest
+      enforce: baseline
+      enforce-version: latest
+      warn: restricted
+      warn-version: latest
+    exemptions:
+      namespaces:
+      - kube-system
+      - monitoring
+      runtimeClasses:
+      - gvisor
+      usernames: []
+    kind: PodSecurityConfiguration
+  name: PodSecurity
+`
+	if string(actual) != expected {
+		t.Errorf("unexpected configuration; expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+func TestBuildAdmissionConfigurationEventRateLimit(t *testing.T) {
+	actual, err := buildAdmissionConfiguration(nil, "pod-security.admission.config.k8s.io/v1", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `apiVersion: apiserver.config.k8s.io/v1
+kind: AdmissionConfiguration
+plugins:
+- configuration:
+    apiVersion: eventratelimit.admission.k8s.io/v1alpha1
+    kind: Configuration
+    limits:
+    - burst: 100
+      qps: 50
+      type: Server
+  name: EventRateLimit
+`
+	if string(actual) != expected {
+		t.Errorf("unexpected configuration; expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
@@ -20,10 +20,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 
+	channelsapi "k8s.io/kops/channels/pkg/api"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/model"
 	"k8s.io/kops/pkg/apis/kops/util"
 	"k8s.io/kops/pkg/assets"
 	"k8s.io/kops/pkg/dns"
@@ -32,6 +36,7 @@ import (
 	"k8s.io/kops/pkg/systemd"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+	"k8s.io/kops/upup/pkg/fi/utils"
 	"k8s.io/kops/util/pkg/distributions"
 
 	"k8s.io/kops/util/pkg/proxy"
@@ -99,8 +104,30 @@ func (t *ProtokubeBuilder) Build(c *fi.ModelBuilderContext) error {
 			Mode:     s("0400"),
 		})
 
+		facts, err := t.buildClusterFacts()
+		if err != nil {
+			return err
+		}
+		c.AddTask(&nodetasks.File{
+			Path:     "/var/lib/kops/cluster-facts.yaml",
+			Contents: fi.NewBytesResource(facts),
+			Type:     nodetasks.FileType_File,
+			Mode:     s("0644"),
+		})
+
+		overrides, err := t.buildAddonOverrides()
+		if err != nil {
+			return err
+		}
+		c.AddTask(&nodetasks.File{
+			Path:     "/var/lib/kops/addon-overrides.yaml",
+			Contents: fi.NewBytesResource(overrides),
+			Type:     nodetasks.FileType_File,
+			Mode:     s("0644"),
+		})
+
 		// retrieve the etcd peer certificates and private keys from the keystore
-		if !t.UseEtcdManager() && t.UseEtcdTLS() {
+		if !t.UseEtcdManager() && !t.UseExternalEtcd() && t.UseEtcdTLS() {
 			for _, x := range []string{"etcd", "etcd-peer", "etcd-client"} {
 				if err := t.BuildCertificateTask(c, x, fmt.Sprintf("%s.pem", x), nil); err != nil {
 					return err
@@ -129,6 +156,60 @@ func (t *ProtokubeBuilder) Build(c *fi.ModelBuilderContext) error {
 	return nil
 }
 
+// buildClusterFacts generates the cluster facts that channels evaluates addon conditions against
+func (t *ProtokubeBuilder) buildClusterFacts() ([]byte, error) {
+	facts := &channelsapi.ClusterFacts{
+		ClusterName:   t.Cluster.ObjectMeta.Name,
+		CloudProvider: t.Cluster.Spec.CloudProvider,
+		Networking:    networkingName(t.Cluster.Spec.Networking),
+	}
+	if t.Cluster.Spec.KubeAPIServer != nil {
+		for k, v := range t.Cluster.Spec.KubeAPIServer.FeatureGates {
+			enabled, err := strconv.ParseBool(v)
+			if err != nil {
+				klog.Warningf("ignoring feature gate %q with invalid value %q", k, v)
+				continue
+			}
+			if facts.FeatureGates == nil {
+				facts.FeatureGates = make(map[string]bool)
+			}
+			facts.FeatureGates[k] = enabled
+		}
+	}
+	return utils.YamlMarshal(facts)
+}
+
+// buildAddonOverrides generates the list of addons disabled or pinned in the cluster spec, that channels honors
+func (t *ProtokubeBuilder) buildAddonOverrides() ([]byte, error) {
+	overrides := []*channelsapi.AddonOverride{}
+	for _, addon := range t.Cluster.Spec.Addons {
+		if addon.Name == "" {
+			continue
+		}
+		overrides = append(overrides, &channelsapi.AddonOverride{
+			Name:         addon.Name,
+			Disabled:     addon.Disabled,
+			Version:      addon.Version,
+			ManifestHash: addon.ManifestHash,
+		})
+	}
+	return utils.YamlMarshal(overrides)
+}
+
+// networkingName returns the name of the networking provider configured in the spec, as in its json field
+func networkingName(networking *kops.NetworkingSpec) string {
+	if networking == nil {
+		return ""
+	}
+	v := reflect.ValueOf(networking).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).Kind() == reflect.Ptr && !v.Field(i).IsNil() {
+			return strings.Split(v.Type().Field(i).Tag.Get("json"), ",")[0]
+		}
+	}
+	return ""
+}
+
 // buildSystemdService generates the manifest for the protokube service
 func (t *ProtokubeBuilder) buildSystemdService() (*nodetasks.Service, error) {
 	k8sVersion, err := util.ParseKubernetesVersion(t.Cluster.Spec.KubernetesVersion)
@@ -175,6 +256,7 @@ type ProtokubeFlags struct {
 	Channels                  []string `json:"channels,omitempty" flag:"channels"`
 	Cloud                     *string  `json:"cloud,omitempty" flag:"cloud"`
 	Containerized             *bool    `json:"containerized,omitempty" flag:"containerized"`
+	DiscoveryCA               *string  `json:"discoveryCA,omitempty" flag:"discovery-ca"`
 	DNSInternalSuffix         *string  `json:"dnsInternalSuffix,omitempty" flag:"dns-internal-suffix"`
 	DNSProvider               *string  `json:"dnsProvider,omitempty" flag:"dns"`
 	DNSServer                 *string  `json:"dns-server,omitempty" flag:"dns-server"`
@@ -210,9 +292,12 @@ type ProtokubeFlags struct {
 	// NodeName is the name of the node as will be created in kubernetes.  Primarily used by BootstrapMasterNodeLabels.
 	NodeName string `json:"nodeName,omitempty" flag:"node-name"`
 
-	GossipProtocol *string `json:"gossip-protocol" flag:"gossip-protocol"`
-	GossipListen   *string `json:"gossip-listen" flag:"gossip-listen"`
-	GossipSecret   *string `json:"gossip-secret" flag:"gossip-secret"`
+	ClusterID *string `json:"cluster-id,omitempty" flag:"cluster-id"`
+
+	GossipProtocol *string  `json:"gossip-protocol" flag:"gossip-protocol"`
+	GossipListen   *string  `json:"gossip-listen" flag:"gossip-listen"`
+	GossipSecret   *string  `json:"gossip-secret" flag:"gossip-secret"`
+	GossipSeed     []string `json:"gossip-seed,omitempty" flag:"gossip-seed"`
 
 	GossipProtocolSecondary *string `json:"gossip-protocol-secondary" flag:"gossip-protocol-secondary" flag-include-empty:"true"`
 	GossipListenSecondary   *string `json:"gossip-listen-secondary" flag:"gossip-listen-secondary"`
@@ -246,7 +331,7 @@ func (t *ProtokubeBuilder) ProtokubeFlags(k8sVersion semver.Version) (*Protokube
 	}
 
 	f.ManageEtcd = false
-	if len(t.NodeupConfig.EtcdManifests) == 0 {
+	if len(t.NodeupConfig.EtcdManifests) == 0 && !t.UseExternalEtcd() {
 		klog.V(4).Infof("no EtcdManifests; protokube will manage etcd")
 		f.ManageEtcd = true
 	}
@@ -330,6 +415,11 @@ func (t *ProtokubeBuilder) ProtokubeFlags(k8sVersion semver.Version) (*Protokube
 				f.GossipSecretSecondary = t.Cluster.Spec.GossipConfig.Secondary.Secret
 			}
 		}
+		if model.UseKopsControllerForGossipDiscovery(t.Cluster) {
+			// The records are read from kops-controller, so there is no mesh to join
+			f.GossipProtocolSecondary = fi.String("")
+			f.DiscoveryCA = fi.String(filepath.Join(t.PathSrvKubernetes(), "ca.crt"))
+		}
 
 		// @TODO: This is hacky, but we want it so that we can have a different internal & external name
 		internalSuffix := t.Cluster.Spec.MasterInternalName
@@ -340,6 +430,12 @@ func (t *ProtokubeBuilder) ProtokubeFlags(k8sVersion semver.Version) (*Protokube
 	if t.Cluster.Spec.CloudProvider != "" {
 		f.Cloud = fi.String(t.Cluster.Spec.CloudProvider)
 
+		if kops.CloudProviderID(t.Cluster.Spec.CloudProvider) == kops.CloudProviderMetal {
+			// Metal machines have no tags or metadata, so seeds and cluster identity come from the config
+			f.ClusterID = fi.String(t.Cluster.ObjectMeta.Name)
+			f.GossipSeed = t.NodeupConfig.GossipSeeds
+		}
+
 		if f.DNSProvider == nil {
 			switch kops.CloudProviderID(t.Cluster.Spec.CloudProvider) {
 			case kops.CloudProviderAWS:
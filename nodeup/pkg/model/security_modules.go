@@ -0,0 +1,179 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/systemd"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+	"k8s.io/kops/util/pkg/distributions"
+)
+
+const (
+	// selinuxService loads the kOps SELinux policy module, enforces SELinux and relabels the directories of the
+	// kubelet and the control plane, before the kubelet starts
+	selinuxService = "kops-selinux.service"
+	// selinuxPolicyModulePath is the path of the kOps SELinux policy module
+	selinuxPolicyModulePath = "/var/lib/kops/selinux/kops.cil"
+)
+
+// selinuxLabels are the file contexts of the directories written by nodeup and shared with the containers
+var selinuxLabels = []struct {
+	Path string
+	Type string
+}{
+	{Path: "/etc/kubernetes", Type: "container_file_t"},
+	{Path: "/srv/kubernetes", Type: "container_file_t"},
+	{Path: "/var/lib/kube-controller-manager", Type: "container_file_t"},
+	{Path: "/var/lib/kube-proxy", Type: "container_file_t"},
+	{Path: "/var/lib/kube-scheduler", Type: "container_file_t"},
+	{Path: "/var/lib/kubelet", Type: "container_var_lib_t"},
+	{Path: "/var/lib/kubelet/pods", Type: "container_file_t"},
+	{Path: "/var/log/containers", Type: "container_log_t"},
+	{Path: "/var/log/pods", Type: "container_log_t"},
+}
+
+// appArmorAddonProfile confines the kOps managed addons; it is based on the default profile of containerd,
+// without the capabilities the addons do not need
+const appArmorAddonProfile = `#include <tunables/global>
+
+profile kops-addon flags=(attach_disconnected,mediate_deleted) {
+  #include <abstractions/base>
+
+  network,
+  capability,
+  file,
+  umount,
+
+  deny capability sys_admin,
+  deny capability sys_module,
+  deny capability sys_ptrace,
+  deny capability sys_rawio,
+  deny mount,
+  deny ptrace,
+
+  deny @{PROC}/* w,
+  deny @{PROC}/{[^1-9],[^1-9][^0-9],[^1-9s][^0-9y][^0-9s],[^1-9][^0-9][^0-9][^0-9]*}/** w,
+  deny @{PROC}/sys/[^k]** w,
+  deny @{PROC}/sys/kernel/{?,??,[^s][^h][^m]**} w,
+  deny @{PROC}/sysrq-trigger rwklx,
+  deny @{PROC}/kcore rwklx,
+
+  deny /sys/[^f]*/** wklx,
+  deny /sys/f[^s]*/** wklx,
+  deny /sys/fs/[^c]*/** wklx,
+  deny /sys/fs/c[^g]*/** wklx,
+  deny /sys/fs/cg[^r]*/** wklx,
+  deny /sys/firmware/** rwklx,
+  deny /sys/kernel/security/** rwklx,
+}
+`
+
+// SecurityModulesBuilder enforces SELinux or loads the AppArmor profiles of the addons on the instances
+type SecurityModulesBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &SecurityModulesBuilder{}
+
+// Build is responsible for configuring the Linux security modules
+func (b *SecurityModulesBuilder) Build(c *fi.ModelBuilderContext) error {
+	securityModules := b.NodeupConfig.SecurityModules
+	if securityModules == nil {
+		return nil
+	}
+
+	if fi.BoolValue(securityModules.SELinux) {
+		if !b.Distribution.IsRHELFamily() || b.Distribution == distributions.DistributionAmazonLinux2 {
+			return fmt.Errorf("SELinux is only supported on RHEL and CentOS, not on %v", b.Distribution)
+		}
+
+		c.AddTask(&nodetasks.File{
+			Path:     "/etc/selinux/config",
+			Contents: fi.NewStringResource("SELINUX=enforcing\nSELINUXTYPE=targeted\n"),
+			Type:     nodetasks.FileType_File,
+		})
+		c.AddTask(&nodetasks.File{
+			Path:     selinuxPolicyModulePath,
+			Contents: fi.NewStringResource(buildSELinuxPolicyModule()),
+			Type:     nodetasks.FileType_File,
+		})
+		c.AddTask(b.buildSELinuxService())
+	}
+
+	if fi.BoolValue(securityModules.AppArmor) {
+		if !b.Distribution.IsDebianFamily() {
+			return fmt.Errorf("AppArmor is only supported on Debian and Ubuntu, not on %v", b.Distribution)
+		}
+
+		profilePath := "/etc/apparmor.d/" + kops.AppArmorAddonProfile
+		c.AddTask(&nodetasks.File{
+			Path:            profilePath,
+			Contents:        fi.NewStringResource(appArmorAddonProfile),
+			Type:            nodetasks.FileType_File,
+			OnChangeExecute: [][]string{{"apparmor_parser", "--replace", profilePath}},
+		})
+	}
+
+	return nil
+}
+
+// buildSELinuxPolicyModule builds the CIL policy module labelling the directories shared with the containers
+func buildSELinuxPolicyModule() string {
+	var sb strings.Builder
+	for _, label := range selinuxLabels {
+		sb.WriteString(fmt.Sprintf("(filecon \"%s(/.*)?\" any (system_u object_r %s ((s0) (s0))))\n", label.Path, label.Type))
+	}
+	return sb.String()
+}
+
+func (b *SecurityModulesBuilder) buildSELinuxService() *nodetasks.Service {
+	var paths []string
+	for _, label := range selinuxLabels {
+		paths = append(paths, label.Path)
+	}
+
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Enforce SELinux and label the directories of the kubelet and the control plane")
+	manifest.Set("Unit", "Documentation", "https://kops.sigs.k8s.io")
+	manifest.Set("Unit", "Before", "containerd.service kubelet.service")
+
+	manifest.Set("Service", "Type", "oneshot")
+	manifest.Set("Service", "RemainAfterExit", "yes")
+	manifest.Set("Service", "ExecStartPre", "/usr/sbin/selinuxenabled")
+	manifest.Set("Service", "ExecStartPre", "/usr/sbin/semodule --install "+selinuxPolicyModulePath)
+	manifest.Set("Service", "ExecStartPre", "/usr/sbin/setenforce 1")
+	manifest.Set("Service", "ExecStart", "/usr/sbin/restorecon -R -i "+strings.Join(paths, " "))
+
+	manifest.Set("Install", "WantedBy", "multi-user.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", selinuxService, manifestString)
+
+	service := &nodetasks.Service{
+		Name:       selinuxService,
+		Definition: s(manifestString),
+	}
+	service.InitDefaults()
+
+	return service
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/distributions"
+)
+
+func TestSecurityModulesBuilder(t *testing.T) {
+	grid := []struct {
+		Distribution    distributions.Distribution
+		SecurityModules kops.SecurityModulesSpec
+		ExpectedTasks   []string
+		ExpectErr       bool
+	}{
+		{
+			Distribution:    distributions.DistributionCentos8,
+			SecurityModules: kops.SecurityModulesSpec{SELinux: fi.Bool(true)},
+			ExpectedTasks:   []string{"File//etc/selinux/config", "File/" + selinuxPolicyModulePath, "Service/" + selinuxService},
+		},
+		{
+			Distribution:    distributions.DistributionUbuntu2004,
+			SecurityModules: kops.SecurityModulesSpec{AppArmor: fi.Bool(true)},
+			ExpectedTasks:   []string{"File//etc/apparmor.d/kops-addon"},
+		},
+		{
+			Distribution:    distributions.DistributionUbuntu2004,
+			SecurityModules: kops.SecurityModulesSpec{SELinux: fi.Bool(true)},
+			ExpectErr:       true,
+		},
+		{
+			Distribution:    distributions.DistributionAmazonLinux2,
+			SecurityModules: kops.SecurityModulesSpec{SELinux: fi.Bool(true)},
+			ExpectErr:       true,
+		},
+		{
+			Distribution:    distributions.DistributionCentos8,
+			SecurityModules: kops.SecurityModulesSpec{AppArmor: fi.Bool(true)},
+			ExpectErr:       true,
+		},
+	}
+
+	for _, g := range grid {
+		b := &SecurityModulesBuilder{
+			NodeupModelContext: &NodeupModelContext{
+				Distribution: g.Distribution,
+				NodeupConfig: &nodeup.Config{SecurityModules: &g.SecurityModules},
+			},
+		}
+		c := &fi.ModelBuilderContext{Tasks: make(map[string]fi.Task)}
+		err := b.Build(c)
+		if g.ExpectErr {
+			if err == nil {
+				t.Errorf("expected error for %+v on %v", g.SecurityModules, g.Distribution)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(c.Tasks) != len(g.ExpectedTasks) {
+			t.Errorf("expected tasks %v, got %d tasks", g.ExpectedTasks, len(c.Tasks))
+		}
+		for _, name := range g.ExpectedTasks {
+			if c.Tasks[name] == nil {
+				t.Errorf("expected task %q", name)
+			}
+		}
+	}
+}
+
+func TestSELinuxPolicyModule(t *testing.T) {
+	policy := buildSELinuxPolicyModule()
+	expected := `(filecon "/srv/kubernetes(/.*)?" any (system_u object_r container_file_t ((s0) (s0))))`
+	if !strings.Contains(policy, expected+"\n") {
+		t.Errorf("expected %q in policy module:\n%s", expected, policy)
+	}
+}
+
+func TestKubeletSELinuxDependency(t *testing.T) {
+	cluster := &kops.Cluster{}
+	cluster.Spec.ContainerRuntime = "containerd"
+
+	b := &KubeletBuilder{
+		NodeupModelContext: &NodeupModelContext{
+			Cluster:      cluster,
+			NodeupConfig: &nodeup.Config{SecurityModules: &kops.SecurityModulesSpec{SELinux: fi.Bool(true)}},
+		},
+	}
+	service := b.buildSystemdService()
+	if !strings.Contains(fi.StringValue(service.Definition), "Requires="+selinuxService) {
+		t.Errorf("expected the kubelet to require %s:\n%s", selinuxService, fi.StringValue(service.Definition))
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/systemd"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+const (
+	// swapFilePath is the path of the file backing the swap space
+	swapFilePath = "/var/swapfile"
+	// swapDeviceName is the name of the dm-crypt device used for encrypted swap
+	swapDeviceName = "kopsswap"
+	// swapFileService is the unit preparing the swap space
+	swapFileService = "kops-swapfile.service"
+)
+
+// SwapBuilder provisions the swap space of the instances
+type SwapBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &SwapBuilder{}
+
+// Build is responsible for creating the swap file and the systemd units activating it
+func (b *SwapBuilder) Build(c *fi.ModelBuilderContext) error {
+	swap := b.NodeupConfig.Swap
+	if swap == nil {
+		return nil
+	}
+
+	if swap.Size == nil || swap.Size.Value() <= 0 {
+		return fmt.Errorf("swap.size must be set to a positive size")
+	}
+
+	encrypted := fi.BoolValue(swap.Encrypted)
+	if encrypted && (b.Distribution.IsDebianFamily() || b.Distribution.IsRHELFamily()) {
+		c.AddTask(&nodetasks.Package{Name: "cryptsetup"})
+	}
+
+	c.AddTask(b.buildSwapFileService(swap.Size.Value(), encrypted))
+	c.AddTask(b.buildSwapUnit(encrypted))
+
+	return nil
+}
+
+// swapDevicePath returns the path of the device activated as swap
+func swapDevicePath(encrypted bool) string {
+	if encrypted {
+		return "/dev/mapper/" + swapDeviceName
+	}
+	return swapFilePath
+}
+
+// swapUnitName returns the name of the systemd swap unit, which is derived from the path of the device
+func swapUnitName(encrypted bool) string {
+	if encrypted {
+		return "dev-mapper-" + swapDeviceName + ".swap"
+	}
+	return "var-swapfile.swap"
+}
+
+// buildSwapFileService builds the unit that allocates the swap file, once, and formats it.
+// Encrypted swap is keyed with a random key, so it is opened and formatted again at each boot.
+func (b *SwapBuilder) buildSwapFileService(size int64, encrypted bool) *nodetasks.Service {
+	allocate := fmt.Sprintf("fallocate -l %d %s && chmod 0600 %s", size, swapFilePath, swapFilePath)
+	if !encrypted {
+		allocate += " && mkswap " + swapFilePath
+	}
+
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Prepare the kops swap space")
+	manifest.Set("Unit", "DefaultDependencies", "no")
+	manifest.Set("Unit", "Before", swapUnitName(encrypted))
+
+	manifest.Set("Service", "Type", "oneshot")
+	manifest.Set("Service", "RemainAfterExit", "yes")
+	manifest.Set("Service", "ExecStart", fmt.Sprintf("/bin/bash -c '[ -f %s ] || (%s)'", swapFilePath, allocate))
+	if encrypted {
+		manifest.Set("Service", "ExecStart", fmt.Sprintf("/bin/bash -c 'cryptsetup open --type plain --cipher aes-xts-plain64 --key-size 512 --key-file /dev/urandom %s %s && mkswap %s'",
+			swapFilePath, swapDeviceName, swapDevicePath(encrypted)))
+		manifest.Set("Service", "ExecStop", "/bin/bash -c 'cryptsetup close "+swapDeviceName+"'")
+	}
+
+	manifest.Set("Install", "WantedBy", "swap.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", swapFileService, manifestString)
+
+	service := &nodetasks.Service{
+		Name:       swapFileService,
+		Definition: s(manifestString),
+	}
+	service.InitDefaults()
+
+	return service
+}
+
+// buildSwapUnit builds the swap unit activating the swap space, the systemd equivalent of an fstab entry
+func (b *SwapBuilder) buildSwapUnit(encrypted bool) *nodetasks.Service {
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "kops swap space")
+	manifest.Set("Unit", "Requires", swapFileService)
+	manifest.Set("Unit", "After", swapFileService)
+
+	manifest.Set("Swap", "What", swapDevicePath(encrypted))
+
+	manifest.Set("Install", "WantedBy", "swap.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built swap manifest %q\n%s", swapUnitName(encrypted), manifestString)
+
+	service := &nodetasks.Service{
+		Name:       swapUnitName(encrypted),
+		Definition: s(manifestString),
+	}
+	service.InitDefaults()
+
+	return service
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+func TestSwapBuilder(t *testing.T) {
+	size := resource.MustParse("1Gi")
+
+	grid := []struct {
+		Encrypted bool
+		Unit      string
+		Expected  []string
+	}{
+		{
+			Unit: "var-swapfile.swap",
+			Expected: []string{
+				"ExecStart=/bin/bash -c '[ -f /var/swapfile ] || (fallocate -l 1073741824 /var/swapfile && chmod 0600 /var/swapfile && mkswap /var/swapfile)'",
+				"What=/var/swapfile",
+			},
+		},
+		{
+			Encrypted: true,
+			Unit:      "dev-mapper-kopsswap.swap",
+			Expected: []string{
+				"ExecStart=/bin/bash -c '[ -f /var/swapfile ] || (fallocate -l 1073741824 /var/swapfile && chmod 0600 /var/swapfile)'",
+				"--key-file /dev/urandom /var/swapfile kopsswap && mkswap /dev/mapper/kopsswap'",
+				"What=/dev/mapper/kopsswap",
+			},
+		},
+	}
+
+	for _, g := range grid {
+		b := &SwapBuilder{
+			NodeupModelContext: &NodeupModelContext{
+				Cluster: &kops.Cluster{},
+				NodeupConfig: &nodeup.Config{
+					Swap: &kops.SwapSpec{Size: &size, Encrypted: fi.Bool(g.Encrypted)},
+				},
+			},
+		}
+
+		c := &fi.ModelBuilderContext{Tasks: make(map[string]fi.Task)}
+		if err := b.Build(c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var definitions []string
+		for _, name := range []string{"Service/" + swapFileService, "Service/" + g.Unit} {
+			task, ok := c.Tasks[name].(*nodetasks.Service)
+			if !ok {
+				t.Fatalf("task %q not found in %v", name, c.Tasks)
+			}
+			definitions = append(definitions, fi.StringValue(task.Definition))
+		}
+
+		for _, expected := range g.Expected {
+			if !strings.Contains(strings.Join(definitions, "\n"), expected) {
+				t.Errorf("expected %q in units:\n%s", expected, strings.Join(definitions, "\n"))
+			}
+		}
+	}
+}
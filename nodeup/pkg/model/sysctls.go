@@ -18,6 +18,7 @@ package model
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"k8s.io/kops/pkg/apis/kops"
@@ -149,6 +150,27 @@ func (b *SysctlBuilder) Build(c *fi.ModelBuilderContext) error {
 		"net.ipv4.ip_forward=1",
 		"")
 
+	// The kubelet refuses to start when it protects the kernel defaults and they differ from its expectations
+	if fi.BoolValue(b.NodeupConfig.KubeletConfig.ProtectKernelDefaults) {
+		sysctls = append(sysctls,
+			"# Kernel settings expected by the kubelet with protectKernelDefaults",
+			"vm.overcommit_memory=1",
+			"vm.panic_on_oom=0",
+			"kernel.panic=10",
+			"kernel.panic_on_oops=1",
+			"kernel.keys.root_maxkeys=1000000",
+			"kernel.keys.root_maxbytes=25000000",
+			"")
+	}
+
+	if swap := b.NodeupConfig.Swap; swap != nil && swap.Swappiness != nil {
+		sysctls = append(sysctls,
+			"# Swappiness from instance group swap spec",
+			"",
+			fmt.Sprintf("vm.swappiness=%d", *swap.Swappiness),
+			"")
+	}
+
 	if params := b.NodeupConfig.SysctlParameters; len(params) > 0 {
 		sysctls = append(sysctls,
 			"# Custom sysctl parameters from instance group spec",
@@ -173,6 +195,20 @@ func (b *SysctlBuilder) Build(c *fi.ModelBuilderContext) error {
 		}
 	}
 
+	if nodeTuning := b.NodeupConfig.NodeTuning; nodeTuning != nil && len(nodeTuning.Sysctls) > 0 {
+		sysctls = append(sysctls,
+			"# Sysctls from node tuning spec",
+			"")
+		var keys []string
+		for k := range nodeTuning.Sysctls {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			sysctls = append(sysctls, k+"="+nodeTuning.Sysctls[k])
+		}
+	}
+
 	c.AddTask(&nodetasks.File{
 		Path:            "/etc/sysctl.d/99-k8s-general.conf",
 		Contents:        fi.NewStringResource(strings.Join(sysctls, "\n")),
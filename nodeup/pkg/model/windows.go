@@ -0,0 +1,342 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/flagbuilder"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+const (
+	// windowsKubernetesDir holds the Kubernetes binaries, kubeconfigs and scripts on Windows nodes
+	windowsKubernetesDir = `C:\k`
+	// windowsPKIDir holds the certificates of the kubelet on Windows nodes
+	windowsPKIDir = windowsKubernetesDir + `\pki`
+	// windowsCNIBinDir holds the CNI plugins on Windows nodes
+	windowsCNIBinDir = windowsKubernetesDir + `\cni`
+	// windowsCNIConfDir holds the CNI configuration on Windows nodes
+	windowsCNIConfDir = windowsCNIBinDir + `\config`
+	// windowsContainerdDir holds the containerd binaries and configuration on Windows nodes
+	windowsContainerdDir = `C:\Program Files\containerd`
+	// windowsCalicoDir is where the Calico for Windows release is extracted
+	windowsCalicoDir = `C:\CalicoWindows`
+	// windowsNetworkingScript installs Calico and kube-proxy once the kubelet is running
+	windowsNetworkingScript = windowsKubernetesDir + `\install-networking.ps1`
+	// windowsContainerdEndpoint is the named pipe containerd listens on
+	windowsContainerdEndpoint = "npipe:////./pipe/containerd-containerd"
+	// windowsPauseImage is the sandbox image, which must support Windows
+	windowsPauseImage = "k8s.gcr.io/pause:3.5"
+)
+
+// WindowsBuilder installs containerd, the kubelet, Calico and kube-proxy on Windows nodes
+type WindowsBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &WindowsBuilder{}
+
+// Build is responsible for configuring a Windows node
+func (b *WindowsBuilder) Build(c *fi.ModelBuilderContext) error {
+	if !b.Distribution.IsWindows() {
+		return nil
+	}
+
+	if b.Cluster.Spec.Networking == nil || b.Cluster.Spec.Networking.Calico == nil {
+		return fmt.Errorf("windows nodes require calico networking")
+	}
+
+	nodeName, err := b.NodeName()
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range []string{windowsKubernetesDir, windowsPKIDir, windowsCNIBinDir, windowsCNIConfDir, windowsContainerdDir} {
+		c.EnsureTask(&nodetasks.File{
+			Path: dir,
+			Type: nodetasks.FileType_Directory,
+		})
+	}
+
+	ca, err := b.GetCert(fi.CertificateIDCA)
+	if err != nil {
+		return err
+	}
+	c.AddTask(&nodetasks.File{
+		Path:     windowsPKIDir + `\ca.crt`,
+		Contents: fi.NewBytesResource(ca),
+		Type:     nodetasks.FileType_File,
+	})
+
+	if err := b.buildContainerd(c); err != nil {
+		return err
+	}
+
+	for _, name := range []string{"kubelet", "kube-proxy", "calico-windows"} {
+		kubeconfig, err := b.BuildBootstrapKubeconfig(name, c)
+		if err != nil {
+			return err
+		}
+		c.AddTask(&nodetasks.File{
+			Path:     windowsKubernetesDir + `\` + name + ".kubeconfig",
+			Contents: kubeconfig,
+			Type:     nodetasks.FileType_File,
+		})
+	}
+
+	cert, key := b.GetBootstrapCert("kubelet-server")
+	c.AddTask(&nodetasks.File{
+		Path:     windowsPKIDir + `\kubelet-server.crt`,
+		Contents: cert,
+		Type:     nodetasks.FileType_File,
+	})
+	c.AddTask(&nodetasks.File{
+		Path:     windowsPKIDir + `\kubelet-server.key`,
+		Contents: key,
+		Type:     nodetasks.FileType_File,
+	})
+
+	for _, name := range []string{"kubelet.exe", "kube-proxy.exe"} {
+		if err := b.addAsset(c, name, windowsKubernetesDir); err != nil {
+			return err
+		}
+	}
+
+	kubeletConfig, err := (&KubeletBuilder{NodeupModelContext: b.NodeupModelContext}).buildKubeletConfigSpec()
+	if err != nil {
+		return err
+	}
+
+	kubeletCommand, err := b.buildKubeletCommand(kubeletConfig, nodeName)
+	if err != nil {
+		return err
+	}
+	c.AddTask(&nodetasks.WindowsService{
+		Name:       "kubelet",
+		BinaryPath: kubeletCommand,
+		DependsOn:  []string{"containerd"},
+	})
+
+	_, calico, err := b.Assets.FindMatch(regexp.MustCompile(`calico-windows.*\.zip$`))
+	if err != nil {
+		return err
+	}
+	c.AddTask(&nodetasks.File{
+		Path:     windowsKubernetesDir + `\calico-windows.zip`,
+		Contents: calico,
+		Type:     nodetasks.FileType_File,
+	})
+
+	script, err := b.buildNetworkingScript(kubeletConfig, nodeName)
+	if err != nil {
+		return err
+	}
+	c.AddTask(&nodetasks.File{
+		Path:       windowsNetworkingScript,
+		Contents:   fi.NewStringResource(script),
+		Type:       nodetasks.FileType_File,
+		AfterFiles: []string{windowsKubernetesDir + `\calico-windows.zip`, windowsKubernetesDir + `\calico-windows.kubeconfig`},
+		// The script waits for the kubelet to register the node, so it must not block nodeup
+		OnChangeExecute: [][]string{{
+			"powershell.exe", "-NoProfile", "-Command",
+			"Start-Process -WindowStyle Hidden -FilePath powershell.exe -ArgumentList '-NoProfile','-ExecutionPolicy','Bypass','-File','" + windowsNetworkingScript + "'",
+		}},
+	})
+
+	return nil
+}
+
+// buildContainerd installs containerd from the Windows release archive and registers its service
+func (b *WindowsBuilder) buildContainerd(c *fi.ModelBuilderContext) error {
+	for _, name := range []string{"containerd.exe", "containerd-shim-runhcs-v1.exe", "ctr.exe"} {
+		if err := b.addAsset(c, name, windowsContainerdDir); err != nil {
+			return err
+		}
+	}
+
+	config := strings.Join([]string{
+		`version = 2`,
+		``,
+		`[plugins."io.containerd.grpc.v1.cri"]`,
+		`  sandbox_image = "` + windowsPauseImage + `"`,
+		`  [plugins."io.containerd.grpc.v1.cri".cni]`,
+		`    bin_dir = "` + strings.ReplaceAll(windowsCNIBinDir, `\`, `/`) + `"`,
+		`    conf_dir = "` + strings.ReplaceAll(windowsCNIConfDir, `\`, `/`) + `"`,
+		``,
+	}, "\n")
+	c.AddTask(&nodetasks.File{
+		Path:     windowsContainerdDir + `\config.toml`,
+		Contents: fi.NewStringResource(config),
+		Type:     nodetasks.FileType_File,
+	})
+
+	c.AddTask(&nodetasks.WindowsService{
+		Name:       "containerd",
+		BinaryPath: `"` + windowsContainerdDir + `\containerd.exe" --run-service --config "` + windowsContainerdDir + `\config.toml"`,
+	})
+
+	return nil
+}
+
+// addAsset copies the asset with the given file name to dir
+func (b *WindowsBuilder) addAsset(c *fi.ModelBuilderContext, name string, dir string) error {
+	asset, err := b.Assets.Find(name, "")
+	if err != nil {
+		return fmt.Errorf("error trying to locate asset %q: %v", name, err)
+	}
+	if asset == nil {
+		return fmt.Errorf("unable to locate asset %q", name)
+	}
+
+	c.AddTask(&nodetasks.File{
+		Path:     dir + `\` + name,
+		Contents: asset,
+		Type:     nodetasks.FileType_File,
+	})
+	return nil
+}
+
+// buildKubeletCommand returns the command line of the kubelet service, replacing the Linux specific settings
+func (b *WindowsBuilder) buildKubeletCommand(kubeletConfig *kops.KubeletConfigSpec, nodeName string) (string, error) {
+	c := *kubeletConfig
+
+	c.ClientCAFile = windowsPKIDir + `\ca.crt`
+	c.KubeconfigPath = windowsKubernetesDir + `\kubelet.kubeconfig`
+	c.BootstrapKubeconfig = ""
+	c.TLSCertFile = windowsPKIDir + `\kubelet-server.crt`
+	c.TLSPrivateKeyFile = windowsPKIDir + `\kubelet-server.key`
+	c.HostnameOverride = nodeName
+	c.PodManifestPath = ""
+	c.VolumePluginDirectory = ""
+	c.ResolverConfig = fi.String("")
+	c.CgroupRoot = ""
+	c.CgroupDriver = ""
+	c.KubeletCgroups = ""
+	c.RuntimeCgroups = ""
+	c.SystemCgroups = ""
+	c.KubeReservedCgroup = ""
+	c.SystemReservedCgroup = ""
+	c.EnforceNodeAllocatable = ""
+	c.ProtectKernelDefaults = nil
+
+	flags, err := flagbuilder.BuildFlags(&c)
+	if err != nil {
+		return "", fmt.Errorf("error building kubelet flags: %v", err)
+	}
+
+	flags += " --windows-service"
+	flags += " --cgroups-per-qos=false"
+	flags += " --enforce-node-allocatable="
+	flags += " --container-runtime=remote"
+	flags += " --runtime-request-timeout=15m"
+	flags += " --container-runtime-endpoint=" + windowsContainerdEndpoint
+
+	return windowsKubernetesDir + `\kubelet.exe ` + flags, nil
+}
+
+// buildKubeProxyCommand returns the command line of the kube-proxy service.
+// The source VIP of the overlay network is only known once Calico is running, so it refers to the $sourceVip variable of the networking script.
+func (b *WindowsBuilder) buildKubeProxyCommand(nodeName string) (string, error) {
+	c := kops.KubeProxyConfig{
+		HostnameOverride: nodeName,
+		ProxyMode:        "kernelspace",
+		FeatureGates:     map[string]string{"WinOverlay": "true"},
+	}
+	if kubeProxy := b.Cluster.Spec.KubeProxy; kubeProxy != nil {
+		c.LogLevel = kubeProxy.LogLevel
+		c.ClusterCIDR = kubeProxy.ClusterCIDR
+		for k, v := range kubeProxy.FeatureGates {
+			c.FeatureGates[k] = v
+		}
+	}
+
+	flags, err := flagbuilder.BuildFlags(&c)
+	if err != nil {
+		return "", fmt.Errorf("error building kube-proxy flags: %v", err)
+	}
+
+	flags += " --windows-service"
+	flags += " --kubeconfig=" + windowsKubernetesDir + `\kube-proxy.kubeconfig`
+	flags += " --network-name=Calico"
+	flags += " --source-vip=$sourceVip"
+
+	return windowsKubernetesDir + `\kube-proxy.exe ` + flags, nil
+}
+
+// buildNetworkingScript returns the PowerShell script installing Calico for Windows and kube-proxy.
+// Calico only creates its network once the kubelet has registered the node, so the script runs in the background.
+func (b *WindowsBuilder) buildNetworkingScript(kubeletConfig *kops.KubeletConfigSpec, nodeName string) (string, error) {
+	kubeProxyCommand, err := b.buildKubeProxyCommand(nodeName)
+	if err != nil {
+		return "", err
+	}
+
+	// Settings appended to config.ps1 take precedence over the defaults of the release
+	calicoConfig := []string{
+		`$env:KUBE_NETWORK = "Calico.*"`,
+		`$env:CALICO_NETWORKING_BACKEND = "vxlan"`,
+		`$env:CALICO_DATASTORE_TYPE = "kubernetes"`,
+		`$env:KUBECONFIG = "` + windowsKubernetesDir + `\calico-windows.kubeconfig"`,
+		`$env:K8S_SERVICE_CIDR = "` + b.Cluster.Spec.ServiceClusterIPRange + `"`,
+		`$env:DNS_NAME_SERVERS = "` + kubeletConfig.ClusterDNS + `"`,
+		`$env:DNS_SEARCH = "svc.` + b.Cluster.Spec.ClusterDNSDomain + `"`,
+		`$env:CNI_BIN_DIR = "` + windowsCNIBinDir + `"`,
+		`$env:CNI_CONF_DIR = "` + windowsCNIConfDir + `"`,
+		`$env:NODENAME = "` + nodeName + `"`,
+		`$env:CALICO_K8S_NODE_REF = "` + nodeName + `"`,
+	}
+
+	lines := []string{
+		`$ErrorActionPreference = "Stop"`,
+		`Start-Transcript -Append -Path "` + windowsKubernetesDir + `\install-networking.log"`,
+		``,
+		`while ((Get-Service -Name kubelet -ErrorAction SilentlyContinue).Status -ne "Running") {`,
+		`  Start-Sleep -Seconds 5`,
+		`}`,
+		``,
+		`Expand-Archive -Force -Path "` + windowsKubernetesDir + `\calico-windows.zip" -DestinationPath "C:\"`,
+		`Add-Content -Path "` + windowsCalicoDir + `\config.ps1" -Value @'`,
+	}
+	lines = append(lines, calicoConfig...)
+	lines = append(lines,
+		`'@`,
+		`& "`+windowsCalicoDir+`\install-calico.ps1"`,
+		``,
+		`Import-Module "`+windowsCalicoDir+`\libs\hns\hns.psm1"`,
+		`while (-not ($endpoint = Get-HnsEndpoint | Where-Object Name -eq "Calico_ep")) {`,
+		`  Start-Sleep -Seconds 5`,
+		`}`,
+		`$sourceVip = $endpoint.IPAddress`,
+		``,
+		`$binaryPath = "`+kubeProxyCommand+`"`,
+		`if (Get-Service -Name kube-proxy -ErrorAction SilentlyContinue) {`,
+		`  sc.exe config kube-proxy binPath= $binaryPath`,
+		`} else {`,
+		`  New-Service -Name kube-proxy -BinaryPathName $binaryPath -StartupType Automatic -DependsOn kubelet | Out-Null`,
+		`}`,
+		`sc.exe failure kube-proxy reset= 0 actions= restart/10000`,
+		`Restart-Service -Name kube-proxy`,
+		``,
+	)
+
+	return strings.Join(lines, "\r\n"), nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestWindowsKubeletCommand(t *testing.T) {
+	b := &WindowsBuilder{
+		NodeupModelContext: &NodeupModelContext{
+			Cluster: &kops.Cluster{},
+		},
+	}
+
+	kubeletConfig := &kops.KubeletConfigSpec{
+		ClientCAFile:        "/srv/kubernetes/ca.crt",
+		KubeconfigPath:      "/var/lib/kubelet/kubeconfig",
+		BootstrapKubeconfig: "/var/lib/kubelet/bootstrap-kubeconfig",
+		PodManifestPath:     "/etc/kubernetes/manifests",
+		CgroupRoot:          "/",
+		CgroupDriver:        "systemd",
+		ClusterDNS:          "100.64.0.10",
+		CloudProvider:       "aws",
+		LogLevel:            fi.Int32(2),
+	}
+
+	actual, err := b.buildKubeletCommand(kubeletConfig, "ip-172-20-32-10.ec2.internal")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `C:\k\kubelet.exe --client-ca-file=C:\k\pki\ca.crt --cloud-provider=aws --cluster-dns=100.64.0.10 --hostname-override=ip-172-20-32-10.ec2.internal` +
+		` --kubeconfig=C:\k\kubelet.kubeconfig --resolv-conf= --tls-cert-file=C:\k\pki\kubelet-server.crt --tls-private-key-file=C:\k\pki\kubelet-server.key --v=2` +
+		` --windows-service --cgroups-per-qos=false --enforce-node-allocatable= --container-runtime=remote --runtime-request-timeout=15m` +
+		` --container-runtime-endpoint=npipe:////./pipe/containerd-containerd`
+	if actual != expected {
+		t.Errorf("unexpected kubelet command; expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+func TestWindowsNetworkingScript(t *testing.T) {
+	cluster := &kops.Cluster{}
+	cluster.Spec.ServiceClusterIPRange = "100.64.0.0/13"
+	cluster.Spec.ClusterDNSDomain = "cluster.local"
+	cluster.Spec.KubeProxy = &kops.KubeProxyConfig{
+		LogLevel:    2,
+		ClusterCIDR: "100.96.0.0/11",
+	}
+
+	b := &WindowsBuilder{
+		NodeupModelContext: &NodeupModelContext{
+			Cluster: cluster,
+		},
+	}
+
+	script, err := b.buildNetworkingScript(&kops.KubeletConfigSpec{ClusterDNS: "100.64.0.10"}, "ip-172-20-32-10.ec2.internal")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, expected := range []string{
+		`$env:K8S_SERVICE_CIDR = "100.64.0.0/13"`,
+		`$env:DNS_NAME_SERVERS = "100.64.0.10"`,
+		`$env:DNS_SEARCH = "svc.cluster.local"`,
+		`$env:NODENAME = "ip-172-20-32-10.ec2.internal"`,
+		`$binaryPath = "C:\k\kube-proxy.exe --cluster-cidr=100.96.0.0/11 --feature-gates=WinOverlay=true --hostname-override=ip-172-20-32-10.ec2.internal` +
+			` --proxy-mode=kernelspace --v=2 --windows-service --kubeconfig=C:\k\kube-proxy.kubeconfig --network-name=Calico --source-vip=$sourceVip"`,
+	} {
+		if !strings.Contains(script, expected+"\r\n") {
+			t.Errorf("expected networking script to contain %q; got:\n%s", expected, script)
+		}
+	}
+}
@@ -32,6 +32,9 @@ func GetACL(p vfs.Path, cluster *kops.Cluster) (vfs.ACL, error) {
 	strategiesMutex.Lock()
 	defer strategiesMutex.Unlock()
 
+	// The ACL is that of the underlying storage
+	p = vfs.Unwrap(p)
+
 	for k, strategy := range strategies {
 		acl, err := strategy.GetACL(p, cluster)
 		if err != nil {
@@ -0,0 +1,162 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addonchannel
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/kops/channels/pkg/api"
+	"k8s.io/kops/upup/pkg/fi/utils"
+)
+
+// ChannelFile is the name of the file listing the addons of a channel
+const ChannelFile = "addons.yaml"
+
+// Channel is an addons channel being authored in a local directory
+type Channel struct {
+	// Dir is the directory of the channel
+	Dir string
+	// Addons is the parsed addons.yaml of the channel
+	Addons *api.Addons
+	// Manifests are the manifests of the channel, by their path relative to the channel.
+	// A nil value means the manifest could not be found.
+	Manifests map[string][]byte
+
+	// raw is the addons.yaml as read
+	raw []byte
+}
+
+// Load reads a channel from its addons.yaml file, or from the directory containing it
+func Load(p string) (*Channel, error) {
+	stat, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+	if stat.IsDir() {
+		p = filepath.Join(p, ChannelFile)
+	}
+
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("error reading channel %q: %v", p, err)
+	}
+
+	c := &Channel{
+		Dir:       filepath.Dir(p),
+		Addons:    &api.Addons{},
+		Manifests: make(map[string][]byte),
+		raw:       data,
+	}
+	if err := utils.YamlUnmarshal(data, c.Addons); err != nil {
+		return nil, fmt.Errorf("error parsing channel %q: %v", p, err)
+	}
+
+	for _, addon := range c.Addons.Spec.Addons {
+		if addon == nil || addon.Manifest == nil || !IsLocalManifest(*addon.Manifest) {
+			continue
+		}
+		manifest := path.Clean(*addon.Manifest)
+		if _, found := c.Manifests[manifest]; found {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(c.Dir, filepath.FromSlash(manifest)))
+		if err != nil {
+			if os.IsNotExist(err) {
+				c.Manifests[manifest] = nil
+				continue
+			}
+			return nil, fmt.Errorf("error reading manifest %q: %v", manifest, err)
+		}
+		c.Manifests[manifest] = data
+	}
+
+	return c, nil
+}
+
+// IsLocalManifest returns true if the manifest is a path in the channel, rather than a URL
+func IsLocalManifest(manifest string) bool {
+	u, err := url.Parse(manifest)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "" && u.Host == "" && !path.IsAbs(manifest) && !strings.HasPrefix(path.Clean(manifest), "..")
+}
+
+// HashManifest computes the manifestHash of a manifest, the way kOps does for the bootstrap channel
+func HashManifest(data []byte) (string, error) {
+	return utils.HashString(strings.TrimSpace(string(data)))
+}
+
+// AddonName returns the name of an addon, defaulting to the name of the channel
+func (c *Channel) AddonName(addon *api.AddonSpec) string {
+	if addon.Name != nil {
+		return *addon.Name
+	}
+	return c.Addons.ObjectMeta.Name
+}
+
+// manifest returns the contents of the manifest of an addon, if it is part of the channel
+func (c *Channel) manifest(addon *api.AddonSpec) ([]byte, bool) {
+	if addon.Manifest == nil || !IsLocalManifest(*addon.Manifest) {
+		return nil, false
+	}
+	data := c.Manifests[path.Clean(*addon.Manifest)]
+	return data, data != nil
+}
+
+// UpdateHashes sets the manifestHash of the addons to the hash of their manifests,
+// returning the names of the addons whose hash changed
+func (c *Channel) UpdateHashes() ([]string, error) {
+	var changed []string
+	for _, addon := range c.Addons.Spec.Addons {
+		if addon == nil {
+			continue
+		}
+		data, ok := c.manifest(addon)
+		if !ok {
+			continue
+		}
+		hash, err := HashManifest(data)
+		if err != nil {
+			return nil, fmt.Errorf("error hashing manifest %q: %v", *addon.Manifest, err)
+		}
+		if addon.ManifestHash != hash {
+			addon.ManifestHash = hash
+			changed = append(changed, c.AddonName(addon))
+		}
+	}
+	return changed, nil
+}
+
+// Marshal serializes the addons.yaml of the channel
+func (c *Channel) Marshal() ([]byte, error) {
+	return utils.YamlMarshal(c.Addons)
+}
+
+// Write writes the addons.yaml of the channel back to its directory
+func (c *Channel) Write() error {
+	data, err := c.Marshal()
+	if err != nil {
+		return fmt.Errorf("error serializing channel: %v", err)
+	}
+	return os.WriteFile(filepath.Join(c.Dir, ChannelFile), data, 0644)
+}
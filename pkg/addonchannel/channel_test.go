@@ -0,0 +1,291 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addonchannel
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+const testChannel = `kind: Addons
+metadata:
+  name: example
+spec:
+  addons:
+  - name: dashboard.example.com
+    version: 1.2.0
+    manifest: dashboard.example.com/k8s-1.20.yaml
+    kubernetesVersion: '>=1.20.0'
+    manifestHash: %s
+  - name: dashboard.example.com
+    version: 1.2.0
+    manifest: dashboard.example.com/k8s-1.20.yaml
+    kubernetesVersion: '>=1.20.0'
+  - name: monitoring.example.com
+    version: v2
+    manifest: monitoring.example.com/v2.yaml
+    kubernetesVersion: '>=1.20'
+    needsRollingUpdate: nodes
+    serviceAccount: monitoring/applier/v2
+    dependsOn:
+    - monitoring.example.com
+    - dashboard.example.com
+    - prometheus-operator.example.com
+  - name: remote.example.com
+    version: 1.0.0
+    manifest: https://example.com/remote.yaml
+    condition: cloudProvider == "aws" && region == "us-east-1"
+`
+
+const testManifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: dashboard
+`
+
+func writeChannel(t *testing.T, hash string) string {
+	dir := t.TempDir()
+	files := map[string]string{
+		"addons.yaml":                         strings.Replace(testChannel, "%s", hash, 1),
+		"dashboard.example.com/k8s-1.20.yaml": testManifest,
+	}
+	for name, contents := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLint(t *testing.T) {
+	c, err := Load(writeChannel(t, "deadbeef"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var actual []string
+	for _, finding := range c.Lint() {
+		actual = append(actual, string(finding.Severity)+" "+finding.Addon+": "+finding.Message)
+	}
+	hash, _ := HashManifest([]byte(testManifest))
+	expected := []string{
+		`Error dashboard.example.com: manifestHash deadbeef does not match manifest "dashboard.example.com/k8s-1.20.yaml", whose hash is ` + hash,
+		`Error dashboard.example.com: duplicate addon for kubernetesVersion ">=1.20.0"; set distinct ids or kubernetesVersion ranges`,
+		`Warning dashboard.example.com: manifestHash is not set; channels only detects changes of the manifest from its version and id`,
+		`Warning monitoring.example.com: version "v2" is not a strict semver version`,
+		`Error monitoring.example.com: kubernetesVersion ">=1.20" is not a semver range: Could not parse Range ">=1.20": Could not parse version "1.20" in ">=1.20": No Major.Minor.Patch elements found`,
+		`Error monitoring.example.com: needsRollingUpdate "nodes" is not one of control-plane, workers or all`,
+		`Error monitoring.example.com: serviceAccount "monitoring/applier/v2" is not of the form name or namespace/name`,
+		`Error monitoring.example.com: addon depends on itself`,
+		`Warning monitoring.example.com: dependsOn "prometheus-operator.example.com" is not an addon of the channel, so it is only ordered when applied together from another channel`,
+		`Error monitoring.example.com: manifest "monitoring.example.com/v2.yaml" not found`,
+		`Error remote.example.com: condition references unknown fact "region"; known facts are clusterName, cloudProvider, networking, kubernetesVersion, nodeCount, controlPlaneCount, featureGates`,
+		`Warning remote.example.com: manifest "https://example.com/remote.yaml" is not part of the channel, so its hash can't be checked`,
+	}
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected findings\nexpected:\n%s\nactual:\n%s", strings.Join(expected, "\n"), strings.Join(actual, "\n"))
+	}
+	if !HasErrors(c.Lint()) {
+		t.Errorf("expected errors")
+	}
+}
+
+func TestUpdateHashes(t *testing.T) {
+	dir := writeChannel(t, "deadbeef")
+	c, err := Load(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	changed, err := c.UpdateHashes()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(changed, ",") != "dashboard.example.com,dashboard.example.com" {
+		t.Errorf("unexpected changed addons %v", changed)
+	}
+	if err := c.Write(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reloaded, err := Load(filepath.Join(dir, ChannelFile))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	hash, _ := HashManifest([]byte(testManifest))
+	for _, addon := range reloaded.Addons.Spec.Addons[:2] {
+		if addon.ManifestHash != hash {
+			t.Errorf("expected manifestHash %s, got %s", hash, addon.ManifestHash)
+		}
+	}
+}
+
+func TestPublish(t *testing.T) {
+	c, err := Load(writeChannel(t, ""))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.Addons.Spec.Addons = c.Addons.Spec.Addons[:1]
+	if _, err := c.UpdateHashes(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dest := vfs.NewMemFSPath(vfs.NewMemFSContext(), "channels/stable")
+	revision, err := c.Publish(dest)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	manifest, err := dest.Join(revision, "dashboard.example.com/k8s-1.20.yaml").ReadFile()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(manifest) != testManifest {
+		t.Errorf("unexpected manifest %q", manifest)
+	}
+
+	addons, err := dest.Join(ChannelFile).ReadFile()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(addons), "manifest: "+revision+"/dashboard.example.com/k8s-1.20.yaml") {
+		t.Errorf("expected the published channel to refer to the manifest of revision %s, got:\n%s", revision, addons)
+	}
+}
+
+// testRegistry is a minimal OCI registry, which requires a bearer token
+type testRegistry struct {
+	mutex     sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string][]byte
+}
+
+func (r *testRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if req.URL.Path == "/token" {
+		user, password, ok := req.BasicAuth()
+		if !ok || user != "user" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"token":"t0k3n"}`))
+		return
+	}
+	if req.Header.Get("Authorization") != "Bearer t0k3n" {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="http://`+req.Host+`/token",service="test",scope="repository:channels:pull,push"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	body, _ := io.ReadAll(req.Body)
+	switch {
+	case req.Method == http.MethodHead && strings.HasPrefix(req.URL.Path, "/v2/channels/blobs/"):
+		if r.blobs[strings.TrimPrefix(req.URL.Path, "/v2/channels/blobs/")] == nil {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	case req.Method == http.MethodPost && req.URL.Path == "/v2/channels/blobs/uploads/":
+		w.Header().Set("Location", "/v2/channels/blobs/uploads/1?state=x")
+		w.WriteHeader(http.StatusAccepted)
+	case req.Method == http.MethodPut && req.URL.Path == "/v2/channels/blobs/uploads/1":
+		if req.URL.Query().Get("state") != "x" || digest(body) != req.URL.Query().Get("digest") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		r.blobs[digest(body)] = body
+		w.WriteHeader(http.StatusCreated)
+	case req.Method == http.MethodPut && strings.HasPrefix(req.URL.Path, "/v2/channels/manifests/"):
+		r.manifests[strings.TrimPrefix(req.URL.Path, "/v2/channels/manifests/")] = body
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestPublishOCI(t *testing.T) {
+	c, err := Load(writeChannel(t, ""))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.Addons.Spec.Addons = c.Addons.Spec.Addons[:1]
+
+	registry := &testRegistry{blobs: make(map[string][]byte), manifests: make(map[string][]byte)}
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	ref, err := ParseOCIReference("oci://" + strings.TrimPrefix(server.URL, "http://") + "/channels:stable")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	publisher := &OCIPublisher{Username: "user", Password: "secret"}
+	if _, err := c.PublishOCI(context.Background(), publisher, ref); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	manifest := &ociManifest{}
+	if err := json.Unmarshal(registry.manifests["stable"], manifest); err != nil {
+		t.Fatalf("error parsing pushed manifest: %v", err)
+	}
+	var titles []string
+	for _, layer := range manifest.Layers {
+		if registry.blobs[layer.Digest] == nil {
+			t.Errorf("layer %s was not pushed", layer.Digest)
+		}
+		titles = append(titles, layer.Annotations[ociTitleAnnotation])
+	}
+	if strings.Join(titles, ",") != "dashboard.example.com/k8s-1.20.yaml,addons.yaml" {
+		t.Errorf("unexpected layers %v", titles)
+	}
+	if registry.blobs[manifest.Config.Digest] == nil {
+		t.Errorf("config was not pushed")
+	}
+}
+
+func TestParseOCIReference(t *testing.T) {
+	grid := map[string]string{
+		"oci://registry.example.com/kops/channels:stable": "registry.example.com/kops/channels:stable",
+		"localhost:5000/channels":                         "localhost:5000/channels:latest",
+	}
+	for input, expected := range grid {
+		ref, err := ParseOCIReference(input)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", input, err)
+			continue
+		}
+		if ref.String() != expected {
+			t.Errorf("%q: expected %q, got %q", input, expected, ref.String())
+		}
+	}
+	if _, err := ParseOCIReference("channels"); err == nil {
+		t.Errorf("expected an error for a reference without registry")
+	}
+}
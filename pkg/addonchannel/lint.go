@@ -0,0 +1,197 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addonchannel
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"k8s.io/kops/channels/pkg/api"
+	"k8s.io/kops/channels/pkg/condition"
+	"sigs.k8s.io/yaml"
+)
+
+// Severity is the severity of a lint finding
+type Severity string
+
+const (
+	// SeverityError findings make the channel unusable, or wrong
+	SeverityError Severity = "Error"
+	// SeverityWarning findings are likely mistakes
+	SeverityWarning Severity = "Warning"
+)
+
+// Finding is a problem found in a channel
+type Finding struct {
+	Severity Severity `json:"severity"`
+	// Addon is the name of the addon, or empty for problems of the channel itself
+	Addon   string `json:"addon,omitempty"`
+	Message string `json:"message"`
+}
+
+// validNeedsRollingUpdate are the legal values of needsRollingUpdate
+var validNeedsRollingUpdate = map[string]bool{
+	"":              true,
+	"control-plane": true,
+	"workers":       true,
+	"all":           true,
+}
+
+// conditionVariables are the facts that conditions can reference
+var conditionVariables = func() map[string]bool {
+	m := make(map[string]bool)
+	for _, v := range api.ConditionVariables {
+		m[v] = true
+	}
+	return m
+}()
+
+// Lint checks the channel for problems
+func (c *Channel) Lint() []*Finding {
+	var findings []*Finding
+	add := func(severity Severity, addon string, format string, args ...interface{}) {
+		findings = append(findings, &Finding{
+			Severity: severity,
+			Addon:    addon,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	if err := yaml.UnmarshalStrict(c.raw, &api.Addons{}); err != nil {
+		add(SeverityError, "", "%v", err)
+	}
+	if c.Addons.Kind != "Addons" {
+		add(SeverityError, "", "kind is %q, expected Addons", c.Addons.Kind)
+	}
+
+	type key struct {
+		name              string
+		id                string
+		kubernetesVersion string
+	}
+	seen := make(map[key]bool)
+
+	names := make(map[string]bool)
+	for _, addon := range c.Addons.Spec.Addons {
+		if addon != nil {
+			names[c.AddonName(addon)] = true
+		}
+	}
+
+	for i, addon := range c.Addons.Spec.Addons {
+		if addon == nil {
+			add(SeverityError, "", "addon %d is empty", i)
+			continue
+		}
+
+		name := c.AddonName(addon)
+		if name == "" {
+			add(SeverityError, "", "addon %d has no name", i)
+		}
+
+		if addon.Version == nil || *addon.Version == "" {
+			add(SeverityError, name, "version is required")
+		} else if _, err := semver.Parse(*addon.Version); err != nil {
+			if _, err := semver.ParseTolerant(*addon.Version); err != nil {
+				add(SeverityError, name, "version %q is not a semver version: %v", *addon.Version, err)
+			} else {
+				add(SeverityWarning, name, "version %q is not a strict semver version", *addon.Version)
+			}
+		}
+
+		if addon.KubernetesVersion != "" {
+			if _, err := semver.ParseRange(addon.KubernetesVersion); err != nil {
+				add(SeverityError, name, "kubernetesVersion %q is not a semver range: %v", addon.KubernetesVersion, err)
+			}
+		}
+
+		k := key{name: name, id: addon.Id, kubernetesVersion: addon.KubernetesVersion}
+		if seen[k] {
+			if addon.Id == "" {
+				add(SeverityError, name, "duplicate addon for kubernetesVersion %q; set distinct ids or kubernetesVersion ranges", addon.KubernetesVersion)
+			} else {
+				add(SeverityError, name, "duplicate id %q for kubernetesVersion %q", addon.Id, addon.KubernetesVersion)
+			}
+		}
+		seen[k] = true
+
+		if !validNeedsRollingUpdate[addon.NeedsRollingUpdate] {
+			add(SeverityError, name, "needsRollingUpdate %q is not one of control-plane, workers or all", addon.NeedsRollingUpdate)
+		}
+
+		if _, _, err := addon.ServiceAccountRef(); err != nil {
+			add(SeverityError, name, "%v", err)
+		}
+
+		if addon.Condition != "" {
+			if program, err := condition.Compile(addon.Condition); err != nil {
+				add(SeverityError, name, "invalid condition: %v", err)
+			} else {
+				for _, variable := range program.Variables() {
+					if !conditionVariables[variable] {
+						add(SeverityError, name, "condition references unknown fact %q; known facts are %s", variable, strings.Join(api.ConditionVariables, ", "))
+					}
+				}
+			}
+		}
+
+		for _, dependency := range addon.DependsOn {
+			if dependency == name {
+				add(SeverityError, name, "addon depends on itself")
+			} else if !names[dependency] {
+				add(SeverityWarning, name, "dependsOn %q is not an addon of the channel, so it is only ordered when applied together from another channel", dependency)
+			}
+		}
+
+		if addon.Manifest == nil || *addon.Manifest == "" {
+			add(SeverityError, name, "manifest is required")
+			continue
+		}
+		if !IsLocalManifest(*addon.Manifest) {
+			add(SeverityWarning, name, "manifest %q is not part of the channel, so its hash can't be checked", *addon.Manifest)
+			continue
+		}
+		data, ok := c.manifest(addon)
+		if !ok {
+			add(SeverityError, name, "manifest %q not found", *addon.Manifest)
+			continue
+		}
+		hash, err := HashManifest(data)
+		if err != nil {
+			add(SeverityError, name, "error hashing manifest %q: %v", *addon.Manifest, err)
+			continue
+		}
+		if addon.ManifestHash == "" {
+			add(SeverityWarning, name, "manifestHash is not set; channels only detects changes of the manifest from its version and id")
+		} else if addon.ManifestHash != hash {
+			add(SeverityError, name, "manifestHash %s does not match manifest %q, whose hash is %s", addon.ManifestHash, *addon.Manifest, hash)
+		}
+	}
+
+	return findings
+}
+
+// HasErrors returns true if any of the findings is an error
+func HasErrors(findings []*Finding) bool {
+	for _, finding := range findings {
+		if finding.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,357 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addonchannel
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	ociManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	// ConfigMediaType identifies OCI artifacts holding an addons channel
+	ConfigMediaType       = "application/vnd.kops.addons.config.v1+json"
+	channelMediaType      = "application/vnd.kops.addons.channel.v1+yaml"
+	manifestMediaType     = "application/vnd.kops.addons.manifest.v1+yaml"
+	ociTitleAnnotation    = "org.opencontainers.image.title"
+	ociRevisionAnnotation = "org.opencontainers.image.revision"
+)
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type ociManifest struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	MediaType     string            `json:"mediaType"`
+	Config        ociDescriptor     `json:"config"`
+	Layers        []ociDescriptor   `json:"layers"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+// OCIReference is the location of an OCI artifact, in the registry/repository:tag form
+type OCIReference struct {
+	Registry   string
+	Repository string
+	Tag        string
+}
+
+// ParseOCIReference parses an OCI reference, with an optional oci:// prefix
+func ParseOCIReference(ref string) (*OCIReference, error) {
+	ref = strings.TrimPrefix(ref, "oci://")
+	slash := strings.Index(ref, "/")
+	if slash <= 0 || slash == len(ref)-1 {
+		return nil, fmt.Errorf("OCI reference %q must be in the registry/repository:tag form", ref)
+	}
+	r := &OCIReference{
+		Registry:   ref[:slash],
+		Repository: ref[slash+1:],
+		Tag:        "latest",
+	}
+	if colon := strings.LastIndex(r.Repository, ":"); colon != -1 {
+		r.Tag = r.Repository[colon+1:]
+		r.Repository = r.Repository[:colon]
+	}
+	if r.Repository == "" || r.Tag == "" {
+		return nil, fmt.Errorf("OCI reference %q must be in the registry/repository:tag form", ref)
+	}
+	return r, nil
+}
+
+func (r *OCIReference) String() string {
+	return r.Registry + "/" + r.Repository + ":" + r.Tag
+}
+
+// baseURL returns the URL of the registry API; local registries are accessed over plain HTTP
+func (r *OCIReference) baseURL() string {
+	host := strings.SplitN(r.Registry, ":", 2)[0]
+	if host == "localhost" || host == "127.0.0.1" {
+		return "http://" + r.Registry
+	}
+	return "https://" + r.Registry
+}
+
+// OCIPublisher pushes channels to an OCI registry
+type OCIPublisher struct {
+	Client *http.Client
+	// Username and Password authenticate to the registry, or to its token service, if set
+	Username string
+	Password string
+
+	token string
+}
+
+// DockerCredentials returns the credentials of a registry from the docker configuration file
+func DockerCredentials(registry string) (string, string, error) {
+	dir := os.Getenv("DOCKER_CONFIG")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", "", nil
+		}
+		dir = filepath.Join(home, ".docker")
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", "", nil
+		}
+		return "", "", err
+	}
+
+	config := struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return "", "", fmt.Errorf("error parsing docker configuration: %v", err)
+	}
+	for _, key := range []string{registry, "https://" + registry} {
+		auth, found := config.Auths[key]
+		if !found || auth.Auth == "" {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+		if err != nil {
+			return "", "", fmt.Errorf("error decoding docker credentials of %s: %v", registry, err)
+		}
+		credentials := strings.SplitN(string(decoded), ":", 2)
+		if len(credentials) != 2 {
+			return "", "", fmt.Errorf("invalid docker credentials for %s", registry)
+		}
+		return credentials[0], credentials[1], nil
+	}
+	return "", "", nil
+}
+
+// PublishOCI pushes the channel to an OCI registry, as an artifact with a layer for addons.yaml and for each manifest.
+// The tag is only moved to the new artifact once all its layers are uploaded, so clients always pull a complete channel.
+func (c *Channel) PublishOCI(ctx context.Context, p *OCIPublisher, ref *OCIReference) (string, error) {
+	revision, err := c.Revision()
+	if err != nil {
+		return "", err
+	}
+	files, err := c.files("")
+	if err != nil {
+		return "", err
+	}
+
+	config := []byte("{}")
+	manifest := &ociManifest{
+		SchemaVersion: 2,
+		MediaType:     ociManifestMediaType,
+		Config:        descriptor(ConfigMediaType, config),
+		Annotations: map[string]string{
+			ociRevisionAnnotation: revision,
+		},
+	}
+	if err := p.pushBlob(ctx, ref, manifest.Config.Digest, config); err != nil {
+		return "", err
+	}
+	for _, f := range files {
+		mediaType := manifestMediaType
+		if f.path == ChannelFile {
+			mediaType = channelMediaType
+		}
+		layer := descriptor(mediaType, f.data)
+		layer.Annotations = map[string]string{ociTitleAnnotation: f.path}
+		if err := p.pushBlob(ctx, ref, layer.Digest, f.data); err != nil {
+			return "", err
+		}
+		manifest.Layers = append(manifest.Layers, layer)
+	}
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return "", fmt.Errorf("error serializing OCI manifest: %v", err)
+	}
+	header := http.Header{"Content-Type": []string{ociManifestMediaType}}
+	resp, err := p.do(ctx, http.MethodPut, ref.baseURL()+"/v2/"+ref.Repository+"/manifests/"+ref.Tag, data, header)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return "", responseError("pushing manifest", resp)
+	}
+	return digest(data), nil
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func descriptor(mediaType string, data []byte) ociDescriptor {
+	return ociDescriptor{
+		MediaType: mediaType,
+		Digest:    digest(data),
+		Size:      int64(len(data)),
+	}
+}
+
+// pushBlob uploads a blob with a monolithic upload, unless the registry already has it
+func (p *OCIPublisher) pushBlob(ctx context.Context, ref *OCIReference, digest string, data []byte) error {
+	base := ref.baseURL() + "/v2/" + ref.Repository + "/blobs/"
+
+	resp, err := p.do(ctx, http.MethodHead, base+digest, nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	resp, err = p.do(ctx, http.MethodPost, base+"uploads/", nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return responseError("starting upload of "+digest, resp)
+	}
+	location, err := resp.Request.URL.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return fmt.Errorf("invalid upload location %q: %v", resp.Header.Get("Location"), err)
+	}
+	query := location.Query()
+	query.Set("digest", digest)
+	location.RawQuery = query.Encode()
+
+	header := http.Header{"Content-Type": []string{"application/octet-stream"}}
+	resp, err = p.do(ctx, http.MethodPut, location.String(), data, header)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return responseError("uploading "+digest, resp)
+	}
+	return nil
+}
+
+// do sends a request to the registry, authenticating with a bearer token when the registry asks for one
+func (p *OCIPublisher) do(ctx context.Context, method string, u string, body []byte, header http.Header) (*http.Response, error) {
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		if p.token != "" {
+			req.Header.Set("Authorization", "Bearer "+p.token)
+		} else if p.Username != "" {
+			req.SetBasicAuth(p.Username, p.Password)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("error sending %s %s: %v", method, u, err)
+		}
+		challenge := resp.Header.Get("WWW-Authenticate")
+		if resp.StatusCode != http.StatusUnauthorized || attempt != 0 || !strings.HasPrefix(challenge, "Bearer ") {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		if p.token, err = p.fetchToken(ctx, challenge); err != nil {
+			return nil, err
+		}
+	}
+}
+
+var challengeParameter = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// fetchToken gets a bearer token from the token service of the registry
+func (p *OCIPublisher) fetchToken(ctx context.Context, challenge string) (string, error) {
+	params := make(map[string]string)
+	for _, match := range challengeParameter.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("invalid authentication challenge %q", challenge)
+	}
+	query := realm.Query()
+	for _, k := range []string{"service", "scope"} {
+		if params[k] != "" {
+			query.Set(k, params[k])
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if p.Username != "" {
+		req.SetBasicAuth(p.Username, p.Password)
+	}
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error getting registry token: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", responseError("getting registry token", resp)
+	}
+
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("error parsing registry token: %v", err)
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	return token.AccessToken, nil
+}
+
+func responseError(action string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("error %s: %s: %s", action, resp.Status, strings.TrimSpace(string(body)))
+}
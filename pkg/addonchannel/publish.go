@@ -0,0 +1,113 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addonchannel
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"sort"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/channels/pkg/api"
+	"k8s.io/kops/upup/pkg/fi/utils"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// file is a file of a published channel
+type file struct {
+	path string
+	data []byte
+}
+
+// files returns the addons.yaml and the manifests of the channel, with the manifests moved under prefix
+func (c *Channel) files(prefix string) ([]file, error) {
+	addons := &api.Addons{}
+	data, err := c.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("error serializing channel: %v", err)
+	}
+	if err := utils.YamlUnmarshal(data, addons); err != nil {
+		return nil, fmt.Errorf("error copying channel: %v", err)
+	}
+
+	var files []file
+	published := make(map[string]bool)
+	for _, addon := range addons.Spec.Addons {
+		if addon == nil || addon.Manifest == nil || !IsLocalManifest(*addon.Manifest) {
+			continue
+		}
+		manifest := path.Clean(*addon.Manifest)
+		data := c.Manifests[manifest]
+		if data == nil {
+			return nil, fmt.Errorf("manifest %q not found", manifest)
+		}
+		p := path.Join(prefix, manifest)
+		addon.Manifest = &p
+		if !published[p] {
+			files = append(files, file{path: p, data: data})
+			published[p] = true
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+
+	data, err = utils.YamlMarshal(addons)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing channel: %v", err)
+	}
+	return append(files, file{path: ChannelFile, data: data}), nil
+}
+
+// Revision returns a hash of the contents of the channel
+func (c *Channel) Revision() (string, error) {
+	files, err := c.files("")
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	for _, f := range files {
+		fmt.Fprintf(h, "%s\n%d\n", f.path, len(f.data))
+		h.Write(f.data)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12], nil
+}
+
+// Publish copies the channel to a VFS location, such as an S3 or GCS bucket.
+// The manifests are written under a directory named after the revision of the channel, and addons.yaml is
+// written last, so that clients never read an addons.yaml referring to manifests which are missing or which
+// don't match their manifestHash.
+func (c *Channel) Publish(dest vfs.Path) (string, error) {
+	revision, err := c.Revision()
+	if err != nil {
+		return "", err
+	}
+
+	files, err := c.files(revision)
+	if err != nil {
+		return "", err
+	}
+	for _, f := range files {
+		p := dest.Join(f.path)
+		klog.V(2).Infof("writing %s", p)
+		if err := p.WriteFile(bytes.NewReader(f.data), nil); err != nil {
+			return "", fmt.Errorf("error writing %s: %v", p, err)
+		}
+	}
+	return revision, nil
+}
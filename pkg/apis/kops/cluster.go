@@ -91,6 +91,8 @@ type ClusterSpec struct {
 	SecretStore string `json:"secretStore,omitempty"`
 	// KeyStore is the VFS path to where SSL keys and certificates are stored
 	KeyStore string `json:"keyStore,omitempty"`
+	// SecretStoreEncryption envelope-encrypts the secrets and keys in the secret store and keystore with a KMS key
+	SecretStoreEncryption *SecretStoreEncryptionSpec `json:"secretStoreEncryption,omitempty"`
 	// ConfigStore is the VFS path to where the configuration (Cluster, InstanceGroups etc) is stored
 	ConfigStore string `json:"configStore,omitempty"`
 	// DNSZone is the DNS zone we should use when configuring DNS
@@ -163,6 +165,8 @@ type ClusterSpec struct {
 	NodeTerminationHandler *NodeTerminationHandlerConfig `json:"nodeTerminationHandler,omitempty"`
 	// MetricsServer determines the metrics server configuration.
 	MetricsServer *MetricsServerConfig `json:"metricsServer,omitempty"`
+	// KubeStateMetrics determines the kube-state-metrics configuration.
+	KubeStateMetrics *KubeStateMetricsConfig `json:"kubeStateMetrics,omitempty"`
 	// CertManager determines the metrics server configuration.
 	CertManager *CertManagerConfig `json:"certManager,omitempty"`
 	// AWSLoadbalancerControllerConfig determines the AWS LB controller configuration.
@@ -176,8 +180,20 @@ type ClusterSpec struct {
 	Authentication *AuthenticationSpec `json:"authentication,omitempty"`
 	// Authorization field controls how the cluster is configured for authorization
 	Authorization *AuthorizationSpec `json:"authorization,omitempty"`
+	// Audit configures a kops-managed audit policy, webhook backend and log shipping for the API server
+	Audit *AuditSpec `json:"audit,omitempty"`
+	// Logging configures a kops-managed fluent-bit addon that ships the logs of the pods and of the node services
+	Logging *LoggingSpec `json:"logging,omitempty"`
+	// PodSecurity configures the cluster-wide defaults and exemptions of the PodSecurity admission plugin
+	PodSecurity *PodSecuritySpec `json:"podSecurity,omitempty"`
 	// NodeAuthorization defined the custom node authorization configuration
 	NodeAuthorization *NodeAuthorizationSpec `json:"nodeAuthorization,omitempty"`
+	// NodeCertificates configures the lifetime of the certificates kops-controller issues to nodes
+	NodeCertificates *NodeCertificatesSpec `json:"nodeCertificates,omitempty"`
+	// NodeBootstrap configures how nodes prove their identity to kops-controller when they bootstrap
+	NodeBootstrap *NodeBootstrapSpec `json:"nodeBootstrap,omitempty"`
+	// InstanceGroupScaling configures the kops-controller API for resizing instance groups from within the cluster
+	InstanceGroupScaling *InstanceGroupScalingSpec `json:"instanceGroupScaling,omitempty"`
 	// CloudLabels defines additional tags or labels on cloud provider resources
 	CloudLabels map[string]string `json:"cloudLabels,omitempty"`
 	// Hooks for custom actions e.g. on first installation
@@ -188,6 +204,13 @@ type ClusterSpec struct {
 	IAM *IAMSpec `json:"iam,omitempty"`
 	// EncryptionConfig controls if encryption is enabled
 	EncryptionConfig *bool `json:"encryptionConfig,omitempty"`
+	// EncryptionAtRest encrypts resources stored in etcd with a KMS key, using a KMS plugin run by kops
+	EncryptionAtRest *EncryptionAtRestSpec `json:"encryptionAtRest,omitempty"`
+	// FIPS restricts the cluster to FIPS 140-2 approved cryptography: the kOps components are run from their
+	// BoringCrypto builds, and the TLS settings of etcd, the control plane and the kubelet are limited to approved ciphers
+	FIPS *bool `json:"fips,omitempty"`
+	// Hardening applies a hardening profile to the components and the instances of the cluster
+	Hardening *HardeningSpec `json:"hardening,omitempty"`
 	// DisableSubnetTags controls if subnets are tagged in AWS
 	DisableSubnetTags bool `json:"disableSubnetTags,omitempty"`
 	// Target allows for us to nest extra config for targets such as terraform
@@ -199,6 +222,8 @@ type ClusterSpec struct {
 	// specified, each parameter must follow the form variable=value, the way
 	// it would appear in sysctl.conf.
 	SysctlParameters []string `json:"sysctlParameters,omitempty"`
+	// NodeTuning configures kernel parameters, kernel modules, huge pages and udev rules on all instances.
+	NodeTuning *NodeTuningSpec `json:"nodeTuning,omitempty"`
 	// RollingUpdate defines the default rolling-update settings for instance groups.
 	RollingUpdate *RollingUpdate `json:"rollingUpdate,omitempty"`
 	// ClusterAutoscaler defines the cluster autoscaler configuration.
@@ -265,10 +290,21 @@ type NodeAuthorizerSpec struct {
 	TokenTTL *metav1.Duration `json:"tokenTTL,omitempty"`
 }
 
-// AddonSpec defines an addon that we want to install in the cluster
+// AddonSpec defines a channel of addons that we want to install in the cluster, or overrides an addon of the channels
 type AddonSpec struct {
 	// Manifest is a path to the manifest that defines the addon
 	Manifest string `json:"manifest,omitempty"`
+	// Priority is the precedence of the channel when it ships an addon of the same name as another channel.
+	// The addon from the channel with the highest priority is installed; the bootstrap channel managed by kOps has priority 0.
+	Priority int32 `json:"priority,omitempty"`
+	// Name is the name of an addon to disable or pin, whichever channel ships it, instead of adding the channel in Manifest.
+	Name string `json:"name,omitempty"`
+	// Disabled stops kOps from installing or updating the addon named Name; resources already installed are left in place.
+	Disabled bool `json:"disabled,omitempty"`
+	// Version pins the addon named Name to this version, instead of rolling it forward with its channel.
+	Version string `json:"version,omitempty"`
+	// ManifestHash pins the addon named Name to the manifest with this hash.
+	ManifestHash string `json:"manifestHash,omitempty"`
 }
 
 // FileAssetSpec defines the structure for a file asset
@@ -293,6 +329,9 @@ type Assets struct {
 	FileRepository *string `json:"fileRepository,omitempty"`
 	// ContainerProxy is a url for a pull-through proxy of a docker registry
 	ContainerProxy *string `json:"containerProxy,omitempty"`
+	// AirGapped requires all files and images to be served from the FileRepository and ContainerRegistry,
+	// so that no phase of the installation needs internet access
+	AirGapped *bool `json:"airGapped,omitempty"`
 }
 
 // IAMSpec adds control over the IAM security policies applied to resources
@@ -340,10 +379,12 @@ type ExecContainerAction struct {
 type AuthenticationSpec struct {
 	Kopeio *KopeioAuthenticationSpec `json:"kopeio,omitempty"`
 	Aws    *AwsAuthenticationSpec    `json:"aws,omitempty"`
+	// OIDC configures the API server to authenticate users with ID tokens from an OpenID Connect provider
+	OIDC *OIDCAuthenticationSpec `json:"oidc,omitempty"`
 }
 
 func (s *AuthenticationSpec) IsEmpty() bool {
-	return s.Kopeio == nil && s.Aws == nil
+	return s.Kopeio == nil && s.Aws == nil && s.OIDC == nil
 }
 
 type KopeioAuthenticationSpec struct {
@@ -366,6 +407,29 @@ type AwsAuthenticationSpec struct {
 	CPULimit *resource.Quantity `json:"cpuLimit,omitempty"`
 }
 
+// OIDCAuthenticationSpec configures authentication with an OpenID Connect provider.
+// On Kubernetes 1.30 and later it is written as a structured AuthenticationConfiguration,
+// otherwise it is passed to the API server as the equivalent --oidc flags.
+type OIDCAuthenticationSpec struct {
+	// IssuerURL is the URL of the OpenID issuer; only the https scheme is accepted
+	IssuerURL string `json:"issuerURL,omitempty"`
+	// ClientID is the client ID the ID tokens must be issued for
+	ClientID string `json:"clientID,omitempty"`
+	// CertificateAuthority is the PEM encoded CA bundle used to verify the issuer. Default the host's root CAs
+	CertificateAuthority string `json:"certificateAuthority,omitempty"`
+	// UsernameClaim is the claim used as the user name. Default sub
+	UsernameClaim string `json:"usernameClaim,omitempty"`
+	// UsernamePrefix is prepended to user names. Default the issuer URL followed by '#', unless usernameClaim is email.
+	// Set to an empty string to disable the prefix.
+	UsernamePrefix *string `json:"usernamePrefix,omitempty"`
+	// GroupsClaim is the claim holding the groups of the user, as a string or array of strings
+	GroupsClaim string `json:"groupsClaim,omitempty"`
+	// GroupsPrefix is prepended to group names
+	GroupsPrefix string `json:"groupsPrefix,omitempty"`
+	// RequiredClaims are claims that must be present in the ID token with the given values
+	RequiredClaims map[string]string `json:"requiredClaims,omitempty"`
+}
+
 type AuthorizationSpec struct {
 	AlwaysAllow *AlwaysAllowAuthorizationSpec `json:"alwaysAllow,omitempty"`
 	RBAC        *RBACAuthorizationSpec        `json:"rbac,omitempty"`
@@ -387,9 +451,24 @@ type AccessSpec struct {
 	DNS *DNSAccessSpec `json:"dns,omitempty"`
 	// LoadBalancer is the configuration for the kube-apiserver ELB
 	LoadBalancer *LoadBalancerAccessSpec `json:"loadBalancer,omitempty"`
+	// Gateway publishes the kube-apiserver through an existing Gateway API gateway
+	Gateway *GatewayAccessSpec `json:"gateway,omitempty"`
 }
 
 type DNSAccessSpec struct {
+	// InternalZoneID is the ID of a private zone in which the API DNS name resolves to the internal addresses of the control plane,
+	// while it keeps resolving to their external addresses in the public zone
+	InternalZoneID string `json:"internalZoneID,omitempty"`
+}
+
+// GatewayAccessSpec provides configuration details related to publishing the API through Gateway API
+type GatewayAccessSpec struct {
+	// Name is the name of the Gateway the route to the API is attached to
+	Name string `json:"name,omitempty"`
+	// Namespace is the namespace of the Gateway. Default "default".
+	Namespace string `json:"namespace,omitempty"`
+	// SectionName is the name of the TLS passthrough listener of the Gateway. If not set, the route is attached to all its listeners.
+	SectionName string `json:"sectionName,omitempty"`
 }
 
 // LoadBalancerType string describes LoadBalancer types (public, internal)
@@ -445,6 +524,28 @@ type LoadBalancerAccessSpec struct {
 	CrossZoneLoadBalancing *bool `json:"crossZoneLoadBalancing,omitempty"`
 	// Subnets allows you to specify the subnets that must be used for the load balancer
 	Subnets []LoadBalancerSubnetSpec `json:"subnets,omitempty"`
+	// PrivateLink publishes the load balancer as a VPC endpoint service
+	PrivateLink *PrivateLinkSpec `json:"privateLink,omitempty"`
+}
+
+// PrivateLinkSpec provides configuration for publishing the API load balancer as a VPC endpoint service
+type PrivateLinkSpec struct {
+	// AllowedPrincipals are the ARNs of the principals allowed to create endpoints for the service
+	AllowedPrincipals []string `json:"allowedPrincipals,omitempty"`
+	// AcceptanceRequired requires the connection requests of endpoints to be accepted manually
+	AcceptanceRequired *bool `json:"acceptanceRequired,omitempty"`
+	// Endpoints are the interface endpoints kOps creates for the service in consumer VPCs
+	Endpoints []PrivateLinkEndpointSpec `json:"endpoints,omitempty"`
+}
+
+// PrivateLinkEndpointSpec provides configuration for an interface endpoint to the API in a consumer VPC
+type PrivateLinkEndpointSpec struct {
+	// VPCID is the ID of the consumer VPC
+	VPCID string `json:"vpcID,omitempty"`
+	// SubnetIDs are the subnets of the consumer VPC in which the endpoint network interfaces are created
+	SubnetIDs []string `json:"subnetIDs,omitempty"`
+	// SecurityGroupIDs are the security groups attached to the endpoint network interfaces
+	SecurityGroupIDs []string `json:"securityGroupIDs,omitempty"`
 }
 
 // KubeDNSConfig defines the kube dns configuration
@@ -481,6 +582,44 @@ type KubeDNSConfig struct {
 	MemoryLimit *resource.Quantity `json:"memoryLimit,omitempty"`
 	// NodeLocalDNS specifies the configuration for the node-local-dns addon
 	NodeLocalDNS *NodeLocalDNSConfig `json:"nodeLocalDNS,omitempty"`
+	// ServerBlocks are additional CoreDNS server blocks, such as zones answered by other nameservers
+	ServerBlocks []CoreDNSServerBlock `json:"serverBlocks,omitempty"`
+	// Rewrites are CoreDNS rewrite rules applied to the names queried from the default server block
+	Rewrites []CoreDNSRewriteRule `json:"rewrites,omitempty"`
+	// Autoscaler configures how the dns-autoscaler scales the number of CoreDNS replicas with the cluster size
+	Autoscaler *DNSAutoscalerConfig `json:"autoscaler,omitempty"`
+}
+
+// CoreDNSServerBlock is a CoreDNS server block serving a set of zones
+type CoreDNSServerBlock struct {
+	// Zones are the DNS zones served by the block
+	Zones []string `json:"zones,omitempty"`
+	// Hosts maps IP addresses to the host names they answer for, so that the zones can resolve differently inside the cluster
+	Hosts map[string][]string `json:"hosts,omitempty"`
+	// Forward are the nameservers that queries not answered by Hosts are forwarded to
+	Forward []string `json:"forward,omitempty"`
+	// CacheTTL is the maximum number of seconds answers are cached. Default 30.
+	CacheTTL int32 `json:"cacheTTL,omitempty"`
+}
+
+// CoreDNSRewriteRule rewrites the name of a query before it is resolved
+type CoreDNSRewriteRule struct {
+	// Match is how From is matched against the queried name: exact, prefix, suffix, substring or regex. Default exact.
+	Match string `json:"match,omitempty"`
+	// From is the name, or part of it, to rewrite
+	From string `json:"from"`
+	// To is the replacement
+	To string `json:"to"`
+}
+
+// DNSAutoscalerConfig are the linear scaling parameters of the dns-autoscaler
+type DNSAutoscalerConfig struct {
+	// CoresPerReplica is the number of node cores per CoreDNS replica. Default 256.
+	CoresPerReplica int32 `json:"coresPerReplica,omitempty"`
+	// NodesPerReplica is the number of nodes per CoreDNS replica. Default 16.
+	NodesPerReplica int32 `json:"nodesPerReplica,omitempty"`
+	// PreventSinglePointFailure runs at least two replicas when the cluster has more than one node. Default true.
+	PreventSinglePointFailure *bool `json:"preventSinglePointFailure,omitempty"`
 }
 
 // NodeLocalDNSConfig are options of the node-local-dns
@@ -495,6 +634,10 @@ type NodeLocalDNSConfig struct {
 	MemoryRequest *resource.Quantity `json:"memoryRequest,omitempty"`
 	// CPURequest specifies the cpu requests of each node-local-dns container in the daemonset. Default 25m.
 	CPURequest *resource.Quantity `json:"cpuRequest,omitempty"`
+	// PrometheusMetricsPort is the port on which each node-local-dns pod exposes its Prometheus metrics. Default 9253.
+	PrometheusMetricsPort int32 `json:"prometheusMetricsPort,omitempty"`
+	// UpstreamZones maps DNS zones to the nameservers that node-local-dns forwards their queries to
+	UpstreamZones map[string][]string `json:"upstreamZones,omitempty"`
 }
 
 // ExternalDNSConfig are options of the dns-controller
@@ -505,27 +648,48 @@ type ExternalDNSConfig struct {
 	WatchIngress *bool `json:"watchIngress,omitempty"`
 	// WatchNamespace is namespace to watch, defaults to all (use to control whom can creates dns entries)
 	WatchNamespace string `json:"watchNamespace,omitempty"`
+	// Provider determines which controller manages the DNS records of Services and Ingresses: dns-controller (default) or external-dns.
+	// The records of the API are always managed by dns-controller.
+	Provider ExternalDNSProvider `json:"provider,omitempty"`
+	// DomainFilters limits external-dns to the zones of the given domains
+	DomainFilters []string `json:"domainFilters,omitempty"`
+	// ZoneIDFilters limits external-dns to the zones with the given IDs. On AWS, its permissions are scoped to these zones, or to the zone of the cluster if not set.
+	ZoneIDFilters []string `json:"zoneIDFilters,omitempty"`
+	// TXTOwnerID identifies the records owned by external-dns in this cluster. Default: the name of the cluster.
+	TXTOwnerID string `json:"txtOwnerID,omitempty"`
+	// Policy is how external-dns synchronizes records: sync, upsert-only or create-only. Default upsert-only.
+	Policy string `json:"policy,omitempty"`
 }
 
+// ExternalDNSProvider is the controller managing the DNS records of Services and Ingresses
+type ExternalDNSProvider string
+
+const (
+	ExternalDNSProviderDNSController ExternalDNSProvider = "dns-controller"
+	ExternalDNSProviderExternalDNS   ExternalDNSProvider = "external-dns"
+)
+
 // EtcdProviderType describes etcd cluster provisioning types (Standalone, Manager)
 type EtcdProviderType string
 
 const (
-	EtcdProviderTypeManager EtcdProviderType = "Manager"
-	EtcdProviderTypeLegacy  EtcdProviderType = "Legacy"
+	EtcdProviderTypeManager  EtcdProviderType = "Manager"
+	EtcdProviderTypeLegacy   EtcdProviderType = "Legacy"
+	EtcdProviderTypeExternal EtcdProviderType = "External"
 )
 
 var SupportedEtcdProviderTypes = []string{
 	string(EtcdProviderTypeManager),
 	string(EtcdProviderTypeLegacy),
+	string(EtcdProviderTypeExternal),
 }
 
 // EtcdClusterSpec is the etcd cluster specification
 type EtcdClusterSpec struct {
 	// Name is the name of the etcd cluster (main, events etc)
 	Name string `json:"name,omitempty"`
-	// Provider is the provider used to run etcd: Manager, Legacy.
-	// Defaults to Manager.
+	// Provider is the provider used to run etcd: Manager, Legacy, External.
+	// Defaults to Manager, or External if external is set.
 	Provider EtcdProviderType `json:"provider,omitempty"`
 	// Members stores the configurations for each member of the cluster (including the data volume)
 	Members []EtcdMemberSpec `json:"etcdMembers,omitempty"`
@@ -545,10 +709,45 @@ type EtcdClusterSpec struct {
 	Backups *EtcdBackupSpec `json:"backups,omitempty"`
 	// Manager describes the manager configuration
 	Manager *EtcdManagerSpec `json:"manager,omitempty"`
+	// External points the API server at an etcd cluster that is not managed by kOps.
+	External *EtcdExternalSpec `json:"external,omitempty"`
 	// MemoryRequest specifies the memory requests of each etcd container in the cluster.
 	MemoryRequest *resource.Quantity `json:"memoryRequest,omitempty"`
 	// CPURequest specifies the cpu requests of each etcd container in the cluster.
 	CPURequest *resource.Quantity `json:"cpuRequest,omitempty"`
+	// QuotaBackendBytes is the size limit of the etcd database. When it is reached, etcd raises a no space alarm
+	// and only accepts reads and deletes.
+	QuotaBackendBytes *resource.Quantity `json:"quotaBackendBytes,omitempty"`
+	// AutoCompaction configures how etcd discards the history of keys.
+	AutoCompaction *EtcdAutoCompactionSpec `json:"autoCompaction,omitempty"`
+	// Defragmentation configures the scheduled defragmentation of the etcd members by etcd-manager.
+	Defragmentation *EtcdDefragmentationSpec `json:"defragmentation,omitempty"`
+	// Metrics exposes the etcd metrics endpoint on the control plane nodes.
+	Metrics *EtcdMetricsSpec `json:"metrics,omitempty"`
+}
+
+// EtcdAutoCompactionSpec describes how etcd compacts the history of keys
+type EtcdAutoCompactionSpec struct {
+	// Mode is the compaction mode: periodic or revision. Defaults to periodic.
+	Mode string `json:"mode,omitempty"`
+	// Retention is how much history is kept: a duration such as 8h for periodic compaction,
+	// or a number of revisions for revision compaction.
+	Retention string `json:"retention,omitempty"`
+}
+
+// EtcdDefragmentationSpec describes when etcd-manager defragments the etcd members
+type EtcdDefragmentationSpec struct {
+	// Interval is the time between defragmentations. The members are defragmented one at a time.
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// EtcdMetricsSpec describes the metrics endpoint of etcd
+type EtcdMetricsSpec struct {
+	// Type is the level of detail of the metrics: basic or extensive. Defaults to basic.
+	Type string `json:"type,omitempty"`
+	// Port is the port where etcd serves metrics over plain http, without requiring a client certificate.
+	// Defaults to 8081 for the main, 8082 for the events and 8083 for the cilium etcd cluster.
+	Port *int32 `json:"port,omitempty"`
 }
 
 // EtcdBackupSpec describes how we want to do backups of etcd
@@ -575,6 +774,19 @@ type EtcdManagerSpec struct {
 	LogLevel *int32 `json:"logLevel,omitempty"`
 }
 
+// IsExternal returns true if the etcd cluster is not managed by kOps
+func (e *EtcdClusterSpec) IsExternal() bool {
+	return e.Provider == EtcdProviderTypeExternal || (e.Provider == "" && e.External != nil)
+}
+
+// EtcdExternalSpec describes an etcd cluster that is managed outside of kOps
+type EtcdExternalSpec struct {
+	// Endpoints are the client URLs of the members of the etcd cluster, e.g. https://etcd-a.example.com:2379.
+	// For https endpoints, the API server trusts the certificates in the etcd-external-ca keyset and
+	// authenticates with the keypair in the etcd-external-client keyset.
+	Endpoints []string `json:"endpoints,omitempty"`
+}
+
 // EtcdMemberSpec is a specification for a etcd member
 type EtcdMemberSpec struct {
 	// Name is the name of the member within the etcd cluster
@@ -668,10 +880,50 @@ func (t *TargetSpec) IsEmpty() bool {
 type TerraformSpec struct {
 	// ProviderExtraConfig contains key/value pairs to add to the rendered terraform "provider" block
 	ProviderExtraConfig *map[string]string `json:"providerExtraConfig,omitempty"`
+	// ProviderVersions overrides the version constraints of the providers in the "required_providers" block, by provider name
+	ProviderVersions map[string]string `json:"providerVersions,omitempty"`
+	// Backend configures where Terraform stores the state of the cluster's resources
+	Backend *TerraformBackendSpec `json:"backend,omitempty"`
+	// SplitFiles writes the network, IAM and instance resources to separate files instead of kubernetes.tf
+	SplitFiles *bool `json:"splitFiles,omitempty"`
+	// ImportExisting looks up the resources that already exist, emitting import blocks for those kOps manages
+	// and data sources for those shared with the cluster, so that Terraform adopts them instead of creating duplicates.
+	// Import blocks require Terraform 1.5.
+	ImportExisting *bool `json:"importExisting,omitempty"`
 }
 
 func (t *TerraformSpec) IsEmpty() bool {
-	return t.ProviderExtraConfig == nil
+	return t.ProviderExtraConfig == nil && len(t.ProviderVersions) == 0 && t.Backend == nil && t.SplitFiles == nil && t.ImportExisting == nil
+}
+
+// TerraformBackendSpec configures the Terraform backend; exactly one backend must be set
+type TerraformBackendSpec struct {
+	// S3 stores the state in an S3 bucket
+	S3 *TerraformS3BackendSpec `json:"s3,omitempty"`
+	// GCS stores the state in a Google Cloud Storage bucket
+	GCS *TerraformGCSBackendSpec `json:"gcs,omitempty"`
+}
+
+// TerraformS3BackendSpec configures the Terraform s3 backend
+type TerraformS3BackendSpec struct {
+	// Bucket is the name of the bucket
+	Bucket string `json:"bucket,omitempty"`
+	// Key is the path of the state in the bucket. Defaults to <cluster name>/terraform.tfstate
+	Key string `json:"key,omitempty"`
+	// Region is the region of the bucket. Defaults to the region of the cluster
+	Region string `json:"region,omitempty"`
+	// DynamoDBTable is the name of the DynamoDB table used to lock the state
+	DynamoDBTable string `json:"dynamodbTable,omitempty"`
+	// Encrypt enables server side encryption of the state
+	Encrypt *bool `json:"encrypt,omitempty"`
+}
+
+// TerraformGCSBackendSpec configures the Terraform gcs backend, which locks the state natively
+type TerraformGCSBackendSpec struct {
+	// Bucket is the name of the bucket
+	Bucket string `json:"bucket,omitempty"`
+	// Prefix is the path of the state in the bucket. Defaults to the cluster name
+	Prefix string `json:"prefix,omitempty"`
 }
 
 // FillDefaults populates default values.
@@ -737,6 +989,8 @@ func (c *Cluster) fillClusterSpecNetworkingSpec() {
 		// OK
 	} else if c.Spec.Networking.GCE != nil {
 		// OK
+	} else if c.Spec.Networking.Antrea != nil {
+		// OK
 	} else {
 		// No networking model selected; choose Kubenet
 		c.Spec.Networking.Kubenet = &KubenetNetworkingSpec{}
@@ -817,6 +1071,10 @@ type GossipConfig struct {
 	Secondary *GossipConfigSecondary `json:"secondary,omitempty"`
 }
 
+// GossipProtocolKopsController is the gossip protocol where kops-controller serves the discovery records,
+// which dns-controller keeps in etcd, in place of a gossip mesh.
+const GossipProtocolKopsController = "kops-controller"
+
 type GossipConfigSecondary struct {
 	Protocol *string `json:"protocol,omitempty"`
 	Listen   *string `json:"listen,omitempty"`
@@ -867,6 +1125,11 @@ type RollingUpdate struct {
 	// nodes.
 	// +optional
 	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+	// DrainTimeout is the maximum time to wait for the pods of a node to be evicted, for example
+	// when a PodDisruptionBudget does not allow the eviction. The rolling update fails once it is
+	// exceeded. Defaults to waiting indefinitely.
+	// +optional
+	DrainTimeout *metav1.Duration `json:"drainTimeout,omitempty"`
 }
 
 type PackagesConfig struct {
@@ -878,6 +1141,10 @@ type PackagesConfig struct {
 	UrlAmd64 *string `json:"urlAmd64,omitempty"`
 	// UrlArm64 overrides the URL for the ARM64 package.
 	UrlArm64 *string `json:"urlArm64,omitempty"`
+	// HashWindows sets the hash for the Windows AMD64 package.
+	HashWindows *string `json:"hashWindows,omitempty"`
+	// UrlWindows sets the URL for the Windows AMD64 package.
+	UrlWindows *string `json:"urlWindows,omitempty"`
 }
 
 type WarmPoolSpec struct {
@@ -925,3 +1192,188 @@ func (in *WarmPoolSpec) ResolveDefaults(ig *InstanceGroup) *WarmPoolSpec {
 	}
 	return &spec
 }
+
+// SecretStoreEncryptionSpec configures envelope encryption of the secret store and keystore
+type SecretStoreEncryptionSpec struct {
+	// KMSKey is the KMS key that encrypts the data key of each secret and key: the ARN of an AWS KMS key or alias,
+	// the resource name of a GCP KMS key (projects/<project>/locations/<location>/keyRings/<keyring>/cryptoKeys/<key>),
+	// or the URL of an Azure Key Vault key (https://<vault>.vault.azure.net/keys/<key>).
+	// After changing the key, run `kops toolbox reencrypt` to re-encrypt the existing secrets and keys with it.
+	KMSKey string `json:"kmsKey,omitempty"`
+}
+
+// NodeCertificatesSpec configures the certificates kops-controller issues to nodes when they bootstrap
+type NodeCertificatesSpec struct {
+	// KubeletValidity is how long the kubelet client and serving certificates issued to nodes are valid for.
+	// When set, kubelets renew their certificates before they expire and kops-controller approves the
+	// renewal requests, so that a leaked node credential is only usable for a bounded time.
+	KubeletValidity *metav1.Duration `json:"kubeletValidity,omitempty"`
+}
+
+// NodeBootstrapSpec configures how nodes prove their identity to kops-controller when they bootstrap
+type NodeBootstrapSpec struct {
+	// UseKopsController makes nodes obtain their credentials from kops-controller on clouds where this is not the default.
+	// Only GCE is supported, where nodes prove their identity with a token signed by Google for their instance.
+	// Nodes of AWS clusters always bootstrap through kops-controller.
+	UseKopsController *bool `json:"useKopsController,omitempty"`
+	// JoinTokens allows nodes to prove their identity with a join token issued for their instance group by
+	// kops toolbox join-token, for machines whose cloud identity kops-controller cannot verify.
+	JoinTokens *bool `json:"joinTokens,omitempty"`
+}
+
+// InstanceGroupScalingSpec configures the kops-controller API for resizing instance groups from within the cluster
+type InstanceGroupScalingSpec struct {
+	// Enabled serves the API, which lets clients authorized to patch instancegroups/scale in the kops.k8s.io
+	// API group change the minimum, maximum and target sizes of node instance groups. Only AWS is supported.
+	Enabled *bool `json:"enabled,omitempty"`
+	// StateStoreLockTable is the DynamoDB table that locks the S3 state store, as named by KOPS_STATE_S3_LOCK_TABLE.
+	// kops-controller takes the lock of the cluster in it while changing an instance group. Required if the state store is on S3.
+	StateStoreLockTable string `json:"stateStoreLockTable,omitempty"`
+}
+
+// AuditSpec configures audit logging of the requests made to the API server
+type AuditSpec struct {
+	// Policy is the audit policy, an audit.k8s.io Policy document.
+	// Defaults to a policy that logs the metadata of all requests.
+	Policy string `json:"policy,omitempty"`
+	// Webhook sends audit events to a remote API
+	Webhook *AuditWebhookSpec `json:"webhook,omitempty"`
+	// LogShipping runs a fluent-bit addon on the control plane nodes that ships the audit log to object storage or a log service
+	LogShipping *AuditLogShippingSpec `json:"logShipping,omitempty"`
+}
+
+// AuditWebhookSpec configures the audit webhook backend of the API server
+type AuditWebhookSpec struct {
+	// Server is the URL audit events are posted to
+	Server string `json:"server,omitempty"`
+	// CertificateAuthority is the PEM encoded CA bundle used to verify the server. Defaults to the system trust store.
+	CertificateAuthority string `json:"certificateAuthority,omitempty"`
+	// Mode is the strategy for sending audit events: batch (the default) or blocking
+	Mode string `json:"mode,omitempty"`
+}
+
+// AuditLogShippingSpec configures where the audit log is shipped to. Exactly one destination must be set.
+type AuditLogShippingSpec struct {
+	// S3 ships the audit log to an S3 bucket
+	S3 *AuditLogS3Spec `json:"s3,omitempty"`
+	// CloudWatch ships the audit log to a CloudWatch Logs log group
+	CloudWatch *AuditLogCloudWatchSpec `json:"cloudWatch,omitempty"`
+	// GCS ships the audit log to a GCS bucket
+	GCS *AuditLogGCSSpec `json:"gcs,omitempty"`
+}
+
+// AuditLogS3Spec ships the audit log to an S3 bucket, using the IAM role of the control plane nodes
+type AuditLogS3Spec struct {
+	// Bucket is the name of the S3 bucket
+	Bucket string `json:"bucket,omitempty"`
+	// Prefix is the key prefix of the uploaded objects
+	Prefix string `json:"prefix,omitempty"`
+	// Region is the region of the bucket. Defaults to the region of the cluster.
+	Region string `json:"region,omitempty"`
+}
+
+// AuditLogCloudWatchSpec ships the audit log to a CloudWatch Logs log group, using the IAM role of the control plane nodes
+type AuditLogCloudWatchSpec struct {
+	// LogGroup is the name of the log group, which is created if it does not exist
+	LogGroup string `json:"logGroup,omitempty"`
+	// Region is the region of the log group. Defaults to the region of the cluster.
+	Region string `json:"region,omitempty"`
+}
+
+// AuditLogGCSSpec ships the audit log to a GCS bucket through its S3 compatible API.
+// The HMAC key used to write to the bucket is read from the kube-system/audit-log-shipping-gcs Secret.
+type AuditLogGCSSpec struct {
+	// Bucket is the name of the GCS bucket
+	Bucket string `json:"bucket,omitempty"`
+	// Prefix is the name prefix of the uploaded objects
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// LoggingSpec configures a fluent-bit addon that ships the logs of the pods and of the node services
+// to one or more sinks
+type LoggingSpec struct {
+	// Enabled enables the log shipping addon
+	Enabled *bool `json:"enabled,omitempty"`
+	// Image is the fluent-bit image. Default: fluent/fluent-bit:1.8.3
+	Image string `json:"image,omitempty"`
+	// CloudWatch ships the logs to a CloudWatch Logs log group
+	CloudWatch *LoggingCloudWatchSpec `json:"cloudWatch,omitempty"`
+	// S3 ships the logs to an S3 bucket
+	S3 *LoggingS3Spec `json:"s3,omitempty"`
+	// Stackdriver ships the logs to Cloud Logging, using the service account of the instances
+	Stackdriver *LoggingStackdriverSpec `json:"stackdriver,omitempty"`
+	// Loki ships the logs to a Loki server
+	Loki *LoggingLokiSpec `json:"loki,omitempty"`
+}
+
+// LoggingCloudWatchSpec ships the logs to a CloudWatch Logs log group
+type LoggingCloudWatchSpec struct {
+	// LogGroup is the name of the log group, which is created if it does not exist
+	LogGroup string `json:"logGroup,omitempty"`
+	// Region is the region of the log group. Defaults to the region of the cluster.
+	Region string `json:"region,omitempty"`
+}
+
+// LoggingS3Spec ships the logs to an S3 bucket
+type LoggingS3Spec struct {
+	// Bucket is the name of the S3 bucket
+	Bucket string `json:"bucket,omitempty"`
+	// Prefix is the key prefix of the uploaded objects
+	Prefix string `json:"prefix,omitempty"`
+	// Region is the region of the bucket. Defaults to the region of the cluster.
+	Region string `json:"region,omitempty"`
+}
+
+// LoggingStackdriverSpec ships the logs to Cloud Logging
+type LoggingStackdriverSpec struct {
+	// ProjectID is the project the logs are written to. Defaults to the project of the cluster.
+	ProjectID string `json:"projectID,omitempty"`
+}
+
+// LoggingLokiSpec ships the logs to a Loki server
+type LoggingLokiSpec struct {
+	// URL is the URL of the push API of the Loki server, e.g. https://loki.example.com:3100/loki/api/v1/push
+	URL string `json:"url,omitempty"`
+	// TenantID is the tenant the logs are written to, when Loki runs in multi-tenant mode
+	TenantID string `json:"tenantID,omitempty"`
+}
+
+// HardeningProfileCIS configures the cluster to meet the CIS Kubernetes Benchmark
+const HardeningProfileCIS = "cis"
+
+// HardeningSpec configures the hardening profile of the cluster
+type HardeningSpec struct {
+	// Profile is the hardening profile: cis configures the cluster to meet the CIS Kubernetes Benchmark
+	Profile string `json:"profile,omitempty"`
+}
+
+// EncryptionAtRestSpec configures encryption of the resources the API server stores in etcd with a KMS provider
+type EncryptionAtRestSpec struct {
+	// KMSKey is the ARN of the AWS KMS key or alias that encrypts the resources the API server writes
+	KMSKey string `json:"kmsKey,omitempty"`
+	// PreviousKMSKeys are the ARNs of KMS keys that stored resources may still be encrypted with.
+	// They are only used for decryption, until `kops toolbox reencrypt --resources` has re-encrypted the resources with KMSKey.
+	PreviousKMSKeys []string `json:"previousKMSKeys,omitempty"`
+	// Resources are the resources to encrypt. Defaults to secrets.
+	Resources []string `json:"resources,omitempty"`
+	// Image is the aws-encryption-provider image.
+	Image string `json:"image,omitempty"`
+}
+
+// PodSecuritySpec configures the PodSecurity admission plugin for namespaces without pod-security.kubernetes.io labels
+type PodSecuritySpec struct {
+	// Enforce is the Pod Security Standard level that pods must meet: privileged, baseline or restricted. Default privileged
+	Enforce string `json:"enforce,omitempty"`
+	// Audit is the level above which violations are recorded in the audit log. Default privileged
+	Audit string `json:"audit,omitempty"`
+	// Warn is the level above which violations are returned to users as warnings. Default privileged
+	Warn string `json:"warn,omitempty"`
+	// Version is the Kubernetes minor version of the Pod Security Standards to apply, such as v1.22. Default latest
+	Version string `json:"version,omitempty"`
+	// ExemptNamespaces are namespaces the policies do not apply to. kube-system is always exempt.
+	ExemptNamespaces []string `json:"exemptNamespaces,omitempty"`
+	// ExemptUsernames are the users whose requests the policies do not apply to
+	ExemptUsernames []string `json:"exemptUsernames,omitempty"`
+	// ExemptRuntimeClasses are the runtime classes of pods the policies do not apply to
+	ExemptRuntimeClasses []string `json:"exemptRuntimeClasses,omitempty"`
+}
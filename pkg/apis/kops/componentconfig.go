@@ -217,6 +217,42 @@ type KubeletConfigSpec struct {
 	ContainerLogMaxFiles *int32 `json:"containerLogMaxFiles,omitempty" flag:"container-log-max-files"`
 	// EnableCadvisorJsonEndpoints enables cAdvisor json `/spec` and `/stats/*` endpoints. Defaults to False.
 	EnableCadvisorJsonEndpoints *bool `json:"enableCadvisorJsonEndpoints,omitempty" flag:"enable-cadvisor-json-endpoints"`
+	// MemorySwapBehavior is how workloads may use swap on nodes with swap: LimitedSwap or UnlimitedSwap.
+	// It is written to the kubelet configuration file.
+	MemorySwapBehavior string `json:"memorySwapBehavior,omitempty"`
+	// MemoryQoS enables the MemoryQoS feature gate, which uses the cgroup v2 memory controller to protect and throttle container memory.
+	MemoryQoS *bool `json:"memoryQoS,omitempty"`
+	// MemoryManagerPolicy is the policy of the memory manager: None or Static.
+	// It is written to the kubelet configuration file.
+	MemoryManagerPolicy string `json:"memoryManagerPolicy,omitempty"`
+	// ReservedMemory is the memory reserved for the system on each NUMA node, which the Static memory manager policy requires.
+	// It is written to the kubelet configuration file.
+	ReservedMemory []KubeletReservedMemory `json:"reservedMemory,omitempty"`
+	// ShutdownGracePeriod is how long the node delays its shutdown to terminate its pods, which enables graceful node shutdown.
+	// It is written to the kubelet configuration file.
+	ShutdownGracePeriod *metav1.Duration `json:"shutdownGracePeriod,omitempty"`
+	// ShutdownGracePeriodCriticalPods is the part of ShutdownGracePeriod that is reserved for terminating critical pods.
+	// It is written to the kubelet configuration file.
+	ShutdownGracePeriodCriticalPods *metav1.Duration `json:"shutdownGracePeriodCriticalPods,omitempty"`
+	// ShutdownGracePeriodByPodPriority sets the shutdown grace period of pods by their priority, in place of ShutdownGracePeriod.
+	// It is written to the kubelet configuration file.
+	ShutdownGracePeriodByPodPriority []KubeletShutdownGracePeriodByPodPriority `json:"shutdownGracePeriodByPodPriority,omitempty"`
+}
+
+// KubeletReservedMemory is the memory reserved for the system on a NUMA node
+type KubeletReservedMemory struct {
+	// NUMANode is the index of the NUMA node.
+	NUMANode int32 `json:"numaNode"`
+	// Limits is the amount reserved of each type of memory, e.g. memory or hugepages-2Mi.
+	Limits map[string]resource.Quantity `json:"limits"`
+}
+
+// KubeletShutdownGracePeriodByPodPriority is the shutdown grace period of the pods with a priority
+type KubeletShutdownGracePeriodByPodPriority struct {
+	// Priority is the lowest priority of the pods this grace period applies to.
+	Priority int32 `json:"priority"`
+	// ShutdownGracePeriodSeconds is the shutdown grace period of these pods, in seconds.
+	ShutdownGracePeriodSeconds int64 `json:"shutdownGracePeriodSeconds"`
 }
 
 // KubeProxyConfig defines the configuration for a proxy
@@ -405,6 +441,8 @@ type KubeAPIServerConfig struct {
 	AuditWebhookInitialBackoff *metav1.Duration `json:"auditWebhookInitialBackoff,omitempty" flag:"audit-webhook-initial-backoff"`
 	// AuditWebhookMode is Strategy for sending audit events. Blocking indicates sending events should block server responses. Batch causes the backend to buffer and write events asynchronously. Known modes are batch,blocking. (default "batch")
 	AuditWebhookMode string `json:"auditWebhookMode,omitempty" flag:"audit-webhook-mode"`
+	// AuthenticationConfigFile is the path to a structured AuthenticationConfiguration file
+	AuthenticationConfigFile string `json:"authenticationConfigFile,omitempty" flag:"authentication-config"`
 	// File with webhook configuration for token authentication in kubeconfig format. The API server will query the remote service to determine authentication for bearer tokens.
 	AuthenticationTokenWebhookConfigFile *string `json:"authenticationTokenWebhookConfigFile,omitempty" flag:"authentication-token-webhook-config-file"`
 	// The duration to cache responses from the webhook token authenticator. Default is 2m. (default 2m0s)
@@ -730,6 +768,9 @@ type OpenstackLoadbalancerConfig struct {
 	FloatingSubnet    *string `json:"floatingSubnet,omitempty"`
 	SubnetID          *string `json:"subnetID,omitempty"`
 	ManageSecGroups   *bool   `json:"manageSecurityGroups,omitempty"`
+	// FloatingIP is a pre-existing floating IP address to attach to the API load balancer,
+	// instead of allocating a new one.
+	FloatingIP *string `json:"floatingIP,omitempty"`
 }
 
 type OpenstackBlockStorageConfig struct {
@@ -894,7 +935,9 @@ type ClusterAutoscalerConfig struct {
 	// Default: false
 	Enabled *bool `json:"enabled,omitempty"`
 	// Expander determines the strategy for which instance group gets expanded.
-	// Supported values: least-waste, most-pods, random.
+	// Supported values: least-waste, most-pods, random, priority.
+	// The priorities used by the priority expander are taken from the instance group annotation
+	// cluster-autoscaler.kops.k8s.io/priority.
 	// Default: least-waste
 	Expander *string `json:"expander,omitempty"`
 	// BalanceSimilarNodeGroups makes cluster autoscaler treat similar node groups as one.
@@ -939,6 +982,16 @@ type MetricsServerConfig struct {
 	Insecure *bool `json:"insecure,omitempty"`
 }
 
+// KubeStateMetricsConfig determines the kube-state-metrics configuration.
+type KubeStateMetricsConfig struct {
+	// Enabled enables kube-state-metrics.
+	// Default: false
+	Enabled *bool `json:"enabled,omitempty"`
+	// Image is the docker container used.
+	// Default: the latest supported image for the specified kubernetes version.
+	Image *string `json:"image,omitempty"`
+}
+
 // CertManagerConfig determines the cert manager configuration.
 type CertManagerConfig struct {
 	// Enabled enables the cert manager.
@@ -17,6 +17,7 @@ limitations under the License.
 package kops
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -25,6 +26,12 @@ const (
 	LabelClusterName = "kops.k8s.io/cluster"
 	// NodeLabelInstanceGroup is a node label set to the name of the instance group
 	NodeLabelInstanceGroup = "kops.k8s.io/instancegroup"
+	// AnnotationClusterAutoscalerPriority is an instance group annotation setting its priority for the priority
+	// expander of cluster autoscaler. Instance groups with higher priorities are expanded first.
+	AnnotationClusterAutoscalerPriority = "cluster-autoscaler.kops.k8s.io/priority"
+	// AnnotationClusterAutoscalerScaleDownDisabled is an instance group annotation that, when "true",
+	// stops cluster autoscaler from removing the nodes of the instance group.
+	AnnotationClusterAutoscalerScaleDownDisabled = "cluster-autoscaler.kops.k8s.io/scale-down-disabled"
 )
 
 // +genclient
@@ -84,6 +91,30 @@ var (
 	SupportedFilesystems = []string{BtfsFilesystem, Ext4Filesystem, XFSFilesystem}
 )
 
+const (
+	// InstanceStorageModeRAID0 stripes the instance store devices into a single volume
+	InstanceStorageModeRAID0 = "RAID0"
+	// InstanceStorageModeLVM creates a logical volume for each path on the instance store devices
+	InstanceStorageModeLVM = "LVM"
+)
+
+var (
+	// SupportedInstanceStorageModes is a list of supported instance store modes
+	SupportedInstanceStorageModes = []string{InstanceStorageModeRAID0, InstanceStorageModeLVM}
+)
+
+const (
+	// OperatingSystemLinux indicates Linux instances
+	OperatingSystemLinux = "linux"
+	// OperatingSystemWindows indicates Windows Server instances
+	OperatingSystemWindows = "windows"
+)
+
+var (
+	// SupportedOperatingSystems is a list of supported instance operating systems
+	SupportedOperatingSystems = []string{OperatingSystemLinux, OperatingSystemWindows}
+)
+
 // InstanceGroupSpec is the specification for an InstanceGroup
 type InstanceGroupSpec struct {
 	// Type determines the role of instances in this instance group: masters or nodes
@@ -184,6 +215,43 @@ type InstanceGroupSpec struct {
 	UpdatePolicy *string `json:"updatePolicy,omitempty"`
 	// WarmPool specifies a pool of pre-warmed instances for later use (AWS only).
 	WarmPool *WarmPoolSpec `json:"warmPool,omitempty"`
+	// ShieldedInstanceConfig configures the Shielded VM options of the instances (GCE only).
+	ShieldedInstanceConfig *ShieldedInstanceConfig `json:"shieldedInstanceConfig,omitempty"`
+	// ConfidentialCompute runs the instances as Confidential VMs, encrypting their memory (GCE only).
+	ConfidentialCompute *bool `json:"confidentialCompute,omitempty"`
+	// EphemeralOSDisk places the root volume on the local storage of the instances instead of a managed disk (Azure only).
+	EphemeralOSDisk *bool `json:"ephemeralOSDisk,omitempty"`
+	// AcceleratedNetworking enables SR-IOV accelerated networking on the instances (Azure only).
+	AcceleratedNetworking *bool `json:"acceleratedNetworking,omitempty"`
+	// Machines is the inventory of pre-existing machines that make up the instance group (metal only).
+	Machines []MetalMachineSpec `json:"machines,omitempty"`
+	// Bootstrap configures how the bootstrap script reaches the instances (AWS and OpenStack only).
+	Bootstrap *InstanceGroupBootstrapSpec `json:"bootstrap,omitempty"`
+	// NvidiaGPU marks the instances as having NVIDIA GPUs, overriding the cluster's containerd.nvidiaGPU settings.
+	NvidiaGPU *NvidiaGPUConfig `json:"nvidiaGPU,omitempty"`
+	// Swap provisions swap space on the instances and allows the kubelet to run with it.
+	Swap *SwapSpec `json:"swap,omitempty"`
+	// NodeTuning configures kernel parameters, kernel modules, huge pages and udev rules, overriding the cluster's nodeTuning settings.
+	NodeTuning *NodeTuningSpec `json:"nodeTuning,omitempty"`
+	// InstanceStorage assembles the local instance store NVMe devices and mounts them (AWS only).
+	InstanceStorage *InstanceStorageSpec `json:"instanceStorage,omitempty"`
+	// GracefulShutdown has the instances shut down gracefully when the cloud provider announces their interruption (AWS and GCE only).
+	GracefulShutdown *GracefulShutdownSpec `json:"gracefulShutdown,omitempty"`
+	// Containerd overrides the cluster's containerd registries, sandbox image, NRI and runtimes on the instances.
+	Containerd *ContainerdConfig `json:"containerd,omitempty"`
+	// ImagePreload pulls images when the instances boot, before the kubelet starts, to shorten the start of their pods.
+	ImagePreload *ImagePreloadSpec `json:"imagePreload,omitempty"`
+	// SecurityModules enforces the SELinux or AppArmor Linux security module on the instances.
+	SecurityModules *SecurityModulesSpec `json:"securityModules,omitempty"`
+	// OperatingSystem is the operating system of the image: linux (the default) or windows.
+	// Windows is only supported for instance groups with role Node (AWS only).
+	OperatingSystem string `json:"operatingSystem,omitempty"`
+	// ScalingSchedules change the size of the instance group at recurring times,
+	// for example to scale it to zero outside of working hours (AWS and GCE only).
+	ScalingSchedules []ScalingSchedule `json:"scalingSchedules,omitempty"`
+	// APIServerEndpoint selects the endpoint that the instances use to reach the API server and kops-controller.
+	// Defaults to the internal API endpoint of the cluster.
+	APIServerEndpoint *APIServerEndpointSpec `json:"apiServerEndpoint,omitempty"`
 }
 
 const (
@@ -198,6 +266,111 @@ const (
 // SpotAllocationStrategies is a collection of supported strategies
 var SpotAllocationStrategies = []string{SpotAllocationStrategyLowestPrices, SpotAllocationStrategyDiversified, SpotAllocationStrategyCapacityOptimized}
 
+const (
+	// BootstrapDeliveryUserData passes the bootstrap script to the instances as user-data
+	BootstrapDeliveryUserData = "UserData"
+	// BootstrapDeliverySSH leaves the user-data of the instances empty; kops toolbox bootstrap runs the
+	// bootstrap script on new instances over SSH
+	BootstrapDeliverySSH = "SSH"
+)
+
+// SwapSpec configures the swap space of the instances
+type SwapSpec struct {
+	// Size is the size of the swap file, e.g. 4Gi.
+	Size *resource.Quantity `json:"size,omitempty"`
+	// Swappiness sets the vm.swappiness kernel parameter, between 0 and 100.
+	Swappiness *int32 `json:"swappiness,omitempty"`
+	// Encrypted places the swap on a dm-crypt device keyed with a random key at each boot.
+	Encrypted *bool `json:"encrypted,omitempty"`
+}
+
+// APIServerEndpointType is the type of endpoint that instances use to reach the API server
+type APIServerEndpointType string
+
+const (
+	// APIServerEndpointTypeInternal uses the internal API name of the cluster
+	APIServerEndpointTypeInternal APIServerEndpointType = "Internal"
+	// APIServerEndpointTypeExternal uses the addresses of the public API load balancer
+	APIServerEndpointTypeExternal APIServerEndpointType = "External"
+	// APIServerEndpointTypeIPs uses a list of IP addresses
+	APIServerEndpointTypeIPs APIServerEndpointType = "IPs"
+)
+
+var SupportedAPIServerEndpointTypes = []string{
+	string(APIServerEndpointTypeInternal),
+	string(APIServerEndpointTypeExternal),
+	string(APIServerEndpointTypeIPs),
+}
+
+// APIServerEndpointSpec configures the endpoint that instances use to reach the API server and kops-controller
+type APIServerEndpointSpec struct {
+	// Type is the type of endpoint, one of Internal, External or IPs.
+	// Internal uses the internal API name, which points to the control plane or the internal load balancer.
+	// External uses the addresses of the public API network load balancer, for instances that can't reach
+	// the internal addresses of the cluster (AWS only).
+	// IPs uses the addresses in ips.
+	Type APIServerEndpointType `json:"type,omitempty"`
+	// IPs are the IP addresses of the API server and kops-controller, when the type is IPs.
+	IPs []string `json:"ips,omitempty"`
+}
+
+// ScalingSchedule changes the size of an instance group at a recurring time
+type ScalingSchedule struct {
+	// Name identifies the schedule within the instance group.
+	Name string `json:"name"`
+	// Schedule is when the schedule starts, in cron format, for example "0 19 * * 1-5" for 19:00 on weekdays.
+	Schedule string `json:"schedule"`
+	// TimeZone is the IANA time zone of the schedule, for example "Europe/Berlin". Defaults to UTC.
+	TimeZone string `json:"timeZone,omitempty"`
+	// MinSize is the minimum number of instances from the start of the schedule.
+	MinSize *int32 `json:"minSize,omitempty"`
+	// MaxSize is the maximum number of instances from the start of the schedule (AWS only).
+	MaxSize *int32 `json:"maxSize,omitempty"`
+	// Duration is how long the schedule lasts; the minimum size only applies while it lasts (GCE only, required).
+	// On AWS, the sizes apply until the start of the next schedule.
+	Duration *metav1.Duration `json:"duration,omitempty"`
+}
+
+// GracefulShutdownSpec configures the graceful shutdown of the instances
+type GracefulShutdownSpec struct {
+	// CloudEvents shuts the instance down when the cloud provider announces that it will be interrupted:
+	// spot interruptions and scheduled events on AWS, preemptions and host maintenance that terminates the
+	// instance on GCE. The kubelet then terminates the pods within their grace periods. Default true.
+	CloudEvents *bool `json:"cloudEvents,omitempty"`
+}
+
+// AppArmorAddonProfile is the AppArmor profile loaded for the kOps managed addons on the instances with securityModules.appArmor
+const AppArmorAddonProfile = "kops-addon"
+
+// SecurityModulesSpec configures the Linux security modules enforced on the instances
+type SecurityModulesSpec struct {
+	// SELinux runs the instances with SELinux enforcing, labels the directories of the kubelet and the control plane
+	// with a kOps policy module, and runs the containers with SELinux labels (RHEL and CentOS only).
+	SELinux *bool `json:"selinux,omitempty"`
+	// AppArmor loads the AppArmor profiles of the kOps managed addons on the instances (Debian and Ubuntu only).
+	AppArmor *bool `json:"appArmor,omitempty"`
+}
+
+// ImagePreloadSpec configures the images pulled when the instances boot
+type ImagePreloadSpec struct {
+	// Images are the images to pull, e.g. docker.io/library/nginx:1.21.
+	Images []string `json:"images,omitempty"`
+	// AddonImages also pulls the images of the addons that run on the instances (default "false").
+	AddonImages *bool `json:"addonImages,omitempty"`
+	// PullThroughCache is the registry, e.g. cache.example.com:5000, to pull the images from instead of their own registry.
+	// The cache serves the images by their full name, e.g. cache.example.com:5000/docker.io/library/nginx:1.21, and they
+	// are tagged with their own name once pulled.
+	PullThroughCache *string `json:"pullThroughCache,omitempty"`
+}
+
+// InstanceGroupBootstrapSpec configures how the bootstrap script, which installs and runs nodeup, reaches the instances
+type InstanceGroupBootstrapSpec struct {
+	// Delivery is UserData or SSH. Default UserData
+	Delivery string `json:"delivery,omitempty"`
+	// SSHUser is the remote user for SSH access to the instances when using SSH delivery. Default root
+	SSHUser string `json:"sshUser,omitempty"`
+}
+
 // InstanceMetadataOptions defines the EC2 instance metadata service options (AWS Only)
 type InstanceMetadataOptions struct {
 	// HTTPPutResponseHopLimit is the desired HTTP PUT response hop limit for instance metadata requests.
@@ -208,6 +381,29 @@ type InstanceMetadataOptions struct {
 	HTTPTokens *string `json:"httpTokens,omitempty"`
 }
 
+// ShieldedInstanceConfig defines the Shielded VM options of an instance (GCE only)
+type ShieldedInstanceConfig struct {
+	// EnableSecureBoot verifies the digital signature of all boot components.
+	EnableSecureBoot *bool `json:"enableSecureBoot,omitempty"`
+	// EnableVTPM enables the virtual Trusted Platform Module.
+	EnableVTPM *bool `json:"enableVtpm,omitempty"`
+	// EnableIntegrityMonitoring enables monitoring of the boot integrity against a baseline.
+	EnableIntegrityMonitoring *bool `json:"enableIntegrityMonitoring,omitempty"`
+}
+
+// MetalMachineSpec defines a pre-existing machine that kOps configures over SSH (metal only)
+type MetalMachineSpec struct {
+	// Name is the hostname of the machine, which is also used as the name of the node.
+	Name string `json:"name,omitempty"`
+	// Address is the IP address or DNS name used to reach the machine over SSH.
+	Address string `json:"address,omitempty"`
+	// SSHUser is the remote user for SSH access to the machine. Defaults to root.
+	SSHUser string `json:"sshUser,omitempty"`
+	// SSHHostKeyFingerprint is the SHA256 fingerprint of the SSH host key of the machine, as printed by ssh-keygen -l.
+	// If not set, the host key must be listed in the known_hosts file of the user running kOps.
+	SSHHostKeyFingerprint string `json:"sshHostKeyFingerprint,omitempty"`
+}
+
 // MixedInstancesPolicySpec defines the specification for an autoscaling group backed by a ec2 fleet
 type MixedInstancesPolicySpec struct {
 	// Instances is a list of instance types which we are willing to run in the EC2 fleet
@@ -263,6 +459,17 @@ type VolumeSpec struct {
 	Type string `json:"type,omitempty"`
 }
 
+// InstanceStorageSpec configures the local instance store NVMe devices of the instances (AWS only)
+type InstanceStorageSpec struct {
+	// Mode is how the devices are combined: RAID0 stripes them into a single volume, shared by the paths,
+	// and LVM creates a logical volume of an equal share for each path. Default RAID0.
+	Mode string `json:"mode,omitempty"`
+	// Filesystem is the filesystem the volumes are formatted with. Default ext4.
+	Filesystem string `json:"filesystem,omitempty"`
+	// Paths are the locations to mount the storage, e.g. /var/lib/containerd.
+	Paths []string `json:"paths,omitempty"`
+}
+
 // VolumeMountSpec defines the specification for mounting a device
 type VolumeMountSpec struct {
 	// Device is the device name to provision and mount
@@ -310,6 +517,70 @@ func (g *InstanceGroup) HasAPIServer() bool {
 	return g.IsMaster() || g.IsAPIServerOnly()
 }
 
+// UsesSSHBootstrap checks if the bootstrap script is run on the instances over SSH rather than passed as user-data
+func (g *InstanceGroup) UsesSSHBootstrap() bool {
+	return g.Spec.Bootstrap != nil && g.Spec.Bootstrap.Delivery == BootstrapDeliverySSH
+}
+
+// NvidiaGPUConfig returns the NVIDIA GPU configuration of the instances, merged with the cluster settings,
+// or nil if the instance group has no NVIDIA GPUs
+func (g *InstanceGroup) NvidiaGPUConfig(cluster *Cluster) *NvidiaGPUConfig {
+	if !g.Spec.NvidiaGPU.IsEnabled() {
+		return nil
+	}
+	if cluster.Spec.Containerd == nil || !cluster.Spec.Containerd.NvidiaGPU.IsEnabled() {
+		return nil
+	}
+
+	config := *cluster.Spec.Containerd.NvidiaGPU
+	if g.Spec.NvidiaGPU.DriverPackage != nil {
+		config.DriverPackage = g.Spec.NvidiaGPU.DriverPackage
+	}
+	if g.Spec.NvidiaGPU.SkipInstall {
+		config.SkipInstall = true
+	}
+	return &config
+}
+
+// ContainerdConfig returns the structured containerd settings of the instances, the instance group
+// registries and runtimes overriding the cluster ones with the same name, or nil if none are set
+func (g *InstanceGroup) ContainerdConfig(cluster *Cluster) *ContainerdConfig {
+	config := &ContainerdConfig{}
+	for _, containerd := range []*ContainerdConfig{cluster.Spec.Containerd, g.Spec.Containerd} {
+		if containerd == nil {
+			continue
+		}
+		for name, registry := range containerd.Registries {
+			if config.Registries == nil {
+				config.Registries = make(map[string]ContainerdRegistryConfig)
+			}
+			config.Registries[name] = registry
+		}
+		for name, runtime := range containerd.Runtimes {
+			if config.Runtimes == nil {
+				config.Runtimes = make(map[string]ContainerdRuntimeConfig)
+			}
+			config.Runtimes[name] = runtime
+		}
+		if containerd.SandboxImage != nil {
+			config.SandboxImage = containerd.SandboxImage
+		}
+		if containerd.NRI != nil {
+			config.NRI = containerd.NRI
+		}
+	}
+
+	if config.Registries == nil && config.Runtimes == nil && config.SandboxImage == nil && config.NRI == nil {
+		return nil
+	}
+	return config
+}
+
+// IsWindows checks if the instances of the instance group run Windows
+func (g *InstanceGroup) IsWindows() bool {
+	return g.Spec.OperatingSystem == OperatingSystemWindows
+}
+
 // IsBastion checks if instanceGroup is a bastion
 func (g *InstanceGroup) IsBastion() bool {
 	switch g.Spec.Role {
@@ -334,3 +605,49 @@ type LoadBalancer struct {
 	// TargetGroupARN to associate with this instance group (AWS ALB/NLB)
 	TargetGroupARN *string `json:"targetGroupArn,omitempty"`
 }
+
+// NodeTuningConfig returns the node tuning of the instances, with the instance group settings overriding the cluster settings
+func (g *InstanceGroup) NodeTuningConfig(cluster *Cluster) *NodeTuningSpec {
+	specs := []*NodeTuningSpec{cluster.Spec.NodeTuning, g.Spec.NodeTuning}
+
+	config := &NodeTuningSpec{}
+	for _, spec := range specs {
+		if spec == nil {
+			continue
+		}
+		for k, v := range spec.Sysctls {
+			if config.Sysctls == nil {
+				config.Sysctls = make(map[string]string)
+			}
+			config.Sysctls[k] = v
+		}
+		for _, module := range spec.KernelModules {
+			found := false
+			for _, m := range config.KernelModules {
+				if m == module {
+					found = true
+				}
+			}
+			if !found {
+				config.KernelModules = append(config.KernelModules, module)
+			}
+		}
+		for k, v := range spec.HugePages {
+			if config.HugePages == nil {
+				config.HugePages = make(map[string]int32)
+			}
+			config.HugePages[k] = v
+		}
+		for k, v := range spec.UdevRules {
+			if config.UdevRules == nil {
+				config.UdevRules = make(map[string]string)
+			}
+			config.UdevRules[k] = v
+		}
+	}
+
+	if config.Sysctls == nil && config.KernelModules == nil && config.HugePages == nil && config.UdevRules == nil {
+		return nil
+	}
+	return config
+}
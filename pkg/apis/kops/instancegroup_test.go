@@ -0,0 +1,100 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kops
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNodeTuningConfig(t *testing.T) {
+	cluster := &Cluster{
+		Spec: ClusterSpec{
+			NodeTuning: &NodeTuningSpec{
+				Sysctls:       map[string]string{"net.core.somaxconn": "1024", "vm.max_map_count": "262144"},
+				KernelModules: []string{"br_netfilter", "ip_vs"},
+			},
+		},
+	}
+	ig := &InstanceGroup{
+		Spec: InstanceGroupSpec{
+			NodeTuning: &NodeTuningSpec{
+				Sysctls:       map[string]string{"net.core.somaxconn": "4096"},
+				KernelModules: []string{"ip_vs", "nvme_tcp"},
+				HugePages:     map[string]int32{"2Mi": 512},
+			},
+		},
+	}
+
+	expected := &NodeTuningSpec{
+		Sysctls:       map[string]string{"net.core.somaxconn": "4096", "vm.max_map_count": "262144"},
+		KernelModules: []string{"br_netfilter", "ip_vs", "nvme_tcp"},
+		HugePages:     map[string]int32{"2Mi": 512},
+	}
+	if actual := ig.NodeTuningConfig(cluster); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected node tuning; expected %+v, got %+v", expected, actual)
+	}
+
+	if actual := (&InstanceGroup{}).NodeTuningConfig(&Cluster{}); actual != nil {
+		t.Errorf("expected no node tuning, got %+v", actual)
+	}
+}
+
+func TestContainerdConfig(t *testing.T) {
+	sandboxImage := "registry.example.com/pause:3.5"
+	cluster := &Cluster{
+		Spec: ClusterSpec{
+			Containerd: &ContainerdConfig{
+				Registries: map[string]ContainerdRegistryConfig{
+					"docker.io": {Mirrors: []string{"https://mirror.example.com"}},
+					"quay.io":   {Mirrors: []string{"https://quay-mirror.example.com"}},
+				},
+				SandboxImage: &sandboxImage,
+			},
+		},
+	}
+	ig := &InstanceGroup{
+		Spec: InstanceGroupSpec{
+			Containerd: &ContainerdConfig{
+				Registries: map[string]ContainerdRegistryConfig{
+					"docker.io": {Mirrors: []string{"https://other-mirror.example.com"}},
+				},
+				Runtimes: map[string]ContainerdRuntimeConfig{
+					"runsc": {Type: "io.containerd.runsc.v1"},
+				},
+			},
+		},
+	}
+
+	expected := &ContainerdConfig{
+		Registries: map[string]ContainerdRegistryConfig{
+			"docker.io": {Mirrors: []string{"https://other-mirror.example.com"}},
+			"quay.io":   {Mirrors: []string{"https://quay-mirror.example.com"}},
+		},
+		Runtimes: map[string]ContainerdRuntimeConfig{
+			"runsc": {Type: "io.containerd.runsc.v1"},
+		},
+		SandboxImage: &sandboxImage,
+	}
+	if actual := ig.ContainerdConfig(cluster); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected containerd settings; expected %+v, got %+v", expected, actual)
+	}
+
+	if actual := (&InstanceGroup{}).ContainerdConfig(&Cluster{}); actual != nil {
+		t.Errorf("expected no containerd settings, got %+v", actual)
+	}
+}
@@ -22,7 +22,33 @@ import (
 
 // UseKopsControllerForNodeBootstrap is true if nodeup should use kops-controller for bootstrapping.
 func UseKopsControllerForNodeBootstrap(cluster *kops.Cluster) bool {
-	return kops.CloudProviderID(cluster.Spec.CloudProvider) == kops.CloudProviderAWS && cluster.IsKubernetesGTE("1.19")
+	switch kops.CloudProviderID(cluster.Spec.CloudProvider) {
+	case kops.CloudProviderAWS:
+		return cluster.IsKubernetesGTE("1.19")
+	case kops.CloudProviderGCE:
+		nodeBootstrap := cluster.Spec.NodeBootstrap
+		return nodeBootstrap != nil && nodeBootstrap.UseKopsController != nil && *nodeBootstrap.UseKopsController && cluster.IsKubernetesGTE("1.19")
+	default:
+		return false
+	}
+}
+
+// UseJoinTokensForNodeBootstrap is true if kops-controller accepts join tokens from bootstrapping nodes.
+func UseJoinTokensForNodeBootstrap(cluster *kops.Cluster) bool {
+	nodeBootstrap := cluster.Spec.NodeBootstrap
+	return UseKopsControllerForNodeBootstrap(cluster) && nodeBootstrap != nil && nodeBootstrap.JoinTokens != nil && *nodeBootstrap.JoinTokens
+}
+
+// UseKopsControllerForInstanceGroupScaling is true if kops-controller serves the API for resizing instance groups.
+func UseKopsControllerForInstanceGroupScaling(cluster *kops.Cluster) bool {
+	scaling := cluster.Spec.InstanceGroupScaling
+	return UseKopsControllerForNodeBootstrap(cluster) && kops.CloudProviderID(cluster.Spec.CloudProvider) == kops.CloudProviderAWS && scaling != nil && scaling.Enabled != nil && *scaling.Enabled
+}
+
+// UseKopsControllerForGossipDiscovery is true if kops-controller serves the discovery records of a gossip cluster.
+func UseKopsControllerForGossipDiscovery(cluster *kops.Cluster) bool {
+	gossip := cluster.Spec.GossipConfig
+	return UseKopsControllerForNodeBootstrap(cluster) && gossip != nil && gossip.Protocol != nil && *gossip.Protocol == kops.GossipProtocolKopsController
 }
 
 // UseCiliumEtcd is true if we are using the Cilium etcd cluster.
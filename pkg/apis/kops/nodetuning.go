@@ -0,0 +1,29 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kops
+
+// NodeTuningSpec configures the kernel of the instances
+type NodeTuningSpec struct {
+	// Sysctls are kernel parameters to set using sysctl(8), keyed by parameter name.
+	Sysctls map[string]string `json:"sysctls,omitempty"`
+	// KernelModules are kernel modules to load at boot.
+	KernelModules []string `json:"kernelModules,omitempty"`
+	// HugePages is the number of huge pages to reserve, keyed by page size, e.g. 2Mi or 1Gi.
+	HugePages map[string]int32 `json:"hugePages,omitempty"`
+	// UdevRules are udev rules to install, keyed by file name, e.g. 90-custom.rules.
+	UdevRules map[string]string `json:"udevRules,omitempty"`
+}
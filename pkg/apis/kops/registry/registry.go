@@ -31,6 +31,10 @@ const (
 	PathClusterCompleted = "cluster.spec"
 	// PathKopsVersionUpdated is the path for the version of kops last used to apply the cluster.
 	PathKopsVersionUpdated = "kops-version.txt"
+	// PathLock is the path of the lock held by operations writing the state of the cluster
+	PathLock = "lock"
+	// PathTaskCache is the path of the state of the cloud tasks recorded by the last update of the cluster
+	PathTaskCache = "taskcache.json"
 )
 
 func ConfigBase(c *api.Cluster) (vfs.Path, error) {
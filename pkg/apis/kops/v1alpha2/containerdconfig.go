@@ -24,12 +24,25 @@ type ContainerdConfig struct {
 	ConfigOverride *string `json:"configOverride,omitempty"`
 	// LogLevel controls the logging details [trace, debug, info, warn, error, fatal, panic] (default "info").
 	LogLevel *string `json:"logLevel,omitempty" flag:"log-level"`
+	// NRI configures the Node Resource Interface, which lets plugins adjust the containers (containerd 1.7 or later).
+	NRI *ContainerdNRIConfig `json:"nri,omitempty"`
+	// NvidiaGPU configures the support for NVIDIA GPUs.
+	NvidiaGPU *NvidiaGPUConfig `json:"nvidiaGPU,omitempty"`
 	// Packages overrides the URL and hash for the packages.
 	Packages *PackagesConfig `json:"packages,omitempty"`
+	// Registries configures the registries by host, e.g. docker.io; nodeup renders them as hosts.toml files
+	// in /etc/containerd/certs.d (containerd 1.5 or later). It cannot be combined with RegistryMirrors.
+	Registries map[string]ContainerdRegistryConfig `json:"registries,omitempty"`
 	// RegistryMirrors is list of image registries
 	RegistryMirrors map[string][]string `json:"registryMirrors,omitempty"`
 	// Root directory for persistent data (default "/var/lib/containerd").
 	Root *string `json:"root,omitempty" flag:"root"`
+	// Runtimes are additional runtime handlers, by the handler name RuntimeClasses refer to, e.g. runsc for gVisor.
+	Runtimes map[string]ContainerdRuntimeConfig `json:"runtimes,omitempty"`
+	// SandboxImage is the image of the pause container of the pods.
+	SandboxImage *string `json:"sandboxImage,omitempty"`
+	// SelinuxEnabled enables SELinux support for the containers run by containerd.
+	SelinuxEnabled *bool `json:"selinuxEnabled,omitempty"`
 	// SkipInstall prevents kOps from installing and modifying containerd in any way (default "false").
 	SkipInstall bool `json:"skipInstall,omitempty"`
 	// State directory for execution state files (default "/run/containerd").
@@ -37,3 +50,41 @@ type ContainerdConfig struct {
 	// Version used to pick the containerd package.
 	Version *string `json:"version,omitempty"`
 }
+
+// ContainerdNRIConfig is the configuration for the Node Resource Interface of containerd
+type ContainerdNRIConfig struct {
+	// Enabled enables NRI; the plugins are run from /opt/nri/plugins (default "false").
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// ContainerdRegistryConfig is the configuration for pulling the images of a registry
+type ContainerdRegistryConfig struct {
+	// Mirrors are the endpoints tried, in order, before the registry itself, e.g. https://mirror.example.com.
+	Mirrors []string `json:"mirrors,omitempty"`
+	// CA is the PEM encoded certificate of the CA that issued the certificates of the registry and its mirrors.
+	CA *string `json:"ca,omitempty"`
+	// SkipVerify disables the verification of the certificates of the registry and its mirrors (default "false").
+	SkipVerify *bool `json:"skipVerify,omitempty"`
+	// Username is the user to authenticate to the registry and its mirrors with.
+	Username *string `json:"username,omitempty"`
+	// Password is the password of the user; it is stored in the cluster spec and on the instances in plain text.
+	Password *string `json:"password,omitempty"`
+}
+
+// ContainerdRuntimeConfig is the configuration for an additional runtime handler of containerd
+type ContainerdRuntimeConfig struct {
+	// Type is the runtime type, e.g. io.containerd.runsc.v1 for gVisor or io.containerd.kata.v2 for Kata Containers.
+	Type string `json:"type,omitempty"`
+	// Options are the options of the runtime, e.g. ConfigPath.
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// NvidiaGPUConfig is the configuration for NVIDIA GPUs
+type NvidiaGPUConfig struct {
+	// Enabled enables the support for NVIDIA GPUs (default "false").
+	Enabled *bool `json:"enabled,omitempty"`
+	// DriverPackage is the OS package that provides the NVIDIA driver.
+	DriverPackage *string `json:"driverPackage,omitempty"`
+	// SkipInstall uses the driver and container toolkit that ship with the image instead of installing them (default "false").
+	SkipInstall bool `json:"skipInstall,omitempty"`
+}
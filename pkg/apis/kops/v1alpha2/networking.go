@@ -35,6 +35,7 @@ type NetworkingSpec struct {
 	Cilium     *CiliumNetworkingSpec     `json:"cilium,omitempty"`
 	LyftVPC    *LyftVPCNetworkingSpec    `json:"lyftvpc,omitempty"`
 	GCE        *GCENetworkingSpec        `json:"gce,omitempty"`
+	Antrea     *AntreaNetworkingSpec     `json:"antrea,omitempty"`
 }
 
 // ClassicNetworkingSpec is the specification of classic networking mode, integrated into kubernetes.
@@ -192,6 +193,9 @@ type CalicoNetworkingSpec struct {
 	// WireguardEnabled enables WireGuard encryption for all on-the-wire pod-to-pod traffic
 	// (default: false)
 	WireguardEnabled bool `json:"wireguardEnabled,omitempty"`
+	// WindowsPackages sets the URL and hash of the Calico for Windows release archive
+	// installed on Windows nodes (urlWindows and hashWindows)
+	WindowsPackages *PackagesConfig `json:"windowsPackages,omitempty"`
 }
 
 // CanalNetworkingSpec declares that we want Canal networking
@@ -531,3 +535,15 @@ type LyftVPCNetworkingSpec struct {
 // GCENetworkingSpec is the specification of GCE's native networking mode, using IP aliases
 type GCENetworkingSpec struct {
 }
+
+// AntreaNetworkingSpec declares that we want Antrea networking
+type AntreaNetworkingSpec struct {
+	// Version overrides the Antrea container image tag.
+	Version string `json:"version,omitempty"`
+	// TunnelType is the tunnel protocol used to encapsulate pod traffic between nodes: geneve (default), vxlan, gre or stt
+	TunnelType string `json:"tunnelType,omitempty"`
+	// MTU is the MTU of the pod interfaces. The default is derived from the node interface and the tunnel overhead.
+	MTU *int32 `json:"mtu,omitempty"`
+	// EnablePrometheusMetrics exposes Prometheus metrics on the Antrea agents and controller
+	EnablePrometheusMetrics bool `json:"enablePrometheusMetrics,omitempty"`
+}
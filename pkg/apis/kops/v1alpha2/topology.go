@@ -37,6 +37,9 @@ type TopologySpec struct {
 
 	// DNS configures options relating to DNS, in particular whether we use a public or a private hosted zone
 	DNS *DNSSpec `json:"dns,omitempty"`
+
+	// InstanceAccess configures how administrators get a shell on the instances of the cluster
+	InstanceAccess *InstanceAccessSpec `json:"instanceAccess,omitempty"`
 }
 
 type DNSSpec struct {
@@ -49,3 +52,19 @@ const (
 	DNSTypePublic  DNSType = "Public"
 	DNSTypePrivate DNSType = "Private"
 )
+
+type InstanceAccessSpec struct {
+	// Type is the mechanism used to get a shell on the instances, one of SSH, SSM or EC2InstanceConnect
+	Type InstanceAccessType `json:"type,omitempty"`
+}
+
+type InstanceAccessType string
+
+const (
+	// InstanceAccessTypeSSH allows direct SSH access from SSHAccess, or through the bastion
+	InstanceAccessTypeSSH InstanceAccessType = "SSH"
+	// InstanceAccessTypeSSM uses AWS Systems Manager Session Manager and does not open port 22
+	InstanceAccessTypeSSM InstanceAccessType = "SSM"
+	// InstanceAccessTypeEC2InstanceConnect uses EC2 Instance Connect to push short-lived SSH keys
+	InstanceAccessTypeEC2InstanceConnect InstanceAccessType = "EC2InstanceConnect"
+)
@@ -33,6 +33,16 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*APIServerEndpointSpec)(nil), (*kops.APIServerEndpointSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_APIServerEndpointSpec_To_kops_APIServerEndpointSpec(a.(*APIServerEndpointSpec), b.(*kops.APIServerEndpointSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.APIServerEndpointSpec)(nil), (*APIServerEndpointSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_APIServerEndpointSpec_To_v1alpha2_APIServerEndpointSpec(a.(*kops.APIServerEndpointSpec), b.(*APIServerEndpointSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*AWSEBSCSIDriver)(nil), (*kops.AWSEBSCSIDriver)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_AWSEBSCSIDriver_To_kops_AWSEBSCSIDriver(a.(*AWSEBSCSIDriver), b.(*kops.AWSEBSCSIDriver), scope)
 	}); err != nil {
@@ -103,6 +113,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*AntreaNetworkingSpec)(nil), (*kops.AntreaNetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_AntreaNetworkingSpec_To_kops_AntreaNetworkingSpec(a.(*AntreaNetworkingSpec), b.(*kops.AntreaNetworkingSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.AntreaNetworkingSpec)(nil), (*AntreaNetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_AntreaNetworkingSpec_To_v1alpha2_AntreaNetworkingSpec(a.(*kops.AntreaNetworkingSpec), b.(*AntreaNetworkingSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Assets)(nil), (*kops.Assets)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_Assets_To_kops_Assets(a.(*Assets), b.(*kops.Assets), scope)
 	}); err != nil {
@@ -113,6 +133,66 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*AuditLogCloudWatchSpec)(nil), (*kops.AuditLogCloudWatchSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_AuditLogCloudWatchSpec_To_kops_AuditLogCloudWatchSpec(a.(*AuditLogCloudWatchSpec), b.(*kops.AuditLogCloudWatchSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.AuditLogCloudWatchSpec)(nil), (*AuditLogCloudWatchSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_AuditLogCloudWatchSpec_To_v1alpha2_AuditLogCloudWatchSpec(a.(*kops.AuditLogCloudWatchSpec), b.(*AuditLogCloudWatchSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*AuditLogGCSSpec)(nil), (*kops.AuditLogGCSSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_AuditLogGCSSpec_To_kops_AuditLogGCSSpec(a.(*AuditLogGCSSpec), b.(*kops.AuditLogGCSSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.AuditLogGCSSpec)(nil), (*AuditLogGCSSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_AuditLogGCSSpec_To_v1alpha2_AuditLogGCSSpec(a.(*kops.AuditLogGCSSpec), b.(*AuditLogGCSSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*AuditLogS3Spec)(nil), (*kops.AuditLogS3Spec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_AuditLogS3Spec_To_kops_AuditLogS3Spec(a.(*AuditLogS3Spec), b.(*kops.AuditLogS3Spec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.AuditLogS3Spec)(nil), (*AuditLogS3Spec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_AuditLogS3Spec_To_v1alpha2_AuditLogS3Spec(a.(*kops.AuditLogS3Spec), b.(*AuditLogS3Spec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*AuditLogShippingSpec)(nil), (*kops.AuditLogShippingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_AuditLogShippingSpec_To_kops_AuditLogShippingSpec(a.(*AuditLogShippingSpec), b.(*kops.AuditLogShippingSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.AuditLogShippingSpec)(nil), (*AuditLogShippingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_AuditLogShippingSpec_To_v1alpha2_AuditLogShippingSpec(a.(*kops.AuditLogShippingSpec), b.(*AuditLogShippingSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*AuditSpec)(nil), (*kops.AuditSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_AuditSpec_To_kops_AuditSpec(a.(*AuditSpec), b.(*kops.AuditSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.AuditSpec)(nil), (*AuditSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_AuditSpec_To_v1alpha2_AuditSpec(a.(*kops.AuditSpec), b.(*AuditSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*AuditWebhookSpec)(nil), (*kops.AuditWebhookSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_AuditWebhookSpec_To_kops_AuditWebhookSpec(a.(*AuditWebhookSpec), b.(*kops.AuditWebhookSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.AuditWebhookSpec)(nil), (*AuditWebhookSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_AuditWebhookSpec_To_v1alpha2_AuditWebhookSpec(a.(*kops.AuditWebhookSpec), b.(*AuditWebhookSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*AuthenticationSpec)(nil), (*kops.AuthenticationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_AuthenticationSpec_To_kops_AuthenticationSpec(a.(*AuthenticationSpec), b.(*kops.AuthenticationSpec), scope)
 	}); err != nil {
@@ -313,6 +393,56 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ContainerdNRIConfig)(nil), (*kops.ContainerdNRIConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ContainerdNRIConfig_To_kops_ContainerdNRIConfig(a.(*ContainerdNRIConfig), b.(*kops.ContainerdNRIConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ContainerdNRIConfig)(nil), (*ContainerdNRIConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ContainerdNRIConfig_To_v1alpha2_ContainerdNRIConfig(a.(*kops.ContainerdNRIConfig), b.(*ContainerdNRIConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ContainerdRegistryConfig)(nil), (*kops.ContainerdRegistryConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ContainerdRegistryConfig_To_kops_ContainerdRegistryConfig(a.(*ContainerdRegistryConfig), b.(*kops.ContainerdRegistryConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ContainerdRegistryConfig)(nil), (*ContainerdRegistryConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ContainerdRegistryConfig_To_v1alpha2_ContainerdRegistryConfig(a.(*kops.ContainerdRegistryConfig), b.(*ContainerdRegistryConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ContainerdRuntimeConfig)(nil), (*kops.ContainerdRuntimeConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ContainerdRuntimeConfig_To_kops_ContainerdRuntimeConfig(a.(*ContainerdRuntimeConfig), b.(*kops.ContainerdRuntimeConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ContainerdRuntimeConfig)(nil), (*ContainerdRuntimeConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ContainerdRuntimeConfig_To_v1alpha2_ContainerdRuntimeConfig(a.(*kops.ContainerdRuntimeConfig), b.(*ContainerdRuntimeConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*CoreDNSRewriteRule)(nil), (*kops.CoreDNSRewriteRule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_CoreDNSRewriteRule_To_kops_CoreDNSRewriteRule(a.(*CoreDNSRewriteRule), b.(*kops.CoreDNSRewriteRule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.CoreDNSRewriteRule)(nil), (*CoreDNSRewriteRule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_CoreDNSRewriteRule_To_v1alpha2_CoreDNSRewriteRule(a.(*kops.CoreDNSRewriteRule), b.(*CoreDNSRewriteRule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*CoreDNSServerBlock)(nil), (*kops.CoreDNSServerBlock)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_CoreDNSServerBlock_To_kops_CoreDNSServerBlock(a.(*CoreDNSServerBlock), b.(*kops.CoreDNSServerBlock), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.CoreDNSServerBlock)(nil), (*CoreDNSServerBlock)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_CoreDNSServerBlock_To_v1alpha2_CoreDNSServerBlock(a.(*kops.CoreDNSServerBlock), b.(*CoreDNSServerBlock), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*DNSAccessSpec)(nil), (*kops.DNSAccessSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_DNSAccessSpec_To_kops_DNSAccessSpec(a.(*DNSAccessSpec), b.(*kops.DNSAccessSpec), scope)
 	}); err != nil {
@@ -323,6 +453,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*DNSAutoscalerConfig)(nil), (*kops.DNSAutoscalerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_DNSAutoscalerConfig_To_kops_DNSAutoscalerConfig(a.(*DNSAutoscalerConfig), b.(*kops.DNSAutoscalerConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.DNSAutoscalerConfig)(nil), (*DNSAutoscalerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_DNSAutoscalerConfig_To_v1alpha2_DNSAutoscalerConfig(a.(*kops.DNSAutoscalerConfig), b.(*DNSAutoscalerConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*DNSControllerGossipConfig)(nil), (*kops.DNSControllerGossipConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_DNSControllerGossipConfig_To_kops_DNSControllerGossipConfig(a.(*DNSControllerGossipConfig), b.(*kops.DNSControllerGossipConfig), scope)
 	}); err != nil {
@@ -373,6 +513,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*EncryptionAtRestSpec)(nil), (*kops.EncryptionAtRestSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_EncryptionAtRestSpec_To_kops_EncryptionAtRestSpec(a.(*EncryptionAtRestSpec), b.(*kops.EncryptionAtRestSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.EncryptionAtRestSpec)(nil), (*EncryptionAtRestSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_EncryptionAtRestSpec_To_v1alpha2_EncryptionAtRestSpec(a.(*kops.EncryptionAtRestSpec), b.(*EncryptionAtRestSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*EnvVar)(nil), (*kops.EnvVar)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_EnvVar_To_kops_EnvVar(a.(*EnvVar), b.(*kops.EnvVar), scope)
 	}); err != nil {
@@ -383,6 +533,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*EtcdAutoCompactionSpec)(nil), (*kops.EtcdAutoCompactionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_EtcdAutoCompactionSpec_To_kops_EtcdAutoCompactionSpec(a.(*EtcdAutoCompactionSpec), b.(*kops.EtcdAutoCompactionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.EtcdAutoCompactionSpec)(nil), (*EtcdAutoCompactionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_EtcdAutoCompactionSpec_To_v1alpha2_EtcdAutoCompactionSpec(a.(*kops.EtcdAutoCompactionSpec), b.(*EtcdAutoCompactionSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*EtcdBackupSpec)(nil), (*kops.EtcdBackupSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_EtcdBackupSpec_To_kops_EtcdBackupSpec(a.(*EtcdBackupSpec), b.(*kops.EtcdBackupSpec), scope)
 	}); err != nil {
@@ -403,6 +563,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*EtcdDefragmentationSpec)(nil), (*kops.EtcdDefragmentationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_EtcdDefragmentationSpec_To_kops_EtcdDefragmentationSpec(a.(*EtcdDefragmentationSpec), b.(*kops.EtcdDefragmentationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.EtcdDefragmentationSpec)(nil), (*EtcdDefragmentationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_EtcdDefragmentationSpec_To_v1alpha2_EtcdDefragmentationSpec(a.(*kops.EtcdDefragmentationSpec), b.(*EtcdDefragmentationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*EtcdExternalSpec)(nil), (*kops.EtcdExternalSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_EtcdExternalSpec_To_kops_EtcdExternalSpec(a.(*EtcdExternalSpec), b.(*kops.EtcdExternalSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.EtcdExternalSpec)(nil), (*EtcdExternalSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_EtcdExternalSpec_To_v1alpha2_EtcdExternalSpec(a.(*kops.EtcdExternalSpec), b.(*EtcdExternalSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*EtcdManagerSpec)(nil), (*kops.EtcdManagerSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_EtcdManagerSpec_To_kops_EtcdManagerSpec(a.(*EtcdManagerSpec), b.(*kops.EtcdManagerSpec), scope)
 	}); err != nil {
@@ -423,6 +603,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*EtcdMetricsSpec)(nil), (*kops.EtcdMetricsSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_EtcdMetricsSpec_To_kops_EtcdMetricsSpec(a.(*EtcdMetricsSpec), b.(*kops.EtcdMetricsSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.EtcdMetricsSpec)(nil), (*EtcdMetricsSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_EtcdMetricsSpec_To_v1alpha2_EtcdMetricsSpec(a.(*kops.EtcdMetricsSpec), b.(*EtcdMetricsSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ExecContainerAction)(nil), (*kops.ExecContainerAction)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_ExecContainerAction_To_kops_ExecContainerAction(a.(*ExecContainerAction), b.(*kops.ExecContainerAction), scope)
 	}); err != nil {
@@ -483,6 +673,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*GatewayAccessSpec)(nil), (*kops.GatewayAccessSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_GatewayAccessSpec_To_kops_GatewayAccessSpec(a.(*GatewayAccessSpec), b.(*kops.GatewayAccessSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.GatewayAccessSpec)(nil), (*GatewayAccessSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_GatewayAccessSpec_To_v1alpha2_GatewayAccessSpec(a.(*kops.GatewayAccessSpec), b.(*GatewayAccessSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*GossipConfig)(nil), (*kops.GossipConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_GossipConfig_To_kops_GossipConfig(a.(*GossipConfig), b.(*kops.GossipConfig), scope)
 	}); err != nil {
@@ -503,6 +703,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*GracefulShutdownSpec)(nil), (*kops.GracefulShutdownSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_GracefulShutdownSpec_To_kops_GracefulShutdownSpec(a.(*GracefulShutdownSpec), b.(*kops.GracefulShutdownSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.GracefulShutdownSpec)(nil), (*GracefulShutdownSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_GracefulShutdownSpec_To_v1alpha2_GracefulShutdownSpec(a.(*kops.GracefulShutdownSpec), b.(*GracefulShutdownSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*HTTPProxy)(nil), (*kops.HTTPProxy)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_HTTPProxy_To_kops_HTTPProxy(a.(*HTTPProxy), b.(*kops.HTTPProxy), scope)
 	}); err != nil {
@@ -513,6 +723,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*HardeningSpec)(nil), (*kops.HardeningSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_HardeningSpec_To_kops_HardeningSpec(a.(*HardeningSpec), b.(*kops.HardeningSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.HardeningSpec)(nil), (*HardeningSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_HardeningSpec_To_v1alpha2_HardeningSpec(a.(*kops.HardeningSpec), b.(*HardeningSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*HookSpec)(nil), (*kops.HookSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_HookSpec_To_kops_HookSpec(a.(*HookSpec), b.(*kops.HookSpec), scope)
 	}); err != nil {
@@ -553,6 +773,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ImagePreloadSpec)(nil), (*kops.ImagePreloadSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ImagePreloadSpec_To_kops_ImagePreloadSpec(a.(*ImagePreloadSpec), b.(*kops.ImagePreloadSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ImagePreloadSpec)(nil), (*ImagePreloadSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ImagePreloadSpec_To_v1alpha2_ImagePreloadSpec(a.(*kops.ImagePreloadSpec), b.(*ImagePreloadSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*InstanceAccessSpec)(nil), (*kops.InstanceAccessSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_InstanceAccessSpec_To_kops_InstanceAccessSpec(a.(*InstanceAccessSpec), b.(*kops.InstanceAccessSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.InstanceAccessSpec)(nil), (*InstanceAccessSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_InstanceAccessSpec_To_v1alpha2_InstanceAccessSpec(a.(*kops.InstanceAccessSpec), b.(*InstanceAccessSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*InstanceGroup)(nil), (*kops.InstanceGroup)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_InstanceGroup_To_kops_InstanceGroup(a.(*InstanceGroup), b.(*kops.InstanceGroup), scope)
 	}); err != nil {
@@ -563,6 +803,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*InstanceGroupBootstrapSpec)(nil), (*kops.InstanceGroupBootstrapSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_InstanceGroupBootstrapSpec_To_kops_InstanceGroupBootstrapSpec(a.(*InstanceGroupBootstrapSpec), b.(*kops.InstanceGroupBootstrapSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.InstanceGroupBootstrapSpec)(nil), (*InstanceGroupBootstrapSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_InstanceGroupBootstrapSpec_To_v1alpha2_InstanceGroupBootstrapSpec(a.(*kops.InstanceGroupBootstrapSpec), b.(*InstanceGroupBootstrapSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*InstanceGroupList)(nil), (*kops.InstanceGroupList)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_InstanceGroupList_To_kops_InstanceGroupList(a.(*InstanceGroupList), b.(*kops.InstanceGroupList), scope)
 	}); err != nil {
@@ -573,6 +823,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*InstanceGroupScalingSpec)(nil), (*kops.InstanceGroupScalingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_InstanceGroupScalingSpec_To_kops_InstanceGroupScalingSpec(a.(*InstanceGroupScalingSpec), b.(*kops.InstanceGroupScalingSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.InstanceGroupScalingSpec)(nil), (*InstanceGroupScalingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_InstanceGroupScalingSpec_To_v1alpha2_InstanceGroupScalingSpec(a.(*kops.InstanceGroupScalingSpec), b.(*InstanceGroupScalingSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*InstanceGroupSpec)(nil), (*kops.InstanceGroupSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_InstanceGroupSpec_To_kops_InstanceGroupSpec(a.(*InstanceGroupSpec), b.(*kops.InstanceGroupSpec), scope)
 	}); err != nil {
@@ -593,6 +853,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*InstanceStorageSpec)(nil), (*kops.InstanceStorageSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_InstanceStorageSpec_To_kops_InstanceStorageSpec(a.(*InstanceStorageSpec), b.(*kops.InstanceStorageSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.InstanceStorageSpec)(nil), (*InstanceStorageSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_InstanceStorageSpec_To_v1alpha2_InstanceStorageSpec(a.(*kops.InstanceStorageSpec), b.(*InstanceStorageSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Keyset)(nil), (*kops.Keyset)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_Keyset_To_kops_Keyset(a.(*Keyset), b.(*kops.Keyset), scope)
 	}); err != nil {
@@ -703,6 +973,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*KubeStateMetricsConfig)(nil), (*kops.KubeStateMetricsConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_KubeStateMetricsConfig_To_kops_KubeStateMetricsConfig(a.(*KubeStateMetricsConfig), b.(*kops.KubeStateMetricsConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.KubeStateMetricsConfig)(nil), (*KubeStateMetricsConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_KubeStateMetricsConfig_To_v1alpha2_KubeStateMetricsConfig(a.(*kops.KubeStateMetricsConfig), b.(*KubeStateMetricsConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*KubeletConfigSpec)(nil), (*kops.KubeletConfigSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_KubeletConfigSpec_To_kops_KubeletConfigSpec(a.(*KubeletConfigSpec), b.(*kops.KubeletConfigSpec), scope)
 	}); err != nil {
@@ -713,6 +993,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*KubeletReservedMemory)(nil), (*kops.KubeletReservedMemory)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_KubeletReservedMemory_To_kops_KubeletReservedMemory(a.(*KubeletReservedMemory), b.(*kops.KubeletReservedMemory), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.KubeletReservedMemory)(nil), (*KubeletReservedMemory)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_KubeletReservedMemory_To_v1alpha2_KubeletReservedMemory(a.(*kops.KubeletReservedMemory), b.(*KubeletReservedMemory), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*KubeletShutdownGracePeriodByPodPriority)(nil), (*kops.KubeletShutdownGracePeriodByPodPriority)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_KubeletShutdownGracePeriodByPodPriority_To_kops_KubeletShutdownGracePeriodByPodPriority(a.(*KubeletShutdownGracePeriodByPodPriority), b.(*kops.KubeletShutdownGracePeriodByPodPriority), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.KubeletShutdownGracePeriodByPodPriority)(nil), (*KubeletShutdownGracePeriodByPodPriority)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_KubeletShutdownGracePeriodByPodPriority_To_v1alpha2_KubeletShutdownGracePeriodByPodPriority(a.(*kops.KubeletShutdownGracePeriodByPodPriority), b.(*KubeletShutdownGracePeriodByPodPriority), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*KubenetNetworkingSpec)(nil), (*kops.KubenetNetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_KubenetNetworkingSpec_To_kops_KubenetNetworkingSpec(a.(*KubenetNetworkingSpec), b.(*kops.KubenetNetworkingSpec), scope)
 	}); err != nil {
@@ -773,6 +1073,56 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*LoggingCloudWatchSpec)(nil), (*kops.LoggingCloudWatchSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_LoggingCloudWatchSpec_To_kops_LoggingCloudWatchSpec(a.(*LoggingCloudWatchSpec), b.(*kops.LoggingCloudWatchSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.LoggingCloudWatchSpec)(nil), (*LoggingCloudWatchSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_LoggingCloudWatchSpec_To_v1alpha2_LoggingCloudWatchSpec(a.(*kops.LoggingCloudWatchSpec), b.(*LoggingCloudWatchSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*LoggingLokiSpec)(nil), (*kops.LoggingLokiSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_LoggingLokiSpec_To_kops_LoggingLokiSpec(a.(*LoggingLokiSpec), b.(*kops.LoggingLokiSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.LoggingLokiSpec)(nil), (*LoggingLokiSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_LoggingLokiSpec_To_v1alpha2_LoggingLokiSpec(a.(*kops.LoggingLokiSpec), b.(*LoggingLokiSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*LoggingS3Spec)(nil), (*kops.LoggingS3Spec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_LoggingS3Spec_To_kops_LoggingS3Spec(a.(*LoggingS3Spec), b.(*kops.LoggingS3Spec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.LoggingS3Spec)(nil), (*LoggingS3Spec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_LoggingS3Spec_To_v1alpha2_LoggingS3Spec(a.(*kops.LoggingS3Spec), b.(*LoggingS3Spec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*LoggingSpec)(nil), (*kops.LoggingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_LoggingSpec_To_kops_LoggingSpec(a.(*LoggingSpec), b.(*kops.LoggingSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.LoggingSpec)(nil), (*LoggingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_LoggingSpec_To_v1alpha2_LoggingSpec(a.(*kops.LoggingSpec), b.(*LoggingSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*LoggingStackdriverSpec)(nil), (*kops.LoggingStackdriverSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_LoggingStackdriverSpec_To_kops_LoggingStackdriverSpec(a.(*LoggingStackdriverSpec), b.(*kops.LoggingStackdriverSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.LoggingStackdriverSpec)(nil), (*LoggingStackdriverSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_LoggingStackdriverSpec_To_v1alpha2_LoggingStackdriverSpec(a.(*kops.LoggingStackdriverSpec), b.(*LoggingStackdriverSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*LyftVPCNetworkingSpec)(nil), (*kops.LyftVPCNetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_LyftVPCNetworkingSpec_To_kops_LyftVPCNetworkingSpec(a.(*LyftVPCNetworkingSpec), b.(*kops.LyftVPCNetworkingSpec), scope)
 	}); err != nil {
@@ -783,6 +1133,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*MetalMachineSpec)(nil), (*kops.MetalMachineSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_MetalMachineSpec_To_kops_MetalMachineSpec(a.(*MetalMachineSpec), b.(*kops.MetalMachineSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.MetalMachineSpec)(nil), (*MetalMachineSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_MetalMachineSpec_To_v1alpha2_MetalMachineSpec(a.(*kops.MetalMachineSpec), b.(*MetalMachineSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*MetricsServerConfig)(nil), (*kops.MetricsServerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_MetricsServerConfig_To_kops_MetricsServerConfig(a.(*MetricsServerConfig), b.(*kops.MetricsServerConfig), scope)
 	}); err != nil {
@@ -843,6 +1203,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NodeBootstrapSpec)(nil), (*kops.NodeBootstrapSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_NodeBootstrapSpec_To_kops_NodeBootstrapSpec(a.(*NodeBootstrapSpec), b.(*kops.NodeBootstrapSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NodeBootstrapSpec)(nil), (*NodeBootstrapSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NodeBootstrapSpec_To_v1alpha2_NodeBootstrapSpec(a.(*kops.NodeBootstrapSpec), b.(*NodeBootstrapSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NodeCertificatesSpec)(nil), (*kops.NodeCertificatesSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_NodeCertificatesSpec_To_kops_NodeCertificatesSpec(a.(*NodeCertificatesSpec), b.(*kops.NodeCertificatesSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NodeCertificatesSpec)(nil), (*NodeCertificatesSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NodeCertificatesSpec_To_v1alpha2_NodeCertificatesSpec(a.(*kops.NodeCertificatesSpec), b.(*NodeCertificatesSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NodeLocalDNSConfig)(nil), (*kops.NodeLocalDNSConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_NodeLocalDNSConfig_To_kops_NodeLocalDNSConfig(a.(*NodeLocalDNSConfig), b.(*kops.NodeLocalDNSConfig), scope)
 	}); err != nil {
@@ -863,6 +1243,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NodeTuningSpec)(nil), (*kops.NodeTuningSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_NodeTuningSpec_To_kops_NodeTuningSpec(a.(*NodeTuningSpec), b.(*kops.NodeTuningSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NodeTuningSpec)(nil), (*NodeTuningSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NodeTuningSpec_To_v1alpha2_NodeTuningSpec(a.(*kops.NodeTuningSpec), b.(*NodeTuningSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NvidiaGPUConfig)(nil), (*kops.NvidiaGPUConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_NvidiaGPUConfig_To_kops_NvidiaGPUConfig(a.(*NvidiaGPUConfig), b.(*kops.NvidiaGPUConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NvidiaGPUConfig)(nil), (*NvidiaGPUConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NvidiaGPUConfig_To_v1alpha2_NvidiaGPUConfig(a.(*kops.NvidiaGPUConfig), b.(*NvidiaGPUConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*OIDCAuthenticationSpec)(nil), (*kops.OIDCAuthenticationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_OIDCAuthenticationSpec_To_kops_OIDCAuthenticationSpec(a.(*OIDCAuthenticationSpec), b.(*kops.OIDCAuthenticationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.OIDCAuthenticationSpec)(nil), (*OIDCAuthenticationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_OIDCAuthenticationSpec_To_v1alpha2_OIDCAuthenticationSpec(a.(*kops.OIDCAuthenticationSpec), b.(*OIDCAuthenticationSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*OpenstackBlockStorageConfig)(nil), (*kops.OpenstackBlockStorageConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_OpenstackBlockStorageConfig_To_kops_OpenstackBlockStorageConfig(a.(*OpenstackBlockStorageConfig), b.(*kops.OpenstackBlockStorageConfig), scope)
 	}); err != nil {
@@ -933,28 +1343,58 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*RBACAuthorizationSpec)(nil), (*kops.RBACAuthorizationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha2_RBACAuthorizationSpec_To_kops_RBACAuthorizationSpec(a.(*RBACAuthorizationSpec), b.(*kops.RBACAuthorizationSpec), scope)
+	if err := s.AddGeneratedConversionFunc((*PodSecuritySpec)(nil), (*kops.PodSecuritySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_PodSecuritySpec_To_kops_PodSecuritySpec(a.(*PodSecuritySpec), b.(*kops.PodSecuritySpec), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*kops.RBACAuthorizationSpec)(nil), (*RBACAuthorizationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_kops_RBACAuthorizationSpec_To_v1alpha2_RBACAuthorizationSpec(a.(*kops.RBACAuthorizationSpec), b.(*RBACAuthorizationSpec), scope)
+	if err := s.AddGeneratedConversionFunc((*kops.PodSecuritySpec)(nil), (*PodSecuritySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_PodSecuritySpec_To_v1alpha2_PodSecuritySpec(a.(*kops.PodSecuritySpec), b.(*PodSecuritySpec), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*RollingUpdate)(nil), (*kops.RollingUpdate)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha2_RollingUpdate_To_kops_RollingUpdate(a.(*RollingUpdate), b.(*kops.RollingUpdate), scope)
+	if err := s.AddGeneratedConversionFunc((*PrivateLinkEndpointSpec)(nil), (*kops.PrivateLinkEndpointSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_PrivateLinkEndpointSpec_To_kops_PrivateLinkEndpointSpec(a.(*PrivateLinkEndpointSpec), b.(*kops.PrivateLinkEndpointSpec), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*kops.RollingUpdate)(nil), (*RollingUpdate)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_kops_RollingUpdate_To_v1alpha2_RollingUpdate(a.(*kops.RollingUpdate), b.(*RollingUpdate), scope)
+	if err := s.AddGeneratedConversionFunc((*kops.PrivateLinkEndpointSpec)(nil), (*PrivateLinkEndpointSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_PrivateLinkEndpointSpec_To_v1alpha2_PrivateLinkEndpointSpec(a.(*kops.PrivateLinkEndpointSpec), b.(*PrivateLinkEndpointSpec), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*RomanaNetworkingSpec)(nil), (*kops.RomanaNetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha2_RomanaNetworkingSpec_To_kops_RomanaNetworkingSpec(a.(*RomanaNetworkingSpec), b.(*kops.RomanaNetworkingSpec), scope)
+	if err := s.AddGeneratedConversionFunc((*PrivateLinkSpec)(nil), (*kops.PrivateLinkSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_PrivateLinkSpec_To_kops_PrivateLinkSpec(a.(*PrivateLinkSpec), b.(*kops.PrivateLinkSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.PrivateLinkSpec)(nil), (*PrivateLinkSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_PrivateLinkSpec_To_v1alpha2_PrivateLinkSpec(a.(*kops.PrivateLinkSpec), b.(*PrivateLinkSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RBACAuthorizationSpec)(nil), (*kops.RBACAuthorizationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_RBACAuthorizationSpec_To_kops_RBACAuthorizationSpec(a.(*RBACAuthorizationSpec), b.(*kops.RBACAuthorizationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.RBACAuthorizationSpec)(nil), (*RBACAuthorizationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_RBACAuthorizationSpec_To_v1alpha2_RBACAuthorizationSpec(a.(*kops.RBACAuthorizationSpec), b.(*RBACAuthorizationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RollingUpdate)(nil), (*kops.RollingUpdate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_RollingUpdate_To_kops_RollingUpdate(a.(*RollingUpdate), b.(*kops.RollingUpdate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.RollingUpdate)(nil), (*RollingUpdate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_RollingUpdate_To_v1alpha2_RollingUpdate(a.(*kops.RollingUpdate), b.(*RollingUpdate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RomanaNetworkingSpec)(nil), (*kops.RomanaNetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_RomanaNetworkingSpec_To_kops_RomanaNetworkingSpec(a.(*RomanaNetworkingSpec), b.(*kops.RomanaNetworkingSpec), scope)
 	}); err != nil {
 		return err
 	}
@@ -993,6 +1433,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ScalingSchedule)(nil), (*kops.ScalingSchedule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ScalingSchedule_To_kops_ScalingSchedule(a.(*ScalingSchedule), b.(*kops.ScalingSchedule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ScalingSchedule)(nil), (*ScalingSchedule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ScalingSchedule_To_v1alpha2_ScalingSchedule(a.(*kops.ScalingSchedule), b.(*ScalingSchedule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SecretStoreEncryptionSpec)(nil), (*kops.SecretStoreEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_SecretStoreEncryptionSpec_To_kops_SecretStoreEncryptionSpec(a.(*SecretStoreEncryptionSpec), b.(*kops.SecretStoreEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SecretStoreEncryptionSpec)(nil), (*SecretStoreEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SecretStoreEncryptionSpec_To_v1alpha2_SecretStoreEncryptionSpec(a.(*kops.SecretStoreEncryptionSpec), b.(*SecretStoreEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SecurityModulesSpec)(nil), (*kops.SecurityModulesSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_SecurityModulesSpec_To_kops_SecurityModulesSpec(a.(*SecurityModulesSpec), b.(*kops.SecurityModulesSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SecurityModulesSpec)(nil), (*SecurityModulesSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SecurityModulesSpec_To_v1alpha2_SecurityModulesSpec(a.(*kops.SecurityModulesSpec), b.(*SecurityModulesSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ServiceAccountExternalPermission)(nil), (*kops.ServiceAccountExternalPermission)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_ServiceAccountExternalPermission_To_kops_ServiceAccountExternalPermission(a.(*ServiceAccountExternalPermission), b.(*kops.ServiceAccountExternalPermission), scope)
 	}); err != nil {
@@ -1013,6 +1483,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ShieldedInstanceConfig)(nil), (*kops.ShieldedInstanceConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ShieldedInstanceConfig_To_kops_ShieldedInstanceConfig(a.(*ShieldedInstanceConfig), b.(*kops.ShieldedInstanceConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ShieldedInstanceConfig)(nil), (*ShieldedInstanceConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ShieldedInstanceConfig_To_v1alpha2_ShieldedInstanceConfig(a.(*kops.ShieldedInstanceConfig), b.(*ShieldedInstanceConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*SnapshotControllerConfig)(nil), (*kops.SnapshotControllerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_SnapshotControllerConfig_To_kops_SnapshotControllerConfig(a.(*SnapshotControllerConfig), b.(*kops.SnapshotControllerConfig), scope)
 	}); err != nil {
@@ -1023,6 +1503,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*SwapSpec)(nil), (*kops.SwapSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_SwapSpec_To_kops_SwapSpec(a.(*SwapSpec), b.(*kops.SwapSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SwapSpec)(nil), (*SwapSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SwapSpec_To_v1alpha2_SwapSpec(a.(*kops.SwapSpec), b.(*SwapSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*TargetSpec)(nil), (*kops.TargetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_TargetSpec_To_kops_TargetSpec(a.(*TargetSpec), b.(*kops.TargetSpec), scope)
 	}); err != nil {
@@ -1033,6 +1523,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*TerraformBackendSpec)(nil), (*kops.TerraformBackendSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_TerraformBackendSpec_To_kops_TerraformBackendSpec(a.(*TerraformBackendSpec), b.(*kops.TerraformBackendSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.TerraformBackendSpec)(nil), (*TerraformBackendSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_TerraformBackendSpec_To_v1alpha2_TerraformBackendSpec(a.(*kops.TerraformBackendSpec), b.(*TerraformBackendSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*TerraformGCSBackendSpec)(nil), (*kops.TerraformGCSBackendSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_TerraformGCSBackendSpec_To_kops_TerraformGCSBackendSpec(a.(*TerraformGCSBackendSpec), b.(*kops.TerraformGCSBackendSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.TerraformGCSBackendSpec)(nil), (*TerraformGCSBackendSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_TerraformGCSBackendSpec_To_v1alpha2_TerraformGCSBackendSpec(a.(*kops.TerraformGCSBackendSpec), b.(*TerraformGCSBackendSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*TerraformS3BackendSpec)(nil), (*kops.TerraformS3BackendSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_TerraformS3BackendSpec_To_kops_TerraformS3BackendSpec(a.(*TerraformS3BackendSpec), b.(*kops.TerraformS3BackendSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.TerraformS3BackendSpec)(nil), (*TerraformS3BackendSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_TerraformS3BackendSpec_To_v1alpha2_TerraformS3BackendSpec(a.(*kops.TerraformS3BackendSpec), b.(*TerraformS3BackendSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*TerraformSpec)(nil), (*kops.TerraformSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_TerraformSpec_To_kops_TerraformSpec(a.(*TerraformSpec), b.(*kops.TerraformSpec), scope)
 	}); err != nil {
@@ -1106,6 +1626,28 @@ func RegisterConversions(s *runtime.Scheme) error {
 	return nil
 }
 
+func autoConvert_v1alpha2_APIServerEndpointSpec_To_kops_APIServerEndpointSpec(in *APIServerEndpointSpec, out *kops.APIServerEndpointSpec, s conversion.Scope) error {
+	out.Type = kops.APIServerEndpointType(in.Type)
+	out.IPs = in.IPs
+	return nil
+}
+
+// Convert_v1alpha2_APIServerEndpointSpec_To_kops_APIServerEndpointSpec is an autogenerated conversion function.
+func Convert_v1alpha2_APIServerEndpointSpec_To_kops_APIServerEndpointSpec(in *APIServerEndpointSpec, out *kops.APIServerEndpointSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_APIServerEndpointSpec_To_kops_APIServerEndpointSpec(in, out, s)
+}
+
+func autoConvert_kops_APIServerEndpointSpec_To_v1alpha2_APIServerEndpointSpec(in *kops.APIServerEndpointSpec, out *APIServerEndpointSpec, s conversion.Scope) error {
+	out.Type = APIServerEndpointType(in.Type)
+	out.IPs = in.IPs
+	return nil
+}
+
+// Convert_kops_APIServerEndpointSpec_To_v1alpha2_APIServerEndpointSpec is an autogenerated conversion function.
+func Convert_kops_APIServerEndpointSpec_To_v1alpha2_APIServerEndpointSpec(in *kops.APIServerEndpointSpec, out *APIServerEndpointSpec, s conversion.Scope) error {
+	return autoConvert_kops_APIServerEndpointSpec_To_v1alpha2_APIServerEndpointSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_AWSEBSCSIDriver_To_kops_AWSEBSCSIDriver(in *AWSEBSCSIDriver, out *kops.AWSEBSCSIDriver, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.Version = in.Version
@@ -1193,6 +1735,15 @@ func autoConvert_v1alpha2_AccessSpec_To_kops_AccessSpec(in *AccessSpec, out *kop
 	} else {
 		out.LoadBalancer = nil
 	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(kops.GatewayAccessSpec)
+		if err := Convert_v1alpha2_GatewayAccessSpec_To_kops_GatewayAccessSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Gateway = nil
+	}
 	return nil
 }
 
@@ -1220,6 +1771,15 @@ func autoConvert_kops_AccessSpec_To_v1alpha2_AccessSpec(in *kops.AccessSpec, out
 	} else {
 		out.LoadBalancer = nil
 	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(GatewayAccessSpec)
+		if err := Convert_kops_GatewayAccessSpec_To_v1alpha2_GatewayAccessSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Gateway = nil
+	}
 	return nil
 }
 
@@ -1230,6 +1790,11 @@ func Convert_kops_AccessSpec_To_v1alpha2_AccessSpec(in *kops.AccessSpec, out *Ac
 
 func autoConvert_v1alpha2_AddonSpec_To_kops_AddonSpec(in *AddonSpec, out *kops.AddonSpec, s conversion.Scope) error {
 	out.Manifest = in.Manifest
+	out.Priority = in.Priority
+	out.Name = in.Name
+	out.Disabled = in.Disabled
+	out.Version = in.Version
+	out.ManifestHash = in.ManifestHash
 	return nil
 }
 
@@ -1240,6 +1805,11 @@ func Convert_v1alpha2_AddonSpec_To_kops_AddonSpec(in *AddonSpec, out *kops.Addon
 
 func autoConvert_kops_AddonSpec_To_v1alpha2_AddonSpec(in *kops.AddonSpec, out *AddonSpec, s conversion.Scope) error {
 	out.Manifest = in.Manifest
+	out.Priority = in.Priority
+	out.Name = in.Name
+	out.Disabled = in.Disabled
+	out.Version = in.Version
+	out.ManifestHash = in.ManifestHash
 	return nil
 }
 
@@ -1310,10 +1880,37 @@ func Convert_kops_AmazonVPCNetworkingSpec_To_v1alpha2_AmazonVPCNetworkingSpec(in
 	return autoConvert_kops_AmazonVPCNetworkingSpec_To_v1alpha2_AmazonVPCNetworkingSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_AntreaNetworkingSpec_To_kops_AntreaNetworkingSpec(in *AntreaNetworkingSpec, out *kops.AntreaNetworkingSpec, s conversion.Scope) error {
+	out.Version = in.Version
+	out.TunnelType = in.TunnelType
+	out.MTU = in.MTU
+	out.EnablePrometheusMetrics = in.EnablePrometheusMetrics
+	return nil
+}
+
+// Convert_v1alpha2_AntreaNetworkingSpec_To_kops_AntreaNetworkingSpec is an autogenerated conversion function.
+func Convert_v1alpha2_AntreaNetworkingSpec_To_kops_AntreaNetworkingSpec(in *AntreaNetworkingSpec, out *kops.AntreaNetworkingSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_AntreaNetworkingSpec_To_kops_AntreaNetworkingSpec(in, out, s)
+}
+
+func autoConvert_kops_AntreaNetworkingSpec_To_v1alpha2_AntreaNetworkingSpec(in *kops.AntreaNetworkingSpec, out *AntreaNetworkingSpec, s conversion.Scope) error {
+	out.Version = in.Version
+	out.TunnelType = in.TunnelType
+	out.MTU = in.MTU
+	out.EnablePrometheusMetrics = in.EnablePrometheusMetrics
+	return nil
+}
+
+// Convert_kops_AntreaNetworkingSpec_To_v1alpha2_AntreaNetworkingSpec is an autogenerated conversion function.
+func Convert_kops_AntreaNetworkingSpec_To_v1alpha2_AntreaNetworkingSpec(in *kops.AntreaNetworkingSpec, out *AntreaNetworkingSpec, s conversion.Scope) error {
+	return autoConvert_kops_AntreaNetworkingSpec_To_v1alpha2_AntreaNetworkingSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_Assets_To_kops_Assets(in *Assets, out *kops.Assets, s conversion.Scope) error {
 	out.ContainerRegistry = in.ContainerRegistry
 	out.FileRepository = in.FileRepository
 	out.ContainerProxy = in.ContainerProxy
+	out.AirGapped = in.AirGapped
 	return nil
 }
 
@@ -1326,6 +1923,7 @@ func autoConvert_kops_Assets_To_v1alpha2_Assets(in *kops.Assets, out *Assets, s
 	out.ContainerRegistry = in.ContainerRegistry
 	out.FileRepository = in.FileRepository
 	out.ContainerProxy = in.ContainerProxy
+	out.AirGapped = in.AirGapped
 	return nil
 }
 
@@ -1334,6 +1932,226 @@ func Convert_kops_Assets_To_v1alpha2_Assets(in *kops.Assets, out *Assets, s conv
 	return autoConvert_kops_Assets_To_v1alpha2_Assets(in, out, s)
 }
 
+func autoConvert_v1alpha2_AuditLogCloudWatchSpec_To_kops_AuditLogCloudWatchSpec(in *AuditLogCloudWatchSpec, out *kops.AuditLogCloudWatchSpec, s conversion.Scope) error {
+	out.LogGroup = in.LogGroup
+	out.Region = in.Region
+	return nil
+}
+
+// Convert_v1alpha2_AuditLogCloudWatchSpec_To_kops_AuditLogCloudWatchSpec is an autogenerated conversion function.
+func Convert_v1alpha2_AuditLogCloudWatchSpec_To_kops_AuditLogCloudWatchSpec(in *AuditLogCloudWatchSpec, out *kops.AuditLogCloudWatchSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_AuditLogCloudWatchSpec_To_kops_AuditLogCloudWatchSpec(in, out, s)
+}
+
+func autoConvert_kops_AuditLogCloudWatchSpec_To_v1alpha2_AuditLogCloudWatchSpec(in *kops.AuditLogCloudWatchSpec, out *AuditLogCloudWatchSpec, s conversion.Scope) error {
+	out.LogGroup = in.LogGroup
+	out.Region = in.Region
+	return nil
+}
+
+// Convert_kops_AuditLogCloudWatchSpec_To_v1alpha2_AuditLogCloudWatchSpec is an autogenerated conversion function.
+func Convert_kops_AuditLogCloudWatchSpec_To_v1alpha2_AuditLogCloudWatchSpec(in *kops.AuditLogCloudWatchSpec, out *AuditLogCloudWatchSpec, s conversion.Scope) error {
+	return autoConvert_kops_AuditLogCloudWatchSpec_To_v1alpha2_AuditLogCloudWatchSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_AuditLogGCSSpec_To_kops_AuditLogGCSSpec(in *AuditLogGCSSpec, out *kops.AuditLogGCSSpec, s conversion.Scope) error {
+	out.Bucket = in.Bucket
+	out.Prefix = in.Prefix
+	return nil
+}
+
+// Convert_v1alpha2_AuditLogGCSSpec_To_kops_AuditLogGCSSpec is an autogenerated conversion function.
+func Convert_v1alpha2_AuditLogGCSSpec_To_kops_AuditLogGCSSpec(in *AuditLogGCSSpec, out *kops.AuditLogGCSSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_AuditLogGCSSpec_To_kops_AuditLogGCSSpec(in, out, s)
+}
+
+func autoConvert_kops_AuditLogGCSSpec_To_v1alpha2_AuditLogGCSSpec(in *kops.AuditLogGCSSpec, out *AuditLogGCSSpec, s conversion.Scope) error {
+	out.Bucket = in.Bucket
+	out.Prefix = in.Prefix
+	return nil
+}
+
+// Convert_kops_AuditLogGCSSpec_To_v1alpha2_AuditLogGCSSpec is an autogenerated conversion function.
+func Convert_kops_AuditLogGCSSpec_To_v1alpha2_AuditLogGCSSpec(in *kops.AuditLogGCSSpec, out *AuditLogGCSSpec, s conversion.Scope) error {
+	return autoConvert_kops_AuditLogGCSSpec_To_v1alpha2_AuditLogGCSSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_AuditLogS3Spec_To_kops_AuditLogS3Spec(in *AuditLogS3Spec, out *kops.AuditLogS3Spec, s conversion.Scope) error {
+	out.Bucket = in.Bucket
+	out.Prefix = in.Prefix
+	out.Region = in.Region
+	return nil
+}
+
+// Convert_v1alpha2_AuditLogS3Spec_To_kops_AuditLogS3Spec is an autogenerated conversion function.
+func Convert_v1alpha2_AuditLogS3Spec_To_kops_AuditLogS3Spec(in *AuditLogS3Spec, out *kops.AuditLogS3Spec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_AuditLogS3Spec_To_kops_AuditLogS3Spec(in, out, s)
+}
+
+func autoConvert_kops_AuditLogS3Spec_To_v1alpha2_AuditLogS3Spec(in *kops.AuditLogS3Spec, out *AuditLogS3Spec, s conversion.Scope) error {
+	out.Bucket = in.Bucket
+	out.Prefix = in.Prefix
+	out.Region = in.Region
+	return nil
+}
+
+// Convert_kops_AuditLogS3Spec_To_v1alpha2_AuditLogS3Spec is an autogenerated conversion function.
+func Convert_kops_AuditLogS3Spec_To_v1alpha2_AuditLogS3Spec(in *kops.AuditLogS3Spec, out *AuditLogS3Spec, s conversion.Scope) error {
+	return autoConvert_kops_AuditLogS3Spec_To_v1alpha2_AuditLogS3Spec(in, out, s)
+}
+
+func autoConvert_v1alpha2_AuditLogShippingSpec_To_kops_AuditLogShippingSpec(in *AuditLogShippingSpec, out *kops.AuditLogShippingSpec, s conversion.Scope) error {
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(kops.AuditLogS3Spec)
+		if err := Convert_v1alpha2_AuditLogS3Spec_To_kops_AuditLogS3Spec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.S3 = nil
+	}
+	if in.CloudWatch != nil {
+		in, out := &in.CloudWatch, &out.CloudWatch
+		*out = new(kops.AuditLogCloudWatchSpec)
+		if err := Convert_v1alpha2_AuditLogCloudWatchSpec_To_kops_AuditLogCloudWatchSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.CloudWatch = nil
+	}
+	if in.GCS != nil {
+		in, out := &in.GCS, &out.GCS
+		*out = new(kops.AuditLogGCSSpec)
+		if err := Convert_v1alpha2_AuditLogGCSSpec_To_kops_AuditLogGCSSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.GCS = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_AuditLogShippingSpec_To_kops_AuditLogShippingSpec is an autogenerated conversion function.
+func Convert_v1alpha2_AuditLogShippingSpec_To_kops_AuditLogShippingSpec(in *AuditLogShippingSpec, out *kops.AuditLogShippingSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_AuditLogShippingSpec_To_kops_AuditLogShippingSpec(in, out, s)
+}
+
+func autoConvert_kops_AuditLogShippingSpec_To_v1alpha2_AuditLogShippingSpec(in *kops.AuditLogShippingSpec, out *AuditLogShippingSpec, s conversion.Scope) error {
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(AuditLogS3Spec)
+		if err := Convert_kops_AuditLogS3Spec_To_v1alpha2_AuditLogS3Spec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.S3 = nil
+	}
+	if in.CloudWatch != nil {
+		in, out := &in.CloudWatch, &out.CloudWatch
+		*out = new(AuditLogCloudWatchSpec)
+		if err := Convert_kops_AuditLogCloudWatchSpec_To_v1alpha2_AuditLogCloudWatchSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.CloudWatch = nil
+	}
+	if in.GCS != nil {
+		in, out := &in.GCS, &out.GCS
+		*out = new(AuditLogGCSSpec)
+		if err := Convert_kops_AuditLogGCSSpec_To_v1alpha2_AuditLogGCSSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.GCS = nil
+	}
+	return nil
+}
+
+// Convert_kops_AuditLogShippingSpec_To_v1alpha2_AuditLogShippingSpec is an autogenerated conversion function.
+func Convert_kops_AuditLogShippingSpec_To_v1alpha2_AuditLogShippingSpec(in *kops.AuditLogShippingSpec, out *AuditLogShippingSpec, s conversion.Scope) error {
+	return autoConvert_kops_AuditLogShippingSpec_To_v1alpha2_AuditLogShippingSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_AuditSpec_To_kops_AuditSpec(in *AuditSpec, out *kops.AuditSpec, s conversion.Scope) error {
+	out.Policy = in.Policy
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(kops.AuditWebhookSpec)
+		if err := Convert_v1alpha2_AuditWebhookSpec_To_kops_AuditWebhookSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Webhook = nil
+	}
+	if in.LogShipping != nil {
+		in, out := &in.LogShipping, &out.LogShipping
+		*out = new(kops.AuditLogShippingSpec)
+		if err := Convert_v1alpha2_AuditLogShippingSpec_To_kops_AuditLogShippingSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.LogShipping = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_AuditSpec_To_kops_AuditSpec is an autogenerated conversion function.
+func Convert_v1alpha2_AuditSpec_To_kops_AuditSpec(in *AuditSpec, out *kops.AuditSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_AuditSpec_To_kops_AuditSpec(in, out, s)
+}
+
+func autoConvert_kops_AuditSpec_To_v1alpha2_AuditSpec(in *kops.AuditSpec, out *AuditSpec, s conversion.Scope) error {
+	out.Policy = in.Policy
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(AuditWebhookSpec)
+		if err := Convert_kops_AuditWebhookSpec_To_v1alpha2_AuditWebhookSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Webhook = nil
+	}
+	if in.LogShipping != nil {
+		in, out := &in.LogShipping, &out.LogShipping
+		*out = new(AuditLogShippingSpec)
+		if err := Convert_kops_AuditLogShippingSpec_To_v1alpha2_AuditLogShippingSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.LogShipping = nil
+	}
+	return nil
+}
+
+// Convert_kops_AuditSpec_To_v1alpha2_AuditSpec is an autogenerated conversion function.
+func Convert_kops_AuditSpec_To_v1alpha2_AuditSpec(in *kops.AuditSpec, out *AuditSpec, s conversion.Scope) error {
+	return autoConvert_kops_AuditSpec_To_v1alpha2_AuditSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_AuditWebhookSpec_To_kops_AuditWebhookSpec(in *AuditWebhookSpec, out *kops.AuditWebhookSpec, s conversion.Scope) error {
+	out.Server = in.Server
+	out.CertificateAuthority = in.CertificateAuthority
+	out.Mode = in.Mode
+	return nil
+}
+
+// Convert_v1alpha2_AuditWebhookSpec_To_kops_AuditWebhookSpec is an autogenerated conversion function.
+func Convert_v1alpha2_AuditWebhookSpec_To_kops_AuditWebhookSpec(in *AuditWebhookSpec, out *kops.AuditWebhookSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_AuditWebhookSpec_To_kops_AuditWebhookSpec(in, out, s)
+}
+
+func autoConvert_kops_AuditWebhookSpec_To_v1alpha2_AuditWebhookSpec(in *kops.AuditWebhookSpec, out *AuditWebhookSpec, s conversion.Scope) error {
+	out.Server = in.Server
+	out.CertificateAuthority = in.CertificateAuthority
+	out.Mode = in.Mode
+	return nil
+}
+
+// Convert_kops_AuditWebhookSpec_To_v1alpha2_AuditWebhookSpec is an autogenerated conversion function.
+func Convert_kops_AuditWebhookSpec_To_v1alpha2_AuditWebhookSpec(in *kops.AuditWebhookSpec, out *AuditWebhookSpec, s conversion.Scope) error {
+	return autoConvert_kops_AuditWebhookSpec_To_v1alpha2_AuditWebhookSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_AuthenticationSpec_To_kops_AuthenticationSpec(in *AuthenticationSpec, out *kops.AuthenticationSpec, s conversion.Scope) error {
 	if in.Kopeio != nil {
 		in, out := &in.Kopeio, &out.Kopeio
@@ -1353,6 +2171,15 @@ func autoConvert_v1alpha2_AuthenticationSpec_To_kops_AuthenticationSpec(in *Auth
 	} else {
 		out.Aws = nil
 	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(kops.OIDCAuthenticationSpec)
+		if err := Convert_v1alpha2_OIDCAuthenticationSpec_To_kops_OIDCAuthenticationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.OIDC = nil
+	}
 	return nil
 }
 
@@ -1380,6 +2207,15 @@ func autoConvert_kops_AuthenticationSpec_To_v1alpha2_AuthenticationSpec(in *kops
 	} else {
 		out.Aws = nil
 	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(OIDCAuthenticationSpec)
+		if err := Convert_kops_OIDCAuthenticationSpec_To_v1alpha2_OIDCAuthenticationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.OIDC = nil
+	}
 	return nil
 }
 
@@ -1611,6 +2447,15 @@ func autoConvert_v1alpha2_CalicoNetworkingSpec_To_kops_CalicoNetworkingSpec(in *
 	out.TyphaPrometheusMetricsPort = in.TyphaPrometheusMetricsPort
 	out.TyphaReplicas = in.TyphaReplicas
 	out.WireguardEnabled = in.WireguardEnabled
+	if in.WindowsPackages != nil {
+		in, out := &in.WindowsPackages, &out.WindowsPackages
+		*out = new(kops.PackagesConfig)
+		if err := Convert_v1alpha2_PackagesConfig_To_kops_PackagesConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.WindowsPackages = nil
+	}
 	return nil
 }
 
@@ -1648,6 +2493,15 @@ func autoConvert_kops_CalicoNetworkingSpec_To_v1alpha2_CalicoNetworkingSpec(in *
 	out.TyphaPrometheusMetricsPort = in.TyphaPrometheusMetricsPort
 	out.TyphaReplicas = in.TyphaReplicas
 	out.WireguardEnabled = in.WireguardEnabled
+	if in.WindowsPackages != nil {
+		in, out := &in.WindowsPackages, &out.WindowsPackages
+		*out = new(PackagesConfig)
+		if err := Convert_kops_PackagesConfig_To_v1alpha2_PackagesConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.WindowsPackages = nil
+	}
 	return nil
 }
 
@@ -2260,6 +3114,15 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	}
 	out.SecretStore = in.SecretStore
 	out.KeyStore = in.KeyStore
+	if in.SecretStoreEncryption != nil {
+		in, out := &in.SecretStoreEncryption, &out.SecretStoreEncryption
+		*out = new(kops.SecretStoreEncryptionSpec)
+		if err := Convert_v1alpha2_SecretStoreEncryptionSpec_To_kops_SecretStoreEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SecretStoreEncryption = nil
+	}
 	out.ConfigStore = in.ConfigStore
 	out.DNSZone = in.DNSZone
 	if in.DNSControllerGossipConfig != nil {
@@ -2450,6 +3313,15 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.MetricsServer = nil
 	}
+	if in.KubeStateMetrics != nil {
+		in, out := &in.KubeStateMetrics, &out.KubeStateMetrics
+		*out = new(kops.KubeStateMetricsConfig)
+		if err := Convert_v1alpha2_KubeStateMetricsConfig_To_kops_KubeStateMetricsConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.KubeStateMetrics = nil
+	}
 	if in.CertManager != nil {
 		in, out := &in.CertManager, &out.CertManager
 		*out = new(kops.CertManagerConfig)
@@ -2504,18 +3376,72 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.Authorization = nil
 	}
-	if in.NodeAuthorization != nil {
-		in, out := &in.NodeAuthorization, &out.NodeAuthorization
-		*out = new(kops.NodeAuthorizationSpec)
-		if err := Convert_v1alpha2_NodeAuthorizationSpec_To_kops_NodeAuthorizationSpec(*in, *out, s); err != nil {
+	if in.Audit != nil {
+		in, out := &in.Audit, &out.Audit
+		*out = new(kops.AuditSpec)
+		if err := Convert_v1alpha2_AuditSpec_To_kops_AuditSpec(*in, *out, s); err != nil {
 			return err
 		}
 	} else {
-		out.NodeAuthorization = nil
+		out.Audit = nil
 	}
-	out.CloudLabels = in.CloudLabels
-	if in.Hooks != nil {
-		in, out := &in.Hooks, &out.Hooks
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(kops.LoggingSpec)
+		if err := Convert_v1alpha2_LoggingSpec_To_kops_LoggingSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Logging = nil
+	}
+	if in.PodSecurity != nil {
+		in, out := &in.PodSecurity, &out.PodSecurity
+		*out = new(kops.PodSecuritySpec)
+		if err := Convert_v1alpha2_PodSecuritySpec_To_kops_PodSecuritySpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PodSecurity = nil
+	}
+	if in.NodeAuthorization != nil {
+		in, out := &in.NodeAuthorization, &out.NodeAuthorization
+		*out = new(kops.NodeAuthorizationSpec)
+		if err := Convert_v1alpha2_NodeAuthorizationSpec_To_kops_NodeAuthorizationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeAuthorization = nil
+	}
+	if in.NodeCertificates != nil {
+		in, out := &in.NodeCertificates, &out.NodeCertificates
+		*out = new(kops.NodeCertificatesSpec)
+		if err := Convert_v1alpha2_NodeCertificatesSpec_To_kops_NodeCertificatesSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeCertificates = nil
+	}
+	if in.NodeBootstrap != nil {
+		in, out := &in.NodeBootstrap, &out.NodeBootstrap
+		*out = new(kops.NodeBootstrapSpec)
+		if err := Convert_v1alpha2_NodeBootstrapSpec_To_kops_NodeBootstrapSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeBootstrap = nil
+	}
+	if in.InstanceGroupScaling != nil {
+		in, out := &in.InstanceGroupScaling, &out.InstanceGroupScaling
+		*out = new(kops.InstanceGroupScalingSpec)
+		if err := Convert_v1alpha2_InstanceGroupScalingSpec_To_kops_InstanceGroupScalingSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.InstanceGroupScaling = nil
+	}
+	out.CloudLabels = in.CloudLabels
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
 		*out = make([]kops.HookSpec, len(*in))
 		for i := range *in {
 			if err := Convert_v1alpha2_HookSpec_To_kops_HookSpec(&(*in)[i], &(*out)[i], s); err != nil {
@@ -2544,6 +3470,25 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 		out.IAM = nil
 	}
 	out.EncryptionConfig = in.EncryptionConfig
+	if in.EncryptionAtRest != nil {
+		in, out := &in.EncryptionAtRest, &out.EncryptionAtRest
+		*out = new(kops.EncryptionAtRestSpec)
+		if err := Convert_v1alpha2_EncryptionAtRestSpec_To_kops_EncryptionAtRestSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.EncryptionAtRest = nil
+	}
+	out.FIPS = in.FIPS
+	if in.Hardening != nil {
+		in, out := &in.Hardening, &out.Hardening
+		*out = new(kops.HardeningSpec)
+		if err := Convert_v1alpha2_HardeningSpec_To_kops_HardeningSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Hardening = nil
+	}
 	out.DisableSubnetTags = in.DisableSubnetTags
 	if in.Target != nil {
 		in, out := &in.Target, &out.Target
@@ -2556,6 +3501,15 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	}
 	out.UseHostCertificates = in.UseHostCertificates
 	out.SysctlParameters = in.SysctlParameters
+	if in.NodeTuning != nil {
+		in, out := &in.NodeTuning, &out.NodeTuning
+		*out = new(kops.NodeTuningSpec)
+		if err := Convert_v1alpha2_NodeTuningSpec_To_kops_NodeTuningSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeTuning = nil
+	}
 	if in.RollingUpdate != nil {
 		in, out := &in.RollingUpdate, &out.RollingUpdate
 		*out = new(kops.RollingUpdate)
@@ -2663,6 +3617,15 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	}
 	out.SecretStore = in.SecretStore
 	out.KeyStore = in.KeyStore
+	if in.SecretStoreEncryption != nil {
+		in, out := &in.SecretStoreEncryption, &out.SecretStoreEncryption
+		*out = new(SecretStoreEncryptionSpec)
+		if err := Convert_kops_SecretStoreEncryptionSpec_To_v1alpha2_SecretStoreEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SecretStoreEncryption = nil
+	}
 	out.ConfigStore = in.ConfigStore
 	out.DNSZone = in.DNSZone
 	if in.DNSControllerGossipConfig != nil {
@@ -2853,6 +3816,15 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.MetricsServer = nil
 	}
+	if in.KubeStateMetrics != nil {
+		in, out := &in.KubeStateMetrics, &out.KubeStateMetrics
+		*out = new(KubeStateMetricsConfig)
+		if err := Convert_kops_KubeStateMetricsConfig_To_v1alpha2_KubeStateMetricsConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.KubeStateMetrics = nil
+	}
 	if in.CertManager != nil {
 		in, out := &in.CertManager, &out.CertManager
 		*out = new(CertManagerConfig)
@@ -2907,6 +3879,33 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.Authorization = nil
 	}
+	if in.Audit != nil {
+		in, out := &in.Audit, &out.Audit
+		*out = new(AuditSpec)
+		if err := Convert_kops_AuditSpec_To_v1alpha2_AuditSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Audit = nil
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(LoggingSpec)
+		if err := Convert_kops_LoggingSpec_To_v1alpha2_LoggingSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Logging = nil
+	}
+	if in.PodSecurity != nil {
+		in, out := &in.PodSecurity, &out.PodSecurity
+		*out = new(PodSecuritySpec)
+		if err := Convert_kops_PodSecuritySpec_To_v1alpha2_PodSecuritySpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PodSecurity = nil
+	}
 	if in.NodeAuthorization != nil {
 		in, out := &in.NodeAuthorization, &out.NodeAuthorization
 		*out = new(NodeAuthorizationSpec)
@@ -2916,6 +3915,33 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.NodeAuthorization = nil
 	}
+	if in.NodeCertificates != nil {
+		in, out := &in.NodeCertificates, &out.NodeCertificates
+		*out = new(NodeCertificatesSpec)
+		if err := Convert_kops_NodeCertificatesSpec_To_v1alpha2_NodeCertificatesSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeCertificates = nil
+	}
+	if in.NodeBootstrap != nil {
+		in, out := &in.NodeBootstrap, &out.NodeBootstrap
+		*out = new(NodeBootstrapSpec)
+		if err := Convert_kops_NodeBootstrapSpec_To_v1alpha2_NodeBootstrapSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeBootstrap = nil
+	}
+	if in.InstanceGroupScaling != nil {
+		in, out := &in.InstanceGroupScaling, &out.InstanceGroupScaling
+		*out = new(InstanceGroupScalingSpec)
+		if err := Convert_kops_InstanceGroupScalingSpec_To_v1alpha2_InstanceGroupScalingSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.InstanceGroupScaling = nil
+	}
 	out.CloudLabels = in.CloudLabels
 	if in.Hooks != nil {
 		in, out := &in.Hooks, &out.Hooks
@@ -2947,6 +3973,25 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 		out.IAM = nil
 	}
 	out.EncryptionConfig = in.EncryptionConfig
+	if in.EncryptionAtRest != nil {
+		in, out := &in.EncryptionAtRest, &out.EncryptionAtRest
+		*out = new(EncryptionAtRestSpec)
+		if err := Convert_kops_EncryptionAtRestSpec_To_v1alpha2_EncryptionAtRestSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.EncryptionAtRest = nil
+	}
+	out.FIPS = in.FIPS
+	if in.Hardening != nil {
+		in, out := &in.Hardening, &out.Hardening
+		*out = new(HardeningSpec)
+		if err := Convert_kops_HardeningSpec_To_v1alpha2_HardeningSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Hardening = nil
+	}
 	out.DisableSubnetTags = in.DisableSubnetTags
 	if in.Target != nil {
 		in, out := &in.Target, &out.Target
@@ -2959,6 +4004,15 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	}
 	out.UseHostCertificates = in.UseHostCertificates
 	out.SysctlParameters = in.SysctlParameters
+	if in.NodeTuning != nil {
+		in, out := &in.NodeTuning, &out.NodeTuning
+		*out = new(NodeTuningSpec)
+		if err := Convert_kops_NodeTuningSpec_To_v1alpha2_NodeTuningSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeTuning = nil
+	}
 	if in.RollingUpdate != nil {
 		in, out := &in.RollingUpdate, &out.RollingUpdate
 		*out = new(RollingUpdate)
@@ -3052,6 +4106,24 @@ func autoConvert_v1alpha2_ContainerdConfig_To_kops_ContainerdConfig(in *Containe
 	out.Address = in.Address
 	out.ConfigOverride = in.ConfigOverride
 	out.LogLevel = in.LogLevel
+	if in.NRI != nil {
+		in, out := &in.NRI, &out.NRI
+		*out = new(kops.ContainerdNRIConfig)
+		if err := Convert_v1alpha2_ContainerdNRIConfig_To_kops_ContainerdNRIConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NRI = nil
+	}
+	if in.NvidiaGPU != nil {
+		in, out := &in.NvidiaGPU, &out.NvidiaGPU
+		*out = new(kops.NvidiaGPUConfig)
+		if err := Convert_v1alpha2_NvidiaGPUConfig_To_kops_NvidiaGPUConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NvidiaGPU = nil
+	}
 	if in.Packages != nil {
 		in, out := &in.Packages, &out.Packages
 		*out = new(kops.PackagesConfig)
@@ -3061,8 +4133,36 @@ func autoConvert_v1alpha2_ContainerdConfig_To_kops_ContainerdConfig(in *Containe
 	} else {
 		out.Packages = nil
 	}
+	if in.Registries != nil {
+		in, out := &in.Registries, &out.Registries
+		*out = make(map[string]kops.ContainerdRegistryConfig, len(*in))
+		for key, val := range *in {
+			newVal := new(kops.ContainerdRegistryConfig)
+			if err := Convert_v1alpha2_ContainerdRegistryConfig_To_kops_ContainerdRegistryConfig(&val, newVal, s); err != nil {
+				return err
+			}
+			(*out)[key] = *newVal
+		}
+	} else {
+		out.Registries = nil
+	}
 	out.RegistryMirrors = in.RegistryMirrors
 	out.Root = in.Root
+	if in.Runtimes != nil {
+		in, out := &in.Runtimes, &out.Runtimes
+		*out = make(map[string]kops.ContainerdRuntimeConfig, len(*in))
+		for key, val := range *in {
+			newVal := new(kops.ContainerdRuntimeConfig)
+			if err := Convert_v1alpha2_ContainerdRuntimeConfig_To_kops_ContainerdRuntimeConfig(&val, newVal, s); err != nil {
+				return err
+			}
+			(*out)[key] = *newVal
+		}
+	} else {
+		out.Runtimes = nil
+	}
+	out.SandboxImage = in.SandboxImage
+	out.SelinuxEnabled = in.SelinuxEnabled
 	out.SkipInstall = in.SkipInstall
 	out.State = in.State
 	out.Version = in.Version
@@ -3078,6 +4178,24 @@ func autoConvert_kops_ContainerdConfig_To_v1alpha2_ContainerdConfig(in *kops.Con
 	out.Address = in.Address
 	out.ConfigOverride = in.ConfigOverride
 	out.LogLevel = in.LogLevel
+	if in.NRI != nil {
+		in, out := &in.NRI, &out.NRI
+		*out = new(ContainerdNRIConfig)
+		if err := Convert_kops_ContainerdNRIConfig_To_v1alpha2_ContainerdNRIConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NRI = nil
+	}
+	if in.NvidiaGPU != nil {
+		in, out := &in.NvidiaGPU, &out.NvidiaGPU
+		*out = new(NvidiaGPUConfig)
+		if err := Convert_kops_NvidiaGPUConfig_To_v1alpha2_NvidiaGPUConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NvidiaGPU = nil
+	}
 	if in.Packages != nil {
 		in, out := &in.Packages, &out.Packages
 		*out = new(PackagesConfig)
@@ -3087,8 +4205,36 @@ func autoConvert_kops_ContainerdConfig_To_v1alpha2_ContainerdConfig(in *kops.Con
 	} else {
 		out.Packages = nil
 	}
+	if in.Registries != nil {
+		in, out := &in.Registries, &out.Registries
+		*out = make(map[string]ContainerdRegistryConfig, len(*in))
+		for key, val := range *in {
+			newVal := new(ContainerdRegistryConfig)
+			if err := Convert_kops_ContainerdRegistryConfig_To_v1alpha2_ContainerdRegistryConfig(&val, newVal, s); err != nil {
+				return err
+			}
+			(*out)[key] = *newVal
+		}
+	} else {
+		out.Registries = nil
+	}
 	out.RegistryMirrors = in.RegistryMirrors
 	out.Root = in.Root
+	if in.Runtimes != nil {
+		in, out := &in.Runtimes, &out.Runtimes
+		*out = make(map[string]ContainerdRuntimeConfig, len(*in))
+		for key, val := range *in {
+			newVal := new(ContainerdRuntimeConfig)
+			if err := Convert_kops_ContainerdRuntimeConfig_To_v1alpha2_ContainerdRuntimeConfig(&val, newVal, s); err != nil {
+				return err
+			}
+			(*out)[key] = *newVal
+		}
+	} else {
+		out.Runtimes = nil
+	}
+	out.SandboxImage = in.SandboxImage
+	out.SelinuxEnabled = in.SelinuxEnabled
 	out.SkipInstall = in.SkipInstall
 	out.State = in.State
 	out.Version = in.Version
@@ -3100,7 +4246,128 @@ func Convert_kops_ContainerdConfig_To_v1alpha2_ContainerdConfig(in *kops.Contain
 	return autoConvert_kops_ContainerdConfig_To_v1alpha2_ContainerdConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_ContainerdNRIConfig_To_kops_ContainerdNRIConfig(in *ContainerdNRIConfig, out *kops.ContainerdNRIConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	return nil
+}
+
+// Convert_v1alpha2_ContainerdNRIConfig_To_kops_ContainerdNRIConfig is an autogenerated conversion function.
+func Convert_v1alpha2_ContainerdNRIConfig_To_kops_ContainerdNRIConfig(in *ContainerdNRIConfig, out *kops.ContainerdNRIConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ContainerdNRIConfig_To_kops_ContainerdNRIConfig(in, out, s)
+}
+
+func autoConvert_kops_ContainerdNRIConfig_To_v1alpha2_ContainerdNRIConfig(in *kops.ContainerdNRIConfig, out *ContainerdNRIConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	return nil
+}
+
+// Convert_kops_ContainerdNRIConfig_To_v1alpha2_ContainerdNRIConfig is an autogenerated conversion function.
+func Convert_kops_ContainerdNRIConfig_To_v1alpha2_ContainerdNRIConfig(in *kops.ContainerdNRIConfig, out *ContainerdNRIConfig, s conversion.Scope) error {
+	return autoConvert_kops_ContainerdNRIConfig_To_v1alpha2_ContainerdNRIConfig(in, out, s)
+}
+
+func autoConvert_v1alpha2_ContainerdRegistryConfig_To_kops_ContainerdRegistryConfig(in *ContainerdRegistryConfig, out *kops.ContainerdRegistryConfig, s conversion.Scope) error {
+	out.Mirrors = in.Mirrors
+	out.CA = in.CA
+	out.SkipVerify = in.SkipVerify
+	out.Username = in.Username
+	out.Password = in.Password
+	return nil
+}
+
+// Convert_v1alpha2_ContainerdRegistryConfig_To_kops_ContainerdRegistryConfig is an autogenerated conversion function.
+func Convert_v1alpha2_ContainerdRegistryConfig_To_kops_ContainerdRegistryConfig(in *ContainerdRegistryConfig, out *kops.ContainerdRegistryConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ContainerdRegistryConfig_To_kops_ContainerdRegistryConfig(in, out, s)
+}
+
+func autoConvert_kops_ContainerdRegistryConfig_To_v1alpha2_ContainerdRegistryConfig(in *kops.ContainerdRegistryConfig, out *ContainerdRegistryConfig, s conversion.Scope) error {
+	out.Mirrors = in.Mirrors
+	out.CA = in.CA
+	out.SkipVerify = in.SkipVerify
+	out.Username = in.Username
+	out.Password = in.Password
+	return nil
+}
+
+// Convert_kops_ContainerdRegistryConfig_To_v1alpha2_ContainerdRegistryConfig is an autogenerated conversion function.
+func Convert_kops_ContainerdRegistryConfig_To_v1alpha2_ContainerdRegistryConfig(in *kops.ContainerdRegistryConfig, out *ContainerdRegistryConfig, s conversion.Scope) error {
+	return autoConvert_kops_ContainerdRegistryConfig_To_v1alpha2_ContainerdRegistryConfig(in, out, s)
+}
+
+func autoConvert_v1alpha2_ContainerdRuntimeConfig_To_kops_ContainerdRuntimeConfig(in *ContainerdRuntimeConfig, out *kops.ContainerdRuntimeConfig, s conversion.Scope) error {
+	out.Type = in.Type
+	out.Options = in.Options
+	return nil
+}
+
+// Convert_v1alpha2_ContainerdRuntimeConfig_To_kops_ContainerdRuntimeConfig is an autogenerated conversion function.
+func Convert_v1alpha2_ContainerdRuntimeConfig_To_kops_ContainerdRuntimeConfig(in *ContainerdRuntimeConfig, out *kops.ContainerdRuntimeConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ContainerdRuntimeConfig_To_kops_ContainerdRuntimeConfig(in, out, s)
+}
+
+func autoConvert_kops_ContainerdRuntimeConfig_To_v1alpha2_ContainerdRuntimeConfig(in *kops.ContainerdRuntimeConfig, out *ContainerdRuntimeConfig, s conversion.Scope) error {
+	out.Type = in.Type
+	out.Options = in.Options
+	return nil
+}
+
+// Convert_kops_ContainerdRuntimeConfig_To_v1alpha2_ContainerdRuntimeConfig is an autogenerated conversion function.
+func Convert_kops_ContainerdRuntimeConfig_To_v1alpha2_ContainerdRuntimeConfig(in *kops.ContainerdRuntimeConfig, out *ContainerdRuntimeConfig, s conversion.Scope) error {
+	return autoConvert_kops_ContainerdRuntimeConfig_To_v1alpha2_ContainerdRuntimeConfig(in, out, s)
+}
+
+func autoConvert_v1alpha2_CoreDNSRewriteRule_To_kops_CoreDNSRewriteRule(in *CoreDNSRewriteRule, out *kops.CoreDNSRewriteRule, s conversion.Scope) error {
+	out.Match = in.Match
+	out.From = in.From
+	out.To = in.To
+	return nil
+}
+
+// Convert_v1alpha2_CoreDNSRewriteRule_To_kops_CoreDNSRewriteRule is an autogenerated conversion function.
+func Convert_v1alpha2_CoreDNSRewriteRule_To_kops_CoreDNSRewriteRule(in *CoreDNSRewriteRule, out *kops.CoreDNSRewriteRule, s conversion.Scope) error {
+	return autoConvert_v1alpha2_CoreDNSRewriteRule_To_kops_CoreDNSRewriteRule(in, out, s)
+}
+
+func autoConvert_kops_CoreDNSRewriteRule_To_v1alpha2_CoreDNSRewriteRule(in *kops.CoreDNSRewriteRule, out *CoreDNSRewriteRule, s conversion.Scope) error {
+	out.Match = in.Match
+	out.From = in.From
+	out.To = in.To
+	return nil
+}
+
+// Convert_kops_CoreDNSRewriteRule_To_v1alpha2_CoreDNSRewriteRule is an autogenerated conversion function.
+func Convert_kops_CoreDNSRewriteRule_To_v1alpha2_CoreDNSRewriteRule(in *kops.CoreDNSRewriteRule, out *CoreDNSRewriteRule, s conversion.Scope) error {
+	return autoConvert_kops_CoreDNSRewriteRule_To_v1alpha2_CoreDNSRewriteRule(in, out, s)
+}
+
+func autoConvert_v1alpha2_CoreDNSServerBlock_To_kops_CoreDNSServerBlock(in *CoreDNSServerBlock, out *kops.CoreDNSServerBlock, s conversion.Scope) error {
+	out.Zones = in.Zones
+	out.Hosts = in.Hosts
+	out.Forward = in.Forward
+	out.CacheTTL = in.CacheTTL
+	return nil
+}
+
+// Convert_v1alpha2_CoreDNSServerBlock_To_kops_CoreDNSServerBlock is an autogenerated conversion function.
+func Convert_v1alpha2_CoreDNSServerBlock_To_kops_CoreDNSServerBlock(in *CoreDNSServerBlock, out *kops.CoreDNSServerBlock, s conversion.Scope) error {
+	return autoConvert_v1alpha2_CoreDNSServerBlock_To_kops_CoreDNSServerBlock(in, out, s)
+}
+
+func autoConvert_kops_CoreDNSServerBlock_To_v1alpha2_CoreDNSServerBlock(in *kops.CoreDNSServerBlock, out *CoreDNSServerBlock, s conversion.Scope) error {
+	out.Zones = in.Zones
+	out.Hosts = in.Hosts
+	out.Forward = in.Forward
+	out.CacheTTL = in.CacheTTL
+	return nil
+}
+
+// Convert_kops_CoreDNSServerBlock_To_v1alpha2_CoreDNSServerBlock is an autogenerated conversion function.
+func Convert_kops_CoreDNSServerBlock_To_v1alpha2_CoreDNSServerBlock(in *kops.CoreDNSServerBlock, out *CoreDNSServerBlock, s conversion.Scope) error {
+	return autoConvert_kops_CoreDNSServerBlock_To_v1alpha2_CoreDNSServerBlock(in, out, s)
+}
+
 func autoConvert_v1alpha2_DNSAccessSpec_To_kops_DNSAccessSpec(in *DNSAccessSpec, out *kops.DNSAccessSpec, s conversion.Scope) error {
+	out.InternalZoneID = in.InternalZoneID
 	return nil
 }
 
@@ -3110,6 +4377,7 @@ func Convert_v1alpha2_DNSAccessSpec_To_kops_DNSAccessSpec(in *DNSAccessSpec, out
 }
 
 func autoConvert_kops_DNSAccessSpec_To_v1alpha2_DNSAccessSpec(in *kops.DNSAccessSpec, out *DNSAccessSpec, s conversion.Scope) error {
+	out.InternalZoneID = in.InternalZoneID
 	return nil
 }
 
@@ -3118,6 +4386,30 @@ func Convert_kops_DNSAccessSpec_To_v1alpha2_DNSAccessSpec(in *kops.DNSAccessSpec
 	return autoConvert_kops_DNSAccessSpec_To_v1alpha2_DNSAccessSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_DNSAutoscalerConfig_To_kops_DNSAutoscalerConfig(in *DNSAutoscalerConfig, out *kops.DNSAutoscalerConfig, s conversion.Scope) error {
+	out.CoresPerReplica = in.CoresPerReplica
+	out.NodesPerReplica = in.NodesPerReplica
+	out.PreventSinglePointFailure = in.PreventSinglePointFailure
+	return nil
+}
+
+// Convert_v1alpha2_DNSAutoscalerConfig_To_kops_DNSAutoscalerConfig is an autogenerated conversion function.
+func Convert_v1alpha2_DNSAutoscalerConfig_To_kops_DNSAutoscalerConfig(in *DNSAutoscalerConfig, out *kops.DNSAutoscalerConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha2_DNSAutoscalerConfig_To_kops_DNSAutoscalerConfig(in, out, s)
+}
+
+func autoConvert_kops_DNSAutoscalerConfig_To_v1alpha2_DNSAutoscalerConfig(in *kops.DNSAutoscalerConfig, out *DNSAutoscalerConfig, s conversion.Scope) error {
+	out.CoresPerReplica = in.CoresPerReplica
+	out.NodesPerReplica = in.NodesPerReplica
+	out.PreventSinglePointFailure = in.PreventSinglePointFailure
+	return nil
+}
+
+// Convert_kops_DNSAutoscalerConfig_To_v1alpha2_DNSAutoscalerConfig is an autogenerated conversion function.
+func Convert_kops_DNSAutoscalerConfig_To_v1alpha2_DNSAutoscalerConfig(in *kops.DNSAutoscalerConfig, out *DNSAutoscalerConfig, s conversion.Scope) error {
+	return autoConvert_kops_DNSAutoscalerConfig_To_v1alpha2_DNSAutoscalerConfig(in, out, s)
+}
+
 func autoConvert_v1alpha2_DNSControllerGossipConfig_To_kops_DNSControllerGossipConfig(in *DNSControllerGossipConfig, out *kops.DNSControllerGossipConfig, s conversion.Scope) error {
 	out.Protocol = in.Protocol
 	out.Listen = in.Listen
@@ -3328,6 +4620,32 @@ func Convert_kops_EgressProxySpec_To_v1alpha2_EgressProxySpec(in *kops.EgressPro
 	return autoConvert_kops_EgressProxySpec_To_v1alpha2_EgressProxySpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_EncryptionAtRestSpec_To_kops_EncryptionAtRestSpec(in *EncryptionAtRestSpec, out *kops.EncryptionAtRestSpec, s conversion.Scope) error {
+	out.KMSKey = in.KMSKey
+	out.PreviousKMSKeys = in.PreviousKMSKeys
+	out.Resources = in.Resources
+	out.Image = in.Image
+	return nil
+}
+
+// Convert_v1alpha2_EncryptionAtRestSpec_To_kops_EncryptionAtRestSpec is an autogenerated conversion function.
+func Convert_v1alpha2_EncryptionAtRestSpec_To_kops_EncryptionAtRestSpec(in *EncryptionAtRestSpec, out *kops.EncryptionAtRestSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_EncryptionAtRestSpec_To_kops_EncryptionAtRestSpec(in, out, s)
+}
+
+func autoConvert_kops_EncryptionAtRestSpec_To_v1alpha2_EncryptionAtRestSpec(in *kops.EncryptionAtRestSpec, out *EncryptionAtRestSpec, s conversion.Scope) error {
+	out.KMSKey = in.KMSKey
+	out.PreviousKMSKeys = in.PreviousKMSKeys
+	out.Resources = in.Resources
+	out.Image = in.Image
+	return nil
+}
+
+// Convert_kops_EncryptionAtRestSpec_To_v1alpha2_EncryptionAtRestSpec is an autogenerated conversion function.
+func Convert_kops_EncryptionAtRestSpec_To_v1alpha2_EncryptionAtRestSpec(in *kops.EncryptionAtRestSpec, out *EncryptionAtRestSpec, s conversion.Scope) error {
+	return autoConvert_kops_EncryptionAtRestSpec_To_v1alpha2_EncryptionAtRestSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_EnvVar_To_kops_EnvVar(in *EnvVar, out *kops.EnvVar, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Value = in.Value
@@ -3350,6 +4668,28 @@ func Convert_kops_EnvVar_To_v1alpha2_EnvVar(in *kops.EnvVar, out *EnvVar, s conv
 	return autoConvert_kops_EnvVar_To_v1alpha2_EnvVar(in, out, s)
 }
 
+func autoConvert_v1alpha2_EtcdAutoCompactionSpec_To_kops_EtcdAutoCompactionSpec(in *EtcdAutoCompactionSpec, out *kops.EtcdAutoCompactionSpec, s conversion.Scope) error {
+	out.Mode = in.Mode
+	out.Retention = in.Retention
+	return nil
+}
+
+// Convert_v1alpha2_EtcdAutoCompactionSpec_To_kops_EtcdAutoCompactionSpec is an autogenerated conversion function.
+func Convert_v1alpha2_EtcdAutoCompactionSpec_To_kops_EtcdAutoCompactionSpec(in *EtcdAutoCompactionSpec, out *kops.EtcdAutoCompactionSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_EtcdAutoCompactionSpec_To_kops_EtcdAutoCompactionSpec(in, out, s)
+}
+
+func autoConvert_kops_EtcdAutoCompactionSpec_To_v1alpha2_EtcdAutoCompactionSpec(in *kops.EtcdAutoCompactionSpec, out *EtcdAutoCompactionSpec, s conversion.Scope) error {
+	out.Mode = in.Mode
+	out.Retention = in.Retention
+	return nil
+}
+
+// Convert_kops_EtcdAutoCompactionSpec_To_v1alpha2_EtcdAutoCompactionSpec is an autogenerated conversion function.
+func Convert_kops_EtcdAutoCompactionSpec_To_v1alpha2_EtcdAutoCompactionSpec(in *kops.EtcdAutoCompactionSpec, out *EtcdAutoCompactionSpec, s conversion.Scope) error {
+	return autoConvert_kops_EtcdAutoCompactionSpec_To_v1alpha2_EtcdAutoCompactionSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_EtcdBackupSpec_To_kops_EtcdBackupSpec(in *EtcdBackupSpec, out *kops.EtcdBackupSpec, s conversion.Scope) error {
 	out.BackupStore = in.BackupStore
 	out.Image = in.Image
@@ -3410,8 +4750,45 @@ func autoConvert_v1alpha2_EtcdClusterSpec_To_kops_EtcdClusterSpec(in *EtcdCluste
 	} else {
 		out.Manager = nil
 	}
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(kops.EtcdExternalSpec)
+		if err := Convert_v1alpha2_EtcdExternalSpec_To_kops_EtcdExternalSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.External = nil
+	}
 	out.MemoryRequest = in.MemoryRequest
 	out.CPURequest = in.CPURequest
+	out.QuotaBackendBytes = in.QuotaBackendBytes
+	if in.AutoCompaction != nil {
+		in, out := &in.AutoCompaction, &out.AutoCompaction
+		*out = new(kops.EtcdAutoCompactionSpec)
+		if err := Convert_v1alpha2_EtcdAutoCompactionSpec_To_kops_EtcdAutoCompactionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AutoCompaction = nil
+	}
+	if in.Defragmentation != nil {
+		in, out := &in.Defragmentation, &out.Defragmentation
+		*out = new(kops.EtcdDefragmentationSpec)
+		if err := Convert_v1alpha2_EtcdDefragmentationSpec_To_kops_EtcdDefragmentationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Defragmentation = nil
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(kops.EtcdMetricsSpec)
+		if err := Convert_v1alpha2_EtcdMetricsSpec_To_kops_EtcdMetricsSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Metrics = nil
+	}
 	return nil
 }
 
@@ -3458,8 +4835,45 @@ func autoConvert_kops_EtcdClusterSpec_To_v1alpha2_EtcdClusterSpec(in *kops.EtcdC
 	} else {
 		out.Manager = nil
 	}
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(EtcdExternalSpec)
+		if err := Convert_kops_EtcdExternalSpec_To_v1alpha2_EtcdExternalSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.External = nil
+	}
 	out.MemoryRequest = in.MemoryRequest
 	out.CPURequest = in.CPURequest
+	out.QuotaBackendBytes = in.QuotaBackendBytes
+	if in.AutoCompaction != nil {
+		in, out := &in.AutoCompaction, &out.AutoCompaction
+		*out = new(EtcdAutoCompactionSpec)
+		if err := Convert_kops_EtcdAutoCompactionSpec_To_v1alpha2_EtcdAutoCompactionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AutoCompaction = nil
+	}
+	if in.Defragmentation != nil {
+		in, out := &in.Defragmentation, &out.Defragmentation
+		*out = new(EtcdDefragmentationSpec)
+		if err := Convert_kops_EtcdDefragmentationSpec_To_v1alpha2_EtcdDefragmentationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Defragmentation = nil
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(EtcdMetricsSpec)
+		if err := Convert_kops_EtcdMetricsSpec_To_v1alpha2_EtcdMetricsSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Metrics = nil
+	}
 	return nil
 }
 
@@ -3468,8 +4882,48 @@ func Convert_kops_EtcdClusterSpec_To_v1alpha2_EtcdClusterSpec(in *kops.EtcdClust
 	return autoConvert_kops_EtcdClusterSpec_To_v1alpha2_EtcdClusterSpec(in, out, s)
 }
 
-func autoConvert_v1alpha2_EtcdManagerSpec_To_kops_EtcdManagerSpec(in *EtcdManagerSpec, out *kops.EtcdManagerSpec, s conversion.Scope) error {
-	out.Image = in.Image
+func autoConvert_v1alpha2_EtcdDefragmentationSpec_To_kops_EtcdDefragmentationSpec(in *EtcdDefragmentationSpec, out *kops.EtcdDefragmentationSpec, s conversion.Scope) error {
+	out.Interval = in.Interval
+	return nil
+}
+
+// Convert_v1alpha2_EtcdDefragmentationSpec_To_kops_EtcdDefragmentationSpec is an autogenerated conversion function.
+func Convert_v1alpha2_EtcdDefragmentationSpec_To_kops_EtcdDefragmentationSpec(in *EtcdDefragmentationSpec, out *kops.EtcdDefragmentationSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_EtcdDefragmentationSpec_To_kops_EtcdDefragmentationSpec(in, out, s)
+}
+
+func autoConvert_kops_EtcdDefragmentationSpec_To_v1alpha2_EtcdDefragmentationSpec(in *kops.EtcdDefragmentationSpec, out *EtcdDefragmentationSpec, s conversion.Scope) error {
+	out.Interval = in.Interval
+	return nil
+}
+
+// Convert_kops_EtcdDefragmentationSpec_To_v1alpha2_EtcdDefragmentationSpec is an autogenerated conversion function.
+func Convert_kops_EtcdDefragmentationSpec_To_v1alpha2_EtcdDefragmentationSpec(in *kops.EtcdDefragmentationSpec, out *EtcdDefragmentationSpec, s conversion.Scope) error {
+	return autoConvert_kops_EtcdDefragmentationSpec_To_v1alpha2_EtcdDefragmentationSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_EtcdExternalSpec_To_kops_EtcdExternalSpec(in *EtcdExternalSpec, out *kops.EtcdExternalSpec, s conversion.Scope) error {
+	out.Endpoints = in.Endpoints
+	return nil
+}
+
+// Convert_v1alpha2_EtcdExternalSpec_To_kops_EtcdExternalSpec is an autogenerated conversion function.
+func Convert_v1alpha2_EtcdExternalSpec_To_kops_EtcdExternalSpec(in *EtcdExternalSpec, out *kops.EtcdExternalSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_EtcdExternalSpec_To_kops_EtcdExternalSpec(in, out, s)
+}
+
+func autoConvert_kops_EtcdExternalSpec_To_v1alpha2_EtcdExternalSpec(in *kops.EtcdExternalSpec, out *EtcdExternalSpec, s conversion.Scope) error {
+	out.Endpoints = in.Endpoints
+	return nil
+}
+
+// Convert_kops_EtcdExternalSpec_To_v1alpha2_EtcdExternalSpec is an autogenerated conversion function.
+func Convert_kops_EtcdExternalSpec_To_v1alpha2_EtcdExternalSpec(in *kops.EtcdExternalSpec, out *EtcdExternalSpec, s conversion.Scope) error {
+	return autoConvert_kops_EtcdExternalSpec_To_v1alpha2_EtcdExternalSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_EtcdManagerSpec_To_kops_EtcdManagerSpec(in *EtcdManagerSpec, out *kops.EtcdManagerSpec, s conversion.Scope) error {
+	out.Image = in.Image
 	if in.Env != nil {
 		in, out := &in.Env, &out.Env
 		*out = make([]kops.EnvVar, len(*in))
@@ -3548,6 +5002,28 @@ func Convert_kops_EtcdMemberSpec_To_v1alpha2_EtcdMemberSpec(in *kops.EtcdMemberS
 	return autoConvert_kops_EtcdMemberSpec_To_v1alpha2_EtcdMemberSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_EtcdMetricsSpec_To_kops_EtcdMetricsSpec(in *EtcdMetricsSpec, out *kops.EtcdMetricsSpec, s conversion.Scope) error {
+	out.Type = in.Type
+	out.Port = in.Port
+	return nil
+}
+
+// Convert_v1alpha2_EtcdMetricsSpec_To_kops_EtcdMetricsSpec is an autogenerated conversion function.
+func Convert_v1alpha2_EtcdMetricsSpec_To_kops_EtcdMetricsSpec(in *EtcdMetricsSpec, out *kops.EtcdMetricsSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_EtcdMetricsSpec_To_kops_EtcdMetricsSpec(in, out, s)
+}
+
+func autoConvert_kops_EtcdMetricsSpec_To_v1alpha2_EtcdMetricsSpec(in *kops.EtcdMetricsSpec, out *EtcdMetricsSpec, s conversion.Scope) error {
+	out.Type = in.Type
+	out.Port = in.Port
+	return nil
+}
+
+// Convert_kops_EtcdMetricsSpec_To_v1alpha2_EtcdMetricsSpec is an autogenerated conversion function.
+func Convert_kops_EtcdMetricsSpec_To_v1alpha2_EtcdMetricsSpec(in *kops.EtcdMetricsSpec, out *EtcdMetricsSpec, s conversion.Scope) error {
+	return autoConvert_kops_EtcdMetricsSpec_To_v1alpha2_EtcdMetricsSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_ExecContainerAction_To_kops_ExecContainerAction(in *ExecContainerAction, out *kops.ExecContainerAction, s conversion.Scope) error {
 	out.Image = in.Image
 	out.Command = in.Command
@@ -3576,6 +5052,11 @@ func autoConvert_v1alpha2_ExternalDNSConfig_To_kops_ExternalDNSConfig(in *Extern
 	out.Disable = in.Disable
 	out.WatchIngress = in.WatchIngress
 	out.WatchNamespace = in.WatchNamespace
+	out.Provider = kops.ExternalDNSProvider(in.Provider)
+	out.DomainFilters = in.DomainFilters
+	out.ZoneIDFilters = in.ZoneIDFilters
+	out.TXTOwnerID = in.TXTOwnerID
+	out.Policy = in.Policy
 	return nil
 }
 
@@ -3588,6 +5069,11 @@ func autoConvert_kops_ExternalDNSConfig_To_v1alpha2_ExternalDNSConfig(in *kops.E
 	out.Disable = in.Disable
 	out.WatchIngress = in.WatchIngress
 	out.WatchNamespace = in.WatchNamespace
+	out.Provider = ExternalDNSProvider(in.Provider)
+	out.DomainFilters = in.DomainFilters
+	out.ZoneIDFilters = in.ZoneIDFilters
+	out.TXTOwnerID = in.TXTOwnerID
+	out.Policy = in.Policy
 	return nil
 }
 
@@ -3700,6 +5186,30 @@ func Convert_kops_GCENetworkingSpec_To_v1alpha2_GCENetworkingSpec(in *kops.GCENe
 	return autoConvert_kops_GCENetworkingSpec_To_v1alpha2_GCENetworkingSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_GatewayAccessSpec_To_kops_GatewayAccessSpec(in *GatewayAccessSpec, out *kops.GatewayAccessSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Namespace = in.Namespace
+	out.SectionName = in.SectionName
+	return nil
+}
+
+// Convert_v1alpha2_GatewayAccessSpec_To_kops_GatewayAccessSpec is an autogenerated conversion function.
+func Convert_v1alpha2_GatewayAccessSpec_To_kops_GatewayAccessSpec(in *GatewayAccessSpec, out *kops.GatewayAccessSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_GatewayAccessSpec_To_kops_GatewayAccessSpec(in, out, s)
+}
+
+func autoConvert_kops_GatewayAccessSpec_To_v1alpha2_GatewayAccessSpec(in *kops.GatewayAccessSpec, out *GatewayAccessSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Namespace = in.Namespace
+	out.SectionName = in.SectionName
+	return nil
+}
+
+// Convert_kops_GatewayAccessSpec_To_v1alpha2_GatewayAccessSpec is an autogenerated conversion function.
+func Convert_kops_GatewayAccessSpec_To_v1alpha2_GatewayAccessSpec(in *kops.GatewayAccessSpec, out *GatewayAccessSpec, s conversion.Scope) error {
+	return autoConvert_kops_GatewayAccessSpec_To_v1alpha2_GatewayAccessSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_GossipConfig_To_kops_GossipConfig(in *GossipConfig, out *kops.GossipConfig, s conversion.Scope) error {
 	out.Protocol = in.Protocol
 	out.Listen = in.Listen
@@ -3766,6 +5276,26 @@ func Convert_kops_GossipConfigSecondary_To_v1alpha2_GossipConfigSecondary(in *ko
 	return autoConvert_kops_GossipConfigSecondary_To_v1alpha2_GossipConfigSecondary(in, out, s)
 }
 
+func autoConvert_v1alpha2_GracefulShutdownSpec_To_kops_GracefulShutdownSpec(in *GracefulShutdownSpec, out *kops.GracefulShutdownSpec, s conversion.Scope) error {
+	out.CloudEvents = in.CloudEvents
+	return nil
+}
+
+// Convert_v1alpha2_GracefulShutdownSpec_To_kops_GracefulShutdownSpec is an autogenerated conversion function.
+func Convert_v1alpha2_GracefulShutdownSpec_To_kops_GracefulShutdownSpec(in *GracefulShutdownSpec, out *kops.GracefulShutdownSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_GracefulShutdownSpec_To_kops_GracefulShutdownSpec(in, out, s)
+}
+
+func autoConvert_kops_GracefulShutdownSpec_To_v1alpha2_GracefulShutdownSpec(in *kops.GracefulShutdownSpec, out *GracefulShutdownSpec, s conversion.Scope) error {
+	out.CloudEvents = in.CloudEvents
+	return nil
+}
+
+// Convert_kops_GracefulShutdownSpec_To_v1alpha2_GracefulShutdownSpec is an autogenerated conversion function.
+func Convert_kops_GracefulShutdownSpec_To_v1alpha2_GracefulShutdownSpec(in *kops.GracefulShutdownSpec, out *GracefulShutdownSpec, s conversion.Scope) error {
+	return autoConvert_kops_GracefulShutdownSpec_To_v1alpha2_GracefulShutdownSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_HTTPProxy_To_kops_HTTPProxy(in *HTTPProxy, out *kops.HTTPProxy, s conversion.Scope) error {
 	out.Host = in.Host
 	out.Port = in.Port
@@ -3788,6 +5318,26 @@ func Convert_kops_HTTPProxy_To_v1alpha2_HTTPProxy(in *kops.HTTPProxy, out *HTTPP
 	return autoConvert_kops_HTTPProxy_To_v1alpha2_HTTPProxy(in, out, s)
 }
 
+func autoConvert_v1alpha2_HardeningSpec_To_kops_HardeningSpec(in *HardeningSpec, out *kops.HardeningSpec, s conversion.Scope) error {
+	out.Profile = in.Profile
+	return nil
+}
+
+// Convert_v1alpha2_HardeningSpec_To_kops_HardeningSpec is an autogenerated conversion function.
+func Convert_v1alpha2_HardeningSpec_To_kops_HardeningSpec(in *HardeningSpec, out *kops.HardeningSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_HardeningSpec_To_kops_HardeningSpec(in, out, s)
+}
+
+func autoConvert_kops_HardeningSpec_To_v1alpha2_HardeningSpec(in *kops.HardeningSpec, out *HardeningSpec, s conversion.Scope) error {
+	out.Profile = in.Profile
+	return nil
+}
+
+// Convert_kops_HardeningSpec_To_v1alpha2_HardeningSpec is an autogenerated conversion function.
+func Convert_kops_HardeningSpec_To_v1alpha2_HardeningSpec(in *kops.HardeningSpec, out *HardeningSpec, s conversion.Scope) error {
+	return autoConvert_kops_HardeningSpec_To_v1alpha2_HardeningSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_HookSpec_To_kops_HookSpec(in *HookSpec, out *kops.HookSpec, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Disabled = in.Disabled
@@ -3942,6 +5492,50 @@ func Convert_kops_IAMSpec_To_v1alpha2_IAMSpec(in *kops.IAMSpec, out *IAMSpec, s
 	return autoConvert_kops_IAMSpec_To_v1alpha2_IAMSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_ImagePreloadSpec_To_kops_ImagePreloadSpec(in *ImagePreloadSpec, out *kops.ImagePreloadSpec, s conversion.Scope) error {
+	out.Images = in.Images
+	out.AddonImages = in.AddonImages
+	out.PullThroughCache = in.PullThroughCache
+	return nil
+}
+
+// Convert_v1alpha2_ImagePreloadSpec_To_kops_ImagePreloadSpec is an autogenerated conversion function.
+func Convert_v1alpha2_ImagePreloadSpec_To_kops_ImagePreloadSpec(in *ImagePreloadSpec, out *kops.ImagePreloadSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ImagePreloadSpec_To_kops_ImagePreloadSpec(in, out, s)
+}
+
+func autoConvert_kops_ImagePreloadSpec_To_v1alpha2_ImagePreloadSpec(in *kops.ImagePreloadSpec, out *ImagePreloadSpec, s conversion.Scope) error {
+	out.Images = in.Images
+	out.AddonImages = in.AddonImages
+	out.PullThroughCache = in.PullThroughCache
+	return nil
+}
+
+// Convert_kops_ImagePreloadSpec_To_v1alpha2_ImagePreloadSpec is an autogenerated conversion function.
+func Convert_kops_ImagePreloadSpec_To_v1alpha2_ImagePreloadSpec(in *kops.ImagePreloadSpec, out *ImagePreloadSpec, s conversion.Scope) error {
+	return autoConvert_kops_ImagePreloadSpec_To_v1alpha2_ImagePreloadSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_InstanceAccessSpec_To_kops_InstanceAccessSpec(in *InstanceAccessSpec, out *kops.InstanceAccessSpec, s conversion.Scope) error {
+	out.Type = kops.InstanceAccessType(in.Type)
+	return nil
+}
+
+// Convert_v1alpha2_InstanceAccessSpec_To_kops_InstanceAccessSpec is an autogenerated conversion function.
+func Convert_v1alpha2_InstanceAccessSpec_To_kops_InstanceAccessSpec(in *InstanceAccessSpec, out *kops.InstanceAccessSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_InstanceAccessSpec_To_kops_InstanceAccessSpec(in, out, s)
+}
+
+func autoConvert_kops_InstanceAccessSpec_To_v1alpha2_InstanceAccessSpec(in *kops.InstanceAccessSpec, out *InstanceAccessSpec, s conversion.Scope) error {
+	out.Type = InstanceAccessType(in.Type)
+	return nil
+}
+
+// Convert_kops_InstanceAccessSpec_To_v1alpha2_InstanceAccessSpec is an autogenerated conversion function.
+func Convert_kops_InstanceAccessSpec_To_v1alpha2_InstanceAccessSpec(in *kops.InstanceAccessSpec, out *InstanceAccessSpec, s conversion.Scope) error {
+	return autoConvert_kops_InstanceAccessSpec_To_v1alpha2_InstanceAccessSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_InstanceGroup_To_kops_InstanceGroup(in *InstanceGroup, out *kops.InstanceGroup, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha2_InstanceGroupSpec_To_kops_InstanceGroupSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -3968,6 +5562,28 @@ func Convert_kops_InstanceGroup_To_v1alpha2_InstanceGroup(in *kops.InstanceGroup
 	return autoConvert_kops_InstanceGroup_To_v1alpha2_InstanceGroup(in, out, s)
 }
 
+func autoConvert_v1alpha2_InstanceGroupBootstrapSpec_To_kops_InstanceGroupBootstrapSpec(in *InstanceGroupBootstrapSpec, out *kops.InstanceGroupBootstrapSpec, s conversion.Scope) error {
+	out.Delivery = in.Delivery
+	out.SSHUser = in.SSHUser
+	return nil
+}
+
+// Convert_v1alpha2_InstanceGroupBootstrapSpec_To_kops_InstanceGroupBootstrapSpec is an autogenerated conversion function.
+func Convert_v1alpha2_InstanceGroupBootstrapSpec_To_kops_InstanceGroupBootstrapSpec(in *InstanceGroupBootstrapSpec, out *kops.InstanceGroupBootstrapSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_InstanceGroupBootstrapSpec_To_kops_InstanceGroupBootstrapSpec(in, out, s)
+}
+
+func autoConvert_kops_InstanceGroupBootstrapSpec_To_v1alpha2_InstanceGroupBootstrapSpec(in *kops.InstanceGroupBootstrapSpec, out *InstanceGroupBootstrapSpec, s conversion.Scope) error {
+	out.Delivery = in.Delivery
+	out.SSHUser = in.SSHUser
+	return nil
+}
+
+// Convert_kops_InstanceGroupBootstrapSpec_To_v1alpha2_InstanceGroupBootstrapSpec is an autogenerated conversion function.
+func Convert_kops_InstanceGroupBootstrapSpec_To_v1alpha2_InstanceGroupBootstrapSpec(in *kops.InstanceGroupBootstrapSpec, out *InstanceGroupBootstrapSpec, s conversion.Scope) error {
+	return autoConvert_kops_InstanceGroupBootstrapSpec_To_v1alpha2_InstanceGroupBootstrapSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_InstanceGroupList_To_kops_InstanceGroupList(in *InstanceGroupList, out *kops.InstanceGroupList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
@@ -4010,6 +5626,28 @@ func Convert_kops_InstanceGroupList_To_v1alpha2_InstanceGroupList(in *kops.Insta
 	return autoConvert_kops_InstanceGroupList_To_v1alpha2_InstanceGroupList(in, out, s)
 }
 
+func autoConvert_v1alpha2_InstanceGroupScalingSpec_To_kops_InstanceGroupScalingSpec(in *InstanceGroupScalingSpec, out *kops.InstanceGroupScalingSpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.StateStoreLockTable = in.StateStoreLockTable
+	return nil
+}
+
+// Convert_v1alpha2_InstanceGroupScalingSpec_To_kops_InstanceGroupScalingSpec is an autogenerated conversion function.
+func Convert_v1alpha2_InstanceGroupScalingSpec_To_kops_InstanceGroupScalingSpec(in *InstanceGroupScalingSpec, out *kops.InstanceGroupScalingSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_InstanceGroupScalingSpec_To_kops_InstanceGroupScalingSpec(in, out, s)
+}
+
+func autoConvert_kops_InstanceGroupScalingSpec_To_v1alpha2_InstanceGroupScalingSpec(in *kops.InstanceGroupScalingSpec, out *InstanceGroupScalingSpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.StateStoreLockTable = in.StateStoreLockTable
+	return nil
+}
+
+// Convert_kops_InstanceGroupScalingSpec_To_v1alpha2_InstanceGroupScalingSpec is an autogenerated conversion function.
+func Convert_kops_InstanceGroupScalingSpec_To_v1alpha2_InstanceGroupScalingSpec(in *kops.InstanceGroupScalingSpec, out *InstanceGroupScalingSpec, s conversion.Scope) error {
+	return autoConvert_kops_InstanceGroupScalingSpec_To_v1alpha2_InstanceGroupScalingSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_InstanceGroupSpec_To_kops_InstanceGroupSpec(in *InstanceGroupSpec, out *kops.InstanceGroupSpec, s conversion.Scope) error {
 	out.Role = kops.InstanceGroupRole(in.Role)
 	out.Image = in.Image
@@ -4164,6 +5802,131 @@ func autoConvert_v1alpha2_InstanceGroupSpec_To_kops_InstanceGroupSpec(in *Instan
 	} else {
 		out.WarmPool = nil
 	}
+	if in.ShieldedInstanceConfig != nil {
+		in, out := &in.ShieldedInstanceConfig, &out.ShieldedInstanceConfig
+		*out = new(kops.ShieldedInstanceConfig)
+		if err := Convert_v1alpha2_ShieldedInstanceConfig_To_kops_ShieldedInstanceConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ShieldedInstanceConfig = nil
+	}
+	out.ConfidentialCompute = in.ConfidentialCompute
+	out.EphemeralOSDisk = in.EphemeralOSDisk
+	out.AcceleratedNetworking = in.AcceleratedNetworking
+	if in.Machines != nil {
+		in, out := &in.Machines, &out.Machines
+		*out = make([]kops.MetalMachineSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_MetalMachineSpec_To_kops_MetalMachineSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Machines = nil
+	}
+	if in.Bootstrap != nil {
+		in, out := &in.Bootstrap, &out.Bootstrap
+		*out = new(kops.InstanceGroupBootstrapSpec)
+		if err := Convert_v1alpha2_InstanceGroupBootstrapSpec_To_kops_InstanceGroupBootstrapSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Bootstrap = nil
+	}
+	if in.NvidiaGPU != nil {
+		in, out := &in.NvidiaGPU, &out.NvidiaGPU
+		*out = new(kops.NvidiaGPUConfig)
+		if err := Convert_v1alpha2_NvidiaGPUConfig_To_kops_NvidiaGPUConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NvidiaGPU = nil
+	}
+	if in.Swap != nil {
+		in, out := &in.Swap, &out.Swap
+		*out = new(kops.SwapSpec)
+		if err := Convert_v1alpha2_SwapSpec_To_kops_SwapSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Swap = nil
+	}
+	if in.NodeTuning != nil {
+		in, out := &in.NodeTuning, &out.NodeTuning
+		*out = new(kops.NodeTuningSpec)
+		if err := Convert_v1alpha2_NodeTuningSpec_To_kops_NodeTuningSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeTuning = nil
+	}
+	if in.InstanceStorage != nil {
+		in, out := &in.InstanceStorage, &out.InstanceStorage
+		*out = new(kops.InstanceStorageSpec)
+		if err := Convert_v1alpha2_InstanceStorageSpec_To_kops_InstanceStorageSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.InstanceStorage = nil
+	}
+	if in.GracefulShutdown != nil {
+		in, out := &in.GracefulShutdown, &out.GracefulShutdown
+		*out = new(kops.GracefulShutdownSpec)
+		if err := Convert_v1alpha2_GracefulShutdownSpec_To_kops_GracefulShutdownSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.GracefulShutdown = nil
+	}
+	if in.Containerd != nil {
+		in, out := &in.Containerd, &out.Containerd
+		*out = new(kops.ContainerdConfig)
+		if err := Convert_v1alpha2_ContainerdConfig_To_kops_ContainerdConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Containerd = nil
+	}
+	if in.ImagePreload != nil {
+		in, out := &in.ImagePreload, &out.ImagePreload
+		*out = new(kops.ImagePreloadSpec)
+		if err := Convert_v1alpha2_ImagePreloadSpec_To_kops_ImagePreloadSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ImagePreload = nil
+	}
+	if in.SecurityModules != nil {
+		in, out := &in.SecurityModules, &out.SecurityModules
+		*out = new(kops.SecurityModulesSpec)
+		if err := Convert_v1alpha2_SecurityModulesSpec_To_kops_SecurityModulesSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SecurityModules = nil
+	}
+	out.OperatingSystem = in.OperatingSystem
+	if in.ScalingSchedules != nil {
+		in, out := &in.ScalingSchedules, &out.ScalingSchedules
+		*out = make([]kops.ScalingSchedule, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_ScalingSchedule_To_kops_ScalingSchedule(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.ScalingSchedules = nil
+	}
+	if in.APIServerEndpoint != nil {
+		in, out := &in.APIServerEndpoint, &out.APIServerEndpoint
+		*out = new(kops.APIServerEndpointSpec)
+		if err := Convert_v1alpha2_APIServerEndpointSpec_To_kops_APIServerEndpointSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.APIServerEndpoint = nil
+	}
 	return nil
 }
 
@@ -4269,62 +6032,187 @@ func autoConvert_kops_InstanceGroupSpec_To_v1alpha2_InstanceGroupSpec(in *kops.I
 			}
 		}
 	} else {
-		out.AdditionalUserData = nil
+		out.AdditionalUserData = nil
+	}
+	out.SuspendProcesses = in.SuspendProcesses
+	if in.ExternalLoadBalancers != nil {
+		in, out := &in.ExternalLoadBalancers, &out.ExternalLoadBalancers
+		*out = make([]LoadBalancer, len(*in))
+		for i := range *in {
+			if err := Convert_kops_LoadBalancer_To_v1alpha2_LoadBalancer(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.ExternalLoadBalancers = nil
+	}
+	out.DetailedInstanceMonitoring = in.DetailedInstanceMonitoring
+	if in.IAM != nil {
+		in, out := &in.IAM, &out.IAM
+		*out = new(IAMProfileSpec)
+		if err := Convert_kops_IAMProfileSpec_To_v1alpha2_IAMProfileSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.IAM = nil
+	}
+	out.SecurityGroupOverride = in.SecurityGroupOverride
+	out.InstanceProtection = in.InstanceProtection
+	out.SysctlParameters = in.SysctlParameters
+	if in.RollingUpdate != nil {
+		in, out := &in.RollingUpdate, &out.RollingUpdate
+		*out = new(RollingUpdate)
+		if err := Convert_kops_RollingUpdate_To_v1alpha2_RollingUpdate(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.RollingUpdate = nil
+	}
+	out.InstanceInterruptionBehavior = in.InstanceInterruptionBehavior
+	out.CompressUserData = in.CompressUserData
+	if in.InstanceMetadata != nil {
+		in, out := &in.InstanceMetadata, &out.InstanceMetadata
+		*out = new(InstanceMetadataOptions)
+		if err := Convert_kops_InstanceMetadataOptions_To_v1alpha2_InstanceMetadataOptions(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.InstanceMetadata = nil
+	}
+	out.UpdatePolicy = in.UpdatePolicy
+	if in.WarmPool != nil {
+		in, out := &in.WarmPool, &out.WarmPool
+		*out = new(WarmPoolSpec)
+		if err := Convert_kops_WarmPoolSpec_To_v1alpha2_WarmPoolSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.WarmPool = nil
+	}
+	if in.ShieldedInstanceConfig != nil {
+		in, out := &in.ShieldedInstanceConfig, &out.ShieldedInstanceConfig
+		*out = new(ShieldedInstanceConfig)
+		if err := Convert_kops_ShieldedInstanceConfig_To_v1alpha2_ShieldedInstanceConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ShieldedInstanceConfig = nil
+	}
+	out.ConfidentialCompute = in.ConfidentialCompute
+	out.EphemeralOSDisk = in.EphemeralOSDisk
+	out.AcceleratedNetworking = in.AcceleratedNetworking
+	if in.Machines != nil {
+		in, out := &in.Machines, &out.Machines
+		*out = make([]MetalMachineSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_MetalMachineSpec_To_v1alpha2_MetalMachineSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Machines = nil
+	}
+	if in.Bootstrap != nil {
+		in, out := &in.Bootstrap, &out.Bootstrap
+		*out = new(InstanceGroupBootstrapSpec)
+		if err := Convert_kops_InstanceGroupBootstrapSpec_To_v1alpha2_InstanceGroupBootstrapSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Bootstrap = nil
+	}
+	if in.NvidiaGPU != nil {
+		in, out := &in.NvidiaGPU, &out.NvidiaGPU
+		*out = new(NvidiaGPUConfig)
+		if err := Convert_kops_NvidiaGPUConfig_To_v1alpha2_NvidiaGPUConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NvidiaGPU = nil
+	}
+	if in.Swap != nil {
+		in, out := &in.Swap, &out.Swap
+		*out = new(SwapSpec)
+		if err := Convert_kops_SwapSpec_To_v1alpha2_SwapSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Swap = nil
+	}
+	if in.NodeTuning != nil {
+		in, out := &in.NodeTuning, &out.NodeTuning
+		*out = new(NodeTuningSpec)
+		if err := Convert_kops_NodeTuningSpec_To_v1alpha2_NodeTuningSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeTuning = nil
+	}
+	if in.InstanceStorage != nil {
+		in, out := &in.InstanceStorage, &out.InstanceStorage
+		*out = new(InstanceStorageSpec)
+		if err := Convert_kops_InstanceStorageSpec_To_v1alpha2_InstanceStorageSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.InstanceStorage = nil
+	}
+	if in.GracefulShutdown != nil {
+		in, out := &in.GracefulShutdown, &out.GracefulShutdown
+		*out = new(GracefulShutdownSpec)
+		if err := Convert_kops_GracefulShutdownSpec_To_v1alpha2_GracefulShutdownSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.GracefulShutdown = nil
 	}
-	out.SuspendProcesses = in.SuspendProcesses
-	if in.ExternalLoadBalancers != nil {
-		in, out := &in.ExternalLoadBalancers, &out.ExternalLoadBalancers
-		*out = make([]LoadBalancer, len(*in))
-		for i := range *in {
-			if err := Convert_kops_LoadBalancer_To_v1alpha2_LoadBalancer(&(*in)[i], &(*out)[i], s); err != nil {
-				return err
-			}
+	if in.Containerd != nil {
+		in, out := &in.Containerd, &out.Containerd
+		*out = new(ContainerdConfig)
+		if err := Convert_kops_ContainerdConfig_To_v1alpha2_ContainerdConfig(*in, *out, s); err != nil {
+			return err
 		}
 	} else {
-		out.ExternalLoadBalancers = nil
+		out.Containerd = nil
 	}
-	out.DetailedInstanceMonitoring = in.DetailedInstanceMonitoring
-	if in.IAM != nil {
-		in, out := &in.IAM, &out.IAM
-		*out = new(IAMProfileSpec)
-		if err := Convert_kops_IAMProfileSpec_To_v1alpha2_IAMProfileSpec(*in, *out, s); err != nil {
+	if in.ImagePreload != nil {
+		in, out := &in.ImagePreload, &out.ImagePreload
+		*out = new(ImagePreloadSpec)
+		if err := Convert_kops_ImagePreloadSpec_To_v1alpha2_ImagePreloadSpec(*in, *out, s); err != nil {
 			return err
 		}
 	} else {
-		out.IAM = nil
+		out.ImagePreload = nil
 	}
-	out.SecurityGroupOverride = in.SecurityGroupOverride
-	out.InstanceProtection = in.InstanceProtection
-	out.SysctlParameters = in.SysctlParameters
-	if in.RollingUpdate != nil {
-		in, out := &in.RollingUpdate, &out.RollingUpdate
-		*out = new(RollingUpdate)
-		if err := Convert_kops_RollingUpdate_To_v1alpha2_RollingUpdate(*in, *out, s); err != nil {
+	if in.SecurityModules != nil {
+		in, out := &in.SecurityModules, &out.SecurityModules
+		*out = new(SecurityModulesSpec)
+		if err := Convert_kops_SecurityModulesSpec_To_v1alpha2_SecurityModulesSpec(*in, *out, s); err != nil {
 			return err
 		}
 	} else {
-		out.RollingUpdate = nil
+		out.SecurityModules = nil
 	}
-	out.InstanceInterruptionBehavior = in.InstanceInterruptionBehavior
-	out.CompressUserData = in.CompressUserData
-	if in.InstanceMetadata != nil {
-		in, out := &in.InstanceMetadata, &out.InstanceMetadata
-		*out = new(InstanceMetadataOptions)
-		if err := Convert_kops_InstanceMetadataOptions_To_v1alpha2_InstanceMetadataOptions(*in, *out, s); err != nil {
-			return err
+	out.OperatingSystem = in.OperatingSystem
+	if in.ScalingSchedules != nil {
+		in, out := &in.ScalingSchedules, &out.ScalingSchedules
+		*out = make([]ScalingSchedule, len(*in))
+		for i := range *in {
+			if err := Convert_kops_ScalingSchedule_To_v1alpha2_ScalingSchedule(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
 		}
 	} else {
-		out.InstanceMetadata = nil
+		out.ScalingSchedules = nil
 	}
-	out.UpdatePolicy = in.UpdatePolicy
-	if in.WarmPool != nil {
-		in, out := &in.WarmPool, &out.WarmPool
-		*out = new(WarmPoolSpec)
-		if err := Convert_kops_WarmPoolSpec_To_v1alpha2_WarmPoolSpec(*in, *out, s); err != nil {
+	if in.APIServerEndpoint != nil {
+		in, out := &in.APIServerEndpoint, &out.APIServerEndpoint
+		*out = new(APIServerEndpointSpec)
+		if err := Convert_kops_APIServerEndpointSpec_To_v1alpha2_APIServerEndpointSpec(*in, *out, s); err != nil {
 			return err
 		}
 	} else {
-		out.WarmPool = nil
+		out.APIServerEndpoint = nil
 	}
 	return nil
 }
@@ -4356,6 +6244,30 @@ func Convert_kops_InstanceMetadataOptions_To_v1alpha2_InstanceMetadataOptions(in
 	return autoConvert_kops_InstanceMetadataOptions_To_v1alpha2_InstanceMetadataOptions(in, out, s)
 }
 
+func autoConvert_v1alpha2_InstanceStorageSpec_To_kops_InstanceStorageSpec(in *InstanceStorageSpec, out *kops.InstanceStorageSpec, s conversion.Scope) error {
+	out.Mode = in.Mode
+	out.Filesystem = in.Filesystem
+	out.Paths = in.Paths
+	return nil
+}
+
+// Convert_v1alpha2_InstanceStorageSpec_To_kops_InstanceStorageSpec is an autogenerated conversion function.
+func Convert_v1alpha2_InstanceStorageSpec_To_kops_InstanceStorageSpec(in *InstanceStorageSpec, out *kops.InstanceStorageSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_InstanceStorageSpec_To_kops_InstanceStorageSpec(in, out, s)
+}
+
+func autoConvert_kops_InstanceStorageSpec_To_v1alpha2_InstanceStorageSpec(in *kops.InstanceStorageSpec, out *InstanceStorageSpec, s conversion.Scope) error {
+	out.Mode = in.Mode
+	out.Filesystem = in.Filesystem
+	out.Paths = in.Paths
+	return nil
+}
+
+// Convert_kops_InstanceStorageSpec_To_v1alpha2_InstanceStorageSpec is an autogenerated conversion function.
+func Convert_kops_InstanceStorageSpec_To_v1alpha2_InstanceStorageSpec(in *kops.InstanceStorageSpec, out *InstanceStorageSpec, s conversion.Scope) error {
+	return autoConvert_kops_InstanceStorageSpec_To_v1alpha2_InstanceStorageSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_Keyset_To_kops_Keyset(in *Keyset, out *kops.Keyset, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha2_KeysetSpec_To_kops_KeysetSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -4599,6 +6511,7 @@ func autoConvert_v1alpha2_KubeAPIServerConfig_To_kops_KubeAPIServerConfig(in *Ku
 	out.AuditWebhookConfigFile = in.AuditWebhookConfigFile
 	out.AuditWebhookInitialBackoff = in.AuditWebhookInitialBackoff
 	out.AuditWebhookMode = in.AuditWebhookMode
+	out.AuthenticationConfigFile = in.AuthenticationConfigFile
 	out.AuthenticationTokenWebhookConfigFile = in.AuthenticationTokenWebhookConfigFile
 	out.AuthenticationTokenWebhookCacheTTL = in.AuthenticationTokenWebhookCacheTTL
 	out.AuthorizationMode = in.AuthorizationMode
@@ -4709,6 +6622,7 @@ func autoConvert_kops_KubeAPIServerConfig_To_v1alpha2_KubeAPIServerConfig(in *ko
 	out.AuditWebhookConfigFile = in.AuditWebhookConfigFile
 	out.AuditWebhookInitialBackoff = in.AuditWebhookInitialBackoff
 	out.AuditWebhookMode = in.AuditWebhookMode
+	out.AuthenticationConfigFile = in.AuthenticationConfigFile
 	out.AuthenticationTokenWebhookConfigFile = in.AuthenticationTokenWebhookConfigFile
 	out.AuthenticationTokenWebhookCacheTTL = in.AuthenticationTokenWebhookCacheTTL
 	out.AuthorizationMode = in.AuthorizationMode
@@ -4911,6 +6825,37 @@ func autoConvert_v1alpha2_KubeDNSConfig_To_kops_KubeDNSConfig(in *KubeDNSConfig,
 	} else {
 		out.NodeLocalDNS = nil
 	}
+	if in.ServerBlocks != nil {
+		in, out := &in.ServerBlocks, &out.ServerBlocks
+		*out = make([]kops.CoreDNSServerBlock, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_CoreDNSServerBlock_To_kops_CoreDNSServerBlock(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.ServerBlocks = nil
+	}
+	if in.Rewrites != nil {
+		in, out := &in.Rewrites, &out.Rewrites
+		*out = make([]kops.CoreDNSRewriteRule, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_CoreDNSRewriteRule_To_kops_CoreDNSRewriteRule(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Rewrites = nil
+	}
+	if in.Autoscaler != nil {
+		in, out := &in.Autoscaler, &out.Autoscaler
+		*out = new(kops.DNSAutoscalerConfig)
+		if err := Convert_v1alpha2_DNSAutoscalerConfig_To_kops_DNSAutoscalerConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Autoscaler = nil
+	}
 	return nil
 }
 
@@ -4944,6 +6889,37 @@ func autoConvert_kops_KubeDNSConfig_To_v1alpha2_KubeDNSConfig(in *kops.KubeDNSCo
 	} else {
 		out.NodeLocalDNS = nil
 	}
+	if in.ServerBlocks != nil {
+		in, out := &in.ServerBlocks, &out.ServerBlocks
+		*out = make([]CoreDNSServerBlock, len(*in))
+		for i := range *in {
+			if err := Convert_kops_CoreDNSServerBlock_To_v1alpha2_CoreDNSServerBlock(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.ServerBlocks = nil
+	}
+	if in.Rewrites != nil {
+		in, out := &in.Rewrites, &out.Rewrites
+		*out = make([]CoreDNSRewriteRule, len(*in))
+		for i := range *in {
+			if err := Convert_kops_CoreDNSRewriteRule_To_v1alpha2_CoreDNSRewriteRule(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Rewrites = nil
+	}
+	if in.Autoscaler != nil {
+		in, out := &in.Autoscaler, &out.Autoscaler
+		*out = new(DNSAutoscalerConfig)
+		if err := Convert_kops_DNSAutoscalerConfig_To_v1alpha2_DNSAutoscalerConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Autoscaler = nil
+	}
 	return nil
 }
 
@@ -5070,6 +7046,28 @@ func Convert_kops_KubeSchedulerConfig_To_v1alpha2_KubeSchedulerConfig(in *kops.K
 	return autoConvert_kops_KubeSchedulerConfig_To_v1alpha2_KubeSchedulerConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_KubeStateMetricsConfig_To_kops_KubeStateMetricsConfig(in *KubeStateMetricsConfig, out *kops.KubeStateMetricsConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Image = in.Image
+	return nil
+}
+
+// Convert_v1alpha2_KubeStateMetricsConfig_To_kops_KubeStateMetricsConfig is an autogenerated conversion function.
+func Convert_v1alpha2_KubeStateMetricsConfig_To_kops_KubeStateMetricsConfig(in *KubeStateMetricsConfig, out *kops.KubeStateMetricsConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha2_KubeStateMetricsConfig_To_kops_KubeStateMetricsConfig(in, out, s)
+}
+
+func autoConvert_kops_KubeStateMetricsConfig_To_v1alpha2_KubeStateMetricsConfig(in *kops.KubeStateMetricsConfig, out *KubeStateMetricsConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Image = in.Image
+	return nil
+}
+
+// Convert_kops_KubeStateMetricsConfig_To_v1alpha2_KubeStateMetricsConfig is an autogenerated conversion function.
+func Convert_kops_KubeStateMetricsConfig_To_v1alpha2_KubeStateMetricsConfig(in *kops.KubeStateMetricsConfig, out *KubeStateMetricsConfig, s conversion.Scope) error {
+	return autoConvert_kops_KubeStateMetricsConfig_To_v1alpha2_KubeStateMetricsConfig(in, out, s)
+}
+
 func autoConvert_v1alpha2_KubeletConfigSpec_To_kops_KubeletConfigSpec(in *KubeletConfigSpec, out *kops.KubeletConfigSpec, s conversion.Scope) error {
 	out.APIServers = in.APIServers
 	out.AnonymousAuth = in.AnonymousAuth
@@ -5156,6 +7154,33 @@ func autoConvert_v1alpha2_KubeletConfigSpec_To_kops_KubeletConfigSpec(in *Kubele
 	out.ContainerLogMaxSize = in.ContainerLogMaxSize
 	out.ContainerLogMaxFiles = in.ContainerLogMaxFiles
 	out.EnableCadvisorJsonEndpoints = in.EnableCadvisorJsonEndpoints
+	out.MemorySwapBehavior = in.MemorySwapBehavior
+	out.MemoryQoS = in.MemoryQoS
+	out.MemoryManagerPolicy = in.MemoryManagerPolicy
+	if in.ReservedMemory != nil {
+		in, out := &in.ReservedMemory, &out.ReservedMemory
+		*out = make([]kops.KubeletReservedMemory, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_KubeletReservedMemory_To_kops_KubeletReservedMemory(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.ReservedMemory = nil
+	}
+	out.ShutdownGracePeriod = in.ShutdownGracePeriod
+	out.ShutdownGracePeriodCriticalPods = in.ShutdownGracePeriodCriticalPods
+	if in.ShutdownGracePeriodByPodPriority != nil {
+		in, out := &in.ShutdownGracePeriodByPodPriority, &out.ShutdownGracePeriodByPodPriority
+		*out = make([]kops.KubeletShutdownGracePeriodByPodPriority, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_KubeletShutdownGracePeriodByPodPriority_To_kops_KubeletShutdownGracePeriodByPodPriority(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.ShutdownGracePeriodByPodPriority = nil
+	}
 	return nil
 }
 
@@ -5250,6 +7275,33 @@ func autoConvert_kops_KubeletConfigSpec_To_v1alpha2_KubeletConfigSpec(in *kops.K
 	out.ContainerLogMaxSize = in.ContainerLogMaxSize
 	out.ContainerLogMaxFiles = in.ContainerLogMaxFiles
 	out.EnableCadvisorJsonEndpoints = in.EnableCadvisorJsonEndpoints
+	out.MemorySwapBehavior = in.MemorySwapBehavior
+	out.MemoryQoS = in.MemoryQoS
+	out.MemoryManagerPolicy = in.MemoryManagerPolicy
+	if in.ReservedMemory != nil {
+		in, out := &in.ReservedMemory, &out.ReservedMemory
+		*out = make([]KubeletReservedMemory, len(*in))
+		for i := range *in {
+			if err := Convert_kops_KubeletReservedMemory_To_v1alpha2_KubeletReservedMemory(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.ReservedMemory = nil
+	}
+	out.ShutdownGracePeriod = in.ShutdownGracePeriod
+	out.ShutdownGracePeriodCriticalPods = in.ShutdownGracePeriodCriticalPods
+	if in.ShutdownGracePeriodByPodPriority != nil {
+		in, out := &in.ShutdownGracePeriodByPodPriority, &out.ShutdownGracePeriodByPodPriority
+		*out = make([]KubeletShutdownGracePeriodByPodPriority, len(*in))
+		for i := range *in {
+			if err := Convert_kops_KubeletShutdownGracePeriodByPodPriority_To_v1alpha2_KubeletShutdownGracePeriodByPodPriority(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.ShutdownGracePeriodByPodPriority = nil
+	}
 	return nil
 }
 
@@ -5258,6 +7310,50 @@ func Convert_kops_KubeletConfigSpec_To_v1alpha2_KubeletConfigSpec(in *kops.Kubel
 	return autoConvert_kops_KubeletConfigSpec_To_v1alpha2_KubeletConfigSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_KubeletReservedMemory_To_kops_KubeletReservedMemory(in *KubeletReservedMemory, out *kops.KubeletReservedMemory, s conversion.Scope) error {
+	out.NUMANode = in.NUMANode
+	out.Limits = in.Limits
+	return nil
+}
+
+// Convert_v1alpha2_KubeletReservedMemory_To_kops_KubeletReservedMemory is an autogenerated conversion function.
+func Convert_v1alpha2_KubeletReservedMemory_To_kops_KubeletReservedMemory(in *KubeletReservedMemory, out *kops.KubeletReservedMemory, s conversion.Scope) error {
+	return autoConvert_v1alpha2_KubeletReservedMemory_To_kops_KubeletReservedMemory(in, out, s)
+}
+
+func autoConvert_kops_KubeletReservedMemory_To_v1alpha2_KubeletReservedMemory(in *kops.KubeletReservedMemory, out *KubeletReservedMemory, s conversion.Scope) error {
+	out.NUMANode = in.NUMANode
+	out.Limits = in.Limits
+	return nil
+}
+
+// Convert_kops_KubeletReservedMemory_To_v1alpha2_KubeletReservedMemory is an autogenerated conversion function.
+func Convert_kops_KubeletReservedMemory_To_v1alpha2_KubeletReservedMemory(in *kops.KubeletReservedMemory, out *KubeletReservedMemory, s conversion.Scope) error {
+	return autoConvert_kops_KubeletReservedMemory_To_v1alpha2_KubeletReservedMemory(in, out, s)
+}
+
+func autoConvert_v1alpha2_KubeletShutdownGracePeriodByPodPriority_To_kops_KubeletShutdownGracePeriodByPodPriority(in *KubeletShutdownGracePeriodByPodPriority, out *kops.KubeletShutdownGracePeriodByPodPriority, s conversion.Scope) error {
+	out.Priority = in.Priority
+	out.ShutdownGracePeriodSeconds = in.ShutdownGracePeriodSeconds
+	return nil
+}
+
+// Convert_v1alpha2_KubeletShutdownGracePeriodByPodPriority_To_kops_KubeletShutdownGracePeriodByPodPriority is an autogenerated conversion function.
+func Convert_v1alpha2_KubeletShutdownGracePeriodByPodPriority_To_kops_KubeletShutdownGracePeriodByPodPriority(in *KubeletShutdownGracePeriodByPodPriority, out *kops.KubeletShutdownGracePeriodByPodPriority, s conversion.Scope) error {
+	return autoConvert_v1alpha2_KubeletShutdownGracePeriodByPodPriority_To_kops_KubeletShutdownGracePeriodByPodPriority(in, out, s)
+}
+
+func autoConvert_kops_KubeletShutdownGracePeriodByPodPriority_To_v1alpha2_KubeletShutdownGracePeriodByPodPriority(in *kops.KubeletShutdownGracePeriodByPodPriority, out *KubeletShutdownGracePeriodByPodPriority, s conversion.Scope) error {
+	out.Priority = in.Priority
+	out.ShutdownGracePeriodSeconds = in.ShutdownGracePeriodSeconds
+	return nil
+}
+
+// Convert_kops_KubeletShutdownGracePeriodByPodPriority_To_v1alpha2_KubeletShutdownGracePeriodByPodPriority is an autogenerated conversion function.
+func Convert_kops_KubeletShutdownGracePeriodByPodPriority_To_v1alpha2_KubeletShutdownGracePeriodByPodPriority(in *kops.KubeletShutdownGracePeriodByPodPriority, out *KubeletShutdownGracePeriodByPodPriority, s conversion.Scope) error {
+	return autoConvert_kops_KubeletShutdownGracePeriodByPodPriority_To_v1alpha2_KubeletShutdownGracePeriodByPodPriority(in, out, s)
+}
+
 func autoConvert_v1alpha2_KubenetNetworkingSpec_To_kops_KubenetNetworkingSpec(in *KubenetNetworkingSpec, out *kops.KubenetNetworkingSpec, s conversion.Scope) error {
 	return nil
 }
@@ -5369,6 +7465,15 @@ func autoConvert_v1alpha2_LoadBalancerAccessSpec_To_kops_LoadBalancerAccessSpec(
 	} else {
 		out.Subnets = nil
 	}
+	if in.PrivateLink != nil {
+		in, out := &in.PrivateLink, &out.PrivateLink
+		*out = new(kops.PrivateLinkSpec)
+		if err := Convert_v1alpha2_PrivateLinkSpec_To_kops_PrivateLinkSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PrivateLink = nil
+	}
 	return nil
 }
 
@@ -5398,6 +7503,15 @@ func autoConvert_kops_LoadBalancerAccessSpec_To_v1alpha2_LoadBalancerAccessSpec(
 	} else {
 		out.Subnets = nil
 	}
+	if in.PrivateLink != nil {
+		in, out := &in.PrivateLink, &out.PrivateLink
+		*out = new(PrivateLinkSpec)
+		if err := Convert_kops_PrivateLinkSpec_To_v1alpha2_PrivateLinkSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PrivateLink = nil
+	}
 	return nil
 }
 
@@ -5418,16 +7532,198 @@ func Convert_v1alpha2_LoadBalancerSubnetSpec_To_kops_LoadBalancerSubnetSpec(in *
 	return autoConvert_v1alpha2_LoadBalancerSubnetSpec_To_kops_LoadBalancerSubnetSpec(in, out, s)
 }
 
-func autoConvert_kops_LoadBalancerSubnetSpec_To_v1alpha2_LoadBalancerSubnetSpec(in *kops.LoadBalancerSubnetSpec, out *LoadBalancerSubnetSpec, s conversion.Scope) error {
-	out.Name = in.Name
-	out.PrivateIPv4Address = in.PrivateIPv4Address
-	out.AllocationID = in.AllocationID
+func autoConvert_kops_LoadBalancerSubnetSpec_To_v1alpha2_LoadBalancerSubnetSpec(in *kops.LoadBalancerSubnetSpec, out *LoadBalancerSubnetSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.PrivateIPv4Address = in.PrivateIPv4Address
+	out.AllocationID = in.AllocationID
+	return nil
+}
+
+// Convert_kops_LoadBalancerSubnetSpec_To_v1alpha2_LoadBalancerSubnetSpec is an autogenerated conversion function.
+func Convert_kops_LoadBalancerSubnetSpec_To_v1alpha2_LoadBalancerSubnetSpec(in *kops.LoadBalancerSubnetSpec, out *LoadBalancerSubnetSpec, s conversion.Scope) error {
+	return autoConvert_kops_LoadBalancerSubnetSpec_To_v1alpha2_LoadBalancerSubnetSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_LoggingCloudWatchSpec_To_kops_LoggingCloudWatchSpec(in *LoggingCloudWatchSpec, out *kops.LoggingCloudWatchSpec, s conversion.Scope) error {
+	out.LogGroup = in.LogGroup
+	out.Region = in.Region
+	return nil
+}
+
+// Convert_v1alpha2_LoggingCloudWatchSpec_To_kops_LoggingCloudWatchSpec is an autogenerated conversion function.
+func Convert_v1alpha2_LoggingCloudWatchSpec_To_kops_LoggingCloudWatchSpec(in *LoggingCloudWatchSpec, out *kops.LoggingCloudWatchSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_LoggingCloudWatchSpec_To_kops_LoggingCloudWatchSpec(in, out, s)
+}
+
+func autoConvert_kops_LoggingCloudWatchSpec_To_v1alpha2_LoggingCloudWatchSpec(in *kops.LoggingCloudWatchSpec, out *LoggingCloudWatchSpec, s conversion.Scope) error {
+	out.LogGroup = in.LogGroup
+	out.Region = in.Region
+	return nil
+}
+
+// Convert_kops_LoggingCloudWatchSpec_To_v1alpha2_LoggingCloudWatchSpec is an autogenerated conversion function.
+func Convert_kops_LoggingCloudWatchSpec_To_v1alpha2_LoggingCloudWatchSpec(in *kops.LoggingCloudWatchSpec, out *LoggingCloudWatchSpec, s conversion.Scope) error {
+	return autoConvert_kops_LoggingCloudWatchSpec_To_v1alpha2_LoggingCloudWatchSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_LoggingLokiSpec_To_kops_LoggingLokiSpec(in *LoggingLokiSpec, out *kops.LoggingLokiSpec, s conversion.Scope) error {
+	out.URL = in.URL
+	out.TenantID = in.TenantID
+	return nil
+}
+
+// Convert_v1alpha2_LoggingLokiSpec_To_kops_LoggingLokiSpec is an autogenerated conversion function.
+func Convert_v1alpha2_LoggingLokiSpec_To_kops_LoggingLokiSpec(in *LoggingLokiSpec, out *kops.LoggingLokiSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_LoggingLokiSpec_To_kops_LoggingLokiSpec(in, out, s)
+}
+
+func autoConvert_kops_LoggingLokiSpec_To_v1alpha2_LoggingLokiSpec(in *kops.LoggingLokiSpec, out *LoggingLokiSpec, s conversion.Scope) error {
+	out.URL = in.URL
+	out.TenantID = in.TenantID
+	return nil
+}
+
+// Convert_kops_LoggingLokiSpec_To_v1alpha2_LoggingLokiSpec is an autogenerated conversion function.
+func Convert_kops_LoggingLokiSpec_To_v1alpha2_LoggingLokiSpec(in *kops.LoggingLokiSpec, out *LoggingLokiSpec, s conversion.Scope) error {
+	return autoConvert_kops_LoggingLokiSpec_To_v1alpha2_LoggingLokiSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_LoggingS3Spec_To_kops_LoggingS3Spec(in *LoggingS3Spec, out *kops.LoggingS3Spec, s conversion.Scope) error {
+	out.Bucket = in.Bucket
+	out.Prefix = in.Prefix
+	out.Region = in.Region
+	return nil
+}
+
+// Convert_v1alpha2_LoggingS3Spec_To_kops_LoggingS3Spec is an autogenerated conversion function.
+func Convert_v1alpha2_LoggingS3Spec_To_kops_LoggingS3Spec(in *LoggingS3Spec, out *kops.LoggingS3Spec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_LoggingS3Spec_To_kops_LoggingS3Spec(in, out, s)
+}
+
+func autoConvert_kops_LoggingS3Spec_To_v1alpha2_LoggingS3Spec(in *kops.LoggingS3Spec, out *LoggingS3Spec, s conversion.Scope) error {
+	out.Bucket = in.Bucket
+	out.Prefix = in.Prefix
+	out.Region = in.Region
+	return nil
+}
+
+// Convert_kops_LoggingS3Spec_To_v1alpha2_LoggingS3Spec is an autogenerated conversion function.
+func Convert_kops_LoggingS3Spec_To_v1alpha2_LoggingS3Spec(in *kops.LoggingS3Spec, out *LoggingS3Spec, s conversion.Scope) error {
+	return autoConvert_kops_LoggingS3Spec_To_v1alpha2_LoggingS3Spec(in, out, s)
+}
+
+func autoConvert_v1alpha2_LoggingSpec_To_kops_LoggingSpec(in *LoggingSpec, out *kops.LoggingSpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Image = in.Image
+	if in.CloudWatch != nil {
+		in, out := &in.CloudWatch, &out.CloudWatch
+		*out = new(kops.LoggingCloudWatchSpec)
+		if err := Convert_v1alpha2_LoggingCloudWatchSpec_To_kops_LoggingCloudWatchSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.CloudWatch = nil
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(kops.LoggingS3Spec)
+		if err := Convert_v1alpha2_LoggingS3Spec_To_kops_LoggingS3Spec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.S3 = nil
+	}
+	if in.Stackdriver != nil {
+		in, out := &in.Stackdriver, &out.Stackdriver
+		*out = new(kops.LoggingStackdriverSpec)
+		if err := Convert_v1alpha2_LoggingStackdriverSpec_To_kops_LoggingStackdriverSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Stackdriver = nil
+	}
+	if in.Loki != nil {
+		in, out := &in.Loki, &out.Loki
+		*out = new(kops.LoggingLokiSpec)
+		if err := Convert_v1alpha2_LoggingLokiSpec_To_kops_LoggingLokiSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Loki = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_LoggingSpec_To_kops_LoggingSpec is an autogenerated conversion function.
+func Convert_v1alpha2_LoggingSpec_To_kops_LoggingSpec(in *LoggingSpec, out *kops.LoggingSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_LoggingSpec_To_kops_LoggingSpec(in, out, s)
+}
+
+func autoConvert_kops_LoggingSpec_To_v1alpha2_LoggingSpec(in *kops.LoggingSpec, out *LoggingSpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Image = in.Image
+	if in.CloudWatch != nil {
+		in, out := &in.CloudWatch, &out.CloudWatch
+		*out = new(LoggingCloudWatchSpec)
+		if err := Convert_kops_LoggingCloudWatchSpec_To_v1alpha2_LoggingCloudWatchSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.CloudWatch = nil
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(LoggingS3Spec)
+		if err := Convert_kops_LoggingS3Spec_To_v1alpha2_LoggingS3Spec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.S3 = nil
+	}
+	if in.Stackdriver != nil {
+		in, out := &in.Stackdriver, &out.Stackdriver
+		*out = new(LoggingStackdriverSpec)
+		if err := Convert_kops_LoggingStackdriverSpec_To_v1alpha2_LoggingStackdriverSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Stackdriver = nil
+	}
+	if in.Loki != nil {
+		in, out := &in.Loki, &out.Loki
+		*out = new(LoggingLokiSpec)
+		if err := Convert_kops_LoggingLokiSpec_To_v1alpha2_LoggingLokiSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Loki = nil
+	}
+	return nil
+}
+
+// Convert_kops_LoggingSpec_To_v1alpha2_LoggingSpec is an autogenerated conversion function.
+func Convert_kops_LoggingSpec_To_v1alpha2_LoggingSpec(in *kops.LoggingSpec, out *LoggingSpec, s conversion.Scope) error {
+	return autoConvert_kops_LoggingSpec_To_v1alpha2_LoggingSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_LoggingStackdriverSpec_To_kops_LoggingStackdriverSpec(in *LoggingStackdriverSpec, out *kops.LoggingStackdriverSpec, s conversion.Scope) error {
+	out.ProjectID = in.ProjectID
+	return nil
+}
+
+// Convert_v1alpha2_LoggingStackdriverSpec_To_kops_LoggingStackdriverSpec is an autogenerated conversion function.
+func Convert_v1alpha2_LoggingStackdriverSpec_To_kops_LoggingStackdriverSpec(in *LoggingStackdriverSpec, out *kops.LoggingStackdriverSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_LoggingStackdriverSpec_To_kops_LoggingStackdriverSpec(in, out, s)
+}
+
+func autoConvert_kops_LoggingStackdriverSpec_To_v1alpha2_LoggingStackdriverSpec(in *kops.LoggingStackdriverSpec, out *LoggingStackdriverSpec, s conversion.Scope) error {
+	out.ProjectID = in.ProjectID
 	return nil
 }
 
-// Convert_kops_LoadBalancerSubnetSpec_To_v1alpha2_LoadBalancerSubnetSpec is an autogenerated conversion function.
-func Convert_kops_LoadBalancerSubnetSpec_To_v1alpha2_LoadBalancerSubnetSpec(in *kops.LoadBalancerSubnetSpec, out *LoadBalancerSubnetSpec, s conversion.Scope) error {
-	return autoConvert_kops_LoadBalancerSubnetSpec_To_v1alpha2_LoadBalancerSubnetSpec(in, out, s)
+// Convert_kops_LoggingStackdriverSpec_To_v1alpha2_LoggingStackdriverSpec is an autogenerated conversion function.
+func Convert_kops_LoggingStackdriverSpec_To_v1alpha2_LoggingStackdriverSpec(in *kops.LoggingStackdriverSpec, out *LoggingStackdriverSpec, s conversion.Scope) error {
+	return autoConvert_kops_LoggingStackdriverSpec_To_v1alpha2_LoggingStackdriverSpec(in, out, s)
 }
 
 func autoConvert_v1alpha2_LyftVPCNetworkingSpec_To_kops_LyftVPCNetworkingSpec(in *LyftVPCNetworkingSpec, out *kops.LyftVPCNetworkingSpec, s conversion.Scope) error {
@@ -5450,6 +7746,32 @@ func Convert_kops_LyftVPCNetworkingSpec_To_v1alpha2_LyftVPCNetworkingSpec(in *ko
 	return autoConvert_kops_LyftVPCNetworkingSpec_To_v1alpha2_LyftVPCNetworkingSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_MetalMachineSpec_To_kops_MetalMachineSpec(in *MetalMachineSpec, out *kops.MetalMachineSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Address = in.Address
+	out.SSHUser = in.SSHUser
+	out.SSHHostKeyFingerprint = in.SSHHostKeyFingerprint
+	return nil
+}
+
+// Convert_v1alpha2_MetalMachineSpec_To_kops_MetalMachineSpec is an autogenerated conversion function.
+func Convert_v1alpha2_MetalMachineSpec_To_kops_MetalMachineSpec(in *MetalMachineSpec, out *kops.MetalMachineSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_MetalMachineSpec_To_kops_MetalMachineSpec(in, out, s)
+}
+
+func autoConvert_kops_MetalMachineSpec_To_v1alpha2_MetalMachineSpec(in *kops.MetalMachineSpec, out *MetalMachineSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Address = in.Address
+	out.SSHUser = in.SSHUser
+	out.SSHHostKeyFingerprint = in.SSHHostKeyFingerprint
+	return nil
+}
+
+// Convert_kops_MetalMachineSpec_To_v1alpha2_MetalMachineSpec is an autogenerated conversion function.
+func Convert_kops_MetalMachineSpec_To_v1alpha2_MetalMachineSpec(in *kops.MetalMachineSpec, out *MetalMachineSpec, s conversion.Scope) error {
+	return autoConvert_kops_MetalMachineSpec_To_v1alpha2_MetalMachineSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_MetricsServerConfig_To_kops_MetricsServerConfig(in *MetricsServerConfig, out *kops.MetricsServerConfig, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.Image = in.Image
@@ -5660,6 +7982,15 @@ func autoConvert_v1alpha2_NetworkingSpec_To_kops_NetworkingSpec(in *NetworkingSp
 	} else {
 		out.GCE = nil
 	}
+	if in.Antrea != nil {
+		in, out := &in.Antrea, &out.Antrea
+		*out = new(kops.AntreaNetworkingSpec)
+		if err := Convert_v1alpha2_AntreaNetworkingSpec_To_kops_AntreaNetworkingSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Antrea = nil
+	}
 	return nil
 }
 
@@ -5804,6 +8135,15 @@ func autoConvert_kops_NetworkingSpec_To_v1alpha2_NetworkingSpec(in *kops.Network
 	} else {
 		out.GCE = nil
 	}
+	if in.Antrea != nil {
+		in, out := &in.Antrea, &out.Antrea
+		*out = new(AntreaNetworkingSpec)
+		if err := Convert_kops_AntreaNetworkingSpec_To_v1alpha2_AntreaNetworkingSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Antrea = nil
+	}
 	return nil
 }
 
@@ -5882,12 +8222,56 @@ func Convert_kops_NodeAuthorizerSpec_To_v1alpha2_NodeAuthorizerSpec(in *kops.Nod
 	return autoConvert_kops_NodeAuthorizerSpec_To_v1alpha2_NodeAuthorizerSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_NodeBootstrapSpec_To_kops_NodeBootstrapSpec(in *NodeBootstrapSpec, out *kops.NodeBootstrapSpec, s conversion.Scope) error {
+	out.UseKopsController = in.UseKopsController
+	out.JoinTokens = in.JoinTokens
+	return nil
+}
+
+// Convert_v1alpha2_NodeBootstrapSpec_To_kops_NodeBootstrapSpec is an autogenerated conversion function.
+func Convert_v1alpha2_NodeBootstrapSpec_To_kops_NodeBootstrapSpec(in *NodeBootstrapSpec, out *kops.NodeBootstrapSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_NodeBootstrapSpec_To_kops_NodeBootstrapSpec(in, out, s)
+}
+
+func autoConvert_kops_NodeBootstrapSpec_To_v1alpha2_NodeBootstrapSpec(in *kops.NodeBootstrapSpec, out *NodeBootstrapSpec, s conversion.Scope) error {
+	out.UseKopsController = in.UseKopsController
+	out.JoinTokens = in.JoinTokens
+	return nil
+}
+
+// Convert_kops_NodeBootstrapSpec_To_v1alpha2_NodeBootstrapSpec is an autogenerated conversion function.
+func Convert_kops_NodeBootstrapSpec_To_v1alpha2_NodeBootstrapSpec(in *kops.NodeBootstrapSpec, out *NodeBootstrapSpec, s conversion.Scope) error {
+	return autoConvert_kops_NodeBootstrapSpec_To_v1alpha2_NodeBootstrapSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_NodeCertificatesSpec_To_kops_NodeCertificatesSpec(in *NodeCertificatesSpec, out *kops.NodeCertificatesSpec, s conversion.Scope) error {
+	out.KubeletValidity = in.KubeletValidity
+	return nil
+}
+
+// Convert_v1alpha2_NodeCertificatesSpec_To_kops_NodeCertificatesSpec is an autogenerated conversion function.
+func Convert_v1alpha2_NodeCertificatesSpec_To_kops_NodeCertificatesSpec(in *NodeCertificatesSpec, out *kops.NodeCertificatesSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_NodeCertificatesSpec_To_kops_NodeCertificatesSpec(in, out, s)
+}
+
+func autoConvert_kops_NodeCertificatesSpec_To_v1alpha2_NodeCertificatesSpec(in *kops.NodeCertificatesSpec, out *NodeCertificatesSpec, s conversion.Scope) error {
+	out.KubeletValidity = in.KubeletValidity
+	return nil
+}
+
+// Convert_kops_NodeCertificatesSpec_To_v1alpha2_NodeCertificatesSpec is an autogenerated conversion function.
+func Convert_kops_NodeCertificatesSpec_To_v1alpha2_NodeCertificatesSpec(in *kops.NodeCertificatesSpec, out *NodeCertificatesSpec, s conversion.Scope) error {
+	return autoConvert_kops_NodeCertificatesSpec_To_v1alpha2_NodeCertificatesSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_NodeLocalDNSConfig_To_kops_NodeLocalDNSConfig(in *NodeLocalDNSConfig, out *kops.NodeLocalDNSConfig, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.LocalIP = in.LocalIP
 	out.ForwardToKubeDNS = in.ForwardToKubeDNS
 	out.MemoryRequest = in.MemoryRequest
 	out.CPURequest = in.CPURequest
+	out.PrometheusMetricsPort = in.PrometheusMetricsPort
+	out.UpstreamZones = in.UpstreamZones
 	return nil
 }
 
@@ -5902,6 +8286,8 @@ func autoConvert_kops_NodeLocalDNSConfig_To_v1alpha2_NodeLocalDNSConfig(in *kops
 	out.ForwardToKubeDNS = in.ForwardToKubeDNS
 	out.MemoryRequest = in.MemoryRequest
 	out.CPURequest = in.CPURequest
+	out.PrometheusMetricsPort = in.PrometheusMetricsPort
+	out.UpstreamZones = in.UpstreamZones
 	return nil
 }
 
@@ -5944,6 +8330,90 @@ func Convert_kops_NodeTerminationHandlerConfig_To_v1alpha2_NodeTerminationHandle
 	return autoConvert_kops_NodeTerminationHandlerConfig_To_v1alpha2_NodeTerminationHandlerConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_NodeTuningSpec_To_kops_NodeTuningSpec(in *NodeTuningSpec, out *kops.NodeTuningSpec, s conversion.Scope) error {
+	out.Sysctls = in.Sysctls
+	out.KernelModules = in.KernelModules
+	out.HugePages = in.HugePages
+	out.UdevRules = in.UdevRules
+	return nil
+}
+
+// Convert_v1alpha2_NodeTuningSpec_To_kops_NodeTuningSpec is an autogenerated conversion function.
+func Convert_v1alpha2_NodeTuningSpec_To_kops_NodeTuningSpec(in *NodeTuningSpec, out *kops.NodeTuningSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_NodeTuningSpec_To_kops_NodeTuningSpec(in, out, s)
+}
+
+func autoConvert_kops_NodeTuningSpec_To_v1alpha2_NodeTuningSpec(in *kops.NodeTuningSpec, out *NodeTuningSpec, s conversion.Scope) error {
+	out.Sysctls = in.Sysctls
+	out.KernelModules = in.KernelModules
+	out.HugePages = in.HugePages
+	out.UdevRules = in.UdevRules
+	return nil
+}
+
+// Convert_kops_NodeTuningSpec_To_v1alpha2_NodeTuningSpec is an autogenerated conversion function.
+func Convert_kops_NodeTuningSpec_To_v1alpha2_NodeTuningSpec(in *kops.NodeTuningSpec, out *NodeTuningSpec, s conversion.Scope) error {
+	return autoConvert_kops_NodeTuningSpec_To_v1alpha2_NodeTuningSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_NvidiaGPUConfig_To_kops_NvidiaGPUConfig(in *NvidiaGPUConfig, out *kops.NvidiaGPUConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.DriverPackage = in.DriverPackage
+	out.SkipInstall = in.SkipInstall
+	return nil
+}
+
+// Convert_v1alpha2_NvidiaGPUConfig_To_kops_NvidiaGPUConfig is an autogenerated conversion function.
+func Convert_v1alpha2_NvidiaGPUConfig_To_kops_NvidiaGPUConfig(in *NvidiaGPUConfig, out *kops.NvidiaGPUConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha2_NvidiaGPUConfig_To_kops_NvidiaGPUConfig(in, out, s)
+}
+
+func autoConvert_kops_NvidiaGPUConfig_To_v1alpha2_NvidiaGPUConfig(in *kops.NvidiaGPUConfig, out *NvidiaGPUConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.DriverPackage = in.DriverPackage
+	out.SkipInstall = in.SkipInstall
+	return nil
+}
+
+// Convert_kops_NvidiaGPUConfig_To_v1alpha2_NvidiaGPUConfig is an autogenerated conversion function.
+func Convert_kops_NvidiaGPUConfig_To_v1alpha2_NvidiaGPUConfig(in *kops.NvidiaGPUConfig, out *NvidiaGPUConfig, s conversion.Scope) error {
+	return autoConvert_kops_NvidiaGPUConfig_To_v1alpha2_NvidiaGPUConfig(in, out, s)
+}
+
+func autoConvert_v1alpha2_OIDCAuthenticationSpec_To_kops_OIDCAuthenticationSpec(in *OIDCAuthenticationSpec, out *kops.OIDCAuthenticationSpec, s conversion.Scope) error {
+	out.IssuerURL = in.IssuerURL
+	out.ClientID = in.ClientID
+	out.CertificateAuthority = in.CertificateAuthority
+	out.UsernameClaim = in.UsernameClaim
+	out.UsernamePrefix = in.UsernamePrefix
+	out.GroupsClaim = in.GroupsClaim
+	out.GroupsPrefix = in.GroupsPrefix
+	out.RequiredClaims = in.RequiredClaims
+	return nil
+}
+
+// Convert_v1alpha2_OIDCAuthenticationSpec_To_kops_OIDCAuthenticationSpec is an autogenerated conversion function.
+func Convert_v1alpha2_OIDCAuthenticationSpec_To_kops_OIDCAuthenticationSpec(in *OIDCAuthenticationSpec, out *kops.OIDCAuthenticationSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_OIDCAuthenticationSpec_To_kops_OIDCAuthenticationSpec(in, out, s)
+}
+
+func autoConvert_kops_OIDCAuthenticationSpec_To_v1alpha2_OIDCAuthenticationSpec(in *kops.OIDCAuthenticationSpec, out *OIDCAuthenticationSpec, s conversion.Scope) error {
+	out.IssuerURL = in.IssuerURL
+	out.ClientID = in.ClientID
+	out.CertificateAuthority = in.CertificateAuthority
+	out.UsernameClaim = in.UsernameClaim
+	out.UsernamePrefix = in.UsernamePrefix
+	out.GroupsClaim = in.GroupsClaim
+	out.GroupsPrefix = in.GroupsPrefix
+	out.RequiredClaims = in.RequiredClaims
+	return nil
+}
+
+// Convert_kops_OIDCAuthenticationSpec_To_v1alpha2_OIDCAuthenticationSpec is an autogenerated conversion function.
+func Convert_kops_OIDCAuthenticationSpec_To_v1alpha2_OIDCAuthenticationSpec(in *kops.OIDCAuthenticationSpec, out *OIDCAuthenticationSpec, s conversion.Scope) error {
+	return autoConvert_kops_OIDCAuthenticationSpec_To_v1alpha2_OIDCAuthenticationSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_OpenstackBlockStorageConfig_To_kops_OpenstackBlockStorageConfig(in *OpenstackBlockStorageConfig, out *kops.OpenstackBlockStorageConfig, s conversion.Scope) error {
 	out.Version = in.Version
 	out.IgnoreAZ = in.IgnoreAZ
@@ -6093,6 +8563,7 @@ func autoConvert_v1alpha2_OpenstackLoadbalancerConfig_To_kops_OpenstackLoadbalan
 	out.FloatingSubnet = in.FloatingSubnet
 	out.SubnetID = in.SubnetID
 	out.ManageSecGroups = in.ManageSecGroups
+	out.FloatingIP = in.FloatingIP
 	return nil
 }
 
@@ -6110,6 +8581,7 @@ func autoConvert_kops_OpenstackLoadbalancerConfig_To_v1alpha2_OpenstackLoadbalan
 	out.FloatingSubnet = in.FloatingSubnet
 	out.SubnetID = in.SubnetID
 	out.ManageSecGroups = in.ManageSecGroups
+	out.FloatingIP = in.FloatingIP
 	return nil
 }
 
@@ -6193,6 +8665,8 @@ func autoConvert_v1alpha2_PackagesConfig_To_kops_PackagesConfig(in *PackagesConf
 	out.HashArm64 = in.HashArm64
 	out.UrlAmd64 = in.UrlAmd64
 	out.UrlArm64 = in.UrlArm64
+	out.HashWindows = in.HashWindows
+	out.UrlWindows = in.UrlWindows
 	return nil
 }
 
@@ -6206,6 +8680,8 @@ func autoConvert_kops_PackagesConfig_To_v1alpha2_PackagesConfig(in *kops.Package
 	out.HashArm64 = in.HashArm64
 	out.UrlAmd64 = in.UrlAmd64
 	out.UrlArm64 = in.UrlArm64
+	out.HashWindows = in.HashWindows
+	out.UrlWindows = in.UrlWindows
 	return nil
 }
 
@@ -6214,6 +8690,106 @@ func Convert_kops_PackagesConfig_To_v1alpha2_PackagesConfig(in *kops.PackagesCon
 	return autoConvert_kops_PackagesConfig_To_v1alpha2_PackagesConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_PodSecuritySpec_To_kops_PodSecuritySpec(in *PodSecuritySpec, out *kops.PodSecuritySpec, s conversion.Scope) error {
+	out.Enforce = in.Enforce
+	out.Audit = in.Audit
+	out.Warn = in.Warn
+	out.Version = in.Version
+	out.ExemptNamespaces = in.ExemptNamespaces
+	out.ExemptUsernames = in.ExemptUsernames
+	out.ExemptRuntimeClasses = in.ExemptRuntimeClasses
+	return nil
+}
+
+// Convert_v1alpha2_PodSecuritySpec_To_kops_PodSecuritySpec is an autogenerated conversion function.
+func Convert_v1alpha2_PodSecuritySpec_To_kops_PodSecuritySpec(in *PodSecuritySpec, out *kops.PodSecuritySpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_PodSecuritySpec_To_kops_PodSecuritySpec(in, out, s)
+}
+
+func autoConvert_kops_PodSecuritySpec_To_v1alpha2_PodSecuritySpec(in *kops.PodSecuritySpec, out *PodSecuritySpec, s conversion.Scope) error {
+	out.Enforce = in.Enforce
+	out.Audit = in.Audit
+	out.Warn = in.Warn
+	out.Version = in.Version
+	out.ExemptNamespaces = in.ExemptNamespaces
+	out.ExemptUsernames = in.ExemptUsernames
+	out.ExemptRuntimeClasses = in.ExemptRuntimeClasses
+	return nil
+}
+
+// Convert_kops_PodSecuritySpec_To_v1alpha2_PodSecuritySpec is an autogenerated conversion function.
+func Convert_kops_PodSecuritySpec_To_v1alpha2_PodSecuritySpec(in *kops.PodSecuritySpec, out *PodSecuritySpec, s conversion.Scope) error {
+	return autoConvert_kops_PodSecuritySpec_To_v1alpha2_PodSecuritySpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_PrivateLinkEndpointSpec_To_kops_PrivateLinkEndpointSpec(in *PrivateLinkEndpointSpec, out *kops.PrivateLinkEndpointSpec, s conversion.Scope) error {
+	out.VPCID = in.VPCID
+	out.SubnetIDs = in.SubnetIDs
+	out.SecurityGroupIDs = in.SecurityGroupIDs
+	return nil
+}
+
+// Convert_v1alpha2_PrivateLinkEndpointSpec_To_kops_PrivateLinkEndpointSpec is an autogenerated conversion function.
+func Convert_v1alpha2_PrivateLinkEndpointSpec_To_kops_PrivateLinkEndpointSpec(in *PrivateLinkEndpointSpec, out *kops.PrivateLinkEndpointSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_PrivateLinkEndpointSpec_To_kops_PrivateLinkEndpointSpec(in, out, s)
+}
+
+func autoConvert_kops_PrivateLinkEndpointSpec_To_v1alpha2_PrivateLinkEndpointSpec(in *kops.PrivateLinkEndpointSpec, out *PrivateLinkEndpointSpec, s conversion.Scope) error {
+	out.VPCID = in.VPCID
+	out.SubnetIDs = in.SubnetIDs
+	out.SecurityGroupIDs = in.SecurityGroupIDs
+	return nil
+}
+
+// Convert_kops_PrivateLinkEndpointSpec_To_v1alpha2_PrivateLinkEndpointSpec is an autogenerated conversion function.
+func Convert_kops_PrivateLinkEndpointSpec_To_v1alpha2_PrivateLinkEndpointSpec(in *kops.PrivateLinkEndpointSpec, out *PrivateLinkEndpointSpec, s conversion.Scope) error {
+	return autoConvert_kops_PrivateLinkEndpointSpec_To_v1alpha2_PrivateLinkEndpointSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_PrivateLinkSpec_To_kops_PrivateLinkSpec(in *PrivateLinkSpec, out *kops.PrivateLinkSpec, s conversion.Scope) error {
+	out.AllowedPrincipals = in.AllowedPrincipals
+	out.AcceptanceRequired = in.AcceptanceRequired
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]kops.PrivateLinkEndpointSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_PrivateLinkEndpointSpec_To_kops_PrivateLinkEndpointSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Endpoints = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_PrivateLinkSpec_To_kops_PrivateLinkSpec is an autogenerated conversion function.
+func Convert_v1alpha2_PrivateLinkSpec_To_kops_PrivateLinkSpec(in *PrivateLinkSpec, out *kops.PrivateLinkSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_PrivateLinkSpec_To_kops_PrivateLinkSpec(in, out, s)
+}
+
+func autoConvert_kops_PrivateLinkSpec_To_v1alpha2_PrivateLinkSpec(in *kops.PrivateLinkSpec, out *PrivateLinkSpec, s conversion.Scope) error {
+	out.AllowedPrincipals = in.AllowedPrincipals
+	out.AcceptanceRequired = in.AcceptanceRequired
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]PrivateLinkEndpointSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_PrivateLinkEndpointSpec_To_v1alpha2_PrivateLinkEndpointSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Endpoints = nil
+	}
+	return nil
+}
+
+// Convert_kops_PrivateLinkSpec_To_v1alpha2_PrivateLinkSpec is an autogenerated conversion function.
+func Convert_kops_PrivateLinkSpec_To_v1alpha2_PrivateLinkSpec(in *kops.PrivateLinkSpec, out *PrivateLinkSpec, s conversion.Scope) error {
+	return autoConvert_kops_PrivateLinkSpec_To_v1alpha2_PrivateLinkSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_RBACAuthorizationSpec_To_kops_RBACAuthorizationSpec(in *RBACAuthorizationSpec, out *kops.RBACAuthorizationSpec, s conversion.Scope) error {
 	return nil
 }
@@ -6236,6 +8812,7 @@ func autoConvert_v1alpha2_RollingUpdate_To_kops_RollingUpdate(in *RollingUpdate,
 	out.DrainAndTerminate = in.DrainAndTerminate
 	out.MaxUnavailable = in.MaxUnavailable
 	out.MaxSurge = in.MaxSurge
+	out.DrainTimeout = in.DrainTimeout
 	return nil
 }
 
@@ -6248,6 +8825,7 @@ func autoConvert_kops_RollingUpdate_To_v1alpha2_RollingUpdate(in *kops.RollingUp
 	out.DrainAndTerminate = in.DrainAndTerminate
 	out.MaxUnavailable = in.MaxUnavailable
 	out.MaxSurge = in.MaxSurge
+	out.DrainTimeout = in.DrainTimeout
 	return nil
 }
 
@@ -6366,6 +8944,78 @@ func Convert_kops_SSHCredentialSpec_To_v1alpha2_SSHCredentialSpec(in *kops.SSHCr
 	return autoConvert_kops_SSHCredentialSpec_To_v1alpha2_SSHCredentialSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_ScalingSchedule_To_kops_ScalingSchedule(in *ScalingSchedule, out *kops.ScalingSchedule, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Schedule = in.Schedule
+	out.TimeZone = in.TimeZone
+	out.MinSize = in.MinSize
+	out.MaxSize = in.MaxSize
+	out.Duration = in.Duration
+	return nil
+}
+
+// Convert_v1alpha2_ScalingSchedule_To_kops_ScalingSchedule is an autogenerated conversion function.
+func Convert_v1alpha2_ScalingSchedule_To_kops_ScalingSchedule(in *ScalingSchedule, out *kops.ScalingSchedule, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ScalingSchedule_To_kops_ScalingSchedule(in, out, s)
+}
+
+func autoConvert_kops_ScalingSchedule_To_v1alpha2_ScalingSchedule(in *kops.ScalingSchedule, out *ScalingSchedule, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Schedule = in.Schedule
+	out.TimeZone = in.TimeZone
+	out.MinSize = in.MinSize
+	out.MaxSize = in.MaxSize
+	out.Duration = in.Duration
+	return nil
+}
+
+// Convert_kops_ScalingSchedule_To_v1alpha2_ScalingSchedule is an autogenerated conversion function.
+func Convert_kops_ScalingSchedule_To_v1alpha2_ScalingSchedule(in *kops.ScalingSchedule, out *ScalingSchedule, s conversion.Scope) error {
+	return autoConvert_kops_ScalingSchedule_To_v1alpha2_ScalingSchedule(in, out, s)
+}
+
+func autoConvert_v1alpha2_SecretStoreEncryptionSpec_To_kops_SecretStoreEncryptionSpec(in *SecretStoreEncryptionSpec, out *kops.SecretStoreEncryptionSpec, s conversion.Scope) error {
+	out.KMSKey = in.KMSKey
+	return nil
+}
+
+// Convert_v1alpha2_SecretStoreEncryptionSpec_To_kops_SecretStoreEncryptionSpec is an autogenerated conversion function.
+func Convert_v1alpha2_SecretStoreEncryptionSpec_To_kops_SecretStoreEncryptionSpec(in *SecretStoreEncryptionSpec, out *kops.SecretStoreEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_SecretStoreEncryptionSpec_To_kops_SecretStoreEncryptionSpec(in, out, s)
+}
+
+func autoConvert_kops_SecretStoreEncryptionSpec_To_v1alpha2_SecretStoreEncryptionSpec(in *kops.SecretStoreEncryptionSpec, out *SecretStoreEncryptionSpec, s conversion.Scope) error {
+	out.KMSKey = in.KMSKey
+	return nil
+}
+
+// Convert_kops_SecretStoreEncryptionSpec_To_v1alpha2_SecretStoreEncryptionSpec is an autogenerated conversion function.
+func Convert_kops_SecretStoreEncryptionSpec_To_v1alpha2_SecretStoreEncryptionSpec(in *kops.SecretStoreEncryptionSpec, out *SecretStoreEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_kops_SecretStoreEncryptionSpec_To_v1alpha2_SecretStoreEncryptionSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_SecurityModulesSpec_To_kops_SecurityModulesSpec(in *SecurityModulesSpec, out *kops.SecurityModulesSpec, s conversion.Scope) error {
+	out.SELinux = in.SELinux
+	out.AppArmor = in.AppArmor
+	return nil
+}
+
+// Convert_v1alpha2_SecurityModulesSpec_To_kops_SecurityModulesSpec is an autogenerated conversion function.
+func Convert_v1alpha2_SecurityModulesSpec_To_kops_SecurityModulesSpec(in *SecurityModulesSpec, out *kops.SecurityModulesSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_SecurityModulesSpec_To_kops_SecurityModulesSpec(in, out, s)
+}
+
+func autoConvert_kops_SecurityModulesSpec_To_v1alpha2_SecurityModulesSpec(in *kops.SecurityModulesSpec, out *SecurityModulesSpec, s conversion.Scope) error {
+	out.SELinux = in.SELinux
+	out.AppArmor = in.AppArmor
+	return nil
+}
+
+// Convert_kops_SecurityModulesSpec_To_v1alpha2_SecurityModulesSpec is an autogenerated conversion function.
+func Convert_kops_SecurityModulesSpec_To_v1alpha2_SecurityModulesSpec(in *kops.SecurityModulesSpec, out *SecurityModulesSpec, s conversion.Scope) error {
+	return autoConvert_kops_SecurityModulesSpec_To_v1alpha2_SecurityModulesSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_ServiceAccountExternalPermission_To_kops_ServiceAccountExternalPermission(in *ServiceAccountExternalPermission, out *kops.ServiceAccountExternalPermission, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Namespace = in.Namespace
@@ -6428,6 +9078,30 @@ func Convert_kops_ServiceAccountIssuerDiscoveryConfig_To_v1alpha2_ServiceAccount
 	return autoConvert_kops_ServiceAccountIssuerDiscoveryConfig_To_v1alpha2_ServiceAccountIssuerDiscoveryConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_ShieldedInstanceConfig_To_kops_ShieldedInstanceConfig(in *ShieldedInstanceConfig, out *kops.ShieldedInstanceConfig, s conversion.Scope) error {
+	out.EnableSecureBoot = in.EnableSecureBoot
+	out.EnableVTPM = in.EnableVTPM
+	out.EnableIntegrityMonitoring = in.EnableIntegrityMonitoring
+	return nil
+}
+
+// Convert_v1alpha2_ShieldedInstanceConfig_To_kops_ShieldedInstanceConfig is an autogenerated conversion function.
+func Convert_v1alpha2_ShieldedInstanceConfig_To_kops_ShieldedInstanceConfig(in *ShieldedInstanceConfig, out *kops.ShieldedInstanceConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ShieldedInstanceConfig_To_kops_ShieldedInstanceConfig(in, out, s)
+}
+
+func autoConvert_kops_ShieldedInstanceConfig_To_v1alpha2_ShieldedInstanceConfig(in *kops.ShieldedInstanceConfig, out *ShieldedInstanceConfig, s conversion.Scope) error {
+	out.EnableSecureBoot = in.EnableSecureBoot
+	out.EnableVTPM = in.EnableVTPM
+	out.EnableIntegrityMonitoring = in.EnableIntegrityMonitoring
+	return nil
+}
+
+// Convert_kops_ShieldedInstanceConfig_To_v1alpha2_ShieldedInstanceConfig is an autogenerated conversion function.
+func Convert_kops_ShieldedInstanceConfig_To_v1alpha2_ShieldedInstanceConfig(in *kops.ShieldedInstanceConfig, out *ShieldedInstanceConfig, s conversion.Scope) error {
+	return autoConvert_kops_ShieldedInstanceConfig_To_v1alpha2_ShieldedInstanceConfig(in, out, s)
+}
+
 func autoConvert_v1alpha2_SnapshotControllerConfig_To_kops_SnapshotControllerConfig(in *SnapshotControllerConfig, out *kops.SnapshotControllerConfig, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.InstallDefaultClass = in.InstallDefaultClass
@@ -6450,6 +9124,30 @@ func Convert_kops_SnapshotControllerConfig_To_v1alpha2_SnapshotControllerConfig(
 	return autoConvert_kops_SnapshotControllerConfig_To_v1alpha2_SnapshotControllerConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_SwapSpec_To_kops_SwapSpec(in *SwapSpec, out *kops.SwapSpec, s conversion.Scope) error {
+	out.Size = in.Size
+	out.Swappiness = in.Swappiness
+	out.Encrypted = in.Encrypted
+	return nil
+}
+
+// Convert_v1alpha2_SwapSpec_To_kops_SwapSpec is an autogenerated conversion function.
+func Convert_v1alpha2_SwapSpec_To_kops_SwapSpec(in *SwapSpec, out *kops.SwapSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_SwapSpec_To_kops_SwapSpec(in, out, s)
+}
+
+func autoConvert_kops_SwapSpec_To_v1alpha2_SwapSpec(in *kops.SwapSpec, out *SwapSpec, s conversion.Scope) error {
+	out.Size = in.Size
+	out.Swappiness = in.Swappiness
+	out.Encrypted = in.Encrypted
+	return nil
+}
+
+// Convert_kops_SwapSpec_To_v1alpha2_SwapSpec is an autogenerated conversion function.
+func Convert_kops_SwapSpec_To_v1alpha2_SwapSpec(in *kops.SwapSpec, out *SwapSpec, s conversion.Scope) error {
+	return autoConvert_kops_SwapSpec_To_v1alpha2_SwapSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_TargetSpec_To_kops_TargetSpec(in *TargetSpec, out *kops.TargetSpec, s conversion.Scope) error {
 	if in.Terraform != nil {
 		in, out := &in.Terraform, &out.Terraform
@@ -6486,8 +9184,124 @@ func Convert_kops_TargetSpec_To_v1alpha2_TargetSpec(in *kops.TargetSpec, out *Ta
 	return autoConvert_kops_TargetSpec_To_v1alpha2_TargetSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_TerraformBackendSpec_To_kops_TerraformBackendSpec(in *TerraformBackendSpec, out *kops.TerraformBackendSpec, s conversion.Scope) error {
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(kops.TerraformS3BackendSpec)
+		if err := Convert_v1alpha2_TerraformS3BackendSpec_To_kops_TerraformS3BackendSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.S3 = nil
+	}
+	if in.GCS != nil {
+		in, out := &in.GCS, &out.GCS
+		*out = new(kops.TerraformGCSBackendSpec)
+		if err := Convert_v1alpha2_TerraformGCSBackendSpec_To_kops_TerraformGCSBackendSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.GCS = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_TerraformBackendSpec_To_kops_TerraformBackendSpec is an autogenerated conversion function.
+func Convert_v1alpha2_TerraformBackendSpec_To_kops_TerraformBackendSpec(in *TerraformBackendSpec, out *kops.TerraformBackendSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_TerraformBackendSpec_To_kops_TerraformBackendSpec(in, out, s)
+}
+
+func autoConvert_kops_TerraformBackendSpec_To_v1alpha2_TerraformBackendSpec(in *kops.TerraformBackendSpec, out *TerraformBackendSpec, s conversion.Scope) error {
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(TerraformS3BackendSpec)
+		if err := Convert_kops_TerraformS3BackendSpec_To_v1alpha2_TerraformS3BackendSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.S3 = nil
+	}
+	if in.GCS != nil {
+		in, out := &in.GCS, &out.GCS
+		*out = new(TerraformGCSBackendSpec)
+		if err := Convert_kops_TerraformGCSBackendSpec_To_v1alpha2_TerraformGCSBackendSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.GCS = nil
+	}
+	return nil
+}
+
+// Convert_kops_TerraformBackendSpec_To_v1alpha2_TerraformBackendSpec is an autogenerated conversion function.
+func Convert_kops_TerraformBackendSpec_To_v1alpha2_TerraformBackendSpec(in *kops.TerraformBackendSpec, out *TerraformBackendSpec, s conversion.Scope) error {
+	return autoConvert_kops_TerraformBackendSpec_To_v1alpha2_TerraformBackendSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_TerraformGCSBackendSpec_To_kops_TerraformGCSBackendSpec(in *TerraformGCSBackendSpec, out *kops.TerraformGCSBackendSpec, s conversion.Scope) error {
+	out.Bucket = in.Bucket
+	out.Prefix = in.Prefix
+	return nil
+}
+
+// Convert_v1alpha2_TerraformGCSBackendSpec_To_kops_TerraformGCSBackendSpec is an autogenerated conversion function.
+func Convert_v1alpha2_TerraformGCSBackendSpec_To_kops_TerraformGCSBackendSpec(in *TerraformGCSBackendSpec, out *kops.TerraformGCSBackendSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_TerraformGCSBackendSpec_To_kops_TerraformGCSBackendSpec(in, out, s)
+}
+
+func autoConvert_kops_TerraformGCSBackendSpec_To_v1alpha2_TerraformGCSBackendSpec(in *kops.TerraformGCSBackendSpec, out *TerraformGCSBackendSpec, s conversion.Scope) error {
+	out.Bucket = in.Bucket
+	out.Prefix = in.Prefix
+	return nil
+}
+
+// Convert_kops_TerraformGCSBackendSpec_To_v1alpha2_TerraformGCSBackendSpec is an autogenerated conversion function.
+func Convert_kops_TerraformGCSBackendSpec_To_v1alpha2_TerraformGCSBackendSpec(in *kops.TerraformGCSBackendSpec, out *TerraformGCSBackendSpec, s conversion.Scope) error {
+	return autoConvert_kops_TerraformGCSBackendSpec_To_v1alpha2_TerraformGCSBackendSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_TerraformS3BackendSpec_To_kops_TerraformS3BackendSpec(in *TerraformS3BackendSpec, out *kops.TerraformS3BackendSpec, s conversion.Scope) error {
+	out.Bucket = in.Bucket
+	out.Key = in.Key
+	out.Region = in.Region
+	out.DynamoDBTable = in.DynamoDBTable
+	out.Encrypt = in.Encrypt
+	return nil
+}
+
+// Convert_v1alpha2_TerraformS3BackendSpec_To_kops_TerraformS3BackendSpec is an autogenerated conversion function.
+func Convert_v1alpha2_TerraformS3BackendSpec_To_kops_TerraformS3BackendSpec(in *TerraformS3BackendSpec, out *kops.TerraformS3BackendSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_TerraformS3BackendSpec_To_kops_TerraformS3BackendSpec(in, out, s)
+}
+
+func autoConvert_kops_TerraformS3BackendSpec_To_v1alpha2_TerraformS3BackendSpec(in *kops.TerraformS3BackendSpec, out *TerraformS3BackendSpec, s conversion.Scope) error {
+	out.Bucket = in.Bucket
+	out.Key = in.Key
+	out.Region = in.Region
+	out.DynamoDBTable = in.DynamoDBTable
+	out.Encrypt = in.Encrypt
+	return nil
+}
+
+// Convert_kops_TerraformS3BackendSpec_To_v1alpha2_TerraformS3BackendSpec is an autogenerated conversion function.
+func Convert_kops_TerraformS3BackendSpec_To_v1alpha2_TerraformS3BackendSpec(in *kops.TerraformS3BackendSpec, out *TerraformS3BackendSpec, s conversion.Scope) error {
+	return autoConvert_kops_TerraformS3BackendSpec_To_v1alpha2_TerraformS3BackendSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_TerraformSpec_To_kops_TerraformSpec(in *TerraformSpec, out *kops.TerraformSpec, s conversion.Scope) error {
 	out.ProviderExtraConfig = in.ProviderExtraConfig
+	out.ProviderVersions = in.ProviderVersions
+	if in.Backend != nil {
+		in, out := &in.Backend, &out.Backend
+		*out = new(kops.TerraformBackendSpec)
+		if err := Convert_v1alpha2_TerraformBackendSpec_To_kops_TerraformBackendSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Backend = nil
+	}
+	out.SplitFiles = in.SplitFiles
+	out.ImportExisting = in.ImportExisting
 	return nil
 }
 
@@ -6498,6 +9312,18 @@ func Convert_v1alpha2_TerraformSpec_To_kops_TerraformSpec(in *TerraformSpec, out
 
 func autoConvert_kops_TerraformSpec_To_v1alpha2_TerraformSpec(in *kops.TerraformSpec, out *TerraformSpec, s conversion.Scope) error {
 	out.ProviderExtraConfig = in.ProviderExtraConfig
+	out.ProviderVersions = in.ProviderVersions
+	if in.Backend != nil {
+		in, out := &in.Backend, &out.Backend
+		*out = new(TerraformBackendSpec)
+		if err := Convert_kops_TerraformBackendSpec_To_v1alpha2_TerraformBackendSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Backend = nil
+	}
+	out.SplitFiles = in.SplitFiles
+	out.ImportExisting = in.ImportExisting
 	return nil
 }
 
@@ -6527,6 +9353,15 @@ func autoConvert_v1alpha2_TopologySpec_To_kops_TopologySpec(in *TopologySpec, ou
 	} else {
 		out.DNS = nil
 	}
+	if in.InstanceAccess != nil {
+		in, out := &in.InstanceAccess, &out.InstanceAccess
+		*out = new(kops.InstanceAccessSpec)
+		if err := Convert_v1alpha2_InstanceAccessSpec_To_kops_InstanceAccessSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.InstanceAccess = nil
+	}
 	return nil
 }
 
@@ -6556,6 +9391,15 @@ func autoConvert_kops_TopologySpec_To_v1alpha2_TopologySpec(in *kops.TopologySpe
 	} else {
 		out.DNS = nil
 	}
+	if in.InstanceAccess != nil {
+		in, out := &in.InstanceAccess, &out.InstanceAccess
+		*out = new(InstanceAccessSpec)
+		if err := Convert_kops_InstanceAccessSpec_To_v1alpha2_InstanceAccessSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.InstanceAccess = nil
+	}
 	return nil
 }
 
@@ -38,6 +38,9 @@ func awsValidateCluster(c *kops.Cluster) field.ErrorList {
 			allErrs = append(allErrs, awsValidateAdditionalSecurityGroups(field.NewPath("spec", "api", "loadBalancer", "additionalSecurityGroups"), c.Spec.API.LoadBalancer.AdditionalSecurityGroups)...)
 			allErrs = append(allErrs, awsValidateSSLPolicy(field.NewPath("spec", "api", "loadBalancer", "sslPolicy"), c.Spec.API.LoadBalancer)...)
 			allErrs = append(allErrs, awsValidateLoadBalancerSubnets(field.NewPath("spec", "api", "loadBalancer", "subnets"), c.Spec)...)
+			if c.Spec.API.LoadBalancer.PrivateLink != nil {
+				allErrs = append(allErrs, awsValidatePrivateLink(field.NewPath("spec", "api", "loadBalancer", "privateLink"), c.Spec.API.LoadBalancer)...)
+			}
 		}
 	}
 
@@ -234,6 +237,31 @@ func awsValidateSSLPolicy(fieldPath *field.Path, spec *kops.LoadBalancerAccessSp
 	return allErrs
 }
 
+func awsValidatePrivateLink(fieldPath *field.Path, spec *kops.LoadBalancerAccessSpec) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.Class != kops.LoadBalancerClassNetwork {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "privateLink requires a Network Load Balancer"))
+	}
+
+	privateLink := spec.PrivateLink
+	if fi.BoolValue(privateLink.AcceptanceRequired) && len(privateLink.Endpoints) != 0 {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("acceptanceRequired"), "endpoints created by kops cannot wait for their connections to be accepted"))
+	}
+
+	for i, endpoint := range privateLink.Endpoints {
+		endpointPath := fieldPath.Child("endpoints").Index(i)
+		if endpoint.VPCID == "" {
+			allErrs = append(allErrs, field.Required(endpointPath.Child("vpcID"), "the VPC of the endpoint must be specified"))
+		}
+		if len(endpoint.SubnetIDs) == 0 {
+			allErrs = append(allErrs, field.Required(endpointPath.Child("subnetIDs"), "the subnets of the endpoint must be specified"))
+		}
+	}
+
+	return allErrs
+}
+
 func awsValidateLoadBalancerSubnets(fieldPath *field.Path, spec kops.ClusterSpec) field.ErrorList {
 	allErrs := field.ErrorList{}
 
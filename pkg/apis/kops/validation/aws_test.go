@@ -540,3 +540,76 @@ func TestLoadBalancerSubnets(t *testing.T) {
 		testErrors(t, test, errs, test.expected)
 	}
 }
+
+func TestPrivateLink(t *testing.T) {
+	tests := []struct {
+		class       kops.LoadBalancerClass
+		privateLink *kops.PrivateLinkSpec
+		expected    []string
+	}{
+		{ // valid (service only)
+			class: kops.LoadBalancerClassNetwork,
+			privateLink: &kops.PrivateLinkSpec{
+				AllowedPrincipals:  []string{"arn:aws:iam::123456789012:root"},
+				AcceptanceRequired: fi.Bool(true),
+			},
+		},
+		{ // valid (with endpoints)
+			class: kops.LoadBalancerClassNetwork,
+			privateLink: &kops.PrivateLinkSpec{
+				Endpoints: []kops.PrivateLinkEndpointSpec{
+					{
+						VPCID:     "vpc-1234",
+						SubnetIDs: []string{"subnet-1234"},
+					},
+				},
+			},
+		},
+		{ // classic load balancer
+			class:       kops.LoadBalancerClassClassic,
+			privateLink: &kops.PrivateLinkSpec{},
+			expected:    []string{"Forbidden::spec.api.loadBalancer.privateLink"},
+		},
+		{ // acceptance required with endpoints
+			class: kops.LoadBalancerClassNetwork,
+			privateLink: &kops.PrivateLinkSpec{
+				AcceptanceRequired: fi.Bool(true),
+				Endpoints: []kops.PrivateLinkEndpointSpec{
+					{
+						VPCID:     "vpc-1234",
+						SubnetIDs: []string{"subnet-1234"},
+					},
+				},
+			},
+			expected: []string{"Forbidden::spec.api.loadBalancer.privateLink.acceptanceRequired"},
+		},
+		{ // incomplete endpoint
+			class: kops.LoadBalancerClassNetwork,
+			privateLink: &kops.PrivateLinkSpec{
+				Endpoints: []kops.PrivateLinkEndpointSpec{
+					{},
+				},
+			},
+			expected: []string{
+				"Required value::spec.api.loadBalancer.privateLink.endpoints[0].vpcID",
+				"Required value::spec.api.loadBalancer.privateLink.endpoints[0].subnetIDs",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		cluster := kops.Cluster{
+			Spec: kops.ClusterSpec{
+				API: &kops.AccessSpec{
+					LoadBalancer: &kops.LoadBalancerAccessSpec{
+						Class:       test.class,
+						Type:        kops.LoadBalancerTypePublic,
+						PrivateLink: test.privateLink,
+					},
+				},
+			},
+		}
+		errs := awsValidateCluster(&cluster)
+		testErrors(t, test, errs, test.expected)
+	}
+}
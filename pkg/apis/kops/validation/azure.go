@@ -0,0 +1,49 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
+)
+
+func azureValidateInstanceGroup(ig *kops.InstanceGroup) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	fieldSpec := field.NewPath("spec")
+
+	for i, zone := range ig.Spec.Zones {
+		if _, err := azure.ZoneToAvailabilityZoneNumber(zone); err != nil {
+			allErrs = append(allErrs, field.Invalid(fieldSpec.Child("zones").Index(i), zone, "zone must be of the form <location>-<availability-zone-number>"))
+		}
+	}
+
+	if ig.Spec.MaxPrice != nil && *ig.Spec.MaxPrice != "" {
+		if _, err := strconv.ParseFloat(*ig.Spec.MaxPrice, 64); err != nil {
+			allErrs = append(allErrs, field.Invalid(fieldSpec.Child("maxPrice"), *ig.Spec.MaxPrice, "maxPrice must be a number"))
+		}
+	}
+
+	if ig.Spec.InstanceInterruptionBehavior != nil {
+		allErrs = append(allErrs, IsValidValue(fieldSpec.Child("instanceInterruptionBehavior"), ig.Spec.InstanceInterruptionBehavior, []string{"terminate", "stop"})...)
+	}
+
+	return allErrs
+}
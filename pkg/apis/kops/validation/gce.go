@@ -17,8 +17,11 @@ limitations under the License.
 package validation
 
 import (
+	"strings"
+
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
 )
 
 func gceValidateCluster(c *kops.Cluster) field.ErrorList {
@@ -45,3 +48,24 @@ func gceValidateCluster(c *kops.Cluster) field.ErrorList {
 
 	return allErrs
 }
+
+func gceValidateInstanceGroup(ig *kops.InstanceGroup) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	fieldSpec := field.NewPath("spec")
+
+	if ig.Spec.Image != "" {
+		tokens := strings.Split(ig.Spec.Image, "/")
+		if len(tokens) > 3 || (len(tokens) == 3 && tokens[1] != "family") {
+			allErrs = append(allErrs, field.Invalid(fieldSpec.Child("image"), ig.Spec.Image, "image must be of the form <name>, <project>/<name> or <project>/family/<family>"))
+		}
+	}
+
+	if fi.BoolValue(ig.Spec.ConfidentialCompute) && ig.Spec.MachineType != "" {
+		if !strings.HasPrefix(ig.Spec.MachineType, "n2d-") && !strings.HasPrefix(ig.Spec.MachineType, "c2d-") {
+			allErrs = append(allErrs, field.Invalid(fieldSpec.Child("machineType"), ig.Spec.MachineType, "confidential compute requires an AMD based machine type (n2d or c2d)"))
+		}
+	}
+
+	return allErrs
+}
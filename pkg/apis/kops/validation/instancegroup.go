@@ -18,16 +18,25 @@ package validation
 
 import (
 	"fmt"
+	"net"
+	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"k8s.io/kops/pkg/nodeidentity/aws"
 
 	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/dns"
+	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/util/pkg/reflectutils"
 )
 
 // ValidateInstanceGroup is responsible for validating the configuration of a instancegroup
@@ -143,6 +152,14 @@ func ValidateInstanceGroup(g *kops.InstanceGroup, cloud fi.Cloud) field.ErrorLis
 
 	allErrs = append(allErrs, IsValidValue(field.NewPath("spec", "updatePolicy"), g.Spec.UpdatePolicy, []string{kops.UpdatePolicyAutomatic, kops.UpdatePolicyExternal})...)
 
+	if g.Spec.NodeTuning != nil {
+		allErrs = append(allErrs, validateNodeTuning(g.Spec.NodeTuning, field.NewPath("spec", "nodeTuning"))...)
+	}
+
+	if g.Spec.OperatingSystem != "" {
+		allErrs = append(allErrs, IsValidValue(field.NewPath("spec", "operatingSystem"), &g.Spec.OperatingSystem, kops.SupportedOperatingSystems)...)
+	}
+
 	return allErrs
 }
 
@@ -191,6 +208,20 @@ func CrossValidateInstanceGroup(g *kops.InstanceGroup, cluster *kops.Cluster, cl
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "role"), "Apiserver role only supported on AWS"))
 	}
 
+	// Validate the kubelet configuration file nodeup will write for this instance group, merged as in nodeup
+	if g.Spec.Kubelet != nil {
+		kubelet := &kops.KubeletConfigSpec{}
+		if g.Spec.Role == kops.InstanceGroupRoleMaster {
+			if cluster.Spec.MasterKubelet != nil {
+				reflectutils.JSONMergeStruct(kubelet, cluster.Spec.MasterKubelet)
+			}
+		} else if cluster.Spec.Kubelet != nil {
+			reflectutils.JSONMergeStruct(kubelet, cluster.Spec.Kubelet)
+		}
+		reflectutils.JSONMergeStruct(kubelet, g.Spec.Kubelet)
+		allErrs = append(allErrs, validateKubeletConfigFile(kubelet, cluster, field.NewPath("spec", "kubelet"))...)
+	}
+
 	// Check that instance groups are defined in subnets that are defined in the cluster
 	{
 		clusterSubnets := make(map[string]*kops.ClusterSubnetSpec)
@@ -216,6 +247,82 @@ func CrossValidateInstanceGroup(g *kops.InstanceGroup, cluster *kops.Cluster, cl
 		}
 	}
 
+	if kops.CloudProviderID(cluster.Spec.CloudProvider) == kops.CloudProviderGCE {
+		allErrs = append(allErrs, gceValidateInstanceGroup(g)...)
+	} else {
+		if g.Spec.ShieldedInstanceConfig != nil {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "shieldedInstanceConfig"), "shielded instances only supported on GCE"))
+		}
+		if g.Spec.ConfidentialCompute != nil {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "confidentialCompute"), "confidential compute only supported on GCE"))
+		}
+	}
+
+	if kops.CloudProviderID(cluster.Spec.CloudProvider) == kops.CloudProviderAzure {
+		allErrs = append(allErrs, azureValidateInstanceGroup(g)...)
+	} else {
+		if g.Spec.EphemeralOSDisk != nil {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "ephemeralOSDisk"), "ephemeral OS disks only supported on Azure"))
+		}
+		if g.Spec.AcceleratedNetworking != nil {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "acceleratedNetworking"), "accelerated networking only supported on Azure"))
+		}
+	}
+
+	if kops.CloudProviderID(cluster.Spec.CloudProvider) == kops.CloudProviderMetal {
+		allErrs = append(allErrs, metalValidateInstanceGroup(g)...)
+	} else if len(g.Spec.Machines) != 0 {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "machines"), "machines only supported on metal"))
+	}
+
+	if g.Spec.Bootstrap != nil {
+		allErrs = append(allErrs, validateInstanceGroupBootstrap(g, cluster, field.NewPath("spec", "bootstrap"))...)
+	}
+
+	if g.Spec.NvidiaGPU != nil {
+		allErrs = append(allErrs, validateInstanceGroupNvidiaGPU(g, cluster, field.NewPath("spec", "nvidiaGPU"))...)
+	}
+
+	if g.Spec.Swap != nil {
+		allErrs = append(allErrs, validateInstanceGroupSwap(g, cluster, field.NewPath("spec", "swap"))...)
+	}
+
+	if g.Spec.InstanceStorage != nil {
+		allErrs = append(allErrs, validateInstanceGroupInstanceStorage(g, cluster, field.NewPath("spec", "instanceStorage"))...)
+	}
+
+	if g.Spec.Containerd != nil {
+		allErrs = append(allErrs, validateInstanceGroupContainerd(g, cluster, field.NewPath("spec", "containerd"))...)
+	}
+
+	if g.Spec.ImagePreload != nil {
+		allErrs = append(allErrs, validateInstanceGroupImagePreload(g, field.NewPath("spec", "imagePreload"))...)
+	}
+
+	if g.Spec.GracefulShutdown != nil {
+		allErrs = append(allErrs, validateInstanceGroupGracefulShutdown(g, cluster, field.NewPath("spec", "gracefulShutdown"))...)
+	}
+
+	if g.Spec.SecurityModules != nil {
+		allErrs = append(allErrs, validateInstanceGroupSecurityModules(g, cluster, field.NewPath("spec", "securityModules"))...)
+	}
+
+	if len(g.Spec.ScalingSchedules) != 0 {
+		allErrs = append(allErrs, validateInstanceGroupScalingSchedules(g, cluster, field.NewPath("spec", "scalingSchedules"))...)
+	}
+
+	if g.Spec.APIServerEndpoint != nil {
+		allErrs = append(allErrs, validateInstanceGroupAPIServerEndpoint(g, cluster, field.NewPath("spec", "apiServerEndpoint"))...)
+	}
+
+	if g.IsWindows() {
+		allErrs = append(allErrs, validateInstanceGroupWindows(g, cluster, field.NewPath("spec", "operatingSystem"))...)
+	}
+
+	if g.Spec.Role == kops.InstanceGroupRoleNode {
+		allErrs = append(allErrs, validateInstanceGroupClusterAutoscaler(g, cluster)...)
+	}
+
 	{
 		warmPool := cluster.Spec.WarmPool.ResolveDefaults(g)
 		if warmPool.MaxSize == nil || *warmPool.MaxSize != 0 {
@@ -245,9 +352,34 @@ func CrossValidateInstanceGroup(g *kops.InstanceGroup, cluster *kops.Cluster, cl
 	return allErrs
 }
 
+func validateInstanceGroupBootstrap(g *kops.InstanceGroup, cluster *kops.Cluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	bootstrap := g.Spec.Bootstrap
+
+	allErrs = append(allErrs, IsValidValue(fldPath.Child("delivery"), &bootstrap.Delivery, []string{"", kops.BootstrapDeliveryUserData, kops.BootstrapDeliverySSH})...)
+
+	if bootstrap.Delivery == kops.BootstrapDeliverySSH {
+		switch kops.CloudProviderID(cluster.Spec.CloudProvider) {
+		case kops.CloudProviderAWS, kops.CloudProviderOpenstack:
+		default:
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("delivery"), "SSH delivery only supported on AWS and OpenStack"))
+		}
+		if g.Spec.Role != kops.InstanceGroupRoleNode {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("delivery"), "SSH delivery only supported on instance groups with role Node"))
+		}
+	} else if bootstrap.SSHUser != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("sshUser"), "sshUser requires SSH delivery"))
+	}
+
+	return allErrs
+}
+
 func ValidateMasterInstanceGroup(g *kops.InstanceGroup, cluster *kops.Cluster) field.ErrorList {
 	allErrs := field.ErrorList{}
 	for _, etcd := range cluster.Spec.EtcdClusters {
+		if etcd.IsExternal() {
+			continue
+		}
 		hasEtcd := false
 		for _, m := range etcd.Members {
 			if fi.StringValue(m.InstanceGroup) == g.ObjectMeta.Name {
@@ -385,3 +517,453 @@ func validateExternalLoadBalancer(lb *kops.LoadBalancer, fldPath *field.Path) fi
 
 	return allErrs
 }
+
+func validateInstanceGroupNvidiaGPU(g *kops.InstanceGroup, cluster *kops.Cluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	nvidiaGPU := g.Spec.NvidiaGPU
+
+	if nvidiaGPU.DriverPackage != nil && *nvidiaGPU.DriverPackage == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("driverPackage"), "driverPackage must not be empty"))
+	}
+
+	if nvidiaGPU.IsEnabled() {
+		if cluster.Spec.Containerd == nil || !cluster.Spec.Containerd.NvidiaGPU.IsEnabled() {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("enabled"), "NVIDIA GPUs require containerd.nvidiaGPU to be enabled in the cluster spec"))
+		}
+		if g.Spec.Role != kops.InstanceGroupRoleNode {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("enabled"), "NVIDIA GPUs only supported on instance groups with role Node"))
+		}
+	}
+
+	return allErrs
+}
+
+// instanceStoragePathRegex restricts the paths to those that map to systemd mount unit names without escaping, other than '-'
+var instanceStoragePathRegex = regexp.MustCompile(`^(/[a-zA-Z0-9_][a-zA-Z0-9_.-]*)+$`)
+
+func validateInstanceGroupInstanceStorage(g *kops.InstanceGroup, cluster *kops.Cluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	storage := g.Spec.InstanceStorage
+
+	if kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "instance storage is only supported on AWS"))
+	}
+
+	if storage.Mode != "" {
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("mode"), &storage.Mode, kops.SupportedInstanceStorageModes)...)
+	}
+	if storage.Filesystem != "" {
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("filesystem"), &storage.Filesystem, kops.SupportedFilesystems)...)
+	}
+
+	if len(storage.Paths) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("paths"), "at least one path must be set"))
+	}
+	paths := sets.NewString()
+	for _, x := range g.Spec.VolumeMounts {
+		paths.Insert(x.Path)
+	}
+	for i, p := range storage.Paths {
+		if !instanceStoragePathRegex.MatchString(p) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("paths").Index(i), p, "path must be absolute and may only contain letters, digits, '_', '.' and '-'"))
+		} else if paths.Has(p) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("paths").Index(i), p))
+		}
+		paths.Insert(p)
+	}
+
+	return allErrs
+}
+
+func validateInstanceGroupClusterAutoscaler(g *kops.InstanceGroup, cluster *kops.Cluster) field.ErrorList {
+	allErrs := field.ErrorList{}
+	annotationsPath := field.NewPath("metadata", "annotations")
+
+	cas := cluster.Spec.ClusterAutoscaler
+	enabled := cas != nil && fi.BoolValue(cas.Enabled)
+	autoscale := g.Spec.Autoscale == nil || *g.Spec.Autoscale
+
+	for _, annotation := range []string{kops.AnnotationClusterAutoscalerPriority, kops.AnnotationClusterAutoscalerScaleDownDisabled} {
+		if _, found := g.Annotations[annotation]; !found {
+			continue
+		}
+		if !enabled {
+			allErrs = append(allErrs, field.Forbidden(annotationsPath.Key(annotation), "cluster autoscaler must be enabled"))
+		} else if !autoscale {
+			allErrs = append(allErrs, field.Forbidden(annotationsPath.Key(annotation), "instance group is not managed by cluster autoscaler"))
+		}
+	}
+
+	if value, found := g.Annotations[kops.AnnotationClusterAutoscalerPriority]; found {
+		fldPath := annotationsPath.Key(kops.AnnotationClusterAutoscalerPriority)
+		if _, err := strconv.ParseInt(value, 10, 32); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath, value, "priority must be an integer"))
+		}
+		if enabled && fi.StringValue(cas.Expander) != "priority" {
+			allErrs = append(allErrs, field.Forbidden(fldPath, "priorities are only used with the priority expander"))
+		}
+	}
+
+	if value, found := g.Annotations[kops.AnnotationClusterAutoscalerScaleDownDisabled]; found {
+		if _, err := strconv.ParseBool(value); err != nil {
+			allErrs = append(allErrs, field.Invalid(annotationsPath.Key(kops.AnnotationClusterAutoscalerScaleDownDisabled), value, "must be true or false"))
+		}
+		if kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
+			allErrs = append(allErrs, field.Forbidden(annotationsPath.Key(kops.AnnotationClusterAutoscalerScaleDownDisabled), "disabling scale down is only supported on AWS"))
+		}
+	}
+
+	// Cluster autoscaler can't do anything with a group that has a fixed size.
+	if enabled && g.Spec.Autoscale != nil && *g.Spec.Autoscale && g.Spec.MinSize != nil && g.Spec.MaxSize != nil && *g.Spec.MinSize == *g.Spec.MaxSize {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "autoscale"), "minSize and maxSize are equal; set autoscale to false to pin the size of the instance group"))
+	}
+
+	return allErrs
+}
+
+func validateInstanceGroupSwap(g *kops.InstanceGroup, cluster *kops.Cluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	swap := g.Spec.Swap
+
+	if swap.Size == nil {
+		allErrs = append(allErrs, field.Required(fldPath.Child("size"), "swap size must be set"))
+	} else if swap.Size.Sign() <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("size"), swap.Size.String(), "swap size must be positive"))
+	}
+
+	if swap.Swappiness != nil && (*swap.Swappiness < 0 || *swap.Swappiness > 100) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("swappiness"), *swap.Swappiness, "swappiness must be between 0 and 100"))
+	}
+
+	if !cluster.IsKubernetesGTE("1.22") {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "swap requires at least Kubernetes 1.22"))
+	}
+
+	if g.Spec.Kubelet != nil {
+		if g.Spec.Kubelet.FailSwapOn != nil && *g.Spec.Kubelet.FailSwapOn {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "kubelet", "failSwapOn"), "failSwapOn cannot be enabled on instance groups with swap"))
+		}
+		if g.Spec.Kubelet.MemorySwapBehavior != "" {
+			allErrs = append(allErrs, IsValidValue(field.NewPath("spec", "kubelet", "memorySwapBehavior"), &g.Spec.Kubelet.MemorySwapBehavior, []string{"LimitedSwap", "UnlimitedSwap"})...)
+		}
+	}
+
+	return allErrs
+}
+
+// validateInstanceGroupContainerd checks that the instance group only sets the structured containerd settings
+func validateInstanceGroupContainerd(g *kops.InstanceGroup, cluster *kops.Cluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if cluster.Spec.ContainerRuntime != "containerd" {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "containerd settings require the containerd container runtime"))
+	}
+
+	other := *g.Spec.Containerd
+	other.NRI, other.Registries, other.Runtimes, other.SandboxImage = nil, nil, nil, nil
+	if !reflect.DeepEqual(other, kops.ContainerdConfig{}) {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "only registries, runtimes, sandboxImage and nri can be set on instance groups"))
+	}
+	if len(g.Spec.Containerd.Registries) > 0 && cluster.Spec.Containerd != nil && len(cluster.Spec.Containerd.RegistryMirrors) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("registries"), "registries cannot be combined with the registryMirrors of the cluster"))
+	}
+
+	allErrs = append(allErrs, validateContainerdSettings(g.Spec.Containerd, cluster, fldPath)...)
+
+	return allErrs
+}
+
+// validateInstanceGroupImagePreload checks the images to pull when the instances boot
+func validateInstanceGroupImagePreload(g *kops.InstanceGroup, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if g.IsWindows() {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "image preloading is not supported on Windows"))
+	}
+
+	for i, image := range g.Spec.ImagePreload.Images {
+		if image == "" || strings.ContainsAny(image, " \t\n") || strings.Contains(image, "://") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("images").Index(i), image, "image must be an image name, e.g. docker.io/library/nginx:1.21"))
+		}
+	}
+
+	if cache := g.Spec.ImagePreload.PullThroughCache; cache != nil {
+		if *cache == "" || strings.ContainsAny(*cache, " \t\n") || strings.Contains(*cache, "://") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("pullThroughCache"), *cache, "pullThroughCache must be a registry host, e.g. cache.example.com:5000"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateInstanceGroupGracefulShutdown checks that the kubelet and the cloud provider support the graceful shutdown of the instances
+func validateInstanceGroupGracefulShutdown(g *kops.InstanceGroup, cluster *kops.Cluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if !cluster.IsKubernetesGTE("1.21") {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "graceful shutdown requires at least Kubernetes 1.21"))
+	}
+	if g.IsWindows() {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "graceful shutdown is not supported on Windows"))
+	}
+
+	cloudEvents := g.Spec.GracefulShutdown.CloudEvents
+	if cloudEvents == nil || *cloudEvents {
+		switch kops.CloudProviderID(cluster.Spec.CloudProvider) {
+		case kops.CloudProviderAWS, kops.CloudProviderGCE:
+		default:
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("cloudEvents"), "cloud events are only supported on AWS and GCE"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateInstanceGroupSecurityModules checks that a single Linux security module is enforced, with a container runtime supporting it.
+// The distribution of the image is checked by nodeup.
+func validateInstanceGroupSecurityModules(g *kops.InstanceGroup, cluster *kops.Cluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	securityModules := g.Spec.SecurityModules
+
+	if g.IsWindows() {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "Linux security modules are not supported on Windows"))
+	}
+	if fi.BoolValue(securityModules.SELinux) {
+		if fi.BoolValue(securityModules.AppArmor) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("appArmor"), "SELinux and AppArmor cannot be enforced together"))
+		}
+		if cluster.Spec.ContainerRuntime != "containerd" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("selinux"), "SELinux requires the containerd container runtime"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateInstanceGroupWindows checks that Windows instances are only combined with the settings and addons nodeup supports on Windows
+func validateInstanceGroupWindows(g *kops.InstanceGroup, cluster *kops.Cluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "Windows is only supported on AWS"))
+	}
+	if g.Spec.Role != kops.InstanceGroupRoleNode {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "Windows is only supported on instance groups with role Node"))
+	}
+	if !cluster.IsKubernetesGTE("1.20") {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "Windows requires at least Kubernetes 1.20"))
+	}
+	if dns.IsGossipHostname(cluster.ObjectMeta.Name) {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "Windows is not supported with gossip DNS"))
+	}
+
+	if cluster.Spec.ContainerRuntime != "containerd" {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "Windows requires the containerd container runtime"))
+	} else if cluster.Spec.Containerd == nil || cluster.Spec.Containerd.Packages == nil ||
+		cluster.Spec.Containerd.Packages.UrlWindows == nil || cluster.Spec.Containerd.Packages.HashWindows == nil {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec", "containerd", "packages", "urlWindows"), "Windows requires the URL and hash of the containerd package for Windows"))
+	}
+
+	if cluster.Spec.KubeProxy != nil && cluster.Spec.KubeProxy.Enabled != nil && !*cluster.Spec.KubeProxy.Enabled {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "Windows requires kube-proxy"))
+	}
+
+	if cluster.Spec.Networking == nil || cluster.Spec.Networking.Calico == nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "Windows requires Calico networking"))
+	} else {
+		calico := cluster.Spec.Networking.Calico
+		if calico.EncapsulationMode != "vxlan" || calico.CrossSubnet {
+			allErrs = append(allErrs, field.Forbidden(fldPath, "Windows requires Calico with encapsulationMode vxlan and crossSubnet disabled"))
+		}
+		if calico.BPFEnabled {
+			allErrs = append(allErrs, field.Forbidden(fldPath, "Windows is not supported with the Calico eBPF dataplane"))
+		}
+		if calico.WindowsPackages == nil || calico.WindowsPackages.UrlWindows == nil || calico.WindowsPackages.HashWindows == nil {
+			allErrs = append(allErrs, field.Required(field.NewPath("spec", "networking", "calico", "windowsPackages", "urlWindows"), "Windows requires the URL and hash of the Calico for Windows package"))
+		}
+	}
+
+	// The node-local-dns DaemonSet has no node selector, so it would be scheduled on the Windows nodes
+	if cluster.Spec.KubeDNS != nil && cluster.Spec.KubeDNS.NodeLocalDNS != nil && fi.BoolValue(cluster.Spec.KubeDNS.NodeLocalDNS.Enabled) {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "Windows is not supported with nodeLocalDNS"))
+	}
+
+	if g.Spec.Bootstrap != nil && g.Spec.Bootstrap.Delivery == kops.BootstrapDeliverySSH {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "bootstrap", "delivery"), "SSH delivery is not supported on Windows"))
+	}
+	if len(g.Spec.AdditionalUserData) != 0 {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "additionalUserData"), "additionalUserData is not supported on Windows"))
+	}
+	if fi.BoolValue(g.Spec.CompressUserData) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "compressUserData"), "compressUserData is not supported on Windows"))
+	}
+	if g.Spec.NvidiaGPU != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "nvidiaGPU"), "NVIDIA GPUs are not supported on Windows"))
+	}
+	if g.Spec.Swap != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "swap"), "swap is not supported on Windows"))
+	}
+	if g.Spec.NodeTuning != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "nodeTuning"), "nodeTuning is not supported on Windows"))
+	}
+	if g.Spec.InstanceStorage != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "instanceStorage"), "instance storage is not supported on Windows"))
+	}
+	if len(g.Spec.Hooks) != 0 {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "hooks"), "hooks are not supported on Windows"))
+	}
+	if len(g.Spec.VolumeMounts) != 0 {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "volumeMounts"), "volumeMounts are not supported on Windows"))
+	}
+
+	return allErrs
+}
+
+var (
+	scalingScheduleNameRegex  = regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
+	scalingScheduleFieldRegex = regexp.MustCompile(`^[0-9A-Za-z*?,/-]+$`)
+)
+
+// validateInstanceGroupScalingSchedules checks that the schedules can be created as ASG scheduled actions on AWS,
+// or as scaling schedules of the autoscalers of the instance group managers on GCE
+func validateInstanceGroupScalingSchedules(g *kops.InstanceGroup, cluster *kops.Cluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	cloudProvider := kops.CloudProviderID(cluster.Spec.CloudProvider)
+	switch cloudProvider {
+	case kops.CloudProviderAWS, kops.CloudProviderGCE:
+	default:
+		return append(allErrs, field.Forbidden(fldPath, "scaling schedules are only supported on AWS and GCE"))
+	}
+	if g.IsMaster() {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "scaling schedules are not supported on control plane instance groups"))
+	}
+
+	names := sets.NewString()
+	for i, schedule := range g.Spec.ScalingSchedules {
+		path := fldPath.Index(i)
+
+		if schedule.Name == "" {
+			allErrs = append(allErrs, field.Required(path.Child("name"), ""))
+		} else if len(schedule.Name) > 63 || !scalingScheduleNameRegex.MatchString(schedule.Name) {
+			allErrs = append(allErrs, field.Invalid(path.Child("name"), schedule.Name, "must consist of lower case alphanumeric characters or '-', start with a letter and be at most 63 characters"))
+		} else if names.Has(schedule.Name) {
+			allErrs = append(allErrs, field.Duplicate(path.Child("name"), schedule.Name))
+		} else {
+			names.Insert(schedule.Name)
+		}
+
+		if schedule.Schedule == "" {
+			allErrs = append(allErrs, field.Required(path.Child("schedule"), ""))
+		} else {
+			fields := strings.Fields(schedule.Schedule)
+			valid := len(fields) == 5
+			for _, f := range fields {
+				if !scalingScheduleFieldRegex.MatchString(f) {
+					valid = false
+				}
+			}
+			if !valid {
+				allErrs = append(allErrs, field.Invalid(path.Child("schedule"), schedule.Schedule, "must be a cron expression with five fields: minute, hour, day of month, month and day of week"))
+			}
+		}
+
+		if schedule.TimeZone != "" {
+			if _, err := time.LoadLocation(schedule.TimeZone); err != nil || strings.EqualFold(schedule.TimeZone, "local") {
+				allErrs = append(allErrs, field.Invalid(path.Child("timeZone"), schedule.TimeZone, "must be an IANA time zone, such as Europe/Berlin"))
+			}
+		}
+
+		if schedule.MinSize != nil && *schedule.MinSize < 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("minSize"), *schedule.MinSize, "must not be negative"))
+		}
+		if schedule.MaxSize != nil && *schedule.MaxSize < 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("maxSize"), *schedule.MaxSize, "must not be negative"))
+		}
+		if schedule.MinSize != nil && schedule.MaxSize != nil && *schedule.MinSize > *schedule.MaxSize {
+			allErrs = append(allErrs, field.Invalid(path.Child("minSize"), *schedule.MinSize, "must not be greater than maxSize"))
+		}
+
+		switch cloudProvider {
+		case kops.CloudProviderAWS:
+			if schedule.MinSize == nil && schedule.MaxSize == nil {
+				allErrs = append(allErrs, field.Required(path.Child("minSize"), "minSize or maxSize must be set"))
+			}
+			if schedule.Duration != nil {
+				allErrs = append(allErrs, field.Forbidden(path.Child("duration"), "duration is only supported on GCE; the sizes apply until the start of the next schedule"))
+			}
+		case kops.CloudProviderGCE:
+			if schedule.MinSize == nil {
+				allErrs = append(allErrs, field.Required(path.Child("minSize"), ""))
+			} else if g.Spec.MaxSize != nil && *schedule.MinSize > *g.Spec.MaxSize {
+				allErrs = append(allErrs, field.Invalid(path.Child("minSize"), *schedule.MinSize, "must not be greater than the maxSize of the instance group"))
+			}
+			if schedule.MaxSize != nil {
+				allErrs = append(allErrs, field.Forbidden(path.Child("maxSize"), "maxSize is only supported on AWS"))
+			}
+			if schedule.Duration == nil {
+				allErrs = append(allErrs, field.Required(path.Child("duration"), ""))
+			} else if schedule.Duration.Duration < 5*time.Minute || schedule.Duration.Duration%time.Second != 0 {
+				allErrs = append(allErrs, field.Invalid(path.Child("duration"), schedule.Duration.Duration.String(), "must be a whole number of seconds, and at least 5m"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validateInstanceGroupAPIServerEndpoint checks that nodeup can point the internal API and kops-controller names
+// of the instances to the endpoint
+func validateInstanceGroupAPIServerEndpoint(g *kops.InstanceGroup, cluster *kops.Cluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	endpoint := g.Spec.APIServerEndpoint
+
+	switch endpoint.Type {
+	case "", kops.APIServerEndpointTypeInternal:
+		if len(endpoint.IPs) != 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("ips"), "ips can only be set when the type is IPs"))
+		}
+		return allErrs
+
+	case kops.APIServerEndpointTypeExternal:
+		if len(endpoint.IPs) != 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("ips"), "ips can only be set when the type is IPs"))
+		}
+		if kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("type"), "the External type is only supported on AWS"))
+		} else if cluster.Spec.API == nil || cluster.Spec.API.LoadBalancer == nil ||
+			cluster.Spec.API.LoadBalancer.Type != kops.LoadBalancerTypePublic ||
+			cluster.Spec.API.LoadBalancer.Class != kops.LoadBalancerClassNetwork {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("type"), "the External type requires a public network load balancer for the API"))
+		}
+
+	case kops.APIServerEndpointTypeIPs:
+		if len(endpoint.IPs) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("ips"), "ips must be set when the type is IPs"))
+		}
+		for i, ip := range endpoint.IPs {
+			if net.ParseIP(ip) == nil {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("ips").Index(i), ip, "must be an IP address"))
+			}
+		}
+
+	default:
+		return append(allErrs, field.NotSupported(fldPath.Child("type"), endpoint.Type, kops.SupportedAPIServerEndpointTypes))
+	}
+
+	if g.Spec.Role != kops.InstanceGroupRoleNode {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "the API server endpoint can only be changed on instance groups with role Node"))
+	}
+	if g.IsWindows() {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "the API server endpoint can't be changed on Windows"))
+	}
+	if dns.IsGossipHostname(cluster.ObjectMeta.Name) {
+		// protokube manages the /etc/hosts entries of the instances in gossip clusters
+		allErrs = append(allErrs, field.Forbidden(fldPath, "the API server endpoint can't be changed in gossip clusters"))
+	}
+	if featureflag.KopsControllerStateStore.Enabled() {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "the API server endpoint can't be changed with the KopsControllerStateStore feature flag"))
+	}
+
+	return allErrs
+}
@@ -18,9 +18,11 @@ package validation
 
 import (
 	"testing"
+	"time"
 
 	"k8s.io/kops/pkg/nodeidentity/aws"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/pkg/apis/kops"
@@ -449,3 +451,1138 @@ func TestValidInstanceGroup(t *testing.T) {
 		testErrors(t, g.Description, errList, []string{})
 	}
 }
+
+func TestGCEInstanceGroup(t *testing.T) {
+	grid := []struct {
+		Spec     kops.InstanceGroupSpec
+		Cloud    kops.CloudProviderID
+		Expected []string
+	}{
+		{
+			Spec: kops.InstanceGroupSpec{
+				Image:               "ubuntu-os-cloud/family/ubuntu-2004-lts",
+				MachineType:         "n2d-standard-2",
+				ConfidentialCompute: fi.Bool(true),
+				ShieldedInstanceConfig: &kops.ShieldedInstanceConfig{
+					EnableSecureBoot: fi.Bool(true),
+				},
+			},
+			Cloud: kops.CloudProviderGCE,
+		},
+		{
+			Spec: kops.InstanceGroupSpec{
+				Image: "ubuntu-os-cloud/images/ubuntu-2004-lts",
+			},
+			Cloud:    kops.CloudProviderGCE,
+			Expected: []string{"Invalid value::spec.image"},
+		},
+		{
+			Spec: kops.InstanceGroupSpec{
+				MachineType:         "e2-standard-2",
+				ConfidentialCompute: fi.Bool(true),
+			},
+			Cloud:    kops.CloudProviderGCE,
+			Expected: []string{"Invalid value::spec.machineType"},
+		},
+		{
+			Spec: kops.InstanceGroupSpec{
+				ConfidentialCompute:    fi.Bool(true),
+				ShieldedInstanceConfig: &kops.ShieldedInstanceConfig{},
+			},
+			Cloud:    kops.CloudProviderAWS,
+			Expected: []string{"Forbidden::spec.shieldedInstanceConfig", "Forbidden::spec.confidentialCompute"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				CloudProvider: string(g.Cloud),
+			},
+		}
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "nodes",
+			},
+			Spec: g.Spec,
+		}
+		ig.Spec.Role = kops.InstanceGroupRoleNode
+		errs := CrossValidateInstanceGroup(ig, cluster, nil)
+		testErrors(t, g.Spec, errs, g.Expected)
+	}
+}
+
+func TestAzureInstanceGroup(t *testing.T) {
+	grid := []struct {
+		Spec     kops.InstanceGroupSpec
+		Cloud    kops.CloudProviderID
+		Expected []string
+	}{
+		{
+			Spec: kops.InstanceGroupSpec{
+				Zones:                        []string{"eastus-1", "eastus-2"},
+				MaxPrice:                     fi.String("0.05"),
+				InstanceInterruptionBehavior: fi.String("stop"),
+				EphemeralOSDisk:              fi.Bool(true),
+				AcceleratedNetworking:        fi.Bool(true),
+			},
+			Cloud: kops.CloudProviderAzure,
+		},
+		{
+			Spec: kops.InstanceGroupSpec{
+				Zones:                        []string{"eastus"},
+				MaxPrice:                     fi.String("cheap"),
+				InstanceInterruptionBehavior: fi.String("hibernate"),
+			},
+			Cloud: kops.CloudProviderAzure,
+			Expected: []string{
+				"Invalid value::spec.zones[0]",
+				"Invalid value::spec.maxPrice",
+				"Unsupported value::spec.instanceInterruptionBehavior",
+			},
+		},
+		{
+			Spec: kops.InstanceGroupSpec{
+				EphemeralOSDisk:       fi.Bool(true),
+				AcceleratedNetworking: fi.Bool(true),
+			},
+			Cloud:    kops.CloudProviderGCE,
+			Expected: []string{"Forbidden::spec.ephemeralOSDisk", "Forbidden::spec.acceleratedNetworking"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				CloudProvider: string(g.Cloud),
+			},
+		}
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "nodes",
+			},
+			Spec: g.Spec,
+		}
+		ig.Spec.Role = kops.InstanceGroupRoleNode
+		errs := CrossValidateInstanceGroup(ig, cluster, nil)
+		testErrors(t, g.Spec, errs, g.Expected)
+	}
+}
+
+func TestMetalInstanceGroup(t *testing.T) {
+	grid := []struct {
+		Spec     kops.InstanceGroupSpec
+		Cloud    kops.CloudProviderID
+		Expected []string
+	}{
+		{
+			Spec: kops.InstanceGroupSpec{
+				Machines: []kops.MetalMachineSpec{
+					{Name: "node-1", Address: "10.0.0.11"},
+					{Name: "node-2", Address: "10.0.0.12", SSHUser: "ubuntu", SSHHostKeyFingerprint: "SHA256:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU"},
+				},
+				MinSize: fi.Int32(2),
+				MaxSize: fi.Int32(2),
+			},
+			Cloud: kops.CloudProviderMetal,
+		},
+		{
+			Spec: kops.InstanceGroupSpec{
+				Machines: []kops.MetalMachineSpec{
+					{Name: "node-1", Address: "10.0.0.11", SSHHostKeyFingerprint: "MD5:16:27:ac:a5:76:28:2d:36:63:1b:56:4d:eb:df:a6:48"},
+				},
+			},
+			Cloud:    kops.CloudProviderMetal,
+			Expected: []string{"Invalid value::spec.machines[0].sshHostKeyFingerprint"},
+		},
+		{
+			Spec:     kops.InstanceGroupSpec{},
+			Cloud:    kops.CloudProviderMetal,
+			Expected: []string{"Required value::spec.machines"},
+		},
+		{
+			Spec: kops.InstanceGroupSpec{
+				Machines: []kops.MetalMachineSpec{
+					{Name: "node-1", Address: "10.0.0.11"},
+					{Name: "node-1"},
+				},
+				MaxSize: fi.Int32(3),
+			},
+			Cloud: kops.CloudProviderMetal,
+			Expected: []string{
+				"Duplicate value::spec.machines[1].name",
+				"Required value::spec.machines[1].address",
+				"Invalid value::spec.maxSize",
+			},
+		},
+		{
+			Spec: kops.InstanceGroupSpec{
+				Machines: []kops.MetalMachineSpec{
+					{Name: "node-1", Address: "10.0.0.11"},
+				},
+			},
+			Cloud:    kops.CloudProviderAWS,
+			Expected: []string{"Forbidden::spec.machines"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				CloudProvider: string(g.Cloud),
+			},
+		}
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "nodes",
+			},
+			Spec: g.Spec,
+		}
+		ig.Spec.Role = kops.InstanceGroupRoleNode
+		errs := CrossValidateInstanceGroup(ig, cluster, nil)
+		testErrors(t, g.Spec, errs, g.Expected)
+	}
+}
+
+func TestInstanceGroupBootstrap(t *testing.T) {
+	grid := []struct {
+		Bootstrap kops.InstanceGroupBootstrapSpec
+		Role      kops.InstanceGroupRole
+		Cloud     kops.CloudProviderID
+		Expected  []string
+	}{
+		{
+			Bootstrap: kops.InstanceGroupBootstrapSpec{Delivery: kops.BootstrapDeliverySSH, SSHUser: "ubuntu"},
+			Role:      kops.InstanceGroupRoleNode,
+			Cloud:     kops.CloudProviderAWS,
+		},
+		{
+			Bootstrap: kops.InstanceGroupBootstrapSpec{Delivery: kops.BootstrapDeliverySSH},
+			Role:      kops.InstanceGroupRoleNode,
+			Cloud:     kops.CloudProviderOpenstack,
+		},
+		{
+			Bootstrap: kops.InstanceGroupBootstrapSpec{Delivery: "WinRM"},
+			Role:      kops.InstanceGroupRoleNode,
+			Cloud:     kops.CloudProviderAWS,
+			Expected:  []string{"Unsupported value::spec.bootstrap.delivery"},
+		},
+		{
+			Bootstrap: kops.InstanceGroupBootstrapSpec{Delivery: kops.BootstrapDeliverySSH},
+			Role:      kops.InstanceGroupRoleMaster,
+			Cloud:     kops.CloudProviderGCE,
+			Expected: []string{
+				"Forbidden::spec.bootstrap.delivery",
+				"Forbidden::spec.bootstrap.delivery",
+			},
+		},
+		{
+			Bootstrap: kops.InstanceGroupBootstrapSpec{SSHUser: "ubuntu"},
+			Role:      kops.InstanceGroupRoleNode,
+			Cloud:     kops.CloudProviderAWS,
+			Expected:  []string{"Forbidden::spec.bootstrap.sshUser"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				CloudProvider: string(g.Cloud),
+			},
+		}
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "nodes",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:      g.Role,
+				Bootstrap: &g.Bootstrap,
+			},
+		}
+		errs := validateInstanceGroupBootstrap(ig, cluster, field.NewPath("spec", "bootstrap"))
+		testErrors(t, g.Bootstrap, errs, g.Expected)
+	}
+}
+
+func TestInstanceGroupNvidiaGPU(t *testing.T) {
+	grid := []struct {
+		NvidiaGPU      kops.NvidiaGPUConfig
+		ClusterEnabled bool
+		Role           kops.InstanceGroupRole
+		Expected       []string
+	}{
+		{
+			NvidiaGPU:      kops.NvidiaGPUConfig{Enabled: fi.Bool(true), DriverPackage: fi.String("nvidia-headless-470-server")},
+			ClusterEnabled: true,
+			Role:           kops.InstanceGroupRoleNode,
+		},
+		{
+			NvidiaGPU: kops.NvidiaGPUConfig{Enabled: fi.Bool(false)},
+			Role:      kops.InstanceGroupRoleMaster,
+		},
+		{
+			NvidiaGPU: kops.NvidiaGPUConfig{Enabled: fi.Bool(true)},
+			Role:      kops.InstanceGroupRoleNode,
+			Expected:  []string{"Forbidden::spec.nvidiaGPU.enabled"},
+		},
+		{
+			NvidiaGPU:      kops.NvidiaGPUConfig{Enabled: fi.Bool(true)},
+			ClusterEnabled: true,
+			Role:           kops.InstanceGroupRoleMaster,
+			Expected:       []string{"Forbidden::spec.nvidiaGPU.enabled"},
+		},
+		{
+			NvidiaGPU:      kops.NvidiaGPUConfig{Enabled: fi.Bool(true), DriverPackage: fi.String("")},
+			ClusterEnabled: true,
+			Role:           kops.InstanceGroupRoleNode,
+			Expected:       []string{"Required value::spec.nvidiaGPU.driverPackage"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				Containerd: &kops.ContainerdConfig{
+					NvidiaGPU: &kops.NvidiaGPUConfig{Enabled: fi.Bool(g.ClusterEnabled)},
+				},
+			},
+		}
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "gpu-nodes",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:      g.Role,
+				NvidiaGPU: &g.NvidiaGPU,
+			},
+		}
+		errs := validateInstanceGroupNvidiaGPU(ig, cluster, field.NewPath("spec", "nvidiaGPU"))
+		testErrors(t, g.NvidiaGPU, errs, g.Expected)
+	}
+}
+
+func TestInstanceGroupSwap(t *testing.T) {
+	size := resource.MustParse("4Gi")
+	zero := resource.MustParse("0")
+
+	grid := []struct {
+		Swap              kops.SwapSpec
+		KubernetesVersion string
+		Kubelet           *kops.KubeletConfigSpec
+		Expected          []string
+	}{
+		{
+			Swap:              kops.SwapSpec{Size: &size, Swappiness: fi.Int32(10), Encrypted: fi.Bool(true)},
+			KubernetesVersion: "1.22.0",
+		},
+		{
+			Swap:              kops.SwapSpec{},
+			KubernetesVersion: "1.22.0",
+			Expected:          []string{"Required value::spec.swap.size"},
+		},
+		{
+			Swap:              kops.SwapSpec{Size: &zero},
+			KubernetesVersion: "1.22.0",
+			Expected:          []string{"Invalid value::spec.swap.size"},
+		},
+		{
+			Swap:              kops.SwapSpec{Size: &size, Swappiness: fi.Int32(101)},
+			KubernetesVersion: "1.22.0",
+			Expected:          []string{"Invalid value::spec.swap.swappiness"},
+		},
+		{
+			Swap:              kops.SwapSpec{Size: &size},
+			KubernetesVersion: "1.21.0",
+			Expected:          []string{"Forbidden::spec.swap"},
+		},
+		{
+			Swap:              kops.SwapSpec{Size: &size},
+			KubernetesVersion: "1.22.0",
+			Kubelet:           &kops.KubeletConfigSpec{FailSwapOn: fi.Bool(true)},
+			Expected:          []string{"Forbidden::spec.kubelet.failSwapOn"},
+		},
+		{
+			Swap:              kops.SwapSpec{Size: &size},
+			KubernetesVersion: "1.22.0",
+			Kubelet:           &kops.KubeletConfigSpec{MemorySwapBehavior: "NoSwap"},
+			Expected:          []string{"Unsupported value::spec.kubelet.memorySwapBehavior"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				KubernetesVersion: g.KubernetesVersion,
+			},
+		}
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "nodes",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:    kops.InstanceGroupRoleNode,
+				Kubelet: g.Kubelet,
+				Swap:    &g.Swap,
+			},
+		}
+		errs := validateInstanceGroupSwap(ig, cluster, field.NewPath("spec", "swap"))
+		testErrors(t, g.Swap, errs, g.Expected)
+	}
+}
+
+func TestInstanceGroupInstanceStorage(t *testing.T) {
+	grid := []struct {
+		InstanceStorage kops.InstanceStorageSpec
+		CloudProvider   kops.CloudProviderID
+		Expected        []string
+	}{
+		{
+			InstanceStorage: kops.InstanceStorageSpec{Paths: []string{"/var/lib/containerd", "/var/lib/kubelet"}},
+			CloudProvider:   kops.CloudProviderAWS,
+		},
+		{
+			InstanceStorage: kops.InstanceStorageSpec{Mode: "LVM", Filesystem: "xfs", Paths: []string{"/var/lib/containerd"}},
+			CloudProvider:   kops.CloudProviderAWS,
+		},
+		{
+			InstanceStorage: kops.InstanceStorageSpec{Paths: []string{"/var/lib/containerd"}},
+			CloudProvider:   kops.CloudProviderGCE,
+			Expected:        []string{"Forbidden::spec.instanceStorage"},
+		},
+		{
+			InstanceStorage: kops.InstanceStorageSpec{Mode: "RAID5", Filesystem: "ext3", Paths: []string{"/data"}},
+			CloudProvider:   kops.CloudProviderAWS,
+			Expected:        []string{"Unsupported value::spec.instanceStorage.mode", "Unsupported value::spec.instanceStorage.filesystem"},
+		},
+		{
+			InstanceStorage: kops.InstanceStorageSpec{},
+			CloudProvider:   kops.CloudProviderAWS,
+			Expected:        []string{"Required value::spec.instanceStorage.paths"},
+		},
+		{
+			InstanceStorage: kops.InstanceStorageSpec{Paths: []string{"var/lib/containerd", "/data dir"}},
+			CloudProvider:   kops.CloudProviderAWS,
+			Expected:        []string{"Invalid value::spec.instanceStorage.paths[0]", "Invalid value::spec.instanceStorage.paths[1]"},
+		},
+		{
+			InstanceStorage: kops.InstanceStorageSpec{Paths: []string{"/data", "/mnt/ebs"}},
+			CloudProvider:   kops.CloudProviderAWS,
+			Expected:        []string{"Duplicate value::spec.instanceStorage.paths[1]"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				CloudProvider: string(g.CloudProvider),
+			},
+		}
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "nodes",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:            kops.InstanceGroupRoleNode,
+				VolumeMounts:    []kops.VolumeMountSpec{{Device: "/dev/xvdd", Filesystem: "ext4", Path: "/mnt/ebs"}},
+				InstanceStorage: &g.InstanceStorage,
+			},
+		}
+		errs := validateInstanceGroupInstanceStorage(ig, cluster, field.NewPath("spec", "instanceStorage"))
+		testErrors(t, g.InstanceStorage, errs, g.Expected)
+	}
+}
+
+func TestInstanceGroupWindows(t *testing.T) {
+	grid := []struct {
+		Description string
+		Cluster     func(c *kops.ClusterSpec)
+		IG          func(ig *kops.InstanceGroupSpec)
+		Expected    []string
+	}{
+		{
+			Description: "valid",
+		},
+		{
+			Description: "gce",
+			Cluster:     func(c *kops.ClusterSpec) { c.CloudProvider = string(kops.CloudProviderGCE) },
+			Expected:    []string{"Forbidden::spec.operatingSystem"},
+		},
+		{
+			Description: "master",
+			IG:          func(ig *kops.InstanceGroupSpec) { ig.Role = kops.InstanceGroupRoleMaster },
+			Expected:    []string{"Forbidden::spec.operatingSystem"},
+		},
+		{
+			Description: "old kubernetes",
+			Cluster:     func(c *kops.ClusterSpec) { c.KubernetesVersion = "1.19.0" },
+			Expected:    []string{"Forbidden::spec.operatingSystem"},
+		},
+		{
+			Description: "docker",
+			Cluster:     func(c *kops.ClusterSpec) { c.ContainerRuntime = "docker" },
+			Expected:    []string{"Forbidden::spec.operatingSystem"},
+		},
+		{
+			Description: "missing containerd package",
+			Cluster:     func(c *kops.ClusterSpec) { c.Containerd.Packages.HashWindows = nil },
+			Expected:    []string{"Required value::spec.containerd.packages.urlWindows"},
+		},
+		{
+			Description: "kube-proxy disabled",
+			Cluster:     func(c *kops.ClusterSpec) { c.KubeProxy = &kops.KubeProxyConfig{Enabled: fi.Bool(false)} },
+			Expected:    []string{"Forbidden::spec.operatingSystem"},
+		},
+		{
+			Description: "cilium",
+			Cluster:     func(c *kops.ClusterSpec) { c.Networking = &kops.NetworkingSpec{Cilium: &kops.CiliumNetworkingSpec{}} },
+			Expected:    []string{"Forbidden::spec.operatingSystem"},
+		},
+		{
+			Description: "calico ipip",
+			Cluster:     func(c *kops.ClusterSpec) { c.Networking.Calico.EncapsulationMode = "ipip" },
+			Expected:    []string{"Forbidden::spec.operatingSystem"},
+		},
+		{
+			Description: "missing calico package",
+			Cluster:     func(c *kops.ClusterSpec) { c.Networking.Calico.WindowsPackages = nil },
+			Expected:    []string{"Required value::spec.networking.calico.windowsPackages.urlWindows"},
+		},
+		{
+			Description: "node-local-dns",
+			Cluster: func(c *kops.ClusterSpec) {
+				c.KubeDNS = &kops.KubeDNSConfig{NodeLocalDNS: &kops.NodeLocalDNSConfig{Enabled: fi.Bool(true)}}
+			},
+			Expected: []string{"Forbidden::spec.operatingSystem"},
+		},
+		{
+			Description: "ssh delivery",
+			IG: func(ig *kops.InstanceGroupSpec) {
+				ig.Bootstrap = &kops.InstanceGroupBootstrapSpec{Delivery: kops.BootstrapDeliverySSH}
+			},
+			Expected: []string{"Forbidden::spec.bootstrap.delivery"},
+		},
+		{
+			Description: "linux only settings",
+			IG: func(ig *kops.InstanceGroupSpec) {
+				ig.AdditionalUserData = []kops.UserData{{Name: "extra.sh", Type: "text/x-shellscript"}}
+				ig.NodeTuning = &kops.NodeTuningSpec{}
+				ig.Swap = &kops.SwapSpec{}
+			},
+			Expected: []string{"Forbidden::spec.additionalUserData", "Forbidden::spec.nodeTuning", "Forbidden::spec.swap"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "minimal.example.com",
+			},
+			Spec: kops.ClusterSpec{
+				CloudProvider:     string(kops.CloudProviderAWS),
+				KubernetesVersion: "1.21.0",
+				ContainerRuntime:  "containerd",
+				Containerd: &kops.ContainerdConfig{
+					Packages: &kops.PackagesConfig{
+						UrlWindows:  fi.String("https://example.com/containerd-windows-amd64.tar.gz"),
+						HashWindows: fi.String("0000000000000000000000000000000000000000000000000000000000000000"),
+					},
+				},
+				Networking: &kops.NetworkingSpec{
+					Calico: &kops.CalicoNetworkingSpec{
+						EncapsulationMode: "vxlan",
+						WindowsPackages: &kops.PackagesConfig{
+							UrlWindows:  fi.String("https://example.com/calico-windows.zip"),
+							HashWindows: fi.String("0000000000000000000000000000000000000000000000000000000000000000"),
+						},
+					},
+				},
+			},
+		}
+		if g.Cluster != nil {
+			g.Cluster(&cluster.Spec)
+		}
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "windows",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:            kops.InstanceGroupRoleNode,
+				OperatingSystem: kops.OperatingSystemWindows,
+			},
+		}
+		if g.IG != nil {
+			g.IG(&ig.Spec)
+		}
+		errs := validateInstanceGroupWindows(ig, cluster, field.NewPath("spec", "operatingSystem"))
+		testErrors(t, g.Description, errs, g.Expected)
+	}
+}
+
+func TestInstanceGroupClusterAutoscaler(t *testing.T) {
+	grid := []struct {
+		Description string
+		Cluster     func(c *kops.ClusterSpec)
+		IG          func(ig *kops.InstanceGroup)
+		Expected    []string
+	}{
+		{
+			Description: "valid",
+			IG: func(ig *kops.InstanceGroup) {
+				ig.Annotations = map[string]string{
+					kops.AnnotationClusterAutoscalerPriority:          "10",
+					kops.AnnotationClusterAutoscalerScaleDownDisabled: "true",
+				}
+			},
+		},
+		{
+			Description: "autoscaler disabled",
+			Cluster:     func(c *kops.ClusterSpec) { c.ClusterAutoscaler.Enabled = fi.Bool(false) },
+			IG: func(ig *kops.InstanceGroup) {
+				ig.Annotations = map[string]string{kops.AnnotationClusterAutoscalerScaleDownDisabled: "true"}
+			},
+			Expected: []string{"Forbidden::metadata.annotations[cluster-autoscaler.kops.k8s.io/scale-down-disabled]"},
+		},
+		{
+			Description: "not autoscaled",
+			IG: func(ig *kops.InstanceGroup) {
+				ig.Spec.Autoscale = fi.Bool(false)
+				ig.Annotations = map[string]string{kops.AnnotationClusterAutoscalerPriority: "10"}
+			},
+			Expected: []string{"Forbidden::metadata.annotations[cluster-autoscaler.kops.k8s.io/priority]"},
+		},
+		{
+			Description: "invalid values",
+			IG: func(ig *kops.InstanceGroup) {
+				ig.Annotations = map[string]string{
+					kops.AnnotationClusterAutoscalerPriority:          "high",
+					kops.AnnotationClusterAutoscalerScaleDownDisabled: "yes please",
+				}
+			},
+			Expected: []string{
+				"Invalid value::metadata.annotations[cluster-autoscaler.kops.k8s.io/priority]",
+				"Invalid value::metadata.annotations[cluster-autoscaler.kops.k8s.io/scale-down-disabled]",
+			},
+		},
+		{
+			Description: "priority without priority expander",
+			Cluster:     func(c *kops.ClusterSpec) { c.ClusterAutoscaler.Expander = fi.String("least-waste") },
+			IG: func(ig *kops.InstanceGroup) {
+				ig.Annotations = map[string]string{kops.AnnotationClusterAutoscalerPriority: "10"}
+			},
+			Expected: []string{"Forbidden::metadata.annotations[cluster-autoscaler.kops.k8s.io/priority]"},
+		},
+		{
+			Description: "scale down disabled on gce",
+			Cluster:     func(c *kops.ClusterSpec) { c.CloudProvider = string(kops.CloudProviderGCE) },
+			IG: func(ig *kops.InstanceGroup) {
+				ig.Annotations = map[string]string{kops.AnnotationClusterAutoscalerScaleDownDisabled: "true"}
+			},
+			Expected: []string{"Forbidden::metadata.annotations[cluster-autoscaler.kops.k8s.io/scale-down-disabled]"},
+		},
+		{
+			Description: "fixed size",
+			IG: func(ig *kops.InstanceGroup) {
+				ig.Spec.Autoscale = fi.Bool(true)
+				ig.Spec.MaxSize = fi.Int32(1)
+			},
+			Expected: []string{"Forbidden::spec.autoscale"},
+		},
+		{
+			Description: "fixed size without autoscale",
+			IG: func(ig *kops.InstanceGroup) {
+				ig.Spec.MaxSize = fi.Int32(1)
+			},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				CloudProvider: string(kops.CloudProviderAWS),
+				ClusterAutoscaler: &kops.ClusterAutoscalerConfig{
+					Enabled:  fi.Bool(true),
+					Expander: fi.String("priority"),
+				},
+			},
+		}
+		if g.Cluster != nil {
+			g.Cluster(&cluster.Spec)
+		}
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "nodes",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:    kops.InstanceGroupRoleNode,
+				MinSize: fi.Int32(1),
+				MaxSize: fi.Int32(3),
+			},
+		}
+		if g.IG != nil {
+			g.IG(ig)
+		}
+		errs := validateInstanceGroupClusterAutoscaler(ig, cluster)
+		testErrors(t, g.Description, errs, g.Expected)
+	}
+}
+
+func TestInstanceGroupKubeletConfigFile(t *testing.T) {
+	grid := []struct {
+		ClusterKubelet *kops.KubeletConfigSpec
+		Kubelet        *kops.KubeletConfigSpec
+		Expected       []string
+	}{
+		{
+			ClusterKubelet: &kops.KubeletConfigSpec{ShutdownGracePeriod: &v1.Duration{Duration: 30 * time.Second}},
+			Kubelet:        &kops.KubeletConfigSpec{ShutdownGracePeriodCriticalPods: &v1.Duration{Duration: 10 * time.Second}},
+		},
+		{
+			Kubelet:  &kops.KubeletConfigSpec{ShutdownGracePeriodCriticalPods: &v1.Duration{Duration: 10 * time.Second}},
+			Expected: []string{"Required value::spec.kubelet.shutdownGracePeriod"},
+		},
+		{
+			ClusterKubelet: &kops.KubeletConfigSpec{ShutdownGracePeriod: &v1.Duration{Duration: 30 * time.Second}},
+			Kubelet: &kops.KubeletConfigSpec{
+				ShutdownGracePeriodByPodPriority: []kops.KubeletShutdownGracePeriodByPodPriority{
+					{Priority: 0, ShutdownGracePeriodSeconds: 60},
+				},
+			},
+			Expected: []string{"Forbidden::spec.kubelet.shutdownGracePeriodByPodPriority"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				KubernetesVersion: "1.23.0",
+				Kubelet:           g.ClusterKubelet,
+			},
+		}
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "nodes",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:    kops.InstanceGroupRoleNode,
+				Kubelet: g.Kubelet,
+			},
+		}
+		errs := CrossValidateInstanceGroup(ig, cluster, nil)
+		testErrors(t, g.Kubelet, errs, g.Expected)
+	}
+}
+
+func TestInstanceGroupGracefulShutdown(t *testing.T) {
+	grid := []struct {
+		GracefulShutdown  kops.GracefulShutdownSpec
+		CloudProvider     kops.CloudProviderID
+		KubernetesVersion string
+		Expected          []string
+	}{
+		{
+			CloudProvider:     kops.CloudProviderAWS,
+			KubernetesVersion: "1.21.0",
+		},
+		{
+			GracefulShutdown:  kops.GracefulShutdownSpec{CloudEvents: fi.Bool(true)},
+			CloudProvider:     kops.CloudProviderGCE,
+			KubernetesVersion: "1.21.0",
+		},
+		{
+			GracefulShutdown:  kops.GracefulShutdownSpec{CloudEvents: fi.Bool(false)},
+			CloudProvider:     kops.CloudProviderOpenstack,
+			KubernetesVersion: "1.21.0",
+		},
+		{
+			CloudProvider:     kops.CloudProviderOpenstack,
+			KubernetesVersion: "1.21.0",
+			Expected:          []string{"Forbidden::spec.gracefulShutdown.cloudEvents"},
+		},
+		{
+			CloudProvider:     kops.CloudProviderAWS,
+			KubernetesVersion: "1.20.0",
+			Expected:          []string{"Forbidden::spec.gracefulShutdown"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				CloudProvider:     string(g.CloudProvider),
+				KubernetesVersion: g.KubernetesVersion,
+			},
+		}
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "nodes",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:             kops.InstanceGroupRoleNode,
+				GracefulShutdown: &g.GracefulShutdown,
+			},
+		}
+		errs := validateInstanceGroupGracefulShutdown(ig, cluster, field.NewPath("spec", "gracefulShutdown"))
+		testErrors(t, g.GracefulShutdown, errs, g.Expected)
+	}
+}
+
+func TestInstanceGroupSecurityModules(t *testing.T) {
+	grid := []struct {
+		ContainerRuntime string
+		OperatingSystem  string
+		SecurityModules  kops.SecurityModulesSpec
+		Expected         []string
+	}{
+		{
+			ContainerRuntime: "containerd",
+			SecurityModules:  kops.SecurityModulesSpec{SELinux: fi.Bool(true)},
+		},
+		{
+			ContainerRuntime: "docker",
+			SecurityModules:  kops.SecurityModulesSpec{AppArmor: fi.Bool(true)},
+		},
+		{
+			ContainerRuntime: "docker",
+			SecurityModules:  kops.SecurityModulesSpec{SELinux: fi.Bool(true), AppArmor: fi.Bool(true)},
+			Expected: []string{
+				"Forbidden::spec.securityModules.appArmor",
+				"Forbidden::spec.securityModules.selinux",
+			},
+		},
+		{
+			ContainerRuntime: "containerd",
+			OperatingSystem:  kops.OperatingSystemWindows,
+			SecurityModules:  kops.SecurityModulesSpec{AppArmor: fi.Bool(true)},
+			Expected:         []string{"Forbidden::spec.securityModules"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				ContainerRuntime: g.ContainerRuntime,
+			},
+		}
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "nodes",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:            kops.InstanceGroupRoleNode,
+				OperatingSystem: g.OperatingSystem,
+				SecurityModules: &g.SecurityModules,
+			},
+		}
+		errs := validateInstanceGroupSecurityModules(ig, cluster, field.NewPath("spec", "securityModules"))
+		testErrors(t, g.SecurityModules, errs, g.Expected)
+	}
+}
+
+func TestInstanceGroupContainerd(t *testing.T) {
+	grid := []struct {
+		ContainerRuntime string
+		ClusterMirrors   map[string][]string
+		Containerd       kops.ContainerdConfig
+		Expected         []string
+	}{
+		{
+			ContainerRuntime: "containerd",
+			Containerd: kops.ContainerdConfig{
+				Registries: map[string]kops.ContainerdRegistryConfig{
+					"docker.io": {Mirrors: []string{"https://mirror.example.com"}},
+				},
+				Runtimes: map[string]kops.ContainerdRuntimeConfig{
+					"kata": {Type: "io.containerd.kata.v2"},
+				},
+			},
+		},
+		{
+			ContainerRuntime: "docker",
+			Containerd:       kops.ContainerdConfig{SandboxImage: fi.String("registry.example.com/pause:3.5")},
+			Expected:         []string{"Forbidden::spec.containerd"},
+		},
+		{
+			ContainerRuntime: "containerd",
+			Containerd:       kops.ContainerdConfig{LogLevel: fi.String("debug")},
+			Expected:         []string{"Forbidden::spec.containerd"},
+		},
+		{
+			ContainerRuntime: "containerd",
+			ClusterMirrors:   map[string][]string{"docker.io": {"https://mirror.example.com"}},
+			Containerd: kops.ContainerdConfig{
+				Registries: map[string]kops.ContainerdRegistryConfig{
+					"docker.io": {Mirrors: []string{"https://mirror.example.com"}},
+				},
+			},
+			Expected: []string{"Forbidden::spec.containerd.registries"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				ContainerRuntime: g.ContainerRuntime,
+				Containerd: &kops.ContainerdConfig{
+					RegistryMirrors: g.ClusterMirrors,
+					Version:         fi.String("1.5.5"),
+				},
+			},
+		}
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "nodes",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:       kops.InstanceGroupRoleNode,
+				Containerd: &g.Containerd,
+			},
+		}
+		errs := validateInstanceGroupContainerd(ig, cluster, field.NewPath("spec", "containerd"))
+		testErrors(t, g.Containerd, errs, g.Expected)
+	}
+}
+
+func TestInstanceGroupImagePreload(t *testing.T) {
+	grid := []struct {
+		OperatingSystem string
+		ImagePreload    kops.ImagePreloadSpec
+		Expected        []string
+	}{
+		{
+			ImagePreload: kops.ImagePreloadSpec{
+				Images:           []string{"nginx:1.21", "k8s.gcr.io/pause:3.5"},
+				AddonImages:      fi.Bool(true),
+				PullThroughCache: fi.String("cache.example.com:5000"),
+			},
+		},
+		{
+			ImagePreload: kops.ImagePreloadSpec{
+				Images:           []string{"", "https://k8s.gcr.io/pause:3.5"},
+				PullThroughCache: fi.String("https://cache.example.com"),
+			},
+			Expected: []string{
+				"Invalid value::spec.imagePreload.images[0]",
+				"Invalid value::spec.imagePreload.images[1]",
+				"Invalid value::spec.imagePreload.pullThroughCache",
+			},
+		},
+		{
+			OperatingSystem: kops.OperatingSystemWindows,
+			ImagePreload:    kops.ImagePreloadSpec{Images: []string{"mcr.microsoft.com/windows/nanoserver:1809"}},
+			Expected:        []string{"Forbidden::spec.imagePreload"},
+		},
+	}
+
+	for _, g := range grid {
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "nodes",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:            kops.InstanceGroupRoleNode,
+				OperatingSystem: g.OperatingSystem,
+				ImagePreload:    &g.ImagePreload,
+			},
+		}
+		errs := validateInstanceGroupImagePreload(ig, field.NewPath("spec", "imagePreload"))
+		testErrors(t, g.ImagePreload, errs, g.Expected)
+	}
+}
+
+func TestInstanceGroupScalingSchedules(t *testing.T) {
+	grid := []struct {
+		CloudProvider kops.CloudProviderID
+		Role          kops.InstanceGroupRole
+		Schedules     []kops.ScalingSchedule
+		Expected      []string
+	}{
+		{
+			CloudProvider: kops.CloudProviderAWS,
+			Schedules: []kops.ScalingSchedule{
+				{Name: "night", Schedule: "0 20 * * *", TimeZone: "Europe/Berlin", MinSize: fi.Int32(0), MaxSize: fi.Int32(0)},
+				{Name: "morning", Schedule: "0 7 * * MON-FRI", MinSize: fi.Int32(2), MaxSize: fi.Int32(4)},
+			},
+		},
+		{
+			CloudProvider: kops.CloudProviderGCE,
+			Schedules: []kops.ScalingSchedule{
+				{Name: "day", Schedule: "0 7 * * 1-5", MinSize: fi.Int32(2), Duration: &v1.Duration{Duration: 13 * time.Hour}},
+			},
+		},
+		{
+			CloudProvider: kops.CloudProviderAWS,
+			Schedules: []kops.ScalingSchedule{
+				{Name: "Night", Schedule: "0 20 * *", TimeZone: "Mars/Olympus", MinSize: fi.Int32(3), MaxSize: fi.Int32(2), Duration: &v1.Duration{Duration: time.Hour}},
+				{Schedule: "0 7 * * *"},
+			},
+			Expected: []string{
+				"Invalid value::spec.scalingSchedules[0].name",
+				"Invalid value::spec.scalingSchedules[0].schedule",
+				"Invalid value::spec.scalingSchedules[0].timeZone",
+				"Invalid value::spec.scalingSchedules[0].minSize",
+				"Forbidden::spec.scalingSchedules[0].duration",
+				"Required value::spec.scalingSchedules[1].name",
+				"Required value::spec.scalingSchedules[1].minSize",
+			},
+		},
+		{
+			CloudProvider: kops.CloudProviderGCE,
+			Schedules: []kops.ScalingSchedule{
+				{Name: "day", Schedule: "0 7 * * *", MaxSize: fi.Int32(2)},
+				{Name: "day", Schedule: "0 7 * * *", MinSize: fi.Int32(5), Duration: &v1.Duration{Duration: time.Minute}},
+			},
+			Expected: []string{
+				"Required value::spec.scalingSchedules[0].minSize",
+				"Forbidden::spec.scalingSchedules[0].maxSize",
+				"Required value::spec.scalingSchedules[0].duration",
+				"Duplicate value::spec.scalingSchedules[1].name",
+				"Invalid value::spec.scalingSchedules[1].minSize",
+				"Invalid value::spec.scalingSchedules[1].duration",
+			},
+		},
+		{
+			CloudProvider: kops.CloudProviderAWS,
+			Role:          kops.InstanceGroupRoleMaster,
+			Schedules: []kops.ScalingSchedule{
+				{Name: "night", Schedule: "0 20 * * *", MinSize: fi.Int32(0)},
+			},
+			Expected: []string{"Forbidden::spec.scalingSchedules"},
+		},
+		{
+			CloudProvider: kops.CloudProviderOpenstack,
+			Schedules: []kops.ScalingSchedule{
+				{Name: "night", Schedule: "0 20 * * *", MinSize: fi.Int32(0)},
+			},
+			Expected: []string{"Forbidden::spec.scalingSchedules"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				CloudProvider: string(g.CloudProvider),
+			},
+		}
+		role := g.Role
+		if role == "" {
+			role = kops.InstanceGroupRoleNode
+		}
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "nodes",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:             role,
+				MinSize:          fi.Int32(0),
+				MaxSize:          fi.Int32(4),
+				ScalingSchedules: g.Schedules,
+			},
+		}
+		errs := validateInstanceGroupScalingSchedules(ig, cluster, field.NewPath("spec", "scalingSchedules"))
+		testErrors(t, g.Schedules, errs, g.Expected)
+	}
+}
+
+func TestInstanceGroupAPIServerEndpoint(t *testing.T) {
+	networkLoadBalancer := &kops.LoadBalancerAccessSpec{
+		Type:  kops.LoadBalancerTypePublic,
+		Class: kops.LoadBalancerClassNetwork,
+	}
+
+	grid := []struct {
+		Endpoint      kops.APIServerEndpointSpec
+		CloudProvider kops.CloudProviderID
+		ClusterName   string
+		Role          kops.InstanceGroupRole
+		LoadBalancer  *kops.LoadBalancerAccessSpec
+		Expected      []string
+	}{
+		{
+			Endpoint: kops.APIServerEndpointSpec{Type: kops.APIServerEndpointTypeInternal},
+		},
+		{
+			Endpoint: kops.APIServerEndpointSpec{Type: kops.APIServerEndpointTypeInternal, IPs: []string{"10.0.0.1"}},
+			Expected: []string{"Forbidden::spec.apiServerEndpoint.ips"},
+		},
+		{
+			Endpoint:     kops.APIServerEndpointSpec{Type: kops.APIServerEndpointTypeExternal},
+			LoadBalancer: networkLoadBalancer,
+		},
+		{
+			Endpoint: kops.APIServerEndpointSpec{Type: kops.APIServerEndpointTypeExternal},
+			LoadBalancer: &kops.LoadBalancerAccessSpec{
+				Type:  kops.LoadBalancerTypePublic,
+				Class: kops.LoadBalancerClassClassic,
+			},
+			Expected: []string{"Forbidden::spec.apiServerEndpoint.type"},
+		},
+		{
+			Endpoint:      kops.APIServerEndpointSpec{Type: kops.APIServerEndpointTypeExternal},
+			CloudProvider: kops.CloudProviderGCE,
+			LoadBalancer:  networkLoadBalancer,
+			Expected:      []string{"Forbidden::spec.apiServerEndpoint.type"},
+		},
+		{
+			Endpoint: kops.APIServerEndpointSpec{Type: kops.APIServerEndpointTypeIPs, IPs: []string{"203.0.113.10", "2001:db8::10"}},
+		},
+		{
+			Endpoint: kops.APIServerEndpointSpec{Type: kops.APIServerEndpointTypeIPs},
+			Expected: []string{"Required value::spec.apiServerEndpoint.ips"},
+		},
+		{
+			Endpoint: kops.APIServerEndpointSpec{Type: kops.APIServerEndpointTypeIPs, IPs: []string{"api.example.com"}},
+			Expected: []string{"Invalid value::spec.apiServerEndpoint.ips[0]"},
+		},
+		{
+			Endpoint: kops.APIServerEndpointSpec{Type: kops.APIServerEndpointTypeIPs, IPs: []string{"203.0.113.10"}},
+			Role:     kops.InstanceGroupRoleAPIServer,
+			Expected: []string{"Forbidden::spec.apiServerEndpoint"},
+		},
+		{
+			Endpoint:    kops.APIServerEndpointSpec{Type: kops.APIServerEndpointTypeIPs, IPs: []string{"203.0.113.10"}},
+			ClusterName: "minimal.k8s.local",
+			Expected:    []string{"Forbidden::spec.apiServerEndpoint"},
+		},
+		{
+			Endpoint: kops.APIServerEndpointSpec{Type: "Direct"},
+			Expected: []string{"Unsupported value::spec.apiServerEndpoint.type"},
+		},
+	}
+
+	for _, g := range grid {
+		cloudProvider := g.CloudProvider
+		if cloudProvider == "" {
+			cloudProvider = kops.CloudProviderAWS
+		}
+		clusterName := g.ClusterName
+		if clusterName == "" {
+			clusterName = "minimal.example.com"
+		}
+		role := g.Role
+		if role == "" {
+			role = kops.InstanceGroupRoleNode
+		}
+		cluster := &kops.Cluster{
+			ObjectMeta: v1.ObjectMeta{
+				Name: clusterName,
+			},
+			Spec: kops.ClusterSpec{
+				CloudProvider: string(cloudProvider),
+				API: &kops.AccessSpec{
+					LoadBalancer: g.LoadBalancer,
+				},
+			},
+		}
+		ig := &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "edge",
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:              role,
+				APIServerEndpoint: &g.Endpoint,
+			},
+		}
+		errs := validateInstanceGroupAPIServerEndpoint(ig, cluster, field.NewPath("spec", "apiServerEndpoint"))
+		testErrors(t, g.Endpoint, errs, g.Expected)
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/util"
+	"k8s.io/kops/pkg/dns"
 	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/pkg/util/subnet"
 	"k8s.io/kops/upup/pkg/fi"
@@ -73,8 +74,8 @@ func ValidateCluster(c *kops.Cluster, strict bool) field.ErrorList {
 		requiresSubnets = false
 		requiresSubnetCIDR = false
 		requiresNetworkCIDR = false
-		if c.Spec.NetworkCIDR != "" {
-			allErrs = append(allErrs, field.Forbidden(fieldSpec.Child("networkCIDR"), "networkCIDR should not be set on DigitalOcean"))
+		if c.Spec.NetworkCIDR != "" && c.SharedVPC() {
+			allErrs = append(allErrs, field.Forbidden(fieldSpec.Child("networkCIDR"), "networkCIDR should not be set on DigitalOcean when using an existing VPC"))
 		}
 	case kops.CloudProviderALI:
 		requiresSubnets = false
@@ -85,6 +86,13 @@ func ValidateCluster(c *kops.Cluster, strict bool) field.ErrorList {
 	case kops.CloudProviderOpenstack:
 		requiresNetworkCIDR = false
 		requiresSubnetCIDR = false
+	case kops.CloudProviderMetal:
+		requiresSubnets = false
+		requiresSubnetCIDR = false
+		requiresNetworkCIDR = false
+		if !dns.IsGossipHostname(c.ObjectMeta.Name) {
+			allErrs = append(allErrs, field.Forbidden(fieldSpec.Child("cloudProvider"), "the metal cloud provider requires a gossip cluster name (ending in .k8s.local)"))
+		}
 
 	default:
 		allErrs = append(allErrs, field.NotSupported(fieldSpec.Child("cloudProvider"), c.Spec.CloudProvider, []string{
@@ -94,6 +102,7 @@ func ValidateCluster(c *kops.Cluster, strict bool) field.ErrorList {
 			string(kops.CloudProviderAzure),
 			string(kops.CloudProviderAWS),
 			string(kops.CloudProviderOpenstack),
+			string(kops.CloudProviderMetal),
 		}))
 	}
 
@@ -287,6 +296,8 @@ func ValidateCluster(c *kops.Cluster, strict bool) field.ErrorList {
 				}
 			}
 		}
+
+		allErrs = append(allErrs, validateCoreDNS(c.Spec.KubeDNS, fieldSpec.Child("kubeDNS"))...)
 	}
 
 	// Check CloudProvider
@@ -306,6 +317,8 @@ func ValidateCluster(c *kops.Cluster, strict bool) field.ErrorList {
 			k8sCloudProvider = "alicloud"
 		case kops.CloudProviderAzure:
 			k8sCloudProvider = "azure"
+		case kops.CloudProviderMetal:
+			k8sCloudProvider = ""
 		default:
 			// We already added an error above
 			k8sCloudProvider = "ignore"
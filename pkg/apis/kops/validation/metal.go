@@ -0,0 +1,62 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+func metalValidateInstanceGroup(ig *kops.InstanceGroup) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	fieldSpec := field.NewPath("spec")
+
+	if len(ig.Spec.Machines) == 0 {
+		allErrs = append(allErrs, field.Required(fieldSpec.Child("machines"), "metal instance groups must list at least one machine"))
+	}
+
+	names := make(map[string]bool)
+	for i, machine := range ig.Spec.Machines {
+		f := fieldSpec.Child("machines").Index(i)
+		if machine.Name == "" {
+			allErrs = append(allErrs, field.Required(f.Child("name"), ""))
+		} else if names[machine.Name] {
+			allErrs = append(allErrs, field.Duplicate(f.Child("name"), machine.Name))
+		}
+		names[machine.Name] = true
+
+		if machine.Address == "" {
+			allErrs = append(allErrs, field.Required(f.Child("address"), ""))
+		}
+
+		if machine.SSHHostKeyFingerprint != "" && !strings.HasPrefix(machine.SSHHostKeyFingerprint, "SHA256:") {
+			allErrs = append(allErrs, field.Invalid(f.Child("sshHostKeyFingerprint"), machine.SSHHostKeyFingerprint, "must be a SHA256 fingerprint, as printed by ssh-keygen -l"))
+		}
+	}
+
+	if ig.Spec.MinSize != nil && int(*ig.Spec.MinSize) > len(ig.Spec.Machines) {
+		allErrs = append(allErrs, field.Invalid(fieldSpec.Child("minSize"), *ig.Spec.MinSize, "minSize cannot be larger than the number of machines"))
+	}
+	if ig.Spec.MaxSize != nil && int(*ig.Spec.MaxSize) > len(ig.Spec.Machines) {
+		allErrs = append(allErrs, field.Invalid(fieldSpec.Child("maxSize"), *ig.Spec.MaxSize, "maxSize cannot be larger than the number of machines"))
+	}
+
+	return allErrs
+}
@@ -17,17 +17,22 @@ limitations under the License.
 package validation
 
 import (
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"net"
 	"net/url"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/blang/semver/v4"
 	"golang.org/x/net/ipv4"
 	"golang.org/x/net/ipv6"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/api/validation"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	utilnet "k8s.io/apimachinery/pkg/util/net"
@@ -35,9 +40,13 @@ import (
 	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/model"
+	"k8s.io/kops/pkg/dns"
+	"k8s.io/kops/pkg/envelope"
 	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/pkg/model/components"
 	"k8s.io/kops/pkg/model/iam"
+	"k8s.io/kops/pkg/wellknownports"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/utils"
 )
@@ -103,11 +112,69 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 
 	if spec.Topology != nil {
 		allErrs = append(allErrs, validateTopology(spec.Topology, fieldPath.Child("topology"))...)
+		if spec.Topology.InstanceAccess != nil {
+			allErrs = append(allErrs, validateInstanceAccess(spec, fieldPath.Child("topology"))...)
+		}
+	}
+
+	if spec.Target != nil && spec.Target.Terraform != nil {
+		allErrs = append(allErrs, validateTerraform(spec.Target.Terraform, fieldPath.Child("target", "terraform"))...)
+	}
+
+	if spec.SecretStoreEncryption != nil {
+		allErrs = append(allErrs, validateSecretStoreEncryption(spec.SecretStoreEncryption, fieldPath.Child("secretStoreEncryption"))...)
+	}
+
+	if spec.Audit != nil {
+		allErrs = append(allErrs, validateAudit(spec, fieldPath.Child("audit"))...)
+	}
+
+	if spec.Logging != nil && fi.BoolValue(spec.Logging.Enabled) {
+		allErrs = append(allErrs, validateLogging(spec, fieldPath.Child("logging"))...)
+	}
+
+	if spec.PodSecurity != nil {
+		allErrs = append(allErrs, validatePodSecurity(spec, c, fieldPath)...)
+	}
+
+	if spec.EncryptionAtRest != nil {
+		allErrs = append(allErrs, validateEncryptionAtRest(spec, fieldPath.Child("encryptionAtRest"))...)
+	}
+
+	if fi.BoolValue(spec.FIPS) {
+		allErrs = append(allErrs, validateFIPS(spec, fieldPath)...)
+	}
+
+	if spec.Hardening != nil {
+		allErrs = append(allErrs, validateHardening(spec, fieldPath)...)
+	}
+
+	if spec.Authentication != nil && spec.Authentication.OIDC != nil {
+		allErrs = append(allErrs, validateOIDCAuthentication(spec, fieldPath)...)
+	}
+
+	if spec.NodeCertificates != nil {
+		allErrs = append(allErrs, validateNodeCertificates(spec.NodeCertificates, c, fieldPath.Child("nodeCertificates"))...)
+	}
+
+	if spec.NodeBootstrap != nil {
+		allErrs = append(allErrs, validateNodeBootstrap(spec.NodeBootstrap, c, fieldPath.Child("nodeBootstrap"))...)
+	}
+
+	if spec.EgressProxy != nil {
+		allErrs = append(allErrs, validateEgressProxy(spec.EgressProxy, fieldPath.Child("egressProxy"))...)
+	}
+
+	if spec.InstanceGroupScaling != nil {
+		allErrs = append(allErrs, validateInstanceGroupScaling(spec.InstanceGroupScaling, c, fieldPath.Child("instanceGroupScaling"))...)
 	}
 
 	// UpdatePolicy
 	allErrs = append(allErrs, IsValidValue(fieldPath.Child("updatePolicy"), spec.UpdatePolicy, []string{kops.UpdatePolicyAutomatic, kops.UpdatePolicyExternal})...)
 
+	// Addons
+	allErrs = append(allErrs, validateAddons(spec.Addons, fieldPath.Child("addons"))...)
+
 	// Hooks
 	for i := range spec.Hooks {
 		allErrs = append(allErrs, validateHookSpec(&spec.Hooks[i], fieldPath.Child("hooks").Index(i))...)
@@ -199,6 +266,7 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 			}
 			allErrs = append(allErrs, validateEtcdBackupStore(spec.EtcdClusters, fieldEtcdClusters)...)
 			allErrs = append(allErrs, validateEtcdTLS(spec.EtcdClusters, fieldEtcdClusters)...)
+			allErrs = append(allErrs, validateEtcdExternalClusters(spec.EtcdClusters, fieldEtcdClusters)...)
 			allErrs = append(allErrs, validateEtcdStorage(spec.EtcdClusters, fieldEtcdClusters)...)
 		}
 	}
@@ -209,6 +277,17 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 
 	if spec.Containerd != nil {
 		allErrs = append(allErrs, validateContainerdConfig(spec.Containerd, fieldPath.Child("containerd"))...)
+		allErrs = append(allErrs, validateContainerdSettings(spec.Containerd, c, fieldPath.Child("containerd"))...)
+		if len(spec.Containerd.Registries) > 0 && len(spec.Containerd.RegistryMirrors) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("containerd", "registries"), "registries cannot be combined with registryMirrors"))
+		}
+		if spec.Containerd.NvidiaGPU != nil {
+			allErrs = append(allErrs, validateNvidiaGPU(spec.Containerd.NvidiaGPU, c, fieldPath.Child("containerd", "nvidiaGPU"))...)
+		}
+	}
+
+	if spec.NodeTuning != nil {
+		allErrs = append(allErrs, validateNodeTuning(spec.NodeTuning, fieldPath.Child("nodeTuning"))...)
 	}
 
 	if spec.Docker != nil {
@@ -219,6 +298,9 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 		if spec.Assets.ContainerProxy != nil && spec.Assets.ContainerRegistry != nil {
 			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("assets", "containerProxy"), "containerProxy cannot be used in conjunction with containerRegistry"))
 		}
+		if fi.BoolValue(spec.Assets.AirGapped) {
+			allErrs = append(allErrs, validateAirGapped(spec, fieldPath)...)
+		}
 	}
 
 	if spec.IAM == nil || spec.IAM.Legacy {
@@ -243,6 +325,26 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 		}
 	}
 
+	if spec.API != nil && spec.API.Gateway != nil {
+		allErrs = append(allErrs, validateGatewayAccess(spec.API, fieldPath.Child("api"))...)
+	}
+
+	if spec.API != nil && spec.API.DNS != nil && spec.API.DNS.InternalZoneID != "" {
+		allErrs = append(allErrs, validateInternalZone(c, fieldPath.Child("api", "dns", "internalZoneID"))...)
+	}
+
+	if spec.API != nil && spec.API.LoadBalancer != nil && spec.API.LoadBalancer.PrivateLink != nil && kops.CloudProviderID(spec.CloudProvider) != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("api", "loadBalancer", "privateLink"), "privateLink is only supported on AWS"))
+	}
+
+	if spec.GossipConfig != nil && fi.StringValue(spec.GossipConfig.Protocol) == kops.GossipProtocolKopsController {
+		allErrs = append(allErrs, validateKopsControllerGossip(c, fieldPath.Child("gossipConfig"))...)
+	}
+
+	if spec.ExternalDNS != nil {
+		allErrs = append(allErrs, validateExternalDNS(c, spec.ExternalDNS, fieldPath.Child("externalDns"))...)
+	}
+
 	if spec.CloudConfig != nil {
 		allErrs = append(allErrs, validateCloudConfiguration(spec.CloudConfig, fieldPath.Child("cloudConfig"))...)
 	}
@@ -339,6 +441,448 @@ func validateIPv6CIDR(cidr string, fieldPath *field.Path) field.ErrorList {
 	return allErrs
 }
 
+func validateSecretStoreEncryption(spec *kops.SecretStoreEncryptionSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.KMSKey == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("kmsKey"), ""))
+	} else if err := envelope.ValidateKeyID(spec.KMSKey); err != nil {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("kmsKey"), spec.KMSKey, err.Error()))
+	}
+
+	return allErrs
+}
+
+// maxPreviousKMSKeys is the number of previous KMS keys whose plugins fit in the health check ports
+// reserved for aws-encryption-provider, together with the plugin of the current key
+const maxPreviousKMSKeys = wellknownports.AWSEncryptionProviderHealthCheckMaxKeys - 1
+
+func validateEncryptionAtRest(spec *kops.ClusterSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	encryption := spec.EncryptionAtRest
+
+	if kops.CloudProviderID(spec.CloudProvider) != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "encryption at rest with a KMS key is only supported on AWS"))
+	}
+
+	validateKey := func(kmsKey string, fldPath *field.Path) {
+		if kmsKey == "" {
+			allErrs = append(allErrs, field.Required(fldPath, ""))
+		} else if parsed, err := arn.Parse(kmsKey); err != nil || parsed.Service != "kms" {
+			allErrs = append(allErrs, field.Invalid(fldPath, kmsKey, "must be the ARN of a KMS key or alias"))
+		}
+	}
+	validateKey(encryption.KMSKey, fieldPath.Child("kmsKey"))
+
+	// Each KMS key has its own plugin, with a health check port in the range reserved for them
+	if len(encryption.PreviousKMSKeys) > maxPreviousKMSKeys {
+		allErrs = append(allErrs, field.TooMany(fieldPath.Child("previousKMSKeys"), len(encryption.PreviousKMSKeys), maxPreviousKMSKeys))
+	}
+
+	keys := sets.NewString(encryption.KMSKey)
+	for i, kmsKey := range encryption.PreviousKMSKeys {
+		validateKey(kmsKey, fieldPath.Child("previousKMSKeys").Index(i))
+		if keys.Has(kmsKey) {
+			allErrs = append(allErrs, field.Duplicate(fieldPath.Child("previousKMSKeys").Index(i), kmsKey))
+		}
+		keys.Insert(kmsKey)
+	}
+
+	for i, resource := range encryption.Resources {
+		if resource == "" {
+			allErrs = append(allErrs, field.Required(fieldPath.Child("resources").Index(i), ""))
+		}
+	}
+
+	return allErrs
+}
+
+// validateFIPS rejects the options which would use cryptography not approved by FIPS 140-2
+func validateFIPS(spec *kops.ClusterSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	validateTLS := func(cipherSuites []string, minVersion string, fldPath *field.Path) {
+		for i, cipherSuite := range cipherSuites {
+			if !sets.NewString(components.FIPSCipherSuites...).Has(cipherSuite) {
+				allErrs = append(allErrs, field.NotSupported(fldPath.Child("tlsCipherSuites").Index(i), cipherSuite, components.FIPSCipherSuites))
+			}
+		}
+		if minVersion != "" && minVersion != "VersionTLS12" && minVersion != "VersionTLS13" {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("tlsMinVersion"), minVersion, []string{"VersionTLS12", "VersionTLS13"}))
+		}
+	}
+	if spec.KubeAPIServer != nil {
+		validateTLS(spec.KubeAPIServer.TLSCipherSuites, spec.KubeAPIServer.TLSMinVersion, fieldPath.Child("kubeAPIServer"))
+	}
+	if spec.KubeControllerManager != nil {
+		validateTLS(spec.KubeControllerManager.TLSCipherSuites, spec.KubeControllerManager.TLSMinVersion, fieldPath.Child("kubeControllerManager"))
+	}
+	if spec.Kubelet != nil {
+		validateTLS(spec.Kubelet.TLSCipherSuites, spec.Kubelet.TLSMinVersion, fieldPath.Child("kubelet"))
+	}
+	if spec.MasterKubelet != nil {
+		validateTLS(spec.MasterKubelet.TLSCipherSuites, spec.MasterKubelet.TLSMinVersion, fieldPath.Child("masterKubelet"))
+	}
+
+	for i, etcdCluster := range spec.EtcdClusters {
+		if etcdCluster.Manager == nil {
+			continue
+		}
+		for j, envVar := range etcdCluster.Manager.Env {
+			if envVar.Name == "ETCD_CIPHER_SUITES" {
+				allErrs = append(allErrs, field.Forbidden(fieldPath.Child("etcdClusters").Index(i).Child("manager", "env").Index(j), "the cipher suites of etcd are set by kOps on FIPS clusters"))
+			}
+		}
+	}
+
+	if spec.Networking != nil && spec.Networking.Calico != nil && spec.Networking.Calico.WireguardEnabled {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("networking", "calico", "wireguardEnabled"), "WireGuard encryption is not FIPS approved"))
+	}
+
+	return allErrs
+}
+
+func validateHardening(spec *kops.ClusterSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	switch spec.Hardening.Profile {
+	case "":
+	case kops.HardeningProfileCIS:
+		if spec.Authorization != nil && spec.Authorization.AlwaysAllow != nil {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("authorization", "alwaysAllow"), "the CIS hardening profile requires RBAC authorization"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fieldPath.Child("hardening", "profile"), spec.Hardening.Profile, []string{kops.HardeningProfileCIS}))
+	}
+
+	return allErrs
+}
+
+func validateAudit(spec *kops.ClusterSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	audit := spec.Audit
+
+	if webhook := audit.Webhook; webhook != nil {
+		webhookPath := fieldPath.Child("webhook")
+		if webhook.Server == "" {
+			allErrs = append(allErrs, field.Required(webhookPath.Child("server"), ""))
+		} else if u, err := url.Parse(webhook.Server); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(webhookPath.Child("server"), webhook.Server, "must be an http or https URL"))
+		}
+		allErrs = append(allErrs, IsValidValue(webhookPath.Child("mode"), &webhook.Mode, []string{"", "batch", "blocking"})...)
+	}
+
+	if shipping := audit.LogShipping; shipping != nil {
+		shippingPath := fieldPath.Child("logShipping")
+
+		destinations := 0
+		if shipping.S3 != nil {
+			destinations++
+			if shipping.S3.Bucket == "" {
+				allErrs = append(allErrs, field.Required(shippingPath.Child("s3", "bucket"), ""))
+			}
+			if kops.CloudProviderID(spec.CloudProvider) != kops.CloudProviderAWS {
+				allErrs = append(allErrs, field.Forbidden(shippingPath.Child("s3"), "shipping audit logs to S3 is only supported on AWS"))
+			}
+		}
+		if shipping.CloudWatch != nil {
+			destinations++
+			if shipping.CloudWatch.LogGroup == "" {
+				allErrs = append(allErrs, field.Required(shippingPath.Child("cloudWatch", "logGroup"), ""))
+			}
+			if kops.CloudProviderID(spec.CloudProvider) != kops.CloudProviderAWS {
+				allErrs = append(allErrs, field.Forbidden(shippingPath.Child("cloudWatch"), "shipping audit logs to CloudWatch is only supported on AWS"))
+			}
+		}
+		if shipping.GCS != nil {
+			destinations++
+			if shipping.GCS.Bucket == "" {
+				allErrs = append(allErrs, field.Required(shippingPath.Child("gcs", "bucket"), ""))
+			}
+		}
+		if destinations != 1 {
+			allErrs = append(allErrs, field.Invalid(shippingPath, destinations, "exactly one of s3, cloudWatch or gcs must be set"))
+		}
+
+		if spec.KubeAPIServer != nil && fi.StringValue(spec.KubeAPIServer.AuditLogPath) == "-" {
+			allErrs = append(allErrs, field.Forbidden(shippingPath, "log shipping requires kubeAPIServer.auditLogPath to be a file"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateLogging(spec *kops.ClusterSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	logging := spec.Logging
+	cloudProvider := kops.CloudProviderID(spec.CloudProvider)
+
+	sinks := 0
+	if logging.CloudWatch != nil {
+		sinks++
+		if logging.CloudWatch.LogGroup == "" {
+			allErrs = append(allErrs, field.Required(fieldPath.Child("cloudWatch", "logGroup"), ""))
+		}
+		if cloudProvider != kops.CloudProviderAWS {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("cloudWatch"), "shipping logs to CloudWatch is only supported on AWS"))
+		}
+	}
+	if logging.S3 != nil {
+		sinks++
+		if logging.S3.Bucket == "" {
+			allErrs = append(allErrs, field.Required(fieldPath.Child("s3", "bucket"), ""))
+		}
+		if cloudProvider != kops.CloudProviderAWS {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("s3"), "shipping logs to S3 is only supported on AWS"))
+		}
+	}
+	if logging.Stackdriver != nil {
+		sinks++
+		if cloudProvider != kops.CloudProviderGCE {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("stackdriver"), "shipping logs to Stackdriver is only supported on GCE"))
+		}
+	}
+	if logging.Loki != nil {
+		sinks++
+		if logging.Loki.URL == "" {
+			allErrs = append(allErrs, field.Required(fieldPath.Child("loki", "url"), ""))
+		} else if u, err := url.Parse(logging.Loki.URL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("loki", "url"), logging.Loki.URL, "must be an http or https URL"))
+		}
+	}
+	if sinks == 0 {
+		allErrs = append(allErrs, field.Required(fieldPath, "at least one of cloudWatch, s3, stackdriver or loki must be set"))
+	}
+
+	return allErrs
+}
+
+var podSecurityVersionRegex = regexp.MustCompile(`^(latest|v1\.[0-9]+)$`)
+
+func validatePodSecurity(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	podSecurity := spec.PodSecurity
+	podSecurityPath := fieldPath.Child("podSecurity")
+
+	if !c.IsKubernetesGTE("1.22") {
+		allErrs = append(allErrs, field.Forbidden(podSecurityPath, "podSecurity requires Kubernetes 1.22 or later"))
+	}
+
+	levels := []string{"", "privileged", "baseline", "restricted"}
+	allErrs = append(allErrs, IsValidValue(podSecurityPath.Child("enforce"), &podSecurity.Enforce, levels)...)
+	allErrs = append(allErrs, IsValidValue(podSecurityPath.Child("audit"), &podSecurity.Audit, levels)...)
+	allErrs = append(allErrs, IsValidValue(podSecurityPath.Child("warn"), &podSecurity.Warn, levels)...)
+
+	if podSecurity.Version != "" && !podSecurityVersionRegex.MatchString(podSecurity.Version) {
+		allErrs = append(allErrs, field.Invalid(podSecurityPath.Child("version"), podSecurity.Version, "must be latest or a Kubernetes minor version such as v1.22"))
+	}
+
+	for i, namespace := range podSecurity.ExemptNamespaces {
+		for _, msg := range validation.ValidateNamespaceName(namespace, false) {
+			allErrs = append(allErrs, field.Invalid(podSecurityPath.Child("exemptNamespaces").Index(i), namespace, msg))
+		}
+	}
+
+	if spec.KubeAPIServer != nil && spec.KubeAPIServer.AdmissionControlConfigFile != "" {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("kubeAPIServer", "admissionControlConfigFile"), "cannot be combined with podSecurity, which writes the admission configuration"))
+	}
+
+	return allErrs
+}
+
+func validateOIDCAuthentication(spec *kops.ClusterSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	oidc := spec.Authentication.OIDC
+	oidcPath := fieldPath.Child("authentication", "oidc")
+
+	if oidc.IssuerURL == "" {
+		allErrs = append(allErrs, field.Required(oidcPath.Child("issuerURL"), ""))
+	} else if u, err := url.Parse(oidc.IssuerURL); err != nil || u.Scheme != "https" || u.Host == "" {
+		allErrs = append(allErrs, field.Invalid(oidcPath.Child("issuerURL"), oidc.IssuerURL, "must be an https URL"))
+	}
+
+	if oidc.ClientID == "" {
+		allErrs = append(allErrs, field.Required(oidcPath.Child("clientID"), ""))
+	}
+
+	if oidc.CertificateAuthority != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(oidc.CertificateAuthority)) {
+		allErrs = append(allErrs, field.Invalid(oidcPath.Child("certificateAuthority"), "...", "must contain PEM encoded certificates"))
+	}
+
+	for claim := range oidc.RequiredClaims {
+		if claim == "" {
+			allErrs = append(allErrs, field.Invalid(oidcPath.Child("requiredClaims"), claim, "claim names must not be empty"))
+		}
+	}
+
+	// The authentication spec replaces the OIDC flags, so only allow flags that match what it would set
+	if c := spec.KubeAPIServer; c != nil {
+		expected := &kops.KubeAPIServerConfig{}
+		components.SetOIDCFlags(expected, oidc)
+
+		apiserverPath := fieldPath.Child("kubeAPIServer")
+		flags := []struct {
+			name     string
+			value    *string
+			expected *string
+		}{
+			{"oidcIssuerURL", c.OIDCIssuerURL, expected.OIDCIssuerURL},
+			{"oidcClientID", c.OIDCClientID, expected.OIDCClientID},
+			{"oidcUsernameClaim", c.OIDCUsernameClaim, expected.OIDCUsernameClaim},
+			{"oidcUsernamePrefix", c.OIDCUsernamePrefix, expected.OIDCUsernamePrefix},
+			{"oidcGroupsClaim", c.OIDCGroupsClaim, expected.OIDCGroupsClaim},
+			{"oidcGroupsPrefix", c.OIDCGroupsPrefix, expected.OIDCGroupsPrefix},
+			{"oidcCAFile", c.OIDCCAFile, expected.OIDCCAFile},
+		}
+		for _, flag := range flags {
+			if flag.value != nil && (flag.expected == nil || *flag.value != *flag.expected) {
+				allErrs = append(allErrs, field.Forbidden(apiserverPath.Child(flag.name), "cannot be combined with authentication.oidc; configure it in authentication.oidc instead"))
+			}
+		}
+		if len(c.OIDCRequiredClaim) != 0 && strings.Join(c.OIDCRequiredClaim, ",") != strings.Join(expected.OIDCRequiredClaim, ",") {
+			allErrs = append(allErrs, field.Forbidden(apiserverPath.Child("oidcRequiredClaim"), "cannot be combined with authentication.oidc; configure it in authentication.oidc.requiredClaims instead"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateNodeCertificates(spec *kops.NodeCertificatesSpec, c *kops.Cluster, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.KubeletValidity != nil {
+		if !model.UseKopsControllerForNodeBootstrap(c) {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("kubeletValidity"), "kubeletValidity requires nodes to bootstrap through kops-controller (AWS and Kubernetes 1.19 or later)"))
+		}
+		if spec.KubeletValidity.Duration < time.Hour {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("kubeletValidity"), spec.KubeletValidity.Duration.String(), "must be at least 1h"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateNodeBootstrap(spec *kops.NodeBootstrapSpec, c *kops.Cluster, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.UseKopsController != nil && *spec.UseKopsController {
+		if kops.CloudProviderID(c.Spec.CloudProvider) != kops.CloudProviderGCE {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("useKopsController"), "useKopsController is only supported on GCE; nodes of AWS clusters always bootstrap through kops-controller"))
+		} else if !c.IsKubernetesGTE("1.19") {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("useKopsController"), "useKopsController requires Kubernetes 1.19 or later"))
+		}
+	}
+
+	if spec.JoinTokens != nil && *spec.JoinTokens && !model.UseKopsControllerForNodeBootstrap(c) {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("joinTokens"), "joinTokens requires nodes to bootstrap through kops-controller"))
+	}
+
+	return allErrs
+}
+
+// validateAirGapped ensures that nothing needs to be fetched from the internet when the cluster is air-gapped
+func validateAirGapped(spec *kops.ClusterSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	assetsPath := fieldPath.Child("assets")
+	if fi.StringValue(spec.Assets.ContainerRegistry) == "" {
+		allErrs = append(allErrs, field.Required(assetsPath.Child("containerRegistry"), "air-gapped clusters must serve their images from a container registry"))
+	}
+	if fi.StringValue(spec.Assets.FileRepository) == "" {
+		allErrs = append(allErrs, field.Required(assetsPath.Child("fileRepository"), "air-gapped clusters must serve their files from a file repository"))
+	}
+	if spec.Assets.ContainerProxy != nil {
+		allErrs = append(allErrs, field.Forbidden(assetsPath.Child("containerProxy"), "air-gapped clusters cannot use a pull-through proxy"))
+	}
+
+	channel := spec.Channel
+	if channel == "" {
+		channel = kops.DefaultChannel
+	}
+	if u, err := kops.ResolveChannel(channel); err == nil && u != nil && strings.HasPrefix(u.String(), kops.DefaultChannelBase) {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("channel"), "air-gapped clusters must use a channel at a local location, such as the channel of the bundle"))
+	}
+
+	return allErrs
+}
+
+func validateEgressProxy(spec *kops.EgressProxySpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	host := spec.HTTPProxy.Host
+	if host == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("httpProxy", "host"), "the host of the proxy must be set"))
+	} else {
+		if strings.ContainsAny(host, " \t\n") {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("httpProxy", "host"), host, "host must not contain whitespace"))
+		} else if strings.Contains(host, "://") && !strings.HasPrefix(host, "http://") && !strings.HasPrefix(host, "https://") {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("httpProxy", "host"), host, "only the http and https schemes are supported"))
+		}
+	}
+
+	if spec.HTTPProxy.Port < 0 || spec.HTTPProxy.Port > 65535 {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("httpProxy", "port"), spec.HTTPProxy.Port, "port must be between 0 and 65535"))
+	}
+
+	if spec.ProxyExcludes != "" {
+		for _, exclude := range strings.Split(spec.ProxyExcludes, ",") {
+			if strings.ContainsAny(strings.TrimSpace(exclude), " \t\n") {
+				allErrs = append(allErrs, field.Invalid(fieldPath.Child("excludes"), spec.ProxyExcludes, "excludes must be a comma separated list without whitespace within its entries"))
+				break
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateInstanceGroupScaling(spec *kops.InstanceGroupScalingSpec, c *kops.Cluster, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.Enabled != nil && *spec.Enabled {
+		if kops.CloudProviderID(c.Spec.CloudProvider) != kops.CloudProviderAWS {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("enabled"), "instanceGroupScaling is only supported on AWS"))
+		} else if !model.UseKopsControllerForNodeBootstrap(c) {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("enabled"), "instanceGroupScaling requires nodes to bootstrap through kops-controller (Kubernetes 1.19 or later)"))
+		} else if strings.HasPrefix(c.Spec.ConfigBase, "s3://") && spec.StateStoreLockTable == "" {
+			// kops-controller refuses to change instance groups without the lock
+			allErrs = append(allErrs, field.Required(fieldPath.Child("stateStoreLockTable"), "instanceGroupScaling requires a DynamoDB table to lock the S3 state store"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateTerraform(terraform *kops.TerraformSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for provider, version := range terraform.ProviderVersions {
+		if version == "" {
+			allErrs = append(allErrs, field.Required(fieldPath.Child("providerVersions").Key(provider), ""))
+		}
+	}
+
+	if backend := terraform.Backend; backend != nil {
+		fieldBackend := fieldPath.Child("backend")
+		switch {
+		case backend.S3 != nil && backend.GCS != nil:
+			allErrs = append(allErrs, field.Forbidden(fieldBackend.Child("gcs"), "only one backend may be set"))
+		case backend.S3 != nil:
+			if backend.S3.Bucket == "" {
+				allErrs = append(allErrs, field.Required(fieldBackend.Child("s3", "bucket"), ""))
+			}
+		case backend.GCS != nil:
+			if backend.GCS.Bucket == "" {
+				allErrs = append(allErrs, field.Required(fieldBackend.Child("gcs", "bucket"), ""))
+			}
+		default:
+			allErrs = append(allErrs, field.Required(fieldBackend, "a backend must be set"))
+		}
+	}
+
+	return allErrs
+}
+
 func validateTopology(topology *kops.TopologySpec, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -375,6 +919,25 @@ func validateTopology(topology *kops.TopologySpec, fieldPath *field.Path) field.
 	return allErrs
 }
 
+func validateInstanceAccess(spec *kops.ClusterSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	access := spec.Topology.InstanceAccess
+	value := string(access.Type)
+	allErrs = append(allErrs, IsValidValue(fieldPath.Child("instanceAccess", "type"), &value, kops.SupportedInstanceAccessTypes)...)
+
+	if access.Type == kops.InstanceAccessTypeSSM || access.Type == kops.InstanceAccessTypeEC2InstanceConnect {
+		if kops.CloudProviderID(spec.CloudProvider) != kops.CloudProviderAWS {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("instanceAccess", "type"), fmt.Sprintf("%s instance access is only supported on AWS", access.Type)))
+		}
+		if spec.Topology.Bastion != nil {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("bastion"), fmt.Sprintf("a bastion cannot be used with %s instance access", access.Type)))
+		}
+	}
+
+	return allErrs
+}
+
 func validateSubnets(cluster *kops.ClusterSpec, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -453,6 +1016,60 @@ func validateSubnet(subnet *kops.ClusterSubnetSpec, fieldPath *field.Path) field
 	return allErrs
 }
 
+// requiredAddons are the addons that nodes need to join the cluster, which can't be disabled
+var requiredAddons = sets.NewString("core.addons.k8s.io", "kops-controller.addons.k8s.io")
+
+// validateAddons checks that each addon either adds a channel, or disables or pins an addon by name
+func validateAddons(addons []kops.AddonSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := sets.NewString()
+	for i := range addons {
+		addon := &addons[i]
+		fldPath := fieldPath.Index(i)
+
+		if addon.Manifest == "" && addon.Name == "" {
+			allErrs = append(allErrs, field.Required(fldPath, "either manifest or name must be set"))
+			continue
+		}
+		if addon.Manifest != "" {
+			if addon.Name != "" {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Child("name"), "name may not be used with manifest"))
+			}
+			if addon.Disabled || addon.Version != "" || addon.ManifestHash != "" {
+				allErrs = append(allErrs, field.Forbidden(fldPath, "disabled, version and manifestHash only apply to an addon selected by name"))
+			}
+			continue
+		}
+
+		if names.Has(addon.Name) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("name"), addon.Name))
+		}
+		names.Insert(addon.Name)
+
+		if addon.Priority != 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("priority"), "priority only applies to a channel set in manifest"))
+		}
+		if addon.Disabled {
+			if addon.Version != "" || addon.ManifestHash != "" {
+				allErrs = append(allErrs, field.Forbidden(fldPath, "a disabled addon may not be pinned to a version or manifestHash"))
+			}
+			if requiredAddons.Has(addon.Name) {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Child("disabled"), fmt.Sprintf("addon %q is required by kOps and can't be disabled", addon.Name)))
+			}
+		} else if addon.Version == "" && addon.ManifestHash == "" {
+			allErrs = append(allErrs, field.Required(fldPath, "an addon selected by name must be disabled, or pinned to a version or manifestHash"))
+		}
+		if addon.Version != "" {
+			if _, err := semver.ParseTolerant(addon.Version); err != nil {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("version"), addon.Version, fmt.Sprintf("unable to parse version: %v", err)))
+			}
+		}
+	}
+
+	return allErrs
+}
+
 // validateFileAssetSpec is responsible for checking a FileAssetSpec is ok
 func validateFileAssetSpec(v *kops.FileAssetSpec, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -627,7 +1244,92 @@ func validateKubelet(k *kops.KubeletConfigSpec, c *kops.Cluster, kubeletPath *fi
 			}
 		}
 
+		if k.MemoryQoS != nil && *k.MemoryQoS && !c.IsKubernetesGTE("1.22") {
+			allErrs = append(allErrs, field.Forbidden(kubeletPath.Child("memoryQoS"), "memoryQoS requires at least Kubernetes 1.22"))
+		}
+
+		allErrs = append(allErrs, validateKubeletConfigFile(k, c, kubeletPath)...)
+	}
+	return allErrs
+}
+
+// validateKubeletConfigFile checks the settings that nodeup writes to the kubelet configuration file,
+// including that the KubeletConfiguration of the cluster's Kubernetes version has them.
+func validateKubeletConfigFile(k *kops.KubeletConfigSpec, c *kops.Cluster, kubeletPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for _, f := range []struct {
+		name       string
+		set        bool
+		minVersion string
+	}{
+		{"memorySwapBehavior", k.MemorySwapBehavior != "", "1.22"},
+		{"memoryManagerPolicy", k.MemoryManagerPolicy != "", "1.22"},
+		{"reservedMemory", len(k.ReservedMemory) != 0, "1.22"},
+		{"shutdownGracePeriod", k.ShutdownGracePeriod != nil, "1.21"},
+		{"shutdownGracePeriodCriticalPods", k.ShutdownGracePeriodCriticalPods != nil, "1.21"},
+		{"shutdownGracePeriodByPodPriority", len(k.ShutdownGracePeriodByPodPriority) != 0, "1.23"},
+	} {
+		if f.set && !c.IsKubernetesGTE(f.minVersion) {
+			allErrs = append(allErrs, field.Forbidden(kubeletPath.Child(f.name), fmt.Sprintf("%s requires at least Kubernetes %s", f.name, f.minVersion)))
+		}
+	}
+
+	if k.MemorySwapBehavior != "" {
+		allErrs = append(allErrs, IsValidValue(kubeletPath.Child("memorySwapBehavior"), &k.MemorySwapBehavior, []string{"LimitedSwap", "UnlimitedSwap"})...)
+	}
+
+	if k.MemoryManagerPolicy != "" {
+		allErrs = append(allErrs, IsValidValue(kubeletPath.Child("memoryManagerPolicy"), &k.MemoryManagerPolicy, []string{"None", "Static"})...)
+	}
+	if k.MemoryManagerPolicy == "Static" && len(k.ReservedMemory) == 0 {
+		allErrs = append(allErrs, field.Required(kubeletPath.Child("reservedMemory"), "the Static memory manager policy requires reservedMemory"))
+	}
+	if k.MemoryManagerPolicy != "Static" && len(k.ReservedMemory) != 0 {
+		allErrs = append(allErrs, field.Forbidden(kubeletPath.Child("reservedMemory"), "reservedMemory requires the Static memory manager policy"))
+	}
+	numaNodes := sets.NewInt32()
+	for i, reserved := range k.ReservedMemory {
+		fldPath := kubeletPath.Child("reservedMemory").Index(i)
+		if reserved.NUMANode < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("numaNode"), reserved.NUMANode, "numaNode must not be negative"))
+		}
+		if numaNodes.Has(reserved.NUMANode) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("numaNode"), reserved.NUMANode))
+		}
+		numaNodes.Insert(reserved.NUMANode)
+		if len(reserved.Limits) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("limits"), "limits must reserve memory"))
+		}
+		for name := range reserved.Limits {
+			if name != "memory" && !strings.HasPrefix(name, "hugepages-") {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("limits").Key(name), name, "only memory and hugepages can be reserved"))
+			}
+		}
+	}
+
+	if k.ShutdownGracePeriodCriticalPods != nil {
+		if k.ShutdownGracePeriod == nil {
+			allErrs = append(allErrs, field.Required(kubeletPath.Child("shutdownGracePeriod"), "shutdownGracePeriodCriticalPods requires shutdownGracePeriod"))
+		} else if k.ShutdownGracePeriodCriticalPods.Duration > k.ShutdownGracePeriod.Duration {
+			allErrs = append(allErrs, field.Invalid(kubeletPath.Child("shutdownGracePeriodCriticalPods"), k.ShutdownGracePeriodCriticalPods.Duration.String(), "shutdownGracePeriodCriticalPods must not be longer than shutdownGracePeriod"))
+		}
+	}
+	if len(k.ShutdownGracePeriodByPodPriority) != 0 && (k.ShutdownGracePeriod != nil || k.ShutdownGracePeriodCriticalPods != nil) {
+		allErrs = append(allErrs, field.Forbidden(kubeletPath.Child("shutdownGracePeriodByPodPriority"), "shutdownGracePeriodByPodPriority cannot be combined with shutdownGracePeriod or shutdownGracePeriodCriticalPods"))
+	}
+	priorities := sets.NewInt32()
+	for i, period := range k.ShutdownGracePeriodByPodPriority {
+		fldPath := kubeletPath.Child("shutdownGracePeriodByPodPriority").Index(i)
+		if priorities.Has(period.Priority) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("priority"), period.Priority))
+		}
+		priorities.Insert(period.Priority)
+		if period.ShutdownGracePeriodSeconds < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("shutdownGracePeriodSeconds"), period.ShutdownGracePeriodSeconds, "shutdownGracePeriodSeconds must not be negative"))
+		}
 	}
+
 	return allErrs
 }
 
@@ -682,6 +1384,9 @@ func validateNetworking(cluster *kops.Cluster, v *kops.NetworkingSpec, fldPath *
 		if optionTaken {
 			allErrs = append(allErrs, field.Forbidden(fldPath.Child("calico"), "only one networking option permitted"))
 		}
+		if v.Calico.BPFEnabled && c.KubeProxy != nil && (c.KubeProxy.Enabled == nil || *c.KubeProxy.Enabled) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Root().Child("spec", "kubeProxy", "enabled"), "When Calico BPF is enabled, kubeProxy must be disabled"))
+		}
 		optionTaken = true
 	}
 
@@ -744,9 +1449,33 @@ func validateNetworking(cluster *kops.Cluster, v *kops.NetworkingSpec, fldPath *
 			allErrs = append(allErrs, field.Forbidden(fldPath.Child("gce"), "only one networking option permitted"))
 		}
 
+		optionTaken = true
+
 		allErrs = append(allErrs, validateNetworkingGCE(c, v.GCE, fldPath.Child("gce"))...)
 	}
 
+	if v.Antrea != nil {
+		if optionTaken {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("antrea"), "only one networking option permitted"))
+		}
+
+		allErrs = append(allErrs, validateNetworkingAntrea(v.Antrea, fldPath.Child("antrea"))...)
+	}
+
+	return allErrs
+}
+
+func validateNetworkingAntrea(v *kops.AntreaNetworkingSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if v.TunnelType != "" {
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("tunnelType"), &v.TunnelType, []string{"geneve", "vxlan", "gre", "stt"})...)
+	}
+
+	if v.MTU != nil && *v.MTU <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("mtu"), *v.MTU, "must be greater than 0"))
+	}
+
 	return allErrs
 }
 
@@ -941,6 +1670,14 @@ func validateEtcdClusterSpec(spec kops.EtcdClusterSpec, c *kops.Cluster, fieldPa
 			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("provider"), "support for Legacy mode removed as of Kubernetes 1.18"))
 		}
 	}
+	allErrs = append(allErrs, validateEtcdTuning(spec, fieldPath)...)
+	if spec.IsExternal() {
+		allErrs = append(allErrs, validateEtcdExternal(spec, fieldPath)...)
+		return allErrs
+	}
+	if spec.External != nil {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("external"), "external can only be set with the External provider"))
+	}
 	if len(spec.Members) == 0 {
 		allErrs = append(allErrs, field.Required(fieldPath.Child("etcdMembers"), "No members defined in etcd cluster"))
 	} else if (len(spec.Members) % 2) == 0 {
@@ -955,6 +1692,132 @@ func validateEtcdClusterSpec(spec kops.EtcdClusterSpec, c *kops.Cluster, fieldPa
 	return allErrs
 }
 
+// validateEtcdTuning checks the storage quota, compaction, defragmentation and metrics settings of an etcd cluster
+func validateEtcdTuning(spec kops.EtcdClusterSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.QuotaBackendBytes == nil && spec.AutoCompaction == nil && spec.Defragmentation == nil && spec.Metrics == nil {
+		return allErrs
+	}
+	if (spec.Provider != "" && spec.Provider != kops.EtcdProviderTypeManager) || spec.IsExternal() {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "quotaBackendBytes, autoCompaction, defragmentation and metrics are only supported with the Manager provider"))
+		return allErrs
+	}
+
+	if spec.QuotaBackendBytes != nil && spec.QuotaBackendBytes.Sign() <= 0 {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("quotaBackendBytes"), spec.QuotaBackendBytes.String(), "must be greater than 0"))
+	}
+
+	if spec.AutoCompaction != nil {
+		compactionPath := fieldPath.Child("autoCompaction")
+		mode := spec.AutoCompaction.Mode
+		if mode == "" {
+			mode = "periodic"
+		}
+		allErrs = append(allErrs, IsValidValue(compactionPath.Child("mode"), &mode, []string{"periodic", "revision"})...)
+
+		retention := spec.AutoCompaction.Retention
+		if retention == "" {
+			allErrs = append(allErrs, field.Required(compactionPath.Child("retention"), "the compaction retention must be set"))
+		} else if mode == "revision" {
+			if n, err := strconv.ParseInt(retention, 10, 64); err != nil || n <= 0 {
+				allErrs = append(allErrs, field.Invalid(compactionPath.Child("retention"), retention, "must be a positive number of revisions for revision compaction"))
+			}
+		} else if mode == "periodic" {
+			// etcd reads a bare number as a number of hours
+			if n, err := strconv.ParseInt(retention, 10, 64); err == nil {
+				if n <= 0 {
+					allErrs = append(allErrs, field.Invalid(compactionPath.Child("retention"), retention, "must be greater than 0"))
+				}
+			} else if d, err := time.ParseDuration(retention); err != nil || d <= 0 {
+				allErrs = append(allErrs, field.Invalid(compactionPath.Child("retention"), retention, "must be a duration, such as 8h, for periodic compaction"))
+			}
+		}
+	}
+
+	if spec.Defragmentation != nil {
+		if spec.Defragmentation.Interval == nil {
+			allErrs = append(allErrs, field.Required(fieldPath.Child("defragmentation", "interval"), "the defragmentation interval must be set"))
+		} else if spec.Defragmentation.Interval.Duration < time.Hour {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("defragmentation", "interval"), spec.Defragmentation.Interval.Duration.String(), "must be at least 1h, as defragmentation blocks writes to the member"))
+		}
+	}
+
+	if spec.Metrics != nil {
+		metricsPath := fieldPath.Child("metrics")
+		if spec.Metrics.Type != "" {
+			allErrs = append(allErrs, IsValidValue(metricsPath.Child("type"), &spec.Metrics.Type, []string{"basic", "extensive"})...)
+		}
+		if spec.Metrics.Port != nil {
+			port := int(*spec.Metrics.Port)
+			if port < 1 || port > 65535 {
+				allErrs = append(allErrs, field.Invalid(metricsPath.Child("port"), port, "must be a valid port number"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validateEtcdExternal checks the configuration of an etcd cluster that is not managed by kOps
+func validateEtcdExternal(spec kops.EtcdClusterSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.Name != "main" && spec.Name != "events" {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("name"), "only the main and events etcd clusters can be external"))
+	}
+	if len(spec.Members) != 0 {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("etcdMembers"), "members cannot be set on an external etcd cluster"))
+	}
+	if spec.Manager != nil {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("manager"), "manager cannot be set on an external etcd cluster"))
+	}
+	if spec.Backups != nil {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("backups"), "backups of an external etcd cluster are not managed by kOps"))
+	}
+
+	if spec.External == nil {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("external"), "the endpoints of the external etcd cluster must be set"))
+		return allErrs
+	}
+
+	endpointsPath := fieldPath.Child("external", "endpoints")
+	if len(spec.External.Endpoints) == 0 {
+		allErrs = append(allErrs, field.Required(endpointsPath, "the endpoints of the external etcd cluster must be set"))
+	}
+	schemes := sets.NewString()
+	for i, endpoint := range spec.External.Endpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			allErrs = append(allErrs, field.Invalid(endpointsPath.Index(i), endpoint, "endpoint must be an http or https URL, such as https://etcd-a.example.com:2379"))
+			continue
+		}
+		schemes.Insert(u.Scheme)
+	}
+	if schemes.Len() > 1 {
+		allErrs = append(allErrs, field.Forbidden(endpointsPath, "endpoints must either all use https or all use http"))
+	}
+
+	return allErrs
+}
+
+// validateEtcdExternalClusters checks that either all or none of the etcd clusters are external.
+// The API server has a single etcd CA and client certificate, so it cannot mix clusters managed by kOps and external ones.
+func validateEtcdExternalClusters(specs []kops.EtcdClusterSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	external := 0
+	for _, x := range specs {
+		if x.IsExternal() {
+			external++
+		}
+	}
+	if external > 0 && external != len(specs) {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "either all or none of the etcd clusters must be external"))
+	}
+
+	return allErrs
+}
+
 // validateEtcdBackupStore checks that the etcd clusters backupStore path is unique.
 func validateEtcdBackupStore(specs []kops.EtcdClusterSpec, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -1244,6 +2107,87 @@ func validateContainerdConfig(config *kops.ContainerdConfig, fldPath *field.Path
 	return allErrs
 }
 
+// validateContainerdSettings checks the structured containerd settings, which can be set on both the cluster and the instance groups
+func validateContainerdSettings(config *kops.ContainerdConfig, c *kops.Cluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	// The containerd version is only set on the cluster; the default version of kOps supports neither registries nor NRI
+	var version *semver.Version
+	if c.Spec.Containerd != nil && c.Spec.Containerd.Version != nil {
+		if sv, err := semver.ParseTolerant(*c.Spec.Containerd.Version); err == nil {
+			version = &sv
+		}
+	}
+
+	if len(config.Registries) > 0 && (version == nil || version.LT(semver.MustParse("1.5.0"))) {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("registries"), "registries require at least containerd 1.5"))
+	}
+	for _, host := range sets.StringKeySet(config.Registries).List() {
+		registry := config.Registries[host]
+		registryPath := fldPath.Child("registries").Key(host)
+
+		if host == "" || strings.Contains(host, "/") {
+			allErrs = append(allErrs, field.Invalid(registryPath, host, "registry must be a host name, e.g. docker.io"))
+		}
+		for i, mirror := range registry.Mirrors {
+			u, err := url.Parse(mirror)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				allErrs = append(allErrs, field.Invalid(registryPath.Child("mirrors").Index(i), mirror, "mirror must be an http or https URL"))
+			}
+		}
+		if (registry.Username == nil) != (registry.Password == nil) {
+			allErrs = append(allErrs, field.Required(registryPath.Child("password"), "username and password must be set together"))
+		}
+		if registry.CA != nil && !x509.NewCertPool().AppendCertsFromPEM([]byte(*registry.CA)) {
+			allErrs = append(allErrs, field.Invalid(registryPath.Child("ca"), "...", "ca must be a PEM encoded certificate"))
+		}
+	}
+
+	for _, name := range sets.StringKeySet(config.Runtimes).List() {
+		runtime := config.Runtimes[name]
+		runtimePath := fldPath.Child("runtimes").Key(name)
+
+		for _, msg := range utilvalidation.IsDNS1123Label(name) {
+			allErrs = append(allErrs, field.Invalid(runtimePath, name, msg))
+		}
+		if name == "runc" {
+			allErrs = append(allErrs, field.Forbidden(runtimePath, "the runc runtime is configured by kOps"))
+		}
+		if runtime.Type == "" {
+			allErrs = append(allErrs, field.Required(runtimePath.Child("type"), "runtime type must be set"))
+		}
+	}
+
+	if config.SandboxImage != nil && *config.SandboxImage == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("sandboxImage"), "sandboxImage must not be empty"))
+	}
+
+	if config.NRI != nil && fi.BoolValue(config.NRI.Enabled) && (version == nil || version.LT(semver.MustParse("1.7.0"))) {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("nri", "enabled"), "NRI requires at least containerd 1.7"))
+	}
+
+	return allErrs
+}
+
+func validateNvidiaGPU(config *kops.NvidiaGPUConfig, c *kops.Cluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if config.DriverPackage != nil && *config.DriverPackage == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("driverPackage"), "driverPackage must not be empty"))
+	}
+
+	if config.IsEnabled() {
+		if c.Spec.ContainerRuntime != "containerd" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("enabled"), "NVIDIA GPUs require the containerd container runtime"))
+		}
+		if !c.IsKubernetesGTE("1.20") {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("enabled"), "NVIDIA GPUs require Kubernetes 1.20 or later"))
+		}
+	}
+
+	return allErrs
+}
+
 func validateDockerConfig(config *kops.DockerConfig, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -1344,6 +2288,117 @@ func validateRollingUpdate(rollingUpdate *kops.RollingUpdate, fldpath *field.Pat
 			allErrs = append(allErrs, field.Forbidden(fldpath.Child("maxSurge"), "Cannot be zero if maxUnavailable is zero"))
 		}
 	}
+	if rollingUpdate.DrainTimeout != nil && rollingUpdate.DrainTimeout.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldpath.Child("drainTimeout"), rollingUpdate.DrainTimeout.Duration.String(), "Cannot be negative"))
+	}
+	return allErrs
+}
+
+func validateGatewayAccess(spec *kops.AccessSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	gatewayPath := fldPath.Child("gateway")
+
+	if spec.DNS != nil || spec.LoadBalancer != nil {
+		allErrs = append(allErrs, field.Forbidden(gatewayPath, "gateway cannot be combined with dns or loadBalancer"))
+	}
+
+	if spec.Gateway.Name == "" {
+		allErrs = append(allErrs, field.Required(gatewayPath.Child("name"), "the name of the gateway must be specified"))
+	} else {
+		for _, msg := range validation.NameIsDNSSubdomain(spec.Gateway.Name, false) {
+			allErrs = append(allErrs, field.Invalid(gatewayPath.Child("name"), spec.Gateway.Name, msg))
+		}
+	}
+
+	if spec.Gateway.Namespace != "" {
+		for _, msg := range validation.ValidateNamespaceName(spec.Gateway.Namespace, false) {
+			allErrs = append(allErrs, field.Invalid(gatewayPath.Child("namespace"), spec.Gateway.Namespace, msg))
+		}
+	}
+
+	if spec.Gateway.SectionName != "" {
+		for _, msg := range utilvalidation.IsDNS1123Subdomain(spec.Gateway.SectionName) {
+			allErrs = append(allErrs, field.Invalid(gatewayPath.Child("sectionName"), spec.Gateway.SectionName, msg))
+		}
+	}
+
+	return allErrs
+}
+
+func validateInternalZone(c *kops.Cluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	switch kops.CloudProviderID(c.Spec.CloudProvider) {
+	case kops.CloudProviderAWS, kops.CloudProviderGCE:
+	default:
+		allErrs = append(allErrs, field.Forbidden(fldPath, "split-horizon DNS is only supported on AWS and GCE"))
+	}
+
+	if dns.IsGossipHostname(c.ObjectMeta.Name) {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "gossip clusters do not publish the API in DNS zones"))
+	}
+
+	return allErrs
+}
+
+func validateKopsControllerGossip(c *kops.Cluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if !dns.IsGossipHostname(c.ObjectMeta.Name) {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("protocol"), "the kops-controller protocol requires a gossip cluster"))
+	}
+
+	if !model.UseKopsControllerForNodeBootstrap(c) {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("protocol"), "the kops-controller protocol requires nodes to bootstrap through kops-controller, which is supported on AWS, and on GCE with nodeBootstrap.useKopsController"))
+	}
+
+	if c.Spec.GossipConfig.Secondary != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("secondary"), "the kops-controller protocol cannot be combined with a secondary protocol"))
+	}
+
+	if c.Spec.DNSControllerGossipConfig != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "dnsControllerGossipConfig"), "dns-controller is configured by gossipConfig when using the kops-controller protocol"))
+	}
+
+	return allErrs
+}
+
+func validateExternalDNS(c *kops.Cluster, spec *kops.ExternalDNSConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.Provider != "" {
+		value := string(spec.Provider)
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("provider"), &value, []string{string(kops.ExternalDNSProviderDNSController), string(kops.ExternalDNSProviderExternalDNS)})...)
+	}
+
+	if spec.Provider != kops.ExternalDNSProviderExternalDNS {
+		return allErrs
+	}
+
+	switch kops.CloudProviderID(c.Spec.CloudProvider) {
+	case kops.CloudProviderAWS, kops.CloudProviderGCE, kops.CloudProviderAzure:
+	default:
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("provider"), "external-dns is only supported on AWS, GCE and Azure"))
+	}
+
+	if fi.BoolValue(spec.WatchIngress) {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("watchIngress"), "ingresses are always watched by external-dns, watchIngress only applies to dns-controller"))
+	}
+
+	if spec.Policy != "" {
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("policy"), &spec.Policy, []string{"sync", "upsert-only", "create-only"})...)
+	}
+
+	for i, domain := range spec.DomainFilters {
+		for _, msg := range utilvalidation.IsDNS1123Subdomain(strings.TrimSuffix(domain, ".")) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("domainFilters").Index(i), domain, msg))
+		}
+	}
+
+	if kops.CloudProviderID(c.Spec.CloudProvider) == kops.CloudProviderAWS && dns.IsGossipHostname(c.ObjectMeta.Name) && len(spec.ZoneIDFilters) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("zoneIDFilters"), "gossip clusters have no hosted zone, so the zones managed by external-dns must be specified"))
+	}
+
 	return allErrs
 }
 
@@ -1358,7 +2413,29 @@ func validateNodeLocalDNS(spec *kops.ClusterSpec, fldpath *field.Path) field.Err
 		}
 	}
 
-	if (spec.KubeProxy != nil && spec.KubeProxy.ProxyMode == "ipvs") || (spec.Networking != nil && spec.Networking.Cilium != nil) {
+	if spec.KubeDNS.NodeLocalDNS.PrometheusMetricsPort < 0 || spec.KubeDNS.NodeLocalDNS.PrometheusMetricsPort > 65535 {
+		allErrs = append(allErrs, field.Invalid(fldpath.Child("kubeDNS", "nodeLocalDNS", "prometheusMetricsPort"), spec.KubeDNS.NodeLocalDNS.PrometheusMetricsPort, "must be between 1 and 65535"))
+	}
+
+	for _, zone := range sets.StringKeySet(spec.KubeDNS.NodeLocalDNS.UpstreamZones).List() {
+		zonePath := fldpath.Child("kubeDNS", "nodeLocalDNS", "upstreamZones").Key(zone)
+		if zone == "" {
+			allErrs = append(allErrs, field.Invalid(zonePath, zone, "zone must not be empty"))
+		}
+		servers := spec.KubeDNS.NodeLocalDNS.UpstreamZones[zone]
+		if len(servers) == 0 {
+			allErrs = append(allErrs, field.Required(zonePath, "at least one nameserver must be specified"))
+		}
+		for i, server := range servers {
+			if !isValidNameserver(server) {
+				allErrs = append(allErrs, field.Invalid(zonePath.Index(i), server, "must be an IP address, optionally followed by a port"))
+			}
+		}
+	}
+
+	// Without iptables interception, pods only reach node-local-dns when the kubelet points them at it
+	ciliumOrCalicoBPF := spec.Networking != nil && (spec.Networking.Cilium != nil || (spec.Networking.Calico != nil && spec.Networking.Calico.BPFEnabled))
+	if (spec.KubeProxy != nil && spec.KubeProxy.ProxyMode == "ipvs") || ciliumOrCalicoBPF {
 		if spec.Kubelet != nil && spec.Kubelet.ClusterDNS != "" && spec.Kubelet.ClusterDNS != spec.KubeDNS.NodeLocalDNS.LocalIP {
 			allErrs = append(allErrs, field.Forbidden(fldpath.Child("kubelet", "clusterDNS"), "Kubelet ClusterDNS must be set to the default IP address for LocalIP"))
 		}
@@ -1371,13 +2448,98 @@ func validateNodeLocalDNS(spec *kops.ClusterSpec, fldpath *field.Path) field.Err
 	return allErrs
 }
 
+func validateCoreDNS(spec *kops.KubeDNSConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(spec.ServerBlocks) > 0 || len(spec.Rewrites) > 0 {
+		if spec.Provider == "KubeDNS" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("provider"), "serverBlocks and rewrites require the CoreDNS provider"))
+		}
+		if spec.ExternalCoreFile != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("externalCoreFile"), "externalCoreFile cannot be combined with serverBlocks or rewrites"))
+		}
+	}
+
+	for i, block := range spec.ServerBlocks {
+		blockPath := fldPath.Child("serverBlocks").Index(i)
+		if len(block.Zones) == 0 {
+			allErrs = append(allErrs, field.Required(blockPath.Child("zones"), "at least one zone must be specified"))
+		}
+		for j, zone := range block.Zones {
+			if zone == "" || zone == "." {
+				allErrs = append(allErrs, field.Invalid(blockPath.Child("zones").Index(j), zone, "zone must not be empty or the root zone"))
+			}
+		}
+		if len(block.Hosts) == 0 && len(block.Forward) == 0 {
+			allErrs = append(allErrs, field.Required(blockPath, "hosts or forward must be specified"))
+		}
+		for _, ip := range sets.StringKeySet(block.Hosts).List() {
+			if net.ParseIP(ip) == nil {
+				allErrs = append(allErrs, field.Invalid(blockPath.Child("hosts").Key(ip), ip, "must be an IP address"))
+			}
+			if len(block.Hosts[ip]) == 0 {
+				allErrs = append(allErrs, field.Required(blockPath.Child("hosts").Key(ip), "at least one host name must be specified"))
+			}
+		}
+		for j, server := range block.Forward {
+			if !isValidNameserver(server) {
+				allErrs = append(allErrs, field.Invalid(blockPath.Child("forward").Index(j), server, "must be an IP address, optionally followed by a port"))
+			}
+		}
+		if block.CacheTTL < 0 {
+			allErrs = append(allErrs, field.Invalid(blockPath.Child("cacheTTL"), block.CacheTTL, "must not be negative"))
+		}
+	}
+
+	for i, rule := range spec.Rewrites {
+		rulePath := fldPath.Child("rewrites").Index(i)
+		if rule.Match != "" {
+			allErrs = append(allErrs, IsValidValue(rulePath.Child("match"), &rule.Match, []string{"exact", "prefix", "suffix", "substring", "regex"})...)
+		}
+		if rule.From == "" {
+			allErrs = append(allErrs, field.Required(rulePath.Child("from"), ""))
+		} else if rule.Match == "regex" {
+			if _, err := regexp.Compile(rule.From); err != nil {
+				allErrs = append(allErrs, field.Invalid(rulePath.Child("from"), rule.From, fmt.Sprintf("invalid regular expression: %v", err)))
+			}
+		}
+		if rule.To == "" {
+			allErrs = append(allErrs, field.Required(rulePath.Child("to"), ""))
+		}
+	}
+
+	if spec.Autoscaler != nil {
+		if spec.Autoscaler.CoresPerReplica < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("autoscaler", "coresPerReplica"), spec.Autoscaler.CoresPerReplica, "must not be negative"))
+		}
+		if spec.Autoscaler.NodesPerReplica < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("autoscaler", "nodesPerReplica"), spec.Autoscaler.NodesPerReplica, "must not be negative"))
+		}
+	}
+
+	return allErrs
+}
+
+// isValidNameserver returns true if the nameserver is an IP address, optionally followed by a port
+func isValidNameserver(nameserver string) bool {
+	host := nameserver
+	if h, _, err := net.SplitHostPort(nameserver); err == nil {
+		host = h
+	}
+	return net.ParseIP(host) != nil
+}
+
 func validateClusterAutoscaler(cluster *kops.Cluster, spec *kops.ClusterAutoscalerConfig, fldPath *field.Path) (allErrs field.ErrorList) {
-	allErrs = append(allErrs, IsValidValue(fldPath.Child("expander"), spec.Expander, []string{"least-waste", "random", "most-pods"})...)
+	allErrs = append(allErrs, IsValidValue(fldPath.Child("expander"), spec.Expander, []string{"least-waste", "random", "most-pods", "priority"})...)
 
 	if kops.CloudProviderID(cluster.Spec.CloudProvider) == kops.CloudProviderOpenstack {
 		allErrs = append(allErrs, field.Forbidden(fldPath, "Cluster autoscaler is not supported on OpenStack"))
 	}
 
+	if fi.StringValue(spec.Expander) == "priority" && kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("expander"), "the priority expander is only supported on AWS"))
+	}
+
 	return allErrs
 }
 
@@ -1393,6 +2555,9 @@ func validateMetricsServer(cluster *kops.Cluster, spec *kops.MetricsServerConfig
 		if !fi.BoolValue(spec.Insecure) && !components.IsCertManagerEnabled(cluster) {
 			allErrs = append(allErrs, field.Forbidden(fldPath.Child("insecure"), "Secure metrics server requires that cert manager is enabled"))
 		}
+		if !fi.BoolValue(spec.Insecure) && !model.UseKopsControllerForNodeBootstrap(cluster) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("insecure"), "Secure metrics server requires nodes to bootstrap through kops-controller, so that the kubelet serving certificates are issued by the cluster CA"))
+		}
 	}
 
 	return allErrs
@@ -1446,3 +2611,57 @@ func validateSnapshotController(cluster *kops.Cluster, spec *kops.SnapshotContro
 	}
 	return allErrs
 }
+
+var (
+	kernelModuleRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	udevRuleNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_.-]+\.rules$`)
+)
+
+func validateNodeTuning(spec *kops.NodeTuningSpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	for _, key := range sortedKeys(spec.Sysctls) {
+		if key == "" || strings.ContainsAny(key, "= \t") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("sysctls"), key, "sysctl names must not be empty or contain '=' or whitespace"))
+		}
+	}
+
+	for i, module := range spec.KernelModules {
+		if !kernelModuleRegex.MatchString(module) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("kernelModules").Index(i), module, "kernel module names may only contain letters, digits, '_' and '-'"))
+		}
+	}
+
+	var sizes []string
+	for size := range spec.HugePages {
+		sizes = append(sizes, size)
+	}
+	sort.Strings(sizes)
+	for _, size := range sizes {
+		q, err := resource.ParseQuantity(size)
+		if err != nil || q.Value() <= 0 || q.Value()%1024 != 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("hugePages"), size, "huge page sizes must be positive multiples of 1Ki, e.g. 2Mi or 1Gi"))
+		}
+		if spec.HugePages[size] < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("hugePages").Key(size), spec.HugePages[size], "the number of huge pages cannot be negative"))
+		}
+	}
+
+	for _, name := range sortedKeys(spec.UdevRules) {
+		if !udevRuleNameRegex.MatchString(name) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("udevRules"), name, "udev rule file names must end with .rules and may only contain letters, digits, '_', '.' and '-'"))
+		}
+		if strings.TrimSpace(spec.UdevRules[name]) == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("udevRules").Key(name), "udev rules must not be empty"))
+		}
+	}
+
+	return allErrs
+}
+
+func sortedKeys(m map[string]string) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
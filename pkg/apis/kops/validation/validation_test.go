@@ -18,7 +18,10 @@ package validation
 
 import (
 	"testing"
+	"time"
 
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -382,6 +385,107 @@ func Test_Validate_Networking_Flannel(t *testing.T) {
 	}
 }
 
+func Test_Validate_Networking_Antrea(t *testing.T) {
+	grid := []struct {
+		Input          kops.NetworkingSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.NetworkingSpec{
+				Antrea: &kops.AntreaNetworkingSpec{},
+			},
+		},
+		{
+			Input: kops.NetworkingSpec{
+				Antrea: &kops.AntreaNetworkingSpec{
+					TunnelType: "vxlan",
+					MTU:        fi.Int32(8950),
+				},
+			},
+		},
+		{
+			Input: kops.NetworkingSpec{
+				Antrea: &kops.AntreaNetworkingSpec{
+					TunnelType: "ipip",
+				},
+			},
+			ExpectedErrors: []string{"Unsupported value::networking.antrea.tunnelType"},
+		},
+		{
+			Input: kops.NetworkingSpec{
+				Antrea: &kops.AntreaNetworkingSpec{
+					MTU: fi.Int32(0),
+				},
+			},
+			ExpectedErrors: []string{"Invalid value::networking.antrea.mtu"},
+		},
+		{
+			Input: kops.NetworkingSpec{
+				Antrea: &kops.AntreaNetworkingSpec{},
+				GCE:    &kops.GCENetworkingSpec{},
+			},
+			ExpectedErrors: []string{"Forbidden::networking.antrea"},
+		},
+	}
+	for _, g := range grid {
+		networking := g.Input
+
+		cluster := &kops.Cluster{}
+		cluster.Spec.Networking = &networking
+
+		errs := validateNetworking(cluster, &networking, field.NewPath("networking"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_Networking_Calico_KubeProxy(t *testing.T) {
+	grid := []struct {
+		Calico         kops.CalicoNetworkingSpec
+		KubeProxy      *kops.KubeProxyConfig
+		ExpectedErrors []string
+	}{
+		{
+			Calico:    kops.CalicoNetworkingSpec{},
+			KubeProxy: &kops.KubeProxyConfig{},
+		},
+		{
+			Calico: kops.CalicoNetworkingSpec{
+				BPFEnabled: true,
+			},
+			KubeProxy: &kops.KubeProxyConfig{
+				Enabled: fi.Bool(false),
+			},
+		},
+		{
+			Calico: kops.CalicoNetworkingSpec{
+				BPFEnabled: true,
+			},
+			KubeProxy:      &kops.KubeProxyConfig{},
+			ExpectedErrors: []string{"Forbidden::networking.spec.kubeProxy.enabled"},
+		},
+		{
+			Calico: kops.CalicoNetworkingSpec{
+				BPFEnabled: true,
+			},
+			KubeProxy: &kops.KubeProxyConfig{
+				Enabled: fi.Bool(true),
+			},
+			ExpectedErrors: []string{"Forbidden::networking.spec.kubeProxy.enabled"},
+		},
+	}
+	for _, g := range grid {
+		networking := &kops.NetworkingSpec{}
+		networking.Calico = &g.Calico
+
+		cluster := &kops.Cluster{}
+		cluster.Spec.Networking = networking
+		cluster.Spec.KubeProxy = g.KubeProxy
+
+		errs := validateNetworking(cluster, networking, field.NewPath("networking"))
+		testErrors(t, g.Calico, errs, g.ExpectedErrors)
+	}
+}
+
 func Test_Validate_AdditionalPolicies(t *testing.T) {
 	grid := []struct {
 		Input          map[string]string
@@ -947,6 +1051,17 @@ func Test_Validate_RollingUpdate(t *testing.T) {
 			},
 			ExpectedErrors: []string{"Invalid value::testField.maxSurge"},
 		},
+		{
+			Input: kops.RollingUpdate{
+				DrainTimeout: &metav1.Duration{Duration: 10 * time.Minute},
+			},
+		},
+		{
+			Input: kops.RollingUpdate{
+				DrainTimeout: &metav1.Duration{Duration: -time.Minute},
+			},
+			ExpectedErrors: []string{"Invalid value::testField.drainTimeout"},
+		},
 		{
 			Input: kops.RollingUpdate{
 				MaxSurge: intStr(intstr.FromInt(-1)),
@@ -1101,6 +1216,73 @@ func Test_Validate_NodeLocalDNS(t *testing.T) {
 			},
 			ExpectedErrors: []string{},
 		},
+		{
+			Input: kops.ClusterSpec{
+				Kubelet: &kops.KubeletConfigSpec{
+					ClusterDNS: "100.64.0.10",
+				},
+				KubeProxy: &kops.KubeProxyConfig{
+					ProxyMode: "iptables",
+				},
+				KubeDNS: &kops.KubeDNSConfig{
+					Provider: "CoreDNS",
+					NodeLocalDNS: &kops.NodeLocalDNSConfig{
+						Enabled: fi.Bool(true),
+						LocalIP: "169.254.20.10",
+					},
+				},
+				Networking: &kops.NetworkingSpec{
+					Calico: &kops.CalicoNetworkingSpec{
+						BPFEnabled: true,
+					},
+				},
+			},
+			ExpectedErrors: []string{"Forbidden::spec.kubelet.clusterDNS"},
+		},
+		{
+			Input: kops.ClusterSpec{
+				KubeDNS: &kops.KubeDNSConfig{
+					Provider: "CoreDNS",
+					NodeLocalDNS: &kops.NodeLocalDNSConfig{
+						Enabled:               fi.Bool(true),
+						PrometheusMetricsPort: 65536,
+					},
+				},
+			},
+			ExpectedErrors: []string{"Invalid value::spec.kubeDNS.nodeLocalDNS.prometheusMetricsPort"},
+		},
+		{
+			Input: kops.ClusterSpec{
+				KubeDNS: &kops.KubeDNSConfig{
+					Provider: "CoreDNS",
+					NodeLocalDNS: &kops.NodeLocalDNSConfig{
+						Enabled: fi.Bool(true),
+						UpstreamZones: map[string][]string{
+							"corp.example.com": {"10.0.0.2", "10.0.0.3:5353"},
+						},
+					},
+				},
+			},
+			ExpectedErrors: []string{},
+		},
+		{
+			Input: kops.ClusterSpec{
+				KubeDNS: &kops.KubeDNSConfig{
+					Provider: "CoreDNS",
+					NodeLocalDNS: &kops.NodeLocalDNSConfig{
+						Enabled: fi.Bool(true),
+						UpstreamZones: map[string][]string{
+							"corp.example.com": {"ns1.example.com"},
+							"example.org":      {},
+						},
+					},
+				},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::spec.kubeDNS.nodeLocalDNS.upstreamZones[corp.example.com][0]",
+				"Required value::spec.kubeDNS.nodeLocalDNS.upstreamZones[example.org]",
+			},
+		},
 	}
 
 	for _, g := range grid {
@@ -1109,141 +1291,1351 @@ func Test_Validate_NodeLocalDNS(t *testing.T) {
 	}
 }
 
-func Test_Validate_CloudConfiguration(t *testing.T) {
+func Test_Validate_Gateway(t *testing.T) {
 	grid := []struct {
-		Description    string
-		Input          kops.CloudConfiguration
+		Input          kops.AccessSpec
 		ExpectedErrors []string
 	}{
 		{
-			Description: "neither",
-			Input:       kops.CloudConfiguration{},
+			Input: kops.AccessSpec{
+				Gateway: &kops.GatewayAccessSpec{
+					Name:        "public",
+					Namespace:   "gateway-system",
+					SectionName: "tls-passthrough",
+				},
+			},
 		},
 		{
-			Description: "all false",
-			Input: kops.CloudConfiguration{
-				ManageStorageClasses: fi.Bool(false),
+			Input: kops.AccessSpec{
+				DNS:     &kops.DNSAccessSpec{},
+				Gateway: &kops.GatewayAccessSpec{Name: "public"},
 			},
+			ExpectedErrors: []string{"Forbidden::spec.api.gateway"},
 		},
 		{
-			Description: "all true",
-			Input: kops.CloudConfiguration{
-				ManageStorageClasses: fi.Bool(true),
+			Input: kops.AccessSpec{
+				Gateway: &kops.GatewayAccessSpec{},
 			},
+			ExpectedErrors: []string{"Required value::spec.api.gateway.name"},
 		},
 		{
-			Description: "os false",
-			Input: kops.CloudConfiguration{
-				Openstack: &kops.OpenstackConfiguration{
-					BlockStorage: &kops.OpenstackBlockStorageConfig{
-						CreateStorageClass: fi.Bool(false),
-					},
-				}},
+			Input: kops.AccessSpec{
+				Gateway: &kops.GatewayAccessSpec{
+					Name:        "Public",
+					Namespace:   "gateway.system",
+					SectionName: "TLS",
+				},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::spec.api.gateway.name",
+				"Invalid value::spec.api.gateway.namespace",
+				"Invalid value::spec.api.gateway.sectionName",
+			},
 		},
+	}
+
+	for _, g := range grid {
+		errs := validateGatewayAccess(&g.Input, field.NewPath("spec", "api"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_ExternalDNS(t *testing.T) {
+	grid := []struct {
+		ClusterName    string
+		CloudProvider  string
+		Input          kops.ExternalDNSConfig
+		ExpectedErrors []string
+	}{
 		{
-			Description: "os true",
-			Input: kops.CloudConfiguration{
-				Openstack: &kops.OpenstackConfiguration{
-					BlockStorage: &kops.OpenstackBlockStorageConfig{
-						CreateStorageClass: fi.Bool(true),
-					},
-				}},
+			ClusterName:   "example.com",
+			CloudProvider: "aws",
+			Input: kops.ExternalDNSConfig{
+				Provider:      kops.ExternalDNSProviderExternalDNS,
+				DomainFilters: []string{"apps.example.com."},
+				Policy:        "sync",
+			},
 		},
 		{
-			Description: "all false, os false",
-			Input: kops.CloudConfiguration{
-				ManageStorageClasses: fi.Bool(false),
-				Openstack: &kops.OpenstackConfiguration{
-					BlockStorage: &kops.OpenstackBlockStorageConfig{
-						CreateStorageClass: fi.Bool(false),
-					},
-				}},
+			ClusterName:   "example.com",
+			CloudProvider: "aws",
+			Input: kops.ExternalDNSConfig{
+				Provider:     kops.ExternalDNSProviderDNSController,
+				WatchIngress: fi.Bool(true),
+			},
 		},
 		{
-			Description: "all false, os true",
-			Input: kops.CloudConfiguration{
-				ManageStorageClasses: fi.Bool(false),
-				Openstack: &kops.OpenstackConfiguration{
-					BlockStorage: &kops.OpenstackBlockStorageConfig{
-						CreateStorageClass: fi.Bool(true),
-					},
-				}},
-			ExpectedErrors: []string{"Forbidden::cloudConfig.manageStorageClasses"},
+			ClusterName:   "example.com",
+			CloudProvider: "aws",
+			Input: kops.ExternalDNSConfig{
+				Provider: "route53",
+			},
+			ExpectedErrors: []string{"Unsupported value::spec.externalDns.provider"},
 		},
 		{
-			Description: "all true, os false",
-			Input: kops.CloudConfiguration{
-				ManageStorageClasses: fi.Bool(true),
-				Openstack: &kops.OpenstackConfiguration{
-					BlockStorage: &kops.OpenstackBlockStorageConfig{
-						CreateStorageClass: fi.Bool(false),
-					},
-				}},
-			ExpectedErrors: []string{"Forbidden::cloudConfig.manageStorageClasses"},
+			ClusterName:   "example.com",
+			CloudProvider: "openstack",
+			Input: kops.ExternalDNSConfig{
+				Provider: kops.ExternalDNSProviderExternalDNS,
+			},
+			ExpectedErrors: []string{"Forbidden::spec.externalDns.provider"},
 		},
 		{
-			Description: "all true, os true",
-			Input: kops.CloudConfiguration{
-				ManageStorageClasses: fi.Bool(true),
-				Openstack: &kops.OpenstackConfiguration{
-					BlockStorage: &kops.OpenstackBlockStorageConfig{
-						CreateStorageClass: fi.Bool(true),
-					},
-				}},
+			ClusterName:   "example.com",
+			CloudProvider: "gce",
+			Input: kops.ExternalDNSConfig{
+				Provider:      kops.ExternalDNSProviderExternalDNS,
+				WatchIngress:  fi.Bool(true),
+				DomainFilters: []string{"Apps_example"},
+				Policy:        "delete",
+			},
+			ExpectedErrors: []string{
+				"Forbidden::spec.externalDns.watchIngress",
+				"Unsupported value::spec.externalDns.policy",
+				"Invalid value::spec.externalDns.domainFilters[0]",
+			},
+		},
+		{
+			ClusterName:   "example.k8s.local",
+			CloudProvider: "aws",
+			Input: kops.ExternalDNSConfig{
+				Provider: kops.ExternalDNSProviderExternalDNS,
+			},
+			ExpectedErrors: []string{"Required value::spec.externalDns.zoneIDFilters"},
+		},
+		{
+			ClusterName:   "example.k8s.local",
+			CloudProvider: "aws",
+			Input: kops.ExternalDNSConfig{
+				Provider:      kops.ExternalDNSProviderExternalDNS,
+				ZoneIDFilters: []string{"Z1AFAKE1ZON3YO"},
+			},
 		},
 	}
 
 	for _, g := range grid {
-		fldPath := field.NewPath("cloudConfig")
-		t.Run(g.Description, func(t *testing.T) {
-			errs := validateCloudConfiguration(&g.Input, fldPath)
-			testErrors(t, g.Input, errs, g.ExpectedErrors)
-		})
+		cluster := &kops.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: g.ClusterName},
+			Spec:       kops.ClusterSpec{CloudProvider: g.CloudProvider},
+		}
+		errs := validateExternalDNS(cluster, &g.Input, field.NewPath("spec", "externalDns"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
 	}
 }
 
-func TestValidateSAExternalPermissions(t *testing.T) {
+func Test_Validate_InternalZone(t *testing.T) {
 	grid := []struct {
-		Description    string
-		Input          []kops.ServiceAccountExternalPermission
+		ClusterName    string
+		CloudProvider  string
 		ExpectedErrors []string
 	}{
-
 		{
-			Description: "Duplicate SA",
-			Input: []kops.ServiceAccountExternalPermission{
-				{
-					Name:      "MySA",
-					Namespace: "MyNS",
-					AWS: &kops.AWSPermission{
-						PolicyARNs: []string{"-"},
-					},
-				},
-				{
-					Name:      "MySA",
-					Namespace: "MyNS",
-					AWS: &kops.AWSPermission{
-						PolicyARNs: []string{"-"},
-					},
-				},
-			},
-			ExpectedErrors: []string{"Duplicate value::iam.serviceAccountExternalPermissions[MyNS/MySA]"},
+			ClusterName:   "example.com",
+			CloudProvider: "aws",
 		},
 		{
-			Description: "Missing permissions",
-			Input: []kops.ServiceAccountExternalPermission{
-				{
-					Name:      "MySA",
-					Namespace: "MyNS",
-				},
-			},
-			ExpectedErrors: []string{"Required value::iam.serviceAccountExternalPermissions[MyNS/MySA].aws"},
+			ClusterName:   "example.com",
+			CloudProvider: "gce",
 		},
 		{
-			Description: "Setting both arn and inline",
-			Input: []kops.ServiceAccountExternalPermission{
-				{
-					Name:      "MySA",
+			ClusterName:    "example.com",
+			CloudProvider:  "openstack",
+			ExpectedErrors: []string{"Forbidden::spec.api.dns.internalZoneID"},
+		},
+		{
+			ClusterName:    "example.k8s.local",
+			CloudProvider:  "aws",
+			ExpectedErrors: []string{"Forbidden::spec.api.dns.internalZoneID"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: g.ClusterName},
+			Spec:       kops.ClusterSpec{CloudProvider: g.CloudProvider},
+		}
+		errs := validateInternalZone(cluster, field.NewPath("spec", "api", "dns", "internalZoneID"))
+		testErrors(t, g, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_KopsControllerGossip(t *testing.T) {
+	grid := []struct {
+		ClusterName    string
+		CloudProvider  string
+		Secondary      bool
+		DNSController  bool
+		ExpectedErrors []string
+	}{
+		{
+			ClusterName:   "example.k8s.local",
+			CloudProvider: "aws",
+		},
+		{
+			ClusterName:    "example.com",
+			CloudProvider:  "aws",
+			ExpectedErrors: []string{"Forbidden::spec.gossipConfig.protocol"},
+		},
+		{
+			ClusterName:    "example.k8s.local",
+			CloudProvider:  "openstack",
+			ExpectedErrors: []string{"Forbidden::spec.gossipConfig.protocol"},
+		},
+		{
+			ClusterName:    "example.k8s.local",
+			CloudProvider:  "aws",
+			Secondary:      true,
+			ExpectedErrors: []string{"Forbidden::spec.gossipConfig.secondary"},
+		},
+		{
+			ClusterName:    "example.k8s.local",
+			CloudProvider:  "aws",
+			DNSController:  true,
+			ExpectedErrors: []string{"Forbidden::spec.dnsControllerGossipConfig"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: g.ClusterName},
+			Spec: kops.ClusterSpec{
+				CloudProvider:     g.CloudProvider,
+				KubernetesVersion: "1.21.0",
+				GossipConfig: &kops.GossipConfig{
+					Protocol: fi.String(kops.GossipProtocolKopsController),
+				},
+			},
+		}
+		if g.Secondary {
+			cluster.Spec.GossipConfig.Secondary = &kops.GossipConfigSecondary{Protocol: fi.String("memberlist")}
+		}
+		if g.DNSController {
+			cluster.Spec.DNSControllerGossipConfig = &kops.DNSControllerGossipConfig{Protocol: fi.String("mesh")}
+		}
+		errs := validateKopsControllerGossip(cluster, field.NewPath("spec", "gossipConfig"))
+		testErrors(t, g, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_CoreDNS(t *testing.T) {
+	grid := []struct {
+		Input          kops.KubeDNSConfig
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.KubeDNSConfig{
+				Provider: "CoreDNS",
+				ServerBlocks: []kops.CoreDNSServerBlock{
+					{
+						Zones:   []string{"corp.example.com"},
+						Forward: []string{"10.0.0.2", "10.0.0.3:5353"},
+					},
+					{
+						Zones: []string{"app.example.com"},
+						Hosts: map[string][]string{
+							"10.1.0.10": {"api.app.example.com"},
+						},
+					},
+				},
+				Rewrites: []kops.CoreDNSRewriteRule{
+					{From: "db.example.com", To: "db.default.svc.cluster.local"},
+					{Match: "regex", From: "(.*)\\.legacy\\.example\\.com", To: "{1}.default.svc.cluster.local"},
+				},
+				Autoscaler: &kops.DNSAutoscalerConfig{
+					CoresPerReplica: 128,
+					NodesPerReplica: 8,
+				},
+			},
+			ExpectedErrors: []string{},
+		},
+		{
+			Input: kops.KubeDNSConfig{
+				Provider: "KubeDNS",
+				Rewrites: []kops.CoreDNSRewriteRule{
+					{From: "db.example.com", To: "db.default.svc.cluster.local"},
+				},
+			},
+			ExpectedErrors: []string{"Forbidden::kubeDNS.provider"},
+		},
+		{
+			Input: kops.KubeDNSConfig{
+				ExternalCoreFile: ".:53 {}",
+				ServerBlocks: []kops.CoreDNSServerBlock{
+					{
+						Zones:   []string{"corp.example.com"},
+						Forward: []string{"10.0.0.2"},
+					},
+				},
+			},
+			ExpectedErrors: []string{"Forbidden::kubeDNS.externalCoreFile"},
+		},
+		{
+			Input: kops.KubeDNSConfig{
+				ServerBlocks: []kops.CoreDNSServerBlock{
+					{
+						Forward: []string{"ns1.example.com"},
+					},
+					{
+						Zones: []string{"."},
+						Hosts: map[string][]string{
+							"api": {"api.example.com"},
+						},
+						CacheTTL: -1,
+					},
+					{
+						Zones: []string{"example.org"},
+					},
+				},
+			},
+			ExpectedErrors: []string{
+				"Required value::kubeDNS.serverBlocks[0].zones",
+				"Invalid value::kubeDNS.serverBlocks[0].forward[0]",
+				"Invalid value::kubeDNS.serverBlocks[1].zones[0]",
+				"Invalid value::kubeDNS.serverBlocks[1].hosts[api]",
+				"Invalid value::kubeDNS.serverBlocks[1].cacheTTL",
+				"Required value::kubeDNS.serverBlocks[2]",
+			},
+		},
+		{
+			Input: kops.KubeDNSConfig{
+				Rewrites: []kops.CoreDNSRewriteRule{
+					{Match: "glob", From: "db.example.com", To: "db.default.svc.cluster.local"},
+					{Match: "regex", From: "(.*", To: "db.default.svc.cluster.local"},
+					{Match: "suffix"},
+				},
+				Autoscaler: &kops.DNSAutoscalerConfig{
+					NodesPerReplica: -1,
+				},
+			},
+			ExpectedErrors: []string{
+				"Unsupported value::kubeDNS.rewrites[0].match",
+				"Invalid value::kubeDNS.rewrites[1].from",
+				"Required value::kubeDNS.rewrites[2].from",
+				"Required value::kubeDNS.rewrites[2].to",
+				"Invalid value::kubeDNS.autoscaler.nodesPerReplica",
+			},
+		},
+	}
+
+	for _, g := range grid {
+		errs := validateCoreDNS(&g.Input, field.NewPath("kubeDNS"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_Terraform(t *testing.T) {
+	grid := []struct {
+		Input          kops.TerraformSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.TerraformSpec{
+				ProviderVersions: map[string]string{"aws": "3.50.0"},
+				Backend: &kops.TerraformBackendSpec{
+					S3: &kops.TerraformS3BackendSpec{
+						Bucket:        "tf-state",
+						DynamoDBTable: "tf-lock",
+					},
+				},
+			},
+		},
+		{
+			Input: kops.TerraformSpec{
+				ProviderVersions: map[string]string{"aws": ""},
+			},
+			ExpectedErrors: []string{"Required value::spec.target.terraform.providerVersions[aws]"},
+		},
+		{
+			Input: kops.TerraformSpec{
+				Backend: &kops.TerraformBackendSpec{},
+			},
+			ExpectedErrors: []string{"Required value::spec.target.terraform.backend"},
+		},
+		{
+			Input: kops.TerraformSpec{
+				Backend: &kops.TerraformBackendSpec{
+					GCS: &kops.TerraformGCSBackendSpec{},
+				},
+			},
+			ExpectedErrors: []string{"Required value::spec.target.terraform.backend.gcs.bucket"},
+		},
+		{
+			Input: kops.TerraformSpec{
+				Backend: &kops.TerraformBackendSpec{
+					S3:  &kops.TerraformS3BackendSpec{Bucket: "tf-state"},
+					GCS: &kops.TerraformGCSBackendSpec{Bucket: "tf-state"},
+				},
+			},
+			ExpectedErrors: []string{"Forbidden::spec.target.terraform.backend.gcs"},
+		},
+	}
+
+	for _, g := range grid {
+		errs := validateTerraform(&g.Input, field.NewPath("spec", "target", "terraform"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_EncryptionAtRest(t *testing.T) {
+	key := "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+	grid := []struct {
+		CloudProvider    string
+		EncryptionConfig *bool
+		Input            kops.EncryptionAtRestSpec
+		ExpectedErrors   []string
+	}{
+		{
+			CloudProvider: "aws",
+			Input: kops.EncryptionAtRestSpec{
+				KMSKey:          key,
+				PreviousKMSKeys: []string{"arn:aws:kms:us-east-1:123456789012:alias/previous"},
+			},
+		},
+		{
+			CloudProvider:  "aws",
+			Input:          kops.EncryptionAtRestSpec{},
+			ExpectedErrors: []string{"Required value::spec.encryptionAtRest.kmsKey"},
+		},
+		{
+			CloudProvider: "aws",
+			Input: kops.EncryptionAtRestSpec{
+				KMSKey:          "1234abcd-12ab-34cd-56ef-1234567890ab",
+				PreviousKMSKeys: []string{"arn:aws:s3:::bucket", key},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::spec.encryptionAtRest.kmsKey",
+				"Invalid value::spec.encryptionAtRest.previousKMSKeys[0]",
+			},
+		},
+		{
+			CloudProvider: "aws",
+			Input: kops.EncryptionAtRestSpec{
+				KMSKey:          key,
+				PreviousKMSKeys: []string{key},
+			},
+			ExpectedErrors: []string{"Duplicate value::spec.encryptionAtRest.previousKMSKeys[0]"},
+		},
+		{
+			CloudProvider:    "aws",
+			EncryptionConfig: fi.Bool(true),
+			Input:            kops.EncryptionAtRestSpec{KMSKey: key},
+		},
+		{
+			CloudProvider: "aws",
+			Input: kops.EncryptionAtRestSpec{
+				KMSKey: key,
+				PreviousKMSKeys: []string{
+					"arn:aws:kms:us-east-1:123456789012:key/1",
+					"arn:aws:kms:us-east-1:123456789012:key/2",
+					"arn:aws:kms:us-east-1:123456789012:key/3",
+					"arn:aws:kms:us-east-1:123456789012:key/4",
+				},
+			},
+		},
+		{
+			CloudProvider: "aws",
+			Input: kops.EncryptionAtRestSpec{
+				KMSKey: key,
+				PreviousKMSKeys: []string{
+					"arn:aws:kms:us-east-1:123456789012:key/1",
+					"arn:aws:kms:us-east-1:123456789012:key/2",
+					"arn:aws:kms:us-east-1:123456789012:key/3",
+					"arn:aws:kms:us-east-1:123456789012:key/4",
+					"arn:aws:kms:us-east-1:123456789012:key/5",
+				},
+			},
+			ExpectedErrors: []string{"Too many::spec.encryptionAtRest.previousKMSKeys"},
+		},
+		{
+			CloudProvider:  "gce",
+			Input:          kops.EncryptionAtRestSpec{KMSKey: key},
+			ExpectedErrors: []string{"Forbidden::spec.encryptionAtRest"},
+		},
+	}
+
+	for _, g := range grid {
+		spec := &kops.ClusterSpec{
+			CloudProvider:    g.CloudProvider,
+			EncryptionConfig: g.EncryptionConfig,
+			EncryptionAtRest: &g.Input,
+		}
+		errs := validateEncryptionAtRest(spec, field.NewPath("spec", "encryptionAtRest"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_EtcdExternal(t *testing.T) {
+	grid := []struct {
+		Input          kops.EtcdClusterSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.EtcdClusterSpec{
+				Name:     "main",
+				Provider: kops.EtcdProviderTypeExternal,
+				External: &kops.EtcdExternalSpec{
+					Endpoints: []string{"https://etcd-a.example.com:2379", "https://etcd-b.example.com:2379/"},
+				},
+			},
+		},
+		{
+			Input: kops.EtcdClusterSpec{
+				Name:     "cilium",
+				Provider: kops.EtcdProviderTypeExternal,
+				External: &kops.EtcdExternalSpec{
+					Endpoints: []string{"https://etcd-a.example.com:2379"},
+				},
+			},
+			ExpectedErrors: []string{"Forbidden::spec.etcdClusters[0].name"},
+		},
+		{
+			Input: kops.EtcdClusterSpec{
+				Name:     "main",
+				Provider: kops.EtcdProviderTypeExternal,
+			},
+			ExpectedErrors: []string{"Required value::spec.etcdClusters[0].external"},
+		},
+		{
+			Input: kops.EtcdClusterSpec{
+				Name:     "main",
+				External: &kops.EtcdExternalSpec{},
+			},
+			ExpectedErrors: []string{"Required value::spec.etcdClusters[0].external.endpoints"},
+		},
+		{
+			Input: kops.EtcdClusterSpec{
+				Name:     "main",
+				Provider: kops.EtcdProviderTypeExternal,
+				Members:  []kops.EtcdMemberSpec{{Name: "a", InstanceGroup: fi.String("master-a")}},
+				Manager:  &kops.EtcdManagerSpec{},
+				Backups:  &kops.EtcdBackupSpec{BackupStore: "s3://bucket/backups"},
+				External: &kops.EtcdExternalSpec{
+					Endpoints: []string{"https://etcd-a.example.com:2379"},
+				},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::spec.etcdClusters[0].etcdMembers",
+				"Forbidden::spec.etcdClusters[0].manager",
+				"Forbidden::spec.etcdClusters[0].backups",
+			},
+		},
+		{
+			Input: kops.EtcdClusterSpec{
+				Name:     "main",
+				Provider: kops.EtcdProviderTypeExternal,
+				External: &kops.EtcdExternalSpec{
+					Endpoints: []string{"etcd-a.example.com:2379", "https://etcd-b.example.com:2379/v3"},
+				},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::spec.etcdClusters[0].external.endpoints[0]",
+				"Invalid value::spec.etcdClusters[0].external.endpoints[1]",
+			},
+		},
+		{
+			Input: kops.EtcdClusterSpec{
+				Name:     "main",
+				Provider: kops.EtcdProviderTypeExternal,
+				External: &kops.EtcdExternalSpec{
+					Endpoints: []string{"https://etcd-a.example.com:2379", "http://etcd-b.example.com:2379"},
+				},
+			},
+			ExpectedErrors: []string{"Forbidden::spec.etcdClusters[0].external.endpoints"},
+		},
+	}
+
+	for _, g := range grid {
+		errs := validateEtcdExternal(g.Input, field.NewPath("spec", "etcdClusters").Index(0))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_EtcdTuning(t *testing.T) {
+	quota := resource.MustParse("8Gi")
+	zero := resource.MustParse("0")
+	grid := []struct {
+		Input          kops.EtcdClusterSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.EtcdClusterSpec{
+				QuotaBackendBytes: &quota,
+				AutoCompaction:    &kops.EtcdAutoCompactionSpec{Retention: "8h"},
+				Defragmentation:   &kops.EtcdDefragmentationSpec{Interval: &metav1.Duration{Duration: 24 * time.Hour}},
+				Metrics:           &kops.EtcdMetricsSpec{Type: "extensive", Port: fi.Int32(8091)},
+			},
+		},
+		{
+			Input: kops.EtcdClusterSpec{
+				AutoCompaction: &kops.EtcdAutoCompactionSpec{Mode: "revision", Retention: "10000"},
+			},
+		},
+		{
+			Input: kops.EtcdClusterSpec{
+				AutoCompaction: &kops.EtcdAutoCompactionSpec{Retention: "24"},
+			},
+		},
+		{
+			Input: kops.EtcdClusterSpec{
+				Provider:          kops.EtcdProviderTypeLegacy,
+				QuotaBackendBytes: &quota,
+			},
+			ExpectedErrors: []string{"Forbidden::spec.etcdClusters[0]"},
+		},
+		{
+			Input: kops.EtcdClusterSpec{
+				QuotaBackendBytes: &zero,
+			},
+			ExpectedErrors: []string{"Invalid value::spec.etcdClusters[0].quotaBackendBytes"},
+		},
+		{
+			Input: kops.EtcdClusterSpec{
+				AutoCompaction: &kops.EtcdAutoCompactionSpec{Mode: "hourly", Retention: "1h"},
+			},
+			ExpectedErrors: []string{"Unsupported value::spec.etcdClusters[0].autoCompaction.mode"},
+		},
+		{
+			Input: kops.EtcdClusterSpec{
+				AutoCompaction: &kops.EtcdAutoCompactionSpec{Mode: "revision"},
+			},
+			ExpectedErrors: []string{"Required value::spec.etcdClusters[0].autoCompaction.retention"},
+		},
+		{
+			Input: kops.EtcdClusterSpec{
+				AutoCompaction: &kops.EtcdAutoCompactionSpec{Mode: "revision", Retention: "1h"},
+			},
+			ExpectedErrors: []string{"Invalid value::spec.etcdClusters[0].autoCompaction.retention"},
+		},
+		{
+			Input: kops.EtcdClusterSpec{
+				AutoCompaction: &kops.EtcdAutoCompactionSpec{Retention: "daily"},
+			},
+			ExpectedErrors: []string{"Invalid value::spec.etcdClusters[0].autoCompaction.retention"},
+		},
+		{
+			Input: kops.EtcdClusterSpec{
+				Defragmentation: &kops.EtcdDefragmentationSpec{Interval: &metav1.Duration{Duration: time.Minute}},
+			},
+			ExpectedErrors: []string{"Invalid value::spec.etcdClusters[0].defragmentation.interval"},
+		},
+		{
+			Input: kops.EtcdClusterSpec{
+				Metrics: &kops.EtcdMetricsSpec{Type: "full", Port: fi.Int32(0)},
+			},
+			ExpectedErrors: []string{
+				"Unsupported value::spec.etcdClusters[0].metrics.type",
+				"Invalid value::spec.etcdClusters[0].metrics.port",
+			},
+		},
+	}
+
+	for _, g := range grid {
+		errs := validateEtcdTuning(g.Input, field.NewPath("spec", "etcdClusters").Index(0))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_Audit(t *testing.T) {
+	grid := []struct {
+		CloudProvider  string
+		Input          kops.AuditSpec
+		ExpectedErrors []string
+	}{
+		{
+			CloudProvider: "aws",
+			Input: kops.AuditSpec{
+				Webhook:     &kops.AuditWebhookSpec{Server: "https://audit.example.com/events", Mode: "blocking"},
+				LogShipping: &kops.AuditLogShippingSpec{S3: &kops.AuditLogS3Spec{Bucket: "audit-logs"}},
+			},
+		},
+		{
+			CloudProvider: "aws",
+			Input: kops.AuditSpec{
+				Webhook: &kops.AuditWebhookSpec{Server: "audit.example.com", Mode: "async"},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::spec.audit.webhook.server",
+				"Unsupported value::spec.audit.webhook.mode",
+			},
+		},
+		{
+			CloudProvider: "aws",
+			Input: kops.AuditSpec{
+				LogShipping: &kops.AuditLogShippingSpec{
+					S3:         &kops.AuditLogS3Spec{Bucket: "audit-logs"},
+					CloudWatch: &kops.AuditLogCloudWatchSpec{},
+				},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::spec.audit.logShipping",
+				"Required value::spec.audit.logShipping.cloudWatch.logGroup",
+			},
+		},
+		{
+			CloudProvider: "gce",
+			Input: kops.AuditSpec{
+				LogShipping: &kops.AuditLogShippingSpec{CloudWatch: &kops.AuditLogCloudWatchSpec{LogGroup: "audit"}},
+			},
+			ExpectedErrors: []string{"Forbidden::spec.audit.logShipping.cloudWatch"},
+		},
+		{
+			CloudProvider: "gce",
+			Input: kops.AuditSpec{
+				LogShipping: &kops.AuditLogShippingSpec{GCS: &kops.AuditLogGCSSpec{Bucket: "audit-logs"}},
+			},
+		},
+	}
+
+	for _, g := range grid {
+		spec := &kops.ClusterSpec{
+			CloudProvider: g.CloudProvider,
+			Audit:         &g.Input,
+		}
+		errs := validateAudit(spec, field.NewPath("spec", "audit"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_Logging(t *testing.T) {
+	grid := []struct {
+		CloudProvider  string
+		Input          kops.LoggingSpec
+		ExpectedErrors []string
+	}{
+		{
+			CloudProvider: "aws",
+			Input: kops.LoggingSpec{
+				CloudWatch: &kops.LoggingCloudWatchSpec{LogGroup: "cluster"},
+				S3:         &kops.LoggingS3Spec{Bucket: "logs"},
+				Loki:       &kops.LoggingLokiSpec{URL: "http://loki.example.com:3100/loki/api/v1/push"},
+			},
+		},
+		{
+			CloudProvider: "gce",
+			Input:         kops.LoggingSpec{Stackdriver: &kops.LoggingStackdriverSpec{}},
+		},
+		{
+			CloudProvider:  "aws",
+			Input:          kops.LoggingSpec{},
+			ExpectedErrors: []string{"Required value::spec.logging"},
+		},
+		{
+			CloudProvider:  "aws",
+			Input:          kops.LoggingSpec{CloudWatch: &kops.LoggingCloudWatchSpec{}, S3: &kops.LoggingS3Spec{}},
+			ExpectedErrors: []string{"Required value::spec.logging.cloudWatch.logGroup", "Required value::spec.logging.s3.bucket"},
+		},
+		{
+			CloudProvider:  "gce",
+			Input:          kops.LoggingSpec{S3: &kops.LoggingS3Spec{Bucket: "logs"}},
+			ExpectedErrors: []string{"Forbidden::spec.logging.s3"},
+		},
+		{
+			CloudProvider:  "aws",
+			Input:          kops.LoggingSpec{Stackdriver: &kops.LoggingStackdriverSpec{}},
+			ExpectedErrors: []string{"Forbidden::spec.logging.stackdriver"},
+		},
+		{
+			CloudProvider:  "openstack",
+			Input:          kops.LoggingSpec{Loki: &kops.LoggingLokiSpec{URL: "loki.example.com"}},
+			ExpectedErrors: []string{"Invalid value::spec.logging.loki.url"},
+		},
+	}
+
+	for _, g := range grid {
+		spec := &kops.ClusterSpec{
+			CloudProvider: g.CloudProvider,
+			Logging:       &g.Input,
+		}
+		errs := validateLogging(spec, field.NewPath("spec", "logging"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_NodeCertificates(t *testing.T) {
+	grid := []struct {
+		CloudProvider     string
+		KubernetesVersion string
+		Input             kops.NodeCertificatesSpec
+		ExpectedErrors    []string
+	}{
+		{
+			CloudProvider:     "aws",
+			KubernetesVersion: "1.20.0",
+			Input:             kops.NodeCertificatesSpec{KubeletValidity: &metav1.Duration{Duration: 24 * time.Hour}},
+		},
+		{
+			CloudProvider:     "aws",
+			KubernetesVersion: "1.20.0",
+			Input:             kops.NodeCertificatesSpec{KubeletValidity: &metav1.Duration{Duration: time.Minute}},
+			ExpectedErrors:    []string{"Invalid value::spec.nodeCertificates.kubeletValidity"},
+		},
+		{
+			CloudProvider:     "aws",
+			KubernetesVersion: "1.18.0",
+			Input:             kops.NodeCertificatesSpec{KubeletValidity: &metav1.Duration{Duration: 24 * time.Hour}},
+			ExpectedErrors:    []string{"Forbidden::spec.nodeCertificates.kubeletValidity"},
+		},
+		{
+			CloudProvider:     "gce",
+			KubernetesVersion: "1.20.0",
+			Input:             kops.NodeCertificatesSpec{KubeletValidity: &metav1.Duration{Duration: 24 * time.Hour}},
+			ExpectedErrors:    []string{"Forbidden::spec.nodeCertificates.kubeletValidity"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				CloudProvider:     g.CloudProvider,
+				KubernetesVersion: g.KubernetesVersion,
+			},
+		}
+		errs := validateNodeCertificates(&g.Input, cluster, field.NewPath("spec", "nodeCertificates"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_NodeBootstrap(t *testing.T) {
+	grid := []struct {
+		CloudProvider     string
+		KubernetesVersion string
+		Input             kops.NodeBootstrapSpec
+		ExpectedErrors    []string
+	}{
+		{
+			CloudProvider:     "gce",
+			KubernetesVersion: "1.20.0",
+			Input:             kops.NodeBootstrapSpec{UseKopsController: fi.Bool(true), JoinTokens: fi.Bool(true)},
+		},
+		{
+			CloudProvider:     "aws",
+			KubernetesVersion: "1.20.0",
+			Input:             kops.NodeBootstrapSpec{JoinTokens: fi.Bool(true)},
+		},
+		{
+			CloudProvider:     "aws",
+			KubernetesVersion: "1.20.0",
+			Input:             kops.NodeBootstrapSpec{UseKopsController: fi.Bool(true)},
+			ExpectedErrors:    []string{"Forbidden::spec.nodeBootstrap.useKopsController"},
+		},
+		{
+			CloudProvider:     "gce",
+			KubernetesVersion: "1.18.0",
+			Input:             kops.NodeBootstrapSpec{UseKopsController: fi.Bool(true)},
+			ExpectedErrors:    []string{"Forbidden::spec.nodeBootstrap.useKopsController"},
+		},
+		{
+			CloudProvider:     "gce",
+			KubernetesVersion: "1.20.0",
+			Input:             kops.NodeBootstrapSpec{JoinTokens: fi.Bool(true)},
+			ExpectedErrors:    []string{"Forbidden::spec.nodeBootstrap.joinTokens"},
+		},
+		{
+			CloudProvider:     "openstack",
+			KubernetesVersion: "1.20.0",
+			Input:             kops.NodeBootstrapSpec{UseKopsController: fi.Bool(true), JoinTokens: fi.Bool(true)},
+			ExpectedErrors:    []string{"Forbidden::spec.nodeBootstrap.useKopsController", "Forbidden::spec.nodeBootstrap.joinTokens"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				CloudProvider:     g.CloudProvider,
+				KubernetesVersion: g.KubernetesVersion,
+				NodeBootstrap:     &g.Input,
+			},
+		}
+		errs := validateNodeBootstrap(&g.Input, cluster, field.NewPath("spec", "nodeBootstrap"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_MetricsServer(t *testing.T) {
+	grid := []struct {
+		CloudProvider  string
+		CertManager    bool
+		Input          kops.MetricsServerConfig
+		ExpectedErrors []string
+	}{
+		{
+			CloudProvider: "aws",
+			Input:         kops.MetricsServerConfig{Enabled: fi.Bool(true), Insecure: fi.Bool(true)},
+		},
+		{
+			CloudProvider: "aws",
+			CertManager:   true,
+			Input:         kops.MetricsServerConfig{Enabled: fi.Bool(true), Insecure: fi.Bool(false)},
+		},
+		{
+			CloudProvider:  "aws",
+			Input:          kops.MetricsServerConfig{Enabled: fi.Bool(true), Insecure: fi.Bool(false)},
+			ExpectedErrors: []string{"Forbidden::spec.metricsServer.insecure"},
+		},
+		{
+			CloudProvider:  "openstack",
+			CertManager:    true,
+			Input:          kops.MetricsServerConfig{Enabled: fi.Bool(true), Insecure: fi.Bool(false)},
+			ExpectedErrors: []string{"Forbidden::spec.metricsServer.insecure"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				CloudProvider:     g.CloudProvider,
+				KubernetesVersion: "1.20.0",
+				CertManager:       &kops.CertManagerConfig{Enabled: fi.Bool(g.CertManager)},
+				MetricsServer:     &g.Input,
+			},
+		}
+		errs := validateMetricsServer(cluster, &g.Input, field.NewPath("spec", "metricsServer"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_AirGapped(t *testing.T) {
+	grid := []struct {
+		Channel        string
+		Input          kops.Assets
+		ExpectedErrors []string
+	}{
+		{
+			Channel: "s3://mirror/channels/stable",
+			Input: kops.Assets{
+				AirGapped:         fi.Bool(true),
+				ContainerRegistry: fi.String("registry.example.com"),
+				FileRepository:    fi.String("https://mirror.s3.amazonaws.com/"),
+			},
+		},
+		{
+			Channel: "none",
+			Input: kops.Assets{
+				AirGapped:         fi.Bool(true),
+				ContainerRegistry: fi.String("registry.example.com"),
+				FileRepository:    fi.String("https://mirror.s3.amazonaws.com/"),
+			},
+		},
+		{
+			Input: kops.Assets{
+				AirGapped:         fi.Bool(true),
+				ContainerRegistry: fi.String("registry.example.com"),
+				FileRepository:    fi.String("https://mirror.s3.amazonaws.com/"),
+			},
+			ExpectedErrors: []string{"Forbidden::spec.channel"},
+		},
+		{
+			Channel: "alpha",
+			Input: kops.Assets{
+				AirGapped:         fi.Bool(true),
+				ContainerRegistry: fi.String("registry.example.com"),
+				FileRepository:    fi.String("https://mirror.s3.amazonaws.com/"),
+			},
+			ExpectedErrors: []string{"Forbidden::spec.channel"},
+		},
+		{
+			Channel: "file:///srv/kops/channels/stable",
+			Input: kops.Assets{
+				AirGapped:      fi.Bool(true),
+				ContainerProxy: fi.String("proxy.example.com"),
+			},
+			ExpectedErrors: []string{
+				"Required value::spec.assets.containerRegistry",
+				"Required value::spec.assets.fileRepository",
+				"Forbidden::spec.assets.containerProxy",
+			},
+		},
+	}
+
+	for _, g := range grid {
+		spec := &kops.ClusterSpec{
+			Channel: g.Channel,
+			Assets:  &g.Input,
+		}
+		errs := validateAirGapped(spec, field.NewPath("spec"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_FIPS(t *testing.T) {
+	grid := []struct {
+		Input          kops.ClusterSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.ClusterSpec{
+				KubeAPIServer: &kops.KubeAPIServerConfig{
+					TLSCipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+					TLSMinVersion:   "VersionTLS12",
+				},
+			},
+		},
+		{
+			Input: kops.ClusterSpec{
+				KubeAPIServer: &kops.KubeAPIServerConfig{
+					TLSCipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305"},
+				},
+				Kubelet: &kops.KubeletConfigSpec{
+					TLSMinVersion: "VersionTLS11",
+				},
+			},
+			ExpectedErrors: []string{
+				"Unsupported value::spec.kubeAPIServer.tlsCipherSuites[1]",
+				"Unsupported value::spec.kubelet.tlsMinVersion",
+			},
+		},
+		{
+			Input: kops.ClusterSpec{
+				EtcdClusters: []kops.EtcdClusterSpec{
+					{
+						Name: "main",
+						Manager: &kops.EtcdManagerSpec{
+							Env: []kops.EnvVar{{Name: "ETCD_CIPHER_SUITES", Value: "TLS_RSA_WITH_AES_128_CBC_SHA"}},
+						},
+					},
+				},
+				Networking: &kops.NetworkingSpec{
+					Calico: &kops.CalicoNetworkingSpec{WireguardEnabled: true},
+				},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::spec.etcdClusters[0].manager.env[0]",
+				"Forbidden::spec.networking.calico.wireguardEnabled",
+			},
+		},
+	}
+
+	for _, g := range grid {
+		errs := validateFIPS(&g.Input, field.NewPath("spec"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_Hardening(t *testing.T) {
+	grid := []struct {
+		Input          kops.ClusterSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.ClusterSpec{
+				Hardening:     &kops.HardeningSpec{Profile: "cis"},
+				Authorization: &kops.AuthorizationSpec{RBAC: &kops.RBACAuthorizationSpec{}},
+			},
+		},
+		{
+			Input: kops.ClusterSpec{
+				Hardening:     &kops.HardeningSpec{Profile: "cis"},
+				Authorization: &kops.AuthorizationSpec{AlwaysAllow: &kops.AlwaysAllowAuthorizationSpec{}},
+			},
+			ExpectedErrors: []string{"Forbidden::spec.authorization.alwaysAllow"},
+		},
+		{
+			Input: kops.ClusterSpec{
+				Hardening: &kops.HardeningSpec{Profile: "stig"},
+			},
+			ExpectedErrors: []string{"Unsupported value::spec.hardening.profile"},
+		},
+	}
+
+	for _, g := range grid {
+		errs := validateHardening(&g.Input, field.NewPath("spec"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_EgressProxy(t *testing.T) {
+	grid := []struct {
+		Input          kops.EgressProxySpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.EgressProxySpec{HTTPProxy: kops.HTTPProxy{Host: "proxy.example.com", Port: 3128}, ProxyExcludes: "example.com, 10.0.0.0/8"},
+		},
+		{
+			Input: kops.EgressProxySpec{HTTPProxy: kops.HTTPProxy{Host: "https://proxy.example.com"}},
+		},
+		{
+			Input:          kops.EgressProxySpec{HTTPProxy: kops.HTTPProxy{Port: 3128}},
+			ExpectedErrors: []string{"Required value::spec.egressProxy.httpProxy.host"},
+		},
+		{
+			Input:          kops.EgressProxySpec{HTTPProxy: kops.HTTPProxy{Host: "socks5://proxy.example.com"}},
+			ExpectedErrors: []string{"Invalid value::spec.egressProxy.httpProxy.host"},
+		},
+		{
+			Input:          kops.EgressProxySpec{HTTPProxy: kops.HTTPProxy{Host: "proxy example.com"}},
+			ExpectedErrors: []string{"Invalid value::spec.egressProxy.httpProxy.host"},
+		},
+		{
+			Input:          kops.EgressProxySpec{HTTPProxy: kops.HTTPProxy{Host: "proxy.example.com", Port: 65536}},
+			ExpectedErrors: []string{"Invalid value::spec.egressProxy.httpProxy.port"},
+		},
+		{
+			Input:          kops.EgressProxySpec{HTTPProxy: kops.HTTPProxy{Host: "proxy.example.com"}, ProxyExcludes: "example.com,internal example.com"},
+			ExpectedErrors: []string{"Invalid value::spec.egressProxy.excludes"},
+		},
+	}
+
+	for _, g := range grid {
+		errs := validateEgressProxy(&g.Input, field.NewPath("spec", "egressProxy"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_Addons(t *testing.T) {
+	grid := []struct {
+		Input          []kops.AddonSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: []kops.AddonSpec{
+				{Manifest: "s3://bucket/addons.yaml", Priority: 10},
+				{Name: "kube-dns.addons.k8s.io", Disabled: true},
+				{Name: "coredns.addons.k8s.io", Version: "1.8.3"},
+				{Name: "metrics-server.addons.k8s.io", ManifestHash: "abc"},
+			},
+		},
+		{
+			Input:          []kops.AddonSpec{{}},
+			ExpectedErrors: []string{"Required value::spec.addons[0]"},
+		},
+		{
+			Input:          []kops.AddonSpec{{Manifest: "s3://bucket/addons.yaml", Name: "coredns.addons.k8s.io"}},
+			ExpectedErrors: []string{"Forbidden::spec.addons[0].name"},
+		},
+		{
+			Input:          []kops.AddonSpec{{Manifest: "s3://bucket/addons.yaml", Disabled: true}},
+			ExpectedErrors: []string{"Forbidden::spec.addons[0]"},
+		},
+		{
+			Input:          []kops.AddonSpec{{Name: "coredns.addons.k8s.io"}},
+			ExpectedErrors: []string{"Required value::spec.addons[0]"},
+		},
+		{
+			Input:          []kops.AddonSpec{{Name: "coredns.addons.k8s.io", Disabled: true, Version: "1.8.3"}},
+			ExpectedErrors: []string{"Forbidden::spec.addons[0]"},
+		},
+		{
+			Input:          []kops.AddonSpec{{Name: "coredns.addons.k8s.io", Version: "latest"}},
+			ExpectedErrors: []string{"Invalid value::spec.addons[0].version"},
+		},
+		{
+			Input:          []kops.AddonSpec{{Name: "coredns.addons.k8s.io", Version: "1.8.3", Priority: 10}},
+			ExpectedErrors: []string{"Forbidden::spec.addons[0].priority"},
+		},
+		{
+			Input:          []kops.AddonSpec{{Name: "kops-controller.addons.k8s.io", Disabled: true}},
+			ExpectedErrors: []string{"Forbidden::spec.addons[0].disabled"},
+		},
+		{
+			Input: []kops.AddonSpec{
+				{Name: "coredns.addons.k8s.io", Version: "1.8.3"},
+				{Name: "coredns.addons.k8s.io", Disabled: true},
+			},
+			ExpectedErrors: []string{"Duplicate value::spec.addons[1].name"},
+		},
+	}
+
+	for _, g := range grid {
+		errs := validateAddons(g.Input, field.NewPath("spec", "addons"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_InstanceGroupScaling(t *testing.T) {
+	grid := []struct {
+		CloudProvider     string
+		KubernetesVersion string
+		ConfigBase        string
+		Input             kops.InstanceGroupScalingSpec
+		ExpectedErrors    []string
+	}{
+		{
+			CloudProvider:     "aws",
+			KubernetesVersion: "1.20.0",
+			ConfigBase:        "s3://bucket/cluster.example.com",
+			Input:             kops.InstanceGroupScalingSpec{Enabled: fi.Bool(true), StateStoreLockTable: "kops-locks"},
+		},
+		{
+			CloudProvider:     "aws",
+			KubernetesVersion: "1.20.0",
+			ConfigBase:        "s3://bucket/cluster.example.com",
+			Input:             kops.InstanceGroupScalingSpec{Enabled: fi.Bool(true)},
+			ExpectedErrors:    []string{"Required value::spec.instanceGroupScaling.stateStoreLockTable"},
+		},
+		{
+			CloudProvider:     "aws",
+			KubernetesVersion: "1.20.0",
+			Input:             kops.InstanceGroupScalingSpec{Enabled: fi.Bool(true)},
+		},
+		{
+			CloudProvider:     "gce",
+			KubernetesVersion: "1.20.0",
+			Input:             kops.InstanceGroupScalingSpec{Enabled: fi.Bool(false)},
+		},
+		{
+			CloudProvider:     "aws",
+			KubernetesVersion: "1.18.0",
+			Input:             kops.InstanceGroupScalingSpec{Enabled: fi.Bool(true)},
+			ExpectedErrors:    []string{"Forbidden::spec.instanceGroupScaling.enabled"},
+		},
+		{
+			CloudProvider:     "gce",
+			KubernetesVersion: "1.20.0",
+			Input:             kops.InstanceGroupScalingSpec{Enabled: fi.Bool(true)},
+			ExpectedErrors:    []string{"Forbidden::spec.instanceGroupScaling.enabled"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				CloudProvider:        g.CloudProvider,
+				KubernetesVersion:    g.KubernetesVersion,
+				ConfigBase:           g.ConfigBase,
+				InstanceGroupScaling: &g.Input,
+			},
+		}
+		errs := validateInstanceGroupScaling(&g.Input, cluster, field.NewPath("spec", "instanceGroupScaling"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_CloudConfiguration(t *testing.T) {
+	grid := []struct {
+		Description    string
+		Input          kops.CloudConfiguration
+		ExpectedErrors []string
+	}{
+		{
+			Description: "neither",
+			Input:       kops.CloudConfiguration{},
+		},
+		{
+			Description: "all false",
+			Input: kops.CloudConfiguration{
+				ManageStorageClasses: fi.Bool(false),
+			},
+		},
+		{
+			Description: "all true",
+			Input: kops.CloudConfiguration{
+				ManageStorageClasses: fi.Bool(true),
+			},
+		},
+		{
+			Description: "os false",
+			Input: kops.CloudConfiguration{
+				Openstack: &kops.OpenstackConfiguration{
+					BlockStorage: &kops.OpenstackBlockStorageConfig{
+						CreateStorageClass: fi.Bool(false),
+					},
+				}},
+		},
+		{
+			Description: "os true",
+			Input: kops.CloudConfiguration{
+				Openstack: &kops.OpenstackConfiguration{
+					BlockStorage: &kops.OpenstackBlockStorageConfig{
+						CreateStorageClass: fi.Bool(true),
+					},
+				}},
+		},
+		{
+			Description: "all false, os false",
+			Input: kops.CloudConfiguration{
+				ManageStorageClasses: fi.Bool(false),
+				Openstack: &kops.OpenstackConfiguration{
+					BlockStorage: &kops.OpenstackBlockStorageConfig{
+						CreateStorageClass: fi.Bool(false),
+					},
+				}},
+		},
+		{
+			Description: "all false, os true",
+			Input: kops.CloudConfiguration{
+				ManageStorageClasses: fi.Bool(false),
+				Openstack: &kops.OpenstackConfiguration{
+					BlockStorage: &kops.OpenstackBlockStorageConfig{
+						CreateStorageClass: fi.Bool(true),
+					},
+				}},
+			ExpectedErrors: []string{"Forbidden::cloudConfig.manageStorageClasses"},
+		},
+		{
+			Description: "all true, os false",
+			Input: kops.CloudConfiguration{
+				ManageStorageClasses: fi.Bool(true),
+				Openstack: &kops.OpenstackConfiguration{
+					BlockStorage: &kops.OpenstackBlockStorageConfig{
+						CreateStorageClass: fi.Bool(false),
+					},
+				}},
+			ExpectedErrors: []string{"Forbidden::cloudConfig.manageStorageClasses"},
+		},
+		{
+			Description: "all true, os true",
+			Input: kops.CloudConfiguration{
+				ManageStorageClasses: fi.Bool(true),
+				Openstack: &kops.OpenstackConfiguration{
+					BlockStorage: &kops.OpenstackBlockStorageConfig{
+						CreateStorageClass: fi.Bool(true),
+					},
+				}},
+		},
+	}
+
+	for _, g := range grid {
+		fldPath := field.NewPath("cloudConfig")
+		t.Run(g.Description, func(t *testing.T) {
+			errs := validateCloudConfiguration(&g.Input, fldPath)
+			testErrors(t, g.Input, errs, g.ExpectedErrors)
+		})
+	}
+}
+
+func TestValidateSAExternalPermissions(t *testing.T) {
+	grid := []struct {
+		Description    string
+		Input          []kops.ServiceAccountExternalPermission
+		ExpectedErrors []string
+	}{
+
+		{
+			Description: "Duplicate SA",
+			Input: []kops.ServiceAccountExternalPermission{
+				{
+					Name:      "MySA",
+					Namespace: "MyNS",
+					AWS: &kops.AWSPermission{
+						PolicyARNs: []string{"-"},
+					},
+				},
+				{
+					Name:      "MySA",
+					Namespace: "MyNS",
+					AWS: &kops.AWSPermission{
+						PolicyARNs: []string{"-"},
+					},
+				},
+			},
+			ExpectedErrors: []string{"Duplicate value::iam.serviceAccountExternalPermissions[MyNS/MySA]"},
+		},
+		{
+			Description: "Missing permissions",
+			Input: []kops.ServiceAccountExternalPermission{
+				{
+					Name:      "MySA",
+					Namespace: "MyNS",
+				},
+			},
+			ExpectedErrors: []string{"Required value::iam.serviceAccountExternalPermissions[MyNS/MySA].aws"},
+		},
+		{
+			Description: "Setting both arn and inline",
+			Input: []kops.ServiceAccountExternalPermission{
+				{
+					Name:      "MySA",
 					Namespace: "MyNS",
 					AWS: &kops.AWSPermission{
 						PolicyARNs:   []string{"-"},
@@ -1290,3 +2682,513 @@ func TestValidateSAExternalPermissions(t *testing.T) {
 	}
 
 }
+
+func Test_Validate_OIDCAuthentication(t *testing.T) {
+	grid := []struct {
+		Input          kops.OIDCAuthenticationSpec
+		KubeAPIServer  *kops.KubeAPIServerConfig
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.OIDCAuthenticationSpec{
+				IssuerURL:      "https://issuer.example.com",
+				ClientID:       "kubernetes",
+				RequiredClaims: map[string]string{"hd": "example.com"},
+			},
+		},
+		{
+			Input: kops.OIDCAuthenticationSpec{},
+			ExpectedErrors: []string{
+				"Required value::spec.authentication.oidc.issuerURL",
+				"Required value::spec.authentication.oidc.clientID",
+			},
+		},
+		{
+			Input: kops.OIDCAuthenticationSpec{
+				IssuerURL:            "http://issuer.example.com",
+				ClientID:             "kubernetes",
+				CertificateAuthority: "not a certificate",
+			},
+			ExpectedErrors: []string{
+				"Invalid value::spec.authentication.oidc.issuerURL",
+				"Invalid value::spec.authentication.oidc.certificateAuthority",
+			},
+		},
+		{
+			Input: kops.OIDCAuthenticationSpec{
+				IssuerURL:      "https://issuer.example.com",
+				ClientID:       "kubernetes",
+				RequiredClaims: map[string]string{"": "example.com"},
+			},
+			ExpectedErrors: []string{"Invalid value::spec.authentication.oidc.requiredClaims"},
+		},
+		{
+			Input: kops.OIDCAuthenticationSpec{
+				IssuerURL: "https://issuer.example.com",
+				ClientID:  "kubernetes",
+			},
+			KubeAPIServer: &kops.KubeAPIServerConfig{
+				OIDCIssuerURL: fi.String("https://other.example.com"),
+				OIDCClientID:  fi.String("kubernetes"),
+			},
+			ExpectedErrors: []string{"Forbidden::spec.kubeAPIServer.oidcIssuerURL"},
+		},
+		{
+			Input: kops.OIDCAuthenticationSpec{
+				IssuerURL:      "https://issuer.example.com",
+				ClientID:       "kubernetes",
+				GroupsClaim:    "groups",
+				RequiredClaims: map[string]string{"hd": "example.com"},
+			},
+			KubeAPIServer: &kops.KubeAPIServerConfig{
+				OIDCUsernameClaim: fi.String("sub"),
+				OIDCGroupsClaim:   fi.String("groups"),
+				OIDCRequiredClaim: []string{"hd=example.com"},
+			},
+		},
+		{
+			Input: kops.OIDCAuthenticationSpec{
+				IssuerURL: "https://issuer.example.com",
+				ClientID:  "kubernetes",
+			},
+			KubeAPIServer: &kops.KubeAPIServerConfig{
+				OIDCUsernameClaim:  fi.String("email"),
+				OIDCUsernamePrefix: fi.String("oidc:"),
+				OIDCGroupsClaim:    fi.String("groups"),
+				OIDCGroupsPrefix:   fi.String("oidc:"),
+				OIDCRequiredClaim:  []string{"hd=example.com"},
+				OIDCCAFile:         fi.String("/srv/kubernetes/ca.crt"),
+			},
+			ExpectedErrors: []string{
+				"Forbidden::spec.kubeAPIServer.oidcUsernameClaim",
+				"Forbidden::spec.kubeAPIServer.oidcUsernamePrefix",
+				"Forbidden::spec.kubeAPIServer.oidcGroupsClaim",
+				"Forbidden::spec.kubeAPIServer.oidcGroupsPrefix",
+				"Forbidden::spec.kubeAPIServer.oidcRequiredClaim",
+				"Forbidden::spec.kubeAPIServer.oidcCAFile",
+			},
+		},
+	}
+
+	for _, g := range grid {
+		spec := &kops.ClusterSpec{
+			Authentication: &kops.AuthenticationSpec{OIDC: &g.Input},
+			KubeAPIServer:  g.KubeAPIServer,
+		}
+		errs := validateOIDCAuthentication(spec, field.NewPath("spec"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_PodSecurity(t *testing.T) {
+	grid := []struct {
+		KubernetesVersion string
+		Input             kops.PodSecuritySpec
+		KubeAPIServer     *kops.KubeAPIServerConfig
+		ExpectedErrors    []string
+	}{
+		{
+			KubernetesVersion: "1.22.0",
+			Input: kops.PodSecuritySpec{
+				Enforce:          "baseline",
+				Warn:             "restricted",
+				Version:          "v1.22",
+				ExemptNamespaces: []string{"monitoring"},
+			},
+		},
+		{
+			KubernetesVersion: "1.21.0",
+			Input:             kops.PodSecuritySpec{},
+			ExpectedErrors:    []string{"Forbidden::spec.podSecurity"},
+		},
+		{
+			KubernetesVersion: "1.22.0",
+			Input: kops.PodSecuritySpec{
+				Enforce:          "strict",
+				Version:          "1.22",
+				ExemptNamespaces: []string{"Monitoring"},
+			},
+			ExpectedErrors: []string{
+				"Unsupported value::spec.podSecurity.enforce",
+				"Invalid value::spec.podSecurity.version",
+				"Invalid value::spec.podSecurity.exemptNamespaces[0]",
+			},
+		},
+		{
+			KubernetesVersion: "1.22.0",
+			Input:             kops.PodSecuritySpec{},
+			KubeAPIServer: &kops.KubeAPIServerConfig{
+				AdmissionControlConfigFile: "/srv/kubernetes/admission.yaml",
+			},
+			ExpectedErrors: []string{"Forbidden::spec.kubeAPIServer.admissionControlConfigFile"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				KubernetesVersion: g.KubernetesVersion,
+				PodSecurity:       &g.Input,
+				KubeAPIServer:     g.KubeAPIServer,
+			},
+		}
+		errs := validatePodSecurity(&cluster.Spec, cluster, field.NewPath("spec"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_NvidiaGPU(t *testing.T) {
+	grid := []struct {
+		KubernetesVersion string
+		ContainerRuntime  string
+		Input             kops.NvidiaGPUConfig
+		ExpectedErrors    []string
+	}{
+		{
+			KubernetesVersion: "1.21.0",
+			ContainerRuntime:  "containerd",
+			Input:             kops.NvidiaGPUConfig{Enabled: fi.Bool(true), DriverPackage: fi.String("nvidia-headless-470-server")},
+		},
+		{
+			KubernetesVersion: "1.21.0",
+			ContainerRuntime:  "docker",
+			Input:             kops.NvidiaGPUConfig{Enabled: fi.Bool(false)},
+		},
+		{
+			KubernetesVersion: "1.19.0",
+			ContainerRuntime:  "docker",
+			Input:             kops.NvidiaGPUConfig{Enabled: fi.Bool(true)},
+			ExpectedErrors: []string{
+				"Forbidden::spec.containerd.nvidiaGPU.enabled",
+				"Forbidden::spec.containerd.nvidiaGPU.enabled",
+			},
+		},
+		{
+			KubernetesVersion: "1.21.0",
+			ContainerRuntime:  "containerd",
+			Input:             kops.NvidiaGPUConfig{Enabled: fi.Bool(true), DriverPackage: fi.String("")},
+			ExpectedErrors:    []string{"Required value::spec.containerd.nvidiaGPU.driverPackage"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				KubernetesVersion: g.KubernetesVersion,
+				ContainerRuntime:  g.ContainerRuntime,
+			},
+		}
+		errs := validateNvidiaGPU(&g.Input, cluster, field.NewPath("spec", "containerd", "nvidiaGPU"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_ContainerdSettings(t *testing.T) {
+	grid := []struct {
+		Version        string
+		Input          kops.ContainerdConfig
+		ExpectedErrors []string
+	}{
+		{
+			Version: "1.5.5",
+			Input: kops.ContainerdConfig{
+				Registries: map[string]kops.ContainerdRegistryConfig{
+					"docker.io": {Mirrors: []string{"https://mirror.example.com"}, Username: fi.String("user"), Password: fi.String("pass")},
+				},
+				Runtimes: map[string]kops.ContainerdRuntimeConfig{
+					"runsc": {Type: "io.containerd.runsc.v1"},
+				},
+				SandboxImage: fi.String("registry.example.com/pause:3.5"),
+			},
+		},
+		{
+			Version: "1.4.6",
+			Input: kops.ContainerdConfig{
+				Registries: map[string]kops.ContainerdRegistryConfig{
+					"docker.io": {Mirrors: []string{"https://mirror.example.com"}},
+				},
+			},
+			ExpectedErrors: []string{"Forbidden::spec.containerd.registries"},
+		},
+		{
+			Version: "1.5.5",
+			Input: kops.ContainerdConfig{
+				Registries: map[string]kops.ContainerdRegistryConfig{
+					"https://docker.io": {Mirrors: []string{"mirror.example.com"}, Username: fi.String("user"), CA: fi.String("not a certificate")},
+				},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::spec.containerd.registries[https://docker.io]",
+				"Invalid value::spec.containerd.registries[https://docker.io].mirrors[0]",
+				"Required value::spec.containerd.registries[https://docker.io].password",
+				"Invalid value::spec.containerd.registries[https://docker.io].ca",
+			},
+		},
+		{
+			Version: "1.5.5",
+			Input: kops.ContainerdConfig{
+				Runtimes: map[string]kops.ContainerdRuntimeConfig{
+					"runc":   {Type: "io.containerd.runc.v2"},
+					"Kata_2": {Type: "io.containerd.kata.v2"},
+					"runsc":  {},
+				},
+				SandboxImage: fi.String(""),
+			},
+			ExpectedErrors: []string{
+				"Invalid value::spec.containerd.runtimes[Kata_2]",
+				"Forbidden::spec.containerd.runtimes[runc]",
+				"Required value::spec.containerd.runtimes[runsc].type",
+				"Required value::spec.containerd.sandboxImage",
+			},
+		},
+		{
+			Version: "1.7.0",
+			Input:   kops.ContainerdConfig{NRI: &kops.ContainerdNRIConfig{Enabled: fi.Bool(true)}},
+		},
+		{
+			Version:        "1.6.6",
+			Input:          kops.ContainerdConfig{NRI: &kops.ContainerdNRIConfig{Enabled: fi.Bool(true)}},
+			ExpectedErrors: []string{"Forbidden::spec.containerd.nri.enabled"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				ContainerRuntime: "containerd",
+				Containerd:       &kops.ContainerdConfig{Version: fi.String(g.Version)},
+			},
+		}
+		errs := validateContainerdSettings(&g.Input, cluster, field.NewPath("spec", "containerd"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_NodeTuning(t *testing.T) {
+	grid := []struct {
+		Input          kops.NodeTuningSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.NodeTuningSpec{
+				Sysctls:       map[string]string{"net.core.somaxconn": "4096"},
+				KernelModules: []string{"ip_vs", "nf-conntrack"},
+				HugePages:     map[string]int32{"2Mi": 512, "1Gi": 2},
+				UdevRules:     map[string]string{"90-nvme.rules": `KERNEL=="nvme[0-9]*n[0-9]*", ATTR{queue/scheduler}="none"`},
+			},
+		},
+		{
+			Input:          kops.NodeTuningSpec{Sysctls: map[string]string{"net.core.somaxconn=1": "4096"}},
+			ExpectedErrors: []string{"Invalid value::spec.nodeTuning.sysctls"},
+		},
+		{
+			Input:          kops.NodeTuningSpec{KernelModules: []string{"ip_vs; reboot"}},
+			ExpectedErrors: []string{"Invalid value::spec.nodeTuning.kernelModules[0]"},
+		},
+		{
+			Input:          kops.NodeTuningSpec{HugePages: map[string]int32{"2M": 512}},
+			ExpectedErrors: []string{"Invalid value::spec.nodeTuning.hugePages"},
+		},
+		{
+			Input:          kops.NodeTuningSpec{HugePages: map[string]int32{"2Mi": -1}},
+			ExpectedErrors: []string{"Invalid value::spec.nodeTuning.hugePages[2Mi]"},
+		},
+		{
+			Input:          kops.NodeTuningSpec{UdevRules: map[string]string{"../90-nvme.rules": "KERNEL==\"nvme*\""}},
+			ExpectedErrors: []string{"Invalid value::spec.nodeTuning.udevRules"},
+		},
+		{
+			Input:          kops.NodeTuningSpec{UdevRules: map[string]string{"90-nvme.rules": ""}},
+			ExpectedErrors: []string{"Required value::spec.nodeTuning.udevRules[90-nvme.rules]"},
+		},
+	}
+
+	for _, g := range grid {
+		errs := validateNodeTuning(&g.Input, field.NewPath("spec", "nodeTuning"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_KubeletConfigFile(t *testing.T) {
+	oneGi := resource.MustParse("1Gi")
+	grid := []struct {
+		Input             kops.KubeletConfigSpec
+		KubernetesVersion string
+		ExpectedErrors    []string
+	}{
+		{
+			Input: kops.KubeletConfigSpec{
+				MemoryManagerPolicy: "Static",
+				ReservedMemory: []kops.KubeletReservedMemory{
+					{NUMANode: 0, Limits: map[string]resource.Quantity{"memory": oneGi, "hugepages-2Mi": oneGi}},
+				},
+				ShutdownGracePeriod:             &metav1.Duration{Duration: 30 * time.Second},
+				ShutdownGracePeriodCriticalPods: &metav1.Duration{Duration: 10 * time.Second},
+			},
+			KubernetesVersion: "1.22.0",
+		},
+		{
+			Input: kops.KubeletConfigSpec{
+				ShutdownGracePeriodByPodPriority: []kops.KubeletShutdownGracePeriodByPodPriority{
+					{Priority: 0, ShutdownGracePeriodSeconds: 60},
+					{Priority: 100000, ShutdownGracePeriodSeconds: 10},
+				},
+			},
+			KubernetesVersion: "1.23.0",
+		},
+		{
+			Input: kops.KubeletConfigSpec{
+				MemoryManagerPolicy: "None",
+				ShutdownGracePeriod: &metav1.Duration{Duration: 30 * time.Second},
+			},
+			KubernetesVersion: "1.20.0",
+			ExpectedErrors: []string{
+				"Forbidden::spec.kubelet.memoryManagerPolicy",
+				"Forbidden::spec.kubelet.shutdownGracePeriod",
+			},
+		},
+		{
+			Input: kops.KubeletConfigSpec{
+				ShutdownGracePeriodByPodPriority: []kops.KubeletShutdownGracePeriodByPodPriority{
+					{Priority: 0, ShutdownGracePeriodSeconds: 60},
+				},
+			},
+			KubernetesVersion: "1.22.0",
+			ExpectedErrors:    []string{"Forbidden::spec.kubelet.shutdownGracePeriodByPodPriority"},
+		},
+		{
+			Input:             kops.KubeletConfigSpec{MemoryManagerPolicy: "BestEffort"},
+			KubernetesVersion: "1.22.0",
+			ExpectedErrors:    []string{"Unsupported value::spec.kubelet.memoryManagerPolicy"},
+		},
+		{
+			Input:             kops.KubeletConfigSpec{MemoryManagerPolicy: "Static"},
+			KubernetesVersion: "1.22.0",
+			ExpectedErrors:    []string{"Required value::spec.kubelet.reservedMemory"},
+		},
+		{
+			Input: kops.KubeletConfigSpec{
+				ReservedMemory: []kops.KubeletReservedMemory{
+					{NUMANode: 0, Limits: map[string]resource.Quantity{"memory": oneGi}},
+				},
+			},
+			KubernetesVersion: "1.22.0",
+			ExpectedErrors:    []string{"Forbidden::spec.kubelet.reservedMemory"},
+		},
+		{
+			Input: kops.KubeletConfigSpec{
+				MemoryManagerPolicy: "Static",
+				ReservedMemory: []kops.KubeletReservedMemory{
+					{NUMANode: -1, Limits: map[string]resource.Quantity{"cpu": oneGi}},
+					{NUMANode: -1},
+				},
+			},
+			KubernetesVersion: "1.22.0",
+			ExpectedErrors: []string{
+				"Invalid value::spec.kubelet.reservedMemory[0].numaNode",
+				"Invalid value::spec.kubelet.reservedMemory[0].limits[cpu]",
+				"Invalid value::spec.kubelet.reservedMemory[1].numaNode",
+				"Duplicate value::spec.kubelet.reservedMemory[1].numaNode",
+				"Required value::spec.kubelet.reservedMemory[1].limits",
+			},
+		},
+		{
+			Input: kops.KubeletConfigSpec{
+				ShutdownGracePeriodCriticalPods: &metav1.Duration{Duration: 10 * time.Second},
+			},
+			KubernetesVersion: "1.22.0",
+			ExpectedErrors:    []string{"Required value::spec.kubelet.shutdownGracePeriod"},
+		},
+		{
+			Input: kops.KubeletConfigSpec{
+				ShutdownGracePeriod:             &metav1.Duration{Duration: 10 * time.Second},
+				ShutdownGracePeriodCriticalPods: &metav1.Duration{Duration: 30 * time.Second},
+			},
+			KubernetesVersion: "1.22.0",
+			ExpectedErrors:    []string{"Invalid value::spec.kubelet.shutdownGracePeriodCriticalPods"},
+		},
+		{
+			Input: kops.KubeletConfigSpec{
+				ShutdownGracePeriod: &metav1.Duration{Duration: 30 * time.Second},
+				ShutdownGracePeriodByPodPriority: []kops.KubeletShutdownGracePeriodByPodPriority{
+					{Priority: 0, ShutdownGracePeriodSeconds: 60},
+					{Priority: 0, ShutdownGracePeriodSeconds: -1},
+				},
+			},
+			KubernetesVersion: "1.23.0",
+			ExpectedErrors: []string{
+				"Forbidden::spec.kubelet.shutdownGracePeriodByPodPriority",
+				"Duplicate value::spec.kubelet.shutdownGracePeriodByPodPriority[1].priority",
+				"Invalid value::spec.kubelet.shutdownGracePeriodByPodPriority[1].shutdownGracePeriodSeconds",
+			},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				KubernetesVersion: g.KubernetesVersion,
+			},
+		}
+		errs := validateKubeletConfigFile(&g.Input, cluster, field.NewPath("spec", "kubelet"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_InstanceAccess(t *testing.T) {
+	grid := []struct {
+		Input          kops.ClusterSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "aws",
+				Topology: &kops.TopologySpec{
+					InstanceAccess: &kops.InstanceAccessSpec{Type: kops.InstanceAccessTypeSSH},
+					Bastion:        &kops.BastionSpec{},
+				},
+			},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "aws",
+				Topology: &kops.TopologySpec{
+					InstanceAccess: &kops.InstanceAccessSpec{Type: kops.InstanceAccessTypeSSM},
+				},
+			},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "aws",
+				Topology: &kops.TopologySpec{
+					InstanceAccess: &kops.InstanceAccessSpec{Type: kops.InstanceAccessTypeEC2InstanceConnect},
+					Bastion:        &kops.BastionSpec{},
+				},
+			},
+			ExpectedErrors: []string{"Forbidden::spec.topology.bastion"},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "gce",
+				Topology: &kops.TopologySpec{
+					InstanceAccess: &kops.InstanceAccessSpec{Type: kops.InstanceAccessTypeSSM},
+				},
+			},
+			ExpectedErrors: []string{"Forbidden::spec.topology.instanceAccess.type"},
+		},
+		{
+			Input: kops.ClusterSpec{
+				CloudProvider: "aws",
+				Topology: &kops.TopologySpec{
+					InstanceAccess: &kops.InstanceAccessSpec{Type: "Telnet"},
+				},
+			},
+			ExpectedErrors: []string{"Unsupported value::spec.topology.instanceAccess.type"},
+		},
+	}
+
+	for _, g := range grid {
+		errs := validateInstanceAccess(&g.Input, field.NewPath("spec", "topology"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
@@ -21,11 +21,33 @@ limitations under the License.
 package kops
 
 import (
+	resource "k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServerEndpointSpec) DeepCopyInto(out *APIServerEndpointSpec) {
+	*out = *in
+	if in.IPs != nil {
+		in, out := &in.IPs, &out.IPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerEndpointSpec.
+func (in *APIServerEndpointSpec) DeepCopy() *APIServerEndpointSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServerEndpointSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSEBSCSIDriver) DeepCopyInto(out *AWSEBSCSIDriver) {
 	*out = *in
@@ -117,6 +139,11 @@ func (in *AccessSpec) DeepCopyInto(out *AccessSpec) {
 		*out = new(LoadBalancerAccessSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(GatewayAccessSpec)
+		**out = **in
+	}
 	return
 }
 
@@ -183,6 +210,27 @@ func (in *AmazonVPCNetworkingSpec) DeepCopy() *AmazonVPCNetworkingSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AntreaNetworkingSpec) DeepCopyInto(out *AntreaNetworkingSpec) {
+	*out = *in
+	if in.MTU != nil {
+		in, out := &in.MTU, &out.MTU
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AntreaNetworkingSpec.
+func (in *AntreaNetworkingSpec) DeepCopy() *AntreaNetworkingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AntreaNetworkingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Assets) DeepCopyInto(out *Assets) {
 	*out = *in
@@ -201,6 +249,11 @@ func (in *Assets) DeepCopyInto(out *Assets) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.AirGapped != nil {
+		in, out := &in.AirGapped, &out.AirGapped
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -214,6 +267,127 @@ func (in *Assets) DeepCopy() *Assets {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditLogCloudWatchSpec) DeepCopyInto(out *AuditLogCloudWatchSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditLogCloudWatchSpec.
+func (in *AuditLogCloudWatchSpec) DeepCopy() *AuditLogCloudWatchSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditLogCloudWatchSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditLogGCSSpec) DeepCopyInto(out *AuditLogGCSSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditLogGCSSpec.
+func (in *AuditLogGCSSpec) DeepCopy() *AuditLogGCSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditLogGCSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditLogS3Spec) DeepCopyInto(out *AuditLogS3Spec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditLogS3Spec.
+func (in *AuditLogS3Spec) DeepCopy() *AuditLogS3Spec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditLogS3Spec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditLogShippingSpec) DeepCopyInto(out *AuditLogShippingSpec) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(AuditLogS3Spec)
+		**out = **in
+	}
+	if in.CloudWatch != nil {
+		in, out := &in.CloudWatch, &out.CloudWatch
+		*out = new(AuditLogCloudWatchSpec)
+		**out = **in
+	}
+	if in.GCS != nil {
+		in, out := &in.GCS, &out.GCS
+		*out = new(AuditLogGCSSpec)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditLogShippingSpec.
+func (in *AuditLogShippingSpec) DeepCopy() *AuditLogShippingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditLogShippingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditSpec) DeepCopyInto(out *AuditSpec) {
+	*out = *in
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(AuditWebhookSpec)
+		**out = **in
+	}
+	if in.LogShipping != nil {
+		in, out := &in.LogShipping, &out.LogShipping
+		*out = new(AuditLogShippingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditSpec.
+func (in *AuditSpec) DeepCopy() *AuditSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditWebhookSpec) DeepCopyInto(out *AuditWebhookSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditWebhookSpec.
+func (in *AuditWebhookSpec) DeepCopy() *AuditWebhookSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditWebhookSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuthenticationSpec) DeepCopyInto(out *AuthenticationSpec) {
 	*out = *in
@@ -227,6 +401,11 @@ func (in *AuthenticationSpec) DeepCopyInto(out *AuthenticationSpec) {
 		*out = new(AwsAuthenticationSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(OIDCAuthenticationSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -404,6 +583,11 @@ func (in *CalicoNetworkingSpec) DeepCopyInto(out *CalicoNetworkingSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.WindowsPackages != nil {
+		in, out := &in.WindowsPackages, &out.WindowsPackages
+		*out = new(PackagesConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -960,6 +1144,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(TopologySpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SecretStoreEncryption != nil {
+		in, out := &in.SecretStoreEncryption, &out.SecretStoreEncryption
+		*out = new(SecretStoreEncryptionSpec)
+		**out = **in
+	}
 	if in.DNSControllerGossipConfig != nil {
 		in, out := &in.DNSControllerGossipConfig, &out.DNSControllerGossipConfig
 		*out = new(DNSControllerGossipConfig)
@@ -1124,6 +1313,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(MetricsServerConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.KubeStateMetrics != nil {
+		in, out := &in.KubeStateMetrics, &out.KubeStateMetrics
+		*out = new(KubeStateMetricsConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.CertManager != nil {
 		in, out := &in.CertManager, &out.CertManager
 		*out = new(CertManagerConfig)
@@ -1154,11 +1348,41 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(AuthorizationSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Audit != nil {
+		in, out := &in.Audit, &out.Audit
+		*out = new(AuditSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(LoggingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodSecurity != nil {
+		in, out := &in.PodSecurity, &out.PodSecurity
+		*out = new(PodSecuritySpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.NodeAuthorization != nil {
 		in, out := &in.NodeAuthorization, &out.NodeAuthorization
 		*out = new(NodeAuthorizationSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NodeCertificates != nil {
+		in, out := &in.NodeCertificates, &out.NodeCertificates
+		*out = new(NodeCertificatesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeBootstrap != nil {
+		in, out := &in.NodeBootstrap, &out.NodeBootstrap
+		*out = new(NodeBootstrapSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstanceGroupScaling != nil {
+		in, out := &in.InstanceGroupScaling, &out.InstanceGroupScaling
+		*out = new(InstanceGroupScalingSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.CloudLabels != nil {
 		in, out := &in.CloudLabels, &out.CloudLabels
 		*out = make(map[string]string, len(*in))
@@ -1188,6 +1412,21 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.EncryptionAtRest != nil {
+		in, out := &in.EncryptionAtRest, &out.EncryptionAtRest
+		*out = new(EncryptionAtRestSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FIPS != nil {
+		in, out := &in.FIPS, &out.FIPS
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Hardening != nil {
+		in, out := &in.Hardening, &out.Hardening
+		*out = new(HardeningSpec)
+		**out = **in
+	}
 	if in.Target != nil {
 		in, out := &in.Target, &out.Target
 		*out = new(TargetSpec)
@@ -1203,6 +1442,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.NodeTuning != nil {
+		in, out := &in.NodeTuning, &out.NodeTuning
+		*out = new(NodeTuningSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.RollingUpdate != nil {
 		in, out := &in.RollingUpdate, &out.RollingUpdate
 		*out = new(RollingUpdate)
@@ -1298,11 +1542,28 @@ func (in *ContainerdConfig) DeepCopyInto(out *ContainerdConfig) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.NRI != nil {
+		in, out := &in.NRI, &out.NRI
+		*out = new(ContainerdNRIConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NvidiaGPU != nil {
+		in, out := &in.NvidiaGPU, &out.NvidiaGPU
+		*out = new(NvidiaGPUConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Packages != nil {
 		in, out := &in.Packages, &out.Packages
 		*out = new(PackagesConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Registries != nil {
+		in, out := &in.Registries, &out.Registries
+		*out = make(map[string]ContainerdRegistryConfig, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	if in.RegistryMirrors != nil {
 		in, out := &in.RegistryMirrors, &out.RegistryMirrors
 		*out = make(map[string][]string, len(*in))
@@ -1323,6 +1584,23 @@ func (in *ContainerdConfig) DeepCopyInto(out *ContainerdConfig) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Runtimes != nil {
+		in, out := &in.Runtimes, &out.Runtimes
+		*out = make(map[string]ContainerdRuntimeConfig, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.SandboxImage != nil {
+		in, out := &in.SandboxImage, &out.SandboxImage
+		*out = new(string)
+		**out = **in
+	}
+	if in.SelinuxEnabled != nil {
+		in, out := &in.SelinuxEnabled, &out.SelinuxEnabled
+		*out = new(bool)
+		**out = **in
+	}
 	if in.State != nil {
 		in, out := &in.State, &out.State
 		*out = new(string)
@@ -1347,129 +1625,292 @@ func (in *ContainerdConfig) DeepCopy() *ContainerdConfig {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DNSAccessSpec) DeepCopyInto(out *DNSAccessSpec) {
+func (in *ContainerdNRIConfig) DeepCopyInto(out *ContainerdNRIConfig) {
 	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSAccessSpec.
-func (in *DNSAccessSpec) DeepCopy() *DNSAccessSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerdNRIConfig.
+func (in *ContainerdNRIConfig) DeepCopy() *ContainerdNRIConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(DNSAccessSpec)
+	out := new(ContainerdNRIConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DNSControllerGossipConfig) DeepCopyInto(out *DNSControllerGossipConfig) {
+func (in *ContainerdRegistryConfig) DeepCopyInto(out *ContainerdRegistryConfig) {
 	*out = *in
-	if in.Protocol != nil {
-		in, out := &in.Protocol, &out.Protocol
+	if in.Mirrors != nil {
+		in, out := &in.Mirrors, &out.Mirrors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CA != nil {
+		in, out := &in.CA, &out.CA
 		*out = new(string)
 		**out = **in
 	}
-	if in.Listen != nil {
-		in, out := &in.Listen, &out.Listen
-		*out = new(string)
+	if in.SkipVerify != nil {
+		in, out := &in.SkipVerify, &out.SkipVerify
+		*out = new(bool)
 		**out = **in
 	}
-	if in.Secret != nil {
-		in, out := &in.Secret, &out.Secret
+	if in.Username != nil {
+		in, out := &in.Username, &out.Username
 		*out = new(string)
 		**out = **in
 	}
-	if in.Secondary != nil {
-		in, out := &in.Secondary, &out.Secondary
-		*out = new(DNSControllerGossipConfigSecondary)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Seed != nil {
-		in, out := &in.Seed, &out.Seed
+	if in.Password != nil {
+		in, out := &in.Password, &out.Password
 		*out = new(string)
 		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSControllerGossipConfig.
-func (in *DNSControllerGossipConfig) DeepCopy() *DNSControllerGossipConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerdRegistryConfig.
+func (in *ContainerdRegistryConfig) DeepCopy() *ContainerdRegistryConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(DNSControllerGossipConfig)
+	out := new(ContainerdRegistryConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DNSControllerGossipConfigSecondary) DeepCopyInto(out *DNSControllerGossipConfigSecondary) {
+func (in *ContainerdRuntimeConfig) DeepCopyInto(out *ContainerdRuntimeConfig) {
 	*out = *in
-	if in.Protocol != nil {
-		in, out := &in.Protocol, &out.Protocol
-		*out = new(string)
-		**out = **in
-	}
-	if in.Listen != nil {
-		in, out := &in.Listen, &out.Listen
-		*out = new(string)
-		**out = **in
-	}
-	if in.Secret != nil {
-		in, out := &in.Secret, &out.Secret
-		*out = new(string)
-		**out = **in
-	}
-	if in.Seed != nil {
-		in, out := &in.Seed, &out.Seed
-		*out = new(string)
-		**out = **in
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSControllerGossipConfigSecondary.
-func (in *DNSControllerGossipConfigSecondary) DeepCopy() *DNSControllerGossipConfigSecondary {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerdRuntimeConfig.
+func (in *ContainerdRuntimeConfig) DeepCopy() *ContainerdRuntimeConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(DNSControllerGossipConfigSecondary)
+	out := new(ContainerdRuntimeConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DNSSpec) DeepCopyInto(out *DNSSpec) {
+func (in *CoreDNSRewriteRule) DeepCopyInto(out *CoreDNSRewriteRule) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSSpec.
-func (in *DNSSpec) DeepCopy() *DNSSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoreDNSRewriteRule.
+func (in *CoreDNSRewriteRule) DeepCopy() *CoreDNSRewriteRule {
 	if in == nil {
 		return nil
 	}
-	out := new(DNSSpec)
+	out := new(CoreDNSRewriteRule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DockerConfig) DeepCopyInto(out *DockerConfig) {
+func (in *CoreDNSServerBlock) DeepCopyInto(out *CoreDNSServerBlock) {
 	*out = *in
-	if in.AuthorizationPlugins != nil {
-		in, out := &in.AuthorizationPlugins, &out.AuthorizationPlugins
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Bridge != nil {
-		in, out := &in.Bridge, &out.Bridge
-		*out = new(string)
-		**out = **in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
 	}
-	if in.BridgeIP != nil {
-		in, out := &in.BridgeIP, &out.BridgeIP
+	if in.Forward != nil {
+		in, out := &in.Forward, &out.Forward
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoreDNSServerBlock.
+func (in *CoreDNSServerBlock) DeepCopy() *CoreDNSServerBlock {
+	if in == nil {
+		return nil
+	}
+	out := new(CoreDNSServerBlock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSAccessSpec) DeepCopyInto(out *DNSAccessSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSAccessSpec.
+func (in *DNSAccessSpec) DeepCopy() *DNSAccessSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSAccessSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSAutoscalerConfig) DeepCopyInto(out *DNSAutoscalerConfig) {
+	*out = *in
+	if in.PreventSinglePointFailure != nil {
+		in, out := &in.PreventSinglePointFailure, &out.PreventSinglePointFailure
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSAutoscalerConfig.
+func (in *DNSAutoscalerConfig) DeepCopy() *DNSAutoscalerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSAutoscalerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSControllerGossipConfig) DeepCopyInto(out *DNSControllerGossipConfig) {
+	*out = *in
+	if in.Protocol != nil {
+		in, out := &in.Protocol, &out.Protocol
+		*out = new(string)
+		**out = **in
+	}
+	if in.Listen != nil {
+		in, out := &in.Listen, &out.Listen
+		*out = new(string)
+		**out = **in
+	}
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(string)
+		**out = **in
+	}
+	if in.Secondary != nil {
+		in, out := &in.Secondary, &out.Secondary
+		*out = new(DNSControllerGossipConfigSecondary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Seed != nil {
+		in, out := &in.Seed, &out.Seed
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSControllerGossipConfig.
+func (in *DNSControllerGossipConfig) DeepCopy() *DNSControllerGossipConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSControllerGossipConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSControllerGossipConfigSecondary) DeepCopyInto(out *DNSControllerGossipConfigSecondary) {
+	*out = *in
+	if in.Protocol != nil {
+		in, out := &in.Protocol, &out.Protocol
+		*out = new(string)
+		**out = **in
+	}
+	if in.Listen != nil {
+		in, out := &in.Listen, &out.Listen
+		*out = new(string)
+		**out = **in
+	}
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(string)
+		**out = **in
+	}
+	if in.Seed != nil {
+		in, out := &in.Seed, &out.Seed
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSControllerGossipConfigSecondary.
+func (in *DNSControllerGossipConfigSecondary) DeepCopy() *DNSControllerGossipConfigSecondary {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSControllerGossipConfigSecondary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSSpec) DeepCopyInto(out *DNSSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSSpec.
+func (in *DNSSpec) DeepCopy() *DNSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DockerConfig) DeepCopyInto(out *DockerConfig) {
+	*out = *in
+	if in.AuthorizationPlugins != nil {
+		in, out := &in.AuthorizationPlugins, &out.AuthorizationPlugins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Bridge != nil {
+		in, out := &in.Bridge, &out.Bridge
+		*out = new(string)
+		**out = **in
+	}
+	if in.BridgeIP != nil {
+		in, out := &in.BridgeIP, &out.BridgeIP
 		*out = new(string)
 		**out = **in
 	}
@@ -1623,6 +2064,32 @@ func (in *EgressProxySpec) DeepCopy() *EgressProxySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EncryptionAtRestSpec) DeepCopyInto(out *EncryptionAtRestSpec) {
+	*out = *in
+	if in.PreviousKMSKeys != nil {
+		in, out := &in.PreviousKMSKeys, &out.PreviousKMSKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EncryptionAtRestSpec.
+func (in *EncryptionAtRestSpec) DeepCopy() *EncryptionAtRestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EncryptionAtRestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvVar) DeepCopyInto(out *EnvVar) {
 	*out = *in
@@ -1639,6 +2106,22 @@ func (in *EnvVar) DeepCopy() *EnvVar {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdAutoCompactionSpec) DeepCopyInto(out *EtcdAutoCompactionSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdAutoCompactionSpec.
+func (in *EtcdAutoCompactionSpec) DeepCopy() *EtcdAutoCompactionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdAutoCompactionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EtcdBackupSpec) DeepCopyInto(out *EtcdBackupSpec) {
 	*out = *in
@@ -1685,6 +2168,11 @@ func (in *EtcdClusterSpec) DeepCopyInto(out *EtcdClusterSpec) {
 		*out = new(EtcdManagerSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(EtcdExternalSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.MemoryRequest != nil {
 		in, out := &in.MemoryRequest, &out.MemoryRequest
 		x := (*in).DeepCopy()
@@ -1695,6 +2183,26 @@ func (in *EtcdClusterSpec) DeepCopyInto(out *EtcdClusterSpec) {
 		x := (*in).DeepCopy()
 		*out = &x
 	}
+	if in.QuotaBackendBytes != nil {
+		in, out := &in.QuotaBackendBytes, &out.QuotaBackendBytes
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.AutoCompaction != nil {
+		in, out := &in.AutoCompaction, &out.AutoCompaction
+		*out = new(EtcdAutoCompactionSpec)
+		**out = **in
+	}
+	if in.Defragmentation != nil {
+		in, out := &in.Defragmentation, &out.Defragmentation
+		*out = new(EtcdDefragmentationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(EtcdMetricsSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1735,6 +2243,48 @@ func (in *EtcdClusterStatus) DeepCopy() *EtcdClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdDefragmentationSpec) DeepCopyInto(out *EtcdDefragmentationSpec) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdDefragmentationSpec.
+func (in *EtcdDefragmentationSpec) DeepCopy() *EtcdDefragmentationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdDefragmentationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdExternalSpec) DeepCopyInto(out *EtcdExternalSpec) {
+	*out = *in
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdExternalSpec.
+func (in *EtcdExternalSpec) DeepCopy() *EtcdExternalSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdExternalSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EtcdManagerSpec) DeepCopyInto(out *EtcdManagerSpec) {
 	*out = *in
@@ -1833,6 +2383,27 @@ func (in *EtcdMemberStatus) DeepCopy() *EtcdMemberStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdMetricsSpec) DeepCopyInto(out *EtcdMetricsSpec) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdMetricsSpec.
+func (in *EtcdMetricsSpec) DeepCopy() *EtcdMetricsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdMetricsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExecContainerAction) DeepCopyInto(out *ExecContainerAction) {
 	*out = *in
@@ -1869,6 +2440,16 @@ func (in *ExternalDNSConfig) DeepCopyInto(out *ExternalDNSConfig) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.DomainFilters != nil {
+		in, out := &in.DomainFilters, &out.DomainFilters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ZoneIDFilters != nil {
+		in, out := &in.ZoneIDFilters, &out.ZoneIDFilters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1956,6 +2537,22 @@ func (in *GCENetworkingSpec) DeepCopy() *GCENetworkingSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayAccessSpec) DeepCopyInto(out *GatewayAccessSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayAccessSpec.
+func (in *GatewayAccessSpec) DeepCopy() *GatewayAccessSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayAccessSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GossipConfig) DeepCopyInto(out *GossipConfig) {
 	*out = *in
@@ -2023,6 +2620,27 @@ func (in *GossipConfigSecondary) DeepCopy() *GossipConfigSecondary {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GracefulShutdownSpec) DeepCopyInto(out *GracefulShutdownSpec) {
+	*out = *in
+	if in.CloudEvents != nil {
+		in, out := &in.CloudEvents, &out.CloudEvents
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GracefulShutdownSpec.
+func (in *GracefulShutdownSpec) DeepCopy() *GracefulShutdownSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GracefulShutdownSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPProxy) DeepCopyInto(out *HTTPProxy) {
 	*out = *in
@@ -2039,6 +2657,22 @@ func (in *HTTPProxy) DeepCopy() *HTTPProxy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardeningSpec) DeepCopyInto(out *HardeningSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardeningSpec.
+func (in *HardeningSpec) DeepCopy() *HardeningSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HardeningSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HookSpec) DeepCopyInto(out *HookSpec) {
 	*out = *in
@@ -2150,6 +2784,53 @@ func (in *IAMSpec) DeepCopy() *IAMSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePreloadSpec) DeepCopyInto(out *ImagePreloadSpec) {
+	*out = *in
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AddonImages != nil {
+		in, out := &in.AddonImages, &out.AddonImages
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PullThroughCache != nil {
+		in, out := &in.PullThroughCache, &out.PullThroughCache
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePreloadSpec.
+func (in *ImagePreloadSpec) DeepCopy() *ImagePreloadSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePreloadSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceAccessSpec) DeepCopyInto(out *InstanceAccessSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceAccessSpec.
+func (in *InstanceAccessSpec) DeepCopy() *InstanceAccessSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceAccessSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstanceGroup) DeepCopyInto(out *InstanceGroup) {
 	*out = *in
@@ -2177,6 +2858,22 @@ func (in *InstanceGroup) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceGroupBootstrapSpec) DeepCopyInto(out *InstanceGroupBootstrapSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceGroupBootstrapSpec.
+func (in *InstanceGroupBootstrapSpec) DeepCopy() *InstanceGroupBootstrapSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceGroupBootstrapSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstanceGroupList) DeepCopyInto(out *InstanceGroupList) {
 	*out = *in
@@ -2210,6 +2907,27 @@ func (in *InstanceGroupList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceGroupScalingSpec) DeepCopyInto(out *InstanceGroupScalingSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceGroupScalingSpec.
+func (in *InstanceGroupScalingSpec) DeepCopy() *InstanceGroupScalingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceGroupScalingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstanceGroupSpec) DeepCopyInto(out *InstanceGroupSpec) {
 	*out = *in
@@ -2432,6 +3150,88 @@ func (in *InstanceGroupSpec) DeepCopyInto(out *InstanceGroupSpec) {
 		*out = new(WarmPoolSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ShieldedInstanceConfig != nil {
+		in, out := &in.ShieldedInstanceConfig, &out.ShieldedInstanceConfig
+		*out = new(ShieldedInstanceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfidentialCompute != nil {
+		in, out := &in.ConfidentialCompute, &out.ConfidentialCompute
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EphemeralOSDisk != nil {
+		in, out := &in.EphemeralOSDisk, &out.EphemeralOSDisk
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AcceleratedNetworking != nil {
+		in, out := &in.AcceleratedNetworking, &out.AcceleratedNetworking
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Machines != nil {
+		in, out := &in.Machines, &out.Machines
+		*out = make([]MetalMachineSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Bootstrap != nil {
+		in, out := &in.Bootstrap, &out.Bootstrap
+		*out = new(InstanceGroupBootstrapSpec)
+		**out = **in
+	}
+	if in.NvidiaGPU != nil {
+		in, out := &in.NvidiaGPU, &out.NvidiaGPU
+		*out = new(NvidiaGPUConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Swap != nil {
+		in, out := &in.Swap, &out.Swap
+		*out = new(SwapSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeTuning != nil {
+		in, out := &in.NodeTuning, &out.NodeTuning
+		*out = new(NodeTuningSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstanceStorage != nil {
+		in, out := &in.InstanceStorage, &out.InstanceStorage
+		*out = new(InstanceStorageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GracefulShutdown != nil {
+		in, out := &in.GracefulShutdown, &out.GracefulShutdown
+		*out = new(GracefulShutdownSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Containerd != nil {
+		in, out := &in.Containerd, &out.Containerd
+		*out = new(ContainerdConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImagePreload != nil {
+		in, out := &in.ImagePreload, &out.ImagePreload
+		*out = new(ImagePreloadSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityModules != nil {
+		in, out := &in.SecurityModules, &out.SecurityModules
+		*out = new(SecurityModulesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScalingSchedules != nil {
+		in, out := &in.ScalingSchedules, &out.ScalingSchedules
+		*out = make([]ScalingSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.APIServerEndpoint != nil {
+		in, out := &in.APIServerEndpoint, &out.APIServerEndpoint
+		*out = new(APIServerEndpointSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -2471,6 +3271,27 @@ func (in *InstanceMetadataOptions) DeepCopy() *InstanceMetadataOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceStorageSpec) DeepCopyInto(out *InstanceStorageSpec) {
+	*out = *in
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceStorageSpec.
+func (in *InstanceStorageSpec) DeepCopy() *InstanceStorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceStorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Keyset) DeepCopyInto(out *Keyset) {
 	*out = *in
@@ -3224,6 +4045,23 @@ func (in *KubeDNSConfig) DeepCopyInto(out *KubeDNSConfig) {
 		*out = new(NodeLocalDNSConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ServerBlocks != nil {
+		in, out := &in.ServerBlocks, &out.ServerBlocks
+		*out = make([]CoreDNSServerBlock, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Rewrites != nil {
+		in, out := &in.Rewrites, &out.Rewrites
+		*out = make([]CoreDNSRewriteRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.Autoscaler != nil {
+		in, out := &in.Autoscaler, &out.Autoscaler
+		*out = new(DNSAutoscalerConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -3343,12 +4181,38 @@ func (in *KubeSchedulerConfig) DeepCopyInto(out *KubeSchedulerConfig) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeSchedulerConfig.
-func (in *KubeSchedulerConfig) DeepCopy() *KubeSchedulerConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeSchedulerConfig.
+func (in *KubeSchedulerConfig) DeepCopy() *KubeSchedulerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeSchedulerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeStateMetricsConfig) DeepCopyInto(out *KubeStateMetricsConfig) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeStateMetricsConfig.
+func (in *KubeStateMetricsConfig) DeepCopy() *KubeStateMetricsConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeSchedulerConfig)
+	out := new(KubeStateMetricsConfig)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -3609,6 +4473,33 @@ func (in *KubeletConfigSpec) DeepCopyInto(out *KubeletConfigSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.MemoryQoS != nil {
+		in, out := &in.MemoryQoS, &out.MemoryQoS
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ReservedMemory != nil {
+		in, out := &in.ReservedMemory, &out.ReservedMemory
+		*out = make([]KubeletReservedMemory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ShutdownGracePeriod != nil {
+		in, out := &in.ShutdownGracePeriod, &out.ShutdownGracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ShutdownGracePeriodCriticalPods != nil {
+		in, out := &in.ShutdownGracePeriodCriticalPods, &out.ShutdownGracePeriodCriticalPods
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ShutdownGracePeriodByPodPriority != nil {
+		in, out := &in.ShutdownGracePeriodByPodPriority, &out.ShutdownGracePeriodByPodPriority
+		*out = make([]KubeletShutdownGracePeriodByPodPriority, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -3622,6 +4513,45 @@ func (in *KubeletConfigSpec) DeepCopy() *KubeletConfigSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeletReservedMemory) DeepCopyInto(out *KubeletReservedMemory) {
+	*out = *in
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = make(map[string]resource.Quantity, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletReservedMemory.
+func (in *KubeletReservedMemory) DeepCopy() *KubeletReservedMemory {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeletReservedMemory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeletShutdownGracePeriodByPodPriority) DeepCopyInto(out *KubeletShutdownGracePeriodByPodPriority) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletShutdownGracePeriodByPodPriority.
+func (in *KubeletShutdownGracePeriodByPodPriority) DeepCopy() *KubeletShutdownGracePeriodByPodPriority {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeletShutdownGracePeriodByPodPriority)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubenetNetworkingSpec) DeepCopyInto(out *KubenetNetworkingSpec) {
 	*out = *in
@@ -3782,6 +4712,11 @@ func (in *LoadBalancerAccessSpec) DeepCopyInto(out *LoadBalancerAccessSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PrivateLink != nil {
+		in, out := &in.PrivateLink, &out.PrivateLink
+		*out = new(PrivateLinkSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -3821,6 +4756,111 @@ func (in *LoadBalancerSubnetSpec) DeepCopy() *LoadBalancerSubnetSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoggingCloudWatchSpec) DeepCopyInto(out *LoggingCloudWatchSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoggingCloudWatchSpec.
+func (in *LoggingCloudWatchSpec) DeepCopy() *LoggingCloudWatchSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LoggingCloudWatchSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoggingLokiSpec) DeepCopyInto(out *LoggingLokiSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoggingLokiSpec.
+func (in *LoggingLokiSpec) DeepCopy() *LoggingLokiSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LoggingLokiSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoggingS3Spec) DeepCopyInto(out *LoggingS3Spec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoggingS3Spec.
+func (in *LoggingS3Spec) DeepCopy() *LoggingS3Spec {
+	if in == nil {
+		return nil
+	}
+	out := new(LoggingS3Spec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoggingSpec) DeepCopyInto(out *LoggingSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CloudWatch != nil {
+		in, out := &in.CloudWatch, &out.CloudWatch
+		*out = new(LoggingCloudWatchSpec)
+		**out = **in
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(LoggingS3Spec)
+		**out = **in
+	}
+	if in.Stackdriver != nil {
+		in, out := &in.Stackdriver, &out.Stackdriver
+		*out = new(LoggingStackdriverSpec)
+		**out = **in
+	}
+	if in.Loki != nil {
+		in, out := &in.Loki, &out.Loki
+		*out = new(LoggingLokiSpec)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoggingSpec.
+func (in *LoggingSpec) DeepCopy() *LoggingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LoggingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoggingStackdriverSpec) DeepCopyInto(out *LoggingStackdriverSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoggingStackdriverSpec.
+func (in *LoggingStackdriverSpec) DeepCopy() *LoggingStackdriverSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LoggingStackdriverSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LyftVPCNetworkingSpec) DeepCopyInto(out *LyftVPCNetworkingSpec) {
 	*out = *in
@@ -3844,6 +4884,22 @@ func (in *LyftVPCNetworkingSpec) DeepCopy() *LyftVPCNetworkingSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetalMachineSpec) DeepCopyInto(out *MetalMachineSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetalMachineSpec.
+func (in *MetalMachineSpec) DeepCopy() *MetalMachineSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MetalMachineSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetricsServerConfig) DeepCopyInto(out *MetricsServerConfig) {
 	*out = *in
@@ -4020,6 +5076,11 @@ func (in *NetworkingSpec) DeepCopyInto(out *NetworkingSpec) {
 		*out = new(GCENetworkingSpec)
 		**out = **in
 	}
+	if in.Antrea != nil {
+		in, out := &in.Antrea, &out.Antrea
+		*out = new(AntreaNetworkingSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -4090,6 +5151,53 @@ func (in *NodeAuthorizerSpec) DeepCopy() *NodeAuthorizerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeBootstrapSpec) DeepCopyInto(out *NodeBootstrapSpec) {
+	*out = *in
+	if in.UseKopsController != nil {
+		in, out := &in.UseKopsController, &out.UseKopsController
+		*out = new(bool)
+		**out = **in
+	}
+	if in.JoinTokens != nil {
+		in, out := &in.JoinTokens, &out.JoinTokens
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeBootstrapSpec.
+func (in *NodeBootstrapSpec) DeepCopy() *NodeBootstrapSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeBootstrapSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeCertificatesSpec) DeepCopyInto(out *NodeCertificatesSpec) {
+	*out = *in
+	if in.KubeletValidity != nil {
+		in, out := &in.KubeletValidity, &out.KubeletValidity
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeCertificatesSpec.
+func (in *NodeCertificatesSpec) DeepCopy() *NodeCertificatesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeCertificatesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeLocalDNSConfig) DeepCopyInto(out *NodeLocalDNSConfig) {
 	*out = *in
@@ -4113,6 +5221,21 @@ func (in *NodeLocalDNSConfig) DeepCopyInto(out *NodeLocalDNSConfig) {
 		x := (*in).DeepCopy()
 		*out = &x
 	}
+	if in.UpstreamZones != nil {
+		in, out := &in.UpstreamZones, &out.UpstreamZones
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
 	return
 }
 
@@ -4154,30 +5277,126 @@ func (in *NodeTerminationHandlerConfig) DeepCopyInto(out *NodeTerminationHandler
 		*out = new(bool)
 		**out = **in
 	}
-	if in.ManagedASGTag != nil {
-		in, out := &in.ManagedASGTag, &out.ManagedASGTag
+	if in.ManagedASGTag != nil {
+		in, out := &in.ManagedASGTag, &out.ManagedASGTag
+		*out = new(string)
+		**out = **in
+	}
+	if in.MemoryRequest != nil {
+		in, out := &in.MemoryRequest, &out.MemoryRequest
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.CPURequest != nil {
+		in, out := &in.CPURequest, &out.CPURequest
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeTerminationHandlerConfig.
+func (in *NodeTerminationHandlerConfig) DeepCopy() *NodeTerminationHandlerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeTerminationHandlerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeTuningSpec) DeepCopyInto(out *NodeTuningSpec) {
+	*out = *in
+	if in.Sysctls != nil {
+		in, out := &in.Sysctls, &out.Sysctls
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.KernelModules != nil {
+		in, out := &in.KernelModules, &out.KernelModules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HugePages != nil {
+		in, out := &in.HugePages, &out.HugePages
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.UdevRules != nil {
+		in, out := &in.UdevRules, &out.UdevRules
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeTuningSpec.
+func (in *NodeTuningSpec) DeepCopy() *NodeTuningSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeTuningSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NvidiaGPUConfig) DeepCopyInto(out *NvidiaGPUConfig) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DriverPackage != nil {
+		in, out := &in.DriverPackage, &out.DriverPackage
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NvidiaGPUConfig.
+func (in *NvidiaGPUConfig) DeepCopy() *NvidiaGPUConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NvidiaGPUConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCAuthenticationSpec) DeepCopyInto(out *OIDCAuthenticationSpec) {
+	*out = *in
+	if in.UsernamePrefix != nil {
+		in, out := &in.UsernamePrefix, &out.UsernamePrefix
 		*out = new(string)
 		**out = **in
 	}
-	if in.MemoryRequest != nil {
-		in, out := &in.MemoryRequest, &out.MemoryRequest
-		x := (*in).DeepCopy()
-		*out = &x
-	}
-	if in.CPURequest != nil {
-		in, out := &in.CPURequest, &out.CPURequest
-		x := (*in).DeepCopy()
-		*out = &x
+	if in.RequiredClaims != nil {
+		in, out := &in.RequiredClaims, &out.RequiredClaims
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeTerminationHandlerConfig.
-func (in *NodeTerminationHandlerConfig) DeepCopy() *NodeTerminationHandlerConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCAuthenticationSpec.
+func (in *OIDCAuthenticationSpec) DeepCopy() *OIDCAuthenticationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeTerminationHandlerConfig)
+	out := new(OIDCAuthenticationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -4312,6 +5531,11 @@ func (in *OpenstackLoadbalancerConfig) DeepCopyInto(out *OpenstackLoadbalancerCo
 		*out = new(bool)
 		**out = **in
 	}
+	if in.FloatingIP != nil {
+		in, out := &in.FloatingIP, &out.FloatingIP
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -4448,6 +5672,16 @@ func (in *PackagesConfig) DeepCopyInto(out *PackagesConfig) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.HashWindows != nil {
+		in, out := &in.HashWindows, &out.HashWindows
+		*out = new(string)
+		**out = **in
+	}
+	if in.UrlWindows != nil {
+		in, out := &in.UrlWindows, &out.UrlWindows
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -4461,6 +5695,96 @@ func (in *PackagesConfig) DeepCopy() *PackagesConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodSecuritySpec) DeepCopyInto(out *PodSecuritySpec) {
+	*out = *in
+	if in.ExemptNamespaces != nil {
+		in, out := &in.ExemptNamespaces, &out.ExemptNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExemptUsernames != nil {
+		in, out := &in.ExemptUsernames, &out.ExemptUsernames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExemptRuntimeClasses != nil {
+		in, out := &in.ExemptRuntimeClasses, &out.ExemptRuntimeClasses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSecuritySpec.
+func (in *PodSecuritySpec) DeepCopy() *PodSecuritySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PodSecuritySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrivateLinkEndpointSpec) DeepCopyInto(out *PrivateLinkEndpointSpec) {
+	*out = *in
+	if in.SubnetIDs != nil {
+		in, out := &in.SubnetIDs, &out.SubnetIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecurityGroupIDs != nil {
+		in, out := &in.SecurityGroupIDs, &out.SecurityGroupIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrivateLinkEndpointSpec.
+func (in *PrivateLinkEndpointSpec) DeepCopy() *PrivateLinkEndpointSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrivateLinkEndpointSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrivateLinkSpec) DeepCopyInto(out *PrivateLinkSpec) {
+	*out = *in
+	if in.AllowedPrincipals != nil {
+		in, out := &in.AllowedPrincipals, &out.AllowedPrincipals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AcceptanceRequired != nil {
+		in, out := &in.AcceptanceRequired, &out.AcceptanceRequired
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]PrivateLinkEndpointSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrivateLinkSpec.
+func (in *PrivateLinkSpec) DeepCopy() *PrivateLinkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrivateLinkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RBACAuthorizationSpec) DeepCopyInto(out *RBACAuthorizationSpec) {
 	*out = *in
@@ -4495,6 +5819,11 @@ func (in *RollingUpdate) DeepCopyInto(out *RollingUpdate) {
 		*out = new(intstr.IntOrString)
 		**out = **in
 	}
+	if in.DrainTimeout != nil {
+		in, out := &in.DrainTimeout, &out.DrainTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	return
 }
 
@@ -4600,6 +5929,79 @@ func (in *SSHCredentialSpec) DeepCopy() *SSHCredentialSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScalingSchedule) DeepCopyInto(out *ScalingSchedule) {
+	*out = *in
+	if in.MinSize != nil {
+		in, out := &in.MinSize, &out.MinSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalingSchedule.
+func (in *ScalingSchedule) DeepCopy() *ScalingSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalingSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreEncryptionSpec) DeepCopyInto(out *SecretStoreEncryptionSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreEncryptionSpec.
+func (in *SecretStoreEncryptionSpec) DeepCopy() *SecretStoreEncryptionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStoreEncryptionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityModulesSpec) DeepCopyInto(out *SecurityModulesSpec) {
+	*out = *in
+	if in.SELinux != nil {
+		in, out := &in.SELinux, &out.SELinux
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AppArmor != nil {
+		in, out := &in.AppArmor, &out.AppArmor
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityModulesSpec.
+func (in *SecurityModulesSpec) DeepCopy() *SecurityModulesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityModulesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceAccountExternalPermission) DeepCopyInto(out *ServiceAccountExternalPermission) {
 	*out = *in
@@ -4637,6 +6039,37 @@ func (in *ServiceAccountIssuerDiscoveryConfig) DeepCopy() *ServiceAccountIssuerD
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShieldedInstanceConfig) DeepCopyInto(out *ShieldedInstanceConfig) {
+	*out = *in
+	if in.EnableSecureBoot != nil {
+		in, out := &in.EnableSecureBoot, &out.EnableSecureBoot
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableVTPM != nil {
+		in, out := &in.EnableVTPM, &out.EnableVTPM
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableIntegrityMonitoring != nil {
+		in, out := &in.EnableIntegrityMonitoring, &out.EnableIntegrityMonitoring
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShieldedInstanceConfig.
+func (in *ShieldedInstanceConfig) DeepCopy() *ShieldedInstanceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ShieldedInstanceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SnapshotControllerConfig) DeepCopyInto(out *SnapshotControllerConfig) {
 	*out = *in
@@ -4658,6 +6091,37 @@ func (in *SnapshotControllerConfig) DeepCopy() *SnapshotControllerConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwapSpec) DeepCopyInto(out *SwapSpec) {
+	*out = *in
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Swappiness != nil {
+		in, out := &in.Swappiness, &out.Swappiness
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Encrypted != nil {
+		in, out := &in.Encrypted, &out.Encrypted
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwapSpec.
+func (in *SwapSpec) DeepCopy() *SwapSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SwapSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TargetSpec) DeepCopyInto(out *TargetSpec) {
 	*out = *in
@@ -4679,6 +6143,69 @@ func (in *TargetSpec) DeepCopy() *TargetSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TerraformBackendSpec) DeepCopyInto(out *TerraformBackendSpec) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(TerraformS3BackendSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GCS != nil {
+		in, out := &in.GCS, &out.GCS
+		*out = new(TerraformGCSBackendSpec)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TerraformBackendSpec.
+func (in *TerraformBackendSpec) DeepCopy() *TerraformBackendSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TerraformBackendSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TerraformGCSBackendSpec) DeepCopyInto(out *TerraformGCSBackendSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TerraformGCSBackendSpec.
+func (in *TerraformGCSBackendSpec) DeepCopy() *TerraformGCSBackendSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TerraformGCSBackendSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TerraformS3BackendSpec) DeepCopyInto(out *TerraformS3BackendSpec) {
+	*out = *in
+	if in.Encrypt != nil {
+		in, out := &in.Encrypt, &out.Encrypt
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TerraformS3BackendSpec.
+func (in *TerraformS3BackendSpec) DeepCopy() *TerraformS3BackendSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TerraformS3BackendSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TerraformSpec) DeepCopyInto(out *TerraformSpec) {
 	*out = *in
@@ -4693,6 +6220,28 @@ func (in *TerraformSpec) DeepCopyInto(out *TerraformSpec) {
 			}
 		}
 	}
+	if in.ProviderVersions != nil {
+		in, out := &in.ProviderVersions, &out.ProviderVersions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Backend != nil {
+		in, out := &in.Backend, &out.Backend
+		*out = new(TerraformBackendSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SplitFiles != nil {
+		in, out := &in.SplitFiles, &out.SplitFiles
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ImportExisting != nil {
+		in, out := &in.ImportExisting, &out.ImportExisting
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -4719,6 +6268,11 @@ func (in *TopologySpec) DeepCopyInto(out *TopologySpec) {
 		*out = new(DNSSpec)
 		**out = **in
 	}
+	if in.InstanceAccess != nil {
+		in, out := &in.InstanceAccess, &out.InstanceAccess
+		*out = new(InstanceAccessSpec)
+		**out = **in
+	}
 	return
 }
 
@@ -18,7 +18,9 @@ package nodeup
 
 import (
 	"strings"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/nodelabels"
 	"k8s.io/kops/upup/pkg/fi"
@@ -47,6 +49,8 @@ type Config struct {
 	Channels []string `json:"channels,omitempty"`
 	// ApiserverAdditionalIPs are additional IP address to put in the apiserver server cert.
 	ApiserverAdditionalIPs []string `json:",omitempty"`
+	// GossipSeeds are the addresses of the control plane machines, for clouds where they cannot be discovered.
+	GossipSeeds []string `json:",omitempty"`
 
 	// Manifests for running etcd
 	EtcdManifests []string `json:"etcdManifests,omitempty"`
@@ -64,11 +68,40 @@ type Config struct {
 	SysctlParameters []string `json:",omitempty"`
 	// VolumeMounts are a collection of volume mounts.
 	VolumeMounts []kops.VolumeMountSpec `json:",omitempty"`
+	// InstanceStorage configures the local instance store devices.
+	InstanceStorage *kops.InstanceStorageSpec `json:",omitempty"`
+	// NvidiaGPU is the configuration for the NVIDIA GPUs of the instances, if they have any.
+	NvidiaGPU *kops.NvidiaGPUConfig `json:"nvidiaGPU,omitempty"`
+	// Swap is the configuration for the swap space of the instances.
+	Swap *kops.SwapSpec `json:"swap,omitempty"`
+	// NodeTuning is the kernel configuration of the instances, merged from the cluster and instance group specs.
+	NodeTuning *kops.NodeTuningSpec `json:"nodeTuning,omitempty"`
+	// GracefulShutdown is the configuration for the graceful shutdown of the instances.
+	GracefulShutdown *kops.GracefulShutdownSpec `json:"gracefulShutdown,omitempty"`
+	// Containerd holds the structured containerd settings of the instances, merged from the cluster and instance group specs.
+	Containerd *kops.ContainerdConfig `json:"containerd,omitempty"`
+	// ImagePreload is the configuration for pulling images when the instances boot, including the addon images to pull.
+	ImagePreload *kops.ImagePreloadSpec `json:"imagePreload,omitempty"`
+	// SecurityModules are the Linux security modules enforced on the instances.
+	SecurityModules *kops.SecurityModulesSpec `json:"securityModules,omitempty"`
+
+	// APIServerEndpoint holds the addresses that the internal API server and kops-controller names are pinned to.
+	APIServerEndpoint *APIServerEndpoint `json:"apiServerEndpoint,omitempty"`
 
 	// ConfigServer holds the configuration for the configuration server
 	ConfigServer *ConfigServerOptions `json:"configServer,omitempty"`
 }
 
+// APIServerEndpoint holds the addresses that nodeup pins the internal API server and kops-controller names to in /etc/hosts
+type APIServerEndpoint struct {
+	// Hostnames are the names that are pinned to the addresses.
+	Hostnames []string `json:"hostnames,omitempty"`
+	// Resolve is the name that nodeup resolves to find the addresses, e.g. the name of the public API load balancer.
+	Resolve string `json:"resolve,omitempty"`
+	// IPs are the addresses, when Resolve is not set.
+	IPs []string `json:"ips,omitempty"`
+}
+
 type ConfigServerOptions struct {
 	// Server is the address of the configuration server to use (kops-controller)
 	Server string `json:"server,omitempty"`
@@ -105,6 +138,8 @@ func NewConfig(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) *Config
 		InstanceGroupRole: role,
 		SysctlParameters:  instanceGroup.Spec.SysctlParameters,
 		VolumeMounts:      instanceGroup.Spec.VolumeMounts,
+		InstanceStorage:   instanceGroup.Spec.InstanceStorage,
+		NodeTuning:        instanceGroup.NodeTuningConfig(cluster),
 	}
 
 	if isMaster {
@@ -134,9 +169,93 @@ func NewConfig(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) *Config
 
 	config.KubeletConfig.Taints = append(config.KubeletConfig.Taints, instanceGroup.Spec.Taints...)
 
+	if nvidiaGPU := instanceGroup.NvidiaGPUConfig(cluster); nvidiaGPU != nil {
+		config.NvidiaGPU = nvidiaGPU
+
+		hasTaint := false
+		for _, taint := range config.KubeletConfig.Taints {
+			if strings.HasPrefix(taint, nodelabels.NvidiaGPUTaint+"=") || strings.HasPrefix(taint, nodelabels.NvidiaGPUTaint+":") {
+				hasTaint = true
+			}
+		}
+		if !hasTaint {
+			config.KubeletConfig.Taints = append(config.KubeletConfig.Taints, nodelabels.NvidiaGPUTaint+"=present:NoSchedule")
+		}
+	}
+
+	if instanceGroup.Spec.Swap != nil {
+		config.Swap = instanceGroup.Spec.Swap
+
+		// The kubelet refuses to start on a node with swap enabled unless told otherwise.
+		config.KubeletConfig.FailSwapOn = fi.Bool(false)
+		setDefaultFeatureGate(&config.KubeletConfig, "NodeSwap")
+		if config.KubeletConfig.MemorySwapBehavior == "" {
+			config.KubeletConfig.MemorySwapBehavior = "LimitedSwap"
+		}
+	}
+
+	config.Containerd = instanceGroup.ContainerdConfig(cluster)
+	config.ImagePreload = instanceGroup.Spec.ImagePreload.DeepCopy()
+	config.SecurityModules = instanceGroup.Spec.SecurityModules.DeepCopy()
+
+	if instanceGroup.Spec.GracefulShutdown != nil {
+		config.GracefulShutdown = instanceGroup.Spec.GracefulShutdown
+
+		// Graceful shutdown needs the kubelet to delay the shutdown; unless configured, the delay fits in the
+		// notice the cloud provider gives before an interruption.
+		kubelet := &config.KubeletConfig
+		if kubelet.ShutdownGracePeriod == nil && len(kubelet.ShutdownGracePeriodByPodPriority) == 0 {
+			gracePeriod, criticalPodsGracePeriod := 90*time.Second, 30*time.Second
+			if kops.CloudProviderID(cluster.Spec.CloudProvider) == kops.CloudProviderGCE {
+				// Preemptible instances are stopped 30 seconds after they are preempted
+				gracePeriod, criticalPodsGracePeriod = 25*time.Second, 10*time.Second
+			}
+			kubelet.ShutdownGracePeriod = &metav1.Duration{Duration: gracePeriod}
+			if kubelet.ShutdownGracePeriodCriticalPods == nil {
+				kubelet.ShutdownGracePeriodCriticalPods = &metav1.Duration{Duration: criticalPodsGracePeriod}
+			}
+		}
+	}
+
+	if fi.BoolValue(config.KubeletConfig.MemoryQoS) {
+		setDefaultFeatureGate(&config.KubeletConfig, "MemoryQoS")
+	}
+
+	// Graceful node shutdown by pod priority is alpha, so it is not enabled by default.
+	if len(config.KubeletConfig.ShutdownGracePeriodByPodPriority) != 0 {
+		setDefaultFeatureGate(&config.KubeletConfig, "GracefulNodeShutdownBasedOnPodPriority")
+	}
+
+	if endpoint := instanceGroup.Spec.APIServerEndpoint; endpoint != nil {
+		hostnames := []string{cluster.Spec.MasterInternalName, "kops-controller.internal." + cluster.ObjectMeta.Name}
+		switch endpoint.Type {
+		case kops.APIServerEndpointTypeExternal:
+			config.APIServerEndpoint = &APIServerEndpoint{
+				Hostnames: hostnames,
+				Resolve:   cluster.Spec.MasterPublicName,
+			}
+		case kops.APIServerEndpointTypeIPs:
+			config.APIServerEndpoint = &APIServerEndpoint{
+				Hostnames: hostnames,
+				IPs:       endpoint.IPs,
+			}
+		}
+	}
+
 	if cluster.Spec.Networking != nil && cluster.Spec.Networking.AmazonVPC != nil {
 		config.DefaultMachineType = fi.String(strings.Split(instanceGroup.Spec.MachineType, ",")[0])
 	}
 
 	return &config
 }
+
+// setDefaultFeatureGate enables a kubelet feature gate, unless it has been set explicitly.
+func setDefaultFeatureGate(kubelet *kops.KubeletConfigSpec, name string) {
+	if _, found := kubelet.FeatureGates[name]; found {
+		return
+	}
+	if kubelet.FeatureGates == nil {
+		kubelet.FeatureGates = make(map[string]string)
+	}
+	kubelet.FeatureGates[name] = "true"
+}
@@ -21,11 +21,13 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/blang/semver/v4"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
@@ -44,11 +46,30 @@ type AssetBuilder struct {
 	AssetsLocation *kops.Assets
 	GetAssets      bool
 
+	// BundleDirectory is an extracted bundle, from which the hashes of the files are read when getting the assets,
+	// so that the assets of an air-gapped cluster can be copied without internet access
+	BundleDirectory string
+
+	// FIPS selects the BoringCrypto builds of the kOps binaries and images
+	FIPS bool
+
 	// KubernetesVersion is the version of kubernetes we are installing
 	KubernetesVersion semver.Version
 
 	// StaticManifests records static manifests
 	StaticManifests []*StaticManifest
+
+	// AddonImages records the images of the manifests passed through RemapManifest
+	AddonImages []*AddonImage
+}
+
+// AddonImage is an image run by an addon, which instance groups can preload
+type AddonImage struct {
+	// Image is the image, as remapped
+	Image string
+
+	// The image will only be preloaded on instances matching the specified roles; all instances if empty
+	Roles []kops.InstanceGroupRole
 }
 
 type StaticManifest struct {
@@ -86,6 +107,7 @@ func NewAssetBuilder(cluster *kops.Cluster, getAssets bool) *AssetBuilder {
 	a := &AssetBuilder{
 		AssetsLocation: cluster.Spec.Assets,
 		GetAssets:      getAssets,
+		FIPS:           values.BoolValue(cluster.Spec.FIPS),
 	}
 
 	version, err := util.ParseKubernetesVersion(cluster.Spec.KubernetesVersion)
@@ -112,18 +134,62 @@ func (a *AssetBuilder) RemapManifest(data []byte) ([]byte, error) {
 		if err := object.RemapImages(a.RemapImage); err != nil {
 			return nil, fmt.Errorf("error remapping images: %v", err)
 		}
+		if err := a.recordAddonImages(object); err != nil {
+			return nil, err
+		}
 	}
 
 	return objects.ToYAML()
 }
 
+// recordAddonImages records the images of the pods of the object, and whether the pods only run on the control plane
+func (a *AssetBuilder) recordAddonImages(object *kubemanifest.Object) error {
+	var fields []string
+	switch object.Kind() {
+	case "Pod":
+		fields = []string{"spec"}
+	case "DaemonSet", "Deployment", "Job", "ReplicaSet", "StatefulSet":
+		fields = []string{"spec", "template", "spec"}
+	case "CronJob":
+		fields = []string{"spec", "jobTemplate", "spec", "template", "spec"}
+	default:
+		return nil
+	}
+
+	podSpec := &corev1.PodSpec{}
+	if err := object.Reparse(podSpec, fields...); err != nil {
+		return fmt.Errorf("error parsing pod spec of %s: %v", object.Kind(), err)
+	}
+
+	var roles []kops.InstanceGroupRole
+	for key := range podSpec.NodeSelector {
+		if key == "node-role.kubernetes.io/master" || key == "node-role.kubernetes.io/control-plane" {
+			roles = []kops.InstanceGroupRole{kops.InstanceGroupRoleMaster}
+		}
+	}
+
+	for _, container := range append(podSpec.InitContainers, podSpec.Containers...) {
+		a.AddonImages = append(a.AddonImages, &AddonImage{
+			Image: container.Image,
+			Roles: roles,
+		})
+	}
+	return nil
+}
+
 // RemapImage normalizes a containers location if a user sets the AssetsLocation ContainerRegistry location.
 func (a *AssetBuilder) RemapImage(image string) (string, error) {
+	image = a.fipsImage(image)
+
 	asset := &ImageAsset{
 		DownloadLocation:  image,
 		CanonicalLocation: image,
 	}
 
+	if a.airGapped() && a.AssetsLocation.ContainerRegistry == nil {
+		return "", fmt.Errorf("assets.containerRegistry must be set to remap image %q of an air-gapped cluster", image)
+	}
+
 	if strings.HasPrefix(image, "k8s.gcr.io/kops/dns-controller:") {
 		// To use user-defined DNS Controller:
 		// 1. DOCKER_REGISTRY=[your docker hub repo] make dns-controller-push
@@ -211,7 +277,7 @@ func (a *AssetBuilder) RemapFileAndSHA(fileURL *url.URL) (*url.URL, *hashing.Has
 		CanonicalURL: fileURL,
 	}
 
-	if a.AssetsLocation != nil && a.AssetsLocation.FileRepository != nil {
+	if a.AssetsLocation != nil && (a.AssetsLocation.FileRepository != nil || a.airGapped()) {
 
 		normalizedFileURL, err := a.remapURL(fileURL)
 		if err != nil {
@@ -247,7 +313,7 @@ func (a *AssetBuilder) RemapFileAndSHAValue(fileURL *url.URL, shaValue string) (
 		SHAValue:     shaValue,
 	}
 
-	if a.AssetsLocation != nil && a.AssetsLocation.FileRepository != nil {
+	if a.AssetsLocation != nil && (a.AssetsLocation.FileRepository != nil || a.airGapped()) {
 		normalizedFile, err := a.remapURL(fileURL)
 		if err != nil {
 			return nil, err
@@ -281,6 +347,10 @@ func (a *AssetBuilder) findHash(file *FileAsset) (*hashing.Hash, error) {
 	u := file.DownloadURL
 	if a.GetAssets {
 		u = file.CanonicalURL
+		if a.BundleDirectory != "" && u != nil {
+			// The bundle stores the files, and their hashes, in the layout of a file repository
+			u = &url.URL{Path: filepath.Join(a.BundleDirectory, BundleFilesDirectory, u.Path)}
+		}
 	}
 
 	if u == nil {
@@ -331,6 +401,31 @@ func (a *AssetBuilder) findHash(file *FileAsset) (*hashing.Hash, error) {
 	return nil, fmt.Errorf("cannot determine hash for %q (have you specified a valid file location?)", u)
 }
 
+// airGapped returns true if all assets must be served from the FileRepository and ContainerRegistry
+// kopsImages are the images built by kOps, which are also published as BoringCrypto builds with a -fips tag suffix
+var kopsImages = []string{
+	"k8s.gcr.io/kops/dns-controller:",
+	"k8s.gcr.io/kops/kops-controller:",
+	"k8s.gcr.io/kops/kube-apiserver-healthcheck:",
+}
+
+// fipsImage returns the BoringCrypto build of a kOps image for FIPS clusters
+func (a *AssetBuilder) fipsImage(image string) string {
+	if !a.FIPS || strings.HasSuffix(image, "-fips") {
+		return image
+	}
+	for _, prefix := range kopsImages {
+		if strings.HasPrefix(image, prefix) {
+			return image + "-fips"
+		}
+	}
+	return image
+}
+
+func (a *AssetBuilder) airGapped() bool {
+	return a.AssetsLocation != nil && a.AssetsLocation.AirGapped != nil && *a.AssetsLocation.AirGapped
+}
+
 func (a *AssetBuilder) remapURL(canonicalURL *url.URL) (*url.URL, error) {
 	f := ""
 	if a.AssetsLocation != nil {
@@ -18,7 +18,10 @@ package assets
 
 import (
 	"io/ioutil"
+	"net/url"
+	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"k8s.io/kops/pkg/apis/kops"
@@ -159,6 +162,111 @@ func TestValidate_RemapImage_ContainerRegistry_MappingMultipleTimesConverges(t *
 
 }
 
+func TestRemapImage_AirGapped(t *testing.T) {
+	builder := buildAssetBuilder(t)
+	airGapped := true
+	builder.AssetsLocation.AirGapped = &airGapped
+
+	if _, err := builder.RemapImage("k8s.gcr.io/pause:3.2"); err == nil {
+		t.Errorf("expected error remapping image without containerRegistry")
+	}
+
+	registry := "registry.example.com"
+	builder.AssetsLocation.ContainerRegistry = &registry
+	remapped, err := builder.RemapImage("k8s.gcr.io/pause:3.2")
+	if err != nil {
+		t.Fatalf("unexpected error remapping image: %v", err)
+	}
+	if remapped != "registry.example.com/pause:3.2" {
+		t.Errorf("unexpected remapped image %q", remapped)
+	}
+}
+
+func TestRemapImage_FIPS(t *testing.T) {
+	builder := buildAssetBuilder(t)
+	builder.FIPS = true
+
+	grid := map[string]string{
+		"k8s.gcr.io/kops/kops-controller:1.22.0":      "k8s.gcr.io/kops/kops-controller:1.22.0-fips",
+		"k8s.gcr.io/kops/kops-controller:1.22.0-fips": "k8s.gcr.io/kops/kops-controller:1.22.0-fips",
+		"k8s.gcr.io/kube-apiserver:v1.21.0":           "k8s.gcr.io/kube-apiserver:v1.21.0",
+	}
+	for image, expected := range grid {
+		remapped, err := builder.RemapImage(image)
+		if err != nil {
+			t.Fatalf("unexpected error remapping image %q: %v", image, err)
+		}
+		if remapped != expected {
+			t.Errorf("expected %q for %q, got %q", expected, image, remapped)
+		}
+	}
+}
+
+func TestRemapFileAndSHAValue_AirGapped(t *testing.T) {
+	builder := buildAssetBuilder(t)
+	airGapped := true
+	builder.AssetsLocation.AirGapped = &airGapped
+
+	canonical, err := url.Parse("https://storage.googleapis.com/kubernetes-release/release/v1.21.0/bin/linux/amd64/kubelet")
+	if err != nil {
+		t.Fatalf("error parsing url: %v", err)
+	}
+
+	if _, err := builder.RemapFileAndSHAValue(canonical, "0000"); err == nil {
+		t.Errorf("expected error remapping file without fileRepository")
+	}
+
+	fileRepository := "https://mirror.example.com/assets"
+	builder.AssetsLocation.FileRepository = &fileRepository
+	remapped, err := builder.RemapFileAndSHAValue(canonical, "0000")
+	if err != nil {
+		t.Fatalf("unexpected error remapping file: %v", err)
+	}
+	expected := "https://mirror.example.com/assets/kubernetes-release/release/v1.21.0/bin/linux/amd64/kubelet"
+	if remapped.String() != expected {
+		t.Errorf("expected %q, got %q", expected, remapped.String())
+	}
+}
+
+func TestRemapFileAndSHA_BundleDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bundle")
+	if err != nil {
+		t.Fatalf("error creating directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	hash := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	hashFile := filepath.Join(dir, BundleFilesDirectory, "kops", "1.21.0", "linux", "amd64", "nodeup.sha256")
+	if err := os.MkdirAll(filepath.Dir(hashFile), 0755); err != nil {
+		t.Fatalf("error creating directory: %v", err)
+	}
+	if err := ioutil.WriteFile(hashFile, []byte(hash), 0644); err != nil {
+		t.Fatalf("error writing hash: %v", err)
+	}
+
+	builder := buildAssetBuilder(t)
+	builder.GetAssets = true
+	builder.BundleDirectory = dir
+	fileRepository := "https://mirror.example.com"
+	builder.AssetsLocation.FileRepository = &fileRepository
+
+	// The canonical location is not reachable, the hash must be read from the bundle
+	canonical, err := url.Parse("https://kops.invalid/kops/1.21.0/linux/amd64/nodeup")
+	if err != nil {
+		t.Fatalf("error parsing url: %v", err)
+	}
+	remapped, h, err := builder.RemapFileAndSHA(canonical)
+	if err != nil {
+		t.Fatalf("unexpected error remapping file: %v", err)
+	}
+	if h.Hex() != hash {
+		t.Errorf("expected hash %q, got %q", hash, h.Hex())
+	}
+	if remapped.String() != "https://mirror.example.com/kops/1.21.0/linux/amd64/nodeup" {
+		t.Errorf("unexpected remapped url %q", remapped.String())
+	}
+}
+
 func TestRemapEmptySection(t *testing.T) {
 	builder := buildAssetBuilder(t)
 
@@ -181,3 +289,61 @@ func TestRemapEmptySection(t *testing.T) {
 
 	golden.AssertMatchesFile(t, string(actual), expectedPath)
 }
+
+func TestRemapManifest_RecordsAddonImages(t *testing.T) {
+	builder := buildAssetBuilder(t)
+
+	proxyURL := "proxy.example.com/"
+	builder.AssetsLocation.ContainerProxy = &proxyURL
+
+	manifest := `apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: agent
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: weave
+        image: weaveworks/weave-kube:2.8.1
+      containers:
+      - name: agent
+        image: k8s.gcr.io/agent:v1.0.0
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: controller
+spec:
+  template:
+    spec:
+      nodeSelector:
+        node-role.kubernetes.io/master: ""
+      containers:
+      - name: controller
+        image: k8s.gcr.io/controller:v1.0.0
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+`
+
+	if _, err := builder.RemapManifest([]byte(manifest)); err != nil {
+		t.Fatalf("error remapping manifest: %v", err)
+	}
+
+	expected := []AddonImage{
+		{Image: "proxy.example.com/weaveworks/weave-kube:2.8.1"},
+		{Image: "proxy.example.com/agent:v1.0.0"},
+		{Image: "proxy.example.com/controller:v1.0.0", Roles: []kops.InstanceGroupRole{kops.InstanceGroupRoleMaster}},
+	}
+	if len(builder.AddonImages) != len(expected) {
+		t.Fatalf("expected %d addon images, got %d", len(expected), len(builder.AddonImages))
+	}
+	for i, image := range builder.AddonImages {
+		if !reflect.DeepEqual(*image, expected[i]) {
+			t.Errorf("expected addon image %+v, got %+v", expected[i], *image)
+		}
+	}
+}
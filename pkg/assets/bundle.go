@@ -0,0 +1,30 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+// A bundle holds all the assets of a cluster, so that air-gapped clusters can be installed without internet access.
+// It is a tar archive with the following layout.
+const (
+	// BundleFilesDirectory holds the files and their hashes, in the layout of a file repository
+	BundleFilesDirectory = "files"
+	// BundleImagesDirectory holds the images as an OCI image layout, referenced by their canonical name
+	BundleImagesDirectory = "images"
+	// BundleChannelsDirectory holds the channel of the cluster
+	BundleChannelsDirectory = "channels"
+	// BundleManifest lists the files and images of the bundle
+	BundleManifest = "bundle.yaml"
+)
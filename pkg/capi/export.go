@@ -0,0 +1,499 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capi
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	clusterAPIVersion        = "cluster.x-k8s.io/v1beta1"
+	infrastructureAPIVersion = "infrastructure.cluster.x-k8s.io/v1beta1"
+	controlPlaneAPIVersion   = "controlplane.cluster.x-k8s.io/v1beta1"
+	bootstrapAPIVersion      = "bootstrap.cluster.x-k8s.io/v1beta1"
+
+	// The instance profiles created by clusterawsadm
+	controlPlaneInstanceProfile = "control-plane.cluster-api-provider-aws.sigs.k8s.io"
+	nodesInstanceProfile        = "nodes.cluster-api-provider-aws.sigs.k8s.io"
+)
+
+// Result holds the Cluster API resources of a kOps cluster
+type Result struct {
+	// Objects are the Cluster API and Cluster API Provider AWS resources
+	Objects []*unstructured.Unstructured
+	// Unsupported describes the features of the cluster which have no equivalent in the
+	// exported resources, and have to be migrated by hand
+	Unsupported []string
+}
+
+type exporter struct {
+	cluster   *kops.Cluster
+	namespace string
+	version   string
+	result    *Result
+}
+
+// Export converts a kOps cluster and its instance groups into Cluster API resources,
+// using the AWS infrastructure provider and the kubeadm bootstrap and control plane providers
+func Export(cluster *kops.Cluster, instanceGroups []*kops.InstanceGroup, namespace string) (*Result, error) {
+	if kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
+		return nil, fmt.Errorf("only AWS clusters can be exported to Cluster API, not %q", cluster.Spec.CloudProvider)
+	}
+
+	e := &exporter{
+		cluster:   cluster,
+		namespace: namespace,
+		version:   "v" + strings.TrimPrefix(cluster.Spec.KubernetesVersion, "v"),
+		result:    &Result{},
+	}
+
+	var masters, nodes []*kops.InstanceGroup
+	for _, ig := range instanceGroups {
+		switch ig.Spec.Role {
+		case kops.InstanceGroupRoleMaster:
+			masters = append(masters, ig)
+		case kops.InstanceGroupRoleNode:
+			nodes = append(nodes, ig)
+		case kops.InstanceGroupRoleBastion:
+			// The bastion is part of the AWSCluster
+		default:
+			e.unsupported("instance group %q: role %s has no Cluster API equivalent", ig.ObjectMeta.Name, ig.Spec.Role)
+		}
+	}
+	if len(masters) == 0 {
+		return nil, fmt.Errorf("cluster %q has no control plane instance groups", cluster.ObjectMeta.Name)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ObjectMeta.Name < nodes[j].ObjectMeta.Name })
+
+	if err := e.exportCluster(); err != nil {
+		return nil, err
+	}
+	e.exportControlPlane(masters)
+	for _, ig := range nodes {
+		e.exportMachineDeployment(ig)
+	}
+	e.reportClusterFeatures()
+
+	return e.result, nil
+}
+
+func (e *exporter) unsupported(format string, args ...interface{}) {
+	e.result.Unsupported = append(e.result.Unsupported, fmt.Sprintf(format, args...))
+}
+
+func (e *exporter) add(apiVersion string, kind string, name string, spec map[string]interface{}) {
+	e.result.Objects = append(e.result.Objects, &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": apiVersion,
+			"kind":       kind,
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": e.namespace,
+			},
+			"spec": spec,
+		},
+	})
+}
+
+func ref(apiVersion string, kind string, name string) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"name":       name,
+	}
+}
+
+func (e *exporter) controlPlaneName() string {
+	return e.cluster.ObjectMeta.Name + "-control-plane"
+}
+
+func (e *exporter) exportCluster() error {
+	cluster := e.cluster
+	name := cluster.ObjectMeta.Name
+
+	clusterNetwork := map[string]interface{}{}
+	if cluster.Spec.PodCIDR != "" {
+		clusterNetwork["pods"] = map[string]interface{}{"cidrBlocks": []interface{}{cluster.Spec.PodCIDR}}
+	}
+	if cluster.Spec.ServiceClusterIPRange != "" {
+		clusterNetwork["services"] = map[string]interface{}{"cidrBlocks": []interface{}{cluster.Spec.ServiceClusterIPRange}}
+	}
+	e.add(clusterAPIVersion, "Cluster", name, map[string]interface{}{
+		"clusterNetwork":    clusterNetwork,
+		"infrastructureRef": ref(infrastructureAPIVersion, "AWSCluster", name),
+		"controlPlaneRef":   ref(controlPlaneAPIVersion, "KubeadmControlPlane", e.controlPlaneName()),
+	})
+
+	region, err := awsup.FindRegion(cluster)
+	if err != nil {
+		return err
+	}
+
+	vpc := map[string]interface{}{}
+	if cluster.Spec.NetworkID != "" {
+		vpc["id"] = cluster.Spec.NetworkID
+	} else {
+		vpc["cidrBlock"] = cluster.Spec.NetworkCIDR
+	}
+	var subnets []interface{}
+	for _, subnet := range cluster.Spec.Subnets {
+		s := map[string]interface{}{
+			"availabilityZone": subnet.Zone,
+			"cidrBlock":        subnet.CIDR,
+			"isPublic":         subnet.Type == kops.SubnetTypePublic || subnet.Type == kops.SubnetTypeUtility,
+		}
+		if subnet.ProviderID != "" {
+			s["id"] = subnet.ProviderID
+		}
+		subnets = append(subnets, s)
+	}
+
+	spec := map[string]interface{}{
+		"region": region,
+		"network": map[string]interface{}{
+			"vpc":     vpc,
+			"subnets": subnets,
+		},
+	}
+
+	scheme := "internet-facing"
+	if cluster.Spec.API != nil && cluster.Spec.API.LoadBalancer != nil && cluster.Spec.API.LoadBalancer.Type == kops.LoadBalancerTypeInternal {
+		scheme = "internal"
+	}
+	if cluster.Spec.API != nil && cluster.Spec.API.LoadBalancer == nil {
+		e.unsupported("spec.api: Cluster API Provider AWS always publishes the API server through a load balancer")
+	}
+	spec["controlPlaneLoadBalancer"] = map[string]interface{}{"scheme": scheme}
+
+	if cluster.Spec.Topology != nil && cluster.Spec.Topology.Bastion != nil {
+		var allowed []interface{}
+		for _, cidr := range cluster.Spec.SSHAccess {
+			allowed = append(allowed, cidr)
+		}
+		spec["bastion"] = map[string]interface{}{
+			"enabled":           true,
+			"allowedCIDRBlocks": allowed,
+		}
+	}
+
+	if cluster.Spec.SSHKeyName != nil {
+		spec["sshKeyName"] = *cluster.Spec.SSHKeyName
+	} else {
+		e.unsupported("SSH public key: the key stored by kOps is not exported; import it as an EC2 key pair and set sshKeyName")
+	}
+
+	if tags := stringMap(cluster.Spec.CloudLabels); tags != nil {
+		spec["additionalTags"] = tags
+	}
+
+	e.add(infrastructureAPIVersion, "AWSCluster", name, spec)
+	return nil
+}
+
+func (e *exporter) cloudProvider() string {
+	if e.cluster.Spec.ExternalCloudControllerManager != nil {
+		return "external"
+	}
+	return "aws"
+}
+
+func (e *exporter) nodeRegistration(ig *kops.InstanceGroup) map[string]interface{} {
+	kubeletExtraArgs := map[string]interface{}{
+		"cloud-provider": e.cloudProvider(),
+	}
+	if len(ig.Spec.NodeLabels) != 0 {
+		var labels []string
+		for k, v := range ig.Spec.NodeLabels {
+			labels = append(labels, k+"="+v)
+		}
+		sort.Strings(labels)
+		kubeletExtraArgs["node-labels"] = strings.Join(labels, ",")
+	}
+
+	registration := map[string]interface{}{
+		"name":             "{{ ds.meta_data.local_hostname }}",
+		"kubeletExtraArgs": kubeletExtraArgs,
+	}
+
+	if len(ig.Spec.Taints) != 0 {
+		var taints []interface{}
+		for _, taint := range ig.Spec.Taints {
+			taints = append(taints, parseTaint(taint))
+		}
+		registration["taints"] = taints
+	}
+	return registration
+}
+
+// parseTaint converts a taint in the key=value:Effect form of the kubelet to its object form
+func parseTaint(s string) map[string]interface{} {
+	taint := map[string]interface{}{}
+	if i := strings.LastIndex(s, ":"); i != -1 {
+		taint["effect"] = s[i+1:]
+		s = s[:i]
+	}
+	if i := strings.Index(s, "="); i != -1 {
+		taint["value"] = s[i+1:]
+		s = s[:i]
+	}
+	taint["key"] = s
+	return taint
+}
+
+func (e *exporter) machineTemplate(name string, ig *kops.InstanceGroup, instanceProfile string) {
+	spec := map[string]interface{}{
+		"instanceType":       ig.Spec.MachineType,
+		"iamInstanceProfile": instanceProfile,
+	}
+	if e.cluster.Spec.SSHKeyName != nil {
+		spec["sshKeyName"] = *e.cluster.Spec.SSHKeyName
+	}
+	if strings.HasPrefix(ig.Spec.Image, "ami-") {
+		spec["ami"] = map[string]interface{}{"id": ig.Spec.Image}
+	} else if ig.Spec.Image != "" {
+		e.unsupported("instance group %q: image %q is not an AMI ID; set ami.id of AWSMachineTemplate %q", ig.ObjectMeta.Name, ig.Spec.Image, name)
+	}
+	if ig.Spec.RootVolumeSize != nil {
+		spec["rootVolume"] = map[string]interface{}{"size": int64(*ig.Spec.RootVolumeSize)}
+	}
+	if ig.Spec.MaxPrice != nil {
+		spec["spotMarketOptions"] = map[string]interface{}{"maxPrice": *ig.Spec.MaxPrice}
+	}
+	if tags := stringMap(ig.Spec.CloudLabels); tags != nil {
+		spec["additionalTags"] = tags
+	}
+
+	e.add(infrastructureAPIVersion, "AWSMachineTemplate", name, map[string]interface{}{
+		"template": map[string]interface{}{"spec": spec},
+	})
+
+	if ig.Spec.MixedInstancesPolicy != nil {
+		e.unsupported("instance group %q: mixedInstancesPolicy needs an AWSMachinePool, which is not exported", ig.ObjectMeta.Name)
+	}
+	if len(ig.Spec.Hooks) != 0 || len(ig.Spec.FileAssets) != 0 || len(ig.Spec.AdditionalUserData) != 0 {
+		e.unsupported("instance group %q: hooks, fileAssets and additionalUserData are not exported; use the preKubeadmCommands and files of the kubeadm config", ig.ObjectMeta.Name)
+	}
+}
+
+func (e *exporter) exportControlPlane(masters []*kops.InstanceGroup) {
+	name := e.controlPlaneName()
+
+	replicas := int64(0)
+	for _, ig := range masters {
+		replicas += int64(fi.Int32Value(ig.Spec.MinSize))
+		if ig.Spec.MachineType != masters[0].Spec.MachineType {
+			e.unsupported("instance group %q: the control plane uses the machine type %s of instance group %q", ig.ObjectMeta.Name, masters[0].Spec.MachineType, masters[0].ObjectMeta.Name)
+		}
+	}
+	e.machineTemplate(name, masters[0], controlPlaneInstanceProfile)
+
+	extraArgs := map[string]interface{}{"cloud-provider": e.cloudProvider()}
+	registration := e.nodeRegistration(masters[0])
+	e.add(controlPlaneAPIVersion, "KubeadmControlPlane", name, map[string]interface{}{
+		"replicas": replicas,
+		"version":  e.version,
+		"machineTemplate": map[string]interface{}{
+			"infrastructureRef": ref(infrastructureAPIVersion, "AWSMachineTemplate", name),
+		},
+		"kubeadmConfigSpec": map[string]interface{}{
+			"clusterConfiguration": map[string]interface{}{
+				"apiServer":         map[string]interface{}{"extraArgs": extraArgs},
+				"controllerManager": map[string]interface{}{"extraArgs": extraArgs},
+			},
+			"initConfiguration": map[string]interface{}{"nodeRegistration": registration},
+			"joinConfiguration": map[string]interface{}{"nodeRegistration": registration},
+		},
+	})
+}
+
+func (e *exporter) exportMachineDeployment(ig *kops.InstanceGroup) {
+	name := e.cluster.ObjectMeta.Name + "-" + ig.ObjectMeta.Name
+	e.machineTemplate(name, ig, nodesInstanceProfile)
+
+	e.add(bootstrapAPIVersion, "KubeadmConfigTemplate", name, map[string]interface{}{
+		"template": map[string]interface{}{
+			"spec": map[string]interface{}{
+				"joinConfiguration": map[string]interface{}{"nodeRegistration": e.nodeRegistration(ig)},
+			},
+		},
+	})
+
+	machineSpec := map[string]interface{}{
+		"clusterName":       e.cluster.ObjectMeta.Name,
+		"version":           e.version,
+		"bootstrap":         map[string]interface{}{"configRef": ref(bootstrapAPIVersion, "KubeadmConfigTemplate", name)},
+		"infrastructureRef": ref(infrastructureAPIVersion, "AWSMachineTemplate", name),
+	}
+	if zones := e.zones(ig); len(zones) == 1 {
+		machineSpec["failureDomain"] = zones[0]
+	} else {
+		e.unsupported("instance group %q: a MachineDeployment has a single failure domain, split it to spread instances over %s", ig.ObjectMeta.Name, strings.Join(zones, ", "))
+	}
+
+	minSize := int64(fi.Int32Value(ig.Spec.MinSize))
+	spec := map[string]interface{}{
+		"clusterName": e.cluster.ObjectMeta.Name,
+		"replicas":    minSize,
+		"template":    map[string]interface{}{"spec": machineSpec},
+	}
+	e.add(clusterAPIVersion, "MachineDeployment", name, spec)
+
+	if maxSize := int64(fi.Int32Value(ig.Spec.MaxSize)); maxSize > minSize {
+		e.result.Objects[len(e.result.Objects)-1].SetAnnotations(map[string]string{
+			"cluster.x-k8s.io/cluster-api-autoscaler-node-group-min-size": fmt.Sprintf("%d", minSize),
+			"cluster.x-k8s.io/cluster-api-autoscaler-node-group-max-size": fmt.Sprintf("%d", maxSize),
+		})
+	}
+}
+
+// zones returns the sorted availability zones of the subnets of the instance group
+func (e *exporter) zones(ig *kops.InstanceGroup) []string {
+	zones := make(map[string]bool)
+	for _, name := range ig.Spec.Subnets {
+		for _, subnet := range e.cluster.Spec.Subnets {
+			if subnet.Name == name {
+				zones[subnet.Zone] = true
+			}
+		}
+	}
+	var sorted []string
+	for zone := range zones {
+		sorted = append(sorted, zone)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// reportClusterFeatures reports the cluster settings which are managed by kOps but not by Cluster API
+func (e *exporter) reportClusterFeatures() {
+	spec := &e.cluster.Spec
+
+	if spec.Networking != nil {
+		v := reflect.ValueOf(spec.Networking).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).IsNil() {
+				continue
+			}
+			tag := strings.Split(v.Type().Field(i).Tag.Get("json"), ",")[0]
+			switch tag {
+			case "classic", "kubenet", "external", "cni":
+			default:
+				e.unsupported("networking %s: Cluster API does not install a CNI; install it with a ClusterResourceSet or after the cluster is up", tag)
+			}
+		}
+	}
+
+	if spec.ExternalCloudControllerManager != nil {
+		e.unsupported("cloudControllerManager: install the AWS cloud controller manager in the workload cluster")
+	}
+
+	addons := map[string]bool{
+		"awsLoadBalancerController": spec.AWSLoadBalancerController != nil && fi.BoolValue(spec.AWSLoadBalancerController.Enabled),
+		"certManager":               spec.CertManager != nil && fi.BoolValue(spec.CertManager.Enabled),
+		"clusterAutoscaler":         spec.ClusterAutoscaler != nil && fi.BoolValue(spec.ClusterAutoscaler.Enabled),
+		"metricsServer":             spec.MetricsServer != nil && fi.BoolValue(spec.MetricsServer.Enabled),
+		"nodeTerminationHandler":    spec.NodeTerminationHandler != nil && fi.BoolValue(spec.NodeTerminationHandler.Enabled),
+	}
+	var enabled []string
+	for addon, on := range addons {
+		if on {
+			enabled = append(enabled, addon)
+		}
+	}
+	sort.Strings(enabled)
+	for _, addon := range enabled {
+		e.unsupported("%s: addons are not managed by Cluster API; install it in the workload cluster", addon)
+	}
+
+	e.unsupported("etcdClusters: the KubeadmControlPlane runs stacked etcd; etcd-manager settings and backups are not exported")
+
+	components := map[string]bool{
+		"kubeAPIServer":         spec.KubeAPIServer != nil,
+		"kubeControllerManager": spec.KubeControllerManager != nil,
+		"kubeScheduler":         spec.KubeScheduler != nil,
+		"kubeProxy":             spec.KubeProxy != nil,
+		"kubelet":               spec.Kubelet != nil,
+		"masterKubelet":         spec.MasterKubelet != nil,
+	}
+	var configured []string
+	for component, set := range components {
+		if set {
+			configured = append(configured, component)
+		}
+	}
+	sort.Strings(configured)
+	for _, component := range configured {
+		e.unsupported("%s: component settings are not exported; set them in the kubeadm config", component)
+	}
+
+	if len(spec.Hooks) != 0 || len(spec.FileAssets) != 0 {
+		e.unsupported("hooks and fileAssets are not exported; use the preKubeadmCommands and files of the kubeadm config")
+	}
+	if spec.AdditionalPolicies != nil || spec.ExternalPolicies != nil {
+		e.unsupported("additionalPolicies and externalPolicies: attach them to the roles of the clusterawsadm instance profiles")
+	}
+	for _, cidr := range spec.KubernetesAPIAccess {
+		if cidr != "0.0.0.0/0" && cidr != "::/0" {
+			e.unsupported("kubernetesAPIAccess: the API load balancer of Cluster API Provider AWS is not restricted to %s", strings.Join(spec.KubernetesAPIAccess, ", "))
+			break
+		}
+	}
+}
+
+func stringMap(m map[string]string) map[string]interface{} {
+	if len(m) == 0 {
+		return nil
+	}
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// ToYAML renders the resources as a multi-document YAML stream, preceded by the
+// unsupported features as comments
+func (r *Result) ToYAML() ([]byte, error) {
+	var b bytes.Buffer
+	if len(r.Unsupported) != 0 {
+		b.WriteString("# The following features of the cluster are not exported:\n")
+		for _, s := range r.Unsupported {
+			b.WriteString("# - " + s + "\n")
+		}
+	}
+	for i, o := range r.Objects {
+		if i != 0 || b.Len() != 0 {
+			b.WriteString("---\n")
+		}
+		y, err := yaml.Marshal(o.Object)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling %s %q: %v", o.GetKind(), o.GetName(), err)
+		}
+		b.Write(y)
+	}
+	return b.Bytes(), nil
+}
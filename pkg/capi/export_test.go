@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capi
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/pkg/testutils/golden"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestExport(t *testing.T) {
+	cluster := testutils.BuildMinimalCluster("minimal.example.com")
+	cluster.Spec.KubernetesVersion = "1.21.0"
+	cluster.Spec.PodCIDR = "100.96.0.0/11"
+	cluster.Spec.ServiceClusterIPRange = "100.64.0.0/13"
+	cluster.Spec.Networking = &kops.NetworkingSpec{Calico: &kops.CalicoNetworkingSpec{}}
+	cluster.Spec.Topology.Bastion = &kops.BastionSpec{}
+	cluster.Spec.Subnets[0].Type = kops.SubnetTypePrivate
+	cluster.Spec.Subnets[1].Type = kops.SubnetTypePrivate
+	cluster.Spec.Subnets[2].Type = kops.SubnetTypeUtility
+	cluster.Spec.MetricsServer = &kops.MetricsServerConfig{Enabled: fi.Bool(true)}
+
+	instanceGroups := []*kops.InstanceGroup{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "nodes"},
+			Spec: kops.InstanceGroupSpec{
+				Role:        kops.InstanceGroupRoleNode,
+				MachineType: "t3.medium",
+				MinSize:     fi.Int32(2),
+				MaxSize:     fi.Int32(4),
+				Subnets:     []string{"subnet-us-mock-1a", "subnet-us-mock-1b"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "master-us-mock-1a"},
+			Spec: kops.InstanceGroupSpec{
+				Role:        kops.InstanceGroupRoleMaster,
+				MachineType: "m5.large",
+				Image:       "099720109477/ubuntu/images/hvm-ssd/ubuntu-focal-20.04-amd64-server-20210720",
+				MinSize:     fi.Int32(1),
+				MaxSize:     fi.Int32(1),
+				Subnets:     []string{"subnet-us-mock-1a"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "bastions"},
+			Spec: kops.InstanceGroupSpec{
+				Role:        kops.InstanceGroupRoleBastion,
+				MachineType: "t3.micro",
+				Subnets:     []string{"subnet-us-mock-1a"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "spot"},
+			Spec: kops.InstanceGroupSpec{
+				Role:           kops.InstanceGroupRoleNode,
+				MachineType:    "c5.xlarge",
+				Image:          "ami-0123456789abcdef0",
+				MinSize:        fi.Int32(1),
+				MaxSize:        fi.Int32(1),
+				MaxPrice:       fi.String("0.10"),
+				RootVolumeSize: fi.Int32(64),
+				Subnets:        []string{"subnet-us-mock-1c"},
+				NodeLabels:     map[string]string{"lifecycle": "spot", "team": "batch"},
+				Taints:         []string{"dedicated=batch:NoSchedule"},
+			},
+		},
+	}
+
+	result, err := Export(cluster, instanceGroups, "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actual, err := result.ToYAML()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	golden.AssertMatchesFile(t, string(actual), "tests/minimal.yaml")
+}
+
+func TestExportRequiresAWS(t *testing.T) {
+	cluster := testutils.BuildMinimalCluster("minimal.example.com")
+	cluster.Spec.CloudProvider = string(kops.CloudProviderGCE)
+
+	if _, err := Export(cluster, nil, "default"); err == nil {
+		t.Errorf("expected an error exporting a GCE cluster")
+	}
+}
+
+func TestParseTaint(t *testing.T) {
+	grid := map[string]map[string]interface{}{
+		"dedicated=batch:NoSchedule": {"key": "dedicated", "value": "batch", "effect": "NoSchedule"},
+		"dedicated:NoExecute":        {"key": "dedicated", "effect": "NoExecute"},
+	}
+	for input, expected := range grid {
+		actual := parseTaint(input)
+		if len(actual) != len(expected) {
+			t.Errorf("%q: expected %v, got %v", input, expected, actual)
+			continue
+		}
+		for k, v := range expected {
+			if actual[k] != v {
+				t.Errorf("%q: expected %v, got %v", input, expected, actual)
+			}
+		}
+	}
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// check is a recommendation of the benchmark
+type check struct {
+	ID          string
+	Description string
+	// Manual checks are reported as warnings, as the recommendation depends on the environment of the cluster
+	Manual bool
+}
+
+// run builds the finding of the check from the explanation of its failure, empty when the check passes
+func (c *check) run(object string, detail string) *Finding {
+	finding := &Finding{
+		ID:          c.ID,
+		Description: c.Description,
+		Object:      object,
+		Result:      ResultPass,
+		Detail:      detail,
+	}
+	if detail != "" {
+		finding.Result = ResultFail
+		if c.Manual {
+			finding.Result = ResultWarn
+		}
+	}
+	return finding
+}
+
+type componentCheck struct {
+	check
+	Component string
+	Check     func(c *Component) string
+}
+
+type kubeletCheck struct {
+	check
+	Check func(k *KubeletConfig) string
+}
+
+const (
+	apiserver         = "kube-apiserver"
+	controllerManager = "kube-controller-manager"
+	scheduler         = "kube-scheduler"
+	etcdManager       = "etcd-manager"
+)
+
+var componentChecks = []componentCheck{
+	{check{"1.2.2", "Ensure that the --token-auth-file parameter is not set", false}, apiserver, flagNotSet("token-auth-file")},
+	{check{"1.2.5", "Ensure that the --kubelet-certificate-authority argument is set as appropriate", true}, apiserver, flagSet("kubelet-certificate-authority")},
+	{check{"1.2.6", "Ensure that the --authorization-mode argument is not set to AlwaysAllow", false}, apiserver, listExcludes("authorization-mode", "AlwaysAllow")},
+	{check{"1.2.7", "Ensure that the --authorization-mode argument includes Node", false}, apiserver, listIncludes("authorization-mode", "Node")},
+	{check{"1.2.8", "Ensure that the --authorization-mode argument includes RBAC", false}, apiserver, listIncludes("authorization-mode", "RBAC")},
+	{check{"1.2.9", "Ensure that the admission control plugin EventRateLimit is set", false}, apiserver, listIncludes("enable-admission-plugins", "EventRateLimit")},
+	{check{"1.2.10", "Ensure that the admission control plugin AlwaysAdmit is not set", false}, apiserver, listExcludes("enable-admission-plugins", "AlwaysAdmit")},
+	{check{"1.2.11", "Ensure that the admission control plugin AlwaysPullImages is set", true}, apiserver, listIncludes("enable-admission-plugins", "AlwaysPullImages")},
+	{check{"1.2.16", "Ensure that the admission control plugin NodeRestriction is set", false}, apiserver, listIncludes("enable-admission-plugins", "NodeRestriction")},
+	{check{"1.2.17", "Ensure that the --secure-port argument is not set to 0", false}, apiserver, flagNotEquals("secure-port", "0")},
+	{check{"1.2.18", "Ensure that the --profiling argument is set to false", false}, apiserver, flagEquals("profiling", "false")},
+	{check{"1.2.19", "Ensure that the --audit-log-path argument is set", false}, apiserver, flagSet("audit-log-path")},
+	{check{"1.2.20", "Ensure that the --audit-log-maxage argument is set to 30 or as appropriate", false}, apiserver, flagAtLeast("audit-log-maxage", 30)},
+	{check{"1.2.21", "Ensure that the --audit-log-maxbackup argument is set to 10 or as appropriate", false}, apiserver, flagAtLeast("audit-log-maxbackup", 10)},
+	{check{"1.2.22", "Ensure that the --audit-log-maxsize argument is set to 100 or as appropriate", false}, apiserver, flagAtLeast("audit-log-maxsize", 100)},
+	{check{"1.2.24", "Ensure that the --service-account-lookup argument is set to true", false}, apiserver, flagNotEquals("service-account-lookup", "false")},
+	{check{"1.2.25", "Ensure that the --service-account-key-file argument is set as appropriate", false}, apiserver, flagSet("service-account-key-file")},
+	{check{"1.2.26", "Ensure that the --etcd-certfile and --etcd-keyfile arguments are set as appropriate", false}, apiserver, flagSet("etcd-certfile", "etcd-keyfile")},
+	{check{"1.2.27", "Ensure that the --tls-cert-file and --tls-private-key-file arguments are set as appropriate", false}, apiserver, flagSet("tls-cert-file", "tls-private-key-file")},
+	{check{"1.2.28", "Ensure that the --client-ca-file argument is set as appropriate", false}, apiserver, flagSet("client-ca-file")},
+	{check{"1.2.29", "Ensure that the --etcd-cafile argument is set as appropriate", false}, apiserver, flagSet("etcd-cafile")},
+	{check{"1.2.30", "Ensure that the --encryption-provider-config argument is set as appropriate", true}, apiserver, flagSet("encryption-provider-config")},
+	{check{"1.2.32", "Ensure that the API Server only makes use of Strong Cryptographic Ciphers", false}, apiserver, flagSet("tls-cipher-suites")},
+
+	{check{"1.3.1", "Ensure that the --terminated-pod-gc-threshold argument is set as appropriate", false}, controllerManager, flagSet("terminated-pod-gc-threshold")},
+	{check{"1.3.2", "Ensure that the --profiling argument is set to false", false}, controllerManager, flagEquals("profiling", "false")},
+	{check{"1.3.3", "Ensure that the --use-service-account-credentials argument is set to true", false}, controllerManager, flagEquals("use-service-account-credentials", "true")},
+	{check{"1.3.4", "Ensure that the --service-account-private-key-file argument is set as appropriate", false}, controllerManager, flagSet("service-account-private-key-file")},
+	{check{"1.3.5", "Ensure that the --root-ca-file argument is set as appropriate", false}, controllerManager, flagSet("root-ca-file")},
+
+	{check{"1.4.1", "Ensure that the --profiling argument is set to false", false}, scheduler, flagEquals("profiling", "false")},
+
+	{check{"2.2", "Ensure that the --client-cert-auth argument is set to true", false}, etcdManager, envEquals("ETCD_CLIENT_CERT_AUTH", "true")},
+	{check{"2.3", "Ensure that the --auto-tls argument is not set to true", false}, etcdManager, envNotEquals("ETCD_AUTO_TLS", "true")},
+	{check{"2.5", "Ensure that the --peer-client-cert-auth argument is set to true", false}, etcdManager, envEquals("ETCD_PEER_CLIENT_CERT_AUTH", "true")},
+	{check{"2.6", "Ensure that the --peer-auto-tls argument is not set to true", false}, etcdManager, envNotEquals("ETCD_PEER_AUTO_TLS", "true")},
+}
+
+var kubeletChecks = []kubeletCheck{
+	{check{"4.2.1", "Ensure that the anonymous-auth argument is set to false", false}, func(k *KubeletConfig) string {
+		if enabled := k.Authentication.Anonymous.Enabled; enabled == nil || *enabled {
+			return "anonymous authentication is enabled"
+		}
+		return ""
+	}},
+	{check{"4.2.2", "Ensure that the --authorization-mode argument is not set to AlwaysAllow", false}, func(k *KubeletConfig) string {
+		if k.Authorization.Mode == "" || k.Authorization.Mode == "AlwaysAllow" {
+			return "authorization mode is AlwaysAllow"
+		}
+		return ""
+	}},
+	{check{"4.2.3", "Ensure that the --client-ca-file argument is set as appropriate", false}, func(k *KubeletConfig) string {
+		if k.Authentication.X509.ClientCAFile == "" {
+			return "client CA file is not set"
+		}
+		return ""
+	}},
+	{check{"4.2.4", "Ensure that the --read-only-port argument is set to 0", false}, func(k *KubeletConfig) string {
+		if k.ReadOnlyPort != 0 {
+			return fmt.Sprintf("read-only port is %d", k.ReadOnlyPort)
+		}
+		return ""
+	}},
+	{check{"4.2.5", "Ensure that the --streaming-connection-idle-timeout argument is not set to 0", false}, func(k *KubeletConfig) string {
+		if k.StreamingConnectionIdleTimeout == "0s" {
+			return "streaming connection idle timeout is 0"
+		}
+		return ""
+	}},
+	{check{"4.2.6", "Ensure that the --protect-kernel-defaults argument is set to true", false}, func(k *KubeletConfig) string {
+		if !k.ProtectKernelDefaults {
+			return "kernel defaults are not protected"
+		}
+		return ""
+	}},
+	{check{"4.2.7", "Ensure that the --make-iptables-util-chains argument is set to true", false}, func(k *KubeletConfig) string {
+		if k.MakeIPTablesUtilChains != nil && !*k.MakeIPTablesUtilChains {
+			return "iptables util chains are not made"
+		}
+		return ""
+	}},
+	{check{"4.2.11", "Ensure that the --rotate-certificates argument is not set to false", true}, func(k *KubeletConfig) string {
+		if k.RotateCertificates != nil && !*k.RotateCertificates {
+			return "certificates are not rotated"
+		}
+		return ""
+	}},
+	{check{"4.2.13", "Ensure that the Kubelet only makes use of Strong Cryptographic Ciphers", false}, func(k *KubeletConfig) string {
+		if len(k.TLSCipherSuites) == 0 {
+			return "cipher suites are not set"
+		}
+		return ""
+	}},
+}
+
+func flagSet(names ...string) func(c *Component) string {
+	return func(c *Component) string {
+		for _, name := range names {
+			if c.Flags[name] == "" {
+				return fmt.Sprintf("--%s is not set", name)
+			}
+		}
+		return ""
+	}
+}
+
+func flagNotSet(name string) func(c *Component) string {
+	return func(c *Component) string {
+		if _, found := c.Flags[name]; found {
+			return fmt.Sprintf("--%s is set", name)
+		}
+		return ""
+	}
+}
+
+func flagEquals(name string, expected string) func(c *Component) string {
+	return func(c *Component) string {
+		if value := c.Flags[name]; value != expected {
+			return fmt.Sprintf("--%s is %q", name, value)
+		}
+		return ""
+	}
+}
+
+func flagNotEquals(name string, unexpected string) func(c *Component) string {
+	return func(c *Component) string {
+		if c.Flags[name] == unexpected {
+			return fmt.Sprintf("--%s is %q", name, unexpected)
+		}
+		return ""
+	}
+}
+
+func flagAtLeast(name string, min int) func(c *Component) string {
+	return func(c *Component) string {
+		value, err := strconv.Atoi(c.Flags[name])
+		if err != nil || value < min {
+			return fmt.Sprintf("--%s is %q, expected at least %d", name, c.Flags[name], min)
+		}
+		return ""
+	}
+}
+
+func listIncludes(name string, item string) func(c *Component) string {
+	return func(c *Component) string {
+		for _, value := range strings.Split(c.Flags[name], ",") {
+			if value == item {
+				return ""
+			}
+		}
+		return fmt.Sprintf("--%s does not include %s", name, item)
+	}
+}
+
+func listExcludes(name string, item string) func(c *Component) string {
+	return func(c *Component) string {
+		for _, value := range strings.Split(c.Flags[name], ",") {
+			if value == item {
+				return fmt.Sprintf("--%s includes %s", name, item)
+			}
+		}
+		return ""
+	}
+}
+
+func envEquals(name string, expected string) func(c *Component) string {
+	return func(c *Component) string {
+		if value := c.Env[name]; value != expected {
+			return fmt.Sprintf("%s is %q", name, value)
+		}
+		return ""
+	}
+}
+
+func envNotEquals(name string, unexpected string) func(c *Component) string {
+	return func(c *Component) string {
+		if c.Env[name] == unexpected {
+			return fmt.Sprintf("%s is %q", name, unexpected)
+		}
+		return ""
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cis checks the configuration of a running cluster against the CIS Kubernetes Benchmark
+package cis
+
+import (
+	"sort"
+	"strings"
+)
+
+// Result is the outcome of a check
+type Result string
+
+const (
+	// ResultPass means the configuration meets the recommendation
+	ResultPass Result = "PASS"
+	// ResultFail means the configuration does not meet the recommendation
+	ResultFail Result = "FAIL"
+	// ResultWarn means the recommendation is not met, but has to be assessed manually
+	ResultWarn Result = "WARN"
+)
+
+// Finding is the result of a check for an object of the cluster
+type Finding struct {
+	// ID is the number of the recommendation in the benchmark, e.g. 1.2.18
+	ID string `json:"id"`
+	// Description is the text of the recommendation
+	Description string `json:"description"`
+	// Object is the pod or the node the check applies to
+	Object string `json:"object"`
+	// Result is the outcome of the check
+	Result Result `json:"result"`
+	// Detail explains a failure
+	Detail string `json:"detail,omitempty"`
+}
+
+// Component is a control plane component, with the flags and the environment of its container
+type Component struct {
+	// Name is one of kube-apiserver, kube-controller-manager, kube-scheduler or etcd-manager
+	Name string
+	// Object identifies the pod running the component
+	Object string
+	// Flags are the --name=value arguments of the container
+	Flags map[string]string
+	// Env is the environment of the container
+	Env map[string]string
+}
+
+// Node is a node of the cluster, with the configuration of its kubelet
+type Node struct {
+	Name          string
+	KubeletConfig *KubeletConfig
+}
+
+// KubeletConfig holds the fields of the kubelet configuration, as returned by the configz endpoint, that the checks apply to
+type KubeletConfig struct {
+	Authentication struct {
+		Anonymous struct {
+			Enabled *bool `json:"enabled"`
+		} `json:"anonymous"`
+		X509 struct {
+			ClientCAFile string `json:"clientCAFile"`
+		} `json:"x509"`
+	} `json:"authentication"`
+	Authorization struct {
+		Mode string `json:"mode"`
+	} `json:"authorization"`
+	ReadOnlyPort                   int32    `json:"readOnlyPort"`
+	StreamingConnectionIdleTimeout string   `json:"streamingConnectionIdleTimeout"`
+	ProtectKernelDefaults          bool     `json:"protectKernelDefaults"`
+	MakeIPTablesUtilChains         *bool    `json:"makeIPTablesUtilChains"`
+	RotateCertificates             *bool    `json:"rotateCertificates"`
+	TLSCipherSuites                []string `json:"tlsCipherSuites"`
+}
+
+// ParseFlags returns the --name=value arguments of a command; flags without a value are set to "true"
+func ParseFlags(args []string) map[string]string {
+	flags := make(map[string]string)
+	for _, arg := range args {
+		// The commands of the static pods are wrapped in a shell by kops
+		for _, field := range strings.Fields(arg) {
+			if !strings.HasPrefix(field, "--") || field == "--" {
+				continue
+			}
+			name, value := strings.TrimPrefix(field, "--"), "true"
+			if i := strings.Index(name, "="); i != -1 {
+				name, value = name[:i], name[i+1:]
+			}
+			flags[name] = value
+		}
+	}
+	return flags
+}
+
+// Run checks the components and the nodes, returning the findings sorted by recommendation
+func Run(components []*Component, nodes []*Node) []*Finding {
+	var findings []*Finding
+	for _, component := range components {
+		for _, check := range componentChecks {
+			if check.Component != component.Name {
+				continue
+			}
+			findings = append(findings, check.run(component.Object, check.Check(component)))
+		}
+	}
+	for _, node := range nodes {
+		for _, check := range kubeletChecks {
+			findings = append(findings, check.run(node.Name, check.Check(node.KubeletConfig)))
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return compareIDs(findings[i].ID, findings[j].ID)
+	})
+	return findings
+}
+
+// compareIDs orders the recommendations numerically, so that 1.2.10 follows 1.2.9
+func compareIDs(a, b string) bool {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] == bs[i] {
+			continue
+		}
+		if len(as[i]) != len(bs[i]) {
+			return len(as[i]) < len(bs[i])
+		}
+		return as[i] < bs[i]
+	}
+	return len(as) < len(bs)
+}
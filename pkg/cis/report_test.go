@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cis
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseFlags(t *testing.T) {
+	args := []string{"/go-runner", "--log-file=/var/log/kube-apiserver.log", "--", "/usr/local/bin/kube-apiserver --allow-privileged --audit-log-maxage=30"}
+	expected := map[string]string{
+		"log-file":         "/var/log/kube-apiserver.log",
+		"allow-privileged": "true",
+		"audit-log-maxage": "30",
+	}
+	if actual := ParseFlags(args); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected flags %v, got %v", expected, actual)
+	}
+}
+
+func TestRun(t *testing.T) {
+	components := []*Component{
+		{
+			Name:   "kube-scheduler",
+			Object: "kube-system/kube-scheduler-a",
+			Flags:  map[string]string{"profiling": "false"},
+		},
+		{
+			Name:   "kube-apiserver",
+			Object: "kube-system/kube-apiserver-a",
+			Flags: map[string]string{
+				"authorization-mode":       "Node,RBAC",
+				"enable-admission-plugins": "NodeRestriction,EventRateLimit",
+				"audit-log-maxage":         "7",
+			},
+		},
+		{
+			Name:   "etcd-manager",
+			Object: "kube-system/etcd-manager-main-a",
+			Env:    map[string]string{"ETCD_CLIENT_CERT_AUTH": "true", "ETCD_PEER_CLIENT_CERT_AUTH": "true"},
+		},
+	}
+
+	kubeletConfig := &KubeletConfig{}
+	if err := json.Unmarshal([]byte(`{"authentication": {"anonymous": {"enabled": false}, "x509": {"clientCAFile": "/srv/kubernetes/ca.crt"}}, "authorization": {"mode": "Webhook"}, "readOnlyPort": 10255}`), kubeletConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	nodes := []*Node{{Name: "node-a", KubeletConfig: kubeletConfig}}
+
+	results := make(map[string]Result)
+	var ids []string
+	for _, finding := range Run(components, nodes) {
+		results[finding.ID] = finding.Result
+		ids = append(ids, finding.ID)
+	}
+
+	expected := map[string]Result{
+		"1.2.2":  ResultPass,
+		"1.2.5":  ResultWarn,
+		"1.2.7":  ResultPass,
+		"1.2.9":  ResultPass,
+		"1.2.11": ResultWarn,
+		"1.2.18": ResultFail,
+		"1.2.20": ResultFail,
+		"1.4.1":  ResultPass,
+		"2.2":    ResultPass,
+		"2.3":    ResultPass,
+		"4.2.1":  ResultPass,
+		"4.2.2":  ResultPass,
+		"4.2.4":  ResultFail,
+		"4.2.6":  ResultFail,
+	}
+	for id, result := range expected {
+		if results[id] != result {
+			t.Errorf("expected %s for %s, got %q", result, id, results[id])
+		}
+	}
+	if _, found := results["1.3.1"]; found {
+		t.Errorf("unexpected finding for the missing kube-controller-manager")
+	}
+
+	// The findings are sorted numerically
+	if ids[0] != "1.2.2" || ids[len(ids)-1] != "4.2.13" {
+		t.Errorf("unexpected order of findings %v", ids)
+	}
+	for i, id := range ids {
+		if id == "1.2.10" && ids[i-1] != "1.2.9" {
+			t.Errorf("expected 1.2.10 after 1.2.9, got %v", ids)
+		}
+	}
+}
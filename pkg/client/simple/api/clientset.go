@@ -18,12 +18,18 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
+	"os"
 	"strings"
 
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/registry"
@@ -31,15 +37,21 @@ import (
 	kopsinternalversion "k8s.io/kops/pkg/client/clientset_generated/clientset/typed/kops/internalversion"
 	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/pkg/client/simple/vfsclientset"
+	"k8s.io/kops/pkg/kubemanifest"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/secrets"
 	"k8s.io/kops/util/pkg/vfs"
 )
 
-// RESTClientset is an implementation of clientset that uses a "real" generated REST client
+// RESTClientset is an implementation of clientset that uses a "real" generated REST client.
+// Against a management cluster with the kOps CRDs installed, it stores each cluster, with its instance groups,
+// keysets and SSH credentials, in a namespace named after the cluster.
 type RESTClientset struct {
 	BaseURL    *url.URL
 	KopsClient kopsinternalversion.KopsInterface
+
+	// KubernetesClient, if set, is used to create the namespace of a cluster when the cluster is created
+	KubernetesClient kubernetes.Interface
 }
 
 // GetCluster implements the GetCluster method of Clientset for a kubernetes-API state store
@@ -48,19 +60,47 @@ func (c *RESTClientset) GetCluster(ctx context.Context, name string) (*kops.Clus
 	return c.KopsClient.Clusters(namespace).Get(ctx, name, metav1.GetOptions{})
 }
 
-// AddonsFor fetches the AddonsClient for the cluster
+// AddonsFor fetches the AddonsClient for the cluster.
+// Addons are stored in the configBase of the cluster, alongside the rest of the configuration that nodes read.
 func (c *RESTClientset) AddonsFor(cluster *kops.Cluster) simple.AddonsClient {
-	// We should manage these directly in the cluster
-	klog.Fatalf("AddonsFor not implemented for RESTClientset")
-	return nil
+	return &restAddonsClient{cluster: cluster}
 }
 
 // CreateCluster implements the CreateCluster method of Clientset for a kubernetes-API state store
 func (c *RESTClientset) CreateCluster(ctx context.Context, cluster *kops.Cluster) (*kops.Cluster, error) {
 	namespace := restNamespaceForClusterName(cluster.Name)
+	if err := c.ensureNamespace(ctx, namespace); err != nil {
+		return nil, err
+	}
 	return c.KopsClient.Clusters(namespace).Create(ctx, cluster, metav1.CreateOptions{})
 }
 
+// ensureNamespace creates the namespace, if it does not already exist
+func (c *RESTClientset) ensureNamespace(ctx context.Context, namespace string) error {
+	if c.KubernetesClient == nil {
+		return nil
+	}
+
+	_, err := c.KubernetesClient.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	if !errors.IsNotFound(err) {
+		return fmt.Errorf("error reading namespace %q: %v", namespace, err)
+	}
+
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: namespace,
+		},
+	}
+	_, err = c.KubernetesClient.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{})
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("error creating namespace %q: %v", namespace, err)
+	}
+	return nil
+}
+
 // UpdateCluster implements the UpdateCluster method of Clientset for a kubernetes-API state store
 func (c *RESTClientset) UpdateCluster(ctx context.Context, cluster *kops.Cluster, status *kops.ClusterStatus) (*kops.Cluster, error) {
 	klog.Warningf("validating cluster update client side; needs to move to server")
@@ -76,14 +116,110 @@ func (c *RESTClientset) UpdateCluster(ctx context.Context, cluster *kops.Cluster
 	return c.KopsClient.Clusters(namespace).Update(ctx, cluster, metav1.UpdateOptions{})
 }
 
-// ConfigBaseFor implements the ConfigBaseFor method of Clientset for a kubernetes-API state store
+const (
+	// restLockLeaseName is the name of the Lease in the namespace of a cluster that locks the cluster
+	restLockLeaseName = "kops-lock"
+	// restLockInfoAnnotation is the annotation on the lock Lease that holds the vfs.LockInfo of its holder
+	restLockInfoAnnotation = "kops.k8s.io/lock-info"
+)
+
+// LockCluster implements the LockCluster method of Clientset for a kubernetes-API state store.
+// The lock is a coordination.k8s.io Lease in the namespace of the cluster, which only one run can create.
+func (c *RESTClientset) LockCluster(ctx context.Context, cluster *kops.Cluster, operation string) (vfs.Unlocker, error) {
+	if c.KubernetesClient == nil {
+		klog.Warningf("state store %s does not support locking, so concurrent operations on cluster %q can overwrite each other's changes", c.BaseURL, cluster.Name)
+		return func() error { return nil }, nil
+	}
+
+	info, err := vfs.NewLockInfo(operation)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling lock info: %v", err)
+	}
+
+	namespace := restNamespaceForClusterName(cluster.Name)
+	acquireTime := metav1.NewMicroTime(info.Created)
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        restLockLeaseName,
+			Namespace:   namespace,
+			Annotations: map[string]string{restLockInfoAnnotation: string(data)},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity: &info.ID,
+			AcquireTime:    &acquireTime,
+		},
+	}
+
+	leases := c.KubernetesClient.CoordinationV1().Leases(namespace)
+	created, err := leases.Create(ctx, lease, metav1.CreateOptions{})
+	if err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("error creating lease %s/%s: %v", namespace, restLockLeaseName, err)
+		}
+		existing, err := leases.Get(ctx, restLockLeaseName, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error reading lease %s/%s: %v", namespace, restLockLeaseName, err)
+		}
+		return nil, &vfs.LockHeldError{Lock: "lease " + namespace + "/" + restLockLeaseName, Holder: restLockHolder(existing)}
+	}
+
+	return func() error {
+		return c.deleteLockLease(context.Background(), created)
+	}, nil
+}
+
+// ForceUnlockCluster implements the ForceUnlockCluster method of Clientset for a kubernetes-API state store
+func (c *RESTClientset) ForceUnlockCluster(ctx context.Context, cluster *kops.Cluster, lockID string) error {
+	if c.KubernetesClient == nil {
+		return vfs.ErrLockingNotSupported
+	}
+
+	namespace := restNamespaceForClusterName(cluster.Name)
+	lease, err := c.KubernetesClient.CoordinationV1().Leases(namespace).Get(ctx, restLockLeaseName, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return os.ErrNotExist
+		}
+		return fmt.Errorf("error reading lease %s/%s: %v", namespace, restLockLeaseName, err)
+	}
+	if holder := restLockHolder(lease); holder.ID != lockID {
+		return fmt.Errorf("lock lease %s/%s is held with lock id %s, not %s", namespace, restLockLeaseName, holder.ID, lockID)
+	}
+	return c.deleteLockLease(ctx, lease)
+}
+
+// deleteLockLease deletes the lock Lease, but only if it is still the same Lease, so that a lock taken since is kept
+func (c *RESTClientset) deleteLockLease(ctx context.Context, lease *coordinationv1.Lease) error {
+	err := c.KubernetesClient.CoordinationV1().Leases(lease.Namespace).Delete(ctx, lease.Name, metav1.DeleteOptions{
+		Preconditions: metav1.NewUIDPreconditions(string(lease.UID)),
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting lease %s/%s: %v", lease.Namespace, lease.Name, err)
+	}
+	return nil
+}
+
+// restLockHolder returns the vfs.LockInfo of the holder of the lock Lease
+func restLockHolder(lease *coordinationv1.Lease) *vfs.LockInfo {
+	info := &vfs.LockInfo{ID: "unknown", Holder: "unknown", Operation: "unknown"}
+	if err := json.Unmarshal([]byte(lease.Annotations[restLockInfoAnnotation]), info); err != nil {
+		info.ID = fi.StringValue(lease.Spec.HolderIdentity)
+	}
+	return info
+}
+
+// ConfigBaseFor implements the ConfigBaseFor method of Clientset for a kubernetes-API state store.
+// Nodes can't read the kubernetes API of the management cluster, so the configuration they read
+// is stored in the configBase of the cluster, which must be set to a cluster-readable location.
 func (c *RESTClientset) ConfigBaseFor(cluster *kops.Cluster) (vfs.Path, error) {
-	if cluster.Spec.ConfigBase != "" {
-		return vfs.Context.BuildVfsPath(cluster.Spec.ConfigBase)
+	if cluster.Spec.ConfigBase == "" {
+		return nil, field.Required(field.NewPath("spec", "configBase"), "a cluster-readable location, such as s3://<bucket>/<cluster>, is required with a kubernetes state store")
 	}
-	// URL for clusters looks like https://<server>/apis/kops/v1alpha2/namespaces/<cluster>/clusters/<cluster>
-	// We probably want to add a subresource for full resources
-	return vfs.Context.BuildVfsPath(c.BaseURL.String())
+	return vfs.Context.BuildVfsPath(cluster.Spec.ConfigBase)
 }
 
 // ListClusters implements the ListClusters method of Clientset for a kubernetes-API state store
@@ -146,6 +282,26 @@ func (c *RESTClientset) DeleteCluster(ctx context.Context, cluster *kops.Cluster
 		}
 	}
 
+	{
+		sshCredentials, err := c.KopsClient.SSHCredentials(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("error listing SSHCredentials: %v", err)
+		}
+
+		for i := range sshCredentials.Items {
+			sshCredential := &sshCredentials.Items[i]
+			err = c.KopsClient.SSHCredentials(namespace).Delete(ctx, sshCredential.Name, metav1.DeleteOptions{})
+			if err != nil {
+				if errors.IsNotFound(err) {
+					// Unlikely...
+					klog.Warningf("SSHCredential was concurrently deleted")
+				} else {
+					return fmt.Errorf("error deleting SSHCredential %q: %v", sshCredential.Name, err)
+				}
+			}
+		}
+	}
+
 	{
 		igs, err := c.KopsClient.InstanceGroups(namespace).List(ctx, metav1.ListOptions{})
 		if err != nil {
@@ -179,6 +335,39 @@ func (c *RESTClientset) DeleteCluster(ctx context.Context, cluster *kops.Cluster
 	return nil
 }
 
+// restAddonsClient stores the addons of a cluster in its configBase
+type restAddonsClient struct {
+	cluster *kops.Cluster
+}
+
+var _ simple.AddonsClient = &restAddonsClient{}
+
+func (c *restAddonsClient) client() (simple.AddonsClient, error) {
+	configBase, err := registry.ConfigBase(c.cluster)
+	if err != nil {
+		return nil, err
+	}
+	return vfsclientset.NewAddonsClient(configBase, c.cluster), nil
+}
+
+// Replace implements AddonsClient::Replace
+func (c *restAddonsClient) Replace(objects kubemanifest.ObjectList) error {
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+	return client.Replace(objects)
+}
+
+// List implements AddonsClient::List
+func (c *restAddonsClient) List() (kubemanifest.ObjectList, error) {
+	client, err := c.client()
+	if err != nil {
+		return nil, err
+	}
+	return client.List()
+}
+
 func restNamespaceForClusterName(clusterName string) string {
 	// We are not allowed dots, so we map them to dashes
 	// This can conflict, but this will simply be a limitation that we pass on to the user
@@ -0,0 +1,207 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kops/pkg/apis/kops"
+	kopsfake "k8s.io/kops/pkg/client/clientset_generated/clientset/fake"
+	"k8s.io/kops/pkg/kubemanifest"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func newTestClientset() *RESTClientset {
+	vfs.Context.ResetMemfsContext(true)
+
+	return &RESTClientset{
+		KopsClient:       kopsfake.NewSimpleClientset().Kops(),
+		KubernetesClient: k8sfake.NewSimpleClientset(),
+	}
+}
+
+func newTestCluster() *kops.Cluster {
+	return &kops.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test.example.com",
+		},
+		Spec: kops.ClusterSpec{
+			ConfigBase: "memfs://tests/test.example.com",
+		},
+	}
+}
+
+func TestCreateClusterCreatesNamespace(t *testing.T) {
+	ctx := context.TODO()
+	c := newTestClientset()
+
+	if _, err := c.CreateCluster(ctx, newTestCluster()); err != nil {
+		t.Fatalf("unexpected error creating cluster: %v", err)
+	}
+
+	if _, err := c.KubernetesClient.CoreV1().Namespaces().Get(ctx, "test-example-com", metav1.GetOptions{}); err != nil {
+		t.Fatalf("expected namespace to be created: %v", err)
+	}
+
+	cluster, err := c.GetCluster(ctx, "test.example.com")
+	if err != nil {
+		t.Fatalf("unexpected error reading cluster: %v", err)
+	}
+	if cluster.Spec.ConfigBase != "memfs://tests/test.example.com" {
+		t.Fatalf("unexpected cluster %v", cluster)
+	}
+}
+
+func TestConfigBaseForRequiresConfigBase(t *testing.T) {
+	c := newTestClientset()
+
+	cluster := newTestCluster()
+	cluster.Spec.ConfigBase = ""
+	if _, err := c.ConfigBaseFor(cluster); err == nil {
+		t.Fatalf("expected error without spec.configBase")
+	}
+
+	cluster.Spec.ConfigBase = "memfs://tests/test.example.com"
+	configBase, err := c.ConfigBaseFor(cluster)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if configBase.Path() != "memfs://tests/test.example.com" {
+		t.Fatalf("unexpected configBase %q", configBase.Path())
+	}
+}
+
+func TestAddonsAreStoredInConfigBase(t *testing.T) {
+	c := newTestClientset()
+	cluster := newTestCluster()
+
+	addons, err := kubemanifest.LoadObjectsFrom([]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: test\n"))
+	if err != nil {
+		t.Fatalf("unexpected error parsing addons: %v", err)
+	}
+	if err := c.AddonsFor(cluster).Replace(addons); err != nil {
+		t.Fatalf("unexpected error writing addons: %v", err)
+	}
+
+	if _, err := vfs.Context.ReadFile("memfs://tests/test.example.com/clusteraddons/default"); err != nil {
+		t.Fatalf("expected addons in configBase: %v", err)
+	}
+
+	actual, err := c.AddonsFor(cluster).List()
+	if err != nil {
+		t.Fatalf("unexpected error listing addons: %v", err)
+	}
+	if len(actual) != 1 {
+		t.Fatalf("unexpected addons %v", actual)
+	}
+}
+
+func TestDeleteCluster(t *testing.T) {
+	ctx := context.TODO()
+	c := newTestClientset()
+
+	cluster, err := c.CreateCluster(ctx, newTestCluster())
+	if err != nil {
+		t.Fatalf("unexpected error creating cluster: %v", err)
+	}
+
+	namespace := restNamespaceForClusterName(cluster.Name)
+	ig := &kops.InstanceGroup{ObjectMeta: metav1.ObjectMeta{Name: "nodes"}}
+	if _, err := c.InstanceGroupsFor(cluster).Create(ctx, ig, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("unexpected error creating instance group: %v", err)
+	}
+	sshCredential := &kops.SSHCredential{ObjectMeta: metav1.ObjectMeta{Name: "admin"}}
+	if _, err := c.KopsClient.SSHCredentials(namespace).Create(ctx, sshCredential, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("unexpected error creating SSHCredential: %v", err)
+	}
+
+	if err := c.DeleteCluster(ctx, cluster); err != nil {
+		t.Fatalf("unexpected error deleting cluster: %v", err)
+	}
+
+	clusters, err := c.ListClusters(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error listing clusters: %v", err)
+	}
+	if len(clusters.Items) != 0 {
+		t.Errorf("expected no clusters, found %d", len(clusters.Items))
+	}
+	igs, err := c.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error listing instance groups: %v", err)
+	}
+	if len(igs.Items) != 0 {
+		t.Errorf("expected no instance groups, found %d", len(igs.Items))
+	}
+	sshCredentials, err := c.KopsClient.SSHCredentials(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error listing SSHCredentials: %v", err)
+	}
+	if len(sshCredentials.Items) != 0 {
+		t.Errorf("expected no SSHCredentials, found %d", len(sshCredentials.Items))
+	}
+}
+
+func TestLockCluster(t *testing.T) {
+	ctx := context.TODO()
+	c := newTestClientset()
+	cluster := newTestCluster()
+
+	unlock, err := c.LockCluster(ctx, cluster, "update cluster")
+	if err != nil {
+		t.Fatalf("unexpected error locking cluster: %v", err)
+	}
+
+	_, err = c.LockCluster(ctx, cluster, "update cluster")
+	lockHeld, ok := err.(*vfs.LockHeldError)
+	if !ok {
+		t.Fatalf("expected the lock to be held, got %v", err)
+	}
+	if lockHeld.Holder.Operation != "update cluster" {
+		t.Errorf("unexpected lock holder %+v", lockHeld.Holder)
+	}
+
+	if err := c.ForceUnlockCluster(ctx, cluster, "other"); err == nil {
+		t.Errorf("expected error releasing the lock with another lock id")
+	}
+
+	if err := unlock(); err != nil {
+		t.Fatalf("unexpected error unlocking cluster: %v", err)
+	}
+	if err := c.ForceUnlockCluster(ctx, cluster, lockHeld.Holder.ID); !os.IsNotExist(err) {
+		t.Errorf("expected the lock to be released, got %v", err)
+	}
+
+	unlock, err = c.LockCluster(ctx, cluster, "delete cluster")
+	if err != nil {
+		t.Fatalf("unexpected error locking cluster again: %v", err)
+	}
+	_, err = c.LockCluster(ctx, cluster, "update cluster")
+	if lockHeld, ok = err.(*vfs.LockHeldError); !ok {
+		t.Fatalf("expected the lock to be held, got %v", err)
+	}
+	if err := c.ForceUnlockCluster(ctx, cluster, lockHeld.Holder.ID); err != nil {
+		t.Fatalf("unexpected error releasing the lock: %v", err)
+	}
+	if _, err := c.LockCluster(ctx, cluster, "update cluster"); err != nil {
+		t.Fatalf("unexpected error locking cluster after releasing the lock: %v", err)
+	}
+}
@@ -60,6 +60,14 @@ type Clientset interface {
 
 	// DeleteCluster deletes all the state for the specified cluster
 	DeleteCluster(ctx context.Context, cluster *kops.Cluster) error
+
+	// LockCluster takes the lock on the state of the cluster for the operation, so that concurrent operations can't interleave their writes.
+	// It fails if another operation holds the lock, and returns the function that releases the lock.
+	LockCluster(ctx context.Context, cluster *kops.Cluster, operation string) (vfs.Unlocker, error)
+
+	// ForceUnlockCluster releases the lock on the state of the cluster that an operation which is no longer running left behind.
+	// The lock is only released if it is held with the lock id; if it is not held, err = os.ErrNotExist
+	ForceUnlockCluster(ctx context.Context, cluster *kops.Cluster, lockID string) error
 }
 
 // AddonsClient is a client for manipulating cluster addons
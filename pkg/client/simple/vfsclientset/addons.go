@@ -38,20 +38,21 @@ type vfsAddonsClient struct {
 
 var _ simple.AddonsClient = &vfsAddonsClient{}
 
-func newAddonsVFS(c *VFSClientset, cluster *kops.Cluster) *vfsAddonsClient {
+func newAddonsVFS(c *VFSClientset, cluster *kops.Cluster) simple.AddonsClient {
 	if cluster == nil || cluster.Name == "" {
 		klog.Fatalf("cluster / cluster.Name is required")
 	}
 
-	clusterName := cluster.Name
+	return NewAddonsClient(c.basePath.Join(cluster.Name), cluster)
+}
 
-	r := &vfsAddonsClient{
+// NewAddonsClient returns an AddonsClient that stores the addons of the cluster under its configBase
+func NewAddonsClient(configBase vfs.Path, cluster *kops.Cluster) simple.AddonsClient {
+	return &vfsAddonsClient{
 		cluster:     cluster,
-		clusterName: clusterName,
+		clusterName: cluster.Name,
+		basePath:    configBase.Join("clusteraddons"),
 	}
-	r.basePath = c.basePath.Join(clusterName, "clusteraddons")
-
-	return r
 }
 
 // TODO: Offer partial replacement?
@@ -28,6 +28,7 @@ import (
 	"k8s.io/kops/pkg/apis/kops/registry"
 	kopsinternalversion "k8s.io/kops/pkg/client/clientset_generated/clientset/typed/kops/internalversion"
 	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/envelope"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/secrets"
 	"k8s.io/kops/util/pkg/vfs"
@@ -35,12 +36,15 @@ import (
 
 type VFSClientset struct {
 	basePath vfs.Path
+
+	// versions records the versions of the configuration files we read, for optimistic concurrency
+	versions *fileVersions
 }
 
 var _ simple.Clientset = &VFSClientset{}
 
 func (c *VFSClientset) clusters() *ClusterVFS {
-	return newClusterVFS(c.basePath)
+	return newClusterVFS(c.basePath, c.versions)
 }
 
 // GetCluster implements the GetCluster method of simple.Clientset for a VFS-backed state store
@@ -87,10 +91,13 @@ func (c *VFSClientset) SecretStore(cluster *kops.Cluster) (fi.SecretStore, error
 			return nil, err
 		}
 		basedir := configBase.Join("secrets")
-		return secrets.NewVFSSecretStore(cluster, basedir), nil
+		return secrets.NewVFSSecretStore(cluster, envelope.ForCluster(cluster, basedir)), nil
 	} else {
 		storePath, err := vfs.Context.BuildVfsPath(cluster.Spec.SecretStore)
-		return secrets.NewVFSSecretStore(cluster, storePath), err
+		if err != nil {
+			return nil, err
+		}
+		return secrets.NewVFSSecretStore(cluster, envelope.ForCluster(cluster, storePath)), nil
 	}
 }
 
@@ -120,10 +127,13 @@ func pkiPath(cluster *kops.Cluster) (vfs.Path, error) {
 		if err != nil {
 			return nil, err
 		}
-		return configBase.Join("pki"), nil
+		return envelope.ForCluster(cluster, configBase.Join("pki")), nil
 	} else {
 		storePath, err := vfs.Context.BuildVfsPath(cluster.Spec.KeyStore)
-		return storePath, err
+		if err != nil {
+			return nil, err
+		}
+		return envelope.ForCluster(cluster, storePath), nil
 	}
 }
 
@@ -143,7 +153,7 @@ func DeleteAllClusterState(basePath vfs.Path) error {
 			continue
 		}
 
-		if relativePath == "config" || relativePath == "cluster.spec" || relativePath == registry.PathKopsVersionUpdated {
+		if relativePath == "config" || relativePath == "cluster.spec" || relativePath == registry.PathKopsVersionUpdated || relativePath == registry.PathLock || relativePath == registry.PathTaskCache {
 			continue
 		}
 		if strings.HasPrefix(relativePath, "addons/") {
@@ -173,6 +183,10 @@ func DeleteAllClusterState(basePath vfs.Path) error {
 	}
 
 	for _, path := range paths {
+		if relativePath, _ := vfs.RelativePath(basePath, path); relativePath == registry.PathLock {
+			// The lock is released by the operation deleting the cluster
+			continue
+		}
 		err = path.Remove()
 		if err != nil {
 			return fmt.Errorf("error deleting cluster file %s: %v", path, err)
@@ -230,9 +244,47 @@ func (c *VFSClientset) DeleteCluster(ctx context.Context, cluster *kops.Cluster)
 	return DeleteAllClusterState(configBase)
 }
 
+// LockCluster implements the LockCluster method of simple.Clientset for a VFS-backed state store
+func (c *VFSClientset) LockCluster(ctx context.Context, cluster *kops.Cluster, operation string) (vfs.Unlocker, error) {
+	configBase, err := c.ConfigBaseFor(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := vfs.NewLockInfo(operation)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := vfs.Lock(configBase.Join(registry.PathLock), info)
+	if err != nil {
+		if err == vfs.ErrLockingNotSupported {
+			hint := ""
+			if _, ok := configBase.(*vfs.S3Path); ok {
+				hint = fmt.Sprintf("; set %s to a DynamoDB table to lock S3 state stores", vfs.S3LockTableEnv)
+			}
+			klog.Warningf("state store %s does not support locking, so concurrent operations on cluster %q can overwrite each other's changes%s", configBase, cluster.Name, hint)
+			return func() error { return nil }, nil
+		}
+		return nil, err
+	}
+	return unlock, nil
+}
+
+// ForceUnlockCluster implements the ForceUnlockCluster method of simple.Clientset for a VFS-backed state store
+func (c *VFSClientset) ForceUnlockCluster(ctx context.Context, cluster *kops.Cluster, lockID string) error {
+	configBase, err := c.ConfigBaseFor(cluster)
+	if err != nil {
+		return err
+	}
+
+	return vfs.ForceUnlock(configBase.Join(registry.PathLock), lockID)
+}
+
 func NewVFSClientset(basePath vfs.Path) simple.Clientset {
 	vfsClientset := &VFSClientset{
 		basePath: basePath,
+		versions: newFileVersions(),
 	}
 	return vfsClientset
 }
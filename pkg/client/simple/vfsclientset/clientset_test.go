@@ -17,8 +17,11 @@ limitations under the License.
 package vfsclientset
 
 import (
+	"context"
+	"os"
 	"testing"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/util/pkg/vfs"
 )
@@ -70,3 +73,105 @@ func TestSSHCredentialStoreOnOwnCFS(t *testing.T) {
 		t.Errorf("Expected %v, got %v", expected, actual)
 	}
 }
+
+func TestWriteConfigDetectsConcurrentWrites(t *testing.T) {
+	vfs.Context.ResetMemfsContext(true)
+	basePath, err := vfs.Context.BuildVfsPath("memfs://some/config/base/instancegroup")
+	if err != nil {
+		t.Fatalf("error building path: %v", err)
+	}
+	cluster := &kops.Cluster{}
+	configPath := basePath.Join("nodes")
+
+	newClient := func() *commonVFS {
+		c := &commonVFS{}
+		c.init("InstanceGroup", basePath, StoreVersion)
+		c.versions = newFileVersions()
+		return c
+	}
+	first := newClient()
+	second := newClient()
+
+	ig := &kops.InstanceGroup{ObjectMeta: metav1.ObjectMeta{Name: "nodes"}}
+	if err := first.writeConfig(cluster, configPath, ig, vfs.WriteOptionCreate); err != nil {
+		t.Fatalf("error creating InstanceGroup: %v", err)
+	}
+	if err := second.writeConfig(cluster, configPath, ig, vfs.WriteOptionCreate); !os.IsExist(err) {
+		t.Errorf("expected creating existing InstanceGroup to fail with os.ErrExist, got: %v", err)
+	}
+
+	// Both read the InstanceGroup, then write it
+	if _, err := second.readConfig(configPath); err != nil {
+		t.Fatalf("error reading InstanceGroup: %v", err)
+	}
+	if _, err := first.readConfig(configPath); err != nil {
+		t.Fatalf("error reading InstanceGroup: %v", err)
+	}
+
+	ig.Spec.Role = kops.InstanceGroupRoleNode
+	if err := first.writeConfig(cluster, configPath, ig, vfs.WriteOptionOnlyIfExists); err != nil {
+		t.Fatalf("error updating InstanceGroup: %v", err)
+	}
+	if err := first.writeConfig(cluster, configPath, ig, vfs.WriteOptionOnlyIfExists); err != nil {
+		t.Errorf("error updating InstanceGroup again: %v", err)
+	}
+
+	ig.Spec.Role = kops.InstanceGroupRoleMaster
+	if err := second.writeConfig(cluster, configPath, ig, vfs.WriteOptionOnlyIfExists); err == nil {
+		t.Errorf("expected the stale update of the InstanceGroup to fail")
+	}
+
+	o, err := newClient().readConfig(configPath)
+	if err != nil {
+		t.Fatalf("error reading InstanceGroup: %v", err)
+	}
+	if role := o.(*kops.InstanceGroup).Spec.Role; role != kops.InstanceGroupRoleNode {
+		t.Errorf("expected InstanceGroup role %q, got %q", kops.InstanceGroupRoleNode, role)
+	}
+}
+
+func TestForceUnlockCluster(t *testing.T) {
+	ctx := context.TODO()
+	vfs.Context.ResetMemfsContext(true)
+	basePath, err := vfs.Context.BuildVfsPath("memfs://some/state/store")
+	if err != nil {
+		t.Fatalf("error building path: %v", err)
+	}
+	clientset := NewVFSClientset(basePath)
+	cluster := &kops.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "minimal.example.com"},
+		Spec:       kops.ClusterSpec{ConfigBase: "memfs://some/state/store/minimal.example.com"},
+	}
+
+	if err := clientset.ForceUnlockCluster(ctx, cluster, "abc"); !os.IsNotExist(err) {
+		t.Errorf("expected os.ErrNotExist releasing a lock that is not held, got: %v", err)
+	}
+
+	if _, err := clientset.LockCluster(ctx, cluster, "update cluster"); err != nil {
+		t.Fatalf("error locking cluster: %v", err)
+	}
+	_, err = clientset.LockCluster(ctx, cluster, "edit cluster")
+	lockHeld, ok := err.(*vfs.LockHeldError)
+	if !ok {
+		t.Fatalf("expected LockHeldError, got: %v", err)
+	}
+
+	// The stale lock survives deleting the cluster state, and is released by its id
+	if err := DeleteAllClusterState(basePath.Join(cluster.Name)); err != nil {
+		t.Fatalf("error deleting cluster state: %v", err)
+	}
+	if err := clientset.ForceUnlockCluster(ctx, cluster, "abc"); err == nil {
+		t.Errorf("expected releasing the lock with another lock id to fail")
+	}
+	if err := clientset.ForceUnlockCluster(ctx, cluster, lockHeld.Holder.ID); err != nil {
+		t.Fatalf("error releasing lock: %v", err)
+	}
+
+	unlock, err := clientset.LockCluster(ctx, cluster, "edit cluster")
+	if err != nil {
+		t.Fatalf("error locking released cluster: %v", err)
+	}
+	if err := unlock(); err != nil {
+		t.Errorf("error releasing lock: %v", err)
+	}
+}
@@ -40,9 +40,10 @@ type ClusterVFS struct {
 	commonVFS
 }
 
-func newClusterVFS(basePath vfs.Path) *ClusterVFS {
+func newClusterVFS(basePath vfs.Path, versions *fileVersions) *ClusterVFS {
 	c := &ClusterVFS{}
 	c.init("Cluster", basePath, StoreVersion)
+	c.versions = versions
 	return c
 }
 
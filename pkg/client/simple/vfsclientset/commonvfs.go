@@ -23,6 +23,7 @@ import (
 	"os"
 	"reflect"
 	"sort"
+	"sync"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -45,6 +46,54 @@ type commonVFS struct {
 	basePath vfs.Path
 	encoder  runtime.Encoder
 	validate ValidationFunction
+
+	// versions records the versions of the files we read, if the state store supports optimistic concurrency
+	versions *fileVersions
+}
+
+// fileVersions records the version of each file when it was first read,
+// so that it is only overwritten if nobody else wrote it in the meantime
+type fileVersions struct {
+	mutex    sync.Mutex
+	versions map[string]string
+}
+
+func newFileVersions() *fileVersions {
+	return &fileVersions{versions: make(map[string]string)}
+}
+
+func (v *fileVersions) get(p vfs.Path) (string, bool) {
+	if v == nil {
+		return "", false
+	}
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	version, found := v.versions[p.Path()]
+	return version, found
+}
+
+// recordRead records the version of the file, unless we already read (or wrote) it before
+func (v *fileVersions) recordRead(p vfs.Path, version string) {
+	if v == nil {
+		return
+	}
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	if _, found := v.versions[p.Path()]; !found {
+		v.versions[p.Path()] = version
+	}
+}
+
+func (v *fileVersions) recordWrite(p vfs.Path, version string) {
+	if v == nil {
+		return
+	}
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	v.versions[p.Path()] = version
 }
 
 func (c *commonVFS) init(kind string, basePath vfs.Path, storeVersion runtime.GroupVersioner) {
@@ -114,7 +163,7 @@ func (c *commonVFS) serialize(o runtime.Object) ([]byte, error) {
 }
 
 func (c *commonVFS) readConfig(configPath vfs.Path) (runtime.Object, error) {
-	data, err := configPath.ReadFile()
+	data, err := c.readFile(configPath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, err
@@ -129,6 +178,52 @@ func (c *commonVFS) readConfig(configPath vfs.Path) (runtime.Object, error) {
 	return object, nil
 }
 
+// readFile reads the file, recording its version if the path supports optimistic concurrency
+func (c *commonVFS) readFile(configPath vfs.Path) ([]byte, error) {
+	versioned, ok := configPath.(vfs.VersionedPath)
+	if !ok || c.versions == nil {
+		return configPath.ReadFile()
+	}
+
+	data, version, err := versioned.ReadFileVersion()
+	if err != nil {
+		return nil, err
+	}
+	c.versions.recordRead(configPath, version)
+	return data, nil
+}
+
+// writeFile writes the file.  If the path supports optimistic concurrency and we read the file before,
+// it is only written if it was not written by anyone else since.
+func (c *commonVFS) writeFile(configPath vfs.Path, data []byte, acl vfs.ACL, create bool) error {
+	rs := bytes.NewReader(data)
+
+	versioned, ok := configPath.(vfs.VersionedPath)
+	version, found := c.versions.get(configPath)
+	if create {
+		version, found = "", true
+	}
+	if !ok || c.versions == nil || !found {
+		if create {
+			return configPath.CreateFile(rs, acl)
+		}
+		return configPath.WriteFile(rs, acl)
+	}
+
+	newVersion, err := versioned.WriteFileIfVersion(rs, acl, version)
+	if err != nil {
+		if err == vfs.ErrVersionConflict {
+			if create {
+				return os.ErrExist
+			}
+			return fmt.Errorf("%s was changed by another operation since it was read; please retry", configPath)
+		}
+		return err
+	}
+	c.versions.recordWrite(configPath, newVersion)
+	return nil
+}
+
 func (c *commonVFS) writeConfig(cluster *kops.Cluster, configPath vfs.Path, o runtime.Object, writeOptions ...vfs.WriteOption) error {
 	data, err := c.serialize(o)
 	if err != nil {
@@ -158,12 +253,7 @@ func (c *commonVFS) writeConfig(cluster *kops.Cluster, configPath vfs.Path, o ru
 		return err
 	}
 
-	rs := bytes.NewReader(data)
-	if create {
-		err = configPath.CreateFile(rs, acl)
-	} else {
-		err = configPath.WriteFile(rs, acl)
-	}
+	err = c.writeFile(configPath, data, acl, create)
 	if err != nil {
 		if create && os.IsExist(err) {
 			klog.Warningf("failed to create file as already exists: %v", configPath)
@@ -66,6 +66,14 @@ func NewInstanceGroupMirror(cluster *kopsapi.Cluster, configBase vfs.Path) Insta
 	return r
 }
 
+// NewInstanceGroupClient returns a client for the instance groups stored under the configBase of a cluster.
+// It is for callers, such as kops-controller, that only know the configBase and not the state store.
+func NewInstanceGroupClient(cluster *kopsapi.Cluster, configBase vfs.Path) kopsinternalversion.InstanceGroupInterface {
+	r := NewInstanceGroupMirror(cluster, configBase).(*InstanceGroupVFS)
+	r.versions = newFileVersions()
+	return r
+}
+
 func newInstanceGroupVFS(c *VFSClientset, cluster *kopsapi.Cluster) *InstanceGroupVFS {
 	if cluster == nil || cluster.Name == "" {
 		klog.Fatalf("cluster / cluster.Name is required")
@@ -79,6 +87,7 @@ func newInstanceGroupVFS(c *VFSClientset, cluster *kopsapi.Cluster) *InstanceGro
 		clusterName: clusterName,
 	}
 	r.init(kind, c.basePath.Join(clusterName, "instancegroup"), StoreVersion)
+	r.versions = c.versions
 	r.validate = func(o runtime.Object) error {
 		return validation.ValidateInstanceGroup(o.(*kopsapi.InstanceGroup), nil).ToAggregate()
 	}
@@ -0,0 +1,225 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cloudapi accounts for the requests kOps makes to cloud provider APIs,
+// and applies client-side rate limits to them.
+package cloudapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/klog/v2"
+)
+
+// OperationStats counts the calls made to an operation of a cloud provider API.
+type OperationStats struct {
+	Provider  string
+	API       string
+	Operation string
+
+	// Calls is the number of calls made, not counting retries.
+	Calls int
+	// Errors is the number of calls that failed after all retries.
+	Errors int
+	// Retries is the number of times calls were retried.
+	Retries int
+	// Throttled is the number of attempts that the API rejected because of its rate limit.
+	Throttled int
+	// Latency is the total time taken by the calls, including retries.
+	Latency time.Duration
+	// MaxLatency is the time taken by the slowest call.
+	MaxLatency time.Duration
+}
+
+// AverageLatency is the average time taken by a call.
+func (s *OperationStats) AverageLatency() time.Duration {
+	if s.Calls == 0 {
+		return 0
+	}
+	return s.Latency / time.Duration(s.Calls)
+}
+
+type operationKey struct {
+	provider  string
+	api       string
+	operation string
+}
+
+type recorder struct {
+	mutex      sync.Mutex
+	operations map[operationKey]*OperationStats
+	limiters   map[string]*rate.Limiter
+}
+
+var defaultRecorder = &recorder{
+	operations: make(map[operationKey]*OperationStats),
+	limiters:   make(map[string]*rate.Limiter),
+}
+
+func (r *recorder) get(provider, api, operation string) *OperationStats {
+	key := operationKey{provider: provider, api: api, operation: operation}
+	stats := r.operations[key]
+	if stats == nil {
+		stats = &OperationStats{Provider: provider, API: api, Operation: operation}
+		r.operations[key] = stats
+	}
+	return stats
+}
+
+// RecordCall records a completed call to an operation of a cloud provider API.
+func RecordCall(provider, api, operation string, latency time.Duration, retries int, err error) {
+	klog.V(4).InfoS("Cloud API call", "provider", provider, "api", api, "operation", operation, "latency", latency, "retries", retries, "err", err)
+
+	r := defaultRecorder
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stats := r.get(provider, api, operation)
+	stats.Calls++
+	stats.Retries += retries
+	if err != nil {
+		stats.Errors++
+	}
+	stats.Latency += latency
+	if latency > stats.MaxLatency {
+		stats.MaxLatency = latency
+	}
+}
+
+// RecordThrottled records an attempt that the cloud provider API rejected because of its rate limit.
+func RecordThrottled(provider, api, operation string) {
+	klog.V(2).InfoS("Cloud API request was throttled", "provider", provider, "api", api, "operation", operation)
+
+	r := defaultRecorder
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.get(provider, api, operation).Throttled++
+}
+
+// Stats returns the calls recorded so far, with the most called operations first.
+func Stats() []*OperationStats {
+	r := defaultRecorder
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var stats []*OperationStats
+	for _, s := range r.operations {
+		c := *s
+		stats = append(stats, &c)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Calls != stats[j].Calls {
+			return stats[i].Calls > stats[j].Calls
+		}
+		if stats[i].API != stats[j].API {
+			return stats[i].API < stats[j].API
+		}
+		return stats[i].Operation < stats[j].Operation
+	})
+	return stats
+}
+
+// Reset forgets the calls recorded so far.
+func Reset() {
+	r := defaultRecorder
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.operations = make(map[operationKey]*OperationStats)
+}
+
+// SetRateLimits limits the requests made to each API, keyed by API name, to the given number per second.
+// APIs that are not listed, or have a limit of zero, are not limited.
+func SetRateLimits(limits map[string]int) error {
+	limiters := make(map[string]*rate.Limiter)
+	for api, limit := range limits {
+		if limit < 0 {
+			return fmt.Errorf("invalid rate limit %d for API %q", limit, api)
+		}
+		if limit > 0 {
+			limiters[api] = rate.NewLimiter(rate.Limit(limit), limit)
+		}
+	}
+
+	r := defaultRecorder
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.limiters = limiters
+	return nil
+}
+
+// WaitForRateLimit blocks until a request can be made to the API without exceeding its rate limit.
+func WaitForRateLimit(ctx context.Context, api string) error {
+	r := defaultRecorder
+	r.mutex.Lock()
+	limiter := r.limiters[api]
+	r.mutex.Unlock()
+
+	if limiter == nil {
+		return nil
+	}
+	return limiter.Wait(ctx)
+}
+
+// PrintSummary prints the number of calls made to each API, and the details of the most called operations.
+func PrintSummary(out io.Writer, stats []*OperationStats, maxOperations int) error {
+	if len(stats) == 0 {
+		return nil
+	}
+
+	calls := 0
+	throttled := 0
+	byAPI := make(map[string]int)
+	for _, s := range stats {
+		calls += s.Calls
+		throttled += s.Throttled
+		byAPI[s.API] += s.Calls
+	}
+	var apis []string
+	for api := range byAPI {
+		apis = append(apis, api)
+	}
+	sort.Strings(apis)
+
+	fmt.Fprintf(out, "\n")
+	fmt.Fprintf(out, "Made %d cloud API calls; %d attempts were throttled.", calls, throttled)
+	for i, api := range apis {
+		if i == 0 {
+			fmt.Fprintf(out, " Calls by API:")
+		}
+		fmt.Fprintf(out, " %s=%d", api, byAPI[api])
+	}
+	fmt.Fprintf(out, "\n")
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "  API\tOPERATION\tCALLS\tERRORS\tRETRIES\tTHROTTLED\tAVG LATENCY\tMAX LATENCY\n")
+	for i, s := range stats {
+		if i == maxOperations {
+			break
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%d\t%d\t%d\t%d\t%v\t%v\n", s.API, s.Operation, s.Calls, s.Errors, s.Retries, s.Throttled, s.AverageLatency().Round(time.Millisecond), s.MaxLatency.Round(time.Millisecond))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	Reset()
+	defer Reset()
+
+	RecordCall("aws", "ec2", "DescribeVpcs", 100*time.Millisecond, 0, nil)
+	RecordThrottled("aws", "ec2", "DescribeVpcs")
+	RecordCall("aws", "ec2", "DescribeVpcs", 300*time.Millisecond, 1, nil)
+	RecordCall("aws", "iam", "GetRole", 50*time.Millisecond, 0, errors.New("NoSuchEntity"))
+
+	stats := Stats()
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 operations, got %d", len(stats))
+	}
+	vpcs := stats[0]
+	if vpcs.Operation != "DescribeVpcs" || vpcs.Calls != 2 || vpcs.Retries != 1 || vpcs.Throttled != 1 || vpcs.Errors != 0 {
+		t.Errorf("unexpected stats: %+v", vpcs)
+	}
+	if vpcs.AverageLatency() != 200*time.Millisecond || vpcs.MaxLatency != 300*time.Millisecond {
+		t.Errorf("unexpected latencies: average %v, max %v", vpcs.AverageLatency(), vpcs.MaxLatency)
+	}
+	if role := stats[1]; role.Operation != "GetRole" || role.Calls != 1 || role.Errors != 1 {
+		t.Errorf("unexpected stats: %+v", role)
+	}
+
+	var out bytes.Buffer
+	if err := PrintSummary(&out, stats, 10); err != nil {
+		t.Fatalf("error printing summary: %v", err)
+	}
+	if !strings.Contains(out.String(), "Made 3 cloud API calls; 1 attempts were throttled. Calls by API: ec2=2 iam=1\n") {
+		t.Errorf("unexpected summary:\n%s", out.String())
+	}
+}
+
+func TestRateLimits(t *testing.T) {
+	if err := SetRateLimits(map[string]int{"ec2": 10, "iam": 0}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer SetRateLimits(nil)
+
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 15; i++ {
+		if err := WaitForRateLimit(ctx, "ec2"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := WaitForRateLimit(ctx, "iam"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// The burst of 10 is allowed immediately, then requests are made every 100ms
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("expected requests to be rate limited, took %v", elapsed)
+	}
+
+	if err := SetRateLimits(map[string]int{"ec2": -1}); err == nil {
+		t.Errorf("expected error for negative rate limit")
+	}
+}
@@ -16,7 +16,12 @@ limitations under the License.
 
 package cloudinstances
 
-import v1 "k8s.io/api/core/v1"
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kops/pkg/apis/kops/util"
+)
 
 // CloudInstanceStatusDetached means the instance needs update and has been detached.
 const CloudInstanceStatusDetached = "Detached"
@@ -32,6 +37,28 @@ type State string
 // WarmPool means the instance is in the warm pool
 const WarmPool State = "WarmPool"
 
+// NeedUpdateReasonConfigurationChanged means the instance does not match the current configuration of its group,
+// such as its launch template or instance template.
+const NeedUpdateReasonConfigurationChanged = "ConfigurationChanged"
+
+// NeedUpdateReasonDetached means the instance has been detached from its group to be replaced.
+const NeedUpdateReasonDetached = "Detached"
+
+// NeedUpdateReasonNodeAnnotated means the node has the kops.k8s.io/needs-update annotation, which addons requiring
+// a rolling update place on nodes.
+const NeedUpdateReasonNodeAnnotated = "NodeAnnotated"
+
+// NeedUpdateReasonKubeletVersionSkew means the kubelet of the node does not run the cluster's Kubernetes version.
+const NeedUpdateReasonKubeletVersionSkew = "KubeletVersionSkew"
+
+// NeedUpdateReason explains why an instance needs update.
+type NeedUpdateReason struct {
+	// Type is one of the NeedUpdateReason constants
+	Type string `json:"type"`
+	// Message is a human-readable explanation
+	Message string `json:"message,omitempty"`
+}
+
 // CloudInstance describes an instance in a CloudInstanceGroup group.
 type CloudInstance struct {
 	// ID is a unique identifier for the instance, meaningful to the cloud
@@ -50,4 +77,41 @@ type CloudInstance struct {
 	PrivateIP string
 	// State is in which state the instance is in
 	State State
+	// NeedUpdateReasons are the reasons the instance needs update
+	NeedUpdateReasons []NeedUpdateReason
+}
+
+// AddNeedUpdateReason records a reason the instance needs update, unless a reason of the same type has been recorded.
+func (c *CloudInstance) AddNeedUpdateReason(reasonType string, message string) {
+	for _, reason := range c.NeedUpdateReasons {
+		if reason.Type == reasonType {
+			return
+		}
+	}
+	c.NeedUpdateReasons = append(c.NeedUpdateReasons, NeedUpdateReason{Type: reasonType, Message: message})
+}
+
+// AddKubeletVersionSkew records that the kubelet of the node does not run the given Kubernetes version.
+// This is informational: it does not make the instance need update, as the instance is only replaced once its
+// configuration has been updated for the new version.
+func (c *CloudInstance) AddKubeletVersionSkew(kubernetesVersion string) {
+	if c.Node == nil || kubernetesVersion == "" {
+		return
+	}
+	kubeletVersion := c.Node.Status.NodeInfo.KubeletVersion
+	if kubeletVersion == "" {
+		return
+	}
+
+	expected, err := util.ParseKubernetesVersion(kubernetesVersion)
+	if err != nil {
+		return
+	}
+	actual, err := util.ParseKubernetesVersion(kubeletVersion)
+	if err != nil {
+		return
+	}
+	if actual.Major != expected.Major || actual.Minor != expected.Minor || actual.Patch != expected.Patch {
+		c.AddNeedUpdateReason(NeedUpdateReasonKubeletVersionSkew, fmt.Sprintf("kubelet runs %s, cluster is configured for %s", kubeletVersion, kubernetesVersion))
+	}
 }
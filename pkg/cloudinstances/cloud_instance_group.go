@@ -74,21 +74,30 @@ func (c *CloudInstanceGroup) Status() string {
 	return "NeedsUpdate"
 }
 
+// AdjustNeedUpdate marks the instances whose nodes have the kops.k8s.io/needs-update annotation as needing update,
+// and records why each instance needs update.
 func (group *CloudInstanceGroup) AdjustNeedUpdate() {
+	for _, member := range group.NeedUpdate {
+		// Explain why the cloud reported the instance as needing update, unless it already has
+		if len(member.NeedUpdateReasons) == 0 {
+			if member.Status == CloudInstanceStatusDetached {
+				member.AddNeedUpdateReason(NeedUpdateReasonDetached, "instance has been detached from its group to be replaced")
+			} else {
+				member.AddNeedUpdateReason(NeedUpdateReasonConfigurationChanged, "instance does not match the current configuration of its group")
+			}
+		}
+		if hasNeedsUpdateAnnotation(member) {
+			member.AddNeedUpdateReason(NeedUpdateReasonNodeAnnotated, "node has the "+needsUpdateAnnotation+" annotation")
+		}
+	}
 
 	if group.Ready != nil {
 		var newReady []*CloudInstance
 		for _, member := range group.Ready {
-			makeNotReady := false
-			if member.Node != nil && member.Node.Annotations != nil {
-				if _, ok := member.Node.Annotations["kops.k8s.io/needs-update"]; ok {
-					makeNotReady = true
-				}
-			}
-
-			if makeNotReady {
+			if hasNeedsUpdateAnnotation(member) {
 				group.NeedUpdate = append(group.NeedUpdate, member)
 				member.Status = CloudInstanceStatusNeedsUpdate
+				member.AddNeedUpdateReason(NeedUpdateReasonNodeAnnotated, "node has the "+needsUpdateAnnotation+" annotation")
 			} else {
 				newReady = append(newReady, member)
 			}
@@ -97,6 +106,16 @@ func (group *CloudInstanceGroup) AdjustNeedUpdate() {
 	}
 }
 
+const needsUpdateAnnotation = "kops.k8s.io/needs-update"
+
+func hasNeedsUpdateAnnotation(member *CloudInstance) bool {
+	if member.Node == nil || member.Node.Annotations == nil {
+		return false
+	}
+	_, ok := member.Node.Annotations[needsUpdateAnnotation]
+	return ok
+}
+
 // GetNodeMap returns a list of nodes keyed by their external id
 func GetNodeMap(nodes []v1.Node, cluster *kopsapi.Cluster) map[string]*v1.Node {
 	nodeMap := make(map[string]*v1.Node)
@@ -114,6 +133,15 @@ func GetNodeMap(nodes []v1.Node, cluster *kopsapi.Cluster) map[string]*v1.Node {
 		return nodeMap
 	}
 
+	// Metal nodes have no provider ID, and are named after the machine
+	if kopsapi.CloudProviderID(cluster.Spec.CloudProvider) == kopsapi.CloudProviderMetal {
+		for i := range nodes {
+			node := &nodes[i]
+			nodeMap[node.Name] = node
+		}
+		return nodeMap
+	}
+
 	delimiter := "/"
 	// Alicloud CCM uses the "{region}.{instance-id}" of a instance as ProviderID.
 	// We need to set delimiter to "." for Alicloud.
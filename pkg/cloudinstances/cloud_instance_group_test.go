@@ -19,6 +19,10 @@ package cloudinstances
 import (
 	"fmt"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestToAzureVMName(t *testing.T) {
@@ -55,3 +59,57 @@ func TestToAzureVMName(t *testing.T) {
 		})
 	}
 }
+
+func TestAdjustNeedUpdate(t *testing.T) {
+	annotated := map[string]string{"kops.k8s.io/needs-update": ""}
+	group := &CloudInstanceGroup{}
+	changed, _ := group.NewCloudInstance("changed", CloudInstanceStatusNeedsUpdate, &v1.Node{ObjectMeta: metav1.ObjectMeta{Annotations: annotated}})
+	detached, _ := group.NewCloudInstance("detached", CloudInstanceStatusDetached, nil)
+	upToDate, _ := group.NewCloudInstance("up-to-date", CloudInstanceStatusUpToDate, &v1.Node{})
+	marked, _ := group.NewCloudInstance("marked", CloudInstanceStatusUpToDate, &v1.Node{ObjectMeta: metav1.ObjectMeta{Annotations: annotated}})
+
+	group.AdjustNeedUpdate()
+	// Adjusting again must not record the reasons twice
+	group.AdjustNeedUpdate()
+
+	assert.Equal(t, []*CloudInstance{upToDate}, group.Ready)
+	assert.Equal(t, []*CloudInstance{changed, detached, marked}, group.NeedUpdate)
+	assert.Equal(t, CloudInstanceStatusNeedsUpdate, marked.Status)
+
+	reasonTypes := func(c *CloudInstance) []string {
+		var types []string
+		for _, reason := range c.NeedUpdateReasons {
+			types = append(types, reason.Type)
+		}
+		return types
+	}
+	assert.Equal(t, []string{NeedUpdateReasonConfigurationChanged, NeedUpdateReasonNodeAnnotated}, reasonTypes(changed))
+	assert.Equal(t, []string{NeedUpdateReasonDetached}, reasonTypes(detached))
+	assert.Empty(t, reasonTypes(upToDate))
+	assert.Equal(t, []string{NeedUpdateReasonNodeAnnotated}, reasonTypes(marked))
+}
+
+func TestAddKubeletVersionSkew(t *testing.T) {
+	grid := []struct {
+		kubeletVersion    string
+		kubernetesVersion string
+		skewed            bool
+	}{
+		{kubeletVersion: "v1.21.1", kubernetesVersion: "1.21.1"},
+		{kubeletVersion: "v1.21.1-eks-1", kubernetesVersion: "v1.21.1"},
+		{kubeletVersion: "v1.20.7", kubernetesVersion: "1.21.1", skewed: true},
+		{kubeletVersion: "v1.21.0", kubernetesVersion: "1.21.1", skewed: true},
+		{kubeletVersion: "", kubernetesVersion: "1.21.1"},
+	}
+	for _, g := range grid {
+		instance := &CloudInstance{Node: &v1.Node{Status: v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{KubeletVersion: g.kubeletVersion}}}}
+		instance.AddKubeletVersionSkew(g.kubernetesVersion)
+		if g.skewed {
+			if assert.Len(t, instance.NeedUpdateReasons, 1, "kubelet %q, cluster %q", g.kubeletVersion, g.kubernetesVersion) {
+				assert.Equal(t, NeedUpdateReasonKubeletVersionSkew, instance.NeedUpdateReasons[0].Type)
+			}
+		} else {
+			assert.Empty(t, instance.NeedUpdateReasons, "kubelet %q, cluster %q", g.kubeletVersion, g.kubernetesVersion)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/registry"
+	"k8s.io/kops/pkg/etcdmanager"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// etcdBackupPollInterval is how often the backup store is checked while waiting for etcd-manager to restore backups
+var etcdBackupPollInterval = 10 * time.Second
+
+// LatestEtcdBackup selects the most recent backup of an etcd cluster
+const LatestEtcdBackup = "latest"
+
+type EtcdBackupOptions struct {
+	// EtcdClusters limits the command to the named etcd clusters; all clusters managed by etcd-manager are used if empty
+	EtcdClusters []string
+}
+
+type EtcdRestoreOptions struct {
+	// EtcdClusters limits the command to the named etcd clusters; all clusters managed by etcd-manager are used if empty
+	EtcdClusters []string
+	// Backups are the backups to restore, each either a backup name, "latest", or <etcd cluster>=<backup name>
+	Backups []string
+	// Yes writes the restore commands; otherwise the restore is only previewed
+	Yes bool
+	// Wait is how long to wait for etcd-manager to restore the backups; the command does not wait if zero
+	Wait time.Duration
+	// WaitForAPIServer is called once the backups are restored, to wait until the API server is serving again
+	WaitForAPIServer func(ctx context.Context) error
+}
+
+// etcdBackupTarget is an etcd cluster together with its backup store
+type etcdBackupTarget struct {
+	spec  *kops.EtcdClusterSpec
+	store *etcdmanager.BackupStore
+}
+
+// RunEtcdBackup implements the toolbox etcd-backup command logic, listing the backups that etcd-manager has taken.
+// etcd-manager takes backups on a schedule and offers no way to request one.
+func RunEtcdBackup(ctx context.Context, out io.Writer, cluster *kops.Cluster, options *EtcdBackupOptions) error {
+	targets, err := etcdBackupTargets(cluster, options.EtcdClusters)
+	if err != nil {
+		return err
+	}
+
+	return listEtcdBackups(out, targets)
+}
+
+// RunEtcdRestore implements the toolbox etcd-restore command logic
+func RunEtcdRestore(ctx context.Context, out io.Writer, cluster *kops.Cluster, options *EtcdRestoreOptions) error {
+	targets, err := etcdBackupTargets(cluster, options.EtcdClusters)
+	if err != nil {
+		return err
+	}
+
+	backupNames, err := parseEtcdRestoreBackups(targets, options.Backups)
+	if err != nil {
+		return err
+	}
+
+	commands := make([]*etcdmanager.Command, len(targets))
+	for i, target := range targets {
+		name := backupNames[target.spec.Name]
+		var backup *etcdmanager.Backup
+		if name == LatestEtcdBackup {
+			backups, err := target.store.ListBackups()
+			if err != nil {
+				return err
+			}
+			if len(backups) == 0 {
+				return fmt.Errorf("etcd cluster %q has no backups in %s", target.spec.Name, target.store.Path())
+			}
+			backup = backups[len(backups)-1]
+		} else {
+			backup, err = target.store.GetBackup(name)
+			if err != nil {
+				return err
+			}
+			if backup == nil {
+				return fmt.Errorf("backup %q of etcd cluster %q not found in %s", name, target.spec.Name, target.store.Path())
+			}
+		}
+
+		spec, err := target.store.ReadClusterSpec()
+		if err != nil {
+			return err
+		}
+		memberCount := int32(len(target.spec.Members))
+		if spec != nil && spec.MemberCount != 0 {
+			memberCount = spec.MemberCount
+		}
+		clusterSpec := &etcdmanager.ClusterSpec{
+			MemberCount: memberCount,
+			EtcdVersion: backup.Info.EtcdVersion,
+		}
+		commands[i] = &etcdmanager.Command{
+			CreateNewCluster: &etcdmanager.CreateNewClusterCommand{ClusterSpec: clusterSpec},
+			RestoreBackup:    &etcdmanager.RestoreBackupCommand{ClusterSpec: clusterSpec, Backup: backup.Name},
+		}
+		fmt.Fprintf(out, "Will restore etcd cluster %q from backup %s (etcd %s, %d members)\n", target.spec.Name, backup.Name, clusterSpec.EtcdVersion, clusterSpec.MemberCount)
+	}
+
+	if !options.Yes {
+		fmt.Fprintf(out, "\nMust specify --yes to restore the backups; the API server will be unavailable until the restore completes.\n")
+		return nil
+	}
+
+	added := make(map[string]string)
+	for i, target := range targets {
+		p, err := target.store.AddCommand(commands[i], time.Now())
+		if err != nil {
+			return err
+		}
+		added[target.spec.Name] = p.Path()
+	}
+
+	fmt.Fprintf(out, "\nAdded the restore commands. etcd-manager carries them out when it restarts: restart the etcd-manager pods on all control plane nodes,\n")
+	fmt.Fprintf(out, "or roll the control plane with \"kops rolling-update cluster --instance-group-roles=Master --cloudonly --force --yes\".\n")
+	fmt.Fprintf(out, "Each etcd cluster is quarantined while it is restored, so the API server cannot read or write partially restored data.\n")
+
+	if options.Wait == 0 {
+		return nil
+	}
+
+	deadline := time.Now().Add(options.Wait)
+	for _, target := range targets {
+		for {
+			pending, err := target.store.ListCommands()
+			if err != nil {
+				return err
+			}
+			if _, found := pending[added[target.spec.Name]]; !found {
+				fmt.Fprintf(out, "etcd cluster %q has been restored\n", target.spec.Name)
+				break
+			}
+			if time.Now().After(deadline) {
+				return fmt.Errorf("timed out waiting for etcd cluster %q to be restored", target.spec.Name)
+			}
+			if err := sleepContext(ctx, etcdBackupPollInterval); err != nil {
+				return err
+			}
+		}
+	}
+
+	if options.WaitForAPIServer != nil {
+		if err := options.WaitForAPIServer(ctx); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "The API server is serving the restored data\n")
+	}
+
+	return nil
+}
+
+// etcdBackupTargets returns the etcd clusters with the given names, or all clusters managed by etcd-manager
+func etcdBackupTargets(cluster *kops.Cluster, names []string) ([]*etcdBackupTarget, error) {
+	var targets []*etcdBackupTarget
+	for i := range cluster.Spec.EtcdClusters {
+		etcdCluster := &cluster.Spec.EtcdClusters[i]
+		if len(names) != 0 && !containsString(names, etcdCluster.Name) {
+			continue
+		}
+		if etcdCluster.IsExternal() || etcdCluster.Provider == kops.EtcdProviderTypeLegacy {
+			if len(names) != 0 {
+				return nil, fmt.Errorf("etcd cluster %q is not managed by etcd-manager", etcdCluster.Name)
+			}
+			continue
+		}
+
+		store, err := etcdBackupStore(cluster, etcdCluster)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, &etcdBackupTarget{spec: etcdCluster, store: store})
+	}
+
+	for _, name := range names {
+		found := false
+		for _, target := range targets {
+			if target.spec.Name == name {
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("etcd cluster %q not found in cluster %q", name, cluster.Name)
+		}
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("cluster %q has no etcd clusters managed by etcd-manager", cluster.Name)
+	}
+
+	return targets, nil
+}
+
+// etcdBackupStore returns the backup store of an etcd cluster, matching the default used by the etcd-manager model
+func etcdBackupStore(cluster *kops.Cluster, etcdCluster *kops.EtcdClusterSpec) (*etcdmanager.BackupStore, error) {
+	if etcdCluster.Backups != nil && etcdCluster.Backups.BackupStore != "" {
+		p, err := vfs.Context.BuildVfsPath(etcdCluster.Backups.BackupStore)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing backup store %q: %v", etcdCluster.Backups.BackupStore, err)
+		}
+		return etcdmanager.NewBackupStore(p), nil
+	}
+
+	configBase, err := registry.ConfigBase(cluster)
+	if err != nil {
+		return nil, err
+	}
+	return etcdmanager.NewBackupStore(configBase.Join("backups", "etcd", etcdCluster.Name)), nil
+}
+
+// parseEtcdRestoreBackups maps each etcd cluster to the backup to restore it from
+func parseEtcdRestoreBackups(targets []*etcdBackupTarget, backups []string) (map[string]string, error) {
+	names := make(map[string]string)
+	for _, backup := range backups {
+		etcdCluster, name := "", backup
+		if i := strings.Index(backup, "="); i != -1 {
+			etcdCluster, name = backup[:i], backup[i+1:]
+		}
+		if name == "" {
+			return nil, fmt.Errorf("invalid backup %q", backup)
+		}
+
+		if etcdCluster == "" {
+			if name != LatestEtcdBackup && len(targets) != 1 {
+				return nil, fmt.Errorf("backup %q must be given as <etcd cluster>=<backup> when restoring more than one etcd cluster", backup)
+			}
+			for _, target := range targets {
+				names[target.spec.Name] = name
+			}
+			continue
+		}
+
+		found := false
+		for _, target := range targets {
+			if target.spec.Name == etcdCluster {
+				names[etcdCluster] = name
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("backup %q is for etcd cluster %q, which is not being restored", backup, etcdCluster)
+		}
+	}
+
+	for _, target := range targets {
+		if names[target.spec.Name] == "" {
+			return nil, fmt.Errorf("no backup specified for etcd cluster %q; use --backup %s=<backup> or --backup %s", target.spec.Name, target.spec.Name, LatestEtcdBackup)
+		}
+	}
+	return names, nil
+}
+
+func listEtcdBackups(out io.Writer, targets []*etcdBackupTarget) error {
+	type row struct {
+		etcdCluster string
+		backup      *etcdmanager.Backup
+	}
+
+	var rows []*row
+	for _, target := range targets {
+		backups, err := target.store.ListBackups()
+		if err != nil {
+			return err
+		}
+		for _, backup := range backups {
+			rows = append(rows, &row{etcdCluster: target.spec.Name, backup: backup})
+		}
+	}
+
+	t := &tables.Table{}
+	t.AddColumn("ETCD CLUSTER", func(r *row) string {
+		return r.etcdCluster
+	})
+	t.AddColumn("BACKUP", func(r *row) string {
+		return r.backup.Name
+	})
+	t.AddColumn("ETCD VERSION", func(r *row) string {
+		return r.backup.Info.EtcdVersion
+	})
+	t.AddColumn("TIMESTAMP", func(r *row) string {
+		if r.backup.Info.Timestamp == 0 {
+			return ""
+		}
+		return time.Unix(0, r.backup.Info.Timestamp).UTC().Format(time.RFC3339)
+	})
+	return t.Render(rows, out, "ETCD CLUSTER", "BACKUP", "ETCD VERSION", "TIMESTAMP")
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// sleepContext waits for the given duration, or until the context is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
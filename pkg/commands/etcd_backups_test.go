@@ -0,0 +1,180 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/etcdmanager"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func buildEtcdBackupCluster(t *testing.T) *kops.Cluster {
+	vfs.Context.ResetMemfsContext(true)
+
+	cluster := testutils.BuildMinimalCluster("test.example.com")
+	cluster.Spec.ConfigBase = "memfs://clusters/test.example.com"
+	cluster.Spec.EtcdClusters[1].Backups = &kops.EtcdBackupSpec{BackupStore: "memfs://backups/events"}
+
+	for _, backup := range []struct {
+		store string
+		name  string
+	}{
+		{"memfs://clusters/test.example.com/backups/etcd/main", "2021-05-01T10:00:00Z-000001"},
+		{"memfs://clusters/test.example.com/backups/etcd/main", "2021-05-02T10:00:00Z-000002"},
+		{"memfs://backups/events", "2021-05-01T10:00:00Z-000001"},
+	} {
+		p, err := vfs.Context.BuildVfsPath(backup.store + "/" + backup.name + "/" + etcdmanager.BackupMetaFile)
+		if err != nil {
+			t.Fatalf("error building vfs path: %v", err)
+		}
+		if err := p.WriteFile(bytes.NewReader([]byte(`{"etcdVersion": "3.4.13"}`)), nil); err != nil {
+			t.Fatalf("error writing backup: %v", err)
+		}
+	}
+
+	return cluster
+}
+
+func TestEtcdBackupList(t *testing.T) {
+	cluster := buildEtcdBackupCluster(t)
+
+	var out bytes.Buffer
+	if err := RunEtcdBackup(context.TODO(), &out, cluster, &EtcdBackupOptions{}); err != nil {
+		t.Fatalf("error listing backups: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[1], "events") || !strings.HasPrefix(lines[3], "main") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
+
+func TestEtcdRestore(t *testing.T) {
+	grid := []struct {
+		Name          string
+		EtcdClusters  []string
+		Backups       []string
+		Yes           bool
+		ExpectedError string
+		Expected      map[string]string
+	}{
+		{
+			Name:     "latest",
+			Backups:  []string{"latest"},
+			Yes:      true,
+			Expected: map[string]string{"main": "2021-05-02T10:00:00Z-000002", "events": "2021-05-01T10:00:00Z-000001"},
+		},
+		{
+			Name:     "per cluster",
+			Backups:  []string{"main=2021-05-01T10:00:00Z-000001", "events=latest"},
+			Yes:      true,
+			Expected: map[string]string{"main": "2021-05-01T10:00:00Z-000001", "events": "2021-05-01T10:00:00Z-000001"},
+		},
+		{
+			Name:         "single cluster",
+			EtcdClusters: []string{"main"},
+			Backups:      []string{"2021-05-01T10:00:00Z-000001"},
+			Yes:          true,
+			Expected:     map[string]string{"main": "2021-05-01T10:00:00Z-000001"},
+		},
+		{
+			Name:     "preview",
+			Backups:  []string{"latest"},
+			Expected: map[string]string{},
+		},
+		{
+			Name:          "ambiguous backup",
+			Backups:       []string{"2021-05-01T10:00:00Z-000001"},
+			ExpectedError: "must be given as <etcd cluster>=<backup>",
+		},
+		{
+			Name:          "missing backup",
+			Backups:       []string{"main=latest"},
+			ExpectedError: `no backup specified for etcd cluster "events"`,
+		},
+		{
+			Name:          "unknown backup",
+			EtcdClusters:  []string{"main"},
+			Backups:       []string{"2020-01-01T00:00:00Z-000001"},
+			ExpectedError: "not found",
+		},
+		{
+			Name:          "unknown etcd cluster",
+			EtcdClusters:  []string{"cilium"},
+			Backups:       []string{"latest"},
+			ExpectedError: `etcd cluster "cilium" not found`,
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			cluster := buildEtcdBackupCluster(t)
+
+			var out bytes.Buffer
+			err := RunEtcdRestore(context.TODO(), &out, cluster, &EtcdRestoreOptions{
+				EtcdClusters: g.EtcdClusters,
+				Backups:      g.Backups,
+				Yes:          g.Yes,
+			})
+			if g.ExpectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), g.ExpectedError) {
+					t.Fatalf("expected error containing %q, got %v", g.ExpectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			restored := make(map[string]string)
+			for i := range cluster.Spec.EtcdClusters {
+				etcdCluster := &cluster.Spec.EtcdClusters[i]
+				store, err := etcdBackupStore(cluster, etcdCluster)
+				if err != nil {
+					t.Fatalf("error building backup store: %v", err)
+				}
+				commands, err := store.ListCommands()
+				if err != nil {
+					t.Fatalf("error listing commands: %v", err)
+				}
+				for _, cmd := range commands {
+					if cmd.RestoreBackup == nil || cmd.CreateNewCluster == nil {
+						t.Fatalf("unexpected command %+v", cmd)
+					}
+					if cmd.RestoreBackup.ClusterSpec.MemberCount != 3 || cmd.RestoreBackup.ClusterSpec.EtcdVersion != "3.4.13" {
+						t.Errorf("unexpected cluster spec %+v", cmd.RestoreBackup.ClusterSpec)
+					}
+					restored[etcdCluster.Name] = cmd.RestoreBackup.Backup
+				}
+			}
+			if len(restored) != len(g.Expected) {
+				t.Fatalf("unexpected restores %v, expected %v", restored, g.Expected)
+			}
+			for k, v := range g.Expected {
+				if restored[k] != v {
+					t.Errorf("unexpected restores %v, expected %v", restored, g.Expected)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,421 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+type MigrateStateOptions struct {
+	// From is the state store to copy the clusters from
+	From string
+	// To is the state store to copy the clusters to
+	To string
+	// ClusterName limits the migration to one cluster; all clusters are migrated if empty
+	ClusterName string
+	// ConfigBase overrides the cluster-readable location of the migrated cluster
+	ConfigBase string
+}
+
+// RunMigrateState implements the toolbox migrate-state command logic
+func RunMigrateState(ctx context.Context, out io.Writer, options *MigrateStateOptions) error {
+	if options.From == "" || options.To == "" {
+		return fmt.Errorf("--from and --to are required")
+	}
+	if strings.TrimSuffix(options.From, "/") == strings.TrimSuffix(options.To, "/") {
+		return fmt.Errorf("--from and --to must be different state stores")
+	}
+	if options.ConfigBase != "" && options.ClusterName == "" {
+		return fmt.Errorf("--config-base can only be set when migrating a single cluster with --name")
+	}
+
+	from, err := util.NewClientset(options.From)
+	if err != nil {
+		return err
+	}
+	to, err := util.NewClientset(options.To)
+	if err != nil {
+		return err
+	}
+
+	var clusterNames []string
+	if options.ClusterName != "" {
+		clusterNames = append(clusterNames, options.ClusterName)
+	} else {
+		clusters, err := from.ListClusters(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		for i := range clusters.Items {
+			clusterNames = append(clusterNames, clusters.Items[i].Name)
+		}
+	}
+
+	for _, clusterName := range clusterNames {
+		if err := MigrateCluster(ctx, out, from, to, clusterName, options.ConfigBase); err != nil {
+			return fmt.Errorf("error migrating cluster %q: %v", clusterName, err)
+		}
+	}
+
+	return nil
+}
+
+// MigrateCluster copies the cluster, with its instance groups, addons, keys, secrets and SSH public keys,
+// from one state store to another, verifying each copy by reading it back.
+// Store locations of the cluster that were in its configBase are moved to the new configBase:
+// configBase if set, otherwise the default configBase of the target state store.
+func MigrateCluster(ctx context.Context, out io.Writer, from simple.Clientset, to simple.Clientset, clusterName string, configBase string) error {
+	cluster, err := from.GetCluster(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+	if cluster == nil {
+		return fmt.Errorf("cluster not found in source state store")
+	}
+
+	existing, err := to.GetCluster(ctx, clusterName)
+	if err == nil && existing != nil {
+		return fmt.Errorf("cluster already exists in target state store")
+	}
+
+	unlock, err := from.LockCluster(ctx, cluster, "toolbox migrate-state")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := unlock(); err != nil {
+			klog.Warningf("error releasing lock of cluster %q: %v", clusterName, err)
+		}
+	}()
+
+	igList, err := from.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("error listing instance groups: %v", err)
+	}
+	addons, err := from.AddonsFor(cluster).List()
+	if err != nil {
+		return fmt.Errorf("error reading addons: %v", err)
+	}
+
+	fromKeyStore, err := from.KeyStore(cluster)
+	if err != nil {
+		return err
+	}
+	fromSecretStore, err := from.SecretStore(cluster)
+	if err != nil {
+		return err
+	}
+	fromSSHCredentialStore, err := from.SSHCredentialStore(cluster)
+	if err != nil {
+		return err
+	}
+
+	migrated := cluster.DeepCopy()
+	migrated.ObjectMeta = metav1.ObjectMeta{
+		Name:        cluster.Name,
+		Labels:      cluster.Labels,
+		Annotations: cluster.Annotations,
+	}
+	if err := relocateStores(migrated, to, configBase); err != nil {
+		return err
+	}
+
+	created, err := to.CreateCluster(ctx, migrated)
+	if err != nil {
+		return fmt.Errorf("error creating cluster: %v", err)
+	}
+	fmt.Fprintf(out, "Copied cluster %s\n", clusterName)
+
+	for i := range igList.Items {
+		ig := igList.Items[i].DeepCopy()
+		ig.ObjectMeta = metav1.ObjectMeta{
+			Name:        ig.Name,
+			Labels:      ig.Labels,
+			Annotations: ig.Annotations,
+		}
+		if _, err := to.InstanceGroupsFor(created).Create(ctx, ig, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("error creating instance group %q: %v", ig.Name, err)
+		}
+		fmt.Fprintf(out, "Copied instance group %s\n", ig.Name)
+	}
+
+	if len(addons) != 0 {
+		if err := to.AddonsFor(created).Replace(addons); err != nil {
+			return fmt.Errorf("error writing addons: %v", err)
+		}
+		fmt.Fprintf(out, "Copied %d addon objects\n", len(addons))
+	}
+
+	toKeyStore, err := to.KeyStore(created)
+	if err != nil {
+		return err
+	}
+	if err := migrateKeysets(out, fromKeyStore, toKeyStore); err != nil {
+		return err
+	}
+
+	toSecretStore, err := to.SecretStore(created)
+	if err != nil {
+		return err
+	}
+	if err := migrateSecrets(out, fromSecretStore, toSecretStore); err != nil {
+		return err
+	}
+
+	toSSHCredentialStore, err := to.SSHCredentialStore(created)
+	if err != nil {
+		return err
+	}
+	if err := migrateSSHCredentials(out, fromSSHCredentialStore, toSSHCredentialStore); err != nil {
+		return err
+	}
+
+	return verifyClusterObjects(ctx, to, migrated, igList.Items)
+}
+
+// relocateStores points the store locations of the cluster that were in its old configBase to its new configBase
+func relocateStores(cluster *kops.Cluster, to simple.Clientset, configBase string) error {
+	oldConfigBase := strings.TrimSuffix(cluster.Spec.ConfigBase, "/")
+
+	cluster.Spec.ConfigBase = configBase
+	newConfigBase, err := to.ConfigBaseFor(cluster)
+	if err != nil {
+		// The target store has no default configBase, so nodes keep reading the configuration from the existing location
+		klog.Infof("keeping configBase %q: %v", oldConfigBase, err)
+		cluster.Spec.ConfigBase = oldConfigBase
+		return nil
+	}
+	cluster.Spec.ConfigBase = newConfigBase.Path()
+
+	for _, location := range []*string{&cluster.Spec.KeyStore, &cluster.Spec.SecretStore, &cluster.Spec.ConfigStore} {
+		if oldConfigBase != "" && (*location == oldConfigBase || strings.HasPrefix(*location, oldConfigBase+"/")) {
+			*location = cluster.Spec.ConfigBase + strings.TrimPrefix(*location, oldConfigBase)
+		}
+	}
+	return nil
+}
+
+func migrateKeysets(out io.Writer, from fi.CAStore, to fi.CAStore) error {
+	keysets, err := from.ListKeysets()
+	if err != nil {
+		return fmt.Errorf("error listing keysets: %v", err)
+	}
+	sort.Slice(keysets, func(i, j int) bool { return keysets[i].Name < keysets[j].Name })
+
+	for _, keyset := range keysets {
+		if keyset.Spec.Type != kops.SecretTypeKeypair {
+			continue
+		}
+		name := keyset.Name
+
+		certs, err := from.FindCertificateKeyset(name)
+		if err != nil {
+			return err
+		}
+		if certs == nil {
+			continue
+		}
+		keys, err := from.FindPrivateKeyset(name)
+		if err != nil {
+			return err
+		}
+		privateMaterial := make(map[string][]byte)
+		if keys != nil {
+			for _, item := range keys.Spec.Keys {
+				privateMaterial[item.Id] = item.PrivateMaterial
+			}
+		}
+
+		for _, item := range certs.Spec.Keys {
+			cert, err := pki.ParsePEMCertificate(item.PublicMaterial)
+			if err != nil {
+				return fmt.Errorf("error parsing certificate %q of keyset %q: %v", item.Id, name, err)
+			}
+			if len(privateMaterial[item.Id]) == 0 {
+				if err := to.AddCert(name, cert); err != nil {
+					return fmt.Errorf("error writing certificate %q of keyset %q: %v", item.Id, name, err)
+				}
+				continue
+			}
+			privateKey, err := pki.ParsePEMPrivateKey(privateMaterial[item.Id])
+			if err != nil {
+				return fmt.Errorf("error parsing private key %q of keyset %q: %v", item.Id, name, err)
+			}
+			if err := to.StoreKeypair(name, cert, privateKey); err != nil {
+				return fmt.Errorf("error writing keypair %q of keyset %q: %v", item.Id, name, err)
+			}
+		}
+
+		if err := verifyKeyset(from, to, name); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Copied keyset %s\n", name)
+	}
+	return nil
+}
+
+// verifyKeyset checks that the keyset has the same certificates and primary private key in both stores
+func verifyKeyset(from fi.CAStore, to fi.CAStore, name string) error {
+	fromPool, err := from.FindCertificatePool(name)
+	if err != nil {
+		return err
+	}
+	toPool, err := to.FindCertificatePool(name)
+	if err != nil {
+		return err
+	}
+	if toPool == nil || !sameCertificates(fromPool.All(), toPool.All()) {
+		return fmt.Errorf("verification failed: certificates of keyset %q differ after copying", name)
+	}
+
+	fromKey, err := from.FindPrivateKey(name)
+	if err != nil {
+		return err
+	}
+	toKey, err := to.FindPrivateKey(name)
+	if err != nil {
+		return err
+	}
+	if (fromKey == nil) != (toKey == nil) {
+		return fmt.Errorf("verification failed: private key of keyset %q differs after copying", name)
+	}
+	if fromKey != nil {
+		fromBytes, err := fromKey.AsBytes()
+		if err != nil {
+			return err
+		}
+		toBytes, err := toKey.AsBytes()
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(fromBytes, toBytes) {
+			return fmt.Errorf("verification failed: private key of keyset %q differs after copying", name)
+		}
+	}
+	return nil
+}
+
+func sameCertificates(a []*pki.Certificate, b []*pki.Certificate) bool {
+	serialize := func(certs []*pki.Certificate) []string {
+		var s []string
+		for _, cert := range certs {
+			pem, err := cert.AsString()
+			if err != nil {
+				return nil
+			}
+			s = append(s, pem)
+		}
+		sort.Strings(s)
+		return s
+	}
+	return reflect.DeepEqual(serialize(a), serialize(b))
+}
+
+func migrateSecrets(out io.Writer, from fi.SecretStore, to fi.SecretStore) error {
+	ids, err := from.ListSecrets()
+	if err != nil {
+		return fmt.Errorf("error listing secrets: %v", err)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		secret, err := from.Secret(id)
+		if err != nil {
+			return err
+		}
+		if _, err := to.ReplaceSecret(id, secret); err != nil {
+			return fmt.Errorf("error writing secret %q: %v", id, err)
+		}
+
+		copied, err := to.FindSecret(id)
+		if err != nil {
+			return err
+		}
+		if copied == nil || !bytes.Equal(copied.Data, secret.Data) {
+			return fmt.Errorf("verification failed: secret %q differs after copying", id)
+		}
+		fmt.Fprintf(out, "Copied secret %s\n", id)
+	}
+	return nil
+}
+
+func migrateSSHCredentials(out io.Writer, from fi.SSHCredentialStore, to fi.SSHCredentialStore) error {
+	sshCredentials, err := from.ListSSHCredentials()
+	if err != nil {
+		return fmt.Errorf("error listing SSH public keys: %v", err)
+	}
+
+	for _, sshCredential := range sshCredentials {
+		name := sshCredential.Name
+		if err := to.AddSSHPublicKey(name, []byte(sshCredential.Spec.PublicKey)); err != nil {
+			return fmt.Errorf("error writing SSH public key %q: %v", name, err)
+		}
+
+		copied, err := to.FindSSHPublicKeys(name)
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, c := range copied {
+			if strings.TrimSpace(c.Spec.PublicKey) == strings.TrimSpace(sshCredential.Spec.PublicKey) {
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("verification failed: SSH public key %q differs after copying", name)
+		}
+		fmt.Fprintf(out, "Copied SSH public key %s\n", name)
+	}
+	return nil
+}
+
+// verifyClusterObjects checks that the cluster and instance group specs read back from the target store are unchanged
+func verifyClusterObjects(ctx context.Context, to simple.Clientset, cluster *kops.Cluster, instanceGroups []kops.InstanceGroup) error {
+	copied, err := to.GetCluster(ctx, cluster.Name)
+	if err != nil {
+		return err
+	}
+	if copied == nil || !reflect.DeepEqual(copied.Spec, cluster.Spec) {
+		return fmt.Errorf("verification failed: cluster spec differs after copying")
+	}
+
+	for i := range instanceGroups {
+		ig := &instanceGroups[i]
+		copiedIG, err := to.InstanceGroupsFor(copied).Get(ctx, ig.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if copiedIG == nil || !reflect.DeepEqual(copiedIG.Spec, ig.Spec) {
+			return fmt.Errorf("verification failed: spec of instance group %q differs after copying", ig.Name)
+		}
+	}
+	return nil
+}
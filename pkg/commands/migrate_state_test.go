@@ -0,0 +1,158 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509/pkix"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/client/simple/vfsclientset"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func newMemFSClientset(t *testing.T, location string) simple.Clientset {
+	basePath, err := vfs.Context.BuildVfsPath(location)
+	if err != nil {
+		t.Fatalf("error building vfs path: %v", err)
+	}
+	return vfsclientset.NewVFSClientset(basePath)
+}
+
+func TestMigrateCluster(t *testing.T) {
+	ctx := context.TODO()
+	vfs.Context.ResetMemfsContext(true)
+
+	from := newMemFSClientset(t, "memfs://source")
+	to := newMemFSClientset(t, "memfs://target")
+
+	cluster := testutils.BuildMinimalCluster("test.example.com")
+	cluster.Spec.ConfigBase = "memfs://source/test.example.com"
+	cluster.Spec.KeyStore = "memfs://source/test.example.com/pki"
+	cluster.Spec.SecretStore = "memfs://shared/secrets"
+	cluster, err := from.CreateCluster(ctx, cluster)
+	if err != nil {
+		t.Fatalf("error creating cluster: %v", err)
+	}
+	ig := testutils.BuildMinimalNodeInstanceGroup("nodes", "subnet-us-mock-1a")
+	if _, err := from.InstanceGroupsFor(cluster).Create(ctx, &ig, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error creating instance group: %v", err)
+	}
+
+	keyStore, err := from.KeyStore(cluster)
+	if err != nil {
+		t.Fatalf("error building keystore: %v", err)
+	}
+	caCert, caKey, _, err := pki.IssueCert(&pki.IssueCertRequest{
+		Type:    "ca",
+		Subject: pkix.Name{CommonName: "kubernetes"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("error issuing CA: %v", err)
+	}
+	if err := keyStore.StoreKeypair(fi.CertificateIDCA, caCert, caKey); err != nil {
+		t.Fatalf("error storing CA: %v", err)
+	}
+
+	secretStore, err := from.SecretStore(cluster)
+	if err != nil {
+		t.Fatalf("error building secret store: %v", err)
+	}
+	if _, _, err := secretStore.GetOrCreateSecret("admin", &fi.Secret{Data: []byte("token")}); err != nil {
+		t.Fatalf("error storing secret: %v", err)
+	}
+
+	if err := MigrateCluster(ctx, ioutil.Discard, from, to, cluster.Name, ""); err != nil {
+		t.Fatalf("error migrating cluster: %v", err)
+	}
+
+	migrated, err := to.GetCluster(ctx, cluster.Name)
+	if err != nil || migrated == nil {
+		t.Fatalf("error reading migrated cluster: %v", err)
+	}
+	if migrated.Spec.ConfigBase != "memfs://target/test.example.com" {
+		t.Errorf("unexpected configBase %q", migrated.Spec.ConfigBase)
+	}
+	if migrated.Spec.KeyStore != "memfs://target/test.example.com/pki" {
+		t.Errorf("unexpected keyStore %q", migrated.Spec.KeyStore)
+	}
+	if migrated.Spec.SecretStore != "memfs://shared/secrets" {
+		t.Errorf("secretStore outside the configBase should be unchanged, was %q", migrated.Spec.SecretStore)
+	}
+
+	igs, err := to.InstanceGroupsFor(migrated).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("error listing instance groups: %v", err)
+	}
+	if len(igs.Items) != 1 || igs.Items[0].Name != "nodes" {
+		t.Errorf("unexpected instance groups %v", igs.Items)
+	}
+
+	migratedKeyStore, err := to.KeyStore(migrated)
+	if err != nil {
+		t.Fatalf("error building keystore: %v", err)
+	}
+	cert, key, _, err := migratedKeyStore.FindKeypair(fi.CertificateIDCA)
+	if err != nil || cert == nil || key == nil {
+		t.Fatalf("expected migrated CA keypair, got %v, %v, %v", cert, key, err)
+	}
+	if !bytes.Equal(cert.Certificate.Raw, caCert.Certificate.Raw) {
+		t.Errorf("migrated CA certificate differs")
+	}
+
+	if _, err := vfs.Context.ReadFile("memfs://target/test.example.com/pki/private/ca/keyset.yaml"); err != nil {
+		t.Errorf("expected CA keyset in the new keystore: %v", err)
+	}
+
+	if err := MigrateCluster(ctx, ioutil.Discard, from, to, cluster.Name, ""); err == nil {
+		t.Errorf("expected error migrating a cluster that already exists in the target")
+	}
+}
+
+func TestRelocateStoresKeepsConfigBaseWithoutDefault(t *testing.T) {
+	cluster := &kops.Cluster{}
+	cluster.Spec.ConfigBase = "s3://bucket/test.example.com"
+	cluster.Spec.KeyStore = "s3://bucket/test.example.com/pki"
+
+	if err := relocateStores(cluster, &noConfigBaseClientset{}, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cluster.Spec.ConfigBase != "s3://bucket/test.example.com" || cluster.Spec.KeyStore != "s3://bucket/test.example.com/pki" {
+		t.Errorf("unexpected relocation %v", cluster.Spec)
+	}
+}
+
+// noConfigBaseClientset is a state store without a default configBase, like a kubernetes state store
+type noConfigBaseClientset struct {
+	simple.Clientset
+}
+
+func (c *noConfigBaseClientset) ConfigBaseFor(cluster *kops.Cluster) (vfs.Path, error) {
+	if cluster.Spec.ConfigBase == "" {
+		return nil, fmt.Errorf("spec.configBase is required")
+	}
+	return vfs.Context.BuildVfsPath(cluster.Spec.ConfigBase)
+}
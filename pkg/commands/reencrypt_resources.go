@@ -0,0 +1,73 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// reencryptPageSize is the number of objects listed at a time
+const reencryptPageSize = 500
+
+// ReencryptResources writes back every object of the given resources unchanged,
+// so that the API server stores them encrypted with its current encryption provider.
+func ReencryptResources(ctx context.Context, out io.Writer, client dynamic.Interface, mapper meta.RESTMapper, resources []string) error {
+	for _, resource := range resources {
+		gvr, err := mapper.ResourceFor(schema.ParseGroupResource(resource).WithVersion(""))
+		if err != nil {
+			return fmt.Errorf("error resolving resource %q: %v", resource, err)
+		}
+
+		rewritten := 0
+		listOptions := metav1.ListOptions{Limit: reencryptPageSize}
+		for {
+			list, err := client.Resource(gvr).List(ctx, listOptions)
+			if err != nil {
+				return fmt.Errorf("error listing %s: %v", resource, err)
+			}
+
+			for i := range list.Items {
+				obj := &list.Items[i]
+				if _, err := client.Resource(gvr).Namespace(obj.GetNamespace()).Update(ctx, obj, metav1.UpdateOptions{}); err != nil {
+					if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+						// The object was written or deleted since we listed it, which also re-encrypted it
+						continue
+					}
+					return fmt.Errorf("error rewriting %s %s/%s: %v", resource, obj.GetNamespace(), obj.GetName(), err)
+				}
+				rewritten++
+			}
+
+			listOptions.Continue = list.GetContinue()
+			if listOptions.Continue == "" {
+				break
+			}
+		}
+
+		fmt.Fprintf(out, "Re-encrypted %d %s\n", rewritten, gvr.GroupResource())
+	}
+
+	return nil
+}
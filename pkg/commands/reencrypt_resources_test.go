@@ -0,0 +1,89 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+)
+
+func TestReencryptResources(t *testing.T) {
+	ctx := context.TODO()
+
+	var updated []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/secrets" && r.URL.Query().Get("continue") == "":
+			fmt.Fprint(w, `{"apiVersion":"v1","kind":"SecretList","metadata":{"continue":"page2"},"items":[
+				{"apiVersion":"v1","kind":"Secret","metadata":{"namespace":"default","name":"a"}},
+				{"apiVersion":"v1","kind":"Secret","metadata":{"namespace":"kube-system","name":"b"}}]}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/secrets" && r.URL.Query().Get("continue") == "page2":
+			fmt.Fprint(w, `{"apiVersion":"v1","kind":"SecretList","metadata":{},"items":[
+				{"apiVersion":"v1","kind":"Secret","metadata":{"namespace":"default","name":"c"}}]}`)
+		case r.Method == http.MethodPut && strings.HasSuffix(r.URL.Path, "/secrets/b"):
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `{"apiVersion":"v1","kind":"Status","status":"Failure","reason":"Conflict","code":409}`)
+		case r.Method == http.MethodPut:
+			body, _ := ioutil.ReadAll(r.Body)
+			updated = append(updated, r.URL.Path)
+			w.Write(body)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := dynamic.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("error building client: %v", err)
+	}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, meta.RESTScopeNamespace)
+
+	var out bytes.Buffer
+	if err := ReencryptResources(ctx, &out, client, mapper, []string{"secrets"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sort.Strings(updated)
+	expected := []string{"/api/v1/namespaces/default/secrets/a", "/api/v1/namespaces/default/secrets/c"}
+	if strings.Join(updated, ",") != strings.Join(expected, ",") {
+		t.Errorf("unexpected updates; expected %v, got %v", expected, updated)
+	}
+	if out.String() != "Re-encrypted 2 secrets\n" {
+		t.Errorf("unexpected output %q", out.String())
+	}
+
+	if err := ReencryptResources(ctx, &out, client, mapper, []string{"widgets"}); err == nil {
+		t.Errorf("expected error for unknown resource")
+	}
+}
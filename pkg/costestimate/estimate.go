@@ -0,0 +1,229 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package costestimate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
+)
+
+// Cost is the estimated monthly cost of a cloud resource
+type Cost struct {
+	// Description describes what is priced, e.g. "2 x t3.medium"
+	Description string
+	// Monthly is the monthly cost in USD
+	Monthly float64
+	// Unpriced lists what is not included in Monthly because its price is unknown, e.g. an instance type
+	Unpriced []string
+}
+
+// Item is the estimated change in the monthly cost of a cloud resource
+type Item struct {
+	// Type is the type of the task, e.g. AutoscalingGroup
+	Type string
+	// Name is the name of the task
+	Name string
+	// Before is the cost before the change; nil if the resource is created
+	Before *Cost
+	// After is the cost after the change
+	After *Cost
+}
+
+// Delta returns the change in the monthly cost of the resource
+func (i *Item) Delta() float64 {
+	delta := i.After.Monthly
+	if i.Before != nil {
+		delta -= i.Before.Monthly
+	}
+	return delta
+}
+
+// Estimate is the estimated change in the monthly cost of the cloud resources of a cluster
+type Estimate struct {
+	Items []*Item
+}
+
+// Total returns the change in the monthly cost of all the resources
+func (e *Estimate) Total() float64 {
+	total := 0.0
+	for _, item := range e.Items {
+		total += item.Delta()
+	}
+	return total
+}
+
+// Unpriced returns what the estimate does not include because its price is unknown
+func (e *Estimate) Unpriced() []string {
+	unpriced := sets.NewString()
+	for _, item := range e.Items {
+		unpriced.Insert(item.After.Unpriced...)
+		if item.Before != nil {
+			unpriced.Insert(item.Before.Unpriced...)
+		}
+	}
+	return unpriced.List()
+}
+
+// EstimateAWS estimates the change in the monthly cost of the AWS resources that the changes would create or update,
+// from the bundled on-demand prices of PriceRegion.  Usage based charges, such as for data transfer, are not included.
+// Nor are deleted resources, as the dry run does not describe them.
+func EstimateAWS(taskMap map[string]fi.Task, changes []*fi.ResourceChange) *Estimate {
+	created := make(map[fi.Task]bool)
+	actual := make(map[fi.Task]fi.Task)
+	for _, change := range changes {
+		switch change.Action {
+		case fi.ChangeActionCreate:
+			created[change.Expected] = true
+		case fi.ChangeActionUpdate:
+			actual[change.Expected] = change.Actual
+		}
+	}
+
+	changed := func(tasks ...fi.Task) bool {
+		for _, task := range tasks {
+			if _, found := actual[task]; found || created[task] {
+				return true
+			}
+		}
+		return false
+	}
+
+	// previous returns the task before the change, or nil if it is created
+	previous := func(task fi.Task) fi.Task {
+		if created[task] {
+			return nil
+		}
+		if a, found := actual[task]; found {
+			return a
+		}
+		return task
+	}
+
+	var keys []string
+	for key := range taskMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	estimate := &Estimate{}
+	for _, key := range keys {
+		task := taskMap[key]
+
+		var item *Item
+		switch e := task.(type) {
+		case *awstasks.AutoscalingGroup:
+			if e.LaunchTemplate == nil || !changed(e, e.LaunchTemplate) {
+				continue
+			}
+			item = &Item{After: groupCost(e, e.LaunchTemplate)}
+			if a, ok := previous(e).(*awstasks.AutoscalingGroup); ok {
+				lt, ok := previous(e.LaunchTemplate).(*awstasks.LaunchTemplate)
+				if !ok {
+					lt = e.LaunchTemplate
+				}
+				item.Before = groupCost(a, lt)
+			}
+
+		case *awstasks.EBSVolume:
+			if !changed(e) {
+				continue
+			}
+			item = &Item{After: volumeCost(1, e.SizeGB, e.VolumeType)}
+			if a, ok := previous(e).(*awstasks.EBSVolume); ok {
+				item.Before = volumeCost(1, a.SizeGB, a.VolumeType)
+			}
+
+		case *awstasks.ClassicLoadBalancer:
+			if !created[e] {
+				continue
+			}
+			item = &Item{After: &Cost{Description: "Classic Load Balancer", Monthly: classicLoadBalancerHourlyPrice * HoursPerMonth}}
+
+		case *awstasks.NetworkLoadBalancer:
+			if !created[e] {
+				continue
+			}
+			item = &Item{After: &Cost{Description: "Network Load Balancer", Monthly: networkLoadBalancerHourlyPrice * HoursPerMonth}}
+
+		case *awstasks.NatGateway:
+			if !created[e] || fi.BoolValue(e.Shared) {
+				continue
+			}
+			item = &Item{After: &Cost{Description: "NAT Gateway", Monthly: natGatewayHourlyPrice * HoursPerMonth}}
+
+		default:
+			continue
+		}
+
+		if item.Before != nil && item.Before.Description == item.After.Description && item.Before.Monthly == item.After.Monthly {
+			continue
+		}
+
+		item.Type = key
+		if i := strings.Index(key, "/"); i != -1 {
+			item.Type, item.Name = key[:i], key[i+1:]
+		}
+		estimate.Items = append(estimate.Items, item)
+	}
+
+	return estimate
+}
+
+// groupCost returns the cost of the minimum number of instances of an autoscaling group, including their root volumes
+func groupCost(asg *awstasks.AutoscalingGroup, lt *awstasks.LaunchTemplate) *Cost {
+	count := fi.Int64Value(asg.MinSize)
+	instanceType := fi.StringValue(lt.InstanceType)
+
+	cost := volumeCost(count, lt.RootVolumeSize, lt.RootVolumeType)
+	cost.Description = fmt.Sprintf("%d x %s, %s", count, instanceType, cost.Description)
+
+	if price, found := onDemandHourlyPrices[instanceType]; found {
+		cost.Monthly += float64(count) * price * HoursPerMonth
+	} else {
+		cost.Unpriced = append(cost.Unpriced, "instance type "+instanceType)
+	}
+
+	return cost
+}
+
+// volumeCost returns the cost of a number of EBS volumes
+func volumeCost(count int64, sizeGB *int64, volumeType *string) *Cost {
+	size := fi.Int64Value(sizeGB)
+	t := fi.StringValue(volumeType)
+	if t == "" {
+		t = "gp2"
+	}
+
+	cost := &Cost{}
+	if count == 1 {
+		cost.Description = fmt.Sprintf("%dGB %s", size, t)
+	} else {
+		cost.Description = fmt.Sprintf("%d x %dGB %s", count, size, t)
+	}
+
+	if price, found := volumeMonthlyPricesPerGB[t]; found {
+		cost.Monthly = float64(count*size) * price
+	} else {
+		cost.Unpriced = append(cost.Unpriced, "volume type "+t)
+	}
+	return cost
+}
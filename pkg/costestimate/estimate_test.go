@@ -0,0 +1,175 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package costestimate
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
+)
+
+func TestEstimateAWS(t *testing.T) {
+	nodesTemplate := &awstasks.LaunchTemplate{
+		Name:           fi.String("nodes"),
+		InstanceType:   fi.String("t3.medium"),
+		RootVolumeSize: fi.Int64(128),
+		RootVolumeType: fi.String("gp3"),
+	}
+	nodes := &awstasks.AutoscalingGroup{
+		Name:           fi.String("nodes"),
+		MinSize:        fi.Int64(3),
+		LaunchTemplate: nodesTemplate,
+	}
+	masterTemplate := &awstasks.LaunchTemplate{
+		Name:           fi.String("master"),
+		InstanceType:   fi.String("x9.unknown"),
+		RootVolumeSize: fi.Int64(64),
+		RootVolumeType: fi.String("gp3"),
+	}
+	master := &awstasks.AutoscalingGroup{
+		Name:           fi.String("master"),
+		MinSize:        fi.Int64(1),
+		LaunchTemplate: masterTemplate,
+	}
+	etcd := &awstasks.EBSVolume{
+		Name:       fi.String("etcd"),
+		SizeGB:     fi.Int64(30),
+		VolumeType: fi.String("gp3"),
+	}
+	unchanged := &awstasks.EBSVolume{
+		Name:       fi.String("unchanged"),
+		SizeGB:     fi.Int64(100),
+		VolumeType: fi.String("gp3"),
+	}
+	api := &awstasks.ClassicLoadBalancer{Name: fi.String("api")}
+	nat := &awstasks.NatGateway{Name: fi.String("nat")}
+	sharedNat := &awstasks.NatGateway{Name: fi.String("shared-nat"), Shared: fi.Bool(true)}
+
+	taskMap := map[string]fi.Task{
+		"AutoscalingGroup/nodes":  nodes,
+		"LaunchTemplate/nodes":    nodesTemplate,
+		"AutoscalingGroup/master": master,
+		"LaunchTemplate/master":   masterTemplate,
+		"EBSVolume/etcd":          etcd,
+		"EBSVolume/unchanged":     unchanged,
+		"ClassicLoadBalancer/api": api,
+		"NatGateway/nat":          nat,
+		"NatGateway/shared-nat":   sharedNat,
+		"VPC/unrelated":           &awstasks.VPC{Name: fi.String("unrelated")},
+	}
+
+	changes := []*fi.ResourceChange{
+		// The instance type of the nodes changes from t3.small, and there is one more node
+		{
+			Action:   fi.ChangeActionUpdate,
+			Actual:   &awstasks.LaunchTemplate{InstanceType: fi.String("t3.small"), RootVolumeSize: fi.Int64(128), RootVolumeType: fi.String("gp3")},
+			Expected: nodesTemplate,
+		},
+		{
+			Action:   fi.ChangeActionUpdate,
+			Actual:   &awstasks.AutoscalingGroup{MinSize: fi.Int64(2)},
+			Expected: nodes,
+		},
+		// The master is created
+		{Action: fi.ChangeActionCreate, Expected: masterTemplate},
+		{Action: fi.ChangeActionCreate, Expected: master},
+		// The etcd volume grows from 20GB
+		{
+			Action:   fi.ChangeActionUpdate,
+			Actual:   &awstasks.EBSVolume{SizeGB: fi.Int64(20), VolumeType: fi.String("gp3")},
+			Expected: etcd,
+		},
+		{Action: fi.ChangeActionCreate, Expected: api},
+		{Action: fi.ChangeActionCreate, Expected: nat},
+		{Action: fi.ChangeActionCreate, Expected: sharedNat},
+		{Action: fi.ChangeActionDelete, Type: "AutoscalingGroup", Name: "old"},
+	}
+
+	estimate := EstimateAWS(taskMap, changes)
+
+	expected := []*Item{
+		{
+			Type:   "AutoscalingGroup",
+			Name:   "master",
+			Before: nil,
+			After:  &Cost{Description: "1 x x9.unknown, 64GB gp3", Monthly: 64 * 0.08, Unpriced: []string{"instance type x9.unknown"}},
+		},
+		{
+			Type:   "AutoscalingGroup",
+			Name:   "nodes",
+			Before: &Cost{Description: "2 x t3.small, 2 x 128GB gp3", Monthly: 2*128*0.08 + 2*0.0208*730},
+			After:  &Cost{Description: "3 x t3.medium, 3 x 128GB gp3", Monthly: 3*128*0.08 + 3*0.0416*730},
+		},
+		{
+			Type:  "ClassicLoadBalancer",
+			Name:  "api",
+			After: &Cost{Description: "Classic Load Balancer", Monthly: 0.025 * 730},
+		},
+		{
+			Type:   "EBSVolume",
+			Name:   "etcd",
+			Before: &Cost{Description: "20GB gp3", Monthly: 20 * 0.08},
+			After:  &Cost{Description: "30GB gp3", Monthly: 30 * 0.08},
+		},
+		{
+			Type:  "NatGateway",
+			Name:  "nat",
+			After: &Cost{Description: "NAT Gateway", Monthly: 0.045 * 730},
+		},
+	}
+
+	if len(estimate.Items) != len(expected) {
+		t.Fatalf("expected %d items, got %d: %v", len(expected), len(estimate.Items), estimate.Items)
+	}
+	for i, item := range estimate.Items {
+		e := expected[i]
+		if item.Type != e.Type || item.Name != e.Name {
+			t.Errorf("item %d: expected %s/%s, got %s/%s", i, e.Type, e.Name, item.Type, item.Name)
+			continue
+		}
+		compareCost(t, item.Type+"/"+item.Name+" before", item.Before, e.Before)
+		compareCost(t, item.Type+"/"+item.Name+" after", item.After, e.After)
+	}
+
+	total := 0.0
+	for _, e := range expected {
+		total += e.Delta()
+	}
+	if math.Abs(estimate.Total()-total) > 0.001 {
+		t.Errorf("expected a total of %.2f, got %.2f", total, estimate.Total())
+	}
+
+	if unpriced := estimate.Unpriced(); !reflect.DeepEqual(unpriced, []string{"instance type x9.unknown"}) {
+		t.Errorf("unexpected unpriced items %v", unpriced)
+	}
+}
+
+func compareCost(t *testing.T, name string, actual, expected *Cost) {
+	t.Helper()
+	if actual == nil || expected == nil {
+		if actual != expected {
+			t.Errorf("%s: expected %v, got %v", name, expected, actual)
+		}
+		return
+	}
+	if actual.Description != expected.Description || math.Abs(actual.Monthly-expected.Monthly) > 0.001 || !reflect.DeepEqual(actual.Unpriced, expected.Unpriced) {
+		t.Errorf("%s: expected %+v, got %+v", name, *expected, *actual)
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package costestimate
+
+// HoursPerMonth is the number of hours in an average month, which AWS uses for monthly prices
+const HoursPerMonth = 730
+
+// PriceRegion is the region of the bundled prices
+const PriceRegion = "us-east-1"
+
+// onDemandHourlyPrices are the hourly on-demand prices in USD of Linux instances in PriceRegion
+var onDemandHourlyPrices = map[string]float64{
+	"t2.micro":   0.0116,
+	"t2.small":   0.023,
+	"t2.medium":  0.0464,
+	"t2.large":   0.0928,
+	"t2.xlarge":  0.1856,
+	"t2.2xlarge": 0.3712,
+
+	"t3.nano":    0.0052,
+	"t3.micro":   0.0104,
+	"t3.small":   0.0208,
+	"t3.medium":  0.0416,
+	"t3.large":   0.0832,
+	"t3.xlarge":  0.1664,
+	"t3.2xlarge": 0.3328,
+
+	"t3a.micro":   0.0094,
+	"t3a.small":   0.0188,
+	"t3a.medium":  0.0376,
+	"t3a.large":   0.0752,
+	"t3a.xlarge":  0.1504,
+	"t3a.2xlarge": 0.3008,
+
+	"t4g.micro":   0.0084,
+	"t4g.small":   0.0168,
+	"t4g.medium":  0.0336,
+	"t4g.large":   0.0672,
+	"t4g.xlarge":  0.1344,
+	"t4g.2xlarge": 0.2688,
+
+	"m3.medium":  0.067,
+	"m3.large":   0.133,
+	"m3.xlarge":  0.266,
+	"m3.2xlarge": 0.532,
+
+	"m4.large":    0.10,
+	"m4.xlarge":   0.20,
+	"m4.2xlarge":  0.40,
+	"m4.4xlarge":  0.80,
+	"m4.10xlarge": 2.00,
+	"m4.16xlarge": 3.20,
+
+	"m5.large":    0.096,
+	"m5.xlarge":   0.192,
+	"m5.2xlarge":  0.384,
+	"m5.4xlarge":  0.768,
+	"m5.8xlarge":  1.536,
+	"m5.12xlarge": 2.304,
+	"m5.16xlarge": 3.072,
+	"m5.24xlarge": 4.608,
+
+	"m5a.large":   0.086,
+	"m5a.xlarge":  0.172,
+	"m5a.2xlarge": 0.344,
+	"m5a.4xlarge": 0.688,
+
+	"m6g.medium":  0.0385,
+	"m6g.large":   0.077,
+	"m6g.xlarge":  0.154,
+	"m6g.2xlarge": 0.308,
+	"m6g.4xlarge": 0.616,
+
+	"c4.large":   0.10,
+	"c4.xlarge":  0.199,
+	"c4.2xlarge": 0.398,
+	"c4.4xlarge": 0.796,
+	"c4.8xlarge": 1.591,
+
+	"c5.large":    0.085,
+	"c5.xlarge":   0.17,
+	"c5.2xlarge":  0.34,
+	"c5.4xlarge":  0.68,
+	"c5.9xlarge":  1.53,
+	"c5.12xlarge": 2.04,
+	"c5.18xlarge": 3.06,
+	"c5.24xlarge": 4.08,
+
+	"c6g.medium":  0.034,
+	"c6g.large":   0.068,
+	"c6g.xlarge":  0.136,
+	"c6g.2xlarge": 0.272,
+	"c6g.4xlarge": 0.544,
+
+	"r4.large":   0.133,
+	"r4.xlarge":  0.266,
+	"r4.2xlarge": 0.532,
+
+	"r5.large":   0.126,
+	"r5.xlarge":  0.252,
+	"r5.2xlarge": 0.504,
+	"r5.4xlarge": 1.008,
+	"r5.8xlarge": 2.016,
+}
+
+// volumeMonthlyPricesPerGB are the monthly prices in USD per GB of EBS volumes in PriceRegion
+var volumeMonthlyPricesPerGB = map[string]float64{
+	"gp2":      0.10,
+	"gp3":      0.08,
+	"io1":      0.125,
+	"io2":      0.125,
+	"st1":      0.045,
+	"sc1":      0.015,
+	"standard": 0.05,
+}
+
+const (
+	// classicLoadBalancerHourlyPrice is the hourly price in USD of a Classic Load Balancer, excluding data processed
+	classicLoadBalancerHourlyPrice = 0.025
+	// networkLoadBalancerHourlyPrice is the hourly price in USD of a Network Load Balancer, excluding capacity units
+	networkLoadBalancerHourlyPrice = 0.0225
+	// natGatewayHourlyPrice is the hourly price in USD of a NAT Gateway, excluding data processed
+	natGatewayHourlyPrice = 0.045
+)
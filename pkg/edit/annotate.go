@@ -0,0 +1,145 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edit
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kops/pkg/diff"
+	"k8s.io/kops/upup/pkg/fi/utils"
+)
+
+// AnnotateErrors adds a comment describing each error below the line of the yaml that the error's field is on,
+// or the line of its closest parent field which is present.
+// Errors which cannot be matched to any line are returned, so that they can be reported elsewhere.
+func AnnotateErrors(data []byte, errs field.ErrorList) ([]byte, field.ErrorList) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return data, errs
+	}
+
+	fieldLines := make(map[string]int)
+	indexFieldLines(&root, "", fieldLines)
+
+	comments := make(map[int][]string)
+	var unmatched field.ErrorList
+	for _, err := range errs {
+		line := findFieldLine(fieldLines, err.Field)
+		if line == 0 {
+			unmatched = append(unmatched, err)
+			continue
+		}
+		comments[line] = append(comments[line], err.ErrorBody())
+	}
+
+	if len(comments) == 0 {
+		return data, unmatched
+	}
+
+	var b bytes.Buffer
+	lines := bytes.Split(data, []byte("\n"))
+	for i, line := range lines {
+		b.Write(line)
+		if i < len(lines)-1 {
+			b.WriteByte('\n')
+		}
+		indent := line[:len(line)-len(bytes.TrimLeft(line, " "))]
+		for _, comment := range comments[i+1] {
+			if i == len(lines)-1 {
+				b.WriteByte('\n')
+			}
+			fmt.Fprintf(&b, "%s# ^ %s\n", indent, comment)
+		}
+	}
+	return b.Bytes(), unmatched
+}
+
+// indexFieldLines records the line of each field below node, using the field path notation of validation errors.
+func indexFieldLines(node *yaml.Node, path string, fieldLines map[string]int) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			indexFieldLines(child, path, fieldLines)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			childPath := key.Value
+			if path != "" {
+				childPath = path + "." + key.Value
+				// Keys of maps are reported as indexes
+				fieldLines[path+"["+key.Value+"]"] = key.Line
+			}
+			fieldLines[childPath] = key.Line
+			indexFieldLines(value, childPath, fieldLines)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			childPath := fmt.Sprintf("%s[%d]", path, i)
+			fieldLines[childPath] = item.Line
+			indexFieldLines(item, childPath, fieldLines)
+		}
+	}
+}
+
+// findFieldLine returns the line of the field, or of its closest parent field which is present, or 0.
+func findFieldLine(fieldLines map[string]int, path string) int {
+	if strings.HasPrefix(path, "objectMeta") {
+		path = "metadata" + strings.TrimPrefix(path, "objectMeta")
+	}
+	for path != "" {
+		if line, found := fieldLines[path]; found {
+			return line
+		}
+		path = parentField(path)
+	}
+	return 0
+}
+
+// parentField returns the path of the field containing the field at path.
+func parentField(path string) string {
+	if strings.HasSuffix(path, "]") {
+		if i := strings.LastIndex(path, "["); i >= 0 {
+			return path[:i]
+		}
+	}
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
+
+// DiffObjects returns a diff of the yaml of two objects, or an empty string if they are the same.
+// It is used to preview the fields which kOps sets when an edited object is saved.
+func DiffObjects(before, after interface{}) (string, error) {
+	beforeYaml, err := utils.YamlMarshal(before)
+	if err != nil {
+		return "", err
+	}
+	afterYaml, err := utils.YamlMarshal(after)
+	if err != nil {
+		return "", err
+	}
+	if bytes.Equal(beforeYaml, afterYaml) {
+		return "", nil
+	}
+	return diff.FormatDiff(string(beforeYaml), string(afterYaml)), nil
+}
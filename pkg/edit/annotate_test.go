@@ -0,0 +1,79 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edit
+
+import (
+	"testing"
+
+	"github.com/MakeNowJust/heredoc/v2"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestAnnotateErrors(t *testing.T) {
+	yaml := heredoc.Doc(`
+	apiVersion: kops.k8s.io/v1alpha2
+	kind: InstanceGroup
+	metadata:
+	  name: nodes
+	spec:
+	  maxSize: 1
+	  minSize: 2
+	  nodeLabels:
+	    kops.k8s.io/instancegroup: nodes
+	  subnets:
+	  - us-test-1a
+	  - us-test-1z
+	`)
+
+	errs := field.ErrorList{
+		field.Invalid(field.NewPath("spec", "minSize"), 2, "minSize must be less than or equal to maxSize"),
+		field.NotFound(field.NewPath("spec", "subnets").Index(1), "us-test-1z"),
+		field.Invalid(field.NewPath("spec", "nodeLabels").Key("kops.k8s.io/instancegroup"), "nodes", "reserved label"),
+		field.Required(field.NewPath("spec", "machineType"), ""),
+		field.Required(field.NewPath("objectMeta", "name"), ""),
+		field.Forbidden(field.NewPath("status"), "unexpected"),
+	}
+
+	expected := heredoc.Doc(`
+	apiVersion: kops.k8s.io/v1alpha2
+	kind: InstanceGroup
+	metadata:
+	  name: nodes
+	  # ^ Required value
+	spec:
+	# ^ Required value
+	  maxSize: 1
+	  minSize: 2
+	  # ^ Invalid value: 2: minSize must be less than or equal to maxSize
+	  nodeLabels:
+	    kops.k8s.io/instancegroup: nodes
+	    # ^ Invalid value: "nodes": reserved label
+	  subnets:
+	  - us-test-1a
+	  - us-test-1z
+	  # ^ Not found: "us-test-1z"
+	`)
+
+	annotated, unmatched := AnnotateErrors([]byte(yaml), errs)
+	if string(annotated) != expected {
+		t.Errorf("unexpected annotated yaml:\n%s\nexpected:\n%s", annotated, expected)
+	}
+	if len(unmatched) != 1 || unmatched[0].Field != "status" {
+		t.Errorf("unexpected unmatched errors: %v", unmatched)
+	}
+}
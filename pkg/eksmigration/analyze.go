@@ -0,0 +1,424 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eksmigration
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/dns"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// Status describes how a feature of a kOps cluster maps to EKS
+type Status string
+
+const (
+	// StatusCompatible features map to an EKS equivalent which is part of the generated configuration
+	StatusCompatible Status = "Compatible"
+	// StatusManual features have an EKS equivalent which has to be set up by hand
+	StatusManual Status = "Manual"
+	// StatusIncompatible features have no EKS equivalent
+	StatusIncompatible Status = "Incompatible"
+)
+
+// Finding is the result of the analysis of one feature of the cluster
+type Finding struct {
+	// Area is the part of the cluster spec, or the instance group, the finding is about
+	Area string `json:"area"`
+	// Status is how the feature maps to EKS
+	Status Status `json:"status"`
+	// Detail describes the EKS equivalent, or what has to be done
+	Detail string `json:"detail"`
+}
+
+// Plan is the EKS equivalent of a kOps cluster
+type Plan struct {
+	// Findings are the results of the analysis, in the order of the cluster spec
+	Findings []*Finding
+
+	Name    string
+	Region  string
+	Version string
+	Tags    map[string]string
+
+	// VPCID and Subnets are set when the cluster runs in an existing VPC
+	VPCID   string
+	Subnets []Subnet
+	// VPCCIDR is the CIDR of a new VPC, when the VPC is managed by kOps
+	VPCCIDR string
+
+	PublicAccessCIDRs []string
+	PrivateEndpoint   bool
+	KMSKey            string
+	AuditLogging      bool
+	OIDC              *kops.OIDCAuthenticationSpec
+	Addons            []string
+	ServiceAccounts   []kops.ServiceAccountExternalPermission
+	NodeGroups        []*NodeGroup
+}
+
+// NodeGroup is the managed node group replacing an instance group
+type NodeGroup struct {
+	Name              string
+	InstanceTypes     []string
+	MinSize           int32
+	MaxSize           int32
+	Spot              bool
+	VolumeSize        int32
+	Labels            map[string]string
+	Taints            []Taint
+	AvailabilityZones []string
+	PrivateNetworking bool
+}
+
+// Subnet is an existing subnet used by the EKS cluster
+type Subnet struct {
+	Name    string
+	ID      string
+	Zone    string
+	Private bool
+}
+
+// Taint is a node taint of a managed node group
+type Taint struct {
+	Key    string
+	Value  string
+	Effect string
+}
+
+// Analyze maps a kOps cluster and its instance groups to EKS
+func Analyze(cluster *kops.Cluster, instanceGroups []*kops.InstanceGroup) (*Plan, error) {
+	if kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
+		return nil, fmt.Errorf("only AWS clusters can be migrated to EKS, not %q", cluster.Spec.CloudProvider)
+	}
+
+	region, err := awsup.FindRegion(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Plan{
+		Name:   strings.ReplaceAll(cluster.ObjectMeta.Name, ".", "-"),
+		Region: region,
+		Tags:   cluster.Spec.CloudLabels,
+	}
+
+	p.analyzeCluster(cluster)
+	p.analyzeNetwork(cluster)
+	p.analyzeAPIServer(cluster)
+	p.analyzeAddons(cluster)
+	p.analyzeIAM(cluster)
+
+	sorted := append([]*kops.InstanceGroup(nil), instanceGroups...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ObjectMeta.Name < sorted[j].ObjectMeta.Name })
+	for _, ig := range sorted {
+		p.analyzeInstanceGroup(cluster, ig)
+	}
+
+	return p, nil
+}
+
+func (p *Plan) add(area string, status Status, format string, args ...interface{}) {
+	p.Findings = append(p.Findings, &Finding{
+		Area:   area,
+		Status: status,
+		Detail: fmt.Sprintf(format, args...),
+	})
+}
+
+// NeedsAction returns the findings which are not covered by the generated configuration
+func (p *Plan) NeedsAction() []*Finding {
+	var findings []*Finding
+	for _, f := range p.Findings {
+		if f.Status != StatusCompatible {
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+func (p *Plan) analyzeCluster(cluster *kops.Cluster) {
+	if p.Name != cluster.ObjectMeta.Name {
+		p.add("metadata.name", StatusManual, "EKS cluster names can't contain dots; the EKS cluster is named %s", p.Name)
+	}
+
+	version := strings.TrimPrefix(cluster.Spec.KubernetesVersion, "v")
+	if parts := strings.SplitN(version, ".", 3); len(parts) >= 2 {
+		p.Version = parts[0] + "." + parts[1]
+	}
+	p.add("kubernetesVersion", StatusCompatible, "EKS %s; check that EKS still supports it", p.Version)
+
+	p.add("etcdClusters", StatusIncompatible, "EKS does not expose etcd, so etcd-manager backups can't be restored; migrate the workloads with a backup tool such as Velero")
+
+	if dns.IsGossipHostname(cluster.ObjectMeta.Name) {
+		p.add("metadata.name", StatusIncompatible, "gossip DNS has no EKS equivalent; clients using api.%s have to switch to the endpoint of the EKS cluster", cluster.ObjectMeta.Name)
+	}
+
+	if len(cluster.Spec.Hooks) != 0 || len(cluster.Spec.FileAssets) != 0 {
+		p.add("hooks, fileAssets", StatusManual, "move them to the user data of a launch template of the node groups")
+	}
+}
+
+func (p *Plan) analyzeNetwork(cluster *kops.Cluster) {
+	shared := cluster.Spec.NetworkID != ""
+	for _, subnet := range cluster.Spec.Subnets {
+		if subnet.ProviderID == "" {
+			shared = false
+		}
+	}
+	if shared {
+		p.VPCID = cluster.Spec.NetworkID
+		for _, subnet := range cluster.Spec.Subnets {
+			p.Subnets = append(p.Subnets, Subnet{
+				Name:    subnet.Name,
+				ID:      subnet.ProviderID,
+				Zone:    subnet.Zone,
+				Private: subnet.Type == kops.SubnetTypePrivate,
+			})
+		}
+		p.add("networkID, subnets", StatusCompatible, "the EKS cluster uses the existing VPC %s and its subnets", p.VPCID)
+	} else {
+		p.VPCCIDR = cluster.Spec.NetworkCIDR
+		p.add("networkID, subnets", StatusManual, "the VPC is managed by kOps and is deleted with the cluster; the configuration creates a new VPC with CIDR %s, set the VPC and subnet IDs to reuse the existing one", p.VPCCIDR)
+	}
+
+	networking := cluster.Spec.Networking
+	if networking == nil {
+		return
+	}
+	if networking.AmazonVPC != nil {
+		p.Addons = append(p.Addons, "vpc-cni")
+		p.add("networking.amazonvpc", StatusCompatible, "vpc-cni EKS addon")
+		return
+	}
+	p.Addons = append(p.Addons, "vpc-cni")
+	v := reflect.ValueOf(networking).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).IsNil() {
+			continue
+		}
+		name := strings.Split(v.Type().Field(i).Tag.Get("json"), ",")[0]
+		p.add("networking."+name, StatusManual, "EKS nodes use the VPC CNI; to keep %s, install it after the cluster is created and remove the vpc-cni addon, or run it in policy-only mode", name)
+	}
+}
+
+func (p *Plan) analyzeAPIServer(cluster *kops.Cluster) {
+	spec := &cluster.Spec
+
+	if spec.API != nil && spec.API.LoadBalancer != nil && spec.API.LoadBalancer.Type == kops.LoadBalancerTypeInternal {
+		p.PrivateEndpoint = true
+		p.add("api.loadBalancer", StatusCompatible, "private EKS endpoint only")
+	}
+	for _, cidr := range spec.KubernetesAPIAccess {
+		if cidr != "0.0.0.0/0" {
+			p.PublicAccessCIDRs = spec.KubernetesAPIAccess
+			p.add("kubernetesAPIAccess", StatusCompatible, "public access to the EKS endpoint is restricted to %s", strings.Join(spec.KubernetesAPIAccess, ", "))
+			break
+		}
+	}
+
+	if spec.EncryptionAtRest != nil && spec.EncryptionAtRest.KMSKey != "" {
+		p.KMSKey = spec.EncryptionAtRest.KMSKey
+		p.add("encryptionAtRest", StatusCompatible, "EKS secrets encryption with %s", p.KMSKey)
+	}
+
+	if spec.Authentication != nil && spec.Authentication.OIDC != nil {
+		p.OIDC = spec.Authentication.OIDC
+	} else if apiServer := spec.KubeAPIServer; apiServer != nil && apiServer.OIDCIssuerURL != nil {
+		p.OIDC = &kops.OIDCAuthenticationSpec{
+			IssuerURL:     fi.StringValue(apiServer.OIDCIssuerURL),
+			ClientID:      fi.StringValue(apiServer.OIDCClientID),
+			UsernameClaim: fi.StringValue(apiServer.OIDCUsernameClaim),
+			GroupsClaim:   fi.StringValue(apiServer.OIDCGroupsClaim),
+		}
+	}
+	if p.OIDC != nil {
+		p.add("authentication.oidc", StatusCompatible, "EKS OIDC identity provider for %s", p.OIDC.IssuerURL)
+	}
+	if spec.Authentication != nil && spec.Authentication.Aws != nil {
+		p.add("authentication.aws", StatusManual, "EKS authenticates IAM identities natively; move the aws-iam-authenticator mappings to the aws-auth ConfigMap")
+	}
+	if spec.Authentication != nil && spec.Authentication.Kopeio != nil {
+		p.add("authentication.kopeio", StatusIncompatible, "EKS does not support webhook authentication")
+	}
+
+	if spec.Audit != nil {
+		p.AuditLogging = true
+		p.add("audit", StatusManual, "EKS ships the audit log to CloudWatch Logs; the audit policy, webhook and log shipping of kOps can't be configured")
+	}
+
+	apiServer := spec.KubeAPIServer
+	if apiServer == nil {
+		return
+	}
+	var plugins []string
+	plugins = append(plugins, apiServer.AdmissionControl...)
+	plugins = append(plugins, apiServer.EnableAdmissionPlugins...)
+	for _, plugin := range apiServer.DisableAdmissionPlugins {
+		plugins = append(plugins, "-"+plugin)
+	}
+	if len(plugins) != 0 {
+		p.add("kubeAPIServer.enableAdmissionPlugins", StatusIncompatible, "EKS does not allow changing the admission plugins (%s); use admission webhooks instead", strings.Join(plugins, ", "))
+	}
+	if apiServer.AuditLogPath != nil || apiServer.AuditPolicyFile != "" {
+		p.AuditLogging = true
+		p.add("kubeAPIServer.auditPolicyFile", StatusManual, "EKS ships the audit log to CloudWatch Logs with a fixed audit policy")
+	}
+}
+
+func (p *Plan) analyzeAddons(cluster *kops.Cluster) {
+	spec := &cluster.Spec
+
+	if spec.KubeDNS != nil && spec.KubeDNS.Provider == "KubeDNS" {
+		p.add("kubeDNS.provider", StatusManual, "EKS runs CoreDNS instead of kube-dns")
+	}
+	p.Addons = append(p.Addons, "coredns", "kube-proxy")
+
+	if spec.CloudConfig != nil && spec.CloudConfig.AWSEBSCSIDriver != nil && fi.BoolValue(spec.CloudConfig.AWSEBSCSIDriver.Enabled) {
+		p.Addons = append(p.Addons, "aws-ebs-csi-driver")
+		p.add("cloudConfig.awsEBSCSIDriver", StatusCompatible, "aws-ebs-csi-driver EKS addon")
+	}
+
+	if spec.NodeTerminationHandler != nil && fi.BoolValue(spec.NodeTerminationHandler.Enabled) {
+		p.add("nodeTerminationHandler", StatusCompatible, "managed node groups drain nodes on spot interruptions and rebalance recommendations")
+	}
+
+	helm := map[string]bool{
+		"awsLoadBalancerController": spec.AWSLoadBalancerController != nil && fi.BoolValue(spec.AWSLoadBalancerController.Enabled),
+		"certManager":               spec.CertManager != nil && fi.BoolValue(spec.CertManager.Enabled),
+		"clusterAutoscaler":         spec.ClusterAutoscaler != nil && fi.BoolValue(spec.ClusterAutoscaler.Enabled),
+		"metricsServer":             spec.MetricsServer != nil && fi.BoolValue(spec.MetricsServer.Enabled),
+	}
+	var names []string
+	for name, enabled := range helm {
+		if enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p.add(name, StatusManual, "not an EKS addon; install it with its Helm chart, using IAM roles for service accounts for its AWS permissions")
+	}
+}
+
+func (p *Plan) analyzeIAM(cluster *kops.Cluster) {
+	spec := &cluster.Spec
+	if spec.IAM != nil {
+		for _, sa := range spec.IAM.ServiceAccountExternalPermissions {
+			if sa.AWS == nil {
+				continue
+			}
+			p.ServiceAccounts = append(p.ServiceAccounts, sa)
+			p.add("iam.serviceAccountExternalPermissions", StatusCompatible, "IAM role for service account %s/%s", sa.Namespace, sa.Name)
+		}
+	}
+	if spec.AdditionalPolicies != nil || spec.ExternalPolicies != nil {
+		p.add("additionalPolicies, externalPolicies", StatusManual, "attach the policies to the node group roles, or better to IAM roles for service accounts")
+	}
+}
+
+func (p *Plan) analyzeInstanceGroup(cluster *kops.Cluster, ig *kops.InstanceGroup) {
+	area := "instanceGroup/" + ig.ObjectMeta.Name
+
+	switch ig.Spec.Role {
+	case kops.InstanceGroupRoleMaster:
+		p.add(area, StatusCompatible, "replaced by the EKS control plane")
+		return
+	case kops.InstanceGroupRoleBastion:
+		p.add(area, StatusManual, "EKS has no bastion; reach the nodes with SSM Session Manager, or keep a bastion in the VPC")
+		return
+	case kops.InstanceGroupRoleAPIServer:
+		p.add(area, StatusIncompatible, "the API servers are part of the EKS control plane and can't run on dedicated instance groups")
+		return
+	}
+
+	if ig.IsWindows() {
+		p.add(area, StatusManual, "Windows nodes are not supported by managed node groups; create a self-managed Windows node group")
+		return
+	}
+
+	ng := &NodeGroup{
+		Name:       ig.ObjectMeta.Name,
+		MinSize:    fi.Int32Value(ig.Spec.MinSize),
+		MaxSize:    fi.Int32Value(ig.Spec.MaxSize),
+		VolumeSize: fi.Int32Value(ig.Spec.RootVolumeSize),
+		Labels:     ig.Spec.NodeLabels,
+	}
+	if ng.MaxSize < ng.MinSize {
+		ng.MaxSize = ng.MinSize
+	}
+	if policy := ig.Spec.MixedInstancesPolicy; policy != nil && len(policy.Instances) != 0 {
+		ng.InstanceTypes = policy.Instances
+		ng.Spot = policy.OnDemandAboveBase != nil && *policy.OnDemandAboveBase == 0
+	} else {
+		ng.InstanceTypes = strings.Split(ig.Spec.MachineType, ",")
+	}
+	if ig.Spec.MaxPrice != nil {
+		ng.Spot = true
+		p.add(area, StatusManual, "managed node groups can't cap the spot price of %s", *ig.Spec.MaxPrice)
+	}
+	for _, taint := range ig.Spec.Taints {
+		ng.Taints = append(ng.Taints, parseTaint(taint))
+	}
+
+	zones := make(map[string]bool)
+	ng.PrivateNetworking = true
+	for _, name := range ig.Spec.Subnets {
+		for _, subnet := range cluster.Spec.Subnets {
+			if subnet.Name == name {
+				zones[subnet.Zone] = true
+				if subnet.Type != kops.SubnetTypePrivate {
+					ng.PrivateNetworking = false
+				}
+			}
+		}
+	}
+	for zone := range zones {
+		ng.AvailabilityZones = append(ng.AvailabilityZones, zone)
+	}
+	sort.Strings(ng.AvailabilityZones)
+
+	p.NodeGroups = append(p.NodeGroups, ng)
+	p.add(area, StatusCompatible, "managed node group %s", ng.Name)
+
+	if ig.Spec.Image != "" {
+		p.add(area, StatusManual, "managed node groups run the EKS optimized AMI; a launch template is needed to keep the image %s", ig.Spec.Image)
+	}
+	if len(ig.Spec.Hooks) != 0 || len(ig.Spec.FileAssets) != 0 || len(ig.Spec.AdditionalUserData) != 0 {
+		p.add(area, StatusManual, "move hooks, fileAssets and additionalUserData to the user data of a launch template")
+	}
+}
+
+// parseTaint converts a taint in the key=value:Effect form of the kubelet
+func parseTaint(s string) Taint {
+	var taint Taint
+	if i := strings.LastIndex(s, ":"); i != -1 {
+		taint.Effect = s[i+1:]
+		s = s[:i]
+	}
+	if i := strings.Index(s, "="); i != -1 {
+		taint.Value = s[i+1:]
+		s = s[:i]
+	}
+	taint.Key = s
+	return taint
+}
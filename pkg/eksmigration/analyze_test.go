@@ -0,0 +1,162 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eksmigration
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/pkg/testutils/golden"
+	"k8s.io/kops/upup/pkg/fi"
+	"sigs.k8s.io/yaml"
+)
+
+func buildCluster() (*kops.Cluster, []*kops.InstanceGroup) {
+	cluster := testutils.BuildMinimalCluster("minimal.example.com")
+	cluster.Spec.KubernetesVersion = "1.21.4"
+	cluster.Spec.NetworkID = "vpc-12345678"
+	cluster.Spec.Subnets[0].ProviderID = "subnet-1a"
+	cluster.Spec.Subnets[0].Type = kops.SubnetTypePrivate
+	cluster.Spec.Subnets[1].ProviderID = "subnet-1b"
+	cluster.Spec.Subnets[1].Type = kops.SubnetTypePrivate
+	cluster.Spec.Subnets[2].ProviderID = "subnet-1c"
+	cluster.Spec.Subnets[2].Type = kops.SubnetTypeUtility
+	cluster.Spec.Networking = &kops.NetworkingSpec{AmazonVPC: &kops.AmazonVPCNetworkingSpec{}}
+	cluster.Spec.KubernetesAPIAccess = []string{"10.0.0.0/8"}
+	cluster.Spec.EncryptionAtRest = &kops.EncryptionAtRestSpec{KMSKey: "arn:aws:kms:us-mock-1:123456789012:key/secrets"}
+	cluster.Spec.CloudConfig = &kops.CloudConfiguration{
+		AWSEBSCSIDriver: &kops.AWSEBSCSIDriver{Enabled: fi.Bool(true)},
+	}
+	cluster.Spec.MetricsServer = &kops.MetricsServerConfig{Enabled: fi.Bool(true)}
+	cluster.Spec.KubeAPIServer = &kops.KubeAPIServerConfig{
+		EnableAdmissionPlugins: []string{"NodeRestriction", "PodSecurityPolicy"},
+		OIDCIssuerURL:          fi.String("https://login.example.com"),
+		OIDCClientID:           fi.String("kubernetes"),
+		OIDCGroupsClaim:        fi.String("groups"),
+	}
+	cluster.Spec.IAM = &kops.IAMSpec{
+		ServiceAccountExternalPermissions: []kops.ServiceAccountExternalPermission{
+			{
+				Name:      "uploader",
+				Namespace: "media",
+				AWS: &kops.AWSPermission{
+					PolicyARNs:   []string{"arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess"},
+					InlinePolicy: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:PutObject","Resource":"arn:aws:s3:::media/*"}]}`,
+				},
+			},
+		},
+	}
+
+	instanceGroups := []*kops.InstanceGroup{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "nodes"},
+			Spec: kops.InstanceGroupSpec{
+				Role:        kops.InstanceGroupRoleNode,
+				MachineType: "t3.medium",
+				MinSize:     fi.Int32(2),
+				MaxSize:     fi.Int32(4),
+				Subnets:     []string{"subnet-us-mock-1a", "subnet-us-mock-1b"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "master-us-mock-1a"},
+			Spec: kops.InstanceGroupSpec{
+				Role:        kops.InstanceGroupRoleMaster,
+				MachineType: "m5.large",
+				MinSize:     fi.Int32(1),
+				MaxSize:     fi.Int32(1),
+				Subnets:     []string{"subnet-us-mock-1a"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "spot"},
+			Spec: kops.InstanceGroupSpec{
+				Role:           kops.InstanceGroupRoleNode,
+				MachineType:    "c5.xlarge",
+				Image:          "ami-0123456789abcdef0",
+				MinSize:        fi.Int32(1),
+				MaxSize:        fi.Int32(1),
+				MaxPrice:       fi.String("0.10"),
+				RootVolumeSize: fi.Int32(64),
+				Subnets:        []string{"subnet-us-mock-1c"},
+				NodeLabels:     map[string]string{"lifecycle": "spot"},
+				Taints:         []string{"dedicated=batch:NoSchedule"},
+			},
+		},
+	}
+
+	return cluster, instanceGroups
+}
+
+func TestAnalyze(t *testing.T) {
+	cluster, instanceGroups := buildCluster()
+
+	plan, err := Analyze(cluster, instanceGroups)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	findings, err := yaml.Marshal(plan.Findings)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	golden.AssertMatchesFile(t, string(findings), "tests/findings.yaml")
+
+	eksctl, err := plan.Eksctl()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	golden.AssertMatchesFile(t, string(eksctl), "tests/eksctl.yaml")
+
+	golden.AssertMatchesFile(t, string(plan.Terraform()), "tests/main.tf")
+}
+
+func TestAnalyzeGossip(t *testing.T) {
+	cluster := testutils.BuildMinimalCluster("minimal.k8s.local")
+
+	plan, err := Analyze(cluster, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	found := false
+	for _, finding := range plan.NeedsAction() {
+		if finding.Area == "metadata.name" && finding.Status == StatusIncompatible {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected gossip DNS to be reported as incompatible, got %v", plan.Findings)
+	}
+	if plan.Name != "minimal-k8s-local" {
+		t.Errorf("expected the EKS cluster to be named minimal-k8s-local, got %q", plan.Name)
+	}
+	if plan.VPCID != "" || plan.VPCCIDR != cluster.Spec.NetworkCIDR {
+		t.Errorf("expected a new VPC with CIDR %q, got %q/%q", cluster.Spec.NetworkCIDR, plan.VPCID, plan.VPCCIDR)
+	}
+}
+
+func TestAnalyzeRequiresAWS(t *testing.T) {
+	cluster := testutils.BuildMinimalCluster("minimal.example.com")
+	cluster.Spec.CloudProvider = string(kops.CloudProviderGCE)
+
+	if _, err := Analyze(cluster, nil); err == nil {
+		t.Errorf("expected an error analyzing a GCE cluster")
+	}
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eksmigration
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/yaml"
+)
+
+// The eksctl ClusterConfig types, see https://eksctl.io/usage/schema/
+
+type eksctlClusterConfig struct {
+	APIVersion        string                   `json:"apiVersion"`
+	Kind              string                   `json:"kind"`
+	Metadata          eksctlMetadata           `json:"metadata"`
+	VPC               *eksctlVPC               `json:"vpc,omitempty"`
+	IAM               *eksctlIAM               `json:"iam,omitempty"`
+	IdentityProviders []eksctlIdentityProvider `json:"identityProviders,omitempty"`
+	SecretsEncryption *eksctlSecretsEncryption `json:"secretsEncryption,omitempty"`
+	Addons            []eksctlAddon            `json:"addons,omitempty"`
+	CloudWatch        *eksctlCloudWatch        `json:"cloudWatch,omitempty"`
+	ManagedNodeGroups []eksctlManagedNodeGroup `json:"managedNodeGroups,omitempty"`
+}
+
+type eksctlMetadata struct {
+	Name    string            `json:"name"`
+	Region  string            `json:"region"`
+	Version string            `json:"version,omitempty"`
+	Tags    map[string]string `json:"tags,omitempty"`
+}
+
+type eksctlVPC struct {
+	ID                string                  `json:"id,omitempty"`
+	CIDR              string                  `json:"cidr,omitempty"`
+	Subnets           *eksctlSubnets          `json:"subnets,omitempty"`
+	PublicAccessCIDRs []string                `json:"publicAccessCIDRs,omitempty"`
+	ClusterEndpoints  *eksctlClusterEndpoints `json:"clusterEndpoints,omitempty"`
+}
+
+type eksctlSubnets struct {
+	Private map[string]eksctlSubnet `json:"private,omitempty"`
+	Public  map[string]eksctlSubnet `json:"public,omitempty"`
+}
+
+type eksctlSubnet struct {
+	ID string `json:"id"`
+	AZ string `json:"az"`
+}
+
+type eksctlClusterEndpoints struct {
+	PrivateAccess bool `json:"privateAccess"`
+	PublicAccess  bool `json:"publicAccess"`
+}
+
+type eksctlIAM struct {
+	WithOIDC        bool                   `json:"withOIDC"`
+	ServiceAccounts []eksctlServiceAccount `json:"serviceAccounts,omitempty"`
+}
+
+type eksctlServiceAccount struct {
+	Metadata         eksctlServiceAccountMetadata `json:"metadata"`
+	AttachPolicyARNs []string                     `json:"attachPolicyARNs,omitempty"`
+	AttachPolicy     map[string]interface{}       `json:"attachPolicy,omitempty"`
+}
+
+type eksctlServiceAccountMetadata struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+type eksctlIdentityProvider struct {
+	Type          string `json:"type"`
+	Name          string `json:"name"`
+	IssuerURL     string `json:"issuerUrl"`
+	ClientID      string `json:"clientId"`
+	UsernameClaim string `json:"usernameClaim,omitempty"`
+	GroupsClaim   string `json:"groupsClaim,omitempty"`
+}
+
+type eksctlSecretsEncryption struct {
+	KeyARN string `json:"keyARN"`
+}
+
+type eksctlAddon struct {
+	Name string `json:"name"`
+}
+
+type eksctlCloudWatch struct {
+	ClusterLogging eksctlClusterLogging `json:"clusterLogging"`
+}
+
+type eksctlClusterLogging struct {
+	EnableTypes []string `json:"enableTypes"`
+}
+
+type eksctlManagedNodeGroup struct {
+	Name              string            `json:"name"`
+	InstanceType      string            `json:"instanceType,omitempty"`
+	InstanceTypes     []string          `json:"instanceTypes,omitempty"`
+	Spot              bool              `json:"spot,omitempty"`
+	MinSize           int32             `json:"minSize"`
+	MaxSize           int32             `json:"maxSize"`
+	DesiredCapacity   int32             `json:"desiredCapacity"`
+	VolumeSize        int32             `json:"volumeSize,omitempty"`
+	AvailabilityZones []string          `json:"availabilityZones,omitempty"`
+	PrivateNetworking bool              `json:"privateNetworking,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Taints            []eksctlTaint     `json:"taints,omitempty"`
+}
+
+type eksctlTaint struct {
+	Key    string `json:"key"`
+	Value  string `json:"value,omitempty"`
+	Effect string `json:"effect"`
+}
+
+// eksctlTaintEffects maps the kubelet taint effects to the ones of the EKS API
+var eksctlTaintEffects = map[string]string{
+	"NoSchedule":       "NO_SCHEDULE",
+	"PreferNoSchedule": "PREFER_NO_SCHEDULE",
+	"NoExecute":        "NO_EXECUTE",
+}
+
+// Eksctl renders the plan as an eksctl ClusterConfig
+func (p *Plan) Eksctl() ([]byte, error) {
+	config := &eksctlClusterConfig{
+		APIVersion: "eksctl.io/v1alpha5",
+		Kind:       "ClusterConfig",
+		Metadata: eksctlMetadata{
+			Name:    p.Name,
+			Region:  p.Region,
+			Version: p.Version,
+			Tags:    p.Tags,
+		},
+	}
+
+	vpc := &eksctlVPC{
+		ID:                p.VPCID,
+		CIDR:              p.VPCCIDR,
+		PublicAccessCIDRs: p.PublicAccessCIDRs,
+	}
+	if len(p.Subnets) != 0 {
+		vpc.Subnets = &eksctlSubnets{}
+		for _, subnet := range p.Subnets {
+			if subnet.Private {
+				if vpc.Subnets.Private == nil {
+					vpc.Subnets.Private = make(map[string]eksctlSubnet)
+				}
+				vpc.Subnets.Private[subnet.Name] = eksctlSubnet{ID: subnet.ID, AZ: subnet.Zone}
+			} else {
+				if vpc.Subnets.Public == nil {
+					vpc.Subnets.Public = make(map[string]eksctlSubnet)
+				}
+				vpc.Subnets.Public[subnet.Name] = eksctlSubnet{ID: subnet.ID, AZ: subnet.Zone}
+			}
+		}
+	}
+	if p.PrivateEndpoint {
+		vpc.ClusterEndpoints = &eksctlClusterEndpoints{PrivateAccess: true, PublicAccess: false}
+	}
+	config.VPC = vpc
+
+	config.IAM = &eksctlIAM{WithOIDC: true}
+	for _, sa := range p.ServiceAccounts {
+		esa := eksctlServiceAccount{
+			Metadata: eksctlServiceAccountMetadata{
+				Name:      sa.Name,
+				Namespace: sa.Namespace,
+			},
+			AttachPolicyARNs: sa.AWS.PolicyARNs,
+		}
+		if sa.AWS.InlinePolicy != "" {
+			if err := yaml.Unmarshal([]byte(sa.AWS.InlinePolicy), &esa.AttachPolicy); err != nil {
+				return nil, fmt.Errorf("parsing inline policy of service account %s/%s: %w", sa.Namespace, sa.Name, err)
+			}
+		}
+		config.IAM.ServiceAccounts = append(config.IAM.ServiceAccounts, esa)
+	}
+
+	if p.OIDC != nil {
+		config.IdentityProviders = append(config.IdentityProviders, eksctlIdentityProvider{
+			Type:          "oidc",
+			Name:          "oidc",
+			IssuerURL:     p.OIDC.IssuerURL,
+			ClientID:      p.OIDC.ClientID,
+			UsernameClaim: p.OIDC.UsernameClaim,
+			GroupsClaim:   p.OIDC.GroupsClaim,
+		})
+	}
+
+	if p.KMSKey != "" {
+		config.SecretsEncryption = &eksctlSecretsEncryption{KeyARN: p.KMSKey}
+	}
+
+	for _, addon := range p.Addons {
+		config.Addons = append(config.Addons, eksctlAddon{Name: addon})
+	}
+
+	if p.AuditLogging {
+		config.CloudWatch = &eksctlCloudWatch{
+			ClusterLogging: eksctlClusterLogging{EnableTypes: []string{"audit"}},
+		}
+	}
+
+	for _, ng := range p.NodeGroups {
+		mng := eksctlManagedNodeGroup{
+			Name:              ng.Name,
+			Spot:              ng.Spot,
+			MinSize:           ng.MinSize,
+			MaxSize:           ng.MaxSize,
+			DesiredCapacity:   ng.MinSize,
+			VolumeSize:        ng.VolumeSize,
+			AvailabilityZones: ng.AvailabilityZones,
+			PrivateNetworking: ng.PrivateNetworking,
+			Labels:            ng.Labels,
+		}
+		if len(ng.InstanceTypes) == 1 {
+			mng.InstanceType = ng.InstanceTypes[0]
+		} else {
+			mng.InstanceTypes = ng.InstanceTypes
+		}
+		for _, taint := range ng.Taints {
+			mng.Taints = append(mng.Taints, eksctlTaint{
+				Key:    taint.Key,
+				Value:  taint.Value,
+				Effect: eksctlTaintEffects[taint.Effect],
+			})
+		}
+		config.ManagedNodeGroups = append(config.ManagedNodeGroups, mng)
+	}
+
+	return yaml.Marshal(config)
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eksmigration
+
+import (
+	"regexp"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var invalidTerraformName = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// terraformName converts a kOps name to a valid terraform identifier
+func terraformName(name string) string {
+	return invalidTerraformName.ReplaceAllString(name, "-")
+}
+
+func traversal(names ...string) hcl.Traversal {
+	t := hcl.Traversal{hcl.TraverseRoot{Name: names[0]}}
+	for _, name := range names[1:] {
+		t = append(t, hcl.TraverseAttr{Name: name})
+	}
+	return t
+}
+
+// typeTokens returns the tokens of a type constraint, which can't be written as a value
+func typeTokens(t string) hclwrite.Tokens {
+	return hclwrite.Tokens{
+		{Type: hclsyntax.TokenIdent, Bytes: []byte(t)},
+	}
+}
+
+func stringList(values []string) cty.Value {
+	if len(values) == 0 {
+		return cty.ListValEmpty(cty.String)
+	}
+	var l []cty.Value
+	for _, v := range values {
+		l = append(l, cty.StringVal(v))
+	}
+	return cty.ListVal(l)
+}
+
+func stringMap(values map[string]string) cty.Value {
+	if len(values) == 0 {
+		return cty.MapValEmpty(cty.String)
+	}
+	m := make(map[string]cty.Value)
+	for k, v := range values {
+		m[k] = cty.StringVal(v)
+	}
+	return cty.MapVal(m)
+}
+
+// Terraform renders the plan as terraform configuration, using the EKS module of terraform-aws-modules
+func (p *Plan) Terraform() []byte {
+	f := hclwrite.NewEmptyFile()
+	root := f.Body()
+
+	if p.VPCID == "" {
+		variable := root.AppendNewBlock("variable", []string{"vpc_id"}).Body()
+		variable.SetAttributeValue("description", cty.StringVal("The VPC of the EKS cluster; kOps VPCs are deleted with the cluster"))
+		variable.SetAttributeRaw("type", typeTokens("string"))
+		root.AppendNewline()
+
+		variable = root.AppendNewBlock("variable", []string{"subnet_ids"}).Body()
+		variable.SetAttributeValue("description", cty.StringVal("The subnets of the EKS cluster"))
+		variable.SetAttributeRaw("type", typeTokens("list(string)"))
+		root.AppendNewline()
+	}
+
+	eks := root.AppendNewBlock("module", []string{"eks"}).Body()
+	eks.SetAttributeValue("source", cty.StringVal("terraform-aws-modules/eks/aws"))
+	eks.SetAttributeValue("version", cty.StringVal("~> 17.0"))
+	eks.AppendNewline()
+	eks.SetAttributeValue("cluster_name", cty.StringVal(p.Name))
+	eks.SetAttributeValue("cluster_version", cty.StringVal(p.Version))
+	if p.VPCID == "" {
+		eks.SetAttributeTraversal("vpc_id", traversal("var", "vpc_id"))
+		eks.SetAttributeTraversal("subnets", traversal("var", "subnet_ids"))
+	} else {
+		eks.SetAttributeValue("vpc_id", cty.StringVal(p.VPCID))
+		var ids []string
+		for _, subnet := range p.Subnets {
+			ids = append(ids, subnet.ID)
+		}
+		eks.SetAttributeValue("subnets", stringList(ids))
+	}
+	eks.SetAttributeValue("enable_irsa", cty.True)
+	if p.PrivateEndpoint {
+		eks.SetAttributeValue("cluster_endpoint_private_access", cty.True)
+		eks.SetAttributeValue("cluster_endpoint_public_access", cty.False)
+	}
+	if len(p.PublicAccessCIDRs) != 0 {
+		eks.SetAttributeValue("cluster_endpoint_public_access_cidrs", stringList(p.PublicAccessCIDRs))
+	}
+	if p.KMSKey != "" {
+		eks.SetAttributeValue("cluster_encryption_config", cty.TupleVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"provider_key_arn": cty.StringVal(p.KMSKey),
+				"resources":        stringList([]string{"secrets"}),
+			}),
+		}))
+	}
+	if p.AuditLogging {
+		eks.SetAttributeValue("cluster_enabled_log_types", stringList([]string{"audit"}))
+	}
+	if len(p.Tags) != 0 {
+		eks.SetAttributeValue("tags", stringMap(p.Tags))
+	}
+
+	if len(p.NodeGroups) != 0 {
+		nodeGroups := make(map[string]cty.Value)
+		for _, ng := range p.NodeGroups {
+			capacityType := "ON_DEMAND"
+			if ng.Spot {
+				capacityType = "SPOT"
+			}
+			attrs := map[string]cty.Value{
+				"desired_capacity": cty.NumberIntVal(int64(ng.MinSize)),
+				"min_capacity":     cty.NumberIntVal(int64(ng.MinSize)),
+				"max_capacity":     cty.NumberIntVal(int64(ng.MaxSize)),
+				"instance_types":   stringList(ng.InstanceTypes),
+				"capacity_type":    cty.StringVal(capacityType),
+			}
+			if ng.VolumeSize != 0 {
+				attrs["disk_size"] = cty.NumberIntVal(int64(ng.VolumeSize))
+			}
+			if len(ng.Labels) != 0 {
+				attrs["k8s_labels"] = stringMap(ng.Labels)
+			}
+			if len(ng.Taints) != 0 {
+				var taints []cty.Value
+				for _, taint := range ng.Taints {
+					taints = append(taints, cty.ObjectVal(map[string]cty.Value{
+						"key":    cty.StringVal(taint.Key),
+						"value":  cty.StringVal(taint.Value),
+						"effect": cty.StringVal(eksctlTaintEffects[taint.Effect]),
+					}))
+				}
+				attrs["taints"] = cty.TupleVal(taints)
+			}
+			nodeGroups[ng.Name] = cty.ObjectVal(attrs)
+		}
+		eks.AppendNewline()
+		eks.SetAttributeValue("node_groups", cty.ObjectVal(nodeGroups))
+	}
+
+	for _, addon := range p.Addons {
+		root.AppendNewline()
+		block := root.AppendNewBlock("resource", []string{"aws_eks_addon", terraformName(addon)}).Body()
+		block.SetAttributeTraversal("cluster_name", traversal("module", "eks", "cluster_id"))
+		block.SetAttributeValue("addon_name", cty.StringVal(addon))
+	}
+
+	if p.OIDC != nil {
+		root.AppendNewline()
+		block := root.AppendNewBlock("resource", []string{"aws_eks_identity_provider_config", "oidc"}).Body()
+		block.SetAttributeTraversal("cluster_name", traversal("module", "eks", "cluster_id"))
+		oidc := block.AppendNewBlock("oidc", nil).Body()
+		oidc.SetAttributeValue("identity_provider_config_name", cty.StringVal("oidc"))
+		oidc.SetAttributeValue("issuer_url", cty.StringVal(p.OIDC.IssuerURL))
+		oidc.SetAttributeValue("client_id", cty.StringVal(p.OIDC.ClientID))
+		if p.OIDC.UsernameClaim != "" {
+			oidc.SetAttributeValue("username_claim", cty.StringVal(p.OIDC.UsernameClaim))
+		}
+		if p.OIDC.GroupsClaim != "" {
+			oidc.SetAttributeValue("groups_claim", cty.StringVal(p.OIDC.GroupsClaim))
+		}
+	}
+
+	for _, sa := range p.ServiceAccounts {
+		name := terraformName(sa.Namespace + "-" + sa.Name)
+		module := "irsa_" + name
+
+		root.AppendNewline()
+		block := root.AppendNewBlock("module", []string{module}).Body()
+		block.SetAttributeValue("source", cty.StringVal("terraform-aws-modules/iam/aws//modules/iam-assumable-role-with-oidc"))
+		block.SetAttributeValue("version", cty.StringVal("~> 4.0"))
+		block.AppendNewline()
+		block.SetAttributeValue("create_role", cty.True)
+		block.SetAttributeValue("role_name", cty.StringVal(p.Name+"-"+name))
+		block.SetAttributeTraversal("provider_url", traversal("module", "eks", "cluster_oidc_issuer_url"))
+		block.SetAttributeValue("oidc_fully_qualified_subjects", stringList([]string{"system:serviceaccount:" + sa.Namespace + ":" + sa.Name}))
+		if len(sa.AWS.PolicyARNs) != 0 {
+			block.SetAttributeValue("role_policy_arns", stringList(sa.AWS.PolicyARNs))
+		}
+
+		if sa.AWS.InlinePolicy != "" {
+			root.AppendNewline()
+			block := root.AppendNewBlock("resource", []string{"aws_iam_role_policy", name}).Body()
+			block.SetAttributeValue("name", cty.StringVal(name))
+			block.SetAttributeTraversal("role", traversal("module", module, "iam_role_name"))
+			block.SetAttributeValue("policy", cty.StringVal(sa.AWS.InlinePolicy))
+		}
+	}
+
+	return hclwrite.Format(f.Bytes())
+}
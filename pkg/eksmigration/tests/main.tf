@@ -0,0 +1,88 @@
+module "eks" {
+  source  = "terraform-aws-modules/eks/aws"
+  version = "~> 17.0"
+
+  cluster_name                         = "minimal-example-com"
+  cluster_version                      = "1.21"
+  vpc_id                               = "vpc-12345678"
+  subnets                              = ["subnet-1a", "subnet-1b", "subnet-1c"]
+  enable_irsa                          = true
+  cluster_endpoint_public_access_cidrs = ["10.0.0.0/8"]
+  cluster_encryption_config = [{
+    provider_key_arn = "arn:aws:kms:us-mock-1:123456789012:key/secrets"
+    resources        = ["secrets"]
+  }]
+
+  node_groups = {
+    nodes = {
+      capacity_type    = "ON_DEMAND"
+      desired_capacity = 2
+      instance_types   = ["t3.medium"]
+      max_capacity     = 4
+      min_capacity     = 2
+    }
+    spot = {
+      capacity_type    = "SPOT"
+      desired_capacity = 1
+      disk_size        = 64
+      instance_types   = ["c5.xlarge"]
+      k8s_labels = {
+        lifecycle = "spot"
+      }
+      max_capacity = 1
+      min_capacity = 1
+      taints = [{
+        effect = "NO_SCHEDULE"
+        key    = "dedicated"
+        value  = "batch"
+      }]
+    }
+  }
+}
+
+resource "aws_eks_addon" "vpc-cni" {
+  cluster_name = module.eks.cluster_id
+  addon_name   = "vpc-cni"
+}
+
+resource "aws_eks_addon" "coredns" {
+  cluster_name = module.eks.cluster_id
+  addon_name   = "coredns"
+}
+
+resource "aws_eks_addon" "kube-proxy" {
+  cluster_name = module.eks.cluster_id
+  addon_name   = "kube-proxy"
+}
+
+resource "aws_eks_addon" "aws-ebs-csi-driver" {
+  cluster_name = module.eks.cluster_id
+  addon_name   = "aws-ebs-csi-driver"
+}
+
+resource "aws_eks_identity_provider_config" "oidc" {
+  cluster_name = module.eks.cluster_id
+  oidc {
+    identity_provider_config_name = "oidc"
+    issuer_url                    = "https://login.example.com"
+    client_id                     = "kubernetes"
+    groups_claim                  = "groups"
+  }
+}
+
+module "irsa_media-uploader" {
+  source  = "terraform-aws-modules/iam/aws//modules/iam-assumable-role-with-oidc"
+  version = "~> 4.0"
+
+  create_role                   = true
+  role_name                     = "minimal-example-com-media-uploader"
+  provider_url                  = module.eks.cluster_oidc_issuer_url
+  oidc_fully_qualified_subjects = ["system:serviceaccount:media:uploader"]
+  role_policy_arns              = ["arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess"]
+}
+
+resource "aws_iam_role_policy" "media-uploader" {
+  name   = "media-uploader"
+  role   = module.irsa_media-uploader.iam_role_name
+  policy = "{\"Version\":\"2012-10-17\",\"Statement\":[{\"Effect\":\"Allow\",\"Action\":\"s3:PutObject\",\"Resource\":\"arn:aws:s3:::media/*\"}]}"
+}
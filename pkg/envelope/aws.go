@@ -0,0 +1,103 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+)
+
+// awsKeyService encrypts data keys with AWS KMS, in the region of the key
+type awsKeyService struct {
+	mutex   sync.Mutex
+	clients map[string]*kms.KMS
+}
+
+func newAWSKeyService() *awsKeyService {
+	return &awsKeyService{clients: make(map[string]*kms.KMS)}
+}
+
+func (s *awsKeyService) client(keyID string) (*kms.KMS, error) {
+	parsed, err := arn.Parse(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing KMS key ARN %q: %v", keyID, err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	client := s.clients[parsed.Region]
+	if client == nil {
+		config := aws.NewConfig().WithRegion(parsed.Region).WithCredentialsChainVerboseErrors(true)
+		sess, err := session.NewSessionWithOptions(session.Options{
+			Config:            *config,
+			SharedConfigState: session.SharedConfigEnable,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error starting new AWS session: %v", err)
+		}
+		client = kms.New(sess, config)
+		s.clients[parsed.Region] = client
+	}
+	return client, nil
+}
+
+// EncryptKey implements KeyService::EncryptKey
+func (s *awsKeyService) EncryptKey(keyID string, dataKey []byte) (string, []byte, error) {
+	client, err := s.client(keyID)
+	if err != nil {
+		return "", nil, err
+	}
+	response, err := client.Encrypt(&kms.EncryptInput{
+		KeyId:     aws.String(keyID),
+		Plaintext: dataKey,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return aws.StringValue(response.KeyId), response.CiphertextBlob, nil
+}
+
+// DecryptKey implements KeyService::DecryptKey
+func (s *awsKeyService) DecryptKey(keyID string, encryptedKey []byte) ([]byte, error) {
+	client, err := s.client(keyID)
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.Decrypt(&kms.DecryptInput{
+		KeyId:          aws.String(keyID),
+		CiphertextBlob: encryptedKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.Plaintext, nil
+}
+
+// AWSKeyARN returns the ARN of the AWS KMS key, or "" if it is not an AWS KMS key
+func AWSKeyARN(keyID string) string {
+	if strings.HasPrefix(keyID, "arn:") && strings.Contains(keyID, ":kms:") {
+		return keyID
+	}
+	return ""
+}
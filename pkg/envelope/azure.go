@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/services/keyvault/v7.0/keyvault"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+)
+
+// azureKeyService wraps data keys with Azure Key Vault keys, using RSA-OAEP-256
+type azureKeyService struct {
+	mutex  sync.Mutex
+	client *keyvault.BaseClient
+}
+
+func newAzureKeyService() *azureKeyService {
+	return &azureKeyService{}
+}
+
+func (s *azureKeyService) keyvault() (*keyvault.BaseClient, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.client == nil {
+		authorizer, err := auth.NewAuthorizerFromEnvironmentWithResource("https://vault.azure.net")
+		if err != nil {
+			return nil, fmt.Errorf("error creating an Azure Key Vault authorizer: %v", err)
+		}
+		client := keyvault.New()
+		client.Authorizer = authorizer
+		s.client = &client
+	}
+	return s.client, nil
+}
+
+// parseAzureKeyID splits a key URL (https://<vault>.vault.azure.net/keys/<name>[/<version>]) into the vault URL, key name and version
+func parseAzureKeyID(keyID string) (string, string, string, error) {
+	u, err := url.Parse(keyID)
+	if err != nil {
+		return "", "", "", fmt.Errorf("error parsing Key Vault key URL %q: %v", keyID, err)
+	}
+	tokens := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(tokens) < 2 || len(tokens) > 3 || tokens[0] != "keys" {
+		return "", "", "", fmt.Errorf("unexpected Key Vault key URL %q, expected https://<vault>.vault.azure.net/keys/<name>[/<version>]", keyID)
+	}
+	version := ""
+	if len(tokens) == 3 {
+		version = tokens[2]
+	}
+	return u.Scheme + "://" + u.Host, tokens[1], version, nil
+}
+
+// EncryptKey implements KeyService::EncryptKey; the returned ID includes the key version
+func (s *azureKeyService) EncryptKey(keyID string, dataKey []byte) (string, []byte, error) {
+	client, err := s.keyvault()
+	if err != nil {
+		return "", nil, err
+	}
+	vaultURL, name, version, err := parseAzureKeyID(keyID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	value := base64.RawURLEncoding.EncodeToString(dataKey)
+	result, err := client.WrapKey(context.Background(), vaultURL, name, version, keyvault.KeyOperationsParameters{
+		Algorithm: keyvault.RSAOAEP256,
+		Value:     &value,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	if result.Kid == nil || result.Result == nil {
+		return "", nil, fmt.Errorf("no wrapped key returned by Key Vault")
+	}
+	encryptedKey, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(*result.Result, "="))
+	if err != nil {
+		return "", nil, fmt.Errorf("error decoding wrapped key: %v", err)
+	}
+	return *result.Kid, encryptedKey, nil
+}
+
+// DecryptKey implements KeyService::DecryptKey
+func (s *azureKeyService) DecryptKey(keyID string, encryptedKey []byte) ([]byte, error) {
+	client, err := s.keyvault()
+	if err != nil {
+		return nil, err
+	}
+	vaultURL, name, version, err := parseAzureKeyID(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	value := base64.RawURLEncoding.EncodeToString(encryptedKey)
+	result, err := client.UnwrapKey(context.Background(), vaultURL, name, version, keyvault.KeyOperationsParameters{
+		Algorithm: keyvault.RSAOAEP256,
+		Value:     &value,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Result == nil {
+		return nil, fmt.Errorf("no unwrapped key returned by Key Vault")
+	}
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(*result.Result, "="))
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// header prefixes envelope-encrypted data, telling it apart from plaintext written before encryption was enabled
+var header = []byte("kops-envelope-v1\n")
+
+// envelope is encrypted data, with the data key that encrypted it, itself encrypted with a KMS key
+type envelope struct {
+	// KMSKey is the configured KMS key that encrypted the data key
+	KMSKey string `json:"kmsKey"`
+	// KeyID is the KMS key (or key version) that encrypted the data key, as reported by the KMS
+	KeyID string `json:"keyID"`
+	// EncryptedKey is the encrypted AES-256 data key
+	EncryptedKey []byte `json:"encryptedKey"`
+	// Nonce is the AES-GCM nonce
+	Nonce []byte `json:"nonce"`
+	// Ciphertext is the encrypted data
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// KeyService encrypts and decrypts data keys with a KMS key
+type KeyService interface {
+	// EncryptKey encrypts the data key with the KMS key, returning the ID of the key (version) that was used
+	EncryptKey(keyID string, dataKey []byte) (string, []byte, error)
+
+	// DecryptKey decrypts a data key that was encrypted with the KMS key (version)
+	DecryptKey(keyID string, encryptedKey []byte) ([]byte, error)
+}
+
+var (
+	keyServicesMutex sync.Mutex
+	keyServices      = map[string]KeyService{}
+)
+
+// keyKind returns the KMS that holds the key, based on the format of its ID
+func keyKind(keyID string) (string, error) {
+	switch {
+	case strings.HasPrefix(keyID, "arn:") && strings.Contains(keyID, ":kms:"):
+		return "aws", nil
+	case strings.HasPrefix(keyID, "projects/") && strings.Contains(keyID, "/cryptoKeys/"):
+		return "gcp", nil
+	case strings.HasPrefix(keyID, "https://") && strings.Contains(keyID, "/keys/"):
+		return "azure", nil
+	default:
+		return "", fmt.Errorf("unrecognized KMS key %q; expected an AWS KMS key ARN, a GCP KMS key resource name or an Azure Key Vault key URL", keyID)
+	}
+}
+
+// keyServiceFor returns the KeyService for the KMS key
+var keyServiceFor = func(keyID string) (KeyService, error) {
+	kind, err := keyKind(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	keyServicesMutex.Lock()
+	defer keyServicesMutex.Unlock()
+
+	if keyServices[kind] == nil {
+		switch kind {
+		case "aws":
+			keyServices[kind] = newAWSKeyService()
+		case "gcp":
+			keyServices[kind] = newGCPKeyService()
+		case "azure":
+			keyServices[kind] = newAzureKeyService()
+		}
+	}
+	return keyServices[kind], nil
+}
+
+// ValidateKeyID checks that the KMS key is in a supported format
+func ValidateKeyID(keyID string) error {
+	_, err := keyKind(keyID)
+	return err
+}
+
+// IsEncrypted returns true if the data is envelope-encrypted
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, header)
+}
+
+// EncryptedWith returns the KMS key that encrypted the data, or "" if it is not encrypted
+func EncryptedWith(data []byte) (string, error) {
+	if !IsEncrypted(data) {
+		return "", nil
+	}
+	e, err := parse(data)
+	if err != nil {
+		return "", err
+	}
+	return e.KMSKey, nil
+}
+
+// Encrypt encrypts the data with a new data key, which is encrypted with the KMS key.
+// The associated data, such as the name of the file, must be passed again to decrypt the data.
+func Encrypt(keyID string, plaintext []byte, associatedData []byte) ([]byte, error) {
+	keyService, err := keyServiceFor(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, fmt.Errorf("error generating data key: %v", err)
+	}
+
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("error generating nonce: %v", err)
+	}
+
+	usedKeyID, encryptedKey, err := keyService.EncryptKey(keyID, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting data key with %q: %v", keyID, err)
+	}
+
+	e := &envelope{
+		KMSKey:       keyID,
+		KeyID:        usedKeyID,
+		EncryptedKey: encryptedKey,
+		Nonce:        nonce,
+		Ciphertext:   gcm.Seal(nil, nonce, plaintext, associatedData),
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing envelope: %v", err)
+	}
+	return append(append([]byte{}, header...), data...), nil
+}
+
+// Decrypt decrypts envelope-encrypted data, with the KMS key that encrypted it and the associated data it was encrypted with.
+// Data that is not envelope-encrypted is rejected.
+func Decrypt(data []byte, associatedData []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return nil, fmt.Errorf("data is not envelope-encrypted")
+	}
+
+	e, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	keyService, err := keyServiceFor(e.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := keyService.DecryptKey(e.KeyID, e.EncryptedKey)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting data key with %q: %v", e.KeyID, err)
+	}
+
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, e.Nonce, e.Ciphertext, associatedData)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting data: %v", err)
+	}
+	return plaintext, nil
+}
+
+func parse(data []byte) (*envelope, error) {
+	e := &envelope{}
+	if err := json.Unmarshal(data[len(header):], e); err != nil {
+		return nil, fmt.Errorf("error parsing envelope: %v", err)
+	}
+	return e, nil
+}
+
+func newGCM(dataKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("error building cipher: %v", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error building cipher: %v", err)
+	}
+	return gcm, nil
+}
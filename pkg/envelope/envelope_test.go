@@ -0,0 +1,227 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// fakeKeyService "encrypts" data keys by prefixing them with the key ID
+type fakeKeyService struct{}
+
+func (s *fakeKeyService) EncryptKey(keyID string, dataKey []byte) (string, []byte, error) {
+	return keyID, append([]byte(keyID+":"), dataKey...), nil
+}
+
+func (s *fakeKeyService) DecryptKey(keyID string, encryptedKey []byte) ([]byte, error) {
+	prefix := []byte(keyID + ":")
+	if !bytes.HasPrefix(encryptedKey, prefix) {
+		return nil, fmt.Errorf("data key was not encrypted with %q", keyID)
+	}
+	return encryptedKey[len(prefix):], nil
+}
+
+func withFakeKeyService(t *testing.T) {
+	original := keyServiceFor
+	keyServiceFor = func(keyID string) (KeyService, error) {
+		if _, err := keyKind(keyID); err != nil {
+			return nil, err
+		}
+		return &fakeKeyService{}, nil
+	}
+	t.Cleanup(func() { keyServiceFor = original })
+}
+
+const (
+	testKey1 = "arn:aws:kms:us-east-1:123456789012:key/11111111-1111-1111-1111-111111111111"
+	testKey2 = "projects/p/locations/global/keyRings/r/cryptoKeys/k"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	withFakeKeyService(t)
+
+	plaintext := []byte("secret")
+	data, err := Encrypt(testKey1, plaintext, []byte("private/ca/keyset.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error encrypting: %v", err)
+	}
+	if !IsEncrypted(data) {
+		t.Fatalf("expected data to be encrypted")
+	}
+	if bytes.Contains(data, plaintext) {
+		t.Fatalf("encrypted data contains the plaintext")
+	}
+	if kmsKey, err := EncryptedWith(data); err != nil || kmsKey != testKey1 {
+		t.Fatalf("unexpected EncryptedWith: %q, %v", kmsKey, err)
+	}
+
+	actual, err := Decrypt(data, []byte("private/ca/keyset.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error decrypting: %v", err)
+	}
+	if !bytes.Equal(actual, plaintext) {
+		t.Fatalf("unexpected plaintext %q", actual)
+	}
+
+	// Data is bound to the associated data it was encrypted with
+	if _, err := Decrypt(data, []byte("private/kubernetes-ca/keyset.yaml")); err == nil {
+		t.Fatalf("expected error decrypting with different associated data")
+	}
+
+	if _, err := Decrypt(plaintext, nil); err == nil {
+		t.Fatalf("expected error decrypting data that is not encrypted")
+	}
+}
+
+func TestValidateKeyID(t *testing.T) {
+	grid := []struct {
+		KeyID string
+		Valid bool
+	}{
+		{KeyID: testKey1, Valid: true},
+		{KeyID: testKey2, Valid: true},
+		{KeyID: "https://myvault.vault.azure.net/keys/mykey", Valid: true},
+		{KeyID: "alias/mykey", Valid: false},
+		{KeyID: "", Valid: false},
+	}
+	for _, g := range grid {
+		err := ValidateKeyID(g.KeyID)
+		if g.Valid && err != nil {
+			t.Errorf("unexpected error for %q: %v", g.KeyID, err)
+		}
+		if !g.Valid && err == nil {
+			t.Errorf("expected error for %q", g.KeyID)
+		}
+	}
+}
+
+func TestPath(t *testing.T) {
+	withFakeKeyService(t)
+
+	inner := vfs.NewMemFSPath(vfs.NewMemFSContext(), "memfs://tests/secrets")
+	p := NewPath(inner, testKey1)
+
+	if err := p.Join("admin").WriteFile(strings.NewReader("secret"), nil); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+
+	raw, err := inner.Join("admin").ReadFile()
+	if err != nil {
+		t.Fatalf("unexpected error reading: %v", err)
+	}
+	if !IsEncrypted(raw) {
+		t.Fatalf("expected file to be stored encrypted, was %q", raw)
+	}
+
+	files, err := p.ReadTree()
+	if err != nil {
+		t.Fatalf("unexpected error listing: %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("unexpected files %v", files)
+	}
+	data, err := files[0].ReadFile()
+	if err != nil {
+		t.Fatalf("unexpected error reading: %v", err)
+	}
+	if string(data) != "secret" {
+		t.Fatalf("unexpected contents %q", data)
+	}
+
+	if vfs.Unwrap(files[0]).Path() != inner.Join("admin").Path() {
+		t.Fatalf("unexpected unwrapped path %q", vfs.Unwrap(files[0]).Path())
+	}
+
+	// A file can't be swapped with another file in the store
+	if err := p.Join("other").WriteFile(strings.NewReader("other"), nil); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	if err := inner.Join("other").WriteFile(bytes.NewReader(raw), nil); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	if _, err := p.Join("other").ReadFile(); err == nil {
+		t.Fatalf("expected error reading a file swapped with another")
+	}
+
+	// Files in plaintext are rejected
+	if err := inner.Join("plain").WriteFile(strings.NewReader("plain"), nil); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	if _, err := p.Join("plain").ReadFile(); err == nil {
+		t.Fatalf("expected error reading a file in plaintext")
+	}
+
+	mirror := vfs.WrapLike(p, vfs.NewMemFSPath(vfs.NewMemFSContext(), "memfs://tests/mirror"))
+	if _, ok := mirror.(*Path); !ok {
+		t.Fatalf("expected mirror to be encrypted, was %T", mirror)
+	}
+}
+
+func TestReencrypt(t *testing.T) {
+	withFakeKeyService(t)
+
+	inner := vfs.NewMemFSPath(vfs.NewMemFSContext(), "memfs://tests/secrets")
+	if err := inner.Join("plain").WriteFile(strings.NewReader("plain"), nil); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	if err := NewPath(inner, testKey1).Join("old").WriteFile(strings.NewReader("old"), nil); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	if err := NewPath(inner, testKey2).Join("current").WriteFile(strings.NewReader("current"), nil); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+
+	aclOracle := func(vfs.Path) (vfs.ACL, error) { return nil, nil }
+
+	reencrypted, err := Reencrypt(NewPath(inner, testKey2), false, aclOracle)
+	if err != nil {
+		t.Fatalf("unexpected error re-encrypting: %v", err)
+	}
+	if len(reencrypted) != 2 {
+		t.Fatalf("expected plain and old to be re-encrypted, was %v", reencrypted)
+	}
+
+	for _, name := range []string{"plain", "old", "current"} {
+		raw, err := inner.Join(name).ReadFile()
+		if err != nil {
+			t.Fatalf("unexpected error reading %s: %v", name, err)
+		}
+		if kmsKey, err := EncryptedWith(raw); err != nil || kmsKey != testKey2 {
+			t.Errorf("expected %s to be encrypted with %q, was %q (%v)", name, testKey2, kmsKey, err)
+		}
+		data, err := NewPath(inner, testKey2).Join(name).ReadFile()
+		if err != nil {
+			t.Fatalf("unexpected error decrypting %s: %v", name, err)
+		}
+		if string(data) != name {
+			t.Errorf("unexpected contents of %s: %q", name, data)
+		}
+	}
+
+	reencrypted, err = Reencrypt(NewPath(inner, testKey2), true, aclOracle)
+	if err != nil {
+		t.Fatalf("unexpected error re-encrypting: %v", err)
+	}
+	if len(reencrypted) != 3 {
+		t.Fatalf("expected all files to be re-encrypted, was %v", reencrypted)
+	}
+}
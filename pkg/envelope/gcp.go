@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync"
+
+	cloudkms "google.golang.org/api/cloudkms/v1"
+)
+
+// gcpKeyService encrypts data keys with Cloud KMS
+type gcpKeyService struct {
+	mutex  sync.Mutex
+	client *cloudkms.Service
+}
+
+func newGCPKeyService() *gcpKeyService {
+	return &gcpKeyService{}
+}
+
+func (s *gcpKeyService) keys() (*cloudkms.ProjectsLocationsKeyRingsCryptoKeysService, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.client == nil {
+		client, err := cloudkms.NewService(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("error building Cloud KMS client: %v", err)
+		}
+		s.client = client
+	}
+	return s.client.Projects.Locations.KeyRings.CryptoKeys, nil
+}
+
+// EncryptKey implements KeyService::EncryptKey; the returned ID is that of the key version
+func (s *gcpKeyService) EncryptKey(keyID string, dataKey []byte) (string, []byte, error) {
+	keys, err := s.keys()
+	if err != nil {
+		return "", nil, err
+	}
+	response, err := keys.Encrypt(keyID, &cloudkms.EncryptRequest{
+		Plaintext: base64.StdEncoding.EncodeToString(dataKey),
+	}).Do()
+	if err != nil {
+		return "", nil, err
+	}
+	encryptedKey, err := base64.StdEncoding.DecodeString(response.Ciphertext)
+	if err != nil {
+		return "", nil, fmt.Errorf("error decoding encrypted key: %v", err)
+	}
+	return response.Name, encryptedKey, nil
+}
+
+// DecryptKey implements KeyService::DecryptKey; the ciphertext identifies the key version, so it is decrypted with the key
+func (s *gcpKeyService) DecryptKey(keyID string, encryptedKey []byte) ([]byte, error) {
+	keys, err := s.keys()
+	if err != nil {
+		return nil, err
+	}
+	if i := strings.Index(keyID, "/cryptoKeyVersions/"); i != -1 {
+		keyID = keyID[:i]
+	}
+	response, err := keys.Decrypt(keyID, &cloudkms.DecryptRequest{
+		Ciphertext: base64.StdEncoding.EncodeToString(encryptedKey),
+	}).Do()
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(response.Plaintext)
+}
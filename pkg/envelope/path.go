@@ -0,0 +1,234 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// Path is a vfs.Path whose contents are envelope-encrypted with a KMS key.
+// Each file is bound to its path relative to the store, so that it can't be swapped with another file,
+// and files that are not encrypted are rejected until they are migrated with Reencrypt.
+type Path struct {
+	inner vfs.Path
+	// root is the path of the store, which the files are bound to the path relative to
+	root   string
+	kmsKey string
+}
+
+var _ vfs.Path = &Path{}
+var _ vfs.WrappedPath = &Path{}
+var _ vfs.HasClusterReadable = &Path{}
+
+// NewPath wraps the path, so that its contents are envelope-encrypted with the KMS key
+func NewPath(inner vfs.Path, kmsKey string) *Path {
+	return &Path{
+		inner:  inner,
+		root:   inner.Path(),
+		kmsKey: kmsKey,
+	}
+}
+
+// ForCluster wraps the secret store or keystore path of the cluster, if the cluster encrypts them
+func ForCluster(cluster *kops.Cluster, p vfs.Path) vfs.Path {
+	if cluster == nil || cluster.Spec.SecretStoreEncryption == nil || cluster.Spec.SecretStoreEncryption.KMSKey == "" {
+		return p
+	}
+	return NewPath(p, cluster.Spec.SecretStoreEncryption.KMSKey)
+}
+
+// KMSKey returns the KMS key that encrypts the contents written to the path
+func (p *Path) KMSKey() string {
+	return p.kmsKey
+}
+
+// Unwrap implements vfs.WrappedPath::Unwrap
+func (p *Path) Unwrap() vfs.Path {
+	return p.inner
+}
+
+// Wrap implements vfs.WrappedPath::Wrap; a path outside of the store, such as a mirror, is the root of a new store
+func (p *Path) Wrap(other vfs.Path) vfs.Path {
+	wrapped := NewPath(other, p.kmsKey)
+	if other.Path() == p.root || strings.HasPrefix(other.Path(), strings.TrimSuffix(p.root, "/")+"/") {
+		wrapped.root = p.root
+	}
+	return wrapped
+}
+
+// associatedData returns the path relative to the store, which the contents are bound to
+func (p *Path) associatedData() []byte {
+	return []byte(strings.TrimPrefix(strings.TrimPrefix(p.inner.Path(), p.root), "/"))
+}
+
+func (p *Path) wrap(paths []vfs.Path) []vfs.Path {
+	var wrapped []vfs.Path
+	for _, child := range paths {
+		wrapped = append(wrapped, p.Wrap(child))
+	}
+	return wrapped
+}
+
+// IsClusterReadable implements vfs.HasClusterReadable::IsClusterReadable
+func (p *Path) IsClusterReadable() bool {
+	return vfs.IsClusterReadable(p.inner)
+}
+
+func (p *Path) Join(relativePath ...string) vfs.Path {
+	return p.Wrap(p.inner.Join(relativePath...))
+}
+
+// ReadFile implements vfs.Path::ReadFile, decrypting the contents
+func (p *Path) ReadFile() ([]byte, error) {
+	data, err := p.inner.ReadFile()
+	if err != nil {
+		return nil, err
+	}
+	if !IsEncrypted(data) {
+		return nil, fmt.Errorf("%s is not encrypted; run kops toolbox reencrypt to encrypt the files written before spec.secretStoreEncryption was set", p.inner)
+	}
+	plaintext, err := Decrypt(data, p.associatedData())
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting %s: %v", p.inner, err)
+	}
+	return plaintext, nil
+}
+
+// WriteTo implements io.WriterTo::WriteTo, decrypting the contents
+func (p *Path) WriteTo(out io.Writer) (int64, error) {
+	data, err := p.ReadFile()
+	if err != nil {
+		return 0, err
+	}
+	n, err := out.Write(data)
+	return int64(n), err
+}
+
+func (p *Path) encrypt(data io.ReadSeeker) (io.ReadSeeker, error) {
+	plaintext, err := ioutil.ReadAll(data)
+	if err != nil {
+		return nil, fmt.Errorf("error reading data: %v", err)
+	}
+	encrypted, err := Encrypt(p.kmsKey, plaintext, p.associatedData())
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting %s: %v", p.inner, err)
+	}
+	return bytes.NewReader(encrypted), nil
+}
+
+// WriteFile implements vfs.Path::WriteFile, encrypting the contents
+func (p *Path) WriteFile(data io.ReadSeeker, acl vfs.ACL) error {
+	encrypted, err := p.encrypt(data)
+	if err != nil {
+		return err
+	}
+	return p.inner.WriteFile(encrypted, acl)
+}
+
+// CreateFile implements vfs.Path::CreateFile, encrypting the contents
+func (p *Path) CreateFile(data io.ReadSeeker, acl vfs.ACL) error {
+	encrypted, err := p.encrypt(data)
+	if err != nil {
+		return err
+	}
+	return p.inner.CreateFile(encrypted, acl)
+}
+
+func (p *Path) Remove() error {
+	return p.inner.Remove()
+}
+
+func (p *Path) RemoveAllVersions() error {
+	return p.inner.RemoveAllVersions()
+}
+
+func (p *Path) Base() string {
+	return p.inner.Base()
+}
+
+func (p *Path) Path() string {
+	return p.inner.Path()
+}
+
+func (p *Path) String() string {
+	return p.Path()
+}
+
+func (p *Path) ReadDir() ([]vfs.Path, error) {
+	paths, err := p.inner.ReadDir()
+	if err != nil {
+		return nil, err
+	}
+	return p.wrap(paths), nil
+}
+
+func (p *Path) ReadTree() ([]vfs.Path, error) {
+	paths, err := p.inner.ReadTree()
+	if err != nil {
+		return nil, err
+	}
+	return p.wrap(paths), nil
+}
+
+// Reencrypt re-encrypts the files in the tree that are not encrypted with the KMS key of the path (or all of them, if all is true),
+// returning the paths of the files that were re-encrypted. It is the only way files in plaintext are read.
+func Reencrypt(p *Path, all bool, aclOracle vfs.ACLOracle) ([]string, error) {
+	files, err := p.inner.ReadTree()
+	if err != nil {
+		return nil, fmt.Errorf("error listing %s: %v", p.inner, err)
+	}
+
+	var reencrypted []string
+	for _, file := range files {
+		data, err := file.ReadFile()
+		if err != nil {
+			return reencrypted, fmt.Errorf("error reading %s: %v", file, err)
+		}
+		kmsKey, err := EncryptedWith(data)
+		if err != nil {
+			return reencrypted, fmt.Errorf("error reading %s: %v", file, err)
+		}
+		if kmsKey == p.kmsKey && !all {
+			continue
+		}
+
+		wrapped := p.Wrap(file).(*Path)
+		plaintext := data
+		if kmsKey != "" {
+			plaintext, err = Decrypt(data, wrapped.associatedData())
+			if err != nil {
+				return reencrypted, fmt.Errorf("error decrypting %s: %v", file, err)
+			}
+		}
+		acl, err := aclOracle(file)
+		if err != nil {
+			return reencrypted, err
+		}
+		if err := wrapped.WriteFile(bytes.NewReader(plaintext), acl); err != nil {
+			return reencrypted, err
+		}
+		reencrypted = append(reencrypted, file.Path())
+	}
+	return reencrypted, nil
+}
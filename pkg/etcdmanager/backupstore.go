@@ -0,0 +1,229 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdmanager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+const (
+	// BackupMetaFile is the file etcd-manager writes next to each backup, describing it.
+	BackupMetaFile = "_etcd_backup.meta"
+
+	// CommandFile is the file holding a command in the control directory of a backup store.
+	CommandFile = "_command.json"
+
+	// controlDir is the directory of the backup store that etcd-manager reads commands from.
+	controlDir = "control"
+)
+
+// ClusterSpec is the desired size and version of an etcd cluster, as understood by etcd-manager.
+type ClusterSpec struct {
+	MemberCount int32  `json:"memberCount,omitempty"`
+	EtcdVersion string `json:"etcdVersion,omitempty"`
+}
+
+// BackupInfo describes a backup taken by etcd-manager.
+type BackupInfo struct {
+	EtcdVersion string       `json:"etcdVersion,omitempty"`
+	Timestamp   int64        `json:"timestamp,string,omitempty"`
+	ClusterSpec *ClusterSpec `json:"clusterSpec,omitempty"`
+}
+
+// Backup is a backup in the backup store.
+type Backup struct {
+	// Name is the name of the backup, as used with the restore command.
+	Name string
+	// Info is the metadata etcd-manager recorded with the backup.
+	Info *BackupInfo
+}
+
+// Command is an instruction to etcd-manager, picked up by the etcd-manager leader when it starts.
+// These are the commands that etcd-manager-ctl writes; etcd-manager has no command to take a backup,
+// as it takes backups on a schedule.
+type Command struct {
+	Timestamp int64 `json:"timestamp,string,omitempty"`
+
+	// CreateNewCluster asks etcd-manager to create a new etcd cluster, replacing the existing one.
+	CreateNewCluster *CreateNewClusterCommand `json:"createNewCluster,omitempty"`
+	// RestoreBackup asks etcd-manager to restore a backup onto the new etcd cluster.
+	RestoreBackup *RestoreBackupCommand `json:"restoreBackup,omitempty"`
+}
+
+// CreateNewClusterCommand asks for a new etcd cluster to be created.
+type CreateNewClusterCommand struct {
+	ClusterSpec *ClusterSpec `json:"clusterSpec,omitempty"`
+}
+
+// RestoreBackupCommand asks for the etcd cluster to be restored from a backup.
+type RestoreBackupCommand struct {
+	ClusterSpec *ClusterSpec `json:"clusterSpec,omitempty"`
+	Backup      string       `json:"backup,omitempty"`
+}
+
+// BackupStore reads and writes the backups and commands of one etcd cluster,
+// using the same layout as etcd-manager and etcd-manager-ctl.
+type BackupStore struct {
+	base vfs.Path
+}
+
+// NewBackupStore returns the backup store at the given path.
+func NewBackupStore(base vfs.Path) *BackupStore {
+	return &BackupStore{base: base}
+}
+
+// Path returns the location of the backup store.
+func (s *BackupStore) Path() vfs.Path {
+	return s.base
+}
+
+// ListBackups returns the backups in the store, oldest first.
+func (s *BackupStore) ListBackups() ([]*Backup, error) {
+	files, err := s.base.ReadTree()
+	if err != nil {
+		return nil, fmt.Errorf("error listing backups in %s: %v", s.base, err)
+	}
+
+	var backups []*Backup
+	for _, f := range files {
+		if f.Base() != BackupMetaFile {
+			continue
+		}
+		name, ok := s.relativeDir(f)
+		if !ok || name == "" || strings.Contains(name, "/") || name == controlDir {
+			continue
+		}
+
+		info, err := readBackupInfo(f)
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, &Backup{Name: name, Info: info})
+	}
+
+	// etcd-manager names backups after the time they were taken, so they sort chronologically.
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Name < backups[j].Name
+	})
+	return backups, nil
+}
+
+// GetBackup returns the named backup, or nil if it does not exist.
+func (s *BackupStore) GetBackup(name string) (*Backup, error) {
+	info, err := readBackupInfo(s.base.Join(name, BackupMetaFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &Backup{Name: name, Info: info}, nil
+}
+
+// ReadClusterSpec returns the cluster spec kOps wrote for etcd-manager, or nil if there is none.
+func (s *BackupStore) ReadClusterSpec() (*ClusterSpec, error) {
+	p := s.base.Join(controlDir, "etcd-cluster-spec")
+	b, err := p.ReadFile()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading %s: %v", p, err)
+	}
+	spec := &ClusterSpec{}
+	if err := json.Unmarshal(b, spec); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", p, err)
+	}
+	return spec, nil
+}
+
+// AddCommand adds a command for etcd-manager to the control directory, returning the path it was written to.
+func (s *BackupStore) AddCommand(cmd *Command, now time.Time) (vfs.Path, error) {
+	cmd.Timestamp = now.UnixNano()
+	b, err := json.MarshalIndent(cmd, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error serializing command: %v", err)
+	}
+
+	p := s.base.Join(controlDir, now.UTC().Format(time.RFC3339Nano), CommandFile)
+	if err := p.WriteFile(bytes.NewReader(b), nil); err != nil {
+		return nil, fmt.Errorf("error writing command to %s: %v", p, err)
+	}
+	return p, nil
+}
+
+// ListCommands returns the commands that etcd-manager has not yet carried out, keyed by their path.
+func (s *BackupStore) ListCommands() (map[string]*Command, error) {
+	files, err := s.base.Join(controlDir).ReadTree()
+	if err != nil {
+		return nil, fmt.Errorf("error listing commands in %s: %v", s.base, err)
+	}
+
+	commands := make(map[string]*Command)
+	for _, f := range files {
+		if f.Base() != CommandFile {
+			continue
+		}
+		b, err := f.ReadFile()
+		if err != nil {
+			if os.IsNotExist(err) {
+				// The command was carried out while we were listing
+				continue
+			}
+			return nil, fmt.Errorf("error reading %s: %v", f, err)
+		}
+		cmd := &Command{}
+		if err := json.Unmarshal(b, cmd); err != nil {
+			return nil, fmt.Errorf("error parsing %s: %v", f, err)
+		}
+		commands[f.Path()] = cmd
+	}
+	return commands, nil
+}
+
+// relativeDir returns the directory of p, relative to the backup store.
+func (s *BackupStore) relativeDir(p vfs.Path) (string, bool) {
+	base := strings.TrimSuffix(s.base.Path(), "/") + "/"
+	if !strings.HasPrefix(p.Path(), base) {
+		return "", false
+	}
+	return path.Dir(strings.TrimPrefix(p.Path(), base)), true
+}
+
+func readBackupInfo(p vfs.Path) (*BackupInfo, error) {
+	b, err := p.ReadFile()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error reading %s: %v", p, err)
+	}
+	info := &BackupInfo{}
+	if err := json.Unmarshal(b, info); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", p, err)
+	}
+	return info, nil
+}
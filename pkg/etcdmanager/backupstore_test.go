@@ -0,0 +1,113 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdmanager
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func writeFile(t *testing.T, p vfs.Path, contents string) {
+	if err := p.WriteFile(bytes.NewReader([]byte(contents)), nil); err != nil {
+		t.Fatalf("error writing %s: %v", p, err)
+	}
+}
+
+func TestBackupStore(t *testing.T) {
+	base := vfs.NewMemFSPath(vfs.NewMemFSContext(), "clusters/test.example.com/backups/etcd/main")
+	writeFile(t, base.Join("2021-05-02T10:00:00Z-000002", BackupMetaFile), `{"etcdVersion": "3.4.13", "timestamp": "1619949600000000000"}`)
+	writeFile(t, base.Join("2021-05-02T10:00:00Z-000002", "etcd.backup.gz"), "data")
+	writeFile(t, base.Join("2021-05-01T10:00:00Z-000001", BackupMetaFile), `{"etcdVersion": "3.4.3"}`)
+	writeFile(t, base.Join("control", "etcd-cluster-spec"), `{"memberCount": 3, "etcdVersion": "3.4.13"}`)
+
+	s := NewBackupStore(base)
+
+	backups, err := s.ListBackups()
+	if err != nil {
+		t.Fatalf("error listing backups: %v", err)
+	}
+	if len(backups) != 2 || backups[0].Name != "2021-05-01T10:00:00Z-000001" || backups[1].Name != "2021-05-02T10:00:00Z-000002" {
+		t.Fatalf("unexpected backups %v", backups)
+	}
+	if backups[1].Info.EtcdVersion != "3.4.13" || backups[1].Info.Timestamp != 1619949600000000000 {
+		t.Errorf("unexpected backup info %+v", backups[1].Info)
+	}
+
+	backup, err := s.GetBackup("2021-05-01T10:00:00Z-000001")
+	if err != nil {
+		t.Fatalf("error getting backup: %v", err)
+	}
+	if backup == nil || backup.Info.EtcdVersion != "3.4.3" {
+		t.Errorf("unexpected backup %v", backup)
+	}
+	backup, err = s.GetBackup("missing")
+	if err != nil || backup != nil {
+		t.Errorf("expected missing backup to be nil, got %v, %v", backup, err)
+	}
+
+	spec, err := s.ReadClusterSpec()
+	if err != nil {
+		t.Fatalf("error reading cluster spec: %v", err)
+	}
+	if spec == nil || spec.MemberCount != 3 {
+		t.Errorf("unexpected cluster spec %v", spec)
+	}
+
+	now := time.Date(2021, 5, 3, 10, 0, 0, 0, time.UTC)
+	p, err := s.AddCommand(&Command{RestoreBackup: &RestoreBackupCommand{Backup: backups[1].Name}}, now)
+	if err != nil {
+		t.Fatalf("error adding command: %v", err)
+	}
+	if p.Path() != "memfs://clusters/test.example.com/backups/etcd/main/control/2021-05-03T10:00:00Z/_command.json" {
+		t.Errorf("unexpected command path %s", p)
+	}
+	data, err := p.ReadFile()
+	if err != nil {
+		t.Fatalf("error reading command: %v", err)
+	}
+	expected := `{
+  "timestamp": "1620036000000000000",
+  "restoreBackup": {
+    "backup": "2021-05-02T10:00:00Z-000002"
+  }
+}`
+	if string(data) != expected {
+		t.Errorf("unexpected command %s, expected %s", data, expected)
+	}
+
+	commands, err := s.ListCommands()
+	if err != nil {
+		t.Fatalf("error listing commands: %v", err)
+	}
+	if len(commands) != 1 || commands[p.Path()] == nil || commands[p.Path()].RestoreBackup.Backup != backups[1].Name {
+		t.Errorf("unexpected commands %v", commands)
+	}
+
+	if err := p.Remove(); err != nil {
+		t.Fatalf("error removing command: %v", err)
+	}
+	commands, err = s.ListCommands()
+	if err != nil {
+		t.Fatalf("error listing commands: %v", err)
+	}
+	if len(commands) != 0 {
+		t.Errorf("expected no commands after removal, got %v", commands)
+	}
+}
@@ -56,8 +56,6 @@ var (
 	EnableExternalCloudController = New("EnableExternalCloudController", Bool(false))
 	// EnableExternalDNS enables external DNS
 	EnableExternalDNS = New("EnableExternalDNS", Bool(false))
-	// EnableSeparateConfigBase allows a config-base that is different from the state store
-	EnableSeparateConfigBase = New("EnableSeparateConfigBase", Bool(false))
 	// ExperimentalClusterDNS allows for setting the kubelet dns flag to experimental values.
 	ExperimentalClusterDNS = New("ExperimentalClusterDNS", Bool(false))
 	// GoogleCloudBucketACL means the ACL will be set on a bucket when using GCS
@@ -93,6 +91,8 @@ var (
 	UseServiceAccountIAM = New("UseServiceAccountIAM", Bool(false))
 	// Azure toggles the Azure support.
 	Azure = New("Azure", Bool(false))
+	// Metal enables the metal cloud provider, which manages pre-existing machines over SSH
+	Metal = New("Metal", Bool(false))
 	// KopsControllerStateStore enables fetching the kops state from kops-controller, instead of requiring access to S3/GCS/etc.
 	KopsControllerStateStore = New("KopsControllerStateStore", Bool(false))
 	// APIServerNodes enables ability to provision nodes that only run the kube-apiserver
@@ -0,0 +1,605 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/dns"
+	"k8s.io/kops/pkg/kopscodecs"
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/pkg/model/iam"
+	nodeidentityaws "k8s.io/kops/pkg/nodeidentity/aws"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// Action is what the first kops update cluster does to a resource
+type Action string
+
+const (
+	// ActionKeep resources are adopted by kOps as they are
+	ActionKeep Action = "Keep"
+	// ActionUpdate resources are adopted and changed by kOps
+	ActionUpdate Action = "Update"
+	// ActionCreate resources don't exist and are created by kOps
+	ActionCreate Action = "Create"
+	// ActionReplace resources are replaced by new resources created by kOps; the old ones have to be deleted by hand
+	ActionReplace Action = "Replace"
+	// ActionManual settings could not be discovered and have to be set in the spec by hand
+	ActionManual Action = "Manual"
+)
+
+// Change describes what the first kops update cluster of the imported cluster does to a resource
+type Change struct {
+	Resource string `json:"resource"`
+	Action   Action `json:"action"`
+	Detail   string `json:"detail"`
+}
+
+// Result is the spec of an imported cluster
+type Result struct {
+	Cluster        *kops.Cluster
+	InstanceGroups []*kops.InstanceGroup
+	// Changes are the changes kOps makes to the cloud resources on the first update of the cluster
+	Changes []*Change
+}
+
+// ToYAML renders the cluster and its instance groups, starting with the changes as comments
+func (r *Result) ToYAML() ([]byte, error) {
+	var b bytes.Buffer
+	for _, change := range r.Changes {
+		fmt.Fprintf(&b, "# %s %s: %s\n", change.Action, change.Resource, change.Detail)
+	}
+	if b.Len() != 0 {
+		b.WriteString("\n")
+	}
+
+	objects := []runtime.Object{r.Cluster}
+	for _, ig := range r.InstanceGroups {
+		objects = append(objects, ig)
+	}
+	for i, obj := range objects {
+		y, err := kopscodecs.ToVersionedYaml(obj)
+		if err != nil {
+			return nil, fmt.Errorf("error serializing %T: %v", obj, err)
+		}
+		if i != 0 {
+			b.WriteString("\n---\n\n")
+		}
+		b.Write(y)
+	}
+	return b.Bytes(), nil
+}
+
+func (r *Result) add(resource string, action Action, format string, args ...interface{}) {
+	r.Changes = append(r.Changes, &Change{
+		Resource: resource,
+		Action:   action,
+		Detail:   fmt.Sprintf(format, args...),
+	})
+}
+
+// instanceGroupBuilder collects the instances of an instance group
+type instanceGroupBuilder struct {
+	ig        *kops.InstanceGroup
+	group     *autoscaling.Group
+	instances []*ec2.Instance
+}
+
+// Build converts the inventory of a cluster into a kOps cluster spec
+func Build(clusterName string, source Source, inventory *Inventory) (*Result, error) {
+	if _, err := clusterFilter(source, clusterName); err != nil {
+		return nil, err
+	}
+
+	r := &Result{}
+
+	name := clusterName
+	if !strings.Contains(name, ".") {
+		name += ".k8s.local"
+		r.add("cluster", ActionManual, "kOps cluster names are DNS names; the cluster is named %s, which uses gossip DNS", name)
+	}
+
+	cluster := &kops.Cluster{}
+	cluster.ObjectMeta.Name = name
+	cluster.Spec.CloudProvider = string(kops.CloudProviderAWS)
+	cluster.Spec.Channel = kops.DefaultChannel
+	cluster.Spec.KubernetesAPIAccess = []string{"0.0.0.0/0"}
+	cluster.Spec.SSHAccess = []string{"0.0.0.0/0"}
+	cluster.Spec.NetworkID = aws.StringValue(inventory.VPC.VpcId)
+	cluster.Spec.NetworkCIDR = aws.StringValue(inventory.VPC.CidrBlock)
+	r.Cluster = cluster
+
+	r.add("cluster", ActionManual, "the Kubernetes version could not be discovered; set spec.kubernetesVersion, or kOps installs the version recommended by the %s channel", cluster.Spec.Channel)
+
+	subnetNames := buildSubnets(r, inventory)
+
+	switch source {
+	case SourceEksctl:
+		cluster.Spec.Networking = &kops.NetworkingSpec{AmazonVPC: &kops.AmazonVPCNetworkingSpec{}}
+		r.add("cluster", ActionKeep, "the nodes keep using the Amazon VPC CNI")
+	default:
+		cluster.Spec.Networking = &kops.NetworkingSpec{CNI: &kops.CNINetworkingSpec{}}
+		r.add("cluster", ActionManual, "the CNI could not be discovered; spec.networking leaves the installed CNI alone, set it to let kOps manage the CNI")
+	}
+
+	buildInstanceGroups(r, clusterName, source, inventory, subnetNames)
+
+	var masters []*kops.InstanceGroup
+	for _, ig := range r.InstanceGroups {
+		if ig.Spec.Role == kops.InstanceGroupRoleMaster {
+			masters = append(masters, ig)
+		}
+	}
+	if len(masters) == 0 {
+		ig := &kops.InstanceGroup{}
+		ig.Spec.Role = kops.InstanceGroupRoleMaster
+		ig.Spec.MinSize = fi.Int32(1)
+		ig.Spec.MaxSize = fi.Int32(1)
+		for _, subnet := range cluster.Spec.Subnets {
+			if subnet.Type != kops.SubnetTypeUtility {
+				ig.Spec.Subnets = []string{subnet.Name}
+				break
+			}
+		}
+		if len(ig.Spec.Subnets) == 0 {
+			ig.Spec.Subnets = []string{cluster.Spec.Subnets[0].Name}
+		}
+		ig.ObjectMeta.Name = "master-" + ig.Spec.Subnets[0]
+		r.InstanceGroups = append(r.InstanceGroups, ig)
+		masters = append(masters, ig)
+		r.add("instancegroup/"+ig.ObjectMeta.Name, ActionCreate, "no control plane instances were found; kOps creates a new control plane")
+	}
+
+	buildEtcdClusters(r, source, inventory, masters)
+
+	if !dns.IsGossipHostname(name) {
+		r.add("dns/api."+name, ActionCreate, "kOps creates the API records in the hosted zone of %s", name)
+	}
+
+	sort.Slice(r.InstanceGroups, func(i, j int) bool {
+		return r.InstanceGroups[i].ObjectMeta.Name < r.InstanceGroups[j].ObjectMeta.Name
+	})
+
+	return r, nil
+}
+
+// buildSubnets adds the subnets of the instances to the cluster, returning the names of the subnets by ID
+func buildSubnets(r *Result, inventory *Inventory) map[string]string {
+	cluster := r.Cluster
+
+	subnets := append([]*ec2.Subnet(nil), inventory.Subnets...)
+	sort.Slice(subnets, func(i, j int) bool {
+		if aws.StringValue(subnets[i].AvailabilityZone) != aws.StringValue(subnets[j].AvailabilityZone) {
+			return aws.StringValue(subnets[i].AvailabilityZone) < aws.StringValue(subnets[j].AvailabilityZone)
+		}
+		return aws.StringValue(subnets[i].SubnetId) < aws.StringValue(subnets[j].SubnetId)
+	})
+
+	private := false
+	for _, subnet := range subnets {
+		if !aws.BoolValue(subnet.MapPublicIpOnLaunch) {
+			private = true
+		}
+	}
+
+	names := make(map[string]string)
+	used := make(map[string]bool)
+	hasUtility := false
+	for _, subnet := range subnets {
+		zone := aws.StringValue(subnet.AvailabilityZone)
+		spec := kops.ClusterSubnetSpec{
+			Zone:       zone,
+			CIDR:       aws.StringValue(subnet.CidrBlock),
+			ProviderID: aws.StringValue(subnet.SubnetId),
+		}
+		switch {
+		case !private:
+			spec.Type = kops.SubnetTypePublic
+			spec.Name = zone
+		case aws.BoolValue(subnet.MapPublicIpOnLaunch):
+			spec.Type = kops.SubnetTypeUtility
+			spec.Name = "utility-" + zone
+			hasUtility = true
+		default:
+			spec.Type = kops.SubnetTypePrivate
+			spec.Name = zone
+			spec.Egress = kops.EgressExternal
+		}
+		spec.Name = uniqueName(used, spec.Name)
+
+		names[spec.ProviderID] = spec.Name
+		cluster.Spec.Subnets = append(cluster.Spec.Subnets, spec)
+	}
+
+	topology := kops.TopologyPublic
+	if private {
+		topology = kops.TopologyPrivate
+	}
+	cluster.Spec.Topology = &kops.TopologySpec{
+		Masters: topology,
+		Nodes:   topology,
+		DNS: &kops.DNSSpec{
+			Type: kops.DNSTypePublic,
+		},
+	}
+
+	r.add("vpc/"+cluster.Spec.NetworkID, ActionUpdate, "the VPC and its subnets are shared with kOps, which tags them with %s%s=shared", tagKubernetesClusterPrefix, cluster.ObjectMeta.Name)
+	if private {
+		r.add("vpc/"+cluster.Spec.NetworkID, ActionKeep, "the private subnets use external egress, so kOps leaves their routes alone")
+		if !hasUtility {
+			r.add("vpc/"+cluster.Spec.NetworkID, ActionManual, "no public subnets were found; add utility subnets for the API load balancer")
+		}
+	}
+
+	return names
+}
+
+// uniqueName returns name, or name with a numeric suffix if it is already used
+func uniqueName(used map[string]bool, name string) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s-%d", name, i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// instanceRole returns the role of an instance in the cluster
+func instanceRole(source Source, instance *ec2.Instance) kops.InstanceGroupRole {
+	hasTag := func(key string) bool {
+		_, ok := awsup.FindEC2Tag(instance.Tags, key)
+		return ok
+	}
+
+	switch source {
+	case SourceKops:
+		switch {
+		case hasTag(awsup.TagNameRolePrefix + "master"):
+			return kops.InstanceGroupRoleMaster
+		case hasTag(awsup.TagNameRolePrefix + "apiserver"):
+			return kops.InstanceGroupRoleAPIServer
+		case hasTag(awsup.TagNameRolePrefix + "bastion"):
+			return kops.InstanceGroupRoleBastion
+		}
+	case SourceKubeadm:
+		if hasTag("node-role.kubernetes.io/control-plane") || hasTag("node-role.kubernetes.io/master") || hasTag(awsup.TagNameRolePrefix+"master") {
+			return kops.InstanceGroupRoleMaster
+		}
+		name, _ := awsup.FindEC2Tag(instance.Tags, tagName)
+		switch {
+		case strings.Contains(name, "control-plane") || strings.Contains(name, "master"):
+			return kops.InstanceGroupRoleMaster
+		case strings.Contains(name, "bastion"):
+			return kops.InstanceGroupRoleBastion
+		}
+	}
+	return kops.InstanceGroupRoleNode
+}
+
+// instanceGroupName returns the name of the instance group of an instance, as recorded by the source
+func instanceGroupName(source Source, instance *ec2.Instance, clusterName string) string {
+	switch source {
+	case SourceKops:
+		if name, ok := awsup.FindEC2Tag(instance.Tags, nodeidentityaws.CloudTagInstanceGroupName); ok {
+			return name
+		}
+		if name, ok := awsup.FindEC2Tag(instance.Tags, tagAutoscalingGroupName); ok {
+			name = strings.TrimSuffix(name, "."+clusterName)
+			name = strings.TrimSuffix(name, ".masters")
+			return strings.TrimSuffix(name, ".apiservers")
+		}
+	case SourceEksctl:
+		if name, ok := awsup.FindEC2Tag(instance.Tags, tagEksctlNodeGroupName); ok {
+			return name
+		}
+		if name, ok := awsup.FindEC2Tag(instance.Tags, tagEKSNodeGroupName); ok {
+			return name
+		}
+	}
+	if name, ok := awsup.FindEC2Tag(instance.Tags, tagAutoscalingGroupName); ok {
+		return name
+	}
+	return ""
+}
+
+func buildInstanceGroups(r *Result, clusterName string, source Source, inventory *Inventory, subnetNames map[string]string) {
+	cluster := r.Cluster
+
+	groups := make(map[string]*autoscaling.Group)
+	for _, group := range inventory.AutoscalingGroups {
+		groups[aws.StringValue(group.AutoScalingGroupName)] = group
+	}
+
+	instances := append([]*ec2.Instance(nil), inventory.Instances...)
+	sort.Slice(instances, func(i, j int) bool {
+		return aws.StringValue(instances[i].InstanceId) < aws.StringValue(instances[j].InstanceId)
+	})
+
+	var builders []*instanceGroupBuilder
+	byKey := make(map[string]*instanceGroupBuilder)
+	for _, instance := range instances {
+		role := instanceRole(source, instance)
+		groupName, _ := awsup.FindEC2Tag(instance.Tags, tagAutoscalingGroupName)
+
+		// Instances outside of autoscaling groups are grouped by role, and by zone for the control plane
+		key := groupName
+		if key == "" {
+			key = "/" + string(role)
+			if role == kops.InstanceGroupRoleMaster {
+				key += "/" + subnetNames[aws.StringValue(instance.SubnetId)]
+			}
+		}
+
+		b := byKey[key]
+		if b == nil {
+			b = &instanceGroupBuilder{
+				ig:    &kops.InstanceGroup{},
+				group: groups[groupName],
+			}
+			b.ig.Spec.Role = role
+			b.ig.ObjectMeta.Name = instanceGroupName(source, instance, clusterName)
+			if b.ig.ObjectMeta.Name == "" {
+				switch role {
+				case kops.InstanceGroupRoleMaster:
+					b.ig.ObjectMeta.Name = "master-" + subnetNames[aws.StringValue(instance.SubnetId)]
+				case kops.InstanceGroupRoleBastion:
+					b.ig.ObjectMeta.Name = "bastions"
+				default:
+					b.ig.ObjectMeta.Name = "nodes"
+				}
+			}
+			byKey[key] = b
+			builders = append(builders, b)
+		}
+		b.instances = append(b.instances, instance)
+	}
+
+	used := make(map[string]bool)
+	keyNames := make(map[string]bool)
+	roles := make(map[kops.InstanceGroupRole][]*ec2.Instance)
+	for _, b := range builders {
+		ig := b.ig
+		ig.ObjectMeta.Name = uniqueName(used, ig.ObjectMeta.Name)
+
+		ig.Spec.MachineType = mostCommon(b.instances, func(instance *ec2.Instance) string {
+			return aws.StringValue(instance.InstanceType)
+		})
+		ig.Spec.Image = mostCommon(b.instances, func(instance *ec2.Instance) string {
+			return aws.StringValue(instance.ImageId)
+		})
+		for _, instance := range b.instances {
+			keyNames[aws.StringValue(instance.KeyName)] = true
+		}
+		roles[ig.Spec.Role] = append(roles[ig.Spec.Role], b.instances...)
+
+		subnets := make(map[string]bool)
+		if b.group != nil {
+			ig.Spec.MinSize = fi.Int32(int32(aws.Int64Value(b.group.MinSize)))
+			ig.Spec.MaxSize = fi.Int32(int32(aws.Int64Value(b.group.MaxSize)))
+			for _, id := range strings.Split(aws.StringValue(b.group.VPCZoneIdentifier), ",") {
+				if subnetNames[id] != "" {
+					subnets[subnetNames[id]] = true
+				}
+			}
+			if policy := b.group.MixedInstancesPolicy; policy != nil && policy.LaunchTemplate != nil && len(policy.LaunchTemplate.Overrides) > 1 {
+				ig.Spec.MixedInstancesPolicy = &kops.MixedInstancesPolicySpec{}
+				for _, override := range policy.LaunchTemplate.Overrides {
+					ig.Spec.MixedInstancesPolicy.Instances = append(ig.Spec.MixedInstancesPolicy.Instances, aws.StringValue(override.InstanceType))
+				}
+			}
+		} else {
+			ig.Spec.MinSize = fi.Int32(int32(len(b.instances)))
+			ig.Spec.MaxSize = fi.Int32(int32(len(b.instances)))
+		}
+		for _, instance := range b.instances {
+			subnets[subnetNames[aws.StringValue(instance.SubnetId)]] = true
+		}
+		ig.Spec.Subnets = sortedKeys(subnets)
+
+		r.InstanceGroups = append(r.InstanceGroups, ig)
+	}
+
+	modelContext := &model.KopsModelContext{
+		IAMModelContext: iam.IAMModelContext{Cluster: cluster},
+	}
+	for _, b := range builders {
+		resource := "instancegroup/" + b.ig.ObjectMeta.Name
+		expected := modelContext.AutoscalingGroupName(b.ig)
+		if b.group == nil {
+			var ids []string
+			for _, instance := range b.instances {
+				ids = append(ids, aws.StringValue(instance.InstanceId))
+			}
+			r.add(resource, ActionReplace, "the instances %s are not in an autoscaling group; kOps creates the autoscaling group %s, terminate the instances by hand once it is ready", strings.Join(ids, ", "), expected)
+			continue
+		}
+		name := aws.StringValue(b.group.AutoScalingGroupName)
+		if name != expected {
+			r.add(resource, ActionReplace, "kOps creates the autoscaling group %s; drain the nodes of %s and delete it by hand once it is ready", expected, name)
+			continue
+		}
+		if b.group.LaunchConfigurationName != nil {
+			r.add(resource, ActionUpdate, "the autoscaling group %s is adopted; its launch configuration is replaced by a launch template, and a rolling update replaces the instances", name)
+			continue
+		}
+		r.add(resource, ActionUpdate, "the autoscaling group %s is adopted; kOps creates a new version of its launch template, and a rolling update replaces the instances", name)
+	}
+
+	var roleNames []string
+	for role := range roles {
+		roleNames = append(roleNames, string(role))
+	}
+	sort.Strings(roleNames)
+	for _, roleName := range roleNames {
+		role := kops.InstanceGroupRole(roleName)
+		securityGroup := modelContext.SecurityGroupName(role)
+		instanceProfile := modelContext.IAMName(role)
+
+		hasSecurityGroup, hasInstanceProfile := false, false
+		for _, instance := range roles[role] {
+			for _, group := range instance.SecurityGroups {
+				if aws.StringValue(group.GroupName) == securityGroup {
+					hasSecurityGroup = true
+				}
+			}
+			if profile := instance.IamInstanceProfile; profile != nil && strings.HasSuffix(aws.StringValue(profile.Arn), "/"+instanceProfile) {
+				hasInstanceProfile = true
+			}
+		}
+		if hasSecurityGroup {
+			r.add("securitygroup/"+securityGroup, ActionUpdate, "the security group is adopted; kOps replaces its rules")
+		} else {
+			r.add("securitygroup/"+securityGroup, ActionCreate, "the %s instances move to a new security group; check that it allows the traffic of the existing one", strings.ToLower(roleName))
+		}
+		if hasInstanceProfile {
+			r.add("instanceprofile/"+instanceProfile, ActionUpdate, "the instance profile is adopted; kOps replaces the policy of its role")
+		} else {
+			r.add("instanceprofile/"+instanceProfile, ActionCreate, "the %s instances get a new instance profile; add the permissions of the existing one to spec.additionalPolicies", strings.ToLower(roleName))
+		}
+	}
+
+	delete(keyNames, "")
+	switch len(keyNames) {
+	case 0:
+	case 1:
+		for keyName := range keyNames {
+			cluster.Spec.SSHKeyName = fi.String(keyName)
+		}
+		r.add("keypair/"+fi.StringValue(cluster.Spec.SSHKeyName), ActionKeep, "the instances keep using the existing key pair")
+	default:
+		r.add("cluster", ActionManual, "the instances use several key pairs (%s); set spec.sshKeyName to the one to keep", strings.Join(sortedKeys(keyNames), ", "))
+	}
+}
+
+// mostCommon returns the most common value of the instances, preferring the first in alphabetical order
+func mostCommon(instances []*ec2.Instance, value func(*ec2.Instance) string) string {
+	counts := make(map[string]int)
+	for _, instance := range instances {
+		counts[value(instance)]++
+	}
+	best := ""
+	for v, count := range counts {
+		if count > counts[best] || (count == counts[best] && v < best) {
+			best = v
+		}
+	}
+	return best
+}
+
+func buildEtcdClusters(r *Result, source Source, inventory *Inventory, masters []*kops.InstanceGroup) {
+	cluster := r.Cluster
+
+	zoneOf := make(map[string]string)
+	for _, subnet := range cluster.Spec.Subnets {
+		zoneOf[subnet.Name] = subnet.Zone
+	}
+
+	// The etcd volumes of a kOps cluster are tagged with the etcd cluster and the name of the member
+	members := make(map[string][]kops.EtcdMemberSpec)
+	for _, volume := range inventory.Volumes {
+		for _, tag := range volume.Tags {
+			key := aws.StringValue(tag.Key)
+			if !strings.HasPrefix(key, awsup.TagNameEtcdClusterPrefix) {
+				continue
+			}
+			etcdCluster := strings.TrimPrefix(key, awsup.TagNameEtcdClusterPrefix)
+			member := strings.SplitN(aws.StringValue(tag.Value), "/", 2)[0]
+
+			spec := kops.EtcdMemberSpec{
+				Name:            member,
+				EncryptedVolume: volume.Encrypted,
+				VolumeType:      volume.VolumeType,
+				VolumeSize:      fi.Int32(int32(aws.Int64Value(volume.Size))),
+			}
+			for _, ig := range masters {
+				for _, subnet := range ig.Spec.Subnets {
+					if spec.InstanceGroup == nil && zoneOf[subnet] == aws.StringValue(volume.AvailabilityZone) {
+						spec.InstanceGroup = fi.String(ig.ObjectMeta.Name)
+					}
+				}
+			}
+			if spec.InstanceGroup == nil {
+				r.add("volume/"+aws.StringValue(volume.VolumeId), ActionManual, "no control plane instance group was found in %s for member %s of etcd cluster %s", aws.StringValue(volume.AvailabilityZone), member, etcdCluster)
+				continue
+			}
+			members[etcdCluster] = append(members[etcdCluster], spec)
+		}
+	}
+
+	if len(members) != 0 {
+		var names []string
+		for name := range members {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			etcd := kops.EtcdClusterSpec{Name: name, Members: members[name]}
+			sort.Slice(etcd.Members, func(i, j int) bool { return etcd.Members[i].Name < etcd.Members[j].Name })
+			cluster.Spec.EtcdClusters = append(cluster.Spec.EtcdClusters, etcd)
+		}
+		r.add("etcd", ActionKeep, "the etcd volumes of the %s clusters are adopted", strings.Join(names, " and "))
+		return
+	}
+
+	// The members are named like the ones of kops create cluster: the instance group names without their common prefix
+	var memberNames []string
+	for _, ig := range masters {
+		memberNames = append(memberNames, strings.TrimPrefix(ig.ObjectMeta.Name, "master-"))
+	}
+	for len(memberNames) != 0 && len(memberNames[0]) > 1 {
+		prefix := memberNames[0][:1]
+		for _, name := range memberNames {
+			if !strings.HasPrefix(name, prefix) {
+				prefix = ""
+			}
+		}
+		if prefix == "" {
+			break
+		}
+		for i := range memberNames {
+			memberNames[i] = strings.TrimPrefix(memberNames[i], prefix)
+		}
+	}
+
+	for _, name := range []string{"main", "events"} {
+		etcd := kops.EtcdClusterSpec{Name: name}
+		for i, ig := range masters {
+			etcd.Members = append(etcd.Members, kops.EtcdMemberSpec{
+				Name:            memberNames[i],
+				InstanceGroup:   fi.String(ig.ObjectMeta.Name),
+				EncryptedVolume: fi.Bool(true),
+			})
+		}
+		cluster.Spec.EtcdClusters = append(cluster.Spec.EtcdClusters, etcd)
+	}
+	switch source {
+	case SourceEksctl:
+		r.add("etcd", ActionCreate, "EKS does not expose etcd; kOps creates empty etcd clusters, so the workloads have to be deployed again")
+	default:
+		r.add("etcd", ActionCreate, "kOps creates empty etcd volumes, which are not the etcd data of the cluster; restore an etcd backup with etcd-manager before moving the workloads")
+	}
+}
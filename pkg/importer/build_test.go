@@ -0,0 +1,240 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/testutils/golden"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func tags(kv ...string) []*ec2.Tag {
+	var tags []*ec2.Tag
+	for i := 0; i < len(kv); i += 2 {
+		tags = append(tags, &ec2.Tag{Key: aws.String(kv[i]), Value: aws.String(kv[i+1])})
+	}
+	return tags
+}
+
+func instance(id, instanceType, subnet string, tags []*ec2.Tag) *ec2.Instance {
+	return &ec2.Instance{
+		InstanceId:   aws.String(id),
+		InstanceType: aws.String(instanceType),
+		ImageId:      aws.String("ami-12345678"),
+		KeyName:      aws.String("admin"),
+		SubnetId:     aws.String(subnet),
+		VpcId:        aws.String("vpc-12345678"),
+		Tags:         tags,
+	}
+}
+
+func buildInventory(instances []*ec2.Instance, groups []*autoscaling.Group) *Inventory {
+	return &Inventory{
+		Region: "us-test-1",
+		VPC: &ec2.Vpc{
+			VpcId:     aws.String("vpc-12345678"),
+			CidrBlock: aws.String("172.20.0.0/16"),
+		},
+		Subnets: []*ec2.Subnet{
+			{
+				SubnetId:         aws.String("subnet-a"),
+				AvailabilityZone: aws.String("us-test-1a"),
+				CidrBlock:        aws.String("172.20.32.0/19"),
+			},
+			{
+				SubnetId:         aws.String("subnet-b"),
+				AvailabilityZone: aws.String("us-test-1b"),
+				CidrBlock:        aws.String("172.20.64.0/19"),
+			},
+			{
+				SubnetId:            aws.String("subnet-utility-a"),
+				AvailabilityZone:    aws.String("us-test-1a"),
+				CidrBlock:           aws.String("172.20.0.0/22"),
+				MapPublicIpOnLaunch: aws.Bool(true),
+			},
+		},
+		Instances:         instances,
+		AutoscalingGroups: groups,
+	}
+}
+
+func TestBuildKubeadm(t *testing.T) {
+	inventory := buildInventory(
+		[]*ec2.Instance{
+			instance("i-control-plane-a", "m5.large", "subnet-a", tags("Name", "k8s-control-plane-1", "kubernetes.io/cluster/kubeadm.example.com", "owned")),
+			instance("i-control-plane-b", "m5.large", "subnet-b", tags("Name", "k8s-control-plane-2", "kubernetes.io/cluster/kubeadm.example.com", "owned")),
+			instance("i-worker-1", "t3.large", "subnet-a", tags("aws:autoscaling:groupName", "k8s-workers", "kubernetes.io/cluster/kubeadm.example.com", "owned")),
+			instance("i-worker-2", "t3.large", "subnet-b", tags("aws:autoscaling:groupName", "k8s-workers", "kubernetes.io/cluster/kubeadm.example.com", "owned")),
+		},
+		[]*autoscaling.Group{
+			{
+				AutoScalingGroupName:    aws.String("k8s-workers"),
+				LaunchConfigurationName: aws.String("k8s-workers-20210801"),
+				MinSize:                 aws.Int64(2),
+				MaxSize:                 aws.Int64(5),
+				VPCZoneIdentifier:       aws.String("subnet-a,subnet-b"),
+			},
+		},
+	)
+
+	result, err := Build("kubeadm.example.com", SourceKubeadm, inventory)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actual, err := result.ToYAML()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	golden.AssertMatchesFile(t, string(actual), "tests/kubeadm.yaml")
+}
+
+func TestBuildEksctl(t *testing.T) {
+	inventory := buildInventory(
+		[]*ec2.Instance{
+			instance("i-1", "m5.xlarge", "subnet-a", tags("alpha.eksctl.io/cluster-name", "eks", "alpha.eksctl.io/nodegroup-name", "ng-1", "aws:autoscaling:groupName", "eks-ng-1-12345")),
+			instance("i-2", "m5.xlarge", "subnet-b", tags("alpha.eksctl.io/cluster-name", "eks", "alpha.eksctl.io/nodegroup-name", "ng-1", "aws:autoscaling:groupName", "eks-ng-1-12345")),
+		},
+		[]*autoscaling.Group{
+			{
+				AutoScalingGroupName: aws.String("eks-ng-1-12345"),
+				MinSize:              aws.Int64(1),
+				MaxSize:              aws.Int64(3),
+				VPCZoneIdentifier:    aws.String("subnet-a,subnet-b"),
+			},
+		},
+	)
+
+	result, err := Build("eks", SourceEksctl, inventory)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actual, err := result.ToYAML()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	golden.AssertMatchesFile(t, string(actual), "tests/eksctl.yaml")
+}
+
+func TestBuildKops(t *testing.T) {
+	clusterTags := func(kv ...string) []*ec2.Tag {
+		return tags(append([]string{"KubernetesCluster", "kops.example.com"}, kv...)...)
+	}
+	inventory := buildInventory(
+		[]*ec2.Instance{
+			instance("i-master", "c5.large", "subnet-a", clusterTags("k8s.io/role/master", "1", "kops.k8s.io/instancegroup", "master-us-test-1a", "aws:autoscaling:groupName", "master-us-test-1a.masters.kops.example.com")),
+			instance("i-node", "t3.medium", "subnet-b", clusterTags("k8s.io/role/node", "1", "kops.k8s.io/instancegroup", "nodes", "aws:autoscaling:groupName", "nodes.kops.example.com")),
+		},
+		[]*autoscaling.Group{
+			{AutoScalingGroupName: aws.String("master-us-test-1a.masters.kops.example.com"), MinSize: aws.Int64(1), MaxSize: aws.Int64(1)},
+			{AutoScalingGroupName: aws.String("nodes.kops.example.com"), MinSize: aws.Int64(1), MaxSize: aws.Int64(2)},
+		},
+	)
+	inventory.Instances[0].SecurityGroups = []*ec2.GroupIdentifier{{GroupName: aws.String("masters.kops.example.com")}}
+	inventory.Volumes = []*ec2.Volume{
+		{
+			VolumeId:         aws.String("vol-main"),
+			AvailabilityZone: aws.String("us-test-1a"),
+			Size:             aws.Int64(20),
+			VolumeType:       aws.String("gp3"),
+			Encrypted:        aws.Bool(true),
+			Tags:             clusterTags("k8s.io/etcd/main", "a/a"),
+		},
+		{
+			VolumeId:         aws.String("vol-events"),
+			AvailabilityZone: aws.String("us-test-1a"),
+			Size:             aws.Int64(20),
+			VolumeType:       aws.String("gp3"),
+			Encrypted:        aws.Bool(true),
+			Tags:             clusterTags("k8s.io/etcd/events", "a/a"),
+		},
+	}
+
+	result, err := Build("kops.example.com", SourceKops, inventory)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var names []string
+	for _, ig := range result.InstanceGroups {
+		names = append(names, ig.ObjectMeta.Name)
+	}
+	if len(names) != 2 || names[0] != "master-us-test-1a" || names[1] != "nodes" {
+		t.Errorf("unexpected instance groups %v", names)
+	}
+
+	etcdClusters := result.Cluster.Spec.EtcdClusters
+	if len(etcdClusters) != 2 || etcdClusters[0].Name != "events" || etcdClusters[1].Name != "main" {
+		t.Fatalf("unexpected etcd clusters %v", etcdClusters)
+	}
+	member := etcdClusters[1].Members[0]
+	if member.Name != "a" || fi.StringValue(member.InstanceGroup) != "master-us-test-1a" || fi.StringValue(member.VolumeType) != "gp3" {
+		t.Errorf("unexpected etcd member %v", member)
+	}
+
+	actions := make(map[string]Action)
+	for _, change := range result.Changes {
+		actions[change.Resource] = change.Action
+	}
+	expected := map[string]Action{
+		"instancegroup/master-us-test-1a":        ActionUpdate,
+		"instancegroup/nodes":                    ActionUpdate,
+		"securitygroup/masters.kops.example.com": ActionUpdate,
+		"securitygroup/nodes.kops.example.com":   ActionCreate,
+		"etcd":                                   ActionKeep,
+	}
+	for resource, action := range expected {
+		if actions[resource] != action {
+			t.Errorf("expected %s to be %s, got %q", resource, action, actions[resource])
+		}
+	}
+}
+
+func TestBuildUnsupportedSource(t *testing.T) {
+	inventory := buildInventory([]*ec2.Instance{instance("i-1", "t3.medium", "subnet-a", nil)}, nil)
+	if _, err := Build("example.com", Source("kube-up"), inventory); err == nil {
+		t.Errorf("expected an error for an unsupported source")
+	}
+}
+
+func TestInstanceRole(t *testing.T) {
+	grid := []struct {
+		source   Source
+		tags     []*ec2.Tag
+		expected kops.InstanceGroupRole
+	}{
+		{SourceKops, tags("k8s.io/role/master", "1"), kops.InstanceGroupRoleMaster},
+		{SourceKops, tags("k8s.io/role/bastion", "1"), kops.InstanceGroupRoleBastion},
+		{SourceKops, tags("k8s.io/role/node", "1"), kops.InstanceGroupRoleNode},
+		{SourceKubeadm, tags("node-role.kubernetes.io/control-plane", ""), kops.InstanceGroupRoleMaster},
+		{SourceKubeadm, tags("Name", "prod-master-0"), kops.InstanceGroupRoleMaster},
+		{SourceKubeadm, tags("Name", "prod-worker-0"), kops.InstanceGroupRoleNode},
+		{SourceEksctl, tags("Name", "eks-master"), kops.InstanceGroupRoleNode},
+	}
+	for _, g := range grid {
+		actual := instanceRole(g.source, &ec2.Instance{Tags: g.tags})
+		if actual != g.expected {
+			t.Errorf("%s %v: expected %s, got %s", g.source, g.tags, g.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// Source is the tool which created the cluster being imported
+type Source string
+
+const (
+	// SourceKops imports the cloud resources of a kOps cluster, for example when its state store was lost
+	SourceKops Source = "kops"
+	// SourceEksctl imports the node groups of a cluster created by eksctl
+	SourceEksctl Source = "eksctl"
+	// SourceKubeadm imports the instances of a cluster created with kubeadm
+	SourceKubeadm Source = "kubeadm"
+)
+
+// SupportedSources are the tools whose clusters can be imported
+var SupportedSources = []Source{SourceKops, SourceEksctl, SourceKubeadm}
+
+const (
+	tagEksctlClusterName       = "alpha.eksctl.io/cluster-name"
+	tagEksctlNodeGroupName     = "alpha.eksctl.io/nodegroup-name"
+	tagEKSNodeGroupName        = "eks:nodegroup-name"
+	tagAutoscalingGroupName    = "aws:autoscaling:groupName"
+	tagKubernetesClusterPrefix = "kubernetes.io/cluster/"
+	tagName                    = "Name"
+)
+
+// Inventory holds the cloud resources of the cluster being imported
+type Inventory struct {
+	Region            string
+	VPC               *ec2.Vpc
+	Subnets           []*ec2.Subnet
+	Instances         []*ec2.Instance
+	AutoscalingGroups []*autoscaling.Group
+	// Volumes are the etcd volumes of a kOps cluster
+	Volumes []*ec2.Volume
+}
+
+// clusterFilter returns the filter matching the resources the source tags for the cluster
+func clusterFilter(source Source, clusterName string) (*ec2.Filter, error) {
+	switch source {
+	case SourceKops:
+		return awsup.NewEC2Filter("tag:"+awsup.TagClusterName, clusterName), nil
+	case SourceEksctl:
+		return awsup.NewEC2Filter("tag:"+tagEksctlClusterName, clusterName), nil
+	case SourceKubeadm:
+		return awsup.NewEC2Filter("tag-key", tagKubernetesClusterPrefix+clusterName), nil
+	default:
+		return nil, fmt.Errorf("unsupported source %q", source)
+	}
+}
+
+// Discover finds the cloud resources of a cluster
+func Discover(cloud awsup.AWSCloud, clusterName string, source Source) (*Inventory, error) {
+	filter, err := clusterFilter(source, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	inventory := &Inventory{
+		Region: cloud.Region(),
+	}
+
+	request := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			filter,
+			awsup.NewEC2Filter("instance-state-name", "pending", "running", "stopping", "stopped"),
+		},
+	}
+	err = cloud.EC2().DescribeInstancesPages(request, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, reservation := range page.Reservations {
+			inventory.Instances = append(inventory.Instances, reservation.Instances...)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing instances: %v", err)
+	}
+	if len(inventory.Instances) == 0 {
+		return nil, fmt.Errorf("no instances found for %s cluster %q", source, clusterName)
+	}
+
+	groupNames := make(map[string]bool)
+	subnetIDs := make(map[string]bool)
+	vpcIDs := make(map[string]bool)
+	for _, instance := range inventory.Instances {
+		if name, ok := awsup.FindEC2Tag(instance.Tags, tagAutoscalingGroupName); ok {
+			groupNames[name] = true
+		}
+		if instance.SubnetId != nil {
+			subnetIDs[aws.StringValue(instance.SubnetId)] = true
+		}
+		if instance.VpcId != nil {
+			vpcIDs[aws.StringValue(instance.VpcId)] = true
+		}
+	}
+
+	if len(groupNames) != 0 {
+		request := &autoscaling.DescribeAutoScalingGroupsInput{
+			AutoScalingGroupNames: aws.StringSlice(sortedKeys(groupNames)),
+		}
+		err := cloud.Autoscaling().DescribeAutoScalingGroupsPages(request, func(page *autoscaling.DescribeAutoScalingGroupsOutput, lastPage bool) bool {
+			inventory.AutoscalingGroups = append(inventory.AutoscalingGroups, page.AutoScalingGroups...)
+			return true
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error listing autoscaling groups: %v", err)
+		}
+	}
+	for _, group := range inventory.AutoscalingGroups {
+		for _, id := range strings.Split(aws.StringValue(group.VPCZoneIdentifier), ",") {
+			if id != "" {
+				subnetIDs[id] = true
+			}
+		}
+	}
+
+	if len(vpcIDs) != 1 {
+		return nil, fmt.Errorf("expected the instances to be in a single VPC, found %v", sortedKeys(vpcIDs))
+	}
+	for id := range vpcIDs {
+		inventory.VPC, err = cloud.DescribeVPC(id)
+		if err != nil {
+			return nil, err
+		}
+		if inventory.VPC == nil {
+			return nil, fmt.Errorf("cannot find vpc %q", id)
+		}
+	}
+
+	subnets, err := cloud.EC2().DescribeSubnets(&ec2.DescribeSubnetsInput{
+		SubnetIds: aws.StringSlice(sortedKeys(subnetIDs)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing subnets: %v", err)
+	}
+	inventory.Subnets = subnets.Subnets
+
+	if source == SourceKops {
+		request := &ec2.DescribeVolumesInput{
+			Filters: []*ec2.Filter{filter},
+		}
+		err := cloud.EC2().DescribeVolumesPages(request, func(page *ec2.DescribeVolumesOutput, lastPage bool) bool {
+			for _, volume := range page.Volumes {
+				for _, tag := range volume.Tags {
+					if strings.HasPrefix(aws.StringValue(tag.Key), awsup.TagNameEtcdClusterPrefix) {
+						inventory.Volumes = append(inventory.Volumes, volume)
+						break
+					}
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error listing volumes: %v", err)
+		}
+	}
+
+	return inventory, nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+// drainReportInterval is how often a node which is still draining is checked for blocking PodDisruptionBudgets
+var drainReportInterval = time.Minute
+
+const (
+	// eventReasonDrainBlocked is the reason of the Event recorded when PodDisruptionBudgets prevent evicting pods from a node
+	eventReasonDrainBlocked = "DrainBlocked"
+	// eventReasonDrainFailed is the reason of the Event recorded when a node could not be drained
+	eventReasonDrainFailed = "DrainFailed"
+)
+
+// blockingPodDisruptionBudgets returns a description of each PodDisruptionBudget which currently does not allow
+// evicting any of the pods remaining on the node, in the form "namespace/name (pods: a, b)".
+func (c *RollingUpdateCluster) blockingPodDisruptionBudgets(nodeName string) ([]string, error) {
+	ctx := c.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	podList, err := c.K8sClient.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing pods on node %q: %v", nodeName, err)
+	}
+
+	podsByNamespace := make(map[string][]corev1.Pod)
+	for _, pod := range podList.Items {
+		if pod.Spec.NodeName != nodeName || !isEvictable(&pod) {
+			continue
+		}
+		podsByNamespace[pod.Namespace] = append(podsByNamespace[pod.Namespace], pod)
+	}
+
+	var blocking []string
+	for namespace, pods := range podsByNamespace {
+		pdbList, err := c.K8sClient.PolicyV1beta1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error listing PodDisruptionBudgets in namespace %q: %v", namespace, err)
+		}
+
+		for _, pdb := range pdbList.Items {
+			if pdb.Status.DisruptionsAllowed > 0 {
+				continue
+			}
+			// An empty selector selects no pods in policy/v1beta1
+			if pdb.Spec.Selector == nil || (len(pdb.Spec.Selector.MatchLabels) == 0 && len(pdb.Spec.Selector.MatchExpressions) == 0) {
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+			if err != nil {
+				klog.Warningf("ignoring PodDisruptionBudget %s/%s with invalid selector: %v", pdb.Namespace, pdb.Name, err)
+				continue
+			}
+
+			var matched []string
+			for _, pod := range pods {
+				if selector.Matches(labels.Set(pod.Labels)) {
+					matched = append(matched, pod.Name)
+				}
+			}
+			if len(matched) != 0 {
+				sort.Strings(matched)
+				blocking = append(blocking, fmt.Sprintf("%s/%s (pods: %s)", pdb.Namespace, pdb.Name, strings.Join(matched, ", ")))
+			}
+		}
+	}
+	sort.Strings(blocking)
+
+	return blocking, nil
+}
+
+// isEvictable returns whether draining evicts the pod; terminated, mirror and DaemonSet pods are left alone
+func isEvictable(pod *corev1.Pod) bool {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return false
+	}
+	if _, found := pod.Annotations[corev1.MirrorPodAnnotationKey]; found {
+		return false
+	}
+	for _, owner := range pod.OwnerReferences {
+		if owner.Controller != nil && *owner.Controller && owner.Kind == "DaemonSet" {
+			return false
+		}
+	}
+	return true
+}
+
+// watchDrain periodically reports the PodDisruptionBudgets blocking the drain of the node, until stopped
+func (c *RollingUpdateCluster) watchDrain(node *corev1.Node) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(drainReportInterval)
+		defer ticker.Stop()
+
+		reported := ""
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				blocking, err := c.blockingPodDisruptionBudgets(node.Name)
+				if err != nil {
+					klog.Warningf("unable to check why node %q is still draining: %v", node.Name, err)
+					continue
+				}
+				if len(blocking) == 0 {
+					continue
+				}
+
+				message := fmt.Sprintf("Eviction of pods is blocked by PodDisruptionBudgets %s", strings.Join(blocking, ", "))
+				klog.Warningf("Node %q is still draining: %s", node.Name, message)
+				if message != reported {
+					c.recordNodeEvent(node, corev1.EventTypeWarning, eventReasonDrainBlocked, message)
+					reported = message
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+	}
+}
+
+// recordNodeEvent records a Kubernetes Event about the node, so that the reason a rolling update is stuck is visible in the cluster
+func (c *RollingUpdateCluster) recordNodeEvent(node *corev1.Node, eventType, reason, message string) {
+	ctx := c.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	now := metav1.Now()
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: node.Name + ".",
+			Namespace:    metav1.NamespaceDefault,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: "v1",
+			Kind:       "Node",
+			Name:       node.Name,
+			UID:        node.UID,
+		},
+		Reason:         reason,
+		Message:        message,
+		Type:           eventType,
+		Source:         corev1.EventSource{Component: "kops-rolling-update"},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+	if _, err := c.K8sClient.CoreV1().Events(metav1.NamespaceDefault).Create(ctx, event, metav1.CreateOptions{}); err != nil {
+		klog.Warningf("unable to record event for node %q: %v", node.Name, err)
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestBlockingPodDisruptionBudgets(t *testing.T) {
+	isController := true
+	pod := func(name, nodeName string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "app", Labels: labels},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+	pdb := func(name string, selector map[string]string, disruptionsAllowed int32) *policyv1beta1.PodDisruptionBudget {
+		return &policyv1beta1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "app"},
+			Spec: policyv1beta1.PodDisruptionBudgetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: selector},
+			},
+			Status: policyv1beta1.PodDisruptionBudgetStatus{DisruptionsAllowed: disruptionsAllowed},
+		}
+	}
+
+	daemonSetPod := pod("agent", "node-a", map[string]string{"app": "db"})
+	daemonSetPod.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: "agent", Controller: &isController}}
+
+	k8sClient := fake.NewSimpleClientset(
+		pod("db-0", "node-a", map[string]string{"app": "db"}),
+		pod("db-1", "node-b", map[string]string{"app": "db"}),
+		pod("web-0", "node-a", map[string]string{"app": "web"}),
+		pod("cache-0", "node-a", map[string]string{"app": "cache"}),
+		daemonSetPod,
+		pdb("db", map[string]string{"app": "db"}, 0),
+		pdb("web", map[string]string{"app": "web"}, 1),
+		pdb("other", map[string]string{"app": "other"}, 0),
+		pdb("empty", nil, 0),
+	)
+	c := &RollingUpdateCluster{Ctx: context.Background(), K8sClient: k8sClient}
+
+	blocking, err := c.blockingPodDisruptionBudgets("node-a")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"app/db (pods: db-0)"}, blocking)
+
+	blocking, err = c.blockingPodDisruptionBudgets("node-c")
+	assert.NoError(t, err)
+	assert.Empty(t, blocking)
+}
+
+func TestRecordNodeEvent(t *testing.T) {
+	k8sClient := fake.NewSimpleClientset()
+	c := &RollingUpdateCluster{Ctx: context.Background(), K8sClient: k8sClient}
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a", UID: "node-a"}}
+	c.recordNodeEvent(node, corev1.EventTypeWarning, eventReasonDrainBlocked, "blocked")
+
+	events, err := k8sClient.CoreV1().Events(metav1.NamespaceDefault).List(context.Background(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	if assert.Len(t, events.Items, 1) {
+		event := events.Items[0]
+		assert.Equal(t, "Node", event.InvolvedObject.Kind)
+		assert.Equal(t, "node-a", event.InvolvedObject.Name)
+		assert.Equal(t, eventReasonDrainBlocked, event.Reason)
+		assert.Equal(t, corev1.EventTypeWarning, event.Type)
+		assert.Equal(t, "blocked", event.Message)
+	}
+}
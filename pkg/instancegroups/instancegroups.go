@@ -17,7 +17,6 @@ limitations under the License.
 package instancegroups
 
 import (
-	"bufio"
 	"context"
 	"fmt"
 	"os"
@@ -27,6 +26,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
 
+	"go.opencensus.io/trace"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -36,45 +36,21 @@ import (
 	"k8s.io/klog/v2"
 	api "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/tracing"
 	"k8s.io/kops/pkg/validation"
 	"k8s.io/kubectl/pkg/drain"
 )
 
 const rollingUpdateTaintKey = "kops.k8s.io/scheduled-for-update"
 
-// promptInteractive asks the user to continue, mostly copied from vendor/google.golang.org/api/examples/gmail.go.
-func promptInteractive(upgradedHostID, upgradedHostName string) (stopPrompting bool, err error) {
-	stopPrompting = false
-	scanner := bufio.NewScanner(os.Stdin)
-	if upgradedHostName != "" {
-		klog.Infof("Pausing after finished %q, node %q", upgradedHostID, upgradedHostName)
-	} else {
-		klog.Infof("Pausing after finished %q", upgradedHostID)
-	}
-	fmt.Print("Continue? (Y)es, (N)o, (A)lwaysYes: [Y] ")
-	scanner.Scan()
-	err = scanner.Err()
-	if err != nil {
-		klog.Infof("unable to interpret input: %v", err)
-		return stopPrompting, err
-	}
-	val := scanner.Text()
-	val = strings.TrimSpace(val)
-	val = strings.ToLower(val)
-	switch val {
-	case "n":
-		klog.Info("User signaled to stop")
-		os.Exit(3)
-	case "a":
-		klog.Info("Always Yes, stop prompting for rest of hosts")
-		stopPrompting = true
-	}
-	return stopPrompting, err
-}
-
 // RollingUpdate performs a rolling update on a list of instances.
 func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.CloudInstanceGroup, sleepAfterTerminate time.Duration) (err error) {
+	ctx, span := tracing.StartSpan(c.Ctx, "RollingUpdateInstanceGroup", trace.StringAttribute("kops.instance_group", group.InstanceGroup.Name))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	start := time.Now()
 	isBastion := group.InstanceGroup.IsBastion()
+	isMaster := group.InstanceGroup.Spec.Role == api.InstanceGroupRoleMaster
 	// Do not need a k8s client if you are doing cloudonly.
 	if c.K8sClient == nil && !c.CloudOnly {
 		return fmt.Errorf("rollingUpdate is missing a k8s client")
@@ -83,17 +59,29 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 	noneReady := len(group.Ready) == 0
 	numInstances := len(group.Ready) + len(group.NeedUpdate)
 	update := group.NeedUpdate
-	if c.Force {
+	if c.Force || (c.ControlPlaneResize && isMaster) {
 		update = append(update, group.Ready...)
 	}
 
+	update, err = c.selectPending(group, update)
+	if err != nil {
+		return err
+	}
+
 	if len(update) == 0 {
 		return nil
 	}
 
+	if err := c.checkPaused(); err != nil {
+		return err
+	}
+	if c.canaryComplete() {
+		return fmt.Errorf("replaced %d canary instances: %w", c.Canary, ErrRollingUpdatePaused)
+	}
+
 	if isBastion {
 		klog.V(3).Info("Not validating the cluster as instance is a bastion.")
-	} else if err = c.maybeValidate("", 1, group); err != nil {
+	} else if err = c.maybeValidate(ctx, "", 1, group); err != nil {
 		return err
 	}
 
@@ -113,7 +101,7 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 	}
 	maxConcurrency := maxSurge + settings.MaxUnavailable.IntValue()
 
-	if group.InstanceGroup.Spec.Role == api.InstanceGroupRoleMaster && maxSurge != 0 {
+	if isMaster && maxSurge != 0 {
 		// Masters are incapable of surging because they rely on registering themselves through
 		// the local apiserver. That apiserver depends on the local etcd, which relies on being
 		// joined to the etcd cluster.
@@ -124,6 +112,12 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 		}
 	}
 
+	if isMaster && c.ControlPlaneResize {
+		// Replacing a control plane instance takes its etcd members down until their volumes are
+		// attached to the replacement, so only one instance may be out of the etcd clusters at a time.
+		maxConcurrency = 1
+	}
+
 	nonWarmPool := []*cloudinstances.CloudInstance{}
 	// Run through the warm pool and delete all instances directly
 	for _, instance := range update {
@@ -148,17 +142,30 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 
 	update = prioritizeUpdate(update)
 
+	// selected records the instances which have been approved (in interactive mode) and counted toward the canary, or skipped
+	selected := make(map[string]bool)
+
 	if maxSurge > 0 && !c.CloudOnly {
-		skippedNodes := 0
-		for numSurge := 1; numSurge <= maxSurge; numSurge++ {
-			u := update[len(update)-numSurge+skippedNodes]
+		numSurge := 0
+		detachFailed := false
+		for i := len(update) - 1; i >= 0 && numSurge < maxSurge; i-- {
+			u := update[i]
 			if u.Status != cloudinstances.CloudInstanceStatusDetached {
+				ok, err := c.selectInstance(u, selected)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					continue
+				}
 				if err := c.detachInstance(u); err != nil {
 					// If detaching a node fails, we simply proceed to the next one instead of
-					// bubbling up the error.
-					skippedNodes++
-					numSurge--
+					// bubbling up the error. The node is then replaced without surging.
+					klog.Warningf("not surging for instance %q: %v", u.ID, err)
+					detachFailed = true
+					continue
 				}
+				numSurge++
 
 				// If noneReady, wait until after one node is detached and its replacement validates
 				// before detaching more in case the current spec does not result in usable nodes.
@@ -167,11 +174,26 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 					klog.Infof("waiting for %v after detaching instance", sleepAfterTerminate)
 					time.Sleep(sleepAfterTerminate)
 
-					if err := c.maybeValidate(" after detaching instance", c.ValidateCount, group); err != nil {
+					if err := c.maybeValidate(ctx, " after detaching instance", c.ValidateCount, group); err != nil {
 						return err
 					}
 					noneReady = false
 				}
+			} else {
+				numSurge++
+			}
+		}
+
+		// Only the instances which were actually detached have been replaced ahead of time,
+		// so any others must not be drained beyond maxUnavailable.
+		if numSurge < maxSurge {
+			maxConcurrency -= maxSurge - numSurge
+			if maxConcurrency < 1 {
+				if detachFailed {
+					// A maxUnavailable of 0 means no instance may be terminated before its replacement is up.
+					return fmt.Errorf("unable to detach any instance of InstanceGroup %s to surge, and maxUnavailable is 0", group.InstanceGroup.Name)
+				}
+				maxConcurrency = 1
 			}
 		}
 	}
@@ -183,15 +205,33 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 
 	terminateChan := make(chan error, maxConcurrency)
 
-	for uIdx, u := range update {
+	started := 0
+	canaryReached := false
+	for _, u := range update {
+		if err := c.checkPaused(); err != nil {
+			return waitForPendingBeforeReturningError(runningDrains, terminateChan, err)
+		}
+
+		ok, err := c.selectInstance(u, selected)
+		if err != nil {
+			return waitForPendingBeforeReturningError(runningDrains, terminateChan, err)
+		}
+		if !ok {
+			if c.canaryComplete() {
+				canaryReached = true
+			}
+			continue
+		}
+
 		go func(m *cloudinstances.CloudInstance) {
-			terminateChan <- c.drainTerminateAndWait(m, sleepAfterTerminate)
+			terminateChan <- c.drainTerminateAndWait(ctx, m, sleepAfterTerminate)
 		}(u)
 		runningDrains++
+		started++
 
 		// Wait until after one node is deleted and its replacement validates before the concurrent draining
 		// in case the current spec does not result in usable nodes.
-		if runningDrains < maxConcurrency && (!noneReady || uIdx > 0) {
+		if runningDrains < maxConcurrency && (!noneReady || started > 1) {
 			continue
 		}
 
@@ -201,27 +241,11 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 			return waitForPendingBeforeReturningError(runningDrains, terminateChan, err)
 		}
 
-		err = c.maybeValidate(" after terminating instance", c.ValidateCount, group)
+		err = c.maybeValidate(ctx, " after terminating instance", c.ValidateCount, group)
 		if err != nil {
 			return waitForPendingBeforeReturningError(runningDrains, terminateChan, err)
 		}
 
-		if c.Interactive {
-			nodeName := ""
-			if u.Node != nil {
-				nodeName = u.Node.Name
-			}
-
-			stopPrompting, err := promptInteractive(u.ID, nodeName)
-			if err != nil {
-				return err
-			}
-			if stopPrompting {
-				// Is a pointer to a struct, changes here push back into the original
-				c.Interactive = false
-			}
-		}
-
 		// Validation tends to return failures from the start of drain until the replacement is
 		// fully ready, so sweep up as many completions as we can before starting the next drain.
 	sweep:
@@ -247,12 +271,18 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 			}
 		}
 
-		err = c.maybeValidate(" after terminating instance", c.ValidateCount, group)
+		err = c.maybeValidate(ctx, " after terminating instance", c.ValidateCount, group)
 		if err != nil {
 			return err
 		}
 	}
 
+	if canaryReached {
+		return fmt.Errorf("replaced %d canary instances: %w", c.Canary, ErrRollingUpdatePaused)
+	}
+
+	klog.InfoS("Rolling update of instance group completed", "instancegroup", group.InstanceGroup.Name, "duration", time.Since(start))
+
 	return nil
 }
 
@@ -376,7 +406,8 @@ func (c *RollingUpdateCluster) patchExcludeFromLB(node *corev1.Node) error {
 	return err
 }
 
-func (c *RollingUpdateCluster) drainTerminateAndWait(u *cloudinstances.CloudInstance, sleepAfterTerminate time.Duration) error {
+func (c *RollingUpdateCluster) drainTerminateAndWait(ctx context.Context, u *cloudinstances.CloudInstance, sleepAfterTerminate time.Duration) (err error) {
+	start := time.Now()
 	instanceID := u.ID
 
 	nodeName := ""
@@ -384,6 +415,9 @@ func (c *RollingUpdateCluster) drainTerminateAndWait(u *cloudinstances.CloudInst
 		nodeName = u.Node.Name
 	}
 
+	_, span := tracing.StartSpan(ctx, "DrainTerminateAndWait", trace.StringAttribute("kops.instance", instanceID), trace.StringAttribute("kops.node", nodeName))
+	defer func() { tracing.EndSpan(span, err) }()
+
 	isBastion := u.CloudInstanceGroup.InstanceGroup.IsBastion()
 
 	if isBastion {
@@ -403,6 +437,7 @@ func (c *RollingUpdateCluster) drainTerminateAndWait(u *cloudinstances.CloudInst
 				}
 				klog.Infof("Ignoring error draining node %q: %v", nodeName, err)
 			}
+			span.Annotate(nil, "drained node")
 		} else {
 			klog.Warningf("Skipping drain of instance %q, because it is not registered in kubernetes", instanceID)
 		}
@@ -418,6 +453,7 @@ func (c *RollingUpdateCluster) drainTerminateAndWait(u *cloudinstances.CloudInst
 			if err := c.deleteNode(u.Node); err != nil {
 				return fmt.Errorf("error deleting node %q: %v", nodeName, err)
 			}
+			span.Annotate(nil, "deleted node")
 		}
 	}
 
@@ -425,12 +461,16 @@ func (c *RollingUpdateCluster) drainTerminateAndWait(u *cloudinstances.CloudInst
 		klog.Errorf("error deleting instance %q, node %q: %v", instanceID, nodeName, err)
 		return err
 	}
+	span.Annotate(nil, "deleted instance")
+	c.recordReplaced(u)
 
 	if err := c.reconcileInstanceGroup(); err != nil {
 		klog.Errorf("error reconciling instance group %q: %v", u.CloudInstanceGroup.HumanName, err)
 		return err
 	}
 
+	klog.InfoS("Replaced instance", "instancegroup", u.CloudInstanceGroup.InstanceGroup.Name, "instance", instanceID, "node", nodeName, "duration", time.Since(start))
+
 	// Wait for the minimum interval
 	klog.Infof("waiting for %v after terminating instance", sleepAfterTerminate)
 	time.Sleep(sleepAfterTerminate)
@@ -462,7 +502,10 @@ func (c *RollingUpdateCluster) reconcileInstanceGroup() error {
 
 }
 
-func (c *RollingUpdateCluster) maybeValidate(operation string, validateCount int, group *cloudinstances.CloudInstanceGroup) error {
+func (c *RollingUpdateCluster) maybeValidate(ctx context.Context, operation string, validateCount int, group *cloudinstances.CloudInstanceGroup) (err error) {
+	_, span := tracing.StartSpan(ctx, "ValidateCluster")
+	defer func() { tracing.EndSpan(span, err) }()
+
 	if c.CloudOnly {
 		klog.Warningf("Not validating cluster as cloudonly flag is set.")
 
@@ -617,20 +660,28 @@ func (c *RollingUpdateCluster) drainNode(u *cloudinstances.CloudInstance) error
 		return fmt.Errorf("node name not set")
 	}
 
+	gracePeriodSeconds := -1
+	if c.PodEvictionGracePeriod != nil {
+		gracePeriodSeconds = int(c.PodEvictionGracePeriod.Seconds())
+	}
+
+	var timeout time.Duration
+	if u.CloudInstanceGroup != nil && u.CloudInstanceGroup.InstanceGroup != nil {
+		timeout = resolveSettings(c.Cluster, u.CloudInstanceGroup.InstanceGroup, 0).DrainTimeout.Duration
+	}
+
 	helper := &drain.Helper{
 		Ctx:                 c.Ctx,
 		Client:              c.K8sClient,
 		Force:               true,
-		GracePeriodSeconds:  -1,
+		GracePeriodSeconds:  gracePeriodSeconds,
 		IgnoreAllDaemonSets: true,
 		Out:                 os.Stdout,
 		ErrOut:              os.Stderr,
+		Timeout:             timeout,
 
 		// We want to proceed even when pods are using emptyDir volumes
 		DeleteEmptyDirData: true,
-
-		// Other options we might want to set:
-		// Timeout?
 	}
 
 	if err := drain.RunCordonOrUncordon(helper, u.Node, true); err != nil {
@@ -647,10 +698,22 @@ func (c *RollingUpdateCluster) drainNode(u *cloudinstances.CloudInstance) error
 		return fmt.Errorf("error excluding node from load balancer: %v", err)
 	}
 
-	if err := drain.RunNodeDrain(helper, u.Node.Name); err != nil {
+	stopWatching := c.watchDrain(u.Node)
+	err := drain.RunNodeDrain(helper, u.Node.Name)
+	stopWatching()
+	if err != nil {
 		if apierrors.IsNotFound(err) {
 			return nil
 		}
+
+		blocking, pdbErr := c.blockingPodDisruptionBudgets(u.Node.Name)
+		if pdbErr != nil {
+			klog.Warningf("unable to check for blocking PodDisruptionBudgets: %v", pdbErr)
+		}
+		if len(blocking) != 0 {
+			err = fmt.Errorf("%v; eviction is blocked by PodDisruptionBudgets %s", err, strings.Join(blocking, ", "))
+		}
+		c.recordNodeEvent(u.Node, corev1.EventTypeWarning, eventReasonDrainFailed, fmt.Sprintf("Rolling update failed to drain the node: %v", err))
 		return fmt.Errorf("error draining node: %v", err)
 	}
 
@@ -688,11 +751,11 @@ func (c *RollingUpdateCluster) UpdateSingleInstance(cloudMember *cloudinstances.
 			if err != nil {
 				return fmt.Errorf("failed to detach instance: %v", err)
 			}
-			if err := c.maybeValidate(" after detaching instance", c.ValidateCount, cloudMember.CloudInstanceGroup); err != nil {
+			if err := c.maybeValidate(c.Ctx, " after detaching instance", c.ValidateCount, cloudMember.CloudInstanceGroup); err != nil {
 				return err
 			}
 		}
 	}
 
-	return c.drainTerminateAndWait(cloudMember, 0)
+	return c.drainTerminateAndWait(c.Ctx, cloudMember, 0)
 }
@@ -0,0 +1,200 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+var stdinScanner = bufio.NewScanner(os.Stdin)
+
+// promptApproval shows the description of an instance and returns the user's answer, lower-cased; replaced in tests
+var promptApproval = func(description string) (string, error) {
+	fmt.Print(description)
+	fmt.Print("Replace? (Y)es, (S)kip, (N)o and pause, (A)lwaysYes: [Y] ")
+	stdinScanner.Scan()
+	if err := stdinScanner.Err(); err != nil {
+		return "", fmt.Errorf("unable to interpret input: %v", err)
+	}
+	return strings.ToLower(strings.TrimSpace(stdinScanner.Text())), nil
+}
+
+// selectInstance decides whether an instance is replaced now: it counts it toward the canary and, in interactive
+// mode, asks the user to approve it. Answering no pauses the rolling update.
+func (c *RollingUpdateCluster) selectInstance(u *cloudinstances.CloudInstance, selected map[string]bool) (bool, error) {
+	if approved, found := selected[u.ID]; found {
+		return approved, nil
+	}
+
+	if !c.claimCanary() {
+		return false, nil
+	}
+
+	if c.Interactive {
+		answer, err := promptApproval(c.describeInstance(u))
+		if err != nil {
+			c.releaseCanary()
+			return false, err
+		}
+		switch answer {
+		case "", "y":
+		case "s":
+			klog.Infof("Skipping instance %q", u.ID)
+			c.releaseCanary()
+			selected[u.ID] = false
+			return false, nil
+		case "n":
+			klog.Info("User signaled to stop")
+			c.releaseCanary()
+			return false, ErrRollingUpdatePaused
+		case "a":
+			klog.Info("Always Yes, stop prompting for rest of hosts")
+			// Is a pointer to a struct, changes here push back into the original
+			c.Interactive = false
+		default:
+			c.releaseCanary()
+			return false, fmt.Errorf("unexpected answer %q", answer)
+		}
+	}
+
+	selected[u.ID] = true
+	return true, nil
+}
+
+// claimCanary counts an instance toward the canary, returning false if the canary is complete
+func (c *RollingUpdateCluster) claimCanary() bool {
+	if c.Canary <= 0 {
+		return true
+	}
+
+	c.canaryMutex.Lock()
+	defer c.canaryMutex.Unlock()
+
+	if c.canaryClaimed >= c.Canary {
+		return false
+	}
+	c.canaryClaimed++
+	return true
+}
+
+// releaseCanary stops counting an instance which was not replaced toward the canary
+func (c *RollingUpdateCluster) releaseCanary() {
+	if c.Canary <= 0 {
+		return
+	}
+
+	c.canaryMutex.Lock()
+	defer c.canaryMutex.Unlock()
+
+	c.canaryClaimed--
+}
+
+// canaryComplete returns whether as many instances as the canary allows have been selected for replacement
+func (c *RollingUpdateCluster) canaryComplete() bool {
+	if c.Canary <= 0 {
+		return false
+	}
+
+	c.canaryMutex.Lock()
+	defer c.canaryMutex.Unlock()
+
+	return c.canaryClaimed >= c.Canary
+}
+
+// describeInstance describes an instance for the user to decide whether to replace it
+func (c *RollingUpdateCluster) describeInstance(u *cloudinstances.CloudInstance) string {
+	var b strings.Builder
+
+	groupName := ""
+	if u.CloudInstanceGroup != nil {
+		groupName = u.CloudInstanceGroup.HumanName
+	}
+	fmt.Fprintf(&b, "\nInstance %q in group %q", u.ID, groupName)
+	if u.Node == nil {
+		fmt.Fprintf(&b, " (not registered as a node)\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, ", node %q\n", u.Node.Name)
+
+	if !u.Node.CreationTimestamp.IsZero() {
+		fmt.Fprintf(&b, "  Age: %s\n", duration.HumanDuration(time.Since(u.Node.CreationTimestamp.Time)))
+	}
+
+	if c.K8sClient != nil {
+		pods, err := c.localStoragePods(u.Node.Name)
+		if err != nil {
+			klog.Warningf("unable to list pods on node %q: %v", u.Node.Name, err)
+		} else if len(pods) != 0 {
+			fmt.Fprintf(&b, "  Pods with local storage, which is lost when the node is replaced:\n")
+			for _, pod := range pods {
+				fmt.Fprintf(&b, "    %s\n", pod)
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// localStoragePods returns the evictable pods on the node which use emptyDir or hostPath volumes, with the volume names
+func (c *RollingUpdateCluster) localStoragePods(nodeName string) ([]string, error) {
+	ctx := c.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	podList, err := c.K8sClient.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var pods []string
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Spec.NodeName != nodeName || !isEvictable(pod) {
+			continue
+		}
+		var volumes []string
+		for _, volume := range pod.Spec.Volumes {
+			if volume.EmptyDir != nil && volume.EmptyDir.Medium != corev1.StorageMediumMemory {
+				volumes = append(volumes, volume.Name+" (emptyDir)")
+			} else if volume.HostPath != nil {
+				volumes = append(volumes, volume.Name+" (hostPath)")
+			}
+		}
+		if len(volumes) != 0 {
+			pods = append(pods, fmt.Sprintf("%s/%s: %s", pod.Namespace, pod.Name, strings.Join(volumes, ", ")))
+		}
+	}
+	sort.Strings(pods)
+
+	return pods, nil
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+type countingDetach struct {
+	autoscalingiface.AutoScalingAPI
+	Detached []string
+}
+
+func (c *countingDetach) DetachInstances(input *autoscaling.DetachInstancesInput) (*autoscaling.DetachInstancesOutput, error) {
+	for _, id := range input.InstanceIds {
+		c.Detached = append(c.Detached, *id)
+	}
+	return &autoscaling.DetachInstancesOutput{}, nil
+}
+
+func withPromptAnswers(t *testing.T, answers ...string) *[]string {
+	prompted := &[]string{}
+	original := promptApproval
+	promptApproval = func(description string) (string, error) {
+		if len(answers) == 0 {
+			t.Fatalf("unexpected prompt: %s", description)
+		}
+		*prompted = append(*prompted, description)
+		answer := answers[0]
+		answers = answers[1:]
+		return answer, nil
+	}
+	t.Cleanup(func() { promptApproval = original })
+	return prompted
+}
+
+func TestRollingUpdateCanary(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Canary = 1
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	makeGroup(groups, c.K8sClient, cloud, "node-2", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.True(t, errors.Is(err, ErrRollingUpdatePaused), "expected paused error, got %v", err)
+
+	assertGroupInstanceCount(t, cloud, "node-1", 2)
+	assertGroupInstanceCount(t, cloud, "node-2", 3)
+}
+
+func TestRollingUpdateCanaryMaxSurge(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Canary = 2
+	countingDetach := &countingDetach{AutoScalingAPI: cloud.MockAutoscaling}
+	cloud.MockAutoscaling = countingDetach
+	cloud.MockEC2 = &ec2IgnoreTags{EC2API: cloud.MockEC2}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 4, 4)
+	two := intstr.FromInt(2)
+	groups["node-1"].InstanceGroup.Spec.RollingUpdate = &kopsapi.RollingUpdate{MaxSurge: &two}
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.True(t, errors.Is(err, ErrRollingUpdatePaused), "expected paused error, got %v", err)
+
+	assertGroupInstanceCount(t, cloud, "node-1", 2)
+	assert.Len(t, countingDetach.Detached, 2)
+}
+
+func TestRollingUpdateCanaryLargerThanUpdate(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Canary = 5
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 0)
+}
+
+func TestRollingUpdateInteractiveSkip(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Interactive = true
+	prompted := withPromptAnswers(t, "s", "y", "")
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 1)
+	assert.Len(t, *prompted, 3)
+	assert.Contains(t, (*prompted)[0], `group "node-1"`)
+}
+
+func TestRollingUpdateInteractiveAlways(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Interactive = true
+	withPromptAnswers(t, "a")
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 0)
+	assert.False(t, c.Interactive)
+}
+
+func TestRollingUpdateInteractiveNo(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Interactive = true
+	withPromptAnswers(t, "y", "n")
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.True(t, errors.Is(err, ErrRollingUpdatePaused), "expected paused error, got %v", err)
+
+	assertGroupInstanceCount(t, cloud, "node-1", 2)
+}
+
+func TestRollingUpdateInteractiveCanarySkipDoesNotCount(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Interactive = true
+	c.Canary = 1
+	withPromptAnswers(t, "s", "y")
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.True(t, errors.Is(err, ErrRollingUpdatePaused), "expected paused error, got %v", err)
+
+	assertGroupInstanceCount(t, cloud, "node-1", 2)
+}
@@ -0,0 +1,300 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/acls"
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/registry"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// ErrRollingUpdatePaused is returned when a rolling update stops because a pause was requested
+var ErrRollingUpdatePaused = errors.New("rolling update paused")
+
+// ProgressPhase is the phase of a rolling update
+type ProgressPhase string
+
+const (
+	ProgressPhaseRunning   ProgressPhase = "Running"
+	ProgressPhasePaused    ProgressPhase = "Paused"
+	ProgressPhaseFailed    ProgressPhase = "Failed"
+	ProgressPhaseCompleted ProgressPhase = "Completed"
+)
+
+// Progress is the checkpoint of a rolling update, persisted so that it can be paused and resumed
+type Progress struct {
+	Phase ProgressPhase `json:"phase"`
+	// PauseRequested is set by kops rolling-update pause; the rolling update stops before draining the next instance
+	PauseRequested bool `json:"pauseRequested,omitempty"`
+	// Error is the error a failed rolling update stopped with
+	Error string `json:"error,omitempty"`
+
+	StartedAt time.Time `json:"startedAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// Options are the options the rolling update was started with, opaque to this package
+	Options json.RawMessage `json:"options,omitempty"`
+
+	// InstanceGroups is the progress of each instance group, keyed by name
+	InstanceGroups map[string]*InstanceGroupProgress `json:"instanceGroups,omitempty"`
+}
+
+// InstanceGroupProgress is the progress of the rolling update of one instance group
+type InstanceGroupProgress struct {
+	// Pending are the IDs of the instances selected for update which have not yet been replaced
+	Pending []string `json:"pending,omitempty"`
+	// Replaced are the IDs of the instances which have been drained and terminated
+	Replaced []string `json:"replaced,omitempty"`
+}
+
+// ProgressStore persists the progress of a rolling update
+type ProgressStore interface {
+	// Load returns the last saved progress, or nil if there is none
+	Load() (*Progress, error)
+	// Save persists the progress
+	Save(progress *Progress) error
+}
+
+type vfsProgressStore struct {
+	cluster *api.Cluster
+	path    vfs.Path
+}
+
+var _ ProgressStore = &vfsProgressStore{}
+
+// NewProgressStore returns a ProgressStore keeping the progress of the cluster's rolling update in the state store
+func NewProgressStore(cluster *api.Cluster) (ProgressStore, error) {
+	configBase, err := registry.ConfigBase(cluster)
+	if err != nil {
+		return nil, err
+	}
+	return &vfsProgressStore{
+		cluster: cluster,
+		path:    configBase.Join("rolling-update", "progress.json"),
+	}, nil
+}
+
+func (s *vfsProgressStore) Load() (*Progress, error) {
+	data, err := s.path.ReadFile()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading rolling update progress %s: %v", s.path, err)
+	}
+
+	progress := &Progress{}
+	if err := json.Unmarshal(data, progress); err != nil {
+		return nil, fmt.Errorf("error parsing rolling update progress %s: %v", s.path, err)
+	}
+	return progress, nil
+}
+
+func (s *vfsProgressStore) Save(progress *Progress) error {
+	data, err := json.MarshalIndent(progress, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializing rolling update progress: %v", err)
+	}
+
+	acl, err := acls.GetACL(s.path, s.cluster)
+	if err != nil {
+		return err
+	}
+
+	if err := s.path.WriteFile(bytes.NewReader(data), acl); err != nil {
+		return fmt.Errorf("error writing rolling update progress %s: %v", s.path, err)
+	}
+	return nil
+}
+
+// startProgress records the start of a rolling update, or the continuation of a resumed one
+func (c *RollingUpdateCluster) startProgress() error {
+	if c.ProgressStore == nil {
+		return nil
+	}
+
+	c.progressMutex.Lock()
+	defer c.progressMutex.Unlock()
+
+	if c.Resume {
+		progress, err := c.ProgressStore.Load()
+		if err != nil {
+			return err
+		}
+		if progress == nil {
+			return fmt.Errorf("no rolling update to resume")
+		}
+		c.progress = progress
+		if c.progress.InstanceGroups == nil {
+			c.progress.InstanceGroups = make(map[string]*InstanceGroupProgress)
+		}
+	} else {
+		c.progress = &Progress{
+			StartedAt:      time.Now().UTC(),
+			Options:        c.ProgressOptions,
+			InstanceGroups: make(map[string]*InstanceGroupProgress),
+		}
+	}
+
+	c.progress.Phase = ProgressPhaseRunning
+	c.progress.PauseRequested = false
+	c.progress.Error = ""
+	c.progress.UpdatedAt = time.Now().UTC()
+	return c.ProgressStore.Save(c.progress)
+}
+
+// finishProgress records the outcome of a rolling update
+func (c *RollingUpdateCluster) finishProgress(err error) error {
+	if c.progress == nil {
+		return err
+	}
+
+	c.progressMutex.Lock()
+	defer c.progressMutex.Unlock()
+
+	switch {
+	case err == nil:
+		c.progress.Phase = ProgressPhaseCompleted
+	case errors.Is(err, ErrRollingUpdatePaused):
+		c.progress.Phase = ProgressPhasePaused
+		c.progress.PauseRequested = false
+	default:
+		c.progress.Phase = ProgressPhaseFailed
+		c.progress.Error = err.Error()
+	}
+
+	if saveErr := c.saveProgress(); saveErr != nil {
+		klog.Warningf("unable to save rolling update progress: %v", saveErr)
+	}
+	return err
+}
+
+// selectPending records the instances of the group selected for update. When resuming a forced rolling update,
+// it instead narrows the selection to the instances which were still pending, so that instances which have
+// already been replaced are not rolled again.
+func (c *RollingUpdateCluster) selectPending(group *cloudinstances.CloudInstanceGroup, update []*cloudinstances.CloudInstance) ([]*cloudinstances.CloudInstance, error) {
+	if c.progress == nil {
+		return update, nil
+	}
+
+	c.progressMutex.Lock()
+	defer c.progressMutex.Unlock()
+
+	name := group.InstanceGroup.ObjectMeta.Name
+	igProgress := c.progress.InstanceGroups[name]
+	if c.Resume && c.Force && igProgress != nil {
+		pending := make(map[string]bool)
+		for _, id := range igProgress.Pending {
+			pending[id] = true
+		}
+		var filtered []*cloudinstances.CloudInstance
+		for _, u := range update {
+			if pending[u.ID] {
+				filtered = append(filtered, u)
+			}
+		}
+		update = filtered
+	}
+
+	if igProgress == nil {
+		igProgress = &InstanceGroupProgress{}
+		c.progress.InstanceGroups[name] = igProgress
+	}
+	igProgress.Pending = nil
+	for _, u := range update {
+		igProgress.Pending = append(igProgress.Pending, u.ID)
+	}
+	sort.Strings(igProgress.Pending)
+
+	return update, c.saveProgress()
+}
+
+// recordReplaced records that an instance has been drained and terminated
+func (c *RollingUpdateCluster) recordReplaced(u *cloudinstances.CloudInstance) {
+	if c.progress == nil || u.CloudInstanceGroup == nil || u.CloudInstanceGroup.InstanceGroup == nil {
+		return
+	}
+
+	c.progressMutex.Lock()
+	defer c.progressMutex.Unlock()
+
+	igProgress := c.progress.InstanceGroups[u.CloudInstanceGroup.InstanceGroup.ObjectMeta.Name]
+	if igProgress == nil {
+		return
+	}
+	var pending []string
+	for _, id := range igProgress.Pending {
+		if id != u.ID {
+			pending = append(pending, id)
+		}
+	}
+	igProgress.Pending = pending
+	igProgress.Replaced = append(igProgress.Replaced, u.ID)
+
+	if err := c.saveProgress(); err != nil {
+		klog.Warningf("unable to save rolling update progress: %v", err)
+	}
+}
+
+// checkPaused returns ErrRollingUpdatePaused if a pause has been requested
+func (c *RollingUpdateCluster) checkPaused() error {
+	if c.progress == nil {
+		return nil
+	}
+
+	c.progressMutex.Lock()
+	defer c.progressMutex.Unlock()
+
+	stored, err := c.ProgressStore.Load()
+	if err != nil {
+		klog.Warningf("unable to check whether the rolling update is paused: %v", err)
+		return nil
+	}
+	if stored != nil && stored.PauseRequested {
+		c.progress.PauseRequested = true
+		return ErrRollingUpdatePaused
+	}
+	return nil
+}
+
+// saveProgress persists the progress; it must be called with progressMutex held.
+// A pause requested since the last save is preserved.
+func (c *RollingUpdateCluster) saveProgress() error {
+	if c.progress.Phase == ProgressPhaseRunning && !c.progress.PauseRequested {
+		stored, err := c.ProgressStore.Load()
+		if err != nil {
+			return err
+		}
+		if stored != nil && stored.PauseRequested && stored.StartedAt.Equal(c.progress.StartedAt) {
+			c.progress.PauseRequested = true
+		}
+	}
+
+	c.progress.UpdatedAt = time.Now().UTC()
+	return c.ProgressStore.Save(c.progress)
+}
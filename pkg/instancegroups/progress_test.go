@@ -0,0 +1,143 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+type memoryProgressStore struct {
+	mutex    sync.Mutex
+	progress *Progress
+
+	// pauseAfterReplaced simulates kops rolling-update pause once that many instances have been replaced
+	pauseAfterReplaced int
+}
+
+var _ ProgressStore = &memoryProgressStore{}
+
+func (s *memoryProgressStore) Load() (*Progress, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.progress == nil {
+		return nil, nil
+	}
+	progress := *s.progress
+	return &progress, nil
+}
+
+func (s *memoryProgressStore) Save(progress *Progress) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	saved := *progress
+	saved.InstanceGroups = make(map[string]*InstanceGroupProgress)
+	replaced := 0
+	for name, ig := range progress.InstanceGroups {
+		saved.InstanceGroups[name] = &InstanceGroupProgress{
+			Pending:  append([]string(nil), ig.Pending...),
+			Replaced: append([]string(nil), ig.Replaced...),
+		}
+		replaced += len(ig.Replaced)
+	}
+	if s.pauseAfterReplaced != 0 && replaced >= s.pauseAfterReplaced && saved.Phase == ProgressPhaseRunning {
+		saved.PauseRequested = true
+	}
+	s.progress = &saved
+	return nil
+}
+
+func TestRollingUpdateProgressCompleted(t *testing.T) {
+	c, cloud := getTestSetup()
+	store := &memoryProgressStore{}
+	c.ProgressStore = store
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 2)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assert.Equal(t, ProgressPhaseCompleted, store.progress.Phase)
+	assert.Empty(t, store.progress.InstanceGroups["node-1"].Pending)
+	assert.ElementsMatch(t, []string{"node-1a", "node-1b"}, store.progress.InstanceGroups["node-1"].Replaced)
+}
+
+func TestRollingUpdateProgressPause(t *testing.T) {
+	c, cloud := getTestSetup()
+	store := &memoryProgressStore{pauseAfterReplaced: 1}
+	c.ProgressStore = store
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.True(t, errors.Is(err, ErrRollingUpdatePaused), "expected paused error, got %v", err)
+
+	assertGroupInstanceCount(t, cloud, "node-1", 2)
+	assert.Equal(t, ProgressPhasePaused, store.progress.Phase)
+	assert.False(t, store.progress.PauseRequested)
+	assert.Len(t, store.progress.InstanceGroups["node-1"].Pending, 2)
+	assert.Len(t, store.progress.InstanceGroups["node-1"].Replaced, 1)
+}
+
+func TestRollingUpdateProgressResumeForce(t *testing.T) {
+	c, cloud := getTestSetup()
+	store := &memoryProgressStore{
+		progress: &Progress{
+			Phase: ProgressPhasePaused,
+			InstanceGroups: map[string]*InstanceGroupProgress{
+				"node-1": {
+					Pending:  []string{"node-1b"},
+					Replaced: []string{"node-1x", "node-1y"},
+				},
+			},
+		},
+	}
+	c.ProgressStore = store
+	c.Resume = true
+	c.Force = true
+
+	// The replacements of already replaced instances are up to date, but would be rolled again by --force
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 0)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 2)
+	assert.Equal(t, ProgressPhaseCompleted, store.progress.Phase)
+	assert.Empty(t, store.progress.InstanceGroups["node-1"].Pending)
+	assert.Equal(t, []string{"node-1x", "node-1y", "node-1b"}, store.progress.InstanceGroups["node-1"].Replaced)
+}
+
+func TestRollingUpdateProgressResumeWithoutProgress(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.ProgressStore = &memoryProgressStore{}
+	c.Resume = true
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.Error(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 3)
+}
@@ -18,6 +18,8 @@ package instancegroups
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
 	"sync"
@@ -46,11 +48,16 @@ type RollingUpdateCluster struct {
 	NodeInterval time.Duration
 	// BastionInterval is the amount of time to wait after stopping a bastion instance
 	BastionInterval time.Duration
-	// Interactive prompts user to continue after each instance is updated
+	// Interactive prompts the user to approve or skip the replacement of each instance
 	Interactive bool
+	// Canary, if nonzero, is the number of instances to replace before pausing the rolling update
+	Canary int
 
 	Force bool
 
+	// ControlPlaneResize replaces every control plane instance, one at a time, even those that do not need updating
+	ControlPlaneResize bool
+
 	// K8sClient is the kubernetes client, used for draining etc
 	K8sClient kubernetes.Interface
 
@@ -65,6 +72,9 @@ type RollingUpdateCluster struct {
 	// PostDrainDelay is the duration we wait after draining each node
 	PostDrainDelay time.Duration
 
+	// PodEvictionGracePeriod, if set, overrides the termination grace period of the pods evicted when draining a node
+	PodEvictionGracePeriod *time.Duration
+
 	// ValidationTimeout is the maximum time to wait for the cluster to validate, once we start validation
 	ValidationTimeout time.Duration
 
@@ -77,6 +87,19 @@ type RollingUpdateCluster struct {
 
 	// ValidateCount is the amount of time that a cluster needs to be validated after single node update
 	ValidateCount int
+
+	// ProgressStore, if set, is used to checkpoint the progress of the rolling update so it can be paused and resumed
+	ProgressStore ProgressStore
+	// ProgressOptions are recorded in the checkpoint, for resuming with the same options
+	ProgressOptions json.RawMessage
+	// Resume continues the rolling update recorded in the ProgressStore
+	Resume bool
+
+	progressMutex sync.Mutex
+	progress      *Progress
+
+	canaryMutex   sync.Mutex
+	canaryClaimed int
 }
 
 // AdjustNeedUpdate adjusts the set of instances that need updating, using factors outside those known by the cloud implementation
@@ -94,6 +117,14 @@ func (c *RollingUpdateCluster) RollingUpdate(groups map[string]*cloudinstances.C
 		return nil
 	}
 
+	if err := c.startProgress(); err != nil {
+		return err
+	}
+
+	return c.finishProgress(c.rollingUpdateGroups(groups))
+}
+
+func (c *RollingUpdateCluster) rollingUpdateGroups(groups map[string]*cloudinstances.CloudInstanceGroup) error {
 	var resultsMutex sync.Mutex
 	results := make(map[string]error)
 
@@ -142,6 +173,9 @@ func (c *RollingUpdateCluster) RollingUpdate(groups map[string]*cloudinstances.C
 
 	// Do not continue update if bastion(s) failed
 	for _, err := range results {
+		if errors.Is(err, ErrRollingUpdatePaused) {
+			return err
+		}
 		if err != nil {
 			return fmt.Errorf("bastion not healthy after update, stopping rolling-update: %q", err)
 		}
@@ -156,6 +190,9 @@ func (c *RollingUpdateCluster) RollingUpdate(groups map[string]*cloudinstances.C
 		for _, k := range sortGroups(masterGroups) {
 			err := c.rollingUpdateInstanceGroup(masterGroups[k], c.MasterInterval)
 
+			if errors.Is(err, ErrRollingUpdatePaused) {
+				return err
+			}
 			// Do not continue update if master(s) failed, cluster is potentially in an unhealthy state
 			if err != nil {
 				return fmt.Errorf("master not healthy after update, stopping rolling-update: %q", err)
@@ -171,6 +208,9 @@ func (c *RollingUpdateCluster) RollingUpdate(groups map[string]*cloudinstances.C
 
 		for _, k := range sortGroups(apiServerGroups) {
 			err := c.rollingUpdateInstanceGroup(apiServerGroups[k], c.NodeInterval)
+			if errors.Is(err, ErrRollingUpdatePaused) {
+				return err
+			}
 
 			results[k] = err
 
@@ -192,6 +232,9 @@ func (c *RollingUpdateCluster) RollingUpdate(groups map[string]*cloudinstances.C
 
 		for _, k := range sortGroups(nodeGroups) {
 			err := c.rollingUpdateInstanceGroup(nodeGroups[k], c.NodeInterval)
+			if errors.Is(err, ErrRollingUpdatePaused) {
+				return err
+			}
 
 			results[k] = err
 
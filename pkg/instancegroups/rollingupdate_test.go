@@ -344,6 +344,22 @@ func TestRollingUpdateEmptyGroup(t *testing.T) {
 	assertGroupInstanceCount(t, cloud, "bastion-1", 1)
 }
 
+func TestRollingUpdateScaledToZeroGroup(t *testing.T) {
+
+	c, cloud := getTestSetup()
+	c.Force = true
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 0, 0)
+	makeGroup(groups, c.K8sClient, cloud, "node-2", kopsapi.InstanceGroupRoleNode, 1, 1)
+
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 0)
+	assertGroupInstanceCount(t, cloud, "node-2", 0)
+}
+
 func TestRollingUpdateUnknownRole(t *testing.T) {
 
 	c, cloud := getTestSetup()
@@ -1235,6 +1251,66 @@ func TestRollingUpdateMaxUnavailableAllNeedUpdateMaster(t *testing.T) {
 	concurrentTest.AssertComplete()
 }
 
+// controlPlaneResizeTest checks that no more than one instance is terminated between validations
+type controlPlaneResizeTest struct {
+	ec2iface.EC2API
+	t          *testing.T
+	mutex      sync.Mutex
+	terminated int
+	validated  int
+}
+
+func (c *controlPlaneResizeTest) Validate() (*validation.ValidationCluster, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.validated = c.terminated
+	return &validation.ValidationCluster{}, nil
+}
+
+func (c *controlPlaneResizeTest) TerminateInstances(input *ec2.TerminateInstancesInput) (*ec2.TerminateInstancesOutput, error) {
+	if input.DryRun != nil && *input.DryRun {
+		return &ec2.TerminateInstancesOutput{}, nil
+	}
+
+	c.mutex.Lock()
+	c.terminated += len(input.InstanceIds)
+	assert.Equal(c.t, 1, c.terminated-c.validated, "instances terminated since the last validation")
+	c.mutex.Unlock()
+
+	return c.EC2API.TerminateInstances(input)
+}
+
+func TestRollingUpdateControlPlaneResize(t *testing.T) {
+
+	c, cloud := getTestSetup()
+
+	resizeTest := &controlPlaneResizeTest{
+		EC2API: cloud.MockEC2,
+		t:      t,
+	}
+	c.ControlPlaneResize = true
+	c.ValidateCount = 1
+	c.ClusterValidator = resizeTest
+	cloud.MockEC2 = resizeTest
+
+	two := intstr.FromInt(2)
+	c.Cluster.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		MaxUnavailable: &two,
+	}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "master-1", kopsapi.InstanceGroupRoleMaster, 3, 1)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 0)
+
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "master-1", 0)
+	assertGroupInstanceCount(t, cloud, "node-1", 3)
+	assert.Equal(t, 3, resizeTest.terminated, "terminated instances")
+}
+
 type concurrentTestAutoscaling struct {
 	autoscalingiface.AutoScalingAPI
 	ConcurrentTest *concurrentTest
@@ -1351,6 +1427,131 @@ func TestRollingUpdateMaxSurgeGreaterThanNeedUpdate(t *testing.T) {
 	assert.Equal(t, 2, countDetach.Count)
 }
 
+type failingDetach struct {
+	autoscalingiface.AutoScalingAPI
+	Count int
+}
+
+func (c *failingDetach) DetachInstances(input *autoscaling.DetachInstancesInput) (*autoscaling.DetachInstancesOutput, error) {
+	c.Count += len(input.InstanceIds)
+	return nil, errors.New("detach not supported")
+}
+
+func TestRollingUpdateMaxSurgeDetachFails(t *testing.T) {
+
+	c, cloud := getTestSetup()
+
+	failingDetach := &failingDetach{AutoScalingAPI: cloud.MockAutoscaling}
+	cloud.MockAutoscaling = failingDetach
+	cloud.MockEC2 = &ec2IgnoreTags{EC2API: cloud.MockEC2}
+
+	two := intstr.FromInt(2)
+	c.Cluster.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		MaxSurge: &two,
+	}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.Error(t, err, "rolling update")
+
+	// maxUnavailable defaults to 0 when surging, so no instance may be terminated without a replacement
+	assertGroupInstanceCount(t, cloud, "node-1", 3)
+	assert.Equal(t, 3, failingDetach.Count)
+}
+
+func TestRollingUpdateMaxSurgeDetachFailsWithMaxUnavailable(t *testing.T) {
+
+	c, cloud := getTestSetup()
+
+	failingDetach := &failingDetach{AutoScalingAPI: cloud.MockAutoscaling}
+	cloud.MockAutoscaling = failingDetach
+	cloud.MockEC2 = &ec2IgnoreTags{EC2API: cloud.MockEC2}
+
+	one := intstr.FromInt(1)
+	two := intstr.FromInt(2)
+	c.Cluster.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		MaxSurge:       &two,
+		MaxUnavailable: &one,
+	}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 0)
+	assert.Equal(t, 3, failingDetach.Count)
+}
+
+// surgeFirstTest fails the first detach and checks that no instance is terminated before another one was detached
+type surgeFirstTest struct {
+	t        *testing.T
+	mutex    sync.Mutex
+	attempts int
+	detached int
+}
+
+type surgeFirstTestAutoscaling struct {
+	autoscalingiface.AutoScalingAPI
+	SurgeFirstTest *surgeFirstTest
+}
+
+func (m *surgeFirstTestAutoscaling) DetachInstances(input *autoscaling.DetachInstancesInput) (*autoscaling.DetachInstancesOutput, error) {
+	m.SurgeFirstTest.mutex.Lock()
+	defer m.SurgeFirstTest.mutex.Unlock()
+
+	m.SurgeFirstTest.attempts++
+	if m.SurgeFirstTest.attempts == 1 {
+		return nil, errors.New("detach not supported")
+	}
+	m.SurgeFirstTest.detached += len(input.InstanceIds)
+	return &autoscaling.DetachInstancesOutput{}, nil
+}
+
+type surgeFirstTestEC2 struct {
+	ec2iface.EC2API
+	SurgeFirstTest *surgeFirstTest
+}
+
+func (m *surgeFirstTestEC2) TerminateInstances(input *ec2.TerminateInstancesInput) (*ec2.TerminateInstancesOutput, error) {
+	m.SurgeFirstTest.mutex.Lock()
+	assert.Positive(m.SurgeFirstTest.t, m.SurgeFirstTest.detached, "terminated an instance before surging")
+	m.SurgeFirstTest.mutex.Unlock()
+	return m.EC2API.TerminateInstances(input)
+}
+
+func TestRollingUpdateMaxSurgeZeroMaxUnavailableSurgesFirst(t *testing.T) {
+
+	c, cloud := getTestSetup()
+
+	surgeFirstTest := &surgeFirstTest{t: t}
+	cloud.MockAutoscaling = &surgeFirstTestAutoscaling{
+		AutoScalingAPI: cloud.MockAutoscaling,
+		SurgeFirstTest: surgeFirstTest,
+	}
+	cloud.MockEC2 = &ec2IgnoreTags{EC2API: &surgeFirstTestEC2{
+		EC2API:         cloud.MockEC2,
+		SurgeFirstTest: surgeFirstTest,
+	}}
+
+	zero := intstr.FromInt(0)
+	two := intstr.FromInt(2)
+	c.Cluster.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		MaxSurge:       &two,
+		MaxUnavailable: &zero,
+	}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 0)
+	assert.Equal(t, 3, surgeFirstTest.attempts)
+	assert.Equal(t, 2, surgeFirstTest.detached)
+}
+
 // Request validate (1)            -->
 //                                 <-- validated
 // Detach instance                 -->
@@ -0,0 +1,74 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jointoken
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+const JoinTokenAuthenticationTokenPrefix = "x-kops-join-token "
+
+// joinTokenRequest is the content of the authorization header of a request authenticated with a join token.
+type joinTokenRequest struct {
+	// Token is the join token, without its secret.
+	Token string `json:"token"`
+	// NodeName is the name the node registers with.
+	NodeName string `json:"nodeName"`
+	// Signature is the signature of the node name and request body, keyed with the token secret.
+	Signature []byte `json:"signature"`
+}
+
+type joinTokenAuthenticator struct {
+	token    *Token
+	nodeName string
+}
+
+var _ fi.Authenticator = &joinTokenAuthenticator{}
+
+// NewAuthenticator returns an authenticator for a node with the given name, using the join token of its instance group.
+func NewAuthenticator(token string, nodeName string) (fi.Authenticator, error) {
+	t, err := Parse(token)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.checkExpiry(time.Now()); err != nil {
+		return nil, fmt.Errorf("%v; issue a new one with kops toolbox join-token", err)
+	}
+	return &joinTokenAuthenticator{
+		token:    t,
+		nodeName: nodeName,
+	}, nil
+}
+
+func (a *joinTokenAuthenticator) CreateToken(body []byte) (string, error) {
+	request := joinTokenRequest{
+		Token:     a.token.id(),
+		NodeName:  a.nodeName,
+		Signature: a.token.signRequest(a.nodeName, body),
+	}
+
+	b, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+	return JoinTokenAuthenticationTokenPrefix + base64.StdEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jointoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SecretName is the name of the secret holding the key that join tokens are derived from.
+	SecretName = "kops-controller-join-token"
+	// TokenPath is where nodeup reads the join token of a machine from.
+	TokenPath = "/var/lib/kops/join-token"
+)
+
+// Token is a join token, which authorizes nodes of an instance group to bootstrap until it expires.
+// The secret of the token is derived from the key held by kops-controller, so tokens don't have to be stored.
+type Token struct {
+	// InstanceGroup is the name of the instance group whose nodes may use the token.
+	InstanceGroup string
+	// Expiry is when the token stops being valid.
+	Expiry time.Time
+	// Secret is the HMAC of the instance group and expiry, keyed with the join token key.
+	Secret []byte
+}
+
+// Issue returns a join token for the instance group which is valid until expiry.
+func Issue(key []byte, instanceGroup string, expiry time.Time) *Token {
+	t := &Token{
+		InstanceGroup: instanceGroup,
+		Expiry:        time.Unix(expiry.Unix(), 0),
+	}
+	t.Secret = t.expectedSecret(key)
+	return t
+}
+
+// Parse parses a join token in the form returned by String.
+func Parse(s string) (*Token, error) {
+	s = strings.TrimSpace(s)
+	i := strings.LastIndex(s, ".")
+	if i == -1 {
+		return nil, fmt.Errorf("join token does not have the expected format")
+	}
+
+	t, err := parseID(s[:i])
+	if err != nil {
+		return nil, err
+	}
+
+	t.Secret, err = base64.RawURLEncoding.DecodeString(s[i+1:])
+	if err != nil {
+		return nil, fmt.Errorf("join token has an invalid secret: %v", err)
+	}
+	return t, nil
+}
+
+// parseID parses a join token without its secret, in the form returned by id.
+// Instance group names may contain dots, so the expiry is the last component.
+func parseID(s string) (*Token, error) {
+	i := strings.LastIndex(s, ".")
+	if i <= 0 {
+		return nil, fmt.Errorf("join token does not have the expected format")
+	}
+
+	expiry, err := strconv.ParseInt(s[i+1:], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("join token has an invalid expiry: %v", err)
+	}
+
+	return &Token{
+		InstanceGroup: s[:i],
+		Expiry:        time.Unix(expiry, 0),
+	}, nil
+}
+
+// String returns the token as <instanceGroup>.<expiry>.<secret>.
+func (t *Token) String() string {
+	return t.id() + "." + base64.RawURLEncoding.EncodeToString(t.Secret)
+}
+
+// checkExpiry returns an error if the token has expired.
+func (t *Token) checkExpiry(now time.Time) error {
+	if !now.Before(t.Expiry) {
+		return fmt.Errorf("join token for instance group %q expired at %s", t.InstanceGroup, t.Expiry.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// id identifies the token without its secret.
+func (t *Token) id() string {
+	return t.InstanceGroup + "." + strconv.FormatInt(t.Expiry.Unix(), 10)
+}
+
+func (t *Token) expectedSecret(key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(t.id()))
+	return mac.Sum(nil)
+}
+
+// signRequest returns the signature of a bootstrap request, which proves knowledge of the token secret without sending it.
+func (t *Token) signRequest(nodeName string, body []byte) []byte {
+	sha := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, t.Secret)
+	_, _ = mac.Write([]byte(nodeName))
+	_, _ = mac.Write([]byte{0})
+	_, _ = mac.Write(sha[:])
+	return mac.Sum(nil)
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jointoken
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	expiry := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	token := Issue([]byte("key"), "nodes.us-test-1a", expiry)
+
+	parsed, err := Parse(token.String() + "\n")
+	if err != nil {
+		t.Fatalf("unexpected error parsing %q: %v", token.String(), err)
+	}
+	if parsed.InstanceGroup != "nodes.us-test-1a" {
+		t.Errorf("unexpected instance group %q", parsed.InstanceGroup)
+	}
+	if !parsed.Expiry.Equal(expiry) {
+		t.Errorf("unexpected expiry %s", parsed.Expiry)
+	}
+	if parsed.String() != token.String() {
+		t.Errorf("token %q was parsed as %q", token.String(), parsed.String())
+	}
+
+	for _, s := range []string{"", "nodes", "nodes.abc.secret", ".1893553445.secret", "nodes.1893553445.!!"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("expected error parsing %q", s)
+		}
+	}
+}
+
+func TestVerifyToken(t *testing.T) {
+	key := []byte("key")
+	now := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	body := []byte(`{"apiVersion":"bootstrap.kops.k8s.io/v1alpha1"}`)
+	nodes := fakeNodeLookup{"node-2": "nodes", "master-1": "master-us-test-1a", "unmanaged-1": ""}
+
+	grid := []struct {
+		Description   string
+		Key           []byte
+		Expiry        time.Time
+		NodeName      string
+		Body          []byte
+		ExpectedError string
+	}{
+		{
+			Description: "valid",
+			Key:         key,
+			Expiry:      now.Add(time.Hour),
+			NodeName:    "node-1",
+			Body:        body,
+		},
+		{
+			Description:   "issued with another key",
+			Key:           []byte("other"),
+			Expiry:        now.Add(time.Hour),
+			NodeName:      "node-1",
+			Body:          body,
+			ExpectedError: "incorrect signature",
+		},
+		{
+			Description:   "expired",
+			Key:           key,
+			Expiry:        now,
+			NodeName:      "node-1",
+			Body:          body,
+			ExpectedError: "expired",
+		},
+		{
+			Description:   "different body",
+			Key:           key,
+			Expiry:        now.Add(time.Hour),
+			NodeName:      "node-1",
+			Body:          []byte(`{}`),
+			ExpectedError: "incorrect signature",
+		},
+		{
+			Description: "existing node of the instance group",
+			Key:         key,
+			Expiry:      now.Add(time.Hour),
+			NodeName:    "node-2",
+			Body:        body,
+		},
+		{
+			Description:   "existing node of another instance group",
+			Key:           key,
+			Expiry:        now.Add(time.Hour),
+			NodeName:      "master-1",
+			Body:          body,
+			ExpectedError: `node "master-1" already exists outside instance group "nodes"`,
+		},
+		{
+			Description:   "existing node without instance group",
+			Key:           key,
+			Expiry:        now.Add(time.Hour),
+			NodeName:      "unmanaged-1",
+			Body:          body,
+			ExpectedError: "already exists outside instance group",
+		},
+		{
+			Description:   "invalid node name",
+			Key:           key,
+			Expiry:        now.Add(time.Hour),
+			NodeName:      "Node_1",
+			Body:          body,
+			ExpectedError: "invalid node name",
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.Description, func(t *testing.T) {
+			token := Issue(g.Key, "nodes", g.Expiry)
+			authenticator := &joinTokenAuthenticator{token: token, nodeName: g.NodeName}
+			header, err := authenticator.CreateToken(g.Body)
+			if err != nil {
+				t.Fatalf("unexpected error creating token: %v", err)
+			}
+
+			verifier := &joinTokenVerifier{key: key, nodes: nodes, now: func() time.Time { return now }}
+			result, err := verifier.VerifyToken(header, body)
+			if g.ExpectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), g.ExpectedError) {
+					t.Fatalf("expected error containing %q, got %v", g.ExpectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.NodeName != g.NodeName || result.InstanceGroupName != "nodes" {
+				t.Errorf("unexpected result %+v", result)
+			}
+		})
+	}
+}
+
+func TestVerifyTokenType(t *testing.T) {
+	verifier := NewVerifier([]byte("key"), fakeNodeLookup{})
+	if _, err := verifier.VerifyToken("x-aws-sts abc", nil); err == nil || err.Error() != "incorrect authorization type" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+// fakeNodeLookup maps the names of existing nodes to their instance group
+type fakeNodeLookup map[string]string
+
+func (f fakeNodeLookup) NodeInstanceGroup(ctx context.Context, nodeName string) (string, bool, error) {
+	instanceGroup, found := f[nodeName]
+	return instanceGroup, found, nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jointoken
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// nodeLookupTimeout bounds how long verifying a token waits for the lookup of the node
+const nodeLookupTimeout = 10 * time.Second
+
+// NodeLookup finds the instance group of existing nodes.
+type NodeLookup interface {
+	// NodeInstanceGroup returns the instance group label of the named node, and whether the node exists.
+	NodeInstanceGroup(ctx context.Context, nodeName string) (string, bool, error)
+}
+
+type joinTokenVerifier struct {
+	key   []byte
+	nodes NodeLookup
+	now   func() time.Time
+}
+
+var _ fi.Verifier = &joinTokenVerifier{}
+
+// NewVerifier returns a verifier of requests authenticated with join tokens issued with the key.
+// The node name of a request is chosen by the node, so the verifier rejects the names of existing nodes
+// that are not in the instance group of the token, e.g. those of the control plane.
+func NewVerifier(key []byte, nodes NodeLookup) fi.Verifier {
+	return &joinTokenVerifier{
+		key:   key,
+		nodes: nodes,
+		now:   time.Now,
+	}
+}
+
+// ReadKey reads the key that join tokens are issued with from the file that nodeup writes it to.
+func ReadKey(path string) ([]byte, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading join token key from %q: %v", path, err)
+	}
+	key := bytes.TrimSpace(b)
+	if len(key) == 0 {
+		return nil, fmt.Errorf("join token key in %q is empty", path)
+	}
+	return key, nil
+}
+
+func (v *joinTokenVerifier) VerifyToken(token string, body []byte) (*fi.VerifyResult, error) {
+	if !strings.HasPrefix(token, JoinTokenAuthenticationTokenPrefix) {
+		return nil, fmt.Errorf("incorrect authorization type")
+	}
+	token = strings.TrimPrefix(token, JoinTokenAuthenticationTokenPrefix)
+
+	b, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("decoding authorization token: %v", err)
+	}
+	request := joinTokenRequest{}
+	if err := json.Unmarshal(b, &request); err != nil {
+		return nil, fmt.Errorf("unmarshalling authorization token: %v", err)
+	}
+
+	t, err := parseID(request.Token)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.checkExpiry(v.now()); err != nil {
+		return nil, err
+	}
+	// The secret is not sent, so a token we did not issue yields a different signature
+	t.Secret = t.expectedSecret(v.key)
+
+	// The node name goes into the certificates we issue, so it must be a valid name
+	if errs := validation.IsDNS1123Subdomain(request.NodeName); len(errs) != 0 {
+		return nil, fmt.Errorf("invalid node name %q: %s", request.NodeName, strings.Join(errs, ", "))
+	}
+
+	if !hmac.Equal(request.Signature, t.signRequest(request.NodeName, body)) {
+		return nil, fmt.Errorf("incorrect signature for join token of instance group %q", t.InstanceGroup)
+	}
+
+	if err := v.checkNodeName(request.NodeName, t.InstanceGroup); err != nil {
+		return nil, err
+	}
+
+	return &fi.VerifyResult{
+		NodeName:          request.NodeName,
+		InstanceGroupName: t.InstanceGroup,
+	}, nil
+}
+
+// checkNodeName returns an error if a node with the name exists outside the instance group, as the certificates
+// issued to the request would let its holder act as that node.
+func (v *joinTokenVerifier) checkNodeName(nodeName string, instanceGroup string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), nodeLookupTimeout)
+	defer cancel()
+
+	nodeInstanceGroup, found, err := v.nodes.NodeInstanceGroup(ctx, nodeName)
+	if err != nil {
+		return fmt.Errorf("looking up node %q: %v", nodeName, err)
+	}
+	if found && nodeInstanceGroup != instanceGroup {
+		return fmt.Errorf("node %q already exists outside instance group %q of the join token", nodeName, instanceGroup)
+	}
+	return nil
+}
@@ -204,3 +204,28 @@ func BuildKubecfg(cluster *kops.Cluster, keyStore fi.Keystore, secretStore fi.Se
 
 	return b, nil
 }
+
+// BuildOIDCAuthenticationExec returns the command line of a kubelogin (kubectl oidc-login) exec credential plugin
+// for the OIDC issuer and client configured on the cluster's API server.
+func BuildOIDCAuthenticationExec(cluster *kops.Cluster, clientSecret string, extraScopes []string) ([]string, error) {
+	apiServer := cluster.Spec.KubeAPIServer
+	if apiServer == nil || fi.StringValue(apiServer.OIDCIssuerURL) == "" || fi.StringValue(apiServer.OIDCClientID) == "" {
+		return nil, fmt.Errorf("cluster %q does not have kubeAPIServer.oidcIssuerURL and kubeAPIServer.oidcClientID set", cluster.ObjectMeta.Name)
+	}
+
+	exec := []string{
+		"kubectl",
+		"oidc-login",
+		"get-token",
+		"--oidc-issuer-url=" + fi.StringValue(apiServer.OIDCIssuerURL),
+		"--oidc-client-id=" + fi.StringValue(apiServer.OIDCClientID),
+	}
+	if clientSecret != "" {
+		exec = append(exec, "--oidc-client-secret="+clientSecret)
+	}
+	for _, scope := range extraScopes {
+		exec = append(exec, "--oidc-extra-scope="+scope)
+	}
+
+	return exec, nil
+}
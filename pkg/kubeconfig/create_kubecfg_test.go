@@ -379,3 +379,32 @@ func TestBuildKubecfg(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildOIDCAuthenticationExec(t *testing.T) {
+	cluster := buildMinimalCluster("testcluster", "testcluster.test.com", false, false)
+	if _, err := BuildOIDCAuthenticationExec(cluster, "", nil); err == nil {
+		t.Errorf("expected error for cluster without OIDC configuration")
+	}
+
+	cluster.Spec.KubeAPIServer = &kops.KubeAPIServerConfig{
+		OIDCIssuerURL: fi.String("https://issuer.example.com"),
+		OIDCClientID:  fi.String("kubernetes"),
+	}
+	got, err := BuildOIDCAuthenticationExec(cluster, "secret", []string{"email", "groups"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		"kubectl",
+		"oidc-login",
+		"get-token",
+		"--oidc-issuer-url=https://issuer.example.com",
+		"--oidc-client-id=kubernetes",
+		"--oidc-client-secret=secret",
+		"--oidc-extra-scope=email",
+		"--oidc-extra-scope=groups",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("BuildOIDCAuthenticationExec() diff (+got, -want): %s", diff)
+	}
+}
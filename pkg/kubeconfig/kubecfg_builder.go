@@ -18,6 +18,7 @@ package kubeconfig
 
 import (
 	"fmt"
+	"sort"
 
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -41,7 +42,8 @@ type KubeconfigBuilder struct {
 	ClientCert []byte
 	ClientKey  []byte
 
-	AuthenticationExec []string
+	AuthenticationExec    []string
+	AuthenticationExecEnv map[string]string
 }
 
 // Create new KubeconfigBuilder
@@ -150,6 +152,24 @@ func (b *KubeconfigBuilder) WriteKubecfg(configAccess clientcmd.ConfigAccess) er
 				Args:       b.AuthenticationExec[1:],
 			}
 
+			var names []string
+			for name := range b.AuthenticationExecEnv {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				authInfo.Exec.Env = append(authInfo.Exec.Env, clientcmdapi.ExecEnvVar{
+					Name:  name,
+					Value: b.AuthenticationExecEnv[name],
+				})
+			}
+
+			// Don't leave a long-lived client certificate from an earlier export next to the plugin
+			if b.ClientCert == nil {
+				authInfo.ClientCertificateData = nil
+				authInfo.ClientKeyData = nil
+			}
+
 			haveUserInfo = true
 		}
 
@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestWriteKubecfgExec(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	if err != nil {
+		t.Fatalf("unexpected error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	pathOptions := clientcmd.NewDefaultPathOptions()
+	pathOptions.GlobalFile = filepath.Join(dir, "config")
+	pathOptions.EnvVar = ""
+	pathOptions.GlobalFileSubpath = ""
+
+	// An earlier export with a client certificate
+	b := &KubeconfigBuilder{
+		Context:    "testcluster",
+		User:       "testcluster",
+		Server:     "https://api.testcluster",
+		ClientCert: []byte(certData),
+		ClientKey:  []byte(privatekeyData),
+	}
+	if err := b.WriteKubecfg(pathOptions); err != nil {
+		t.Fatalf("unexpected error writing kubeconfig: %v", err)
+	}
+
+	b = &KubeconfigBuilder{
+		Context:            "testcluster",
+		User:               "testcluster",
+		Server:             "https://api.testcluster",
+		AuthenticationExec: []string{"aws-iam-authenticator", "token", "-i", "testcluster"},
+		AuthenticationExecEnv: map[string]string{
+			"B": "2",
+			"A": "1",
+		},
+	}
+	if err := b.WriteKubecfg(pathOptions); err != nil {
+		t.Fatalf("unexpected error writing kubeconfig: %v", err)
+	}
+
+	config, err := clientcmd.LoadFromFile(pathOptions.GlobalFile)
+	if err != nil {
+		t.Fatalf("unexpected error reading kubeconfig: %v", err)
+	}
+	authInfo := config.AuthInfos["testcluster"]
+	if authInfo == nil {
+		t.Fatalf("user testcluster not found")
+	}
+	if authInfo.ClientCertificateData != nil || authInfo.ClientKeyData != nil {
+		t.Errorf("expected client certificate to be removed")
+	}
+	want := &clientcmdapi.ExecConfig{
+		APIVersion: "client.authentication.k8s.io/v1beta1",
+		Command:    "aws-iam-authenticator",
+		Args:       []string{"token", "-i", "testcluster"},
+		Env: []clientcmdapi.ExecEnvVar{
+			{Name: "A", Value: "1"},
+			{Name: "B", Value: "2"},
+		},
+	}
+	if diff := cmp.Diff(authInfo.Exec, want); diff != "" {
+		t.Errorf("unexpected exec config (+got, -want): %s", diff)
+	}
+}
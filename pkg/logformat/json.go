@@ -0,0 +1,170 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logformat
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// jsonLogger is a logr.Logger writing one JSON object per line.
+// Verbosity is filtered by klog before messages reach the logger.
+type jsonLogger struct {
+	out   *lockedWriter
+	name  string
+	level int
+	// values holds the key/value pairs added with WithValues
+	values []interface{}
+	now    func() time.Time
+}
+
+var _ logr.Logger = &jsonLogger{}
+
+type lockedWriter struct {
+	mutex sync.Mutex
+	w     io.Writer
+}
+
+func newJSONLogger(w io.Writer) *jsonLogger {
+	return &jsonLogger{
+		out: &lockedWriter{w: w},
+		now: time.Now,
+	}
+}
+
+func (l *jsonLogger) Enabled() bool {
+	return true
+}
+
+func (l *jsonLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.write("info", nil, msg, keysAndValues)
+}
+
+func (l *jsonLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.write("error", err, msg, keysAndValues)
+}
+
+func (l *jsonLogger) V(level int) logr.Logger {
+	c := *l
+	c.level += level
+	return &c
+}
+
+func (l *jsonLogger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	return l.withValues(keysAndValues)
+}
+
+// withValues returns a copy of the logger with the key/value pairs added; existing keys are replaced.
+func (l *jsonLogger) withValues(keysAndValues []interface{}) *jsonLogger {
+	c := *l
+	c.values = append([]interface{}{}, l.values...)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		replaced := false
+		for j := 0; j+1 < len(c.values); j += 2 {
+			if c.values[j] == keysAndValues[i] {
+				c.values[j+1] = keysAndValues[i+1]
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			c.values = append(c.values, keysAndValues[i], keysAndValues[i+1])
+		}
+	}
+	return &c
+}
+
+func (l *jsonLogger) WithName(name string) logr.Logger {
+	c := *l
+	if c.name == "" {
+		c.name = name
+	} else {
+		c.name += "/" + name
+	}
+	return &c
+}
+
+func (l *jsonLogger) write(level string, err error, msg string, keysAndValues []interface{}) {
+	var b bytes.Buffer
+	b.WriteString("{")
+	writeField(&b, "ts", l.now().UTC().Format(time.RFC3339Nano))
+	writeField(&b, "level", level)
+	if l.level != 0 {
+		writeField(&b, "v", l.level)
+	}
+	if l.name != "" {
+		writeField(&b, "logger", l.name)
+	}
+	// klog passes formatted messages with a trailing newline
+	writeField(&b, "msg", strings.TrimSuffix(msg, "\n"))
+	if err != nil {
+		writeField(&b, "error", err.Error())
+	}
+	writeKeysAndValues(&b, l.values)
+	writeKeysAndValues(&b, keysAndValues)
+	b.WriteString("}\n")
+
+	l.out.mutex.Lock()
+	defer l.out.mutex.Unlock()
+	l.out.w.Write(b.Bytes())
+}
+
+func writeKeysAndValues(b *bytes.Buffer, keysAndValues []interface{}) {
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprintf("%v", keysAndValues[i])
+		}
+		var value interface{}
+		if i+1 < len(keysAndValues) {
+			value = keysAndValues[i+1]
+		}
+		writeField(b, key, value)
+	}
+}
+
+func writeField(b *bytes.Buffer, key string, value interface{}) {
+	if b.Len() > 1 {
+		b.WriteString(",")
+	}
+	k, _ := json.Marshal(key)
+	b.Write(k)
+	b.WriteString(":")
+
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		value = nil
+	}
+	switch v := value.(type) {
+	case error:
+		value = v.Error()
+	case fmt.Stringer:
+		value = v.String()
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprintf("%+v", value))
+	}
+	b.Write(data)
+}
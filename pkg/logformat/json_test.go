@@ -0,0 +1,47 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logformat
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestJSONLogger(t *testing.T) {
+	var out bytes.Buffer
+	l := newJSONLogger(&out)
+	l.now = func() time.Time {
+		return time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	}
+
+	withCluster := l.withValues([]interface{}{"cluster", "old.example.com"}).withValues([]interface{}{"cluster", "minimal.example.com"})
+	withCluster.Info("Executing task\n", "task", "VPC/minimal.example.com", "duration", 1500*time.Millisecond)
+	withCluster.V(2).WithName("rolling-update").Info("validating", "instancegroup", "nodes")
+	l.Error(errors.New("boom"), "error running task", "task", "Subnet/a", "attempt", 3)
+	l.Info("odd", "key")
+
+	expected := `{"ts":"2021-06-01T12:00:00Z","level":"info","msg":"Executing task","cluster":"minimal.example.com","task":"VPC/minimal.example.com","duration":"1.5s"}
+{"ts":"2021-06-01T12:00:00Z","level":"info","v":2,"logger":"rolling-update","msg":"validating","cluster":"minimal.example.com","instancegroup":"nodes"}
+{"ts":"2021-06-01T12:00:00Z","level":"error","msg":"error running task","error":"boom","task":"Subnet/a","attempt":3}
+{"ts":"2021-06-01T12:00:00Z","level":"info","msg":"odd","key":null}
+`
+	if out.String() != expected {
+		t.Errorf("unexpected output\nactual:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
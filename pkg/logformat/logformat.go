@@ -0,0 +1,97 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logformat
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"k8s.io/klog/v2"
+	"k8s.io/klog/v2/klogr"
+)
+
+const (
+	// FormatText is the default klog text format.
+	FormatText = "text"
+	// FormatJSON writes one JSON object per log line.
+	FormatJSON = "json"
+
+	// EnvVar sets the log format when --log-format is not specified.
+	EnvVar = "KOPS_LOG_FORMAT"
+)
+
+var (
+	mutex  sync.Mutex
+	format = FormatText
+	logger *jsonLogger
+)
+
+// InitFlags registers the --log-format flag on the flagset; if flagset is nil, flag.CommandLine is used.
+// The default is taken from the KOPS_LOG_FORMAT environment variable.
+func InitFlags(flagset *flag.FlagSet) {
+	if flagset == nil {
+		flagset = flag.CommandLine
+	}
+	if s := os.Getenv(EnvVar); s != "" {
+		format = s
+	}
+	flagset.StringVar(&format, "log-format", format, fmt.Sprintf("Log output format: %s or %s. Overrides %s environment variable", FormatText, FormatJSON, EnvVar))
+}
+
+// Apply configures klog for the selected format. It should be called once flags have been parsed.
+func Apply() error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	switch format {
+	case "", FormatText:
+		return nil
+	case FormatJSON:
+		logger = newJSONLogger(os.Stderr)
+		klog.SetLogger(logger)
+		return nil
+	default:
+		return fmt.Errorf("unknown log format %q, must be %s or %s", format, FormatText, FormatJSON)
+	}
+}
+
+// WithValues adds fields, such as the cluster or instance group, to every subsequent log line.
+// It has no effect on the text format.
+func WithValues(keysAndValues ...interface{}) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if logger == nil {
+		return
+	}
+	logger = logger.withValues(keysAndValues)
+	klog.SetLogger(logger)
+}
+
+// Logger returns a logr.Logger for the selected format, for libraries such as controller-runtime.
+func Logger() logr.Logger {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if logger == nil {
+		return klogr.New()
+	}
+	return logger
+}
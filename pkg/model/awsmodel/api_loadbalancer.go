@@ -25,6 +25,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/dns"
+	"k8s.io/kops/pkg/wellknownports"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
 	"k8s.io/kops/upup/pkg/fi/utils"
@@ -157,6 +158,13 @@ func (b *APILoadBalancerBuilder) Build(c *fi.ModelBuilderContext) error {
 			nlbListeners = append(nlbListeners, nlbListener)
 		}
 
+		if b.UseLoadBalancerForExternalNodes() {
+			nlbListeners = append(nlbListeners, &awstasks.NetworkLoadBalancerListener{
+				Port:            wellknownports.KopsControllerPort,
+				TargetGroupName: b.NLBTargetGroupName("kops-controller"),
+			})
+		}
+
 		if lbSpec.SecurityGroupOverride != nil {
 			klog.V(1).Infof("WARNING: You are overwriting the Load Balancers, Security Group. When this is done you are responsible for ensure the correct rules!")
 		}
@@ -277,8 +285,34 @@ func (b *APILoadBalancerBuilder) Build(c *fi.ModelBuilderContext) error {
 				c.AddTask(secondaryTG)
 				nlb.TargetGroups = append(nlb.TargetGroups, secondaryTG)
 			}
+
+			if b.UseLoadBalancerForExternalNodes() {
+				// Instance groups using the external API endpoint reach kops-controller through the load balancer
+				kopsControllerGroupName := b.NLBTargetGroupName("kops-controller")
+				kopsControllerGroupTags := b.CloudTags(kopsControllerGroupName, false)
+
+				// Override the returned name to be the expected NLB TG name
+				kopsControllerGroupTags["Name"] = kopsControllerGroupName
+				kopsControllerTG := &awstasks.TargetGroup{
+					Name:               fi.String(kopsControllerGroupName),
+					Lifecycle:          b.Lifecycle,
+					VPC:                b.LinkToVPC(),
+					Tags:               kopsControllerGroupTags,
+					Protocol:           fi.String("TCP"),
+					Port:               fi.Int64(wellknownports.KopsControllerPort),
+					HealthyThreshold:   fi.Int64(2),
+					UnhealthyThreshold: fi.Int64(2),
+					Shared:             fi.Bool(false),
+				}
+				c.AddTask(kopsControllerTG)
+				nlb.TargetGroups = append(nlb.TargetGroups, kopsControllerTG)
+			}
 			sort.Stable(awstasks.OrderTargetGroupsByName(nlb.TargetGroups))
 			c.AddTask(nlb)
+
+			if lbSpec.PrivateLink != nil {
+				b.addPrivateLink(c, nlb, lbSpec.PrivateLink)
+			}
 		}
 
 	}
@@ -437,6 +471,24 @@ func (b *APILoadBalancerBuilder) Build(c *fi.ModelBuilderContext) error {
 					}
 					c.AddTask(t)
 				}
+
+				if b.UseLoadBalancerForExternalNodes() {
+					// Allow access to kops-controller through NLB
+					t := &awstasks.SecurityGroupRule{
+						Name:          fi.String(fmt.Sprintf("kops-controller-elb-%s", cidr)),
+						Lifecycle:     b.SecurityLifecycle,
+						FromPort:      fi.Int64(wellknownports.KopsControllerPort),
+						Protocol:      fi.String("tcp"),
+						SecurityGroup: masterGroup.Task,
+						ToPort:        fi.Int64(wellknownports.KopsControllerPort),
+					}
+					if utils.IsIPv6CIDR(cidr) {
+						t.IPv6CIDR = fi.String(cidr)
+					} else {
+						t.CIDR = fi.String(cidr)
+					}
+					c.AddTask(t)
+				}
 			}
 		}
 	}
@@ -494,6 +546,19 @@ func (b *APILoadBalancerBuilder) Build(c *fi.ModelBuilderContext) error {
 					CIDR:          fi.String(cidr),
 				})
 			}
+
+			if b.UseLoadBalancerForExternalNodes() {
+				// Allow the health checks of the kops-controller target group
+				c.AddTask(&awstasks.SecurityGroupRule{
+					Name:          fi.String(fmt.Sprintf("kops-controller-elb-to-master%s", suffix)),
+					Lifecycle:     b.SecurityLifecycle,
+					FromPort:      fi.Int64(wellknownports.KopsControllerPort),
+					Protocol:      fi.String("tcp"),
+					SecurityGroup: masterGroup.Task,
+					ToPort:        fi.Int64(wellknownports.KopsControllerPort),
+					CIDR:          fi.String(b.Cluster.Spec.NetworkCIDR),
+				})
+			}
 		}
 	}
 
@@ -529,6 +594,33 @@ func (a ByScoreDescending) Less(i, j int) bool {
 // We have already applied the rules to match internal subnets to internal ELBs and vice-versa for public-facing ELBs.
 // For internal ELBs: we prefer the master subnets
 // For public facing ELBs: we prefer the utility subnets
+// addPrivateLink publishes the API load balancer as a VPC endpoint service, with an endpoint in each of the listed consumer VPCs
+func (b *APILoadBalancerBuilder) addPrivateLink(c *fi.ModelBuilderContext, nlb *awstasks.NetworkLoadBalancer, spec *kops.PrivateLinkSpec) {
+	serviceName := "api." + b.ClusterName()
+	service := &awstasks.VPCEndpointService{
+		Name:                fi.String(serviceName),
+		Lifecycle:           b.Lifecycle,
+		NetworkLoadBalancer: nlb,
+		AcceptanceRequired:  fi.Bool(fi.BoolValue(spec.AcceptanceRequired)),
+		AllowedPrincipals:   spec.AllowedPrincipals,
+		Tags:                b.CloudTags(serviceName, false),
+	}
+	c.AddTask(service)
+
+	for _, endpoint := range spec.Endpoints {
+		endpointName := "api-" + endpoint.VPCID + "." + b.ClusterName()
+		c.AddTask(&awstasks.VPCEndpoint{
+			Name:             fi.String(endpointName),
+			Lifecycle:        b.Lifecycle,
+			VPCID:            fi.String(endpoint.VPCID),
+			Service:          service,
+			SubnetIDs:        endpoint.SubnetIDs,
+			SecurityGroupIDs: endpoint.SecurityGroupIDs,
+			Tags:             b.CloudTags(endpointName, false),
+		})
+	}
+}
+
 func (b *APILoadBalancerBuilder) chooseBestSubnetForELB(zone string, subnets []*kops.ClusterSubnetSpec) *kops.ClusterSubnetSpec {
 	if len(subnets) == 0 {
 		return nil
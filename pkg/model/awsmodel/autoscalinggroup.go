@@ -122,6 +122,36 @@ func (b *AutoscalingGroupModelBuilder) Build(c *fi.ModelBuilderContext) error {
 			}
 			c.AddTask(warmPoolTask)
 		}
+
+		{
+			scheduleTask := &awstasks.AutoscalingSchedule{
+				Name:             &name,
+				Lifecycle:        b.Lifecycle,
+				AutoscalingGroup: b.LinkToAutoscalingGroup(ig),
+			}
+			for _, schedule := range ig.Spec.ScalingSchedules {
+				timeZone := schedule.TimeZone
+				if timeZone == "" {
+					timeZone = "UTC"
+				}
+				action := &awstasks.ScheduledAction{
+					Name:       fi.String(awstasks.ScheduledActionPrefix + schedule.Name),
+					Recurrence: fi.String(schedule.Schedule),
+					TimeZone:   fi.String(timeZone),
+				}
+				if schedule.MinSize != nil {
+					action.MinSize = fi.Int64(int64(*schedule.MinSize))
+				}
+				if schedule.MaxSize != nil {
+					action.MaxSize = fi.Int64(int64(*schedule.MaxSize))
+				}
+				scheduleTask.Actions = append(scheduleTask.Actions, action)
+			}
+			sort.Slice(scheduleTask.Actions, func(i, j int) bool {
+				return fi.StringValue(scheduleTask.Actions[i].Name) < fi.StringValue(scheduleTask.Actions[j].Name)
+			})
+			c.AddTask(scheduleTask)
+		}
 	}
 
 	return nil
@@ -172,6 +202,10 @@ func (b *AutoscalingGroupModelBuilder) buildLaunchTemplateTask(c *fi.ModelBuilde
 	if err != nil {
 		return nil, err
 	}
+	if ig.UsesSSHBootstrap() {
+		// The bootstrap script is still built, as kops toolbox bootstrap runs it on the instances over SSH
+		userData = nil
+	}
 
 	lt := &awstasks.LaunchTemplate{
 		Name:                         fi.String(name),
@@ -397,6 +431,9 @@ func (b *AutoscalingGroupModelBuilder) buildAutoScalingGroupTask(c *fi.ModelBuil
 
 	t.MinSize = minSize
 	t.MaxSize = maxSize
+	if len(ig.Spec.ScalingSchedules) > 0 {
+		t.ScheduledScaling = fi.Bool(true)
+	}
 
 	subnets, err := b.GatherSubnets(ig)
 	if err != nil {
@@ -435,6 +472,9 @@ func (b *AutoscalingGroupModelBuilder) buildAutoScalingGroupTask(c *fi.ModelBuil
 				if b.Cluster.Spec.API.LoadBalancer.SSLCertificate != "" {
 					t.TargetGroups = append(t.TargetGroups, b.LinkToTargetGroup("tls"))
 				}
+				if b.UseLoadBalancerForExternalNodes() && ig.IsMaster() {
+					t.TargetGroups = append(t.TargetGroups, b.LinkToTargetGroup("kops-controller"))
+				}
 			} else {
 				t.LoadBalancers = append(t.LoadBalancers, b.LinkToCLB("api"))
 			}
@@ -220,3 +220,81 @@ func TestAPIServerAdditionalSecurityGroupsWithNLB(t *testing.T) {
 		})
 	}
 }
+
+func TestExternalAPIServerEndpointTargetGroup(t *testing.T) {
+	cluster := buildMinimalCluster()
+	cluster.Spec.KubernetesVersion = "1.21.0"
+	cluster.Spec.API = &kops.AccessSpec{
+		LoadBalancer: &kops.LoadBalancerAccessSpec{
+			Class: kops.LoadBalancerClassNetwork,
+			Type:  kops.LoadBalancerTypePublic,
+		},
+	}
+
+	subnets := []string{cluster.Spec.Subnets[0].Name}
+	master := &kops.InstanceGroup{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "master1",
+		},
+		Spec: kops.InstanceGroupSpec{
+			Role:    kops.InstanceGroupRoleMaster,
+			Subnets: subnets,
+		},
+	}
+	edge := &kops.InstanceGroup{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "edge",
+		},
+		Spec: kops.InstanceGroupSpec{
+			Role:    kops.InstanceGroupRoleNode,
+			Subnets: subnets,
+			APIServerEndpoint: &kops.APIServerEndpointSpec{
+				Type: kops.APIServerEndpointTypeExternal,
+			},
+		},
+	}
+
+	b := AutoscalingGroupModelBuilder{
+		AWSModelContext: &AWSModelContext{
+			KopsModelContext: &model.KopsModelContext{
+				IAMModelContext: iam.IAMModelContext{Cluster: cluster},
+				SSHPublicKeys:   [][]byte{[]byte(sshPublicKeyEntry)},
+				InstanceGroups:  []*kops.InstanceGroup{master, edge},
+			},
+		},
+		Cluster: cluster,
+	}
+
+	c := &fi.ModelBuilderContext{
+		Tasks: make(map[string]fi.Task),
+	}
+
+	// We need the CA for the bootstrap script
+	caTask := &fitasks.Keypair{
+		Name:    fi.String(fi.CertificateIDCA),
+		Subject: "cn=kubernetes",
+		Type:    "ca",
+	}
+	c.AddTask(caTask)
+
+	if err := b.Build(c); err != nil {
+		t.Fatalf("error from Build: %v", err)
+	}
+
+	kopsControllerGroupName := b.NLBTargetGroupName("kops-controller")
+	hasKopsControllerTargetGroup := func(name string) bool {
+		asg := c.Tasks["AutoscalingGroup/"+name].(*awstasks.AutoscalingGroup)
+		for _, tg := range asg.TargetGroups {
+			if fi.StringValue(tg.Name) == kopsControllerGroupName {
+				return true
+			}
+		}
+		return false
+	}
+	if !hasKopsControllerTargetGroup("master1.masters." + cluster.Name) {
+		t.Errorf("expected the control plane to be attached to target group %q", kopsControllerGroupName)
+	}
+	if hasKopsControllerTargetGroup("edge." + cluster.Name) {
+		t.Errorf("expected the nodes not to be attached to target group %q", kopsControllerGroupName)
+	}
+}
@@ -59,6 +59,9 @@ func (b *ExternalAccessModelBuilder) Build(c *fi.ModelBuilderContext) error {
 		// This is admittedly a little odd... adding a bastion shuts down direct access to the masters/nodes
 		// But I think we can always add more permissions in this case later, but we can't easily take them away
 		klog.V(2).Infof("bastion is in use; won't configure SSH access to master / node instances")
+	} else if b.Cluster.Spec.Topology.GetInstanceAccessType() == kops.InstanceAccessTypeSSM {
+		// Session Manager sessions are opened by the SSM agent over an outbound connection
+		klog.V(2).Infof("SSM instance access is in use; won't configure SSH access to master / node instances")
 	} else {
 		for _, sshAccess := range b.Cluster.Spec.SSHAccess {
 			for _, masterGroup := range masterGroups {
@@ -0,0 +1,99 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awsmodel
+
+import (
+	"testing"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/pkg/model/iam"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
+)
+
+func TestExternalAccessInstanceAccess(t *testing.T) {
+	grid := []struct {
+		name        string
+		access      *kops.InstanceAccessSpec
+		expectedSSH int
+	}{
+		{
+			name:        "default",
+			expectedSSH: 2,
+		},
+		{
+			name:        "ec2-instance-connect",
+			access:      &kops.InstanceAccessSpec{Type: kops.InstanceAccessTypeEC2InstanceConnect},
+			expectedSSH: 2,
+		},
+		{
+			name:        "ssm",
+			access:      &kops.InstanceAccessSpec{Type: kops.InstanceAccessTypeSSM},
+			expectedSSH: 0,
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			cluster := buildMinimalCluster()
+			cluster.Spec.SSHAccess = []string{"0.0.0.0/0"}
+			cluster.Spec.Topology = &kops.TopologySpec{
+				Masters:        kops.TopologyPublic,
+				Nodes:          kops.TopologyPublic,
+				InstanceAccess: g.access,
+			}
+
+			subnets := []string{cluster.Spec.Subnets[0].Name}
+			b := ExternalAccessModelBuilder{
+				AWSModelContext: &AWSModelContext{
+					KopsModelContext: &model.KopsModelContext{
+						IAMModelContext: iam.IAMModelContext{Cluster: cluster},
+						InstanceGroups: []*kops.InstanceGroup{
+							{
+								ObjectMeta: v1.ObjectMeta{Name: "master"},
+								Spec:       kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleMaster, Subnets: subnets},
+							},
+							{
+								ObjectMeta: v1.ObjectMeta{Name: "nodes"},
+								Spec:       kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleNode, Subnets: subnets},
+							},
+						},
+					},
+				},
+			}
+
+			c := &fi.ModelBuilderContext{
+				Tasks: make(map[string]fi.Task),
+			}
+			if err := b.Build(c); err != nil {
+				t.Fatalf("error from Build: %v", err)
+			}
+
+			ssh := 0
+			for _, task := range c.Tasks {
+				if rule, ok := task.(*awstasks.SecurityGroupRule); ok && fi.Int64Value(rule.FromPort) == 22 {
+					ssh++
+				}
+			}
+			if ssh != g.expectedSSH {
+				t.Errorf("expected %d SSH rules, got %d", g.expectedSSH, ssh)
+			}
+		})
+	}
+}
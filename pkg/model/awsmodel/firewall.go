@@ -31,6 +31,7 @@ type Protocol int
 
 const (
 	ProtocolIPIP Protocol = 4
+	ProtocolGRE  Protocol = 47
 )
 
 // FirewallModelBuilder configures firewall network objects
@@ -149,6 +150,10 @@ func (b *FirewallModelBuilder) applyNodeToMasterBlockSpecificPorts(c *fi.ModelBu
 		protocols = append(protocols, ProtocolIPIP)
 	}
 
+	if b.Cluster.Spec.Networking.Antrea != nil && b.Cluster.Spec.Networking.Antrea.TunnelType == "gre" {
+		protocols = append(protocols, ProtocolGRE)
+	}
+
 	tcpRanges := []portRange{
 		{From: 1, To: 0},
 	}
@@ -197,6 +202,8 @@ func (b *FirewallModelBuilder) applyNodeToMasterBlockSpecificPorts(c *fi.ModelBu
 				switch protocol {
 				case ProtocolIPIP:
 					name = "ipip"
+				case ProtocolGRE:
+					name = "gre"
 				default:
 					klog.Warningf("unknown protocol %q - naming by number", awsName)
 				}
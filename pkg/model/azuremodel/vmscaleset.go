@@ -18,6 +18,7 @@ package azuremodel
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
@@ -26,6 +27,7 @@ import (
 	"k8s.io/kops/pkg/model"
 	"k8s.io/kops/pkg/model/defaults"
 	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
 	"k8s.io/kops/upup/pkg/fi/cloudup/azuretasks"
 )
 
@@ -132,11 +134,56 @@ func (b *VMScaleSetModelBuilder) buildVMScaleSetTask(
 		}
 	}
 
+	for _, zone := range ig.Spec.Zones {
+		n, err := azure.ZoneToAvailabilityZoneNumber(zone)
+		if err != nil {
+			return nil, err
+		}
+		t.Zones = append(t.Zones, n)
+	}
+
+	if ig.Spec.MaxPrice != nil {
+		if err := setSpotPriority(t, &ig.Spec); err != nil {
+			return nil, err
+		}
+	}
+
+	if fi.BoolValue(ig.Spec.AcceleratedNetworking) {
+		t.AcceleratedNetworking = fi.Bool(true)
+	}
+
 	t.Tags = b.CloudTagsForInstanceGroup(ig)
 
 	return t, nil
 }
 
+// setSpotPriority configures the VM Scale Set to run Spot virtual machines, mapping the
+// spot related fields of the InstanceGroup to their Azure equivalents.
+func setSpotPriority(t *azuretasks.VMScaleSet, spec *kops.InstanceGroupSpec) error {
+	t.Priority = fi.String(string(compute.Spot))
+
+	// An empty max price pays up to the on-demand price, as with AWS.
+	maxPrice := -1.0
+	if s := fi.StringValue(spec.MaxPrice); s != "" {
+		var err error
+		if maxPrice, err = strconv.ParseFloat(s, 64); err != nil {
+			return fmt.Errorf("invalid maxPrice %q: %v", s, err)
+		}
+	}
+	t.MaxPrice = fi.Float64(maxPrice)
+
+	switch fi.StringValue(spec.InstanceInterruptionBehavior) {
+	case "", "terminate":
+		t.EvictionPolicy = fi.String(string(compute.Delete))
+	case "stop":
+		t.EvictionPolicy = fi.String(string(compute.Deallocate))
+	default:
+		return fmt.Errorf("unsupported instanceInterruptionBehavior %q for Azure spot instances", *spec.InstanceInterruptionBehavior)
+	}
+
+	return nil
+}
+
 func getCapacity(spec *kops.InstanceGroupSpec) (*int64, error) {
 	// Follow the convention that all other CSPs have.
 	minSize := int32(1)
@@ -181,7 +228,7 @@ func getStorageProfile(spec *kops.InstanceGroupSpec) (*compute.VirtualMachineSca
 		return nil, err
 	}
 
-	return &compute.VirtualMachineScaleSetStorageProfile{
+	profile := &compute.VirtualMachineScaleSetStorageProfile{
 		ImageReference: imageReference,
 		OsDisk: &compute.VirtualMachineScaleSetOSDisk{
 			// TODO(kenji): Support Windows.
@@ -193,7 +240,17 @@ func getStorageProfile(spec *kops.InstanceGroupSpec) (*compute.VirtualMachineSca
 			},
 			Caching: compute.CachingTypes(compute.HostCachingReadWrite),
 		},
-	}, nil
+	}
+
+	if fi.BoolValue(spec.EphemeralOSDisk) {
+		// Ephemeral OS disks are placed on the VM cache or temporary disk, and require read-only caching.
+		profile.OsDisk.Caching = compute.CachingTypes(compute.HostCachingReadOnly)
+		profile.OsDisk.DiffDiskSettings = &compute.DiffDiskSettings{
+			Option: compute.Local,
+		}
+	}
+
+	return profile, nil
 }
 
 func parseImage(image string) (*compute.ImageReference, error) {
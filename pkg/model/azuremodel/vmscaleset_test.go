@@ -26,6 +26,7 @@ import (
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/model/defaults"
 	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azuretasks"
 	"k8s.io/kops/upup/pkg/fi/fitasks"
 )
 
@@ -161,6 +162,34 @@ func TestGetStorageProfile(t *testing.T) {
 				},
 			},
 		},
+		{
+			spec: kops.InstanceGroupSpec{
+				Image:           "Canonical:UbuntuServer:18.04-LTS:latest",
+				Role:            kops.InstanceGroupRoleNode,
+				RootVolumeSize:  fi.Int32(30),
+				EphemeralOSDisk: fi.Bool(true),
+			},
+			profile: &compute.VirtualMachineScaleSetStorageProfile{
+				ImageReference: &compute.ImageReference{
+					Publisher: to.StringPtr("Canonical"),
+					Offer:     to.StringPtr("UbuntuServer"),
+					Sku:       to.StringPtr("18.04-LTS"),
+					Version:   to.StringPtr("latest"),
+				},
+				OsDisk: &compute.VirtualMachineScaleSetOSDisk{
+					OsType:       compute.OperatingSystemTypes(compute.Linux),
+					CreateOption: compute.DiskCreateOptionTypesFromImage,
+					DiskSizeGB:   to.Int32Ptr(30),
+					ManagedDisk: &compute.VirtualMachineScaleSetManagedDiskParameters{
+						StorageAccountType: compute.StorageAccountTypesPremiumLRS,
+					},
+					Caching: compute.CachingTypes(compute.HostCachingReadOnly),
+					DiffDiskSettings: &compute.DiffDiskSettings{
+						Option: compute.Local,
+					},
+				},
+			},
+		},
 	}
 
 	for i, tc := range testCases {
@@ -176,6 +205,65 @@ func TestGetStorageProfile(t *testing.T) {
 	}
 }
 
+func TestSetSpotPriority(t *testing.T) {
+	testCases := []struct {
+		spec           kops.InstanceGroupSpec
+		success        bool
+		maxPrice       float64
+		evictionPolicy string
+	}{
+		{
+			spec: kops.InstanceGroupSpec{
+				MaxPrice: fi.String(""),
+			},
+			success:        true,
+			maxPrice:       -1,
+			evictionPolicy: "Delete",
+		},
+		{
+			spec: kops.InstanceGroupSpec{
+				MaxPrice:                     fi.String("0.05"),
+				InstanceInterruptionBehavior: fi.String("stop"),
+			},
+			success:        true,
+			maxPrice:       0.05,
+			evictionPolicy: "Deallocate",
+		},
+		{
+			spec: kops.InstanceGroupSpec{
+				MaxPrice:                     fi.String("0.05"),
+				InstanceInterruptionBehavior: fi.String("hibernate"),
+			},
+			success: false,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test case %d", i), func(t *testing.T) {
+			vmss := &azuretasks.VMScaleSet{}
+			err := setSpotPriority(vmss, &tc.spec)
+			if !tc.success {
+				if err == nil {
+					t.Fatalf("unexpected success")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if a, e := fi.StringValue(vmss.Priority), "Spot"; a != e {
+				t.Errorf("expected priority %s, but got %s", e, a)
+			}
+			if a, e := fi.Float64Value(vmss.MaxPrice), tc.maxPrice; a != e {
+				t.Errorf("expected max price %f, but got %f", e, a)
+			}
+			if a, e := fi.StringValue(vmss.EvictionPolicy), tc.evictionPolicy; a != e {
+				t.Errorf("expected eviction policy %s, but got %s", e, a)
+			}
+		})
+	}
+}
+
 func TestParseImage(t *testing.T) {
 	testCases := []struct {
 		image    string
@@ -24,7 +24,6 @@ import (
 	"fmt"
 	"os"
 	"sort"
-	"strconv"
 	"strings"
 	"text/template"
 
@@ -40,6 +39,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi/fitasks"
 	"k8s.io/kops/util/pkg/architectures"
 	"k8s.io/kops/util/pkg/mirrors"
+	"k8s.io/kops/util/pkg/proxy"
 )
 
 type NodeUpConfigBuilder interface {
@@ -49,6 +49,7 @@ type NodeUpConfigBuilder interface {
 // BootstrapScriptBuilder creates the bootstrap script
 type BootstrapScriptBuilder struct {
 	NodeUpAssets        map[architectures.Architecture]*mirrors.MirroredAsset
+	NodeUpWindowsAsset  *mirrors.MirroredAsset
 	NodeUpConfigBuilder NodeUpConfigBuilder
 }
 
@@ -116,6 +117,11 @@ func (b *BootstrapScript) buildEnvironmentVariables(cluster *kops.Cluster) (map[
 		env["S3_SECRET_ACCESS_KEY"] = os.Getenv("S3_SECRET_ACCESS_KEY")
 	}
 
+	// nodeup exports traces when an OTLP endpoint reachable from the instances is given
+	if os.Getenv("NODEUP_OTEL_EXPORTER_OTLP_ENDPOINT") != "" {
+		env["OTEL_EXPORTER_OTLP_ENDPOINT"] = os.Getenv("NODEUP_OTEL_EXPORTER_OTLP_ENDPOINT")
+	}
+
 	if kops.CloudProviderID(cluster.Spec.CloudProvider) == kops.CloudProviderOpenstack {
 
 		osEnvs := []string{
@@ -226,6 +232,11 @@ func (b *BootstrapScriptBuilder) ResourceNodeUp(c *fi.ModelBuilderContext, ig *k
 	return &task.resource, nil
 }
 
+// Resource returns the bootstrap script, which is rendered when the task runs
+func (b *BootstrapScript) Resource() fi.Resource {
+	return &b.resource
+}
+
 func (b *BootstrapScript) GetName() *string {
 	return &b.Name
 }
@@ -271,6 +282,18 @@ func (b *BootstrapScript) Run(c *fi.Context) error {
 			}
 			return ""
 		},
+		"NodeUpSourceWindows": func() string {
+			if b.builder.NodeUpWindowsAsset != nil {
+				return strings.Join(b.builder.NodeUpWindowsAsset.Locations, ",")
+			}
+			return ""
+		},
+		"NodeUpSourceHashWindows": func() string {
+			if b.builder.NodeUpWindowsAsset != nil {
+				return b.builder.NodeUpWindowsAsset.Hash.Hex()
+			}
+			return ""
+		},
 		"KubeEnv": func() (string, error) {
 			return b.kubeEnv(b.ig, c, b.ca)
 		},
@@ -295,6 +318,25 @@ func (b *BootstrapScript) Run(c *fi.Context) error {
 			return b.String(), nil
 		},
 
+		"WindowsEnvironmentVariables": func() (string, error) {
+			env, err := b.buildEnvironmentVariables(c.Cluster)
+			if err != nil {
+				return "", err
+			}
+
+			var keys []string
+			for k := range env {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+
+			var b bytes.Buffer
+			for _, k := range keys {
+				b.WriteString(fmt.Sprintf("$env:%s = '%s'\n", k, strings.ReplaceAll(env[k], "'", "''")))
+			}
+			return b.String(), nil
+		},
+
 		"ProxyEnv": func() string {
 			return b.createProxyEnv(c.Cluster.Spec.EgressProxy)
 		},
@@ -523,24 +565,16 @@ func (b *BootstrapScript) computeFingerprint(content string) (string, error) {
 func (b *BootstrapScript) createProxyEnv(ps *kops.EgressProxySpec) string {
 	var buffer bytes.Buffer
 
-	if ps != nil && ps.HTTPProxy.Host != "" {
-		var httpProxyURL string
-
-		// TODO double check that all the code does this
-		// TODO move this into a validate so we can enforce the string syntax
-		if !strings.HasPrefix(ps.HTTPProxy.Host, "http://") {
-			httpProxyURL = "http://"
-		}
-
-		if ps.HTTPProxy.Port != 0 {
-			httpProxyURL += ps.HTTPProxy.Host + ":" + strconv.Itoa(ps.HTTPProxy.Port)
-		} else {
-			httpProxyURL += ps.HTTPProxy.Host
-		}
+	httpProxyURL := proxy.URL(ps)
+	if httpProxyURL != "" {
+		// Remove the settings of a previous boot, so they are not duplicated
+		buffer.WriteString(`sed -i '/^\(http_proxy\|https_proxy\|HTTP_PROXY\|HTTPS_PROXY\|no_proxy\|NO_PROXY\)=/d' /etc/environment` + "\n")
 
 		// Set env variables for base environment
 		buffer.WriteString(`echo "http_proxy=` + httpProxyURL + `" >> /etc/environment` + "\n")
 		buffer.WriteString(`echo "https_proxy=` + httpProxyURL + `" >> /etc/environment` + "\n")
+		buffer.WriteString(`echo "HTTP_PROXY=` + httpProxyURL + `" >> /etc/environment` + "\n")
+		buffer.WriteString(`echo "HTTPS_PROXY=` + httpProxyURL + `" >> /etc/environment` + "\n")
 		buffer.WriteString(`echo "no_proxy=` + ps.ProxyExcludes + `" >> /etc/environment` + "\n")
 		buffer.WriteString(`echo "NO_PROXY=` + ps.ProxyExcludes + `" >> /etc/environment` + "\n")
 
@@ -555,13 +589,16 @@ func (b *BootstrapScript) createProxyEnv(ps *kops.EgressProxySpec) string {
 		buffer.WriteString("*[Uu]buntu*)\n")
 		buffer.WriteString(`  echo "Acquire::http::Proxy \"${http_proxy}\";" > /etc/apt/apt.conf.d/30proxy ;;` + "\n")
 		buffer.WriteString("*[Rr]ed[Hh]at*)\n")
-		buffer.WriteString(`  echo "proxy=${http_proxy}" >> /etc/yum.conf ;;` + "\n")
+		buffer.WriteString(`  sed -i '/^proxy=/d' /etc/yum.conf && echo "proxy=${http_proxy}" >> /etc/yum.conf ;;` + "\n")
 		buffer.WriteString("esac\n")
 
-		// Set env variables for systemd
+		// Set env variables for systemd, in a drop-in so the settings are replaced on every boot
+		buffer.WriteString("mkdir -p /etc/systemd/system.conf.d\n")
+		buffer.WriteString(`echo "[Manager]" > /etc/systemd/system.conf.d/50-kops-proxy.conf` + "\n")
 		buffer.WriteString(`echo "DefaultEnvironment=\"http_proxy=${http_proxy}\" \"https_proxy=${http_proxy}\"`)
+		buffer.WriteString(` \"HTTP_PROXY=${http_proxy}\" \"HTTPS_PROXY=${http_proxy}\"`)
 		buffer.WriteString(` \"NO_PROXY=${no_proxy}\" \"no_proxy=${no_proxy}\""`)
-		buffer.WriteString(" >> /etc/systemd/system.conf\n")
+		buffer.WriteString(" >> /etc/systemd/system.conf.d/50-kops-proxy.conf\n")
 
 		// Restart stuff
 		buffer.WriteString("systemctl daemon-reload\n")
@@ -47,10 +47,18 @@ func Test_ProxyFunc(t *testing.T) {
 		t.Fatalf("script cannot be empty")
 	}
 
-	if !strings.HasPrefix(script, "echo \"http_proxy=http://example.com:80\" >> /etc/environment") {
+	if !strings.HasPrefix(script, "sed -i ") {
+		t.Fatalf("script not removing previous proxy settings")
+	}
+
+	if !strings.Contains(script, "echo \"http_proxy=http://example.com:80\" >> /etc/environment") {
 		t.Fatalf("script not setting http_proxy properly")
 	}
 
+	if !strings.Contains(script, "echo \"HTTPS_PROXY=http://example.com:80\" >> /etc/environment") {
+		t.Fatalf("script not setting HTTPS_PROXY properly")
+	}
+
 	ps.ProxyExcludes = "www.google.com,www.kubernetes.io"
 
 	script = b.createProxyEnv(ps)
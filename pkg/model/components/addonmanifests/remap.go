@@ -18,20 +18,25 @@ package addonmanifests
 
 import (
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"k8s.io/klog/v2"
 	addonsapi "k8s.io/kops/channels/pkg/api"
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/assets"
 	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/pkg/kubemanifest"
 	"k8s.io/kops/pkg/model"
 	"k8s.io/kops/pkg/model/components/addonmanifests/awsloadbalancercontroller"
 	"k8s.io/kops/pkg/model/components/addonmanifests/dnscontroller"
+	"k8s.io/kops/pkg/model/components/addonmanifests/externaldns"
+	"k8s.io/kops/pkg/model/components/addonmanifests/logshipper"
 	"k8s.io/kops/pkg/model/iam"
 	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/proxy"
 )
 
 func RemapAddonManifest(addon *addonsapi.AddonSpec, context *model.KopsModelContext, assetBuilder *assets.AssetBuilder, manifest []byte) ([]byte, error) {
@@ -54,11 +59,21 @@ func RemapAddonManifest(addon *addonsapi.AddonSpec, context *model.KopsModelCont
 			return nil, fmt.Errorf("failed to annotate %q: %w", name, err)
 		}
 
+		err = addPodSecurityLabels(context, objects)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add pod security labels for %q: %w", name, err)
+		}
+
 		err = addServiceAccountRole(context, objects)
 		if err != nil {
 			return nil, fmt.Errorf("failed to add service account for %q: %w", name, err)
 		}
 
+		err = addProxyEnv(context, objects)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add proxy environment for %q: %w", name, err)
+		}
+
 		b, err := objects.ToYAML()
 		if err != nil {
 			return nil, err
@@ -84,7 +99,7 @@ func addServiceAccountRole(context *model.KopsModelContext, objects kubemanifest
 	}
 
 	for _, object := range objects {
-		if object.Kind() != "Deployment" {
+		if object.Kind() != "Deployment" && object.Kind() != "DaemonSet" {
 			continue
 		}
 		if object.APIVersion() != "apps/v1" {
@@ -93,11 +108,11 @@ func addServiceAccountRole(context *model.KopsModelContext, objects kubemanifest
 		podSpec := &corev1.PodSpec{}
 
 		if err := object.Reparse(podSpec, "spec", "template", "spec"); err != nil {
-			return fmt.Errorf("failed to parse spec.template.spec from Deployment: %v", err)
+			return fmt.Errorf("failed to parse spec.template.spec from %s: %v", object.Kind(), err)
 		}
 		containers := podSpec.Containers
 		sa := podSpec.ServiceAccountName
-		subject := getWellknownServiceAccount(sa)
+		subject := getWellknownServiceAccount(context.Cluster, sa)
 		if subject == nil {
 			continue
 		}
@@ -115,10 +130,115 @@ func addServiceAccountRole(context *model.KopsModelContext, objects kubemanifest
 	return nil
 }
 
-func getWellknownServiceAccount(name string) iam.Subject {
+// addPodSecurityLabels exempts the namespaces created by addons from the cluster-wide PodSecurity defaults,
+// as the addons run privileged workloads. Levels already set by the manifest are kept.
+func addPodSecurityLabels(context *model.KopsModelContext, objects kubemanifest.ObjectList) error {
+	if context.Cluster.Spec.PodSecurity == nil {
+		return nil
+	}
+
+	for _, object := range objects {
+		if object.Kind() != "Namespace" {
+			continue
+		}
+
+		meta := &metav1.ObjectMeta{}
+		if err := object.Reparse(meta, "metadata"); err != nil {
+			return fmt.Errorf("failed to parse metadata of Namespace: %w", err)
+		}
+
+		if meta.Labels == nil {
+			meta.Labels = make(map[string]string)
+		}
+		for _, mode := range []string{"enforce", "audit", "warn"} {
+			key := "pod-security.kubernetes.io/" + mode
+			if _, found := meta.Labels[key]; !found {
+				meta.Labels[key] = "privileged"
+			}
+		}
+
+		if err := object.Set(meta, "metadata"); err != nil {
+			return fmt.Errorf("failed to set object: %w", err)
+		}
+	}
+	return nil
+}
+
+// podSpecPath returns the path of the pod spec within objects of the given kind, or nil if the kind has none
+func podSpecPath(kind string) []string {
+	switch kind {
+	case "Pod":
+		return []string{"spec"}
+	case "DaemonSet", "Deployment", "Job", "ReplicaSet", "StatefulSet":
+		return []string{"spec", "template", "spec"}
+	case "CronJob":
+		return []string{"spec", "jobTemplate", "spec", "template", "spec"}
+	default:
+		return nil
+	}
+}
+
+// addProxyEnv sets the egress proxy environment variables on all containers of the addons,
+// so they can reach the outside world. Variables already set by the manifest are kept.
+func addProxyEnv(context *model.KopsModelContext, objects kubemanifest.ObjectList) error {
+	proxyEnv := proxy.GetProxyEnvVars(context.Cluster.Spec.EgressProxy)
+	if len(proxyEnv) == 0 {
+		return nil
+	}
+
+	for _, object := range objects {
+		path := podSpecPath(object.Kind())
+		if path == nil {
+			continue
+		}
+
+		podSpec := &corev1.PodSpec{}
+		if err := object.Reparse(podSpec, path...); err != nil {
+			return fmt.Errorf("failed to parse %s from %s: %w", strings.Join(path, "."), object.Kind(), err)
+		}
+
+		for i := range podSpec.InitContainers {
+			addEnvVars(&podSpec.InitContainers[i], proxyEnv)
+		}
+		for i := range podSpec.Containers {
+			addEnvVars(&podSpec.Containers[i], proxyEnv)
+		}
+
+		if err := object.Set(podSpec, path...); err != nil {
+			return fmt.Errorf("failed to set object: %w", err)
+		}
+	}
+	return nil
+}
+
+// addEnvVars appends the variables not yet set on the container
+func addEnvVars(container *corev1.Container, envVars []corev1.EnvVar) {
+	for _, envVar := range envVars {
+		found := false
+		for _, existing := range container.Env {
+			if existing.Name == envVar.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			container.Env = append(container.Env, envVar)
+		}
+	}
+}
+
+func getWellknownServiceAccount(cluster *kops.Cluster, name string) iam.Subject {
 	switch name {
 	case "aws-load-balancer-controller":
 		return &awsloadbalancercontroller.ServiceAccount{}
+	case "external-dns":
+		return &externaldns.ServiceAccount{}
+	case "log-shipper":
+		// The log shipper only has a role when it writes to AWS
+		if !iam.LoggingUsesAWS(cluster.Spec.Logging) {
+			return nil
+		}
+		return &logshipper.ServiceAccount{}
 	default:
 		return nil
 	}
@@ -0,0 +1,176 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addonmanifests
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/featureflag"
+	"k8s.io/kops/pkg/kubemanifest"
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestAddProxyEnv(t *testing.T) {
+	manifest := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: addon
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: example.com/init:1.0
+      containers:
+      - name: addon
+        image: example.com/addon:1.0
+        env:
+        - name: NO_PROXY
+          value: custom
+---
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: job
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: job
+            image: example.com/job:1.0
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+`
+	objects, err := kubemanifest.LoadObjectsFrom([]byte(manifest))
+	if err != nil {
+		t.Fatalf("error loading manifest: %v", err)
+	}
+
+	context := &model.KopsModelContext{}
+	context.Cluster = &kops.Cluster{}
+	context.Cluster.Spec.EgressProxy = &kops.EgressProxySpec{
+		HTTPProxy:     kops.HTTPProxy{Host: "proxy.example.com", Port: 3128},
+		ProxyExcludes: "127.0.0.1,.svc",
+	}
+
+	if err := addProxyEnv(context, objects); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	deployment := &corev1.PodSpec{}
+	if err := objects[0].Reparse(deployment, "spec", "template", "spec"); err != nil {
+		t.Fatalf("error parsing deployment: %v", err)
+	}
+	cronJob := &corev1.PodSpec{}
+	if err := objects[1].Reparse(cronJob, "spec", "jobTemplate", "spec", "template", "spec"); err != nil {
+		t.Fatalf("error parsing cronjob: %v", err)
+	}
+
+	for _, container := range []corev1.Container{deployment.InitContainers[0], deployment.Containers[0], cronJob.Containers[0]} {
+		env := make(map[string]string)
+		for _, envVar := range container.Env {
+			if _, found := env[envVar.Name]; found {
+				t.Errorf("duplicate env var %q in container %q", envVar.Name, container.Name)
+			}
+			env[envVar.Name] = envVar.Value
+		}
+		if env["HTTPS_PROXY"] != "http://proxy.example.com:3128" {
+			t.Errorf("expected HTTPS_PROXY to be set in container %q, got %v", container.Name, env)
+		}
+		expectedNoProxy := "127.0.0.1,.svc"
+		if container.Name == "addon" {
+			expectedNoProxy = "custom"
+		}
+		if env["NO_PROXY"] != expectedNoProxy {
+			t.Errorf("expected NO_PROXY %q in container %q, got %q", expectedNoProxy, container.Name, env["NO_PROXY"])
+		}
+	}
+}
+
+func TestAddServiceAccountRoleLogShipper(t *testing.T) {
+	featureflag.ParseFlags("+UseServiceAccountIAM")
+	defer featureflag.ParseFlags("-UseServiceAccountIAM")
+
+	manifest := `
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: log-shipper
+spec:
+  template:
+    spec:
+      serviceAccountName: log-shipper
+      containers:
+      - name: fluent-bit
+        image: fluent/fluent-bit:1.8.3
+`
+
+	grid := []struct {
+		Logging kops.LoggingSpec
+		RoleARN string
+	}{
+		{
+			Logging: kops.LoggingSpec{Enabled: fi.Bool(true), CloudWatch: &kops.LoggingCloudWatchSpec{LogGroup: "cluster"}},
+			RoleARN: "arn:aws:iam::123456789012:role/log-shipper.kube-system.sa.minimal.example.com",
+		},
+		{
+			Logging: kops.LoggingSpec{Enabled: fi.Bool(true), Loki: &kops.LoggingLokiSpec{URL: "https://loki.example.com/loki/api/v1/push"}},
+		},
+	}
+
+	for _, g := range grid {
+		objects, err := kubemanifest.LoadObjectsFrom([]byte(manifest))
+		if err != nil {
+			t.Fatalf("error loading manifest: %v", err)
+		}
+
+		context := &model.KopsModelContext{}
+		context.AWSAccountID = "123456789012"
+		context.AWSPartition = "aws"
+		context.Cluster = &kops.Cluster{}
+		context.Cluster.Name = "minimal.example.com"
+		context.Cluster.Spec.CloudProvider = string(kops.CloudProviderAWS)
+		context.Cluster.Spec.Logging = &g.Logging
+
+		if err := addServiceAccountRole(context, objects); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		podSpec := &corev1.PodSpec{}
+		if err := objects[0].Reparse(podSpec, "spec", "template", "spec"); err != nil {
+			t.Fatalf("error parsing daemonset: %v", err)
+		}
+		roleARN := ""
+		for _, envVar := range podSpec.Containers[0].Env {
+			if envVar.Name == "AWS_ROLE_ARN" {
+				roleARN = envVar.Value
+			}
+		}
+		if roleARN != g.RoleARN {
+			t.Errorf("expected role %q, got %q", g.RoleARN, roleARN)
+		}
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/loader"
@@ -75,6 +76,9 @@ func (b *KubeAPIServerOptionsBuilder) BuildOptions(o interface{}) error {
 		if clusterSpec.Authentication.Kopeio != nil {
 			c.AuthenticationTokenWebhookConfigFile = fi.String("/etc/kubernetes/authn.config")
 		}
+		if clusterSpec.Authentication.OIDC != nil {
+			b.configureOIDC(clusterSpec)
+		}
 	}
 
 	if clusterSpec.Authorization == nil || clusterSpec.Authorization.IsEmpty() {
@@ -118,6 +122,9 @@ func (b *KubeAPIServerOptionsBuilder) BuildOptions(o interface{}) error {
 		c.CloudProvider = "alicloud"
 	case kops.CloudProviderAzure:
 		c.CloudProvider = "azure"
+	case kops.CloudProviderMetal:
+		// There is no in-tree cloud provider for pre-existing machines
+		c.CloudProvider = ""
 	default:
 		return fmt.Errorf("unknown cloudprovider %q", clusterSpec.CloudProvider)
 	}
@@ -137,6 +144,17 @@ func (b *KubeAPIServerOptionsBuilder) BuildOptions(o interface{}) error {
 	c.EtcdServersOverrides = nil
 
 	for _, etcdCluster := range clusterSpec.EtcdClusters {
+		if etcdCluster.IsExternal() && etcdCluster.External != nil {
+			switch etcdCluster.Name {
+			case "main":
+				c.EtcdServers = append(c.EtcdServers, etcdCluster.External.Endpoints...)
+			case "events":
+				// Endpoints of a resource override are separated by semicolons
+				c.EtcdServersOverrides = append(c.EtcdServersOverrides, "/events#"+strings.Join(etcdCluster.External.Endpoints, ";"))
+			}
+			continue
+		}
+
 		protocol := "http"
 		if etcdCluster.EnableEtcdTLS {
 			protocol = "https"
@@ -165,9 +183,38 @@ func (b *KubeAPIServerOptionsBuilder) BuildOptions(o interface{}) error {
 			"NodeRestriction",
 			"ResourceQuota",
 		}
+		if IsCISHardened(clusterSpec) {
+			c.EnableAdmissionPlugins = append(c.EnableAdmissionPlugins, "AlwaysPullImages", "EventRateLimit")
+		}
 		c.EnableAdmissionPlugins = append(c.EnableAdmissionPlugins, c.AppendAdmissionPlugins...)
 	}
 
+	if podSecurity := clusterSpec.PodSecurity; podSecurity != nil {
+		if podSecurity.Enforce == "" {
+			podSecurity.Enforce = "privileged"
+		}
+		if podSecurity.Audit == "" {
+			podSecurity.Audit = "privileged"
+		}
+		if podSecurity.Warn == "" {
+			podSecurity.Warn = "privileged"
+		}
+		if podSecurity.Version == "" {
+			podSecurity.Version = "latest"
+		}
+
+		// PodSecurity is alpha in 1.22, and enabled by default from 1.23
+		if b.IsKubernetesLT("1.23") {
+			if c.FeatureGates == nil {
+				c.FeatureGates = make(map[string]string)
+			}
+			if _, found := c.FeatureGates["PodSecurity"]; !found {
+				c.FeatureGates["PodSecurity"] = "true"
+			}
+			c.EnableAdmissionPlugins = append(c.EnableAdmissionPlugins, "PodSecurity")
+		}
+	}
+
 	// We make sure to disable AnonymousAuth
 	c.AnonymousAuth = fi.Bool(false)
 
@@ -175,9 +222,125 @@ func (b *KubeAPIServerOptionsBuilder) BuildOptions(o interface{}) error {
 	c.InsecureBindAddress = ""
 	c.InsecurePort = 0
 
+	if clusterSpec.Audit != nil {
+		b.configureAudit(clusterSpec)
+	}
+
+	if encryption := clusterSpec.EncryptionAtRest; encryption != nil {
+		if len(encryption.Resources) == 0 {
+			encryption.Resources = []string{"secrets"}
+		}
+		if encryption.Image == "" {
+			encryption.Image = "k8s.gcr.io/provider-aws/aws-encryption-provider:v0.1.0"
+		}
+	}
+
 	return nil
 }
 
+// defaultAuditPolicy logs the metadata of all requests
+const defaultAuditPolicy = `apiVersion: audit.k8s.io/v1
+kind: Policy
+omitStages:
+- RequestReceived
+rules:
+- level: Metadata
+`
+
+// configureAudit points the apiserver at the audit policy, webhook configuration and log file managed by kops
+func (b *KubeAPIServerOptionsBuilder) configureAudit(clusterSpec *kops.ClusterSpec) {
+	audit := clusterSpec.Audit
+	c := clusterSpec.KubeAPIServer
+
+	if c.AuditPolicyFile == "" {
+		c.AuditPolicyFile = "/srv/kubernetes/audit-policy.yaml"
+		if audit.Policy == "" {
+			audit.Policy = defaultAuditPolicy
+		}
+	}
+
+	if audit.Webhook != nil {
+		if c.AuditWebhookConfigFile == "" {
+			c.AuditWebhookConfigFile = "/srv/kubernetes/audit-webhook-config.yaml"
+		}
+		if c.AuditWebhookMode == "" {
+			c.AuditWebhookMode = audit.Webhook.Mode
+		}
+	}
+
+	// Log to a file unless events are only sent to the webhook; the log shipper tails this file
+	if c.AuditLogPath == nil && (audit.Webhook == nil || audit.LogShipping != nil) {
+		c.AuditLogPath = fi.String("/var/log/kube-apiserver-audit/audit.log")
+		if c.AuditLogMaxSize == nil {
+			c.AuditLogMaxSize = fi.Int32(100)
+		}
+		if c.AuditLogMaxBackups == nil {
+			c.AuditLogMaxBackups = fi.Int32(5)
+		}
+	}
+}
+
+// configureOIDC points the apiserver at the structured authentication configuration written by nodeup,
+// or sets the equivalent --oidc flags on versions that do not support it
+func (b *KubeAPIServerOptionsBuilder) configureOIDC(clusterSpec *kops.ClusterSpec) {
+	oidc := clusterSpec.Authentication.OIDC
+	c := clusterSpec.KubeAPIServer
+
+	if oidc.UsernameClaim == "" {
+		oidc.UsernameClaim = "sub"
+	}
+
+	// The authentication spec replaces any OIDC flags; the apiserver rejects them alongside --authentication-config
+	c.OIDCIssuerURL = nil
+	c.OIDCClientID = nil
+	c.OIDCUsernameClaim = nil
+	c.OIDCUsernamePrefix = nil
+	c.OIDCGroupsClaim = nil
+	c.OIDCGroupsPrefix = nil
+	c.OIDCRequiredClaim = nil
+	c.OIDCCAFile = nil
+
+	if b.IsKubernetesGTE("1.30") {
+		if c.AuthenticationConfigFile == "" {
+			c.AuthenticationConfigFile = "/srv/kubernetes/authentication-config.yaml"
+		}
+		return
+	}
+
+	SetOIDCFlags(c, oidc)
+}
+
+// SetOIDCFlags sets the --oidc flags that are equivalent to the authentication spec
+func SetOIDCFlags(c *kops.KubeAPIServerConfig, oidc *kops.OIDCAuthenticationSpec) {
+	c.OIDCIssuerURL = fi.String(oidc.IssuerURL)
+	c.OIDCClientID = fi.String(oidc.ClientID)
+	if oidc.UsernameClaim != "" {
+		c.OIDCUsernameClaim = fi.String(oidc.UsernameClaim)
+	} else {
+		c.OIDCUsernameClaim = fi.String("sub")
+	}
+	if oidc.UsernamePrefix != nil {
+		if *oidc.UsernamePrefix == "" {
+			// The apiserver treats an empty prefix as unset
+			c.OIDCUsernamePrefix = fi.String("-")
+		} else {
+			c.OIDCUsernamePrefix = fi.String(*oidc.UsernamePrefix)
+		}
+	}
+	if oidc.GroupsClaim != "" {
+		c.OIDCGroupsClaim = fi.String(oidc.GroupsClaim)
+	}
+	if oidc.GroupsPrefix != "" {
+		c.OIDCGroupsPrefix = fi.String(oidc.GroupsPrefix)
+	}
+	for _, claim := range sets.StringKeySet(oidc.RequiredClaims).List() {
+		c.OIDCRequiredClaim = append(c.OIDCRequiredClaim, claim+"="+oidc.RequiredClaims[claim])
+	}
+	if oidc.CertificateAuthority != "" {
+		c.OIDCCAFile = fi.String("/srv/kubernetes/oidc-ca.crt")
+	}
+}
+
 // buildAPIServerCount calculates the count of the api servers, essentially the number of node marked as Master role
 func (b *KubeAPIServerOptionsBuilder) buildAPIServerCount(clusterSpec *kops.ClusterSpec) int {
 	// The --apiserver-count flag is (generally agreed) to be something we need to get rid of in k8s
@@ -205,6 +368,12 @@ func (b *KubeAPIServerOptionsBuilder) buildAPIServerCount(clusterSpec *kops.Clus
 
 	counts := make(map[string]int)
 	for _, etcdCluster := range clusterSpec.EtcdClusters {
+		if etcdCluster.IsExternal() {
+			// The members of an external etcd cluster are not control plane nodes, so we can't use them;
+			// the flag is ignored by the default lease endpoint reconciler anyway.
+			counts[etcdCluster.Name] = 1
+			continue
+		}
 		counts[etcdCluster.Name] = len(etcdCluster.Members)
 	}
 
@@ -57,5 +57,10 @@ func (b *CalicoOptionsBuilder) BuildOptions(o interface{}) error {
 		rebindIfEmpty(&c.IPIPMode, "Never")
 	}
 
+	// Once kube-proxy is gone, Felix should remove the iptables rules it left behind on the nodes
+	if c.BPFEnabled && clusterSpec.KubeProxy != nil && clusterSpec.KubeProxy.Enabled != nil && !*clusterSpec.KubeProxy.Enabled {
+		c.BPFKubeProxyIptablesCleanupEnabled = true
+	}
+
 	return nil
 }
@@ -26,6 +26,9 @@ import (
 	"k8s.io/kops/upup/pkg/fi/loader"
 )
 
+// DefaultNvidiaDriverPackage is the OS package that provides the NVIDIA driver, unless the user picks another one
+const DefaultNvidiaDriverPackage = "nvidia-headless-460-server"
+
 // ContainerdOptionsBuilder adds options for containerd to the model
 type ContainerdOptionsBuilder struct {
 	*OptionsContext
@@ -54,6 +57,17 @@ func (b *ContainerdOptionsBuilder) BuildOptions(o interface{}) error {
 		}
 		// Set default log level to INFO
 		containerd.LogLevel = fi.String("info")
+		if containerd.NvidiaGPU.IsEnabled() && containerd.NvidiaGPU.DriverPackage == nil {
+			containerd.NvidiaGPU.DriverPackage = fi.String(DefaultNvidiaDriverPackage)
+		}
+		// Air-gapped clusters can't pull the default pause image of containerd
+		if clusterSpec.Assets != nil && fi.BoolValue(clusterSpec.Assets.AirGapped) && containerd.SandboxImage == nil {
+			image, err := b.AssetBuilder.RemapImage(pauseImage)
+			if err != nil {
+				return err
+			}
+			containerd.SandboxImage = fi.String(image)
+		}
 		// Build config file for containerd running in CRI mode
 		if fi.StringValue(containerd.ConfigOverride) == "" {
 			config, _ := toml.Load("")
@@ -64,6 +78,9 @@ func (b *ContainerdOptionsBuilder) BuildOptions(o interface{}) error {
 			config.SetPath([]string{"plugins", "io.containerd.grpc.v1.cri", "containerd", "runtimes", "runc", "runtime_type"}, "io.containerd.runc.v2")
 			// only enable systemd cgroups for kubernetes >= 1.20
 			config.SetPath([]string{"plugins", "io.containerd.grpc.v1.cri", "containerd", "runtimes", "runc", "options", "SystemdCgroup"}, b.IsKubernetesGTE("1.20"))
+			if fi.BoolValue(containerd.SelinuxEnabled) {
+				config.SetPath([]string{"plugins", "io.containerd.grpc.v1.cri", "enable_selinux"}, true)
+			}
 			if UsesKubenet(clusterSpec.Networking) {
 				// Using containerd with Kubenet requires special configuration.
 				// This is a temporary backwards-compatible solution for kubenet users and will be deprecated when Kubenet is deprecated:
@@ -22,6 +22,7 @@ import (
 	kopsapi "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/util"
 	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/upup/pkg/fi"
 )
 
 func buildContainerdCluster(version string) *kopsapi.Cluster {
@@ -125,3 +126,34 @@ func Test_Build_Containerd_Needed_Runtime(t *testing.T) {
 		}
 	}
 }
+
+func Test_Build_Containerd_AirGapped_SandboxImage(t *testing.T) {
+	c := buildContainerdCluster("1.21.0")
+	c.Spec.ContainerRuntime = "containerd"
+	c.Spec.Assets = &kopsapi.Assets{
+		AirGapped:         fi.Bool(true),
+		ContainerRegistry: fi.String("registry.example.com"),
+	}
+	b := assets.NewAssetBuilder(c, false)
+
+	version, err := util.ParseKubernetesVersion("1.21.0")
+	if err != nil {
+		t.Fatalf("unexpected error from ParseKubernetesVersion: %v", err)
+	}
+
+	ob := &ContainerdOptionsBuilder{
+		&OptionsContext{
+			AssetBuilder:      b,
+			KubernetesVersion: *version,
+		},
+	}
+
+	err = ob.BuildOptions(&c.Spec)
+	if err != nil {
+		t.Fatalf("unexpected error from BuildOptions: %v", err)
+	}
+
+	if fi.StringValue(c.Spec.Containerd.SandboxImage) != "registry.example.com/pause:3.2" {
+		t.Fatalf("expected the pause image to be remapped, got %q", fi.StringValue(c.Spec.Containerd.SandboxImage))
+	}
+}
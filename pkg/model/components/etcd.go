@@ -46,7 +46,11 @@ func (b *EtcdOptionsBuilder) BuildOptions(o interface{}) error {
 	for i := range spec.EtcdClusters {
 		c := &spec.EtcdClusters[i]
 		if c.Provider == "" {
-			c.Provider = kops.EtcdProviderTypeManager
+			if c.External != nil {
+				c.Provider = kops.EtcdProviderTypeExternal
+			} else {
+				c.Provider = kops.EtcdProviderTypeManager
+			}
 		}
 
 		// Ensure the version is set
@@ -71,6 +75,18 @@ func (b *EtcdOptionsBuilder) BuildOptions(o interface{}) error {
 			c.EnableTLSAuth = true
 		}
 
+		// An external cluster uses TLS, and authenticates the API server by its client certificate, if its endpoints are https
+		if c.Provider == kops.EtcdProviderTypeExternal && c.External != nil {
+			tls := len(c.External.Endpoints) != 0
+			for _, endpoint := range c.External.Endpoints {
+				if !strings.HasPrefix(endpoint, "https://") {
+					tls = false
+				}
+			}
+			c.EnableEtcdTLS = tls
+			c.EnableTLSAuth = tls
+		}
+
 		// We remap the etcd manager image when we build the manifest,
 		// but we need to map the standalone images here because protokube launches them
 		if c.Provider == kops.EtcdProviderTypeLegacy {
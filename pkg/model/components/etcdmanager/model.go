@@ -19,6 +19,7 @@ package etcdmanager
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
 	v1 "k8s.io/api/core/v1"
@@ -32,6 +33,7 @@ import (
 	"k8s.io/kops/pkg/k8scodecs"
 	"k8s.io/kops/pkg/kubemanifest"
 	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/pkg/model/components"
 	"k8s.io/kops/pkg/wellknownports"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/aliup"
@@ -287,6 +289,7 @@ func (b *EtcdManagerBuilder) buildPod(etcdCluster kops.EtcdClusterSpec) (*v1.Pod
 	quarantinedClientPort := wellknownports.EtcdMainQuarantinedClientPort
 
 	grpcPort := wellknownports.EtcdMainGRPC
+	metricsPort := wellknownports.EtcdMainMetrics
 
 	// The dns suffix logic mirrors the existing logic, so we should be compatible with existing clusters
 	// (etcd makes it difficult to change peer urls, treating it as a cluster event, for reasons unknown)
@@ -310,11 +313,13 @@ func (b *EtcdManagerBuilder) buildPod(etcdCluster kops.EtcdClusterSpec) (*v1.Pod
 		peerPort = 2381
 		grpcPort = wellknownports.EtcdEventsGRPC
 		quarantinedClientPort = wellknownports.EtcdEventsQuarantinedClientPort
+		metricsPort = wellknownports.EtcdEventsMetrics
 	case "cilium":
 		clientPort = 4003
 		peerPort = 2382
 		grpcPort = wellknownports.EtcdCiliumGRPC
 		quarantinedClientPort = wellknownports.EtcdCiliumQuarantinedClientPort
+		metricsPort = wellknownports.EtcdCiliumMetrics
 	default:
 		return nil, fmt.Errorf("unknown etcd cluster key %q", etcdCluster.Name)
 	}
@@ -350,6 +355,10 @@ func (b *EtcdManagerBuilder) buildPod(etcdCluster kops.EtcdClusterSpec) (*v1.Pod
 		config.DiscoveryPollInterval = etcdCluster.Manager.DiscoveryPollInterval
 	}
 
+	if etcdCluster.Defragmentation != nil && etcdCluster.Defragmentation.Interval != nil {
+		config.DefragInterval = fi.String(etcdCluster.Defragmentation.Interval.Duration.String())
+	}
+
 	{
 		scheme := "https"
 
@@ -435,6 +444,16 @@ func (b *EtcdManagerBuilder) buildPod(etcdCluster kops.EtcdClusterSpec) (*v1.Pod
 			}
 			config.VolumeNameTag = openstack.TagNameEtcdClusterPrefix + etcdCluster.Name
 
+		case kops.CloudProviderMetal:
+			// Metal machines have no cloud volumes; etcd-manager uses directories under /mnt/disks instead
+			config.VolumeProvider = "external"
+
+			config.VolumeTag = []string{
+				fmt.Sprintf("kubernetes.io/cluster/%s=owned", b.Cluster.Name),
+				"k8s.io/etcd/" + etcdCluster.Name,
+			}
+			config.VolumeNameTag = "k8s.io/etcd/" + etcdCluster.Name
+
 		default:
 			return nil, fmt.Errorf("CloudProvider %q not supported with etcd-manager", b.Cluster.Spec.CloudProvider)
 		}
@@ -504,6 +523,49 @@ func (b *EtcdManagerBuilder) buildPod(etcdCluster kops.EtcdClusterSpec) (*v1.Pod
 
 	container.Env = envMap.ToEnvVars()
 
+	// Variables starting with ETCD_ are passed down to etcd by etcd-manager
+	if etcdCluster.QuotaBackendBytes != nil {
+		container.Env = append(container.Env, v1.EnvVar{Name: "ETCD_QUOTA_BACKEND_BYTES", Value: strconv.FormatInt(etcdCluster.QuotaBackendBytes.Value(), 10)})
+	}
+	if etcdCluster.AutoCompaction != nil {
+		mode := etcdCluster.AutoCompaction.Mode
+		if mode == "" {
+			mode = "periodic"
+		}
+		container.Env = append(container.Env, v1.EnvVar{Name: "ETCD_AUTO_COMPACTION_MODE", Value: mode})
+		if etcdCluster.AutoCompaction.Retention != "" {
+			container.Env = append(container.Env, v1.EnvVar{Name: "ETCD_AUTO_COMPACTION_RETENTION", Value: etcdCluster.AutoCompaction.Retention})
+		}
+	}
+	if etcdCluster.Metrics != nil {
+		metricsType := etcdCluster.Metrics.Type
+		if metricsType == "" {
+			metricsType = "basic"
+		}
+		if etcdCluster.Metrics.Port != nil {
+			metricsPort = int(*etcdCluster.Metrics.Port)
+		}
+		// A plain http listener separate from the client URLs, so scraping needs no etcd client certificate
+		container.Env = append(container.Env,
+			v1.EnvVar{Name: "ETCD_METRICS", Value: metricsType},
+			v1.EnvVar{Name: "ETCD_LISTEN_METRICS_URLS", Value: fmt.Sprintf("http://0.0.0.0:%d", metricsPort)},
+		)
+	}
+
+	if fi.BoolValue(b.Cluster.Spec.FIPS) || components.IsCISHardened(&b.Cluster.Spec) {
+		container.Env = append(container.Env, v1.EnvVar{Name: "ETCD_CIPHER_SUITES", Value: strings.Join(components.FIPSCipherSuites, ",")})
+	}
+
+	// The CIS benchmark requires client certificates for clients and peers, and forbids self-signed certificates
+	if components.IsCISHardened(&b.Cluster.Spec) {
+		container.Env = append(container.Env,
+			v1.EnvVar{Name: "ETCD_CLIENT_CERT_AUTH", Value: "true"},
+			v1.EnvVar{Name: "ETCD_PEER_CLIENT_CERT_AUTH", Value: "true"},
+			v1.EnvVar{Name: "ETCD_AUTO_TLS", Value: "false"},
+			v1.EnvVar{Name: "ETCD_PEER_AUTO_TLS", Value: "false"},
+		)
+	}
+
 	if etcdCluster.Manager != nil && len(etcdCluster.Manager.Env) > 0 {
 		for _, envVar := range etcdCluster.Manager.Env {
 			klog.Warningf("overloading ENV var in manifest %s with %s=%s", bundle, envVar.Name, envVar.Value)
@@ -562,6 +624,7 @@ type config struct {
 	GrpcPort              int      `flag:"grpc-port"`
 	ClientUrls            string   `flag:"client-urls"`
 	DiscoveryPollInterval *string  `flag:"discovery-poll-interval"`
+	DefragInterval        *string  `flag:"defrag-interval"`
 	QuarantineClientUrls  string   `flag:"quarantine-client-urls"`
 	ClusterName           string   `flag:"cluster-name"`
 	BackupStore           string   `flag:"backup-store"`
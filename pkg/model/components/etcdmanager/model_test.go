@@ -34,6 +34,7 @@ func Test_RunEtcdManagerBuilder(t *testing.T) {
 		"tests/pollinterval",
 		"tests/proxy",
 		"tests/overwrite_settings",
+		"tests/tuning",
 	}
 	for _, basedir := range tests {
 		basedir := basedir
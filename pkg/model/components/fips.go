@@ -0,0 +1,71 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/loader"
+)
+
+// FIPSCipherSuites are the TLS 1.2 cipher suites approved by FIPS 140-2, which are offered by etcd,
+// the control plane and the kubelet of FIPS clusters. They are also the strong ciphers of the CIS hardening profile.
+var FIPSCipherSuites = []string{
+	"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+	"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+	"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+	"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+}
+
+// FIPSTLSMinVersion is the minimum TLS version of FIPS clusters
+const FIPSTLSMinVersion = "VersionTLS12"
+
+// FIPSOptionsBuilder limits the TLS settings of FIPS clusters to approved ciphers
+type FIPSOptionsBuilder struct {
+	*OptionsContext
+}
+
+var _ loader.OptionsBuilder = &FIPSOptionsBuilder{}
+
+func (b *FIPSOptionsBuilder) BuildOptions(o interface{}) error {
+	clusterSpec := o.(*kops.ClusterSpec)
+	if !fi.BoolValue(clusterSpec.FIPS) {
+		return nil
+	}
+
+	if clusterSpec.KubeAPIServer != nil {
+		setFIPSTLS(&clusterSpec.KubeAPIServer.TLSCipherSuites, &clusterSpec.KubeAPIServer.TLSMinVersion)
+	}
+	if clusterSpec.KubeControllerManager != nil {
+		setFIPSTLS(&clusterSpec.KubeControllerManager.TLSCipherSuites, &clusterSpec.KubeControllerManager.TLSMinVersion)
+	}
+	if clusterSpec.Kubelet != nil {
+		setFIPSTLS(&clusterSpec.Kubelet.TLSCipherSuites, &clusterSpec.Kubelet.TLSMinVersion)
+	}
+
+	return nil
+}
+
+// setFIPSTLS defaults the cipher suites and the minimum TLS version of a component, unless set by the user
+func setFIPSTLS(cipherSuites *[]string, minVersion *string) {
+	if len(*cipherSuites) == 0 {
+		*cipherSuites = append([]string(nil), FIPSCipherSuites...)
+	}
+	if *minVersion == "" {
+		*minVersion = FIPSTLSMinVersion
+	}
+}
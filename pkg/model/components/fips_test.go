@@ -0,0 +1,60 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func Test_Build_FIPS_TLS(t *testing.T) {
+	clusterSpec := &kops.ClusterSpec{
+		FIPS: fi.Bool(true),
+		KubeAPIServer: &kops.KubeAPIServerConfig{
+			TLSCipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"},
+		},
+		KubeControllerManager: &kops.KubeControllerManagerConfig{},
+		Kubelet: &kops.KubeletConfigSpec{
+			TLSMinVersion: "VersionTLS13",
+		},
+	}
+
+	b := &FIPSOptionsBuilder{OptionsContext: &OptionsContext{}}
+	if err := b.BuildOptions(clusterSpec); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The settings of the user are kept
+	if !reflect.DeepEqual(clusterSpec.KubeAPIServer.TLSCipherSuites, []string{"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"}) {
+		t.Errorf("unexpected kube-apiserver cipher suites %v", clusterSpec.KubeAPIServer.TLSCipherSuites)
+	}
+	if clusterSpec.KubeAPIServer.TLSMinVersion != FIPSTLSMinVersion {
+		t.Errorf("unexpected kube-apiserver minimum TLS version %q", clusterSpec.KubeAPIServer.TLSMinVersion)
+	}
+	if !reflect.DeepEqual(clusterSpec.KubeControllerManager.TLSCipherSuites, FIPSCipherSuites) {
+		t.Errorf("unexpected kube-controller-manager cipher suites %v", clusterSpec.KubeControllerManager.TLSCipherSuites)
+	}
+	if !reflect.DeepEqual(clusterSpec.Kubelet.TLSCipherSuites, FIPSCipherSuites) {
+		t.Errorf("unexpected kubelet cipher suites %v", clusterSpec.Kubelet.TLSCipherSuites)
+	}
+	if clusterSpec.Kubelet.TLSMinVersion != "VersionTLS13" {
+		t.Errorf("unexpected kubelet minimum TLS version %q", clusterSpec.Kubelet.TLSMinVersion)
+	}
+}
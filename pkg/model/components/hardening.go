@@ -0,0 +1,120 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/loader"
+)
+
+// HardeningOptionsBuilder applies the settings of the hardening profile, ahead of the defaults of the components
+type HardeningOptionsBuilder struct {
+	*OptionsContext
+}
+
+var _ loader.OptionsBuilder = &HardeningOptionsBuilder{}
+
+func (b *HardeningOptionsBuilder) BuildOptions(o interface{}) error {
+	clusterSpec := o.(*kops.ClusterSpec)
+	if !IsCISHardened(clusterSpec) {
+		return nil
+	}
+
+	if clusterSpec.Authorization == nil || clusterSpec.Authorization.IsEmpty() {
+		clusterSpec.Authorization = &kops.AuthorizationSpec{RBAC: &kops.RBACAuthorizationSpec{}}
+	}
+
+	if clusterSpec.KubeAPIServer == nil {
+		clusterSpec.KubeAPIServer = &kops.KubeAPIServerConfig{}
+	}
+	apiserver := clusterSpec.KubeAPIServer
+	if apiserver.EnableProfiling == nil {
+		apiserver.EnableProfiling = fi.Bool(false)
+	}
+	setFIPSTLS(&apiserver.TLSCipherSuites, &apiserver.TLSMinVersion)
+
+	// The audit log is kept for 30 days, in 10 files of 100MB
+	if clusterSpec.Audit == nil {
+		clusterSpec.Audit = &kops.AuditSpec{}
+	}
+	if apiserver.AuditLogMaxAge == nil {
+		apiserver.AuditLogMaxAge = fi.Int32(30)
+	}
+	if apiserver.AuditLogMaxBackups == nil {
+		apiserver.AuditLogMaxBackups = fi.Int32(10)
+	}
+	if apiserver.AuditLogMaxSize == nil {
+		apiserver.AuditLogMaxSize = fi.Int32(100)
+	}
+
+	// Pods must meet the baseline Pod Security Standard, and are warned about the restricted one
+	if clusterSpec.PodSecurity == nil && b.IsKubernetesGTE("1.22") {
+		clusterSpec.PodSecurity = &kops.PodSecuritySpec{
+			Enforce: "baseline",
+			Audit:   "restricted",
+			Warn:    "restricted",
+		}
+	}
+
+	if clusterSpec.KubeControllerManager == nil {
+		clusterSpec.KubeControllerManager = &kops.KubeControllerManagerConfig{}
+	}
+	kcm := clusterSpec.KubeControllerManager
+	if kcm.EnableProfiling == nil {
+		kcm.EnableProfiling = fi.Bool(false)
+	}
+	if kcm.TerminatedPodGCThreshold == nil {
+		kcm.TerminatedPodGCThreshold = fi.Int32(12500)
+	}
+	if kcm.UseServiceAccountCredentials == nil {
+		kcm.UseServiceAccountCredentials = fi.Bool(true)
+	}
+	setFIPSTLS(&kcm.TLSCipherSuites, &kcm.TLSMinVersion)
+
+	if clusterSpec.KubeScheduler == nil {
+		clusterSpec.KubeScheduler = &kops.KubeSchedulerConfig{}
+	}
+	if clusterSpec.KubeScheduler.EnableProfiling == nil {
+		clusterSpec.KubeScheduler.EnableProfiling = fi.Bool(false)
+	}
+
+	if clusterSpec.Kubelet == nil {
+		clusterSpec.Kubelet = &kops.KubeletConfigSpec{}
+	}
+	kubelet := clusterSpec.Kubelet
+	if kubelet.AnonymousAuth == nil {
+		kubelet.AnonymousAuth = fi.Bool(false)
+	}
+	if kubelet.AuthorizationMode == "" {
+		kubelet.AuthorizationMode = "Webhook"
+	}
+	if kubelet.ReadOnlyPort == nil {
+		kubelet.ReadOnlyPort = fi.Int32(0)
+	}
+	if kubelet.ProtectKernelDefaults == nil {
+		kubelet.ProtectKernelDefaults = fi.Bool(true)
+	}
+	setFIPSTLS(&kubelet.TLSCipherSuites, &kubelet.TLSMinVersion)
+
+	return nil
+}
+
+// IsCISHardened returns true if the cluster applies the CIS hardening profile
+func IsCISHardened(clusterSpec *kops.ClusterSpec) bool {
+	return clusterSpec.Hardening != nil && clusterSpec.Hardening.Profile == kops.HardeningProfileCIS
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func Test_Build_Hardening_CIS(t *testing.T) {
+	clusterSpec := &kops.ClusterSpec{
+		Hardening: &kops.HardeningSpec{Profile: kops.HardeningProfileCIS},
+		Kubelet: &kops.KubeletConfigSpec{
+			ReadOnlyPort: fi.Int32(10255),
+		},
+	}
+
+	b := &HardeningOptionsBuilder{OptionsContext: &OptionsContext{KubernetesVersion: semver.MustParse("1.22.0")}}
+	if err := b.BuildOptions(clusterSpec); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if clusterSpec.Authorization == nil || clusterSpec.Authorization.RBAC == nil {
+		t.Errorf("expected RBAC authorization, got %v", clusterSpec.Authorization)
+	}
+	if clusterSpec.Audit == nil {
+		t.Errorf("expected the audit log to be enabled")
+	}
+	if clusterSpec.PodSecurity == nil || clusterSpec.PodSecurity.Enforce != "baseline" {
+		t.Errorf("expected the baseline pod security standard to be enforced, got %v", clusterSpec.PodSecurity)
+	}
+	if fi.BoolValue(clusterSpec.KubeAPIServer.EnableProfiling) || fi.BoolValue(clusterSpec.KubeControllerManager.EnableProfiling) || fi.BoolValue(clusterSpec.KubeScheduler.EnableProfiling) {
+		t.Errorf("expected profiling to be disabled")
+	}
+	if fi.Int32Value(clusterSpec.KubeAPIServer.AuditLogMaxAge) != 30 {
+		t.Errorf("unexpected audit log max age %v", clusterSpec.KubeAPIServer.AuditLogMaxAge)
+	}
+	if !fi.BoolValue(clusterSpec.KubeControllerManager.UseServiceAccountCredentials) {
+		t.Errorf("expected kube-controller-manager to use service account credentials")
+	}
+	if clusterSpec.Kubelet.AnonymousAuth == nil || *clusterSpec.Kubelet.AnonymousAuth {
+		t.Errorf("expected kubelet anonymous auth to be disabled, got %v", clusterSpec.Kubelet.AnonymousAuth)
+	}
+	if clusterSpec.Kubelet.AuthorizationMode != "Webhook" {
+		t.Errorf("unexpected kubelet authorization mode %q", clusterSpec.Kubelet.AuthorizationMode)
+	}
+	// The settings of the user are kept
+	if fi.Int32Value(clusterSpec.Kubelet.ReadOnlyPort) != 10255 {
+		t.Errorf("unexpected kubelet read-only port %v", clusterSpec.Kubelet.ReadOnlyPort)
+	}
+}
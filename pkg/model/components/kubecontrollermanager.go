@@ -95,6 +95,9 @@ func (b *KubeControllerManagerOptionsBuilder) BuildOptions(o interface{}) error
 	case kops.CloudProviderAzure:
 		kcm.CloudProvider = "azure"
 
+	case kops.CloudProviderMetal:
+		kcm.CloudProvider = ""
+
 	default:
 		return fmt.Errorf("unknown cloudprovider %q", clusterSpec.CloudProvider)
 	}
@@ -150,6 +153,11 @@ func (b *KubeControllerManagerOptionsBuilder) BuildOptions(o interface{}) error
 		kcm.Controllers = []string{"*", "tokencleaner"}
 	}
 
+	// Renewed kubelet certificates are signed by the controller manager, so they should be as short-lived as the bootstrap ones
+	if clusterSpec.NodeCertificates != nil && clusterSpec.NodeCertificates.KubeletValidity != nil && kcm.ExperimentalClusterSigningDuration == nil {
+		kcm.ExperimentalClusterSigningDuration = &metav1.Duration{Duration: clusterSpec.NodeCertificates.KubeletValidity.Duration}
+	}
+
 	if clusterSpec.CloudConfig != nil && clusterSpec.CloudConfig.AWSEBSCSIDriver != nil && fi.BoolValue(clusterSpec.CloudConfig.AWSEBSCSIDriver.Enabled) {
 
 		if kcm.FeatureGates == nil {
@@ -75,6 +75,33 @@ func (b *KubeDnsOptionsBuilder) BuildOptions(o interface{}) error {
 		clusterSpec.KubeDNS.MemoryLimit = &defaultMemoryLimit
 	}
 
+	for i := range clusterSpec.KubeDNS.ServerBlocks {
+		if clusterSpec.KubeDNS.ServerBlocks[i].CacheTTL == 0 {
+			clusterSpec.KubeDNS.ServerBlocks[i].CacheTTL = 30
+		}
+	}
+
+	for i := range clusterSpec.KubeDNS.Rewrites {
+		if clusterSpec.KubeDNS.Rewrites[i].Match == "" {
+			clusterSpec.KubeDNS.Rewrites[i].Match = "exact"
+		}
+	}
+
+	autoscaler := clusterSpec.KubeDNS.Autoscaler
+	if autoscaler == nil {
+		autoscaler = &kops.DNSAutoscalerConfig{}
+		clusterSpec.KubeDNS.Autoscaler = autoscaler
+	}
+	if autoscaler.CoresPerReplica == 0 {
+		autoscaler.CoresPerReplica = 256
+	}
+	if autoscaler.NodesPerReplica == 0 {
+		autoscaler.NodesPerReplica = 16
+	}
+	if autoscaler.PreventSinglePointFailure == nil {
+		autoscaler.PreventSinglePointFailure = fi.Bool(true)
+	}
+
 	nodeLocalDNS := clusterSpec.KubeDNS.NodeLocalDNS
 	if nodeLocalDNS == nil {
 		nodeLocalDNS = &kops.NodeLocalDNSConfig{}
@@ -100,5 +127,9 @@ func (b *KubeDnsOptionsBuilder) BuildOptions(o interface{}) error {
 		nodeLocalDNS.CPURequest = &defaultCPURequest
 	}
 
+	if nodeLocalDNS.PrometheusMetricsPort == 0 {
+		nodeLocalDNS.PrometheusMetricsPort = 9253
+	}
+
 	return nil
 }
@@ -26,6 +26,9 @@ import (
 	"k8s.io/kops/upup/pkg/fi/loader"
 )
 
+// pauseImage is the image of the pause container of the pods
+const pauseImage = "k8s.gcr.io/pause:3.2"
+
 // KubeletOptionsBuilder adds options for kubelets
 type KubeletOptionsBuilder struct {
 	*OptionsContext
@@ -192,7 +195,7 @@ func (b *KubeletOptionsBuilder) BuildOptions(o interface{}) error {
 		}
 
 		// Specify our pause image
-		image := "k8s.gcr.io/pause:3.2"
+		image := pauseImage
 		var err error
 		if image, err = b.AssetBuilder.RemapImage(image); err != nil {
 			return err
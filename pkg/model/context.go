@@ -19,6 +19,7 @@ package model
 import (
 	"fmt"
 	"net"
+	"strconv"
 	"strings"
 
 	"k8s.io/kops/pkg/apis/kops"
@@ -26,6 +27,7 @@ import (
 	"k8s.io/kops/pkg/apis/kops/util"
 	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/pkg/model/components"
+	"k8s.io/kops/pkg/model/defaults"
 	"k8s.io/kops/pkg/model/iam"
 	nodeidentityaws "k8s.io/kops/pkg/nodeidentity/aws"
 	"k8s.io/kops/pkg/nodelabels"
@@ -39,7 +41,8 @@ import (
 )
 
 const (
-	clusterAutoscalerNodeTemplateTaint = "k8s.io/cluster-autoscaler/node-template/taint/"
+	clusterAutoscalerNodeTemplateTaint    = "k8s.io/cluster-autoscaler/node-template/taint/"
+	clusterAutoscalerNodeTemplateResource = "k8s.io/cluster-autoscaler/node-template/resources/"
 )
 
 // KopsModelContext is the kops model
@@ -129,6 +132,16 @@ func (b *KopsModelContext) NodeInstanceGroups() []*kops.InstanceGroup {
 	return groups
 }
 
+// HasWindowsInstanceGroups returns true if any of the InstanceGroups runs Windows
+func (b *KopsModelContext) HasWindowsInstanceGroups() bool {
+	for _, ig := range b.InstanceGroups {
+		if ig.IsWindows() {
+			return true
+		}
+	}
+	return false
+}
+
 // CloudTagsForInstanceGroup computes the tags to apply to instances in the specified InstanceGroup
 func (b *KopsModelContext) CloudTagsForInstanceGroup(ig *kops.InstanceGroup) (map[string]string, error) {
 	labels := b.CloudTags(b.AutoscalingGroupName(ig), false)
@@ -159,6 +172,32 @@ func (b *KopsModelContext) CloudTagsForInstanceGroup(ig *kops.InstanceGroup) (ma
 		splits := strings.SplitN(v, "=", 2)
 		if len(splits) > 1 {
 			labels[clusterAutoscalerNodeTemplateTaint+splits[0]] = splits[1]
+		} else if splits = strings.SplitN(v, ":", 2); len(splits) > 1 {
+			// Taints without a value are written as "key:Effect"; cluster autoscaler expects the value ":Effect"
+			labels[clusterAutoscalerNodeTemplateTaint+splits[0]] = ":" + splits[1]
+		}
+	}
+
+	// When scaling from zero, cluster autoscaler has no node to inspect and builds the node template from these tags
+	if ig.Spec.Role == kops.InstanceGroupRoleNode && ig.Spec.MinSize != nil && *ig.Spec.MinSize == 0 {
+		rootVolumeSize, err := defaults.DefaultInstanceGroupVolumeSize(ig.Spec.Role)
+		if err != nil {
+			return nil, err
+		}
+		if fi.Int32Value(ig.Spec.RootVolumeSize) > 0 {
+			rootVolumeSize = fi.Int32Value(ig.Spec.RootVolumeSize)
+		}
+		labels[clusterAutoscalerNodeTemplateResource+"ephemeral-storage"] = fmt.Sprintf("%dGi", rootVolumeSize)
+
+		if ig.IsWindows() {
+			labels[nodeidentityaws.ClusterAutoscalerNodeTemplateLabel+"kubernetes.io/os"] = "windows"
+		}
+	}
+
+	// Apply the cluster autoscaler scale-down-disabled flag, which kops-controller copies to the nodes
+	if value, found := ig.Annotations[kops.AnnotationClusterAutoscalerScaleDownDisabled]; found && ig.Spec.Role == kops.InstanceGroupRoleNode {
+		if disabled, err := strconv.ParseBool(value); err == nil {
+			labels[nodeidentityaws.CloudTagScaleDownDisabled] = strconv.FormatBool(disabled)
 		}
 	}
 
@@ -230,6 +269,11 @@ func (b *KopsModelContext) UseKopsControllerForNodeBootstrap() bool {
 	return model.UseKopsControllerForNodeBootstrap(b.Cluster)
 }
 
+// UseJoinTokensForNodeBootstrap checks if kops-controller accepts join tokens from bootstrapping nodes.
+func (b *KopsModelContext) UseJoinTokensForNodeBootstrap() bool {
+	return model.UseJoinTokensForNodeBootstrap(b.Cluster)
+}
+
 // UseBootstrapTokens checks if bootstrap tokens are enabled
 func (b *KopsModelContext) UseBootstrapTokens() bool {
 	if b.Cluster.Spec.KubeAPIServer == nil || b.UseKopsControllerForNodeBootstrap() {
@@ -311,6 +355,31 @@ func (b *KopsModelContext) UseNetworkLoadBalancer() bool {
 	return b.Cluster.Spec.API.LoadBalancer.Class == kops.LoadBalancerClassNetwork
 }
 
+// UseLoadBalancerForExternalNodes checks if the API load balancer also forwards kops-controller traffic,
+// for instance groups that reach the control plane through the public API endpoint
+func (b *KopsModelContext) UseLoadBalancerForExternalNodes() bool {
+	if !b.UseLoadBalancerForAPI() || !b.UseNetworkLoadBalancer() || !b.UseKopsControllerForNodeBootstrap() {
+		return false
+	}
+	for _, ig := range b.InstanceGroups {
+		if endpoint := ig.Spec.APIServerEndpoint; endpoint != nil && endpoint.Type == kops.APIServerEndpointTypeExternal {
+			return true
+		}
+	}
+	return false
+}
+
+// UseExternalEtcd checks if the etcd clusters are managed outside of kOps
+func (b *KopsModelContext) UseExternalEtcd() bool {
+	for _, x := range b.Cluster.Spec.EtcdClusters {
+		if x.IsExternal() {
+			return true
+		}
+	}
+
+	return false
+}
+
 // UseEtcdManager checks to see if etcd manager is enabled
 func (b *KopsModelContext) UseEtcdManager() bool {
 	for _, x := range b.Cluster.Spec.EtcdClusters {
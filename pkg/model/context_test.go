@@ -0,0 +1,106 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/model/iam"
+	nodeidentityaws "k8s.io/kops/pkg/nodeidentity/aws"
+	"k8s.io/kops/upup/pkg/fi"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCloudTagsForInstanceGroupScaleFromZero(t *testing.T) {
+	grid := []struct {
+		name     string
+		spec     kops.InstanceGroupSpec
+		expected map[string]string
+	}{
+		{
+			name: "non-zero minSize",
+			spec: kops.InstanceGroupSpec{
+				Role:    kops.InstanceGroupRoleNode,
+				MinSize: fi.Int32(1),
+			},
+			expected: map[string]string{
+				clusterAutoscalerNodeTemplateResource + "ephemeral-storage": "",
+			},
+		},
+		{
+			name: "default volume size",
+			spec: kops.InstanceGroupSpec{
+				Role:    kops.InstanceGroupRoleNode,
+				MinSize: fi.Int32(0),
+			},
+			expected: map[string]string{
+				clusterAutoscalerNodeTemplateResource + "ephemeral-storage": "128Gi",
+			},
+		},
+		{
+			name: "custom volume size",
+			spec: kops.InstanceGroupSpec{
+				Role:           kops.InstanceGroupRoleNode,
+				MinSize:        fi.Int32(0),
+				RootVolumeSize: fi.Int32(50),
+			},
+			expected: map[string]string{
+				clusterAutoscalerNodeTemplateResource + "ephemeral-storage": "50Gi",
+			},
+		},
+		{
+			name: "windows",
+			spec: kops.InstanceGroupSpec{
+				Role:            kops.InstanceGroupRoleNode,
+				MinSize:         fi.Int32(0),
+				OperatingSystem: kops.OperatingSystemWindows,
+			},
+			expected: map[string]string{
+				clusterAutoscalerNodeTemplateResource + "ephemeral-storage":             "128Gi",
+				nodeidentityaws.ClusterAutoscalerNodeTemplateLabel + "kubernetes.io/os": "windows",
+			},
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			b := &KopsModelContext{
+				IAMModelContext: iam.IAMModelContext{
+					Cluster: &kops.Cluster{
+						ObjectMeta: metav1.ObjectMeta{Name: "minimal.example.com"},
+					},
+				},
+			}
+			ig := &kops.InstanceGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "nodes"},
+				Spec:       g.spec,
+			}
+
+			tags, err := b.CloudTagsForInstanceGroup(ig)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for k, v := range g.expected {
+				if tags[k] != v {
+					t.Errorf("expected tag %q to be %q, got %q", k, v, tags[k])
+				}
+			}
+		})
+	}
+}
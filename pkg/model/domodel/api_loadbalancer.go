@@ -66,6 +66,7 @@ func (b *APILoadBalancerModelBuilder) Build(c *fi.ModelBuilderContext) error {
 		Region:     fi.String(b.Cluster.Spec.Subnets[0].Region),
 		DropletTag: fi.String(clusterMasterTag),
 		Lifecycle:  b.Lifecycle,
+		VPC:        b.LinkToVPC(),
 	}
 	c.AddTask(loadbalancer)
 
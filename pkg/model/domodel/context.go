@@ -16,9 +16,36 @@ limitations under the License.
 
 package domodel
 
-import "k8s.io/kops/pkg/model"
+import (
+	"strings"
+
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/dotasks"
+)
 
 // DigitalOcean Model Context
 type DOModelContext struct {
 	*model.KopsModelContext
 }
+
+// NameForVPC returns the name of the VPC that kOps creates for the cluster
+func (c *DOModelContext) NameForVPC() string {
+	// replace "." with "-" since DO API does not accept "."
+	return "vpc-" + strings.Replace(c.ClusterName(), ".", "-", -1)
+}
+
+// UsesVPC returns true if the droplets and load balancers of the cluster are placed in a VPC of their own, which is
+// the case when the network of the cluster is set explicitly. Clusters created before kOps managed the VPC stay
+// in the default VPC of the region, as their API load balancer cannot be moved to another VPC.
+func (c *DOModelContext) UsesVPC() bool {
+	return c.Cluster.SharedVPC() || c.Cluster.Spec.NetworkCIDR != ""
+}
+
+// LinkToVPC returns the VPC that droplets and load balancers are placed in, or nil for the default VPC of the region
+func (c *DOModelContext) LinkToVPC() *dotasks.VPC {
+	if !c.UsesVPC() {
+		return nil
+	}
+	return &dotasks.VPC{Name: fi.String(c.NameForVPC())}
+}
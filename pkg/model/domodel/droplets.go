@@ -68,6 +68,7 @@ func (d *DropletBuilder) Build(c *fi.ModelBuilderContext) error {
 			SSHKey: fi.String(sshKeyFingerPrint),
 
 			Tags: []string{clusterTag},
+			VPC:  d.LinkToVPC(),
 		}
 
 		if ig.IsMaster() {
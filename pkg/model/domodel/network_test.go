@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package domodel
+
+import (
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/pkg/model/iam"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/dotasks"
+)
+
+func TestNetworkModelBuilder(t *testing.T) {
+	grid := []struct {
+		Description string
+		NetworkCIDR string
+		NetworkID   string
+		Expected    *dotasks.VPC
+	}{
+		{
+			Description: "existing cluster without a network",
+		},
+		{
+			Description: "network CIDR",
+			NetworkCIDR: "10.10.0.0/16",
+			Expected:    &dotasks.VPC{Name: fi.String("vpc-minimal-example-com"), Region: fi.String("nyc1"), IPRange: fi.String("10.10.0.0/16")},
+		},
+		{
+			Description: "existing VPC",
+			NetworkID:   "d0a4b7c2-0000-0000-0000-000000000000",
+			Expected:    &dotasks.VPC{Name: fi.String("vpc-minimal-example-com"), Region: fi.String("nyc1"), ID: fi.String("d0a4b7c2-0000-0000-0000-000000000000"), Shared: fi.Bool(true)},
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.Description, func(t *testing.T) {
+			cluster := &kops.Cluster{}
+			cluster.ObjectMeta.Name = "minimal.example.com"
+			cluster.Spec.CloudProvider = string(kops.CloudProviderDO)
+			cluster.Spec.Subnets = []kops.ClusterSubnetSpec{{Name: "nyc1", Region: "nyc1"}}
+			cluster.Spec.NetworkCIDR = g.NetworkCIDR
+			cluster.Spec.NetworkID = g.NetworkID
+
+			b := &NetworkModelBuilder{
+				DOModelContext: &DOModelContext{KopsModelContext: &model.KopsModelContext{IAMModelContext: iam.IAMModelContext{Cluster: cluster}}},
+				Lifecycle:      fi.LifecycleSync,
+			}
+			c := &fi.ModelBuilderContext{Tasks: make(map[string]fi.Task)}
+			if err := b.Build(c); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			vpc, _ := c.Tasks["VPC/vpc-minimal-example-com"].(*dotasks.VPC)
+			link := b.LinkToVPC()
+			if g.Expected == nil {
+				if len(c.Tasks) != 0 || link != nil {
+					t.Errorf("expected the default VPC of the region to be kept, got tasks %v and link %v", c.Tasks, link)
+				}
+				return
+			}
+			if vpc == nil || link == nil {
+				t.Fatalf("expected a VPC task and link, got tasks %v and link %v", c.Tasks, link)
+			}
+			if fi.StringValue(vpc.Region) != fi.StringValue(g.Expected.Region) || fi.StringValue(vpc.IPRange) != fi.StringValue(g.Expected.IPRange) ||
+				fi.StringValue(vpc.ID) != fi.StringValue(g.Expected.ID) || fi.BoolValue(vpc.Shared) != fi.BoolValue(g.Expected.Shared) {
+				t.Errorf("unexpected VPC %+v, expected %+v", vpc, g.Expected)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gcetasks"
 )
 
@@ -48,7 +49,7 @@ func (b *APILoadBalancerBuilder) Build(c *fi.ModelBuilderContext) error {
 	// OK
 
 	case kops.LoadBalancerTypeInternal:
-		return fmt.Errorf("internal LoadBalancers are not yet supported by kops on GCE")
+		return b.buildInternalLoadBalancer(c)
 
 	default:
 		return fmt.Errorf("unhandled LoadBalancer type %q", lbSpec.Type)
@@ -97,3 +98,95 @@ func (b *APILoadBalancerBuilder) Build(c *fi.ModelBuilderContext) error {
 	return nil
 
 }
+
+// buildInternalLoadBalancer builds a regional internal TCP load balancer in front of the masters,
+// reachable only from within the cluster network.
+func (b *APILoadBalancerBuilder) buildInternalLoadBalancer(c *fi.ModelBuilderContext) error {
+	var subnet *gcetasks.Subnet
+	if gce.UsesIPAliases(b.Cluster) {
+		subnet = b.LinkToIPAliasSubnet()
+	}
+
+	healthCheck := &gcetasks.HealthCheck{
+		Name:      s(b.NameForHealthCheck("api")),
+		Lifecycle: b.Lifecycle,
+		Port:      i64(443),
+	}
+	c.AddTask(healthCheck)
+
+	backendService := &gcetasks.BackendService{
+		Name:                s(b.NameForBackendService("api")),
+		Lifecycle:           b.Lifecycle,
+		LoadBalancingScheme: s("INTERNAL"),
+		Protocol:            s("TCP"),
+		HealthChecks:        []*gcetasks.HealthCheck{healthCheck},
+	}
+	for _, ig := range b.MasterInstanceGroups() {
+		zones, err := b.FindZonesForInstanceGroup(ig)
+		if err != nil {
+			return err
+		}
+		for _, zone := range zones {
+			backendService.InstanceGroupManagers = append(backendService.InstanceGroupManagers, &gcetasks.InstanceGroupManager{
+				Name: s(gce.NameForInstanceGroupManager(b.Cluster, ig, zone)),
+				Zone: s(zone),
+			})
+		}
+	}
+	c.AddTask(backendService)
+
+	ipAddress := &gcetasks.Address{
+		Name:        s(b.NameForIPAddress("api")),
+		Lifecycle:   b.Lifecycle,
+		AddressType: s("INTERNAL"),
+		Subnetwork:  subnet,
+
+		// Ensure the IP address is included in our certificate
+		ForAPIServer: true,
+	}
+	c.AddTask(ipAddress)
+
+	// We keep the same name as the public forwarding rule, so that the API ingress status
+	// (and therefore the kubeconfig and DNS records) are discovered in the same way.
+	forwardingRule := &gcetasks.ForwardingRule{
+		Name:                s(b.NameForForwardingRule("api")),
+		Lifecycle:           b.Lifecycle,
+		LoadBalancingScheme: "INTERNAL",
+		IPProtocol:          "TCP",
+		Ports:               []string{"443"},
+		BackendService:      backendService,
+		IPAddress:           ipAddress,
+		Network:             b.LinkToNetwork(),
+		Subnetwork:          subnet,
+	}
+	c.AddTask(forwardingRule)
+
+	// Allow traffic into the API (port 443) from KubernetesAPIAccess CIDRs
+	{
+		t := &gcetasks.FirewallRule{
+			Name:         s(b.NameForFirewallRule("https-api")),
+			Lifecycle:    b.Lifecycle,
+			Network:      b.LinkToNetwork(),
+			SourceRanges: b.Cluster.Spec.KubernetesAPIAccess,
+			TargetTags:   []string{b.GCETagForRole(kops.InstanceGroupRoleMaster)},
+			Allowed:      []string{"tcp:443"},
+		}
+		c.AddTask(t)
+	}
+
+	// Allow the GCE health checkers to reach the API
+	// https://cloud.google.com/load-balancing/docs/health-check-concepts#ip-ranges
+	{
+		t := &gcetasks.FirewallRule{
+			Name:         s(b.NameForFirewallRule("api-health-check")),
+			Lifecycle:    b.Lifecycle,
+			Network:      b.LinkToNetwork(),
+			SourceRanges: []string{"35.191.0.0/16", "130.211.0.0/22"},
+			TargetTags:   []string{b.GCETagForRole(kops.InstanceGroupRoleMaster)},
+			Allowed:      []string{"tcp:443"},
+		}
+		c.AddTask(t)
+	}
+
+	return nil
+}
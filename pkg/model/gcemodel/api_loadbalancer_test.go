@@ -0,0 +1,202 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcemodel
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/pkg/model/iam"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gcetasks"
+)
+
+func buildInternalLoadBalancerModel(t *testing.T, ipAliases bool) map[string]fi.Task {
+	cluster := &kops.Cluster{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "minimal.example.com",
+		},
+		Spec: kops.ClusterSpec{
+			API: &kops.AccessSpec{
+				LoadBalancer: &kops.LoadBalancerAccessSpec{
+					Type: kops.LoadBalancerTypeInternal,
+				},
+			},
+			KubernetesAPIAccess: []string{"10.0.0.0/8"},
+			Networking:          &kops.NetworkingSpec{},
+			Subnets: []kops.ClusterSubnetSpec{
+				{Name: "us-test1", Region: "us-test1", Type: kops.SubnetTypePublic},
+			},
+		},
+	}
+	if ipAliases {
+		cluster.Spec.Networking.GCE = &kops.GCENetworkingSpec{}
+	}
+
+	var instanceGroups []*kops.InstanceGroup
+	for _, zone := range []string{"us-test1-a", "us-test1-b"} {
+		instanceGroups = append(instanceGroups, &kops.InstanceGroup{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "master-" + zone,
+			},
+			Spec: kops.InstanceGroupSpec{
+				Role:    kops.InstanceGroupRoleMaster,
+				Subnets: []string{"us-test1"},
+				Zones:   []string{zone},
+			},
+		})
+	}
+	instanceGroups = append(instanceGroups, &kops.InstanceGroup{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "nodes",
+		},
+		Spec: kops.InstanceGroupSpec{
+			Role:    kops.InstanceGroupRoleNode,
+			Subnets: []string{"us-test1"},
+			Zones:   []string{"us-test1-a"},
+		},
+	})
+
+	b := &APILoadBalancerBuilder{
+		GCEModelContext: &GCEModelContext{
+			KopsModelContext: &model.KopsModelContext{
+				IAMModelContext: iam.IAMModelContext{Cluster: cluster},
+				InstanceGroups:  instanceGroups,
+			},
+		},
+		Lifecycle: fi.LifecycleSync,
+	}
+	c := &fi.ModelBuilderContext{
+		Tasks: make(map[string]fi.Task),
+	}
+	if err := b.Build(c); err != nil {
+		t.Fatalf("unexpected error building the API load balancer: %v", err)
+	}
+	return c.Tasks
+}
+
+func TestInternalAPILoadBalancer(t *testing.T) {
+	tasks := buildInternalLoadBalancerModel(t, true)
+
+	var keys []string
+	for key := range tasks {
+		keys = append(keys, key)
+	}
+	for _, key := range []string{
+		"HealthCheck/api-minimal-example-com",
+		"BackendService/api-minimal-example-com",
+		"Address/api-minimal-example-com",
+		"ForwardingRule/api-minimal-example-com",
+		"FirewallRule/https-api-minimal-example-com",
+		"FirewallRule/api-health-check-minimal-example-com",
+	} {
+		if tasks[key] == nil {
+			t.Errorf("task %q not found in %v", key, keys)
+		}
+	}
+	if tasks["TargetPool/api-minimal-example-com"] != nil {
+		t.Errorf("internal load balancer should not have a target pool")
+	}
+
+	healthCheck, ok := tasks["HealthCheck/api-minimal-example-com"].(*gcetasks.HealthCheck)
+	if !ok {
+		t.Fatalf("health check not found")
+	}
+	if fi.Int64Value(healthCheck.Port) != 443 {
+		t.Errorf("unexpected health check port %d", fi.Int64Value(healthCheck.Port))
+	}
+
+	backendService, ok := tasks["BackendService/api-minimal-example-com"].(*gcetasks.BackendService)
+	if !ok {
+		t.Fatalf("backend service not found")
+	}
+	if fi.StringValue(backendService.LoadBalancingScheme) != "INTERNAL" || fi.StringValue(backendService.Protocol) != "TCP" {
+		t.Errorf("unexpected backend service: %+v", backendService)
+	}
+	if len(backendService.HealthChecks) != 1 || backendService.HealthChecks[0] != healthCheck {
+		t.Errorf("backend service does not use the health check")
+	}
+	var backends []string
+	for _, igm := range backendService.InstanceGroupManagers {
+		backends = append(backends, fi.StringValue(igm.Zone)+"/"+fi.StringValue(igm.Name))
+	}
+	expectedBackends := []string{
+		"us-test1-a/a-master-us-test1-a-minimal-example-com",
+		"us-test1-b/b-master-us-test1-b-minimal-example-com",
+	}
+	if !reflect.DeepEqual(backends, expectedBackends) {
+		t.Errorf("unexpected backends: expected %v, got %v", expectedBackends, backends)
+	}
+
+	address, ok := tasks["Address/api-minimal-example-com"].(*gcetasks.Address)
+	if !ok {
+		t.Fatalf("address not found")
+	}
+	if fi.StringValue(address.AddressType) != "INTERNAL" || !address.ForAPIServer {
+		t.Errorf("unexpected address: %+v", address)
+	}
+	if address.Subnetwork == nil || fi.StringValue(address.Subnetwork.Name) != "default-minimal-example-com" {
+		t.Errorf("address should be in the IP alias subnet, got %+v", address.Subnetwork)
+	}
+
+	forwardingRule, ok := tasks["ForwardingRule/api-minimal-example-com"].(*gcetasks.ForwardingRule)
+	if !ok {
+		t.Fatalf("forwarding rule not found")
+	}
+	if forwardingRule.LoadBalancingScheme != "INTERNAL" || forwardingRule.IPProtocol != "TCP" {
+		t.Errorf("unexpected forwarding rule: %+v", forwardingRule)
+	}
+	if !reflect.DeepEqual(forwardingRule.Ports, []string{"443"}) {
+		t.Errorf("unexpected forwarding rule ports: %v", forwardingRule.Ports)
+	}
+	if forwardingRule.BackendService != backendService || forwardingRule.IPAddress != address || forwardingRule.TargetPool != nil {
+		t.Errorf("forwarding rule does not forward the address to the backend service")
+	}
+	if fi.StringValue(forwardingRule.Network.Name) != "default" {
+		t.Errorf("unexpected forwarding rule network %q", fi.StringValue(forwardingRule.Network.Name))
+	}
+
+	healthCheckFirewall, ok := tasks["FirewallRule/api-health-check-minimal-example-com"].(*gcetasks.FirewallRule)
+	if !ok {
+		t.Fatalf("health check firewall rule not found")
+	}
+	if !reflect.DeepEqual(healthCheckFirewall.SourceRanges, []string{"35.191.0.0/16", "130.211.0.0/22"}) {
+		t.Errorf("unexpected health check source ranges: %v", healthCheckFirewall.SourceRanges)
+	}
+}
+
+func TestInternalAPILoadBalancerWithoutIPAliases(t *testing.T) {
+	tasks := buildInternalLoadBalancerModel(t, false)
+
+	address, ok := tasks["Address/api-minimal-example-com"].(*gcetasks.Address)
+	if !ok {
+		t.Fatalf("address not found")
+	}
+	if address.Subnetwork != nil {
+		t.Errorf("address should not be in a subnet without IP aliases, got %q", fi.StringValue(address.Subnetwork.Name))
+	}
+	forwardingRule, ok := tasks["ForwardingRule/api-minimal-example-com"].(*gcetasks.ForwardingRule)
+	if !ok {
+		t.Fatalf("forwarding rule not found")
+	}
+	if forwardingRule.Subnetwork != nil {
+		t.Errorf("forwarding rule should not be in a subnet without IP aliases, got %q", fi.StringValue(forwardingRule.Subnetwork.Name))
+	}
+}
@@ -102,6 +102,13 @@ func (b *AutoscalingGroupModelBuilder) buildInstanceTemplate(c *fi.ModelBuilderC
 				},
 			}
 
+			if ig.Spec.ShieldedInstanceConfig != nil {
+				t.ShieldedSecureBoot = ig.Spec.ShieldedInstanceConfig.EnableSecureBoot
+				t.ShieldedVTPM = ig.Spec.ShieldedInstanceConfig.EnableVTPM
+				t.ShieldedIntegrityMonitoring = ig.Spec.ShieldedInstanceConfig.EnableIntegrityMonitoring
+			}
+			t.ConfidentialCompute = ig.Spec.ConfidentialCompute
+
 			nodeRole, err := iam.BuildNodeRoleSubject(ig.Spec.Role, false)
 			if err != nil {
 				return nil, err
@@ -195,32 +202,99 @@ func (b *AutoscalingGroupModelBuilder) splitToZones(ig *kops.InstanceGroup) (map
 		// 1) no support in terraform
 		// 2) we can't steer to specific zones AFAICT, only to all zones in the region
 
-		targetSizes := make([]int, len(zones))
-		totalSize := 0
-		for i := range zones {
-			targetSizes[i] = minSize / len(zones)
-			totalSize += targetSizes[i]
+		return splitAcrossZones(minSize, zones), nil
+	}
+}
+
+// splitAcrossZones spreads count instances as evenly as possible across the zones
+func splitAcrossZones(count int, zones []string) map[string]int {
+	targetSizes := make([]int, len(zones))
+	totalSize := 0
+	for i := range zones {
+		targetSizes[i] = count / len(zones)
+		totalSize += targetSizes[i]
+	}
+	i := 0
+	for {
+		if totalSize >= count {
+			break
 		}
-		i := 0
-		for {
-			if totalSize >= minSize {
-				break
-			}
-			targetSizes[i]++
-			totalSize++
+		targetSizes[i]++
+		totalSize++
 
-			i++
-			if i > len(targetSizes) {
-				i = 0
-			}
+		i++
+		if i > len(targetSizes) {
+			i = 0
 		}
+	}
+
+	countByZone := make(map[string]int)
+	for i, zone := range zones {
+		countByZone[zone] = targetSizes[i]
+	}
+	return countByZone
+}
+
+// buildAutoscaler builds the autoscaler of the instance group manager in the zone, which applies the scaling schedules
+func (b *AutoscalingGroupModelBuilder) buildAutoscaler(ig *kops.InstanceGroup, igm *gcetasks.InstanceGroupManager, zone string, minReplicas int) (*gcetasks.Autoscaler, error) {
+	t := &gcetasks.Autoscaler{
+		Name:                 igm.Name,
+		Lifecycle:            b.Lifecycle,
+		Zone:                 s(zone),
+		InstanceGroupManager: igm,
+		Enabled:              fi.Bool(len(ig.Spec.ScalingSchedules) > 0),
+	}
+	if !fi.BoolValue(t.Enabled) {
+		return t, nil
+	}
+
+	zones, err := b.FindZonesForInstanceGroup(ig)
+	if err != nil {
+		return nil, err
+	}
 
-		instanceCountByZone := make(map[string]int)
-		for i, zone := range zones {
-			instanceCountByZone[zone] = targetSizes[i]
+	maxSize := 1
+	if ig.Spec.MaxSize != nil {
+		maxSize = int(fi.Int32Value(ig.Spec.MaxSize))
+	} else if ig.Spec.Role == kops.InstanceGroupRoleNode {
+		maxSize = 2
+	}
+	maxReplicas := splitAcrossZones(maxSize, zones)[zone]
+	if maxReplicas < minReplicas {
+		maxReplicas = minReplicas
+	}
+
+	t.ScalingSchedules = make(map[string]*gcetasks.AutoscalerScalingSchedule)
+	for _, schedule := range ig.Spec.ScalingSchedules {
+		minRequiredReplicas := splitAcrossZones(int(fi.Int32Value(schedule.MinSize)), zones)[zone]
+		if maxReplicas < minRequiredReplicas {
+			maxReplicas = minRequiredReplicas
+		}
+
+		timeZone := schedule.TimeZone
+		if timeZone == "" {
+			timeZone = "UTC"
+		}
+
+		scalingSchedule := &gcetasks.AutoscalerScalingSchedule{
+			Schedule:            s(schedule.Schedule),
+			TimeZone:            s(timeZone),
+			MinRequiredReplicas: fi.Int64(int64(minRequiredReplicas)),
+		}
+		if schedule.Duration != nil {
+			scalingSchedule.DurationSec = fi.Int64(int64(schedule.Duration.Duration.Seconds()))
 		}
-		return instanceCountByZone, nil
+		t.ScalingSchedules[schedule.Name] = scalingSchedule
 	}
+
+	// The autoscaler needs room for at least one instance
+	if maxReplicas < 1 {
+		maxReplicas = 1
+	}
+	t.MinReplicas = fi.Int64(int64(minReplicas))
+	t.MaxReplicas = fi.Int64(int64(maxReplicas))
+
+	return t, nil
 }
 
 func (b *AutoscalingGroupModelBuilder) Build(c *fi.ModelBuilderContext) error {
@@ -249,15 +323,26 @@ func (b *AutoscalingGroupModelBuilder) Build(c *fi.ModelBuilderContext) error {
 				InstanceTemplate: instanceTemplate,
 			}
 
-			// Attach masters to load balancer if we're using one
+			// Attach masters to load balancer if we're using one.
+			// Internal load balancers reference the instance groups from their backend service instead.
 			switch ig.Spec.Role {
 			case kops.InstanceGroupRoleMaster:
-				if b.UseLoadBalancerForAPI() {
+				if b.UseLoadBalancerForAPI() && b.Cluster.Spec.API.LoadBalancer.Type != kops.LoadBalancerTypeInternal {
 					t.TargetPools = append(t.TargetPools, b.LinkToTargetPool("api"))
 				}
 			}
 
+			if len(ig.Spec.ScalingSchedules) > 0 {
+				t.Autoscaled = fi.Bool(true)
+			}
+
 			c.AddTask(t)
+
+			autoscaler, err := b.buildAutoscaler(ig, t, zone, targetSize)
+			if err != nil {
+				return err
+			}
+			c.AddTask(autoscaler)
 		}
 	}
 
@@ -89,3 +89,11 @@ func (c *GCEModelContext) NameForIPAddress(id string) string {
 func (c *GCEModelContext) NameForFirewallRule(id string) string {
 	return c.SafeObjectName(id)
 }
+
+func (c *GCEModelContext) NameForBackendService(id string) string {
+	return c.SafeObjectName(id)
+}
+
+func (c *GCEModelContext) NameForHealthCheck(id string) string {
+	return c.SafeObjectName(id)
+}
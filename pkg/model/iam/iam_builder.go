@@ -38,6 +38,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/model"
+	"k8s.io/kops/pkg/envelope"
 	"k8s.io/kops/pkg/util/stringorslice"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
@@ -270,13 +271,17 @@ func (r *NodeRoleAPIServer) BuildAWSPolicy(b *PolicyBuilder) (*Policy, error) {
 	}
 
 	if b.Cluster.Spec.Networking != nil && b.Cluster.Spec.Networking.Cilium != nil && b.Cluster.Spec.Networking.Cilium.Ipam == kops.CiliumIpamEni {
-		addCiliumEniPermissions(p, resource)
+		addCiliumEniPermissions(p, resource, b.IAMPrefix())
 	}
 
 	if b.Cluster.Spec.Networking != nil && b.Cluster.Spec.Networking.Calico != nil && (b.Cluster.Spec.Networking.Calico.CrossSubnet || b.Cluster.Spec.Networking.Calico.AWSSrcDstCheck != "") {
 		addCalicoSrcDstCheckPermissions(p)
 	}
 
+	if !b.UseServiceAccountIAM && LoggingUsesAWS(b.Cluster.Spec.Logging) {
+		AddLoggingPermissions(p, b.Cluster.Spec.Logging, b.IAMPrefix())
+	}
+
 	return p, nil
 }
 
@@ -310,6 +315,12 @@ func (r *NodeRoleMaster) BuildAWSPolicy(b *PolicyBuilder) (*Policy, error) {
 		if b.Cluster.Spec.AWSLoadBalancerController != nil && fi.BoolValue(b.Cluster.Spec.AWSLoadBalancerController.Enabled) {
 			AddAWSLoadbalancerControllerPermissions(p, resource, b.Cluster.GetName())
 		}
+		if b.Cluster.Spec.ExternalDNS != nil && b.Cluster.Spec.ExternalDNS.Provider == kops.ExternalDNSProviderExternalDNS {
+			AddExternalDNSPermissions(b, p)
+		}
+		if LoggingUsesAWS(b.Cluster.Spec.Logging) {
+			AddLoggingPermissions(p, b.Cluster.Spec.Logging, b.IAMPrefix())
+		}
 	}
 
 	if b.Cluster.Spec.IAM.AllowContainerRegistry {
@@ -325,7 +336,7 @@ func (r *NodeRoleMaster) BuildAWSPolicy(b *PolicyBuilder) (*Policy, error) {
 	}
 
 	if b.Cluster.Spec.Networking != nil && b.Cluster.Spec.Networking.Cilium != nil && b.Cluster.Spec.Networking.Cilium.Ipam == kops.CiliumIpamEni {
-		addCiliumEniPermissions(p, resource)
+		addCiliumEniPermissions(p, resource, b.IAMPrefix())
 	}
 
 	if b.Cluster.Spec.Networking != nil && b.Cluster.Spec.Networking.Calico != nil && (b.Cluster.Spec.Networking.Calico.CrossSubnet || b.Cluster.Spec.Networking.Calico.AWSSrcDstCheck != "") {
@@ -340,6 +351,22 @@ func (r *NodeRoleMaster) BuildAWSPolicy(b *PolicyBuilder) (*Policy, error) {
 	if b.Cluster.Spec.SnapshotController != nil && fi.BoolValue(b.Cluster.Spec.SnapshotController.Enabled) {
 		addSnapshotPersmissions(p, b.Cluster.GetName())
 	}
+
+	if b.Cluster.Spec.Audit != nil && b.Cluster.Spec.Audit.LogShipping != nil {
+		addAuditLogShippingPermissions(p, b.Cluster.Spec.Audit.LogShipping, b.IAMPrefix())
+	}
+
+	if b.Cluster.Spec.EncryptionAtRest != nil {
+		addEncryptionAtRestPermissions(p, b.Cluster.Spec.EncryptionAtRest)
+	}
+
+	if b.Cluster.Spec.Topology.GetInstanceAccessType() == kops.InstanceAccessTypeSSM {
+		addSSMInstanceAccessPermissions(p)
+	}
+
+	if model.UseKopsControllerForInstanceGroupScaling(b.Cluster) && b.Cluster.Spec.InstanceGroupScaling.StateStoreLockTable != "" {
+		addStateStoreLockPermissions(p, b.Cluster.Spec.InstanceGroupScaling.StateStoreLockTable, b.IAMPrefix())
+	}
 	return p, nil
 }
 
@@ -375,6 +402,14 @@ func (r *NodeRoleNode) BuildAWSPolicy(b *PolicyBuilder) (*Policy, error) {
 		addCalicoSrcDstCheckPermissions(p)
 	}
 
+	if !b.UseServiceAccountIAM && LoggingUsesAWS(b.Cluster.Spec.Logging) {
+		AddLoggingPermissions(p, b.Cluster.Spec.Logging, b.IAMPrefix())
+	}
+
+	if b.Cluster.Spec.Topology.GetInstanceAccessType() == kops.InstanceAccessTypeSSM {
+		addSSMInstanceAccessPermissions(p)
+	}
+
 	return p, nil
 }
 
@@ -455,6 +490,7 @@ func (b *PolicyBuilder) AddS3Permissions(p *Policy) (*Policy, error) {
 	sort.Strings(roots)
 
 	s3Buckets := sets.NewString()
+	readsState := false
 
 	for _, root := range roots {
 		vfsPath, err := vfs.Context.BuildVfsPath(root)
@@ -474,6 +510,7 @@ func (b *PolicyBuilder) AddS3Permissions(p *Policy) (*Policy, error) {
 			}
 
 			if len(resources) != 0 {
+				readsState = true
 				sort.Strings(resources)
 
 				// Add the prefix for IAM
@@ -500,6 +537,17 @@ func (b *PolicyBuilder) AddS3Permissions(p *Policy) (*Policy, error) {
 		}
 	}
 
+	// Secrets and keys encrypted with a KMS key can only be read with decrypt access to that key
+	if readsState && b.Cluster.Spec.SecretStoreEncryption != nil {
+		if keyARN := envelope.AWSKeyARN(b.Cluster.Spec.SecretStoreEncryption.KMSKey); keyARN != "" {
+			p.Statement = append(p.Statement, &Statement{
+				Effect:   StatementEffectAllow,
+				Action:   stringorslice.Slice([]string{"kms:Decrypt"}),
+				Resource: stringorslice.Of(keyARN),
+			})
+		}
+	}
+
 	writeablePaths, err := WriteableVFSPaths(b.Cluster, b.Role)
 	if err != nil {
 		return nil, err
@@ -570,6 +618,16 @@ func WriteableVFSPaths(cluster *kops.Cluster, role Subject) ([]vfs.Path, error)
 
 			backupStores.Insert(backupStore)
 		}
+
+		// kops-controller persists the size limits of instance groups it resizes
+		if model.UseKopsControllerForInstanceGroupScaling(cluster) {
+			configBase, err := vfs.Context.BuildVfsPath(cluster.Spec.ConfigBase)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse VFS path %q: %v", cluster.Spec.ConfigBase, err)
+			}
+
+			paths = append(paths, configBase.Join("instancegroup"))
+		}
 	}
 
 	return paths, nil
@@ -714,6 +772,42 @@ func addCalicoSrcDstCheckPermissions(p *Policy) {
 	})
 }
 
+// addSSMInstanceAccessPermissions allows the SSM agent to register the instance and serve Session Manager sessions
+func addSSMInstanceAccessPermissions(p *Policy) {
+	p.Statement = append(p.Statement, &Statement{
+		Effect: StatementEffectAllow,
+		Action: stringorslice.Of(
+			"ec2messages:AcknowledgeMessage",
+			"ec2messages:DeleteMessage",
+			"ec2messages:FailMessage",
+			"ec2messages:GetEndpoint",
+			"ec2messages:GetMessages",
+			"ec2messages:SendReply",
+			"ssm:UpdateInstanceInformation",
+			"ssmmessages:CreateControlChannel",
+			"ssmmessages:CreateDataChannel",
+			"ssmmessages:OpenControlChannel",
+			"ssmmessages:OpenDataChannel",
+		),
+		Resource: stringorslice.Slice([]string{"*"}),
+	})
+}
+
+// addStateStoreLockPermissions lets kops-controller take the lock of the cluster in the DynamoDB table that locks the S3 state store
+func addStateStoreLockPermissions(p *Policy, table string, iamPrefix string) {
+	p.Statement = append(p.Statement, &Statement{
+		Effect: StatementEffectAllow,
+		Action: stringorslice.Of(
+			"dynamodb:DeleteItem",
+			"dynamodb:GetItem",
+			"dynamodb:PutItem",
+		),
+		Resource: stringorslice.Slice([]string{
+			fmt.Sprintf("%s:dynamodb:*:*:table/%s", iamPrefix, table),
+		}),
+	})
+}
+
 // AddAWSLoadbalancerControllerPermissions adds the permissions needed for the aws load balancer controller to the givnen policy
 func AddAWSLoadbalancerControllerPermissions(p *Policy, resource stringorslice.StringOrSlice, clusterName string) {
 	addMasterEC2Policies(p, resource, clusterName)
@@ -796,16 +890,19 @@ func AddDNSControllerPermissions(b *PolicyBuilder, p *Policy) {
 	}
 
 	// TODO: Route53 currently not supported in China, need to check and fail/return
-	// Remove /hostedzone/ prefix (if present)
-	hostedZoneID := strings.TrimPrefix(b.HostedZoneID, "/")
-	hostedZoneID = strings.TrimPrefix(hostedZoneID, "hostedzone/")
+	zones := []string{b.IAMPrefix() + ":route53:::hostedzone/" + trimHostedZoneID(b.HostedZoneID)}
+
+	// The private zone of split-horizon DNS
+	if b.Cluster.Spec.API != nil && b.Cluster.Spec.API.DNS != nil && b.Cluster.Spec.API.DNS.InternalZoneID != "" {
+		zones = append(zones, b.IAMPrefix()+":route53:::hostedzone/"+trimHostedZoneID(b.Cluster.Spec.API.DNS.InternalZoneID))
+	}
 
 	p.Statement = append(p.Statement, &Statement{
 		Effect: StatementEffectAllow,
 		Action: stringorslice.Of("route53:ChangeResourceRecordSets",
 			"route53:ListResourceRecordSets",
 			"route53:GetHostedZone"),
-		Resource: stringorslice.Slice([]string{b.IAMPrefix() + ":route53:::hostedzone/" + hostedZoneID}),
+		Resource: stringorslice.Slice(zones),
 	})
 
 	p.Statement = append(p.Statement, &Statement{
@@ -822,6 +919,44 @@ func AddDNSControllerPermissions(b *PolicyBuilder, p *Policy) {
 	})
 }
 
+// AddExternalDNSPermissions adds IAM permissions used by external-dns.
+// Changes are limited to the zones of spec.externalDns.zoneIDFilters, or to the zone of the cluster if there are none.
+func AddExternalDNSPermissions(b *PolicyBuilder, p *Policy) {
+	var zoneIDs []string
+	if b.Cluster.Spec.ExternalDNS != nil {
+		zoneIDs = b.Cluster.Spec.ExternalDNS.ZoneIDFilters
+	}
+	if len(zoneIDs) == 0 && b.HostedZoneID != "" {
+		zoneIDs = []string{b.HostedZoneID}
+	}
+	if len(zoneIDs) == 0 {
+		return
+	}
+
+	var zones []string
+	for _, zoneID := range zoneIDs {
+		zones = append(zones, b.IAMPrefix()+":route53:::hostedzone/"+trimHostedZoneID(zoneID))
+	}
+
+	p.Statement = append(p.Statement, &Statement{
+		Effect:   StatementEffectAllow,
+		Action:   stringorslice.Of("route53:ChangeResourceRecordSets", "route53:ListResourceRecordSets"),
+		Resource: stringorslice.Slice(zones),
+	})
+
+	p.Statement = append(p.Statement, &Statement{
+		Effect:   StatementEffectAllow,
+		Action:   stringorslice.Of("route53:ListHostedZones", "route53:ListTagsForResource"),
+		Resource: stringorslice.Slice([]string{"*"}),
+	})
+}
+
+// trimHostedZoneID removes the /hostedzone/ prefix (if present) of a Route53 hosted zone ID
+func trimHostedZoneID(hostedZoneID string) string {
+	hostedZoneID = strings.TrimPrefix(hostedZoneID, "/")
+	return strings.TrimPrefix(hostedZoneID, "hostedzone/")
+}
+
 func addKMSIAMPolicies(p *Policy, resource stringorslice.StringOrSlice) {
 	// TODO could use "kms:ViaService" Condition Key here?
 	p.Statement = append(p.Statement, &Statement{
@@ -1071,7 +1206,7 @@ func addLyftVPCPermissions(p *Policy, resource stringorslice.StringOrSlice, clus
 	)
 }
 
-func addCiliumEniPermissions(p *Policy, resource stringorslice.StringOrSlice) {
+func addCiliumEniPermissions(p *Policy, resource stringorslice.StringOrSlice, iamPrefix string) {
 	p.Statement = append(p.Statement,
 		&Statement{
 			Effect: StatementEffectAllow,
@@ -1082,6 +1217,7 @@ func addCiliumEniPermissions(p *Policy, resource stringorslice.StringOrSlice) {
 				"ec2:UnassignPrivateIpAddresses",
 				"ec2:CreateNetworkInterface",
 				"ec2:DescribeNetworkInterfaces",
+				"ec2:DescribeInstanceTypes",
 				"ec2:DescribeVpcPeeringConnections",
 				"ec2:DescribeSecurityGroups",
 				"ec2:DetachNetworkInterface",
@@ -1091,6 +1227,15 @@ func addCiliumEniPermissions(p *Policy, resource stringorslice.StringOrSlice) {
 			}),
 			Resource: resource,
 		},
+		// The Cilium operator tags the ENIs it creates, so that it can find and garbage collect them
+		&Statement{
+			Effect: StatementEffectAllow,
+			Action: stringorslice.Slice([]string{
+				"ec2:CreateTags",
+			}),
+			Resource: stringorslice.Slice([]string{
+				strings.Join([]string{iamPrefix, ":ec2:*:*:network-interface/*"}, ""),
+			})},
 	)
 }
 
@@ -1124,6 +1269,73 @@ func addAmazonVPCCNIPermissions(p *Policy, resource stringorslice.StringOrSlice,
 	)
 }
 
+// addEncryptionAtRestPermissions allows the KMS plugins on the control plane nodes to use the encryption at rest keys
+func addEncryptionAtRestPermissions(p *Policy, encryption *kops.EncryptionAtRestSpec) {
+	keys := append([]string{encryption.KMSKey}, encryption.PreviousKMSKeys...)
+	p.Statement = append(p.Statement, &Statement{
+		Effect: StatementEffectAllow,
+		Action: stringorslice.Of(
+			"kms:Decrypt",
+			"kms:DescribeKey",
+			"kms:Encrypt",
+		),
+		Resource: stringorslice.Slice(keys),
+	})
+}
+
+// addAuditLogShippingPermissions allows the audit log shipper on the control plane nodes to write to its destination
+func addAuditLogShippingPermissions(p *Policy, shipping *kops.AuditLogShippingSpec, iamPrefix string) {
+	if shipping.S3 != nil {
+		addS3PutObjectPermissions(p, shipping.S3.Bucket, shipping.S3.Prefix, iamPrefix)
+	}
+
+	if shipping.CloudWatch != nil {
+		addCloudWatchLogsPermissions(p, shipping.CloudWatch.LogGroup, iamPrefix)
+	}
+}
+
+// AddLoggingPermissions allows the log shipper to write to its AWS sinks
+func AddLoggingPermissions(p *Policy, logging *kops.LoggingSpec, iamPrefix string) {
+	if logging.S3 != nil {
+		addS3PutObjectPermissions(p, logging.S3.Bucket, logging.S3.Prefix, iamPrefix)
+	}
+
+	if logging.CloudWatch != nil {
+		addCloudWatchLogsPermissions(p, logging.CloudWatch.LogGroup, iamPrefix)
+	}
+}
+
+// LoggingUsesAWS returns true if the log shipper writes to an AWS sink
+func LoggingUsesAWS(logging *kops.LoggingSpec) bool {
+	return logging != nil && fi.BoolValue(logging.Enabled) && (logging.S3 != nil || logging.CloudWatch != nil)
+}
+
+func addS3PutObjectPermissions(p *Policy, bucket string, prefix string, iamPrefix string) {
+	objects := bucket + "/*"
+	if prefix = strings.Trim(prefix, "/"); prefix != "" {
+		objects = bucket + "/" + prefix + "/*"
+	}
+	p.Statement = append(p.Statement, &Statement{
+		Effect:   StatementEffectAllow,
+		Action:   stringorslice.Of("s3:PutObject"),
+		Resource: stringorslice.Slice([]string{strings.Join([]string{iamPrefix, ":s3:::", objects}, "")}),
+	})
+}
+
+func addCloudWatchLogsPermissions(p *Policy, logGroupName string, iamPrefix string) {
+	logGroup := strings.Join([]string{iamPrefix, ":logs:*:*:log-group:", logGroupName}, "")
+	p.Statement = append(p.Statement, &Statement{
+		Effect: StatementEffectAllow,
+		Action: stringorslice.Of(
+			"logs:CreateLogGroup",
+			"logs:CreateLogStream",
+			"logs:DescribeLogStreams",
+			"logs:PutLogEvents",
+		),
+		Resource: stringorslice.Slice([]string{logGroup, logGroup + ":*"}),
+	})
+}
+
 func addNodeTerminationHandlerSQSPermissions(p *Policy, resource stringorslice.StringOrSlice) {
 	p.Statement = append(p.Statement,
 		&Statement{
@@ -204,6 +204,55 @@ func TestPolicyGeneration(t *testing.T) {
 	}
 }
 
+func TestExternalDNSPolicy(t *testing.T) {
+	b := &PolicyBuilder{
+		Cluster: &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				ExternalDNS: &kops.ExternalDNSConfig{
+					Provider:      kops.ExternalDNSProviderExternalDNS,
+					ZoneIDFilters: []string{"/hostedzone/Z1AFAKE1ZON3YO", "Z2AFAKE2ZON3YO"},
+				},
+			},
+		},
+		HostedZoneID: "Z3AFAKE3ZON3YO",
+	}
+
+	p := &Policy{
+		Version: PolicyDefaultVersion,
+	}
+	AddExternalDNSPermissions(b, p)
+
+	actualPolicy, err := p.AsJSON()
+	if err != nil {
+		t.Fatalf("failed to convert generated IAM Policy to JSON. Error: %v", err)
+	}
+
+	golden.AssertMatchesFile(t, actualPolicy, "tests/iam_builder_external_dns.json")
+}
+
+func TestLoggingPolicy(t *testing.T) {
+	logging := &kops.LoggingSpec{
+		Enabled:    fi.Bool(true),
+		CloudWatch: &kops.LoggingCloudWatchSpec{LogGroup: "minimal.example.com"},
+		S3:         &kops.LoggingS3Spec{Bucket: "logs", Prefix: "/clusters/minimal/"},
+	}
+	if !LoggingUsesAWS(logging) {
+		t.Fatalf("expected logging to use AWS")
+	}
+
+	p := &Policy{
+		Version: PolicyDefaultVersion,
+	}
+	AddLoggingPermissions(p, logging, "arn:aws")
+
+	actualPolicy, err := p.AsJSON()
+	if err != nil {
+		t.Fatalf("failed to convert generated IAM Policy to JSON. Error: %v", err)
+	}
+
+	golden.AssertMatchesFile(t, actualPolicy, "tests/iam_builder_logging.json")
+}
+
 func TestEmptyPolicy(t *testing.T) {
 
 	role := &GenericServiceAccount{
@@ -234,3 +283,31 @@ func TestEmptyPolicy(t *testing.T) {
 	}
 
 }
+
+func TestSSMInstanceAccessPolicy(t *testing.T) {
+	p := &Policy{
+		Version: PolicyDefaultVersion,
+	}
+	addSSMInstanceAccessPermissions(p)
+
+	actualPolicy, err := p.AsJSON()
+	if err != nil {
+		t.Fatalf("failed to convert generated IAM Policy to JSON. Error: %v", err)
+	}
+
+	golden.AssertMatchesFile(t, actualPolicy, "tests/iam_builder_ssm.json")
+}
+
+func TestStateStoreLockPolicy(t *testing.T) {
+	p := &Policy{
+		Version: PolicyDefaultVersion,
+	}
+	addStateStoreLockPermissions(p, "kops-locks", "arn:aws")
+
+	actualPolicy, err := p.AsJSON()
+	if err != nil {
+		t.Fatalf("failed to convert generated IAM Policy to JSON. Error: %v", err)
+	}
+
+	golden.AssertMatchesFile(t, actualPolicy, "tests/iam_builder_state_store_lock.json")
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metalmodel
+
+import (
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/metaltasks"
+)
+
+// MachineModelBuilder bootstraps the machines listed in each instance group
+type MachineModelBuilder struct {
+	*MetalModelContext
+
+	BootstrapScriptBuilder *model.BootstrapScriptBuilder
+	Lifecycle              fi.Lifecycle
+}
+
+var _ fi.ModelBuilder = &MachineModelBuilder{}
+
+func (b *MachineModelBuilder) Build(c *fi.ModelBuilderContext) error {
+	for _, ig := range b.InstanceGroups {
+		userData, err := b.BootstrapScriptBuilder.ResourceNodeUp(c, ig)
+		if err != nil {
+			return err
+		}
+
+		for _, machine := range ig.Spec.Machines {
+			t := &metaltasks.Machine{
+				Name:      fi.String(machine.Name),
+				Lifecycle: b.Lifecycle,
+				Address:   fi.String(machine.Address),
+				UserData:  userData,
+			}
+			if machine.SSHUser != "" {
+				t.SSHUser = fi.String(machine.SSHUser)
+			}
+			if machine.SSHHostKeyFingerprint != "" {
+				t.SSHHostKeyFingerprint = fi.String(machine.SSHHostKeyFingerprint)
+			}
+			c.AddTask(t)
+		}
+	}
+
+	return nil
+}
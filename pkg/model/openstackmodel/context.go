@@ -47,6 +47,10 @@ func (c *OpenstackModelContext) createCloud() (openstack.OpenstackCloud, error)
 }
 
 func (c *OpenstackModelContext) UseVIPACL() bool {
+	// The OVN provider does not support allowed CIDRs on listeners
+	if c.IsOVNLoadBalancer() {
+		return false
+	}
 	osCloud, err := c.createCloud()
 	if err != nil {
 		return false
@@ -54,6 +58,12 @@ func (c *OpenstackModelContext) UseVIPACL() bool {
 	return openstackutil.IsOctaviaFeatureSupported(osCloud.LoadBalancerClient(), openstackutil.OctaviaFeatureVIPACL)
 }
 
+// IsOVNLoadBalancer returns true if the API loadbalancer uses the OVN Octavia provider
+func (c *OpenstackModelContext) IsOVNLoadBalancer() bool {
+	os := c.Cluster.Spec.CloudConfig.Openstack
+	return os != nil && os.Loadbalancer != nil && fi.StringValue(os.Loadbalancer.Provider) == "ovn"
+}
+
 func (c *OpenstackModelContext) GetNetworkName() (string, error) {
 	if c.Cluster.Spec.NetworkID == "" {
 		return c.ClusterName(), nil
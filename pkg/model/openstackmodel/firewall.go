@@ -36,6 +36,7 @@ const (
 	IPProtocolUDP   = string(rules.ProtocolUDP)
 	IPV4            = string(rules.EtherType4)
 	ProtocolIPEncap = "4" // IP in IPv4/IPv6
+	ProtocolGRE     = "47"
 )
 
 // FirewallModelBuilder configures firewall network objects
@@ -407,6 +408,23 @@ func (b *FirewallModelBuilder) addCNIRules(c *fi.ModelBuilderContext, sgMap map[
 		if b.Cluster.Spec.Networking.Kuberouter != nil {
 			protocols = append(protocols, ProtocolIPEncap)
 		}
+
+		if b.Cluster.Spec.Networking.Antrea != nil {
+			// Antrea agent and controller APIs
+			tcpPorts = append(tcpPorts, 10349, 10350)
+			switch b.Cluster.Spec.Networking.Antrea.TunnelType {
+			case "", "geneve":
+				udpPorts = append(udpPorts, 6081)
+			case "vxlan":
+				udpPorts = append(udpPorts, 4789)
+			case "gre":
+				protocols = append(protocols, ProtocolGRE)
+			case "stt":
+				tcpPorts = append(tcpPorts, 7471)
+			default:
+				klog.Warningf("unknown antrea tunnel type %q", b.Cluster.Spec.Networking.Antrea.TunnelType)
+			}
+		}
 	}
 
 	masterName := b.SecurityGroupName(kops.InstanceGroupRoleMaster)
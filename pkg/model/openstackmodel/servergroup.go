@@ -91,6 +91,10 @@ func (b *ServerGroupModelBuilder) buildInstances(c *fi.ModelBuilderContext, sg *
 	if err != nil {
 		return fmt.Errorf("could not create startup script for instance group %s: %v", ig.Name, err)
 	}
+	if ig.UsesSSHBootstrap() {
+		// The bootstrap script is still built, as kops toolbox bootstrap runs it on the instances over SSH
+		startupScript = nil
+	}
 
 	var securityGroups []*openstacktasks.SecurityGroup
 	securityGroupName := b.SecurityGroupName(ig.Spec.Role)
@@ -260,10 +264,12 @@ func (b *ServerGroupModelBuilder) Build(c *fi.ModelBuilderContext) error {
 		if lbSubnetName == "" {
 			return fmt.Errorf("could not find subnet for master loadbalancer")
 		}
+		lbSpec := b.Cluster.Spec.CloudConfig.Openstack.Loadbalancer
 		lbTask := &openstacktasks.LB{
 			Name:      fi.String(b.Cluster.Spec.MasterPublicName),
 			Subnet:    fi.String(lbSubnetName),
 			Lifecycle: b.Lifecycle,
+			Provider:  lbSpec.Provider,
 		}
 
 		useVIPACL := b.UseVIPACL()
@@ -276,6 +282,7 @@ func (b *ServerGroupModelBuilder) Build(c *fi.ModelBuilderContext) error {
 		lbfipTask := &openstacktasks.FloatingIP{
 			Name:      fi.String(fmt.Sprintf("%s-%s", "fip", *lbTask.Name)),
 			LB:        lbTask,
+			IP:        lbSpec.FloatingIP,
 			Lifecycle: b.Lifecycle,
 		}
 		c.AddTask(lbfipTask)
@@ -289,6 +296,10 @@ func (b *ServerGroupModelBuilder) Build(c *fi.ModelBuilderContext) error {
 			Loadbalancer: lbTask,
 			Lifecycle:    b.Lifecycle,
 		}
+		if b.IsOVNLoadBalancer() {
+			// The OVN provider does not support round robin balancing
+			poolTask.Method = fi.String("SOURCE_IP_PORT")
+		}
 		c.AddTask(poolTask)
 
 		listenerTask := &openstacktasks.LBListener{
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"strings"
 
+	"k8s.io/kops/pkg/jointoken"
 	"k8s.io/kops/pkg/rbac"
 	"k8s.io/kops/pkg/tokens"
 	"k8s.io/kops/upup/pkg/fi"
@@ -74,6 +75,8 @@ func (b *PKIModelBuilder) Build(c *fi.ModelBuilderContext) error {
 
 	if b.UseEtcdManager() {
 		// We generate keypairs in the etcdmanager task itself
+	} else if b.UseExternalEtcd() {
+		// The keypairs of an external etcd cluster are provided by the user
 	} else if b.UseEtcdTLS() {
 		// check if we need to generate certificates for etcd peers certificates from a different CA?
 		// @question i think we should use another KeyStore for this, perhaps registering a EtcdKeyStore given
@@ -195,6 +198,10 @@ func (b *PKIModelBuilder) Build(c *fi.ModelBuilderContext) error {
 		})
 	}
 
+	if b.UseJoinTokensForNodeBootstrap() {
+		c.AddTask(&fitasks.Secret{Name: fi.String(jointoken.SecretName), Lifecycle: b.Lifecycle})
+	}
+
 	// Create auth tokens (though this is deprecated)
 	for _, x := range tokens.GetKubernetesAuthTokens_Deprecated() {
 		c.AddTask(&fitasks.Secret{Name: fi.String(x), Lifecycle: b.Lifecycle})
@@ -173,9 +173,87 @@ download-release
 echo "== nodeup node config done =="
 `
 
+// WindowsNodeUpTemplate is the PowerShell bootstrap script for Windows instance groups.
+// It is run by EC2Launch on the first boot of the instance.
+var WindowsNodeUpTemplate = `<powershell>
+$ErrorActionPreference = "Stop"
+$ProgressPreference = "SilentlyContinue"
+[Net.ServicePointManager]::SecurityProtocol = [Net.SecurityProtocolType]::Tls12
+
+$NodeUpURL = "{{ NodeUpSourceWindows }}"
+$NodeUpHash = "{{ NodeUpSourceHashWindows }}"
+
+{{ WindowsEnvironmentVariables }}
+
+$InstallDir = "C:\kops"
+
+# Retry a download until we get it. args: file, sha, urls
+function Download-OrBust {
+  param([string]$File, [string]$Hash, [string]$URLs)
+
+  if (Test-Path $File) {
+    if ((Get-FileHash -Algorithm SHA256 -Path $File).Hash.ToLower() -eq $Hash) {
+      return
+    }
+    Remove-Item -Force $File
+  }
+
+  while ($true) {
+    foreach ($url in $URLs.Split(",")) {
+      try {
+        Write-Output "Attempting download of $url"
+        Invoke-WebRequest -UseBasicParsing -Uri $url -OutFile $File
+      } catch {
+        Write-Output "== Download of $url failed: $_ =="
+        continue
+      }
+      $actual = (Get-FileHash -Algorithm SHA256 -Path $File).Hash.ToLower()
+      if ($actual -ne $Hash) {
+        Write-Output "== Hash validation of $url failed. Retrying. =="
+        Remove-Item -Force $File
+      } else {
+        Write-Output "== Downloaded $url (SHA256 = $Hash) =="
+        return
+      }
+    }
+
+    Write-Output "All downloads failed; sleeping before retrying"
+    Start-Sleep -Seconds 60
+  }
+}
+
+####################################################################################
+
+Write-Output "== nodeup node config starting =="
+New-Item -ItemType Directory -Force -Path "$InstallDir\bin","$InstallDir\conf","$InstallDir\cache" | Out-Null
+
+Set-Content -Path "$InstallDir\conf\cluster_spec.yaml" -Value @'
+{{ ClusterSpec }}
+'@
+
+Set-Content -Path "$InstallDir\conf\ig_spec.yaml" -Value @'
+{{ IGSpec }}
+'@
+
+Set-Content -Path "$InstallDir\conf\kube_env.yaml" -Value @'
+{{ KubeEnv }}
+'@
+
+Download-OrBust "$InstallDir\bin\nodeup.exe" $NodeUpHash $NodeUpURL
+
+Write-Output "Running nodeup"
+& "$InstallDir\bin\nodeup.exe" --conf="$InstallDir\conf\kube_env.yaml" --cache="$InstallDir\cache" --v=8
+Write-Output "== nodeup node config done =="
+</powershell>
+<persist>false</persist>
+`
+
 // AWSNodeUpTemplate returns a MIME Multi Part Archive containing the nodeup (bootstrap) script
 // and any additional User Data passed to using AdditionalUserData in the IG Spec
 func AWSNodeUpTemplate(ig *kops.InstanceGroup) (string, error) {
+	if ig.IsWindows() {
+		return WindowsNodeUpTemplate, nil
+	}
 
 	userDataTemplate := NodeUpTemplate
 
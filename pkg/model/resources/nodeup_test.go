@@ -19,6 +19,8 @@ package resources
 import (
 	"strings"
 	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
 )
 
 func Test_NodeUpTabs(t *testing.T) {
@@ -28,3 +30,21 @@ func Test_NodeUpTabs(t *testing.T) {
 		}
 	}
 }
+
+func Test_WindowsNodeUpTemplate(t *testing.T) {
+	for i, line := range strings.Split(WindowsNodeUpTemplate, "\n") {
+		if strings.Contains(line, "\t") {
+			t.Errorf("WindowsNodeUpTemplate contains unexpected character %q on line %d: %q", "\t", i, line)
+		}
+	}
+
+	ig := &kops.InstanceGroup{}
+	ig.Spec.OperatingSystem = kops.OperatingSystemWindows
+	actual, err := AWSNodeUpTemplate(ig)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual != WindowsNodeUpTemplate {
+		t.Errorf("expected the Windows bootstrap script for a Windows instance group")
+	}
+}
@@ -32,6 +32,7 @@ import (
 	expirationcache "k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/nodeidentity"
+	"k8s.io/kops/pkg/nodelabels"
 )
 
 const (
@@ -40,6 +41,8 @@ const (
 	CloudTagInstanceGroupName = "kops.k8s.io/instancegroup"
 	// ClusterAutoscalerNodeTemplateLabel is the prefix used on node labels when copying to cloud tags.
 	ClusterAutoscalerNodeTemplateLabel = "k8s.io/cluster-autoscaler/node-template/label/"
+	// CloudTagScaleDownDisabled is a cloud tag that, when "true", stops cluster autoscaler from removing the node
+	CloudTagScaleDownDisabled = "kops.k8s.io/cluster-autoscaler-scale-down-disabled"
 	// The expiration time of nodeidentity.Info cache.
 	cacheTTL = 60 * time.Minute
 )
@@ -141,14 +144,18 @@ func (i *nodeIdentifier) IdentifyNode(ctx context.Context, node *corev1.Node) (*
 	}
 
 	info := &nodeidentity.Info{
-		InstanceID: instanceID,
-		Labels:     labels,
+		InstanceID:  instanceID,
+		Labels:      labels,
+		Annotations: map[string]string{},
 	}
 
 	for _, tag := range instance.Tags {
 		if strings.HasPrefix(aws.StringValue(tag.Key), ClusterAutoscalerNodeTemplateLabel) {
 			info.Labels[strings.TrimPrefix(aws.StringValue(tag.Key), ClusterAutoscalerNodeTemplateLabel)] = aws.StringValue(tag.Value)
 		}
+		if aws.StringValue(tag.Key) == CloudTagScaleDownDisabled {
+			info.Annotations[nodelabels.ClusterAutoscalerScaleDownDisabledAnnotation] = aws.StringValue(tag.Value)
+		}
 	}
 
 	// If caching is enabled add the nodeidentity.Info to cache.
@@ -34,6 +34,9 @@ import (
 // This is used by the gce nodeidentifier to securely identify the node instancegroup
 const MetadataKeyInstanceGroupName = "kops-k8s-io-instance-group-name"
 
+// MetadataKeyClusterName is the key for the metadata that specifies the cluster name
+const MetadataKeyClusterName = "cluster-name"
+
 // nodeIdentifier identifies a node from GCE
 type nodeIdentifier struct {
 	// computeService is the GCE client
@@ -97,6 +100,36 @@ func (i *nodeIdentifier) IdentifyNode(ctx context.Context, node *corev1.Node) (*
 		return nil, fmt.Errorf("providerID %q did not match our project %q", providerID, i.project)
 	}
 
+	instanceInfo, err := i.identifyInstance(zone, instanceName)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &nodeidentity.LegacyInfo{}
+	info.InstanceGroup = instanceInfo.InstanceGroup
+	return info, nil
+}
+
+// InstanceInfo is the identity of a GCE instance, as established from the managed instance group that created it
+type InstanceInfo struct {
+	// Instance is the GCE instance
+	Instance *compute.Instance
+	// ClusterName is the name of the cluster the instance belongs to
+	ClusterName string
+	// InstanceGroup is the name of the kops InstanceGroup the instance is a member of
+	InstanceGroup string
+}
+
+// IdentifyInstance queries GCE for the identity of a running instance in the project
+func IdentifyInstance(computeService *compute.Service, project string, zone string, instanceName string) (*InstanceInfo, error) {
+	i := &nodeIdentifier{
+		computeService: computeService,
+		project:        project,
+	}
+	return i.identifyInstance(zone, instanceName)
+}
+
+func (i *nodeIdentifier) identifyInstance(zone string, instanceName string) (*InstanceInfo, error) {
 	instance, err := i.getInstance(zone, instanceName)
 	if err != nil {
 		return nil, err
@@ -143,9 +176,11 @@ func (i *nodeIdentifier) IdentifyNode(ctx context.Context, node *corev1.Node) (*
 		return nil, fmt.Errorf("ig name not set on instance template %s", instanceTemplate.Name)
 	}
 
-	info := &nodeidentity.LegacyInfo{}
-	info.InstanceGroup = igName
-	return info, nil
+	return &InstanceInfo{
+		Instance:      instance,
+		ClusterName:   getMetadataValue(instanceTemplate.Properties.Metadata, MetadataKeyClusterName),
+		InstanceGroup: igName,
+	}, nil
 }
 
 // getInstance queries GCE for the instance with the specified name, returning an error if not found
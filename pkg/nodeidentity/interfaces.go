@@ -27,8 +27,9 @@ type Identifier interface {
 }
 
 type Info struct {
-	InstanceID string
-	Labels     map[string]string
+	InstanceID  string
+	Labels      map[string]string
+	Annotations map[string]string
 }
 
 type LegacyIdentifier interface {
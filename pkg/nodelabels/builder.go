@@ -17,6 +17,8 @@ limitations under the License.
 package nodelabels
 
 import (
+	"strconv"
+
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/util/pkg/reflectutils"
@@ -33,6 +35,14 @@ const (
 	RoleLabelNode16      = "node-role.kubernetes.io/node"
 
 	RoleLabelControlPlane20 = "node-role.kubernetes.io/control-plane"
+
+	// NvidiaGPUPresentLabel marks the nodes with NVIDIA GPUs, which the device plugin is scheduled on
+	NvidiaGPUPresentLabel = "nvidia.com/gpu.present"
+	// NvidiaGPUTaint keeps workloads that don't request GPUs off the nodes with NVIDIA GPUs
+	NvidiaGPUTaint = "nvidia.com/gpu"
+
+	// ClusterAutoscalerScaleDownDisabledAnnotation stops cluster autoscaler from removing the node it is set on
+	ClusterAutoscalerScaleDownDisabledAnnotation = "cluster-autoscaler.kubernetes.io/scale-down-disabled"
 )
 
 // BuildNodeLabels returns the node labels for the specified instance group
@@ -81,6 +91,13 @@ func BuildNodeLabels(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) m
 		}
 	}
 
+	if instanceGroup.NvidiaGPUConfig(cluster) != nil {
+		if nodeLabels == nil {
+			nodeLabels = make(map[string]string)
+		}
+		nodeLabels[NvidiaGPUPresentLabel] = "true"
+	}
+
 	for k, v := range instanceGroup.Spec.NodeLabels {
 		if nodeLabels == nil {
 			nodeLabels = make(map[string]string)
@@ -91,6 +108,19 @@ func BuildNodeLabels(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) m
 	return nodeLabels
 }
 
+// BuildNodeAnnotations returns the node annotations for the specified instance group
+func BuildNodeAnnotations(instanceGroup *kops.InstanceGroup) map[string]string {
+	nodeAnnotations := make(map[string]string)
+
+	if value, found := instanceGroup.Annotations[kops.AnnotationClusterAutoscalerScaleDownDisabled]; found && instanceGroup.Spec.Role == kops.InstanceGroupRoleNode {
+		if disabled, err := strconv.ParseBool(value); err == nil {
+			nodeAnnotations[ClusterAutoscalerScaleDownDisabledAnnotation] = strconv.FormatBool(disabled)
+		}
+	}
+
+	return nodeAnnotations
+}
+
 // BuildMandatoryControlPlaneLabels returns the list of labels all CP nodes must have
 func BuildMandatoryControlPlaneLabels() map[string]string {
 	nodeLabels := make(map[string]string)
@@ -20,7 +20,9 @@ import (
 	"reflect"
 	"testing"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
 )
 
 func TestBuildNodeLabels(t *testing.T) {
@@ -111,6 +113,32 @@ func TestBuildNodeLabels(t *testing.T) {
 				"node3":         "override3",
 			},
 		},
+		{
+			name: "RoleNodeWithNvidiaGPU",
+			cluster: &kops.Cluster{
+				Spec: kops.ClusterSpec{
+					KubernetesVersion: "v1.21.0",
+					Containerd: &kops.ContainerdConfig{
+						NvidiaGPU: &kops.NvidiaGPUConfig{
+							Enabled: fi.Bool(true),
+						},
+					},
+				},
+			},
+			ig: &kops.InstanceGroup{
+				Spec: kops.InstanceGroupSpec{
+					Role: kops.InstanceGroupRoleNode,
+					NvidiaGPU: &kops.NvidiaGPUConfig{
+						Enabled: fi.Bool(true),
+					},
+				},
+			},
+			expected: map[string]string{
+				RoleLabelNode16:       "",
+				RoleLabelName15:       RoleNodeLabelValue15,
+				NvidiaGPUPresentLabel: "true",
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -122,3 +150,50 @@ func TestBuildNodeLabels(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildNodeAnnotations(t *testing.T) {
+	tests := []struct {
+		name     string
+		ig       *kops.InstanceGroup
+		expected map[string]string
+	}{
+		{
+			name: "NoAnnotations",
+			ig: &kops.InstanceGroup{
+				Spec: kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleNode},
+			},
+			expected: map[string]string{},
+		},
+		{
+			name: "ScaleDownDisabled",
+			ig: &kops.InstanceGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{kops.AnnotationClusterAutoscalerScaleDownDisabled: "True"},
+				},
+				Spec: kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleNode},
+			},
+			expected: map[string]string{
+				ClusterAutoscalerScaleDownDisabledAnnotation: "true",
+			},
+		},
+		{
+			name: "ScaleDownDisabledOnControlPlane",
+			ig: &kops.InstanceGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{kops.AnnotationClusterAutoscalerScaleDownDisabled: "true"},
+				},
+				Spec: kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleMaster},
+			},
+			expected: map[string]string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out := BuildNodeAnnotations(test.ig)
+			if !reflect.DeepEqual(out, test.expected) {
+				t.Fatalf("Actual result:\n%v\nExpect:\n%v", out, test.expected)
+			}
+		})
+	}
+}
@@ -30,6 +30,7 @@ const (
 	// core kubernetes process identities
 	KubeProxy             = "system:kube-proxy"
 	KubeRouter            = "system:kube-router"
+	CalicoWindows         = "system:calico-windows"
 	KubeControllerManager = "system:kube-controller-manager"
 	KubeScheduler         = "system:kube-scheduler"
 )
@@ -69,8 +69,11 @@ func ListResourcesAWS(cloud awsup.AWSCloud, clusterName string) (map[string]*res
 		// EC2 VPC
 		ListDhcpOptions,
 		ListInternetGateways,
+		ListNetworkInterfaces,
 		ListRouteTables,
 		ListSubnets,
+		ListVPCEndpoints,
+		ListVPCEndpointServices,
 		ListVPCs,
 		// ELBs
 		ListELBs,
@@ -613,6 +616,22 @@ func ListVolumes(cloud fi.Cloud, clusterName string) ([]*resources.Resource, err
 	if err != nil {
 		return nil, err
 	}
+
+	// Volumes created by controllers for PersistentVolumes may not have the kOps tags
+	controllerVolumes, err := describeControllerVolumes(c, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	for _, volume := range volumes {
+		seen[aws.StringValue(volume.VolumeId)] = true
+	}
+	for _, volume := range controllerVolumes {
+		if !seen[aws.StringValue(volume.VolumeId)] {
+			volumes = append(volumes, volume)
+		}
+	}
+
 	var resourceTrackers []*resources.Resource
 
 	elasticIPs := make(map[string]bool)
@@ -620,11 +639,12 @@ func ListVolumes(cloud fi.Cloud, clusterName string) ([]*resources.Resource, err
 		id := aws.StringValue(volume.VolumeId)
 
 		resourceTracker := &resources.Resource{
-			Name:    FindName(volume.Tags),
-			ID:      id,
-			Type:    "volume",
-			Deleter: DeleteVolume,
-			Shared:  HasSharedTag(ec2.ResourceTypeVolume+":"+id, volume.Tags, clusterName),
+			Name:       FindName(volume.Tags),
+			ID:         id,
+			Type:       "volume",
+			Deleter:    DeleteVolume,
+			Shared:     HasSharedTag(ec2.ResourceTypeVolume+":"+id, volume.Tags, clusterName),
+			CreatedFor: createdFor(ec2TagMap(volume.Tags)),
 		}
 
 		var blocks []string
@@ -1425,12 +1445,13 @@ func ListELBs(cloud fi.Cloud, clusterName string) ([]*resources.Resource, error)
 	for _, elb := range elbs {
 		id := aws.StringValue(elb.LoadBalancerName)
 		resourceTracker := &resources.Resource{
-			Name:    FindELBName(elbTags[id]),
-			ID:      id,
-			Type:    TypeLoadBalancer,
-			Deleter: DeleteELB,
-			Dumper:  DumpELB,
-			Obj:     elb,
+			Name:       FindELBName(elbTags[id]),
+			ID:         id,
+			Type:       TypeLoadBalancer,
+			Deleter:    DeleteELB,
+			Dumper:     DumpELB,
+			CreatedFor: createdFor(elbTagMap(elbTags[id])),
+			Obj:        elb,
 		}
 
 		var blocks []string
@@ -1488,7 +1509,7 @@ func DescribeELBs(cloud fi.Cloud) ([]*elb.LoadBalancerDescription, map[string][]
 		for _, t := range tagResponse.TagDescriptions {
 			elbName := aws.StringValue(t.LoadBalancerName)
 
-			if !matchesElbTags(tags, t.Tags) {
+			if !matchesElbTags(tags, t.Tags) && !createdByControllerForCluster(tags[awsup.TagClusterName], elbTagMap(t.Tags)) {
 				continue
 			}
 
@@ -1511,7 +1532,7 @@ func DescribeELBs(cloud fi.Cloud) ([]*elb.LoadBalancerDescription, map[string][]
 
 // For NLBs and ALBs
 func ListELBV2s(cloud fi.Cloud, clusterName string) ([]*resources.Resource, error) {
-	elbv2s, _, err := DescribeELBV2s(cloud)
+	elbv2s, elbv2Tags, err := DescribeELBV2s(cloud)
 	if err != nil {
 		return nil, err
 	}
@@ -1519,13 +1540,15 @@ func ListELBV2s(cloud fi.Cloud, clusterName string) ([]*resources.Resource, erro
 	var resourceTrackers []*resources.Resource
 	for _, elb := range elbv2s {
 		id := aws.StringValue(elb.LoadBalancerName)
+		arn := aws.StringValue(elb.LoadBalancerArn)
 		resourceTracker := &resources.Resource{
-			Name:    id,
-			ID:      string(*elb.LoadBalancerArn),
-			Type:    TypeLoadBalancer,
-			Deleter: DeleteELBV2,
-			Dumper:  DumpELB,
-			Obj:     elb,
+			Name:       id,
+			ID:         arn,
+			Type:       TypeLoadBalancer,
+			Deleter:    DeleteELBV2,
+			Dumper:     DumpELB,
+			CreatedFor: createdFor(elbv2TagMap(elbv2Tags[arn])),
+			Obj:        elb,
 		}
 
 		var blocks []string
@@ -1581,7 +1604,7 @@ func DescribeELBV2s(cloud fi.Cloud) ([]*elbv2.LoadBalancer, map[string][]*elbv2.
 		for _, t := range tagResponse.TagDescriptions {
 
 			elbARN := aws.StringValue(t.ResourceArn)
-			if !matchesElbV2Tags(tags, t.Tags) {
+			if !matchesElbV2Tags(tags, t.Tags) && !createdByControllerForCluster(tags[awsup.TagClusterName], elbv2TagMap(t.Tags)) {
 				continue
 			}
 
@@ -1603,7 +1626,7 @@ func DescribeELBV2s(cloud fi.Cloud) ([]*elbv2.LoadBalancer, map[string][]*elbv2.
 }
 
 func ListTargetGroups(cloud fi.Cloud, clusterName string) ([]*resources.Resource, error) {
-	targetgroups, _, err := DescribeTargetGroups(cloud)
+	targetgroups, targetgroupTags, err := DescribeTargetGroups(cloud)
 	if err != nil {
 		return nil, err
 	}
@@ -1611,13 +1634,15 @@ func ListTargetGroups(cloud fi.Cloud, clusterName string) ([]*resources.Resource
 	var resourceTrackers []*resources.Resource
 	for _, tg := range targetgroups {
 		id := aws.StringValue(tg.TargetGroupName)
+		arn := aws.StringValue(tg.TargetGroupArn)
 		resourceTracker := &resources.Resource{
-			Name:    id,
-			ID:      string(*tg.TargetGroupArn),
-			Type:    TypeTargetGroup,
-			Deleter: DeleteTargetGroup,
-			Dumper:  DumpELB,
-			Obj:     tg,
+			Name:       id,
+			ID:         arn,
+			Type:       TypeTargetGroup,
+			Deleter:    DeleteTargetGroup,
+			Dumper:     DumpELB,
+			CreatedFor: createdFor(elbv2TagMap(targetgroupTags[arn])),
+			Obj:        tg,
 		}
 
 		resourceTrackers = append(resourceTrackers, resourceTracker)
@@ -1662,7 +1687,7 @@ func DescribeTargetGroups(cloud fi.Cloud) ([]*elbv2.TargetGroup, map[string][]*e
 
 		for _, t := range tagResponse.TagDescriptions {
 			tgARN := aws.StringValue(t.ResourceArn)
-			if !matchesElbV2Tags(tags, t.Tags) {
+			if !matchesElbV2Tags(tags, t.Tags) && !createdByControllerForCluster(tags[awsup.TagClusterName], elbv2TagMap(t.Tags)) {
 				continue
 			}
 			targetgroupTags[tgARN] = t.Tags
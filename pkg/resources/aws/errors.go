@@ -26,7 +26,7 @@ func IsDependencyViolation(err error) bool {
 	switch code {
 	case "":
 		return false
-	case "AuthFailure", "DependencyViolation", "InvalidIPAddress.InUse", "VolumeInUse", "ResourceInUse":
+	case "AuthFailure", "DependencyViolation", "InvalidIPAddress.InUse", "VolumeInUse", "ResourceInUse", "InvalidNetworkInterface.InUse":
 		return true
 	default:
 		klog.Infof("unexpected aws error code: %q", code)
@@ -0,0 +1,209 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/resources"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// Tags placed by controllers running in the cluster on the resources they create
+const (
+	// tagServiceName is placed by the AWS cloud provider on the load balancers it creates for Services
+	tagServiceName = "kubernetes.io/service-name"
+	// tagLoadBalancerControllerCluster, tagServiceStack and tagIngressStack are placed by the AWS Load Balancer Controller
+	tagLoadBalancerControllerCluster = "elbv2.k8s.aws/cluster"
+	tagServiceStack                  = "service.k8s.aws/stack"
+	tagIngressStack                  = "ingress.k8s.aws/stack"
+	// tagPVCNamespace, tagPVCName and tagPVName are placed by the EBS volume plugin and the EBS CSI driver
+	tagPVCNamespace = "kubernetes.io/created-for/pvc/namespace"
+	tagPVCName      = "kubernetes.io/created-for/pvc/name"
+	tagPVName       = "kubernetes.io/created-for/pv/name"
+	// tagCSIVolumeName is placed by the EBS CSI driver
+	tagCSIVolumeName = "CSIVolumeName"
+	// tagVPCCNICluster is placed by the Amazon VPC CNI on the network interfaces it creates
+	tagVPCCNICluster = "cluster.k8s.amazonaws.com/name"
+)
+
+const TypeNetworkInterface = "network-interface"
+
+// createdByControllerForCluster returns whether a controller running in the cluster created a resource with the tags
+func createdByControllerForCluster(clusterName string, tags map[string]string) bool {
+	if clusterName == "" {
+		return false
+	}
+	return tags["kubernetes.io/cluster/"+clusterName] == "owned" ||
+		tags[tagLoadBalancerControllerCluster] == clusterName ||
+		tags[tagVPCCNICluster] == clusterName
+}
+
+// createdFor describes what a controller running in the cluster created a resource with the tags for,
+// or returns an empty string if no controller did
+func createdFor(tags map[string]string) string {
+	if name := tags[tagServiceName]; name != "" {
+		return "Service " + name
+	}
+	if name := tags[tagServiceStack]; name != "" {
+		return "Service " + name
+	}
+	if name := tags[tagIngressStack]; name != "" {
+		return "Ingress " + name
+	}
+	if name := tags[tagPVCName]; name != "" {
+		if namespace := tags[tagPVCNamespace]; namespace != "" {
+			name = namespace + "/" + name
+		}
+		return "PersistentVolumeClaim " + name
+	}
+	if name := tags[tagPVName]; name != "" {
+		return "PersistentVolume " + name
+	}
+	if name := tags[tagCSIVolumeName]; name != "" {
+		return "PersistentVolume " + name
+	}
+	if tags[tagVPCCNICluster] != "" {
+		return "Amazon VPC CNI"
+	}
+	return ""
+}
+
+func ec2TagMap(tags []*ec2.Tag) map[string]string {
+	m := make(map[string]string)
+	for _, tag := range tags {
+		m[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return m
+}
+
+func elbTagMap(tags []*elb.Tag) map[string]string {
+	m := make(map[string]string)
+	for _, tag := range tags {
+		m[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return m
+}
+
+func elbv2TagMap(tags []*elbv2.Tag) map[string]string {
+	m := make(map[string]string)
+	for _, tag := range tags {
+		m[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return m
+}
+
+// describeControllerVolumes returns the volumes which controllers running in the cluster created,
+// which are tagged as owned by the cluster but not necessarily with the kOps tags
+func describeControllerVolumes(cloud awsup.AWSCloud, clusterName string) ([]*ec2.Volume, error) {
+	var volumes []*ec2.Volume
+
+	klog.V(2).Infof("Listing EC2 Volumes owned by the cluster")
+	request := &ec2.DescribeVolumesInput{
+		Filters: []*ec2.Filter{awsup.NewEC2Filter("tag:kubernetes.io/cluster/"+clusterName, "owned")},
+	}
+
+	err := cloud.EC2().DescribeVolumesPages(request, func(p *ec2.DescribeVolumesOutput, lastPage bool) bool {
+		volumes = append(volumes, p.Volumes...)
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error describing volumes: %v", err)
+	}
+
+	return volumes, nil
+}
+
+// ListNetworkInterfaces lists the network interfaces the Amazon VPC CNI created for the cluster, which
+// would otherwise prevent the deletion of its subnets and security groups. Interfaces that are deleted
+// along with the instance they are attached to are left out.
+func ListNetworkInterfaces(cloud fi.Cloud, clusterName string) ([]*resources.Resource, error) {
+	c := cloud.(awsup.AWSCloud)
+
+	klog.V(2).Infof("Listing EC2 NetworkInterfaces")
+	request := &ec2.DescribeNetworkInterfacesInput{
+		Filters: []*ec2.Filter{awsup.NewEC2Filter("tag:"+tagVPCCNICluster, clusterName)},
+	}
+
+	var resourceTrackers []*resources.Resource
+	err := c.EC2().DescribeNetworkInterfacesPages(request, func(p *ec2.DescribeNetworkInterfacesOutput, lastPage bool) bool {
+		for _, eni := range p.NetworkInterfaces {
+			if eni.Attachment != nil && aws.BoolValue(eni.Attachment.DeleteOnTermination) {
+				continue
+			}
+
+			id := aws.StringValue(eni.NetworkInterfaceId)
+			tags := ec2TagMap(eni.TagSet)
+
+			resourceTracker := &resources.Resource{
+				Name:       tags["Name"],
+				ID:         id,
+				Type:       TypeNetworkInterface,
+				Deleter:    DeleteNetworkInterface,
+				CreatedFor: createdFor(tags),
+				Obj:        eni,
+			}
+
+			var blocks []string
+			blocks = append(blocks, "subnet:"+aws.StringValue(eni.SubnetId))
+			blocks = append(blocks, "vpc:"+aws.StringValue(eni.VpcId))
+			for _, sg := range eni.Groups {
+				blocks = append(blocks, "security-group:"+aws.StringValue(sg.GroupId))
+			}
+			resourceTracker.Blocks = blocks
+
+			resourceTrackers = append(resourceTrackers, resourceTracker)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error describing network interfaces: %v", err)
+	}
+
+	return resourceTrackers, nil
+}
+
+// DeleteNetworkInterface deletes a network interface; interfaces still attached to an instance being
+// terminated are retried, and those deleted along with their instance are done
+func DeleteNetworkInterface(cloud fi.Cloud, r *resources.Resource) error {
+	c := cloud.(awsup.AWSCloud)
+
+	id := r.ID
+
+	klog.V(2).Infof("Deleting EC2 NetworkInterface %q", id)
+	request := &ec2.DeleteNetworkInterfaceInput{
+		NetworkInterfaceId: &id,
+	}
+	_, err := c.EC2().DeleteNetworkInterface(request)
+	if err != nil {
+		if awsup.AWSErrorCode(err) == "InvalidNetworkInterfaceID.NotFound" {
+			klog.V(2).Infof("Got NotFound error deleting NetworkInterface %q; will treat as already-deleted", id)
+			return nil
+		}
+		if IsDependencyViolation(err) {
+			return err
+		}
+		return fmt.Errorf("error deleting NetworkInterface %q: %v", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/kops/cloudmock/aws/mockec2"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+func TestCreatedFor(t *testing.T) {
+	grid := []struct {
+		tags     map[string]string
+		expected string
+	}{
+		{
+			tags:     map[string]string{"KubernetesCluster": "me.example.com", "Name": "api.me.example.com"},
+			expected: "",
+		},
+		{
+			tags:     map[string]string{"kubernetes.io/cluster/me.example.com": "owned", "kubernetes.io/service-name": "default/web"},
+			expected: "Service default/web",
+		},
+		{
+			tags:     map[string]string{"elbv2.k8s.aws/cluster": "me.example.com", "ingress.k8s.aws/stack": "default/web"},
+			expected: "Ingress default/web",
+		},
+		{
+			tags:     map[string]string{"kubernetes.io/created-for/pvc/namespace": "db", "kubernetes.io/created-for/pvc/name": "data"},
+			expected: "PersistentVolumeClaim db/data",
+		},
+		{
+			tags:     map[string]string{"CSIVolumeName": "pvc-1234"},
+			expected: "PersistentVolume pvc-1234",
+		},
+		{
+			tags:     map[string]string{"cluster.k8s.amazonaws.com/name": "me.example.com"},
+			expected: "Amazon VPC CNI",
+		},
+	}
+	for _, g := range grid {
+		if actual := createdFor(g.tags); actual != g.expected {
+			t.Errorf("tags %v: expected %q, actual %q", g.tags, g.expected, actual)
+		}
+	}
+}
+
+func TestListVolumesCreatedFor(t *testing.T) {
+	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
+	clusterName := "me.example.com"
+
+	c := &mockec2.MockEC2{}
+	cloud.MockEC2 = c
+
+	tagSpecifications := func(tags map[string]string) []*ec2.TagSpecification {
+		spec := &ec2.TagSpecification{ResourceType: aws.String(ec2.ResourceTypeVolume)}
+		for k, v := range tags {
+			spec.Tags = append(spec.Tags, &ec2.Tag{Key: aws.String(k), Value: aws.String(v)})
+		}
+		return []*ec2.TagSpecification{spec}
+	}
+
+	etcd, err := c.CreateVolume(&ec2.CreateVolumeInput{
+		TagSpecifications: tagSpecifications(map[string]string{
+			"KubernetesCluster":                    clusterName,
+			"kubernetes.io/cluster/" + clusterName: "owned",
+		}),
+	})
+	if err != nil {
+		t.Fatalf("error creating volume: %v", err)
+	}
+	pvc, err := c.CreateVolume(&ec2.CreateVolumeInput{
+		TagSpecifications: tagSpecifications(map[string]string{
+			"kubernetes.io/cluster/" + clusterName:    "owned",
+			"kubernetes.io/created-for/pvc/namespace": "db",
+			"kubernetes.io/created-for/pvc/name":      "data",
+		}),
+	})
+	if err != nil {
+		t.Fatalf("error creating volume: %v", err)
+	}
+
+	resourceTrackers, err := ListVolumes(cloud, clusterName)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	createdFor := make(map[string]string)
+	for _, r := range resourceTrackers {
+		if _, found := createdFor[r.ID]; found {
+			t.Errorf("volume %q listed twice", r.ID)
+		}
+		createdFor[r.ID] = r.CreatedFor
+	}
+	expected := map[string]string{
+		aws.StringValue(etcd.VolumeId): "",
+		aws.StringValue(pvc.VolumeId):  "PersistentVolumeClaim db/data",
+	}
+	if len(createdFor) != len(expected) {
+		t.Fatalf("expected volumes %v, actual %v", expected, createdFor)
+	}
+	for id, v := range expected {
+		if createdFor[id] != v {
+			t.Errorf("volume %q: expected created for %q, actual %q", id, v, createdFor[id])
+		}
+	}
+}
+
+func TestListNetworkInterfaces(t *testing.T) {
+	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
+	clusterName := "me.example.com"
+
+	c := &mockec2.MockEC2{}
+	cloud.MockEC2 = c
+
+	create := func(cluster string) string {
+		response, err := c.CreateNetworkInterface(&ec2.CreateNetworkInterfaceInput{
+			SubnetId: aws.String("subnet-1234"),
+			Groups:   []*string{aws.String("sg-1234")},
+			TagSpecifications: []*ec2.TagSpecification{
+				{
+					ResourceType: aws.String(ec2.ResourceTypeNetworkInterface),
+					Tags:         []*ec2.Tag{{Key: aws.String(tagVPCCNICluster), Value: aws.String(cluster)}},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("error creating network interface: %v", err)
+		}
+		return aws.StringValue(response.NetworkInterface.NetworkInterfaceId)
+	}
+	id := create(clusterName)
+	create("other.example.com")
+	attached := create(clusterName)
+	c.NetworkInterfaces[attached].Attachment = &ec2.NetworkInterfaceAttachment{
+		InstanceId:          aws.String("i-1234"),
+		DeleteOnTermination: aws.Bool(true),
+	}
+
+	resourceTrackers, err := ListNetworkInterfaces(cloud, clusterName)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resourceTrackers) != 1 {
+		t.Fatalf("expected 1 network interface, actual %d", len(resourceTrackers))
+	}
+	r := resourceTrackers[0]
+	if r.ID != id || r.Type != TypeNetworkInterface || r.CreatedFor != "Amazon VPC CNI" {
+		t.Errorf("unexpected network interface %+v", r)
+	}
+	blocks := map[string]bool{}
+	for _, block := range r.Blocks {
+		blocks[block] = true
+	}
+	if !blocks["subnet:subnet-1234"] || !blocks["security-group:sg-1234"] {
+		t.Errorf("expected network interface to block its subnet and security group, actual %v", r.Blocks)
+	}
+
+	if err := DeleteNetworkInterface(cloud, r); err != nil {
+		t.Fatalf("error deleting network interface: %v", err)
+	}
+	if c.NetworkInterfaces[id] != nil {
+		t.Errorf("network interface %q was not deleted", id)
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kops/pkg/resources"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+const (
+	TypeVPCEndpoint        = "vpc-endpoint"
+	TypeVPCEndpointService = "vpc-endpoint-service"
+)
+
+func ListVPCEndpointServices(cloud fi.Cloud, clusterName string) ([]*resources.Resource, error) {
+	c := cloud.(awsup.AWSCloud)
+
+	klog.V(2).Infof("Listing EC2 VPC endpoint services")
+	request := &ec2.DescribeVpcEndpointServiceConfigurationsInput{
+		Filters: BuildEC2Filters(cloud),
+	}
+	response, err := c.EC2().DescribeVpcEndpointServiceConfigurations(request)
+	if err != nil {
+		return nil, fmt.Errorf("error listing VPC endpoint services: %v", err)
+	}
+
+	var resourceTrackers []*resources.Resource
+	for _, service := range response.ServiceConfigurations {
+		resourceTracker := &resources.Resource{
+			Name:    FindName(service.Tags),
+			ID:      aws.StringValue(service.ServiceId),
+			Type:    TypeVPCEndpointService,
+			Deleter: DeleteVPCEndpointService,
+			Obj:     service,
+		}
+
+		// The load balancers cannot be deleted while they back a service
+		var blocks []string
+		for _, arn := range service.NetworkLoadBalancerArns {
+			blocks = append(blocks, TypeLoadBalancer+":"+aws.StringValue(arn))
+		}
+		resourceTracker.Blocks = blocks
+
+		resourceTrackers = append(resourceTrackers, resourceTracker)
+	}
+
+	return resourceTrackers, nil
+}
+
+func DeleteVPCEndpointService(cloud fi.Cloud, r *resources.Resource) error {
+	c := cloud.(awsup.AWSCloud)
+
+	id := r.ID
+
+	klog.V(2).Infof("Deleting EC2 VPC endpoint service %q", id)
+	request := &ec2.DeleteVpcEndpointServiceConfigurationsInput{
+		ServiceIds: []*string{&id},
+	}
+	response, err := c.EC2().DeleteVpcEndpointServiceConfigurations(request)
+	if err != nil {
+		if awsup.AWSErrorCode(err) == "InvalidVpcEndpointServiceId.NotFound" {
+			// Concurrently deleted
+			return nil
+		}
+		return fmt.Errorf("error deleting VPC endpoint service %q: %v", id, err)
+	}
+	for _, item := range response.Unsuccessful {
+		if item.Error != nil {
+			// Usually because endpoints are still connected to the service, so we retry
+			return fmt.Errorf("error deleting VPC endpoint service %q: %s", id, aws.StringValue(item.Error.Message))
+		}
+	}
+	return nil
+}
+
+func ListVPCEndpoints(cloud fi.Cloud, clusterName string) ([]*resources.Resource, error) {
+	c := cloud.(awsup.AWSCloud)
+
+	klog.V(2).Infof("Listing EC2 VPC endpoints")
+	request := &ec2.DescribeVpcEndpointsInput{
+		Filters: BuildEC2Filters(cloud),
+	}
+	response, err := c.EC2().DescribeVpcEndpoints(request)
+	if err != nil {
+		return nil, fmt.Errorf("error listing VPC endpoints: %v", err)
+	}
+
+	var resourceTrackers []*resources.Resource
+	for _, endpoint := range response.VpcEndpoints {
+		switch aws.StringValue(endpoint.State) {
+		case "Deleted", "Deleting":
+			continue
+		}
+
+		resourceTracker := &resources.Resource{
+			Name:    FindName(endpoint.Tags),
+			ID:      aws.StringValue(endpoint.VpcEndpointId),
+			Type:    TypeVPCEndpoint,
+			Deleter: DeleteVPCEndpoint,
+			Obj:     endpoint,
+		}
+
+		var blocks []string
+		for _, subnet := range endpoint.SubnetIds {
+			blocks = append(blocks, "subnet:"+aws.StringValue(subnet))
+		}
+		for _, group := range endpoint.Groups {
+			blocks = append(blocks, "security-group:"+aws.StringValue(group.GroupId))
+		}
+		blocks = append(blocks, "vpc:"+aws.StringValue(endpoint.VpcId))
+		resourceTracker.Blocks = blocks
+
+		resourceTrackers = append(resourceTrackers, resourceTracker)
+	}
+
+	// Endpoints must be removed before the service they connect to
+	services, err := ListVPCEndpointServices(cloud, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	for _, resourceTracker := range resourceTrackers {
+		endpoint := resourceTracker.Obj.(*ec2.VpcEndpoint)
+		for _, service := range services {
+			if aws.StringValue(service.Obj.(*ec2.ServiceConfiguration).ServiceName) == aws.StringValue(endpoint.ServiceName) {
+				resourceTracker.Blocks = append(resourceTracker.Blocks, TypeVPCEndpointService+":"+service.ID)
+			}
+		}
+	}
+
+	return resourceTrackers, nil
+}
+
+func DeleteVPCEndpoint(cloud fi.Cloud, r *resources.Resource) error {
+	c := cloud.(awsup.AWSCloud)
+
+	id := r.ID
+
+	klog.V(2).Infof("Deleting EC2 VPC endpoint %q", id)
+	request := &ec2.DeleteVpcEndpointsInput{
+		VpcEndpointIds: []*string{&id},
+	}
+	response, err := c.EC2().DeleteVpcEndpoints(request)
+	if err != nil {
+		if awsup.AWSErrorCode(err) == "InvalidVpcEndpointId.NotFound" {
+			// Concurrently deleted
+			return nil
+		}
+		return fmt.Errorf("error deleting VPC endpoint %q: %v", id, err)
+	}
+	for _, item := range response.Unsuccessful {
+		if item.Error != nil {
+			return fmt.Errorf("error deleting VPC endpoint %q: %s", id, aws.StringValue(item.Error.Message))
+		}
+	}
+	return nil
+}
@@ -39,6 +39,7 @@ const (
 	resourceTypeVolume       = "volume"
 	resourceTypeDNSRecord    = "dns-record"
 	resourceTypeLoadBalancer = "loadbalancer"
+	resourceTypeVPC          = "vpc"
 )
 
 type listFn func(fi.Cloud, string) ([]*resources.Resource, error)
@@ -51,6 +52,7 @@ func ListResources(cloud do.DOCloud, clusterName string) (map[string]*resources.
 		listDroplets,
 		listDNS,
 		listLoadBalancers,
+		listVPCs,
 	}
 
 	for _, fn := range listFunctions {
@@ -87,6 +89,10 @@ func listDroplets(cloud fi.Cloud, clusterName string) ([]*resources.Resource, er
 			Obj:     droplet,
 		}
 
+		if droplet.VPCUUID != "" {
+			resourceTracker.Blocks = append(resourceTracker.Blocks, resourceTypeVPC+":"+droplet.VPCUUID)
+		}
+
 		resourceTrackers = append(resourceTrackers, resourceTracker)
 	}
 
@@ -236,6 +242,9 @@ func listLoadBalancers(cloud fi.Cloud, clusterName string) ([]*resources.Resourc
 			for _, dropletID := range lb.DropletIDs {
 				blocks = append(blocks, "droplet:"+strconv.Itoa(dropletID))
 			}
+			if lb.VPCUUID != "" {
+				blocks = append(blocks, resourceTypeVPC+":"+lb.VPCUUID)
+			}
 
 			resourceTracker.Blocks = blocks
 			resourceTrackers = append(resourceTrackers, resourceTracker)
@@ -245,6 +254,48 @@ func listLoadBalancers(cloud fi.Cloud, clusterName string) ([]*resources.Resourc
 	return resourceTrackers, nil
 }
 
+func listVPCs(cloud fi.Cloud, clusterName string) ([]*resources.Resource, error) {
+	c := cloud.(do.DOCloud)
+	var resourceTrackers []*resources.Resource
+
+	// VPCs cannot be tagged, so we only delete the VPC that kOps created with the cluster name
+	vpcName := "vpc-" + strings.Replace(clusterName, ".", "-", -1)
+
+	opt := &godo.ListOptions{}
+	for {
+		vpcs, resp, err := c.VPCsService().List(context.TODO(), opt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list vpcs: %v", err)
+		}
+
+		for _, vpc := range vpcs {
+			if vpc.Name != vpcName {
+				continue
+			}
+			resourceTrackers = append(resourceTrackers, &resources.Resource{
+				Name:    vpc.Name,
+				ID:      vpc.ID,
+				Type:    resourceTypeVPC,
+				Deleter: deleteVPC,
+				Obj:     vpc,
+			})
+		}
+
+		if resp.Links == nil || resp.Links.IsLastPage() {
+			break
+		}
+
+		page, err := resp.Links.CurrentPage()
+		if err != nil {
+			return nil, err
+		}
+
+		opt.Page = page + 1
+	}
+
+	return resourceTrackers, nil
+}
+
 func deleteDroplet(cloud fi.Cloud, t *resources.Resource) error {
 	c := cloud.(do.DOCloud)
 	dropletID, err := strconv.Atoi(t.ID)
@@ -305,6 +356,16 @@ func deleteLoadBalancer(cloud fi.Cloud, t *resources.Resource) error {
 	return nil
 }
 
+func deleteVPC(cloud fi.Cloud, t *resources.Resource) error {
+	c := cloud.(do.DOCloud)
+	_, err := c.VPCsService().Delete(context.TODO(), t.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete vpc with name %s %v", t.Name, err)
+	}
+
+	return nil
+}
+
 func waitForDetach(cloud do.DOCloud, action *godo.Action) error {
 	timeout := time.After(10 * time.Second)
 	ticker := time.NewTicker(500 * time.Millisecond)
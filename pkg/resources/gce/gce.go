@@ -38,9 +38,12 @@ const (
 	typeInstanceTemplate     = "InstanceTemplate"
 	typeDisk                 = "Disk"
 	typeInstanceGroupManager = "InstanceGroupManager"
+	typeAutoscaler           = "Autoscaler"
 	typeTargetPool           = "TargetPool"
 	typeFirewallRule         = "FirewallRule"
 	typeForwardingRule       = "ForwardingRule"
+	typeBackendService       = "BackendService"
+	typeHealthCheck          = "HealthCheck"
 	typeAddress              = "Address"
 	typeRoute                = "Route"
 	typeSubnet               = "Subnet"
@@ -91,6 +94,8 @@ func ListResourcesGCE(gceCloud gce.GCECloud, clusterName string, region string)
 		d.listInstanceGroupManagersAndInstances,
 		d.listTargetPools,
 		d.listForwardingRules,
+		d.listBackendServices,
+		d.listHealthChecks,
 		d.listFirewallRules,
 		d.listGCEDisks,
 		d.listGCEDNSZone,
@@ -203,6 +208,18 @@ func (d *clusterDiscoveryGCE) listInstanceGroupManagersAndInstances() ([]*resour
 		if err != nil {
 			return nil, fmt.Errorf("error listing InstanceGroupManagers: %v", err)
 		}
+
+		// Autoscalers keyed by the URL of the InstanceGroupManager they target
+		autoscalers := make(map[string]*compute.Autoscaler)
+		{
+			l, err := c.Compute().Autoscalers().List(ctx, project, zoneName)
+			if err != nil {
+				return nil, fmt.Errorf("error listing Autoscalers: %v", err)
+			}
+			for _, a := range l {
+				autoscalers[a.Target] = a
+			}
+		}
 		for i := range is {
 			mig := is[i] // avoid closure-in-loop go-tcha
 			instanceTemplate := instanceTemplates[mig.InstanceTemplate]
@@ -224,6 +241,21 @@ func (d *clusterDiscoveryGCE) listInstanceGroupManagersAndInstances() ([]*resour
 			klog.V(4).Infof("Found resource: %s", mig.SelfLink)
 			resourceTrackers = append(resourceTrackers, resourceTracker)
 
+			// The InstanceGroupManager can't be deleted while an Autoscaler targets it
+			if autoscaler := autoscalers[mig.SelfLink]; autoscaler != nil {
+				autoscalerTracker := &resources.Resource{
+					Name:    autoscaler.Name,
+					ID:      zoneName + "/" + autoscaler.Name,
+					Type:    typeAutoscaler,
+					Deleter: func(cloud fi.Cloud, r *resources.Resource) error { return gce.DeleteAutoscaler(c, autoscaler) },
+					Obj:     autoscaler,
+					Blocks:  []string{typeInstanceGroupManager + ":" + resourceTracker.ID},
+				}
+
+				klog.V(4).Infof("Found resource: %s", autoscaler.SelfLink)
+				resourceTrackers = append(resourceTrackers, autoscalerTracker)
+			}
+
 			instanceTrackers, err := d.listManagedInstances(mig)
 			if err != nil {
 				return nil, fmt.Errorf("error listing instances in InstanceGroupManager: %v", err)
@@ -445,6 +477,10 @@ func (d *clusterDiscoveryGCE) listForwardingRules() ([]*resources.Resource, erro
 			resourceTracker.Blocks = append(resourceTracker.Blocks, typeTargetPool+":"+gce.LastComponent(fr.Target))
 		}
 
+		if fr.BackendService != "" {
+			resourceTracker.Blocks = append(resourceTracker.Blocks, typeBackendService+":"+gce.LastComponent(fr.BackendService))
+		}
+
 		if fr.IPAddress != "" {
 			resourceTracker.Blocks = append(resourceTracker.Blocks, typeAddress+":"+gce.LastComponent(fr.IPAddress))
 		}
@@ -478,6 +514,126 @@ func deleteForwardingRule(cloud fi.Cloud, r *resources.Resource) error {
 	return c.WaitForOp(op)
 }
 
+func (d *clusterDiscoveryGCE) listBackendServices() ([]*resources.Resource, error) {
+	c := d.gceCloud
+
+	var resourceTrackers []*resources.Resource
+
+	ctx := context.Background()
+
+	bss, err := c.Compute().RegionBackendServices().List(ctx, c.Project(), c.Region())
+	if err != nil {
+		return nil, fmt.Errorf("error listing BackendServices: %v", err)
+	}
+
+	for _, bs := range bss {
+		if !d.matchesClusterName(bs.Name) {
+			continue
+		}
+
+		resourceTracker := &resources.Resource{
+			Name:    bs.Name,
+			ID:      bs.Name,
+			Type:    typeBackendService,
+			Deleter: deleteBackendService,
+			Obj:     bs,
+		}
+
+		for _, hc := range bs.HealthChecks {
+			resourceTracker.Blocks = append(resourceTracker.Blocks, typeHealthCheck+":"+gce.LastComponent(hc))
+		}
+
+		for _, b := range bs.Backends {
+			u, err := gce.ParseGoogleCloudURL(b.Group)
+			if err != nil {
+				return nil, err
+			}
+			resourceTracker.Blocks = append(resourceTracker.Blocks, typeInstanceGroupManager+":"+u.Zone+"/"+u.Name)
+		}
+
+		klog.V(4).Infof("Found resource: %s", bs.SelfLink)
+		resourceTrackers = append(resourceTrackers, resourceTracker)
+	}
+
+	return resourceTrackers, nil
+}
+
+func deleteBackendService(cloud fi.Cloud, r *resources.Resource) error {
+	c := cloud.(gce.GCECloud)
+	t := r.Obj.(*compute.BackendService)
+
+	klog.V(2).Infof("Deleting GCE BackendService %s", t.SelfLink)
+	u, err := gce.ParseGoogleCloudURL(t.SelfLink)
+	if err != nil {
+		return err
+	}
+
+	op, err := c.Compute().RegionBackendServices().Delete(u.Project, u.Region, u.Name)
+	if err != nil {
+		if gce.IsNotFound(err) {
+			klog.Infof("BackendService not found, assuming deleted: %q", t.SelfLink)
+			return nil
+		}
+		return fmt.Errorf("error deleting BackendService %s: %v", t.SelfLink, err)
+	}
+
+	return c.WaitForOp(op)
+}
+
+func (d *clusterDiscoveryGCE) listHealthChecks() ([]*resources.Resource, error) {
+	c := d.gceCloud
+
+	var resourceTrackers []*resources.Resource
+
+	ctx := context.Background()
+
+	hcs, err := c.Compute().HealthChecks().List(ctx, c.Project())
+	if err != nil {
+		return nil, fmt.Errorf("error listing HealthChecks: %v", err)
+	}
+
+	for _, hc := range hcs {
+		if !d.matchesClusterName(hc.Name) {
+			continue
+		}
+
+		resourceTracker := &resources.Resource{
+			Name:    hc.Name,
+			ID:      hc.Name,
+			Type:    typeHealthCheck,
+			Deleter: deleteHealthCheck,
+			Obj:     hc,
+		}
+
+		klog.V(4).Infof("Found resource: %s", hc.SelfLink)
+		resourceTrackers = append(resourceTrackers, resourceTracker)
+	}
+
+	return resourceTrackers, nil
+}
+
+func deleteHealthCheck(cloud fi.Cloud, r *resources.Resource) error {
+	c := cloud.(gce.GCECloud)
+	t := r.Obj.(*compute.HealthCheck)
+
+	klog.V(2).Infof("Deleting GCE HealthCheck %s", t.SelfLink)
+	u, err := gce.ParseGoogleCloudURL(t.SelfLink)
+	if err != nil {
+		return err
+	}
+
+	op, err := c.Compute().HealthChecks().Delete(u.Project, u.Name)
+	if err != nil {
+		if gce.IsNotFound(err) {
+			klog.Infof("HealthCheck not found, assuming deleted: %q", t.SelfLink)
+			return nil
+		}
+		return fmt.Errorf("error deleting HealthCheck %s: %v", t.SelfLink, err)
+	}
+
+	return c.WaitForOp(op)
+}
+
 // listFirewallRules discovers Firewall objects for the cluster
 func (d *clusterDiscoveryGCE) listFirewallRules() ([]*resources.Resource, error) {
 	c := d.gceCloud
@@ -17,6 +17,8 @@ limitations under the License.
 package openstack
 
 import (
+	"strings"
+
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
 	l3floatingip "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
 	"k8s.io/kops/pkg/resources"
@@ -43,6 +45,14 @@ func (os *clusterDiscoveryOS) listL3FloatingIPs(routerID string) ([]*resources.R
 		return resourceTrackers, err
 	}
 	for _, floatingIP := range floatingIPs {
+		// Pre-existing floating IPs attached to the cluster were not created by kOps; the one attached to
+		// the API load balancer usually has no description, so it is recognized by its address
+		if floatingIP.Description != "" && !strings.HasPrefix(floatingIP.Description, "fip-") {
+			continue
+		}
+		if os.apiFloatingIP != "" && (floatingIP.FloatingIP == os.apiFloatingIP || floatingIP.ID == os.apiFloatingIP) {
+			continue
+		}
 		if floatingIP.RouterID == routerID {
 			resourceTracker := &resources.Resource{
 				Name:    floatingIP.FloatingIP,
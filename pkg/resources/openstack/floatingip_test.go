@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openstack
+
+import (
+	"testing"
+
+	l3floatingip "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/routers"
+	"k8s.io/kops/cloudmock/openstack/mocknetworking"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
+)
+
+func TestListL3FloatingIPs(t *testing.T) {
+	cloud := openstack.BuildMockOpenstackCloud("us-test1")
+	cloud.MockNeutronClient = mocknetworking.CreateClient()
+
+	router, err := cloud.CreateRouter(routers.CreateOpts{
+		Name:         "minimal-example-com",
+		AdminStateUp: fi.Bool(true),
+		GatewayInfo:  &routers.GatewayInfo{NetworkID: "external"},
+	})
+	if err != nil {
+		t.Fatalf("error creating router: %v", err)
+	}
+
+	for _, opts := range []l3floatingip.CreateOpts{
+		// Created by kOps for the API load balancer
+		{FloatingNetworkID: "external", FloatingIP: "203.0.113.1", PortID: "lb-port", Description: "fip-api-minimal-example-com"},
+		// Pre-existing floating IP set in spec.cloudConfig.openstack.loadbalancer.floatingIP
+		{FloatingNetworkID: "external", FloatingIP: "203.0.113.2", PortID: "lb-port"},
+		// Pre-existing floating IP of the user
+		{FloatingNetworkID: "external", FloatingIP: "203.0.113.3", PortID: "other-port", Description: "bastion"},
+		// Not attached to the cluster
+		{FloatingNetworkID: "other", FloatingIP: "198.51.100.1", PortID: "other-port"},
+	} {
+		if _, err := cloud.CreateL3FloatingIP(opts); err != nil {
+			t.Fatalf("error creating floating IP: %v", err)
+		}
+	}
+
+	os := &clusterDiscoveryOS{
+		cloud:         cloud,
+		osCloud:       cloud,
+		clusterName:   "minimal.example.com",
+		apiFloatingIP: "203.0.113.2",
+	}
+	trackers, err := os.listL3FloatingIPs(router.ID)
+	if err != nil {
+		t.Fatalf("error listing floating IPs: %v", err)
+	}
+
+	var names []string
+	for _, tracker := range trackers {
+		names = append(names, tracker.Name)
+	}
+	if len(names) != 1 || names[0] != "203.0.113.1" {
+		t.Errorf("expected only the floating IP created by kOps, got %v", names)
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package openstack
 
 import (
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/resources"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
@@ -28,16 +29,21 @@ type clusterDiscoveryOS struct {
 	cloud       fi.Cloud
 	osCloud     openstack.OpenstackCloud
 	clusterName string
+	// apiFloatingIP is the pre-existing floating IP that the cluster attaches to its API load balancer
+	apiFloatingIP string
 }
 
 // ListResources lists the OpenStack resources kops manages
-func ListResources(cloud openstack.OpenstackCloud, clusterName string) (map[string]*resources.Resource, error) {
+func ListResources(cloud openstack.OpenstackCloud, cluster *kops.Cluster) (map[string]*resources.Resource, error) {
 	resources := make(map[string]*resources.Resource)
 
 	os := &clusterDiscoveryOS{
 		cloud:       cloud,
 		osCloud:     cloud,
-		clusterName: clusterName,
+		clusterName: cluster.Name,
+	}
+	if c := cluster.Spec.CloudConfig; c != nil && c.Openstack != nil && c.Openstack.Loadbalancer != nil {
+		os.apiFloatingIP = fi.StringValue(c.Openstack.Loadbalancer.FloatingIP)
 	}
 
 	listFunctions := []openstackListFn{
@@ -47,7 +47,7 @@ func ListResources(cloud fi.Cloud, cluster *kops.Cluster, region string) (map[st
 	case kops.CloudProviderGCE:
 		return gce.ListResourcesGCE(cloud.(cloudgce.GCECloud), clusterName, region)
 	case kops.CloudProviderOpenstack:
-		return openstack.ListResources(cloud.(cloudopenstack.OpenstackCloud), clusterName)
+		return openstack.ListResources(cloud.(cloudopenstack.OpenstackCloud), cluster)
 	case kops.CloudProviderALI:
 		return ali.ListResourcesALI(cloud.(cloudali.ALICloud), clusterName, region)
 	case kops.CloudProviderAzure:
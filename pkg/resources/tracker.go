@@ -28,6 +28,10 @@ type Resource struct {
 	// If true, this resource is not owned by the cluster
 	Shared bool
 
+	// CreatedFor describes what a controller running in the cluster created this resource for, such as a Service or a
+	// PersistentVolumeClaim; it is empty for resources created by kOps
+	CreatedFor string
+
 	Blocks  []string
 	Blocked []string
 	Done    bool
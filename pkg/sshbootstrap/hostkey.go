@@ -0,0 +1,59 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sshbootstrap
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// HostKeyCallback accepts the host key with the fingerprint or, if the fingerprint is empty,
+// the host keys listed in the known_hosts file. Unknown machines are rejected rather than trusted,
+// because the bootstrap script carries the credentials to join the cluster.
+func HostKeyCallback(fingerprint string, knownHostsPath string) ssh.HostKeyCallback {
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		actual := ssh.FingerprintSHA256(key)
+
+		if fingerprint != "" {
+			if actual != fingerprint {
+				return fmt.Errorf("host key of %s has fingerprint %s, but %s was expected", hostname, actual, fingerprint)
+			}
+			return nil
+		}
+
+		callback, err := knownhosts.New(knownHostsPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return fmt.Errorf("host key of %s (%s) cannot be verified, as %s does not exist; add the verified host key to it", hostname, actual, knownHostsPath)
+			}
+			return fmt.Errorf("error reading known hosts from %s: %v", knownHostsPath, err)
+		}
+
+		err = callback(hostname, remote, key)
+		if keyErr, ok := err.(*knownhosts.KeyError); ok {
+			if len(keyErr.Want) == 0 {
+				return fmt.Errorf("host key of %s (%s) is not in %s; add the verified host key to it", hostname, actual, knownHostsPath)
+			}
+			return fmt.Errorf("host key of %s (%s) does not match the key in %s:%d; the machine may have been reinstalled, or the connection intercepted", hostname, actual, keyErr.Want[0].Filename, keyErr.Want[0].Line)
+		}
+		return err
+	}
+}
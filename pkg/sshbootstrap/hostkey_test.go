@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sshbootstrap
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+func newHostKey(t *testing.T) ssh.PublicKey {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	key, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatalf("error building public key: %v", err)
+	}
+	return key
+}
+
+func TestHostKeyCallback(t *testing.T) {
+	dir, err := ioutil.TempDir("", "known_hosts")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	known := newHostKey(t)
+	other := newHostKey(t)
+
+	knownHostsPath := filepath.Join(dir, "known_hosts")
+	if err := ioutil.WriteFile(knownHostsPath, []byte(knownhosts.Line([]string{"192.0.2.1"}, known)+"\n"), 0600); err != nil {
+		t.Fatalf("error writing known_hosts: %v", err)
+	}
+
+	grid := []struct {
+		Name           string
+		Fingerprint    string
+		KnownHostsPath string
+		Host           string
+		Key            ssh.PublicKey
+		ExpectedError  string
+	}{
+		{
+			Name:        "matching fingerprint",
+			Fingerprint: ssh.FingerprintSHA256(known),
+			Host:        "192.0.2.2",
+			Key:         known,
+		},
+		{
+			Name:          "other fingerprint",
+			Fingerprint:   ssh.FingerprintSHA256(known),
+			Host:          "192.0.2.1",
+			Key:           other,
+			ExpectedError: "was expected",
+		},
+		{
+			Name:           "known host",
+			KnownHostsPath: knownHostsPath,
+			Host:           "192.0.2.1",
+			Key:            known,
+		},
+		{
+			Name:           "known host with other key",
+			KnownHostsPath: knownHostsPath,
+			Host:           "192.0.2.1",
+			Key:            other,
+			ExpectedError:  "does not match the key in",
+		},
+		{
+			Name:           "unknown host",
+			KnownHostsPath: knownHostsPath,
+			Host:           "192.0.2.2",
+			Key:            known,
+			ExpectedError:  "is not in",
+		},
+		{
+			Name:           "missing known_hosts",
+			KnownHostsPath: filepath.Join(dir, "missing"),
+			Host:           "192.0.2.1",
+			Key:            known,
+			ExpectedError:  "does not exist",
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			callback := HostKeyCallback(g.Fingerprint, g.KnownHostsPath)
+			remote := &net.TCPAddr{IP: net.ParseIP(g.Host), Port: 22}
+			err := callback(net.JoinHostPort(g.Host, "22"), remote, g.Key)
+			if g.ExpectedError == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("expected an error containing %q", g.ExpectedError)
+			} else if !strings.Contains(err.Error(), g.ExpectedError) {
+				t.Errorf("expected an error containing %q, got %v", g.ExpectedError, err)
+			}
+		})
+	}
+}
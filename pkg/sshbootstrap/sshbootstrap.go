@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sshbootstrap runs the bootstrap script, which installs and runs nodeup, on machines over SSH
+package sshbootstrap
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// bootstrapScriptPath is where the bootstrap script is written on the machine
+	bootstrapScriptPath = "/var/lib/kops/bootstrap.sh"
+	// bootstrapHashPath records the hash of the last bootstrap script that ran successfully
+	bootstrapHashPath = "/var/lib/kops/bootstrap.sha256"
+)
+
+// HashBootstrapScript returns the hash that is recorded on a machine once the script has run
+func HashBootstrapScript(script string) string {
+	hash := sha256.Sum256([]byte(script))
+	return hex.EncodeToString(hash[:])
+}
+
+// Dial connects to the SSH server of the machine at the address
+func Dial(name string, address string, sshConfig *ssh.ClientConfig) (*ssh.Client, error) {
+	addr := net.JoinHostPort(address, "22")
+	client, err := ssh.Dial("tcp", addr, sshConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to machine %q at %s: %v", name, addr, err)
+	}
+	return client, nil
+}
+
+// BootstrapHash returns the hash of the last bootstrap script that ran successfully on the machine,
+// or an empty string if the machine has never been bootstrapped
+func BootstrapHash(client *ssh.Client, user string) (string, error) {
+	out, err := RunCommand(client, user, "cat "+bootstrapHashPath+" 2>/dev/null || true", nil)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// RunBootstrapScript copies the bootstrap script to the machine and runs it
+func RunBootstrapScript(client *ssh.Client, user string, script string) error {
+	hash := HashBootstrapScript(script)
+
+	// The hash is only recorded once the script succeeds, so that a failed bootstrap is retried
+	cmd := fmt.Sprintf("mkdir -p /var/lib/kops && cat > %s && chmod 0700 %s && %s && echo %s > %s",
+		bootstrapScriptPath, bootstrapScriptPath, bootstrapScriptPath, hash, bootstrapHashPath)
+	_, err := RunCommand(client, user, cmd, strings.NewReader(script))
+	return err
+}
+
+// RunCommand runs a shell command on the machine, using sudo unless connected as root
+func RunCommand(client *ssh.Client, user string, cmd string, stdin io.Reader) ([]byte, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("error creating SSH session: %v", err)
+	}
+	defer session.Close()
+
+	if user != "" && user != "root" {
+		cmd = "sudo sh -c '" + cmd + "'"
+	}
+
+	var stdout, stderr bytes.Buffer
+	session.Stdin = stdin
+	session.Stdout = &stdout
+	session.Stderr = &stderr
+	if err := session.Run(cmd); err != nil {
+		return nil, fmt.Errorf("error running %q: %v: %s", cmd, err, stderr.String())
+	}
+	return stdout.Bytes(), nil
+}
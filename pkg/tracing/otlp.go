@@ -0,0 +1,327 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opencensus.io/trace"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// scopeName is the instrumentation scope of the spans we export
+	scopeName = "k8s.io/kops"
+
+	// exportInterval is how often buffered spans are sent to the collector
+	exportInterval = 5 * time.Second
+	// maxBatchSize is the maximum number of spans sent in one request
+	maxBatchSize = 512
+	// maxQueueSize is the maximum number of spans buffered; further spans are dropped until the next export
+	maxQueueSize = 4096
+)
+
+// otlpExporter sends spans to an OpenTelemetry collector, using the JSON encoding of OTLP over HTTP.
+//
+// Spans are recorded with OpenCensus, which kOps already depends on, rather than the OpenTelemetry SDK:
+// the OpenTelemetry OTLP exporters need go-logr/logr v1, which the vendored klog and Kubernetes
+// libraries are not compatible with, and newer grpc and protobuf modules. Once those are upgraded,
+// this exporter should be replaced with go.opentelemetry.io/otel/exporters/otlp/otlptrace.
+type otlpExporter struct {
+	endpoint   string
+	headers    map[string]string
+	resource   []otlpKeyValue
+	httpClient *http.Client
+
+	mutex   sync.Mutex
+	spans   []*trace.SpanData
+	dropped int
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+var _ trace.Exporter = &otlpExporter{}
+
+func newOTLPExporter(endpoint string, headers map[string]string, resource map[string]string) *otlpExporter {
+	e := &otlpExporter{
+		endpoint:   endpoint,
+		headers:    headers,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	attributes := make(map[string]interface{})
+	for k, v := range resource {
+		attributes[k] = v
+	}
+	e.resource = buildKeyValues(attributes)
+
+	go e.run()
+	return e
+}
+
+// ExportSpan buffers a finished span; it implements trace.Exporter.
+func (e *otlpExporter) ExportSpan(span *trace.SpanData) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if len(e.spans) >= maxQueueSize {
+		e.dropped++
+		return
+	}
+	e.spans = append(e.spans, span)
+}
+
+// Shutdown stops the periodic export and sends the remaining spans.
+func (e *otlpExporter) Shutdown() {
+	close(e.stop)
+	<-e.done
+	e.flush()
+}
+
+func (e *otlpExporter) run() {
+	defer close(e.done)
+
+	ticker := time.NewTicker(exportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-e.stop:
+			return
+		case <-ticker.C:
+			e.flush()
+		}
+	}
+}
+
+// flush sends the buffered spans, in batches of at most maxBatchSize.
+// Export failures are logged rather than returned, so that tracing never fails the traced operation.
+func (e *otlpExporter) flush() {
+	e.mutex.Lock()
+	spans := e.spans
+	dropped := e.dropped
+	e.spans = nil
+	e.dropped = 0
+	e.mutex.Unlock()
+
+	if dropped != 0 {
+		klog.Warningf("dropped %d spans because the export queue was full", dropped)
+	}
+
+	for len(spans) != 0 {
+		n := len(spans)
+		if n > maxBatchSize {
+			n = maxBatchSize
+		}
+		if err := e.send(spans[:n]); err != nil {
+			klog.Warningf("failed to export %d spans to %s: %v", n, e.endpoint, err)
+		}
+		spans = spans[n:]
+	}
+}
+
+func (e *otlpExporter) send(spans []*trace.SpanData) error {
+	body, err := json.Marshal(e.buildRequest(spans))
+	if err != nil {
+		return fmt.Errorf("encoding spans: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %q", resp.Status)
+	}
+	return nil
+}
+
+func (e *otlpExporter) buildRequest(spans []*trace.SpanData) *otlpTracesRequest {
+	scopeSpans := otlpScopeSpans{
+		Scope: otlpScope{Name: scopeName},
+	}
+	for _, span := range spans {
+		scopeSpans.Spans = append(scopeSpans.Spans, buildSpan(span))
+	}
+
+	return &otlpTracesRequest{
+		ResourceSpans: []otlpResourceSpans{
+			{
+				Resource:   otlpResource{Attributes: e.resource},
+				ScopeSpans: []otlpScopeSpans{scopeSpans},
+			},
+		},
+	}
+}
+
+func buildSpan(span *trace.SpanData) otlpSpan {
+	s := otlpSpan{
+		TraceID:           hex.EncodeToString(span.TraceID[:]),
+		SpanID:            hex.EncodeToString(span.SpanID[:]),
+		Name:              span.Name,
+		Kind:              otlpSpanKindInternal,
+		StartTimeUnixNano: unixNano(span.StartTime),
+		EndTimeUnixNano:   unixNano(span.EndTime),
+		Attributes:        buildKeyValues(span.Attributes),
+	}
+	if span.ParentSpanID != (trace.SpanID{}) {
+		s.ParentSpanID = hex.EncodeToString(span.ParentSpanID[:])
+	}
+	switch span.SpanKind {
+	case trace.SpanKindServer:
+		s.Kind = otlpSpanKindServer
+	case trace.SpanKindClient:
+		s.Kind = otlpSpanKindClient
+	}
+	for _, annotation := range span.Annotations {
+		s.Events = append(s.Events, otlpEvent{
+			TimeUnixNano: unixNano(annotation.Time),
+			Name:         annotation.Message,
+			Attributes:   buildKeyValues(annotation.Attributes),
+		})
+	}
+	if span.Status.Code != trace.StatusCodeOK {
+		s.Status = otlpStatus{
+			Code:    otlpStatusCodeError,
+			Message: span.Status.Message,
+		}
+	}
+	return s
+}
+
+// buildKeyValues converts attributes to OTLP key-values, sorted by key for a stable encoding.
+func buildKeyValues(attributes map[string]interface{}) []otlpKeyValue {
+	var keyValues []otlpKeyValue
+	for k, v := range attributes {
+		kv := otlpKeyValue{Key: k}
+		switch v := v.(type) {
+		case string:
+			kv.Value.StringValue = &v
+		case bool:
+			kv.Value.BoolValue = &v
+		case int64:
+			s := strconv.FormatInt(v, 10)
+			kv.Value.IntValue = &s
+		case float64:
+			kv.Value.DoubleValue = &v
+		default:
+			s := fmt.Sprintf("%v", v)
+			kv.Value.StringValue = &s
+		}
+		keyValues = append(keyValues, kv)
+	}
+	sort.Slice(keyValues, func(i, j int) bool {
+		return keyValues[i].Key < keyValues[j].Key
+	})
+	return keyValues
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// The types below are the JSON encoding of the OTLP trace export request.
+// 64 bit integers are encoded as strings, and trace and span IDs as hex strings.
+
+const (
+	otlpSpanKindInternal = 1
+	otlpSpanKindServer   = 2
+	otlpSpanKindClient   = 3
+
+	otlpStatusCodeError = 2
+)
+
+type otlpTracesRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Events            []otlpEvent    `json:"events,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano string         `json:"timeUnixNano"`
+	Name         string         `json:"name"`
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+
+	"go.opencensus.io/trace"
+)
+
+func TestOTLPExporter(t *testing.T) {
+	var mutex sync.Mutex
+	var requests []*otlpTracesRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/json" || r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		request := &otlpTracesRequest{}
+		if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mutex.Lock()
+		requests = append(requests, request)
+		mutex.Unlock()
+	}))
+	defer server.Close()
+
+	exporter := newOTLPExporter(server.URL+"/v1/traces", map[string]string{"Authorization": "Bearer token"}, map[string]string{"service.name": "kops"})
+	trace.RegisterExporter(exporter)
+	defer trace.UnregisterExporter(exporter)
+
+	ctx, parent := trace.StartSpan(context.Background(), "parent", trace.WithSampler(trace.AlwaysSample()))
+	_, child := StartSpan(ctx, "child", trace.StringAttribute("task", "Keypair/ca"), trace.Int64Attribute("attempt", 2))
+	child.Annotate(nil, "drained")
+	EndSpan(child, errors.New("boom"))
+	EndSpan(parent, nil)
+
+	exporter.Shutdown()
+
+	if len(requests) != 1 || len(requests[0].ResourceSpans) != 1 || len(requests[0].ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("expected one request with one resource and scope, got %+v", requests)
+	}
+	resourceSpans := requests[0].ResourceSpans[0]
+	if len(resourceSpans.Resource.Attributes) != 1 || resourceSpans.Resource.Attributes[0].Key != "service.name" || *resourceSpans.Resource.Attributes[0].Value.StringValue != "kops" {
+		t.Errorf("unexpected resource %+v", resourceSpans.Resource)
+	}
+
+	spans := resourceSpans.ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	c, p := spans[0], spans[1]
+	if c.Name != "child" || p.Name != "parent" {
+		t.Fatalf("unexpected span names %q and %q", c.Name, p.Name)
+	}
+	if c.TraceID != p.TraceID || len(c.TraceID) != 32 {
+		t.Errorf("expected spans in the same trace, got %q and %q", c.TraceID, p.TraceID)
+	}
+	if c.ParentSpanID != p.SpanID || p.ParentSpanID != "" {
+		t.Errorf("expected child of %q, got parent %q", p.SpanID, c.ParentSpanID)
+	}
+	if c.Kind != otlpSpanKindInternal {
+		t.Errorf("expected internal span, got kind %d", c.Kind)
+	}
+	if c.Status.Code != otlpStatusCodeError || c.Status.Message != "boom" || p.Status.Code != 0 {
+		t.Errorf("unexpected statuses %+v and %+v", c.Status, p.Status)
+	}
+	if len(c.Events) != 1 || c.Events[0].Name != "drained" {
+		t.Errorf("unexpected events %+v", c.Events)
+	}
+
+	attempt, task := "2", "Keypair/ca"
+	expected := []otlpKeyValue{
+		{Key: "attempt", Value: otlpAnyValue{IntValue: &attempt}},
+		{Key: "task", Value: otlpAnyValue{StringValue: &task}},
+	}
+	if !reflect.DeepEqual(c.Attributes, expected) {
+		t.Errorf("unexpected attributes %+v", c.Attributes)
+	}
+}
+
+func TestParseKeyValues(t *testing.T) {
+	grid := []struct {
+		Input       string
+		Expected    map[string]string
+		ExpectError bool
+	}{
+		{
+			Input:    "",
+			Expected: map[string]string{},
+		},
+		{
+			Input:    "Authorization=Basic%20dXNlcjpwYXNz, x-tenant = kops ",
+			Expected: map[string]string{"Authorization": "Basic dXNlcjpwYXNz", "x-tenant": "kops"},
+		},
+		{
+			Input:    "k8s.cluster.name=a+b.example.com",
+			Expected: map[string]string{"k8s.cluster.name": "a+b.example.com"},
+		},
+		{
+			Input:       "missing-value",
+			ExpectError: true,
+		},
+	}
+
+	for _, g := range grid {
+		actual, err := parseKeyValues(g.Input)
+		if g.ExpectError {
+			if err == nil {
+				t.Errorf("expected error parsing %q", g.Input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error parsing %q: %v", g.Input, err)
+			continue
+		}
+		if !reflect.DeepEqual(actual, g.Expected) {
+			t.Errorf("parsing %q: expected %v, got %v", g.Input, g.Expected, actual)
+		}
+	}
+}
+
+func TestInitProtocol(t *testing.T) {
+	grid := []struct {
+		Protocol       string
+		TracesProtocol string
+		ExpectError    bool
+	}{
+		{},
+		{
+			Protocol: "http/json",
+		},
+		{
+			Protocol:    "grpc",
+			ExpectError: true,
+		},
+		{
+			Protocol:       "grpc",
+			TracesProtocol: "http/json",
+		},
+		{
+			TracesProtocol: "http/protobuf",
+			ExpectError:    true,
+		},
+	}
+
+	os.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "http://127.0.0.1:4318/v1/traces")
+	defer os.Unsetenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+	defer os.Unsetenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	defer os.Unsetenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL")
+
+	for _, g := range grid {
+		os.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", g.Protocol)
+		os.Setenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", g.TracesProtocol)
+
+		shutdown, err := Init("kops")
+		if g.ExpectError {
+			if err == nil {
+				shutdown()
+				t.Errorf("expected error for protocol %q and traces protocol %q", g.Protocol, g.TracesProtocol)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for protocol %q and traces protocol %q: %v", g.Protocol, g.TracesProtocol, err)
+			continue
+		}
+		shutdown()
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"go.opencensus.io/trace"
+	"k8s.io/klog/v2"
+)
+
+// Init configures the export of the spans of the named service to the OTLP/HTTP endpoint set in the environment,
+// following the OpenTelemetry conventions:
+//
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT or OTEL_EXPORTER_OTLP_ENDPOINT is the collector endpoint,
+// OTEL_EXPORTER_OTLP_TRACES_HEADERS or OTEL_EXPORTER_OTLP_HEADERS are extra headers to send,
+// OTEL_EXPORTER_OTLP_TRACES_PROTOCOL or OTEL_EXPORTER_OTLP_PROTOCOL must be http/json if set,
+// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES describe the traced process,
+// and OTEL_SDK_DISABLED turns tracing off.
+//
+// If no endpoint is set, spans are not exported. The returned function exports the remaining spans;
+// it must be called before the process exits.
+func Init(serviceName string) (func(), error) {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return func() {}, nil
+	}
+
+	endpoint := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+	if endpoint == "" {
+		if base := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); base != "" {
+			endpoint = strings.TrimSuffix(base, "/") + "/v1/traces"
+		}
+	}
+	if endpoint == "" {
+		return func() {}, nil
+	}
+	protocol := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL")
+	if protocol == "" {
+		protocol = os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	}
+	if protocol != "" && protocol != "http/json" {
+		return nil, fmt.Errorf("unsupported OTLP protocol %q: kOps only exports traces with http/json", protocol)
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid OTLP traces endpoint %q: must be an http or https URL", endpoint)
+	}
+
+	headers, err := parseKeyValues(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"))
+	if err != nil {
+		return nil, fmt.Errorf("parsing OTEL_EXPORTER_OTLP_HEADERS: %v", err)
+	}
+	traceHeaders, err := parseKeyValues(os.Getenv("OTEL_EXPORTER_OTLP_TRACES_HEADERS"))
+	if err != nil {
+		return nil, fmt.Errorf("parsing OTEL_EXPORTER_OTLP_TRACES_HEADERS: %v", err)
+	}
+	for k, v := range traceHeaders {
+		headers[k] = v
+	}
+
+	resource, err := parseKeyValues(os.Getenv("OTEL_RESOURCE_ATTRIBUTES"))
+	if err != nil {
+		return nil, fmt.Errorf("parsing OTEL_RESOURCE_ATTRIBUTES: %v", err)
+	}
+	resource["service.name"] = serviceName
+	if name := os.Getenv("OTEL_SERVICE_NAME"); name != "" {
+		resource["service.name"] = name
+	}
+
+	exporter := newOTLPExporter(endpoint, headers, resource)
+	trace.RegisterExporter(exporter)
+	trace.ApplyConfig(trace.Config{DefaultSampler: trace.AlwaysSample()})
+	klog.V(2).Infof("exporting traces to %s", endpoint)
+
+	return func() {
+		trace.UnregisterExporter(exporter)
+		exporter.Shutdown()
+	}, nil
+}
+
+// StartSpan starts a span with the given attributes, as a child of the span of ctx if there is one.
+func StartSpan(ctx context.Context, name string, attributes ...trace.Attribute) (context.Context, *trace.Span) {
+	ctx, span := trace.StartSpan(ctx, name)
+	if len(attributes) != 0 {
+		span.AddAttributes(attributes...)
+	}
+	return ctx, span
+}
+
+// EndSpan ends the span, marking it as failed if err is not nil.
+func EndSpan(span *trace.Span, err error) {
+	if err != nil {
+		span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: err.Error()})
+	}
+	span.End()
+}
+
+// parseKeyValues parses a comma separated list of key=value pairs, whose values may be URL encoded.
+func parseKeyValues(s string) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		tokens := strings.SplitN(pair, "=", 2)
+		if len(tokens) != 2 || strings.TrimSpace(tokens[0]) == "" {
+			return nil, fmt.Errorf("expected key=value, got %q", pair)
+		}
+		value, err := url.PathUnescape(strings.TrimSpace(tokens[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %q: %v", tokens[0], err)
+		}
+		values[strings.TrimSpace(tokens[0])] = value
+	}
+	return values, nil
+}
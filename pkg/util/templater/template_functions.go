@@ -17,6 +17,7 @@ limitations under the License.
 package templater
 
 import (
+	"strings"
 	"text/template"
 
 	"github.com/Masterminds/sprig/v3"
@@ -25,6 +26,7 @@ import (
 	kopsapi "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/util"
 	"k8s.io/kops/util/pkg/architectures"
+	"sigs.k8s.io/yaml"
 )
 
 // templateFuncsMap returns a map if the template functions for this template
@@ -42,6 +44,30 @@ func (r *Templater) templateFuncsMap(tm *template.Template) template.FuncMap {
 
 		return content
 	}
+	// @step: helm style helpers which sprig does not provide
+	funcs["toYaml"] = func(v interface{}) string {
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			panic(err.Error())
+		}
+		return strings.TrimSuffix(string(data), "\n")
+	}
+	funcs["fromYaml"] = func(content string) map[string]interface{} {
+		m := make(map[string]interface{})
+		if err := yaml.Unmarshal([]byte(content), &m); err != nil {
+			panic(err.Error())
+		}
+		return m
+	}
+	funcs["required"] = func(message string, v interface{}) interface{} {
+		if v == nil {
+			panic(message)
+		}
+		if s, ok := v.(string); ok && s == "" {
+			panic(message)
+		}
+		return v
+	}
 
 	funcs["ChannelRecommendedKubernetesUpgradeVersion"] = func(version string) string {
 
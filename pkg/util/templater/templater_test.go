@@ -72,6 +72,31 @@ func TestRenderMissingValue(t *testing.T) {
 	makeRenderTests(t, cases)
 }
 
+func TestRenderYAMLFunctions(t *testing.T) {
+	cases := []renderTest{
+		{
+			Context:  map[string]interface{}{"node": map[string]interface{}{"minSize": 1, "image": "test"}},
+			Template: `{{ .node | toYaml }}`,
+			Expected: "image: test\nminSize: 1",
+		},
+		{
+			Template: `{{ $v := fromYaml "a:\n  b: c" }}{{ $v.a.b }}`,
+			Expected: "c",
+		},
+		{
+			Context:  map[string]interface{}{"region": "eu-west-1"},
+			Template: `{{ required "region is required" .region }}`,
+			Expected: "eu-west-1",
+		},
+		{
+			Context:  map[string]interface{}{"region": ""},
+			Template: `{{ required "region is required" .region }}`,
+			NotOK:    true,
+		},
+	}
+	makeRenderTests(t, cases)
+}
+
 func TestRenderIndent(t *testing.T) {
 	cases := []renderTest{
 		{
@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/channels/pkg/channels"
+)
+
+const addonNameLabel = "addon.kops.k8s.io/name"
+
+// addonsCheck checks that the Deployments and DaemonSets of the addons applied by channels are available
+type addonsCheck struct{}
+
+func init() {
+	RegisterCheck(&addonsCheck{})
+}
+
+func (c *addonsCheck) Name() string {
+	return "addons"
+}
+
+func (c *addonsCheck) Check(ctx context.Context, checkContext *CheckContext, validation *ValidationCluster) error {
+	client := checkContext.K8sClient
+
+	namespace, err := client.CoreV1().Namespaces().Get(ctx, metav1.NamespaceSystem, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error reading namespace %q: %v", metav1.NamespaceSystem, err)
+	}
+
+	addons := channels.FindAddons(namespace)
+	var names []string
+	for name := range addons {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		// The objects of an addon are labeled with the name channels records it under
+		selector := addonNameLabel + "=" + name
+
+		deployments, err := client.AppsV1().Deployments(metav1.NamespaceAll).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return fmt.Errorf("error listing Deployments of addon %q: %v", name, err)
+		}
+		for i := range deployments.Items {
+			deployment := &deployments.Items[i]
+			if message := deploymentProblem(deployment); message != "" {
+				validation.addError(&ValidationError{
+					Kind:    "Addon",
+					Name:    name,
+					Message: fmt.Sprintf("addon %q deployment %q %s", name, deployment.Namespace+"/"+deployment.Name, message),
+				})
+			}
+		}
+
+		daemonSets, err := client.AppsV1().DaemonSets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return fmt.Errorf("error listing DaemonSets of addon %q: %v", name, err)
+		}
+		for i := range daemonSets.Items {
+			daemonSet := &daemonSets.Items[i]
+			if message := daemonSetProblem(daemonSet); message != "" {
+				validation.addError(&ValidationError{
+					Kind:    "Addon",
+					Name:    name,
+					Message: fmt.Sprintf("addon %q daemonset %q %s", name, daemonSet.Namespace+"/"+daemonSet.Name, message),
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+func deploymentProblem(deployment *appsv1.Deployment) string {
+	desired := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return "has not yet been rolled out"
+	}
+	if deployment.Status.UpdatedReplicas < desired {
+		return fmt.Sprintf("has %d of %d replicas updated", deployment.Status.UpdatedReplicas, desired)
+	}
+	if deployment.Status.AvailableReplicas < desired {
+		return fmt.Sprintf("has %d of %d replicas available", deployment.Status.AvailableReplicas, desired)
+	}
+	return ""
+}
+
+func daemonSetProblem(daemonSet *appsv1.DaemonSet) string {
+	desired := daemonSet.Status.DesiredNumberScheduled
+	if daemonSet.Status.ObservedGeneration < daemonSet.Generation {
+		return "has not yet been rolled out"
+	}
+	if daemonSet.Status.UpdatedNumberScheduled < desired {
+		return fmt.Sprintf("has %d of %d pods updated", daemonSet.Status.UpdatedNumberScheduled, desired)
+	}
+	if daemonSet.Status.NumberAvailable < desired {
+		return fmt.Sprintf("has %d of %d pods available", daemonSet.Status.NumberAvailable, desired)
+	}
+	return ""
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// antreaCheck checks that NetworkPolicies are enforced: the Antrea controller computes them and the agent of every node applies them
+type antreaCheck struct{}
+
+func init() {
+	RegisterCheck(&antreaCheck{})
+}
+
+func (c *antreaCheck) Name() string {
+	return "antrea"
+}
+
+func (c *antreaCheck) Check(ctx context.Context, checkContext *CheckContext, validation *ValidationCluster) error {
+	cluster := checkContext.Cluster
+	if cluster == nil || cluster.Spec.Networking == nil || cluster.Spec.Networking.Antrea == nil {
+		return nil
+	}
+
+	pods, err := checkContext.K8sClient.CoreV1().Pods(metav1.NamespaceSystem).List(ctx, metav1.ListOptions{LabelSelector: "app=antrea"})
+	if err != nil {
+		return fmt.Errorf("error listing antrea pods: %v", err)
+	}
+
+	controllerReady := false
+	agentReady := map[string]bool{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !isPodReady(pod) {
+			continue
+		}
+		switch pod.Labels["component"] {
+		case "antrea-controller":
+			controllerReady = true
+		case "antrea-agent":
+			agentReady[pod.Spec.NodeName] = true
+		}
+	}
+
+	if !controllerReady {
+		validation.addError(&ValidationError{
+			Kind:    "Deployment",
+			Name:    "kube-system/antrea-controller",
+			Message: "antrea-controller is not ready, so changes to NetworkPolicies are not applied",
+		})
+	}
+
+	for _, node := range checkContext.Nodes {
+		if agentReady[node.Name] {
+			continue
+		}
+		validation.addError(&ValidationError{
+			Kind:    "Node",
+			Name:    node.Name,
+			Message: fmt.Sprintf("node %q does not have a ready antrea agent, so NetworkPolicies are not enforced on it", node.Name),
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+)
+
+// certificateExpiryCheck checks that the certificates served by the API server do not expire soon
+type certificateExpiryCheck struct {
+	// threshold is how long before their expiry certificates fail the check
+	threshold time.Duration
+}
+
+func init() {
+	RegisterCheck(&certificateExpiryCheck{threshold: 30 * 24 * time.Hour})
+}
+
+func (c *certificateExpiryCheck) Name() string {
+	return "certificates"
+}
+
+func (c *certificateExpiryCheck) Check(ctx context.Context, checkContext *CheckContext, validation *ValidationCluster) error {
+	apiURL, err := url.Parse(checkContext.Host)
+	if err != nil {
+		return fmt.Errorf("unable to parse Kubernetes cluster API URL: %v", err)
+	}
+	address := apiURL.Host
+	if apiURL.Port() == "" {
+		address = net.JoinHostPort(apiURL.Hostname(), "443")
+	}
+
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: 10 * time.Second},
+		Config: &tls.Config{
+			// Only the validity period is checked here; kubectl verifies the certificates
+			InsecureSkipVerify: true,
+		},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return fmt.Errorf("error connecting to API server: %v", err)
+	}
+	defer conn.Close()
+
+	now := time.Now()
+	for _, certificate := range conn.(*tls.Conn).ConnectionState().PeerCertificates {
+		if message := certificateExpiryProblem(certificate, now, c.threshold); message != "" {
+			validation.addError(&ValidationError{
+				Kind:    "Certificate",
+				Name:    certificate.Subject.CommonName,
+				Message: fmt.Sprintf("API server certificate %q %s", certificate.Subject.CommonName, message),
+			})
+		}
+	}
+
+	return nil
+}
+
+func certificateExpiryProblem(certificate *x509.Certificate, now time.Time, threshold time.Duration) string {
+	if now.After(certificate.NotAfter) {
+		return fmt.Sprintf("expired at %s", certificate.NotAfter.UTC().Format(time.RFC3339))
+	}
+	if now.Add(threshold).After(certificate.NotAfter) {
+		return fmt.Sprintf("expires at %s", certificate.NotAfter.UTC().Format(time.RFC3339))
+	}
+	return ""
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+// ciliumCheck checks that every node runs a ready Cilium agent, whose readiness reflects its "cilium status"
+type ciliumCheck struct{}
+
+func init() {
+	RegisterCheck(&ciliumCheck{})
+}
+
+func (c *ciliumCheck) Name() string {
+	return "cilium"
+}
+
+func (c *ciliumCheck) Check(ctx context.Context, checkContext *CheckContext, validation *ValidationCluster) error {
+	cilium := ciliumSpec(checkContext.Cluster)
+	if cilium == nil {
+		return nil
+	}
+
+	pods, err := checkContext.K8sClient.CoreV1().Pods(metav1.NamespaceSystem).List(ctx, metav1.ListOptions{LabelSelector: "k8s-app=cilium"})
+	if err != nil {
+		return fmt.Errorf("error listing cilium pods: %v", err)
+	}
+
+	ready := map[string]bool{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if isPodReady(pod) {
+			ready[pod.Spec.NodeName] = true
+		}
+	}
+
+	for _, node := range checkContext.Nodes {
+		if ready[node.Name] {
+			continue
+		}
+		message := fmt.Sprintf("node %q does not have a ready cilium agent", node.Name)
+		if cilium.EnableNodePort {
+			// With the strict kube-proxy replacement, nothing else implements services on the node
+			message += ", so services cannot be reached from it"
+		}
+		validation.addError(&ValidationError{
+			Kind:    "Node",
+			Name:    node.Name,
+			Message: message,
+		})
+	}
+
+	return nil
+}
+
+// ciliumSpec returns the Cilium networking of the cluster, or nil if it does not use Cilium
+func ciliumSpec(cluster *kops.Cluster) *kops.CiliumNetworkingSpec {
+	if cluster == nil || cluster.Spec.Networking == nil {
+		return nil
+	}
+	return cluster.Spec.Networking.Cilium
+}
+
+// IsCiliumKubeProxyReplacementStrict returns true if Cilium replaces kube-proxy entirely on the nodes of the cluster,
+// in which case the services of a node only work once its Cilium agent is ready
+func IsCiliumKubeProxyReplacementStrict(cluster *kops.Cluster) bool {
+	cilium := ciliumSpec(cluster)
+	return cilium != nil && cilium.EnableNodePort
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// etcdCheck checks that every member of each etcd cluster has a ready etcd-manager and that the API server can reach etcd
+type etcdCheck struct{}
+
+func init() {
+	RegisterCheck(&etcdCheck{})
+}
+
+func (c *etcdCheck) Name() string {
+	return "etcd"
+}
+
+func (c *etcdCheck) Check(ctx context.Context, checkContext *CheckContext, validation *ValidationCluster) error {
+	client := checkContext.K8sClient
+
+	for _, etcdCluster := range checkContext.Cluster.Spec.EtcdClusters {
+		// External etcd clusters have no member pods; the API server's readiness check below still covers them
+		if etcdCluster.IsExternal() {
+			continue
+		}
+		app := "etcd-manager-" + etcdCluster.Name
+		pods, err := client.CoreV1().Pods(metav1.NamespaceSystem).List(ctx, metav1.ListOptions{LabelSelector: "k8s-app=" + app})
+		if err != nil {
+			return fmt.Errorf("error listing %s pods: %v", app, err)
+		}
+
+		ready := 0
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if isPodReady(pod) {
+				ready++
+			} else {
+				validation.addError(&ValidationError{
+					Kind:    "Pod",
+					Name:    pod.Namespace + "/" + pod.Name,
+					Message: fmt.Sprintf("etcd cluster %q member pod %q is not ready", etcdCluster.Name, pod.Name),
+				})
+			}
+		}
+		if ready < len(etcdCluster.Members) {
+			validation.addError(&ValidationError{
+				Kind:    "EtcdCluster",
+				Name:    etcdCluster.Name,
+				Message: fmt.Sprintf("etcd cluster %q has %d of %d members ready", etcdCluster.Name, ready, len(etcdCluster.Members)),
+			})
+		}
+	}
+
+	// The API server's own view of etcd, which fails if it cannot reach a quorum
+	if restClient := client.Discovery().RESTClient(); restClient != nil {
+		body, err := restClient.Get().AbsPath("/readyz/etcd").DoRaw(ctx)
+		if err != nil {
+			validation.addError(&ValidationError{
+				Kind:    "EtcdCluster",
+				Name:    "apiserver",
+				Message: fmt.Sprintf("API server reports etcd is not ready: %v %s", err, body),
+			})
+		}
+	}
+
+	return nil
+}
+
+func isPodReady(pod *v1.Pod) bool {
+	if pod.Status.Phase != v1.PodRunning {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// Check is an additional validation of a cluster, run after its nodes and pods have been validated
+type Check interface {
+	// Name is the name used to select or skip the check
+	Name() string
+	// Check adds the problems it finds to the validation
+	Check(ctx context.Context, c *CheckContext, validation *ValidationCluster) error
+}
+
+// CheckContext holds what a Check may inspect
+type CheckContext struct {
+	Cluster   *kops.Cluster
+	Cloud     fi.Cloud
+	Host      string
+	K8sClient kubernetes.Interface
+	// Nodes are all the nodes registered with the cluster
+	Nodes []v1.Node
+}
+
+// ValidationCheck records the outcome of a Check
+type ValidationCheck struct {
+	Name string `json:"name"`
+	// Status is one of Passed, Failed or Error
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	CheckStatusPassed = "Passed"
+	CheckStatusFailed = "Failed"
+	CheckStatusError  = "Error"
+)
+
+var checks = map[string]Check{}
+
+// RegisterCheck makes a Check available for selection
+func RegisterCheck(check Check) {
+	name := check.Name()
+	if _, found := checks[name]; found {
+		panic(fmt.Sprintf("validation check %q registered twice", name))
+	}
+	checks[name] = check
+}
+
+// CheckNames returns the names of the registered checks, sorted
+func CheckNames() []string {
+	var names []string
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SelectChecks returns the checks chosen by the selectors. A selector naming a check selects it; a selector
+// prefixed with "-" skips it. If no check is selected, all checks which are not skipped are returned.
+func SelectChecks(selectors []string) ([]Check, error) {
+	selected := map[string]bool{}
+	skipped := map[string]bool{}
+	for _, selector := range selectors {
+		selector = strings.TrimSpace(selector)
+		if selector == "" {
+			continue
+		}
+		skip := strings.HasPrefix(selector, "-")
+		name := strings.TrimPrefix(selector, "-")
+		if _, found := checks[name]; !found {
+			return nil, fmt.Errorf("unknown validation check %q, expected one of %s", name, strings.Join(CheckNames(), ","))
+		}
+		if skip {
+			skipped[name] = true
+		} else {
+			selected[name] = true
+		}
+	}
+
+	var result []Check
+	for _, name := range CheckNames() {
+		if skipped[name] || (len(selected) != 0 && !selected[name]) {
+			continue
+		}
+		result = append(result, checks[name])
+	}
+	return result, nil
+}
+
+// runChecks runs the checks, recording the outcome of each of them
+func (v *ValidationCluster) runChecks(ctx context.Context, c *CheckContext, checks []Check) {
+	for _, check := range checks {
+		failures := len(v.Failures)
+		result := &ValidationCheck{
+			Name:   check.Name(),
+			Status: CheckStatusPassed,
+		}
+		if err := check.Check(ctx, c, v); err != nil {
+			result.Status = CheckStatusError
+			result.Message = err.Error()
+			v.addError(&ValidationError{
+				Kind:    "Check",
+				Name:    check.Name(),
+				Message: fmt.Sprintf("validation check %q failed to run: %v", check.Name(), err),
+			})
+		} else if len(v.Failures) != failures {
+			result.Status = CheckStatusFailed
+		}
+		for _, failure := range v.Failures[failures:] {
+			failure.Check = check.Name()
+		}
+		v.Checks = append(v.Checks, result)
+	}
+}
@@ -0,0 +1,362 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+)
+
+func TestSelectChecks(t *testing.T) {
+	grid := []struct {
+		selectors []string
+		expected  []string
+		err       bool
+	}{
+		{
+			expected: []string{"addons", "antrea", "certificates", "cilium", "etcd", "node-conditions"},
+		},
+		{
+			selectors: []string{"etcd", "addons"},
+			expected:  []string{"addons", "etcd"},
+		},
+		{
+			selectors: []string{"-certificates"},
+			expected:  []string{"addons", "antrea", "cilium", "etcd", "node-conditions"},
+		},
+		{
+			selectors: []string{"etcd", "addons", "-etcd"},
+			expected:  []string{"addons"},
+		},
+		{
+			selectors: []string{"unknown"},
+			err:       true,
+		},
+	}
+	for _, g := range grid {
+		selected, err := SelectChecks(g.selectors)
+		if g.err {
+			assert.Error(t, err, "selectors %v", g.selectors)
+			continue
+		}
+		require.NoError(t, err, "selectors %v", g.selectors)
+		var names []string
+		for _, check := range selected {
+			names = append(names, check.Name())
+		}
+		assert.Equal(t, g.expected, names, "selectors %v", g.selectors)
+	}
+}
+
+type failingCheck struct{}
+
+func (c *failingCheck) Name() string {
+	return "failing"
+}
+
+func (c *failingCheck) Check(ctx context.Context, checkContext *CheckContext, validation *ValidationCluster) error {
+	return errors.New("unreachable")
+}
+
+func TestRunChecks(t *testing.T) {
+	checkContext := &CheckContext{
+		Nodes: []v1.Node{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+				Status: v1.NodeStatus{
+					Conditions: []v1.NodeCondition{
+						{Type: v1.NodeDiskPressure, Status: v1.ConditionTrue, Message: "kubelet has disk pressure"},
+						{Type: v1.NodeMemoryPressure, Status: v1.ConditionFalse},
+					},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-2"},
+			},
+		},
+	}
+
+	validation := &ValidationCluster{}
+	validation.runChecks(context.Background(), checkContext, []Check{&nodeConditionsCheck{}, &failingCheck{}})
+
+	assert.Equal(t, []*ValidationCheck{
+		{Name: "node-conditions", Status: CheckStatusFailed},
+		{Name: "failing", Status: CheckStatusError, Message: "unreachable"},
+	}, validation.Checks)
+	assert.Equal(t, []*ValidationError{
+		{
+			Kind:    "Node",
+			Name:    "node-1",
+			Message: `node "node-1" has condition DiskPressure: kubelet has disk pressure`,
+			Check:   "node-conditions",
+		},
+		{
+			Kind:    "Check",
+			Name:    "failing",
+			Message: `validation check "failing" failed to run: unreachable`,
+			Check:   "failing",
+		},
+	}, validation.Failures)
+}
+
+func TestAddonsCheck(t *testing.T) {
+	one := int32(1)
+	two := int32(2)
+	addonLabels := func(name string) map[string]string {
+		return map[string]string{addonNameLabel: name}
+	}
+	client := fake.NewSimpleClientset(
+		&v1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "kube-system",
+				Annotations: map[string]string{
+					"addons.k8s.io/coredns.addons.k8s.io":        `{"version":"1.7.0-kops.3"}`,
+					"addons.k8s.io/networking.cilium.io":         `{"version":"1.9.0-kops.1"}`,
+					"addons.k8s.io/dns-controller.addons.k8s.io": `{"version":"1.21.0"}`,
+				},
+			},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "coredns", Labels: addonLabels("coredns.addons.k8s.io")},
+			Spec:       appsv1.DeploymentSpec{Replicas: &two},
+			Status:     appsv1.DeploymentStatus{UpdatedReplicas: 2, AvailableReplicas: 1},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "dns-controller", Labels: addonLabels("dns-controller.addons.k8s.io")},
+			Spec:       appsv1.DeploymentSpec{Replicas: &one},
+			Status:     appsv1.DeploymentStatus{UpdatedReplicas: 1, AvailableReplicas: 1},
+		},
+		&appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "cilium", Labels: addonLabels("networking.cilium.io"), Generation: 2},
+			Status:     appsv1.DaemonSetStatus{ObservedGeneration: 1, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 3},
+		},
+	)
+
+	validation := &ValidationCluster{}
+	err := (&addonsCheck{}).Check(context.Background(), &CheckContext{K8sClient: client}, validation)
+	require.NoError(t, err)
+
+	assert.Equal(t, []*ValidationError{
+		{
+			Kind:    "Addon",
+			Name:    "coredns.addons.k8s.io",
+			Message: `addon "coredns.addons.k8s.io" deployment "kube-system/coredns" has 1 of 2 replicas available`,
+		},
+		{
+			Kind:    "Addon",
+			Name:    "networking.cilium.io",
+			Message: `addon "networking.cilium.io" daemonset "kube-system/cilium" has not yet been rolled out`,
+		},
+	}, validation.Failures)
+}
+
+func TestEtcdCheck(t *testing.T) {
+	etcdPod := func(name string, app string, ready v1.ConditionStatus) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: name, Labels: map[string]string{"k8s-app": app}},
+			Status: v1.PodStatus{
+				Phase:      v1.PodRunning,
+				Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: ready}},
+			},
+		}
+	}
+	client := fake.NewSimpleClientset(
+		etcdPod("etcd-manager-main-master-a", "etcd-manager-main", v1.ConditionTrue),
+		etcdPod("etcd-manager-main-master-b", "etcd-manager-main", v1.ConditionTrue),
+		etcdPod("etcd-manager-main-master-c", "etcd-manager-main", v1.ConditionFalse),
+		etcdPod("etcd-manager-events-master-a", "etcd-manager-events", v1.ConditionTrue),
+	)
+	cluster := &kopsapi.Cluster{
+		Spec: kopsapi.ClusterSpec{
+			EtcdClusters: []kopsapi.EtcdClusterSpec{
+				{
+					Name:    "main",
+					Members: []kopsapi.EtcdMemberSpec{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+				},
+				{
+					Name:    "events",
+					Members: []kopsapi.EtcdMemberSpec{{Name: "a"}},
+				},
+			},
+		},
+	}
+
+	validation := &ValidationCluster{}
+	err := (&etcdCheck{}).Check(context.Background(), &CheckContext{Cluster: cluster, K8sClient: client}, validation)
+	require.NoError(t, err)
+
+	assert.Equal(t, []*ValidationError{
+		{
+			Kind:    "Pod",
+			Name:    "kube-system/etcd-manager-main-master-c",
+			Message: `etcd cluster "main" member pod "etcd-manager-main-master-c" is not ready`,
+		},
+		{
+			Kind:    "EtcdCluster",
+			Name:    "main",
+			Message: `etcd cluster "main" has 2 of 3 members ready`,
+		},
+	}, validation.Failures)
+}
+
+func TestCiliumCheck(t *testing.T) {
+	ciliumPod := func(name string, node string, ready v1.ConditionStatus) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: name, Labels: map[string]string{"k8s-app": "cilium"}},
+			Spec:       v1.PodSpec{NodeName: node},
+			Status: v1.PodStatus{
+				Phase:      v1.PodRunning,
+				Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: ready}},
+			},
+		}
+	}
+	client := fake.NewSimpleClientset(
+		ciliumPod("cilium-a", "node-a", v1.ConditionTrue),
+		ciliumPod("cilium-b", "node-b", v1.ConditionFalse),
+	)
+	checkContext := &CheckContext{
+		K8sClient: client,
+		Nodes: []v1.Node{
+			{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "node-b"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "node-c"}},
+		},
+	}
+
+	grid := []struct {
+		networking *kopsapi.NetworkingSpec
+		expected   []*ValidationError
+	}{
+		{
+			networking: &kopsapi.NetworkingSpec{Calico: &kopsapi.CalicoNetworkingSpec{}},
+		},
+		{
+			networking: &kopsapi.NetworkingSpec{Cilium: &kopsapi.CiliumNetworkingSpec{}},
+			expected: []*ValidationError{
+				{Kind: "Node", Name: "node-b", Message: `node "node-b" does not have a ready cilium agent`},
+				{Kind: "Node", Name: "node-c", Message: `node "node-c" does not have a ready cilium agent`},
+			},
+		},
+		{
+			networking: &kopsapi.NetworkingSpec{Cilium: &kopsapi.CiliumNetworkingSpec{EnableNodePort: true}},
+			expected: []*ValidationError{
+				{Kind: "Node", Name: "node-b", Message: `node "node-b" does not have a ready cilium agent, so services cannot be reached from it`},
+				{Kind: "Node", Name: "node-c", Message: `node "node-c" does not have a ready cilium agent, so services cannot be reached from it`},
+			},
+		},
+	}
+	for _, g := range grid {
+		checkContext.Cluster = &kopsapi.Cluster{Spec: kopsapi.ClusterSpec{Networking: g.networking}}
+
+		validation := &ValidationCluster{}
+		require.NoError(t, (&ciliumCheck{}).Check(context.Background(), checkContext, validation))
+		assert.Equal(t, g.expected, validation.Failures)
+	}
+}
+
+func TestAntreaCheck(t *testing.T) {
+	antreaPod := func(name string, component string, node string, ready v1.ConditionStatus) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: name, Labels: map[string]string{"app": "antrea", "component": component}},
+			Spec:       v1.PodSpec{NodeName: node},
+			Status: v1.PodStatus{
+				Phase:      v1.PodRunning,
+				Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: ready}},
+			},
+		}
+	}
+	nodes := []v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-b"}},
+	}
+
+	grid := []struct {
+		networking *kopsapi.NetworkingSpec
+		pods       []*v1.Pod
+		expected   []*ValidationError
+	}{
+		{
+			networking: &kopsapi.NetworkingSpec{Calico: &kopsapi.CalicoNetworkingSpec{}},
+		},
+		{
+			networking: &kopsapi.NetworkingSpec{Antrea: &kopsapi.AntreaNetworkingSpec{}},
+			pods: []*v1.Pod{
+				antreaPod("antrea-controller-1", "antrea-controller", "node-a", v1.ConditionTrue),
+				antreaPod("antrea-agent-a", "antrea-agent", "node-a", v1.ConditionTrue),
+				antreaPod("antrea-agent-b", "antrea-agent", "node-b", v1.ConditionTrue),
+			},
+		},
+		{
+			networking: &kopsapi.NetworkingSpec{Antrea: &kopsapi.AntreaNetworkingSpec{}},
+			pods: []*v1.Pod{
+				antreaPod("antrea-controller-1", "antrea-controller", "node-a", v1.ConditionFalse),
+				antreaPod("antrea-agent-a", "antrea-agent", "node-a", v1.ConditionTrue),
+				antreaPod("antrea-agent-b", "antrea-agent", "node-b", v1.ConditionFalse),
+			},
+			expected: []*ValidationError{
+				{Kind: "Deployment", Name: "kube-system/antrea-controller", Message: "antrea-controller is not ready, so changes to NetworkPolicies are not applied"},
+				{Kind: "Node", Name: "node-b", Message: `node "node-b" does not have a ready antrea agent, so NetworkPolicies are not enforced on it`},
+			},
+		},
+	}
+	for _, g := range grid {
+		client := fake.NewSimpleClientset()
+		for _, pod := range g.pods {
+			_, err := client.CoreV1().Pods(pod.Namespace).Create(context.Background(), pod, metav1.CreateOptions{})
+			require.NoError(t, err)
+		}
+		checkContext := &CheckContext{
+			K8sClient: client,
+			Nodes:     nodes,
+			Cluster:   &kopsapi.Cluster{Spec: kopsapi.ClusterSpec{Networking: g.networking}},
+		}
+
+		validation := &ValidationCluster{}
+		require.NoError(t, (&antreaCheck{}).Check(context.Background(), checkContext, validation))
+		assert.Equal(t, g.expected, validation.Failures)
+	}
+}
+
+func TestCertificateExpiryCheck(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	notAfter := server.Certificate().NotAfter
+
+	validation := &ValidationCluster{}
+	check := &certificateExpiryCheck{threshold: time.Until(notAfter) - time.Hour}
+	require.NoError(t, check.Check(context.Background(), &CheckContext{Host: server.URL}, validation))
+	assert.Empty(t, validation.Failures)
+
+	check = &certificateExpiryCheck{threshold: time.Until(notAfter) + time.Hour}
+	require.NoError(t, check.Check(context.Background(), &CheckContext{Host: server.URL}, validation))
+	if assert.Len(t, validation.Failures, 1) {
+		assert.Equal(t, "Certificate", validation.Failures[0].Kind)
+		assert.Contains(t, validation.Failures[0].Message, "expires at "+notAfter.UTC().Format(time.RFC3339))
+	}
+}
@@ -17,6 +17,9 @@ limitations under the License.
 package validation
 
 import (
+	"context"
+	"fmt"
+
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 )
@@ -66,3 +69,41 @@ func isNodeReady(node *v1.Node) bool {
 
 	return true
 }
+
+// nodeConditionsCheck checks that no node reports memory, disk or PID pressure
+type nodeConditionsCheck struct{}
+
+func init() {
+	RegisterCheck(&nodeConditionsCheck{})
+}
+
+var pressureConditions = []v1.NodeConditionType{
+	v1.NodeMemoryPressure,
+	v1.NodeDiskPressure,
+	v1.NodePIDPressure,
+}
+
+func (c *nodeConditionsCheck) Name() string {
+	return "node-conditions"
+}
+
+func (c *nodeConditionsCheck) Check(ctx context.Context, checkContext *CheckContext, validation *ValidationCluster) error {
+	for i := range checkContext.Nodes {
+		node := &checkContext.Nodes[i]
+		for _, conditionType := range pressureConditions {
+			cond := findNodeCondition(node, conditionType)
+			if cond != nil && cond.Status == v1.ConditionTrue {
+				message := fmt.Sprintf("node %q has condition %s", node.Name, conditionType)
+				if cond.Message != "" {
+					message += ": " + cond.Message
+				}
+				validation.addError(&ValidationError{
+					Kind:    "Node",
+					Name:    node.Name,
+					Message: message,
+				})
+			}
+		}
+	}
+	return nil
+}
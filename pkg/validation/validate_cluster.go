@@ -41,6 +41,9 @@ type ValidationCluster struct {
 	Failures []*ValidationError `json:"failures,omitempty"`
 
 	Nodes []*ValidationNode `json:"nodes,omitempty"`
+
+	// Checks are the outcomes of the additional checks which were run
+	Checks []*ValidationCheck `json:"checks,omitempty"`
 }
 
 // ValidationError holds a validation failure
@@ -50,6 +53,8 @@ type ValidationError struct {
 	Message string `json:"message,omitempty"`
 	// The InstanceGroup field is used to indicate which instance group this validation error is coming from
 	InstanceGroup *kops.InstanceGroup `json:"instanceGroup,omitempty"`
+	// The Check field is the name of the additional check which found the failure, if any
+	Check string `json:"check,omitempty"`
 }
 
 type ClusterValidator interface {
@@ -63,6 +68,7 @@ type clusterValidatorImpl struct {
 	instanceGroups []*kops.InstanceGroup
 	host           string
 	k8sClient      kubernetes.Interface
+	checks         []Check
 }
 
 func (v *ValidationCluster) addError(failure *ValidationError) {
@@ -98,7 +104,8 @@ func hasPlaceHolderIP(host string) (bool, error) {
 	return false, nil
 }
 
-func NewClusterValidator(cluster *kops.Cluster, cloud fi.Cloud, instanceGroupList *kops.InstanceGroupList, host string, k8sClient kubernetes.Interface) (ClusterValidator, error) {
+// NewClusterValidator builds a ClusterValidator which, besides validating the nodes and pods, runs the given checks
+func NewClusterValidator(cluster *kops.Cluster, cloud fi.Cloud, instanceGroupList *kops.InstanceGroupList, host string, k8sClient kubernetes.Interface, checks []Check) (ClusterValidator, error) {
 	var instanceGroups []*kops.InstanceGroup
 
 	for i := range instanceGroupList.Items {
@@ -116,6 +123,7 @@ func NewClusterValidator(cluster *kops.Cluster, cloud fi.Cloud, instanceGroupLis
 		instanceGroups: instanceGroups,
 		host:           host,
 		k8sClient:      k8sClient,
+		checks:         checks,
 	}, nil
 }
 
@@ -161,10 +169,20 @@ func (v *clusterValidatorImpl) Validate() (*ValidationCluster, error) {
 	}
 	readyNodes, nodeInstanceGroupMapping := validation.validateNodes(cloudGroups, v.instanceGroups)
 
-	if err := validation.collectPodFailures(ctx, v.k8sClient, readyNodes, nodeInstanceGroupMapping); err != nil {
+	scaledToZero := workersScaledToZero(cloudGroups)
+
+	if err := validation.collectPodFailures(ctx, v.k8sClient, readyNodes, nodeInstanceGroupMapping, scaledToZero); err != nil {
 		return nil, fmt.Errorf("cannot get pod health for %q: %v", clusterName, err)
 	}
 
+	validation.runChecks(ctx, &CheckContext{
+		Cluster:   v.cluster,
+		Cloud:     v.cloud,
+		Host:      v.host,
+		K8sClient: v.k8sClient,
+		Nodes:     nodeList.Items,
+	}, v.checks)
+
 	return validation, nil
 }
 
@@ -174,8 +192,24 @@ var masterStaticPods = []string{
 	"kube-scheduler",
 }
 
+// workersScaledToZero returns true if the cluster has node instance groups and all of them are scaled to zero,
+// in which case pods that only schedule onto worker nodes are expected to remain pending.
+func workersScaledToZero(cloudGroups map[string]*cloudinstances.CloudInstanceGroup) bool {
+	found := false
+	for _, cloudGroup := range cloudGroups {
+		if cloudGroup.InstanceGroup.Spec.Role != kops.InstanceGroupRoleNode {
+			continue
+		}
+		if cloudGroup.TargetSize != 0 || len(cloudGroup.Ready) != 0 || len(cloudGroup.NeedUpdate) != 0 {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
 func (v *ValidationCluster) collectPodFailures(ctx context.Context, client kubernetes.Interface, nodes []v1.Node,
-	nodeInstanceGroupMapping map[string]*kops.InstanceGroup) error {
+	nodeInstanceGroupMapping map[string]*kops.InstanceGroup, scaledToZero bool) error {
 	masterWithoutPod := map[string]map[string]bool{}
 	nodeByAddress := map[string]string{}
 
@@ -217,6 +251,10 @@ func (v *ValidationCluster) collectPodFailures(ctx context.Context, client kuber
 		}
 
 		if pod.Status.Phase == v1.PodPending {
+			if scaledToZero && pod.Spec.NodeName == "" {
+				klog.V(2).Infof("ignoring unscheduled %s pod %q; all node instance groups are scaled to zero", priority, pod.Namespace+"/"+pod.Name)
+				return nil
+			}
 			v.addError(&ValidationError{
 				Kind:          "Pod",
 				Name:          pod.Namespace + "/" + pod.Name,
@@ -121,7 +121,7 @@ func testValidate(t *testing.T, groups map[string]*cloudinstances.CloudInstanceG
 
 	mockcloud := BuildMockCloud(t, groups, cluster, instanceGroups)
 
-	validator, err := NewClusterValidator(cluster, mockcloud, &kopsapi.InstanceGroupList{Items: instanceGroups}, "https://api.testcluster.k8s.local", fake.NewSimpleClientset(objects...))
+	validator, err := NewClusterValidator(cluster, mockcloud, &kopsapi.InstanceGroupList{Items: instanceGroups}, "https://api.testcluster.k8s.local", fake.NewSimpleClientset(objects...), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -146,7 +146,7 @@ func Test_ValidateCloudGroupMissing(t *testing.T) {
 
 	mockcloud := BuildMockCloud(t, nil, cluster, instanceGroups)
 
-	validator, err := NewClusterValidator(cluster, mockcloud, &kopsapi.InstanceGroupList{Items: instanceGroups}, "https://api.testcluster.k8s.local", fake.NewSimpleClientset())
+	validator, err := NewClusterValidator(cluster, mockcloud, &kopsapi.InstanceGroupList{Items: instanceGroups}, "https://api.testcluster.k8s.local", fake.NewSimpleClientset(), nil)
 	require.NoError(t, err)
 	v, err := validator.Validate()
 	require.NoError(t, err)
@@ -749,6 +749,92 @@ func Test_ValidatePodFailure(t *testing.T) {
 	}
 }
 
+func Test_ValidateNodesScaledToZero(t *testing.T) {
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	groups["master-1"] = &cloudinstances.CloudInstanceGroup{
+		InstanceGroup: &kopsapi.InstanceGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "master-1",
+			},
+			Spec: kopsapi.InstanceGroupSpec{
+				Role: kopsapi.InstanceGroupRoleMaster,
+			},
+		},
+		MinSize:    1,
+		TargetSize: 1,
+		Ready: []*cloudinstances.CloudInstance{
+			{
+				ID: "i-00001",
+				Node: &v1.Node{
+					ObjectMeta: metav1.ObjectMeta{Name: "master-1a"},
+					Status: v1.NodeStatus{
+						Conditions: []v1.NodeCondition{
+							{Type: "Ready", Status: v1.ConditionTrue},
+						},
+					},
+				},
+			},
+		},
+	}
+	groups["node-1"] = &cloudinstances.CloudInstanceGroup{
+		InstanceGroup: &kopsapi.InstanceGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node-1",
+			},
+			Spec: kopsapi.InstanceGroupSpec{
+				Role:    kopsapi.InstanceGroupRoleNode,
+				MinSize: fi.Int32(0),
+			},
+		},
+		MinSize:    0,
+		TargetSize: 0,
+	}
+
+	t.Run("unscheduled", func(t *testing.T) {
+		v, err := testValidate(t, groups, makePodList(
+			[]map[string]string{
+				{
+					"name":              "pod1",
+					"priorityClassName": "system-cluster-critical",
+					"ready":             "false",
+					"phase":             string(v1.PodPending),
+				},
+			},
+		))
+
+		require.NoError(t, err)
+		if !assert.Empty(t, v.Failures) {
+			printDebug(t, v)
+		}
+	})
+
+	t.Run("scheduled", func(t *testing.T) {
+		v, err := testValidate(t, groups, makePodList(
+			[]map[string]string{
+				{
+					"name":              "pod1",
+					"priorityClassName": "system-cluster-critical",
+					"ready":             "false",
+					"phase":             string(v1.PodPending),
+					"nodeName":          "master-1a",
+				},
+			},
+		))
+
+		expected := ValidationError{
+			Kind:    "Pod",
+			Name:    "kube-system/pod1",
+			Message: "system-cluster-critical pod \"pod1\" is pending",
+		}
+
+		require.NoError(t, err)
+		if !assert.Len(t, v.Failures, 1) ||
+			!assert.Equal(t, &expected, v.Failures[0]) {
+			printDebug(t, v)
+		}
+	})
+}
+
 func printDebug(t *testing.T, v *ValidationCluster) {
 	t.Logf("cluster - %d failures", len(v.Failures))
 	for _, fail := range v.Failures {
@@ -773,6 +859,7 @@ func dummyPod(podMap map[string]string) v1.Pod {
 		},
 		Spec: v1.PodSpec{
 			PriorityClassName: podMap["priorityClassName"],
+			NodeName:          podMap["nodeName"],
 		},
 		Status: v1.PodStatus{
 			Phase: v1.PodPhase(podMap["phase"]),
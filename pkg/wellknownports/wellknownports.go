@@ -17,6 +17,9 @@ limitations under the License.
 package wellknownports
 
 const (
+	// KopsControllerMetrics is the port where kops-controller serves metrics.
+	KopsControllerMetrics = 3987
+
 	// KopsControllerPort is the port where kops-controller listens.
 	KopsControllerPort = 3988
 
@@ -53,6 +56,22 @@ const (
 	// ProtokubeGossipMemberlist is the port where protokube listens for the memberlist-backed gossip
 	ProtokubeGossipMemberlist = 4000
 
+	// AWSEncryptionProviderHealthCheck is the first of the ports where the aws-encryption-provider KMS plugins
+	// listen for health checks, one port per KMS key, up to AWSEncryptionProviderHealthCheckMaxKeys ports (4010-4014)
+	AWSEncryptionProviderHealthCheck = 4010
+
+	// AWSEncryptionProviderHealthCheckMaxKeys is the number of ports reserved for the aws-encryption-provider health checks
+	AWSEncryptionProviderHealthCheckMaxKeys = 5
+
+	// EtcdMainMetrics is the default port where etcd serves metrics, for the main etcd
+	EtcdMainMetrics = 8081
+
+	// EtcdEventsMetrics is the default port where etcd serves metrics, for the events etcd
+	EtcdEventsMetrics = 8082
+
+	// EtcdCiliumMetrics is the default port where etcd serves metrics, for the cilium etcd
+	EtcdCiliumMetrics = 8083
+
 	// CiliumOperatorPrometheusPort is the port the Cilium Operator exposes metrics
 	CiliumPrometheusOperatorPort = 6942
 
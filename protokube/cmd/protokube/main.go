@@ -29,9 +29,11 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/dns-controller/pkg/dns"
 	"k8s.io/kops/dnsprovider/pkg/dnsprovider"
+	"k8s.io/kops/pkg/logformat"
 	"k8s.io/kops/pkg/wellknownports"
 	"k8s.io/kops/protokube/pkg/gossip"
 	gossipdns "k8s.io/kops/protokube/pkg/gossip/dns"
+	"k8s.io/kops/protokube/pkg/gossip/kopscontroller"
 	_ "k8s.io/kops/protokube/pkg/gossip/memberlist"
 	_ "k8s.io/kops/protokube/pkg/gossip/mesh"
 	"k8s.io/kops/protokube/pkg/protokube"
@@ -49,6 +51,7 @@ var (
 
 func main() {
 	klog.InitFlags(nil)
+	logformat.InitFlags(nil)
 
 	fmt.Printf("protokube version %s\n", BuildVersion)
 
@@ -67,23 +70,27 @@ func run() error {
 	var flagChannels, tlsCert, tlsKey, tlsCA, peerCert, peerKey, peerCA string
 	var etcdBackupImage, etcdBackupStore, etcdImageSource, etcdElectionTimeout, etcdHeartbeatInterval string
 	var dnsUpdateInterval int
+	var gossipSeedAddresses []string
+	var discoveryCA string
 
 	flag.BoolVar(&applyTaints, "apply-taints", applyTaints, "Apply taints to nodes based on the role")
 	flag.BoolVar(&containerized, "containerized", containerized, "Set if we are running containerized.")
 	flag.BoolVar(&initializeRBAC, "initialize-rbac", initializeRBAC, "Set if we should initialize RBAC")
 	flag.BoolVar(&master, "master", master, "Whether or not this node is a master")
-	flag.StringVar(&cloud, "cloud", "aws", "CloudProvider we are using (aws,digitalocean,gce,openstack)")
+	flag.StringVar(&cloud, "cloud", "aws", "CloudProvider we are using (aws,digitalocean,gce,metal,openstack)")
 	flag.StringVar(&clusterID, "cluster-id", clusterID, "Cluster ID")
 	flag.StringVar(&dnsInternalSuffix, "dns-internal-suffix", dnsInternalSuffix, "DNS suffix for internal domain names")
 	flag.StringVar(&dnsServer, "dns-server", dnsServer, "DNS Server")
 	flags.IntVar(&dnsUpdateInterval, "dns-update-interval", 5, "Configure interval at which to update DNS records.")
 	flag.StringVar(&flagChannels, "channels", flagChannels, "channels to install")
-	flag.StringVar(&gossipProtocol, "gossip-protocol", "mesh", "mesh/memberlist")
+	flag.StringVar(&gossipProtocol, "gossip-protocol", "mesh", "mesh/memberlist/kops-controller")
+	flags.StringSliceVar(&gossipSeedAddresses, "gossip-seed", gossipSeedAddresses, "Static gossip seeds, for clouds where they cannot be discovered")
 	flag.StringVar(&gossipListen, "gossip-listen", fmt.Sprintf("0.0.0.0:%d", wellknownports.ProtokubeGossipWeaveMesh), "address:port on which to bind for gossip")
 	flags.StringVar(&gossipSecret, "gossip-secret", gossipSecret, "Secret to use to secure gossip")
 	flag.StringVar(&gossipProtocolSecondary, "gossip-protocol-secondary", "memberlist", "mesh/memberlist")
 	flag.StringVar(&gossipListenSecondary, "gossip-listen-secondary", fmt.Sprintf("0.0.0.0:%d", wellknownports.ProtokubeGossipMemberlist), "address:port on which to bind for gossip")
 	flags.StringVar(&gossipSecretSecondary, "gossip-secret-secondary", gossipSecret, "Secret to use to secure gossip")
+	flags.StringVar(&discoveryCA, "discovery-ca", discoveryCA, "Path to the CA certificate that kops-controller serves discovery records with, if the gossip protocol is kops-controller")
 	flag.StringVar(&peerCA, "peer-ca", peerCA, "Path to a file containing the peer ca in PEM format")
 	flag.StringVar(&peerCert, "peer-cert", peerCert, "Path to a file containing the peer certificate")
 	flag.StringVar(&peerKey, "peer-key", peerKey, "Path to a file containing the private key for the peers")
@@ -118,6 +125,10 @@ func run() error {
 	flags.AddGoFlagSet(flag.CommandLine)
 	flags.Parse(os.Args)
 
+	if err := logformat.Apply(); err != nil {
+		return err
+	}
+
 	var volumes protokube.Volumes
 	var internalIP net.IP
 
@@ -206,6 +217,14 @@ func run() error {
 		if clusterID == "" {
 			clusterID = azureVolumes.ClusterID()
 		}
+	} else if cloud == "metal" {
+		klog.Info("Initializing metal")
+		var err error
+		internalIP, err = protokube.GetMetalInternalIP()
+		if err != nil {
+			klog.Errorf("Error getting internal IP: %v", err)
+			os.Exit(1)
+		}
 	} else {
 		klog.Errorf("Unknown cloud %q", cloud)
 		os.Exit(1)
@@ -214,6 +233,7 @@ func run() error {
 	if clusterID == "" {
 		return fmt.Errorf("cluster-id is required (cannot be determined from cloud)")
 	}
+	logformat.WithValues("cluster", clusterID)
 	klog.Infof("cluster-id: %s", clusterID)
 
 	if internalIP == nil {
@@ -286,6 +306,12 @@ func run() error {
 				return err
 			}
 			gossipName = volumes.(*protokube.AzureVolumes).InstanceID()
+		} else if cloud == "metal" {
+			gossipSeeds = gossip.NewStaticSeedProvider(gossipSeedAddresses)
+			gossipName, err = os.Hostname()
+			if err != nil {
+				return fmt.Errorf("error getting hostname: %v", err)
+			}
 		} else {
 			klog.Fatalf("seed provider for %q not yet implemented", cloud)
 		}
@@ -298,7 +324,12 @@ func run() error {
 		channelName := "dns"
 		var gossipState gossip.GossipState
 
-		gossipState, err = gossip.GetGossipState(gossipProtocol, gossipListen, channelName, gossipName, []byte(gossipSecret), gossipSeeds)
+		if gossipProtocol == kopscontroller.ProtocolName {
+			// The seeds are used to find the kops-controllers, which serve the records dns-controller wrote
+			gossipState, err = kopscontroller.NewClientState(gossipSeeds, discoveryCA, "kops-controller"+dnsInternalSuffix)
+		} else {
+			gossipState, err = gossip.GetGossipState(gossipProtocol, gossipListen, channelName, gossipName, []byte(gossipSecret), gossipSeeds)
+		}
 		if err != nil {
 			klog.Errorf("Error initializing gossip: %v", err)
 			os.Exit(1)
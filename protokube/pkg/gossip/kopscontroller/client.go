@@ -0,0 +1,241 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kopscontroller
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/wellknownports"
+	"k8s.io/kops/protokube/pkg/gossip"
+)
+
+// ClientState is the gossip state of protokube, read from the discovery endpoint of kops-controller.
+// Values written locally, such as the zone records, are kept on this node only.
+type ClientState struct {
+	seeds      gossip.SeedProvider
+	httpClient *http.Client
+
+	// pollInterval is how often kops-controller is polled for changes
+	pollInterval time.Duration
+
+	mutex sync.Mutex
+	// server is the kops-controller we last read the records from; we keep using it until it fails
+	server string
+	// etag identifies the records we last read, so unchanged records are not sent again
+	etag         string
+	remoteValues map[string]string
+	localValues  map[string]string
+	lastSnapshot *gossip.GossipStateSnapshot
+}
+
+var _ gossip.GossipState = &ClientState{}
+
+// NewClientState builds a ClientState that polls the kops-controllers found through seeds.
+// The kops-controller certificate must be issued by the CA in caPath for serverName.
+func NewClientState(seeds gossip.SeedProvider, caPath string, serverName string) (*ClientState, error) {
+	caBytes, err := ioutil.ReadFile(caPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading CA certificate %q: %v", caPath, err)
+	}
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(caBytes) {
+		return nil, fmt.Errorf("no certificates found in %q", caPath)
+	}
+
+	httpClient := &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			TLSHandshakeTimeout: 5 * time.Second,
+			TLSClientConfig: &tls.Config{
+				RootCAs:    certPool,
+				ServerName: serverName,
+				MinVersion: tls.VersionTLS12,
+			},
+		},
+	}
+
+	return newClientState(seeds, httpClient), nil
+}
+
+func newClientState(seeds gossip.SeedProvider, httpClient *http.Client) *ClientState {
+	return &ClientState{
+		seeds:        seeds,
+		httpClient:   httpClient,
+		pollInterval: 10 * time.Second,
+		remoteValues: make(map[string]string),
+		localValues:  make(map[string]string),
+		lastSnapshot: &gossip.GossipStateSnapshot{
+			Values: make(map[string]string),
+		},
+	}
+}
+
+func (s *ClientState) Snapshot() *gossip.GossipStateSnapshot {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.lastSnapshot
+}
+
+// UpdateValues changes the local values; only dns-controller can change the records served by kops-controller
+func (s *ClientState) UpdateValues(removeKeys []string, putKeys map[string]string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, k := range removeKeys {
+		delete(s.localValues, k)
+	}
+	for k, v := range putKeys {
+		s.localValues[k] = v
+	}
+	s.updateSnapshot()
+	return nil
+}
+
+// updateSnapshot merges the remote and local values, and replaces the snapshot if they have changed.
+// The caller must hold the mutex.
+func (s *ClientState) updateSnapshot() {
+	values := make(map[string]string)
+	for k, v := range s.remoteValues {
+		values[k] = v
+	}
+	for k, v := range s.localValues {
+		values[k] = v
+	}
+
+	if equalValues(s.lastSnapshot.Values, values) {
+		return
+	}
+	s.lastSnapshot = &gossip.GossipStateSnapshot{
+		Values:  values,
+		Version: s.lastSnapshot.Version + 1,
+	}
+}
+
+// Start polls kops-controller; it only returns on error
+func (s *ClientState) Start() error {
+	for {
+		if err := s.poll(); err != nil {
+			klog.Warningf("error reading discovery records: %v", err)
+		}
+		time.Sleep(s.pollInterval)
+	}
+}
+
+// poll reads the records from the last kops-controller that worked, falling back to the seeds
+func (s *ClientState) poll() error {
+	s.mutex.Lock()
+	server := s.server
+	s.mutex.Unlock()
+
+	if server != "" {
+		err := s.fetch(server)
+		if err == nil {
+			return nil
+		}
+		klog.Infof("error reading discovery records from %s, trying other servers: %v", server, err)
+	}
+
+	seeds, err := s.seeds.GetSeeds()
+	if err != nil {
+		return fmt.Errorf("error finding kops-controller servers: %v", err)
+	}
+	if len(seeds) == 0 {
+		return fmt.Errorf("no kops-controller servers found")
+	}
+
+	var errs []error
+	for _, seed := range seeds {
+		if _, _, err := net.SplitHostPort(seed); err != nil {
+			seed = net.JoinHostPort(seed, strconv.Itoa(wellknownports.KopsControllerPort))
+		}
+		if seed == server {
+			continue
+		}
+		err := s.fetch(seed)
+		if err == nil {
+			s.mutex.Lock()
+			s.server = seed
+			s.mutex.Unlock()
+			return nil
+		}
+		klog.V(2).Infof("error reading discovery records from %s: %v", seed, err)
+		errs = append(errs, err)
+	}
+	return fmt.Errorf("no kops-controller server could be read (%d tried): %v", len(errs), errs)
+}
+
+// fetch reads the records from the kops-controller at server
+func (s *ClientState) fetch(server string) error {
+	u := url.URL{
+		Scheme: "https",
+		Host:   server,
+		Path:   DiscoveryPath,
+	}
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	etag := s.etag
+	s.mutex.Unlock()
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	response, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusNotModified:
+		return nil
+	case http.StatusOK:
+	default:
+		detail, _ := ioutil.ReadAll(response.Body)
+		return fmt.Errorf("unexpected status %q: %s", response.Status, string(detail))
+	}
+
+	discovery := &DiscoveryResponse{}
+	if err := json.NewDecoder(response.Body).Decode(discovery); err != nil {
+		return fmt.Errorf("error parsing discovery response: %v", err)
+	}
+	if discovery.Values == nil {
+		discovery.Values = make(map[string]string)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.etag = response.Header.Get("ETag")
+	s.remoteValues = discovery.Values
+	s.updateSnapshot()
+	return nil
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kopscontroller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/protokube/pkg/gossip"
+)
+
+// ConfigMapState is the gossip state of dns-controller, stored in a ConfigMap
+type ConfigMapState struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+
+	// refreshInterval is how often the ConfigMap is re-read, in case it was changed by someone else
+	refreshInterval time.Duration
+
+	mutex        sync.Mutex
+	lastSnapshot *gossip.GossipStateSnapshot
+}
+
+var _ gossip.GossipState = &ConfigMapState{}
+
+// NewConfigMapState builds a ConfigMapState for the discovery ConfigMap
+func NewConfigMapState(client kubernetes.Interface) *ConfigMapState {
+	return &ConfigMapState{
+		client:          client,
+		namespace:       ConfigMapNamespace,
+		name:            ConfigMapName,
+		refreshInterval: time.Minute,
+		lastSnapshot: &gossip.GossipStateSnapshot{
+			Values: make(map[string]string),
+		},
+	}
+}
+
+func (s *ConfigMapState) Snapshot() *gossip.GossipStateSnapshot {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.lastSnapshot
+}
+
+func (s *ConfigMapState) UpdateValues(removeKeys []string, putKeys map[string]string) error {
+	if len(removeKeys) == 0 && len(putKeys) == 0 {
+		return nil
+	}
+
+	ctx := context.TODO()
+
+	var values map[string]string
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return fmt.Errorf("error reading ConfigMap %s/%s: %v", s.namespace, s.name, err)
+			}
+			configMap = nil
+		}
+
+		values, err = ValuesFromConfigMap(configMap)
+		if err != nil {
+			return err
+		}
+		for _, k := range removeKeys {
+			delete(values, k)
+		}
+		for k, v := range putKeys {
+			values[k] = v
+		}
+
+		if configMap == nil {
+			configMap = &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: s.namespace,
+					Name:      s.name,
+				},
+			}
+			if err := setConfigMapValues(configMap, values); err != nil {
+				return err
+			}
+			_, err = s.client.CoreV1().ConfigMaps(s.namespace).Create(ctx, configMap, metav1.CreateOptions{})
+			if apierrors.IsAlreadyExists(err) {
+				// Created concurrently; treat as a conflict so we re-read it
+				return apierrors.NewConflict(v1.Resource("configmaps"), s.name, err)
+			}
+		} else {
+			if err := setConfigMapValues(configMap, values); err != nil {
+				return err
+			}
+			_, err = s.client.CoreV1().ConfigMaps(s.namespace).Update(ctx, configMap, metav1.UpdateOptions{})
+		}
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error updating ConfigMap %s/%s: %v", s.namespace, s.name, err)
+	}
+
+	s.setValues(values)
+	return nil
+}
+
+// setValues replaces the snapshot if the values have changed
+func (s *ConfigMapState) setValues(values map[string]string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if equalValues(s.lastSnapshot.Values, values) {
+		return
+	}
+	s.lastSnapshot = &gossip.GossipStateSnapshot{
+		Values:  values,
+		Version: s.lastSnapshot.Version + 1,
+	}
+}
+
+// refresh re-reads the ConfigMap
+func (s *ConfigMapState) refresh(ctx context.Context) error {
+	configMap, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("error reading ConfigMap %s/%s: %v", s.namespace, s.name, err)
+		}
+		configMap = nil
+	}
+
+	values, err := ValuesFromConfigMap(configMap)
+	if err != nil {
+		return err
+	}
+	s.setValues(values)
+	return nil
+}
+
+// Start reads the ConfigMap periodically; it only returns on error
+func (s *ConfigMapState) Start() error {
+	for {
+		if err := s.refresh(context.TODO()); err != nil {
+			klog.Warningf("error refreshing discovery records: %v", err)
+		}
+		time.Sleep(s.refreshInterval)
+	}
+}
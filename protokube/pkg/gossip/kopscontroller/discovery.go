@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kopscontroller replaces the gossip mesh with discovery records that are kept in etcd.
+// dns-controller writes the records to a ConfigMap, and kops-controller serves them to protokube on every node.
+package kopscontroller
+
+import (
+	"encoding/json"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ProtocolName is the gossip protocol name that selects discovery through kops-controller.
+	ProtocolName = "kops-controller"
+
+	// ConfigMapNamespace is the namespace of the ConfigMap holding the discovery records.
+	ConfigMapNamespace = "kube-system"
+	// ConfigMapName is the name of the ConfigMap holding the discovery records.
+	ConfigMapName = "kops-discovery"
+	// configMapKey is the key of the ConfigMap data the records are stored under.
+	// ConfigMap keys cannot contain the slashes the record keys use, so the records are stored as a single JSON document.
+	configMapKey = "values.json"
+
+	// DiscoveryPath is the path kops-controller serves the discovery records on.
+	DiscoveryPath = "/discovery"
+)
+
+// DiscoveryResponse is the body of a response from the discovery endpoint.
+type DiscoveryResponse struct {
+	// Values are the discovery records, in the same format as gossip values.
+	Values map[string]string `json:"values"`
+}
+
+// ValuesFromConfigMap returns the discovery records stored in the ConfigMap.
+func ValuesFromConfigMap(configMap *v1.ConfigMap) (map[string]string, error) {
+	values := make(map[string]string)
+	if configMap == nil || configMap.Data[configMapKey] == "" {
+		return values, nil
+	}
+	if err := json.Unmarshal([]byte(configMap.Data[configMapKey]), &values); err != nil {
+		return nil, fmt.Errorf("error parsing discovery records in ConfigMap %s/%s: %v", configMap.Namespace, configMap.Name, err)
+	}
+	return values, nil
+}
+
+// setConfigMapValues stores the discovery records in the ConfigMap.
+func setConfigMapValues(configMap *v1.ConfigMap, values map[string]string) error {
+	b, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("error serializing discovery records: %v", err)
+	}
+	if configMap.Data == nil {
+		configMap.Data = make(map[string]string)
+	}
+	configMap.Data[configMapKey] = string(b)
+	return nil
+}
+
+// equalValues returns true if both sets of records are the same.
+func equalValues(l, r map[string]string) bool {
+	if len(l) != len(r) {
+		return false
+	}
+	for k, v := range l {
+		if rv, found := r[k]; !found || rv != v {
+			return false
+		}
+	}
+	return true
+}
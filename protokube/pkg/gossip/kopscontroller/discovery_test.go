@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kopscontroller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kops/protokube/pkg/gossip"
+)
+
+func TestConfigMapState(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	state := NewConfigMapState(client)
+
+	if err := state.UpdateValues(nil, map[string]string{
+		"dns/local/A/api.internal.minimal.k8s.local": "10.0.0.1",
+		"dns/local/A/kops-controller.internal":       "10.0.0.1",
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := state.UpdateValues([]string{"dns/local/A/kops-controller.internal"}, map[string]string{
+		"dns/local/A/api.internal.minimal.k8s.local": "10.0.0.1,10.0.0.2",
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"dns/local/A/api.internal.minimal.k8s.local": "10.0.0.1,10.0.0.2",
+	}
+
+	snapshot := state.Snapshot()
+	if !reflect.DeepEqual(snapshot.Values, expected) {
+		t.Errorf("unexpected snapshot values: %v", snapshot.Values)
+	}
+	if snapshot.Version != 2 {
+		t.Errorf("unexpected snapshot version %d", snapshot.Version)
+	}
+
+	configMap, err := client.CoreV1().ConfigMaps(ConfigMapNamespace).Get(context.TODO(), ConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("error reading ConfigMap: %v", err)
+	}
+	values, err := ValuesFromConfigMap(configMap)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("unexpected ConfigMap values: %v", values)
+	}
+
+	// Re-reading unchanged values must not change the version
+	if err := state.refresh(context.TODO()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if state.Snapshot().Version != 2 {
+		t.Errorf("unexpected snapshot version %d after refresh", state.Snapshot().Version)
+	}
+}
+
+func TestClientState(t *testing.T) {
+	values := map[string]string{
+		"dns/local/A/api.internal.minimal.k8s.local": "10.0.0.1",
+	}
+	etag := `"1"`
+	requests := 0
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != DiscoveryPath {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		_ = json.NewEncoder(w).Encode(&DiscoveryResponse{Values: values})
+	}))
+	defer server.Close()
+
+	address := strings.TrimPrefix(server.URL, "https://")
+	state := newClientState(gossip.NewStaticSeedProvider([]string{"127.0.0.1:1", address}), server.Client())
+
+	if err := state.UpdateValues(nil, map[string]string{"dns/local/NS/local": "gossip"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := state.poll(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	snapshot := state.Snapshot()
+	expected := map[string]string{
+		"dns/local/A/api.internal.minimal.k8s.local": "10.0.0.1",
+		"dns/local/NS/local":                         "gossip",
+	}
+	if !reflect.DeepEqual(snapshot.Values, expected) {
+		t.Errorf("unexpected snapshot values: %v", snapshot.Values)
+	}
+	if state.server != address {
+		t.Errorf("expected server %q to be remembered, got %q", address, state.server)
+	}
+
+	// Unchanged records are not sent again, and do not change the version
+	if err := state.poll(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if state.Snapshot() != snapshot {
+		t.Errorf("snapshot changed although the records did not")
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+}
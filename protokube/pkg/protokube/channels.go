@@ -25,12 +25,25 @@ import (
 	"k8s.io/klog/v2"
 )
 
-// applyChannel is responsible for applying the channel manifests
-func applyChannel(channel string) error {
+// channelsCacheDir keeps the last fetched channels and manifests, so an outage of the state store doesn't block applies
+const channelsCacheDir = "/var/cache/kops/channels"
+
+// clusterFactsFile holds the cluster facts written by nodeup, that addon conditions are evaluated against
+const clusterFactsFile = "/var/lib/kops/cluster-facts.yaml"
+
+// addonOverridesFile holds the addons disabled or pinned in the cluster spec, written by nodeup
+const addonOverridesFile = "/var/lib/kops/addon-overrides.yaml"
+
+// applyChannels is responsible for applying the channel manifests.
+// The channels are applied together, with later channels taking precedence when they ship the same addon.
+func applyChannels(channels []string) error {
 	// We don't embed the channels code because we expect this will eventually be part of kubectl
-	klog.Infof("checking channel: %q", channel)
+	klog.Infof("checking channels: %q", channels)
 
-	out, err := execChannels("apply", "channel", channel, "--v=4", "--yes")
+	args := []string{"apply", "channel"}
+	args = append(args, channels...)
+	args = append(args, "--cache-dir="+channelsCacheDir, "--facts-file="+clusterFactsFile, "--overrides-file="+addonOverridesFile, "--v=4", "--yes")
+	out, err := execChannels(args...)
 	klog.V(4).Infof("apply channel output was: %v", out)
 	return err
 }
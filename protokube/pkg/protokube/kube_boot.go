@@ -36,7 +36,7 @@ var (
 
 // KubeBoot is the options for the protokube service
 type KubeBoot struct {
-	// Channels is a list of channel to apply
+	// Channels is a list of channel to apply, with later channels taking precedence
 	Channels []string
 	// InitializeRBAC should be set to true if we should create the core RBAC roles
 	InitializeRBAC bool
@@ -171,9 +171,9 @@ func (k *KubeBoot) syncOnce(ctx context.Context) error {
 				klog.Warningf("error initializing rbac: %v", err)
 			}
 		}
-		for _, channel := range k.Channels {
-			if err := applyChannel(channel); err != nil {
-				klog.Warningf("error applying channel %q: %v", channel, err)
+		if len(k.Channels) != 0 {
+			if err := applyChannels(k.Channels); err != nil {
+				klog.Warningf("error applying channels %q: %v", k.Channels, err)
 			}
 		}
 	}
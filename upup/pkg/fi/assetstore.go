@@ -217,6 +217,27 @@ func (a *AssetStore) Add(id string) error {
 	return fmt.Errorf("unknown asset format: %q", id)
 }
 
+// IsCached returns true if the asset, in the hash@urls format, was already downloaded with a matching hash,
+// so that adding it won't download it again
+func (a *AssetStore) IsCached(id string) bool {
+	i := strings.Index(id, "@http")
+	if i == -1 {
+		return false
+	}
+	hash, err := hashing.FromString(id[:i])
+	if err != nil {
+		return false
+	}
+	primaryURL := strings.Split(id[i+1:], ",")[0]
+	match, err := fileHasHash(a.localFile(primaryURL, hash), hash)
+	return err == nil && match
+}
+
+// localFile returns the path in the cache that the asset downloaded from primaryURL is stored at
+func (a *AssetStore) localFile(primaryURL string, hash *hashing.Hash) string {
+	return path.Join(a.cacheDir, hash.String()+"_"+utils.SanitizeString(primaryURL))
+}
+
 func (a *AssetStore) addURLs(urls []string, hash *hashing.Hash) error {
 	if len(urls) == 0 {
 		return fmt.Errorf("no urls were specified")
@@ -240,7 +261,7 @@ func (a *AssetStore) addURLs(urls []string, hash *hashing.Hash) error {
 
 	// We assume the first url is the "main" url, and download to that _name_, wherever we get it from
 	primaryURL := urls[0]
-	localFile := path.Join(a.cacheDir, hash.String()+"_"+utils.SanitizeString(primaryURL))
+	localFile := a.localFile(primaryURL, hash)
 
 	for _, url := range urls {
 		_, err = DownloadURL(url, localFile, hash)
@@ -0,0 +1,240 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assettasks
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/remotes"
+	digest "github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// BundleWriter writes the assets of a cluster to a tar archive, so that air-gapped clusters can be installed
+// without internet access. Files are written in the layout of a file repository, and images as an OCI image layout.
+type BundleWriter struct {
+	tw       *tar.Writer
+	registry *ociRegistry
+	blobs    map[digest.Digest]bool
+	index    ocispec.Index
+	modTime  time.Time
+}
+
+var _ remotes.Pusher = &BundleWriter{}
+
+// NewBundleWriter builds a BundleWriter writing the archive to w
+func NewBundleWriter(w io.Writer) *BundleWriter {
+	return &BundleWriter{
+		tw:      tar.NewWriter(w),
+		blobs:   make(map[digest.Digest]bool),
+		index:   ocispec.Index{Versioned: specs.Versioned{SchemaVersion: 2}},
+		modTime: time.Now(),
+	}
+}
+
+// WriteFile writes a file to the archive
+func (b *BundleWriter) WriteFile(name string, data []byte) error {
+	if err := b.writeHeader(name, int64(len(data))); err != nil {
+		return err
+	}
+	if _, err := b.tw.Write(data); err != nil {
+		return fmt.Errorf("error writing %q to bundle: %v", name, err)
+	}
+	return nil
+}
+
+func (b *BundleWriter) writeHeader(name string, size int64) error {
+	header := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0644,
+		Size:     size,
+		ModTime:  b.modTime,
+	}
+	if err := b.tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("error writing %q to bundle: %v", name, err)
+	}
+	return nil
+}
+
+// AddFile downloads the file, verifies that it matches the sha, and writes the file and its hash to the archive,
+// at the path of the file in the file repository
+func (b *BundleWriter) AddFile(source *url.URL, sha string) error {
+	sha = strings.TrimSpace(sha)
+	shaExtension, err := fileExtensionForSHA(sha)
+	if err != nil {
+		return err
+	}
+	shaHash, err := hashing.FromString(sha)
+	if err != nil {
+		return fmt.Errorf("unable to parse sha: %q, %v", sha, err)
+	}
+
+	klog.Infof("adding file %q to bundle", source)
+	data, err := vfs.Context.ReadFile(source.String())
+	if err != nil {
+		return fmt.Errorf("error downloading file %q: %v", source, err)
+	}
+
+	dataHash, err := shaHash.Algorithm.Hash(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("unable to hash file %q: %v", source, err)
+	}
+	if !shaHash.Equal(dataHash) {
+		return fmt.Errorf("the file %q has hash %q, expected %q", source, dataHash.String(), shaHash.String())
+	}
+
+	name := path.Join(assets.BundleFilesDirectory, source.Path)
+	if err := b.WriteFile(name, data); err != nil {
+		return err
+	}
+	return b.WriteFile(name+shaExtension, []byte(shaHash.Hex()))
+}
+
+// AddImage adds the image, including all its platforms, to the archive
+func (b *BundleWriter) AddImage(ctx context.Context, image string) error {
+	if b.registry == nil {
+		registry, err := newOCIRegistry(ctx)
+		if err != nil {
+			return err
+		}
+		b.registry = registry
+	}
+
+	klog.Infof("adding image %q to bundle", image)
+	name, desc, err := b.registry.resolver.Resolve(ctx, image)
+	if err != nil {
+		return fmt.Errorf("error resolving image %q: %v", image, err)
+	}
+	fetcher, err := b.registry.resolver.Fetcher(ctx, name)
+	if err != nil {
+		return fmt.Errorf("error fetching image %q: %v", image, err)
+	}
+
+	return b.addImage(ctx, image, fetcher, desc)
+}
+
+func (b *BundleWriter) addImage(ctx context.Context, image string, fetcher remotes.Fetcher, desc ocispec.Descriptor) error {
+	if err := copyDescriptor(ctx, fetcher, b, desc); err != nil {
+		return fmt.Errorf("error adding image %q to bundle: %v", image, err)
+	}
+
+	desc.Annotations = map[string]string{ocispec.AnnotationRefName: image}
+	b.index.Manifests = append(b.index.Manifests, desc)
+	return nil
+}
+
+// Push writes a blob to the image layout of the archive; blobs are only written once
+func (b *BundleWriter) Push(ctx context.Context, desc ocispec.Descriptor) (content.Writer, error) {
+	if b.blobs[desc.Digest] {
+		return nil, errdefs.ErrAlreadyExists
+	}
+	if err := desc.Digest.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid digest %q: %v", desc.Digest, err)
+	}
+
+	name := path.Join(assets.BundleImagesDirectory, "blobs", desc.Digest.Algorithm().String(), desc.Digest.Hex())
+	if err := b.writeHeader(name, desc.Size); err != nil {
+		return nil, err
+	}
+	return &bundleBlobWriter{
+		bundle:   b,
+		desc:     desc,
+		digester: desc.Digest.Algorithm().Digester(),
+	}, nil
+}
+
+// Close writes the index of the images, and closes the archive
+func (b *BundleWriter) Close() error {
+	layout, err := json.Marshal(ocispec.ImageLayout{Version: ocispec.ImageLayoutVersion})
+	if err != nil {
+		return fmt.Errorf("error building image layout: %v", err)
+	}
+	if err := b.WriteFile(path.Join(assets.BundleImagesDirectory, ocispec.ImageLayoutFile), layout); err != nil {
+		return err
+	}
+
+	index, err := json.Marshal(b.index)
+	if err != nil {
+		return fmt.Errorf("error building image index: %v", err)
+	}
+	if err := b.WriteFile(path.Join(assets.BundleImagesDirectory, "index.json"), index); err != nil {
+		return err
+	}
+
+	return b.tw.Close()
+}
+
+// bundleBlobWriter streams a blob to the archive, verifying its digest
+type bundleBlobWriter struct {
+	bundle   *BundleWriter
+	desc     ocispec.Descriptor
+	digester digest.Digester
+	written  int64
+}
+
+func (w *bundleBlobWriter) Write(p []byte) (int, error) {
+	n, err := w.bundle.tw.Write(p)
+	w.digester.Hash().Write(p[:n])
+	w.written += int64(n)
+	return n, err
+}
+
+func (w *bundleBlobWriter) Close() error {
+	return nil
+}
+
+func (w *bundleBlobWriter) Digest() digest.Digest {
+	return w.digester.Digest()
+}
+
+func (w *bundleBlobWriter) Commit(ctx context.Context, size int64, expected digest.Digest, opts ...content.Opt) error {
+	if w.written != size || w.digester.Digest() != expected {
+		return fmt.Errorf("digest mismatch writing %s to bundle", expected)
+	}
+	w.bundle.blobs[expected] = true
+	return nil
+}
+
+func (w *bundleBlobWriter) Status() (content.Status, error) {
+	return content.Status{
+		Ref:      w.desc.Digest.String(),
+		Offset:   w.written,
+		Total:    w.desc.Size,
+		Expected: w.desc.Digest,
+	}, nil
+}
+
+func (w *bundleBlobWriter) Truncate(size int64) error {
+	return fmt.Errorf("blobs of a bundle cannot be truncated")
+}
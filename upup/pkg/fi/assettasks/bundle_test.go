@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assettasks
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func TestBundleRoundTrip(t *testing.T) {
+	config := []byte(`{"architecture":"amd64"}`)
+	layer := []byte("layer")
+
+	manifest, err := json.Marshal(ocispec.Manifest{
+		Config: blobDescriptor(ocispec.MediaTypeImageConfig, config),
+		Layers: []ocispec.Descriptor{
+			blobDescriptor(ocispec.MediaTypeImageLayer, layer),
+		},
+	})
+	if err != nil {
+		t.Fatalf("error building manifest: %v", err)
+	}
+	manifestDesc := blobDescriptor(ocispec.MediaTypeImageManifest, manifest)
+
+	source := &memoryRegistry{blobs: map[digest.Digest][]byte{
+		digest.FromBytes(config): config,
+		digest.FromBytes(layer):  layer,
+		manifestDesc.Digest:      manifest,
+	}}
+
+	var archive bytes.Buffer
+	bundle := NewBundleWriter(&archive)
+	if err := bundle.WriteFile("channels/stable", []byte("spec: {}")); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+	// The same image under two names is only stored once
+	for _, image := range []string{"k8s.gcr.io/pause:3.2", "registry.example.com/pause:3.2"} {
+		if err := bundle.addImage(context.TODO(), image, source, manifestDesc); err != nil {
+			t.Fatalf("error adding image: %v", err)
+		}
+	}
+	if err := bundle.Close(); err != nil {
+		t.Fatalf("error closing bundle: %v", err)
+	}
+
+	dir, err := ioutil.TempDir("", "bundle")
+	if err != nil {
+		t.Fatalf("error creating directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	names := make(map[string]int)
+	tr := tar.NewReader(&archive)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("error reading bundle: %v", err)
+		}
+		names[header.Name]++
+		p := filepath.Join(dir, header.Name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("error creating directory: %v", err)
+		}
+		b, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("error reading %q: %v", header.Name, err)
+		}
+		if err := ioutil.WriteFile(p, b, 0644); err != nil {
+			t.Fatalf("error writing %q: %v", header.Name, err)
+		}
+	}
+
+	for _, name := range []string{"channels/stable", "images/oci-layout", "images/index.json", "images/blobs/sha256/" + manifestDesc.Digest.Hex()} {
+		if names[name] != 1 {
+			t.Errorf("expected %q once in bundle, found %d times", name, names[name])
+		}
+	}
+
+	layout, err := newOCILayout(filepath.Join(dir, "images"))
+	if err != nil {
+		t.Fatalf("error reading layout: %v", err)
+	}
+	if layout.resolve("k8s.gcr.io/kube-apiserver:v1.21.0") != nil {
+		t.Errorf("unexpected image found in layout")
+	}
+	desc := layout.resolve("k8s.gcr.io/pause:3.2")
+	if desc == nil {
+		t.Fatalf("image not found in layout")
+	}
+
+	target := &memoryRegistry{blobs: map[digest.Digest][]byte{}}
+	if err := copyDescriptor(context.TODO(), layout, target, *desc); err != nil {
+		t.Fatalf("unexpected error copying image from layout: %v", err)
+	}
+	for d := range source.blobs {
+		if !bytes.Equal(source.blobs[d], target.blobs[d]) {
+			t.Errorf("blob %s was not copied", d)
+		}
+	}
+}
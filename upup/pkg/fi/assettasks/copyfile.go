@@ -192,6 +192,19 @@ func transferFile(c *fi.Context, source string, target string, sha string) error
 		return err
 	}
 
+	// Verify the upload before writing the hash, so an interrupted copy is retried on the next run
+	uploaded, err := uploadVFS.ReadFile()
+	if err != nil {
+		return fmt.Errorf("error reading back %q: %v", objectStore, err)
+	}
+	uploadedHash, err := shaHash.Algorithm.Hash(bytes.NewReader(uploaded))
+	if err != nil {
+		return fmt.Errorf("unable to hash uploaded file %q: %v", objectStore, err)
+	}
+	if !shaHash.Equal(uploadedHash) {
+		return fmt.Errorf("the uploaded file %q has hash %q, expected %q", objectStore, uploadedHash.String(), shaHash.String())
+	}
+
 	b := []byte(shaHash.Hex())
 	if err := writeFile(c, shaVFS, b); err != nil {
 		return err
@@ -17,8 +17,10 @@ limitations under the License.
 package assettasks
 
 import (
+	"context"
 	"fmt"
 
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/upup/pkg/fi"
 )
@@ -30,7 +32,11 @@ type CopyImage struct {
 	Name        *string
 	SourceImage *string
 	TargetImage *string
-	Lifecycle   fi.Lifecycle
+	// UseDocker copies the image with the docker daemon, instead of directly between the registries
+	UseDocker *bool
+	// SourceLayout is an OCI image layout holding the source image, such as the images of an extracted bundle
+	SourceLayout *string
+	Lifecycle    fi.Lifecycle
 }
 
 var _ fi.CompareWithID = &CopyImage{}
@@ -40,59 +46,77 @@ func (e *CopyImage) CompareWithID() *string {
 }
 
 func (e *CopyImage) Find(c *fi.Context) (*CopyImage, error) {
-	return nil, nil
-
-	// The problem here is that we can tag a local image with the remote tag, but there is no way to know
-	// if that has actually been pushed to the remote registry without doing a docker push
-
-	// The solution is probably to query the registries directly, but that is a little bit more code...
-
-	// For now, we just always do the copy; it isn't _too_ slow when things have already been pushed
-
-	//d, err := newDocker()
-	//if err != nil {
-	//	return nil, err
-	//}
-	//
-	//source := fi.StringValue(e.SourceImage)
-	//target := fi.StringValue(e.TargetImage)
-	//
-	//targetImage, err := d.findImage(target)
-	//if err != nil {
-	//	return nil, err
-	//}
-	//if targetImage == nil {
-	//	klog.V(4).Infof("target image %q not found", target)
-	//	return nil, nil
-	//}
-	//
-	//// We want to verify that the target image matches
-	//if err := d.pullImage(source); err != nil {
-	//	return nil, err
-	//}
-	//
-	//sourceImage, err := d.findImage(source)
-	//if err != nil {
-	//	return nil, err
-	//}
-	//if sourceImage == nil {
-	//	return nil, fmt.Errorf("source image %q not found", source)
-	//}
-	//
-	//if sourceImage.ID == targetImage.ID {
-	//	actual := &CopyImage{}
-	//	actual.Name = e.Name
-	//	actual.SourceImage = e.SourceImage
-	//	actual.TargetImage = e.TargetImage
-	//	klog.Infof("Found image %q = %s", target, sourceImage.ID)
-	//	return actual, nil
-	//}
-	//
-	//klog.V(2).Infof("Target image %q does not match source %q: %q vs %q",
-	//	target, source,
-	//	targetImage.ID, sourceImage.ID)
-	//
-	//return nil, nil
+	if fi.BoolValue(e.UseDocker) {
+		// We can tag a local image with the remote tag, but there is no way to know
+		// if that has actually been pushed to the remote registry without doing a docker push,
+		// so we always do the copy; it isn't _too_ slow when things have already been pushed
+		return nil, nil
+	}
+
+	ctx := context.TODO()
+	registry, err := newOCIRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	source := fi.StringValue(e.SourceImage)
+	target := fi.StringValue(e.TargetImage)
+
+	targetDesc, err := registry.resolve(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if targetDesc == nil {
+		klog.V(4).Infof("target image %q not found", target)
+		return nil, nil
+	}
+
+	sourceDesc, err := e.resolveSource(ctx, registry)
+	if err != nil {
+		return nil, err
+	}
+
+	if sourceDesc.Digest != targetDesc.Digest {
+		klog.V(2).Infof("target image %q does not match source %q: %s vs %s", target, source, targetDesc.Digest, sourceDesc.Digest)
+		return nil, nil
+	}
+
+	klog.V(2).Infof("found image %q = %s", target, targetDesc.Digest)
+	actual := &CopyImage{
+		Name:         e.Name,
+		SourceImage:  e.SourceImage,
+		TargetImage:  e.TargetImage,
+		UseDocker:    e.UseDocker,
+		SourceLayout: e.SourceLayout,
+		Lifecycle:    e.Lifecycle,
+	}
+	return actual, nil
+}
+
+// resolveSource returns the descriptor of the source image, from the source layout if set
+func (e *CopyImage) resolveSource(ctx context.Context, registry *ociRegistry) (*ocispec.Descriptor, error) {
+	source := fi.StringValue(e.SourceImage)
+
+	if e.SourceLayout != nil {
+		layout, err := newOCILayout(*e.SourceLayout)
+		if err != nil {
+			return nil, err
+		}
+		desc := layout.resolve(source)
+		if desc == nil {
+			return nil, fmt.Errorf("source image %q not found in %q", source, *e.SourceLayout)
+		}
+		return desc, nil
+	}
+
+	desc, err := registry.resolve(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	if desc == nil {
+		return nil, fmt.Errorf("source image %q not found", source)
+	}
+	return desc, nil
 }
 
 func (e *CopyImage) Run(c *fi.Context) error {
@@ -109,10 +133,47 @@ func (s *CopyImage) CheckChanges(a, e, changes *CopyImage) error {
 	if fi.StringValue(e.TargetImage) == "" {
 		return fi.RequiredField("TargetImage")
 	}
+	if e.SourceLayout != nil && fi.BoolValue(e.UseDocker) {
+		return fmt.Errorf("UseDocker cannot be used with SourceLayout")
+	}
 	return nil
 }
 
 func (_ *CopyImage) Render(c *fi.Context, a, e, changes *CopyImage) error {
+	source := fi.StringValue(e.SourceImage)
+	target := fi.StringValue(e.TargetImage)
+
+	if !fi.BoolValue(e.UseDocker) {
+		ctx := context.TODO()
+		registry, err := newOCIRegistry(ctx)
+		if err != nil {
+			return err
+		}
+
+		if e.SourceLayout != nil {
+			layout, err := newOCILayout(*e.SourceLayout)
+			if err != nil {
+				return err
+			}
+			desc := layout.resolve(source)
+			if desc == nil {
+				return fmt.Errorf("source image %q not found in %q", source, *e.SourceLayout)
+			}
+
+			klog.Infof("copying image %q from %q to %q", source, *e.SourceLayout, target)
+			if err := registry.pushImage(ctx, layout, *desc, target); err != nil {
+				return fmt.Errorf("error copying image %q to %q: %v", source, target, err)
+			}
+			return nil
+		}
+
+		klog.Infof("copying image from %q to %q", source, target)
+		if err := registry.copyImage(ctx, source, target); err != nil {
+			return fmt.Errorf("error copying image %q to %q: %v", source, target, err)
+		}
+		return nil
+	}
+
 	api, err := newDockerAPI()
 	if err != nil {
 		return err
@@ -124,9 +185,6 @@ func (_ *CopyImage) Render(c *fi.Context, a, e, changes *CopyImage) error {
 
 	}
 
-	source := fi.StringValue(e.SourceImage)
-	target := fi.StringValue(e.TargetImage)
-
 	klog.Infof("copying docker image from %q to %q", source, target)
 
 	err = cli.pullImage(source)
@@ -0,0 +1,79 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assettasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/remotes"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// ociLayout reads images from an OCI image layout, such as the images of an extracted bundle.
+// Images are referenced by the name in their org.opencontainers.image.ref.name annotation.
+type ociLayout struct {
+	dir   string
+	index ocispec.Index
+}
+
+var _ remotes.Fetcher = &ociLayout{}
+
+func newOCILayout(dir string) (*ociLayout, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, "index.json"))
+	if err != nil {
+		return nil, fmt.Errorf("error reading image layout %q: %v", dir, err)
+	}
+
+	l := &ociLayout{dir: dir}
+	if err := json.Unmarshal(b, &l.index); err != nil {
+		return nil, fmt.Errorf("error parsing index of image layout %q: %v", dir, err)
+	}
+	return l, nil
+}
+
+// resolve returns the descriptor for the image, or nil if the layout does not contain it
+func (l *ociLayout) resolve(image string) *ocispec.Descriptor {
+	for _, desc := range l.index.Manifests {
+		if desc.Annotations[ocispec.AnnotationRefName] == image {
+			desc.Annotations = nil
+			return &desc
+		}
+	}
+	return nil
+}
+
+func (l *ociLayout) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	if err := desc.Digest.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid digest %q: %v", desc.Digest, err)
+	}
+
+	f, err := os.Open(filepath.Join(l.dir, "blobs", desc.Digest.Algorithm().String(), desc.Digest.Hex()))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errdefs.ErrNotFound
+		}
+		return nil, fmt.Errorf("error reading %s from image layout %q: %v", desc.Digest, l.dir, err)
+	}
+	return f, nil
+}
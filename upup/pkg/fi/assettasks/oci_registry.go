@@ -0,0 +1,172 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assettasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/remotes"
+	orasdocker "github.com/deislabs/oras/pkg/auth/docker"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"k8s.io/klog/v2"
+)
+
+// ociRegistry copies images directly between registries, using the credentials from the docker config file.
+// Unlike the docker CLI, it does not need a docker daemon.
+type ociRegistry struct {
+	resolver remotes.Resolver
+}
+
+func newOCIRegistry(ctx context.Context) (*ociRegistry, error) {
+	client, err := orasdocker.NewClient()
+	if err != nil {
+		return nil, fmt.Errorf("error loading docker credentials: %v", err)
+	}
+	resolver, err := client.Resolver(ctx, http.DefaultClient, false)
+	if err != nil {
+		return nil, fmt.Errorf("error building registry resolver: %v", err)
+	}
+	return &ociRegistry{resolver: resolver}, nil
+}
+
+// resolve returns the descriptor for the image, or nil if it does not exist
+func (r *ociRegistry) resolve(ctx context.Context, image string) (*ocispec.Descriptor, error) {
+	_, desc, err := r.resolver.Resolve(ctx, image)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error resolving image %q: %v", image, err)
+	}
+	return &desc, nil
+}
+
+// copyImage copies the source image, including all its platforms, to the target image.
+// Blobs that already exist in the target registry are skipped, and the digest of every copied blob is verified.
+func (r *ociRegistry) copyImage(ctx context.Context, source, target string) error {
+	name, desc, err := r.resolver.Resolve(ctx, source)
+	if err != nil {
+		return fmt.Errorf("error resolving image %q: %v", source, err)
+	}
+
+	fetcher, err := r.resolver.Fetcher(ctx, name)
+	if err != nil {
+		return fmt.Errorf("error fetching image %q: %v", source, err)
+	}
+
+	return r.pushImage(ctx, fetcher, desc, target)
+}
+
+// pushImage pushes the image described by desc, read from fetcher, to the target image
+func (r *ociRegistry) pushImage(ctx context.Context, fetcher remotes.Fetcher, desc ocispec.Descriptor, target string) error {
+	pusher, err := r.resolver.Pusher(ctx, target)
+	if err != nil {
+		return fmt.Errorf("error pushing image %q: %v", target, err)
+	}
+
+	return copyDescriptor(ctx, fetcher, pusher, desc)
+}
+
+// copyDescriptor copies the children of desc, and then desc itself, so that manifests are only pushed after their contents
+func copyDescriptor(ctx context.Context, fetcher remotes.Fetcher, pusher remotes.Pusher, desc ocispec.Descriptor) error {
+	var children []ocispec.Descriptor
+
+	switch desc.MediaType {
+	case images.MediaTypeDockerSchema2ManifestList, ocispec.MediaTypeImageIndex:
+		var index ocispec.Index
+		if err := fetchJSON(ctx, fetcher, desc, &index); err != nil {
+			return err
+		}
+		children = index.Manifests
+
+	case images.MediaTypeDockerSchema2Manifest, ocispec.MediaTypeImageManifest:
+		var manifest ocispec.Manifest
+		if err := fetchJSON(ctx, fetcher, desc, &manifest); err != nil {
+			return err
+		}
+		children = append(children, manifest.Config)
+		children = append(children, manifest.Layers...)
+	}
+
+	for _, child := range children {
+		if err := copyDescriptor(ctx, fetcher, pusher, child); err != nil {
+			return err
+		}
+	}
+
+	return copyBlob(ctx, fetcher, pusher, desc)
+}
+
+func fetchJSON(ctx context.Context, fetcher remotes.Fetcher, desc ocispec.Descriptor, into interface{}) error {
+	rc, err := fetcher.Fetch(ctx, desc)
+	if err != nil {
+		return fmt.Errorf("error fetching %s: %v", desc.Digest, err)
+	}
+	defer rc.Close()
+
+	verifier := desc.Digest.Verifier()
+	if err := json.NewDecoder(io.TeeReader(rc, verifier)).Decode(into); err != nil {
+		return fmt.Errorf("error parsing %s: %v", desc.Digest, err)
+	}
+	// Read any trailing bytes, so the whole blob is verified
+	if _, err := io.Copy(verifier, rc); err != nil {
+		return fmt.Errorf("error reading %s: %v", desc.Digest, err)
+	}
+	if !verifier.Verified() {
+		return fmt.Errorf("digest mismatch for %s", desc.Digest)
+	}
+	return nil
+}
+
+func copyBlob(ctx context.Context, fetcher remotes.Fetcher, pusher remotes.Pusher, desc ocispec.Descriptor) error {
+	writer, err := pusher.Push(ctx, desc)
+	if err != nil {
+		if errdefs.IsAlreadyExists(err) {
+			klog.V(4).Infof("%s already exists in target registry", desc.Digest)
+			return nil
+		}
+		return fmt.Errorf("error pushing %s: %v", desc.Digest, err)
+	}
+	defer writer.Close()
+
+	rc, err := fetcher.Fetch(ctx, desc)
+	if err != nil {
+		return fmt.Errorf("error fetching %s: %v", desc.Digest, err)
+	}
+	defer rc.Close()
+
+	verifier := desc.Digest.Verifier()
+	n, err := io.Copy(writer, io.TeeReader(rc, verifier))
+	if err != nil {
+		return fmt.Errorf("error copying %s: %v", desc.Digest, err)
+	}
+	if n != desc.Size || !verifier.Verified() {
+		// Returning without committing aborts the upload
+		return fmt.Errorf("digest mismatch copying %s (read %d of %d bytes)", desc.Digest, n, desc.Size)
+	}
+
+	if err := writer.Commit(ctx, desc.Size, desc.Digest); err != nil && !errdefs.IsAlreadyExists(err) {
+		return fmt.Errorf("error committing %s: %v", desc.Digest, err)
+	}
+	return nil
+}
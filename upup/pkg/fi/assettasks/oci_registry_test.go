@@ -0,0 +1,139 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assettasks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/remotes"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+type memoryRegistry struct {
+	blobs map[digest.Digest][]byte
+}
+
+var _ remotes.Fetcher = &memoryRegistry{}
+var _ remotes.Pusher = &memoryRegistry{}
+
+func (m *memoryRegistry) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	b, found := m.blobs[desc.Digest]
+	if !found {
+		return nil, errdefs.ErrNotFound
+	}
+	return ioutil.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (m *memoryRegistry) Push(ctx context.Context, desc ocispec.Descriptor) (content.Writer, error) {
+	if _, found := m.blobs[desc.Digest]; found {
+		return nil, errdefs.ErrAlreadyExists
+	}
+	return &memoryWriter{registry: m}, nil
+}
+
+type memoryWriter struct {
+	bytes.Buffer
+	registry *memoryRegistry
+}
+
+func (w *memoryWriter) Close() error {
+	return nil
+}
+
+func (w *memoryWriter) Digest() digest.Digest {
+	return digest.FromBytes(w.Bytes())
+}
+
+func (w *memoryWriter) Commit(ctx context.Context, size int64, expected digest.Digest, opts ...content.Opt) error {
+	if w.Digest() != expected {
+		return fmt.Errorf("unexpected digest")
+	}
+	w.registry.blobs[expected] = w.Bytes()
+	return nil
+}
+
+func (w *memoryWriter) Status() (content.Status, error) {
+	return content.Status{}, nil
+}
+
+func (w *memoryWriter) Truncate(size int64) error {
+	return nil
+}
+
+func blobDescriptor(mediaType string, b []byte) ocispec.Descriptor {
+	return ocispec.Descriptor{
+		MediaType: mediaType,
+		Digest:    digest.FromBytes(b),
+		Size:      int64(len(b)),
+	}
+}
+
+func TestCopyDescriptor(t *testing.T) {
+	config := []byte(`{"architecture":"amd64"}`)
+	layer := []byte("layer")
+	existingLayer := []byte("existing layer")
+
+	manifest, err := json.Marshal(ocispec.Manifest{
+		Config: blobDescriptor(ocispec.MediaTypeImageConfig, config),
+		Layers: []ocispec.Descriptor{
+			blobDescriptor(ocispec.MediaTypeImageLayer, layer),
+			blobDescriptor(ocispec.MediaTypeImageLayer, existingLayer),
+		},
+	})
+	if err != nil {
+		t.Fatalf("error building manifest: %v", err)
+	}
+	manifestDesc := blobDescriptor(ocispec.MediaTypeImageManifest, manifest)
+
+	source := &memoryRegistry{blobs: map[digest.Digest][]byte{
+		digest.FromBytes(config):        config,
+		digest.FromBytes(layer):         layer,
+		digest.FromBytes(existingLayer): existingLayer,
+		manifestDesc.Digest:             manifest,
+	}}
+	target := &memoryRegistry{blobs: map[digest.Digest][]byte{
+		digest.FromBytes(existingLayer): existingLayer,
+	}}
+
+	if err := copyDescriptor(context.TODO(), source, target, manifestDesc); err != nil {
+		t.Fatalf("unexpected error copying image: %v", err)
+	}
+	for d := range source.blobs {
+		if !bytes.Equal(source.blobs[d], target.blobs[d]) {
+			t.Errorf("blob %s was not copied", d)
+		}
+	}
+
+	// A source blob that does not match its digest must not be copied
+	corrupt := blobDescriptor(ocispec.MediaTypeImageLayer, []byte("expected"))
+	source.blobs[corrupt.Digest] = []byte("tampered")
+	if err := copyDescriptor(context.TODO(), source, target, corrupt); err == nil {
+		t.Errorf("expected digest mismatch error")
+	}
+	if _, found := target.blobs[corrupt.Digest]; found {
+		t.Errorf("corrupt blob was copied")
+	}
+}
@@ -16,6 +16,11 @@ limitations under the License.
 
 package fi
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Authenticator generates authentication credentials for requests.
 type Authenticator interface {
 	CreateToken(body []byte) (string, error)
@@ -34,3 +39,18 @@ type VerifyResult struct {
 type Verifier interface {
 	VerifyToken(token string, body []byte) (*VerifyResult, error)
 }
+
+// MultiVerifier verifies requests with the verifier for the type of their authentication credentials,
+// which is identified by the prefix of the token.
+type MultiVerifier map[string]Verifier
+
+var _ Verifier = MultiVerifier{}
+
+func (m MultiVerifier) VerifyToken(token string, body []byte) (*VerifyResult, error) {
+	for prefix, verifier := range m {
+		if strings.HasPrefix(token, prefix) {
+			return verifier.VerifyToken(token, body)
+		}
+	}
+	return nil, fmt.Errorf("incorrect authorization type")
+}
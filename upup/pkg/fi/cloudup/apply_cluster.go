@@ -25,11 +25,13 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/blang/semver/v4"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	kopsbase "k8s.io/kops"
 	"k8s.io/kops/pkg/acls"
@@ -53,18 +55,22 @@ import (
 	"k8s.io/kops/pkg/model/domodel"
 	"k8s.io/kops/pkg/model/gcemodel"
 	"k8s.io/kops/pkg/model/iam"
+	"k8s.io/kops/pkg/model/metalmodel"
 	"k8s.io/kops/pkg/model/openstackmodel"
 	"k8s.io/kops/pkg/templates"
 	"k8s.io/kops/pkg/wellknownports"
 	"k8s.io/kops/upup/models"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/aliup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
 	"k8s.io/kops/upup/pkg/fi/cloudup/bootstrapchannelbuilder"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/crossplane"
 	"k8s.io/kops/upup/pkg/fi/cloudup/do"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+	"k8s.io/kops/upup/pkg/fi/cloudup/metal"
 	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
@@ -98,6 +104,9 @@ type ApplyClusterCmd struct {
 	// NodeUpAssets are the assets for downloading nodeup
 	NodeUpAssets map[architectures.Architecture]*mirrors.MirroredAsset
 
+	// NodeUpWindowsAsset is the asset for downloading nodeup on Windows instances
+	NodeUpWindowsAsset *mirrors.MirroredAsset
+
 	// TargetName specifies how we are operating e.g. direct to GCE, or AWS, or dry-run, or terraform
 	TargetName string
 
@@ -113,6 +122,9 @@ type ApplyClusterCmd struct {
 	//  url with hash: <hex>@http://... or <hex>@https://...
 	Assets map[architectures.Architecture][]*mirrors.MirroredAsset
 
+	// WindowsAssets is the list of sources for files on Windows instances
+	WindowsAssets []*mirrors.MirroredAsset
+
 	Clientset simple.Clientset
 
 	// DryRun is true if this is only a dry run
@@ -121,6 +133,14 @@ type ApplyClusterCmd struct {
 	// AllowKopsDowngrade permits applying with a kops version older than what was last used to apply to the cluster.
 	AllowKopsDowngrade bool
 
+	// CloudformationChangeSet creates a change set for the cluster stack when rendering to cloudformation
+	CloudformationChangeSet bool
+
+	// TaskCache skips finding the cloud resources of the tasks that are unchanged since the last update with it set,
+	// both when previewing and applying changes. It is opt-in because a cached task does not repair changes made to
+	// its cloud resources outside of kOps, which every update has always done.
+	TaskCache bool
+
 	// RunTasksOptions defines parameters for task execution, e.g. retry interval
 	RunTasksOptions *fi.RunTasksOptions
 
@@ -138,6 +158,18 @@ type ApplyClusterCmd struct {
 	// GetAssets is whether this is called just to obtain the list of assets.
 	GetAssets bool
 
+	// BundleDirectory is an extracted bundle, from which the hashes of the assets are read when GetAssets is set.
+	BundleDirectory string
+
+	// DryRunOutput is where the dry-run target prints its report; defaults to stdout.
+	DryRunOutput io.Writer
+
+	// BuildTasksOnly stops once the TaskMap has been built, without finding or changing any cloud resources.
+	BuildTasksOnly bool
+
+	// SSHPublicKeys overrides the keys in the SSH credential store, for clusters that have not been written to the state store yet.
+	SSHPublicKeys [][]byte
+
 	// TaskMap is the map of tasks that we built (output)
 	TaskMap map[string]fi.Task
 
@@ -145,6 +177,8 @@ type ApplyClusterCmd struct {
 	ImageAssets []*assets.ImageAsset
 	// FileAssets are the file assets we use (output).
 	FileAssets []*assets.FileAsset
+	// TaskReport holds the durations and retries of the tasks that were run (output).
+	TaskReport *fi.RunTasksReport
 }
 
 func (c *ApplyClusterCmd) Run(ctx context.Context) error {
@@ -214,6 +248,7 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 	}
 
 	assetBuilder := assets.NewAssetBuilder(c.Cluster, c.GetAssets)
+	assetBuilder.BundleDirectory = c.BundleDirectory
 	err = c.upgradeSpecs(assetBuilder)
 	if err != nil {
 		return err
@@ -316,7 +351,8 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 			warn = true
 		}
 
-		if warn {
+		// BuildTasksOnly output is machine-readable, so we avoid printing to stdout
+		if warn && !c.BuildTasksOnly {
 			fmt.Println("")
 			fmt.Printf("%s\n", starline)
 			fmt.Println("")
@@ -365,8 +401,8 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 
 	project := ""
 
-	var sshPublicKeys [][]byte
-	{
+	sshPublicKeys := c.SSHPublicKeys
+	if sshPublicKeys == nil {
 		keys, err := sshCredentialStore.FindSSHPublicKeys(fi.SecretNameSSHPrimary)
 		if err != nil {
 			return fmt.Errorf("error retrieving SSH public key %q: %v", fi.SecretNameSSHPrimary, err)
@@ -453,6 +489,12 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 				return fmt.Errorf("exactly one 'admin' SSH public key can be specified when running with AzureCloud; please delete a key using `kops delete secret`")
 			}
 		}
+	case kops.CloudProviderMetal:
+		{
+			if !featureflag.Metal.Enabled() {
+				return fmt.Errorf("metal support is currently alpha, and is feature-gated. Please export KOPS_FEATURE_FLAGS=Metal")
+			}
+		}
 	case kops.CloudProviderOpenstack:
 		{
 			if len(sshPublicKeys) == 0 {
@@ -484,13 +526,14 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		cloud:            cloud,
 	}
 
-	configBuilder, err := newNodeUpConfigBuilder(cluster, assetBuilder, c.Assets)
+	configBuilder, err := newNodeUpConfigBuilder(cluster, c.InstanceGroups, assetBuilder, c.Assets, c.WindowsAssets)
 	if err != nil {
 		return err
 	}
 	bootstrapScriptBuilder := &model.BootstrapScriptBuilder{
 		NodeUpConfigBuilder: configBuilder,
 		NodeUpAssets:        c.NodeUpAssets,
+		NodeUpWindowsAsset:  c.NodeUpWindowsAsset,
 	}
 
 	{
@@ -593,6 +636,7 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 			l.Builders = append(l.Builders,
 				&domodel.APILoadBalancerModelBuilder{DOModelContext: doModelContext, Lifecycle: securityLifecycle},
 				&domodel.DropletBuilder{DOModelContext: doModelContext, BootstrapScriptBuilder: bootstrapScriptBuilder, Lifecycle: clusterLifecycle},
+				&domodel.NetworkModelBuilder{DOModelContext: doModelContext, Lifecycle: networkLifecycle},
 			)
 		case kops.CloudProviderGCE:
 			gceModelContext := &gcemodel.GCEModelContext{
@@ -651,6 +695,15 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 				&openstackmodel.ServerGroupModelBuilder{OpenstackModelContext: openstackModelContext, BootstrapScriptBuilder: bootstrapScriptBuilder, Lifecycle: clusterLifecycle},
 			)
 
+		case kops.CloudProviderMetal:
+			metalModelContext := &metalmodel.MetalModelContext{
+				KopsModelContext: modelContext,
+			}
+
+			l.Builders = append(l.Builders,
+				&metalmodel.MachineModelBuilder{MetalModelContext: metalModelContext, BootstrapScriptBuilder: bootstrapScriptBuilder, Lifecycle: clusterLifecycle},
+			)
+
 		default:
 			return fmt.Errorf("unknown cloudprovider %q", cluster.Spec.CloudProvider)
 		}
@@ -660,6 +713,10 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		return fmt.Errorf("error building tasks: %v", err)
 	}
 
+	if c.BuildTasksOnly {
+		return nil
+	}
+
 	var target fi.Target
 	dryRun := false
 	shouldPrecreateDNS := true
@@ -679,14 +736,19 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 			target = aliup.NewALIAPITarget(cloud.(aliup.ALICloud))
 		case kops.CloudProviderAzure:
 			target = azure.NewAzureAPITarget(cloud.(azure.AzureCloud))
+		case kops.CloudProviderMetal:
+			target = metal.NewMetalAPITarget(cloud.(metal.MetalCloud))
 		default:
 			return fmt.Errorf("direct configuration not supported with CloudProvider:%q", cluster.Spec.CloudProvider)
 		}
 
 	case TargetTerraform:
-		checkExisting = false
 		outDir := c.OutDir
 		tf := terraform.NewTerraformTarget(cloud, project, outDir, cluster.Spec.Target)
+		tf.ClusterName = cluster.ObjectMeta.Name
+
+		// Existing resources are only looked up when they are to be adopted with import blocks
+		checkExisting = tf.ImportExisting()
 
 		// We include a few "util" variables in the TF output
 		if err := tf.AddOutputVariable("region", terraformWriter.LiteralFromStringValue(cloud.Region())); err != nil {
@@ -711,13 +773,31 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 	case TargetCloudformation:
 		checkExisting = false
 		outDir := c.OutDir
-		target = cloudformation.NewCloudformationTarget(cloud, project, outDir)
+		cfTarget := cloudformation.NewCloudformationTarget(cloud, project, outDir)
+		if c.CloudformationChangeSet {
+			cfTarget.ChangeSetStackName = cloudformation.StackName(cluster.ObjectMeta.Name)
+		}
+		target = cfTarget
 
 		// Can cause conflicts with cloudformation management
 		shouldPrecreateDNS = false
 
+	case TargetCrossplane:
+		if kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
+			return fmt.Errorf("crossplane output is only supported with CloudProvider:%q", kops.CloudProviderAWS)
+		}
+		checkExisting = false
+		outDir := c.OutDir
+		target = crossplane.NewCrossplaneTarget(cloud, project, outDir)
+
+		// Can cause conflicts with crossplane management
+		shouldPrecreateDNS = false
+
 	case TargetDryRun:
 		var out io.Writer = os.Stdout
+		if c.DryRunOutput != nil {
+			out = c.DryRunOutput
+		}
 		if c.GetAssets {
 			out = io.Discard
 		}
@@ -732,7 +812,8 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 	}
 	c.Target = target
 
-	if checkExisting {
+	// Terraform tracks deletions itself, even when existing resources are looked up
+	if checkExisting && c.TargetName != TargetTerraform {
 		c.TaskMap, err = l.FindDeletions(cloud, c.LifecycleOverrides)
 		if err != nil {
 			return fmt.Errorf("error finding deletions: %w", err)
@@ -783,7 +864,38 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		options.InitDefaults()
 	}
 
-	err = context.RunTasks(options)
+	switch kops.CloudProviderID(cluster.Spec.CloudProvider) {
+	case kops.CloudProviderAWS:
+		if options.TaskAPI == nil {
+			options.TaskAPI = awstasks.TaskAPI
+		}
+		if options.IsThrottlingError == nil {
+			options.IsThrottlingError = awsup.IsThrottlingError
+		}
+	case kops.CloudProviderGCE:
+		if options.IsThrottlingError == nil {
+			options.IsThrottlingError = gce.IsThrottlingError
+		}
+	}
+
+	// The cache is only written when changes are applied, so that a preview never writes to the state store
+	var taskCache *fi.TaskCache
+	if options.Cache == nil && c.TaskCache && (c.TargetName == TargetDirect || (dryRun && !c.GetAssets)) {
+		taskCache = fi.LoadTaskCache(configBase.Join(registry.PathTaskCache), fi.DefaultTaskCacheMaxAge)
+		if dryRun {
+			taskCache.ReadOnly()
+		}
+		options.Cache = taskCache
+	}
+
+	err = context.RunTasks(ctx, options)
+	c.TaskReport = context.Report()
+	if taskCache != nil && !dryRun {
+		// Save even if some tasks failed, so that the tasks that were applied are skipped next time
+		if err := taskCache.Save(); err != nil {
+			klog.Warningf("unable to save task cache: %v", err)
+		}
+	}
 	if err != nil {
 		return fmt.Errorf("error running tasks: %v", err)
 	}
@@ -1087,6 +1199,57 @@ func (c *ApplyClusterCmd) addFileAssets(assetBuilder *assets.AssetBuilder) error
 		c.NodeUpAssets[arch] = asset
 	}
 
+	c.WindowsAssets = nil
+	c.NodeUpWindowsAsset = nil
+	for _, ig := range c.InstanceGroups {
+		if ig.IsWindows() {
+			return c.addWindowsFileAssets(assetBuilder, baseURL)
+		}
+	}
+
+	return nil
+}
+
+// addWindowsFileAssets adds the assets installed by nodeup on Windows instances, which only exist for AMD64
+func (c *ApplyClusterCmd) addWindowsFileAssets(assetBuilder *assets.AssetBuilder, baseURL string) error {
+	for _, an := range []string{"/bin/windows/amd64/kubelet.exe", "/bin/windows/amd64/kube-proxy.exe"} {
+		k, err := url.Parse(baseURL)
+		if err != nil {
+			return err
+		}
+		k.Path = path.Join(k.Path, an)
+
+		u, hash, err := assetBuilder.RemapFileAndSHA(k)
+		if err != nil {
+			return err
+		}
+		c.WindowsAssets = append(c.WindowsAssets, mirrors.BuildMirroredAsset(u, hash))
+	}
+
+	packages := []*kops.PackagesConfig{}
+	if c.Cluster.Spec.Containerd != nil {
+		packages = append(packages, c.Cluster.Spec.Containerd.Packages)
+	}
+	if c.Cluster.Spec.Networking != nil && c.Cluster.Spec.Networking.Calico != nil {
+		packages = append(packages, c.Cluster.Spec.Networking.Calico.WindowsPackages)
+	}
+	for _, p := range packages {
+		if p == nil || p.UrlWindows == nil || p.HashWindows == nil {
+			return fmt.Errorf("the URL and hash of the Windows packages must be set when using Windows instance groups")
+		}
+		u, hash, err := findAssetsUrlHash(assetBuilder, *p.UrlWindows, *p.HashWindows)
+		if err != nil {
+			return err
+		}
+		c.WindowsAssets = append(c.WindowsAssets, mirrors.BuildMirroredAsset(u, hash))
+	}
+
+	asset, err := NodeUpWindowsAsset(assetBuilder)
+	if err != nil {
+		return err
+	}
+	c.NodeUpWindowsAsset = asset
+
 	return nil
 }
 
@@ -1125,29 +1288,44 @@ type nodeUpConfigBuilder struct {
 	//  raw url: http://... or https://...
 	//  url with hash: <hex>@http://... or <hex>@https://...
 	assets map[architectures.Architecture][]*mirrors.MirroredAsset
+	// windowsAssets is the list of sources for files on Windows instances
+	windowsAssets []*mirrors.MirroredAsset
 
 	assetBuilder   *assets.AssetBuilder
 	channels       []string
 	configBase     vfs.Path
 	cluster        *kops.Cluster
 	etcdManifests  map[kops.InstanceGroupRole][]string
+	gossipSeeds    []string
 	images         map[kops.InstanceGroupRole]map[architectures.Architecture][]*nodeup.Image
 	protokubeAsset map[architectures.Architecture][]*mirrors.MirroredAsset
 	channelsAsset  map[architectures.Architecture][]*mirrors.MirroredAsset
 }
 
-func newNodeUpConfigBuilder(cluster *kops.Cluster, assetBuilder *assets.AssetBuilder, assets map[architectures.Architecture][]*mirrors.MirroredAsset) (model.NodeUpConfigBuilder, error) {
+func newNodeUpConfigBuilder(cluster *kops.Cluster, instanceGroups []*kops.InstanceGroup, assetBuilder *assets.AssetBuilder, assets map[architectures.Architecture][]*mirrors.MirroredAsset, windowsAssets []*mirrors.MirroredAsset) (model.NodeUpConfigBuilder, error) {
 	configBase, err := vfs.Context.BuildVfsPath(cluster.Spec.ConfigBase)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing config base %q: %v", cluster.Spec.ConfigBase, err)
 	}
 
-	channels := []string{
-		configBase.Join("addons", "bootstrap-channel.yaml").Path(),
+	// Channels are applied together, with later channels taking precedence over earlier ones,
+	// so we order them by priority; the bootstrap channel has priority 0.
+	addons := []kops.AddonSpec{
+		{Manifest: configBase.Join("addons", "bootstrap-channel.yaml").Path()},
 	}
+	for _, addon := range cluster.Spec.Addons {
+		// Addons selected by name override the addons of the channels, and are passed to channels by nodeup
+		if addon.Manifest != "" {
+			addons = append(addons, addon)
+		}
+	}
+	sort.SliceStable(addons, func(i, j int) bool {
+		return addons[i].Priority < addons[j].Priority
+	})
 
-	for i := range cluster.Spec.Addons {
-		channels = append(channels, cluster.Spec.Addons[i].Manifest)
+	var channels []string
+	for i := range addons {
+		channels = append(channels, addons[i].Manifest)
 	}
 
 	etcdManifests := map[kops.InstanceGroupRole][]string{}
@@ -1268,13 +1446,27 @@ func newNodeUpConfigBuilder(cluster *kops.Cluster, assetBuilder *assets.AssetBui
 		}
 	}
 
+	var gossipSeeds []string
+	if kops.CloudProviderID(cluster.Spec.CloudProvider) == kops.CloudProviderMetal {
+		for _, ig := range instanceGroups {
+			if !ig.IsMaster() {
+				continue
+			}
+			for _, machine := range ig.Spec.Machines {
+				gossipSeeds = append(gossipSeeds, machine.Address)
+			}
+		}
+	}
+
 	configBuilder := nodeUpConfigBuilder{
 		assetBuilder:   assetBuilder,
 		assets:         assets,
+		windowsAssets:  windowsAssets,
 		channels:       channels,
 		configBase:     configBase,
 		cluster:        cluster,
 		etcdManifests:  etcdManifests,
+		gossipSeeds:    gossipSeeds,
 		images:         images,
 		protokubeAsset: protokubeAsset,
 		channelsAsset:  channelsAsset,
@@ -1301,10 +1493,17 @@ func (n *nodeUpConfigBuilder) BuildConfig(ig *kops.InstanceGroup, apiserverAddit
 
 	config := nodeup.NewConfig(cluster, ig)
 	config.Assets = make(map[architectures.Architecture][]string)
-	for _, arch := range architectures.GetSupported() {
-		config.Assets[arch] = []string{}
-		for _, a := range n.assets[arch] {
-			config.Assets[arch] = append(config.Assets[arch], a.CompactString())
+	if ig.IsWindows() {
+		config.Assets[architectures.ArchitectureAmd64] = []string{}
+		for _, a := range n.windowsAssets {
+			config.Assets[architectures.ArchitectureAmd64] = append(config.Assets[architectures.ArchitectureAmd64], a.CompactString())
+		}
+	} else {
+		for _, arch := range architectures.GetSupported() {
+			config.Assets[arch] = []string{}
+			for _, a := range n.assets[arch] {
+				config.Assets[arch] = append(config.Assets[arch], a.CompactString())
+			}
 		}
 	}
 	config.ClusterName = cluster.ObjectMeta.Name
@@ -1371,9 +1570,27 @@ func (n *nodeUpConfigBuilder) BuildConfig(ig *kops.InstanceGroup, apiserverAddit
 		})
 	}
 
+	if config.ImagePreload != nil && fi.BoolValue(config.ImagePreload.AddonImages) {
+		images := sets.NewString(config.ImagePreload.Images...)
+		for _, image := range n.assetBuilder.AddonImages {
+			match := len(image.Roles) == 0
+			for _, r := range image.Roles {
+				if r == role {
+					match = true
+				}
+			}
+
+			if match && !images.Has(image.Image) {
+				images.Insert(image.Image)
+				config.ImagePreload.Images = append(config.ImagePreload.Images, image.Image)
+			}
+		}
+	}
+
 	config.Images = n.images[role]
 	config.Channels = n.channels
 	config.EtcdManifests = n.etcdManifests[role]
+	config.GossipSeeds = n.gossipSeeds
 
 	return config, nil
 }
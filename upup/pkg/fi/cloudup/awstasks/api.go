@@ -0,0 +1,54 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import "k8s.io/kops/upup/pkg/fi"
+
+// The AWS APIs that tasks call, for fi.RunTasksOptions.MaxConcurrencyPerAPI
+const (
+	APIAutoscaling = "autoscaling"
+	APIEC2         = "ec2"
+	APIELB         = "elb"
+	APIEventBridge = "eventbridge"
+	APIIAM         = "iam"
+	APIRoute53     = "route53"
+	APISQS         = "sqs"
+)
+
+// TaskAPI returns the AWS API that the task calls, or "" if it is not an AWS task.
+func TaskAPI(task fi.Task) string {
+	switch task.(type) {
+	case *AutoscalingGroup, *AutoscalingLifecycleHook, *AutoscalingSchedule, *WarmPool:
+		return APIAutoscaling
+	case *ClassicLoadBalancer, *NetworkLoadBalancer, *TargetGroup:
+		return APIELB
+	case *DNSName, *DNSZone:
+		return APIRoute53
+	case *EventBridgeRule, *EventBridgeTarget:
+		return APIEventBridge
+	case *IAMInstanceProfile, *IAMInstanceProfileRole, *IAMOIDCProvider, *IAMRole, *IAMRolePolicy:
+		return APIIAM
+	case *SQS:
+		return APISQS
+	case *DHCPOptions, *EBSVolume, *ElasticIP, *Instance, *InternetGateway, *LaunchTemplate, *NatGateway,
+		*Route, *RouteTable, *RouteTableAssociation, *SecurityGroup, *SecurityGroupRule, *SSHKey, *Subnet,
+		*VPC, *VPCAmazonIPv6CIDRBlock, *VPCCIDRBlock, *VPCDHCPOptionsAssociation, *VPCEndpoint, *VPCEndpointService:
+		return APIEC2
+	default:
+		return ""
+	}
+}
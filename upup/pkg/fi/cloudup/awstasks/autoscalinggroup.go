@@ -28,6 +28,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/crossplane"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 	"k8s.io/kops/util/pkg/maps"
@@ -77,6 +78,9 @@ type AutoscalingGroup struct {
 	MixedSpotInstancePools *int64
 	// MixedSpotMaxPrice is the maximum price per unit hour you are willing to pay for a Spot Instance
 	MixedSpotMaxPrice *string
+	// ScheduledScaling indicates that scheduled actions change the size of the asg, so MinSize and MaxSize
+	// are only applied when the asg is created
+	ScheduledScaling *bool
 	// Subnets is a collection of subnets to attach the nodes to
 	Subnets []*Subnet
 	// SuspendProcesses
@@ -244,6 +248,13 @@ func (e *AutoscalingGroup) Find(c *fi.Context) (*AutoscalingGroup, error) {
 
 	// Avoid spurious changes
 	actual.Lifecycle = e.Lifecycle
+	actual.ScheduledScaling = e.ScheduledScaling
+
+	// Don't undo the size changes made by the scheduled actions
+	if fi.BoolValue(e.ScheduledScaling) {
+		actual.MinSize = e.MinSize
+		actual.MaxSize = e.MaxSize
+	}
 
 	if g.NewInstancesProtectedFromScaleIn != nil {
 		actual.InstanceProtection = g.NewInstancesProtectedFromScaleIn
@@ -985,6 +996,11 @@ func (_ *AutoscalingGroup) RenderTerraform(t *terraform.TerraformTarget, a, e, c
 	}
 	tf.SuspendedProcesses = processes
 
+	// Adopt the resource if it already exists
+	if a != nil && t.ImportExisting() {
+		t.AddImport("aws_autoscaling_group", *e.Name, fi.StringValue(a.Name))
+	}
+
 	return t.RenderResource("aws_autoscaling_group", *e.Name, tf)
 }
 
@@ -1131,3 +1147,133 @@ func (_ *AutoscalingGroup) RenderCloudformation(t *cloudformation.Cloudformation
 func (e *AutoscalingGroup) CloudformationLink() *cloudformation.Literal {
 	return cloudformation.Ref("AWS::AutoScaling::AutoScalingGroup", fi.StringValue(e.Name))
 }
+
+type crossplaneASGTag struct {
+	Key               *string `json:"key"`
+	Value             *string `json:"value"`
+	PropagateAtLaunch *bool   `json:"propagateAtLaunch"`
+}
+
+type crossplaneAutoscalingLaunchTemplateSpecification struct {
+	// LaunchTemplateID is the ID of the template to use.
+	LaunchTemplateID *string `json:"id,omitempty"`
+	// LaunchTemplateIDRef refers to the launch template managed by crossplane.
+	LaunchTemplateIDRef *crossplane.Reference `json:"idRef,omitempty"`
+	// Version is the version of the Launch Template to use.
+	Version *string `json:"version,omitempty"`
+}
+
+type crossplaneAutoscalingMixedInstancesPolicyLaunchTemplate struct {
+	LaunchTemplateSpecification []*crossplaneAutoscalingLaunchTemplateSpecification                `json:"launchTemplateSpecification,omitempty"`
+	Override                    []*crossplaneAutoscalingMixedInstancesPolicyLaunchTemplateOverride `json:"override,omitempty"`
+}
+
+type crossplaneAutoscalingMixedInstancesPolicyLaunchTemplateOverride struct {
+	InstanceType *string `json:"instanceType,omitempty"`
+}
+
+type crossplaneAutoscalingInstanceDistribution struct {
+	OnDemandAllocationStrategy          *string `json:"onDemandAllocationStrategy,omitempty"`
+	OnDemandBaseCapacity                *int64  `json:"onDemandBaseCapacity,omitempty"`
+	OnDemandPercentageAboveBaseCapacity *int64  `json:"onDemandPercentageAboveBaseCapacity,omitempty"`
+	SpotAllocationStrategy              *string `json:"spotAllocationStrategy,omitempty"`
+	SpotInstancePools                   *int64  `json:"spotInstancePools,omitempty"`
+	SpotMaxPrice                        *string `json:"spotMaxPrice,omitempty"`
+}
+
+type crossplaneMixedInstancesPolicy struct {
+	LaunchTemplate       []*crossplaneAutoscalingMixedInstancesPolicyLaunchTemplate `json:"launchTemplate,omitempty"`
+	InstanceDistribution []*crossplaneAutoscalingInstanceDistribution               `json:"instancesDistribution,omitempty"`
+}
+
+type crossplaneAutoscalingGroup struct {
+	Region                *string                                             `json:"region"`
+	LaunchTemplate        []*crossplaneAutoscalingLaunchTemplateSpecification `json:"launchTemplate,omitempty"`
+	MaxSize               *int64                                              `json:"maxSize,omitempty"`
+	MinSize               *int64                                              `json:"minSize,omitempty"`
+	MixedInstancesPolicy  []*crossplaneMixedInstancesPolicy                   `json:"mixedInstancesPolicy,omitempty"`
+	VPCZoneIdentifier     []string                                            `json:"vpcZoneIdentifier,omitempty"`
+	VPCZoneIdentifierRefs []*crossplane.Reference                             `json:"vpcZoneIdentifierRefs,omitempty"`
+	Tags                  []*crossplaneASGTag                                 `json:"tag,omitempty"`
+	MetricsGranularity    *string                                             `json:"metricsGranularity,omitempty"`
+	EnabledMetrics        []*string                                           `json:"enabledMetrics,omitempty"`
+	SuspendedProcesses    []*string                                           `json:"suspendedProcesses,omitempty"`
+	InstanceProtection    *bool                                               `json:"protectFromScaleIn,omitempty"`
+}
+
+func (_ *AutoscalingGroup) RenderCrossplane(t *crossplane.CrossplaneTarget, a, e, changes *AutoscalingGroup) error {
+	if len(e.LoadBalancers) != 0 || len(e.TargetGroups) != 0 {
+		return fmt.Errorf("load balancers are not supported by the crossplane target")
+	}
+
+	xp := &crossplaneAutoscalingGroup{
+		Region:             fi.String(t.Cloud.Region()),
+		MinSize:            e.MinSize,
+		MaxSize:            e.MaxSize,
+		MetricsGranularity: e.Granularity,
+		EnabledMetrics:     aws.StringSlice(e.Metrics),
+		InstanceProtection: e.InstanceProtection,
+	}
+
+	for _, s := range e.Subnets {
+		subnet := s.CrossplaneLink()
+		if subnet.Ref != nil {
+			xp.VPCZoneIdentifierRefs = append(xp.VPCZoneIdentifierRefs, subnet.Ref)
+		} else {
+			xp.VPCZoneIdentifier = append(xp.VPCZoneIdentifier, fi.StringValue(subnet.Value))
+		}
+	}
+
+	for _, k := range maps.SortedKeys(e.Tags) {
+		v := e.Tags[k]
+		xp.Tags = append(xp.Tags, &crossplaneASGTag{
+			Key:               fi.String(k),
+			Value:             fi.String(v),
+			PropagateAtLaunch: fi.Bool(true),
+		})
+	}
+
+	if e.LaunchTemplate == nil {
+		return fmt.Errorf("could not find launch template")
+	}
+	launchTemplate := &crossplaneAutoscalingLaunchTemplateSpecification{
+		LaunchTemplateIDRef: e.LaunchTemplate.CrossplaneLink().Ref,
+		Version:             fi.String("$Latest"),
+	}
+
+	if e.UseMixedInstancesPolicy() {
+		xp.MixedInstancesPolicy = []*crossplaneMixedInstancesPolicy{
+			{
+				LaunchTemplate: []*crossplaneAutoscalingMixedInstancesPolicyLaunchTemplate{
+					{
+						LaunchTemplateSpecification: []*crossplaneAutoscalingLaunchTemplateSpecification{launchTemplate},
+					},
+				},
+				InstanceDistribution: []*crossplaneAutoscalingInstanceDistribution{
+					{
+						OnDemandAllocationStrategy:          e.MixedOnDemandAllocationStrategy,
+						OnDemandBaseCapacity:                e.MixedOnDemandBase,
+						OnDemandPercentageAboveBaseCapacity: e.MixedOnDemandAboveBase,
+						SpotAllocationStrategy:              e.MixedSpotAllocationStrategy,
+						SpotInstancePools:                   e.MixedSpotInstancePools,
+						SpotMaxPrice:                        e.MixedSpotMaxPrice,
+					},
+				},
+			},
+		}
+
+		for _, x := range e.MixedInstanceOverrides {
+			xp.MixedInstancesPolicy[0].LaunchTemplate[0].Override = append(xp.MixedInstancesPolicy[0].LaunchTemplate[0].Override, &crossplaneAutoscalingMixedInstancesPolicyLaunchTemplateOverride{InstanceType: fi.String(x)})
+		}
+	} else {
+		xp.LaunchTemplate = []*crossplaneAutoscalingLaunchTemplateSpecification{launchTemplate}
+	}
+
+	if e.SuspendProcesses != nil {
+		for _, p := range *e.SuspendProcesses {
+			xp.SuspendedProcesses = append(xp.SuspendedProcesses, fi.String(p))
+		}
+	}
+
+	return t.RenderNamedResource("autoscaling.aws.upbound.io/v1beta1", "AutoscalingGroup", *e.Name, *e.Name, xp)
+}
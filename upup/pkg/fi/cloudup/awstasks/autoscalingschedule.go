@@ -0,0 +1,244 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
+)
+
+// ScheduledActionPrefix is the prefix of the names of the scheduled actions managed by kOps.
+// Scheduled actions without the prefix are left alone.
+const ScheduledActionPrefix = "kops-"
+
+// AutoscalingSchedule provides the definition for the scheduled actions of an ASG in aws.
+// +kops:fitask
+type AutoscalingSchedule struct {
+	// Name is the name of the ASG.
+	Name *string
+	// Lifecycle is the resource lifecycle.
+	Lifecycle fi.Lifecycle
+
+	AutoscalingGroup *AutoscalingGroup
+	// Actions are the scheduled actions of the ASG, sorted by name.
+	Actions []*ScheduledAction
+}
+
+// ScheduledAction is a recurring change to the size of an ASG.
+type ScheduledAction struct {
+	// Name is the name of the scheduled action, starting with ScheduledActionPrefix.
+	Name *string
+	// Recurrence is the cron expression of the scheduled action.
+	Recurrence *string
+	// TimeZone is the time zone of the cron expression; UTC is used if not set.
+	TimeZone *string
+	// MinSize is the smallest number of nodes in the ASG after the scheduled action runs.
+	MinSize *int64
+	// MaxSize is the max number of nodes in the ASG after the scheduled action runs.
+	MaxSize *int64
+}
+
+var _ fi.HasDependencies = &ScheduledAction{}
+
+// GetDependencies returns the dependencies of the scheduled action; it has none.
+func (*ScheduledAction) GetDependencies(tasks map[string]fi.Task) []fi.Task {
+	return nil
+}
+
+// Find is used to discover the scheduled actions of the ASG in the cloud provider.
+func (e *AutoscalingSchedule) Find(c *fi.Context) (*AutoscalingSchedule, error) {
+	cloud := c.Cloud.(awsup.AWSCloud)
+
+	actual := &AutoscalingSchedule{
+		Name:             e.Name,
+		Lifecycle:        e.Lifecycle,
+		AutoscalingGroup: e.AutoscalingGroup,
+	}
+
+	request := &autoscaling.DescribeScheduledActionsInput{
+		AutoScalingGroupName: e.AutoscalingGroup.Name,
+	}
+	err := cloud.Autoscaling().DescribeScheduledActionsPages(request, func(page *autoscaling.DescribeScheduledActionsOutput, lastPage bool) bool {
+		for _, action := range page.ScheduledUpdateGroupActions {
+			if !strings.HasPrefix(aws.StringValue(action.ScheduledActionName), ScheduledActionPrefix) {
+				continue
+			}
+			actual.Actions = append(actual.Actions, &ScheduledAction{
+				Name:       action.ScheduledActionName,
+				Recurrence: action.Recurrence,
+				TimeZone:   action.TimeZone,
+				MinSize:    action.MinSize,
+				MaxSize:    action.MaxSize,
+			})
+		}
+		return true
+	})
+	if err != nil {
+		// The ASG doesn't exist yet
+		if awsup.AWSErrorCode(err) == "ValidationError" {
+			return actual, nil
+		}
+		return nil, fmt.Errorf("error listing scheduled actions of ASG %q: %w", fi.StringValue(e.AutoscalingGroup.Name), err)
+	}
+	sortScheduledActions(actual.Actions)
+
+	return actual, nil
+}
+
+func (e *AutoscalingSchedule) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(e, c)
+}
+
+func (*AutoscalingSchedule) CheckChanges(a, e, changes *AutoscalingSchedule) error {
+	for _, action := range e.Actions {
+		if !strings.HasPrefix(fi.StringValue(action.Name), ScheduledActionPrefix) {
+			return fmt.Errorf("scheduled action name %q must start with %q", fi.StringValue(action.Name), ScheduledActionPrefix)
+		}
+	}
+	return nil
+}
+
+func (*AutoscalingSchedule) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *AutoscalingSchedule) error {
+	if changes == nil {
+		return nil
+	}
+
+	svc := t.Cloud.Autoscaling()
+
+	existing := make(map[string]*ScheduledAction)
+	if a != nil {
+		for _, action := range a.Actions {
+			existing[fi.StringValue(action.Name)] = action
+		}
+	}
+
+	for _, action := range e.Actions {
+		name := fi.StringValue(action.Name)
+		if reflect.DeepEqual(existing[name], action) {
+			delete(existing, name)
+			continue
+		}
+		delete(existing, name)
+
+		klog.V(2).Infof("Updating scheduled action %q of ASG %q", name, fi.StringValue(e.AutoscalingGroup.Name))
+		request := &autoscaling.PutScheduledUpdateGroupActionInput{
+			AutoScalingGroupName: e.AutoscalingGroup.Name,
+			ScheduledActionName:  action.Name,
+			Recurrence:           action.Recurrence,
+			TimeZone:             action.TimeZone,
+			MinSize:              action.MinSize,
+			MaxSize:              action.MaxSize,
+		}
+		if _, err := svc.PutScheduledUpdateGroupAction(request); err != nil {
+			if awsup.AWSErrorCode(err) == "ValidationError" {
+				return fi.NewTryAgainLaterError("waiting for ASG to become ready")
+			}
+			return fmt.Errorf("error updating scheduled action %q: %w", name, err)
+		}
+	}
+
+	for name := range existing {
+		klog.V(2).Infof("Deleting scheduled action %q of ASG %q", name, fi.StringValue(e.AutoscalingGroup.Name))
+		request := &autoscaling.DeleteScheduledActionInput{
+			AutoScalingGroupName: e.AutoscalingGroup.Name,
+			ScheduledActionName:  aws.String(name),
+		}
+		if _, err := svc.DeleteScheduledAction(request); err != nil {
+			return fmt.Errorf("error deleting scheduled action %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+type terraformAutoscalingSchedule struct {
+	ScheduledActionName  *string                  `json:"scheduled_action_name" cty:"scheduled_action_name"`
+	AutoScalingGroupName *terraformWriter.Literal `json:"autoscaling_group_name" cty:"autoscaling_group_name"`
+	Recurrence           *string                  `json:"recurrence" cty:"recurrence"`
+	TimeZone             *string                  `json:"time_zone,omitempty" cty:"time_zone"`
+	// The size fields default to 0 in terraform, -1 leaves them unchanged
+	MinSize         *int64 `json:"min_size" cty:"min_size"`
+	MaxSize         *int64 `json:"max_size" cty:"max_size"`
+	DesiredCapacity *int64 `json:"desired_capacity" cty:"desired_capacity"`
+}
+
+func (_ *AutoscalingSchedule) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *AutoscalingSchedule) error {
+	for _, action := range e.Actions {
+		tf := &terraformAutoscalingSchedule{
+			ScheduledActionName:  action.Name,
+			AutoScalingGroupName: e.AutoscalingGroup.TerraformLink(),
+			Recurrence:           action.Recurrence,
+			TimeZone:             action.TimeZone,
+			MinSize:              fi.Int64(-1),
+			MaxSize:              fi.Int64(-1),
+			DesiredCapacity:      fi.Int64(-1),
+		}
+		if action.MinSize != nil {
+			tf.MinSize = action.MinSize
+		}
+		if action.MaxSize != nil {
+			tf.MaxSize = action.MaxSize
+		}
+
+		if err := t.RenderResource("aws_autoscaling_schedule", fi.StringValue(e.Name)+"-"+fi.StringValue(action.Name), tf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type cloudformationAutoscalingSchedule struct {
+	AutoScalingGroupName *cloudformation.Literal `json:"AutoScalingGroupName"`
+	Recurrence           *string                 `json:"Recurrence"`
+	TimeZone             *string                 `json:"TimeZone,omitempty"`
+	MinSize              *int64                  `json:"MinSize,omitempty"`
+	MaxSize              *int64                  `json:"MaxSize,omitempty"`
+}
+
+func (_ *AutoscalingSchedule) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *AutoscalingSchedule) error {
+	for _, action := range e.Actions {
+		cf := &cloudformationAutoscalingSchedule{
+			AutoScalingGroupName: e.AutoscalingGroup.CloudformationLink(),
+			Recurrence:           action.Recurrence,
+			TimeZone:             action.TimeZone,
+			MinSize:              action.MinSize,
+			MaxSize:              action.MaxSize,
+		}
+
+		if err := t.RenderResource("AWS::AutoScaling::ScheduledAction", fi.StringValue(e.Name)+"-"+fi.StringValue(action.Name), cf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sortScheduledActions(actions []*ScheduledAction) {
+	sort.Slice(actions, func(i, j int) bool {
+		return fi.StringValue(actions[i].Name) < fi.StringValue(actions[j].Name)
+	})
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"k8s.io/kops/cloudmock/aws/mockautoscaling"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+func TestAutoscalingScheduleSync(t *testing.T) {
+	ctx := context.TODO()
+
+	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
+	c := &mockautoscaling.MockAutoscaling{}
+	cloud.MockAutoscaling = c
+
+	// Pre-create the ASG, with a scheduled action that isn't managed by kOps
+	_, err := c.CreateAutoScalingGroup(&autoscaling.CreateAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String("nodes.example.com"),
+		MinSize:              aws.Int64(2),
+		MaxSize:              aws.Int64(4),
+	})
+	if err != nil {
+		t.Fatalf("error creating test ASG: %v", err)
+	}
+	_, err = c.PutScheduledUpdateGroupAction(&autoscaling.PutScheduledUpdateGroupActionInput{
+		AutoScalingGroupName: aws.String("nodes.example.com"),
+		ScheduledActionName:  aws.String("manual"),
+		Recurrence:           aws.String("0 12 * * *"),
+		MaxSize:              aws.Int64(10),
+	})
+	if err != nil {
+		t.Fatalf("error creating test scheduled action: %v", err)
+	}
+
+	run := func(actions ...*ScheduledAction) {
+		asg := &AutoscalingGroup{
+			Name:      aws.String("nodes.example.com"),
+			Lifecycle: fi.LifecycleIgnore,
+			Tags:      make(map[string]string),
+		}
+		schedule := &AutoscalingSchedule{
+			Name:             aws.String("nodes.example.com"),
+			Lifecycle:        fi.LifecycleSync,
+			AutoscalingGroup: asg,
+			Actions:          actions,
+		}
+		allTasks := map[string]fi.Task{
+			"asg":      asg,
+			"schedule": schedule,
+		}
+
+		target := &awsup.AWSAPITarget{
+			Cloud: cloud,
+		}
+
+		context, err := fi.NewContext(target, nil, cloud, nil, nil, nil, true, allTasks)
+		if err != nil {
+			t.Fatalf("error building context: %v", err)
+		}
+		defer context.Close()
+
+		if err := context.RunTasks(ctx, testRunTasksOptions); err != nil {
+			t.Fatalf("unexpected error during Run: %v", err)
+		}
+	}
+
+	actionNames := func() []string {
+		var names []string
+		response, err := c.DescribeScheduledActions(&autoscaling.DescribeScheduledActionsInput{
+			AutoScalingGroupName: aws.String("nodes.example.com"),
+		})
+		if err != nil {
+			t.Fatalf("error listing scheduled actions: %v", err)
+		}
+		for _, action := range response.ScheduledUpdateGroupActions {
+			names = append(names, aws.StringValue(action.ScheduledActionName))
+		}
+		return names
+	}
+
+	run(
+		&ScheduledAction{
+			Name:       aws.String("kops-evening"),
+			Recurrence: aws.String("0 19 * * 1-5"),
+			TimeZone:   aws.String("Europe/Berlin"),
+			MinSize:    aws.Int64(0),
+			MaxSize:    aws.Int64(0),
+		},
+		&ScheduledAction{
+			Name:       aws.String("kops-morning"),
+			Recurrence: aws.String("0 7 * * 1-5"),
+			TimeZone:   aws.String("Europe/Berlin"),
+			MinSize:    aws.Int64(2),
+			MaxSize:    aws.Int64(4),
+		},
+	)
+	if names, expected := actionNames(), []string{"kops-evening", "kops-morning", "manual"}; !reflect.DeepEqual(names, expected) {
+		t.Fatalf("unexpected scheduled actions after create, expected %v, got %v", expected, names)
+	}
+	evening := c.ScheduledActions["nodes.example.com"]["kops-evening"]
+	if aws.StringValue(evening.TimeZone) != "Europe/Berlin" || aws.Int64Value(evening.MaxSize) != 0 {
+		t.Errorf("unexpected scheduled action: %v", evening)
+	}
+
+	run(
+		&ScheduledAction{
+			Name:       aws.String("kops-evening"),
+			Recurrence: aws.String("0 20 * * 1-5"),
+			TimeZone:   aws.String("Europe/Berlin"),
+			MinSize:    aws.Int64(0),
+			MaxSize:    aws.Int64(0),
+		},
+	)
+	if names, expected := actionNames(), []string{"kops-evening", "manual"}; !reflect.DeepEqual(names, expected) {
+		t.Fatalf("unexpected scheduled actions after update, expected %v, got %v", expected, names)
+	}
+	if recurrence := aws.StringValue(c.ScheduledActions["nodes.example.com"]["kops-evening"].Recurrence); recurrence != "0 20 * * 1-5" {
+		t.Errorf("unexpected recurrence after update: %q", recurrence)
+	}
+
+	run()
+	if names, expected := actionNames(), []string{"manual"}; !reflect.DeepEqual(names, expected) {
+		t.Fatalf("unexpected scheduled actions after delete, expected %v, got %v", expected, names)
+	}
+}
+
+func TestAutoscalingScheduleTerraformRender(t *testing.T) {
+	cases := []*renderTest{
+		{
+			Resource: &AutoscalingSchedule{
+				Name:             fi.String("nodes"),
+				AutoscalingGroup: &AutoscalingGroup{Name: fi.String("nodes")},
+				Actions: []*ScheduledAction{
+					{
+						Name:       fi.String("kops-evening"),
+						Recurrence: fi.String("0 19 * * 1-5"),
+						TimeZone:   fi.String("Europe/Berlin"),
+						MinSize:    fi.Int64(0),
+					},
+				},
+			},
+			Expected: `provider "aws" {
+  region = "eu-west-2"
+}
+
+resource "aws_autoscaling_schedule" "nodes-kops-evening" {
+  autoscaling_group_name = aws_autoscaling_group.nodes.id
+  desired_capacity       = -1
+  max_size               = -1
+  min_size               = 0
+  recurrence             = "0 19 * * 1-5"
+  scheduled_action_name  = "kops-evening"
+  time_zone              = "Europe/Berlin"
+}
+
+terraform {
+  required_version = ">= 0.12.26"
+  required_providers {
+    aws = {
+      "source"  = "hashicorp/aws"
+      "version" = ">= 3.34.0"
+    }
+  }
+}
+`,
+		},
+	}
+
+	doRenderTests(t, "RenderTerraform", cases)
+}
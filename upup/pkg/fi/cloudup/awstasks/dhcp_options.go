@@ -27,6 +27,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/crossplane"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -216,3 +217,28 @@ func (_ *DHCPOptions) RenderCloudformation(t *cloudformation.CloudformationTarge
 func (e *DHCPOptions) CloudformationLink() *cloudformation.Literal {
 	return cloudformation.Ref("AWS::EC2::DHCPOptions", *e.Name)
 }
+
+type crossplaneDHCPOptions struct {
+	Region            *string           `json:"region"`
+	DomainName        *string           `json:"domainName,omitempty"`
+	DomainNameServers []string          `json:"domainNameServers,omitempty"`
+	Tags              map[string]string `json:"tags,omitempty"`
+}
+
+func (_ *DHCPOptions) RenderCrossplane(t *crossplane.CrossplaneTarget, a, e, changes *DHCPOptions) error {
+	xp := &crossplaneDHCPOptions{
+		Region:     fi.String(t.Cloud.Region()),
+		DomainName: e.DomainName,
+		Tags:       e.Tags,
+	}
+
+	if e.DomainNameServers != nil {
+		xp.DomainNameServers = strings.Split(*e.DomainNameServers, ",")
+	}
+
+	return t.RenderResource("ec2.aws.upbound.io/v1beta1", "VPCDHCPOptions", *e.Name, xp)
+}
+
+func (e *DHCPOptions) CrossplaneLink() *crossplane.Literal {
+	return crossplane.LiteralRef(*e.Name)
+}
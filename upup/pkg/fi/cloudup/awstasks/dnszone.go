@@ -30,6 +30,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/crossplane"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -351,3 +352,27 @@ func (e *DNSZone) CloudformationLink() *cloudformation.Literal {
 
 	return cloudformation.Ref("AWS::Route53::HostedZone", *e.Name)
 }
+
+func (_ *DNSZone) RenderCrossplane(t *crossplane.CrossplaneTarget, a, e, changes *DNSZone) error {
+	cloud := t.Cloud.(awsup.AWSCloud)
+
+	dnsName := fi.StringValue(e.DNSName)
+
+	// As with the other targets, we reuse an existing zone rather than creating a new one
+	klog.Infof("Check for existing route53 zone to re-use with name %q", dnsName)
+	z, err := e.findExisting(cloud)
+	if err != nil {
+		return err
+	}
+
+	if z != nil {
+		klog.Infof("Existing zone %q found; will configure crossplane to reuse", aws.StringValue(z.HostedZone.Name))
+
+		e.ZoneID = z.HostedZone.Id
+
+		// Don't render a task
+		return nil
+	}
+
+	return fmt.Errorf("creation of Route53 hosted zones is not supported by the crossplane target; create the zone %q first", dnsName)
+}
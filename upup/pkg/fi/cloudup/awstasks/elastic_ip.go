@@ -27,6 +27,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/crossplane"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 )
 
@@ -290,6 +291,11 @@ func (_ *ElasticIP) RenderTerraform(t *terraform.TerraformTarget, a, e, changes
 		Tags: e.Tags,
 	}
 
+	// Adopt the resource if it already exists
+	if a != nil && t.ImportExisting() {
+		t.AddImport("aws_eip", *e.Name, fi.StringValue(a.ID))
+	}
+
 	return t.RenderResource("aws_eip", *e.Name, tf)
 }
 
@@ -341,3 +347,37 @@ func (e *ElasticIP) CloudformationAllocationID() *cloudformation.Literal {
 
 	return cloudformation.GetAtt("AWS::EC2::EIP", *e.Name, "AllocationId")
 }
+
+type crossplaneElasticIP struct {
+	Region *string           `json:"region"`
+	VPC    *bool             `json:"vpc,omitempty"`
+	Tags   map[string]string `json:"tags,omitempty"`
+}
+
+func (_ *ElasticIP) RenderCrossplane(t *crossplane.CrossplaneTarget, a, e, changes *ElasticIP) error {
+	if fi.BoolValue(e.Shared) {
+		if e.ID == nil {
+			return fmt.Errorf("ID must be set, if ElasticIP is shared: %v", e)
+		}
+		return nil
+	}
+
+	xp := &crossplaneElasticIP{
+		Region: fi.String(t.Cloud.Region()),
+		VPC:    aws.Bool(true),
+		Tags:   e.Tags,
+	}
+
+	return t.RenderResource("ec2.aws.upbound.io/v1beta1", "EIP", *e.Name, xp)
+}
+
+func (e *ElasticIP) CrossplaneLink() *crossplane.Literal {
+	if fi.BoolValue(e.Shared) {
+		if e.ID == nil {
+			klog.Fatalf("ID must be set, if ElasticIP is shared: %v", e)
+		}
+		return crossplane.LiteralFromStringValue(*e.ID)
+	}
+
+	return crossplane.LiteralRef(*e.Name)
+}
@@ -18,6 +18,7 @@ package awstasks
 
 import (
 	"bytes"
+	"context"
 	"os"
 	"reflect"
 	"testing"
@@ -38,6 +39,8 @@ var testRunTasksOptions = fi.RunTasksOptions{
 }
 
 func TestElasticIPCreate(t *testing.T) {
+	ctx := context.TODO()
+
 	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
 	c := &mockec2.MockEC2{}
 	cloud.MockEC2 = c
@@ -84,7 +87,7 @@ func TestElasticIPCreate(t *testing.T) {
 		}
 		defer context.Close()
 
-		if err := context.RunTasks(testRunTasksOptions); err != nil {
+		if err := context.RunTasks(ctx, testRunTasksOptions); err != nil {
 			t.Fatalf("unexpected error during Run: %v", err)
 		}
 
@@ -120,6 +123,8 @@ func TestElasticIPCreate(t *testing.T) {
 }
 
 func checkNoChanges(t *testing.T, cloud fi.Cloud, allTasks map[string]fi.Task) {
+	ctx := context.TODO()
+
 	cluster := &kops.Cluster{
 		Spec: kops.ClusterSpec{
 			KubernetesVersion: "v1.9.0",
@@ -133,7 +138,7 @@ func checkNoChanges(t *testing.T, cloud fi.Cloud, allTasks map[string]fi.Task) {
 	}
 	defer context.Close()
 
-	if err := context.RunTasks(testRunTasksOptions); err != nil {
+	if err := context.RunTasks(ctx, testRunTasksOptions); err != nil {
 		t.Fatalf("unexpected error during Run: %v", err)
 	}
 
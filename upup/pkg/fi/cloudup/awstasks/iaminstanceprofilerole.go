@@ -26,6 +26,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/crossplane"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -123,6 +124,11 @@ func (_ *IAMInstanceProfileRole) RenderTerraform(t *terraform.TerraformTarget, a
 		Tags: e.InstanceProfile.Tags,
 	}
 
+	// Adopt the resource if it already exists
+	if a != nil && t.ImportExisting() {
+		t.AddImport("aws_iam_instance_profile", *e.InstanceProfile.Name, fi.StringValue(e.InstanceProfile.Name))
+	}
+
 	return t.RenderResource("aws_iam_instance_profile", *e.InstanceProfile.Name, tf)
 }
 
@@ -140,3 +146,21 @@ func (_ *IAMInstanceProfileRole) RenderCloudformation(t *cloudformation.Cloudfor
 
 	return t.RenderResource("AWS::IAM::InstanceProfile", *e.InstanceProfile.Name, cf)
 }
+
+type crossplaneIAMInstanceProfile struct {
+	Role    *string               `json:"role,omitempty"`
+	RoleRef *crossplane.Reference `json:"roleRef,omitempty"`
+	Tags    map[string]string     `json:"tags,omitempty"`
+}
+
+func (_ *IAMInstanceProfileRole) RenderCrossplane(t *crossplane.CrossplaneTarget, a, e, changes *IAMInstanceProfileRole) error {
+	role := e.Role.CrossplaneLink()
+	xp := &crossplaneIAMInstanceProfile{
+		Role:    role.Value,
+		RoleRef: role.Ref,
+		Tags:    e.InstanceProfile.Tags,
+	}
+
+	name := *e.InstanceProfile.Name
+	return t.RenderNamedResource("iam.aws.upbound.io/v1beta1", "InstanceProfile", name, name, xp)
+}
@@ -32,6 +32,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/crossplane"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -354,6 +355,11 @@ func (_ *IAMRole) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *I
 		t.AddOutputVariable(*e.ExportWithID+"_role_name", e.TerraformLink())
 	}
 
+	// Adopt the resource if it already exists
+	if a != nil && t.ImportExisting() {
+		t.AddImport("aws_iam_role", *e.Name, fi.StringValue(a.Name))
+	}
+
 	return t.RenderResource("aws_iam_role", *e.Name, tf)
 }
 
@@ -396,3 +402,28 @@ func (_ *IAMRole) RenderCloudformation(t *cloudformation.CloudformationTarget, a
 func (e *IAMRole) CloudformationLink() *cloudformation.Literal {
 	return cloudformation.Ref("AWS::IAM::Role", *e.Name)
 }
+
+type crossplaneIAMRole struct {
+	AssumeRolePolicy    *string           `json:"assumeRolePolicy"`
+	PermissionsBoundary *string           `json:"permissionsBoundary,omitempty"`
+	Tags                map[string]string `json:"tags,omitempty"`
+}
+
+func (_ *IAMRole) RenderCrossplane(t *crossplane.CrossplaneTarget, a, e, changes *IAMRole) error {
+	policy, err := fi.ResourceAsString(e.RolePolicyDocument)
+	if err != nil {
+		return fmt.Errorf("error rendering RolePolicyDocument: %v", err)
+	}
+
+	xp := &crossplaneIAMRole{
+		AssumeRolePolicy:    fi.String(policy),
+		PermissionsBoundary: e.PermissionsBoundary,
+		Tags:                e.Tags,
+	}
+
+	return t.RenderNamedResource("iam.aws.upbound.io/v1beta1", "Role", *e.Name, *e.Name, xp)
+}
+
+func (e *IAMRole) CrossplaneLink() *crossplane.Literal {
+	return crossplane.LiteralRef(*e.Name)
+}
@@ -31,6 +31,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/crossplane"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -393,3 +394,53 @@ func (_ *IAMRolePolicy) RenderCloudformation(t *cloudformation.CloudformationTar
 func (e *IAMRolePolicy) CloudformationLink() *cloudformation.Literal {
 	return cloudformation.Ref("AWS::IAM::Policy", *e.Name)
 }
+
+type crossplaneIAMRolePolicy struct {
+	Role      *string               `json:"role,omitempty"`
+	RoleRef   *crossplane.Reference `json:"roleRef,omitempty"`
+	Policy    *string               `json:"policy,omitempty"`
+	PolicyArn *string               `json:"policyArn,omitempty"`
+}
+
+func (_ *IAMRolePolicy) RenderCrossplane(t *crossplane.CrossplaneTarget, a, e, changes *IAMRolePolicy) error {
+	role := e.Role.CrossplaneLink()
+
+	if e.ExternalPolicies != nil && len(*e.ExternalPolicies) > 0 {
+		for _, policy := range *e.ExternalPolicies {
+			// create a hash of the arn
+			h := fnv.New32a()
+			h.Write([]byte(policy))
+
+			name := fmt.Sprintf("%s-%d", *e.Name, h.Sum32())
+
+			xp := &crossplaneIAMRolePolicy{
+				Role:      role.Value,
+				RoleRef:   role.Ref,
+				PolicyArn: s(policy),
+			}
+
+			err := t.RenderResource("iam.aws.upbound.io/v1beta1", "RolePolicyAttachment", name, xp)
+			if err != nil {
+				return fmt.Errorf("error rendering RolePolicyAttachment: %v", err)
+			}
+		}
+	}
+
+	policyString, err := e.policyDocumentString()
+	if err != nil {
+		return fmt.Errorf("error rendering PolicyDocument: %v", err)
+	}
+
+	if policyString == "" {
+		// A deletion; we simply don't render
+		return nil
+	}
+
+	xp := &crossplaneIAMRolePolicy{
+		Role:    role.Value,
+		RoleRef: role.Ref,
+		Policy:  fi.String(policyString),
+	}
+
+	return t.RenderNamedResource("iam.aws.upbound.io/v1beta1", "RolePolicy", *e.Name, *e.Name, xp)
+}
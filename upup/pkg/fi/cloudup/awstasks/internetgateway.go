@@ -24,6 +24,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/crossplane"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -191,21 +192,7 @@ func (_ *InternetGateway) RenderTerraform(t *terraform.TerraformTarget, a, e, ch
 
 		// But ... attempt to discover the ID so TerraformLink works
 		if e.ID == nil {
-			request := &ec2.DescribeInternetGatewaysInput{}
-			vpcID := fi.StringValue(e.VPC.ID)
-			if vpcID == "" {
-				return fmt.Errorf("VPC ID is required when InternetGateway is shared")
-			}
-			request.Filters = []*ec2.Filter{awsup.NewEC2Filter("attachment.vpc-id", vpcID)}
-			igw, err := findInternetGateway(t.Cloud.(awsup.AWSCloud), request)
-			if err != nil {
-				return err
-			}
-			if igw == nil {
-				klog.Warningf("Cannot find internet gateway for VPC %q", vpcID)
-			} else {
-				e.ID = igw.InternetGatewayId
-			}
+			return e.findSharedID(t.Cloud.(awsup.AWSCloud))
 		}
 
 		return nil
@@ -216,6 +203,11 @@ func (_ *InternetGateway) RenderTerraform(t *terraform.TerraformTarget, a, e, ch
 		Tags:  e.Tags,
 	}
 
+	// Adopt the resource if it already exists
+	if a != nil && t.ImportExisting() {
+		t.AddImport("aws_internet_gateway", *e.Name, fi.StringValue(a.ID))
+	}
+
 	return t.RenderResource("aws_internet_gateway", *e.Name, tf)
 }
 
@@ -308,3 +300,61 @@ func (e *InternetGateway) CloudformationLink() *cloudformation.Literal {
 
 	return cloudformation.Ref("AWS::EC2::InternetGateway", *e.Name)
 }
+
+// findSharedID discovers the ID of a shared internet gateway, from the VPC it is attached to
+func (e *InternetGateway) findSharedID(cloud awsup.AWSCloud) error {
+	request := &ec2.DescribeInternetGatewaysInput{}
+	vpcID := fi.StringValue(e.VPC.ID)
+	if vpcID == "" {
+		return fmt.Errorf("VPC ID is required when InternetGateway is shared")
+	}
+	request.Filters = []*ec2.Filter{awsup.NewEC2Filter("attachment.vpc-id", vpcID)}
+	igw, err := findInternetGateway(cloud, request)
+	if err != nil {
+		return err
+	}
+	if igw == nil {
+		klog.Warningf("Cannot find internet gateway for VPC %q", vpcID)
+	} else {
+		e.ID = igw.InternetGatewayId
+	}
+	return nil
+}
+
+type crossplaneInternetGateway struct {
+	Region   *string               `json:"region"`
+	VPCID    *string               `json:"vpcId,omitempty"`
+	VPCIDRef *crossplane.Reference `json:"vpcIdRef,omitempty"`
+	Tags     map[string]string     `json:"tags,omitempty"`
+}
+
+func (_ *InternetGateway) RenderCrossplane(t *crossplane.CrossplaneTarget, a, e, changes *InternetGateway) error {
+	if fi.BoolValue(e.Shared) {
+		// Not managed by crossplane, but discover the ID so CrossplaneLink works
+		if e.ID == nil {
+			return e.findSharedID(t.Cloud.(awsup.AWSCloud))
+		}
+		return nil
+	}
+
+	vpc := e.VPC.CrossplaneLink()
+	xp := &crossplaneInternetGateway{
+		Region:   fi.String(t.Cloud.Region()),
+		VPCID:    vpc.Value,
+		VPCIDRef: vpc.Ref,
+		Tags:     e.Tags,
+	}
+
+	return t.RenderResource("ec2.aws.upbound.io/v1beta1", "InternetGateway", *e.Name, xp)
+}
+
+func (e *InternetGateway) CrossplaneLink() *crossplane.Literal {
+	if fi.BoolValue(e.Shared) {
+		if e.ID == nil {
+			klog.Fatalf("ID must be set, if InternetGateway is shared: %s", e)
+		}
+		return crossplane.LiteralFromStringValue(*e.ID)
+	}
+
+	return crossplane.LiteralRef(*e.Name)
+}
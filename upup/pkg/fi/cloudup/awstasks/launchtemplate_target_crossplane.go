@@ -0,0 +1,303 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"encoding/base64"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/crossplane"
+	"k8s.io/kops/util/pkg/maps"
+)
+
+type crossplaneLaunchTemplateNetworkInterface struct {
+	// AssociatePublicIPAddress associates a public ip address with the network interface. Boolean value.
+	AssociatePublicIPAddress *bool `json:"associatePublicIpAddress,omitempty"`
+	// DeleteOnTermination indicates whether the network interface should be destroyed on instance termination.
+	DeleteOnTermination *bool `json:"deleteOnTermination,omitempty"`
+	// Ipv6AddressCount is the number of IPv6 addresses to assign with the primary network interface.
+	Ipv6AddressCount *int64 `json:"ipv6AddressCount,omitempty"`
+	// SecurityGroups is a list of security group IDs.
+	SecurityGroups []string `json:"securityGroups,omitempty"`
+	// SecurityGroupRefs refers to the security groups managed by crossplane.
+	SecurityGroupRefs []*crossplane.Reference `json:"securityGroupRefs,omitempty"`
+}
+
+type crossplaneLaunchTemplateMonitoring struct {
+	// Enabled indicates that monitoring is enabled
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+type crossplaneLaunchTemplatePlacement struct {
+	// Tenancy is the tenancy of the instance (if the instance is running in a VPC). Can be default, dedicated, or host.
+	Tenancy *string `json:"tenancy,omitempty"`
+}
+
+type crossplaneLaunchTemplateIAMProfile struct {
+	// Name is the name of the profile
+	Name *string `json:"name,omitempty"`
+}
+
+type crossplaneLaunchTemplateMarketOptionsSpotOptions struct {
+	// BlockDurationMinutes is required duration in minutes. This value must be a multiple of 60.
+	BlockDurationMinutes *int64 `json:"blockDurationMinutes,omitempty"`
+	// InstanceInterruptionBehavior is the behavior when a Spot Instance is interrupted. Can be hibernate, stop, or terminate
+	InstanceInterruptionBehavior *string `json:"instanceInterruptionBehavior,omitempty"`
+	// MaxPrice is the maximum hourly price you're willing to pay for the Spot Instances.
+	MaxPrice *string `json:"maxPrice,omitempty"`
+}
+
+type crossplaneLaunchTemplateMarketOptions struct {
+	// MarketType is the option type
+	MarketType *string `json:"marketType,omitempty"`
+	// SpotOptions are the set of options
+	SpotOptions []*crossplaneLaunchTemplateMarketOptionsSpotOptions `json:"spotOptions,omitempty"`
+}
+
+type crossplaneLaunchTemplateCreditSpecification struct {
+	// CPUCredits is the credit option for CPU usage on some instance types.
+	CPUCredits *string `json:"cpuCredits,omitempty"`
+}
+
+type crossplaneLaunchTemplateBlockDeviceEBS struct {
+	// VolumeType is the ebs type to use
+	VolumeType *string `json:"volumeType,omitempty"`
+	// VolumeSize is the volume size
+	VolumeSize *int64 `json:"volumeSize,omitempty"`
+	// IOPS is the provisioned IOPS
+	IOPS *int64 `json:"iops,omitempty"`
+	// Throughput is the gp3 volume throughput
+	Throughput *int64 `json:"throughput,omitempty"`
+	// DeleteOnTermination indicates the volume should die with the instance
+	DeleteOnTermination *bool `json:"deleteOnTermination,omitempty"`
+	// Encrypted indicates the device should be encrypted
+	Encrypted *bool `json:"encrypted,omitempty"`
+	// KmsKeyID is the encryption key identifier for the volume
+	KmsKeyID *string `json:"kmsKeyId,omitempty"`
+}
+
+type crossplaneLaunchTemplateBlockDevice struct {
+	// DeviceName is the name of the device
+	DeviceName *string `json:"deviceName,omitempty"`
+	// VirtualName is used for the ephemeral devices
+	VirtualName *string `json:"virtualName,omitempty"`
+	// EBS defines the ebs spec
+	EBS []*crossplaneLaunchTemplateBlockDeviceEBS `json:"ebs,omitempty"`
+}
+
+type crossplaneLaunchTemplateTagSpecification struct {
+	// ResourceType is the type of resource to tag.
+	ResourceType *string `json:"resourceType,omitempty"`
+	// Tags are the tags to apply to the resource.
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+type crossplaneLaunchTemplateInstanceMetadata struct {
+	// HTTPEndpoint enables or disables the HTTP metadata endpoint on instances.
+	HTTPEndpoint *string `json:"httpEndpoint,omitempty"`
+	// HTTPPutResponseHopLimit is the desired HTTP PUT response hop limit for instance metadata requests.
+	HTTPPutResponseHopLimit *int64 `json:"httpPutResponseHopLimit,omitempty"`
+	// HTTPTokens is the state of token usage for your instance metadata requests.
+	HTTPTokens *string `json:"httpTokens,omitempty"`
+}
+
+type crossplaneLaunchTemplate struct {
+	// Region is the region of the launch template
+	Region *string `json:"region"`
+	// Name is the name of the launch template
+	Name *string `json:"name,omitempty"`
+	// BlockDeviceMappings is the device mappings
+	BlockDeviceMappings []*crossplaneLaunchTemplateBlockDevice `json:"blockDeviceMappings,omitempty"`
+	// CreditSpecification is the credit option for CPU Usage on some instance types
+	CreditSpecification []*crossplaneLaunchTemplateCreditSpecification `json:"creditSpecification,omitempty"`
+	// EBSOptimized indicates if the root device is ebs optimized
+	EBSOptimized *bool `json:"ebsOptimized,omitempty"`
+	// IAMInstanceProfile is the IAM profile to assign to the nodes
+	IAMInstanceProfile []*crossplaneLaunchTemplateIAMProfile `json:"iamInstanceProfile,omitempty"`
+	// ImageID is the ami to use for the instances
+	ImageID *string `json:"imageId,omitempty"`
+	// InstanceType is the type of instance
+	InstanceType *string `json:"instanceType,omitempty"`
+	// KeyName is the registered key pair to use for instances
+	KeyName *string `json:"keyName,omitempty"`
+	// MarketOptions are the spot pricing options
+	MarketOptions []*crossplaneLaunchTemplateMarketOptions `json:"instanceMarketOptions,omitempty"`
+	// MetadataOptions are the instance metadata options.
+	MetadataOptions []*crossplaneLaunchTemplateInstanceMetadata `json:"metadataOptions,omitempty"`
+	// Monitoring are the instance monitoring options
+	Monitoring []*crossplaneLaunchTemplateMonitoring `json:"monitoring,omitempty"`
+	// NetworkInterfaces are the networking options
+	NetworkInterfaces []*crossplaneLaunchTemplateNetworkInterface `json:"networkInterfaces,omitempty"`
+	// Placement are the tenancy options
+	Placement []*crossplaneLaunchTemplatePlacement `json:"placement,omitempty"`
+	// TagSpecifications are the tags to apply to a resource when it is created.
+	TagSpecifications []*crossplaneLaunchTemplateTagSpecification `json:"tagSpecifications,omitempty"`
+	// UserData is the user data for the instances, base64 encoded
+	UserData *string `json:"userData,omitempty"`
+	// Tags are the tags of the launch template
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// CrossplaneLink returns a reference to the launch template
+func (t *LaunchTemplate) CrossplaneLink() *crossplane.Literal {
+	return crossplane.LiteralRef(fi.StringValue(t.Name))
+}
+
+// RenderCrossplane is responsible for rendering the crossplane launch template
+func (t *LaunchTemplate) RenderCrossplane(target *crossplane.CrossplaneTarget, a, e, changes *LaunchTemplate) error {
+	var err error
+
+	cloud := target.Cloud.(awsup.AWSCloud)
+
+	var image *string
+	if e.ImageID != nil {
+		im, err := cloud.ResolveImage(fi.StringValue(e.ImageID))
+		if err != nil {
+			return err
+		}
+		image = im.ImageId
+	}
+
+	xp := &crossplaneLaunchTemplate{
+		Region:       fi.String(target.Cloud.Region()),
+		Name:         e.Name,
+		EBSOptimized: e.RootVolumeOptimization,
+		ImageID:      image,
+		InstanceType: e.InstanceType,
+		MetadataOptions: []*crossplaneLaunchTemplateInstanceMetadata{
+			{
+				HTTPEndpoint:            fi.String("enabled"),
+				HTTPTokens:              e.HTTPTokens,
+				HTTPPutResponseHopLimit: e.HTTPPutResponseHopLimit,
+			},
+		},
+		NetworkInterfaces: []*crossplaneLaunchTemplateNetworkInterface{
+			{
+				AssociatePublicIPAddress: e.AssociatePublicIP,
+				DeleteOnTermination:      fi.Bool(true),
+				Ipv6AddressCount:         e.IPv6AddressCount,
+			},
+		},
+	}
+
+	if fi.StringValue(e.SpotPrice) != "" {
+		marketSpotOptions := crossplaneLaunchTemplateMarketOptionsSpotOptions{MaxPrice: e.SpotPrice}
+		if e.SpotDurationInMinutes != nil {
+			marketSpotOptions.BlockDurationMinutes = e.SpotDurationInMinutes
+		}
+		if e.InstanceInterruptionBehavior != nil {
+			marketSpotOptions.InstanceInterruptionBehavior = e.InstanceInterruptionBehavior
+		}
+		xp.MarketOptions = []*crossplaneLaunchTemplateMarketOptions{
+			{
+				MarketType:  fi.String("spot"),
+				SpotOptions: []*crossplaneLaunchTemplateMarketOptionsSpotOptions{&marketSpotOptions},
+			},
+		}
+	}
+	if fi.StringValue(e.CPUCredits) != "" {
+		xp.CreditSpecification = []*crossplaneLaunchTemplateCreditSpecification{
+			{CPUCredits: e.CPUCredits},
+		}
+	}
+	for _, x := range e.SecurityGroups {
+		sg := x.CrossplaneLink()
+		if sg.Ref != nil {
+			xp.NetworkInterfaces[0].SecurityGroupRefs = append(xp.NetworkInterfaces[0].SecurityGroupRefs, sg.Ref)
+		} else {
+			xp.NetworkInterfaces[0].SecurityGroups = append(xp.NetworkInterfaces[0].SecurityGroups, fi.StringValue(sg.Value))
+		}
+	}
+	if e.SSHKey != nil && !e.SSHKey.NoSSHKey() {
+		xp.KeyName = e.SSHKey.Name
+	}
+	if e.Tenancy != nil {
+		xp.Placement = []*crossplaneLaunchTemplatePlacement{{Tenancy: e.Tenancy}}
+	}
+	if e.InstanceMonitoring != nil {
+		xp.Monitoring = []*crossplaneLaunchTemplateMonitoring{
+			{Enabled: e.InstanceMonitoring},
+		}
+	}
+	if e.IAMInstanceProfile != nil {
+		xp.IAMInstanceProfile = []*crossplaneLaunchTemplateIAMProfile{
+			{Name: e.IAMInstanceProfile.Name},
+		}
+	}
+	if e.UserData != nil {
+		d, err := fi.ResourceAsBytes(e.UserData)
+		if err != nil {
+			return err
+		}
+		if d != nil {
+			xp.UserData = fi.String(base64.StdEncoding.EncodeToString(d))
+		}
+	}
+	devices, err := e.buildRootDevice(cloud)
+	if err != nil {
+		return err
+	}
+	additionals, err := buildAdditionalDevices(e.BlockDeviceMappings)
+	if err != nil {
+		return err
+	}
+	for _, m := range []map[string]*BlockDeviceMapping{devices, additionals} {
+		for _, n := range maps.SortedKeys(m) {
+			x := m[n]
+			xp.BlockDeviceMappings = append(xp.BlockDeviceMappings, &crossplaneLaunchTemplateBlockDevice{
+				DeviceName: fi.String(n),
+				EBS: []*crossplaneLaunchTemplateBlockDeviceEBS{
+					{
+						DeleteOnTermination: fi.Bool(true),
+						Encrypted:           x.EbsEncrypted,
+						KmsKeyID:            x.EbsKmsKey,
+						IOPS:                x.EbsVolumeIops,
+						Throughput:          x.EbsVolumeThroughput,
+						VolumeSize:          x.EbsVolumeSize,
+						VolumeType:          x.EbsVolumeType,
+					},
+				},
+			})
+		}
+	}
+
+	devices, err = buildEphemeralDevices(cloud, fi.StringValue(e.InstanceType))
+	if err != nil {
+		return err
+	}
+	for _, n := range maps.SortedKeys(devices) {
+		xp.BlockDeviceMappings = append(xp.BlockDeviceMappings, &crossplaneLaunchTemplateBlockDevice{
+			VirtualName: devices[n].VirtualName,
+			DeviceName:  fi.String(n),
+		})
+	}
+
+	if e.Tags != nil {
+		xp.TagSpecifications = append(xp.TagSpecifications, &crossplaneLaunchTemplateTagSpecification{
+			ResourceType: fi.String("instance"),
+			Tags:         e.Tags,
+		})
+		xp.TagSpecifications = append(xp.TagSpecifications, &crossplaneLaunchTemplateTagSpecification{
+			ResourceType: fi.String("volume"),
+			Tags:         e.Tags,
+		})
+		xp.Tags = e.Tags
+	}
+
+	return target.RenderResource("ec2.aws.upbound.io/v1beta1", "LaunchTemplate", fi.StringValue(e.Name), xp)
+}
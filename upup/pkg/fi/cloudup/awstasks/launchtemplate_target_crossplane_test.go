@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestLaunchTemplateCrossplaneRender(t *testing.T) {
+	cases := []*renderTest{
+		{
+			Resource: &LaunchTemplate{
+				Name:              fi.String("test"),
+				AssociatePublicIP: fi.Bool(true),
+				BlockDeviceMappings: []*BlockDeviceMapping{
+					{
+						DeviceName:             fi.String("/dev/xvdd"),
+						EbsVolumeType:          fi.String("gp2"),
+						EbsVolumeSize:          fi.Int64(100),
+						EbsDeleteOnTermination: fi.Bool(true),
+						EbsEncrypted:           fi.Bool(true),
+					},
+				},
+				IAMInstanceProfile: &IAMInstanceProfile{
+					Name: fi.String("nodes"),
+				},
+				ID:                     fi.String("test-11"),
+				InstanceMonitoring:     fi.Bool(true),
+				InstanceType:           fi.String("t2.medium"),
+				RootVolumeOptimization: fi.Bool(true),
+				SpotPrice:              fi.String("10"),
+				SSHKey: &SSHKey{
+					Name: fi.String("mykey"),
+				},
+				SecurityGroups: []*SecurityGroup{
+					{Name: fi.String("nodes-1"), ID: fi.String("1111")},
+					{Name: fi.String("nodes-2"), ID: fi.String("2222"), Shared: fi.Bool(true)},
+				},
+				Tenancy:                 fi.String("dedicated"),
+				HTTPTokens:              fi.String("required"),
+				HTTPPutResponseHopLimit: fi.Int64(1),
+			},
+			Expected: `apiVersion: ec2.aws.upbound.io/v1beta1
+kind: LaunchTemplate
+metadata:
+  name: test
+spec:
+  forProvider:
+    blockDeviceMappings:
+    - deviceName: /dev/xvdd
+      ebs:
+      - deleteOnTermination: true
+        encrypted: true
+        volumeSize: 100
+        volumeType: gp2
+    ebsOptimized: true
+    iamInstanceProfile:
+    - name: nodes
+    instanceMarketOptions:
+    - marketType: spot
+      spotOptions:
+      - maxPrice: "10"
+    instanceType: t2.medium
+    keyName: mykey
+    metadataOptions:
+    - httpEndpoint: enabled
+      httpPutResponseHopLimit: 1
+      httpTokens: required
+    monitoring:
+    - enabled: true
+    name: test
+    networkInterfaces:
+    - associatePublicIpAddress: true
+      deleteOnTermination: true
+      securityGroupRefs:
+      - name: nodes-1
+      securityGroups:
+      - "2222"
+    placement:
+    - tenancy: dedicated
+    region: eu-west-2
+`,
+		},
+	}
+	doRenderTests(t, "RenderCrossplane", cases)
+}
@@ -324,5 +324,10 @@ func (t *LaunchTemplate) RenderTerraform(target *terraform.TerraformTarget, a, e
 		tf.Tags = e.Tags
 	}
 
+	// Adopt the resource if it already exists
+	if a != nil && target.ImportExisting() {
+		target.AddImport("aws_launch_template", fi.StringValue(e.Name), fi.StringValue(a.ID))
+	}
+
 	return target.RenderResource("aws_launch_template", fi.StringValue(e.Name), tf)
 }
@@ -27,6 +27,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/crossplane"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -383,6 +384,11 @@ func (_ *NatGateway) RenderTerraform(t *terraform.TerraformTarget, a, e, changes
 		Tag:          e.Tags,
 	}
 
+	// Adopt the resource if it already exists
+	if a != nil && t.ImportExisting() {
+		t.AddImport("aws_nat_gateway", *e.Name, fi.StringValue(a.ID))
+	}
+
 	return t.RenderResource("aws_nat_gateway", *e.Name, tf)
 }
 
@@ -434,3 +440,45 @@ func (e *NatGateway) CloudformationLink() *cloudformation.Literal {
 
 	return cloudformation.Ref("AWS::EC2::NatGateway", *e.Name)
 }
+
+type crossplaneNATGateway struct {
+	Region          *string               `json:"region"`
+	AllocationID    *string               `json:"allocationId,omitempty"`
+	AllocationIDRef *crossplane.Reference `json:"allocationIdRef,omitempty"`
+	SubnetID        *string               `json:"subnetId,omitempty"`
+	SubnetIDRef     *crossplane.Reference `json:"subnetIdRef,omitempty"`
+	Tags            map[string]string     `json:"tags,omitempty"`
+}
+
+func (_ *NatGateway) RenderCrossplane(t *crossplane.CrossplaneTarget, a, e, changes *NatGateway) error {
+	if fi.BoolValue(e.Shared) {
+		if e.ID == nil {
+			return fmt.Errorf("ID must be set, if NatGateway is shared: %s", e)
+		}
+		return nil
+	}
+
+	allocation := e.ElasticIP.CrossplaneLink()
+	subnet := e.Subnet.CrossplaneLink()
+	xp := &crossplaneNATGateway{
+		Region:          fi.String(t.Cloud.Region()),
+		AllocationID:    allocation.Value,
+		AllocationIDRef: allocation.Ref,
+		SubnetID:        subnet.Value,
+		SubnetIDRef:     subnet.Ref,
+		Tags:            e.Tags,
+	}
+
+	return t.RenderResource("ec2.aws.upbound.io/v1beta1", "NATGateway", *e.Name, xp)
+}
+
+func (e *NatGateway) CrossplaneLink() *crossplane.Literal {
+	if fi.BoolValue(e.Shared) {
+		if e.ID == nil {
+			klog.Fatalf("ID must be set, if NatGateway is shared: %s", e)
+		}
+		return crossplane.LiteralFromStringValue(*e.ID)
+	}
+
+	return crossplane.LiteralRef(*e.Name)
+}
@@ -27,6 +27,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/crossplane"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 )
 
@@ -61,6 +62,9 @@ func doRenderTests(t *testing.T, method string, cases []*renderTest) {
 		case "RenderCloudformation":
 			target = cloudformation.NewCloudformationTarget(cloud, "test", outdir)
 			filename = "kubernetes.json"
+		case "RenderCrossplane":
+			target = crossplane.NewCrossplaneTarget(cloud, "test", outdir)
+			filename = "kubernetes.yaml"
 		default:
 			t.Errorf("unknown render method: %s", method)
 			t.FailNow()
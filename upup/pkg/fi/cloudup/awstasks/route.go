@@ -25,6 +25,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/crossplane"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -329,3 +330,45 @@ func (_ *Route) RenderCloudformation(t *cloudformation.CloudformationTarget, a,
 
 	return t.RenderResource("AWS::EC2::Route", *e.Name, tf)
 }
+
+type crossplaneRoute struct {
+	Region           *string               `json:"region"`
+	RouteTableID     *string               `json:"routeTableId,omitempty"`
+	RouteTableIDRef  *crossplane.Reference `json:"routeTableIdRef,omitempty"`
+	CIDR             *string               `json:"destinationCidrBlock,omitempty"`
+	IPv6CIDR         *string               `json:"destinationIpv6CidrBlock,omitempty"`
+	GatewayID        *string               `json:"gatewayId,omitempty"`
+	GatewayIDRef     *crossplane.Reference `json:"gatewayIdRef,omitempty"`
+	NATGatewayID     *string               `json:"natGatewayId,omitempty"`
+	NATGatewayIDRef  *crossplane.Reference `json:"natGatewayIdRef,omitempty"`
+	TransitGatewayID *string               `json:"transitGatewayId,omitempty"`
+}
+
+func (_ *Route) RenderCrossplane(t *crossplane.CrossplaneTarget, a, e, changes *Route) error {
+	routeTable := e.RouteTable.CrossplaneLink()
+	xp := &crossplaneRoute{
+		Region:          fi.String(t.Cloud.Region()),
+		RouteTableID:    routeTable.Value,
+		RouteTableIDRef: routeTable.Ref,
+		CIDR:            e.CIDR,
+		IPv6CIDR:        e.IPv6CIDR,
+	}
+
+	if e.InternetGateway == nil && e.NatGateway == nil && e.TransitGatewayID == nil {
+		return fmt.Errorf("missing target for route")
+	} else if e.InternetGateway != nil {
+		gateway := e.InternetGateway.CrossplaneLink()
+		xp.GatewayID, xp.GatewayIDRef = gateway.Value, gateway.Ref
+	} else if e.NatGateway != nil {
+		gateway := e.NatGateway.CrossplaneLink()
+		xp.NATGatewayID, xp.NATGatewayIDRef = gateway.Value, gateway.Ref
+	} else if e.TransitGatewayID != nil {
+		xp.TransitGatewayID = e.TransitGatewayID
+	}
+
+	if e.Instance != nil {
+		return fmt.Errorf("routes to instances are not supported by the crossplane target")
+	}
+
+	return t.RenderResource("ec2.aws.upbound.io/v1beta1", "Route", *e.Name, xp)
+}
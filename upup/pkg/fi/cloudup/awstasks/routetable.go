@@ -25,6 +25,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/crossplane"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -210,6 +211,11 @@ func (_ *RouteTable) RenderTerraform(t *terraform.TerraformTarget, a, e, changes
 		Tags:  e.Tags,
 	}
 
+	// Adopt the resource if it already exists
+	if a != nil && t.ImportExisting() {
+		t.AddImport("aws_route_table", *e.Name, fi.StringValue(a.ID))
+	}
+
 	return t.RenderResource("aws_route_table", *e.Name, tf)
 }
 
@@ -234,3 +240,26 @@ func (_ *RouteTable) RenderCloudformation(t *cloudformation.CloudformationTarget
 func (e *RouteTable) CloudformationLink() *cloudformation.Literal {
 	return cloudformation.Ref("AWS::EC2::RouteTable", *e.Name)
 }
+
+type crossplaneRouteTable struct {
+	Region   *string               `json:"region"`
+	VPCID    *string               `json:"vpcId,omitempty"`
+	VPCIDRef *crossplane.Reference `json:"vpcIdRef,omitempty"`
+	Tags     map[string]string     `json:"tags,omitempty"`
+}
+
+func (_ *RouteTable) RenderCrossplane(t *crossplane.CrossplaneTarget, a, e, changes *RouteTable) error {
+	vpc := e.VPC.CrossplaneLink()
+	xp := &crossplaneRouteTable{
+		Region:   fi.String(t.Cloud.Region()),
+		VPCID:    vpc.Value,
+		VPCIDRef: vpc.Ref,
+		Tags:     e.Tags,
+	}
+
+	return t.RenderResource("ec2.aws.upbound.io/v1beta1", "RouteTable", *e.Name, xp)
+}
+
+func (e *RouteTable) CrossplaneLink() *crossplane.Literal {
+	return crossplane.LiteralRef(*e.Name)
+}
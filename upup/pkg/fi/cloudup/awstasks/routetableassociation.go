@@ -25,6 +25,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/crossplane"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -222,3 +223,25 @@ func (_ *RouteTableAssociation) RenderCloudformation(t *cloudformation.Cloudform
 func (e *RouteTableAssociation) CloudformationLink() *cloudformation.Literal {
 	return cloudformation.Ref("AWS::EC2::SubnetRouteTableAssociation", *e.Name)
 }
+
+type crossplaneRouteTableAssociation struct {
+	Region          *string               `json:"region"`
+	SubnetID        *string               `json:"subnetId,omitempty"`
+	SubnetIDRef     *crossplane.Reference `json:"subnetIdRef,omitempty"`
+	RouteTableID    *string               `json:"routeTableId,omitempty"`
+	RouteTableIDRef *crossplane.Reference `json:"routeTableIdRef,omitempty"`
+}
+
+func (_ *RouteTableAssociation) RenderCrossplane(t *crossplane.CrossplaneTarget, a, e, changes *RouteTableAssociation) error {
+	subnet := e.Subnet.CrossplaneLink()
+	routeTable := e.RouteTable.CrossplaneLink()
+	xp := &crossplaneRouteTableAssociation{
+		Region:          fi.String(t.Cloud.Region()),
+		SubnetID:        subnet.Value,
+		SubnetIDRef:     subnet.Ref,
+		RouteTableID:    routeTable.Value,
+		RouteTableIDRef: routeTable.Ref,
+	}
+
+	return t.RenderResource("ec2.aws.upbound.io/v1beta1", "RouteTableAssociation", *e.Name, xp)
+}
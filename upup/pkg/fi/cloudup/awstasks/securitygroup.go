@@ -27,6 +27,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/crossplane"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -195,6 +196,9 @@ func (_ *SecurityGroup) RenderTerraform(t *terraform.TerraformTarget, a, e, chan
 	shared := fi.BoolValue(e.Shared)
 	if shared {
 		// Not terraform owned / managed
+		if t.ImportExisting() {
+			return t.RenderDataSource("aws_security_group", *e.Name, &terraformDataSourceByID{ID: e.ID})
+		}
 		return nil
 	}
 
@@ -205,6 +209,11 @@ func (_ *SecurityGroup) RenderTerraform(t *terraform.TerraformTarget, a, e, chan
 		Tags:        e.Tags,
 	}
 
+	// Adopt the resource if it already exists
+	if a != nil && t.ImportExisting() {
+		t.AddImport("aws_security_group", *e.Name, fi.StringValue(a.ID))
+	}
+
 	return t.RenderResource("aws_security_group", *e.Name, tf)
 }
 
@@ -521,3 +530,42 @@ func (r *PortRemovalRule) Matches(permission *ec2.IpPermission) bool {
 	}
 	return true
 }
+
+type crossplaneSecurityGroup struct {
+	Region      *string               `json:"region"`
+	Name        *string               `json:"name,omitempty"`
+	VPCID       *string               `json:"vpcId,omitempty"`
+	VPCIDRef    *crossplane.Reference `json:"vpcIdRef,omitempty"`
+	Description *string               `json:"description,omitempty"`
+	Tags        map[string]string     `json:"tags,omitempty"`
+}
+
+func (_ *SecurityGroup) RenderCrossplane(t *crossplane.CrossplaneTarget, a, e, changes *SecurityGroup) error {
+	if fi.BoolValue(e.Shared) {
+		// Not managed by crossplane
+		return nil
+	}
+
+	vpc := e.VPC.CrossplaneLink()
+	xp := &crossplaneSecurityGroup{
+		Region:      fi.String(t.Cloud.Region()),
+		Name:        e.Name,
+		VPCID:       vpc.Value,
+		VPCIDRef:    vpc.Ref,
+		Description: e.Description,
+		Tags:        e.Tags,
+	}
+
+	return t.RenderResource("ec2.aws.upbound.io/v1beta1", "SecurityGroup", *e.Name, xp)
+}
+
+func (e *SecurityGroup) CrossplaneLink() *crossplane.Literal {
+	if fi.BoolValue(e.Shared) {
+		if e.ID == nil {
+			klog.Fatalf("ID must be set, if SecurityGroup is shared: %s", e)
+		}
+		return crossplane.LiteralFromStringValue(*e.ID)
+	}
+
+	return crossplane.LiteralRef(*e.Name)
+}
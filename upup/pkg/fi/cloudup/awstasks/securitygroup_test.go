@@ -17,6 +17,7 @@ limitations under the License.
 package awstasks
 
 import (
+	"context"
 	"reflect"
 	"testing"
 
@@ -94,6 +95,8 @@ func testNotMatches(t *testing.T, rule *PortRemovalRule, permission *ec2.IpPermi
 }
 
 func TestSecurityGroupCreate(t *testing.T) {
+	ctx := context.TODO()
+
 	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
 	c := &mockec2.MockEC2{}
 	cloud.MockEC2 = c
@@ -135,7 +138,7 @@ func TestSecurityGroupCreate(t *testing.T) {
 		}
 		defer context.Close()
 
-		if err := context.RunTasks(testRunTasksOptions); err != nil {
+		if err := context.RunTasks(ctx, testRunTasksOptions); err != nil {
 			t.Fatalf("unexpected error during Run: %v", err)
 		}
 
@@ -28,6 +28,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/crossplane"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -429,3 +430,63 @@ func (_ *SecurityGroupRule) RenderCloudformation(t *cloudformation.Cloudformatio
 
 	return t.RenderResource(cfType, *e.Name, tf)
 }
+
+type crossplaneSecurityGroupRule struct {
+	Region *string `json:"region"`
+	Type   *string `json:"type"`
+
+	SecurityGroupID          *string               `json:"securityGroupId,omitempty"`
+	SecurityGroupIDRef       *crossplane.Reference `json:"securityGroupIdRef,omitempty"`
+	SourceSecurityGroupID    *string               `json:"sourceSecurityGroupId,omitempty"`
+	SourceSecurityGroupIDRef *crossplane.Reference `json:"sourceSecurityGroupIdRef,omitempty"`
+
+	FromPort *int64 `json:"fromPort"`
+	ToPort   *int64 `json:"toPort"`
+
+	Protocol       *string  `json:"protocol"`
+	CIDRBlocks     []string `json:"cidrBlocks,omitempty"`
+	IPv6CIDRBlocks []string `json:"ipv6CidrBlocks,omitempty"`
+}
+
+func (_ *SecurityGroupRule) RenderCrossplane(t *crossplane.CrossplaneTarget, a, e, changes *SecurityGroupRule) error {
+	securityGroup := e.SecurityGroup.CrossplaneLink()
+	xp := &crossplaneSecurityGroupRule{
+		Region:             fi.String(t.Cloud.Region()),
+		Type:               fi.String("ingress"),
+		SecurityGroupID:    securityGroup.Value,
+		SecurityGroupIDRef: securityGroup.Ref,
+		FromPort:           e.FromPort,
+		ToPort:             e.ToPort,
+		Protocol:           e.Protocol,
+	}
+	if fi.BoolValue(e.Egress) {
+		xp.Type = fi.String("egress")
+	}
+
+	if e.Protocol == nil {
+		xp.Protocol = fi.String("-1")
+		xp.FromPort = fi.Int64(0)
+		xp.ToPort = fi.Int64(0)
+	}
+
+	if xp.FromPort == nil {
+		xp.FromPort = fi.Int64(0)
+	}
+	if xp.ToPort == nil {
+		xp.ToPort = fi.Int64(65535)
+	}
+
+	if e.SourceGroup != nil {
+		sourceGroup := e.SourceGroup.CrossplaneLink()
+		xp.SourceSecurityGroupID, xp.SourceSecurityGroupIDRef = sourceGroup.Value, sourceGroup.Ref
+	}
+
+	if e.CIDR != nil {
+		xp.CIDRBlocks = append(xp.CIDRBlocks, *e.CIDR)
+	}
+	if e.IPv6CIDR != nil {
+		xp.IPv6CIDRBlocks = append(xp.IPv6CIDRBlocks, *e.IPv6CIDR)
+	}
+
+	return t.RenderResource("ec2.aws.upbound.io/v1beta1", "SecurityGroupRule", *e.Name, xp)
+}
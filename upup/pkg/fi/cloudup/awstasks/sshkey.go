@@ -29,6 +29,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/crossplane"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 )
 
@@ -252,3 +253,29 @@ func (_ *SSHKey) RenderCloudformation(t *cloudformation.CloudformationTarget, a,
 func (e *SSHKey) NoSSHKey() bool {
 	return e.ID == nil && e.Name == nil && e.PublicKey == nil && e.KeyFingerprint == nil
 }
+
+type crossplaneSSHKey struct {
+	Region    *string           `json:"region"`
+	PublicKey *string           `json:"publicKey"`
+	Tags      map[string]string `json:"tags,omitempty"`
+}
+
+func (_ *SSHKey) RenderCrossplane(t *crossplane.CrossplaneTarget, a, e, changes *SSHKey) error {
+	// We don't want to render a key definition when we're using one that already exists
+	if e.IsExistingKey() {
+		return nil
+	}
+
+	publicKey, err := fi.ResourceAsString(e.PublicKey)
+	if err != nil {
+		return fmt.Errorf("error rendering PublicKey: %v", err)
+	}
+
+	xp := &crossplaneSSHKey{
+		Region:    fi.String(t.Cloud.Region()),
+		PublicKey: fi.String(publicKey),
+		Tags:      e.Tags,
+	}
+
+	return t.RenderNamedResource("ec2.aws.upbound.io/v1beta1", "KeyPair", *e.Name, *e.Name, xp)
+}
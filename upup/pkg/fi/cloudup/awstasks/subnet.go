@@ -26,6 +26,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/crossplane"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 	"k8s.io/kops/upup/pkg/fi/utils"
@@ -270,6 +271,11 @@ func (_ *Subnet) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *Su
 		// We probably shouldn't output subnet_ids only in this case - we normally output them by role,
 		// but removing it now might break people.  We could always output subnet_ids though, if we
 		// ever get a request for that.
+		if t.ImportExisting() {
+			if err := t.RenderDataSource("aws_subnet", *e.Name, &terraformDataSourceByID{ID: e.ID}); err != nil {
+				return err
+			}
+		}
 		return t.AddOutputVariableArray("subnet_ids", terraformWriter.LiteralFromStringValue(*e.ID))
 	}
 
@@ -281,6 +287,11 @@ func (_ *Subnet) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *Su
 		Tags:             e.Tags,
 	}
 
+	// Adopt the resource if it already exists
+	if a != nil && t.ImportExisting() {
+		t.AddImport("aws_subnet", *e.Name, fi.StringValue(a.ID))
+	}
+
 	return t.RenderResource("aws_subnet", *e.Name, tf)
 }
 
@@ -409,3 +420,44 @@ func (d *deleteSubnetIPv6CIDRBlock) TaskName() string {
 func (d *deleteSubnetIPv6CIDRBlock) Item() string {
 	return fmt.Sprintf("%v: ipv6cidr=%v", *d.vpcID, *d.ipv6CidrBlock)
 }
+
+type crossplaneSubnet struct {
+	Region           *string               `json:"region"`
+	VPCID            *string               `json:"vpcId,omitempty"`
+	VPCIDRef         *crossplane.Reference `json:"vpcIdRef,omitempty"`
+	CIDR             *string               `json:"cidrBlock,omitempty"`
+	IPv6CIDR         *string               `json:"ipv6CidrBlock,omitempty"`
+	AvailabilityZone *string               `json:"availabilityZone,omitempty"`
+	Tags             map[string]string     `json:"tags,omitempty"`
+}
+
+func (_ *Subnet) RenderCrossplane(t *crossplane.CrossplaneTarget, a, e, changes *Subnet) error {
+	if fi.BoolValue(e.Shared) {
+		// Not managed by crossplane
+		return nil
+	}
+
+	vpc := e.VPC.CrossplaneLink()
+	xp := &crossplaneSubnet{
+		Region:           fi.String(t.Cloud.Region()),
+		VPCID:            vpc.Value,
+		VPCIDRef:         vpc.Ref,
+		CIDR:             e.CIDR,
+		IPv6CIDR:         e.IPv6CIDR,
+		AvailabilityZone: e.AvailabilityZone,
+		Tags:             e.Tags,
+	}
+
+	return t.RenderResource("ec2.aws.upbound.io/v1beta1", "Subnet", *e.Name, xp)
+}
+
+func (e *Subnet) CrossplaneLink() *crossplane.Literal {
+	if fi.BoolValue(e.Shared) {
+		if e.ID == nil {
+			klog.Fatalf("ID must be set, if subnet is shared: %s", e)
+		}
+		return crossplane.LiteralFromStringValue(*e.ID)
+	}
+
+	return crossplane.LiteralRef(*e.Name)
+}
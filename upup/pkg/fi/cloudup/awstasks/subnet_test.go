@@ -17,6 +17,7 @@ limitations under the License.
 package awstasks
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"testing"
@@ -64,6 +65,8 @@ func Test_Subnet_CannotChangeSubnet(t *testing.T) {
 }
 
 func TestSubnetCreate(t *testing.T) {
+	ctx := context.TODO()
+
 	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
 	c := &mockec2.MockEC2{}
 	cloud.MockEC2 = c
@@ -104,7 +107,7 @@ func TestSubnetCreate(t *testing.T) {
 		}
 		defer context.Close()
 
-		if err := context.RunTasks(testRunTasksOptions); err != nil {
+		if err := context.RunTasks(ctx, testRunTasksOptions); err != nil {
 			t.Fatalf("unexpected error during Run: %v", err)
 		}
 
@@ -140,6 +143,8 @@ func TestSubnetCreate(t *testing.T) {
 }
 
 func TestSharedSubnetCreateDoesNotCreateNew(t *testing.T) {
+	ctx := context.TODO()
+
 	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
 	c := &mockec2.MockEC2{}
 	cloud.MockEC2 = c
@@ -222,7 +227,7 @@ func TestSharedSubnetCreateDoesNotCreateNew(t *testing.T) {
 		}
 		defer context.Close()
 
-		if err := context.RunTasks(testRunTasksOptions); err != nil {
+		if err := context.RunTasks(ctx, testRunTasksOptions); err != nil {
 			t.Fatalf("unexpected error during Run: %v", err)
 		}
 
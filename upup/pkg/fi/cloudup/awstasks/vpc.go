@@ -27,6 +27,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/crossplane"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -292,6 +293,9 @@ func (_ *VPC) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *VPC)
 	if shared {
 		// Not terraform owned / managed
 		// We won't apply changes, but our validation (kops update) will still warn
+		if t.ImportExisting() {
+			return t.RenderDataSource("aws_vpc", *e.Name, &terraformDataSourceByID{ID: e.ID})
+		}
 		return nil
 	}
 
@@ -308,9 +312,19 @@ func (_ *VPC) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *VPC)
 		AmazonIPv6:         e.AmazonIPv6,
 	}
 
+	// Adopt the resource if it already exists
+	if a != nil && t.ImportExisting() {
+		t.AddImport("aws_vpc", *e.Name, fi.StringValue(a.ID))
+	}
+
 	return t.RenderResource("aws_vpc", *e.Name, tf)
 }
 
+// terraformDataSourceByID looks up a resource that is shared with the cluster by its ID
+type terraformDataSourceByID struct {
+	ID *string `cty:"id"`
+}
+
 func (e *VPC) TerraformLink() *terraformWriter.Literal {
 	shared := fi.BoolValue(e.Shared)
 	if shared {
@@ -392,3 +406,41 @@ func (d *deleteVPCCIDRBlock) TaskName() string {
 func (d *deleteVPCCIDRBlock) Item() string {
 	return fmt.Sprintf("%v: cidr=%v", *d.vpcID, *d.cidrBlock)
 }
+
+type crossplaneVPC struct {
+	Region             *string           `json:"region"`
+	CIDR               *string           `json:"cidrBlock,omitempty"`
+	EnableDNSHostnames *bool             `json:"enableDnsHostnames,omitempty"`
+	EnableDNSSupport   *bool             `json:"enableDnsSupport,omitempty"`
+	AmazonIPv6         *bool             `json:"assignGeneratedIpv6CidrBlock,omitempty"`
+	Tags               map[string]string `json:"tags,omitempty"`
+}
+
+func (_ *VPC) RenderCrossplane(t *crossplane.CrossplaneTarget, a, e, changes *VPC) error {
+	if fi.BoolValue(e.Shared) {
+		// Not managed by crossplane
+		return nil
+	}
+
+	xp := &crossplaneVPC{
+		Region:             fi.String(t.Cloud.Region()),
+		CIDR:               e.CIDR,
+		EnableDNSHostnames: e.EnableDNSHostnames,
+		EnableDNSSupport:   e.EnableDNSSupport,
+		AmazonIPv6:         e.AmazonIPv6,
+		Tags:               e.Tags,
+	}
+
+	return t.RenderResource("ec2.aws.upbound.io/v1beta1", "VPC", *e.Name, xp)
+}
+
+func (e *VPC) CrossplaneLink() *crossplane.Literal {
+	if fi.BoolValue(e.Shared) {
+		if e.ID == nil {
+			klog.Fatalf("ID must be set, if VPC is shared: %s", e)
+		}
+		return crossplane.LiteralFromStringValue(*e.ID)
+	}
+
+	return crossplane.LiteralRef(*e.Name)
+}
@@ -24,6 +24,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/crossplane"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
@@ -129,3 +130,25 @@ func (_ *VPCDHCPOptionsAssociation) RenderCloudformation(t *cloudformation.Cloud
 
 	return t.RenderResource("AWS::EC2::VPCDHCPOptionsAssociation", *e.Name, tf)
 }
+
+type crossplaneVPCDHCPOptionsAssociation struct {
+	Region           *string               `json:"region"`
+	VPCID            *string               `json:"vpcId,omitempty"`
+	VPCIDRef         *crossplane.Reference `json:"vpcIdRef,omitempty"`
+	DHCPOptionsID    *string               `json:"dhcpOptionsId,omitempty"`
+	DHCPOptionsIDRef *crossplane.Reference `json:"dhcpOptionsIdRef,omitempty"`
+}
+
+func (_ *VPCDHCPOptionsAssociation) RenderCrossplane(t *crossplane.CrossplaneTarget, a, e, changes *VPCDHCPOptionsAssociation) error {
+	vpc := e.VPC.CrossplaneLink()
+	dhcpOptions := e.DHCPOptions.CrossplaneLink()
+	xp := &crossplaneVPCDHCPOptionsAssociation{
+		Region:           fi.String(t.Cloud.Region()),
+		VPCID:            vpc.Value,
+		VPCIDRef:         vpc.Ref,
+		DHCPOptionsID:    dhcpOptions.Value,
+		DHCPOptionsIDRef: dhcpOptions.Ref,
+	}
+
+	return t.RenderResource("ec2.aws.upbound.io/v1beta1", "VPCDHCPOptionsAssociation", *e.Name, xp)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package awstasks
 
 import (
+	"context"
 	"reflect"
 	"testing"
 
@@ -28,6 +29,8 @@ import (
 )
 
 func TestVPCCreate(t *testing.T) {
+	ctx := context.TODO()
+
 	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
 	c := &mockec2.MockEC2{}
 	cloud.MockEC2 = c
@@ -59,7 +62,7 @@ func TestVPCCreate(t *testing.T) {
 		}
 		defer context.Close()
 
-		if err := context.RunTasks(testRunTasksOptions); err != nil {
+		if err := context.RunTasks(ctx, testRunTasksOptions); err != nil {
 			t.Fatalf("unexpected error during Run: %v", err)
 		}
 
@@ -132,6 +135,8 @@ func Test4758(t *testing.T) {
 }
 
 func TestSharedVPCAdditionalCIDR(t *testing.T) {
+	ctx := context.TODO()
+
 	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
 	c := &mockec2.MockEC2{}
 	c.CreateVpcWithId(&ec2.CreateVpcInput{
@@ -183,7 +188,7 @@ func TestSharedVPCAdditionalCIDR(t *testing.T) {
 		}
 		defer context.Close()
 
-		if err := context.RunTasks(testRunTasksOptions); err != nil {
+		if err := context.RunTasks(ctx, testRunTasksOptions); err != nil {
 			t.Fatalf("unexpected error during Run: %v", err)
 		}
 
@@ -0,0 +1,229 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
+)
+
+// VPCEndpoint is an interface endpoint, in a consumer VPC, for a VPCEndpointService
+// +kops:fitask
+type VPCEndpoint struct {
+	Name      *string
+	Lifecycle fi.Lifecycle
+
+	ID *string
+	// VPCID is the consumer VPC, which is usually not managed by kops
+	VPCID   *string
+	Service *VPCEndpointService
+
+	SubnetIDs        []string
+	SecurityGroupIDs []string
+
+	Tags map[string]string
+}
+
+var _ fi.CompareWithID = &VPCEndpoint{}
+
+func (e *VPCEndpoint) CompareWithID() *string {
+	return e.ID
+}
+
+func (e *VPCEndpoint) Find(c *fi.Context) (*VPCEndpoint, error) {
+	cloud := c.Cloud.(awsup.AWSCloud)
+
+	request := &ec2.DescribeVpcEndpointsInput{}
+	if e.ID != nil {
+		request.VpcEndpointIds = []*string{e.ID}
+	} else {
+		request.Filters = append(cloud.BuildFilters(e.Name), awsup.NewEC2Filter("vpc-id", fi.StringValue(e.VPCID)))
+	}
+
+	response, err := cloud.EC2().DescribeVpcEndpoints(request)
+	if err != nil {
+		return nil, fmt.Errorf("error listing VPC endpoints: %v", err)
+	}
+
+	var endpoints []*ec2.VpcEndpoint
+	for _, endpoint := range response.VpcEndpoints {
+		switch aws.StringValue(endpoint.State) {
+		case "Deleted", "Deleting", "Failed", "Rejected":
+			klog.V(2).Infof("ignoring VPC endpoint %q in state %q", aws.StringValue(endpoint.VpcEndpointId), aws.StringValue(endpoint.State))
+			continue
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	if len(endpoints) == 0 {
+		return nil, nil
+	}
+	if len(endpoints) != 1 {
+		return nil, fmt.Errorf("found multiple VPC endpoints named %q", fi.StringValue(e.Name))
+	}
+	endpoint := endpoints[0]
+
+	actual := &VPCEndpoint{
+		Name:      e.Name,
+		Lifecycle: e.Lifecycle,
+		ID:        endpoint.VpcEndpointId,
+		VPCID:     endpoint.VpcId,
+		Service:   &VPCEndpointService{ServiceName: endpoint.ServiceName},
+		SubnetIDs: aws.StringValueSlice(endpoint.SubnetIds),
+		Tags:      intersectTags(endpoint.Tags, e.Tags),
+	}
+	for _, group := range endpoint.Groups {
+		actual.SecurityGroupIDs = append(actual.SecurityGroupIDs, aws.StringValue(group.GroupId))
+	}
+	sort.Strings(actual.SubnetIDs)
+	sort.Strings(actual.SecurityGroupIDs)
+
+	klog.V(2).Infof("found matching VPC endpoint %q", aws.StringValue(actual.ID))
+
+	e.ID = actual.ID
+
+	return actual, nil
+}
+
+func (e *VPCEndpoint) Normalize() {
+	sort.Strings(e.SubnetIDs)
+	sort.Strings(e.SecurityGroupIDs)
+}
+
+func (e *VPCEndpoint) Run(c *fi.Context) error {
+	e.Normalize()
+	return fi.DefaultDeltaRunMethod(e, c)
+}
+
+func (_ *VPCEndpoint) CheckChanges(a, e, changes *VPCEndpoint) error {
+	if a == nil {
+		if e.VPCID == nil {
+			return fi.RequiredField("VPCID")
+		}
+		if e.Service == nil {
+			return fi.RequiredField("Service")
+		}
+		if len(e.SubnetIDs) == 0 {
+			return fi.RequiredField("SubnetIDs")
+		}
+	} else {
+		if changes.VPCID != nil {
+			return fi.CannotChangeField("VPCID")
+		}
+		if changes.Service != nil {
+			return fi.CannotChangeField("Service")
+		}
+	}
+	return nil
+}
+
+func (_ *VPCEndpoint) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *VPCEndpoint) error {
+	if a == nil {
+		klog.V(2).Infof("Creating VPC endpoint %q in VPC %q", fi.StringValue(e.Name), fi.StringValue(e.VPCID))
+
+		request := &ec2.CreateVpcEndpointInput{
+			VpcEndpointType:  aws.String(ec2.VpcEndpointTypeInterface),
+			VpcId:            e.VPCID,
+			ServiceName:      e.Service.ServiceName,
+			SubnetIds:        aws.StringSlice(e.SubnetIDs),
+			SecurityGroupIds: aws.StringSlice(e.SecurityGroupIDs),
+		}
+
+		response, err := t.Cloud.EC2().CreateVpcEndpoint(request)
+		if err != nil {
+			return fmt.Errorf("error creating VPC endpoint: %v", err)
+		}
+
+		e.ID = response.VpcEndpoint.VpcEndpointId
+	} else if changes.SubnetIDs != nil || changes.SecurityGroupIDs != nil {
+		klog.V(2).Infof("Modifying VPC endpoint %q", fi.StringValue(e.ID))
+
+		request := &ec2.ModifyVpcEndpointInput{
+			VpcEndpointId: e.ID,
+		}
+		addSubnets, removeSubnets := diffStrings(a.SubnetIDs, e.SubnetIDs)
+		request.AddSubnetIds = aws.StringSlice(addSubnets)
+		request.RemoveSubnetIds = aws.StringSlice(removeSubnets)
+		addGroups, removeGroups := diffStrings(a.SecurityGroupIDs, e.SecurityGroupIDs)
+		request.AddSecurityGroupIds = aws.StringSlice(addGroups)
+		request.RemoveSecurityGroupIds = aws.StringSlice(removeGroups)
+
+		if _, err := t.Cloud.EC2().ModifyVpcEndpoint(request); err != nil {
+			return fmt.Errorf("error modifying VPC endpoint %q: %v", fi.StringValue(e.ID), err)
+		}
+	}
+
+	return t.AddAWSTags(*e.ID, e.Tags)
+}
+
+type terraformVPCEndpoint struct {
+	VPCID            *string                  `json:"vpc_id" cty:"vpc_id"`
+	ServiceName      *terraformWriter.Literal `json:"service_name" cty:"service_name"`
+	VPCEndpointType  string                   `json:"vpc_endpoint_type" cty:"vpc_endpoint_type"`
+	SubnetIDs        []string                 `json:"subnet_ids,omitempty" cty:"subnet_ids"`
+	SecurityGroupIDs []string                 `json:"security_group_ids,omitempty" cty:"security_group_ids"`
+	Tags             map[string]string        `json:"tags,omitempty" cty:"tags"`
+}
+
+func (_ *VPCEndpoint) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *VPCEndpoint) error {
+	tf := &terraformVPCEndpoint{
+		VPCID:            e.VPCID,
+		ServiceName:      e.Service.TerraformLink("service_name"),
+		VPCEndpointType:  ec2.VpcEndpointTypeInterface,
+		SubnetIDs:        e.SubnetIDs,
+		SecurityGroupIDs: e.SecurityGroupIDs,
+		Tags:             e.Tags,
+	}
+
+	return t.RenderResource("aws_vpc_endpoint", *e.Name, tf)
+}
+
+func (e *VPCEndpoint) TerraformLink() *terraformWriter.Literal {
+	return terraformWriter.LiteralProperty("aws_vpc_endpoint", *e.Name, "id")
+}
+
+type cloudformationVPCEndpoint struct {
+	VPCID            *string                 `json:"VpcId"`
+	ServiceName      *cloudformation.Literal `json:"ServiceName"`
+	VPCEndpointType  string                  `json:"VpcEndpointType"`
+	SubnetIDs        []string                `json:"SubnetIds,omitempty"`
+	SecurityGroupIDs []string                `json:"SecurityGroupIds,omitempty"`
+}
+
+func (_ *VPCEndpoint) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *VPCEndpoint) error {
+	cf := &cloudformationVPCEndpoint{
+		VPCID:            e.VPCID,
+		ServiceName:      e.Service.CloudformationServiceName(t.Cloud.Region()),
+		VPCEndpointType:  ec2.VpcEndpointTypeInterface,
+		SubnetIDs:        e.SubnetIDs,
+		SecurityGroupIDs: e.SecurityGroupIDs,
+	}
+
+	return t.RenderResource("AWS::EC2::VPCEndpoint", *e.Name, cf)
+}
+
+func (e *VPCEndpoint) CloudformationLink() *cloudformation.Literal {
+	return cloudformation.Ref("AWS::EC2::VPCEndpoint", *e.Name)
+}
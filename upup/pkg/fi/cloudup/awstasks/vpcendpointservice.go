@@ -0,0 +1,285 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
+)
+
+// VPCEndpointService publishes a network load balancer through PrivateLink
+// +kops:fitask
+type VPCEndpointService struct {
+	Name      *string
+	Lifecycle fi.Lifecycle
+
+	ID *string
+	// ServiceName is the name consumers use to create endpoints, assigned by AWS
+	ServiceName *string
+
+	NetworkLoadBalancer *NetworkLoadBalancer
+
+	// AcceptanceRequired is set if connections from new endpoints must be accepted manually
+	AcceptanceRequired *bool
+	// AllowedPrincipals are the ARNs of the principals allowed to create endpoints
+	AllowedPrincipals []string
+
+	Tags map[string]string
+}
+
+var _ fi.CompareWithID = &VPCEndpointService{}
+
+// CompareWithID uses the service name, because endpoints only know the name of the service they connect to
+func (e *VPCEndpointService) CompareWithID() *string {
+	return e.ServiceName
+}
+
+func (e *VPCEndpointService) Find(c *fi.Context) (*VPCEndpointService, error) {
+	cloud := c.Cloud.(awsup.AWSCloud)
+
+	request := &ec2.DescribeVpcEndpointServiceConfigurationsInput{}
+	if e.ID != nil {
+		request.ServiceIds = []*string{e.ID}
+	} else {
+		request.Filters = cloud.BuildFilters(e.Name)
+	}
+
+	response, err := cloud.EC2().DescribeVpcEndpointServiceConfigurations(request)
+	if err != nil {
+		return nil, fmt.Errorf("error listing VPC endpoint services: %v", err)
+	}
+	if response == nil || len(response.ServiceConfigurations) == 0 {
+		return nil, nil
+	}
+	if len(response.ServiceConfigurations) != 1 {
+		return nil, fmt.Errorf("found multiple VPC endpoint services named %q", fi.StringValue(e.Name))
+	}
+	service := response.ServiceConfigurations[0]
+
+	actual := &VPCEndpointService{
+		Name:               e.Name,
+		Lifecycle:          e.Lifecycle,
+		ID:                 service.ServiceId,
+		ServiceName:        service.ServiceName,
+		AcceptanceRequired: service.AcceptanceRequired,
+		Tags:               intersectTags(service.Tags, e.Tags),
+	}
+
+	if e.NetworkLoadBalancer != nil {
+		lb, err := cloud.FindELBV2ByNameTag(e.NetworkLoadBalancer.Tags["Name"])
+		if err != nil {
+			return nil, err
+		}
+		for _, arn := range service.NetworkLoadBalancerArns {
+			if lb != nil && aws.StringValue(arn) == aws.StringValue(lb.LoadBalancerArn) {
+				actual.NetworkLoadBalancer = e.NetworkLoadBalancer
+			}
+		}
+	}
+
+	permissions, err := cloud.EC2().DescribeVpcEndpointServicePermissions(&ec2.DescribeVpcEndpointServicePermissionsInput{
+		ServiceId: service.ServiceId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error describing permissions of VPC endpoint service %q: %v", aws.StringValue(service.ServiceId), err)
+	}
+	for _, principal := range permissions.AllowedPrincipals {
+		actual.AllowedPrincipals = append(actual.AllowedPrincipals, aws.StringValue(principal.Principal))
+	}
+	sort.Strings(actual.AllowedPrincipals)
+
+	klog.V(2).Infof("found matching VPC endpoint service %q", aws.StringValue(actual.ID))
+
+	e.ID = actual.ID
+	e.ServiceName = actual.ServiceName
+
+	return actual, nil
+}
+
+func (e *VPCEndpointService) Normalize() {
+	sort.Strings(e.AllowedPrincipals)
+}
+
+func (e *VPCEndpointService) Run(c *fi.Context) error {
+	e.Normalize()
+	return fi.DefaultDeltaRunMethod(e, c)
+}
+
+func (_ *VPCEndpointService) CheckChanges(a, e, changes *VPCEndpointService) error {
+	if a == nil {
+		if e.NetworkLoadBalancer == nil {
+			return fi.RequiredField("NetworkLoadBalancer")
+		}
+	} else {
+		if changes.NetworkLoadBalancer != nil {
+			return fi.CannotChangeField("NetworkLoadBalancer")
+		}
+	}
+	return nil
+}
+
+func (_ *VPCEndpointService) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *VPCEndpointService) error {
+	if a == nil {
+		lb, err := t.Cloud.FindELBV2ByNameTag(e.NetworkLoadBalancer.Tags["Name"])
+		if err != nil {
+			return err
+		}
+		if lb == nil {
+			return fmt.Errorf("network load balancer %q not found", fi.StringValue(e.NetworkLoadBalancer.Name))
+		}
+
+		klog.V(2).Infof("Creating VPC endpoint service %q", fi.StringValue(e.Name))
+
+		request := &ec2.CreateVpcEndpointServiceConfigurationInput{
+			AcceptanceRequired:      fi.Bool(fi.BoolValue(e.AcceptanceRequired)),
+			NetworkLoadBalancerArns: []*string{lb.LoadBalancerArn},
+		}
+
+		response, err := t.Cloud.EC2().CreateVpcEndpointServiceConfiguration(request)
+		if err != nil {
+			return fmt.Errorf("error creating VPC endpoint service: %v", err)
+		}
+
+		e.ID = response.ServiceConfiguration.ServiceId
+		e.ServiceName = response.ServiceConfiguration.ServiceName
+	} else if changes.AcceptanceRequired != nil {
+		klog.V(2).Infof("Modifying VPC endpoint service %q", fi.StringValue(e.ID))
+
+		request := &ec2.ModifyVpcEndpointServiceConfigurationInput{
+			ServiceId:          e.ID,
+			AcceptanceRequired: fi.Bool(fi.BoolValue(e.AcceptanceRequired)),
+		}
+		if _, err := t.Cloud.EC2().ModifyVpcEndpointServiceConfiguration(request); err != nil {
+			return fmt.Errorf("error modifying VPC endpoint service %q: %v", fi.StringValue(e.ID), err)
+		}
+	}
+
+	var actualPrincipals []string
+	if a != nil {
+		actualPrincipals = a.AllowedPrincipals
+	}
+	add, remove := diffStrings(actualPrincipals, e.AllowedPrincipals)
+	if len(add) != 0 || len(remove) != 0 {
+		klog.V(2).Infof("Updating permissions of VPC endpoint service %q", fi.StringValue(e.ID))
+
+		request := &ec2.ModifyVpcEndpointServicePermissionsInput{
+			ServiceId:               e.ID,
+			AddAllowedPrincipals:    aws.StringSlice(add),
+			RemoveAllowedPrincipals: aws.StringSlice(remove),
+		}
+		if _, err := t.Cloud.EC2().ModifyVpcEndpointServicePermissions(request); err != nil {
+			return fmt.Errorf("error updating permissions of VPC endpoint service %q: %v", fi.StringValue(e.ID), err)
+		}
+	}
+
+	return t.AddAWSTags(*e.ID, e.Tags)
+}
+
+// diffStrings returns the values that are only in expected, and those that are only in actual
+func diffStrings(actual, expected []string) (add []string, remove []string) {
+	actualSet := make(map[string]bool)
+	for _, s := range actual {
+		actualSet[s] = true
+	}
+	expectedSet := make(map[string]bool)
+	for _, s := range expected {
+		expectedSet[s] = true
+		if !actualSet[s] {
+			add = append(add, s)
+		}
+	}
+	for _, s := range actual {
+		if !expectedSet[s] {
+			remove = append(remove, s)
+		}
+	}
+	return add, remove
+}
+
+type terraformVPCEndpointService struct {
+	AcceptanceRequired      bool                       `json:"acceptance_required" cty:"acceptance_required"`
+	NetworkLoadBalancerARNs []*terraformWriter.Literal `json:"network_load_balancer_arns" cty:"network_load_balancer_arns"`
+	AllowedPrincipals       []string                   `json:"allowed_principals,omitempty" cty:"allowed_principals"`
+	Tags                    map[string]string          `json:"tags,omitempty" cty:"tags"`
+}
+
+func (_ *VPCEndpointService) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *VPCEndpointService) error {
+	tf := &terraformVPCEndpointService{
+		AcceptanceRequired:      fi.BoolValue(e.AcceptanceRequired),
+		NetworkLoadBalancerARNs: []*terraformWriter.Literal{e.NetworkLoadBalancer.TerraformLink()},
+		AllowedPrincipals:       e.AllowedPrincipals,
+		Tags:                    e.Tags,
+	}
+
+	return t.RenderResource("aws_vpc_endpoint_service", *e.Name, tf)
+}
+
+func (e *VPCEndpointService) TerraformLink(params ...string) *terraformWriter.Literal {
+	prop := "id"
+	if len(params) > 0 {
+		prop = params[0]
+	}
+	return terraformWriter.LiteralProperty("aws_vpc_endpoint_service", *e.Name, prop)
+}
+
+type cloudformationVPCEndpointService struct {
+	AcceptanceRequired      bool                      `json:"AcceptanceRequired"`
+	NetworkLoadBalancerARNs []*cloudformation.Literal `json:"NetworkLoadBalancerArns"`
+}
+
+type cloudformationVPCEndpointServicePermissions struct {
+	AllowedPrincipals []string                `json:"AllowedPrincipals"`
+	ServiceID         *cloudformation.Literal `json:"ServiceId"`
+}
+
+func (_ *VPCEndpointService) RenderCloudformation(t *cloudformation.CloudformationTarget, a, e, changes *VPCEndpointService) error {
+	cf := &cloudformationVPCEndpointService{
+		AcceptanceRequired:      fi.BoolValue(e.AcceptanceRequired),
+		NetworkLoadBalancerARNs: []*cloudformation.Literal{e.NetworkLoadBalancer.CloudformationLink()},
+	}
+	if err := t.RenderResource("AWS::EC2::VPCEndpointService", *e.Name, cf); err != nil {
+		return err
+	}
+
+	if len(e.AllowedPrincipals) == 0 {
+		return nil
+	}
+
+	permissions := &cloudformationVPCEndpointServicePermissions{
+		AllowedPrincipals: e.AllowedPrincipals,
+		ServiceID:         e.CloudformationLink(),
+	}
+	return t.RenderResource("AWS::EC2::VPCEndpointServicePermissions", *e.Name, permissions)
+}
+
+func (e *VPCEndpointService) CloudformationLink() *cloudformation.Literal {
+	return cloudformation.Ref("AWS::EC2::VPCEndpointService", *e.Name)
+}
+
+// CloudformationServiceName builds the name consumers use to connect to the service, which CloudFormation does not expose
+func (e *VPCEndpointService) CloudformationServiceName(region string) *cloudformation.Literal {
+	return cloudformation.Join("", "com.amazonaws.vpce."+region+".", e.CloudformationLink())
+}
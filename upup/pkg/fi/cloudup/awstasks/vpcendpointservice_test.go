@@ -0,0 +1,175 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func testVPCEndpointService() *VPCEndpointService {
+	return &VPCEndpointService{
+		Name:                fi.String("api.test"),
+		NetworkLoadBalancer: &NetworkLoadBalancer{Name: fi.String("api-test")},
+		AllowedPrincipals:   []string{"arn:aws:iam::123456789012:root"},
+		Tags: map[string]string{
+			"Name": "api.test",
+		},
+	}
+}
+
+func testVPCEndpoint() *VPCEndpoint {
+	return &VPCEndpoint{
+		Name:             fi.String("api-vpc-1234.test"),
+		VPCID:            fi.String("vpc-1234"),
+		Service:          testVPCEndpointService(),
+		SubnetIDs:        []string{"subnet-1234"},
+		SecurityGroupIDs: []string{"sg-1234"},
+		Tags: map[string]string{
+			"Name": "api-vpc-1234.test",
+		},
+	}
+}
+
+func TestVPCEndpointServiceTerraformRender(t *testing.T) {
+	cases := []*renderTest{
+		{
+			Resource: testVPCEndpointService(),
+			Expected: `provider "aws" {
+  region = "eu-west-2"
+}
+
+resource "aws_vpc_endpoint_service" "api-test" {
+  acceptance_required        = false
+  allowed_principals         = ["arn:aws:iam::123456789012:root"]
+  network_load_balancer_arns = [aws_lb.api-test.id]
+  tags = {
+    "Name" = "api.test"
+  }
+}
+
+terraform {
+  required_version = ">= 0.12.26"
+  required_providers {
+    aws = {
+      "source"  = "hashicorp/aws"
+      "version" = ">= 3.34.0"
+    }
+  }
+}
+`,
+		},
+		{
+			Resource: testVPCEndpoint(),
+			Expected: `provider "aws" {
+  region = "eu-west-2"
+}
+
+resource "aws_vpc_endpoint" "api-vpc-1234-test" {
+  security_group_ids = ["sg-1234"]
+  service_name       = aws_vpc_endpoint_service.api-test.service_name
+  subnet_ids         = ["subnet-1234"]
+  tags = {
+    "Name" = "api-vpc-1234.test"
+  }
+  vpc_endpoint_type = "Interface"
+  vpc_id            = "vpc-1234"
+}
+
+terraform {
+  required_version = ">= 0.12.26"
+  required_providers {
+    aws = {
+      "source"  = "hashicorp/aws"
+      "version" = ">= 3.34.0"
+    }
+  }
+}
+`,
+		},
+	}
+
+	doRenderTests(t, "RenderTerraform", cases)
+}
+
+func TestVPCEndpointServiceCloudformationRender(t *testing.T) {
+	cases := []*renderTest{
+		{
+			Resource: testVPCEndpointService(),
+			Expected: `{
+  "Resources": {
+    "AWSEC2VPCEndpointServicePermissionsapitest": {
+      "Type": "AWS::EC2::VPCEndpointServicePermissions",
+      "Properties": {
+        "AllowedPrincipals": [
+          "arn:aws:iam::123456789012:root"
+        ],
+        "ServiceId": {
+          "Ref": "AWSEC2VPCEndpointServiceapitest"
+        }
+      }
+    },
+    "AWSEC2VPCEndpointServiceapitest": {
+      "Type": "AWS::EC2::VPCEndpointService",
+      "Properties": {
+        "AcceptanceRequired": false,
+        "NetworkLoadBalancerArns": [
+          {
+            "Ref": "AWSElasticLoadBalancingV2LoadBalancerapitest"
+          }
+        ]
+      }
+    }
+  }
+}`,
+		},
+		{
+			Resource: testVPCEndpoint(),
+			Expected: `{
+  "Resources": {
+    "AWSEC2VPCEndpointapivpc1234test": {
+      "Type": "AWS::EC2::VPCEndpoint",
+      "Properties": {
+        "VpcId": "vpc-1234",
+        "ServiceName": {
+          "Fn::Join": [
+            "",
+            [
+              "com.amazonaws.vpce.eu-west-2.",
+              {
+                "Ref": "AWSEC2VPCEndpointServiceapitest"
+              }
+            ]
+          ]
+        },
+        "VpcEndpointType": "Interface",
+        "SubnetIds": [
+          "subnet-1234"
+        ],
+        "SecurityGroupIds": [
+          "sg-1234"
+        ]
+      }
+    }
+  }
+}`,
+		},
+	}
+
+	doRenderTests(t, "RenderCloudformation", cases)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awsup
+
+import (
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"k8s.io/kops/pkg/cloudapi"
+)
+
+// ProviderName identifies AWS in the accounting of cloud API calls
+const ProviderName = "aws"
+
+// apiNames maps the AWS service names to the API names used for rate limits,
+// where they differ from the lowercase service name.
+var apiNames = map[string]string{
+	"elasticloadbalancing": "elb",
+}
+
+// apiName returns the API name that calls of the request are accounted and rate limited by.
+func apiName(r *request.Request) string {
+	service := r.ClientInfo.ServiceName
+	if name, ok := apiNames[service]; ok {
+		return name
+	}
+	return strings.ToLower(service)
+}
+
+func operationName(r *request.Request) string {
+	if r.Operation == nil {
+		return "?"
+	}
+	return r.Operation.Name
+}
+
+// waitForRateLimit delays every attempt of a request until it is within the client-side rate limit of its API
+func waitForRateLimit(r *request.Request) {
+	if err := cloudapi.WaitForRateLimit(r.Context(), apiName(r)); err != nil {
+		r.Error = err
+	}
+}
+
+// recordThrottled counts the attempts that AWS rejected because of its rate limits
+func recordThrottled(r *request.Request) {
+	if request.IsErrorThrottle(r.Error) {
+		cloudapi.RecordThrottled(ProviderName, apiName(r), operationName(r))
+	}
+}
+
+// recordCall counts the request once it has completed, successfully or not
+func recordCall(r *request.Request) {
+	cloudapi.RecordCall(ProviderName, apiName(r), operationName(r), time.Since(r.Time), r.RetryCount, r.Error)
+}
+
+// addAccountingHandlers rate limits the requests, and records them for the summary of cloud API calls
+func addAccountingHandlers(h *request.Handlers) {
+	h.Sign.PushFrontNamed(request.NamedHandler{
+		Name: "kops/rate-limit",
+		Fn:   waitForRateLimit,
+	})
+	h.Retry.PushBackNamed(request.NamedHandler{
+		Name: "kops/record-throttled",
+		Fn:   recordThrottled,
+	})
+	h.Complete.PushBackNamed(request.NamedHandler{
+		Name: "kops/record-call",
+		Fn:   recordCall,
+	})
+}
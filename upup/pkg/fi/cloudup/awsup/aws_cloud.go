@@ -382,6 +382,7 @@ func NewAWSCloud(region string, tags map[string]string) (AWSCloud, error) {
 }
 
 func (c *awsCloudImplementation) addHandlers(regionName string, h *request.Handlers) {
+	addAccountingHandlers(h)
 
 	delayer := c.getCrossRequestRetryDelay(regionName)
 	if delayer != nil {
@@ -905,6 +906,9 @@ func buildCloudInstance(i *autoscaling.Instance, instances map[string]*ec2.Insta
 	if err != nil {
 		return fmt.Errorf("error creating cloud instance group member: %v", err)
 	}
+	if status == cloudinstances.CloudInstanceStatusNeedsUpdate {
+		cm.AddNeedUpdateReason(cloudinstances.NeedUpdateReasonConfigurationChanged, fmt.Sprintf("instance was launched from %q, current is %q", currentConfigName, newConfigName))
+	}
 	if strings.HasPrefix(*i.LifecycleState, "Warmed") {
 		cm.State = cloudinstances.WarmPool
 	}
@@ -18,6 +18,7 @@ package awsup
 
 import (
 	"encoding/base32"
+	"errors"
 	"fmt"
 	"hash/fnv"
 	"os"
@@ -28,6 +29,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/ec2"
@@ -186,6 +188,37 @@ func AWSErrorMessage(err error) string {
 	return ""
 }
 
+// throttlingErrorCodes are the error codes AWS APIs return when they rate limit requests
+var throttlingErrorCodes = []string{
+	"EC2ThrottledException",
+	"PriorRequestNotComplete",
+	"RequestLimitExceeded",
+	"RequestThrottled",
+	"RequestThrottledException",
+	"Throttling",
+	"ThrottlingException",
+	"TooManyRequestsException",
+}
+
+// IsThrottlingError reports whether err was returned because an AWS API was rate limiting requests.
+// Tasks often wrap the error as text, so the error code is also looked for in the message.
+func IsThrottlingError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var awsError awserr.Error
+	if errors.As(err, &awsError) {
+		return request.IsErrorThrottle(awsError)
+	}
+	message := err.Error()
+	for _, code := range throttlingErrorCodes {
+		if strings.Contains(message, code+": ") {
+			return true
+		}
+	}
+	return false
+}
+
 // EC2TagSpecification converts a map of tags to an EC2 TagSpecification
 func EC2TagSpecification(resourceType string, tags map[string]string) []*ec2.TagSpecification {
 	if len(tags) == 0 {
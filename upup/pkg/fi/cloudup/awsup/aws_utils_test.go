@@ -17,11 +17,13 @@ limitations under the License.
 package awsup
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"k8s.io/kops/pkg/apis/kops"
 )
@@ -193,3 +195,41 @@ func TestTruncateString(t *testing.T) {
 		})
 	}
 }
+
+func TestIsThrottlingError(t *testing.T) {
+	grid := []struct {
+		err      error
+		expected bool
+	}{
+		{
+			err:      nil,
+			expected: false,
+		},
+		{
+			err:      awserr.New("RequestLimitExceeded", "Request limit exceeded.", nil),
+			expected: true,
+		},
+		{
+			err:      fmt.Errorf("error creating role: %w", awserr.New("Throttling", "Rate exceeded", nil)),
+			expected: true,
+		},
+		{
+			err:      fmt.Errorf("error creating VPC: %v", awserr.New("RequestLimitExceeded", "Request limit exceeded.", nil)),
+			expected: true,
+		},
+		{
+			err:      awserr.New("InvalidVpcID.NotFound", "The vpc ID 'vpc-1' does not exist", nil),
+			expected: false,
+		},
+		{
+			err:      errors.New("error creating VPC: VpcLimitExceeded: The maximum number of VPCs has been reached."),
+			expected: false,
+		},
+	}
+	for _, g := range grid {
+		actual := IsThrottlingError(g.err)
+		if actual != g.expected {
+			t.Errorf("IsThrottlingError(%v): expected %v, got %v", g.err, g.expected, actual)
+		}
+	}
+}
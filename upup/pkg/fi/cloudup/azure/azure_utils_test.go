@@ -61,3 +61,44 @@ func TestZoneToLocation(t *testing.T) {
 		})
 	}
 }
+
+func TestZoneToAvailabilityZoneNumber(t *testing.T) {
+	testCases := []struct {
+		zone    string
+		success bool
+		number  string
+	}{
+		{
+			zone:    "eastus-1",
+			success: true,
+			number:  "1",
+		},
+		{
+			zone:    "eastus",
+			success: false,
+		},
+		{
+			zone:    "eastus-",
+			success: false,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test case %d", i), func(t *testing.T) {
+			n, err := ZoneToAvailabilityZoneNumber(tc.zone)
+			if !tc.success {
+				if err == nil {
+					t.Fatalf("unexpected success")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if n != tc.number {
+				t.Errorf("expected %s but got %s", tc.number, n)
+			}
+		})
+	}
+}
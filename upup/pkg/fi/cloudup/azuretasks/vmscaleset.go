@@ -107,6 +107,19 @@ type VMScaleSet struct {
 	SKUName *string
 	// Capacity specifies the number of virtual machines the VM Scale Set.
 	Capacity *int64
+	// Zones is the list of availability zones (e.g. "1") the virtual machines are spread across.
+	// Zones can only be set when the VM Scale Set is created.
+	Zones []string
+	// zonesUnmanaged is set by Find for a VM Scale Set that was created without zones, whose zones are left as they are.
+	zonesUnmanaged bool
+	// Priority is set to Spot for VM Scale Sets of Spot virtual machines.
+	Priority *string
+	// EvictionPolicy specifies what happens to evicted Spot virtual machines (Delete or Deallocate).
+	EvictionPolicy *string
+	// MaxPrice is the maximum price in US dollars per hour paid for Spot virtual machines, -1 meaning the on-demand price.
+	MaxPrice *float64
+	// AcceleratedNetworking enables SR-IOV accelerated networking on the network interfaces.
+	AcceleratedNetworking *bool
 	// ComputerNamePrefix is the prefix of each VM name of the form <prefix><base-36-instance-id>.
 	// See https://docs.microsoft.com/en-us/azure/virtual-machine-scale-sets/virtual-machine-scale-sets-instance-ids.
 	ComputerNamePrefix *string
@@ -231,6 +244,24 @@ func (s *VMScaleSet) Find(c *fi.Context) (*VMScaleSet, error) {
 			Name: to.StringPtr(loadBalancerID.LoadBalancerName),
 		}
 	}
+	if found.Zones != nil {
+		vmss.Zones = *found.Zones
+	} else if s.Zones != nil {
+		// The VM Scale Set was created without zones, before they were set from the instance group,
+		// and zones can't be added to it, so they are not reconciled.
+		vmss.Zones = s.Zones
+		vmss.zonesUnmanaged = true
+	}
+	if profile.Priority == compute.Spot {
+		vmss.Priority = to.StringPtr(string(profile.Priority))
+		vmss.EvictionPolicy = to.StringPtr(string(profile.EvictionPolicy))
+		if profile.BillingProfile != nil {
+			vmss.MaxPrice = profile.BillingProfile.MaxPrice
+		}
+	}
+	if to.Bool(nwConfig.EnableAcceleratedNetworking) {
+		vmss.AcceleratedNetworking = to.BoolPtr(true)
+	}
 	return vmss, nil
 }
 
@@ -254,6 +285,12 @@ func (s *VMScaleSet) CheckChanges(a, e, changes *VMScaleSet) error {
 	if changes.Name != nil {
 		return fi.CannotChangeField("Name")
 	}
+	if changes.Priority != nil {
+		return fi.CannotChangeField("Priority")
+	}
+	if changes.Zones != nil {
+		return fi.CannotChangeField("Zones")
+	}
 	return nil
 }
 
@@ -330,8 +367,9 @@ func (s *VMScaleSet) RenderAzure(t *azure.AzureAPITarget, a, e, changes *VMScale
 	networkConfig := compute.VirtualMachineScaleSetNetworkConfiguration{
 		Name: to.StringPtr(name + "-netconfig"),
 		VirtualMachineScaleSetNetworkConfigurationProperties: &compute.VirtualMachineScaleSetNetworkConfigurationProperties{
-			Primary:            to.BoolPtr(true),
-			EnableIPForwarding: to.BoolPtr(true),
+			Primary:                     to.BoolPtr(true),
+			EnableIPForwarding:          to.BoolPtr(true),
+			EnableAcceleratedNetworking: e.AcceleratedNetworking,
 			IPConfigurations: &[]compute.VirtualMachineScaleSetIPConfiguration{
 				{
 					Name: to.StringPtr(name + "-ipconfig"),
@@ -341,12 +379,18 @@ func (s *VMScaleSet) RenderAzure(t *azure.AzureAPITarget, a, e, changes *VMScale
 		},
 	}
 
+	zones := e.Zones
+	if a != nil && a.zonesUnmanaged {
+		zones = nil
+	}
+
 	vmss := compute.VirtualMachineScaleSet{
 		Location: to.StringPtr(t.Cloud.Region()),
 		Sku: &compute.Sku{
 			Name:     e.SKUName,
 			Capacity: e.Capacity,
 		},
+		Zones: stringSlicePtr(zones),
 		VirtualMachineScaleSetProperties: &compute.VirtualMachineScaleSetProperties{
 			UpgradePolicy: &compute.UpgradePolicy{
 				Mode: compute.UpgradeModeManual,
@@ -370,6 +414,17 @@ func (s *VMScaleSet) RenderAzure(t *azure.AzureAPITarget, a, e, changes *VMScale
 		Tags: e.Tags,
 	}
 
+	if e.Priority != nil {
+		profile := vmss.VirtualMachineProfile
+		profile.Priority = compute.VirtualMachinePriorityTypes(*e.Priority)
+		profile.EvictionPolicy = compute.VirtualMachineEvictionPolicyTypes(fi.StringValue(e.EvictionPolicy))
+		if e.MaxPrice != nil {
+			profile.BillingProfile = &compute.BillingProfile{
+				MaxPrice: e.MaxPrice,
+			}
+		}
+	}
+
 	result, err := t.Cloud.VMScaleSet().CreateOrUpdate(
 		context.TODO(),
 		*e.ResourceGroup.Name,
@@ -381,3 +436,10 @@ func (s *VMScaleSet) RenderAzure(t *azure.AzureAPITarget, a, e, changes *VMScale
 	e.PrincipalID = result.Identity.PrincipalID
 	return nil
 }
+
+func stringSlicePtr(l []string) *[]string {
+	if len(l) == 0 {
+		return nil
+	}
+	return &l
+}
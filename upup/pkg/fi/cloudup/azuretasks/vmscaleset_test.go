@@ -288,6 +288,47 @@ func TestVMScaleSetFind(t *testing.T) {
 	if !*actual.RequirePublicIP {
 		t.Errorf("unexpected require public IP")
 	}
+
+	// Zones are not reconciled for a VM Scale Set created without zones.
+	vmss.Zones = []string{"1", "2"}
+	actual, err = vmss.Find(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if a, e := actual.Zones, vmss.Zones; !reflect.DeepEqual(a, e) {
+		t.Errorf("unexpected zones: expected %v, but got %v", e, a)
+	}
+	changes := &VMScaleSet{}
+	fi.BuildChanges(actual, vmss, changes)
+	if changes.Zones != nil {
+		t.Errorf("unexpected zones change: %v", changes.Zones)
+	}
+}
+
+func TestVMScaleSetRenderAzureZones(t *testing.T) {
+	cloud := NewMockAzureCloud("eastus")
+	apiTarget := azure.NewAzureAPITarget(cloud)
+	expected := newTestVMScaleSet()
+	expected.Zones = []string{"1", "2"}
+	if err := expected.RenderAzure(apiTarget, nil, expected, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if a, e := cloud.VMScaleSetsClient.VMSSes[*expected.Name].Zones, expected.Zones; a == nil || !reflect.DeepEqual(*a, e) {
+		t.Errorf("unexpected zones: expected %v, but got %v", e, a)
+	}
+
+	// Zones are left out when updating a VM Scale Set created without zones.
+	cloud = NewMockAzureCloud("eastus")
+	apiTarget = azure.NewAzureAPITarget(cloud)
+	actual := newTestVMScaleSet()
+	actual.Zones = expected.Zones
+	actual.zonesUnmanaged = true
+	if err := expected.RenderAzure(apiTarget, actual, expected, &VMScaleSet{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if a := cloud.VMScaleSetsClient.VMSSes[*expected.Name].Zones; a != nil {
+		t.Errorf("unexpected zones: %v", *a)
+	}
 }
 
 func TestVMScaleSetRun(t *testing.T) {
@@ -337,6 +378,11 @@ func TestVMScaleSetCheckChanges(t *testing.T) {
 			changes: &VMScaleSet{Name: to.StringPtr("newName")},
 			success: false,
 		},
+		{
+			a:       &VMScaleSet{Name: to.StringPtr("name"), Zones: []string{"1"}},
+			changes: &VMScaleSet{Zones: []string{"1", "2"}},
+			success: false,
+		},
 	}
 	for i, tc := range testCases {
 		t.Run(fmt.Sprintf("test case %d", i), func(t *testing.T) {
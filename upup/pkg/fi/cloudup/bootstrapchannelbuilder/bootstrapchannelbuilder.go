@@ -31,6 +31,8 @@ import (
 	"k8s.io/kops/pkg/model/components/addonmanifests"
 	"k8s.io/kops/pkg/model/components/addonmanifests/awsloadbalancercontroller"
 	"k8s.io/kops/pkg/model/components/addonmanifests/dnscontroller"
+	"k8s.io/kops/pkg/model/components/addonmanifests/externaldns"
+	"k8s.io/kops/pkg/model/components/addonmanifests/logshipper"
 	"k8s.io/kops/pkg/model/iam"
 	"k8s.io/kops/pkg/templates"
 	"k8s.io/kops/pkg/wellknownoperators"
@@ -280,6 +282,26 @@ func (b *BootstrapChannelBuilder) buildAddons(c *fi.ModelBuilderContext) (*chann
 		}
 	}
 
+	// The ServiceMonitor scraping kops-controller is applied once prometheus-operator is installed
+	{
+		key := "kops-controller-monitoring.addons.k8s.io"
+		version := "1.22.0-alpha.1"
+
+		{
+			location := key + "/k8s-1.16.yaml"
+			id := "k8s-1.16"
+
+			addons.Spec.Addons = append(addons.Spec.Addons, &channelsapi.AddonSpec{
+				Name:      fi.String(key),
+				Version:   fi.String(version),
+				Selector:  map[string]string{"k8s-addon": key},
+				Manifest:  fi.String(location),
+				Id:        id,
+				NeedsCRDs: []string{"servicemonitors.monitoring.coreos.com"},
+			})
+		}
+	}
+
 	{
 		key := "core.addons.k8s.io"
 		version := "1.4.0"
@@ -471,7 +493,7 @@ func (b *BootstrapChannelBuilder) buildAddons(c *fi.ModelBuilderContext) (*chann
 		}
 	}
 
-	if featureflag.EnableExternalDNS.Enabled() {
+	if featureflag.EnableExternalDNS.Enabled() || (externalDNS != nil && externalDNS.Provider == kops.ExternalDNSProviderExternalDNS) {
 		{
 			key := "external-dns.addons.k8s.io"
 			version := "0.7.6-kops.1"
@@ -489,6 +511,23 @@ func (b *BootstrapChannelBuilder) buildAddons(c *fi.ModelBuilderContext) (*chann
 				})
 			}
 		}
+
+		// Generate external-dns ServiceAccount IAM permissions
+		if b.UseServiceAccountIAM() {
+			awsModelContext := &awsmodel.AWSModelContext{
+				KopsModelContext: b.KopsModelContext,
+			}
+
+			serviceAccountRoles := []iam.Subject{&externaldns.ServiceAccount{}}
+			for _, serviceAccountRole := range serviceAccountRoles {
+				iamModelBuilder := &awsmodel.IAMModelBuilder{AWSModelContext: awsModelContext, Lifecycle: b.Lifecycle, Cluster: b.Cluster}
+
+				_, err := iamModelBuilder.BuildServiceAccountRoleTasks(serviceAccountRole, c)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
 	}
 
 	// @check if the node-local-dns is enabled
@@ -554,6 +593,96 @@ func (b *BootstrapChannelBuilder) buildAddons(c *fi.ModelBuilderContext) (*chann
 		}
 	}
 
+	if b.Cluster.Spec.KubeStateMetrics != nil && fi.BoolValue(b.Cluster.Spec.KubeStateMetrics.Enabled) {
+		key := "kube-state-metrics.addons.k8s.io"
+		version := "2.1.1"
+
+		{
+			location := key + "/k8s-1.16.yaml"
+			id := "k8s-1.16"
+
+			addons.Spec.Addons = append(addons.Spec.Addons, &channelsapi.AddonSpec{
+				Name:     fi.String(key),
+				Version:  fi.String(version),
+				Selector: map[string]string{"k8s-addon": key},
+				Manifest: fi.String(location),
+				Id:       id,
+			})
+		}
+	}
+
+	if b.Cluster.Spec.Audit != nil && b.Cluster.Spec.Audit.LogShipping != nil {
+		key := "audit-log-shipper.addons.k8s.io"
+		version := "1.8.3"
+
+		{
+			location := key + "/k8s-1.16.yaml"
+			id := "k8s-1.16"
+
+			addons.Spec.Addons = append(addons.Spec.Addons, &channelsapi.AddonSpec{
+				Name:     fi.String(key),
+				Version:  fi.String(version),
+				Selector: map[string]string{"k8s-addon": key},
+				Manifest: fi.String(location),
+				Id:       id,
+			})
+		}
+	}
+
+	if b.Cluster.Spec.Logging != nil && fi.BoolValue(b.Cluster.Spec.Logging.Enabled) {
+		key := "log-shipper.addons.k8s.io"
+		version := "1.8.3"
+
+		{
+			location := key + "/k8s-1.16.yaml"
+			id := "k8s-1.16"
+
+			addons.Spec.Addons = append(addons.Spec.Addons, &channelsapi.AddonSpec{
+				Name:     fi.String(key),
+				Version:  fi.String(version),
+				Selector: map[string]string{"k8s-addon": key},
+				Manifest: fi.String(location),
+				Id:       id,
+			})
+		}
+
+		// Generate log-shipper ServiceAccount IAM permissions
+		if b.UseServiceAccountIAM() && iam.LoggingUsesAWS(b.Cluster.Spec.Logging) {
+			awsModelContext := &awsmodel.AWSModelContext{
+				KopsModelContext: b.KopsModelContext,
+			}
+
+			serviceAccountRoles := []iam.Subject{&logshipper.ServiceAccount{}}
+			for _, serviceAccountRole := range serviceAccountRoles {
+				iamModelBuilder := &awsmodel.IAMModelBuilder{AWSModelContext: awsModelContext, Lifecycle: b.Lifecycle, Cluster: b.Cluster}
+
+				_, err := iamModelBuilder.BuildServiceAccountRoleTasks(serviceAccountRole, c)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if b.Cluster.Spec.Containerd != nil && b.Cluster.Spec.Containerd.NvidiaGPU.IsEnabled() {
+		key := "nvidia.addons.k8s.io"
+		version := "0.9.0"
+
+		{
+			location := key + "/k8s-1.20.yaml"
+			id := "k8s-1.20"
+
+			addons.Spec.Addons = append(addons.Spec.Addons, &channelsapi.AddonSpec{
+				Name:              fi.String(key),
+				Version:           fi.String(version),
+				Selector:          map[string]string{"k8s-addon": key},
+				Manifest:          fi.String(location),
+				KubernetesVersion: ">=1.20.0",
+				Id:                id,
+			})
+		}
+	}
+
 	if b.Cluster.Spec.CertManager != nil && fi.BoolValue(b.Cluster.Spec.CertManager.Enabled) && (b.Cluster.Spec.CertManager.Managed == nil || fi.BoolValue(b.Cluster.Spec.CertManager.Managed)) {
 		{
 			key := "certmanager.io"
@@ -882,6 +1011,26 @@ func (b *BootstrapChannelBuilder) buildAddons(c *fi.ModelBuilderContext) (*chann
 		return nil, fmt.Errorf("failed to add cilium addon: %w", err)
 	}
 
+	if b.Cluster.Spec.Networking.Antrea != nil {
+		key := "networking.antrea.io"
+		versions := map[string]string{
+			"k8s-1.16": "1.2.0-kops.1",
+		}
+
+		{
+			id := "k8s-1.16"
+			location := key + "/" + id + ".yaml"
+
+			addons.Spec.Addons = append(addons.Spec.Addons, &channelsapi.AddonSpec{
+				Name:     fi.String(key),
+				Version:  fi.String(versions[id]),
+				Selector: networkingSelector(),
+				Manifest: fi.String(location),
+				Id:       id,
+			})
+		}
+	}
+
 	authenticationSelector := map[string]string{"role.kubernetes.io/authentication": "1"}
 
 	if b.Cluster.Spec.Authentication != nil {
@@ -1047,5 +1196,22 @@ func (b *BootstrapChannelBuilder) buildAddons(c *fi.ModelBuilderContext) (*chann
 		})
 	}
 
+	if b.Cluster.Spec.API != nil && b.Cluster.Spec.API.Gateway != nil {
+		key := "api-gateway.addons.k8s.io"
+		version := "0.4.0-kops.1"
+
+		{
+			id := "k8s-1.16"
+			location := key + "/" + id + ".yaml"
+			addons.Spec.Addons = append(addons.Spec.Addons, &channelsapi.AddonSpec{
+				Name:     fi.String(key),
+				Version:  fi.String(version),
+				Selector: map[string]string{"k8s-addon": key},
+				Manifest: fi.String(location),
+				Id:       id,
+			})
+		}
+	}
+
 	return addons, nil
 }
@@ -44,13 +44,20 @@ func TestBootstrapChannelBuilder_BuildTasks(t *testing.T) {
 
 	h.SetupMockAWS()
 
-	runChannelBuilderTest(t, "simple", []string{"kops-controller.addons.k8s.io-k8s-1.16"})
+	runChannelBuilderTest(t, "simple", []string{"kops-controller.addons.k8s.io-k8s-1.16", "kops-controller-monitoring.addons.k8s.io-k8s-1.16"})
 	// Use cilium networking, proxy
 	runChannelBuilderTest(t, "cilium", []string{"kops-controller.addons.k8s.io-k8s-1.16"})
 	runChannelBuilderTest(t, "weave", []string{})
+	runChannelBuilderTest(t, "antrea", []string{"networking.antrea.io-k8s-1.16"})
+	runChannelBuilderTest(t, "nodelocaldns", []string{"nodelocaldns.addons.k8s.io-k8s-1.12"})
+	runChannelBuilderTest(t, "coredns", []string{"coredns.addons.k8s.io-k8s-1.12"})
+	runChannelBuilderTest(t, "api-gateway", []string{"api-gateway.addons.k8s.io-k8s-1.16"})
+	runChannelBuilderTest(t, "external-dns", []string{"external-dns.addons.k8s.io-k8s-1.12"})
 	runChannelBuilderTest(t, "amazonvpc", []string{"networking.amazon-vpc-routed-eni-k8s-1.16"})
 	runChannelBuilderTest(t, "amazonvpc-containerd", []string{"networking.amazon-vpc-routed-eni-k8s-1.16"})
 	runChannelBuilderTest(t, "awsiamauthenticator", []string{"authentication.aws-k8s-1.12"})
+	runChannelBuilderTest(t, "metrics-server", []string{"metrics-server.addons.k8s.io-k8s-1.11", "kube-state-metrics.addons.k8s.io-k8s-1.16"})
+	runChannelBuilderTest(t, "log-shipper", []string{"log-shipper.addons.k8s.io-k8s-1.16"})
 }
 
 func TestBootstrapChannelBuilder_ServiceAccountIAM(t *testing.T) {
@@ -0,0 +1,166 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudformation
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awscloudformation "github.com/aws/aws-sdk-go/service/cloudformation"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+const (
+	driftDetectionPollInterval = 5 * time.Second
+	driftDetectionTimeout      = 5 * time.Minute
+)
+
+// StackName returns the name of the stack that holds the resources of the cluster
+func StackName(clusterName string) string {
+	return "kubernetes-" + strings.Replace(clusterName, ".", "-", -1)
+}
+
+// ChangeSet is a change set that was created for the rendered template; it is only applied once it is executed
+type ChangeSet struct {
+	// StackName is the name of the stack the change set applies to
+	StackName string
+	// ID is the ARN of the change set
+	ID string
+	// Drifted is true if the resources of the stack no longer match its template
+	Drifted bool
+	// Changes are the changes the change set would make; empty if the stack is up to date
+	Changes []*awscloudformation.Change
+}
+
+// createChangeSet creates a change set with the template, rather than updating the stack directly,
+// so that the changes can be reviewed (and any drift reconciled) before they are executed
+func (t *CloudformationTarget) createChangeSet(stackName string, templateBody []byte) (*ChangeSet, error) {
+	cf := t.Cloud.(awsup.AWSCloud).CloudFormation()
+
+	changeSet := &ChangeSet{StackName: stackName}
+
+	changeSetType := awscloudformation.ChangeSetTypeUpdate
+	stacks, err := cf.DescribeStacks(&awscloudformation.DescribeStacksInput{StackName: aws.String(stackName)})
+	if err != nil {
+		if !strings.Contains(err.Error(), "does not exist") {
+			return nil, fmt.Errorf("error describing stack %q: %v", stackName, err)
+		}
+		changeSetType = awscloudformation.ChangeSetTypeCreate
+	} else if len(stacks.Stacks) == 0 {
+		changeSetType = awscloudformation.ChangeSetTypeCreate
+	}
+
+	if changeSetType == awscloudformation.ChangeSetTypeUpdate {
+		drifted, err := detectStackDrift(cf, stackName)
+		if err != nil {
+			return nil, err
+		}
+		changeSet.Drifted = drifted
+	}
+
+	name := "kops-" + time.Now().UTC().Format("20060102-150405")
+	klog.Infof("Creating change set %q for stack %q", name, stackName)
+	response, err := cf.CreateChangeSet(&awscloudformation.CreateChangeSetInput{
+		StackName:     aws.String(stackName),
+		ChangeSetName: aws.String(name),
+		ChangeSetType: aws.String(changeSetType),
+		Capabilities:  aws.StringSlice([]string{awscloudformation.CapabilityCapabilityNamedIam}),
+		TemplateBody:  aws.String(string(templateBody)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating change set for stack %q: %v", stackName, err)
+	}
+	changeSet.ID = aws.StringValue(response.Id)
+
+	describe := &awscloudformation.DescribeChangeSetInput{ChangeSetName: response.Id}
+	if err := cf.WaitUntilChangeSetCreateComplete(describe); err != nil {
+		// A change set without changes fails; that just means the stack is up to date
+		status, describeErr := cf.DescribeChangeSet(describe)
+		if describeErr == nil && strings.Contains(aws.StringValue(status.StatusReason), "didn't contain changes") {
+			return changeSet, nil
+		}
+		return nil, fmt.Errorf("error waiting for change set %q: %v", name, err)
+	}
+
+	for {
+		status, err := cf.DescribeChangeSet(describe)
+		if err != nil {
+			return nil, fmt.Errorf("error describing change set %q: %v", name, err)
+		}
+		changeSet.Changes = append(changeSet.Changes, status.Changes...)
+		if status.NextToken == nil {
+			break
+		}
+		describe.NextToken = status.NextToken
+	}
+
+	return changeSet, nil
+}
+
+// detectStackDrift returns true if the resources of the stack were changed outside of CloudFormation
+func detectStackDrift(cf *awscloudformation.CloudFormation, stackName string) (bool, error) {
+	klog.Infof("Detecting drift of stack %q", stackName)
+	detection, err := cf.DetectStackDrift(&awscloudformation.DetectStackDriftInput{StackName: aws.String(stackName)})
+	if err != nil {
+		return false, fmt.Errorf("error detecting drift of stack %q: %v", stackName, err)
+	}
+
+	drifted := false
+	err = wait.PollImmediate(driftDetectionPollInterval, driftDetectionTimeout, func() (bool, error) {
+		status, err := cf.DescribeStackDriftDetectionStatus(&awscloudformation.DescribeStackDriftDetectionStatusInput{
+			StackDriftDetectionId: detection.StackDriftDetectionId,
+		})
+		if err != nil {
+			return false, fmt.Errorf("error checking drift of stack %q: %v", stackName, err)
+		}
+		switch aws.StringValue(status.DetectionStatus) {
+		case awscloudformation.StackDriftDetectionStatusDetectionInProgress:
+			return false, nil
+		case awscloudformation.StackDriftDetectionStatusDetectionFailed:
+			return false, fmt.Errorf("drift detection of stack %q failed: %s", stackName, aws.StringValue(status.DetectionStatusReason))
+		}
+		drifted = aws.StringValue(status.StackDriftStatus) == awscloudformation.StackDriftStatusDrifted
+		return true, nil
+	})
+	return drifted, err
+}
+
+// String describes the changes of the change set, one resource per line
+func (c *ChangeSet) String() string {
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "ACTION\tRESOURCE\tTYPE\tREPLACEMENT\n")
+	for _, change := range c.Changes {
+		rc := change.ResourceChange
+		if rc == nil {
+			continue
+		}
+		replacement := aws.StringValue(rc.Replacement)
+		if replacement == "" {
+			replacement = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", aws.StringValue(rc.Action), aws.StringValue(rc.LogicalResourceId), aws.StringValue(rc.ResourceType), replacement)
+	}
+	w.Flush()
+	return b.String()
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudformation
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awscloudformation "github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+func TestStackName(t *testing.T) {
+	if actual := StackName("minimal.example.com"); actual != "kubernetes-minimal-example-com" {
+		t.Errorf("unexpected stack name %q", actual)
+	}
+}
+
+func TestChangeSetString(t *testing.T) {
+	changeSet := &ChangeSet{
+		Changes: []*awscloudformation.Change{
+			{
+				ResourceChange: &awscloudformation.ResourceChange{
+					Action:            aws.String("Add"),
+					LogicalResourceId: aws.String("AWSEC2VPCminimalexamplecom"),
+					ResourceType:      aws.String("AWS::EC2::VPC"),
+				},
+			},
+			{
+				ResourceChange: &awscloudformation.ResourceChange{
+					Action:            aws.String("Modify"),
+					LogicalResourceId: aws.String("AWSEC2LaunchTemplatenodesminimalexamplecom"),
+					ResourceType:      aws.String("AWS::EC2::LaunchTemplate"),
+					Replacement:       aws.String("False"),
+				},
+			},
+		},
+	}
+
+	expected := "ACTION  RESOURCE                                    TYPE                      REPLACEMENT\n" +
+		"Add     AWSEC2VPCminimalexamplecom                  AWS::EC2::VPC             -\n" +
+		"Modify  AWSEC2LaunchTemplatenodesminimalexamplecom  AWS::EC2::LaunchTemplate  False\n"
+	if actual := changeSet.String(); actual != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", actual, expected)
+	}
+}
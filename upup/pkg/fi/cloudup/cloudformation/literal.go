@@ -71,6 +71,13 @@ func LiteralString(v string) *Literal {
 	return &Literal{json: j}
 }
 
+// Join concatenates the values, which may be strings or literals, with the delimiter
+func Join(delimiter string, values ...interface{}) *Literal {
+	j := make(map[string]interface{})
+	j["Fn::Join"] = []interface{}{delimiter, values}
+	return &Literal{json: j}
+}
+
 //
 //func LiteralSelfLink(resourceType, resourceName string) *Literal {
 //	return LiteralProperty(resourceType, resourceName, "self_link")
@@ -35,6 +35,11 @@ type CloudformationTarget struct {
 
 	outDir string
 
+	// ChangeSetStackName is the stack to create a change set for, with the rendered template; no change set is created if empty
+	ChangeSetStackName string
+	// ChangeSet is the change set that was created (output)
+	ChangeSet *ChangeSet
+
 	// mutex protects the following items (resources & files)
 	mutex     sync.Mutex
 	resources map[string]*cloudformationResource
@@ -168,5 +173,12 @@ func (t *CloudformationTarget) Finish(taskMap map[string]fi.Task) error {
 
 	klog.Infof("Cloudformation output is in %s", t.outDir)
 
+	if t.ChangeSetStackName != "" {
+		t.ChangeSet, err = t.createChangeSet(t.ChangeSetStackName, jsonBytes)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,39 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crossplane
+
+// Reference refers to another managed resource by its name
+type Reference struct {
+	Name string `json:"name"`
+}
+
+// Literal is the value of a field that identifies another resource: either the ID of a resource
+// that is not managed by kOps, or a reference that Crossplane resolves once the managed resource exists.
+type Literal struct {
+	Value *string
+	Ref   *Reference
+}
+
+// LiteralFromStringValue identifies an existing resource by its ID
+func LiteralFromStringValue(s string) *Literal {
+	return &Literal{Value: &s}
+}
+
+// LiteralRef identifies the managed resource with the given name
+func LiteralRef(name string) *Literal {
+	return &Literal{Ref: &Reference{Name: sanitizeName(name)}}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crossplane
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"sigs.k8s.io/yaml"
+)
+
+// AnnotationExternalName is the annotation that sets the name of the cloud resource backing a managed resource
+const AnnotationExternalName = "crossplane.io/external-name"
+
+// CrossplaneTarget renders the tasks as Crossplane managed resources, to be reconciled by the Crossplane AWS provider
+type CrossplaneTarget struct {
+	Cloud   fi.Cloud
+	Project string
+
+	outDir string
+
+	// mutex protects the following items (resources)
+	mutex     sync.Mutex
+	resources map[string]*crossplaneResource
+}
+
+func NewCrossplaneTarget(cloud fi.Cloud, project string, outDir string) *CrossplaneTarget {
+	return &CrossplaneTarget{
+		Cloud:     cloud,
+		Project:   project,
+		outDir:    outDir,
+		resources: make(map[string]*crossplaneResource),
+	}
+}
+
+var _ fi.Target = &CrossplaneTarget{}
+
+type crossplaneResource struct {
+	APIVersion   string
+	Kind         string
+	Name         string
+	ExternalName string
+	ForProvider  interface{}
+}
+
+// A managed resource name must be a valid DNS subdomain
+func sanitizeName(name string) string {
+	name = strings.ToLower(name)
+	name = strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '.' {
+			return r
+		}
+		return '-'
+	}, name)
+	return strings.Trim(name, "-.")
+}
+
+func (t *CrossplaneTarget) ProcessDeletions() bool {
+	// Crossplane only reconciles the resources that are applied; deleted resources must be removed from the cluster
+	return false
+}
+
+// RenderResource adds a managed resource, with forProvider holding its parameters
+func (t *CrossplaneTarget) RenderResource(apiVersion string, kind string, name string, forProvider interface{}) error {
+	return t.RenderNamedResource(apiVersion, kind, name, "", forProvider)
+}
+
+// RenderNamedResource adds a managed resource whose cloud resource is named externalName
+func (t *CrossplaneTarget) RenderNamedResource(apiVersion string, kind string, name string, externalName string, forProvider interface{}) error {
+	res := &crossplaneResource{
+		APIVersion:   apiVersion,
+		Kind:         kind,
+		Name:         sanitizeName(name),
+		ExternalName: externalName,
+		ForProvider:  forProvider,
+	}
+
+	key := apiVersion + "/" + kind + "/" + res.Name
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.resources[key] != nil {
+		return fmt.Errorf("duplicate resource found: %s %s", kind, res.Name)
+	}
+	t.resources[key] = res
+
+	return nil
+}
+
+func (t *CrossplaneTarget) Finish(taskMap map[string]fi.Task) error {
+	keys := make([]string, 0, len(t.resources))
+	for k := range t.resources {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var objects []string
+	for _, k := range keys {
+		res := t.resources[k]
+
+		metadata := map[string]interface{}{
+			"name": res.Name,
+		}
+		if res.ExternalName != "" {
+			metadata["annotations"] = map[string]string{
+				AnnotationExternalName: res.ExternalName,
+			}
+		}
+
+		obj := map[string]interface{}{
+			"apiVersion": res.APIVersion,
+			"kind":       res.Kind,
+			"metadata":   metadata,
+			"spec": map[string]interface{}{
+				"forProvider": res.ForProvider,
+			},
+		}
+
+		b, err := yaml.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("error marshaling %s %s to yaml: %v", res.Kind, res.Name, err)
+		}
+		objects = append(objects, string(b))
+	}
+
+	p := path.Join(t.outDir, "kubernetes.yaml")
+
+	if err := os.MkdirAll(t.outDir, os.FileMode(0755)); err != nil {
+		return fmt.Errorf("error creating output directory %q: %v", t.outDir, err)
+	}
+
+	if err := ioutil.WriteFile(p, []byte(strings.Join(objects, "---\n")), os.FileMode(0644)); err != nil {
+		return fmt.Errorf("error writing crossplane data to output file %q: %v", p, err)
+	}
+
+	klog.Infof("Crossplane output is in %s", t.outDir)
+
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crossplane
+
+import (
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"k8s.io/kops/pkg/diff"
+)
+
+type testSubnet struct {
+	VPCIDRef *Reference `json:"vpcIdRef,omitempty"`
+}
+
+func TestFinish(t *testing.T) {
+	outDir := t.TempDir()
+	target := NewCrossplaneTarget(nil, "", outDir)
+
+	if err := target.RenderResource("ec2.aws.upbound.io/v1beta1", "Subnet", "us-test-1a.minimal.example.com", &testSubnet{VPCIDRef: LiteralRef("minimal.example.com").Ref}); err != nil {
+		t.Fatalf("error rendering subnet: %v", err)
+	}
+	if err := target.RenderNamedResource("iam.aws.upbound.io/v1beta1", "Role", "masters.minimal.example.com", "masters.minimal.example.com", map[string]string{}); err != nil {
+		t.Fatalf("error rendering role: %v", err)
+	}
+	if err := target.RenderResource("ec2.aws.upbound.io/v1beta1", "Subnet", "us-test-1a.minimal.example.com", &testSubnet{}); err == nil {
+		t.Errorf("expected an error rendering a duplicate subnet")
+	}
+
+	if err := target.Finish(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `apiVersion: ec2.aws.upbound.io/v1beta1
+kind: Subnet
+metadata:
+  name: us-test-1a.minimal.example.com
+spec:
+  forProvider:
+    vpcIdRef:
+      name: minimal.example.com
+---
+apiVersion: iam.aws.upbound.io/v1beta1
+kind: Role
+metadata:
+  annotations:
+    crossplane.io/external-name: masters.minimal.example.com
+  name: masters.minimal.example.com
+spec:
+  forProvider: {}
+`
+	actual, err := ioutil.ReadFile(path.Join(outDir, "kubernetes.yaml"))
+	if err != nil {
+		t.Fatalf("error reading output: %v", err)
+	}
+	if string(actual) != expected {
+		t.Errorf("unexpected output:\n%s", diff.FormatDiff(expected, string(actual)))
+	}
+}
+
+func TestSanitizeName(t *testing.T) {
+	grid := map[string]string{
+		"minimal.example.com":                           "minimal.example.com",
+		"kubernetes.minimal.example.com-ab:cd:ef":       "kubernetes.minimal.example.com-ab-cd-ef",
+		"from-0.0.0.0/0-ingress-tcp-22to22-masters-Foo": "from-0.0.0.0-0-ingress-tcp-22to22-masters-foo",
+		"-leading.and.trailing.":                        "leading.and.trailing",
+	}
+	for name, expected := range grid {
+		if actual := sanitizeName(name); actual != expected {
+			t.Errorf("sanitizeName(%q) = %q, expected %q", name, actual, expected)
+		}
+	}
+}
@@ -171,8 +171,20 @@ func assignProxy(cluster *kops.Cluster) (*kops.EgressProxySpec, error) {
 	if egressProxy != nil {
 
 		var egressSlice []string
+		excluded := make(map[string]bool)
+		addExclude := func(exclude string) {
+			exclude = strings.TrimSpace(exclude)
+			if exclude == "" || excluded[exclude] {
+				return
+			}
+			excluded[exclude] = true
+			egressSlice = append(egressSlice, exclude)
+		}
+
 		if egressProxy.ProxyExcludes != "" {
-			egressSlice = strings.Split(egressProxy.ProxyExcludes, ",")
+			for _, exclude := range strings.Split(egressProxy.ProxyExcludes, ",") {
+				addExclude(exclude)
+			}
 		}
 
 		ip, _, err := net.ParseCIDR(cluster.Spec.NonMasqueradeCIDR)
@@ -195,29 +207,35 @@ func assignProxy(cluster *kops.Cluster) (*kops.EgressProxySpec, error) {
 			firstIP,
 			cluster.Spec.NonMasqueradeCIDR,
 		} {
-			if exclude == "" {
-				continue
-			}
-			if !strings.Contains(egressProxy.ProxyExcludes, exclude) {
-				egressSlice = append(egressSlice, exclude)
-			}
+			addExclude(exclude)
 		}
 
-		awsNoProxy := "169.254.169.254"
-
-		if cluster.Spec.CloudProvider == "aws" && !strings.Contains(cluster.Spec.EgressProxy.ProxyExcludes, awsNoProxy) {
-			egressSlice = append(egressSlice, awsNoProxy)
-		}
+		// the metadata endpoint is link-local on all clouds
+		addExclude("169.254.169.254")
 
 		// the kube-apiserver will need to talk to kubelets on their node IP addresses port 10250
 		// for pod logs to be available via the api
 		if cluster.Spec.NetworkCIDR != "" {
-			if !strings.Contains(cluster.Spec.EgressProxy.ProxyExcludes, cluster.Spec.NetworkCIDR) {
-				egressSlice = append(egressSlice, cluster.Spec.NetworkCIDR)
-			}
+			addExclude(cluster.Spec.NetworkCIDR)
 		} else {
 			klog.Warningf("No NetworkCIDR defined (yet), not adding to egressProxy.excludes")
 		}
+		for _, cidr := range cluster.Spec.AdditionalNetworkCIDRs {
+			addExclude(cidr)
+		}
+
+		if kops.CloudProviderID(cluster.Spec.CloudProvider) == kops.CloudProviderGCE {
+			addExclude("metadata.google.internal")
+		}
+
+		// in-cluster traffic never goes through the proxy
+		addExclude(cluster.Spec.MasterInternalName)
+		addExclude(cluster.Spec.ServiceClusterIPRange)
+		addExclude(cluster.Spec.PodCIDR)
+		if cluster.Spec.KubeControllerManager != nil {
+			addExclude(cluster.Spec.KubeControllerManager.ClusterCIDR)
+		}
+		addExclude(".svc")
 
 		egressProxy.ProxyExcludes = strings.Join(egressSlice, ",")
 		klog.V(8).Infof("Completed setting up Proxy excludes as follows: %q", egressProxy.ProxyExcludes)
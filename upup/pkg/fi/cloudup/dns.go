@@ -234,7 +234,10 @@ func buildPrecreateDNSHostnames(cluster *kops.Cluster) []string {
 
 	var dnsHostnames []string
 
-	if cluster.Spec.MasterPublicName != "" {
+	if cluster.Spec.API != nil && cluster.Spec.API.Gateway != nil {
+		// The record of the public name points to the gateway, and is not managed by kops
+		klog.V(2).Infof("not pre-creating MasterPublicName %q, as it is published through a gateway", cluster.Spec.MasterPublicName)
+	} else if cluster.Spec.MasterPublicName != "" {
 		dnsHostnames = append(dnsHostnames, cluster.Spec.MasterPublicName)
 	} else {
 		klog.Warningf("cannot pre-create MasterPublicName - not set")
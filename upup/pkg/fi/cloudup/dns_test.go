@@ -68,3 +68,23 @@ func TestPrecreateDNSNames(t *testing.T) {
 		t.Fatalf("unexpected records.  expected=%v actual=%v", expected, actual)
 	}
 }
+
+func TestPrecreateDNSNamesGateway(t *testing.T) {
+	cluster := &kops.Cluster{}
+	cluster.ObjectMeta.Name = "cluster1.example.com"
+	cluster.Spec.MasterPublicName = "api." + cluster.ObjectMeta.Name
+	cluster.Spec.MasterInternalName = "api.internal." + cluster.ObjectMeta.Name
+	cluster.Spec.API = &kops.AccessSpec{
+		Gateway: &kops.GatewayAccessSpec{Name: "public"},
+	}
+
+	actual := buildPrecreateDNSHostnames(cluster)
+
+	expected := []string{
+		"api.internal.cluster1.example.com",
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("unexpected records.  expected=%v actual=%v", expected, actual)
+	}
+}
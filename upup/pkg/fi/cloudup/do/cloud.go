@@ -64,6 +64,7 @@ type DOCloud interface {
 	VolumeService() godo.StorageService
 	VolumeActionService() godo.StorageActionsService
 	LoadBalancersService() godo.LoadBalancersService
+	VPCsService() godo.VPCsService
 	DomainService() godo.DomainsService
 	ActionsService() godo.ActionsService
 	FindClusterStatus(cluster *kops.Cluster) (*kops.ClusterStatus, error)
@@ -209,6 +210,10 @@ func (c *doCloudImplementation) LoadBalancersService() godo.LoadBalancersService
 	return c.Client.LoadBalancers
 }
 
+func (c *doCloudImplementation) VPCsService() godo.VPCsService {
+	return c.Client.VPCs
+}
+
 func (c *doCloudImplementation) DomainService() godo.DomainsService {
 	return c.Client.Domains
 }
@@ -217,9 +222,13 @@ func (c *doCloudImplementation) ActionsService() godo.ActionsService {
 	return c.Client.Actions
 }
 
-// FindVPCInfo is not implemented, it's only here to satisfy the fi.Cloud interface
+// FindVPCInfo returns the IP range of the VPC with the given id
 func (c *doCloudImplementation) FindVPCInfo(id string) (*fi.VPCInfo, error) {
-	return nil, errors.New("not implemented")
+	vpc, _, err := c.VPCsService().Get(context.TODO(), id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting VPC %q: %v", id, err)
+	}
+	return &fi.VPCInfo{CIDR: vpc.IPRange}, nil
 }
 
 func (c *doCloudImplementation) GetApiIngressStatus(cluster *kops.Cluster) ([]fi.ApiIngressStatus, error) {
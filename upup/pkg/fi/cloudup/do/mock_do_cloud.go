@@ -107,6 +107,10 @@ func (c *doCloudMockImplementation) LoadBalancersService() godo.LoadBalancersSer
 	return c.Client.LoadBalancers
 }
 
+func (c *doCloudMockImplementation) VPCsService() godo.VPCsService {
+	return c.Client.VPCs
+}
+
 func (c *doCloudMockImplementation) DomainService() godo.DomainsService {
 	return c.Client.Domains
 }
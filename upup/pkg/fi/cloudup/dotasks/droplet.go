@@ -42,6 +42,7 @@ type Droplet struct {
 	Tags     []string
 	Count    int
 	UserData fi.Resource
+	VPC      *VPC
 }
 
 var _ fi.Task = &Droplet{}
@@ -74,7 +75,7 @@ func (d *Droplet) Find(c *fi.Context) (*Droplet, error) {
 		return nil, nil
 	}
 
-	return &Droplet{
+	actual := &Droplet{
 		Name:      fi.String(foundDroplet.Name),
 		Count:     count,
 		Region:    fi.String(foundDroplet.Region.Slug),
@@ -84,7 +85,13 @@ func (d *Droplet) Find(c *fi.Context) (*Droplet, error) {
 		SSHKey:    d.SSHKey,   // TODO: get from droplet or ignore change
 		UserData:  d.UserData, // TODO: get from droplet or ignore change
 		Lifecycle: d.Lifecycle,
-	}, nil
+	}
+
+	if d.VPC != nil && foundDroplet.VPCUUID != "" {
+		actual.VPC = &VPC{ID: fi.String(foundDroplet.VPCUUID)}
+	}
+
+	return actual, nil
 }
 
 func listDroplets(cloud do.DOCloud) ([]godo.Droplet, error) {
@@ -124,6 +131,11 @@ func (_ *Droplet) RenderDO(t *do.DOAPITarget, a, e, changes *Droplet) error {
 		return err
 	}
 
+	var vpcUUID string
+	if e.VPC != nil {
+		vpcUUID = fi.StringValue(e.VPC.ID)
+	}
+
 	var newDropletCount int
 	if a == nil {
 		newDropletCount = e.Count
@@ -132,6 +144,10 @@ func (_ *Droplet) RenderDO(t *do.DOAPITarget, a, e, changes *Droplet) error {
 		expectedCount := e.Count
 		actualCount := a.Count
 
+		if changes.VPC != nil {
+			klog.Warningf("existing droplets %q cannot be moved to VPC %q; only new droplets will be placed in it", fi.StringValue(e.Name), vpcUUID)
+		}
+
 		if expectedCount == actualCount {
 			return nil
 		}
@@ -150,6 +166,7 @@ func (_ *Droplet) RenderDO(t *do.DOAPITarget, a, e, changes *Droplet) error {
 			Size:              fi.StringValue(e.Size),
 			Image:             godo.DropletCreateImage{Slug: fi.StringValue(e.Image)},
 			PrivateNetworking: true,
+			VPCUUID:           vpcUUID,
 			Tags:              e.Tags,
 			UserData:          userData,
 			SSHKeys:           []godo.DropletCreateSSHKey{{Fingerprint: fi.StringValue(e.SSHKey)}},
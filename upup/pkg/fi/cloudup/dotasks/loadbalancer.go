@@ -41,6 +41,7 @@ type LoadBalancer struct {
 	DropletTag   *string
 	IPAddress    *string
 	ForAPIServer bool
+	VPC          *VPC
 }
 
 var _ fi.CompareWithID = &LoadBalancer{}
@@ -52,28 +53,54 @@ func (lb *LoadBalancer) CompareWithID() *string {
 
 func (lb *LoadBalancer) Find(c *fi.Context) (*LoadBalancer, error) {
 	klog.V(10).Infof("load balancer FIND - ID=%s, name=%s", fi.StringValue(lb.ID), fi.StringValue(lb.Name))
-	if fi.StringValue(lb.ID) == "" {
-		// Loadbalancer = nil if not found
-		return nil, nil
-	}
 
 	cloud := c.Cloud.(do.DOCloud)
-	lbService := cloud.LoadBalancersService()
-	loadbalancer, _, err := lbService.Get(context.TODO(), fi.StringValue(lb.ID))
 
-	if err != nil {
-		return nil, fmt.Errorf("load balancer service get request returned error %v", err)
+	var loadbalancer *godo.LoadBalancer
+	if fi.StringValue(lb.ID) != "" {
+		found, _, err := cloud.LoadBalancersService().Get(context.TODO(), fi.StringValue(lb.ID))
+		if err != nil {
+			return nil, fmt.Errorf("load balancer service get request returned error %v", err)
+		}
+		loadbalancer = found
+	} else {
+		loadBalancers, err := cloud.GetAllLoadBalancers()
+		if err != nil {
+			return nil, fmt.Errorf("LoadBalancers.List returned error: %v", err)
+		}
+		for i := range loadBalancers {
+			if loadBalancers[i].Name == fi.StringValue(lb.Name) {
+				loadbalancer = &loadBalancers[i]
+				break
+			}
+		}
 	}
 
-	return &LoadBalancer{
-		Name:   fi.String(loadbalancer.Name),
-		ID:     fi.String(loadbalancer.ID),
-		Region: fi.String(loadbalancer.Region.Slug),
+	if loadbalancer == nil {
+		// Loadbalancer = nil if not found
+		return nil, nil
+	}
+
+	actual := &LoadBalancer{
+		Name:       fi.String(loadbalancer.Name),
+		ID:         fi.String(loadbalancer.ID),
+		Region:     fi.String(loadbalancer.Region.Slug),
+		DropletTag: fi.String(loadbalancer.Tag),
+		IPAddress:  fi.String(loadbalancer.IP),
 
 		// Ignore system fields
 		Lifecycle:    lb.Lifecycle,
 		ForAPIServer: lb.ForAPIServer,
-	}, nil
+	}
+
+	if lb.VPC != nil && loadbalancer.VPCUUID != "" {
+		actual.VPC = &VPC{ID: fi.String(loadbalancer.VPCUUID)}
+	}
+
+	lb.ID = actual.ID
+	lb.IPAddress = fi.String(loadbalancer.IP)
+
+	return actual, nil
 }
 
 func (lb *LoadBalancer) Run(c *fi.Context) error {
@@ -91,6 +118,10 @@ func (_ *LoadBalancer) CheckChanges(a, e, changes *LoadBalancer) error {
 		if changes.Region != nil {
 			return fi.CannotChangeField("Region")
 		}
+		// DigitalOcean cannot move a load balancer to another VPC
+		if changes.VPC != nil {
+			return fi.CannotChangeField("VPC")
+		}
 	} else {
 		if e.Name == nil {
 			return fi.RequiredField("Name")
@@ -103,6 +134,11 @@ func (_ *LoadBalancer) CheckChanges(a, e, changes *LoadBalancer) error {
 }
 
 func (_ *LoadBalancer) RenderDO(t *do.DOAPITarget, a, e, changes *LoadBalancer) error {
+	if a != nil {
+		// TODO: Support updating the droplet tag, forwarding rules and health check
+		return nil
+	}
+
 	Rules := []godo.ForwardingRule{
 		{
 			EntryProtocol:  "https",
@@ -129,21 +165,9 @@ func (_ *LoadBalancer) RenderDO(t *do.DOAPITarget, a, e, changes *LoadBalancer)
 		HealthyThreshold:       5,
 	}
 
-	// check if load balancer exist.
-	loadBalancers, err := t.Cloud.GetAllLoadBalancers()
-
-	if err != nil {
-		return fmt.Errorf("LoadBalancers.List returned error: %v", err)
-	}
-
-	for _, loadbalancer := range loadBalancers {
-		klog.V(10).Infof("load balancer retrieved=%s, e.Name=%s", loadbalancer.Name, fi.StringValue(e.Name))
-		if strings.Contains(loadbalancer.Name, fi.StringValue(e.Name)) {
-			// load balancer already exists.
-			e.ID = fi.String(loadbalancer.ID)
-			e.IPAddress = fi.String(loadbalancer.IP) // This will be empty on create, but will be filled later on FindIPAddress invokation.
-			return nil
-		}
+	var vpcUUID string
+	if e.VPC != nil {
+		vpcUUID = fi.StringValue(e.VPC.ID)
 	}
 
 	// load balancer doesn't exist. Create one.
@@ -156,6 +180,7 @@ func (_ *LoadBalancer) RenderDO(t *do.DOAPITarget, a, e, changes *LoadBalancer)
 		Tag:             fi.StringValue(e.DropletTag),
 		ForwardingRules: Rules,
 		HealthCheck:     HealthCheck,
+		VPCUUID:         vpcUUID,
 	})
 
 	if err != nil {
@@ -0,0 +1,209 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dotasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/digitalocean/godo"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/do"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
+)
+
+// VPC represents a DigitalOcean VPC that droplets and load balancers are placed in
+// +kops:fitask
+type VPC struct {
+	Name      *string
+	ID        *string
+	Lifecycle fi.Lifecycle
+
+	Region  *string
+	IPRange *string
+
+	// Shared is set if this is a shared VPC, which kOps references but does not manage
+	Shared *bool
+}
+
+var _ fi.CompareWithID = &VPC{}
+
+func (v *VPC) CompareWithID() *string {
+	return v.ID
+}
+
+func (v *VPC) Find(c *fi.Context) (*VPC, error) {
+	cloud := c.Cloud.(do.DOCloud)
+	vpcService := cloud.VPCsService()
+
+	var vpc *godo.VPC
+	if fi.StringValue(v.ID) != "" {
+		found, _, err := vpcService.Get(context.TODO(), fi.StringValue(v.ID))
+		if err != nil {
+			return nil, fmt.Errorf("error getting VPC %q: %v", fi.StringValue(v.ID), err)
+		}
+		vpc = found
+	} else {
+		vpcs, err := listVPCs(cloud)
+		if err != nil {
+			return nil, err
+		}
+		for _, found := range vpcs {
+			if found.Name == fi.StringValue(v.Name) {
+				vpc = found
+				break
+			}
+		}
+	}
+
+	if vpc == nil {
+		// VPC = nil if not found
+		return nil, nil
+	}
+
+	actual := &VPC{
+		Name:    fi.String(vpc.Name),
+		ID:      fi.String(vpc.ID),
+		Region:  fi.String(vpc.RegionSlug),
+		IPRange: fi.String(vpc.IPRange),
+
+		// Ignore system fields
+		Lifecycle: v.Lifecycle,
+		Shared:    v.Shared,
+	}
+
+	// The IP range is allocated by DigitalOcean when not specified
+	if v.IPRange == nil {
+		actual.IPRange = nil
+	}
+
+	// Shared VPCs are referenced by ID, and we don't care about their name
+	if fi.BoolValue(v.Shared) {
+		actual.Name = v.Name
+	}
+
+	v.ID = actual.ID
+
+	return actual, nil
+}
+
+func listVPCs(cloud do.DOCloud) ([]*godo.VPC, error) {
+	allVPCs := []*godo.VPC{}
+
+	opt := &godo.ListOptions{}
+	for {
+		vpcs, resp, err := cloud.VPCsService().List(context.TODO(), opt)
+		if err != nil {
+			return nil, fmt.Errorf("error listing VPCs: %v", err)
+		}
+
+		allVPCs = append(allVPCs, vpcs...)
+
+		if resp.Links == nil || resp.Links.IsLastPage() {
+			break
+		}
+
+		page, err := resp.Links.CurrentPage()
+		if err != nil {
+			return nil, err
+		}
+
+		opt.Page = page + 1
+	}
+
+	return allVPCs, nil
+}
+
+func (v *VPC) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(v, c)
+}
+
+func (_ *VPC) CheckChanges(a, e, changes *VPC) error {
+	if a != nil {
+		if changes.Name != nil {
+			return fi.CannotChangeField("Name")
+		}
+		if changes.ID != nil {
+			return fi.CannotChangeField("ID")
+		}
+		if changes.Region != nil {
+			return fi.CannotChangeField("Region")
+		}
+		if changes.IPRange != nil {
+			return fi.CannotChangeField("IPRange")
+		}
+	} else {
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		if e.Region == nil {
+			return fi.RequiredField("Region")
+		}
+	}
+	return nil
+}
+
+func (_ *VPC) RenderDO(t *do.DOAPITarget, a, e, changes *VPC) error {
+	if fi.BoolValue(e.Shared) {
+		if a == nil {
+			return fmt.Errorf("VPC with id %q not found", fi.StringValue(e.ID))
+		}
+		return nil
+	}
+
+	if a != nil {
+		return nil
+	}
+
+	klog.V(2).Infof("Creating VPC with Name:%q", fi.StringValue(e.Name))
+
+	vpc, _, err := t.Cloud.VPCsService().Create(context.TODO(), &godo.VPCCreateRequest{
+		Name:       fi.StringValue(e.Name),
+		RegionSlug: fi.StringValue(e.Region),
+		IPRange:    fi.StringValue(e.IPRange),
+	})
+	if err != nil {
+		return fmt.Errorf("error creating VPC: %v", err)
+	}
+
+	e.ID = fi.String(vpc.ID)
+
+	return nil
+}
+
+// terraformVPC represents the digitalocean_vpc resource in terraform
+// https://registry.terraform.io/providers/digitalocean/digitalocean/latest/docs/resources/vpc
+type terraformVPC struct {
+	Name    *string `json:"name" cty:"name"`
+	Region  *string `json:"region" cty:"region"`
+	IPRange *string `json:"ip_range,omitempty" cty:"ip_range"`
+}
+
+func (_ *VPC) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *VPC) error {
+	if fi.BoolValue(e.Shared) {
+		return nil
+	}
+
+	tf := &terraformVPC{
+		Name:    e.Name,
+		Region:  e.Region,
+		IPRange: e.IPRange,
+	}
+	return t.RenderResource("digitalocean_vpc", *e.Name, tf)
+}
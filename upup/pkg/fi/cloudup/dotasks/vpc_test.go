@@ -0,0 +1,137 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dotasks
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/digitalocean/godo"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/do"
+)
+
+type fakeVPCClient struct {
+	godo.VPCsService
+
+	vpcs []*godo.VPC
+}
+
+func (f fakeVPCClient) Get(ctx context.Context, id string) (*godo.VPC, *godo.Response, error) {
+	for _, vpc := range f.vpcs {
+		if vpc.ID == id {
+			return vpc, nil, nil
+		}
+	}
+	return nil, nil, &godo.ErrorResponse{Message: "not found"}
+}
+
+func (f fakeVPCClient) List(ctx context.Context, opt *godo.ListOptions) ([]*godo.VPC, *godo.Response, error) {
+	return f.vpcs, &godo.Response{}, nil
+}
+
+func Test_VPCFind(t *testing.T) {
+	vpcs := []*godo.VPC{
+		{
+			ID:         "default-id",
+			Name:       "default-nyc1",
+			RegionSlug: "nyc1",
+			IPRange:    "10.116.0.0/20",
+		},
+		{
+			ID:         "cluster-id",
+			Name:       "vpc-test-k8s-local",
+			RegionSlug: "nyc1",
+			IPRange:    "10.10.0.0/16",
+		},
+	}
+
+	testcases := []struct {
+		name   string
+		inVPC  *VPC
+		outVPC *VPC
+	}{
+		{
+			"found by name",
+			&VPC{
+				Name:    fi.String("vpc-test-k8s-local"),
+				Region:  fi.String("nyc1"),
+				IPRange: fi.String("10.10.0.0/16"),
+			},
+			&VPC{
+				Name:    fi.String("vpc-test-k8s-local"),
+				ID:      fi.String("cluster-id"),
+				Region:  fi.String("nyc1"),
+				IPRange: fi.String("10.10.0.0/16"),
+			},
+		},
+		{
+			"allocated ip range is ignored",
+			&VPC{
+				Name:   fi.String("vpc-test-k8s-local"),
+				Region: fi.String("nyc1"),
+			},
+			&VPC{
+				Name:   fi.String("vpc-test-k8s-local"),
+				ID:     fi.String("cluster-id"),
+				Region: fi.String("nyc1"),
+			},
+		},
+		{
+			"shared vpc found by id",
+			&VPC{
+				Name:   fi.String("vpc-test-k8s-local"),
+				ID:     fi.String("default-id"),
+				Region: fi.String("nyc1"),
+				Shared: fi.Bool(true),
+			},
+			&VPC{
+				Name:   fi.String("vpc-test-k8s-local"),
+				ID:     fi.String("default-id"),
+				Region: fi.String("nyc1"),
+				Shared: fi.Bool(true),
+			},
+		},
+		{
+			"no vpc found",
+			&VPC{
+				Name:   fi.String("vpc-other-k8s-local"),
+				Region: fi.String("nyc1"),
+			},
+			nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cloud := do.BuildMockDOCloud("nyc1")
+			cloud.Client.VPCs = fakeVPCClient{vpcs: vpcs}
+			ctx := newContext(cloud)
+
+			actualVPC, err := tc.inVPC.Find(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actualVPC, tc.outVPC) {
+				t.Error("unexpected vpc")
+				t.Logf("actual vpc: %v", actualVPC)
+				t.Logf("expected vpc: %v", tc.outVPC)
+			}
+		})
+	}
+}
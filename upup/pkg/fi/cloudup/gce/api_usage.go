@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	compute "google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+	"k8s.io/kops/pkg/cloudapi"
+)
+
+// ProviderName identifies GCE in the accounting of cloud API calls
+const ProviderName = "gce"
+
+// accountingTransport rate limits the requests to the GCE APIs, and records them for the summary of cloud API calls
+type accountingTransport struct {
+	base http.RoundTripper
+}
+
+var _ http.RoundTripper = &accountingTransport{}
+
+// apiClientOptions returns the options for building API clients whose requests are rate limited and accounted for.
+func apiClientOptions(ctx context.Context) ([]option.ClientOption, error) {
+	transport, err := htransport.NewTransport(ctx, &accountingTransport{base: http.DefaultTransport}, option.WithScopes(compute.CloudPlatformScope))
+	if err != nil {
+		return nil, fmt.Errorf("error building GCE API transport: %v", err)
+	}
+	return []option.ClientOption{option.WithHTTPClient(&http.Client{Transport: transport})}, nil
+}
+
+func (t *accountingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	api := apiName(req)
+	operation := req.Method + " " + collectionName(req.URL.EscapedPath())
+
+	if err := cloudapi.WaitForRateLimit(req.Context(), api); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	callErr := err
+	if resp != nil {
+		if resp.StatusCode == http.StatusTooManyRequests {
+			cloudapi.RecordThrottled(ProviderName, api, operation)
+		}
+		// Not found is the expected answer when looking for a resource that has not been created yet
+		if resp.StatusCode >= 400 && resp.StatusCode != http.StatusNotFound {
+			callErr = fmt.Errorf("%s", resp.Status)
+		}
+	}
+	cloudapi.RecordCall(ProviderName, api, operation, time.Since(start), 0, callErr)
+
+	return resp, err
+}
+
+// apiName returns the name of the API from the host of the request, such as compute for compute.googleapis.com,
+// or from the first element of the path for the APIs served by www.googleapis.com.
+func apiName(req *http.Request) string {
+	host := req.URL.Hostname()
+	if name := strings.TrimSuffix(host, ".googleapis.com"); name != host && name != "www" {
+		return name
+	}
+	if tokens := strings.Split(strings.TrimPrefix(req.URL.Path, "/"), "/"); tokens[0] != "" {
+		return tokens[0]
+	}
+	return host
+}
+
+var apiVersion = regexp.MustCompile(`^v[0-9]+[a-z0-9]*$`)
+
+// collectionName returns the type of resource that a request is for, such as instances for
+// /compute/v1/projects/p/zones/z/instances/i, from the elements that alternate with resource names after the version.
+func collectionName(path string) string {
+	tokens := strings.Split(strings.Trim(path, "/"), "/")
+	for i, token := range tokens {
+		if apiVersion.MatchString(token) {
+			tokens = tokens[i+1:]
+			break
+		}
+	}
+
+	collection := "?"
+	for i := 0; i < len(tokens); i += 2 {
+		if tokens[i] != "" {
+			collection = tokens[i]
+		}
+	}
+	return collection
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestAPIUsageNames(t *testing.T) {
+	grid := []struct {
+		url        string
+		api        string
+		collection string
+	}{
+		{
+			url:        "https://compute.googleapis.com/compute/v1/projects/p/zones/us-test1-a/instances/master-1",
+			api:        "compute",
+			collection: "instances",
+		},
+		{
+			url:        "https://compute.googleapis.com/compute/v1/projects/p/zones/us-test1-a/instances/master-1/setMetadata",
+			api:        "compute",
+			collection: "setMetadata",
+		},
+		{
+			url:        "https://www.googleapis.com/storage/v1/b/bucket/o/cluster%2Fconfig",
+			api:        "storage",
+			collection: "o",
+		},
+		{
+			url:        "https://iam.googleapis.com/v1/projects/p/serviceAccounts",
+			api:        "iam",
+			collection: "serviceAccounts",
+		},
+	}
+	for _, g := range grid {
+		req, err := http.NewRequest(http.MethodGet, g.url, nil)
+		if err != nil {
+			t.Fatalf("error building request for %q: %v", g.url, err)
+		}
+		if api := apiName(req); api != g.api {
+			t.Errorf("unexpected API for %q: got %q, expected %q", g.url, api, g.api)
+		}
+		if collection := collectionName(req.URL.EscapedPath()); collection != g.collection {
+			t.Errorf("unexpected collection for %q: got %q, expected %q", g.url, collection, g.collection)
+		}
+	}
+}
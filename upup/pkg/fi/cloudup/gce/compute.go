@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	compute "google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
 )
 
 type ComputeClient interface {
@@ -32,6 +33,8 @@ type ComputeClient interface {
 	Subnetworks() SubnetworkClient
 	Routes() RouteClient
 	ForwardingRules() ForwardingRuleClient
+	RegionBackendServices() RegionBackendServiceClient
+	HealthChecks() HealthCheckClient
 	Addresses() AddressClient
 	Firewalls() FirewallClient
 	Routers() RouterClient
@@ -39,6 +42,7 @@ type ComputeClient interface {
 	Instances() InstanceClient
 	InstanceTemplates() InstanceTemplateClient
 	InstanceGroupManagers() InstanceGroupManagerClient
+	Autoscalers() AutoscalerClient
 	TargetPools() TargetPoolClient
 
 	Disks() DiskClient
@@ -50,8 +54,8 @@ type computeClientImpl struct {
 
 var _ ComputeClient = &computeClientImpl{}
 
-func newComputeClientImpl(ctx context.Context) (*computeClientImpl, error) {
-	srv, err := compute.NewService(ctx)
+func newComputeClientImpl(ctx context.Context, opts ...option.ClientOption) (*computeClientImpl, error) {
+	srv, err := compute.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("error building compute API client: %v", err)
 	}
@@ -102,6 +106,18 @@ func (c *computeClientImpl) ForwardingRules() ForwardingRuleClient {
 	}
 }
 
+func (c *computeClientImpl) RegionBackendServices() RegionBackendServiceClient {
+	return &regionBackendServiceClientImpl{
+		srv: c.srv.RegionBackendServices,
+	}
+}
+
+func (c *computeClientImpl) HealthChecks() HealthCheckClient {
+	return &healthCheckClientImpl{
+		srv: c.srv.HealthChecks,
+	}
+}
+
 func (c *computeClientImpl) Addresses() AddressClient {
 	return &addressClientImpl{
 		srv: c.srv.Addresses,
@@ -138,6 +154,12 @@ func (c *computeClientImpl) InstanceGroupManagers() InstanceGroupManagerClient {
 	}
 }
 
+func (c *computeClientImpl) Autoscalers() AutoscalerClient {
+	return &autoscalerClientImpl{
+		srv: c.srv.Autoscalers,
+	}
+}
+
 func (c *computeClientImpl) TargetPools() TargetPoolClient {
 	return &targetPoolClientImpl{
 		srv: c.srv.TargetPools,
@@ -330,6 +352,83 @@ func (c *forwardingRuleClientImpl) List(ctx context.Context, project, region str
 	return frs, nil
 }
 
+type RegionBackendServiceClient interface {
+	Insert(project, region string, bs *compute.BackendService) (*compute.Operation, error)
+	Update(project, region, name string, bs *compute.BackendService) (*compute.Operation, error)
+	Delete(project, region, name string) (*compute.Operation, error)
+	Get(project, region, name string) (*compute.BackendService, error)
+	List(ctx context.Context, project, region string) ([]*compute.BackendService, error)
+}
+
+type regionBackendServiceClientImpl struct {
+	srv *compute.RegionBackendServicesService
+}
+
+var _ RegionBackendServiceClient = &regionBackendServiceClientImpl{}
+
+func (c *regionBackendServiceClientImpl) Insert(project, region string, bs *compute.BackendService) (*compute.Operation, error) {
+	return c.srv.Insert(project, region, bs).Do()
+}
+
+func (c *regionBackendServiceClientImpl) Update(project, region, name string, bs *compute.BackendService) (*compute.Operation, error) {
+	return c.srv.Update(project, region, name, bs).Do()
+}
+
+func (c *regionBackendServiceClientImpl) Delete(project, region, name string) (*compute.Operation, error) {
+	return c.srv.Delete(project, region, name).Do()
+}
+
+func (c *regionBackendServiceClientImpl) Get(project, region, name string) (*compute.BackendService, error) {
+	return c.srv.Get(project, region, name).Do()
+}
+
+func (c *regionBackendServiceClientImpl) List(ctx context.Context, project, region string) ([]*compute.BackendService, error) {
+	var bss []*compute.BackendService
+	if err := c.srv.List(project, region).Pages(ctx, func(p *compute.BackendServiceList) error {
+		bss = append(bss, p.Items...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return bss, nil
+}
+
+type HealthCheckClient interface {
+	Insert(project string, hc *compute.HealthCheck) (*compute.Operation, error)
+	Delete(project, name string) (*compute.Operation, error)
+	Get(project, name string) (*compute.HealthCheck, error)
+	List(ctx context.Context, project string) ([]*compute.HealthCheck, error)
+}
+
+type healthCheckClientImpl struct {
+	srv *compute.HealthChecksService
+}
+
+var _ HealthCheckClient = &healthCheckClientImpl{}
+
+func (c *healthCheckClientImpl) Insert(project string, hc *compute.HealthCheck) (*compute.Operation, error) {
+	return c.srv.Insert(project, hc).Do()
+}
+
+func (c *healthCheckClientImpl) Delete(project, name string) (*compute.Operation, error) {
+	return c.srv.Delete(project, name).Do()
+}
+
+func (c *healthCheckClientImpl) Get(project, name string) (*compute.HealthCheck, error) {
+	return c.srv.Get(project, name).Do()
+}
+
+func (c *healthCheckClientImpl) List(ctx context.Context, project string) ([]*compute.HealthCheck, error) {
+	var hcs []*compute.HealthCheck
+	if err := c.srv.List(project).Pages(ctx, func(p *compute.HealthCheckList) error {
+		hcs = append(hcs, p.Items...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return hcs, nil
+}
+
 type AddressClient interface {
 	Insert(project, region string, addr *compute.Address) (*compute.Operation, error)
 	Delete(project, region, name string) (*compute.Operation, error)
@@ -604,6 +703,47 @@ func (c *instanceGroupManagerClientImpl) Resize(project, zone, name string, newS
 	return c.srv.Resize(project, zone, name, newSize).Do()
 }
 
+type AutoscalerClient interface {
+	Insert(project, zone string, a *compute.Autoscaler) (*compute.Operation, error)
+	Update(project, zone string, a *compute.Autoscaler) (*compute.Operation, error)
+	Delete(project, zone, name string) (*compute.Operation, error)
+	Get(project, zone, name string) (*compute.Autoscaler, error)
+	List(ctx context.Context, project, zone string) ([]*compute.Autoscaler, error)
+}
+
+type autoscalerClientImpl struct {
+	srv *compute.AutoscalersService
+}
+
+var _ AutoscalerClient = &autoscalerClientImpl{}
+
+func (c *autoscalerClientImpl) Insert(project, zone string, a *compute.Autoscaler) (*compute.Operation, error) {
+	return c.srv.Insert(project, zone, a).Do()
+}
+
+func (c *autoscalerClientImpl) Update(project, zone string, a *compute.Autoscaler) (*compute.Operation, error) {
+	return c.srv.Update(project, zone, a).Autoscaler(a.Name).Do()
+}
+
+func (c *autoscalerClientImpl) Delete(project, zone, name string) (*compute.Operation, error) {
+	return c.srv.Delete(project, zone, name).Do()
+}
+
+func (c *autoscalerClientImpl) Get(project, zone, name string) (*compute.Autoscaler, error) {
+	return c.srv.Get(project, zone, name).Do()
+}
+
+func (c *autoscalerClientImpl) List(ctx context.Context, project, zone string) ([]*compute.Autoscaler, error) {
+	var autoscalers []*compute.Autoscaler
+	if err := c.srv.List(project, zone).Pages(ctx, func(page *compute.AutoscalerList) error {
+		autoscalers = append(autoscalers, page.Items...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return autoscalers, nil
+}
+
 type TargetPoolClient interface {
 	Insert(project, region string, tp *compute.TargetPool) (*compute.Operation, error)
 	Delete(project, region, name string) (*compute.Operation, error)
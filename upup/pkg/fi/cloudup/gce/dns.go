@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	dns "google.golang.org/api/dns/v1"
+	"google.golang.org/api/option"
 )
 
 type DNSClient interface {
@@ -35,8 +36,8 @@ type dnsClientImpl struct {
 
 var _ DNSClient = &dnsClientImpl{}
 
-func newDNSClientImpl(ctx context.Context) (*dnsClientImpl, error) {
-	srv, err := dns.NewService(ctx)
+func newDNSClientImpl(ctx context.Context, opts ...option.ClientOption) (*dnsClientImpl, error) {
+	srv, err := dns.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("error building DNS API client: %v", err)
 	}
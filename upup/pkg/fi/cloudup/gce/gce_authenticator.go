@@ -0,0 +1,62 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+
+	"cloud.google.com/go/compute/metadata"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+const GCEAuthenticationTokenPrefix = "x-gce-identity "
+
+type gceAuthenticator struct {
+	clusterName string
+}
+
+var _ fi.Authenticator = &gceAuthenticator{}
+
+// NewGCEAuthenticator returns an authenticator that proves the identity of the instance
+// to the kops-controller of the cluster with an identity token signed by Google.
+func NewGCEAuthenticator(clusterName string) (fi.Authenticator, error) {
+	if !metadata.OnGCE() {
+		return nil, fmt.Errorf("GCE metadata server is not available")
+	}
+	return &gceAuthenticator{
+		clusterName: clusterName,
+	}, nil
+}
+
+func (a *gceAuthenticator) CreateToken(body []byte) (string, error) {
+	// The identity token only signs its audience, so the audience includes a hash of the body content
+	audience := bootstrapAudience(a.clusterName, body)
+	token, err := metadata.Get("instance/service-accounts/default/identity?format=full&audience=" + url.QueryEscape(audience))
+	if err != nil {
+		return "", fmt.Errorf("getting identity token from GCE metadata: %v", err)
+	}
+	return GCEAuthenticationTokenPrefix + token, nil
+}
+
+// bootstrapAudience returns the audience of the identity token for a bootstrap request to the kops-controller of the cluster.
+func bootstrapAudience(clusterName string, body []byte) string {
+	sha := sha256.Sum256(body)
+	return "kops-controller.internal." + clusterName + "/" + base64.RawURLEncoding.EncodeToString(sha[:])
+}
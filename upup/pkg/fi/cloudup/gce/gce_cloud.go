@@ -121,25 +121,30 @@ func NewGCECloud(region string, project string, labels map[string]string) (GCECl
 		klog.Infof("Will load GOOGLE_APPLICATION_CREDENTIALS from %s", os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"))
 	}
 
-	computeClient, err := newComputeClientImpl(ctx)
+	opts, err := apiClientOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	computeClient, err := newComputeClientImpl(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("error building compute API client: %v", err)
 	}
 	c.compute = computeClient
 
-	storageService, err := storage.NewService(ctx)
+	storageService, err := storage.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("error building storage API client: %v", err)
 	}
 	c.storage = storageService
 
-	iamService, err := iam.NewService(ctx)
+	iamService, err := iam.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("error building IAM API client: %v", err)
 	}
 	c.iam = iamService
 
-	dnsClient, err := newDNSClientImpl(ctx)
+	dnsClient, err := newDNSClientImpl(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("error building DNS API client: %v", err)
 	}
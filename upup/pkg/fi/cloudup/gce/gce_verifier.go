@@ -0,0 +1,205 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	compute "google.golang.org/api/compute/v1"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+	nodeidentitygce "k8s.io/kops/pkg/nodeidentity/gce"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+const (
+	// googleIssuer is the issuer of the identity tokens of GCE instances
+	googleIssuer = "https://accounts.google.com"
+	// googleKeysURL serves the keys that Google signs identity tokens with
+	googleKeysURL = "https://www.googleapis.com/oauth2/v3/certs"
+)
+
+type GCEVerifierOptions struct {
+	// ProjectID is the GCP project that nodes are permitted to run in.
+	ProjectID string `json:"projectID"`
+	// ClusterName is the name of the cluster, which is the audience of the identity tokens of its nodes.
+	ClusterName string `json:"clusterName"`
+}
+
+type gceVerifier struct {
+	opt     GCEVerifierOptions
+	compute *compute.Service
+	client  http.Client
+
+	// mutex guards the signing keys, which are fetched when a token is signed with an unknown key
+	mutex       sync.Mutex
+	keys        jose.JSONWebKeySet
+	keysFetched time.Time
+}
+
+var _ fi.Verifier = &gceVerifier{}
+
+// identityClaims are the claims about the instance in a GCE identity token
+type identityClaims struct {
+	Google struct {
+		ComputeEngine struct {
+			ProjectID    string `json:"project_id"`
+			Zone         string `json:"zone"`
+			InstanceID   string `json:"instance_id"`
+			InstanceName string `json:"instance_name"`
+		} `json:"compute_engine"`
+	} `json:"google"`
+}
+
+func NewGCEVerifier(opt *GCEVerifierOptions) (fi.Verifier, error) {
+	computeService, err := compute.NewService(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("error building compute API client: %v", err)
+	}
+
+	return &gceVerifier{
+		opt:     *opt,
+		compute: computeService,
+		client: http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}, nil
+}
+
+func (v *gceVerifier) VerifyToken(token string, body []byte) (*fi.VerifyResult, error) {
+	if !strings.HasPrefix(token, GCEAuthenticationTokenPrefix) {
+		return nil, fmt.Errorf("incorrect authorization type")
+	}
+	token = strings.TrimPrefix(token, GCEAuthenticationTokenPrefix)
+
+	claims, err := verifyIdentityToken(token, bootstrapAudience(v.opt.ClusterName, body), time.Now(), v.key)
+	if err != nil {
+		return nil, err
+	}
+	instanceClaims := claims.Google.ComputeEngine
+	if instanceClaims.ProjectID != v.opt.ProjectID {
+		return nil, fmt.Errorf("instance %q is in project %q, not %q", instanceClaims.InstanceName, instanceClaims.ProjectID, v.opt.ProjectID)
+	}
+
+	// The instance group is read from the managed instance group of the instance, which can't be changed from the instance
+	info, err := nodeidentitygce.IdentifyInstance(v.compute, v.opt.ProjectID, instanceClaims.Zone, instanceClaims.InstanceName)
+	if err != nil {
+		return nil, err
+	}
+	// Instance names can be reused, instance IDs can't
+	if strconv.FormatUint(info.Instance.Id, 10) != instanceClaims.InstanceID {
+		return nil, fmt.Errorf("instance %q has id %d, not %s", instanceClaims.InstanceName, info.Instance.Id, instanceClaims.InstanceID)
+	}
+	if info.ClusterName != v.opt.ClusterName {
+		return nil, fmt.Errorf("instance %q is not a member of cluster %q", instanceClaims.InstanceName, v.opt.ClusterName)
+	}
+
+	return &fi.VerifyResult{
+		NodeName:          info.Instance.Name,
+		InstanceGroupName: info.InstanceGroup,
+	}, nil
+}
+
+// verifyIdentityToken verifies the signature of a GCE identity token and that it is valid for the audience, returning its claims.
+func verifyIdentityToken(token string, audience string, now time.Time, keyFn func(keyID string) (*jose.JSONWebKey, error)) (*identityClaims, error) {
+	parsed, err := jwt.ParseSigned(token)
+	if err != nil {
+		return nil, fmt.Errorf("parsing identity token: %v", err)
+	}
+	if len(parsed.Headers) != 1 {
+		return nil, fmt.Errorf("identity token has %d signatures", len(parsed.Headers))
+	}
+	if parsed.Headers[0].Algorithm != string(jose.RS256) {
+		return nil, fmt.Errorf("identity token has unexpected algorithm %q", parsed.Headers[0].Algorithm)
+	}
+
+	key, err := keyFn(parsed.Headers[0].KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	standardClaims := jwt.Claims{}
+	claims := &identityClaims{}
+	if err := parsed.Claims(key, &standardClaims, claims); err != nil {
+		return nil, fmt.Errorf("verifying identity token: %v", err)
+	}
+
+	err = standardClaims.ValidateWithLeeway(jwt.Expected{
+		Issuer:   googleIssuer,
+		Audience: jwt.Audience{audience},
+		Time:     now,
+	}, time.Minute)
+	if err != nil {
+		return nil, fmt.Errorf("validating identity token: %v", err)
+	}
+
+	if claims.Google.ComputeEngine.InstanceName == "" {
+		return nil, fmt.Errorf("identity token does not identify an instance; request it with format=full")
+	}
+	return claims, nil
+}
+
+// key returns the Google signing key with the given ID, fetching the current keys if it is unknown.
+func (v *gceVerifier) key(keyID string) (*jose.JSONWebKey, error) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	keys := v.keys.Key(keyID)
+	// Google rotates its keys regularly; we limit how often unknown keys make us fetch them
+	if len(keys) == 0 && time.Since(v.keysFetched) > time.Minute {
+		if err := v.fetchKeys(); err != nil {
+			return nil, err
+		}
+		keys = v.keys.Key(keyID)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("identity token is signed with unknown key %q", keyID)
+	}
+	return &keys[0], nil
+}
+
+func (v *gceVerifier) fetchKeys() error {
+	response, err := v.client.Get(googleKeysURL)
+	if err != nil {
+		return fmt.Errorf("fetching Google signing keys: %v", err)
+	}
+	defer response.Body.Close()
+
+	b, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("reading Google signing keys: %v", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("received status code %d fetching Google signing keys: %s", response.StatusCode, string(b))
+	}
+
+	keys := jose.JSONWebKeySet{}
+	if err := json.Unmarshal(b, &keys); err != nil {
+		return fmt.Errorf("decoding Google signing keys: %v", err)
+	}
+	v.keys = keys
+	v.keysFetched = time.Now()
+	return nil
+}
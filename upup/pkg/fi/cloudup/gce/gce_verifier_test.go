@@ -0,0 +1,139 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+func TestVerifyIdentityToken(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: privateKey}, (&jose.SignerOptions{}).WithHeader("kid", "key-1"))
+	if err != nil {
+		t.Fatalf("error building signer: %v", err)
+	}
+	publicKey := &jose.JSONWebKey{Key: &privateKey.PublicKey, KeyID: "key-1", Algorithm: string(jose.RS256)}
+	keyFn := func(keyID string) (*jose.JSONWebKey, error) {
+		if keyID != "key-1" {
+			return nil, fmt.Errorf("unknown key %q", keyID)
+		}
+		return publicKey, nil
+	}
+
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	body := []byte(`{"apiVersion":"bootstrap.kops.k8s.io/v1alpha1"}`)
+	audience := bootstrapAudience("minimal.example.com", body)
+
+	instance := &identityClaims{}
+	instance.Google.ComputeEngine.ProjectID = "testproject"
+	instance.Google.ComputeEngine.Zone = "us-test1-a"
+	instance.Google.ComputeEngine.InstanceID = "1234"
+	instance.Google.ComputeEngine.InstanceName = "nodes-abcd"
+
+	grid := []struct {
+		Description   string
+		Claims        jwt.Claims
+		Instance      *identityClaims
+		Audience      string
+		ExpectedError string
+	}{
+		{
+			Description: "valid",
+			Claims:      jwt.Claims{Issuer: googleIssuer, Audience: jwt.Audience{audience}, Expiry: jwt.NewNumericDate(now.Add(time.Hour))},
+			Instance:    instance,
+			Audience:    audience,
+		},
+		{
+			Description:   "other body",
+			Claims:        jwt.Claims{Issuer: googleIssuer, Audience: jwt.Audience{bootstrapAudience("minimal.example.com", []byte("{}"))}, Expiry: jwt.NewNumericDate(now.Add(time.Hour))},
+			Instance:      instance,
+			Audience:      audience,
+			ExpectedError: "invalid audience",
+		},
+		{
+			Description:   "other issuer",
+			Claims:        jwt.Claims{Issuer: "https://example.com", Audience: jwt.Audience{audience}, Expiry: jwt.NewNumericDate(now.Add(time.Hour))},
+			Instance:      instance,
+			Audience:      audience,
+			ExpectedError: "invalid issuer",
+		},
+		{
+			Description:   "expired",
+			Claims:        jwt.Claims{Issuer: googleIssuer, Audience: jwt.Audience{audience}, Expiry: jwt.NewNumericDate(now.Add(-time.Hour))},
+			Instance:      instance,
+			Audience:      audience,
+			ExpectedError: "expired",
+		},
+		{
+			Description:   "no instance",
+			Claims:        jwt.Claims{Issuer: googleIssuer, Audience: jwt.Audience{audience}, Expiry: jwt.NewNumericDate(now.Add(time.Hour))},
+			Instance:      &identityClaims{},
+			Audience:      audience,
+			ExpectedError: "does not identify an instance",
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.Description, func(t *testing.T) {
+			token, err := jwt.Signed(signer).Claims(g.Claims).Claims(g.Instance).CompactSerialize()
+			if err != nil {
+				t.Fatalf("error signing token: %v", err)
+			}
+
+			claims, err := verifyIdentityToken(token, g.Audience, now, keyFn)
+			if g.ExpectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), g.ExpectedError) {
+					t.Fatalf("expected error containing %q, got %v", g.ExpectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if claims.Google.ComputeEngine != instance.Google.ComputeEngine {
+				t.Errorf("unexpected claims %+v", claims.Google.ComputeEngine)
+			}
+		})
+	}
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	otherSigner, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: otherKey}, (&jose.SignerOptions{}).WithHeader("kid", "key-1"))
+	if err != nil {
+		t.Fatalf("error building signer: %v", err)
+	}
+	forged, err := jwt.Signed(otherSigner).Claims(grid[0].Claims).Claims(instance).CompactSerialize()
+	if err != nil {
+		t.Fatalf("error signing token: %v", err)
+	}
+	if _, err := verifyIdentityToken(forged, audience, now, keyFn); err == nil {
+		t.Errorf("expected error verifying token signed with another key")
+	}
+}
@@ -44,6 +44,26 @@ func DeleteInstanceGroupManager(c GCECloud, t *compute.InstanceGroupManager) err
 	return c.WaitForOp(op)
 }
 
+// DeleteAutoscaler deletes the specified Autoscaler in GCE
+func DeleteAutoscaler(c GCECloud, t *compute.Autoscaler) error {
+	klog.V(2).Infof("Deleting GCE Autoscaler %s", t.SelfLink)
+	u, err := ParseGoogleCloudURL(t.SelfLink)
+	if err != nil {
+		return err
+	}
+
+	op, err := c.Compute().Autoscalers().Delete(u.Project, u.Zone, u.Name)
+	if err != nil {
+		if IsNotFound(err) {
+			klog.Infof("Autoscaler not found, assuming deleted: %q", t.SelfLink)
+			return nil
+		}
+		return fmt.Errorf("error deleting Autoscaler %s: %v", t.SelfLink, err)
+	}
+
+	return c.WaitForOp(op)
+}
+
 // DeleteInstanceTemplate deletes the specified InstanceTemplate (by URL) in GCE
 func DeleteInstanceTemplate(c GCECloud, selfLink string) error {
 	klog.V(2).Infof("Deleting GCE InstanceTemplate %s", selfLink)
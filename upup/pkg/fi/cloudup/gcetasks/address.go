@@ -34,6 +34,10 @@ type Address struct {
 
 	IPAddress    *string
 	ForAPIServer bool
+
+	// AddressType is INTERNAL for addresses reserved within Subnetwork, and EXTERNAL (the default) otherwise.
+	AddressType *string
+	Subnetwork  *Subnet
 }
 
 var _ fi.CompareWithID = &ForwardingRule{}
@@ -90,6 +94,12 @@ func (e *Address) find(cloud gce.GCECloud) (*Address, error) {
 	actual := &Address{}
 	actual.IPAddress = &r.Address
 	actual.Name = &r.Name
+	if r.AddressType == "INTERNAL" {
+		actual.AddressType = &r.AddressType
+	}
+	if r.Subnetwork != "" {
+		actual.Subnetwork = &Subnet{Name: fi.String(lastComponent(r.Subnetwork))}
+	}
 
 	return actual, nil
 }
@@ -123,6 +133,9 @@ func (_ *Address) CheckChanges(a, e, changes *Address) error {
 		if changes.IPAddress != nil {
 			return fi.CannotChangeField("Address")
 		}
+		if changes.AddressType != nil {
+			return fi.CannotChangeField("AddressType")
+		}
 	}
 	return nil
 }
@@ -133,6 +146,11 @@ func (_ *Address) RenderGCE(t *gce.GCEAPITarget, a, e, changes *Address) error {
 		Name:    *e.Name,
 		Address: fi.StringValue(e.IPAddress),
 		Region:  cloud.Region(),
+
+		AddressType: fi.StringValue(e.AddressType),
+	}
+	if e.Subnetwork != nil {
+		addr.Subnetwork = e.Subnetwork.URL(cloud.Project(), cloud.Region())
 	}
 
 	if a == nil {
@@ -154,12 +172,18 @@ func (_ *Address) RenderGCE(t *gce.GCEAPITarget, a, e, changes *Address) error {
 }
 
 type terraformAddress struct {
-	Name *string `json:"name,omitempty" cty:"name"`
+	Name        *string                  `json:"name,omitempty" cty:"name"`
+	AddressType *string                  `json:"address_type,omitempty" cty:"address_type"`
+	Subnetwork  *terraformWriter.Literal `json:"subnetwork,omitempty" cty:"subnetwork"`
 }
 
 func (_ *Address) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *Address) error {
 	tf := &terraformAddress{
-		Name: e.Name,
+		Name:        e.Name,
+		AddressType: e.AddressType,
+	}
+	if e.Subnetwork != nil {
+		tf.Subnetwork = e.Subnetwork.TerraformName()
 	}
 	return t.RenderResource("google_compute_address", *e.Name, tf)
 }
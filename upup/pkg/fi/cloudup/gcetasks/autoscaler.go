@@ -0,0 +1,253 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcetasks
+
+import (
+	"fmt"
+
+	compute "google.golang.org/api/compute/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
+	"k8s.io/kops/util/pkg/maps"
+)
+
+// autoscalerDescription marks the autoscalers managed by kOps; other autoscalers are left alone.
+const autoscalerDescription = "Managed by kOps"
+
+// Autoscaler is the autoscaler of an InstanceGroupManager, used for the scaling schedules of the instance group.
+// +kops:fitask
+type Autoscaler struct {
+	Name      *string
+	Lifecycle fi.Lifecycle
+
+	Zone                 *string
+	InstanceGroupManager *InstanceGroupManager
+
+	// Enabled is false if kOps should not manage an autoscaler for the InstanceGroupManager.
+	Enabled *bool
+
+	MinReplicas *int64
+	MaxReplicas *int64
+	// ScalingSchedules are the scaling schedules of the autoscaler, keyed by name.
+	ScalingSchedules map[string]*AutoscalerScalingSchedule
+}
+
+// AutoscalerScalingSchedule raises the minimum size of the InstanceGroupManager at recurring times.
+type AutoscalerScalingSchedule struct {
+	Schedule            *string
+	TimeZone            *string
+	DurationSec         *int64
+	MinRequiredReplicas *int64
+}
+
+var _ fi.HasDependencies = &AutoscalerScalingSchedule{}
+
+// GetDependencies returns the dependencies of the scaling schedule; it has none.
+func (*AutoscalerScalingSchedule) GetDependencies(tasks map[string]fi.Task) []fi.Task {
+	return nil
+}
+
+var _ fi.CompareWithID = &Autoscaler{}
+
+func (e *Autoscaler) CompareWithID() *string {
+	return e.Name
+}
+
+func (e *Autoscaler) Find(c *fi.Context) (*Autoscaler, error) {
+	cloud := c.Cloud.(gce.GCECloud)
+
+	actual := &Autoscaler{
+		Name:                 e.Name,
+		Lifecycle:            e.Lifecycle,
+		Zone:                 e.Zone,
+		InstanceGroupManager: e.InstanceGroupManager,
+		Enabled:              fi.Bool(false),
+	}
+
+	r, err := cloud.Compute().Autoscalers().Get(cloud.Project(), *e.Zone, *e.Name)
+	if err != nil {
+		if gce.IsNotFound(err) {
+			return actual, nil
+		}
+		return nil, fmt.Errorf("error getting Autoscaler: %v", err)
+	}
+
+	if r.Description != autoscalerDescription {
+		if fi.BoolValue(e.Enabled) {
+			return nil, fmt.Errorf("autoscaler %q is not managed by kOps; delete it to use scaling schedules", *e.Name)
+		}
+		klog.V(2).Infof("Ignoring Autoscaler %q not managed by kOps", *e.Name)
+		return actual, nil
+	}
+
+	actual.Enabled = fi.Bool(true)
+	if policy := r.AutoscalingPolicy; policy != nil {
+		actual.MinReplicas = fi.Int64(policy.MinNumReplicas)
+		actual.MaxReplicas = fi.Int64(policy.MaxNumReplicas)
+		for name, schedule := range policy.ScalingSchedules {
+			if actual.ScalingSchedules == nil {
+				actual.ScalingSchedules = make(map[string]*AutoscalerScalingSchedule)
+			}
+			actual.ScalingSchedules[name] = &AutoscalerScalingSchedule{
+				Schedule:            fi.String(schedule.Schedule),
+				TimeZone:            fi.String(schedule.TimeZone),
+				DurationSec:         fi.Int64(schedule.DurationSec),
+				MinRequiredReplicas: fi.Int64(schedule.MinRequiredReplicas),
+			}
+		}
+	}
+
+	return actual, nil
+}
+
+func (e *Autoscaler) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(e, c)
+}
+
+func (_ *Autoscaler) CheckChanges(a, e, changes *Autoscaler) error {
+	if fi.StringValue(e.Name) == "" {
+		return fi.RequiredField("Name")
+	}
+	if fi.StringValue(e.Zone) == "" {
+		return fi.RequiredField("Zone")
+	}
+	if fi.BoolValue(e.Enabled) && e.MaxReplicas == nil {
+		return fi.RequiredField("MaxReplicas")
+	}
+	return nil
+}
+
+func (_ *Autoscaler) RenderGCE(t *gce.GCEAPITarget, a, e, changes *Autoscaler) error {
+	cloud := t.Cloud
+	project := cloud.Project()
+
+	if !fi.BoolValue(e.Enabled) {
+		if a != nil && fi.BoolValue(a.Enabled) {
+			klog.V(2).Infof("Deleting Autoscaler %q", *e.Name)
+			op, err := cloud.Compute().Autoscalers().Delete(project, *e.Zone, *e.Name)
+			if err != nil {
+				return fmt.Errorf("error deleting Autoscaler: %v", err)
+			}
+			if err := cloud.WaitForOp(op); err != nil {
+				return fmt.Errorf("error deleting Autoscaler: %v", err)
+			}
+		}
+		return nil
+	}
+
+	policy := &compute.AutoscalingPolicy{
+		MinNumReplicas: fi.Int64Value(e.MinReplicas),
+		MaxNumReplicas: fi.Int64Value(e.MaxReplicas),
+		// MinNumReplicas 0 will normally be omitted by the marshaling code; we need to force it
+		ForceSendFields: []string{"MinNumReplicas"},
+	}
+	for name, schedule := range e.ScalingSchedules {
+		if policy.ScalingSchedules == nil {
+			policy.ScalingSchedules = make(map[string]compute.AutoscalingPolicyScalingSchedule)
+		}
+		policy.ScalingSchedules[name] = compute.AutoscalingPolicyScalingSchedule{
+			Schedule:            fi.StringValue(schedule.Schedule),
+			TimeZone:            fi.StringValue(schedule.TimeZone),
+			DurationSec:         fi.Int64Value(schedule.DurationSec),
+			MinRequiredReplicas: fi.Int64Value(schedule.MinRequiredReplicas),
+			ForceSendFields:     []string{"MinRequiredReplicas"},
+		}
+	}
+
+	o := &compute.Autoscaler{
+		Name:              *e.Name,
+		Description:       autoscalerDescription,
+		Target:            e.InstanceGroupManager.URL(project),
+		AutoscalingPolicy: policy,
+	}
+
+	if a == nil || !fi.BoolValue(a.Enabled) {
+		klog.V(2).Infof("Creating Autoscaler %q", o.Name)
+		op, err := cloud.Compute().Autoscalers().Insert(project, *e.Zone, o)
+		if err != nil {
+			return fmt.Errorf("error creating Autoscaler: %v", err)
+		}
+		if err := cloud.WaitForOp(op); err != nil {
+			return fmt.Errorf("error creating Autoscaler: %v", err)
+		}
+	} else {
+		klog.V(2).Infof("Updating Autoscaler %q", o.Name)
+		op, err := cloud.Compute().Autoscalers().Update(project, *e.Zone, o)
+		if err != nil {
+			return fmt.Errorf("error updating Autoscaler: %v", err)
+		}
+		if err := cloud.WaitForOp(op); err != nil {
+			return fmt.Errorf("error updating Autoscaler: %v", err)
+		}
+	}
+
+	return nil
+}
+
+type terraformAutoscaler struct {
+	Name              *string                     `json:"name" cty:"name"`
+	Description       *string                     `json:"description" cty:"description"`
+	Zone              *string                     `json:"zone" cty:"zone"`
+	Target            *terraformWriter.Literal    `json:"target" cty:"target"`
+	AutoscalingPolicy *terraformAutoscalingPolicy `json:"autoscaling_policy" cty:"autoscaling_policy"`
+}
+
+type terraformAutoscalingPolicy struct {
+	MinReplicas      *int64                      `json:"min_replicas" cty:"min_replicas"`
+	MaxReplicas      *int64                      `json:"max_replicas" cty:"max_replicas"`
+	ScalingSchedules []*terraformScalingSchedule `json:"scaling_schedules,omitempty" cty:"scaling_schedules"`
+}
+
+type terraformScalingSchedule struct {
+	Name                *string `json:"name" cty:"name"`
+	Schedule            *string `json:"schedule" cty:"schedule"`
+	TimeZone            *string `json:"time_zone" cty:"time_zone"`
+	DurationSec         *int64  `json:"duration_sec" cty:"duration_sec"`
+	MinRequiredReplicas *int64  `json:"min_required_replicas" cty:"min_required_replicas"`
+}
+
+func (_ *Autoscaler) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *Autoscaler) error {
+	if !fi.BoolValue(e.Enabled) {
+		return nil
+	}
+
+	tf := &terraformAutoscaler{
+		Name:        e.Name,
+		Description: fi.String(autoscalerDescription),
+		Zone:        e.Zone,
+		Target:      e.InstanceGroupManager.TerraformLink(),
+		AutoscalingPolicy: &terraformAutoscalingPolicy{
+			MinReplicas: e.MinReplicas,
+			MaxReplicas: e.MaxReplicas,
+		},
+	}
+	for _, name := range maps.SortedKeys(e.ScalingSchedules) {
+		schedule := e.ScalingSchedules[name]
+		tf.AutoscalingPolicy.ScalingSchedules = append(tf.AutoscalingPolicy.ScalingSchedules, &terraformScalingSchedule{
+			Name:                fi.String(name),
+			Schedule:            schedule.Schedule,
+			TimeZone:            schedule.TimeZone,
+			DurationSec:         schedule.DurationSec,
+			MinRequiredReplicas: schedule.MinRequiredReplicas,
+		})
+	}
+
+	return t.RenderResource("google_compute_autoscaler", *e.Name, tf)
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcetasks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	compute "google.golang.org/api/compute/v1"
+	gcemock "k8s.io/kops/cloudmock/gce"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+)
+
+func TestAutoscalerSync(t *testing.T) {
+	ctx := context.TODO()
+
+	cloud := gcemock.InstallMockGCECloud("us-test1", "testproject")
+
+	run := func(autoscaler *Autoscaler) {
+		igm := &InstanceGroupManager{
+			Name:      fi.String(fi.StringValue(autoscaler.Name)),
+			Lifecycle: fi.LifecycleIgnore,
+			Zone:      autoscaler.Zone,
+		}
+		autoscaler.Lifecycle = fi.LifecycleSync
+		autoscaler.InstanceGroupManager = igm
+
+		allTasks := map[string]fi.Task{
+			"igm":        igm,
+			"autoscaler": autoscaler,
+		}
+
+		context, err := fi.NewContext(gce.NewGCEAPITarget(cloud), nil, cloud, nil, nil, nil, true, allTasks)
+		if err != nil {
+			t.Fatalf("error building context: %v", err)
+		}
+		defer context.Close()
+
+		options := fi.RunTasksOptions{
+			MaxTaskDuration:         2 * time.Second,
+			WaitAfterAllTasksFailed: 500 * time.Millisecond,
+		}
+		if err := context.RunTasks(ctx, options); err != nil {
+			t.Fatalf("unexpected error during Run: %v", err)
+		}
+	}
+
+	get := func(name string) *compute.Autoscaler {
+		a, err := cloud.Compute().Autoscalers().Get("testproject", "us-test1-a", name)
+		if err != nil {
+			if gce.IsNotFound(err) {
+				return nil
+			}
+			t.Fatalf("error getting autoscaler: %v", err)
+		}
+		return a
+	}
+
+	// An autoscaler that isn't managed by kOps is left alone
+	if _, err := cloud.Compute().Autoscalers().Insert("testproject", "us-test1-a", &compute.Autoscaler{Name: "other"}); err != nil {
+		t.Fatalf("error creating autoscaler: %v", err)
+	}
+	run(&Autoscaler{
+		Name:    fi.String("other"),
+		Zone:    fi.String("us-test1-a"),
+		Enabled: fi.Bool(false),
+	})
+	if get("other") == nil {
+		t.Errorf("autoscaler not managed by kOps was deleted")
+	}
+
+	run(&Autoscaler{
+		Name:        fi.String("a-nodes"),
+		Zone:        fi.String("us-test1-a"),
+		Enabled:     fi.Bool(true),
+		MinReplicas: fi.Int64(0),
+		MaxReplicas: fi.Int64(3),
+		ScalingSchedules: map[string]*AutoscalerScalingSchedule{
+			"working-hours": {
+				Schedule:            fi.String("0 8 * * 1-5"),
+				TimeZone:            fi.String("Europe/Berlin"),
+				DurationSec:         fi.Int64(36000),
+				MinRequiredReplicas: fi.Int64(2),
+			},
+		},
+	})
+	a := get("a-nodes")
+	if a == nil {
+		t.Fatalf("autoscaler was not created")
+	}
+	if a.Target != "https://www.googleapis.com/compute/v1/projects/testproject/zones/us-test1-a/instanceGroupManagers/a-nodes" {
+		t.Errorf("unexpected target: %q", a.Target)
+	}
+	if a.AutoscalingPolicy.MaxNumReplicas != 3 || a.AutoscalingPolicy.ScalingSchedules["working-hours"].MinRequiredReplicas != 2 {
+		t.Errorf("unexpected autoscaling policy: %+v", a.AutoscalingPolicy)
+	}
+
+	run(&Autoscaler{
+		Name:        fi.String("a-nodes"),
+		Zone:        fi.String("us-test1-a"),
+		Enabled:     fi.Bool(true),
+		MinReplicas: fi.Int64(0),
+		MaxReplicas: fi.Int64(3),
+		ScalingSchedules: map[string]*AutoscalerScalingSchedule{
+			"working-hours": {
+				Schedule:            fi.String("0 9 * * 1-5"),
+				TimeZone:            fi.String("Europe/Berlin"),
+				DurationSec:         fi.Int64(36000),
+				MinRequiredReplicas: fi.Int64(2),
+			},
+		},
+	})
+	if schedule := get("a-nodes").AutoscalingPolicy.ScalingSchedules["working-hours"].Schedule; schedule != "0 9 * * 1-5" {
+		t.Errorf("unexpected schedule after update: %q", schedule)
+	}
+
+	run(&Autoscaler{
+		Name:    fi.String("a-nodes"),
+		Zone:    fi.String("us-test1-a"),
+		Enabled: fi.Bool(false),
+	})
+	if get("a-nodes") != nil {
+		t.Errorf("autoscaler was not deleted")
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcetasks
+
+import (
+	"fmt"
+	"reflect"
+
+	compute "google.golang.org/api/compute/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
+)
+
+// BackendService represents a GCE regional BackendService, as used by internal TCP load balancers
+// +kops:fitask
+type BackendService struct {
+	Name      *string
+	Lifecycle fi.Lifecycle
+
+	LoadBalancingScheme   *string
+	Protocol              *string
+	HealthChecks          []*HealthCheck
+	InstanceGroupManagers []*InstanceGroupManager
+}
+
+var _ fi.CompareWithID = &BackendService{}
+
+func (e *BackendService) CompareWithID() *string {
+	return e.Name
+}
+
+func (e *BackendService) Find(c *fi.Context) (*BackendService, error) {
+	cloud := c.Cloud.(gce.GCECloud)
+	name := fi.StringValue(e.Name)
+
+	r, err := cloud.Compute().RegionBackendServices().Get(cloud.Project(), cloud.Region(), name)
+	if err != nil {
+		if gce.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting BackendService %q: %v", name, err)
+	}
+
+	actual := &BackendService{
+		Name:                fi.String(r.Name),
+		LoadBalancingScheme: fi.String(r.LoadBalancingScheme),
+		Protocol:            fi.String(r.Protocol),
+	}
+	for _, hc := range r.HealthChecks {
+		actual.HealthChecks = append(actual.HealthChecks, &HealthCheck{
+			Name: fi.String(lastComponent(hc)),
+		})
+	}
+	for _, b := range r.Backends {
+		// Managed instance groups share their name with the instance group they manage
+		actual.InstanceGroupManagers = append(actual.InstanceGroupManagers, &InstanceGroupManager{
+			Name: fi.String(lastComponent(b.Group)),
+		})
+	}
+
+	// Ignore "system" fields
+	actual.Lifecycle = e.Lifecycle
+
+	return actual, nil
+}
+
+func (e *BackendService) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(e, c)
+}
+
+func (_ *BackendService) CheckChanges(a, e, changes *BackendService) error {
+	if fi.StringValue(e.Name) == "" {
+		return fi.RequiredField("Name")
+	}
+	if a != nil {
+		if changes.LoadBalancingScheme != nil {
+			return fi.CannotChangeField("LoadBalancingScheme")
+		}
+	}
+	return nil
+}
+
+func (e *BackendService) URL(cloud gce.GCECloud) string {
+	name := fi.StringValue(e.Name)
+
+	return fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/%s/regions/%s/backendServices/%s", cloud.Project(), cloud.Region(), name)
+}
+
+func (e *BackendService) mapToGCE(cloud gce.GCECloud) *compute.BackendService {
+	o := &compute.BackendService{
+		Name:                fi.StringValue(e.Name),
+		LoadBalancingScheme: fi.StringValue(e.LoadBalancingScheme),
+		Protocol:            fi.StringValue(e.Protocol),
+	}
+
+	for _, hc := range e.HealthChecks {
+		o.HealthChecks = append(o.HealthChecks, hc.URL(cloud))
+	}
+
+	for _, igm := range e.InstanceGroupManagers {
+		o.Backends = append(o.Backends, &compute.Backend{
+			Group:         fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/%s/zones/%s/instanceGroups/%s", cloud.Project(), fi.StringValue(igm.Zone), fi.StringValue(igm.Name)),
+			BalancingMode: "CONNECTION",
+		})
+	}
+
+	return o
+}
+
+func (_ *BackendService) RenderGCE(t *gce.GCEAPITarget, a, e, changes *BackendService) error {
+	name := fi.StringValue(e.Name)
+	o := e.mapToGCE(t.Cloud)
+
+	if a == nil {
+		klog.V(4).Infof("Creating BackendService %q", o.Name)
+
+		op, err := t.Cloud.Compute().RegionBackendServices().Insert(t.Cloud.Project(), t.Cloud.Region(), o)
+		if err != nil {
+			return fmt.Errorf("error creating BackendService %q: %v", name, err)
+		}
+
+		if err := t.Cloud.WaitForOp(op); err != nil {
+			return fmt.Errorf("error creating BackendService: %v", err)
+		}
+	} else {
+		empty := &BackendService{}
+		if !reflect.DeepEqual(empty, &BackendService{
+			Protocol:              changes.Protocol,
+			HealthChecks:          changes.HealthChecks,
+			InstanceGroupManagers: changes.InstanceGroupManagers,
+		}) {
+			klog.V(4).Infof("Updating BackendService %q", o.Name)
+
+			op, err := t.Cloud.Compute().RegionBackendServices().Update(t.Cloud.Project(), t.Cloud.Region(), name, o)
+			if err != nil {
+				return fmt.Errorf("error updating BackendService %q: %v", name, err)
+			}
+
+			if err := t.Cloud.WaitForOp(op); err != nil {
+				return fmt.Errorf("error updating BackendService: %v", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+type terraformBackendService struct {
+	Name                string                     `json:"name" cty:"name"`
+	LoadBalancingScheme string                     `json:"load_balancing_scheme,omitempty" cty:"load_balancing_scheme"`
+	Protocol            string                     `json:"protocol,omitempty" cty:"protocol"`
+	HealthChecks        []*terraformWriter.Literal `json:"health_checks,omitempty" cty:"health_checks"`
+	Backend             []*terraformBackend        `json:"backend,omitempty" cty:"backend"`
+}
+
+type terraformBackend struct {
+	Group         *terraformWriter.Literal `json:"group" cty:"group"`
+	BalancingMode string                   `json:"balancing_mode,omitempty" cty:"balancing_mode"`
+}
+
+func (_ *BackendService) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *BackendService) error {
+	name := fi.StringValue(e.Name)
+
+	tf := &terraformBackendService{
+		Name:                name,
+		LoadBalancingScheme: fi.StringValue(e.LoadBalancingScheme),
+		Protocol:            fi.StringValue(e.Protocol),
+	}
+
+	for _, hc := range e.HealthChecks {
+		tf.HealthChecks = append(tf.HealthChecks, hc.TerraformLink())
+	}
+
+	for _, igm := range e.InstanceGroupManagers {
+		tf.Backend = append(tf.Backend, &terraformBackend{
+			Group:         terraformWriter.LiteralProperty("google_compute_instance_group_manager", fi.StringValue(igm.Name), "instance_group"),
+			BalancingMode: "CONNECTION",
+		})
+	}
+
+	return t.RenderResource("google_compute_region_backend_service", name, tf)
+}
+
+func (e *BackendService) TerraformLink() *terraformWriter.Literal {
+	name := fi.StringValue(e.Name)
+
+	return terraformWriter.LiteralSelfLink("google_compute_region_backend_service", name)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcetasks
+
+import (
+	"reflect"
+	"testing"
+
+	gcemock "k8s.io/kops/cloudmock/gce"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestBackendService(t *testing.T) {
+	cloud := gcemock.InstallMockGCECloud("us-test1", "testproject")
+
+	buildTasks := func(scheme string, zones ...string) map[string]fi.Task {
+		healthCheck := &HealthCheck{
+			Name:      fi.String("api-minimal-example-com"),
+			Lifecycle: fi.LifecycleSync,
+			Port:      fi.Int64(443),
+		}
+		backendService := &BackendService{
+			Name:                fi.String("api-minimal-example-com"),
+			Lifecycle:           fi.LifecycleSync,
+			LoadBalancingScheme: fi.String(scheme),
+			Protocol:            fi.String("TCP"),
+			HealthChecks:        []*HealthCheck{healthCheck},
+		}
+		allTasks := map[string]fi.Task{
+			"healthCheck":    healthCheck,
+			"backendService": backendService,
+		}
+		for _, zone := range zones {
+			igm := &InstanceGroupManager{
+				Name:      fi.String("a-master-" + zone + "-minimal-example-com"),
+				Lifecycle: fi.LifecycleIgnore,
+				Zone:      fi.String(zone),
+			}
+			backendService.InstanceGroupManagers = append(backendService.InstanceGroupManagers, igm)
+			allTasks["igm-"+zone] = igm
+		}
+		return allTasks
+	}
+
+	if err := runTasks(t, cloud, buildTasks("INTERNAL", "us-test1-a")); err != nil {
+		t.Fatalf("unexpected error creating backend service: %v", err)
+	}
+
+	bs, err := cloud.Compute().RegionBackendServices().Get("testproject", "us-test1", "api-minimal-example-com")
+	if err != nil {
+		t.Fatalf("error getting backend service: %v", err)
+	}
+	if bs.LoadBalancingScheme != "INTERNAL" || bs.Protocol != "TCP" {
+		t.Errorf("unexpected backend service: %+v", bs)
+	}
+	expectedHealthChecks := []string{
+		"https://www.googleapis.com/compute/v1/projects/testproject/global/healthChecks/api-minimal-example-com",
+	}
+	if !reflect.DeepEqual(bs.HealthChecks, expectedHealthChecks) {
+		t.Errorf("unexpected health checks: expected %v, got %v", expectedHealthChecks, bs.HealthChecks)
+	}
+	if len(bs.Backends) != 1 {
+		t.Fatalf("expected 1 backend, got %d", len(bs.Backends))
+	}
+	expectedGroup := "https://www.googleapis.com/compute/v1/projects/testproject/zones/us-test1-a/instanceGroups/a-master-us-test1-a-minimal-example-com"
+	if bs.Backends[0].Group != expectedGroup || bs.Backends[0].BalancingMode != "CONNECTION" {
+		t.Errorf("unexpected backend: %+v", bs.Backends[0])
+	}
+
+	checkNoChanges(t, cloud, buildTasks("INTERNAL", "us-test1-a"))
+
+	// Adding a zone adds a backend for the instance group of the zone
+	if err := runTasks(t, cloud, buildTasks("INTERNAL", "us-test1-a", "us-test1-b")); err != nil {
+		t.Fatalf("unexpected error updating backend service: %v", err)
+	}
+	bs, err = cloud.Compute().RegionBackendServices().Get("testproject", "us-test1", "api-minimal-example-com")
+	if err != nil {
+		t.Fatalf("error getting backend service: %v", err)
+	}
+	var groups []string
+	for _, backend := range bs.Backends {
+		groups = append(groups, lastComponent(backend.Group))
+	}
+	expectedGroups := []string{"a-master-us-test1-a-minimal-example-com", "a-master-us-test1-b-minimal-example-com"}
+	if !reflect.DeepEqual(groups, expectedGroups) {
+		t.Errorf("unexpected backends after update: expected %v, got %v", expectedGroups, groups)
+	}
+
+	checkNoChanges(t, cloud, buildTasks("INTERNAL", "us-test1-a", "us-test1-b"))
+
+	// The load balancing scheme of an existing backend service can't be changed
+	if err := runTasks(t, cloud, buildTasks("EXTERNAL", "us-test1-a", "us-test1-b")); err == nil {
+		t.Errorf("expected an error changing the load balancing scheme")
+	}
+}
@@ -37,6 +37,14 @@ type ForwardingRule struct {
 	TargetPool *TargetPool
 	IPAddress  *Address
 	IPProtocol string
+
+	// LoadBalancingScheme is set to INTERNAL for internal TCP load balancers, which
+	// forward to a BackendService on the given Ports within a Network and Subnetwork.
+	LoadBalancingScheme string
+	Ports               []string
+	BackendService      *BackendService
+	Network             *Network
+	Subnetwork          *Subnet
 }
 
 var _ fi.CompareWithID = &ForwardingRule{}
@@ -67,6 +75,21 @@ func (e *ForwardingRule) Find(c *fi.Context) (*ForwardingRule, error) {
 			Name: fi.String(lastComponent(r.Target)),
 		}
 	}
+	if r.BackendService != "" {
+		actual.BackendService = &BackendService{
+			Name: fi.String(lastComponent(r.BackendService)),
+		}
+	}
+	if r.LoadBalancingScheme == "INTERNAL" {
+		actual.LoadBalancingScheme = r.LoadBalancingScheme
+		actual.Ports = r.Ports
+		if r.Network != "" {
+			actual.Network = &Network{Name: fi.String(lastComponent(r.Network))}
+		}
+		if r.Subnetwork != "" {
+			actual.Subnetwork = &Subnet{Name: fi.String(lastComponent(r.Subnetwork))}
+		}
+	}
 	if r.IPAddress != "" {
 		address, err := findAddressByIP(cloud, r.IPAddress)
 		if err != nil {
@@ -96,15 +119,29 @@ func (_ *ForwardingRule) RenderGCE(t *gce.GCEAPITarget, a, e, changes *Forwardin
 	name := fi.StringValue(e.Name)
 
 	o := &compute.ForwardingRule{
-		Name:       name,
-		PortRange:  e.PortRange,
-		IPProtocol: e.IPProtocol,
+		Name:                name,
+		PortRange:           e.PortRange,
+		IPProtocol:          e.IPProtocol,
+		LoadBalancingScheme: e.LoadBalancingScheme,
+		Ports:               e.Ports,
 	}
 
 	if e.TargetPool != nil {
 		o.Target = e.TargetPool.URL(t.Cloud)
 	}
 
+	if e.BackendService != nil {
+		o.BackendService = e.BackendService.URL(t.Cloud)
+	}
+
+	if e.Network != nil {
+		o.Network = e.Network.URL(t.Cloud.Project())
+	}
+
+	if e.Subnetwork != nil {
+		o.Subnetwork = e.Subnetwork.URL(t.Cloud.Project(), t.Cloud.Region())
+	}
+
 	if e.IPAddress != nil {
 		o.IPAddress = fi.StringValue(e.IPAddress.IPAddress)
 		if o.IPAddress == "" {
@@ -139,26 +176,45 @@ func (_ *ForwardingRule) RenderGCE(t *gce.GCEAPITarget, a, e, changes *Forwardin
 }
 
 type terraformForwardingRule struct {
-	Name       string                   `json:"name" cty:"name"`
-	PortRange  string                   `json:"port_range,omitempty" cty:"port_range"`
-	Target     *terraformWriter.Literal `json:"target,omitempty" cty:"target"`
-	IPAddress  *terraformWriter.Literal `json:"ip_address,omitempty" cty:"ip_address"`
-	IPProtocol string                   `json:"ip_protocol,omitempty" cty:"ip_protocol"`
+	Name                string                   `json:"name" cty:"name"`
+	PortRange           string                   `json:"port_range,omitempty" cty:"port_range"`
+	Target              *terraformWriter.Literal `json:"target,omitempty" cty:"target"`
+	IPAddress           *terraformWriter.Literal `json:"ip_address,omitempty" cty:"ip_address"`
+	IPProtocol          string                   `json:"ip_protocol,omitempty" cty:"ip_protocol"`
+	LoadBalancingScheme string                   `json:"load_balancing_scheme,omitempty" cty:"load_balancing_scheme"`
+	Ports               []string                 `json:"ports,omitempty" cty:"ports"`
+	BackendService      *terraformWriter.Literal `json:"backend_service,omitempty" cty:"backend_service"`
+	Network             *terraformWriter.Literal `json:"network,omitempty" cty:"network"`
+	Subnetwork          *terraformWriter.Literal `json:"subnetwork,omitempty" cty:"subnetwork"`
 }
 
 func (_ *ForwardingRule) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *ForwardingRule) error {
 	name := fi.StringValue(e.Name)
 
 	tf := &terraformForwardingRule{
-		Name:       name,
-		PortRange:  e.PortRange,
-		IPProtocol: e.IPProtocol,
+		Name:                name,
+		PortRange:           e.PortRange,
+		IPProtocol:          e.IPProtocol,
+		LoadBalancingScheme: e.LoadBalancingScheme,
+		Ports:               e.Ports,
 	}
 
 	if e.TargetPool != nil {
 		tf.Target = e.TargetPool.TerraformLink()
 	}
 
+	if e.BackendService != nil {
+		tf.BackendService = e.BackendService.TerraformLink()
+	}
+
+	if e.Network != nil {
+		tf.Network = e.Network.TerraformName()
+	}
+
+	if e.Subnetwork != nil {
+		tf.Subnetwork = e.Subnetwork.TerraformName()
+	}
+
 	if e.IPAddress != nil {
 		tf.IPAddress = e.IPAddress.TerraformAddress()
 	}
@@ -0,0 +1,145 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcetasks
+
+import (
+	"fmt"
+
+	compute "google.golang.org/api/compute/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
+)
+
+// HealthCheck represents a GCE (global) TCP HealthCheck
+// +kops:fitask
+type HealthCheck struct {
+	Name      *string
+	Lifecycle fi.Lifecycle
+
+	Port *int64
+}
+
+var _ fi.CompareWithID = &HealthCheck{}
+
+func (e *HealthCheck) CompareWithID() *string {
+	return e.Name
+}
+
+func (e *HealthCheck) Find(c *fi.Context) (*HealthCheck, error) {
+	cloud := c.Cloud.(gce.GCECloud)
+	name := fi.StringValue(e.Name)
+
+	r, err := cloud.Compute().HealthChecks().Get(cloud.Project(), name)
+	if err != nil {
+		if gce.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting HealthCheck %q: %v", name, err)
+	}
+
+	actual := &HealthCheck{
+		Name: fi.String(r.Name),
+	}
+	if r.TcpHealthCheck != nil {
+		actual.Port = fi.Int64(r.TcpHealthCheck.Port)
+	}
+
+	// Ignore "system" fields
+	actual.Lifecycle = e.Lifecycle
+
+	return actual, nil
+}
+
+func (e *HealthCheck) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(e, c)
+}
+
+func (_ *HealthCheck) CheckChanges(a, e, changes *HealthCheck) error {
+	if fi.StringValue(e.Name) == "" {
+		return fi.RequiredField("Name")
+	}
+	if e.Port == nil {
+		return fi.RequiredField("Port")
+	}
+	return nil
+}
+
+func (e *HealthCheck) URL(cloud gce.GCECloud) string {
+	name := fi.StringValue(e.Name)
+
+	return fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/%s/global/healthChecks/%s", cloud.Project(), name)
+}
+
+func (_ *HealthCheck) RenderGCE(t *gce.GCEAPITarget, a, e, changes *HealthCheck) error {
+	name := fi.StringValue(e.Name)
+
+	o := &compute.HealthCheck{
+		Name: name,
+		Type: "TCP",
+		TcpHealthCheck: &compute.TCPHealthCheck{
+			Port: fi.Int64Value(e.Port),
+		},
+	}
+
+	if a == nil {
+		klog.V(4).Infof("Creating HealthCheck %q", o.Name)
+
+		op, err := t.Cloud.Compute().HealthChecks().Insert(t.Cloud.Project(), o)
+		if err != nil {
+			return fmt.Errorf("error creating HealthCheck %q: %v", name, err)
+		}
+
+		if err := t.Cloud.WaitForOp(op); err != nil {
+			return fmt.Errorf("error creating HealthCheck: %v", err)
+		}
+	} else {
+		return fmt.Errorf("cannot apply changes to HealthCheck: %v", changes)
+	}
+
+	return nil
+}
+
+type terraformHealthCheck struct {
+	Name           string                       `json:"name" cty:"name"`
+	TCPHealthCheck *terraformTCPHealthCheckPort `json:"tcp_health_check,omitempty" cty:"tcp_health_check"`
+}
+
+type terraformTCPHealthCheckPort struct {
+	Port int64 `json:"port" cty:"port"`
+}
+
+func (_ *HealthCheck) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *HealthCheck) error {
+	name := fi.StringValue(e.Name)
+
+	tf := &terraformHealthCheck{
+		Name: name,
+		TCPHealthCheck: &terraformTCPHealthCheckPort{
+			Port: fi.Int64Value(e.Port),
+		},
+	}
+
+	return t.RenderResource("google_compute_health_check", name, tf)
+}
+
+func (e *HealthCheck) TerraformLink() *terraformWriter.Literal {
+	name := fi.StringValue(e.Name)
+
+	return terraformWriter.LiteralSelfLink("google_compute_health_check", name)
+}
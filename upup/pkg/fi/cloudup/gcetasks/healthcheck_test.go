@@ -0,0 +1,129 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcetasks
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	gcemock "k8s.io/kops/cloudmock/gce"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+)
+
+var testRunTasksOptions = fi.RunTasksOptions{
+	MaxTaskDuration:         2 * time.Second,
+	WaitAfterAllTasksFailed: 500 * time.Millisecond,
+}
+
+// runTasks applies the tasks to the cloud.
+func runTasks(t *testing.T, cloud gce.GCECloud, allTasks map[string]fi.Task) error {
+	c, err := fi.NewContext(gce.NewGCEAPITarget(cloud), nil, cloud, nil, nil, nil, true, allTasks)
+	if err != nil {
+		t.Fatalf("error building context: %v", err)
+	}
+	defer c.Close()
+
+	return c.RunTasks(context.TODO(), testRunTasksOptions)
+}
+
+// checkNoChanges fails the test if a dry run of the tasks reports changes.
+func checkNoChanges(t *testing.T, cloud gce.GCECloud, allTasks map[string]fi.Task) {
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			KubernetesVersion: "v1.21.0",
+		},
+	}
+	assetBuilder := assets.NewAssetBuilder(cluster, false)
+	target := fi.NewDryRunTarget(assetBuilder, os.Stderr)
+	c, err := fi.NewContext(target, nil, cloud, nil, nil, nil, true, allTasks)
+	if err != nil {
+		t.Fatalf("error building context: %v", err)
+	}
+	defer c.Close()
+
+	if err := c.RunTasks(context.TODO(), testRunTasksOptions); err != nil {
+		t.Fatalf("unexpected error during dry run: %v", err)
+	}
+
+	if target.HasChanges() {
+		var b bytes.Buffer
+		if err := target.PrintReport(allTasks, &b); err != nil {
+			t.Fatalf("error building report: %v", err)
+		}
+		t.Fatalf("dry run had changes after applying: %v", b.String())
+	}
+}
+
+func TestHealthCheck(t *testing.T) {
+	cloud := gcemock.InstallMockGCECloud("us-test1", "testproject")
+
+	buildTasks := func(port int64) map[string]fi.Task {
+		return map[string]fi.Task{
+			"healthCheck": &HealthCheck{
+				Name:      fi.String("api-minimal-example-com"),
+				Lifecycle: fi.LifecycleSync,
+				Port:      fi.Int64(port),
+			},
+		}
+	}
+
+	if err := runTasks(t, cloud, buildTasks(443)); err != nil {
+		t.Fatalf("unexpected error creating health check: %v", err)
+	}
+
+	hc, err := cloud.Compute().HealthChecks().Get("testproject", "api-minimal-example-com")
+	if err != nil {
+		t.Fatalf("error getting health check: %v", err)
+	}
+	if hc.Type != "TCP" || hc.TcpHealthCheck == nil || hc.TcpHealthCheck.Port != 443 {
+		t.Errorf("unexpected health check: %+v", hc)
+	}
+
+	checkNoChanges(t, cloud, buildTasks(443))
+
+	// Changing an existing health check is not supported
+	if err := runTasks(t, cloud, buildTasks(8443)); err == nil {
+		t.Errorf("expected an error changing the port of the health check")
+	}
+}
+
+func TestHealthCheckRequiresPort(t *testing.T) {
+	e := &HealthCheck{
+		Name: fi.String("api-minimal-example-com"),
+	}
+	if err := e.CheckChanges(nil, e, e); err == nil {
+		t.Errorf("expected an error for a health check without a port")
+	}
+}
+
+func TestHealthCheckURL(t *testing.T) {
+	cloud := gcemock.InstallMockGCECloud("us-test1", "testproject")
+
+	e := &HealthCheck{
+		Name: fi.String("api-minimal-example-com"),
+	}
+	expected := "https://www.googleapis.com/compute/v1/projects/testproject/global/healthChecks/api-minimal-example-com"
+	if url := e.URL(cloud); url != expected {
+		t.Errorf("unexpected URL: expected %q, got %q", expected, url)
+	}
+}
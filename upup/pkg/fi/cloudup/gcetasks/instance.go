@@ -361,10 +361,16 @@ func BuildMachineTypeURL(project, zone, name string) string {
 	return fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/%s/zones/%s/machineTypes/%s", project, zone, name)
 }
 
+// BuildImageURL maps an image spec to the full URL of the image.
+// The spec can be "<name>", "<project>/<name>" or "<project>/family/<family>";
+// the latter resolves to the latest image in the image family.
 func BuildImageURL(defaultProject, nameSpec string) string {
 	tokens := strings.Split(nameSpec, "/")
 	var project, name string
-	if len(tokens) == 2 {
+	if len(tokens) == 3 && tokens[1] == "family" {
+		project = tokens[0]
+		name = "family/" + tokens[2]
+	} else if len(tokens) == 2 {
 		project = tokens[0]
 		name = tokens[1]
 	} else if len(tokens) == 1 {
@@ -380,6 +386,15 @@ func BuildImageURL(defaultProject, nameSpec string) string {
 }
 
 func ShortenImageURL(defaultProject string, imageURL string) (string, error) {
+	if i := strings.Index(imageURL, "/global/images/family/"); i != -1 {
+		u, err := gce.ParseGoogleCloudURL(imageURL[:i] + "/global/images/" + imageURL[i+len("/global/images/family/"):])
+		if err != nil {
+			return "", err
+		}
+		klog.V(4).Infof("Resolved image family %q -> %q", imageURL, u.Project+"/family/"+u.Name)
+		return u.Project + "/family/" + u.Name, nil
+	}
+
 	u, err := gce.ParseGoogleCloudURL(imageURL)
 	if err != nil {
 		return "", err
@@ -0,0 +1,67 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcetasks
+
+import (
+	"testing"
+)
+
+func TestImageURLRoundTrip(t *testing.T) {
+	grid := []struct {
+		Spec      string
+		URL       string
+		Shortened string
+	}{
+		{
+			Spec:      "my-image",
+			URL:       "https://www.googleapis.com/compute/v1/projects/my-project/global/images/my-image",
+			Shortened: "my-image",
+		},
+		{
+			Spec:      "cos-cloud/cos-stable-65-10323-99-0",
+			URL:       "https://www.googleapis.com/compute/v1/projects/cos-cloud/global/images/cos-stable-65-10323-99-0",
+			Shortened: "cos-cloud/cos-stable-65-10323-99-0",
+		},
+		{
+			Spec:      "ubuntu-os-cloud/family/ubuntu-2004-lts",
+			URL:       "https://www.googleapis.com/compute/v1/projects/ubuntu-os-cloud/global/images/family/ubuntu-2004-lts",
+			Shortened: "ubuntu-os-cloud/family/ubuntu-2004-lts",
+		},
+		{
+			Spec:      "my-project/family/custom",
+			URL:       "https://www.googleapis.com/compute/v1/projects/my-project/global/images/family/custom",
+			Shortened: "my-project/family/custom",
+		},
+	}
+
+	for _, g := range grid {
+		u := BuildImageURL("my-project", g.Spec)
+		if u != g.URL {
+			t.Errorf("unexpected URL for %q: expected %q, got %q", g.Spec, g.URL, u)
+			continue
+		}
+
+		shortened, err := ShortenImageURL("my-project", u)
+		if err != nil {
+			t.Errorf("unexpected error shortening %q: %v", u, err)
+			continue
+		}
+		if shortened != g.Shortened {
+			t.Errorf("unexpected shortened image for %q: expected %q, got %q", u, g.Shortened, shortened)
+		}
+	}
+}
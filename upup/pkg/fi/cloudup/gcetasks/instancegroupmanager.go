@@ -38,6 +38,10 @@ type InstanceGroupManager struct {
 	TargetSize       *int64
 
 	TargetPools []*TargetPool
+
+	// Autoscaled is true if an Autoscaler changes the size of the InstanceGroupManager,
+	// so TargetSize is only applied when the InstanceGroupManager is created
+	Autoscaled *bool
 }
 
 var _ fi.CompareWithID = &InstanceGroupManager{}
@@ -73,6 +77,12 @@ func (e *InstanceGroupManager) Find(c *fi.Context) (*InstanceGroupManager, error
 
 	// Ignore "system" fields
 	actual.Lifecycle = e.Lifecycle
+	actual.Autoscaled = e.Autoscaled
+
+	// Don't undo the size changes made by the autoscaler
+	if fi.BoolValue(e.Autoscaled) {
+		actual.TargetSize = e.TargetSize
+	}
 
 	return actual, nil
 }
@@ -85,6 +95,10 @@ func (_ *InstanceGroupManager) CheckChanges(a, e, changes *InstanceGroupManager)
 	return nil
 }
 
+func (e *InstanceGroupManager) URL(project string) string {
+	return fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/%s/zones/%s/instanceGroupManagers/%s", project, fi.StringValue(e.Zone), fi.StringValue(e.Name))
+}
+
 func (_ *InstanceGroupManager) RenderGCE(t *gce.GCEAPITarget, a, e, changes *InstanceGroupManager) error {
 	project := t.Cloud.Project()
 
@@ -176,7 +190,7 @@ type terraformInstanceGroupManager struct {
 	Zone             *string                    `json:"zone" cty:"zone"`
 	BaseInstanceName *string                    `json:"base_instance_name" cty:"base_instance_name"`
 	Version          *terraformVersion          `json:"version" cty:"version"`
-	TargetSize       *int64                     `json:"target_size" cty:"target_size"`
+	TargetSize       *int64                     `json:"target_size,omitempty" cty:"target_size"`
 	TargetPools      []*terraformWriter.Literal `json:"target_pools,omitempty" cty:"target_pools"`
 }
 
@@ -189,7 +203,10 @@ func (_ *InstanceGroupManager) RenderTerraform(t *terraform.TerraformTarget, a,
 		Name:             e.Name,
 		Zone:             e.Zone,
 		BaseInstanceName: e.BaseInstanceName,
-		TargetSize:       e.TargetSize,
+	}
+	// The target size of an autoscaled instance group manager is owned by the autoscaler
+	if !fi.BoolValue(e.Autoscaled) {
+		tf.TargetSize = e.TargetSize
 	}
 	tf.Version = &terraformVersion{
 		InstanceTemplate: e.InstanceTemplate.TerraformLink(),
@@ -201,3 +218,7 @@ func (_ *InstanceGroupManager) RenderTerraform(t *terraform.TerraformTarget, a,
 
 	return t.RenderResource("google_compute_instance_group_manager", *e.Name, tf)
 }
+
+func (e *InstanceGroupManager) TerraformLink() *terraformWriter.Literal {
+	return terraformWriter.LiteralSelfLink("google_compute_instance_group_manager", *e.Name)
+}
@@ -70,6 +70,14 @@ type InstanceTemplate struct {
 	Metadata    map[string]fi.Resource
 	MachineType *string
 
+	// ShieldedSecureBoot, ShieldedVTPM and ShieldedIntegrityMonitoring configure Shielded VM options.
+	ShieldedSecureBoot          *bool
+	ShieldedVTPM                *bool
+	ShieldedIntegrityMonitoring *bool
+
+	// ConfidentialCompute runs the instances as Confidential VMs.
+	ConfidentialCompute *bool
+
 	// HasExternalIP is set to true when an external IP is allocated to an instance.
 	HasExternalIP *bool
 
@@ -128,6 +136,14 @@ func (e *InstanceTemplate) Find(c *fi.Context) (*InstanceTemplate, error) {
 		if p.Scheduling != nil {
 			actual.Preemptible = &p.Scheduling.Preemptible
 		}
+		if p.ShieldedInstanceConfig != nil {
+			actual.ShieldedSecureBoot = fi.Bool(p.ShieldedInstanceConfig.EnableSecureBoot)
+			actual.ShieldedVTPM = fi.Bool(p.ShieldedInstanceConfig.EnableVtpm)
+			actual.ShieldedIntegrityMonitoring = fi.Bool(p.ShieldedInstanceConfig.EnableIntegrityMonitoring)
+		}
+		if p.ConfidentialInstanceConfig != nil {
+			actual.ConfidentialCompute = fi.Bool(p.ConfidentialInstanceConfig.EnableConfidentialCompute)
+		}
 		if len(p.NetworkInterfaces) != 0 {
 			ni := p.NetworkInterfaces[0]
 			actual.Network = &Network{Name: fi.String(lastComponent(ni.Network))}
@@ -245,6 +261,26 @@ func (e *InstanceTemplate) mapToGCE(project string, region string) (*compute.Ins
 		}
 	}
 
+	var confidentialInstanceConfig *compute.ConfidentialInstanceConfig
+	if e.ConfidentialCompute != nil {
+		confidentialInstanceConfig = &compute.ConfidentialInstanceConfig{
+			EnableConfidentialCompute: *e.ConfidentialCompute,
+		}
+		// Confidential VMs do not support live migration
+		if *e.ConfidentialCompute {
+			scheduling.OnHostMaintenance = "TERMINATE"
+		}
+	}
+
+	var shieldedInstanceConfig *compute.ShieldedInstanceConfig
+	if e.ShieldedSecureBoot != nil || e.ShieldedVTPM != nil || e.ShieldedIntegrityMonitoring != nil {
+		shieldedInstanceConfig = &compute.ShieldedInstanceConfig{
+			EnableSecureBoot:          fi.BoolValue(e.ShieldedSecureBoot),
+			EnableVtpm:                fi.BoolValue(e.ShieldedVTPM),
+			EnableIntegrityMonitoring: fi.BoolValue(e.ShieldedIntegrityMonitoring),
+		}
+	}
+
 	var disks []*compute.AttachedDisk
 	disks = append(disks, &compute.AttachedDisk{
 		Kind: "compute#attachedDisk",
@@ -355,6 +391,10 @@ func (e *InstanceTemplate) mapToGCE(project string, region string) (*compute.Ins
 
 			ServiceAccounts: serviceAccounts,
 
+			ShieldedInstanceConfig: shieldedInstanceConfig,
+
+			ConfidentialInstanceConfig: confidentialInstanceConfig,
+
 			Tags: tags,
 		},
 	}
@@ -457,6 +497,18 @@ type terraformInstanceTemplate struct {
 	Metadata              map[string]*terraformWriter.Literal      `json:"metadata,omitempty" cty:"metadata"`
 	MetadataStartupScript *terraformWriter.Literal                 `json:"metadata_startup_script,omitempty" cty:"metadata_startup_script"`
 	Tags                  []string                                 `json:"tags,omitempty" cty:"tags"`
+	ShieldedInstance      *terraformShieldedInstanceConfig         `json:"shielded_instance_config,omitempty" cty:"shielded_instance_config"`
+	ConfidentialInstance  *terraformConfidentialInstanceConfig     `json:"confidential_instance_config,omitempty" cty:"confidential_instance_config"`
+}
+
+type terraformShieldedInstanceConfig struct {
+	EnableSecureBoot          bool `json:"enable_secure_boot" cty:"enable_secure_boot"`
+	EnableVTPM                bool `json:"enable_vtpm" cty:"enable_vtpm"`
+	EnableIntegrityMonitoring bool `json:"enable_integrity_monitoring" cty:"enable_integrity_monitoring"`
+}
+
+type terraformConfidentialInstanceConfig struct {
+	EnableConfidentialCompute bool `json:"enable_confidential_compute" cty:"enable_confidential_compute"`
 }
 
 type terraformServiceAccount struct {
@@ -612,6 +664,20 @@ func (_ *InstanceTemplate) RenderTerraform(t *terraform.TerraformTarget, a, e, c
 		}
 	}
 
+	if c := i.Properties.ShieldedInstanceConfig; c != nil {
+		tf.ShieldedInstance = &terraformShieldedInstanceConfig{
+			EnableSecureBoot:          c.EnableSecureBoot,
+			EnableVTPM:                c.EnableVtpm,
+			EnableIntegrityMonitoring: c.EnableIntegrityMonitoring,
+		}
+	}
+
+	if c := i.Properties.ConfidentialInstanceConfig; c != nil {
+		tf.ConfidentialInstance = &terraformConfidentialInstanceConfig{
+			EnableConfidentialCompute: c.EnableConfidentialCompute,
+		}
+	}
+
 	return t.RenderResource("google_compute_instance_template", name, tf)
 }
 
@@ -0,0 +1,233 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metal
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/dnsprovider/pkg/dnsprovider"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/sshbootstrap"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// DefaultSSHUser is the remote user used when a machine does not specify one
+const DefaultSSHUser = "root"
+
+// MetalCloud exposes the operations kOps needs on clusters of pre-existing machines,
+// which are configured over SSH rather than through a cloud API
+type MetalCloud interface {
+	fi.Cloud
+	// SSHConfig returns the SSH client configuration for connecting to a machine as the given user.
+	// The machine must present the host key with the given fingerprint or, if none is given,
+	// a host key listed for it in the known_hosts file.
+	SSHConfig(user string, hostKeyFingerprint string) (*ssh.ClientConfig, error)
+	// SSHDuringDryRun is true if machines are connected to during a dry run, to compare their state
+	SSHDuringDryRun() bool
+	// EnableSSHDuringDryRun connects to machines during dry runs, for commands that need their state
+	EnableSSHDuringDryRun()
+}
+
+// static compile time check to validate MetalCloud's fi.Cloud Interface.
+var _ fi.Cloud = &metalCloudImplementation{}
+
+type metalCloudImplementation struct {
+	// privateKeyPath is the path of the SSH private key used to connect to machines
+	privateKeyPath string
+	// knownHostsPath is the path of the known_hosts file used to verify the host keys of machines
+	knownHostsPath string
+
+	mutex           sync.Mutex
+	signer          ssh.Signer
+	sshDuringDryRun bool
+}
+
+// NewMetalCloud returns a MetalCloud. The SSH private key used to connect to machines
+// is read from KOPS_METAL_SSH_PRIVATE_KEY, defaulting to ~/.ssh/id_rsa, and their host keys
+// are verified against KOPS_METAL_SSH_KNOWN_HOSTS, defaulting to ~/.ssh/known_hosts.
+// Machines are only connected to during a dry run if KOPS_METAL_SSH_DURING_DRY_RUN is true.
+func NewMetalCloud() (MetalCloud, error) {
+	privateKeyPath := os.Getenv("KOPS_METAL_SSH_PRIVATE_KEY")
+	if privateKeyPath == "" {
+		privateKeyPath = "~/.ssh/id_rsa"
+	}
+
+	knownHostsPath := os.Getenv("KOPS_METAL_SSH_KNOWN_HOSTS")
+	if knownHostsPath == "" {
+		knownHostsPath = "~/.ssh/known_hosts"
+	}
+
+	sshDuringDryRun := false
+	if s := os.Getenv("KOPS_METAL_SSH_DURING_DRY_RUN"); s != "" {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing KOPS_METAL_SSH_DURING_DRY_RUN %q: %v", s, err)
+		}
+		sshDuringDryRun = b
+	}
+
+	return &metalCloudImplementation{
+		privateKeyPath:  expandHome(privateKeyPath),
+		knownHostsPath:  expandHome(knownHostsPath),
+		sshDuringDryRun: sshDuringDryRun,
+	}, nil
+}
+
+func expandHome(p string) string {
+	if strings.HasPrefix(p, "~/") {
+		return filepath.Join(os.Getenv("HOME"), p[2:])
+	}
+	return p
+}
+
+func (c *metalCloudImplementation) SSHDuringDryRun() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.sshDuringDryRun
+}
+
+func (c *metalCloudImplementation) EnableSSHDuringDryRun() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.sshDuringDryRun = true
+}
+
+func (c *metalCloudImplementation) SSHConfig(user string, hostKeyFingerprint string) (*ssh.ClientConfig, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.signer == nil {
+		key, err := ioutil.ReadFile(c.privateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading SSH private key %q: %v", c.privateKeyPath, err)
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing SSH private key %q: %v", c.privateKeyPath, err)
+		}
+		c.signer = signer
+	}
+
+	if user == "" {
+		user = DefaultSSHUser
+	}
+
+	return &ssh.ClientConfig{
+		User: user,
+		Auth: []ssh.AuthMethod{
+			ssh.PublicKeys(c.signer),
+		},
+		HostKeyCallback: sshbootstrap.HostKeyCallback(hostKeyFingerprint, c.knownHostsPath),
+	}, nil
+}
+
+// ProviderID returns the kops api identifier for the metal cloud provider
+func (c *metalCloudImplementation) ProviderID() kops.CloudProviderID {
+	return kops.CloudProviderMetal
+}
+
+// Region is not meaningful for metal clusters
+func (c *metalCloudImplementation) Region() string {
+	return ""
+}
+
+// DNS is not supported; metal clusters use gossip DNS
+func (c *metalCloudImplementation) DNS() (dnsprovider.Interface, error) {
+	return nil, errors.New("DNS is not supported on metal; use a gossip based cluster name")
+}
+
+// FindVPCInfo is not implemented, it's only here to satisfy the fi.Cloud interface
+func (c *metalCloudImplementation) FindVPCInfo(id string) (*fi.VPCInfo, error) {
+	return nil, errors.New("not implemented")
+}
+
+// DeleteInstance is not supported, as kOps does not own the machines
+func (c *metalCloudImplementation) DeleteInstance(i *cloudinstances.CloudInstance) error {
+	return fmt.Errorf("metal cloud provider does not support deleting machine %q", i.ID)
+}
+
+// DeleteGroup is not supported, as kOps does not own the machines
+func (c *metalCloudImplementation) DeleteGroup(g *cloudinstances.CloudInstanceGroup) error {
+	return fmt.Errorf("metal cloud provider does not support deleting instance group %q", g.HumanName)
+}
+
+// DetachInstance is not supported, as there is no spare capacity to surge into
+func (c *metalCloudImplementation) DetachInstance(i *cloudinstances.CloudInstance) error {
+	return fmt.Errorf("metal cloud provider does not support surging")
+}
+
+// GetCloudGroups returns the machines listed in each instance group.
+// Machines are matched to nodes by name, and are always reported as up to date,
+// because kOps reconfigures them in place rather than replacing them.
+func (c *metalCloudImplementation) GetCloudGroups(cluster *kops.Cluster, instancegroups []*kops.InstanceGroup, warnUnmatched bool, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error) {
+	nodeMap := cloudinstances.GetNodeMap(nodes, cluster)
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	for _, ig := range instancegroups {
+		cg := &cloudinstances.CloudInstanceGroup{
+			HumanName:     ig.ObjectMeta.Name,
+			InstanceGroup: ig,
+			MinSize:       len(ig.Spec.Machines),
+			TargetSize:    len(ig.Spec.Machines),
+			MaxSize:       len(ig.Spec.Machines),
+		}
+
+		for _, machine := range ig.Spec.Machines {
+			node := nodeMap[machine.Name]
+			if node == nil && warnUnmatched {
+				klog.Warningf("machine %q in instance group %q has not joined the cluster", machine.Name, ig.ObjectMeta.Name)
+			}
+			cm, err := cg.NewCloudInstance(machine.Name, cloudinstances.CloudInstanceStatusUpToDate, node)
+			if err != nil {
+				return nil, fmt.Errorf("error creating cloud instance group member: %v", err)
+			}
+			cm.MachineType = ig.Spec.MachineType
+			if ig.Spec.Role == kops.InstanceGroupRoleMaster {
+				cm.Roles = []string{string(kops.InstanceGroupRoleMaster)}
+			} else {
+				cm.Roles = []string{string(kops.InstanceGroupRoleNode)}
+			}
+		}
+
+		groups[ig.ObjectMeta.Name] = cg
+	}
+
+	return groups, nil
+}
+
+// FindClusterStatus returns an empty status, as etcd volumes are local directories on the machines
+func (c *metalCloudImplementation) FindClusterStatus(cluster *kops.Cluster) (*kops.ClusterStatus, error) {
+	return &kops.ClusterStatus{}, nil
+}
+
+// GetApiIngressStatus returns no ingresses, as there is no load balancer in front of the API
+func (c *metalCloudImplementation) GetApiIngressStatus(cluster *kops.Cluster) ([]fi.ApiIngressStatus, error) {
+	return nil, nil
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metaltasks
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/sshbootstrap"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/metal"
+)
+
+// Machine is a pre-existing machine that is bootstrapped by running nodeup over SSH
+// +kops:fitask
+type Machine struct {
+	Name      *string
+	Lifecycle fi.Lifecycle
+
+	Address *string
+	SSHUser *string
+	// SSHHostKeyFingerprint is the fingerprint of the machine's host key; if unset, the host key is verified with known_hosts
+	SSHHostKeyFingerprint *string
+
+	// UserData is the bootstrap script, which installs and runs nodeup
+	UserData fi.Resource
+	// BootstrapHash is the hash of the bootstrap script that has been run on the machine
+	BootstrapHash *string
+}
+
+var _ fi.CompareWithID = &Machine{}
+
+func (m *Machine) CompareWithID() *string {
+	return m.Name
+}
+
+func (m *Machine) Find(c *fi.Context) (*Machine, error) {
+	cloud := c.Cloud.(metal.MetalCloud)
+
+	userData, err := fi.ResourceAsString(m.UserData)
+	if err != nil {
+		return nil, err
+	}
+	m.BootstrapHash = fi.String(sshbootstrap.HashBootstrapScript(userData))
+
+	actual := &Machine{
+		Name:                  m.Name,
+		Address:               m.Address,
+		SSHUser:               m.SSHUser,
+		SSHHostKeyFingerprint: m.SSHHostKeyFingerprint,
+		UserData:              m.UserData,
+
+		// Ignore system fields
+		Lifecycle: m.Lifecycle,
+	}
+
+	// Reading the bootstrap hash needs a root shell on the machine, which a preview only opens if asked to
+	if _, ok := c.Target.(*fi.DryRunTarget); ok && !cloud.SSHDuringDryRun() {
+		klog.Infof("not connecting to machine %q during a dry run, so it is shown as if it will be bootstrapped; set KOPS_METAL_SSH_DURING_DRY_RUN=true to compare its bootstrap script", fi.StringValue(m.Name))
+		return actual, nil
+	}
+
+	client, err := dialMachine(cloud, m, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	hash, err := sshbootstrap.BootstrapHash(client, fi.StringValue(m.SSHUser))
+	if err != nil {
+		return nil, err
+	}
+
+	if hash != "" {
+		actual.BootstrapHash = fi.String(hash)
+	}
+
+	return actual, nil
+}
+
+func (m *Machine) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(m, c)
+}
+
+func (_ *Machine) CheckChanges(a, e, changes *Machine) error {
+	if a != nil {
+		if changes.Name != nil {
+			return fi.CannotChangeField("Name")
+		}
+	} else {
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		if e.Address == nil {
+			return fi.RequiredField("Address")
+		}
+	}
+	return nil
+}
+
+func (_ *Machine) RenderMetal(t *metal.MetalAPITarget, a, e, changes *Machine) error {
+	userData, err := fi.ResourceAsString(e.UserData)
+	if err != nil {
+		return err
+	}
+
+	client, err := dialMachine(t.Cloud, e, nil)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	klog.Infof("running bootstrap script on machine %q", fi.StringValue(e.Name))
+
+	if err := sshbootstrap.RunBootstrapScript(client, fi.StringValue(e.SSHUser), userData); err != nil {
+		return fmt.Errorf("error bootstrapping machine %q: %v", fi.StringValue(e.Name), err)
+	}
+
+	return nil
+}
+
+// InspectMachine connects to the machine at the address and returns its hostname, which enrollment uses
+// as the machine's name, and the fingerprint of its verified host key, which enrollment records
+func InspectMachine(cloud metal.MetalCloud, address string, sshUser string, hostKeyFingerprint string) (string, string, error) {
+	m := &Machine{
+		Name:    fi.String(address),
+		Address: fi.String(address),
+		SSHUser: fi.String(sshUser),
+	}
+	if hostKeyFingerprint != "" {
+		m.SSHHostKeyFingerprint = fi.String(hostKeyFingerprint)
+	}
+
+	var fingerprint string
+	client, err := dialMachine(cloud, m, func(key ssh.PublicKey) {
+		fingerprint = ssh.FingerprintSHA256(key)
+	})
+	if err != nil {
+		return "", "", err
+	}
+	defer client.Close()
+
+	out, err := sshbootstrap.RunCommand(client, "", "hostname", nil)
+	if err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(string(out)), fingerprint, nil
+}
+
+// dialMachine connects to the machine, calling onHostKey with its host key once it has been verified
+func dialMachine(cloud metal.MetalCloud, m *Machine, onHostKey func(key ssh.PublicKey)) (*ssh.Client, error) {
+	sshConfig, err := cloud.SSHConfig(fi.StringValue(m.SSHUser), fi.StringValue(m.SSHHostKeyFingerprint))
+	if err != nil {
+		return nil, err
+	}
+
+	if onHostKey != nil {
+		verify := sshConfig.HostKeyCallback
+		sshConfig.HostKeyCallback = func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			if err := verify(hostname, remote, key); err != nil {
+				return err
+			}
+			onHostKey(key)
+			return nil
+		}
+	}
+
+	return sshbootstrap.Dial(fi.StringValue(m.Name), fi.StringValue(m.Address), sshConfig)
+}
@@ -926,6 +926,8 @@ func setupNetworking(opt *NewClusterOptions, cluster *api.Cluster) error {
 		cluster.Spec.Networking.LyftVPC = &api.LyftVPCNetworkingSpec{}
 	case "gce":
 		cluster.Spec.Networking.GCE = &api.GCENetworkingSpec{}
+	case "antrea":
+		cluster.Spec.Networking.Antrea = &api.AntreaNetworkingSpec{}
 	default:
 		return fmt.Errorf("unknown networking mode %q", opt.Networking)
 	}
@@ -141,6 +141,9 @@ func osBuildCloudInstanceGroup(c OpenstackCloud, cluster *kops.Cluster, ig *kops
 		if err != nil {
 			return nil, fmt.Errorf("error creating cloud instance group member: %v", err)
 		}
+		if status == cloudinstances.CloudInstanceStatusNeedsUpdate {
+			cm.AddNeedUpdateReason(cloudinstances.NeedUpdateReasonConfigurationChanged, fmt.Sprintf("instance was created at generation %q, current is %q", observedName, generationName))
+		}
 
 		if server.Flavor["original_name"] != nil {
 			cm.MachineType = server.Flavor["original_name"].(string)
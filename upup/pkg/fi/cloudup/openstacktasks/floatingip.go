@@ -148,6 +148,11 @@ func (e *FloatingIP) Find(c *fi.Context) (*FloatingIP, error) {
 			LB:        e.LB,
 			Lifecycle: e.Lifecycle,
 		}
+		if e.IP != nil {
+			// A pre-existing floating IP keeps its own description
+			actual.Name = e.Name
+			actual.IP = fi.String(fip.FloatingIP)
+		}
 		e.ID = actual.ID
 		return actual, nil
 	}
@@ -262,6 +267,32 @@ func (_ *FloatingIP) ShouldCreate(a, e, changes *FloatingIP) (bool, error) {
 func (f *FloatingIP) RenderOpenstack(t *openstack.OpenstackAPITarget, a, e, changes *FloatingIP) error {
 	cloud := t.Cloud.(openstack.OpenstackCloud)
 
+	if a == nil && e.LB != nil && e.IP != nil {
+		// Attach a pre-existing floating IP to the load balancer
+		fips, err := cloud.ListL3FloatingIPs(l3floatingip.ListOpts{
+			FloatingIP: fi.StringValue(e.IP),
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to list floating ip %s: %v", fi.StringValue(e.IP), err)
+		}
+		if len(fips) != 1 {
+			return fmt.Errorf("Expected exactly one floating ip with address %s, found %d", fi.StringValue(e.IP), len(fips))
+		}
+		if fips[0].PortID != "" && fips[0].PortID != fi.StringValue(e.LB.PortID) {
+			return fmt.Errorf("Floating ip %s is already associated to port %s", fi.StringValue(e.IP), fips[0].PortID)
+		}
+
+		_, err = l3floatingip.Update(cloud.NetworkingClient(), fips[0].ID, l3floatingip.UpdateOpts{
+			PortID: e.LB.PortID,
+		}).Extract()
+		if err != nil {
+			return fmt.Errorf("Failed to associate floating ip %s to port %s: %v", fi.StringValue(e.IP), fi.StringValue(e.LB.PortID), err)
+		}
+
+		e.ID = fi.String(fips[0].ID)
+		return nil
+	}
+
 	if a == nil {
 		external, err := cloud.GetExternalNetwork()
 		if err != nil {
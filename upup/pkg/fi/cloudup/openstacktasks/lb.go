@@ -40,6 +40,8 @@ type LB struct {
 	Lifecycle     fi.Lifecycle
 	PortID        *string
 	SecurityGroup *SecurityGroup
+	// Provider is the Octavia provider driver (e.g. amphora or ovn) of the load balancer.
+	Provider *string
 }
 
 const (
@@ -127,6 +129,11 @@ func NewLBTaskFromCloud(cloud openstack.OpenstackCloud, lifecycle fi.Lifecycle,
 		VipSubnet: fi.String(lb.VipSubnetID),
 	}
 
+	// Only compare the provider when one was requested, as the default depends on the cloud
+	if find != nil && find.Provider != nil {
+		actual.Provider = fi.String(lb.Provider)
+	}
+
 	if secGroup {
 		sg, err := getSecurityGroupByName(&SecurityGroup{Name: fi.String(lb.Name)}, osCloud)
 		if err != nil {
@@ -184,6 +191,9 @@ func (_ *LB) CheckChanges(a, e, changes *LB) error {
 		if changes.Name != nil {
 			return fi.CannotChangeField("Name")
 		}
+		if changes.Provider != nil {
+			return fi.CannotChangeField("Provider")
+		}
 	}
 	return nil
 }
@@ -205,6 +215,7 @@ func (_ *LB) RenderOpenstack(t *openstack.OpenstackAPITarget, a, e, changes *LB)
 		lbopts := loadbalancers.CreateOpts{
 			Name:        fi.StringValue(e.Name),
 			VipSubnetID: subnets[0].ID,
+			Provider:    fi.StringValue(e.Provider),
 		}
 		lb, err := t.Cloud.CreateLB(lbopts)
 		if err != nil {
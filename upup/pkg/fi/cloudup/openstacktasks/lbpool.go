@@ -31,6 +31,8 @@ type LBPool struct {
 	Name         *string
 	Lifecycle    fi.Lifecycle
 	Loadbalancer *LB
+	// Method is the load balancing algorithm of the pool, defaulting to ROUND_ROBIN.
+	Method *string
 }
 
 // GetDependencies returns the dependencies of the Instance task
@@ -74,6 +76,9 @@ func NewLBPoolTaskFromCloud(cloud openstack.OpenstackCloud, lifecycle fi.Lifecyc
 		a.Loadbalancer = loadbalancerTask
 	}
 	if find != nil {
+		if find.Method != nil {
+			a.Method = fi.String(pool.LBMethod)
+		}
 		// Update all search terms
 		find.ID = a.ID
 		find.Name = a.Name
@@ -120,6 +125,9 @@ func (_ *LBPool) CheckChanges(a, e, changes *LBPool) error {
 		if changes.Name != nil {
 			return fi.CannotChangeField("Name")
 		}
+		if changes.Method != nil {
+			return fi.CannotChangeField("Method")
+		}
 	}
 	return nil
 }
@@ -139,6 +147,9 @@ func (_ *LBPool) RenderOpenstack(t *openstack.OpenstackAPITarget, a, e, changes
 			Protocol:       v2pools.ProtocolTCP,
 			LoadbalancerID: fi.StringValue(e.Loadbalancer.ID),
 		}
+		if e.Method != nil {
+			poolopts.LBMethod = v2pools.LBMethod(fi.StringValue(e.Method))
+		}
 		pool, err := t.Cloud.CreatePool(poolopts)
 		if err != nil {
 			return fmt.Errorf("error creating LB pool: %v", err)
@@ -126,6 +126,10 @@ func (c *populateClusterSpec) run(clientset simple.Clientset) error {
 					return fmt.Errorf("EtcdClusters #%d did not specify a Name", i)
 				}
 
+				if etcd.IsExternal() {
+					continue
+				}
+
 				for i, m := range etcd.Members {
 					if m.Name == "" {
 						return fmt.Errorf("EtcdMember #%d of etcd-cluster %s did not specify a Name", i, etcd.Name)
@@ -264,6 +268,7 @@ func (c *populateClusterSpec) run(clientset simple.Clientset) error {
 			codeModels = append(codeModels, &components.DefaultsOptionsBuilder{Context: optionsContext})
 			codeModels = append(codeModels, &components.EtcdOptionsBuilder{OptionsContext: optionsContext})
 			codeModels = append(codeModels, &etcdmanager.EtcdManagerOptionsBuilder{OptionsContext: optionsContext})
+			codeModels = append(codeModels, &components.HardeningOptionsBuilder{OptionsContext: optionsContext})
 			codeModels = append(codeModels, &components.KubeAPIServerOptionsBuilder{OptionsContext: optionsContext})
 			codeModels = append(codeModels, &components.DockerOptionsBuilder{OptionsContext: optionsContext})
 			codeModels = append(codeModels, &components.ContainerdOptionsBuilder{OptionsContext: optionsContext})
@@ -272,6 +277,7 @@ func (c *populateClusterSpec) run(clientset simple.Clientset) error {
 			codeModels = append(codeModels, &components.KubeletOptionsBuilder{OptionsContext: optionsContext})
 			codeModels = append(codeModels, &components.KubeControllerManagerOptionsBuilder{OptionsContext: optionsContext})
 			codeModels = append(codeModels, &components.KubeSchedulerOptionsBuilder{OptionsContext: optionsContext})
+			codeModels = append(codeModels, &components.FIPSOptionsBuilder{OptionsContext: optionsContext})
 			codeModels = append(codeModels, &components.KubeProxyOptionsBuilder{Context: optionsContext})
 			codeModels = append(codeModels, &components.CloudConfigurationOptionsBuilder{Context: optionsContext})
 			codeModels = append(codeModels, &components.CalicoOptionsBuilder{Context: optionsContext})
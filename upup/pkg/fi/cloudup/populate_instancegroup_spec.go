@@ -75,6 +75,16 @@ func PopulateInstanceGroupSpec(cluster *kops.Cluster, input *kops.InstanceGroup,
 	ig := &kops.InstanceGroup{}
 	reflectutils.JSONMergeStruct(ig, input)
 
+	// The size of a metal instance group is the number of machines listed in it
+	if cloud.ProviderID() == kops.CloudProviderMetal {
+		if ig.Spec.MinSize == nil {
+			ig.Spec.MinSize = fi.Int32(int32(len(ig.Spec.Machines)))
+		}
+		if ig.Spec.MaxSize == nil {
+			ig.Spec.MaxSize = fi.Int32(int32(len(ig.Spec.Machines)))
+		}
+	}
+
 	// TODO: Clean up
 	if ig.IsMaster() {
 		if ig.Spec.MachineType == "" {
@@ -121,7 +131,8 @@ func PopulateInstanceGroupSpec(cluster *kops.Cluster, input *kops.InstanceGroup,
 		}
 	}
 
-	if ig.Spec.Image == "" {
+	// Machines on metal are provisioned with their operating system before kOps manages them
+	if ig.Spec.Image == "" && cloud.ProviderID() != kops.CloudProviderMetal {
 		architecture, err := MachineArchitecture(cloud, ig.Spec.MachineType)
 		if err != nil {
 			return nil, fmt.Errorf("unable to determine machine architecture for InstanceGroup %q: %v", ig.ObjectMeta.Name, err)
@@ -31,8 +31,10 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"path"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -51,10 +53,12 @@ import (
 	"k8s.io/kops/pkg/model"
 	"k8s.io/kops/pkg/resources/spotinst"
 	"k8s.io/kops/pkg/wellknownports"
+	"k8s.io/kops/protokube/pkg/gossip/kopscontroller"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
 	"k8s.io/kops/util/pkg/env"
+	"k8s.io/kops/util/pkg/proxy"
 )
 
 // TemplateFunctions provides a collection of methods used throughout the templates
@@ -97,8 +101,14 @@ func (tf *TemplateFunctions) AddTo(dest template.FuncMap, secretStore fi.SecretS
 
 	dest["GetInstanceGroup"] = tf.GetInstanceGroup
 	dest["GetNodeInstanceGroups"] = tf.GetNodeInstanceGroups
+	dest["ClusterAutoscalerPriorities"] = tf.ClusterAutoscalerPriorities
+	dest["ClusterAutoscalerBalancingIgnoreLabels"] = tf.ClusterAutoscalerBalancingIgnoreLabels
 	dest["HasHighlyAvailableControlPlane"] = tf.HasHighlyAvailableControlPlane
+	dest["AppArmorAddonProfile"] = tf.AppArmorAddonProfile
+	dest["HasWindowsInstanceGroups"] = tf.HasWindowsInstanceGroups
 	dest["ControlPlaneControllerReplicas"] = tf.ControlPlaneControllerReplicas
+	dest["ClusterNodeCount"] = tf.ClusterNodeCount
+	dest["IsLargeCluster"] = tf.IsLargeCluster
 
 	dest["CloudTags"] = tf.CloudTagsForInstanceGroup
 	dest["KubeDNS"] = func() *kops.KubeDNSConfig {
@@ -117,6 +127,14 @@ func (tf *TemplateFunctions) AddTo(dest template.FuncMap, secretStore fi.SecretS
 
 	dest["KopsControllerArgv"] = tf.KopsControllerArgv
 	dest["KopsControllerConfig"] = tf.KopsControllerConfig
+	dest["KopsControllerMetricsPort"] = func() int {
+		return wellknownports.KopsControllerMetrics
+	}
+	dest["AuditLogShipperConfig"] = tf.AuditLogShipperConfig
+	dest["LogShipperConfig"] = tf.LogShipperConfig
+	dest["AuditLogDir"] = func() string {
+		return path.Dir(fi.StringValue(cluster.Spec.KubeAPIServer.AuditLogPath))
+	}
 	dest["DnsControllerArgv"] = tf.DNSControllerArgv
 	dest["ExternalDnsArgv"] = tf.ExternalDNSArgv
 	dest["CloudControllerConfigArgv"] = tf.CloudControllerConfigArgv
@@ -135,6 +153,12 @@ func (tf *TemplateFunctions) AddTo(dest template.FuncMap, secretStore fi.SecretS
 	dest["UseKopsControllerForNodeBootstrap"] = func() bool {
 		return tf.UseKopsControllerForNodeBootstrap()
 	}
+	dest["UseKopsControllerForInstanceGroupScaling"] = func() bool {
+		return apiModel.UseKopsControllerForInstanceGroupScaling(tf.Cluster)
+	}
+	dest["UseKopsControllerForGossipDiscovery"] = func() bool {
+		return apiModel.UseKopsControllerForGossipDiscovery(tf.Cluster)
+	}
 
 	dest["DO_TOKEN"] = func() string {
 		return os.Getenv("DIGITALOCEAN_ACCESS_TOKEN")
@@ -298,6 +322,48 @@ func (tf *TemplateFunctions) HasHighlyAvailableControlPlane() bool {
 	return false
 }
 
+// largeClusterNodeCount is the number of nodes from which the metrics addons run in their highly available configuration
+const largeClusterNodeCount = 100
+
+// ClusterNodeCount returns the number of nodes the cluster can grow to, based on the sizes of its instance groups
+func (tf *TemplateFunctions) ClusterNodeCount() int {
+	count := 0
+	for _, ig := range tf.InstanceGroups {
+		if ig.Spec.MaxSize != nil {
+			count += int(*ig.Spec.MaxSize)
+		} else if ig.Spec.MinSize != nil {
+			count += int(*ig.Spec.MinSize)
+		} else {
+			count++
+		}
+	}
+	return count
+}
+
+// IsLargeCluster returns true if the cluster can grow to a size where the metrics addons should be highly available
+func (tf *TemplateFunctions) IsLargeCluster() bool {
+	return tf.ClusterNodeCount() >= largeClusterNodeCount
+}
+
+// AppArmorAddonProfile returns the AppArmor profile of the addons that run on the control plane, if all the
+// control plane instance groups load it, or "" otherwise
+func (tf *TemplateFunctions) AppArmorAddonProfile() string {
+	found := false
+	for _, ig := range tf.InstanceGroups {
+		if ig.Spec.Role != kops.InstanceGroupRoleMaster {
+			continue
+		}
+		if ig.Spec.SecurityModules == nil || !fi.BoolValue(ig.Spec.SecurityModules.AppArmor) {
+			return ""
+		}
+		found = true
+	}
+	if !found {
+		return ""
+	}
+	return "localhost/" + kops.AppArmorAddonProfile
+}
+
 // CloudControllerConfigArgv returns the args to external cloud controller
 func (tf *TemplateFunctions) CloudControllerConfigArgv() ([]string, error) {
 	cluster := tf.Cluster
@@ -365,6 +431,10 @@ func (tf *TemplateFunctions) DNSControllerArgv() ([]string, error) {
 		if cluster.Spec.ExternalDNS.WatchIngress != nil {
 			watchIngress = fi.BoolValue(cluster.Spec.ExternalDNS.WatchIngress)
 		}
+		// Ingresses are left to external-dns when it is the provider
+		if cluster.Spec.ExternalDNS.Provider == kops.ExternalDNSProviderExternalDNS {
+			watchIngress = false
+		}
 
 		if watchIngress {
 			klog.Warningln("--watch-ingress=true set on dns-controller")
@@ -380,7 +450,10 @@ func (tf *TemplateFunctions) DNSControllerArgv() ([]string, error) {
 		argv = append(argv, "--dns=gossip")
 
 		// Configuration specifically for the DNS controller gossip
-		if cluster.Spec.DNSControllerGossipConfig != nil {
+		if apiModel.UseKopsControllerForGossipDiscovery(cluster) {
+			// The records are kept in a ConfigMap and served by kops-controller, so there is no mesh to join
+			argv = append(argv, "--gossip-protocol="+kops.GossipProtocolKopsController)
+		} else if cluster.Spec.DNSControllerGossipConfig != nil {
 			if cluster.Spec.DNSControllerGossipConfig.Protocol != nil {
 				argv = append(argv, "--gossip-protocol="+*cluster.Spec.DNSControllerGossipConfig.Protocol)
 			}
@@ -452,6 +525,9 @@ func (tf *TemplateFunctions) DNSControllerArgv() ([]string, error) {
 	}
 	// permit wildcard updates
 	argv = append(argv, "--zone=*/*")
+	if cluster.Spec.API != nil && cluster.Spec.API.DNS != nil && cluster.Spec.API.DNS.InternalZoneID != "" {
+		argv = append(argv, "--internal-zone=*/"+cluster.Spec.API.DNS.InternalZoneID)
+	}
 	// Verbose, but not crazy logging
 	argv = append(argv, "-v=2")
 
@@ -463,8 +539,9 @@ func (tf *TemplateFunctions) KopsControllerConfig() (string, error) {
 	cluster := tf.Cluster
 
 	config := &kopscontrollerconfig.Options{
-		Cloud:      cluster.Spec.CloudProvider,
-		ConfigBase: cluster.Spec.ConfigBase,
+		Cloud:          cluster.Spec.CloudProvider,
+		ConfigBase:     cluster.Spec.ConfigBase,
+		MetricsAddress: fmt.Sprintf(":%d", wellknownports.KopsControllerMetrics),
 	}
 
 	if featureflag.CacheNodeidentityInfo.Enabled() {
@@ -484,6 +561,9 @@ func (tf *TemplateFunctions) KopsControllerConfig() (string, error) {
 		if cluster.Spec.Networking.Kuberouter != nil {
 			certNames = append(certNames, "kube-router")
 		}
+		if cluster.Spec.Networking.Calico != nil && tf.HasWindowsInstanceGroups() {
+			certNames = append(certNames, "calico-windows")
+		}
 
 		pkiDir := "/etc/kubernetes/kops-controller/pki"
 		config.Server = &kopscontrollerconfig.ServerOptions{
@@ -526,9 +606,36 @@ func (tf *TemplateFunctions) KopsControllerConfig() (string, error) {
 				NodesRoles: nodesRoles.List(),
 				Region:     tf.Region,
 			}
+		case kops.CloudProviderGCE:
+			config.Server.Provider.GCE = &gce.GCEVerifierOptions{
+				ProjectID:   tf.cloud.(gce.GCECloud).Project(),
+				ClusterName: cluster.ObjectMeta.Name,
+			}
 		default:
 			return "", fmt.Errorf("unsupported cloud provider %s", cluster.Spec.CloudProvider)
 		}
+
+		if apiModel.UseJoinTokensForNodeBootstrap(cluster) {
+			config.Server.JoinTokenKeyPath = path.Join(pkiDir, "join-token.key")
+		}
+
+		if apiModel.UseKopsControllerForInstanceGroupScaling(cluster) {
+			config.Server.InstanceGroupScaling = &kopscontrollerconfig.InstanceGroupScalingOptions{
+				ClusterName:         cluster.ObjectMeta.Name,
+				StateStoreLockTable: cluster.Spec.InstanceGroupScaling.StateStoreLockTable,
+			}
+		}
+
+		if apiModel.UseKopsControllerForGossipDiscovery(cluster) {
+			config.Server.Discovery = &kopscontrollerconfig.DiscoveryOptions{
+				ConfigMapName: kopscontroller.ConfigMapName,
+			}
+		}
+
+		if cluster.Spec.NodeCertificates != nil && cluster.Spec.NodeCertificates.KubeletValidity != nil {
+			config.Server.KubeletCertificateValidity = cluster.Spec.NodeCertificates.KubeletValidity
+			config.ApproveKubeletCertificates = true
+		}
 	}
 
 	// To avoid indentation problems, we marshal as json.  json is a subset of yaml
@@ -540,6 +647,222 @@ func (tf *TemplateFunctions) KopsControllerConfig() (string, error) {
 	return string(b), nil
 }
 
+// AuditLogShipperConfig returns the fluent-bit configuration that ships the apiserver audit log
+func (tf *TemplateFunctions) AuditLogShipperConfig() (string, error) {
+	cluster := tf.Cluster
+	if cluster.Spec.Audit == nil || cluster.Spec.Audit.LogShipping == nil {
+		return "", fmt.Errorf("audit log shipping is not configured")
+	}
+	shipping := cluster.Spec.Audit.LogShipping
+
+	var lines []string
+	add := func(key, value string) {
+		lines = append(lines, fmt.Sprintf("    %-18s%s", key, value))
+	}
+
+	lines = append(lines, "[SERVICE]")
+	add("Flush", "5")
+	add("Log_Level", "info")
+	add("Daemon", "off")
+	lines = append(lines, "", "[INPUT]")
+	add("Name", "tail")
+	add("Tag", "kube-apiserver-audit")
+	add("Path", fi.StringValue(cluster.Spec.KubeAPIServer.AuditLogPath))
+	add("DB", "/var/lib/fluent-bit/kube-apiserver-audit.db")
+	add("Mem_Buf_Limit", "16MB")
+	add("Skip_Long_Lines", "On")
+	add("Refresh_Interval", "10")
+	lines = append(lines, "", "[OUTPUT]")
+
+	s3Output := func(bucket, prefix, region string) {
+		fluentBitS3Output(add, "kube-apiserver-audit", bucket, prefix, "/${NODE_NAME}/%Y/%m/%d/%H%M%S-$UUID.gz", region)
+	}
+
+	switch {
+	case shipping.S3 != nil:
+		region := shipping.S3.Region
+		if region == "" {
+			region = tf.Region
+		}
+		s3Output(shipping.S3.Bucket, shipping.S3.Prefix, region)
+	case shipping.CloudWatch != nil:
+		region := shipping.CloudWatch.Region
+		if region == "" {
+			region = tf.Region
+		}
+		add("Name", "cloudwatch_logs")
+		add("Match", "kube-apiserver-audit")
+		add("region", region)
+		add("log_group_name", shipping.CloudWatch.LogGroup)
+		add("log_stream_name", "kube-apiserver-audit-${NODE_NAME}")
+		add("auto_create_group", "On")
+	case shipping.GCS != nil:
+		// GCS accepts S3 requests signed with an HMAC key
+		s3Output(shipping.GCS.Bucket, shipping.GCS.Prefix, "auto")
+		add("endpoint", "https://storage.googleapis.com")
+	default:
+		return "", fmt.Errorf("no audit log shipping destination configured")
+	}
+
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// fluentBitS3Output adds the settings of an s3 output of fluent-bit, uploading the records matching match
+// to the key of the bucket under prefix
+func fluentBitS3Output(add func(key, value string), match, bucket, prefix, key, region string) {
+	if prefix = strings.Trim(prefix, "/"); prefix != "" {
+		key = "/" + prefix + key
+	}
+	add("Name", "s3")
+	add("Match", match)
+	add("bucket", bucket)
+	add("region", region)
+	add("total_file_size", "50M")
+	add("upload_timeout", "5m")
+	add("use_put_object", "On")
+	add("compression", "gzip")
+	add("s3_key_format", key)
+	add("store_dir", "/var/lib/fluent-bit/s3")
+}
+
+// LogShipperConfig returns the fluent-bit configuration of the log shipper, collecting the logs of the pods
+// and of the node services and sending them to the configured sinks
+func (tf *TemplateFunctions) LogShipperConfig() (string, error) {
+	cluster := tf.Cluster
+	logging := cluster.Spec.Logging
+	if logging == nil {
+		return "", fmt.Errorf("logging is not configured")
+	}
+
+	var lines []string
+	add := func(key, value string) {
+		lines = append(lines, fmt.Sprintf("    %-24s%s", key, value))
+	}
+	section := func(name string) {
+		if len(lines) != 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "["+name+"]")
+	}
+
+	section("SERVICE")
+	add("Flush", "5")
+	add("Log_Level", "info")
+	add("Daemon", "off")
+
+	section("INPUT")
+	add("Name", "tail")
+	add("Tag", "kube.*")
+	add("Path", "/var/log/containers/*.log")
+	add("multiline.parser", "docker, cri")
+	add("DB", "/var/lib/fluent-bit/containers.db")
+	add("Mem_Buf_Limit", "16MB")
+	add("Skip_Long_Lines", "On")
+	add("Refresh_Interval", "10")
+
+	section("INPUT")
+	add("Name", "systemd")
+	add("Tag", "host.*")
+	units := []string{"kubelet.service", "kops-configuration.service", "protokube.service"}
+	if cluster.Spec.ContainerRuntime == "docker" {
+		units = append(units, "docker.service")
+	} else {
+		units = append(units, "containerd.service")
+	}
+	for _, unit := range units {
+		add("Systemd_Filter", "_SYSTEMD_UNIT="+unit)
+	}
+	add("Read_From_Tail", "On")
+	add("Strip_Underscores", "On")
+	add("DB", "/var/lib/fluent-bit/systemd.db")
+
+	section("FILTER")
+	add("Name", "kubernetes")
+	add("Match", "kube.*")
+	add("Kube_Tag_Prefix", "kube.var.log.containers.")
+	add("Merge_Log", "On")
+	add("Keep_Log", "Off")
+	add("K8S-Logging.Parser", "On")
+	add("K8S-Logging.Exclude", "On")
+
+	section("FILTER")
+	add("Name", "record_modifier")
+	add("Match", "*")
+	add("Record", "node ${NODE_NAME}")
+	add("Record", "cluster "+cluster.ObjectMeta.Name)
+
+	found := false
+	if logging.CloudWatch != nil {
+		region := logging.CloudWatch.Region
+		if region == "" {
+			region = tf.Region
+		}
+		section("OUTPUT")
+		add("Name", "cloudwatch_logs")
+		add("Match", "*")
+		add("region", region)
+		add("log_group_name", logging.CloudWatch.LogGroup)
+		add("log_stream_prefix", "${NODE_NAME}.")
+		add("auto_create_group", "On")
+		found = true
+	}
+	if logging.S3 != nil {
+		region := logging.S3.Region
+		if region == "" {
+			region = tf.Region
+		}
+		section("OUTPUT")
+		fluentBitS3Output(add, "*", logging.S3.Bucket, logging.S3.Prefix, "/${NODE_NAME}/$TAG/%Y/%m/%d/%H%M%S-$UUID.gz", region)
+		found = true
+	}
+	if logging.Stackdriver != nil {
+		section("OUTPUT")
+		add("Name", "stackdriver")
+		add("Match", "*")
+		add("resource", "gce_instance")
+		if logging.Stackdriver.ProjectID != "" {
+			add("export_to_project_id", logging.Stackdriver.ProjectID)
+		}
+		found = true
+	}
+	if logging.Loki != nil {
+		u, err := url.Parse(logging.Loki.URL)
+		if err != nil {
+			return "", fmt.Errorf("parsing loki url %q: %v", logging.Loki.URL, err)
+		}
+		port := u.Port()
+		if port == "" {
+			port = "80"
+			if u.Scheme == "https" {
+				port = "443"
+			}
+		}
+		section("OUTPUT")
+		add("Name", "loki")
+		add("Match", "*")
+		add("host", u.Hostname())
+		add("port", port)
+		if u.Scheme == "https" {
+			add("tls", "On")
+			add("tls.verify", "On")
+		}
+		if u.Path != "" {
+			add("uri", u.Path)
+		}
+		if logging.Loki.TenantID != "" {
+			add("tenant_id", logging.Loki.TenantID)
+		}
+		add("labels", "job=fluent-bit, cluster="+cluster.ObjectMeta.Name)
+		add("auto_kubernetes_labels", "On")
+		found = true
+	}
+	if !found {
+		return "", fmt.Errorf("no logging sink configured")
+	}
+
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
 // KopsControllerArgv returns the args to kops-controller
 func (tf *TemplateFunctions) KopsControllerArgv() ([]string, error) {
 	var argv []string
@@ -568,38 +891,48 @@ func (tf *TemplateFunctions) ExternalDNSArgv() ([]string, error) {
 		project := cluster.Spec.Project
 		argv = append(argv, "--provider=google")
 		argv = append(argv, "--google-project="+project)
+	case kops.CloudProviderAzure:
+		// The cloud config of the masters has the format of the azure.json file expected by external-dns
+		argv = append(argv, "--provider=azure")
+		argv = append(argv, "--azure-config-file=/etc/kubernetes/cloud.config")
 	default:
 		return nil, fmt.Errorf("unhandled cloudprovider %q", cluster.Spec.CloudProvider)
 	}
 
 	argv = append(argv, "--source=ingress")
 
+	externalDNS := cluster.Spec.ExternalDNS
+	if externalDNS == nil || externalDNS.Provider != kops.ExternalDNSProviderExternalDNS {
+		return argv, nil
+	}
+
+	argv = append(argv, "--source=service")
+	for _, domain := range externalDNS.DomainFilters {
+		argv = append(argv, "--domain-filter="+domain)
+	}
+	for _, zoneID := range externalDNS.ZoneIDFilters {
+		argv = append(argv, "--zone-id-filter="+zoneID)
+	}
+	if externalDNS.TXTOwnerID != "" {
+		argv = append(argv, "--txt-owner-id="+externalDNS.TXTOwnerID)
+	} else {
+		argv = append(argv, "--txt-owner-id="+cluster.ObjectMeta.Name)
+	}
+	if externalDNS.Policy != "" {
+		argv = append(argv, "--policy="+externalDNS.Policy)
+	} else {
+		argv = append(argv, "--policy=upsert-only")
+	}
+
 	return argv, nil
 }
 
 func (tf *TemplateFunctions) ProxyEnv() map[string]string {
-	cluster := tf.Cluster
-
 	envs := map[string]string{}
-	proxies := cluster.Spec.EgressProxy
-	if proxies == nil {
-		return envs
-	}
-	httpProxy := proxies.HTTPProxy
-	if httpProxy.Host != "" {
-		var portSuffix string
-		if httpProxy.Port != 0 {
-			portSuffix = ":" + strconv.Itoa(httpProxy.Port)
-		} else {
-			portSuffix = ""
+	for _, envVar := range proxy.GetProxyEnvVars(tf.Cluster.Spec.EgressProxy) {
+		if envVar.Value != "" {
+			envs[envVar.Name] = envVar.Value
 		}
-		url := "http://" + httpProxy.Host + portSuffix
-		envs["http_proxy"] = url
-		envs["https_proxy"] = url
-	}
-	if proxies.ProxyExcludes != "" {
-		envs["no_proxy"] = proxies.ProxyExcludes
-		envs["NO_PROXY"] = proxies.ProxyExcludes
 	}
 	return envs
 }
@@ -662,3 +995,62 @@ func (tf *TemplateFunctions) GetNodeInstanceGroups() map[string]kops.InstanceGro
 	}
 	return nodegroups
 }
+
+// ClusterAutoscalerPriorities returns the configuration of the cluster autoscaler priority expander,
+// built from the priority annotations of the node instance groups. Instance groups without a priority get priority 0.
+// It returns "" if no instance group has a priority.
+func (tf *TemplateFunctions) ClusterAutoscalerPriorities() (string, error) {
+	priorities := make(map[int][]string)
+	found := false
+	for _, ig := range tf.InstanceGroups {
+		if ig.Spec.Role != kops.InstanceGroupRoleNode || (ig.Spec.Autoscale != nil && !*ig.Spec.Autoscale) {
+			continue
+		}
+		priority := 0
+		if value, ok := ig.Annotations[kops.AnnotationClusterAutoscalerPriority]; ok {
+			p, err := strconv.Atoi(value)
+			if err != nil {
+				return "", fmt.Errorf("invalid cluster autoscaler priority %q for instance group %q: %v", value, ig.Name, err)
+			}
+			priority = p
+			found = true
+		}
+		priorities[priority] = append(priorities[priority], "^"+regexp.QuoteMeta(tf.AutoscalingGroupName(ig))+"$")
+	}
+	if !found {
+		return "", nil
+	}
+
+	var keys []int
+	for k := range priorities {
+		keys = append(keys, k)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(keys)))
+
+	// The regular expressions are single-quoted, so the backslashes don't need escaping.
+	var b strings.Builder
+	for _, k := range keys {
+		patterns := priorities[k]
+		sort.Strings(patterns)
+		fmt.Fprintf(&b, "%d:\n", k)
+		for _, pattern := range patterns {
+			fmt.Fprintf(&b, "  - '%s'\n", pattern)
+		}
+	}
+	return b.String(), nil
+}
+
+// ClusterAutoscalerBalancingIgnoreLabels returns the node labels cluster autoscaler should ignore when looking
+// for similar node groups. Every node carries the name of its instance group as a label, so without ignoring it
+// no two instance groups would ever be considered similar.
+func (tf *TemplateFunctions) ClusterAutoscalerBalancingIgnoreLabels() []string {
+	cas := tf.Cluster.Spec.ClusterAutoscaler
+	if cas == nil || !fi.BoolValue(cas.BalanceSimilarNodeGroups) {
+		return nil
+	}
+	// --balancing-ignore-label is not supported by older versions of cluster autoscaler
+	if !tf.IsKubernetesGTE("1.19") {
+		return nil
+	}
+	return []string{kops.NodeLabelInstanceGroup}
+}
@@ -19,8 +19,10 @@ package cloudup
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/upup/pkg/fi"
 )
@@ -207,3 +209,296 @@ func Test_TemplateFunctions_CloudControllerConfigArgv(t *testing.T) {
 		})
 	}
 }
+
+func Test_TemplateFunctions_AuditLogShipperConfig(t *testing.T) {
+	tests := []struct {
+		desc           string
+		shipping       *kops.AuditLogShippingSpec
+		expectedOutput []string
+	}{
+		{
+			desc:     "S3",
+			shipping: &kops.AuditLogShippingSpec{S3: &kops.AuditLogS3Spec{Bucket: "audit-logs", Prefix: "/clusters/minimal/"}},
+			expectedOutput: []string{
+				"    Name              s3\n",
+				"    bucket            audit-logs\n",
+				"    region            us-test-1\n",
+				"    s3_key_format     /clusters/minimal/${NODE_NAME}/%Y/%m/%d/%H%M%S-$UUID.gz\n",
+			},
+		},
+		{
+			desc:     "CloudWatch",
+			shipping: &kops.AuditLogShippingSpec{CloudWatch: &kops.AuditLogCloudWatchSpec{LogGroup: "audit", Region: "us-test-2"}},
+			expectedOutput: []string{
+				"    Name              cloudwatch_logs\n",
+				"    region            us-test-2\n",
+				"    log_group_name    audit\n",
+			},
+		},
+		{
+			desc:     "GCS",
+			shipping: &kops.AuditLogShippingSpec{GCS: &kops.AuditLogGCSSpec{Bucket: "audit-logs"}},
+			expectedOutput: []string{
+				"    s3_key_format     /${NODE_NAME}/%Y/%m/%d/%H%M%S-$UUID.gz\n",
+				"    endpoint          https://storage.googleapis.com\n",
+			},
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.desc, func(t *testing.T) {
+			tf := &TemplateFunctions{}
+			tf.Region = "us-test-1"
+			tf.Cluster = &kops.Cluster{Spec: kops.ClusterSpec{
+				Audit: &kops.AuditSpec{LogShipping: testCase.shipping},
+				KubeAPIServer: &kops.KubeAPIServerConfig{
+					AuditLogPath: fi.String("/var/log/kube-apiserver-audit/audit.log"),
+				},
+			}}
+
+			actual, err := tf.AuditLogShipperConfig()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(actual, "    Path              /var/log/kube-apiserver-audit/audit.log\n") {
+				t.Errorf("audit log path not found in config:\n%s", actual)
+			}
+			for _, expected := range testCase.expectedOutput {
+				if !strings.Contains(actual, expected) {
+					t.Errorf("%q not found in config:\n%s", expected, actual)
+				}
+			}
+		})
+	}
+}
+
+func Test_TemplateFunctions_LogShipperConfig(t *testing.T) {
+	tests := []struct {
+		desc           string
+		logging        *kops.LoggingSpec
+		expectedOutput []string
+	}{
+		{
+			desc:    "CloudWatch and S3",
+			logging: &kops.LoggingSpec{CloudWatch: &kops.LoggingCloudWatchSpec{LogGroup: "minimal"}, S3: &kops.LoggingS3Spec{Bucket: "logs", Prefix: "minimal"}},
+			expectedOutput: []string{
+				"    Name                    cloudwatch_logs\n",
+				"    log_group_name          minimal\n",
+				"    region                  us-test-1\n",
+				"    s3_key_format           /minimal/${NODE_NAME}/$TAG/%Y/%m/%d/%H%M%S-$UUID.gz\n",
+			},
+		},
+		{
+			desc:    "Stackdriver",
+			logging: &kops.LoggingSpec{Stackdriver: &kops.LoggingStackdriverSpec{ProjectID: "logs"}},
+			expectedOutput: []string{
+				"    Name                    stackdriver\n",
+				"    export_to_project_id    logs\n",
+			},
+		},
+		{
+			desc:    "Loki",
+			logging: &kops.LoggingSpec{Loki: &kops.LoggingLokiSpec{URL: "https://loki.example.com/loki/api/v1/push", TenantID: "minimal"}},
+			expectedOutput: []string{
+				"    host                    loki.example.com\n",
+				"    port                    443\n",
+				"    tls                     On\n",
+				"    uri                     /loki/api/v1/push\n",
+				"    tenant_id               minimal\n",
+			},
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.desc, func(t *testing.T) {
+			tf := &TemplateFunctions{}
+			tf.Region = "us-test-1"
+			tf.Cluster = &kops.Cluster{Spec: kops.ClusterSpec{
+				ContainerRuntime: "containerd",
+				Logging:          testCase.logging,
+			}}
+			tf.Cluster.Name = "minimal.example.com"
+
+			actual, err := tf.LogShipperConfig()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, expected := range append(testCase.expectedOutput,
+				"    Path                    /var/log/containers/*.log\n",
+				"    Systemd_Filter          _SYSTEMD_UNIT=containerd.service\n",
+				"    Record                  cluster minimal.example.com\n",
+			) {
+				if !strings.Contains(actual, expected) {
+					t.Errorf("%q not found in config:\n%s", expected, actual)
+				}
+			}
+		})
+	}
+
+	tf := &TemplateFunctions{}
+	tf.Cluster = &kops.Cluster{Spec: kops.ClusterSpec{Logging: &kops.LoggingSpec{}}}
+	if _, err := tf.LogShipperConfig(); err == nil {
+		t.Errorf("expected error without sinks")
+	}
+}
+
+func Test_TemplateFunctions_ClusterAutoscalerPriorities(t *testing.T) {
+	newIG := func(name string, role kops.InstanceGroupRole, priority string) *kops.InstanceGroup {
+		ig := &kops.InstanceGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       kops.InstanceGroupSpec{Role: role},
+		}
+		if priority != "" {
+			ig.Annotations = map[string]string{kops.AnnotationClusterAutoscalerPriority: priority}
+		}
+		return ig
+	}
+	fixed := newIG("fixed", kops.InstanceGroupRoleNode, "")
+	fixed.Spec.Autoscale = fi.Bool(false)
+
+	tests := []struct {
+		desc           string
+		instanceGroups []*kops.InstanceGroup
+		expected       string
+	}{
+		{
+			desc: "no priorities",
+			instanceGroups: []*kops.InstanceGroup{
+				newIG("nodes", kops.InstanceGroupRoleNode, ""),
+			},
+			expected: "",
+		},
+		{
+			desc: "priorities",
+			instanceGroups: []*kops.InstanceGroup{
+				newIG("master-us-test-1a", kops.InstanceGroupRoleMaster, ""),
+				newIG("on-demand", kops.InstanceGroupRoleNode, "10"),
+				newIG("spot-b", kops.InstanceGroupRoleNode, "50"),
+				newIG("spot-a", kops.InstanceGroupRoleNode, "50"),
+				newIG("nodes", kops.InstanceGroupRoleNode, ""),
+				fixed,
+			},
+			expected: "50:\n" +
+				"  - '^spot-a\\.minimal\\.example\\.com$'\n" +
+				"  - '^spot-b\\.minimal\\.example\\.com$'\n" +
+				"10:\n" +
+				"  - '^on-demand\\.minimal\\.example\\.com$'\n" +
+				"0:\n" +
+				"  - '^nodes\\.minimal\\.example\\.com$'\n",
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.desc, func(t *testing.T) {
+			tf := &TemplateFunctions{}
+			tf.Cluster = &kops.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "minimal.example.com"}}
+			tf.InstanceGroups = testCase.instanceGroups
+
+			actual, err := tf.ClusterAutoscalerPriorities()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual != testCase.expected {
+				t.Errorf("expected priorities:\n%s\nactual:\n%s", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+func Test_TemplateFunctions_AppArmorAddonProfile(t *testing.T) {
+	newIG := func(name string, role kops.InstanceGroupRole, appArmor bool) *kops.InstanceGroup {
+		return &kops.InstanceGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: kops.InstanceGroupSpec{
+				Role:            role,
+				SecurityModules: &kops.SecurityModulesSpec{AppArmor: fi.Bool(appArmor)},
+			},
+		}
+	}
+
+	tests := []struct {
+		desc           string
+		instanceGroups []*kops.InstanceGroup
+		expected       string
+	}{
+		{
+			desc: "all control plane instance groups",
+			instanceGroups: []*kops.InstanceGroup{
+				newIG("master-us-test-1a", kops.InstanceGroupRoleMaster, true),
+				newIG("master-us-test-1b", kops.InstanceGroupRoleMaster, true),
+				newIG("nodes", kops.InstanceGroupRoleNode, false),
+			},
+			expected: "localhost/kops-addon",
+		},
+		{
+			desc: "some control plane instance groups",
+			instanceGroups: []*kops.InstanceGroup{
+				newIG("master-us-test-1a", kops.InstanceGroupRoleMaster, true),
+				newIG("master-us-test-1b", kops.InstanceGroupRoleMaster, false),
+			},
+			expected: "",
+		},
+		{
+			desc: "no control plane instance groups",
+			instanceGroups: []*kops.InstanceGroup{
+				newIG("nodes", kops.InstanceGroupRoleNode, true),
+			},
+			expected: "",
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.desc, func(t *testing.T) {
+			tf := &TemplateFunctions{}
+			tf.InstanceGroups = testCase.instanceGroups
+
+			if actual := tf.AppArmorAddonProfile(); actual != testCase.expected {
+				t.Errorf("expected profile %q, got %q", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+func Test_TemplateFunctions_ClusterNodeCount(t *testing.T) {
+	tests := []struct {
+		desc           string
+		instanceGroups []*kops.InstanceGroup
+		expected       int
+		large          bool
+	}{
+		{
+			desc: "small cluster",
+			instanceGroups: []*kops.InstanceGroup{
+				{Spec: kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleMaster, MinSize: fi.Int32(1), MaxSize: fi.Int32(1)}},
+				{Spec: kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleNode, MinSize: fi.Int32(2), MaxSize: fi.Int32(5)}},
+			},
+			expected: 6,
+		},
+		{
+			desc: "instance groups without sizes",
+			instanceGroups: []*kops.InstanceGroup{
+				{Spec: kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleNode, MinSize: fi.Int32(3)}},
+				{Spec: kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleNode}},
+			},
+			expected: 4,
+		},
+		{
+			desc: "large cluster",
+			instanceGroups: []*kops.InstanceGroup{
+				{Spec: kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleMaster, MinSize: fi.Int32(1), MaxSize: fi.Int32(1)}},
+				{Spec: kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleNode, MinSize: fi.Int32(10), MaxSize: fi.Int32(99)}},
+			},
+			expected: 100,
+			large:    true,
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.desc, func(t *testing.T) {
+			tf := &TemplateFunctions{}
+			tf.InstanceGroups = testCase.instanceGroups
+
+			if actual := tf.ClusterNodeCount(); actual != testCase.expected {
+				t.Errorf("expected %d nodes, got %d", testCase.expected, actual)
+			}
+			if actual := tf.IsLargeCluster(); actual != testCase.large {
+				t.Errorf("expected large cluster %v, got %v", testCase.large, actual)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"strings"
 
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
@@ -80,6 +81,116 @@ func tfGetProviderExtraConfig(c *kops.TargetSpec) map[string]string {
 	return nil
 }
 
+// tfGetTerraformSpec returns the terraform config of the cluster, which may be nil
+func tfGetTerraformSpec(c *kops.TargetSpec) *kops.TerraformSpec {
+	if c != nil {
+		return c.Terraform
+	}
+	return nil
+}
+
+// ImportExisting returns true if existing resources should be adopted with import blocks and data sources
+func (t *TerraformTarget) ImportExisting() bool {
+	tf := tfGetTerraformSpec(t.clusterSpecTarget)
+	return tf != nil && fi.BoolValue(tf.ImportExisting)
+}
+
+// RendersUnchangedTasks implements fi.RendersUnchangedTasks; when importing existing resources, tasks
+// that already exist must still be rendered, so that the configuration is complete.
+func (t *TerraformTarget) RendersUnchangedTasks() bool {
+	return t.ImportExisting()
+}
+
+// tfRequiredVersion returns the terraform version constraint; import blocks need terraform 1.5
+func tfRequiredVersion(imports []*terraformWriter.Import) string {
+	if len(imports) != 0 {
+		return ">= 1.5.0"
+	}
+	return ">= 0.12.26"
+}
+
+// tfGetProviderVersion returns the version constraint of the provider, unless it is overridden in the cluster spec
+func tfGetProviderVersion(c *kops.TargetSpec, provider string, defaultVersion string) string {
+	if tf := tfGetTerraformSpec(c); tf != nil && tf.ProviderVersions[provider] != "" {
+		return tf.ProviderVersions[provider]
+	}
+	return defaultVersion
+}
+
+// tfGetBackendConfig returns the type and configuration of the backend block, or an empty type if there is none
+func tfGetBackendConfig(c *kops.TargetSpec, clusterName string, region string) (string, map[string]interface{}) {
+	tf := tfGetTerraformSpec(c)
+	if tf == nil || tf.Backend == nil {
+		return "", nil
+	}
+
+	if s3 := tf.Backend.S3; s3 != nil {
+		config := map[string]interface{}{
+			"bucket": s3.Bucket,
+			"key":    s3.Key,
+			"region": s3.Region,
+		}
+		if s3.Key == "" {
+			config["key"] = clusterName + "/terraform.tfstate"
+		}
+		if s3.Region == "" {
+			config["region"] = region
+		}
+		if s3.DynamoDBTable != "" {
+			config["dynamodb_table"] = s3.DynamoDBTable
+		}
+		if s3.Encrypt != nil {
+			config["encrypt"] = *s3.Encrypt
+		}
+		return "s3", config
+	}
+
+	if gcs := tf.Backend.GCS; gcs != nil {
+		config := map[string]interface{}{
+			"bucket": gcs.Bucket,
+			"prefix": gcs.Prefix,
+		}
+		if gcs.Prefix == "" {
+			config["prefix"] = clusterName
+		}
+		return "gcs", config
+	}
+
+	return "", nil
+}
+
+// tfFileForResourceType returns the file that resources of the type are written to.
+// Unless the cluster spec splits the files, all resources are written to kubernetes.tf
+func tfFileForResourceType(c *kops.TargetSpec, resourceType string) string {
+	if tf := tfGetTerraformSpec(c); tf == nil || !fi.BoolValue(tf.SplitFiles) {
+		return ""
+	}
+
+	switch {
+	case strings.Contains(resourceType, "_iam_") || strings.HasPrefix(resourceType, "google_service_account"):
+		return "iam"
+	case hasAnyPrefix(resourceType, "aws_launch_", "aws_autoscaling_", "aws_ebs_", "aws_instance", "aws_key_pair",
+		"google_compute_instance", "google_compute_disk", "spotinst_"):
+		return "instances"
+	case hasAnyPrefix(resourceType, "aws_vpc", "aws_subnet", "aws_route", "aws_internet_gateway", "aws_egress_only_internet_gateway",
+		"aws_nat_gateway", "aws_eip", "aws_security_group", "aws_elb", "aws_lb", "aws_proxy_protocol_policy", "aws_load_balancer_",
+		"google_compute_network", "google_compute_subnetwork", "google_compute_firewall", "google_compute_router",
+		"google_compute_address", "google_compute_forwarding_rule", "google_compute_target_pool", "google_compute_http_health_check"):
+		return "network"
+	default:
+		return ""
+	}
+}
+
+func hasAnyPrefix(s string, prefixes ...string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 func (t *TerraformTarget) Finish(taskMap map[string]fi.Task) error {
 	var err error
 	if featureflag.TerraformJSON.Enabled() {
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"sort"
 
+	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/gocty"
@@ -51,50 +52,52 @@ func (t *TerraformTarget) finishHCL2(taskMap map[string]fi.Task) error {
 	}
 	rootBody.AppendNewline()
 
-	resourcesByType, err := t.GetResourcesByType()
+	dataSourcesByType, err := t.GetDataSourcesByType()
 	if err != nil {
 		return err
 	}
+	if err := writeBlocksByType(dataSourcesByType, "data", func(string) *hclwrite.Body { return rootBody }); err != nil {
+		return err
+	}
 
-	resourceTypes := make([]string, 0, len(resourcesByType))
-	for resourceType := range resourcesByType {
-		resourceTypes = append(resourceTypes, resourceType)
+	resourcesByType, err := t.GetResourcesByType()
+	if err != nil {
+		return err
 	}
-	sort.Strings(resourceTypes)
-	for _, resourceType := range resourceTypes {
-		resources := resourcesByType[resourceType]
-		resourceNames := make([]string, 0, len(resources))
-		for resourceName := range resources {
-			resourceNames = append(resourceNames, resourceName)
-		}
-		sort.Strings(resourceNames)
-		for _, resourceName := range resourceNames {
-			item := resources[resourceName]
 
-			resBlock := rootBody.AppendNewBlock("resource", []string{resourceType, resourceName})
-			resBody := resBlock.Body()
-			resType, err := gocty.ImpliedType(item)
-			if err != nil {
-				return err
-			}
-			resVal, err := gocty.ToCtyValue(item, resType)
-			if err != nil {
-				return err
-			}
-			if resVal.IsNull() {
-				continue
-			}
-			resVal.ForEachElement(func(key cty.Value, value cty.Value) bool {
-				writeValue(resBody, key.AsString(), value)
-				return false
-			})
-			rootBody.AppendNewline()
+	// Resources of the modules that are split into their own files
+	moduleFiles := make(map[string]*hclwrite.File)
+	bodyFor := func(resourceType string) *hclwrite.Body {
+		module := tfFileForResourceType(t.clusterSpecTarget, resourceType)
+		if module == "" {
+			return rootBody
 		}
+		if moduleFiles[module] == nil {
+			moduleFiles[module] = hclwrite.NewEmptyFile()
+		}
+		return moduleFiles[module].Body()
+	}
+
+	if err := writeBlocksByType(resourcesByType, "resource", bodyFor); err != nil {
+		return err
+	}
+
+	// Import blocks are written next to the resources they adopt
+	imports := t.GetImports()
+	for _, i := range imports {
+		body := bodyFor(i.ResourceType)
+		importBody := body.AppendNewBlock("import", nil).Body()
+		importBody.SetAttributeTraversal("to", hcl.Traversal{
+			hcl.TraverseRoot{Name: i.ResourceType},
+			hcl.TraverseAttr{Name: i.ResourceName},
+		})
+		importBody.SetAttributeValue("id", cty.StringVal(i.ID))
+		body.AppendNewline()
 	}
 
 	terraformBlock := rootBody.AppendNewBlock("terraform", []string{})
 	terraformBody := terraformBlock.Body()
-	terraformBody.SetAttributeValue("required_version", cty.StringVal(">= 0.12.26"))
+	terraformBody.SetAttributeValue("required_version", cty.StringVal(tfRequiredVersion(imports)))
 
 	requiredProvidersBlock := terraformBody.AppendNewBlock("required_providers", []string{})
 	requiredProvidersBody := requiredProvidersBlock.Body()
@@ -102,24 +105,91 @@ func (t *TerraformTarget) finishHCL2(taskMap map[string]fi.Task) error {
 	if t.Cloud.ProviderID() == kops.CloudProviderGCE {
 		writeMap(requiredProvidersBody, "google", map[string]cty.Value{
 			"source":  cty.StringVal("hashicorp/google"),
-			"version": cty.StringVal(">= 2.19.0"),
+			"version": cty.StringVal(tfGetProviderVersion(t.clusterSpecTarget, "google", ">= 2.19.0")),
 		})
 	} else if t.Cloud.ProviderID() == kops.CloudProviderAWS {
 		writeMap(requiredProvidersBody, "aws", map[string]cty.Value{
 			"source":  cty.StringVal("hashicorp/aws"),
-			"version": cty.StringVal(">= 3.34.0"),
+			"version": cty.StringVal(tfGetProviderVersion(t.clusterSpecTarget, "aws", ">= 3.34.0")),
 		})
 		if featureflag.Spotinst.Enabled() {
 			writeMap(requiredProvidersBody, "spotinst", map[string]cty.Value{
 				"source":  cty.StringVal("spotinst/spotinst"),
-				"version": cty.StringVal(">= 1.33.0"),
+				"version": cty.StringVal(tfGetProviderVersion(t.clusterSpecTarget, "spotinst", ">= 1.33.0")),
 			})
 		}
 	}
 
+	if backendType, backendConfig := tfGetBackendConfig(t.clusterSpecTarget, t.ClusterName, t.Cloud.Region()); backendType != "" {
+		backendBody := terraformBody.AppendNewBlock("backend", []string{backendType}).Body()
+		keys := make([]string, 0, len(backendConfig))
+		for k := range backendConfig {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			switch v := backendConfig[k].(type) {
+			case bool:
+				backendBody.SetAttributeValue(k, cty.BoolVal(v))
+			case string:
+				backendBody.SetAttributeValue(k, cty.StringVal(v))
+			}
+		}
+	}
+
 	bytes := hclwrite.Format(f.Bytes())
 	t.Files["kubernetes.tf"] = bytes
 
+	for module, moduleFile := range moduleFiles {
+		t.Files[module+".tf"] = hclwrite.Format(moduleFile.Bytes())
+	}
+
+	return nil
+}
+
+// writeBlocksByType writes a block for each item, sorted by type and name, to the body returned for its type
+// Example:
+// resource "aws_vpc" "name" {
+//   cidr_block = "172.20.0.0/16"
+// }
+func writeBlocksByType(itemsByType map[string]map[string]interface{}, blockType string, bodyFor func(itemType string) *hclwrite.Body) error {
+	itemTypes := make([]string, 0, len(itemsByType))
+	for itemType := range itemsByType {
+		itemTypes = append(itemTypes, itemType)
+	}
+	sort.Strings(itemTypes)
+	for _, itemType := range itemTypes {
+		body := bodyFor(itemType)
+
+		items := itemsByType[itemType]
+		itemNames := make([]string, 0, len(items))
+		for itemName := range items {
+			itemNames = append(itemNames, itemName)
+		}
+		sort.Strings(itemNames)
+		for _, itemName := range itemNames {
+			item := items[itemName]
+
+			block := body.AppendNewBlock(blockType, []string{itemType, itemName})
+			blockBody := block.Body()
+			ctyType, err := gocty.ImpliedType(item)
+			if err != nil {
+				return err
+			}
+			val, err := gocty.ToCtyValue(item, ctyType)
+			if err != nil {
+				return err
+			}
+			if val.IsNull() {
+				continue
+			}
+			val.ForEachElement(func(key cty.Value, value cty.Value) bool {
+				writeValue(blockBody, key.AsString(), value)
+				return false
+			})
+			body.AppendNewline()
+		}
+	}
 	return nil
 }
 
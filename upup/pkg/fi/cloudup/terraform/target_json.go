@@ -30,6 +30,39 @@ func (t *TerraformTarget) finishJSON(taskMap map[string]fi.Task) error {
 		return err
 	}
 
+	// Resources of the modules that are split into their own files
+	moduleResourcesByType := make(map[string]map[string]map[string]interface{})
+	for resourceType, resources := range resourcesByType {
+		if module := tfFileForResourceType(t.clusterSpecTarget, resourceType); module != "" {
+			if moduleResourcesByType[module] == nil {
+				moduleResourcesByType[module] = make(map[string]map[string]interface{})
+			}
+			moduleResourcesByType[module][resourceType] = resources
+			delete(resourcesByType, resourceType)
+		}
+	}
+
+	// Import blocks are written next to the resources they adopt
+	imports := t.GetImports()
+	var rootImports []map[string]interface{}
+	moduleImports := make(map[string][]map[string]interface{})
+	for _, i := range imports {
+		importBlock := map[string]interface{}{
+			"to": i.ResourceType + "." + i.ResourceName,
+			"id": i.ID,
+		}
+		if module := tfFileForResourceType(t.clusterSpecTarget, i.ResourceType); module != "" {
+			moduleImports[module] = append(moduleImports[module], importBlock)
+		} else {
+			rootImports = append(rootImports, importBlock)
+		}
+	}
+
+	dataSourcesByType, err := t.GetDataSourcesByType()
+	if err != nil {
+		return err
+	}
+
 	providersByName := make(map[string]map[string]interface{})
 	if t.Cloud.ProviderID() == kops.CloudProviderGCE {
 		providerGoogle := make(map[string]interface{})
@@ -67,6 +100,12 @@ func (t *TerraformTarget) finishJSON(taskMap map[string]fi.Task) error {
 
 	data := make(map[string]interface{})
 	data["resource"] = resourcesByType
+	if len(dataSourcesByType) != 0 {
+		data["data"] = dataSourcesByType
+	}
+	if len(rootImports) != 0 {
+		data["import"] = rootImports
+	}
 	if len(providersByName) != 0 {
 		data["provider"] = providersByName
 	}
@@ -78,13 +117,13 @@ func (t *TerraformTarget) finishJSON(taskMap map[string]fi.Task) error {
 	}
 
 	terraformConfiguration := make(map[string]interface{})
-	terraformConfiguration["required_version"] = ">= 0.12.26"
+	terraformConfiguration["required_version"] = tfRequiredVersion(imports)
 
 	requiredProvidersByName := make(map[string]interface{})
 	if t.Cloud.ProviderID() == kops.CloudProviderGCE {
 		requiredProviderGoogle := make(map[string]interface{})
 		requiredProviderGoogle["source"] = "hashicorp/google"
-		requiredProviderGoogle["version"] = ">= 2.19.0"
+		requiredProviderGoogle["version"] = tfGetProviderVersion(t.clusterSpecTarget, "google", ">= 2.19.0")
 		for k, v := range tfGetProviderExtraConfig(t.clusterSpecTarget) {
 			requiredProviderGoogle[k] = v
 		}
@@ -92,7 +131,7 @@ func (t *TerraformTarget) finishJSON(taskMap map[string]fi.Task) error {
 	} else if t.Cloud.ProviderID() == kops.CloudProviderAWS {
 		requiredProviderAWS := make(map[string]interface{})
 		requiredProviderAWS["source"] = "hashicorp/aws"
-		requiredProviderAWS["version"] = ">= 2.46.0"
+		requiredProviderAWS["version"] = tfGetProviderVersion(t.clusterSpecTarget, "aws", ">= 2.46.0")
 		for k, v := range tfGetProviderExtraConfig(t.clusterSpecTarget) {
 			requiredProviderAWS[k] = v
 		}
@@ -103,6 +142,12 @@ func (t *TerraformTarget) finishJSON(taskMap map[string]fi.Task) error {
 		terraformConfiguration["required_providers"] = requiredProvidersByName
 	}
 
+	if backendType, backendConfig := tfGetBackendConfig(t.clusterSpecTarget, t.ClusterName, t.Cloud.Region()); backendType != "" {
+		terraformConfiguration["backend"] = map[string]interface{}{
+			backendType: backendConfig,
+		}
+	}
+
 	data["terraform"] = terraformConfiguration
 
 	jsonBytes, err := json.MarshalIndent(data, "", "  ")
@@ -111,5 +156,17 @@ func (t *TerraformTarget) finishJSON(taskMap map[string]fi.Task) error {
 	}
 
 	t.Files["kubernetes.tf.json"] = jsonBytes
+
+	for module, moduleResources := range moduleResourcesByType {
+		moduleData := map[string]interface{}{"resource": moduleResources}
+		if len(moduleImports[module]) != 0 {
+			moduleData["import"] = moduleImports[module]
+		}
+		jsonBytes, err := json.MarshalIndent(moduleData, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshaling terraform data to json: %v", err)
+		}
+		t.Files[module+".tf.json"] = jsonBytes
+	}
 	return nil
 }
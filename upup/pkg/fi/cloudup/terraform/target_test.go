@@ -0,0 +1,176 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package terraform
+
+import (
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/diff"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+type fakeCloud struct {
+	fi.Cloud
+}
+
+func (c *fakeCloud) ProviderID() kops.CloudProviderID {
+	return kops.CloudProviderAWS
+}
+
+func (c *fakeCloud) Region() string {
+	return "us-test-1"
+}
+
+type terraformTestResource struct {
+	Name *string `cty:"name"`
+}
+
+func TestFinishHCL2TerraformSpec(t *testing.T) {
+	target := NewTerraformTarget(&fakeCloud{}, "", t.TempDir(), &kops.TargetSpec{
+		Terraform: &kops.TerraformSpec{
+			ProviderVersions: map[string]string{"aws": "3.50.0"},
+			Backend: &kops.TerraformBackendSpec{
+				S3: &kops.TerraformS3BackendSpec{
+					Bucket:        "tf-state",
+					DynamoDBTable: "tf-lock",
+					Encrypt:       fi.Bool(true),
+				},
+			},
+			SplitFiles: fi.Bool(true),
+		},
+	})
+	target.ClusterName = "minimal.example.com"
+
+	for _, resourceType := range []string{"aws_vpc", "aws_iam_role", "aws_launch_template", "aws_s3_bucket_object"} {
+		if err := target.RenderResource(resourceType, "minimal-example-com", &terraformTestResource{Name: fi.String("minimal.example.com")}); err != nil {
+			t.Fatalf("error rendering %s: %v", resourceType, err)
+		}
+	}
+
+	if err := target.finishHCL2(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"kubernetes.tf": `provider "aws" {
+  region = "us-test-1"
+}
+
+resource "aws_s3_bucket_object" "minimal-example-com" {
+  name = "minimal.example.com"
+}
+
+terraform {
+  required_version = ">= 0.12.26"
+  required_providers {
+    aws = {
+      "source"  = "hashicorp/aws"
+      "version" = "3.50.0"
+    }
+  }
+  backend "s3" {
+    bucket         = "tf-state"
+    dynamodb_table = "tf-lock"
+    encrypt        = true
+    key            = "minimal.example.com/terraform.tfstate"
+    region         = "us-test-1"
+  }
+}
+`,
+		"iam.tf": `resource "aws_iam_role" "minimal-example-com" {
+  name = "minimal.example.com"
+}
+
+`,
+		"instances.tf": `resource "aws_launch_template" "minimal-example-com" {
+  name = "minimal.example.com"
+}
+
+`,
+		"network.tf": `resource "aws_vpc" "minimal-example-com" {
+  name = "minimal.example.com"
+}
+
+`,
+	}
+	if len(target.Files) != len(expected) {
+		t.Errorf("expected %d files, got %d", len(expected), len(target.Files))
+	}
+	for name, contents := range expected {
+		actual := string(target.Files[name])
+		if actual != contents {
+			t.Errorf("unexpected %s:\n%s", name, diff.FormatDiff(contents, actual))
+		}
+	}
+}
+
+func TestFinishHCL2ImportExisting(t *testing.T) {
+	target := NewTerraformTarget(&fakeCloud{}, "", t.TempDir(), &kops.TargetSpec{
+		Terraform: &kops.TerraformSpec{
+			ImportExisting: fi.Bool(true),
+		},
+	})
+	target.ClusterName = "minimal.example.com"
+
+	if !target.RendersUnchangedTasks() {
+		t.Errorf("expected unchanged tasks to be rendered when importing existing resources")
+	}
+
+	if err := target.RenderResource("aws_iam_role", "masters.minimal.example.com", &terraformTestResource{Name: fi.String("masters.minimal.example.com")}); err != nil {
+		t.Fatalf("error rendering resource: %v", err)
+	}
+	target.AddImport("aws_iam_role", "masters.minimal.example.com", "masters.minimal.example.com")
+	if err := target.RenderDataSource("aws_subnet", "us-test-1a.minimal.example.com", &terraformTestResource{Name: fi.String("us-test-1a")}); err != nil {
+		t.Fatalf("error rendering data source: %v", err)
+	}
+
+	if err := target.finishHCL2(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `provider "aws" {
+  region = "us-test-1"
+}
+
+data "aws_subnet" "us-test-1a-minimal-example-com" {
+  name = "us-test-1a"
+}
+
+resource "aws_iam_role" "masters-minimal-example-com" {
+  name = "masters.minimal.example.com"
+}
+
+import {
+  to = aws_iam_role.masters-minimal-example-com
+  id = "masters.minimal.example.com"
+}
+
+terraform {
+  required_version = ">= 1.5.0"
+  required_providers {
+    aws = {
+      "source"  = "hashicorp/aws"
+      "version" = ">= 3.34.0"
+    }
+  }
+}
+`
+	if actual := string(target.Files["kubernetes.tf"]); actual != expected {
+		t.Errorf("unexpected kubernetes.tf:\n%s", diff.FormatDiff(expected, actual))
+	}
+}
@@ -19,6 +19,7 @@ package terraformWriter
 import (
 	"fmt"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -29,6 +30,10 @@ type TerraformWriter struct {
 	mutex sync.Mutex
 	// resources is a list of TF items that should be created
 	resources []*terraformResource
+	// dataSources is a list of TF data sources for existing resources that are not managed
+	dataSources []*terraformResource
+	// imports is a list of existing resources that should be imported
+	imports []*Import
 	// outputs is a list of our TF output variables
 	outputs map[string]*terraformOutputVariable
 	// Files is a map of TF resource Files that should be created
@@ -40,6 +45,14 @@ type OutputValue struct {
 	ValueArray []*Literal
 }
 
+// Import is an existing resource that terraform should adopt instead of creating
+type Import struct {
+	ResourceType string
+	ResourceName string
+	// ID is the identifier of the existing resource, in the format the resource type imports
+	ID string
+}
+
 type terraformResource struct {
 	ResourceType string
 	ResourceName string
@@ -98,6 +111,34 @@ func (t *TerraformWriter) RenderResource(resourceType string, resourceName strin
 	return nil
 }
 
+// RenderDataSource adds a data source, which references an existing resource without managing it
+func (t *TerraformWriter) RenderDataSource(dataType string, dataName string, e interface{}) error {
+	res := &terraformResource{
+		ResourceType: dataType,
+		ResourceName: dataName,
+		Item:         e,
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.dataSources = append(t.dataSources, res)
+
+	return nil
+}
+
+// AddImport records that the resource already exists, so terraform imports it instead of creating it
+func (t *TerraformWriter) AddImport(resourceType string, resourceName string, id string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.imports = append(t.imports, &Import{
+		ResourceType: resourceType,
+		ResourceName: tfSanitize(resourceName),
+		ID:           id,
+	})
+}
+
 func (t *TerraformWriter) AddOutputVariable(key string, literal *Literal) error {
 	v := &terraformOutputVariable{
 		Key:   key,
@@ -135,9 +176,30 @@ func (t *TerraformWriter) AddOutputVariableArray(key string, literal *Literal) e
 }
 
 func (t *TerraformWriter) GetResourcesByType() (map[string]map[string]interface{}, error) {
+	return groupByType(t.resources)
+}
+
+// GetDataSourcesByType returns the data sources, by data source type and name
+func (t *TerraformWriter) GetDataSourcesByType() (map[string]map[string]interface{}, error) {
+	return groupByType(t.dataSources)
+}
+
+// GetImports returns the resources to import, sorted by resource type and name
+func (t *TerraformWriter) GetImports() []*Import {
+	imports := append([]*Import(nil), t.imports...)
+	sort.Slice(imports, func(i, j int) bool {
+		if imports[i].ResourceType != imports[j].ResourceType {
+			return imports[i].ResourceType < imports[j].ResourceType
+		}
+		return imports[i].ResourceName < imports[j].ResourceName
+	})
+	return imports
+}
+
+func groupByType(items []*terraformResource) (map[string]map[string]interface{}, error) {
 	resourcesByType := make(map[string]map[string]interface{})
 
-	for _, res := range t.resources {
+	for _, res := range items {
 		resources := resourcesByType[res.ResourceType]
 		if resources == nil {
 			resources = make(map[string]interface{})
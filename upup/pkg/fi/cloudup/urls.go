@@ -39,6 +39,9 @@ var kopsBaseURL *url.URL
 // nodeUpAsset caches the nodeup binary download url/hash
 var nodeUpAsset map[architectures.Architecture]*mirrors.MirroredAsset
 
+// nodeUpWindowsAsset caches the Windows nodeup binary download url/hash
+var nodeUpWindowsAsset *mirrors.MirroredAsset
+
 // protokubeAsset caches the protokube binary download url/hash
 var protokubeAsset map[architectures.Architecture]*mirrors.MirroredAsset
 
@@ -104,6 +107,24 @@ func NodeUpAsset(assetsBuilder *assets.AssetBuilder, arch architectures.Architec
 	return nodeUpAsset[arch], nil
 }
 
+// NodeUpWindowsAsset returns the asset for where the Windows nodeup binary should be downloaded
+func NodeUpWindowsAsset(assetsBuilder *assets.AssetBuilder) (*mirrors.MirroredAsset, error) {
+	if nodeUpWindowsAsset != nil {
+		// Avoid repeated logging
+		klog.V(8).Infof("Using cached Windows nodeup location: %v", nodeUpWindowsAsset.Locations)
+		return nodeUpWindowsAsset, nil
+	}
+
+	u, hash, err := KopsFileURL("windows/amd64/nodeup.exe", assetsBuilder)
+	if err != nil {
+		return nil, err
+	}
+	nodeUpWindowsAsset = mirrors.BuildMirroredAsset(u, hash)
+	klog.V(8).Infof("Using default Windows nodeup location: %q", u.String())
+
+	return nodeUpWindowsAsset, nil
+}
+
 // ProtokubeAsset returns the url and hash of the protokube binary
 func ProtokubeAsset(assetsBuilder *assets.AssetBuilder, arch architectures.Architecture) (*mirrors.MirroredAsset, error) {
 	if protokubeAsset == nil {
@@ -151,6 +172,11 @@ func KopsFileURL(file string, assetBuilder *assets.AssetBuilder) (*url.URL, *has
 		return nil, nil, err
 	}
 
+	// The BoringCrypto builds of FIPS clusters are published next to the standard builds
+	if assetBuilder.FIPS {
+		file = path.Join("fips", file)
+	}
+
 	base.Path = path.Join(base.Path, file)
 
 	fileURL, hash, err := assetBuilder.RemapFileAndSHA(base)
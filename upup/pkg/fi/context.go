@@ -18,6 +18,7 @@ package fi
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -46,6 +47,9 @@ type Context struct {
 	tasks map[string]Task
 
 	warnings []*Warning
+
+	// report summarizes the last call to RunTasks
+	report *RunTasksReport
 }
 
 // Warning holds the details of a warning encountered during validation/creation
@@ -79,12 +83,17 @@ func (c *Context) AllTasks() map[string]Task {
 	return c.tasks
 }
 
-func (c *Context) RunTasks(options RunTasksOptions) error {
+func (c *Context) RunTasks(ctx context.Context, options RunTasksOptions) error {
 	e := &executor{
 		context: c,
 		options: options,
 	}
-	return e.RunTasks(c.tasks)
+	return e.RunTasks(ctx, c.tasks)
+}
+
+// Report returns the durations and retries of the tasks from the last call to RunTasks, or nil if RunTasks was not called.
+func (c *Context) Report() *RunTasksReport {
+	return c.report
 }
 
 func (c *Context) Close() {
@@ -84,6 +84,11 @@ func DefaultDeltaRunMethod(e Task, c *Context) error {
 				return err
 			}
 		}
+	} else if ru, ok := c.Target.(RendersUnchangedTasks); ok && ru.RendersUnchangedTasks() {
+		err = c.Render(a, e, changes)
+		if err != nil {
+			return err
+		}
 	}
 
 	if producesDeletions, ok := e.(ProducesDeletions); ok && c.Target.ProcessDeletions() {
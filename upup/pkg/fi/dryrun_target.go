@@ -151,56 +151,8 @@ func (t *DryRunTarget) PrintReport(taskMap map[string]Task, out io.Writer) error
 				taskName := getTaskName(r.changes)
 				fmt.Fprintf(b, "  %s/%s\n", taskName, idForTask(taskMap, r.e))
 
-				changes := reflect.ValueOf(r.changes)
-				if changes.Kind() == reflect.Ptr && !changes.IsNil() {
-					changes = changes.Elem()
-				}
-
-				if changes.Kind() == reflect.Struct {
-					for i := 0; i < changes.NumField(); i++ {
-
-						field := changes.Field(i)
-
-						fieldName := changes.Type().Field(i).Name
-						if changes.Type().Field(i).PkgPath != "" {
-							// Not exported
-							continue
-						}
-
-						fieldValue := reflectutils.ValueAsString(field)
-
-						shouldPrint := true
-						if fieldName == "Name" {
-							// The field name is already printed above, no need to repeat it.
-							shouldPrint = false
-						}
-						if fieldName == "Lifecycle" {
-							// Lifecycle is a "system" field; no need to show it
-							shouldPrint = false
-						}
-						if fieldValue == "<nil>" || fieldValue == "<resource>" {
-							// Uninformative
-							shouldPrint = false
-						}
-						if fieldValue == "id:<nil>" {
-							// Uninformative, but we can often print the name instead
-							name := ""
-							if field.CanInterface() {
-								hasName, ok := field.Interface().(HasName)
-								if ok {
-									name = StringValue(hasName.GetName())
-								}
-							}
-							if name != "" {
-								fieldValue = "name:" + name
-							} else {
-								shouldPrint = false
-							}
-						}
-						if shouldPrint {
-							fmt.Fprintf(b, "  \t%-20s\t%s\n", fieldName, fieldValue)
-						}
-					}
+				for _, change := range buildCreateList(r.changes) {
+					fmt.Fprintf(b, "  \t%-20s\t%s\n", change.FieldName, change.Expected)
 				}
 
 				fmt.Fprintf(b, "\n")
@@ -275,6 +227,70 @@ func (t *DryRunTarget) PrintReport(taskMap map[string]Task, out io.Writer) error
 type change struct {
 	FieldName   string
 	Description string
+	// Actual and Expected are the values before and after the change, when they are not resources
+	Actual   string
+	Expected string
+}
+
+// buildCreateList returns the informative fields of a task that would be created
+func buildCreateList(task Task) []change {
+	var changeList []change
+
+	changes := reflect.ValueOf(task)
+	if changes.Kind() == reflect.Ptr && !changes.IsNil() {
+		changes = changes.Elem()
+	}
+
+	if changes.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for i := 0; i < changes.NumField(); i++ {
+
+		field := changes.Field(i)
+
+		fieldName := changes.Type().Field(i).Name
+		if changes.Type().Field(i).PkgPath != "" {
+			// Not exported
+			continue
+		}
+
+		fieldValue := reflectutils.ValueAsString(field)
+
+		shouldPrint := true
+		if fieldName == "Name" {
+			// The field name is already printed above, no need to repeat it.
+			shouldPrint = false
+		}
+		if fieldName == "Lifecycle" {
+			// Lifecycle is a "system" field; no need to show it
+			shouldPrint = false
+		}
+		if fieldValue == "<nil>" || fieldValue == "<resource>" {
+			// Uninformative
+			shouldPrint = false
+		}
+		if fieldValue == "id:<nil>" {
+			// Uninformative, but we can often print the name instead
+			name := ""
+			if field.CanInterface() {
+				hasName, ok := field.Interface().(HasName)
+				if ok {
+					name = StringValue(hasName.GetName())
+				}
+			}
+			if name != "" {
+				fieldValue = "name:" + name
+			} else {
+				shouldPrint = false
+			}
+		}
+		if shouldPrint {
+			changeList = append(changeList, change{FieldName: fieldName, Expected: fieldValue})
+		}
+	}
+
+	return changeList
 }
 
 func buildChangeList(a, e, changes Task) ([]change, error) {
@@ -319,6 +335,8 @@ func buildChangeList(a, e, changes Task) ([]change, error) {
 			}
 
 			description := ""
+			actual := ""
+			expected := ""
 			ignored := false
 			if fieldValE.CanInterface() {
 
@@ -334,13 +352,15 @@ func buildChangeList(a, e, changes Task) ([]change, error) {
 				}
 
 				if !ignored && description == "" {
-					description = fmt.Sprintf(" %v -> %v", reflectutils.ValueAsString(fieldValA), reflectutils.ValueAsString(fieldValE))
+					actual = reflectutils.ValueAsString(fieldValA)
+					expected = reflectutils.ValueAsString(fieldValE)
+					description = fmt.Sprintf(" %v -> %v", actual, expected)
 				}
 			}
 			if ignored {
 				continue
 			}
-			changeList = append(changeList, change{FieldName: valC.Type().Field(i).Name, Description: description})
+			changeList = append(changeList, change{FieldName: valC.Type().Field(i).Name, Description: description, Actual: actual, Expected: expected})
 		}
 	} else {
 		return nil, fmt.Errorf("unhandled change type: %v", valC.Type())
@@ -414,6 +434,104 @@ func (t *DryRunTarget) Changes() (map[string]Task, map[string]Task) {
 	return creates, updates
 }
 
+// ChangeAction is the kind of change that would be made to a resource
+type ChangeAction string
+
+const (
+	ChangeActionCreate ChangeAction = "create"
+	ChangeActionUpdate ChangeAction = "update"
+	ChangeActionDelete ChangeAction = "delete"
+)
+
+// ResourceChange describes a resource that would be created, updated or deleted
+type ResourceChange struct {
+	Action ChangeAction
+	// Type is the type of the task, e.g. VPC, or the task name of a deletion
+	Type string
+	// Name is the name of the task, or the item of a deletion
+	Name string
+	// Fields are the fields that would be set or changed; empty for deletions
+	Fields []FieldChange
+	// Actual is the task as it was found; nil for creations and deletions
+	Actual Task
+	// Expected is the task as it would be after the change; nil for deletions
+	Expected Task
+}
+
+// FieldChange describes the change to a single field of a resource
+type FieldChange struct {
+	Field string
+	// Actual is the current value of the field; empty for creations
+	Actual string
+	// Expected is the desired value of the field
+	Expected string
+	// Diff is set instead of Actual and Expected for resources, such as file contents
+	Diff string
+}
+
+// ResourceChanges returns the changes that would be made, ordered as creations, updates and then deletions
+func (t *DryRunTarget) ResourceChanges(taskMap map[string]Task) ([]*ResourceChange, error) {
+	var creates []*render
+	var updates []*render
+	for _, r := range t.changes {
+		if r.aIsNil {
+			creates = append(creates, r)
+		} else {
+			updates = append(updates, r)
+		}
+	}
+	sort.Sort(ByTaskKey(creates))
+	sort.Sort(ByTaskKey(updates))
+
+	var resourceChanges []*ResourceChange
+	for _, r := range creates {
+		rc := &ResourceChange{
+			Action:   ChangeActionCreate,
+			Type:     getTaskName(r.changes),
+			Name:     idForTask(taskMap, r.e),
+			Expected: r.e,
+		}
+		for _, c := range buildCreateList(r.changes) {
+			rc.Fields = append(rc.Fields, FieldChange{Field: c.FieldName, Expected: c.Expected})
+		}
+		resourceChanges = append(resourceChanges, rc)
+	}
+
+	for _, r := range updates {
+		changeList, err := buildChangeList(r.a, r.e, r.changes)
+		if err != nil {
+			return nil, err
+		}
+		rc := &ResourceChange{
+			Action:   ChangeActionUpdate,
+			Type:     getTaskName(r.changes),
+			Name:     idForTask(taskMap, r.e),
+			Actual:   r.a,
+			Expected: r.e,
+		}
+		for _, c := range changeList {
+			fc := FieldChange{Field: c.FieldName, Actual: c.Actual, Expected: c.Expected}
+			if c.Actual == "" && c.Expected == "" {
+				fc.Diff = c.Description
+			}
+			rc.Fields = append(rc.Fields, fc)
+		}
+		resourceChanges = append(resourceChanges, rc)
+	}
+
+	deletions := append([]Deletion(nil), t.deletions...)
+	sort.Sort(DeletionByTaskName(deletions))
+	for _, d := range deletions {
+		resourceChanges = append(resourceChanges, &ResourceChange{
+			Action: ChangeActionDelete,
+			Type:   d.TaskName(),
+			Name:   d.Item(),
+		})
+	}
+
+	return resourceChanges, nil
+}
+
 // HasChanges returns true iff any changes would have been made
 func (t *DryRunTarget) HasChanges() bool {
 	return len(t.changes)+len(t.deletions) != 0
@@ -95,3 +95,68 @@ func Test_DryrunTarget_PrintReport(t *testing.T) {
 	err = target.PrintReport(tasks, &out)
 	assert.NoError(t, err, "target.PrintReport()")
 }
+
+func Test_DryrunTarget_ResourceChanges(t *testing.T) {
+	builder := assets.NewAssetBuilder(&api.Cluster{
+		Spec: api.ClusterSpec{
+			KubernetesVersion: "1.17.3",
+		},
+	}, false)
+	target := NewDryRunTarget(builder, &bytes.Buffer{})
+	tasks := map[string]Task{}
+
+	var existingA, existingE, createdE *testTask
+	{
+		a := &testTask{
+			Name:      String("existing"),
+			Lifecycle: LifecycleSync,
+			Tags:      map[string]string{"key": "old"},
+		}
+		e := &testTask{
+			Name:      String("existing"),
+			Lifecycle: LifecycleSync,
+			Tags:      map[string]string{"key": "new"},
+		}
+		changes := reflect.New(reflect.TypeOf(e).Elem()).Interface().(Task)
+		_ = BuildChanges(a, e, changes)
+		assert.NoError(t, target.Render(a, e, changes), "target.Render()")
+		tasks["testTask/existing"] = e
+		existingA, existingE = a, e
+	}
+
+	{
+		var a *testTask
+		e := &testTask{
+			Name:      String("created"),
+			Lifecycle: LifecycleSync,
+			Tags:      map[string]string{"key": "value"},
+		}
+		changes := reflect.New(reflect.TypeOf(e).Elem()).Interface().(Task)
+		_ = BuildChanges(a, e, changes)
+		assert.NoError(t, target.Render(a, e, changes), "target.Render()")
+		tasks["testTask/created"] = e
+		createdE = e
+	}
+
+	resourceChanges, err := target.ResourceChanges(tasks)
+	assert.NoError(t, err, "target.ResourceChanges()")
+
+	expected := []*ResourceChange{
+		{
+			Action:   ChangeActionCreate,
+			Type:     "testTask",
+			Name:     "created",
+			Fields:   []FieldChange{{Field: "Tags", Expected: "{key: value}"}},
+			Expected: createdE,
+		},
+		{
+			Action:   ChangeActionUpdate,
+			Type:     "testTask",
+			Name:     "existing",
+			Fields:   []FieldChange{{Field: "Tags", Actual: "{key: old}", Expected: "{key: new}"}},
+			Actual:   existingA,
+			Expected: existingE,
+		},
+	}
+	assert.Equal(t, expected, resourceChanges)
+}
@@ -17,18 +17,28 @@ limitations under the License.
 package fi
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"go.opencensus.io/trace"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/tracing"
 )
 
 type executor struct {
 	context *Context
 
 	options RunTasksOptions
+
+	// apiSemaphores limits the tasks running against each API, per MaxConcurrencyPerAPI
+	apiSemaphores map[string]chan struct{}
 }
 
 type taskState struct {
@@ -38,21 +48,74 @@ type taskState struct {
 	deadline     time.Time
 	lastError    error
 	dependencies []*taskState
+
+	// retryAfter is set when the task was throttled; the task is not run again before then
+	retryAfter time.Time
+	attempts   int
+	throttled  int
+	duration   time.Duration
+	// cached is set when the task was skipped because it is unchanged since it was last applied
+	cached bool
 }
 
 type RunTasksOptions struct {
 	MaxTaskDuration         time.Duration
 	WaitAfterAllTasksFailed time.Duration
+	// MaxConcurrency limits the number of tasks run at the same time; zero means no limit.
+	MaxConcurrency int
+	// MaxConcurrencyPerAPI limits the number of tasks calling the same cloud provider API at the same time,
+	// keyed by the API name returned by TaskAPI. APIs that are not listed are only limited by MaxConcurrency.
+	MaxConcurrencyPerAPI map[string]int
+	// TaskAPI returns the cloud provider API that a task calls, such as "iam" or "ec2", or "" if it calls none.
+	TaskAPI func(task Task) string
+
+	// IsThrottlingError reports whether a task failed because the cloud provider API was rate limiting requests.
+	// Throttled tasks are retried after an exponential backoff with jitter, instead of at the next pass.
+	IsThrottlingError func(err error) bool
+	// ThrottlingBackoff is the delay before the first retry of a throttled task; it doubles with every retry.
+	ThrottlingBackoff time.Duration
+	// MaxThrottlingBackoff caps the delay between retries of a throttled task.
+	MaxThrottlingBackoff time.Duration
+
+	// Cache, if set, skips the tasks that are unchanged since they were last applied successfully.
+	// Only tasks with the Sync lifecycle that call a cloud provider API, according to TaskAPI, are cached.
+	Cache *TaskCache
 }
 
 func (o *RunTasksOptions) InitDefaults() {
 	o.MaxTaskDuration = 10 * time.Minute
 	o.WaitAfterAllTasksFailed = 10 * time.Second
+	o.ThrottlingBackoff = 2 * time.Second
+	o.MaxThrottlingBackoff = time.Minute
+}
+
+// TaskResult records how a task was run.
+type TaskResult struct {
+	Key string
+	// Duration is the time spent running the task, summed over all attempts.
+	Duration time.Duration
+	// Attempts is the number of times the task was run.
+	Attempts int
+	// Throttled is the number of attempts that failed with a throttling error.
+	Throttled int
+}
+
+// RunTasksReport summarizes the execution of the tasks, for diagnosing slow updates.
+type RunTasksReport struct {
+	// Duration is the wall-clock time taken to run all the tasks.
+	Duration time.Duration
+	// Tasks holds a result for every task that was run, slowest first.
+	Tasks []*TaskResult
+	// Cached is the number of tasks that were skipped because they were unchanged since the last run.
+	Cached int
 }
 
 // RunTasks executes all the tasks, considering their dependencies
 // It will perform some re-execution on error, retrying as long as progress is still being made
-func (e *executor) RunTasks(taskMap map[string]Task) error {
+func (e *executor) RunTasks(ctx context.Context, taskMap map[string]Task) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "RunTasks", trace.Int64Attribute("kops.tasks", int64(len(taskMap))))
+	defer func() { tracing.EndSpan(span, err) }()
+
 	dependencies := FindTaskDependencies(taskMap)
 
 	for _, task := range taskMap {
@@ -83,9 +146,23 @@ func (e *executor) RunTasks(taskMap map[string]Task) error {
 		}
 	}
 
+	e.apiSemaphores = make(map[string]chan struct{})
+	for api, limit := range e.options.MaxConcurrencyPerAPI {
+		if limit > 0 {
+			e.apiSemaphores[api] = make(chan struct{}, limit)
+		}
+	}
+
+	start := time.Now()
+	defer func() {
+		e.context.report = buildReport(taskStates, time.Since(start))
+	}()
+
 	for {
 		var canRun []*taskState
+		var backingOff []*taskState
 		doneCount := 0
+		now := time.Now()
 		for _, ts := range taskStates {
 			if ts.done {
 				doneCount++
@@ -100,25 +177,36 @@ func (e *executor) RunTasks(taskMap map[string]Task) error {
 			}
 			if ready {
 				if ts.deadline.IsZero() {
-					ts.deadline = time.Now().Add(e.options.MaxTaskDuration)
-				} else if time.Now().After(ts.deadline) {
+					ts.deadline = now.Add(e.options.MaxTaskDuration)
+				} else if now.After(ts.deadline) {
 					return fmt.Errorf("deadline exceeded executing task %v. Example error: %v", ts.key, ts.lastError)
 				}
+				if now.Before(ts.retryAfter) {
+					backingOff = append(backingOff, ts)
+					continue
+				}
 				canRun = append(canRun, ts)
 			}
 		}
 
 		klog.Infof("Tasks: %d done / %d total; %d can run", doneCount, len(taskStates), len(canRun))
 		if len(canRun) == 0 {
-			break
+			if len(backingOff) == 0 {
+				break
+			}
+			delay := time.Until(earliestRetry(backingOff))
+			klog.Infof("Waiting %v to retry %d throttled task(s)", delay.Round(time.Second), len(backingOff))
+			time.Sleep(delay)
+			continue
 		}
 
 		progress := false
+		throttled := 0
 
 		var tasks []*taskState
 		tasks = append(tasks, canRun...)
 
-		taskErrors := e.forkJoin(tasks)
+		taskErrors := e.forkJoin(ctx, tasks)
 		var errors []error
 		for i, err := range taskErrors {
 			ts := tasks[i]
@@ -133,7 +221,13 @@ func (e *executor) RunTasks(taskMap map[string]Task) error {
 				}
 
 				remaining := time.Second * time.Duration(int(time.Until(ts.deadline).Seconds()))
-				if _, ok := err.(*TryAgainLaterError); ok {
+				if e.options.IsThrottlingError != nil && e.options.IsThrottlingError(err) {
+					ts.throttled++
+					backoff := e.throttlingBackoff(ts.throttled)
+					ts.retryAfter = time.Now().Add(backoff)
+					throttled++
+					klog.Warningf("task %q was throttled, retrying in %v (%v remaining to succeed): %v", ts.key, backoff.Round(time.Millisecond), remaining, err)
+				} else if _, ok := err.(*TryAgainLaterError); ok {
 					klog.V(2).Infof("Task %q not ready: %v", ts.key, err)
 				} else {
 					klog.Warningf("error running task %q (%v remaining to succeed): %v", ts.key, remaining, err)
@@ -152,8 +246,11 @@ func (e *executor) RunTasks(taskMap map[string]Task) error {
 				// Logic error!
 				panic("did not make progress executing tasks; but no errors reported")
 			}
-			klog.Infof("No progress made, sleeping before retrying %d task(s)", len(errors))
-			time.Sleep(e.options.WaitAfterAllTasksFailed)
+			// Throttled tasks wait for their own backoff
+			if throttled < len(errors) {
+				klog.Infof("No progress made, sleeping before retrying %d task(s)", len(errors))
+				time.Sleep(e.options.WaitAfterAllTasksFailed)
+			}
 		}
 	}
 
@@ -171,11 +268,83 @@ func (e *executor) RunTasks(taskMap map[string]Task) error {
 	return nil
 }
 
-func (e *executor) forkJoin(tasks []*taskState) []error {
+// throttlingBackoff returns the delay before retrying a task that has been throttled the given number of times.
+func (e *executor) throttlingBackoff(throttled int) time.Duration {
+	backoff := e.options.ThrottlingBackoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+	limit := e.options.MaxThrottlingBackoff
+	if limit <= 0 {
+		limit = time.Minute
+	}
+	backoff = time.Duration(math.Min(float64(backoff)*math.Pow(2, float64(throttled-1)), float64(limit)))
+	// Spread out the retries, so that throttled tasks don't all hit the API again at the same moment
+	return wait.Jitter(backoff/2, 1.0)
+}
+
+// isCacheable returns true if the task can be skipped when it is unchanged since it was last applied.
+func (e *executor) isCacheable(task Task) bool {
+	if e.options.Cache == nil || e.options.TaskAPI == nil || e.options.TaskAPI(task) == "" {
+		return false
+	}
+	if reflect.TypeOf(task).Kind() != reflect.Ptr || reflect.TypeOf(task).Elem().Kind() != reflect.Struct {
+		return false
+	}
+	if hl, ok := task.(HasLifecycle); ok && hl.GetLifecycle() != LifecycleSync {
+		return false
+	}
+	return true
+}
+
+func earliestRetry(tasks []*taskState) time.Time {
+	earliest := tasks[0].retryAfter
+	for _, ts := range tasks[1:] {
+		if ts.retryAfter.Before(earliest) {
+			earliest = ts.retryAfter
+		}
+	}
+	return earliest
+}
+
+func buildReport(taskStates map[string]*taskState, duration time.Duration) *RunTasksReport {
+	report := &RunTasksReport{
+		Duration: duration,
+	}
+	for _, ts := range taskStates {
+		if ts.cached {
+			report.Cached++
+			continue
+		}
+		if ts.attempts == 0 {
+			continue
+		}
+		report.Tasks = append(report.Tasks, &TaskResult{
+			Key:       ts.key,
+			Duration:  ts.duration,
+			Attempts:  ts.attempts,
+			Throttled: ts.throttled,
+		})
+	}
+	sort.Slice(report.Tasks, func(i, j int) bool {
+		if report.Tasks[i].Duration != report.Tasks[j].Duration {
+			return report.Tasks[i].Duration > report.Tasks[j].Duration
+		}
+		return report.Tasks[i].Key < report.Tasks[j].Key
+	})
+	return report
+}
+
+func (e *executor) forkJoin(ctx context.Context, tasks []*taskState) []error {
 	if len(tasks) == 0 {
 		return nil
 	}
 
+	var semaphore chan struct{}
+	if e.options.MaxConcurrency > 0 {
+		semaphore = make(chan struct{}, e.options.MaxConcurrency)
+	}
+
 	var wg sync.WaitGroup
 	results := make([]error, len(tasks))
 	for i := 0; i < len(tasks); i++ {
@@ -183,8 +352,48 @@ func (e *executor) forkJoin(tasks []*taskState) []error {
 		go func(ts *taskState, index int) {
 			results[index] = fmt.Errorf("function panic")
 			defer wg.Done()
+			if e.options.TaskAPI != nil {
+				if apiSemaphore := e.apiSemaphores[e.options.TaskAPI(ts.task)]; apiSemaphore != nil {
+					apiSemaphore <- struct{}{}
+					defer func() { <-apiSemaphore }()
+				}
+			}
+			if semaphore != nil {
+				semaphore <- struct{}{}
+				defer func() { <-semaphore }()
+			}
+			var hash string
+			var before reflect.Value
+			if e.isCacheable(ts.task) {
+				var err error
+				hash, err = hashTask(ts.key, ts.task)
+				if err != nil {
+					klog.V(4).Infof("not caching task %q: %v", ts.key, err)
+				} else if e.options.Cache.restore(ts.key, hash, ts.task) {
+					klog.V(2).Infof("Skipping task %q: unchanged since it was last applied", ts.key)
+					ts.cached = true
+					results[index] = nil
+					return
+				} else {
+					before = reflect.New(reflect.TypeOf(ts.task).Elem()).Elem()
+					before.Set(reflect.ValueOf(ts.task).Elem())
+				}
+			}
 			klog.V(2).Infof("Executing task %q: %v\n", ts.key, ts.task)
+			_, span := tracing.StartSpan(ctx, ts.key)
+			start := time.Now()
 			results[index] = ts.task.Run(e.context)
+			ts.attempts++
+			ts.duration += time.Since(start)
+			tracing.EndSpan(span, results[index])
+			if before.IsValid() {
+				if results[index] == nil {
+					e.options.Cache.record(ts.key, hash, before, ts.task)
+				} else {
+					e.options.Cache.forget(ts.key)
+				}
+			}
+			klog.V(2).InfoS("Finished task", "task", ts.key, "duration", time.Since(start), "err", results[index])
 		}(tasks[i], i)
 	}
 
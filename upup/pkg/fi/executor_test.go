@@ -0,0 +1,161 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errTestThrottled = errors.New("Throttling: Rate exceeded")
+
+// testExecutorTask fails with a throttling error until it has been throttled the given number of times
+type testExecutorTask struct {
+	API      string
+	Throttle int
+
+	tracker *concurrencyTracker
+}
+
+var _ Task = &testExecutorTask{}
+
+func (t *testExecutorTask) Run(_ *Context) error {
+	if t.tracker != nil {
+		t.tracker.start(t.API)
+		defer t.tracker.stop(t.API)
+		time.Sleep(10 * time.Millisecond)
+	}
+	if t.Throttle > 0 {
+		t.Throttle--
+		return errTestThrottled
+	}
+	return nil
+}
+
+type concurrencyTracker struct {
+	mutex   sync.Mutex
+	running map[string]int
+	max     map[string]int
+}
+
+func (c *concurrencyTracker) start(api string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.running[api]++
+	if c.running[api] > c.max[api] {
+		c.max[api] = c.running[api]
+	}
+}
+
+func (c *concurrencyTracker) stop(api string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.running[api]--
+}
+
+func runTestTasks(t *testing.T, tasks map[string]Task, options RunTasksOptions) *RunTasksReport {
+	t.Helper()
+
+	c, err := NewContext(nil, nil, nil, nil, nil, nil, true, tasks)
+	if err != nil {
+		t.Fatalf("error building context: %v", err)
+	}
+	defer c.Close()
+
+	if err := c.RunTasks(context.Background(), options); err != nil {
+		t.Fatalf("unexpected error running tasks: %v", err)
+	}
+	return c.Report()
+}
+
+func TestRunTasksThrottlingBackoff(t *testing.T) {
+	var options RunTasksOptions
+	options.InitDefaults()
+	options.ThrottlingBackoff = 10 * time.Millisecond
+	options.MaxThrottlingBackoff = 20 * time.Millisecond
+	options.IsThrottlingError = func(err error) bool {
+		return errors.Is(err, errTestThrottled)
+	}
+
+	tasks := map[string]Task{
+		"throttled": &testExecutorTask{Throttle: 3},
+		"fine":      &testExecutorTask{},
+	}
+	start := time.Now()
+	report := runTestTasks(t, tasks, options)
+	if elapsed := time.Since(start); elapsed >= options.WaitAfterAllTasksFailed {
+		t.Errorf("throttled task should be retried after its backoff, took %v", elapsed)
+	}
+
+	results := make(map[string]*TaskResult)
+	for _, result := range report.Tasks {
+		results[result.Key] = result
+	}
+	if r := results["throttled"]; r == nil || r.Attempts != 4 || r.Throttled != 3 {
+		t.Errorf("unexpected result for throttled task: %+v", r)
+	}
+	if r := results["fine"]; r == nil || r.Attempts != 1 || r.Throttled != 0 {
+		t.Errorf("unexpected result for task: %+v", r)
+	}
+}
+
+func TestThrottlingBackoff(t *testing.T) {
+	e := &executor{}
+	e.options.InitDefaults()
+
+	for throttled, expected := range map[int]time.Duration{1: 2 * time.Second, 2: 4 * time.Second, 3: 8 * time.Second, 10: time.Minute, 100: time.Minute} {
+		for i := 0; i < 10; i++ {
+			backoff := e.throttlingBackoff(throttled)
+			if backoff < expected/2 || backoff > expected {
+				t.Errorf("backoff after %d throttles was %v, expected between %v and %v", throttled, backoff, expected/2, expected)
+			}
+		}
+	}
+}
+
+func TestRunTasksMaxConcurrencyPerAPI(t *testing.T) {
+	var options RunTasksOptions
+	options.InitDefaults()
+	options.MaxConcurrencyPerAPI = map[string]int{"iam": 2}
+	options.TaskAPI = func(task Task) string {
+		return task.(*testExecutorTask).API
+	}
+
+	tracker := &concurrencyTracker{
+		running: make(map[string]int),
+		max:     make(map[string]int),
+	}
+	tasks := make(map[string]Task)
+	for _, key := range []string{"a", "b", "c", "d", "e", "f"} {
+		tasks["iam-"+key] = &testExecutorTask{API: "iam", tracker: tracker}
+		tasks["ec2-"+key] = &testExecutorTask{API: "ec2", tracker: tracker}
+	}
+
+	report := runTestTasks(t, tasks, options)
+	if len(report.Tasks) != len(tasks) {
+		t.Errorf("expected %d task results, got %d", len(tasks), len(report.Tasks))
+	}
+	if tracker.max["iam"] != 2 {
+		t.Errorf("expected at most 2 concurrent iam tasks, got %d", tracker.max["iam"])
+	}
+	if tracker.max["ec2"] <= 2 {
+		t.Errorf("expected ec2 tasks not to be limited, got %d concurrent", tracker.max["ec2"])
+	}
+}
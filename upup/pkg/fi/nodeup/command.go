@@ -36,6 +36,8 @@ import (
 	"k8s.io/kops/pkg/apis/nodeup"
 	"k8s.io/kops/pkg/assets"
 	"k8s.io/kops/pkg/configserver"
+	"k8s.io/kops/pkg/envelope"
+	"k8s.io/kops/pkg/logformat"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/nodeup/cloudinit"
@@ -68,9 +70,7 @@ type NodeUpCommand struct {
 }
 
 // Run is responsible for perform the nodeup process
-func (c *NodeUpCommand) Run(out io.Writer) error {
-	ctx := context.Background()
-
+func (c *NodeUpCommand) Run(ctx context.Context, out io.Writer) error {
 	if c.ConfigLocation != "" {
 		config, err := vfs.Context.ReadFile(c.ConfigLocation)
 		if err != nil {
@@ -89,6 +89,12 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		return fmt.Errorf("CacheDir is required")
 	}
 
+	if c.Target == "direct" {
+		if err := runPreflight(ctx, out, "bootstrap", bootstrapPreflightEndpoints(c.config), probePreflightEndpoint); err != nil {
+			return err
+		}
+	}
+
 	var configBase vfs.Path
 
 	// If we're using a config server instead of vfs, nodeConfig will hold our configuration
@@ -173,6 +179,11 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		klog.Warningf("No instance group defined in nodeup config")
 	}
 
+	logformat.WithValues("cluster", c.cluster.ObjectMeta.Name)
+	if c.instanceGroup != nil {
+		logformat.WithValues("instancegroup", c.instanceGroup.ObjectMeta.Name)
+	}
+
 	err := evaluateSpec(c)
 	if err != nil {
 		return err
@@ -188,8 +199,14 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		return fmt.Errorf("error determining OS distribution: %v", err)
 	}
 
-	configAssets := c.config.Assets[architecture]
 	assetStore := fi.NewAssetStore(c.CacheDir)
+	if c.Target == "direct" {
+		if err := runPreflight(ctx, out, "node", nodePreflightEndpoints(c.cluster, c.config, architecture, assetStore), probePreflightEndpoint); err != nil {
+			return err
+		}
+	}
+
+	configAssets := c.config.Assets[architecture]
 	for _, asset := range configAssets {
 		err := assetStore.Add(asset)
 		if err != nil {
@@ -233,7 +250,7 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 			return fmt.Errorf("error building secret store path: %v", err)
 		}
 
-		secretStore = secrets.NewVFSSecretStore(c.cluster, p)
+		secretStore = secrets.NewVFSSecretStore(c.cluster, envelope.ForCluster(c.cluster, p))
 		modelContext.SecretStore = secretStore
 	} else {
 		return fmt.Errorf("SecretStore not set")
@@ -248,7 +265,7 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 			return fmt.Errorf("error building key store path: %v", err)
 		}
 
-		modelContext.KeyStore = fi.NewVFSCAStore(c.cluster, p)
+		modelContext.KeyStore = fi.NewVFSCAStore(c.cluster, envelope.ForCluster(c.cluster, p))
 		keyStore = modelContext.KeyStore
 	} else {
 		return fmt.Errorf("KeyStore not set")
@@ -271,56 +288,81 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		}
 	}
 
-	if err := loadKernelModules(modelContext); err != nil {
-		return err
+	if !distribution.UsesSettingsAPI() && !distribution.IsWindows() {
+		if err := loadKernelModules(modelContext); err != nil {
+			return err
+		}
 	}
 
 	loader := &Loader{}
-	loader.Builders = append(loader.Builders, &model.NTPBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.MiscUtilsBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.DirectoryBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.UpdateServiceBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.VolumesBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.ContainerdBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.DockerBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.ProtokubeBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.CloudConfigBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.FileAssetsBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.HookBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.KubeletBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.KubectlBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.EtcdBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.LogrotateBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.ManifestsBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.PackagesBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.SecretBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.FirewallBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.SysctlBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.KubeAPIServerBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.KubeControllerManagerBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.KubeSchedulerBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.EtcdManagerTLSBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.KubeProxyBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.KopsControllerBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.AWSEBSCSIDriverBuilder{NodeupModelContext: modelContext})
-
-	loader.Builders = append(loader.Builders, &networking.CommonBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &networking.CalicoBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &networking.CiliumBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &networking.KuberouterBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &networking.LyftVPCBuilder{NodeupModelContext: modelContext})
-
-	loader.Builders = append(loader.Builders, &model.BootstrapClientBuilder{NodeupModelContext: modelContext})
-	taskMap, err := loader.Build()
+	if distribution.UsesSettingsAPI() {
+		// The OS manages its own services, so we only hand it our configuration
+		loader.Builders = append(loader.Builders, &model.BottlerocketBuilder{NodeupModelContext: modelContext})
+	} else if distribution.IsWindows() {
+		loader.Builders = append(loader.Builders, &model.WindowsBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.BootstrapClientBuilder{NodeupModelContext: modelContext})
+	} else {
+		loader.Builders = append(loader.Builders, &model.NTPBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.MiscUtilsBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.DirectoryBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.UpdateServiceBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.VolumesBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.InstanceStorageBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.SwapBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.NodeTuningBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.SecurityModulesBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.ContainerdBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.NvidiaBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.DockerBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.ProtokubeBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.EtcHostsBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.InstanceAccessBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.CloudConfigBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.FileAssetsBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.HookBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.KubeletBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.GracefulShutdownBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.ImagePreloadBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.KubectlBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.EtcdBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.LogrotateBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.ManifestsBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.PackagesBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.SecretBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.FirewallBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.SysctlBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.KubeAPIServerBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.EncryptionProviderBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.KubeControllerManagerBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.KubeSchedulerBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.EtcdManagerTLSBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.KubeProxyBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.KopsControllerBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.AWSEBSCSIDriverBuilder{NodeupModelContext: modelContext})
+
+		loader.Builders = append(loader.Builders, &networking.CommonBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &networking.AntreaBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &networking.CalicoBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &networking.CiliumBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &networking.KuberouterBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &networking.LyftVPCBuilder{NodeupModelContext: modelContext})
+
+		loader.Builders = append(loader.Builders, &model.BootstrapClientBuilder{NodeupModelContext: modelContext})
+		loader.Builders = append(loader.Builders, &model.CISBuilder{NodeupModelContext: modelContext})
+	}
+	taskMap, err := loader.Build(ctx)
 	if err != nil {
 		return fmt.Errorf("error building loader: %v", err)
 	}
 
-	for i, image := range c.config.Images[architecture] {
-		taskMap["LoadImage."+strconv.Itoa(i)] = &nodetasks.LoadImageTask{
-			Sources: image.Sources,
-			Hash:    image.Hash,
-			Runtime: c.cluster.Spec.ContainerRuntime,
+	// On distros with a settings API nodeup cannot reach the container runtime, and Windows nodes run other images, so images are pulled on demand
+	if !distribution.UsesSettingsAPI() && !distribution.IsWindows() {
+		for i, image := range c.config.Images[architecture] {
+			taskMap["LoadImage."+strconv.Itoa(i)] = &nodetasks.LoadImageTask{
+				Sources: image.Sources,
+				Hash:    image.Hash,
+				Runtime: c.cluster.Spec.ContainerRuntime,
+			}
 		}
 	}
 	// Protokube load image task is in ProtokubeBuilder
@@ -352,7 +394,7 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	var options fi.RunTasksOptions
 	options.InitDefaults()
 
-	err = context.RunTasks(options)
+	err = context.RunTasks(ctx, options)
 	if err != nil {
 		klog.Exitf("error running tasks: %v", err)
 	}
@@ -17,10 +17,12 @@ limitations under the License.
 package nodeup
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 
 	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/tracing"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
 )
@@ -30,13 +32,15 @@ type Loader struct {
 }
 
 // Build is responsible for running the build tasks for nodeup
-func (l *Loader) Build() (map[string]fi.Task, error) {
+func (l *Loader) Build(ctx context.Context) (map[string]fi.Task, error) {
 	tasks := make(map[string]fi.Task)
 	for _, builder := range l.Builders {
 		context := &fi.ModelBuilderContext{
 			Tasks: tasks,
 		}
+		_, span := tracing.StartSpan(ctx, reflect.TypeOf(builder).String())
 		err := builder.Build(context)
+		tracing.EndSpan(span, err)
 		if err != nil {
 			return nil, fmt.Errorf("building %s: %v", reflect.TypeOf(builder), err)
 		}
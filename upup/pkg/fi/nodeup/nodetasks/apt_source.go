@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetasks
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/cloudinit"
+	"k8s.io/kops/upup/pkg/fi/nodeup/local"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+const (
+	aptKeyringsDir = "/usr/share/keyrings"
+	aptSourcesDir  = "/etc/apt/sources.list.d"
+)
+
+// AptSource adds an apt repository, along with the key that signs it
+type AptSource struct {
+	Name string `json:"name"`
+
+	// Keyring is the URL of the ASCII armored key that signs the repository
+	Keyring string `json:"keyring"`
+	// Sources are the repository entries, without the leading "deb" and options
+	Sources []string `json:"sources"`
+}
+
+var _ fi.Task = &AptSource{}
+
+func (e *AptSource) String() string {
+	return fmt.Sprintf("AptSource: %s", e.Name)
+}
+
+var _ fi.HasName = &AptSource{}
+
+func (e *AptSource) GetName() *string {
+	return fi.String("AptSource-" + e.Name)
+}
+
+func (e *AptSource) keyringPath() string {
+	return filepath.Join(aptKeyringsDir, e.Name+".asc")
+}
+
+func (e *AptSource) sourcesPath() string {
+	return filepath.Join(aptSourcesDir, e.Name+".list")
+}
+
+// buildSourcesList returns the contents of the sources list, with every entry pinned to our keyring
+func (e *AptSource) buildSourcesList() string {
+	var lines []string
+	for _, source := range e.Sources {
+		lines = append(lines, fmt.Sprintf("deb [signed-by=%s] %s", e.keyringPath(), source))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func (e *AptSource) Find(c *fi.Context) (*AptSource, error) {
+	if _, err := os.Stat(e.keyringPath()); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error checking for keyring %q: %v", e.keyringPath(), err)
+	}
+
+	sources, err := ioutil.ReadFile(e.sourcesPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading sources list %q: %v", e.sourcesPath(), err)
+	}
+	if string(sources) != e.buildSourcesList() {
+		return nil, nil
+	}
+
+	actual := &AptSource{
+		Name:    e.Name,
+		Keyring: e.Keyring,
+		Sources: e.Sources,
+	}
+	return actual, nil
+}
+
+func (e *AptSource) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(e, c)
+}
+
+func (s *AptSource) CheckChanges(a, e, changes *AptSource) error {
+	return nil
+}
+
+func (_ *AptSource) RenderLocal(t *local.LocalTarget, a, e, changes *AptSource) error {
+	keyring, err := vfs.Context.ReadFile(e.Keyring)
+	if err != nil {
+		return fmt.Errorf("error downloading keyring for %q: %v", e.Name, err)
+	}
+	if err := fi.WriteFile(e.keyringPath(), fi.NewBytesResource(keyring), 0644, 0755, "", ""); err != nil {
+		return err
+	}
+	if err := fi.WriteFile(e.sourcesPath(), fi.NewStringResource(e.buildSourcesList()), 0644, 0755, "", ""); err != nil {
+		return err
+	}
+
+	packageManagerLock.Lock()
+	defer packageManagerLock.Unlock()
+
+	args := []string{"apt-get", "update"}
+	klog.Infof("running command %s", args)
+	cmd := exec.Command(args[0], args[1:]...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("error updating packages for %q: %v: %s", e.Name, err, string(output))
+	}
+
+	return nil
+}
+
+func (_ *AptSource) RenderCloudInit(t *cloudinit.CloudInitTarget, a, e, changes *AptSource) error {
+	return fmt.Errorf("AptSource::RenderCloudInit not implemented")
+}
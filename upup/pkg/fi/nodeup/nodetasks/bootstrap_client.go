@@ -59,12 +59,16 @@ var _ fi.HasName = &BootstrapClientTask{}
 var _ fi.HasDependencies = &BootstrapClientTask{}
 
 func (b *BootstrapClientTask) GetDependencies(tasks map[string]fi.Task) []fi.Task {
-	// BootstrapClient depends on the protokube service to ensure gossip DNS
+	// BootstrapClient depends on the protokube service to ensure gossip DNS,
+	// and on the /etc/hosts entries of the kops-controller name
 	var deps []fi.Task
 	for _, v := range tasks {
 		if svc, ok := v.(*Service); ok && svc.Name == protokubeService {
 			deps = append(deps, v)
 		}
+		if _, ok := v.(*UpdateEtcHostsTask); ok {
+			deps = append(deps, v)
+		}
 	}
 	return deps
 }
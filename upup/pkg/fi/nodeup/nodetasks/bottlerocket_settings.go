@@ -0,0 +1,84 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetasks
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/cloudinit"
+	"k8s.io/kops/upup/pkg/fi/nodeup/local"
+)
+
+// BottlerocketSettings applies settings through the Bottlerocket API, which then restarts the affected services
+type BottlerocketSettings struct {
+	Name string `json:"name"`
+
+	// Settings is the tree of settings to apply, rooted below the "settings" key of the API
+	Settings map[string]interface{} `json:"settings"`
+}
+
+var _ fi.Task = &BottlerocketSettings{}
+
+func (e *BottlerocketSettings) String() string {
+	return fmt.Sprintf("BottlerocketSettings: %s", e.Name)
+}
+
+var _ fi.HasName = &BottlerocketSettings{}
+
+func (e *BottlerocketSettings) GetName() *string {
+	return fi.String("BottlerocketSettings-" + e.Name)
+}
+
+func (e *BottlerocketSettings) Find(c *fi.Context) (*BottlerocketSettings, error) {
+	// Setting values that are already set is a no-op for the API, so we always apply them
+	return nil, nil
+}
+
+func (e *BottlerocketSettings) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(e, c)
+}
+
+func (s *BottlerocketSettings) CheckChanges(a, e, changes *BottlerocketSettings) error {
+	return nil
+}
+
+func (_ *BottlerocketSettings) RenderLocal(t *local.LocalTarget, a, e, changes *BottlerocketSettings) error {
+	return e.execute(t)
+}
+
+func (e *BottlerocketSettings) execute(t Executor) error {
+	settings, err := json.Marshal(e.Settings)
+	if err != nil {
+		return fmt.Errorf("error serializing settings %q: %v", e.Name, err)
+	}
+
+	args := []string{"apiclient", "set", "--json", string(settings)}
+
+	klog.Infof("applying Bottlerocket settings %q", e.Name)
+	if output, err := t.CombinedOutput(args); err != nil {
+		return fmt.Errorf("error applying Bottlerocket settings %q: %v: %s", e.Name, err, string(output))
+	}
+
+	return nil
+}
+
+func (_ *BottlerocketSettings) RenderCloudInit(t *cloudinit.CloudInitTarget, a, e, changes *BottlerocketSettings) error {
+	return fmt.Errorf("BottlerocketSettings::RenderCloudInit not implemented")
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetasks
+
+import (
+	"testing"
+)
+
+func TestBottlerocketSettingsCommand(t *testing.T) {
+	settings := &BottlerocketSettings{
+		Name: "kubernetes",
+		Settings: map[string]interface{}{
+			"kubernetes": map[string]interface{}{
+				"cluster-name": "minimal.example.com",
+				"node-labels": map[string]string{
+					"kops.k8s.io/instancegroup": "nodes",
+				},
+			},
+		},
+	}
+
+	executor := &MockExecutor{
+		Commands: []*MockCommand{
+			{Args: []string{"apiclient", "set", "--json", `{"kubernetes":{"cluster-name":"minimal.example.com","node-labels":{"kops.k8s.io/instancegroup":"nodes"}}}`}},
+		},
+	}
+
+	if err := settings.execute(executor); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(executor.Commands) != 0 {
+		t.Errorf("not all expected commands were called: %s", executor.Commands)
+	}
+}
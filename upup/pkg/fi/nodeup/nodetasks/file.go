@@ -21,9 +21,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"strconv"
 	"strings"
-	"syscall"
 
 	"k8s.io/klog/v2"
 	"k8s.io/kops/upup/pkg/fi"
@@ -125,27 +123,9 @@ func findFile(p string) (*File, error) {
 	actual.Path = p
 	actual.Mode = fi.String(fi.FileModeToString(stat.Mode() & os.ModePerm))
 
-	uid := int(stat.Sys().(*syscall.Stat_t).Uid)
-	owner, err := fi.LookupUserByID(uid)
-	if err != nil {
+	if err := findFileOwner(stat, actual); err != nil {
 		return nil, err
 	}
-	if owner != nil {
-		actual.Owner = fi.String(owner.Name)
-	} else {
-		actual.Owner = fi.String(strconv.Itoa(uid))
-	}
-
-	gid := int(stat.Sys().(*syscall.Stat_t).Gid)
-	group, err := fi.LookupGroupByID(gid)
-	if err != nil {
-		return nil, err
-	}
-	if group != nil {
-		actual.Group = fi.String(group.Name)
-	} else {
-		actual.Group = fi.String(strconv.Itoa(gid))
-	}
 
 	if (stat.Mode() & os.ModeSymlink) != 0 {
 		target, err := os.Readlink(p)
@@ -73,6 +73,13 @@ func (e *Package) GetDependencies(tasks map[string]fi.Task) []fi.Task {
 		}
 	}
 
+	// Packages may come from any of the added repositories
+	for _, v := range tasks {
+		if _, ok := v.(*AptSource); ok {
+			deps = append(deps, v)
+		}
+	}
+
 	// If this package is a bare deb, install it after OS managed packages
 	if !e.isOSPackage() {
 		for _, v := range tasks {
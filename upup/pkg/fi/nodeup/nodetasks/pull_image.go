@@ -29,6 +29,15 @@ import (
 type PullImageTask struct {
 	Name    string
 	Runtime string
+
+	// Source is the image to pull, which is tagged as Name once pulled; Name is pulled if empty
+	Source string `json:"source,omitempty"`
+	// HostsDir is the directory of the containerd registry hosts files, which ctr only reads when told to
+	HostsDir string `json:"hostsDir,omitempty"`
+	// BeforeServices are the services that are only started once the image is pulled
+	BeforeServices []string `json:"beforeServices,omitempty"`
+	// BestEffort logs the errors pulling the image instead of failing the task
+	BestEffort bool `json:"bestEffort,omitempty"`
 }
 
 var _ fi.Task = &PullImageTask{}
@@ -63,23 +72,45 @@ func (e *PullImageTask) Run(c *fi.Context) error {
 		return fmt.Errorf("no runtime specified")
 	}
 
+	source := e.Name
+	if e.Source != "" {
+		source = e.Source
+	}
+
 	// Pull the container image
-	var args []string
+	var commands [][]string
 	switch runtime {
 	case "docker":
-		args = []string{"docker", "pull", e.Name}
+		commands = append(commands, []string{"docker", "pull", source})
+		if source != e.Name {
+			commands = append(commands, []string{"docker", "tag", source, e.Name})
+		}
 	case "containerd":
-		args = []string{"ctr", "--namespace", "k8s.io", "images", "pull", e.Name}
+		args := []string{"ctr", "--namespace", "k8s.io", "images", "pull"}
+		if e.HostsDir != "" {
+			args = append(args, "--hosts-dir", e.HostsDir)
+		}
+		commands = append(commands, append(args, source))
+		if source != e.Name {
+			commands = append(commands, []string{"ctr", "--namespace", "k8s.io", "images", "tag", "--force", source, e.Name})
+		}
 	default:
 		return fmt.Errorf("unknown container runtime: %s", runtime)
 	}
-	human := strings.Join(args, " ")
 
-	klog.Infof("running command %s", human)
-	cmd := exec.Command(args[0], args[1:]...)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("error pulling docker image with '%s': %v: %s", human, err, string(output))
+	for _, args := range commands {
+		human := strings.Join(args, " ")
+
+		klog.Infof("running command %s", human)
+		cmd := exec.Command(args[0], args[1:]...)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			if e.BestEffort {
+				klog.Warningf("error pulling image with '%s': %v: %s", human, err, string(output))
+				return nil
+			}
+			return fmt.Errorf("error pulling docker image with '%s': %v: %s", human, err, string(output))
+		}
 	}
 
 	return nil
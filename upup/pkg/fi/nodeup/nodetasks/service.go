@@ -75,8 +75,14 @@ func (p *Service) GetDependencies(tasks map[string]fi.Task) []fi.Task {
 		switch v := v.(type) {
 		case *Package, *UpdatePackages, *UserTask, *GroupTask, *Chattr, *BindMount, *Archive:
 			deps = append(deps, v)
-		case *Service, *LoadImageTask, *PullImageTask, *IssueCert, *BootstrapClientTask, *KubeConfig:
+		case *Service, *LoadImageTask, *IssueCert, *BootstrapClientTask, *KubeConfig:
 			// ignore
+		case *PullImageTask:
+			for _, s := range v.BeforeServices {
+				if p.Name == s {
+					deps = append(deps, v)
+				}
+			}
 		case *File:
 			if len(v.BeforeServices) > 0 {
 				for _, s := range v.BeforeServices {
@@ -155,6 +161,8 @@ func (e *Service) systemdSystemPath() (string, error) {
 		return flatcarSystemdSystemPath, nil
 	} else if d == distributions.DistributionContainerOS {
 		return containerosSystemdSystemPath, nil
+	} else if d.UsesSettingsAPI() {
+		return "", fmt.Errorf("unsupported systemd system: services are configured through the settings API")
 	} else {
 		return "", fmt.Errorf("unsupported systemd system")
 	}
@@ -0,0 +1,95 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetasks
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/protokube/pkg/gossip/dns/hosts"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/cloudinit"
+	"k8s.io/kops/upup/pkg/fi/nodeup/local"
+)
+
+// UpdateEtcHostsTask pins hostnames to addresses in the kops-managed block of /etc/hosts
+type UpdateEtcHostsTask struct {
+	Name string `json:"name"`
+
+	// Hostnames are the names that are pinned to the addresses.
+	Hostnames []string `json:"hostnames,omitempty"`
+	// Resolve is the name that is resolved to find the addresses, when set.
+	Resolve string `json:"resolve,omitempty"`
+	// IPs are the addresses, when Resolve is not set.
+	IPs []string `json:"ips,omitempty"`
+}
+
+var _ fi.Task = &UpdateEtcHostsTask{}
+var _ fi.HasName = &UpdateEtcHostsTask{}
+
+func (e *UpdateEtcHostsTask) String() string {
+	return fmt.Sprintf("UpdateEtcHostsTask: %s", e.Name)
+}
+
+func (e *UpdateEtcHostsTask) GetName() *string {
+	return &e.Name
+}
+
+func (e *UpdateEtcHostsTask) Find(c *fi.Context) (*UpdateEtcHostsTask, error) {
+	// The addresses may have changed, so we always update the file; unchanged files are not rewritten
+	return nil, nil
+}
+
+func (e *UpdateEtcHostsTask) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(e, c)
+}
+
+func (_ *UpdateEtcHostsTask) CheckChanges(a, e, changes *UpdateEtcHostsTask) error {
+	if len(e.Hostnames) == 0 {
+		return fi.RequiredField("Hostnames")
+	}
+	return nil
+}
+
+func (_ *UpdateEtcHostsTask) RenderLocal(t *local.LocalTarget, a, e, changes *UpdateEtcHostsTask) error {
+	addresses := e.IPs
+	if e.Resolve != "" {
+		resolved, err := net.LookupHost(e.Resolve)
+		if err != nil {
+			return fmt.Errorf("error resolving %q: %w", e.Resolve, err)
+		}
+		sort.Strings(resolved)
+		addresses = resolved
+	}
+	if len(addresses) == 0 {
+		return fmt.Errorf("no addresses to pin %v to", e.Hostnames)
+	}
+
+	addrToHosts := make(map[string][]string)
+	for _, address := range addresses {
+		addrToHosts[address] = append([]string(nil), e.Hostnames...)
+	}
+
+	klog.Infof("pinning %v to %v in /etc/hosts", e.Hostnames, addresses)
+	return hosts.UpdateHostsFileWithRecords("/etc/hosts", addrToHosts)
+}
+
+func (_ *UpdateEtcHostsTask) RenderCloudInit(t *cloudinit.CloudInitTarget, a, e, changes *UpdateEtcHostsTask) error {
+	return fmt.Errorf("UpdateEtcHostsTask::RenderCloudInit not implemented")
+}
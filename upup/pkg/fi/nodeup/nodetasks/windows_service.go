@@ -0,0 +1,121 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetasks
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/cloudinit"
+	"k8s.io/kops/upup/pkg/fi/nodeup/local"
+)
+
+// WindowsService registers a service with the Windows service control manager and (re)starts it
+type WindowsService struct {
+	Name string `json:"name"`
+
+	// BinaryPath is the command line of the service, including its arguments
+	BinaryPath string `json:"binaryPath"`
+
+	// DependsOn is the list of services that must be running before this service is started
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+var _ fi.Task = &WindowsService{}
+var _ fi.HasDependencies = &WindowsService{}
+
+// GetDependencies implements HasDependencies::GetDependencies
+func (e *WindowsService) GetDependencies(tasks map[string]fi.Task) []fi.Task {
+	var deps []fi.Task
+	for _, v := range tasks {
+		// Services depend on all the files they use, and on the services listed in DependsOn
+		if s, ok := v.(*WindowsService); ok {
+			for _, name := range e.DependsOn {
+				if s.Name == name {
+					deps = append(deps, v)
+				}
+			}
+			continue
+		}
+		deps = append(deps, v)
+	}
+	return deps
+}
+
+func (e *WindowsService) String() string {
+	return fmt.Sprintf("WindowsService: %s", e.Name)
+}
+
+var _ fi.HasName = &WindowsService{}
+
+func (e *WindowsService) GetName() *string {
+	return fi.String("WindowsService-" + e.Name)
+}
+
+func (e *WindowsService) Find(c *fi.Context) (*WindowsService, error) {
+	// The service configuration is cheap to apply, so we always apply it and restart the service
+	return nil, nil
+}
+
+func (e *WindowsService) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(e, c)
+}
+
+func (s *WindowsService) CheckChanges(a, e, changes *WindowsService) error {
+	return nil
+}
+
+func (_ *WindowsService) RenderLocal(t *local.LocalTarget, a, e, changes *WindowsService) error {
+	return e.execute(t)
+}
+
+func (e *WindowsService) execute(t Executor) error {
+	verb := "config"
+	if _, err := t.CombinedOutput([]string{"sc.exe", "query", e.Name}); err != nil {
+		verb = "create"
+	}
+
+	args := []string{"sc.exe", verb, e.Name, "binPath=", e.BinaryPath, "start=", "auto"}
+	if len(e.DependsOn) != 0 {
+		args = append(args, "depend=", strings.Join(e.DependsOn, "/"))
+	}
+
+	klog.Infof("registering Windows service %q", e.Name)
+	if output, err := t.CombinedOutput(args); err != nil {
+		return fmt.Errorf("error registering service %q: %v: %s", e.Name, err, string(output))
+	}
+
+	// Restart the service if it exits unexpectedly
+	args = []string{"sc.exe", "failure", e.Name, "reset=", "0", "actions=", "restart/10000"}
+	if output, err := t.CombinedOutput(args); err != nil {
+		return fmt.Errorf("error configuring recovery of service %q: %v: %s", e.Name, err, string(output))
+	}
+
+	klog.Infof("restarting Windows service %q", e.Name)
+	args = []string{"powershell.exe", "-NoProfile", "-Command", "Restart-Service", "-Name", e.Name}
+	if output, err := t.CombinedOutput(args); err != nil {
+		return fmt.Errorf("error restarting service %q: %v: %s", e.Name, err, string(output))
+	}
+
+	return nil
+}
+
+func (_ *WindowsService) RenderCloudInit(t *cloudinit.CloudInitTarget, a, e, changes *WindowsService) error {
+	return fmt.Errorf("WindowsService::RenderCloudInit not implemented")
+}
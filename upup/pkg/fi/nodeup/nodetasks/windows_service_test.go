@@ -0,0 +1,69 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetasks
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestWindowsServiceCommands(t *testing.T) {
+	grid := []struct {
+		service  *WindowsService
+		executor *MockExecutor
+	}{
+		{
+			service: &WindowsService{
+				Name:       "containerd",
+				BinaryPath: `"C:\Program Files\containerd\containerd.exe" --run-service`,
+			},
+			executor: &MockExecutor{
+				Commands: []*MockCommand{
+					{Args: []string{"sc.exe", "query", "containerd"}, Error: fmt.Errorf("exit status 1060")},
+					{Args: []string{"sc.exe", "create", "containerd", "binPath=", `"C:\Program Files\containerd\containerd.exe" --run-service`, "start=", "auto"}},
+					{Args: []string{"sc.exe", "failure", "containerd", "reset=", "0", "actions=", "restart/10000"}},
+					{Args: []string{"powershell.exe", "-NoProfile", "-Command", "Restart-Service", "-Name", "containerd"}},
+				},
+			},
+		},
+		{
+			service: &WindowsService{
+				Name:       "kubelet",
+				BinaryPath: `C:\k\kubelet.exe --windows-service`,
+				DependsOn:  []string{"containerd"},
+			},
+			executor: &MockExecutor{
+				Commands: []*MockCommand{
+					{Args: []string{"sc.exe", "query", "kubelet"}},
+					{Args: []string{"sc.exe", "config", "kubelet", "binPath=", `C:\k\kubelet.exe --windows-service`, "start=", "auto", "depend=", "containerd"}},
+					{Args: []string{"sc.exe", "failure", "kubelet", "reset=", "0", "actions=", "restart/10000"}},
+					{Args: []string{"powershell.exe", "-NoProfile", "-Command", "Restart-Service", "-Name", "kubelet"}},
+				},
+			},
+		},
+	}
+
+	for _, g := range grid {
+		if err := g.service.execute(g.executor); err != nil {
+			t.Errorf("unexpected error from %v: %v", g.service, err)
+			continue
+		}
+		if len(g.executor.Commands) != 0 {
+			t.Errorf("not all expected commands were called for %v: %s", g.service, g.executor.Commands)
+		}
+	}
+}
@@ -0,0 +1,471 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/nodeup/pkg/model"
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/pkg/dns"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/architectures"
+)
+
+// preflightTimeout is how long each address of an endpoint is given to answer
+const preflightTimeout = 5 * time.Second
+
+// preflightConsole is where the report of an unreachable endpoint is copied, so it shows in the console output of the
+// instance even when nodeup logs only to the journal
+var preflightConsole = "/dev/console"
+
+// preflightEndpoint is an endpoint that the node needs to reach; it is reachable when any of its addresses answers.
+type preflightEndpoint struct {
+	// Purpose is what the node uses the endpoint for, e.g. "state store".
+	Purpose string
+	// Addresses are the alternative host:port addresses of the endpoint, e.g. the mirrors of an asset.
+	Addresses []string
+	// NTP is true when the addresses are time servers, which are queried over UDP instead of dialed.
+	NTP bool
+	// Required is true when nodeup cannot configure the node without the endpoint. An unreachable endpoint that is
+	// not required is only reported, as the components that use it may reach it in ways nodeup cannot see.
+	Required bool
+	// Hint is the advice printed when the endpoint is unreachable.
+	Hint string
+}
+
+// preflightResult is the outcome of probing an endpoint
+type preflightResult struct {
+	Endpoint *preflightEndpoint
+	// Reachable is the address that answered, or "" when none did.
+	Reachable string
+	// Errors are the errors of the addresses that did not answer.
+	Errors []error
+}
+
+// preflightProber checks that the address answers
+type preflightProber func(ctx context.Context, endpoint *preflightEndpoint, address string) error
+
+// runPreflight probes the endpoints, writes a report of the results to out and returns an error listing the required
+// endpoints that are unreachable.
+func runPreflight(ctx context.Context, out io.Writer, phase string, endpoints []*preflightEndpoint, probe preflightProber) error {
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	results := make([]*preflightResult, len(endpoints))
+	var wg sync.WaitGroup
+	for i, endpoint := range endpoints {
+		wg.Add(1)
+		go func(i int, endpoint *preflightEndpoint) {
+			defer wg.Done()
+			result := &preflightResult{Endpoint: endpoint}
+			for _, address := range endpoint.Addresses {
+				err := probe(ctx, endpoint, address)
+				if err == nil {
+					result.Reachable = address
+					break
+				}
+				result.Errors = append(result.Errors, err)
+			}
+			results[i] = result
+		}(i, endpoint)
+	}
+	wg.Wait()
+
+	report, failed := preflightReport(phase, results)
+	fmt.Fprint(out, report)
+	if unreachable(results) {
+		klog.Warningf("connectivity preflight (%s) found unreachable endpoints", phase)
+		if err := writeToConsole(report); err != nil {
+			klog.V(2).Infof("unable to copy the preflight report to %s: %v", preflightConsole, err)
+		}
+	}
+
+	if len(failed) != 0 {
+		return fmt.Errorf("connectivity preflight failed, unable to reach the %s; see the report above for how to fix it", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// preflightReport formats the results, returning the report and the purposes of the required endpoints that failed
+func preflightReport(phase string, results []*preflightResult) (string, []string) {
+	var b strings.Builder
+	var failed []string
+
+	fmt.Fprintf(&b, "Connectivity preflight (%s):\n", phase)
+	for _, result := range results {
+		endpoint := result.Endpoint
+		if result.Reachable != "" {
+			fmt.Fprintf(&b, "  OK    %s: %s\n", endpoint.Purpose, result.Reachable)
+			continue
+		}
+
+		status := "WARN"
+		if endpoint.Required {
+			status = "FAIL"
+			failed = append(failed, endpoint.Purpose)
+		}
+		fmt.Fprintf(&b, "  %s  %s: unable to reach %s\n", status, endpoint.Purpose, strings.Join(endpoint.Addresses, " or "))
+		for _, err := range result.Errors {
+			fmt.Fprintf(&b, "          %v\n", err)
+		}
+		if endpoint.Hint != "" {
+			fmt.Fprintf(&b, "        %s\n", endpoint.Hint)
+		}
+	}
+
+	return b.String(), failed
+}
+
+func unreachable(results []*preflightResult) bool {
+	for _, result := range results {
+		if result.Reachable == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// writeToConsole copies the report to the console, prefixing its lines so they can be found in the console output
+func writeToConsole(report string) error {
+	f, err := os.OpenFile(preflightConsole, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.WriteString(f, "nodeup: "+strings.ReplaceAll(strings.TrimSuffix(report, "\n"), "\n", "\nnodeup: ")+"\n")
+	return err
+}
+
+// probePreflightEndpoint dials the address over TCP, or queries it over SNTP for a time server
+func probePreflightEndpoint(ctx context.Context, endpoint *preflightEndpoint, address string) error {
+	ctx, cancel := context.WithTimeout(ctx, preflightTimeout)
+	defer cancel()
+
+	if endpoint.NTP {
+		return querySNTP(ctx, address)
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// querySNTP sends a client request to the time server and checks that it answers (RFC 4330)
+func querySNTP(ctx context.Context, address string) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+
+	request := make([]byte, 48)
+	// LI = 0, VN = 3, Mode = 3 (client)
+	request[0] = 0x1b
+	if _, err := conn.Write(request); err != nil {
+		return fmt.Errorf("error sending NTP request to %s: %w", address, err)
+	}
+
+	response := make([]byte, 48)
+	n, err := conn.Read(response)
+	if err != nil {
+		return fmt.Errorf("no NTP response from %s: %w", address, err)
+	}
+	if n < 48 || response[0]&0x07 != 4 {
+		return fmt.Errorf("invalid NTP response from %s", address)
+	}
+	return nil
+}
+
+// bootstrapPreflightEndpoints returns the endpoint that nodeup reads the cluster configuration from
+func bootstrapPreflightEndpoints(config *nodeup.Config) []*preflightEndpoint {
+	if config.ConfigServer != nil {
+		u, err := url.Parse(config.ConfigServer.Server)
+		if err != nil {
+			return nil
+		}
+		return []*preflightEndpoint{{
+			Purpose:   "configuration server",
+			Addresses: []string{urlAddress(u)},
+			Required:  true,
+			Hint:      "kops-controller serves the configuration from the control plane; check that it is running and that the security groups or firewall rules let the instances reach it.",
+		}}
+	}
+
+	location := ""
+	if config.ConfigBase != nil {
+		location = *config.ConfigBase
+	} else if config.ClusterLocation != nil {
+		location = *config.ClusterLocation
+	}
+	u, guessed := stateStoreURL(location)
+	if u == nil {
+		return nil
+	}
+	hint := "nodeup reads the cluster configuration from the state store; allow egress to the object store, e.g. with a VPC endpoint or through spec.egressProxy."
+	if guessed {
+		// Regional, private and partition specific endpoints are not known to nodeup, so the guess may be wrong
+		hint += " The address is guessed from the state store location; set S3_ENDPOINT if the object store has another endpoint."
+	}
+	return []*preflightEndpoint{{
+		Purpose:   "state store",
+		Addresses: []string{urlAddress(u)},
+		Required:  !guessed,
+		Hint:      hint,
+	}}
+}
+
+// nodePreflightEndpoints returns the endpoints that nodeup and the components it installs use to configure the node.
+// The mirrors of the assets that are already in the asset store are left out, as nodeup won't download them.
+func nodePreflightEndpoints(cluster *api.Cluster, config *nodeup.Config, architecture architectures.Architecture, assetStore *fi.AssetStore) []*preflightEndpoint {
+	var endpoints []*preflightEndpoint
+
+	seen := make(map[string]bool)
+	for _, asset := range config.Assets[architecture] {
+		if assetStore.IsCached(asset) {
+			continue
+		}
+		var addresses []string
+		for _, location := range assetURLs(asset) {
+			if u, err := url.Parse(location); err == nil && u.Host != "" {
+				addresses = appendUnique(addresses, urlAddress(u))
+			}
+		}
+		key := strings.Join(addresses, ",")
+		if len(addresses) == 0 || seen[key] {
+			continue
+		}
+		seen[key] = true
+		endpoints = append(endpoints, &preflightEndpoint{
+			Purpose:   "asset mirror",
+			Addresses: addresses,
+			Required:  true,
+			Hint:      "nodeup downloads the kubelet, kubectl and the container runtime from here; allow egress to it or mirror the files with spec.assets.fileRepository.",
+		})
+	}
+
+	for _, registry := range imageRegistries(config) {
+		addresses := []string{registryAddress(registry)}
+		for _, mirror := range registryMirrors(config.Containerd, registry) {
+			if u, err := url.Parse(mirror); err == nil && u.Host != "" {
+				addresses = appendUnique(addresses, urlAddress(u))
+			}
+		}
+		endpoints = append(endpoints, &preflightEndpoint{
+			Purpose:   "container registry " + registry,
+			Addresses: addresses,
+			Hint:      "containerd pulls the images of the pods from here and the kubelet cannot start them without it; allow egress to the registry or mirror the images with spec.assets.containerRegistry.",
+		})
+	}
+
+	if config.InstanceGroupRole != api.InstanceGroupRoleMaster {
+		var hosts []string
+		if endpoint := config.APIServerEndpoint; endpoint != nil && endpoint.Resolve != "" {
+			hosts = append(hosts, endpoint.Resolve)
+		} else if endpoint != nil && len(endpoint.IPs) != 0 {
+			hosts = append(hosts, endpoint.IPs...)
+		} else if name := cluster.Spec.MasterInternalName; name != "" && !dns.IsGossipHostname(name) {
+			hosts = append(hosts, name)
+		}
+		var addresses []string
+		for _, host := range hosts {
+			addresses = append(addresses, urlAddress(&url.URL{Scheme: "https", Host: host}))
+		}
+		if len(addresses) != 0 {
+			endpoints = append(endpoints, &preflightEndpoint{
+				Purpose:   "API server",
+				Addresses: addresses,
+				Hint:      "the kubelet registers the node through the API load balancer; check that the control plane is up and that its security groups or firewall rules let the instances reach port 443.",
+			})
+		}
+	}
+
+	if host := model.NTPHost(cluster); host != "" {
+		endpoints = append(endpoints, &preflightEndpoint{
+			Purpose:   "time server",
+			Addresses: []string{net.JoinHostPort(host, "123")},
+			NTP:       true,
+			Hint:      "the node keeps its clock in sync with this server, and clock skew breaks the TLS and cloud API calls; allow UDP port 123 to it or set spec.ntp.managed to false to use another time source.",
+		})
+	}
+
+	return endpoints
+}
+
+// stateStoreURL returns the HTTP endpoint that serves the VFS location, or nil when it is not known.
+// guessed is true when the endpoint is the public endpoint of the object store rather than one that is configured.
+func stateStoreURL(location string) (u *url.URL, guessed bool) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, false
+	}
+
+	var endpoint string
+	switch u.Scheme {
+	case "s3", "do":
+		if s3Endpoint := os.Getenv("S3_ENDPOINT"); s3Endpoint != "" {
+			endpoint = s3Endpoint
+		} else if u.Scheme == "s3" {
+			guessed = true
+			if strings.Contains(u.Host, ".") {
+				// Dotted bucket names don't match the certificate of the virtual-hosted endpoint
+				endpoint = "https://s3.amazonaws.com"
+			} else {
+				endpoint = "https://" + u.Host + ".s3.amazonaws.com"
+			}
+		}
+	case "gs":
+		guessed = true
+		endpoint = "https://storage.googleapis.com"
+	case "azureblob":
+		if account := os.Getenv("AZURE_STORAGE_ACCOUNT"); account != "" {
+			guessed = true
+			endpoint = "https://" + account + ".blob.core.windows.net"
+		}
+	case "http", "https":
+		return u, false
+	}
+	if endpoint == "" {
+		return nil, false
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+
+	u, err = url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, false
+	}
+	return u, guessed
+}
+
+// urlAddress returns the host:port address that a request for the URL connects to, which is the egress proxy when
+// one is configured for it
+func urlAddress(u *url.URL) string {
+	if proxy, err := http.ProxyFromEnvironment(&http.Request{URL: u}); err == nil && proxy != nil {
+		u = proxy
+	}
+	if u.Port() != "" {
+		return u.Host
+	}
+	port := "443"
+	if u.Scheme == "http" {
+		port = "80"
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// assetURLs returns the locations of an asset in one of the formats of fi.AssetStore.Add
+func assetURLs(asset string) []string {
+	if i := strings.Index(asset, "@http"); i != -1 {
+		asset = asset[i+1:]
+	}
+	if !strings.HasPrefix(asset, "http://") && !strings.HasPrefix(asset, "https://") {
+		return nil
+	}
+	return strings.Split(asset, ",")
+}
+
+// imageRegistries returns the registries that the kubelet and the image preload pull from
+func imageRegistries(config *nodeup.Config) []string {
+	var images []string
+	if image := config.KubeletConfig.PodInfraContainerImage; image != "" {
+		images = append(images, image)
+	}
+
+	var registries []string
+	if preload := config.ImagePreload; preload != nil {
+		if preload.PullThroughCache != nil && *preload.PullThroughCache != "" {
+			registries = append(registries, *preload.PullThroughCache)
+		} else {
+			images = append(images, preload.Images...)
+		}
+	}
+
+	for _, image := range images {
+		registries = appendUnique(registries, imageRegistry(image))
+	}
+	sort.Strings(registries)
+	return registries
+}
+
+// imageRegistry returns the registry of the image reference, following the rules of docker reference names
+func imageRegistry(image string) string {
+	i := strings.Index(image, "/")
+	if i == -1 {
+		return "docker.io"
+	}
+	domain := image[:i]
+	if !strings.ContainsAny(domain, ".:") && domain != "localhost" {
+		return "docker.io"
+	}
+	return domain
+}
+
+// registryAddress returns the host:port address that a request for the API of the registry connects to
+func registryAddress(registry string) string {
+	if registry == "docker.io" {
+		registry = "registry-1.docker.io"
+	}
+	return urlAddress(&url.URL{Scheme: "https", Host: registry})
+}
+
+// registryMirrors returns the mirrors that containerd tries before the registry
+func registryMirrors(containerd *api.ContainerdConfig, registry string) []string {
+	if containerd == nil {
+		return nil
+	}
+	mirrors := append([]string{}, containerd.RegistryMirrors[registry]...)
+	mirrors = append(mirrors, containerd.RegistryMirrors["*"]...)
+	if config, found := containerd.Registries[registry]; found {
+		mirrors = append(mirrors, config.Mirrors...)
+	}
+	return mirrors
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
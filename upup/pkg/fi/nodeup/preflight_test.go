@@ -0,0 +1,265 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/utils"
+	"k8s.io/kops/util/pkg/architectures"
+	"k8s.io/kops/util/pkg/hashing"
+)
+
+func TestBootstrapPreflightEndpoints(t *testing.T) {
+	grid := []struct {
+		Config   *nodeup.Config
+		Expected []string
+		// Required is whether the endpoint is known rather than guessed from the location
+		Required bool
+	}{
+		{
+			Config:   &nodeup.Config{ConfigBase: fi.String("s3://bucket/minimal.example.com")},
+			Expected: []string{"state store: bucket.s3.amazonaws.com:443"},
+		},
+		{
+			Config:   &nodeup.Config{ConfigBase: fi.String("s3://state.example.com/minimal.example.com")},
+			Expected: []string{"state store: s3.amazonaws.com:443"},
+		},
+		{
+			Config:   &nodeup.Config{ClusterLocation: fi.String("gs://bucket/minimal.example.com/cluster-completed.spec")},
+			Expected: []string{"state store: storage.googleapis.com:443"},
+		},
+		{
+			Config:   &nodeup.Config{ConfigBase: fi.String("https://state.example.com/minimal.example.com")},
+			Expected: []string{"state store: state.example.com:443"},
+			Required: true,
+		},
+		{
+			Config:   &nodeup.Config{ConfigBase: fi.String("memfs://tests/minimal.example.com")},
+			Expected: nil,
+		},
+		{
+			Config: &nodeup.Config{
+				ConfigBase:   fi.String("s3://bucket/minimal.example.com"),
+				ConfigServer: &nodeup.ConfigServerOptions{Server: "https://kops-controller.internal.minimal.example.com:3988/"},
+			},
+			Expected: []string{"configuration server: kops-controller.internal.minimal.example.com:3988"},
+			Required: true,
+		},
+	}
+
+	for _, g := range grid {
+		endpoints := bootstrapPreflightEndpoints(g.Config)
+		actual := describeEndpoints(endpoints)
+		if !reflect.DeepEqual(actual, g.Expected) {
+			t.Errorf("unexpected endpoints: expected %v, got %v", g.Expected, actual)
+		}
+		for _, endpoint := range endpoints {
+			if endpoint.Required != g.Required {
+				t.Errorf("unexpected Required for %s: expected %v, got %v", endpoint.Purpose, g.Required, endpoint.Required)
+			}
+		}
+	}
+}
+
+func TestBootstrapPreflightEndpointsS3Endpoint(t *testing.T) {
+	os.Setenv("S3_ENDPOINT", "https://s3.cn-north-1.amazonaws.com.cn")
+	defer os.Unsetenv("S3_ENDPOINT")
+
+	endpoints := bootstrapPreflightEndpoints(&nodeup.Config{ConfigBase: fi.String("s3://bucket/minimal.example.com")})
+	expected := []string{"state store: s3.cn-north-1.amazonaws.com.cn:443"}
+	if actual := describeEndpoints(endpoints); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("unexpected endpoints: expected %v, got %v", expected, actual)
+	}
+	if !endpoints[0].Required {
+		t.Errorf("expected the configured state store endpoint to be required")
+	}
+}
+
+func TestNodePreflightEndpoints(t *testing.T) {
+	cluster := &api.Cluster{}
+	cluster.ObjectMeta.Name = "minimal.example.com"
+	cluster.Spec.CloudProvider = string(api.CloudProviderAWS)
+	cluster.Spec.MasterInternalName = "api.internal.minimal.example.com"
+
+	config := &nodeup.Config{
+		Assets: map[architectures.Architecture][]string{
+			architectures.ArchitectureAmd64: {
+				"1111111111111111111111111111111111111111111111111111111111111111@https://artifacts.k8s.io/kubelet,https://mirror.example.com/kubelet",
+				"2222222222222222222222222222222222222222222222222222222222222222@https://artifacts.k8s.io/kubectl,https://mirror.example.com/kubectl",
+				"3333333333333333333333333333333333333333333333333333333333333333@https://github.com/containerd/containerd.tar.gz",
+			},
+		},
+		InstanceGroupRole: api.InstanceGroupRoleNode,
+		KubeletConfig:     api.KubeletConfigSpec{PodInfraContainerImage: "k8s.gcr.io/pause:3.2"},
+		Containerd: &api.ContainerdConfig{
+			RegistryMirrors: map[string][]string{"k8s.gcr.io": {"https://registry.example.com:5000"}},
+		},
+		ImagePreload: &api.ImagePreloadSpec{Images: []string{"nginx:1.21", "k8s.gcr.io/pause:3.2"}},
+	}
+
+	expected := []string{
+		"asset mirror: artifacts.k8s.io:443, mirror.example.com:443",
+		"asset mirror: github.com:443",
+		"container registry docker.io: registry-1.docker.io:443",
+		"container registry k8s.gcr.io: k8s.gcr.io:443, registry.example.com:5000",
+		"API server: api.internal.minimal.example.com:443",
+		"time server: 169.254.169.123:123",
+	}
+	assetStore := fi.NewAssetStore(t.TempDir())
+	actual := describeEndpoints(nodePreflightEndpoints(cluster, config, architectures.ArchitectureAmd64, assetStore))
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected endpoints: expected %v, got %v", expected, actual)
+	}
+
+	// The control plane does not wait for its own API server, and the instances of an unmanaged NTP keep their own time servers
+	config.InstanceGroupRole = api.InstanceGroupRoleMaster
+	cluster.Spec.NTP = &api.NTPConfig{Managed: fi.Bool(false)}
+	actual = describeEndpoints(nodePreflightEndpoints(cluster, config, architectures.ArchitectureAmd64, assetStore))
+	if !reflect.DeepEqual(actual, expected[:4]) {
+		t.Errorf("unexpected endpoints: expected %v, got %v", expected[:4], actual)
+	}
+}
+
+func TestNodePreflightEndpointsCachedAssets(t *testing.T) {
+	cacheDir := t.TempDir()
+	contents := []byte("containerd")
+	hash, err := hashing.HashAlgorithmSHA256.Hash(bytes.NewReader(contents))
+	if err != nil {
+		t.Fatalf("error hashing asset: %v", err)
+	}
+	primaryURL := "https://github.com/containerd/containerd.tar.gz"
+	if err := ioutil.WriteFile(filepath.Join(cacheDir, hash.String()+"_"+utils.SanitizeString(primaryURL)), contents, 0644); err != nil {
+		t.Fatalf("error writing cached asset: %v", err)
+	}
+
+	cluster := &api.Cluster{}
+	config := &nodeup.Config{
+		Assets: map[architectures.Architecture][]string{
+			architectures.ArchitectureAmd64: {
+				"1111111111111111111111111111111111111111111111111111111111111111@https://artifacts.k8s.io/kubelet",
+				hash.Hex() + "@" + primaryURL,
+			},
+		},
+		InstanceGroupRole: api.InstanceGroupRoleMaster,
+	}
+
+	expected := []string{"asset mirror: artifacts.k8s.io:443"}
+	actual := describeEndpoints(nodePreflightEndpoints(cluster, config, architectures.ArchitectureAmd64, fi.NewAssetStore(cacheDir)))
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected endpoints: expected %v, got %v", expected, actual)
+	}
+}
+
+func TestRunPreflight(t *testing.T) {
+	endpoints := []*preflightEndpoint{
+		{Purpose: "state store", Addresses: []string{"bucket.s3.amazonaws.com:443"}, Required: true, Hint: "allow egress to the state store"},
+		{Purpose: "asset mirror", Addresses: []string{"artifacts.k8s.io:443", "mirror.example.com:443"}, Required: true},
+		{Purpose: "time server", Addresses: []string{"169.254.169.123:123"}, NTP: true, Hint: "allow UDP port 123"},
+	}
+	reachable := map[string]bool{"mirror.example.com:443": true}
+	probe := func(ctx context.Context, endpoint *preflightEndpoint, address string) error {
+		if reachable[address] {
+			return nil
+		}
+		return fmt.Errorf("dial tcp %s: i/o timeout", address)
+	}
+
+	console := filepath.Join(t.TempDir(), "console")
+	if err := ioutil.WriteFile(console, nil, 0644); err != nil {
+		t.Fatalf("error creating console: %v", err)
+	}
+	defer func(previous string) { preflightConsole = previous }(preflightConsole)
+	preflightConsole = console
+
+	var out bytes.Buffer
+	err := runPreflight(context.Background(), &out, "test", endpoints, probe)
+	if err == nil || !strings.Contains(err.Error(), "unable to reach the state store;") {
+		t.Errorf("expected the state store to fail the preflight, got %v", err)
+	}
+
+	expected := `Connectivity preflight (test):
+  FAIL  state store: unable to reach bucket.s3.amazonaws.com:443
+          dial tcp bucket.s3.amazonaws.com:443: i/o timeout
+        allow egress to the state store
+  OK    asset mirror: mirror.example.com:443
+  WARN  time server: unable to reach 169.254.169.123:123
+          dial tcp 169.254.169.123:123: i/o timeout
+        allow UDP port 123
+`
+	if out.String() != expected {
+		t.Errorf("unexpected report:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+
+	b, err := ioutil.ReadFile(console)
+	if err != nil {
+		t.Fatalf("error reading console: %v", err)
+	}
+	if !strings.HasPrefix(string(b), "nodeup: Connectivity preflight (test):\nnodeup:   FAIL  state store") {
+		t.Errorf("expected the report on the console, got:\n%s", b)
+	}
+
+	reachable["bucket.s3.amazonaws.com:443"] = true
+	if err := runPreflight(context.Background(), &out, "test", endpoints, probe); err != nil {
+		t.Errorf("unexpected error when only an optional endpoint is unreachable: %v", err)
+	}
+}
+
+func TestQuerySNTP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error listening: %v", err)
+	}
+	defer conn.Close()
+
+	go func() {
+		request := make([]byte, 48)
+		_, addr, err := conn.ReadFrom(request)
+		if err != nil {
+			return
+		}
+		response := make([]byte, 48)
+		// LI = 0, VN = 3, Mode = 4 (server)
+		response[0] = 0x1c
+		conn.WriteTo(response, addr)
+	}()
+
+	endpoint := &preflightEndpoint{NTP: true}
+	if err := probePreflightEndpoint(context.Background(), endpoint, conn.LocalAddr().String()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func describeEndpoints(endpoints []*preflightEndpoint) []string {
+	var descriptions []string
+	for _, endpoint := range endpoints {
+		descriptions = append(descriptions, endpoint.Purpose+": "+strings.Join(endpoint.Addresses, ", "))
+	}
+	return descriptions
+}
@@ -55,6 +55,9 @@ func (c *VFSSecretStore) MirrorTo(basedir vfs.Path) error {
 	}
 	klog.V(2).Infof("Mirroring secret store from %q to %q", c.basedir, basedir)
 
+	// Encrypt the mirror like the secret store
+	basedir = vfs.WrapLike(c.basedir, basedir)
+
 	secrets, err := c.ListSecrets()
 	if err != nil {
 		return fmt.Errorf("error listing secrets for mirror: %v", err)
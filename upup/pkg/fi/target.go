@@ -24,3 +24,9 @@ type Target interface {
 	// Some providers (e.g. Terraform) actively keep state, and will delete resources automatically
 	ProcessDeletions() bool
 }
+
+// RendersUnchangedTasks is implemented by targets that must render every task, even one that already exists
+// and has no changes, e.g. a Terraform target that adopts existing resources.
+type RendersUnchangedTasks interface {
+	RendersUnchangedTasks() bool
+}
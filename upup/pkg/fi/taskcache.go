@@ -0,0 +1,330 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+	kopsbase "k8s.io/kops"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// DefaultTaskCacheMaxAge is how long a cached task is trusted before it is reconciled against the cloud again.
+const DefaultTaskCacheMaxAge = 24 * time.Hour
+
+// TaskCache records the tasks that were applied successfully, so that a later run can skip
+// finding the cloud resources of tasks whose definition has not changed since.
+//
+// A task is identified by a hash of its fields, including the resources and tasks it references.
+// The fields a task sets on itself while running, such as the ID of the cloud resource, are
+// recorded alongside the hash and restored when the task is skipped.
+//
+// Changes made to cloud resources outside of kOps are not detected until the entry expires.
+type TaskCache struct {
+	path   vfs.Path
+	maxAge time.Duration
+	// readOnly is set for previews, whose tasks are not applied and so must not be recorded.
+	readOnly bool
+
+	mutex   sync.Mutex
+	entries map[string]*taskCacheEntry
+}
+
+type taskCacheEntry struct {
+	Hash      string                     `json:"hash"`
+	Outputs   map[string]json.RawMessage `json:"outputs,omitempty"`
+	Timestamp time.Time                  `json:"timestamp"`
+}
+
+type taskCacheFile struct {
+	Tasks map[string]*taskCacheEntry `json:"tasks"`
+}
+
+// LoadTaskCache reads the task cache from path; a missing or unreadable cache is treated as empty.
+func LoadTaskCache(path vfs.Path, maxAge time.Duration) *TaskCache {
+	c := &TaskCache{
+		path:    path,
+		maxAge:  maxAge,
+		entries: make(map[string]*taskCacheEntry),
+	}
+
+	data, err := path.ReadFile()
+	if err != nil {
+		if !os.IsNotExist(err) {
+			klog.Warningf("ignoring task cache %s: %v", path, err)
+		}
+		return c
+	}
+
+	file := &taskCacheFile{}
+	if err := json.Unmarshal(data, file); err != nil {
+		klog.Warningf("ignoring task cache %s: %v", path, err)
+		return c
+	}
+	for key, entry := range file.Tasks {
+		if entry != nil {
+			c.entries[key] = entry
+		}
+	}
+	return c
+}
+
+// Save writes the task cache back to where it was loaded from, dropping expired entries; a read-only cache is not written.
+func (c *TaskCache) Save() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.readOnly {
+		return nil
+	}
+
+	file := &taskCacheFile{Tasks: make(map[string]*taskCacheEntry)}
+	for key, entry := range c.entries {
+		if !c.expired(entry) {
+			file.Tasks[key] = entry
+		}
+	}
+
+	data, err := json.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("error serializing task cache: %v", err)
+	}
+	if err := c.path.WriteFile(bytes.NewReader(data), nil); err != nil {
+		return fmt.Errorf("error writing task cache %s: %v", c.path, err)
+	}
+	return nil
+}
+
+// ReadOnly stops the cache from recording tasks or being saved, for runs that do not apply them such as previews.
+func (c *TaskCache) ReadOnly() *TaskCache {
+	c.readOnly = true
+	return c
+}
+
+func (c *TaskCache) expired(entry *taskCacheEntry) bool {
+	return c.maxAge > 0 && time.Since(entry.Timestamp) > c.maxAge
+}
+
+// restore sets the recorded outputs on the task and returns true if the task is unchanged since it was last applied.
+func (c *TaskCache) restore(key string, hash string, task Task) bool {
+	c.mutex.Lock()
+	entry := c.entries[key]
+	c.mutex.Unlock()
+
+	if entry == nil || entry.Hash != hash || c.expired(entry) {
+		return false
+	}
+
+	v := reflect.ValueOf(task).Elem()
+	for name, data := range entry.Outputs {
+		field := v.FieldByName(name)
+		if !field.IsValid() || !field.CanSet() {
+			return false
+		}
+		value := reflect.New(field.Type())
+		if err := json.Unmarshal(data, value.Interface()); err != nil {
+			klog.Warningf("ignoring cached %s of task %q: %v", name, key, err)
+			return false
+		}
+		field.Set(value.Elem())
+	}
+	return true
+}
+
+// record stores the hash of a task that was applied successfully, with the fields it set on itself while running.
+// before is a copy of the task taken before it ran.
+func (c *TaskCache) record(key string, hash string, before reflect.Value, task Task) {
+	if c.readOnly {
+		return
+	}
+
+	after := reflect.ValueOf(task).Elem()
+	outputs := make(map[string]json.RawMessage)
+	for i := 0; i < after.NumField(); i++ {
+		field := after.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if reflect.DeepEqual(before.Field(i).Interface(), after.Field(i).Interface()) {
+			continue
+		}
+		if !isCacheableOutput(field.Type) {
+			klog.V(4).Infof("not caching task %q: field %s of type %v changed while running", key, field.Name, field.Type)
+			c.forget(key)
+			return
+		}
+		data, err := json.Marshal(after.Field(i).Interface())
+		if err != nil {
+			klog.V(4).Infof("not caching task %q: %v", key, err)
+			c.forget(key)
+			return
+		}
+		outputs[field.Name] = data
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries[key] = &taskCacheEntry{
+		Hash:      hash,
+		Outputs:   outputs,
+		Timestamp: time.Now().UTC(),
+	}
+}
+
+// forget removes the entry for a task, so that it is reconciled on the next run.
+func (c *TaskCache) forget(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.entries, key)
+}
+
+// isCacheableOutput returns true if values of the type can be recorded as JSON and restored unchanged.
+func isCacheableOutput(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Ptr, reflect.Slice:
+		return isCacheableOutput(t.Elem())
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String && isCacheableOutput(t.Elem())
+	default:
+		return false
+	}
+}
+
+// hashTask computes the hash identifying the definition of a task.
+// Referenced tasks are hashed as well, so that a change to the outputs of a dependency changes the hash of its dependents.
+func hashTask(key string, task Task) (string, error) {
+	h := &taskHasher{
+		hash:    sha256.New(),
+		visited: make(map[uintptr]bool),
+	}
+	fmt.Fprintf(h.hash, "%s\x00%s\x00", kopsbase.Version, key)
+	if err := h.write(reflect.ValueOf(task)); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.hash.Sum(nil)), nil
+}
+
+type taskHasher struct {
+	hash    hash.Hash
+	visited map[uintptr]bool
+}
+
+var (
+	resourceType = reflect.TypeOf((*Resource)(nil)).Elem()
+	taskType     = reflect.TypeOf((*Task)(nil)).Elem()
+)
+
+func (h *taskHasher) write(v reflect.Value) error {
+	if !v.IsValid() {
+		fmt.Fprint(h.hash, "nil;")
+		return nil
+	}
+
+	if v.Kind() != reflect.Interface && v.Type().Implements(resourceType) && !v.Type().Implements(taskType) {
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			fmt.Fprint(h.hash, "nil;")
+			return nil
+		}
+		data, err := ResourceAsBytes(v.Interface().(Resource))
+		if err != nil {
+			return fmt.Errorf("error reading resource: %v", err)
+		}
+		fmt.Fprintf(h.hash, "resource:%d:", len(data))
+		h.hash.Write(data)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			fmt.Fprint(h.hash, "nil;")
+			return nil
+		}
+		if v.Kind() == reflect.Ptr {
+			if h.visited[v.Pointer()] {
+				fmt.Fprint(h.hash, "visited;")
+				return nil
+			}
+			h.visited[v.Pointer()] = true
+		}
+		fmt.Fprintf(h.hash, "%v:", v.Elem().Type())
+		return h.write(v.Elem())
+
+	case reflect.Struct:
+		fmt.Fprint(h.hash, "{")
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			fmt.Fprintf(h.hash, "%s=", field.Name)
+			if err := h.write(v.Field(i)); err != nil {
+				return fmt.Errorf("%s: %v", field.Name, err)
+			}
+		}
+		fmt.Fprint(h.hash, "}")
+		return nil
+
+	case reflect.Slice, reflect.Array:
+		fmt.Fprintf(h.hash, "[%d:", v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if err := h.write(v.Index(i)); err != nil {
+				return err
+			}
+		}
+		fmt.Fprint(h.hash, "]")
+		return nil
+
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		fmt.Fprintf(h.hash, "map[%d:", len(keys))
+		for _, k := range keys {
+			fmt.Fprintf(h.hash, "%v=", k.Interface())
+			if err := h.write(v.MapIndex(k)); err != nil {
+				return err
+			}
+		}
+		fmt.Fprint(h.hash, "]")
+		return nil
+
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return nil
+
+	default:
+		fmt.Fprintf(h.hash, "%#v;", v.Interface())
+		return nil
+	}
+}
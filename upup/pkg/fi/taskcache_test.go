@@ -0,0 +1,156 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// testCachedTask sets its ID when it runs, as tasks do when they find or create their cloud resource
+type testCachedTask struct {
+	Name      *string
+	ID        *string
+	Lifecycle Lifecycle
+	Parent    *testCachedTask
+	Data      Resource
+
+	runs int
+}
+
+var _ Task = &testCachedTask{}
+
+func (t *testCachedTask) Run(_ *Context) error {
+	t.runs++
+	t.ID = String("id-" + StringValue(t.Name))
+	return nil
+}
+
+func (t *testCachedTask) GetLifecycle() Lifecycle {
+	return t.Lifecycle
+}
+
+func (t *testCachedTask) SetLifecycle(lifecycle Lifecycle) {
+	t.Lifecycle = lifecycle
+}
+
+func buildCachedTasks(data string) (*testCachedTask, *testCachedTask) {
+	parent := &testCachedTask{Name: String("parent"), Lifecycle: LifecycleSync}
+	child := &testCachedTask{Name: String("child"), Lifecycle: LifecycleSync, Parent: parent, Data: NewStringResource(data)}
+	return parent, child
+}
+
+func TestRunTasksCache(t *testing.T) {
+	path := vfs.NewMemFSPath(vfs.NewMemFSContext(), "taskcache.json")
+
+	run := func(data string, cached int) (*testCachedTask, *testCachedTask) {
+		t.Helper()
+
+		var options RunTasksOptions
+		options.InitDefaults()
+		options.TaskAPI = func(task Task) string { return "ec2" }
+		options.Cache = LoadTaskCache(path, DefaultTaskCacheMaxAge)
+
+		parent, child := buildCachedTasks(data)
+		report := runTestTasks(t, map[string]Task{"parent": parent, "child": child}, options)
+		if report.Cached != cached {
+			t.Errorf("expected %d cached tasks, got %d", cached, report.Cached)
+		}
+		if err := options.Cache.Save(); err != nil {
+			t.Fatalf("error saving cache: %v", err)
+		}
+		if StringValue(parent.ID) != "id-parent" || StringValue(child.ID) != "id-child" {
+			t.Errorf("expected IDs to be set, got %q and %q", StringValue(parent.ID), StringValue(child.ID))
+		}
+		return parent, child
+	}
+
+	parent, child := run("a", 0)
+	if parent.runs != 1 || child.runs != 1 {
+		t.Errorf("expected tasks to run on the first update")
+	}
+
+	parent, child = run("a", 2)
+	if parent.runs != 0 || child.runs != 0 {
+		t.Errorf("expected unchanged tasks to be skipped")
+	}
+
+	parent, child = run("b", 1)
+	if parent.runs != 0 || child.runs != 1 {
+		t.Errorf("expected only the changed task to run, got parent=%d child=%d", parent.runs, child.runs)
+	}
+}
+
+func TestRunTasksCacheIgnoresOtherLifecycles(t *testing.T) {
+	var options RunTasksOptions
+	options.InitDefaults()
+	options.TaskAPI = func(task Task) string { return "ec2" }
+	options.Cache = LoadTaskCache(vfs.NewMemFSPath(vfs.NewMemFSContext(), "taskcache.json"), DefaultTaskCacheMaxAge)
+
+	for i := 0; i < 2; i++ {
+		task := &testCachedTask{Name: String("task"), Lifecycle: LifecycleExistsAndValidates}
+		report := runTestTasks(t, map[string]Task{"task": task}, options)
+		if report.Cached != 0 || task.runs != 1 {
+			t.Errorf("expected task with lifecycle %q to run, cached=%d runs=%d", task.Lifecycle, report.Cached, task.runs)
+		}
+	}
+}
+
+func TestTaskCachePreview(t *testing.T) {
+	path := vfs.NewMemFSPath(vfs.NewMemFSContext(), "taskcache.json")
+
+	var options RunTasksOptions
+	options.InitDefaults()
+	options.TaskAPI = func(task Task) string { return "ec2" }
+
+	// A preview does not apply its tasks, so they are not recorded
+	options.Cache = LoadTaskCache(path, DefaultTaskCacheMaxAge).ReadOnly()
+	parent, child := buildCachedTasks("a")
+	runTestTasks(t, map[string]Task{"parent": parent, "child": child}, options)
+	if len(options.Cache.entries) != 0 {
+		t.Errorf("expected a read-only cache not to record tasks, got %d entries", len(options.Cache.entries))
+	}
+
+	if err := options.Cache.Save(); err != nil {
+		t.Fatalf("error saving cache: %v", err)
+	}
+	if _, err := path.ReadFile(); !os.IsNotExist(err) {
+		t.Errorf("expected a read-only cache not to be written, got %v", err)
+	}
+}
+
+func TestTaskCacheExpiry(t *testing.T) {
+	path := vfs.NewMemFSPath(vfs.NewMemFSContext(), "taskcache.json")
+	cache := LoadTaskCache(path, time.Hour)
+
+	task := &testCachedTask{Name: String("task")}
+	hash, err := hashTask("task", task)
+	if err != nil {
+		t.Fatalf("error hashing task: %v", err)
+	}
+	cache.entries["task"] = &taskCacheEntry{Hash: hash, Timestamp: time.Now().Add(-2 * time.Hour)}
+	if cache.restore("task", hash, task) {
+		t.Errorf("expected expired entry not to be used")
+	}
+	cache.entries["task"].Timestamp = time.Now()
+	if !cache.restore("task", hash, task) {
+		t.Errorf("expected entry to be used")
+	}
+}
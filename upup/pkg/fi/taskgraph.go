@@ -0,0 +1,207 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// TaskGraph is a machine-readable description of the tasks kops has built, and the order they depend on each other.
+type TaskGraph struct {
+	Tasks []*TaskGraphNode `json:"tasks"`
+}
+
+// TaskGraphNode describes a single task in the TaskGraph
+type TaskGraphNode struct {
+	// Key is the unique key of the task, in the form <type>/<name>
+	Key string `json:"key"`
+	// Type is the type of the task, e.g. VPC
+	Type string `json:"type"`
+	// Name is the name of the task
+	Name string `json:"name"`
+	// Lifecycle is the lifecycle of the task, if it has one
+	Lifecycle Lifecycle `json:"lifecycle,omitempty"`
+	// Dependencies are the keys of the tasks that must run before this task
+	Dependencies []string `json:"dependencies,omitempty"`
+	// Spec holds the non-empty exported fields of the task.
+	// References to other tasks are replaced by their keys, and resources are not rendered.
+	Spec map[string]interface{} `json:"spec,omitempty"`
+}
+
+// BuildTaskGraph builds the TaskGraph for the map of tasks, sorted by key
+func BuildTaskGraph(tasks map[string]Task) (*TaskGraph, error) {
+	taskKeys := make(map[Task]string)
+	for k, t := range tasks {
+		taskKeys[t] = k
+	}
+
+	dependencies := FindTaskDependencies(tasks)
+
+	graph := &TaskGraph{}
+	for key, task := range tasks {
+		node := &TaskGraphNode{
+			Key:  key,
+			Type: getTaskName(task),
+			Name: key,
+		}
+		if i := strings.Index(key, "/"); i != -1 {
+			node.Name = key[i+1:]
+		}
+		if hl, ok := task.(HasLifecycle); ok {
+			node.Lifecycle = hl.GetLifecycle()
+		}
+
+		deps := append([]string(nil), dependencies[key]...)
+		sort.Strings(deps)
+		node.Dependencies = deps
+
+		v := reflect.ValueOf(task)
+		if v.Kind() == reflect.Ptr {
+			v = v.Elem()
+		}
+		if v.Kind() == reflect.Struct {
+			spec, err := taskGraphStruct(taskKeys, v, true)
+			if err != nil {
+				return nil, fmt.Errorf("error describing task %q: %v", key, err)
+			}
+			node.Spec = spec
+		}
+
+		graph.Tasks = append(graph.Tasks, node)
+	}
+
+	sort.Slice(graph.Tasks, func(i, j int) bool {
+		return graph.Tasks[i].Key < graph.Tasks[j].Key
+	})
+
+	return graph, nil
+}
+
+func taskGraphStruct(taskKeys map[Task]string, v reflect.Value, isTask bool) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.PkgPath != "" {
+			// Not exported
+			continue
+		}
+		if isTask && field.Type == reflect.TypeOf(Lifecycle("")) {
+			// Reported on the node itself
+			continue
+		}
+
+		value, err := taskGraphValue(taskKeys, v.Field(i))
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", field.Name, err)
+		}
+		if value != nil {
+			fields[field.Name] = value
+		}
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// taskGraphValue converts v to a value that can be serialized; it returns nil for empty values
+func taskGraphValue(taskKeys map[Task]string, v reflect.Value) (interface{}, error) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+	}
+
+	if v.CanInterface() {
+		intf := v.Interface()
+		if t, ok := intf.(Task); ok {
+			if key, found := taskKeys[t]; found {
+				return key, nil
+			}
+		}
+		if _, ok := intf.(Resource); ok {
+			// Resources may depend on tasks that have not yet run, and can contain secrets
+			return "<resource>", nil
+		}
+		switch intf.(type) {
+		case json.Marshaler, encoding.TextMarshaler:
+			return intf, nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return taskGraphValue(taskKeys, v.Elem())
+
+	case reflect.Struct:
+		fields, err := taskGraphStruct(taskKeys, v, false)
+		if err != nil || fields == nil {
+			return nil, err
+		}
+		return fields, nil
+
+	case reflect.Slice, reflect.Array:
+		if v.Len() == 0 {
+			return nil, nil
+		}
+		var values []interface{}
+		for i := 0; i < v.Len(); i++ {
+			value, err := taskGraphValue(taskKeys, v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+
+	case reflect.Map:
+		if v.Len() == 0 {
+			return nil, nil
+		}
+		values := make(map[string]interface{})
+		iter := v.MapRange()
+		for iter.Next() {
+			value, err := taskGraphValue(taskKeys, iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			values[fmt.Sprintf("%v", iter.Key().Interface())] = value
+		}
+		return values, nil
+
+	case reflect.String:
+		if v.Len() == 0 {
+			return nil, nil
+		}
+		return v.String(), nil
+
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return nil, nil
+
+	default:
+		if !v.CanInterface() {
+			return nil, nil
+		}
+		return v.Interface(), nil
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testGraphTask struct {
+	Name      *string
+	Lifecycle Lifecycle
+	Parent    *testGraphTask
+	UserData  Resource
+	Tags      map[string]string
+	Ports     []int
+	Enabled   *bool
+}
+
+var _ Task = &testGraphTask{}
+
+func (*testGraphTask) Run(_ *Context) error {
+	panic("not implemented")
+}
+
+func (t *testGraphTask) GetLifecycle() Lifecycle {
+	return t.Lifecycle
+}
+
+func (t *testGraphTask) SetLifecycle(lifecycle Lifecycle) {
+	t.Lifecycle = lifecycle
+}
+
+func Test_BuildTaskGraph(t *testing.T) {
+	parent := &testGraphTask{
+		Name:      String("parent"),
+		Lifecycle: LifecycleExistsAndWarnIfChanges,
+	}
+	child := &testGraphTask{
+		Name:      String("child"),
+		Lifecycle: LifecycleSync,
+		Parent:    parent,
+		UserData:  NewStringResource("secret"),
+		Tags:      map[string]string{"owner": "kops"},
+		Ports:     []int{443},
+		Enabled:   Bool(false),
+	}
+	tasks := map[string]Task{
+		"testGraphTask/parent": parent,
+		"testGraphTask/child":  child,
+	}
+
+	graph, err := BuildTaskGraph(tasks)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := &TaskGraph{
+		Tasks: []*TaskGraphNode{
+			{
+				Key:          "testGraphTask/child",
+				Type:         "testGraphTask",
+				Name:         "child",
+				Lifecycle:    LifecycleSync,
+				Dependencies: []string{"testGraphTask/parent"},
+				Spec: map[string]interface{}{
+					"Name":     "child",
+					"Parent":   "testGraphTask/parent",
+					"UserData": "<resource>",
+					"Tags":     map[string]interface{}{"owner": "kops"},
+					"Ports":    []interface{}{443},
+					"Enabled":  false,
+				},
+			},
+			{
+				Key:       "testGraphTask/parent",
+				Type:      "testGraphTask",
+				Name:      "parent",
+				Lifecycle: LifecycleExistsAndWarnIfChanges,
+				Spec: map[string]interface{}{
+					"Name": "parent",
+				},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(graph, expected) {
+		for i := range graph.Tasks {
+			t.Logf("actual: %+v", graph.Tasks[i])
+		}
+		t.Errorf("unexpected task graph")
+	}
+}
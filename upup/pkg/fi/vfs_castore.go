@@ -439,6 +439,9 @@ func (c *VFSCAStore) MirrorTo(basedir vfs.Path) error {
 	}
 	klog.V(2).Infof("Mirroring key store from %q to %q", c.basedir, basedir)
 
+	// Encrypt the mirror like the key store
+	basedir = vfs.WrapLike(c.basedir, basedir)
+
 	keysets, err := c.ListKeysets()
 	if err != nil {
 		return err
@@ -644,6 +647,10 @@ func (c *VFSCAStore) FindPrivateKeyset(name string) (*kops.Keyset, error) {
 		return nil, err
 	}
 
+	if keys == nil {
+		return nil, nil
+	}
+
 	o, err := keys.ToAPIObject(name, true)
 	if err != nil {
 		return nil, err
@@ -51,6 +51,8 @@ var (
 	DistributionCentos8      = Distribution{packageFormat: "rpm", project: "centos", id: "centos8", version: 8}
 	DistributionFlatcar      = Distribution{packageFormat: "", project: "flatcar", id: "flatcar", version: 0}
 	DistributionContainerOS  = Distribution{packageFormat: "", project: "containeros", id: "containeros", version: 0}
+	DistributionBottlerocket = Distribution{packageFormat: "", project: "bottlerocket", id: "bottlerocket", version: 0}
+	DistributionWindows      = Distribution{packageFormat: "", project: "windows", id: "windows", version: 0}
 )
 
 // IsDebianFamily returns true if this distribution uses deb packages and generally follows debian package names
@@ -70,7 +72,17 @@ func (d *Distribution) IsRHELFamily() bool {
 
 // IsSystemd returns true if this distribution uses systemd
 func (d *Distribution) IsSystemd() bool {
-	return true
+	return !d.IsWindows()
+}
+
+// IsWindows returns true if this distribution is Windows Server
+func (d *Distribution) IsWindows() bool {
+	return d.project == "windows"
+}
+
+// UsesSettingsAPI returns true if the services of this distribution are configured through an API rather than systemd units
+func (d *Distribution) UsesSettingsAPI() bool {
+	return d.project == "bottlerocket"
 }
 
 // DefaultUsers returns the name of the system users for this distribution
@@ -82,10 +94,12 @@ func (d *Distribution) DefaultUsers() ([]string, error) {
 		return []string{"ubuntu"}, nil
 	case "centos":
 		return []string{"centos"}, nil
-	case "rhel", "amazonlinux2":
+	case "rhel", "amazonlinux2", "bottlerocket":
 		return []string{"ec2-user"}, nil
 	case "flatcar":
 		return []string{"core"}, nil
+	case "windows":
+		return []string{"Administrator"}, nil
 	default:
 		return nil, fmt.Errorf("unknown distro %v", d)
 	}
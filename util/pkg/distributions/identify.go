@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path"
+	"runtime"
 	"strings"
 
 	"k8s.io/klog/v2"
@@ -27,6 +28,11 @@ import (
 
 // FindDistribution identifies the distribution on which we are running
 func FindDistribution(rootfs string) (Distribution, error) {
+	// Windows has no /etc/os-release file
+	if runtime.GOOS == "windows" {
+		return DistributionWindows, nil
+	}
+
 	// All supported distros have an /etc/os-release file
 	osReleaseBytes, err := ioutil.ReadFile(path.Join(rootfs, "etc/os-release"))
 	osRelease := make(map[string]string)
@@ -71,6 +77,9 @@ func FindDistribution(rootfs string) (Distribution, error) {
 	}
 
 	// Some distros have a more verbose VERSION_ID
+	if strings.HasPrefix(distro, "bottlerocket-") {
+		return DistributionBottlerocket, nil
+	}
 	if strings.HasPrefix(distro, "cos-") {
 		return DistributionContainerOS, nil
 	}
@@ -34,6 +34,11 @@ func TestFindDistribution(t *testing.T) {
 			err:      nil,
 			expected: DistributionAmazonLinux2,
 		},
+		{
+			rootfs:   "bottlerocket",
+			err:      nil,
+			expected: DistributionBottlerocket,
+		},
 		{
 			rootfs:   "centos7",
 			err:      nil,
@@ -18,6 +18,7 @@ package proxy
 
 import (
 	"strconv"
+	"strings"
 
 	"k8s.io/kops/pkg/apis/kops"
 
@@ -25,20 +26,33 @@ import (
 	"k8s.io/klog/v2"
 )
 
+// URL returns the URL of the proxy, or "" if no proxy host is set
+func URL(proxies *kops.EgressProxySpec) string {
+	if proxies == nil || proxies.HTTPProxy.Host == "" {
+		return ""
+	}
+
+	url := proxies.HTTPProxy.Host
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		url = "http://" + url
+	}
+	if proxies.HTTPProxy.Port != 0 {
+		url += ":" + strconv.Itoa(proxies.HTTPProxy.Port)
+	}
+	return url
+}
+
+// GetProxyEnvVars returns the environment variables configuring the proxy. Both the lower and upper case
+// variables are set, as programs differ in the ones they read.
 func GetProxyEnvVars(proxies *kops.EgressProxySpec) []v1.EnvVar {
 	if proxies == nil {
 		return []v1.EnvVar{}
 	}
 
-	if proxies.HTTPProxy.Host == "" {
+	httpProxyURL := URL(proxies)
+	if httpProxyURL == "" {
 		klog.Warning("EgressProxy set but no proxy host provided")
-	}
-
-	var httpProxyURL string
-	if proxies.HTTPProxy.Port == 0 {
-		httpProxyURL = "http://" + proxies.HTTPProxy.Host
-	} else {
-		httpProxyURL = "http://" + proxies.HTTPProxy.Host + ":" + strconv.Itoa(proxies.HTTPProxy.Port)
+		return []v1.EnvVar{}
 	}
 
 	noProxy := proxies.ProxyExcludes
@@ -46,6 +60,8 @@ func GetProxyEnvVars(proxies *kops.EgressProxySpec) []v1.EnvVar {
 	return []v1.EnvVar{
 		{Name: "http_proxy", Value: httpProxyURL},
 		{Name: "https_proxy", Value: httpProxyURL},
+		{Name: "HTTP_PROXY", Value: httpProxyURL},
+		{Name: "HTTPS_PROXY", Value: httpProxyURL},
 		{Name: "NO_PROXY", Value: noProxy},
 		{Name: "no_proxy", Value: noProxy},
 	}
@@ -40,14 +40,7 @@ func TestGetProxyEnvVars(t *testing.T) {
 					Port: 1234,
 				},
 			},
-			// TODO: The output seems a little bit weird for this case
-			// Should we return empty array instead of just warning?
-			expected: []v1.EnvVar{
-				{Name: "http_proxy", Value: "http://:1234"},
-				{Name: "https_proxy", Value: "http://:1234"},
-				{Name: "NO_PROXY", Value: ""},
-				{Name: "no_proxy", Value: ""},
-			},
+			expected: []v1.EnvVar{},
 		},
 		{
 			inProxies: &kops.EgressProxySpec{
@@ -59,6 +52,8 @@ func TestGetProxyEnvVars(t *testing.T) {
 			expected: []v1.EnvVar{
 				{Name: "http_proxy", Value: "http://a.b.c.d:1234"},
 				{Name: "https_proxy", Value: "http://a.b.c.d:1234"},
+				{Name: "HTTP_PROXY", Value: "http://a.b.c.d:1234"},
+				{Name: "HTTPS_PROXY", Value: "http://a.b.c.d:1234"},
 				{Name: "NO_PROXY", Value: ""},
 				{Name: "no_proxy", Value: ""},
 			},
@@ -74,10 +69,28 @@ func TestGetProxyEnvVars(t *testing.T) {
 			expected: []v1.EnvVar{
 				{Name: "http_proxy", Value: "http://a.b.c.d"},
 				{Name: "https_proxy", Value: "http://a.b.c.d"},
+				{Name: "HTTP_PROXY", Value: "http://a.b.c.d"},
+				{Name: "HTTPS_PROXY", Value: "http://a.b.c.d"},
 				{Name: "NO_PROXY", Value: "1.1.1.1,2.2.2.2"},
 				{Name: "no_proxy", Value: "1.1.1.1,2.2.2.2"},
 			},
 		},
+		{
+			inProxies: &kops.EgressProxySpec{
+				HTTPProxy: kops.HTTPProxy{
+					Host: "https://proxy.example.com",
+					Port: 3128,
+				},
+			},
+			expected: []v1.EnvVar{
+				{Name: "http_proxy", Value: "https://proxy.example.com:3128"},
+				{Name: "https_proxy", Value: "https://proxy.example.com:3128"},
+				{Name: "HTTP_PROXY", Value: "https://proxy.example.com:3128"},
+				{Name: "HTTPS_PROXY", Value: "https://proxy.example.com:3128"},
+				{Name: "NO_PROXY", Value: ""},
+				{Name: "no_proxy", Value: ""},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -112,6 +112,63 @@ func (p *FSPath) CreateFile(data io.ReadSeeker, acl ACL) error {
 	return p.WriteFile(data, acl)
 }
 
+// Lock implements Lockable::Lock, by exclusively creating the lock file
+func (p *FSPath) Lock(info *LockInfo) (Unlocker, error) {
+	data, err := info.marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	dir := path.Dir(p.location)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("error creating directories %q: %v", dir, err)
+	}
+
+	f, err := os.OpenFile(p.location, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			holder, _ := ioutil.ReadFile(p.location)
+			return nil, &LockHeldError{Lock: p.location, Holder: unmarshalLockInfo(holder)}
+		}
+		return nil, fmt.Errorf("error taking lock %s: %v", p.location, err)
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(p.location)
+		return nil, fmt.Errorf("error writing lock %s: %v", p.location, err)
+	}
+
+	return func() error {
+		if err := os.Remove(p.location); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error releasing lock %s: %v", p.location, err)
+		}
+		return nil
+	}, nil
+}
+
+// ForceUnlock implements Lockable::ForceUnlock
+func (p *FSPath) ForceUnlock(id string) error {
+	data, err := ioutil.ReadFile(p.location)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return os.ErrNotExist
+		}
+		return fmt.Errorf("error reading lock %s: %v", p.location, err)
+	}
+	if err := checkLockID(p.location, data, id); err != nil {
+		return err
+	}
+	if err := os.Remove(p.location); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error releasing lock %s: %v", p.location, err)
+	}
+	return nil
+}
+
+var _ Lockable = &FSPath{}
+
 // ReadFile implements Path::ReadFile
 func (p *FSPath) ReadFile() ([]byte, error) {
 	file, err := ioutil.ReadFile(p.location)
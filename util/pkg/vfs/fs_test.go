@@ -114,3 +114,42 @@ func TestWriteTo(t *testing.T) {
 		}
 	}
 }
+
+func TestFSLock(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "test")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	lockPath := NewFSPath(path.Join(tempDir, "cluster", "lock"))
+
+	info, err := NewLockInfo("update cluster")
+	if err != nil {
+		t.Fatalf("error building lock info: %v", err)
+	}
+	unlock, err := lockPath.Lock(info)
+	if err != nil {
+		t.Fatalf("error taking lock: %v", err)
+	}
+
+	other, err := NewLockInfo("edit cluster")
+	if err != nil {
+		t.Fatalf("error building lock info: %v", err)
+	}
+	_, err = lockPath.Lock(other)
+	lockHeld, ok := err.(*LockHeldError)
+	if !ok {
+		t.Fatalf("expected LockHeldError, got: %v", err)
+	}
+	if lockHeld.Holder.ID != info.ID {
+		t.Errorf("expected lock to be held by %q, got %q", info.ID, lockHeld.Holder.ID)
+	}
+
+	if err := unlock(); err != nil {
+		t.Fatalf("error releasing lock: %v", err)
+	}
+	if _, err := os.Stat(lockPath.Path()); !os.IsNotExist(err) {
+		t.Errorf("expected lock file to be removed, got: %v", err)
+	}
+}
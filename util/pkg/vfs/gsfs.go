@@ -22,11 +22,12 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"os"
 	"path"
+	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"google.golang.org/api/googleapi"
@@ -148,15 +149,26 @@ func (p *GSPath) Join(relativePath ...string) Path {
 }
 
 func (p *GSPath) WriteFile(data io.ReadSeeker, acl ACL) error {
+	_, err := p.writeFile(data, acl, nil)
+	return err
+}
+
+// writeFile writes the file, only if it is at generation ifGenerationMatch if that is set (0 meaning it must not exist),
+// and returns the written object
+func (p *GSPath) writeFile(data io.ReadSeeker, acl ACL, ifGenerationMatch *int64) (*storage.Object, error) {
 	md5Hash, err := hashing.HashAlgorithmMD5.Hash(data)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	md5Base64 := base64.StdEncoding.EncodeToString(md5Hash.HashValue)
+
+	var written *storage.Object
+	attempted := false
 	done, err := RetryWithBackoff(gcsWriteBackoff, func() (bool, error) {
 		obj := &storage.Object{
 			Name:    p.key,
-			Md5Hash: base64.StdEncoding.EncodeToString(md5Hash.HashValue),
+			Md5Hash: md5Base64,
 		}
 
 		if acl != nil {
@@ -174,46 +186,156 @@ func (p *GSPath) WriteFile(data io.ReadSeeker, acl ACL) error {
 			return false, fmt.Errorf("error seeking to start of data stream for write to %s: %v", p, err)
 		}
 
-		_, err = p.client.Objects.Insert(p.bucket, obj).Media(data).Do()
+		call := p.client.Objects.Insert(p.bucket, obj).Media(data)
+		if ifGenerationMatch != nil {
+			call = call.IfGenerationMatch(*ifGenerationMatch)
+		}
+		written, err = call.Do()
 		if err != nil {
+			if isGCSPreconditionFailed(err) {
+				if attempted {
+					// An earlier attempt may have written the object, with only its response lost
+					current, getErr := p.client.Objects.Get(p.bucket, p.key).Do()
+					if getErr == nil && current.Md5Hash == md5Base64 {
+						written = current
+						return true, nil
+					}
+				}
+				// Not recoverable
+				return true, ErrVersionConflict
+			}
+			attempted = true
 			return false, fmt.Errorf("error writing %s: %v", p, err)
 		}
 
 		return true, nil
 	})
 	if err != nil {
-		return err
+		return nil, err
 	} else if done {
-		return nil
+		return written, nil
 	} else {
 		// Shouldn't happen - we always return a non-nil error with false
-		return wait.ErrWaitTimeout
+		return nil, wait.ErrWaitTimeout
 	}
 }
 
-// To prevent concurrent creates on the same file while maintaining atomicity of writes,
-// we take a process-wide lock during the operation.
-// Not a great approach, but fine for a single process (with low concurrency)
-// TODO: should we enable versioning?
-var createFileLockGCS sync.Mutex
-
+// CreateFile writes the file contents, with a precondition that the object does not exist yet
 func (p *GSPath) CreateFile(data io.ReadSeeker, acl ACL) error {
-	createFileLockGCS.Lock()
-	defer createFileLockGCS.Unlock()
-
-	// Check if exists
-	_, err := p.ReadFile()
-	if err == nil {
+	_, err := p.WriteFileIfVersion(data, acl, "")
+	if err == ErrVersionConflict {
 		return os.ErrExist
 	}
+	return err
+}
+
+// ReadFileVersion implements VersionedPath::ReadFileVersion; the version is the generation of the object
+func (p *GSPath) ReadFileVersion() ([]byte, string, error) {
+	klog.V(4).Infof("Reading file %q", p)
+
+	obj, err := p.client.Objects.Get(p.bucket, p.key).Do()
+	if err != nil {
+		if isGCSNotFound(err) {
+			return nil, "", os.ErrNotExist
+		}
+		return nil, "", fmt.Errorf("error reading %s: %v", p, err)
+	}
+
+	response, err := p.client.Objects.Get(p.bucket, p.key).Generation(obj.Generation).Download()
+	if err != nil {
+		if isGCSNotFound(err) {
+			// The object was replaced since we read the generation
+			return nil, "", ErrVersionConflict
+		}
+		return nil, "", fmt.Errorf("error reading %s: %v", p, err)
+	}
+	defer response.Body.Close()
+
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("error reading %s: %v", p, err)
+	}
+	return data, strconv.FormatInt(obj.Generation, 10), nil
+}
+
+// WriteFileIfVersion implements VersionedPath::WriteFileIfVersion, using a precondition on the generation of the object
+func (p *GSPath) WriteFileIfVersion(data io.ReadSeeker, acl ACL, version string) (string, error) {
+	generation := int64(0)
+	if version != "" {
+		var err error
+		generation, err = strconv.ParseInt(version, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid version %q for %s: %v", version, p, err)
+		}
+	}
+
+	obj, err := p.writeFile(data, acl, &generation)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(obj.Generation, 10), nil
+}
+
+var _ VersionedPath = &GSPath{}
+
+// Lock implements Lockable::Lock, by creating the object with a precondition that it does not exist
+func (p *GSPath) Lock(info *LockInfo) (Unlocker, error) {
+	data, err := info.marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := p.WriteFileIfVersion(bytes.NewReader(data), nil, "")
+	if err != nil {
+		if err == ErrVersionConflict {
+			holder, readErr := p.ReadFile()
+			if readErr != nil && !os.IsNotExist(readErr) {
+				return nil, fmt.Errorf("error reading lock %s: %v", p, readErr)
+			}
+			return nil, &LockHeldError{Lock: p.Path(), Holder: unmarshalLockInfo(holder)}
+		}
+		return nil, fmt.Errorf("error taking lock %s: %v", p, err)
+	}
 
-	if !os.IsNotExist(err) {
+	generation, _ := strconv.ParseInt(version, 10, 64)
+	return func() error {
+		err := p.client.Objects.Delete(p.bucket, p.key).IfGenerationMatch(generation).Do()
+		if err != nil && !isGCSNotFound(err) {
+			if isGCSPreconditionFailed(err) {
+				return fmt.Errorf("lock %s was taken over by another holder", p)
+			}
+			return fmt.Errorf("error releasing lock %s: %v", p, err)
+		}
+		return nil
+	}, nil
+}
+
+// ForceUnlock implements Lockable::ForceUnlock, deleting the object with a precondition on the generation we checked
+func (p *GSPath) ForceUnlock(id string) error {
+	data, version, err := p.ReadFileVersion()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return os.ErrNotExist
+		}
+		return fmt.Errorf("error reading lock %s: %v", p, err)
+	}
+	if err := checkLockID(p.Path(), data, id); err != nil {
 		return err
 	}
 
-	return p.WriteFile(data, acl)
+	generation, _ := strconv.ParseInt(version, 10, 64)
+	err = p.client.Objects.Delete(p.bucket, p.key).IfGenerationMatch(generation).Do()
+	if err != nil && !isGCSNotFound(err) {
+		if isGCSPreconditionFailed(err) {
+			return fmt.Errorf("lock %s was taken over by another holder", p)
+		}
+		return fmt.Errorf("error releasing lock %s: %v", p, err)
+	}
+	return nil
 }
 
+var _ Lockable = &GSPath{}
+
 // ReadFile implements Path::ReadFile
 func (p *GSPath) ReadFile() ([]byte, error) {
 	var b bytes.Buffer
@@ -379,3 +501,11 @@ func isGCSNotFound(err error) bool {
 	ae, ok := err.(*googleapi.Error)
 	return ok && ae.Code == http.StatusNotFound
 }
+
+func isGCSPreconditionFailed(err error) bool {
+	if err == nil {
+		return false
+	}
+	ae, ok := err.(*googleapi.Error)
+	return ok && ae.Code == http.StatusPreconditionFailed
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfs
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/option"
+	storage "google.golang.org/api/storage/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// fakeGCSServer serves object inserts with generation preconditions, failing the responses of the first loseResponses inserts
+// after the object was written
+type fakeGCSServer struct {
+	objects       map[string]*storage.Object
+	loseResponses int
+}
+
+var md5HashRegexp = regexp.MustCompile(`"md5Hash":"([^"]*)"`)
+
+func (s *fakeGCSServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/b/bucket/o"):
+		body, _ := ioutil.ReadAll(r.Body)
+		match := md5HashRegexp.FindSubmatch(body)
+		if match == nil {
+			http.Error(w, "missing md5Hash", http.StatusBadRequest)
+			return
+		}
+		name := "lock"
+		if existing := s.objects[name]; existing != nil && r.URL.Query().Get("ifGenerationMatch") == "0" {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			w.Write([]byte(`{"error":{"code":412,"message":"Precondition Failed"}}`))
+			return
+		}
+		obj := &storage.Object{Bucket: "bucket", Name: name, Md5Hash: string(match[1]), Generation: int64(len(s.objects) + 1)}
+		s.objects[name] = obj
+		if s.loseResponses > 0 {
+			s.loseResponses--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error":{"code":503,"message":"Service Unavailable"}}`))
+			return
+		}
+		json.NewEncoder(w).Encode(obj)
+	case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/b/bucket/o/lock"):
+		obj := s.objects["lock"]
+		if obj == nil {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":404,"message":"Not Found"}}`))
+			return
+		}
+		json.NewEncoder(w).Encode(obj)
+	default:
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+	}
+}
+
+func TestGSPathCreateFileLostResponse(t *testing.T) {
+	original := gcsWriteBackoff
+	gcsWriteBackoff = wait.Backoff{Duration: time.Millisecond, Steps: 5}
+	defer func() { gcsWriteBackoff = original }()
+
+	grid := []struct {
+		Name     string
+		Existing string
+		Expected error
+	}{
+		{
+			Name: "response of the write is lost",
+		},
+		{
+			Name:     "object is written by someone else",
+			Existing: "other",
+			Expected: os.ErrExist,
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			fake := &fakeGCSServer{objects: map[string]*storage.Object{}, loseResponses: 1}
+			if g.Existing != "" {
+				fake.objects["lock"] = &storage.Object{Bucket: "bucket", Name: "lock", Md5Hash: g.Existing, Generation: 1}
+			}
+			server := httptest.NewServer(fake)
+			defer server.Close()
+
+			client, err := storage.NewService(context.Background(), option.WithEndpoint(server.URL+"/storage/v1/"), option.WithHTTPClient(server.Client()))
+			if err != nil {
+				t.Fatalf("error building client: %v", err)
+			}
+
+			err = NewGSPath(client, "bucket", "lock").CreateFile(strings.NewReader("data"), nil)
+			if err != g.Expected {
+				t.Errorf("expected error %v, got %v", g.Expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"time"
+)
+
+// ErrLockingNotSupported is returned when a path cannot be used as a lock
+var ErrLockingNotSupported = errors.New("locking is not supported")
+
+// ErrVersionConflict is returned when a conditional write fails, because the file was written since it was read
+var ErrVersionConflict = errors.New("file was modified since it was read")
+
+// LockInfo describes the holder of a lock
+type LockInfo struct {
+	// ID uniquely identifies the lock
+	ID string `json:"id"`
+	// Holder is the user and host holding the lock
+	Holder string `json:"holder"`
+	// Operation is the operation the lock is held for
+	Operation string `json:"operation"`
+	// Created is when the lock was taken
+	Created time.Time `json:"created"`
+}
+
+// NewLockInfo builds the LockInfo for taking a lock for the operation from this process
+func NewLockInfo(operation string) (*LockInfo, error) {
+	b := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return nil, fmt.Errorf("error generating lock id: %v", err)
+	}
+
+	holder := "unknown"
+	if u, err := user.Current(); err == nil {
+		holder = u.Username
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		holder += "@" + hostname
+	}
+
+	return &LockInfo{
+		ID:        hex.EncodeToString(b),
+		Holder:    holder,
+		Operation: operation,
+		Created:   time.Now().UTC(),
+	}, nil
+}
+
+func (i *LockInfo) marshal() ([]byte, error) {
+	data, err := json.Marshal(i)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling lock info: %v", err)
+	}
+	return data, nil
+}
+
+func unmarshalLockInfo(data []byte) *LockInfo {
+	info := &LockInfo{}
+	if err := json.Unmarshal(data, info); err != nil {
+		return &LockInfo{ID: "unknown", Holder: "unknown", Operation: "unknown"}
+	}
+	return info
+}
+
+// LockHeldError is returned when a lock is already held by someone else
+type LockHeldError struct {
+	// Lock is the path of the lock
+	Lock string
+	// Holder describes who holds the lock
+	Holder *LockInfo
+}
+
+func (e *LockHeldError) Error() string {
+	return fmt.Sprintf("lock %s is held by %s for %q since %s (lock id %s)", e.Lock, e.Holder.Holder, e.Holder.Operation, e.Holder.Created.Format(time.RFC3339), e.Holder.ID)
+}
+
+// Unlocker releases a lock
+type Unlocker func() error
+
+// Lockable is implemented by paths that can be used as an exclusive lock
+type Lockable interface {
+	// Lock takes the lock stored at the path, failing with a *LockHeldError if it is already held
+	Lock(info *LockInfo) (Unlocker, error)
+
+	// ForceUnlock releases the lock stored at the path on behalf of its holder, which is no longer running.
+	// The lock is only released if it is held with the lock id, so that a lock taken since is kept.
+	// If the lock is not held, err = os.ErrNotExist
+	ForceUnlock(id string) error
+}
+
+// Lock takes the lock stored at the path, returning ErrLockingNotSupported if the path cannot be used as a lock
+func Lock(p Path, info *LockInfo) (Unlocker, error) {
+	l, ok := p.(Lockable)
+	if !ok {
+		return nil, ErrLockingNotSupported
+	}
+	return l.Lock(info)
+}
+
+// ForceUnlock releases the lock stored at the path if it is held with the lock id,
+// returning ErrLockingNotSupported if the path cannot be used as a lock
+func ForceUnlock(p Path, id string) error {
+	l, ok := p.(Lockable)
+	if !ok {
+		return ErrLockingNotSupported
+	}
+	return l.ForceUnlock(id)
+}
+
+// checkLockID returns an error unless the lock, with the data of its LockInfo, is held with the lock id
+func checkLockID(lock string, data []byte, id string) error {
+	if holder := unmarshalLockInfo(data); holder.ID != id {
+		return fmt.Errorf("lock %s is held with lock id %s, not %s", lock, holder.ID, id)
+	}
+	return nil
+}
+
+// VersionedPath is implemented by paths that support optimistic concurrency,
+// by only writing a file if it was not written since it was read
+type VersionedPath interface {
+	// ReadFileVersion returns the contents of the file, with an opaque version that changes whenever the file is written.
+	// If the file did not exist, err = os.ErrNotExist
+	ReadFileVersion() ([]byte, string, error)
+
+	// WriteFileIfVersion writes the file, but only if it is still at the version, returning the new version.
+	// An empty version means the file must not exist.  Returns ErrVersionConflict if the file is at another version.
+	WriteFileIfVersion(data io.ReadSeeker, acl ACL, version string) (string, error)
+}
@@ -17,11 +17,13 @@ limitations under the License.
 package vfs
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 	"sync"
 )
@@ -33,6 +35,10 @@ type MemFSPath struct {
 	mutex    sync.Mutex
 	contents []byte
 	children map[string]*MemFSPath
+	// removed is set when the file is removed, so that it is no longer listed
+	removed bool
+	// version is incremented on every write, for optimistic concurrency
+	version int
 }
 
 var _ Path = &MemFSPath{}
@@ -105,6 +111,8 @@ func (p *MemFSPath) WriteFile(r io.ReadSeeker, acl ACL) error {
 		return fmt.Errorf("error reading data: %v", err)
 	}
 	p.contents = data
+	p.removed = false
+	p.version++
 	return nil
 }
 
@@ -117,6 +125,84 @@ func (p *MemFSPath) CreateFile(data io.ReadSeeker, acl ACL) error {
 	return p.WriteFile(data, acl)
 }
 
+// ReadFileVersion implements VersionedPath::ReadFileVersion
+func (p *MemFSPath) ReadFileVersion() ([]byte, string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.contents == nil {
+		return nil, "", os.ErrNotExist
+	}
+	return p.contents, strconv.Itoa(p.version), nil
+}
+
+// WriteFileIfVersion implements VersionedPath::WriteFileIfVersion
+func (p *MemFSPath) WriteFileIfVersion(data io.ReadSeeker, acl ACL, version string) (string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	current := ""
+	if p.contents != nil {
+		current = strconv.Itoa(p.version)
+	}
+	if current != version {
+		return "", ErrVersionConflict
+	}
+	if err := p.WriteFile(data, acl); err != nil {
+		return "", err
+	}
+	return strconv.Itoa(p.version), nil
+}
+
+var _ VersionedPath = &MemFSPath{}
+
+// Lock implements Lockable::Lock
+func (p *MemFSPath) Lock(info *LockInfo) (Unlocker, error) {
+	data, err := info.marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := p.WriteFileIfVersion(bytes.NewReader(data), nil, "")
+	if err != nil {
+		if err == ErrVersionConflict {
+			holder, _ := p.ReadFile()
+			return nil, &LockHeldError{Lock: p.Path(), Holder: unmarshalLockInfo(holder)}
+		}
+		return nil, err
+	}
+
+	return func() error {
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+
+		if p.contents != nil && strconv.Itoa(p.version) != version {
+			return fmt.Errorf("lock %s was taken over by another holder", p)
+		}
+		p.contents = nil
+		p.removed = true
+		return nil
+	}, nil
+}
+
+// ForceUnlock implements Lockable::ForceUnlock
+func (p *MemFSPath) ForceUnlock(id string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.contents == nil {
+		return os.ErrNotExist
+	}
+	if err := checkLockID(p.Path(), p.contents, id); err != nil {
+		return err
+	}
+	p.contents = nil
+	p.removed = true
+	return nil
+}
+
+var _ Lockable = &MemFSPath{}
+
 // ReadFile implements Path::ReadFile
 func (p *MemFSPath) ReadFile() ([]byte, error) {
 	if p.contents == nil {
@@ -141,11 +227,22 @@ func (p *MemFSPath) ReadDir() ([]Path, error) {
 
 	var paths []Path
 	for _, f := range p.children {
+		if f.isRemoved() {
+			continue
+		}
 		paths = append(paths, f)
 	}
 	return paths, nil
 }
 
+// isRemoved returns true if the file was removed, and has not been written since
+func (p *MemFSPath) isRemoved() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.removed
+}
+
 func (p *MemFSPath) ReadTree() ([]Path, error) {
 	var paths []Path
 	p.readTree(&paths)
@@ -157,7 +254,7 @@ func (p *MemFSPath) readTree(dest *[]Path) {
 	defer p.mutex.Unlock()
 
 	for _, f := range p.children {
-		if !f.HasChildren() {
+		if !f.HasChildren() && !f.isRemoved() {
 			*dest = append(*dest, f)
 		}
 		f.readTree(dest)
@@ -178,6 +275,7 @@ func (p *MemFSPath) String() string {
 
 func (p *MemFSPath) Remove() error {
 	p.contents = nil
+	p.removed = true
 	return nil
 }
 
@@ -163,3 +163,114 @@ func TestMemFsReadTree(t *testing.T) {
 		}
 	}
 }
+
+func TestMemFsWriteFileIfVersion(t *testing.T) {
+	memfspath := NewMemFSPath(NewMemFSContext(), "/root/config")
+
+	version, err := memfspath.WriteFileIfVersion(bytes.NewReader([]byte("v1")), nil, "")
+	if err != nil {
+		t.Fatalf("Failed creating file: %v", err)
+	}
+
+	if _, err := memfspath.WriteFileIfVersion(bytes.NewReader([]byte("v1")), nil, ""); err != ErrVersionConflict {
+		t.Errorf("Expected ErrVersionConflict creating an existing file, got: %v", err)
+	}
+
+	data, readVersion, err := memfspath.ReadFileVersion()
+	if err != nil {
+		t.Fatalf("Failed reading file: %v", err)
+	}
+	if string(data) != "v1" || readVersion != version {
+		t.Errorf("Expected v1 at version %q, got %q at version %q", version, data, readVersion)
+	}
+
+	// Someone else writes the file in between
+	if err := memfspath.WriteFile(bytes.NewReader([]byte("other")), nil); err != nil {
+		t.Fatalf("Failed writing file: %v", err)
+	}
+
+	if _, err := memfspath.WriteFileIfVersion(bytes.NewReader([]byte("v2")), nil, readVersion); err != ErrVersionConflict {
+		t.Errorf("Expected ErrVersionConflict writing a stale version, got: %v", err)
+	}
+
+	_, readVersion, err = memfspath.ReadFileVersion()
+	if err != nil {
+		t.Fatalf("Failed reading file: %v", err)
+	}
+	if _, err := memfspath.WriteFileIfVersion(bytes.NewReader([]byte("v2")), nil, readVersion); err != nil {
+		t.Errorf("Failed writing the current version: %v", err)
+	}
+}
+
+func TestMemFsLock(t *testing.T) {
+	lockPath := NewMemFSPath(NewMemFSContext(), "/root/cluster/lock")
+
+	info, err := NewLockInfo("update cluster")
+	if err != nil {
+		t.Fatalf("Failed building lock info: %v", err)
+	}
+	unlock, err := Lock(lockPath, info)
+	if err != nil {
+		t.Fatalf("Failed taking lock: %v", err)
+	}
+
+	other, err := NewLockInfo("edit cluster")
+	if err != nil {
+		t.Fatalf("Failed building lock info: %v", err)
+	}
+	_, err = Lock(lockPath, other)
+	lockHeld, ok := err.(*LockHeldError)
+	if !ok {
+		t.Fatalf("Expected LockHeldError, got: %v", err)
+	}
+	if lockHeld.Holder.ID != info.ID || lockHeld.Holder.Operation != "update cluster" {
+		t.Errorf("Expected lock to be held by %v, got %v", info, lockHeld.Holder)
+	}
+
+	if err := unlock(); err != nil {
+		t.Fatalf("Failed releasing lock: %v", err)
+	}
+
+	unlock, err = Lock(lockPath, other)
+	if err != nil {
+		t.Fatalf("Failed taking released lock: %v", err)
+	}
+	if err := unlock(); err != nil {
+		t.Errorf("Failed releasing lock: %v", err)
+	}
+}
+
+func TestMemFsForceUnlock(t *testing.T) {
+	lockPath := NewMemFSPath(NewMemFSContext(), "/root/cluster/lock")
+
+	if err := ForceUnlock(lockPath, "abc"); !os.IsNotExist(err) {
+		t.Errorf("Expected os.ErrNotExist force-unlocking a lock that is not held, got: %v", err)
+	}
+
+	stale, err := NewLockInfo("update cluster")
+	if err != nil {
+		t.Fatalf("Failed building lock info: %v", err)
+	}
+	if _, err := Lock(lockPath, stale); err != nil {
+		t.Fatalf("Failed taking lock: %v", err)
+	}
+
+	if err := ForceUnlock(lockPath, "abc"); err == nil {
+		t.Errorf("Expected force-unlocking with another lock id to fail")
+	}
+	if err := ForceUnlock(lockPath, stale.ID); err != nil {
+		t.Fatalf("Failed force-unlocking: %v", err)
+	}
+
+	other, err := NewLockInfo("edit cluster")
+	if err != nil {
+		t.Fatalf("Failed building lock info: %v", err)
+	}
+	unlock, err := Lock(lockPath, other)
+	if err != nil {
+		t.Fatalf("Failed taking force-unlocked lock: %v", err)
+	}
+	if err := unlock(); err != nil {
+		t.Errorf("Failed releasing lock: %v", err)
+	}
+}
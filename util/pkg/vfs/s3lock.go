@@ -0,0 +1,171 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfs
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"k8s.io/klog/v2"
+)
+
+// S3LockTableEnv is the environment variable naming the DynamoDB table that holds the locks for S3 paths.
+// The table must have a string partition key named LockID.
+const S3LockTableEnv = "KOPS_STATE_S3_LOCK_TABLE"
+
+// Lock implements Lockable::Lock, by conditionally putting an item into the DynamoDB lock table, in the region of the bucket.
+// S3 offers no conditional writes, so S3 paths can only be locked when a lock table is configured.
+func (p *S3Path) Lock(info *LockInfo) (Unlocker, error) {
+	table := os.Getenv(S3LockTableEnv)
+	if table == "" {
+		return nil, ErrLockingNotSupported
+	}
+
+	data, err := info.marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := p.dynamoDBClient()
+	if err != nil {
+		return nil, err
+	}
+
+	lockID := p.Path()
+	klog.V(2).Infof("Taking lock %s in DynamoDB table %q", lockID, table)
+	_, err = client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(table),
+		Item: map[string]*dynamodb.AttributeValue{
+			"LockID": {S: aws.String(lockID)},
+			"Info":   {S: aws.String(string(data))},
+		},
+		ConditionExpression: aws.String("attribute_not_exists(LockID)"),
+	})
+	if err != nil {
+		if isConditionalCheckFailed(err) {
+			holder, err := client.GetItem(&dynamodb.GetItemInput{
+				TableName:      aws.String(table),
+				Key:            map[string]*dynamodb.AttributeValue{"LockID": {S: aws.String(lockID)}},
+				ConsistentRead: aws.Bool(true),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error reading lock %s from DynamoDB table %q: %v", lockID, table, err)
+			}
+			var holderInfo []byte
+			if v := holder.Item["Info"]; v != nil {
+				holderInfo = []byte(aws.StringValue(v.S))
+			}
+			return nil, &LockHeldError{Lock: lockID, Holder: unmarshalLockInfo(holderInfo)}
+		}
+		return nil, fmt.Errorf("error taking lock %s in DynamoDB table %q: %v", lockID, table, err)
+	}
+
+	return func() error {
+		_, err := client.DeleteItem(&dynamodb.DeleteItemInput{
+			TableName:                 aws.String(table),
+			Key:                       map[string]*dynamodb.AttributeValue{"LockID": {S: aws.String(lockID)}},
+			ConditionExpression:       aws.String("Info = :info"),
+			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":info": {S: aws.String(string(data))}},
+		})
+		if err != nil {
+			if isConditionalCheckFailed(err) {
+				return fmt.Errorf("lock %s was taken over by another holder", lockID)
+			}
+			return fmt.Errorf("error releasing lock %s in DynamoDB table %q: %v", lockID, table, err)
+		}
+		return nil
+	}, nil
+}
+
+// ForceUnlock implements Lockable::ForceUnlock, deleting the item from the DynamoDB lock table
+// with a condition that it was not replaced since we checked it
+func (p *S3Path) ForceUnlock(id string) error {
+	table := os.Getenv(S3LockTableEnv)
+	if table == "" {
+		return ErrLockingNotSupported
+	}
+
+	client, err := p.dynamoDBClient()
+	if err != nil {
+		return err
+	}
+
+	lockID := p.Path()
+	holder, err := client.GetItem(&dynamodb.GetItemInput{
+		TableName:      aws.String(table),
+		Key:            map[string]*dynamodb.AttributeValue{"LockID": {S: aws.String(lockID)}},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return fmt.Errorf("error reading lock %s from DynamoDB table %q: %v", lockID, table, err)
+	}
+	v := holder.Item["Info"]
+	if v == nil {
+		return os.ErrNotExist
+	}
+	data := aws.StringValue(v.S)
+	if err := checkLockID(lockID, []byte(data), id); err != nil {
+		return err
+	}
+
+	_, err = client.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName:                 aws.String(table),
+		Key:                       map[string]*dynamodb.AttributeValue{"LockID": {S: aws.String(lockID)}},
+		ConditionExpression:       aws.String("Info = :info"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":info": {S: aws.String(data)}},
+	})
+	if err != nil {
+		if isConditionalCheckFailed(err) {
+			return fmt.Errorf("lock %s was taken over by another holder", lockID)
+		}
+		return fmt.Errorf("error releasing lock %s in DynamoDB table %q: %v", lockID, table, err)
+	}
+	return nil
+}
+
+var _ Lockable = &S3Path{}
+
+func (p *S3Path) dynamoDBClient() (*dynamodb.DynamoDB, error) {
+	if p.bucketDetails == nil || p.bucketDetails.region == "" {
+		bucketDetails, err := p.s3Context.getDetailsForBucket(p.bucket)
+		p.bucketDetails = bucketDetails
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	config := aws.NewConfig().WithRegion(p.bucketDetails.region).WithCredentialsChainVerboseErrors(true)
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *config,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error starting new AWS session: %v", err)
+	}
+	return dynamodb.New(sess, config), nil
+}
+
+func isConditionalCheckFailed(err error) bool {
+	if awsErr, ok := err.(awserr.Error); ok {
+		return awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException
+	}
+	return false
+}
@@ -63,9 +63,25 @@ func newVaultPath(client *vault.Client, scheme string, path string) (*VaultPath,
 	}, nil
 }
 
+// WriteFile writes a new version of the file; the KV v2 engine keeps the previous versions
 func (p *VaultPath) WriteFile(data io.ReadSeeker, acl ACL) error {
 	klog.V(4).Infof("Writing file %q", p)
 
+	return p.write(data, nil)
+}
+
+// CreateFile writes the file only if it does not exist, using a check-and-set of version 0
+func (p *VaultPath) CreateFile(data io.ReadSeeker, acl ACL) error {
+	klog.V(4).Infof("Creating file %q", p)
+
+	err := p.write(data, map[string]interface{}{"cas": 0})
+	if err != nil && isVaultCheckAndSetMismatch(err) {
+		return os.ErrExist
+	}
+	return err
+}
+
+func (p *VaultPath) write(data io.ReadSeeker, options map[string]interface{}) error {
 	file, err := encodeData(data)
 	if err != nil {
 		return err
@@ -76,18 +92,17 @@ func (p *VaultPath) WriteFile(data io.ReadSeeker, acl ACL) error {
 			"file": file,
 		},
 	}
+	if options != nil {
+		payload["options"] = options
+	}
 
 	_, err = p.vaultClient.Logical().Write(p.dataPath(), payload)
 	return err
 }
 
-func (p *VaultPath) CreateFile(data io.ReadSeeker, acl ACL) error {
-	file, _ := p.ReadFile()
-	if file == nil {
-		return p.WriteFile(data, acl)
-	} else {
-		return fmt.Errorf("file already exists: %v", p.path)
-	}
+// isVaultCheckAndSetMismatch returns true if the error is a failed check-and-set, because the file was written since
+func isVaultCheckAndSetMismatch(err error) bool {
+	return strings.Contains(err.Error(), "check-and-set parameter did not match the current version")
 }
 
 func (p *VaultPath) ReadFile() ([]byte, error) {
@@ -100,9 +115,15 @@ func (p *VaultPath) ReadFile() ([]byte, error) {
 		return nil, os.ErrNotExist
 	}
 
-	data := secret.Data["data"].(map[string]interface{})
+	data, ok := secret.Data["data"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected data in %s", p)
+	}
 
-	encodedString := data["file"].(string)
+	encodedString, ok := data["file"].(string)
+	if !ok {
+		return nil, fmt.Errorf("%s was not written by kOps: no file field", p)
+	}
 	return base64.StdEncoding.DecodeString(encodedString)
 }
 
@@ -112,22 +133,11 @@ func (p *VaultPath) Remove() error {
 	return err
 }
 
+// RemoveAllVersions permanently removes the file, with all of its versions, by deleting its metadata
 func (p *VaultPath) RemoveAllVersions() error {
 	klog.V(8).Infof("removing all versions of file %s", p)
 
-	data := map[string][]string{
-		"versions": {"1"},
-	}
-	_, err := p.vaultClient.Logical().DeleteWithData(p.dataPath(), data)
-	if err != nil {
-		return err
-	}
-	err = p.destroy()
-	if err != nil {
-		return err
-	}
-	err = p.deleteMetadata()
-	return err
+	return p.deleteMetadata()
 }
 
 func (p *VaultPath) Base() string {
@@ -151,11 +161,17 @@ func (p *VaultPath) ReadDir() ([]Path, error) {
 	if secret == nil {
 		return nil, os.ErrNotExist
 	}
-	data := secret.Data["keys"].([]interface{})
+	data, ok := secret.Data["keys"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected listing of %s", p)
+	}
 	paths := make([]Path, 0)
 	for _, key := range data {
-		path := p.Join(key.(string))
-		paths = append(paths, path)
+		name, ok := key.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected key %v in listing of %s", key, p)
+		}
+		paths = append(paths, p.Join(name))
 	}
 	return paths, nil
 }
@@ -191,8 +207,14 @@ func (p *VaultPath) Join(relativePath ...string) Path {
 	return path
 }
 
+// WriteTo implements io.WriterTo
 func (p *VaultPath) WriteTo(out io.Writer) (int64, error) {
-	panic("writeTo not implemented")
+	data, err := p.ReadFile()
+	if err != nil {
+		return 0, err
+	}
+	n, err := out.Write(data)
+	return int64(n), err
 }
 
 func encodeData(data io.ReadSeeker) (string, error) {
@@ -234,26 +256,6 @@ func (p *VaultPath) String() string {
 	return p.Path()
 }
 
-func (p VaultPath) destroy() error {
-
-	data := map[string][]string{
-		"versions": {"1"},
-	}
-	r := p.vaultClient.NewRequest("PUT", "/v1/"+p.mountPoint+"/destroy/"+p.path)
-
-	r.SetJSONBody(data)
-
-	ctx, cancelFunc := context.WithCancel(context.Background())
-	defer cancelFunc()
-	resp, err := p.vaultClient.RawRequestWithContext(ctx, r)
-	if resp != nil {
-		defer resp.Body.Close()
-	}
-
-	return err
-
-}
-
 func (p VaultPath) deleteMetadata() error {
 
 	r := p.vaultClient.NewRequest("DELETE", "/v1/"+p.mountPoint+"/metadata/"+p.path)
@@ -148,8 +148,29 @@ func Test_CreateFile(t *testing.T) {
 	}
 
 	err = p.CreateFile(strings.NewReader(secret), nil)
-	if err == nil {
-		t.Errorf("Should have failed to create file at %v", path)
+	if !os.IsExist(err) {
+		t.Errorf("Should have failed to create existing file at %v, got %v", path, err)
+	}
+}
+
+func Test_WriteTo(t *testing.T) {
+	client := createClient(t)
+
+	p, _ := newVaultPath(client, "http://", "/secret/writetotest")
+
+	secret := "my very special secret"
+	err := p.WriteFile(strings.NewReader(secret), nil)
+	if err != nil {
+		t.Errorf("Failed to write file: %v", err)
+	}
+
+	var b bytes.Buffer
+	n, err := p.WriteTo(&b)
+	if err != nil {
+		t.Errorf("Failed to write to buffer: %v", err)
+	}
+	if n != int64(len(secret)) || b.String() != secret {
+		t.Errorf("Failed to write to buffer. Got %q, expected %q", b.String(), secret)
 	}
 }
 
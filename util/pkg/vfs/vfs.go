@@ -76,6 +76,36 @@ type Path interface {
 	ReadTree() ([]Path, error)
 }
 
+// WrappedPath is implemented by paths that wrap another path, for example to encrypt its contents
+type WrappedPath interface {
+	Path
+
+	// Unwrap returns the wrapped path
+	Unwrap() Path
+
+	// Wrap wraps another path the same way
+	Wrap(p Path) Path
+}
+
+// Unwrap returns the innermost path wrapped by p, or p if it does not wrap another path
+func Unwrap(p Path) Path {
+	for {
+		wrapped, ok := p.(WrappedPath)
+		if !ok {
+			return p
+		}
+		p = wrapped.Unwrap()
+	}
+}
+
+// WrapLike wraps p the same way as template, so that for example a copy of an encrypted store is encrypted too
+func WrapLike(template Path, p Path) Path {
+	if wrapped, ok := template.(WrappedPath); ok {
+		return wrapped.Wrap(WrapLike(wrapped.Unwrap(), p))
+	}
+	return p
+}
+
 type HasHash interface {
 	// Returns the hash of the file contents, with the preferred hash algorithm
 	PreferredHash() (*hashing.Hash, error)